@@ -0,0 +1,108 @@
+// Package stream benchmarks the gateway's streaming path end to end: an
+// in-process upstream serves a fixed SSE body, and the chat completions
+// handler relays it to a discarding response writer.
+//
+//	go test ./bench/stream -run '^$' -bench . -benchmem
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/api"
+)
+
+// streamChunks is the number of content events per benchmarked response.
+const streamChunks = 200
+
+const requestBody = `{"model":"gpt-4o","stream":true,"messages":[{"role":"user","content":"hi"}]}`
+
+func BenchmarkChatCompletionStream_Decoded(b *testing.B) {
+	benchmarkChatCompletionStream(b, &api.ClientHandlerConfig{DisableStreamPassthrough: true})
+}
+
+func BenchmarkChatCompletionStream_Passthrough(b *testing.B) {
+	benchmarkChatCompletionStream(b, &api.ClientHandlerConfig{})
+}
+
+func benchmarkChatCompletionStream(b *testing.B, cfg *api.ClientHandlerConfig) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, upstreamBody())
+	}))
+	b.Cleanup(upstream.Close)
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "bench",
+			BaseURL:             upstream.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { _ = client.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := api.NewClientHandler(client, logger, cfg)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(requestBody))
+		w := &discardWriter{header: make(http.Header)}
+		handler.ChatCompletions(w, req)
+		if w.status != http.StatusOK || w.events != streamChunks+1 {
+			b.Fatalf("status %d, %d events", w.status, w.events)
+		}
+	}
+}
+
+// upstreamBody renders an OpenAI-format stream of streamChunks content events.
+func upstreamBody() string {
+	var sb strings.Builder
+	for i := 0; i < streamChunks; i++ {
+		finish := "null"
+		if i == streamChunks-1 {
+			finish = `"stop"`
+		}
+		fmt.Fprintf(&sb, `data: {"id":"chatcmpl-bench","object":"chat.completion.chunk","created":1700000000,"model":"gpt-4o","choices":[{"index":0,"delta":{"content":"token %d "},"finish_reason":%s}]}`+"\n\n", i, finish)
+	}
+	sb.WriteString("data: [DONE]\n\n")
+	return sb.String()
+}
+
+// discardWriter is a flushing http.ResponseWriter that counts SSE events
+// without keeping the body.
+type discardWriter struct {
+	header http.Header
+	status int
+	events int
+}
+
+func (w *discardWriter) Header() http.Header { return w.header }
+
+func (w *discardWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *discardWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	w.events += bytes.Count(p, []byte("\n\n"))
+	return len(p), nil
+}
+
+func (w *discardWriter) Flush() {}
@@ -0,0 +1,49 @@
+package llmux
+
+import (
+	"context"
+	"time"
+)
+
+// Cache statuses reported on ChatResponse.CacheStatus.
+const (
+	CacheStatusHit  = "hit"
+	CacheStatusMiss = "miss"
+)
+
+type cacheControlContextKey struct{}
+
+// WithCacheControl attaches per-request cache behavior to the context:
+// NoCache skips the cache read, NoStore skips the write, TTL overrides the
+// configured cache TTL and MaxAge rejects cached responses older than it.
+func WithCacheControl(ctx context.Context, ctrl CacheControl) context.Context {
+	return context.WithValue(ctx, cacheControlContextKey{}, ctrl)
+}
+
+// CacheControlFromContext returns the cache controls attached to ctx.
+func CacheControlFromContext(ctx context.Context) (CacheControl, bool) {
+	if ctx == nil {
+		return CacheControl{}, false
+	}
+	ctrl, ok := ctx.Value(cacheControlContextKey{}).(CacheControl)
+	return ctrl, ok
+}
+
+// cacheTTL returns the TTL for entries written under ctx.
+func (c *Client) cacheTTL(ctx context.Context) time.Duration {
+	if ctrl, _ := CacheControlFromContext(ctx); ctrl.TTL > 0 {
+		return ctrl.TTL
+	}
+	return c.config.CacheTTL
+}
+
+// cachedResponseFresh reports whether a cached response satisfies the
+// request's max age, judged by the response creation time.
+func cachedResponseFresh(ctx context.Context, resp *ChatResponse) bool {
+	ctrl, _ := CacheControlFromContext(ctx)
+	maxAge := ctrl.MaxAge
+	if maxAge <= 0 || resp == nil || resp.Created == 0 {
+		return true
+	}
+	return time.Since(time.Unix(resp.Created, 0)) <= maxAge
+}
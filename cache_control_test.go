@@ -0,0 +1,116 @@
+package llmux
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/caches/memory"
+)
+
+func newCacheControlClient(t *testing.T) (*Client, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			ID:      "cached",
+			Object:  "chat.completion",
+			Created: time.Now().Unix(),
+			Model:   "m",
+			Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: jsonString("ok")}, FinishReason: "stop"}},
+			Usage:   &Usage{PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"m"}, baseURL: server.URL}, []string{"m"}),
+		withTestPricing(t, "m"),
+		WithCache(memory.New(memory.DefaultConfig())),
+		WithRetry(0, 0),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client, &calls
+}
+
+func cacheControlRequest() *ChatRequest {
+	return &ChatRequest{Model: "m", Messages: []ChatMessage{{Role: "user", Content: jsonString("hi")}}}
+}
+
+func TestCacheControl_Status(t *testing.T) {
+	client, calls := newCacheControlClient(t)
+	ctx := context.Background()
+
+	resp, err := client.ChatCompletion(ctx, cacheControlRequest())
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.CacheStatus != CacheStatusMiss {
+		t.Fatalf("first response cache status = %q, want miss", resp.CacheStatus)
+	}
+
+	resp, err = client.ChatCompletion(ctx, cacheControlRequest())
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.CacheStatus != CacheStatusHit {
+		t.Fatalf("second response cache status = %q, want hit", resp.CacheStatus)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("upstream calls = %d, want 1", got)
+	}
+}
+
+func TestCacheControl_NoCacheAndNoStore(t *testing.T) {
+	client, calls := newCacheControlClient(t)
+
+	noStore := WithCacheControl(context.Background(), CacheControl{NoStore: true})
+	if _, err := client.ChatCompletion(noStore, cacheControlRequest()); err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	resp, err := client.ChatCompletion(context.Background(), cacheControlRequest())
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.CacheStatus != CacheStatusMiss {
+		t.Fatalf("no-store response was cached: status %q", resp.CacheStatus)
+	}
+
+	noCache := WithCacheControl(context.Background(), CacheControl{NoCache: true})
+	resp, err = client.ChatCompletion(noCache, cacheControlRequest())
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.CacheStatus != CacheStatusMiss {
+		t.Fatalf("no-cache response status = %q, want miss", resp.CacheStatus)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("upstream calls = %d, want 3", got)
+	}
+}
+
+func TestCacheControl_TTLAndMaxAge(t *testing.T) {
+	client, _ := newCacheControlClient(t)
+
+	if got := client.cacheTTL(WithCacheControl(context.Background(), CacheControl{TTL: 5 * time.Minute})); got != 5*time.Minute {
+		t.Fatalf("cacheTTL() = %v, want 5m", got)
+	}
+
+	ctx := WithCacheControl(context.Background(), CacheControl{MaxAge: time.Minute})
+	if !cachedResponseFresh(ctx, &ChatResponse{Created: time.Now().Unix()}) {
+		t.Fatal("expected recent response to be fresh")
+	}
+	if cachedResponseFresh(ctx, &ChatResponse{Created: time.Now().Add(-time.Hour).Unix()}) {
+		t.Fatal("expected hour-old response to be stale")
+	}
+}
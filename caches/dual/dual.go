@@ -14,6 +14,8 @@ import (
 
 // Cache implements a two-tier cache with in-memory (L1) and Redis (L2).
 // Writes go to both caches, reads check L1 first then L2 with backfill.
+// Concurrent L1 misses for the same key share one Redis read, and Redis
+// writes can optionally be applied asynchronously (write-behind).
 type Cache struct {
 	local  *memory.Cache
 	remote *redis.Cache
@@ -23,11 +25,22 @@ type Cache struct {
 	mu                  sync.RWMutex
 	lastRedisAccessTime map[string]time.Time
 
+	// Duplicate fill suppression
+	fills fillGroup
+
+	// Write-behind queue (nil when disabled)
+	writes       chan writeOp
+	writerDone   chan struct{}
+	writeMu      sync.RWMutex
+	writesClosed bool
+
 	// Statistics
-	localHits atomic.Int64
-	redisHits atomic.Int64
-	misses    atomic.Int64
-	backfills atomic.Int64
+	localHits   atomic.Int64
+	redisHits   atomic.Int64
+	misses      atomic.Int64
+	backfills   atomic.Int64
+	sharedFills atomic.Int64
+	writeErrors atomic.Int64
 }
 
 // Config holds configuration for dual Cache.
@@ -36,6 +49,13 @@ type Config struct {
 	RedisTTL           time.Duration // TTL for Redis cache (default: 1 hour)
 	BatchThrottleTime  time.Duration // Throttle repeated Redis queries (default: 10 seconds)
 	MaxThrottleEntries int           // Max entries in throttle map (default: 10000)
+
+	// WriteBehind applies Redis writes asynchronously after the local write,
+	// in order, from a single background worker (default: false).
+	WriteBehind bool
+	// WriteQueueSize bounds pending write-behind operations; when full,
+	// writes block until the worker catches up (default: 1024).
+	WriteQueueSize int
 }
 
 // DefaultConfig returns sensible defaults.
@@ -45,10 +65,12 @@ func DefaultConfig() Config {
 		RedisTTL:           time.Hour,
 		BatchThrottleTime:  10 * time.Second,
 		MaxThrottleEntries: 10000,
+		WriteQueueSize:     1024,
 	}
 }
 
-// New creates a new dual-tier cache.
+// New creates a new dual-tier cache. LocalTTL is capped at RedisTTL so the
+// local tier never outlives the shared one.
 func New(local *memory.Cache, remote *redis.Cache, cfg Config) *Cache {
 	if cfg.LocalTTL <= 0 {
 		cfg.LocalTTL = 5 * time.Minute
@@ -62,13 +84,31 @@ func New(local *memory.Cache, remote *redis.Cache, cfg Config) *Cache {
 	if cfg.MaxThrottleEntries <= 0 {
 		cfg.MaxThrottleEntries = 10000
 	}
+	if cfg.WriteQueueSize <= 0 {
+		cfg.WriteQueueSize = 1024
+	}
+	cfg.LocalTTL = min(cfg.LocalTTL, cfg.RedisTTL)
 
-	return &Cache{
+	c := &Cache{
 		local:               local,
 		remote:              remote,
 		config:              cfg,
 		lastRedisAccessTime: make(map[string]time.Time),
 	}
+	if cfg.WriteBehind && remote != nil {
+		c.writes = make(chan writeOp, cfg.WriteQueueSize)
+		c.writerDone = make(chan struct{})
+		go c.runWriteBehind()
+	}
+	return c
+}
+
+// localTTL bounds the local TTL by the remaining Redis TTL, when known.
+func (c *Cache) localTTL(remaining time.Duration) time.Duration {
+	if remaining > 0 && remaining < c.config.LocalTTL {
+		return remaining
+	}
+	return c.config.LocalTTL
 }
 
 // Get retrieves a value, checking local cache first, then Redis.
@@ -79,17 +119,20 @@ func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
 		return val, nil
 	}
 
-	// L2: Check Redis
+	// L2: Check Redis, sharing the read with concurrent misses for the key
 	if c.remote != nil {
-		val, err := c.remote.Get(ctx, key)
+		fillCtx := context.WithoutCancel(ctx)
+		val, err, shared := c.fills.do(key, func() ([]byte, error) {
+			return c.fill(fillCtx, key)
+		})
+		if shared {
+			c.sharedFills.Add(1)
+		}
 		if err != nil {
 			return nil, err
 		}
 		if val != nil {
 			c.redisHits.Add(1)
-			// Backfill local cache - best-effort, failure doesn't affect main flow
-			_ = c.local.Set(ctx, key, val, c.config.LocalTTL) //nolint:errcheck // backfill is best-effort
-			c.backfills.Add(1)
 			return val, nil
 		}
 	}
@@ -98,25 +141,35 @@ func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
 	return nil, nil
 }
 
+// fill reads key from Redis and backfills the local cache for no longer
+// than the entry's remaining Redis TTL.
+func (c *Cache) fill(ctx context.Context, key string) ([]byte, error) {
+	val, remaining, err := c.remote.GetWithTTL(ctx, key)
+	if err != nil || val == nil {
+		return nil, err
+	}
+	// Backfill local cache - best-effort, failure doesn't affect main flow
+	_ = c.local.Set(ctx, key, val, c.localTTL(remaining)) //nolint:errcheck // backfill is best-effort
+	c.backfills.Add(1)
+	return val, nil
+}
+
 // Set stores a value in both caches.
 func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
 	// Determine TTLs
-	localTTL := c.config.LocalTTL
 	redisTTL := ttl
 	if redisTTL <= 0 {
 		redisTTL = c.config.RedisTTL
 	}
 
 	// Write to local cache
-	if err := c.local.Set(ctx, key, value, localTTL); err != nil {
+	if err := c.local.Set(ctx, key, value, c.localTTL(redisTTL)); err != nil {
 		return err
 	}
 
 	// Write to Redis
 	if c.remote != nil {
-		if err := c.remote.Set(ctx, key, value, redisTTL); err != nil {
-			return err
-		}
+		return c.writeRemote(ctx, writeOp{entries: []cache.Entry{{Key: key, Value: value, TTL: redisTTL}}})
 	}
 
 	return nil
@@ -134,7 +187,7 @@ func (c *Cache) SetLocalOnly(ctx context.Context, key string, value []byte, ttl
 func (c *Cache) Delete(ctx context.Context, key string) error {
 	_ = c.local.Delete(ctx, key) //nolint:errcheck // best-effort local delete
 	if c.remote != nil {
-		return c.remote.Delete(ctx, key)
+		return c.writeRemote(ctx, writeOp{del: key})
 	}
 	return nil
 }
@@ -144,10 +197,14 @@ func (c *Cache) SetPipeline(ctx context.Context, entries []cache.Entry) error {
 	// Adjust TTLs for local cache
 	localEntries := make([]cache.Entry, len(entries))
 	for i, e := range entries {
+		redisTTL := e.TTL
+		if redisTTL <= 0 {
+			redisTTL = c.config.RedisTTL
+		}
 		localEntries[i] = cache.Entry{
 			Key:   e.Key,
 			Value: e.Value,
-			TTL:   c.config.LocalTTL,
+			TTL:   c.localTTL(redisTTL),
 		}
 	}
 
@@ -157,8 +214,8 @@ func (c *Cache) SetPipeline(ctx context.Context, entries []cache.Entry) error {
 	}
 
 	// Write to Redis
-	if c.remote != nil {
-		return c.remote.SetPipeline(ctx, entries)
+	if c.remote != nil && len(entries) > 0 {
+		return c.writeRemote(ctx, writeOp{entries: entries})
 	}
 
 	return nil
@@ -267,8 +324,9 @@ func (c *Cache) Ping(ctx context.Context) error {
 	return nil
 }
 
-// Close closes both cache backends.
+// Close flushes pending write-behind operations and closes both cache backends.
 func (c *Cache) Close() error {
+	c.closeWriteBehind()
 	_ = c.local.Close()
 	if c.remote != nil {
 		return c.remote.Close()
@@ -304,13 +362,16 @@ func (c *Cache) Stats() cache.Stats {
 
 // DetailedStats holds detailed statistics for both tiers.
 type DetailedStats struct {
-	LocalHits  int64       `json:"local_hits"`
-	RedisHits  int64       `json:"redis_hits"`
-	Misses     int64       `json:"misses"`
-	Backfills  int64       `json:"backfills"`
-	HitRate    float64     `json:"hit_rate"`
-	LocalStats cache.Stats `json:"local_stats"`
-	RedisStats cache.Stats `json:"redis_stats"`
+	LocalHits     int64       `json:"local_hits"`
+	RedisHits     int64       `json:"redis_hits"`
+	Misses        int64       `json:"misses"`
+	Backfills     int64       `json:"backfills"`
+	SharedFills   int64       `json:"shared_fills"`   // Redis reads avoided by joining an in-flight fill
+	PendingWrites int         `json:"pending_writes"` // Queued write-behind operations
+	WriteErrors   int64       `json:"write_errors"`   // Failed write-behind operations
+	HitRate       float64     `json:"hit_rate"`
+	LocalStats    cache.Stats `json:"local_stats"`
+	RedisStats    cache.Stats `json:"redis_stats"`
 }
 
 // GetDetailedStats returns detailed statistics for both cache tiers.
@@ -326,12 +387,15 @@ func (c *Cache) GetDetailedStats() DetailedStats {
 	}
 
 	stats := DetailedStats{
-		LocalHits:  localHits,
-		RedisHits:  redisHits,
-		Misses:     misses,
-		Backfills:  c.backfills.Load(),
-		HitRate:    hitRate,
-		LocalStats: c.local.Stats(),
+		LocalHits:     localHits,
+		RedisHits:     redisHits,
+		Misses:        misses,
+		Backfills:     c.backfills.Load(),
+		SharedFills:   c.sharedFills.Load(),
+		PendingWrites: len(c.writes),
+		WriteErrors:   c.writeErrors.Load(),
+		HitRate:       hitRate,
+		LocalStats:    c.local.Stats(),
 	}
 
 	if c.remote != nil {
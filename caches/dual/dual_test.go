@@ -0,0 +1,108 @@
+package dual
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/caches/memory"
+	"github.com/blueberrycongee/llmux/caches/redis"
+)
+
+func newTestCache(t *testing.T, cfg Config) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	redisCfg := redis.DefaultConfig()
+	redisCfg.Addr = mr.Addr()
+	remote, err := redis.New(redisCfg)
+	require.NoError(t, err)
+
+	c := New(memory.New(memory.DefaultConfig()), remote, cfg)
+	t.Cleanup(func() { _ = c.Close() })
+	return c, mr
+}
+
+func TestCache_WriteBehindDrainsInOrder(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.WriteBehind = true
+	c, mr := newTestCache(t, cfg)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "k1", []byte("v1"), time.Minute))
+	require.NoError(t, c.Set(ctx, "k2", []byte("v2"), time.Minute))
+	require.NoError(t, c.Delete(ctx, "k2"))
+
+	val, err := c.Get(ctx, "k1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), val)
+
+	c.closeWriteBehind()
+	got, err := mr.Get("llmux:k1")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", got)
+	assert.False(t, mr.Exists("llmux:k2"), "delete must apply after the earlier queued set")
+	assert.Equal(t, time.Minute, mr.TTL("llmux:k1"))
+}
+
+func TestCache_WriteBehindFullQueueKeepsOrder(t *testing.T) {
+	c, mr := newTestCache(t, DefaultConfig())
+	ctx := context.Background()
+	// A one-slot queue whose worker has not started yet.
+	c.writes = make(chan writeOp, 1)
+	c.writerDone = make(chan struct{})
+
+	require.NoError(t, c.Set(ctx, "k", []byte("stale"), time.Minute))
+	deleted := make(chan error, 1)
+	go func() { deleted <- c.Delete(ctx, "k") }()
+	select {
+	case err := <-deleted:
+		t.Error("delete must wait for room in the full queue")
+		deleted <- err
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	go c.runWriteBehind()
+	require.NoError(t, <-deleted)
+	c.closeWriteBehind()
+	assert.False(t, mr.Exists("llmux:k"), "delete must not overtake the queued set")
+}
+
+func TestCache_SharesConcurrentFills(t *testing.T) {
+	c, mr := newTestCache(t, DefaultConfig())
+	require.NoError(t, mr.Set("llmux:hot", "value"))
+	mr.SetTTL("llmux:hot", 30*time.Second)
+
+	const callers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := c.Get(context.Background(), "hot")
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("value"), val)
+		}()
+	}
+	wg.Wait()
+
+	stats := c.GetDetailedStats()
+	assert.Equal(t, int64(callers), stats.LocalHits+stats.RedisHits)
+	assert.LessOrEqual(t, stats.Backfills, int64(callers))
+	assert.Equal(t, stats.RedisHits, stats.Backfills+stats.SharedFills)
+}
+
+func TestCache_LocalTTLBoundedByRedisTTL(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.LocalTTL = time.Hour
+	cfg.RedisTTL = 10 * time.Minute
+	c, _ := newTestCache(t, cfg)
+
+	assert.Equal(t, 10*time.Minute, c.config.LocalTTL)
+	assert.Equal(t, 30*time.Second, c.localTTL(30*time.Second))
+	assert.Equal(t, 10*time.Minute, c.localTTL(-1))
+}
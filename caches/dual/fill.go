@@ -0,0 +1,43 @@
+package dual
+
+import "sync"
+
+// fillCall is an in-flight Redis read shared by concurrent local misses.
+type fillCall struct {
+	wg  sync.WaitGroup
+	val []byte
+	err error
+}
+
+// fillGroup suppresses duplicate in-flight fills of the same key, so a burst
+// of local misses for one key costs a single Redis round trip.
+type fillGroup struct {
+	mu    sync.Mutex
+	calls map[string]*fillCall
+}
+
+// do runs fn once per key among concurrent callers and reports whether the
+// result was shared with an earlier caller.
+func (g *fillGroup) do(key string, fn func() ([]byte, error)) ([]byte, error, bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*fillCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.val, call.err, true
+	}
+	call := &fillCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.val, call.err = fn()
+	call.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	return call.val, call.err, false
+}
@@ -0,0 +1,73 @@
+package dual
+
+import (
+	"context"
+
+	"github.com/blueberrycongee/llmux/pkg/cache"
+)
+
+// writeOp is a queued Redis mutation. Ops are applied by a single worker in
+// enqueue order, so a delete never races ahead of an earlier set.
+type writeOp struct {
+	entries []cache.Entry
+	del     string
+}
+
+func (op writeOp) apply(ctx context.Context, c *Cache) error {
+	if op.del != "" {
+		return c.remote.Delete(ctx, op.del)
+	}
+	if len(op.entries) == 1 {
+		e := op.entries[0]
+		return c.remote.Set(ctx, e.Key, e.Value, e.TTL)
+	}
+	return c.remote.SetPipeline(ctx, op.entries)
+}
+
+// writeRemote applies op to Redis, asynchronously when write-behind is
+// enabled. A full queue blocks until there is room or ctx is done, since a
+// synchronous write could overtake queued ops for the same key. A closed
+// cache writes synchronously once the queue has drained.
+func (c *Cache) writeRemote(ctx context.Context, op writeOp) error {
+	if c.writes != nil {
+		c.writeMu.RLock()
+		if !c.writesClosed {
+			defer c.writeMu.RUnlock()
+			select {
+			case c.writes <- op:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		c.writeMu.RUnlock()
+		<-c.writerDone
+	}
+	return op.apply(ctx, c)
+}
+
+// runWriteBehind drains queued writes until the queue is closed.
+func (c *Cache) runWriteBehind() {
+	defer close(c.writerDone)
+	for op := range c.writes {
+		if err := op.apply(context.Background(), c); err != nil {
+			c.writeErrors.Add(1)
+		}
+	}
+}
+
+// closeWriteBehind stops accepting queued writes and waits for pending ones.
+func (c *Cache) closeWriteBehind() {
+	if c.writes == nil {
+		return
+	}
+	c.writeMu.Lock()
+	if c.writesClosed {
+		c.writeMu.Unlock()
+		return
+	}
+	c.writesClosed = true
+	close(c.writes)
+	c.writeMu.Unlock()
+	<-c.writerDone
+}
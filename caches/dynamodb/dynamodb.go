@@ -0,0 +1,444 @@
+// Package dynamodb provides a DynamoDB-based cache implementation.
+// It calls the DynamoDB JSON API directly with SigV4-signed requests.
+package dynamodb
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/pkg/cache"
+)
+
+const (
+	// batchGetLimit and batchWriteLimit are the DynamoDB per-call item limits.
+	batchGetLimit   = 100
+	batchWriteLimit = 25
+
+	// valueAttribute holds the cached bytes.
+	valueAttribute = "value"
+
+	targetPrefix = "DynamoDB_20120810."
+)
+
+// Cache implements cache.Cache interface using a DynamoDB table as backend.
+//
+// Items are stored as {key: S, value: B, expires_at: N}. DynamoDB TTL
+// deletion is lazy, so expiry is also checked on read; enable TTL on the
+// table's expiry attribute to have expired items removed.
+type Cache struct {
+	cfg        Config
+	awsCfg     aws.Config
+	httpClient *http.Client
+	signer     *v4.Signer
+	endpoint   string
+
+	// Statistics
+	hits   atomic.Int64
+	misses atomic.Int64
+	sets   atomic.Int64
+	errors atomic.Int64
+}
+
+// Config holds configuration for DynamoDB Cache.
+type Config struct {
+	Table        string        `yaml:"table"`         // Table name (partition key is a string attribute)
+	Region       string        `yaml:"region"`        // AWS region (default: from AWS config)
+	Endpoint     string        `yaml:"endpoint"`      // Custom endpoint (e.g., DynamoDB Local)
+	Namespace    string        `yaml:"namespace"`     // Key namespace prefix
+	DefaultTTL   time.Duration `yaml:"default_ttl"`   // Default TTL (default: 1 hour)
+	Timeout      time.Duration `yaml:"timeout"`       // Per-call timeout (default: 3 seconds)
+	KeyAttribute string        `yaml:"key_attribute"` // Partition key attribute (default: "key")
+	TTLAttribute string        `yaml:"ttl_attribute"` // Expiry attribute in Unix seconds (default: "expires_at")
+}
+
+// DefaultConfig returns sensible defaults.
+func DefaultConfig() Config {
+	return Config{
+		Table:        "llmux-cache",
+		Namespace:    "llmux",
+		DefaultTTL:   time.Hour,
+		Timeout:      3 * time.Second,
+		KeyAttribute: "key",
+		TTLAttribute: "expires_at",
+	}
+}
+
+// New creates a DynamoDB cache using credentials from the default AWS config
+// chain and verifies the table exists.
+func New(cfg Config) (*Cache, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if cfg.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.Region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return NewWithAWSConfig(cfg, awsCfg, nil)
+}
+
+// NewWithAWSConfig creates a DynamoDB cache from an explicit AWS config.
+// If httpClient is nil, a client with the configured timeout is used.
+func NewWithAWSConfig(cfg Config, awsCfg aws.Config, httpClient *http.Client) (*Cache, error) {
+	if cfg.Table == "" {
+		return nil, errors.New("dynamodb: table is required")
+	}
+	if cfg.DefaultTTL <= 0 {
+		cfg.DefaultTTL = time.Hour
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 3 * time.Second
+	}
+	if cfg.KeyAttribute == "" {
+		cfg.KeyAttribute = "key"
+	}
+	if cfg.TTLAttribute == "" {
+		cfg.TTLAttribute = "expires_at"
+	}
+	if cfg.Region == "" {
+		cfg.Region = awsCfg.Region
+	}
+	if cfg.Region == "" {
+		return nil, errors.New("dynamodb: region is required")
+	}
+	if awsCfg.Credentials == nil {
+		return nil, errors.New("dynamodb: AWS credentials are not configured")
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://dynamodb.%s.amazonaws.com", cfg.Region)
+	}
+
+	c := &Cache{
+		cfg:        cfg,
+		awsCfg:     awsCfg,
+		httpClient: httpClient,
+		signer:     v4.NewSigner(),
+		endpoint:   endpoint,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	if err := c.Ping(ctx); err != nil {
+		return nil, fmt.Errorf("dynamodb ping failed: %w", err)
+	}
+	return c, nil
+}
+
+// attributeValue is the DynamoDB JSON encoding of an attribute.
+type attributeValue struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+	B []byte  `json:"B,omitempty"` // base64 in the wire format
+}
+
+type item map[string]attributeValue
+
+// apiError is an error response from the DynamoDB API.
+type apiError struct {
+	StatusCode int
+	Type       string `json:"__type"`
+	Message    string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("dynamodb: %d %s: %s", e.StatusCode, e.Type, e.Message)
+}
+
+func (c *Cache) prefixKey(key string) string {
+	if c.cfg.Namespace == "" {
+		return key
+	}
+	return c.cfg.Namespace + ":" + key
+}
+
+func (c *Cache) keyItem(key string) item {
+	k := c.prefixKey(key)
+	return item{c.cfg.KeyAttribute: {S: &k}}
+}
+
+// value extracts an unexpired value from it.
+func (c *Cache) value(it item, now time.Time) ([]byte, bool) {
+	if it == nil {
+		return nil, false
+	}
+	if exp := it[c.cfg.TTLAttribute].N; exp != nil {
+		if ts, err := strconv.ParseInt(*exp, 10, 64); err == nil && ts <= now.Unix() {
+			return nil, false
+		}
+	}
+	val, ok := it[valueAttribute]
+	if !ok {
+		return nil, false
+	}
+	if val.B == nil {
+		return []byte{}, true
+	}
+	return val.B, true
+}
+
+func (c *Cache) newItem(key string, value []byte, ttl time.Duration) item {
+	if ttl <= 0 {
+		ttl = c.cfg.DefaultTTL
+	}
+	it := c.keyItem(key)
+	exp := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	it[c.cfg.TTLAttribute] = attributeValue{N: &exp}
+	it[valueAttribute] = attributeValue{B: value}
+	return it
+}
+
+// call invokes a DynamoDB API operation and decodes the response into out.
+func (c *Cache) call(ctx context.Context, op string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", targetPrefix+op)
+
+	creds, err := c.awsCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieve credentials: %w", err)
+	}
+	payloadHash := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), "dynamodb", c.cfg.Region, time.Now()); err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &apiError{StatusCode: resp.StatusCode}
+		_ = json.Unmarshal(data, apiErr)
+		return apiErr
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// Get retrieves a value from DynamoDB.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	var out struct {
+		Item item `json:"Item"`
+	}
+	err := c.call(ctx, "GetItem", map[string]any{
+		"TableName":      c.cfg.Table,
+		"Key":            c.keyItem(key),
+		"ConsistentRead": false,
+	}, &out)
+	if err != nil {
+		c.errors.Add(1)
+		return nil, fmt.Errorf("dynamodb get: %w", err)
+	}
+	val, ok := c.value(out.Item, time.Now())
+	if !ok {
+		c.misses.Add(1)
+		return nil, nil
+	}
+	c.hits.Add(1)
+	return val, nil
+}
+
+// Set stores a value in DynamoDB with TTL.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := c.call(ctx, "PutItem", map[string]any{
+		"TableName": c.cfg.Table,
+		"Item":      c.newItem(key, value, ttl),
+	}, nil)
+	if err != nil {
+		c.errors.Add(1)
+		return fmt.Errorf("dynamodb set: %w", err)
+	}
+	c.sets.Add(1)
+	return nil
+}
+
+// Delete removes a key from DynamoDB.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	err := c.call(ctx, "DeleteItem", map[string]any{
+		"TableName": c.cfg.Table,
+		"Key":       c.keyItem(key),
+	}, nil)
+	if err != nil {
+		c.errors.Add(1)
+		return fmt.Errorf("dynamodb delete: %w", err)
+	}
+	return nil
+}
+
+type writeRequest struct {
+	PutRequest struct {
+		Item item `json:"Item"`
+	} `json:"PutRequest"`
+}
+
+// SetPipeline stores multiple entries using BatchWriteItem.
+// Unprocessed items are retried until the context is done.
+func (c *Cache) SetPipeline(ctx context.Context, entries []cache.Entry) error {
+	// BatchWriteItem rejects duplicate keys within one call; keep the last.
+	latest := make(map[string]int, len(entries))
+	for i, e := range entries {
+		latest[e.Key] = i
+	}
+	requests := make([]writeRequest, 0, len(latest))
+	for i, e := range entries {
+		if latest[e.Key] != i {
+			continue
+		}
+		var wr writeRequest
+		wr.PutRequest.Item = c.newItem(e.Key, e.Value, e.TTL)
+		requests = append(requests, wr)
+	}
+
+	for len(requests) > 0 {
+		n := min(len(requests), batchWriteLimit)
+		pending := requests[:n]
+		requests = requests[n:]
+		for len(pending) > 0 {
+			var out struct {
+				UnprocessedItems map[string][]writeRequest `json:"UnprocessedItems"`
+			}
+			err := c.call(ctx, "BatchWriteItem", map[string]any{
+				"RequestItems": map[string][]writeRequest{c.cfg.Table: pending},
+			}, &out)
+			if err != nil {
+				c.errors.Add(1)
+				return fmt.Errorf("dynamodb pipeline: %w", err)
+			}
+			c.sets.Add(int64(len(pending) - len(out.UnprocessedItems[c.cfg.Table])))
+			pending = out.UnprocessedItems[c.cfg.Table]
+			if len(pending) > 0 && ctx.Err() != nil {
+				return fmt.Errorf("dynamodb pipeline: %w", ctx.Err())
+			}
+		}
+	}
+	return nil
+}
+
+// GetMulti retrieves multiple keys using BatchGetItem.
+func (c *Cache) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	result := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	// BatchGetItem rejects duplicate keys within one call.
+	original := make(map[string]string, len(keys))
+	pendingKeys := make([]item, 0, len(keys))
+	for _, key := range keys {
+		prefixed := c.prefixKey(key)
+		if _, dup := original[prefixed]; dup {
+			continue
+		}
+		original[prefixed] = key
+		pendingKeys = append(pendingKeys, c.keyItem(key))
+	}
+
+	now := time.Now()
+	for len(pendingKeys) > 0 {
+		n := min(len(pendingKeys), batchGetLimit)
+		batch := pendingKeys[:n]
+		pendingKeys = pendingKeys[n:]
+		for len(batch) > 0 {
+			var out struct {
+				Responses       map[string][]item `json:"Responses"`
+				UnprocessedKeys map[string]struct {
+					Keys []item `json:"Keys"`
+				} `json:"UnprocessedKeys"`
+			}
+			err := c.call(ctx, "BatchGetItem", map[string]any{
+				"RequestItems": map[string]any{
+					c.cfg.Table: map[string]any{"Keys": batch},
+				},
+			}, &out)
+			if err != nil {
+				c.errors.Add(1)
+				return result, fmt.Errorf("dynamodb get multi: %w", err)
+			}
+			for _, it := range out.Responses[c.cfg.Table] {
+				k := it[c.cfg.KeyAttribute].S
+				if k == nil {
+					continue
+				}
+				if val, ok := c.value(it, now); ok {
+					result[original[*k]] = val
+				}
+			}
+			batch = out.UnprocessedKeys[c.cfg.Table].Keys
+			if len(batch) > 0 && ctx.Err() != nil {
+				return result, fmt.Errorf("dynamodb get multi: %w", ctx.Err())
+			}
+		}
+	}
+
+	c.hits.Add(int64(len(result)))
+	c.misses.Add(int64(len(original) - len(result)))
+	return result, nil
+}
+
+// Ping checks that the table exists and is reachable.
+func (c *Cache) Ping(ctx context.Context) error {
+	return c.call(ctx, "DescribeTable", map[string]any{"TableName": c.cfg.Table}, nil)
+}
+
+// Close releases idle HTTP connections.
+func (c *Cache) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// Stats returns cache statistics.
+func (c *Cache) Stats() cache.Stats {
+	hits := c.hits.Load()
+	misses := c.misses.Load()
+	total := hits + misses
+
+	var hitRate float64
+	if total > 0 {
+		hitRate = float64(hits) / float64(total)
+	}
+
+	return cache.Stats{
+		Hits:    hits,
+		Misses:  misses,
+		Sets:    c.sets.Load(),
+		Errors:  c.errors.Load(),
+		HitRate: hitRate,
+	}
+}
@@ -0,0 +1,187 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/cache"
+)
+
+// fakeTable is an in-memory DynamoDB table serving the JSON API operations
+// used by the cache.
+type fakeTable struct {
+	t     *testing.T
+	name  string
+	mu    sync.Mutex
+	items map[string]item
+	calls map[string]int
+}
+
+func (f *fakeTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	assert.Contains(f.t, r.Header.Get("Authorization"), "AWS4-HMAC-SHA256")
+	assert.Equal(f.t, "application/x-amz-json-1.0", r.Header.Get("Content-Type"))
+	op := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), targetPrefix)
+	body, _ := io.ReadAll(r.Body)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[op]++
+
+	var in struct {
+		TableName    string
+		Key          item
+		Item         item
+		RequestItems map[string]json.RawMessage
+	}
+	require.NoError(f.t, json.Unmarshal(body, &in))
+
+	if in.TableName != "" && in.TableName != f.name {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ResourceNotFoundException","message":"Requested resource not found"}`))
+		return
+	}
+
+	var out any = map[string]any{}
+	switch op {
+	case "DescribeTable":
+	case "GetItem":
+		if it, ok := f.items[*in.Key["key"].S]; ok {
+			out = map[string]any{"Item": it}
+		}
+	case "PutItem":
+		f.items[*in.Item["key"].S] = in.Item
+	case "DeleteItem":
+		delete(f.items, *in.Key["key"].S)
+	case "BatchWriteItem":
+		var reqs []writeRequest
+		require.NoError(f.t, json.Unmarshal(in.RequestItems[f.name], &reqs))
+		require.LessOrEqual(f.t, len(reqs), batchWriteLimit)
+		for _, wr := range reqs {
+			f.items[*wr.PutRequest.Item["key"].S] = wr.PutRequest.Item
+		}
+	case "BatchGetItem":
+		var req struct{ Keys []item }
+		require.NoError(f.t, json.Unmarshal(in.RequestItems[f.name], &req))
+		require.LessOrEqual(f.t, len(req.Keys), batchGetLimit)
+		found := []item{}
+		for _, k := range req.Keys {
+			if it, ok := f.items[*k["key"].S]; ok {
+				found = append(found, it)
+			}
+		}
+		out = map[string]any{"Responses": map[string][]item{f.name: found}}
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func newTestCache(t *testing.T) (*Cache, *fakeTable) {
+	t.Helper()
+	table := &fakeTable{t: t, name: "cache", items: make(map[string]item), calls: make(map[string]int)}
+	srv := httptest.NewServer(table)
+	t.Cleanup(srv.Close)
+
+	cfg := DefaultConfig()
+	cfg.Table = "cache"
+	cfg.Endpoint = srv.URL
+	awsCfg := aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	}
+	c, err := NewWithAWSConfig(cfg, awsCfg, srv.Client())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c, table
+}
+
+func TestCache_SetGetDelete(t *testing.T) {
+	c, table := newTestCache(t)
+	ctx := context.Background()
+
+	val, err := c.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	require.NoError(t, c.Set(ctx, "k", []byte{0, 1, 2, 'x'}, time.Minute))
+	stored := table.items["llmux:k"]
+	require.NotNil(t, stored)
+	exp, err := strconv.ParseInt(*stored["expires_at"].N, 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Now().Add(time.Minute).Unix(), exp, 2)
+
+	val, err = c.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2, 'x'}, val)
+
+	require.NoError(t, c.Delete(ctx, "k"))
+	val, err = c.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	stats := c.Stats()
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(2), stats.Misses)
+	assert.Equal(t, int64(1), stats.Sets)
+}
+
+func TestCache_IgnoresExpiredItems(t *testing.T) {
+	c, table := newTestCache(t)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "k", []byte("v"), time.Minute))
+	past := strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)
+	it := table.items["llmux:k"]
+	it["expires_at"] = attributeValue{N: &past}
+
+	val, err := c.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Nil(t, val, "items past their expiry must not be served before DynamoDB deletes them")
+}
+
+func TestCache_BatchesRespectLimits(t *testing.T) {
+	c, table := newTestCache(t)
+	ctx := context.Background()
+
+	var entries []cache.Entry
+	var keys []string
+	for i := 0; i < 120; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		keys = append(keys, key)
+		entries = append(entries, cache.Entry{Key: key, Value: []byte(key), TTL: time.Minute})
+	}
+	require.NoError(t, c.SetPipeline(ctx, entries))
+	assert.Len(t, table.items, 120)
+	assert.Equal(t, 5, table.calls["BatchWriteItem"])
+
+	got, err := c.GetMulti(ctx, append(keys, "absent", "key-0"))
+	require.NoError(t, err)
+	assert.Len(t, got, 120)
+	assert.Equal(t, []byte("key-7"), got["key-7"])
+	assert.Equal(t, 2, table.calls["BatchGetItem"])
+}
+
+func TestNew_FailsForMissingTable(t *testing.T) {
+	c, _ := newTestCache(t)
+
+	cfg := c.cfg
+	cfg.Table = "other"
+	_, err := NewWithAWSConfig(cfg, c.awsCfg, c.httpClient)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ResourceNotFoundException")
+}
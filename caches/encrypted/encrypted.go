@@ -0,0 +1,119 @@
+// Package encrypted provides a cache wrapper that encrypts values at rest
+// with per-tenant envelope encryption.
+package encrypted
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blueberrycongee/llmux/pkg/cache"
+	"github.com/blueberrycongee/llmux/pkg/encryption"
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+// TenantFunc returns the tenant whose data key protects values stored under ctx.
+type TenantFunc func(ctx context.Context) string
+
+// Cache encrypts values before they reach the wrapped cache and decrypts them
+// on read. Values that cannot be decrypted for the requesting tenant,
+// including plaintext written before encryption was enabled, are treated as
+// misses.
+type Cache struct {
+	inner   cache.Cache
+	keyring *encryption.Keyring
+	tenant  TenantFunc
+}
+
+// New wraps inner. A nil tenant func uses the routing tenant scope of the
+// request context (the API key ID in gateway mode).
+func New(inner cache.Cache, keyring *encryption.Keyring, tenant TenantFunc) *Cache {
+	if tenant == nil {
+		tenant = router.TenantScopeFromContext
+	}
+	return &Cache{inner: inner, keyring: keyring, tenant: tenant}
+}
+
+// Get retrieves and decrypts a value.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.inner.Get(ctx, key)
+	if err != nil || data == nil {
+		return data, err
+	}
+	return c.open(ctx, data)
+}
+
+// Set encrypts and stores a value.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	sealed, err := c.keyring.Encrypt(c.tenant(ctx), value)
+	if err != nil {
+		return err
+	}
+	return c.inner.Set(ctx, key, sealed, ttl)
+}
+
+// Delete removes a key.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	return c.inner.Delete(ctx, key)
+}
+
+// SetPipeline encrypts and stores multiple entries under the tenant of ctx.
+func (c *Cache) SetPipeline(ctx context.Context, entries []cache.Entry) error {
+	tenant := c.tenant(ctx)
+	sealed := make([]cache.Entry, len(entries))
+	for i, entry := range entries {
+		value, err := c.keyring.Encrypt(tenant, entry.Value)
+		if err != nil {
+			return err
+		}
+		sealed[i] = cache.Entry{Key: entry.Key, Value: value, TTL: entry.TTL}
+	}
+	return c.inner.SetPipeline(ctx, sealed)
+}
+
+// GetMulti retrieves and decrypts multiple keys; undecryptable values are
+// omitted like missing keys.
+func (c *Cache) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values, err := c.inner.GetMulti(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]byte, len(values))
+	for key, data := range values {
+		plaintext, err := c.open(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		if plaintext != nil {
+			out[key] = plaintext
+		}
+	}
+	return out, nil
+}
+
+// Ping checks the wrapped cache.
+func (c *Cache) Ping(ctx context.Context) error {
+	return c.inner.Ping(ctx)
+}
+
+// Close closes the wrapped cache.
+func (c *Cache) Close() error {
+	return c.inner.Close()
+}
+
+// Stats returns the wrapped cache statistics.
+func (c *Cache) Stats() cache.Stats {
+	return c.inner.Stats()
+}
+
+func (c *Cache) open(ctx context.Context, data []byte) ([]byte, error) {
+	plaintext, err := c.keyring.Decrypt(c.tenant(ctx), data)
+	switch {
+	case err == nil:
+		return plaintext, nil
+	case errors.Is(err, encryption.ErrNotEncrypted), errors.Is(err, encryption.ErrDecrypt), errors.Is(err, encryption.ErrUnknownKey):
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
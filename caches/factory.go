@@ -1,12 +1,16 @@
 // Package caches provides public cache implementations for LLMux library mode.
-// It includes memory, redis, and dual-tier cache backends.
+// It includes memory, redis, dual-tier, memcached and dynamodb cache backends.
 package caches
 
 import (
 	"github.com/blueberrycongee/llmux/caches/dual"
+	"github.com/blueberrycongee/llmux/caches/dynamodb"
+	"github.com/blueberrycongee/llmux/caches/encrypted"
+	"github.com/blueberrycongee/llmux/caches/memcached"
 	"github.com/blueberrycongee/llmux/caches/memory"
 	"github.com/blueberrycongee/llmux/caches/redis"
 	"github.com/blueberrycongee/llmux/pkg/cache"
+	"github.com/blueberrycongee/llmux/pkg/encryption"
 )
 
 // Type re-exports cache types for convenience.
@@ -14,9 +18,11 @@ type Type = cache.Type
 
 // Cache type constants.
 const (
-	TypeLocal = cache.TypeLocal
-	TypeRedis = cache.TypeRedis
-	TypeDual  = cache.TypeDual
+	TypeLocal     = cache.TypeLocal
+	TypeRedis     = cache.TypeRedis
+	TypeDual      = cache.TypeDual
+	TypeMemcached = cache.TypeMemcached
+	TypeDynamoDB  = cache.TypeDynamoDB
 )
 
 // NewMemory creates a new in-memory cache with the given configuration.
@@ -56,16 +62,39 @@ func NewDualDefault() (*dual.Cache, error) {
 	return dual.New(local, remote, dual.DefaultConfig()), nil
 }
 
+// NewMemcached creates a new Memcached cache with the given configuration.
+// Returns error if any server is unreachable.
+func NewMemcached(cfg memcached.Config) (*memcached.Cache, error) {
+	return memcached.New(cfg)
+}
+
+// NewDynamoDB creates a new DynamoDB cache with the given configuration,
+// using credentials from the default AWS config chain.
+// Returns error if the table is not reachable.
+func NewDynamoDB(cfg dynamodb.Config) (*dynamodb.Cache, error) {
+	return dynamodb.New(cfg)
+}
+
+// NewEncrypted wraps a cache so values are envelope-encrypted at rest with
+// per-tenant data keys.
+func NewEncrypted(inner cache.Cache, keyring *encryption.Keyring) *encrypted.Cache {
+	return encrypted.New(inner, keyring, nil)
+}
+
 // Re-export config types for convenience.
 type (
-	MemoryConfig = memory.Config
-	RedisConfig  = redis.Config
-	DualConfig   = dual.Config
+	MemoryConfig    = memory.Config
+	RedisConfig     = redis.Config
+	DualConfig      = dual.Config
+	MemcachedConfig = memcached.Config
+	DynamoDBConfig  = dynamodb.Config
 )
 
 // Re-export default config functions.
 var (
-	DefaultMemoryConfig = memory.DefaultConfig
-	DefaultRedisConfig  = redis.DefaultConfig
-	DefaultDualConfig   = dual.DefaultConfig
+	DefaultMemoryConfig    = memory.DefaultConfig
+	DefaultRedisConfig     = redis.DefaultConfig
+	DefaultDualConfig      = dual.DefaultConfig
+	DefaultMemcachedConfig = memcached.DefaultConfig
+	DefaultDynamoDBConfig  = dynamodb.DefaultConfig
 )
@@ -0,0 +1,444 @@
+// Package memcached provides a Memcached-based cache implementation.
+// It speaks the Memcached text protocol directly and shards keys across
+// servers by hash.
+package memcached
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/blueberrycongee/llmux/pkg/cache"
+)
+
+// maxKeyLength is the Memcached key length limit.
+const maxKeyLength = 250
+
+// maxRelativeTTL is the largest expiration Memcached treats as relative;
+// longer TTLs must be sent as absolute Unix timestamps.
+const maxRelativeTTL = 30 * 24 * time.Hour
+
+// ErrServerError is returned when Memcached answers with an error line.
+var ErrServerError = errors.New("memcached server error")
+
+// Cache implements cache.Cache interface using Memcached as backend.
+type Cache struct {
+	servers    []*server
+	namespace  string
+	defaultTTL time.Duration
+
+	// Statistics
+	hits   atomic.Int64
+	misses atomic.Int64
+	sets   atomic.Int64
+	errors atomic.Int64
+}
+
+// Config holds configuration for Memcached Cache.
+type Config struct {
+	Addrs        []string      `yaml:"addrs"`          // Server addresses (e.g., "localhost:11211")
+	Namespace    string        `yaml:"namespace"`      // Key namespace prefix
+	DefaultTTL   time.Duration `yaml:"default_ttl"`    // Default TTL (default: 1 hour)
+	Timeout      time.Duration `yaml:"timeout"`        // Dial and I/O timeout (default: 1 second)
+	MaxIdleConns int           `yaml:"max_idle_conns"` // Idle connections kept per server (default: 4)
+}
+
+// DefaultConfig returns sensible defaults.
+func DefaultConfig() Config {
+	return Config{
+		Addrs:        []string{"localhost:11211"},
+		Namespace:    "llmux",
+		DefaultTTL:   time.Hour,
+		Timeout:      time.Second,
+		MaxIdleConns: 4,
+	}
+}
+
+// New creates a new Memcached cache client and verifies every server responds.
+func New(cfg Config) (*Cache, error) {
+	if len(cfg.Addrs) == 0 {
+		return nil, errors.New("memcached: at least one address is required")
+	}
+	if cfg.DefaultTTL <= 0 {
+		cfg.DefaultTTL = time.Hour
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = time.Second
+	}
+	if cfg.MaxIdleConns <= 0 {
+		cfg.MaxIdleConns = 4
+	}
+
+	c := &Cache{namespace: cfg.Namespace, defaultTTL: cfg.DefaultTTL}
+	for _, addr := range cfg.Addrs {
+		c.servers = append(c.servers, &server{
+			addr:    addr,
+			timeout: cfg.Timeout,
+			idle:    make(chan *conn, cfg.MaxIdleConns),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	if err := c.Ping(ctx); err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("memcached ping failed: %w", err)
+	}
+	return c, nil
+}
+
+// itemKey maps a cache key to a valid Memcached key: namespaced, without
+// whitespace or control characters, and at most 250 bytes.
+func (c *Cache) itemKey(key string) string {
+	if c.namespace != "" {
+		key = c.namespace + ":" + key
+	}
+	if len(key) <= maxKeyLength && !strings.ContainsFunc(key, func(r rune) bool { return r <= ' ' || r == 0x7f }) {
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return c.namespace + ":h:" + hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) serverFor(itemKey string) *server {
+	if len(c.servers) == 1 {
+		return c.servers[0]
+	}
+	return c.servers[crc32.ChecksumIEEE([]byte(itemKey))%uint32(len(c.servers))]
+}
+
+// expiration converts a TTL to the Memcached exptime field. Relative TTLs
+// are rounded up to whole seconds, since an exptime of 0 never expires.
+func expiration(ttl time.Duration) int64 {
+	if ttl > maxRelativeTTL {
+		return time.Now().Add(ttl).Unix()
+	}
+	return int64((ttl + time.Second - 1) / time.Second)
+}
+
+// Get retrieves a value from Memcached.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	itemKey := c.itemKey(key)
+	values, err := c.serverFor(itemKey).get(ctx, []string{itemKey})
+	if err != nil {
+		c.errors.Add(1)
+		return nil, fmt.Errorf("memcached get: %w", err)
+	}
+	val, ok := values[itemKey]
+	if !ok {
+		c.misses.Add(1)
+		return nil, nil
+	}
+	c.hits.Add(1)
+	return val, nil
+}
+
+// Set stores a value in Memcached with TTL.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = c.defaultTTL
+	}
+	itemKey := c.itemKey(key)
+	if err := c.serverFor(itemKey).set(ctx, itemKey, value, expiration(ttl)); err != nil {
+		c.errors.Add(1)
+		return fmt.Errorf("memcached set: %w", err)
+	}
+	c.sets.Add(1)
+	return nil
+}
+
+// Delete removes a key from Memcached.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	itemKey := c.itemKey(key)
+	if err := c.serverFor(itemKey).delete(ctx, itemKey); err != nil {
+		c.errors.Add(1)
+		return fmt.Errorf("memcached delete: %w", err)
+	}
+	return nil
+}
+
+// SetPipeline stores multiple entries.
+func (c *Cache) SetPipeline(ctx context.Context, entries []cache.Entry) error {
+	for _, e := range entries {
+		if err := c.Set(ctx, e.Key, e.Value, e.TTL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMulti retrieves multiple keys with one request per server.
+func (c *Cache) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	result := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	byServer := make(map[*server][]string)
+	original := make(map[string]string, len(keys))
+	for _, key := range keys {
+		itemKey := c.itemKey(key)
+		original[itemKey] = key
+		srv := c.serverFor(itemKey)
+		byServer[srv] = append(byServer[srv], itemKey)
+	}
+
+	for srv, itemKeys := range byServer {
+		values, err := srv.get(ctx, itemKeys)
+		if err != nil {
+			c.errors.Add(1)
+			return result, fmt.Errorf("memcached get: %w", err)
+		}
+		for itemKey, val := range values {
+			result[original[itemKey]] = val
+		}
+	}
+
+	c.hits.Add(int64(len(result)))
+	c.misses.Add(int64(len(keys) - len(result)))
+	return result, nil
+}
+
+// Ping checks that every server responds.
+func (c *Cache) Ping(ctx context.Context) error {
+	for _, srv := range c.servers {
+		if err := srv.version(ctx); err != nil {
+			return fmt.Errorf("%s: %w", srv.addr, err)
+		}
+	}
+	return nil
+}
+
+// Close closes idle connections.
+func (c *Cache) Close() error {
+	for _, srv := range c.servers {
+		srv.close()
+	}
+	return nil
+}
+
+// Stats returns cache statistics.
+func (c *Cache) Stats() cache.Stats {
+	hits := c.hits.Load()
+	misses := c.misses.Load()
+	total := hits + misses
+
+	var hitRate float64
+	if total > 0 {
+		hitRate = float64(hits) / float64(total)
+	}
+
+	return cache.Stats{
+		Hits:    hits,
+		Misses:  misses,
+		Sets:    c.sets.Load(),
+		Errors:  c.errors.Load(),
+		HitRate: hitRate,
+	}
+}
+
+// server is one Memcached node with a small idle connection pool.
+type server struct {
+	addr    string
+	timeout time.Duration
+
+	mu     sync.Mutex
+	closed bool
+	idle   chan *conn
+}
+
+type conn struct {
+	nc net.Conn
+	rw *bufio.ReadWriter
+}
+
+func (s *server) acquire(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-s.idle:
+		return cn, nil
+	default:
+	}
+	d := net.Dialer{Timeout: s.timeout}
+	nc, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{nc: nc, rw: bufio.NewReadWriter(bufio.NewReader(nc), bufio.NewWriter(nc))}, nil
+}
+
+// release returns a healthy connection to the pool; broken ones are closed.
+func (s *server) release(cn *conn, healthy bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if healthy && !s.closed {
+		select {
+		case s.idle <- cn:
+			return
+		default:
+		}
+	}
+	_ = cn.nc.Close()
+}
+
+func (s *server) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.idle)
+	for cn := range s.idle {
+		_ = cn.nc.Close()
+	}
+}
+
+// do runs one request/response exchange on a pooled connection.
+func (s *server) do(ctx context.Context, fn func(rw *bufio.ReadWriter) error) error {
+	cn, err := s.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = cn.nc.SetDeadline(deadline)
+
+	err = fn(cn.rw)
+	// Server error replies leave the connection in a consistent state.
+	s.release(cn, err == nil || errors.Is(err, ErrServerError))
+	return err
+}
+
+func (s *server) get(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	err := s.do(ctx, func(rw *bufio.ReadWriter) error {
+		if _, err := fmt.Fprintf(rw, "get %s\r\n", strings.Join(keys, " ")); err != nil {
+			return err
+		}
+		if err := rw.Flush(); err != nil {
+			return err
+		}
+		for {
+			line, err := readLine(rw.Reader)
+			if err != nil {
+				return err
+			}
+			if line == "END" {
+				return nil
+			}
+			fields := strings.Fields(line)
+			if len(fields) < 4 || fields[0] != "VALUE" {
+				return replyError(line)
+			}
+			size, err := strconv.Atoi(fields[3])
+			if err != nil || size < 0 {
+				return fmt.Errorf("malformed value line %q", line)
+			}
+			buf := make([]byte, size+2)
+			if _, err := io.ReadFull(rw.Reader, buf); err != nil {
+				return err
+			}
+			if !bytes.HasSuffix(buf, []byte("\r\n")) {
+				return fmt.Errorf("malformed value for %q", fields[1])
+			}
+			values[fields[1]] = buf[:size]
+		}
+	})
+	return values, err
+}
+
+func (s *server) set(ctx context.Context, key string, value []byte, exptime int64) error {
+	return s.do(ctx, func(rw *bufio.ReadWriter) error {
+		if _, err := fmt.Fprintf(rw, "set %s 0 %d %d\r\n", key, exptime, len(value)); err != nil {
+			return err
+		}
+		if _, err := rw.Write(value); err != nil {
+			return err
+		}
+		if _, err := rw.WriteString("\r\n"); err != nil {
+			return err
+		}
+		if err := rw.Flush(); err != nil {
+			return err
+		}
+		line, err := readLine(rw.Reader)
+		if err != nil {
+			return err
+		}
+		if line != "STORED" {
+			return replyError(line)
+		}
+		return nil
+	})
+}
+
+func (s *server) delete(ctx context.Context, key string) error {
+	return s.do(ctx, func(rw *bufio.ReadWriter) error {
+		if _, err := fmt.Fprintf(rw, "delete %s\r\n", key); err != nil {
+			return err
+		}
+		if err := rw.Flush(); err != nil {
+			return err
+		}
+		line, err := readLine(rw.Reader)
+		if err != nil {
+			return err
+		}
+		if line != "DELETED" && line != "NOT_FOUND" {
+			return replyError(line)
+		}
+		return nil
+	})
+}
+
+func (s *server) version(ctx context.Context) error {
+	return s.do(ctx, func(rw *bufio.ReadWriter) error {
+		if _, err := rw.WriteString("version\r\n"); err != nil {
+			return err
+		}
+		if err := rw.Flush(); err != nil {
+			return err
+		}
+		line, err := readLine(rw.Reader)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(line, "VERSION ") {
+			return replyError(line)
+		}
+		return nil
+	})
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// replyError converts an unexpected reply line into an error.
+func replyError(line string) error {
+	switch {
+	case line == "ERROR", strings.HasPrefix(line, "CLIENT_ERROR"), strings.HasPrefix(line, "SERVER_ERROR"),
+		line == "NOT_STORED":
+		return fmt.Errorf("%w: %s", ErrServerError, line)
+	default:
+		return fmt.Errorf("unexpected reply %q", line)
+	}
+}
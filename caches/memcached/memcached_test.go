@@ -0,0 +1,216 @@
+package memcached
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/cache"
+)
+
+// fakeServer is a minimal in-process Memcached speaking get/set/delete/version.
+type fakeServer struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	items    map[string][]byte
+	exptimes map[string]int64
+	gets     int
+}
+
+func startFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeServer{ln: ln, items: make(map[string][]byte), exptimes: make(map[string]int64)}
+	go s.serve()
+	t.Cleanup(func() { _ = ln.Close() })
+	return s
+}
+
+func (s *fakeServer) addr() string { return s.ln.Addr().String() }
+
+func (s *fakeServer) serve() {
+	for {
+		nc, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(nc)
+	}
+}
+
+func (s *fakeServer) handle(nc net.Conn) {
+	defer nc.Close()
+	r := bufio.NewReader(nc)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		s.mu.Lock()
+		switch fields[0] {
+		case "version":
+			fmt.Fprint(nc, "VERSION 1.6.0\r\n")
+		case "get":
+			s.gets++
+			for _, key := range fields[1:] {
+				if v, ok := s.items[key]; ok {
+					fmt.Fprintf(nc, "VALUE %s 0 %d\r\n%s\r\n", key, len(v), v)
+				}
+			}
+			fmt.Fprint(nc, "END\r\n")
+		case "set":
+			size, _ := strconv.Atoi(fields[4])
+			exptime, _ := strconv.ParseInt(fields[3], 10, 64)
+			buf := make([]byte, size+2)
+			s.mu.Unlock()
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.items[fields[1]] = buf[:size]
+			s.exptimes[fields[1]] = exptime
+			fmt.Fprint(nc, "STORED\r\n")
+		case "delete":
+			if _, ok := s.items[fields[1]]; ok {
+				delete(s.items, fields[1])
+				fmt.Fprint(nc, "DELETED\r\n")
+			} else {
+				fmt.Fprint(nc, "NOT_FOUND\r\n")
+			}
+		default:
+			fmt.Fprint(nc, "ERROR\r\n")
+		}
+		s.mu.Unlock()
+	}
+}
+
+func newTestCache(t *testing.T, servers ...*fakeServer) *Cache {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.Addrs = nil
+	for _, s := range servers {
+		cfg.Addrs = append(cfg.Addrs, s.addr())
+	}
+	c, err := New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestCache_SetGetDelete(t *testing.T) {
+	srv := startFakeServer(t)
+	c := newTestCache(t, srv)
+	ctx := context.Background()
+
+	val, err := c.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	require.NoError(t, c.Set(ctx, "k", []byte("line1\r\nline2"), time.Minute))
+	val, err = c.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("line1\r\nline2"), val)
+	assert.Equal(t, int64(60), srv.exptimes["llmux:k"])
+
+	require.NoError(t, c.Delete(ctx, "k"))
+	require.NoError(t, c.Delete(ctx, "k"), "deleting a missing key is not an error")
+	val, err = c.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	stats := c.Stats()
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(2), stats.Misses)
+	assert.Equal(t, int64(1), stats.Sets)
+}
+
+func TestCache_LongTTLUsesAbsoluteExpiry(t *testing.T) {
+	srv := startFakeServer(t)
+	c := newTestCache(t, srv)
+
+	require.NoError(t, c.Set(context.Background(), "k", []byte("v"), 60*24*time.Hour))
+	assert.Greater(t, srv.exptimes["llmux:k"], time.Now().Unix())
+}
+
+func TestCache_SubSecondTTLExpires(t *testing.T) {
+	srv := startFakeServer(t)
+	c := newTestCache(t, srv)
+
+	require.NoError(t, c.Set(context.Background(), "k", []byte("v"), 200*time.Millisecond))
+	assert.Equal(t, int64(1), srv.exptimes["llmux:k"])
+	require.NoError(t, c.Set(context.Background(), "k", []byte("v"), 1500*time.Millisecond))
+	assert.Equal(t, int64(2), srv.exptimes["llmux:k"])
+}
+
+func TestCache_HashesInvalidKeys(t *testing.T) {
+	srv := startFakeServer(t)
+	c := newTestCache(t, srv)
+	ctx := context.Background()
+
+	long := strings.Repeat("x", 300)
+	for _, key := range []string{long, "has space", "tab\tkey"} {
+		require.NoError(t, c.Set(ctx, key, []byte(key), time.Minute))
+		val, err := c.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(key), val)
+	}
+	for key := range srv.items {
+		assert.LessOrEqual(t, len(key), maxKeyLength)
+		assert.NotContains(t, key, " ")
+	}
+}
+
+func TestCache_GetMultiShardsAcrossServers(t *testing.T) {
+	a, b := startFakeServer(t), startFakeServer(t)
+	c := newTestCache(t, a, b)
+	ctx := context.Background()
+
+	var entries []cache.Entry
+	var keys []string
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		keys = append(keys, key)
+		entries = append(entries, cache.Entry{Key: key, Value: []byte(key), TTL: time.Minute})
+	}
+	require.NoError(t, c.SetPipeline(ctx, entries))
+	assert.NotEmpty(t, a.items)
+	assert.NotEmpty(t, b.items)
+
+	a.gets, b.gets = 0, 0
+	got, err := c.GetMulti(ctx, append(keys, "absent"))
+	require.NoError(t, err)
+	assert.Len(t, got, 20)
+	for _, key := range keys {
+		assert.Equal(t, []byte(key), got[key])
+	}
+	assert.Equal(t, 1, a.gets, "one multi-get per server")
+	assert.Equal(t, 1, b.gets, "one multi-get per server")
+}
+
+func TestNew_FailsWhenServerUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	cfg := DefaultConfig()
+	cfg.Addrs = []string{addr}
+	_, err = New(cfg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package llmux
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/resilience"
+	"github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/provider"
+)
+
+// CircuitBreakerConfig configures the per-provider circuit breaker, which
+// counts connection-level failures to a provider. HTTP error responses are
+// handled by the router's cooldown instead.
+type CircuitBreakerConfig struct {
+	// Disabled turns the breaker off.
+	Disabled bool
+	// FailureThreshold is the consecutive failures that open the circuit (0 = 5).
+	FailureThreshold int
+	// SuccessThreshold is the successful probes that close it again (0 = 2).
+	SuccessThreshold int
+	// OpenTimeout is how long the circuit stays open before probing (0 = 30s).
+	OpenTimeout time.Duration
+}
+
+// resilienceConfig returns the resilience manager settings for cfg.
+func (cfg CircuitBreakerConfig) resilienceConfig() resilience.ManagerConfig {
+	managerCfg := resilience.DefaultManagerConfig()
+	cb := &managerCfg.CircuitBreaker
+	cb.FailureThreshold = valueOr(cfg.FailureThreshold, cb.FailureThreshold)
+	cb.SuccessThreshold = valueOr(cfg.SuccessThreshold, cb.SuccessThreshold)
+	cb.Timeout = valueOr(cfg.OpenTimeout, cb.Timeout)
+	if cb.HalfOpenMaxRequests < cb.SuccessThreshold {
+		cb.HalfOpenMaxRequests = cb.SuccessThreshold
+	}
+	return managerCfg
+}
+
+// allowUpstream checks the provider's circuit breaker before an upstream call.
+// An open circuit fails fast with a 503 that is only retried when fallback can
+// move the request to another deployment.
+func (c *Client) allowUpstream(deployment *provider.Deployment) error {
+	if c.config.CircuitBreaker.Disabled || c.resilienceManager == nil {
+		return nil
+	}
+	if c.resilienceManager.GetCircuitBreaker(deployment.ProviderName).Allow() {
+		return nil
+	}
+	llmErr := errors.NewServiceUnavailableError(deployment.ProviderName, deployment.ModelName,
+		fmt.Sprintf("circuit breaker open for provider %s", deployment.ProviderName))
+	llmErr.Retryable = c.config.FallbackEnabled
+	return llmErr
+}
+
+// recordUpstream records the outcome of an upstream call allowed by
+// allowUpstream. Only transport errors count as failures; requests canceled
+// by the caller are not counted.
+func (c *Client) recordUpstream(ctx context.Context, deployment *provider.Deployment, err error) {
+	if c.config.CircuitBreaker.Disabled || c.resilienceManager == nil {
+		return
+	}
+	cb := c.resilienceManager.GetCircuitBreaker(deployment.ProviderName)
+	switch {
+	case err == nil:
+		cb.RecordSuccess()
+	case ctx.Err() != nil:
+		cb.Cancel()
+	default:
+		before := cb.State()
+		cb.RecordFailure()
+		if before != resilience.StateOpen && cb.State() == resilience.StateOpen {
+			c.logger.Warn("provider circuit opened",
+				"provider", deployment.ProviderName,
+				"error", err,
+			)
+		}
+	}
+}
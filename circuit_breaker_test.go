@@ -0,0 +1,64 @@
+package llmux
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/provider"
+)
+
+func TestClient_CircuitBreakerShortCircuitsConnectionFailures(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	deadURL := "http://" + ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	client, err := New(
+		WithProviderInstance("primary", &httpMockProvider{name: "primary", models: []string{"test-model"}, baseURL: deadURL}, []string{"test-model"}),
+		withTestPricing(t, "test-model"),
+		WithRetry(0, 0),
+		WithFallback(false),
+		WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	req := &ChatRequest{Model: "test-model", Messages: []ChatMessage{{Role: "user", Content: jsonString("hi")}}}
+	for i := 0; i < 2; i++ {
+		_, err := client.ChatCompletion(context.Background(), req)
+		require.Error(t, err)
+		var llmErr *errors.LLMError
+		require.False(t, stderrors.As(err, &llmErr), "connection failure should not be a circuit error")
+	}
+	require.Equal(t, "open", client.ResilienceStats("primary").CircuitState)
+
+	_, err = client.ChatCompletion(context.Background(), req)
+	var llmErr *errors.LLMError
+	require.True(t, stderrors.As(err, &llmErr))
+	require.Equal(t, http.StatusServiceUnavailable, llmErr.StatusCode)
+	require.False(t, llmErr.Retryable)
+}
+
+func TestClient_CircuitBreakerDisabled(t *testing.T) {
+	client, err := New(
+		WithProviderInstance("primary", &httpMockProvider{name: "primary", models: []string{"test-model"}, baseURL: "http://example.invalid"}, []string{"test-model"}),
+		withTestPricing(t, "test-model"),
+		WithCircuitBreaker(CircuitBreakerConfig{Disabled: true}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	deployment := &provider.Deployment{ProviderName: "primary", ModelName: "test-model"}
+	for i := 0; i < 10; i++ {
+		require.NoError(t, client.allowUpstream(deployment))
+		client.recordUpstream(context.Background(), deployment, stderrors.New("connection refused"))
+	}
+	require.Empty(t, client.ResilienceStats("primary").CircuitState)
+}
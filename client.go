@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"log/slog"
+	"math"
 	"math/rand"
 	"net/http"
 	"sort"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/blueberrycongee/llmux/internal/auth"
 	"github.com/blueberrycongee/llmux/internal/httputil"
+	"github.com/blueberrycongee/llmux/internal/memory"
 	"github.com/blueberrycongee/llmux/internal/metrics"
 	"github.com/blueberrycongee/llmux/internal/observability"
 	"github.com/blueberrycongee/llmux/internal/plugin"
@@ -36,17 +38,31 @@ import (
 // Client is safe for concurrent use by multiple goroutines.
 type Client struct {
 	providers        map[string]provider.Provider
-	deployments      map[string][]*provider.Deployment // model -> deployments
+	deployments      map[string][]*provider.Deployment  // model -> deployments
+	deploymentConfig map[string]router.DeploymentConfig // deployment ID -> routing config
 	router           router.Router
 	cache            cache.Cache
 	cacheTypeLabel   string
-	httpClient       *http.Client
-	streamHTTPClient *http.Client
+	prefixCache      *prefixCache
+	negativeCache    *negativeCache
+	sessionMemory    *SessionMemory
+	longTermMemory   *LongTermMemory
+	memoryModel      string // embedding model of long-term memory
+	memoryQuotas     *MemoryQuotaConfig
+	memoryRetention  MemoryRetention
+	ingestChunking   ChunkConfig
+	ingestExtractors map[string]Extractor
+	defaultHTTP      *http.Client
+	providerHTTP     map[string]*http.Client // provider name -> own transport
 	logger           *slog.Logger
 	config           *ClientConfig
 	pricing          *pricing.Registry
+	unpricedWarned   sync.Map                                // "provider/model" -> struct{}
+	credentials      map[string]*provider.CredentialRollover // provider name -> rollover
 	pipeline         *plugin.Pipeline
 	fallbackReporter FallbackReporter
+	activeStreams    sync.Map // *StreamReader -> *ActiveStream
+	routingTraces    *routingTraceLog
 
 	// Provider factories for creating providers from config
 	factories map[string]provider.Factory
@@ -85,16 +101,25 @@ func New(opts ...Option) (*Client, error) {
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	if err := validateVirtualModels(cfg.VirtualModels); err != nil {
+		return nil, err
+	}
+	if err := validateMaxTokensPolicy(cfg.MaxTokensPolicy); err != nil {
+		return nil, err
+	}
 
 	c := &Client{
 		providers:         make(map[string]provider.Provider),
 		deployments:       make(map[string][]*provider.Deployment),
+		deploymentConfig:  make(map[string]router.DeploymentConfig),
+		credentials:       make(map[string]*provider.CredentialRollover),
+		providerHTTP:      make(map[string]*http.Client),
 		factories:         make(map[string]provider.Factory),
 		config:            cfg,
 		logger:            cfg.Logger,
-		pricing:           pricing.NewRegistry(),
+		pricing:           cfg.PricingRegistry,
 		fallbackReporter:  cfg.FallbackReporter,
-		resilienceManager: resilience.NewManager(resilience.DefaultManagerConfig()),
+		resilienceManager: resilience.NewManager(cfg.CircuitBreaker.resilienceConfig()),
 		// #nosec G404 -- non-cryptographic randomness for backoff jitter.
 		backoffRand: rand.New(rand.NewSource(time.Now().UnixNano())),
 		requestPool: sync.Pool{
@@ -105,25 +130,21 @@ func New(opts ...Option) (*Client, error) {
 		},
 	}
 
-	// Initialize HTTP client with connection pooling
-	transport := &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     90 * time.Second,
-	}
-	c.httpClient = &http.Client{Transport: transport, Timeout: cfg.Timeout}
-	// Streaming should be controlled via ctx deadlines, not a global http.Client timeout.
-	streamTransport := transport.Clone()
-	if cfg.Timeout > 0 {
-		// Apply the configured timeout to the response headers only (TTFB), so long-running
-		// streams are not killed mid-flight.
-		streamTransport.ResponseHeaderTimeout = cfg.Timeout
+	if cfg.RouterTraceCapacity > 0 {
+		c.routingTraces = newRoutingTraceLog(cfg.RouterTraceCapacity)
 	}
-	c.streamHTTPClient = &http.Client{Transport: streamTransport}
+
+	// Initialize HTTP client with connection pooling
+	c.defaultHTTP = newHTTPClient(newUpstreamTransport(provider.TransportConfig{}))
 
 	// Register built-in provider factories
 	c.registerBuiltinFactories()
 
+	if c.pricing == nil {
+		c.pricing = pricing.NewRegistry()
+		// Routers built from the config share it.
+		cfg.PricingRegistry = c.pricing
+	}
 	// Load custom pricing if provided
 	if cfg.PricingFile != "" {
 		if err := c.pricing.Load(cfg.PricingFile); err != nil {
@@ -156,7 +177,7 @@ func New(opts ...Option) (*Client, error) {
 	// Register deployments with router
 	for _, deployments := range c.deployments {
 		for _, d := range deployments {
-			c.router.AddDeployment(d)
+			c.router.AddDeploymentWithConfig(d, c.deploymentConfig[d.ID])
 		}
 	}
 
@@ -165,6 +186,33 @@ func New(opts ...Option) (*Client, error) {
 		c.cache = cfg.Cache
 		c.cacheTypeLabel = cfg.CacheTypeLabel
 	}
+	if cfg.PrefixCacheEnabled {
+		c.prefixCache = newPrefixCache(cfg.CacheTTL)
+	}
+	if cfg.NegativeCacheTTL > 0 {
+		c.negativeCache = newNegativeCache(cfg.NegativeCacheTTL)
+	}
+	c.sessionMemory = cfg.SessionMemory
+	c.memoryRetention = cfg.MemoryRetention
+	if cfg.LongTermMemory.Store != nil {
+		c.longTermMemory = NewLongTermMemory(cfg.LongTermMemory.Store, c.memoryEmbedder(cfg.LongTermMemory.EmbeddingModel), cfg.LongTermMemory.Recall)
+		c.memoryModel = cfg.LongTermMemory.EmbeddingModel
+		c.ingestChunking = cfg.LongTermMemory.Chunking
+		c.ingestExtractors = memory.DefaultExtractors()
+		for contentType, extractor := range cfg.LongTermMemory.Extractors {
+			c.ingestExtractors[contentType] = extractor
+		}
+	}
+	if cfg.MemoryQuotas != nil {
+		quotas := *cfg.MemoryQuotas
+		if quotas.Ledger == nil {
+			quotas.Ledger = NewMemoryQuotaLedger()
+		}
+		if quotas.SessionTTL == 0 {
+			quotas.SessionTTL = cfg.MemoryRetention.SessionIdle
+		}
+		c.memoryQuotas = &quotas
+	}
 
 	// Initialize distributed rate limiter
 	c.rateLimiterConfig = cfg.RateLimiterConfig
@@ -227,13 +275,21 @@ func (c *Client) ChatCompletion(ctx context.Context, req *ChatRequest) (*ChatRes
 	if len(req.Messages) == 0 {
 		return nil, fmt.Errorf("messages is required")
 	}
+	req = c.expandVirtualModel(req)
+	ctx = c.withTenantScope(ctx)
+	req, sessionTurn, err := c.withSessionHistory(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 
 	// Get plugin context
 	pCtx := c.pipeline.GetContext(ctx, generateRequestID())
 	defer c.pipeline.PutContext(pCtx)
+	pCtx.Model = req.Model
 
 	// Run PreHooks
 	req, sc, _ := c.pipeline.RunPreHooks(pCtx, req)
+	pCtx.Model = req.Model
 	if sc != nil {
 		// Short-circuit
 		if sc.Error != nil {
@@ -260,74 +316,108 @@ func (c *Client) ChatCompletion(ctx context.Context, req *ChatRequest) (*ChatRes
 	}
 
 	apiKey := c.rateLimitAPIKey(ctx)
-	rateLimitKey := c.buildRateLimitKey(req.Model, req.User, apiKey)
+	rateLimitKey, endUserKey := c.buildRateLimitKey(req.Model, req.User, apiKey)
 	promptEstimate := tokenizer.EstimatePromptTokens(canonicalModel, req)
 	estimatedTokens := promptEstimate
 	if req.MaxTokens > 0 {
 		estimatedTokens += req.MaxTokens
 	}
-	if err := c.checkRateLimit(ctx, rateLimitKey, canonicalModel, estimatedTokens); err != nil {
+	if err := c.checkRateLimit(ctx, rateLimitKey, endUserKey, canonicalModel, estimatedTokens); err != nil {
+		return nil, err
+	}
+	if err := c.cachedClientError(ctx, req); err != nil {
 		return nil, err
 	}
 
 	var resp *ChatResponse
-	var err error
+	sandbox := IsSandbox(ctx)
 
 	// Check cache for non-streaming requests (if not handled by plugin)
 	// Note: Ideally cache should be a plugin, but keeping this for backward compatibility
 	// or if built-in cache is preferred.
-	if c.cache != nil && !req.Stream {
+	cacheCtrl, _ := CacheControlFromContext(ctx)
+	if c.cache != nil && !req.Stream && !cacheCtrl.NoCache && !sandbox {
 		if cached, cacheErr := c.getFromCache(ctx, req); cacheErr == nil && cached != nil {
 			resp = cached
 		}
 	}
+	if resp == nil && c.prefixCache != nil && !req.Stream && !sandbox {
+		if c.cache != nil && !cacheCtrl.NoCache {
+			resp = c.prefixCacheLookup(ctx, req)
+		}
+		if resp == nil {
+			ctx = c.observePromptPrefix(ctx, req, canonicalModel)
+		}
+	}
 
 	if resp != nil {
-		provider := ""
+		resp.CacheStatus = CacheStatusHit
+		provider, deploymentID := "", ""
 		if resp.Usage != nil {
-			provider = resp.Usage.Provider
+			provider, deploymentID = resp.Usage.Provider, resp.Usage.Deployment
 		}
-		if pricingErr := c.validatePricing(canonicalModel, provider); pricingErr != nil {
+		if pricingErr := c.validatePricing(deploymentID, canonicalModel, provider); pricingErr != nil {
 			return nil, pricingErr
 		}
 	}
 
 	if resp == nil {
-		// Route to deployment
-		var deployment *provider.Deployment
-		reqCtx := buildRouterRequestContext(req, promptEstimate, req.Stream)
-		deployment, err = c.router.PickWithContext(ctx, reqCtx)
-		if err != nil {
-			err = fmt.Errorf("no available deployment for model %s: %w", req.Model, err)
+		if sandbox {
+			resp = c.sandboxCompletion(req)
+		} else if draftModel, ok := c.speculativeDraftModel(req, canonicalModel); ok {
+			resp, err = c.speculativeCompletion(ctx, req, draftModel, promptEstimate)
 		} else {
-			// Get provider
-			c.mu.RLock()
-			prov, ok := c.providers[deployment.ProviderName]
-			c.mu.RUnlock()
-			if !ok {
-				err = fmt.Errorf("provider %s not found", deployment.ProviderName)
-			} else {
-				// Execute with retry
-				resp, err = c.executeWithRetry(ctx, prov, deployment, req)
-			}
+			resp, err = c.routeAndExecute(ctx, req, promptEstimate)
+		}
+		if err != nil {
+			c.rememberClientError(ctx, req, err)
 		}
 	}
+	if err == nil {
+		resp = c.checkStructuredOutput(ctx, req, resp)
+	}
 
 	// Run PostHooks
 	// We pass the number of plugins that ran in PreHook phase (all of them if no short-circuit)
 	runFrom := c.pipeline.PluginCount()
 	finalResp, finalErr := c.pipeline.RunPostHooks(pCtx, resp, err, runFrom)
 
-	if finalErr == nil && finalResp != nil {
-		// Store in cache if successful and not streaming
-		if c.cache != nil && !req.Stream {
+	if finalErr == nil && finalResp != nil && c.cache != nil && !req.Stream && !sandbox {
+		if finalResp.CacheStatus == "" {
+			finalResp.CacheStatus = CacheStatusMiss
+		}
+		// Store fresh responses unless the caller opted out
+		if finalResp.CacheStatus == CacheStatusMiss && !cacheCtrl.NoStore {
 			c.storeInCache(ctx, req, finalResp)
+			if c.prefixCache != nil {
+				c.prefixCacheStore(ctx, req, finalResp)
+			}
 		}
 	}
+	if finalErr == nil && finalResp != nil && len(finalResp.Choices) > 0 {
+		c.recordSessionTurn(ctx, sessionTurn, &finalResp.Choices[0].Message)
+	}
 
 	return finalResp, finalErr
 }
 
+// routeAndExecute picks a deployment for req and executes it with retries.
+func (c *Client) routeAndExecute(ctx context.Context, req *ChatRequest, promptEstimate int) (*ChatResponse, error) {
+	reqCtx := buildRouterRequestContext(req, promptEstimate, req.Stream)
+	deployment, err := c.pickDeployment(ctx, reqCtx)
+	if err != nil {
+		return nil, routingError(req.Model, req.Tags, err)
+	}
+
+	c.mu.RLock()
+	prov, ok := c.providers[deployment.ProviderName]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("provider %s not found", deployment.ProviderName)
+	}
+	return c.executeWithRetry(ctx, prov, deployment, req)
+}
+
 // ChatCompletionStream sends a streaming chat completion request.
 // Returns a StreamReader that can be used to iterate over response chunks.
 //
@@ -364,7 +454,12 @@ func (c *Client) ChatCompletionStream(ctx context.Context, req *ChatRequest) (*S
 	}
 
 	req.Stream = true
+	req = c.expandVirtualModel(req)
 	ctx = c.withTenantScope(ctx)
+	req, sessionTurn, err := c.withSessionHistory(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 
 	// Get plugin context
 	pCtx := c.pipeline.GetContext(ctx, generateRequestID())
@@ -392,23 +487,39 @@ func (c *Client) ChatCompletionStream(ctx context.Context, req *ChatRequest) (*S
 	}
 
 	apiKey := c.rateLimitAPIKey(ctx)
-	rateLimitKey := c.buildRateLimitKey(req.Model, req.User, apiKey)
+	rateLimitKey, endUserKey := c.buildRateLimitKey(req.Model, req.User, apiKey)
 	promptEstimate := tokenizer.EstimatePromptTokens(canonicalModel, req)
 	estimatedTokens := promptEstimate
 	if req.MaxTokens > 0 {
 		estimatedTokens += req.MaxTokens
 	}
-	if err := c.checkRateLimit(ctx, rateLimitKey, canonicalModel, estimatedTokens); err != nil {
+	if err := c.checkRateLimit(ctx, rateLimitKey, endUserKey, canonicalModel, estimatedTokens); err != nil {
+		c.pipeline.PutContext(pCtx)
+		return nil, err
+	}
+	if err := c.cachedClientError(ctx, req); err != nil {
 		c.pipeline.PutContext(pCtx)
 		return nil, err
 	}
 
+	if IsSandbox(ctx) {
+		pCtx.Provider = SandboxProviderName
+		stream := newStreamReaderFromChannel(ctx, c, req, c.sandboxStream(req), c.pipeline, pCtx, runFrom)
+		if sessionTurn != nil {
+			stream.onFinish = func(content string) {
+				c.recordSessionTurn(ctx, sessionTurn, &ChatMessage{Role: "assistant", Content: jsonString(content)})
+			}
+		}
+		return stream, nil
+	}
+
 	var lastErr error
 	var deployment *provider.Deployment
 	var pendingFallback *fallbackAttempt
 
 	// Retry loop
-	for attempt := 0; attempt <= c.config.RetryCount; attempt++ {
+	retryCount := c.retryCount()
+	for attempt := 0; attempt <= retryCount; attempt++ {
 		if attempt > 0 {
 			backoff := c.retryBackoff(attempt)
 			if backoff > 0 {
@@ -431,9 +542,12 @@ func (c *Client) ChatCompletionStream(ctx context.Context, req *ChatRequest) (*S
 		// 3. We don't have a deployment yet (e.g. previous pick failed)
 		if attempt == 0 || c.config.FallbackEnabled || deployment == nil {
 			reqCtx := buildRouterRequestContext(req, promptEstimate, true)
-			newDeployment, err := c.router.PickWithContext(ctx, reqCtx)
+			newDeployment, err := c.pickDeployment(ctx, reqCtx)
 			if err != nil {
-				lastErr = fmt.Errorf("no available deployment for model %s: %w", req.Model, err)
+				lastErr = routingError(req.Model, req.Tags, err)
+				if llmErr, ok := lastErr.(*errors.LLMError); ok && !llmErr.Retryable {
+					break
+				}
 				// If we can't pick a deployment and we don't have one from before, we can't proceed
 				if deployment == nil {
 					continue
@@ -467,7 +581,16 @@ func (c *Client) ChatCompletionStream(ctx context.Context, req *ChatRequest) (*S
 			continue
 		}
 
-		if err := c.validatePricing(req.Model, deployment.ProviderName); err != nil {
+		if err := c.validatePricing(deployment.ID, req.Model, deployment.ProviderName); err != nil {
+			if pendingFallback != nil {
+				c.reportFallback(ctx, pendingFallback.originalModel, pendingFallback.fallbackModel, err, false)
+				pendingFallback = nil
+			}
+			c.pipeline.PutContext(pCtx)
+			return nil, err
+		}
+		upstreamReq, err := c.limitMaxTokens(deployment, req.Model, req)
+		if err != nil {
 			if pendingFallback != nil {
 				c.reportFallback(ctx, pendingFallback.originalModel, pendingFallback.fallbackModel, err, false)
 				pendingFallback = nil
@@ -487,7 +610,7 @@ func (c *Client) ChatCompletionStream(ctx context.Context, req *ChatRequest) (*S
 		}
 
 		// Build and execute request
-		httpReq, err := prov.BuildRequest(ctx, sanitizeChatRequestForProvider(req))
+		httpReq, err := prov.BuildRequest(ctx, sanitizeChatRequestForProvider(upstreamReq))
 		if err != nil {
 			release()
 			if pendingFallback != nil {
@@ -498,13 +621,35 @@ func (c *Client) ChatCompletionStream(ctx context.Context, req *ChatRequest) (*S
 			return nil, fmt.Errorf("build request: %w", err)
 		}
 
-		c.router.ReportRequestStart(ctx, deployment)
+		if err := c.allowUpstream(deployment); err != nil {
+			release()
+			lastErr = err
+			if pendingFallback != nil {
+				c.reportFallback(ctx, pendingFallback.originalModel, pendingFallback.fallbackModel, err, false)
+				pendingFallback = nil
+			}
+			if !c.config.FallbackEnabled {
+				break
+			}
+			continue
+		}
 
-		resp, err := c.streamHTTPClient.Do(httpReq)
+		rt := c.activeRouter()
+		rt.ReportRequestStart(ctx, deployment)
+
+		upstreamCtx, stopHeaderTimeout, cancelReq := c.withHeaderTimeout(ctx, deployment, req.Model)
+		httpReq = httpReq.WithContext(upstreamCtx)
+
+		sentAt := time.Now()
+		resp, err := c.httpClientFor(deployment.ProviderName).Do(httpReq)
+		stopHeaderTimeout()
+		c.reportCredential(deployment.ProviderName, httpReq, resp, err)
+		c.recordUpstream(ctx, deployment, err)
 		if err != nil {
+			cancelReq()
 			release()
-			c.router.ReportFailure(ctx, deployment, err)
-			c.router.ReportRequestEnd(ctx, deployment)
+			rt.ReportFailure(ctx, deployment, err)
+			rt.ReportRequestEnd(ctx, deployment)
 			lastErr = fmt.Errorf("execute request: %w", err)
 			if pendingFallback != nil {
 				c.reportFallback(ctx, pendingFallback.originalModel, pendingFallback.fallbackModel, err, false)
@@ -513,14 +658,16 @@ func (c *Client) ChatCompletionStream(ctx context.Context, req *ChatRequest) (*S
 			continue
 		}
 
+		resp.Body = cancelOnClose(resp.Body, cancelReq)
+
 		if resp.StatusCode >= 500 {
 			// Server error, retryable
 			body, _ := httputil.ReadLimitedBody(resp.Body, httputil.DefaultMaxResponseBodyBytes)
 			_ = resp.Body.Close()
 			llmErr := prov.MapError(resp.StatusCode, body)
 			release()
-			c.router.ReportFailure(ctx, deployment, llmErr)
-			c.router.ReportRequestEnd(ctx, deployment)
+			rt.ReportFailure(ctx, deployment, llmErr)
+			rt.ReportRequestEnd(ctx, deployment)
 			lastErr = llmErr
 			if pendingFallback != nil {
 				c.reportFallback(ctx, pendingFallback.originalModel, pendingFallback.fallbackModel, llmErr, false)
@@ -538,8 +685,8 @@ func (c *Client) ChatCompletionStream(ctx context.Context, req *ChatRequest) (*S
 			// Check if it's a retryable client error (e.g. 429 Rate Limit)
 			if llmErr, ok := llmErr.(*LLMError); ok && llmErr.Retryable {
 				release()
-				c.router.ReportFailure(ctx, deployment, llmErr)
-				c.router.ReportRequestEnd(ctx, deployment)
+				rt.ReportFailure(ctx, deployment, llmErr)
+				rt.ReportRequestEnd(ctx, deployment)
 				lastErr = llmErr
 				if pendingFallback != nil {
 					c.reportFallback(ctx, pendingFallback.originalModel, pendingFallback.fallbackModel, llmErr, false)
@@ -550,12 +697,13 @@ func (c *Client) ChatCompletionStream(ctx context.Context, req *ChatRequest) (*S
 
 			// Non-retryable error
 			release()
-			c.router.ReportFailure(ctx, deployment, llmErr)
-			c.router.ReportRequestEnd(ctx, deployment)
+			rt.ReportFailure(ctx, deployment, llmErr)
+			rt.ReportRequestEnd(ctx, deployment)
 			if pendingFallback != nil {
 				c.reportFallback(ctx, pendingFallback.originalModel, pendingFallback.fallbackModel, llmErr, false)
 				pendingFallback = nil
 			}
+			c.rememberClientError(ctx, req, llmErr)
 			c.pipeline.PutContext(pCtx)
 			return nil, llmErr
 		}
@@ -565,12 +713,19 @@ func (c *Client) ChatCompletionStream(ctx context.Context, req *ChatRequest) (*S
 			c.reportFallback(ctx, pendingFallback.originalModel, pendingFallback.fallbackModel, pendingFallback.err, true)
 			pendingFallback = nil
 		}
-		return newStreamReader(ctx, c, req, resp.Body, prov, deployment, c.router, c.pipeline, pCtx, runFrom, release), nil
+		stream := newStreamReader(ctx, c, req, sentAt, resp.Body, prov, deployment, rt, c.pipeline, pCtx, runFrom, release)
+		if sessionTurn != nil {
+			stream.onFinish = func(content string) {
+				c.recordSessionTurn(ctx, sessionTurn, &ChatMessage{Role: "assistant", Content: jsonString(content)})
+			}
+		}
+		return stream, nil
 	}
 
 	if lastErr == nil {
 		lastErr = fmt.Errorf("max retries exceeded")
 	}
+	c.rememberClientError(ctx, req, lastErr)
 	c.pipeline.PutContext(pCtx)
 	return nil, lastErr
 }
@@ -596,18 +751,22 @@ func (c *Client) Embedding(ctx context.Context, req *types.EmbeddingRequest) (*t
 	}
 
 	apiKey := c.rateLimitAPIKey(ctx)
-	rateLimitKey := c.buildRateLimitKey(req.Model, req.User, apiKey)
+	rateLimitKey, endUserKey := c.buildRateLimitKey(req.Model, req.User, apiKey)
 	promptEstimate := tokenizer.EstimateEmbeddingTokens(canonicalModel, req)
-	if err := c.checkRateLimit(ctx, rateLimitKey, canonicalModel, promptEstimate); err != nil {
+	if err := c.checkRateLimit(ctx, rateLimitKey, endUserKey, canonicalModel, promptEstimate); err != nil {
 		return nil, err
 	}
+	if IsSandbox(ctx) {
+		return c.sandboxEmbedding(req), nil
+	}
 
 	var lastErr error
 	var deployment *provider.Deployment
 	var pendingFallback *fallbackAttempt
 
 	// Retry loop
-	for attempt := 0; attempt <= c.config.RetryCount; attempt++ {
+	retryCount := c.retryCount()
+	for attempt := 0; attempt <= retryCount; attempt++ {
 		if attempt > 0 {
 			backoff := c.retryBackoff(attempt)
 			if backoff > 0 {
@@ -623,9 +782,15 @@ func (c *Client) Embedding(ctx context.Context, req *types.EmbeddingRequest) (*t
 
 		// Route to deployment
 		if attempt == 0 || c.config.FallbackEnabled || deployment == nil {
-			newDeployment, err := c.router.Pick(ctx, req.Model)
+			newDeployment, err := c.pickDeployment(ctx, &router.RequestContext{
+				Model: req.Model,
+				Tags:  append([]string(nil), req.Tags...),
+			})
 			if err != nil {
-				lastErr = fmt.Errorf("no available deployment for model %s: %w", req.Model, err)
+				lastErr = routingError(req.Model, req.Tags, err)
+				if llmErr, ok := lastErr.(*errors.LLMError); ok && !llmErr.Retryable {
+					return nil, lastErr
+				}
 				if deployment == nil {
 					continue
 				}
@@ -667,6 +832,7 @@ func (c *Client) Embedding(ctx context.Context, req *types.EmbeddingRequest) (*t
 		// Execute request
 		reqForProvider := *req
 		reqForProvider.Model = canonicalModel
+		reqForProvider.Tags = nil
 		resp, err := c.executeEmbeddingOnce(ctx, prov, deployment, &reqForProvider, promptEstimate)
 		if err == nil {
 			resp.Model = originalModel
@@ -708,7 +874,7 @@ func (c *Client) executeEmbeddingOnce(
 	ctx = c.withTenantScope(ctx)
 	start := time.Now()
 
-	if err := c.validatePricing(req.Model, deployment.ProviderName); err != nil {
+	if err := c.validatePricing(deployment.ID, req.Model, deployment.ProviderName); err != nil {
 		return nil, err
 	}
 
@@ -718,17 +884,27 @@ func (c *Client) executeEmbeddingOnce(
 	}
 	defer release()
 
-	httpReq, err := prov.BuildEmbeddingRequest(ctx, req)
+	upstreamCtx, cancel := c.withUpstreamTimeout(ctx, deployment, req.Model)
+	defer cancel()
+
+	httpReq, err := prov.BuildEmbeddingRequest(upstreamCtx, req)
 	if err != nil {
 		return nil, fmt.Errorf("build request: %w", err)
 	}
 
-	c.router.ReportRequestStart(ctx, deployment)
-	defer c.router.ReportRequestEnd(ctx, deployment)
+	if err := c.allowUpstream(deployment); err != nil {
+		return nil, err
+	}
+
+	rt := c.activeRouter()
+	rt.ReportRequestStart(ctx, deployment)
+	defer rt.ReportRequestEnd(ctx, deployment)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.httpClientFor(deployment.ProviderName).Do(httpReq)
+	c.reportCredential(deployment.ProviderName, httpReq, resp, err)
+	c.recordUpstream(ctx, deployment, err)
 	if err != nil {
-		c.router.ReportFailure(ctx, deployment, err)
+		rt.ReportFailure(ctx, deployment, err)
 		return nil, fmt.Errorf("execute request: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
@@ -738,13 +914,13 @@ func (c *Client) executeEmbeddingOnce(
 	if resp.StatusCode >= 400 {
 		body, _ := httputil.ReadLimitedBody(resp.Body, httputil.DefaultMaxResponseBodyBytes)
 		llmErr := prov.MapError(resp.StatusCode, body)
-		c.router.ReportFailure(ctx, deployment, llmErr)
+		rt.ReportFailure(ctx, deployment, llmErr)
 		return nil, llmErr
 	}
 
 	embResp, err := prov.ParseEmbeddingResponse(resp)
 	if err != nil {
-		c.router.ReportFailure(ctx, deployment, err)
+		rt.ReportFailure(ctx, deployment, err)
 		return nil, fmt.Errorf("parse response: %w", err)
 	}
 
@@ -759,6 +935,9 @@ func (c *Client) executeEmbeddingOnce(
 	if embResp.Usage.Provider == "" {
 		embResp.Usage.Provider = deployment.ProviderName
 	}
+	if embResp.Usage.Deployment == "" {
+		embResp.Usage.Deployment = deployment.ID
+	}
 
 	// Report success metrics
 	metrics := &router.ResponseMetrics{
@@ -768,7 +947,7 @@ func (c *Client) executeEmbeddingOnce(
 		metrics.TotalTokens = embResp.Usage.TotalTokens
 		metrics.InputTokens = embResp.Usage.PromptTokens
 	}
-	c.router.ReportSuccess(ctx, deployment, metrics)
+	rt.ReportSuccess(ctx, deployment, metrics)
 
 	return embResp, nil
 }
@@ -793,6 +972,16 @@ func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
 			}
 		}
 	}
+	for name := range c.config.VirtualModels {
+		if !seen[name] {
+			models = append(models, Model{
+				ID:       name,
+				Provider: "virtual",
+				Object:   "model",
+			})
+			seen[name] = true
+		}
+	}
 
 	return models, nil
 }
@@ -825,6 +1014,7 @@ func (c *Client) RemoveProvider(name string) error {
 		for _, d := range deployments {
 			if d.ProviderName == name {
 				c.router.RemoveDeployment(d.ID)
+				delete(c.deploymentConfig, d.ID)
 			} else {
 				remaining = append(remaining, d)
 			}
@@ -837,13 +1027,18 @@ func (c *Client) RemoveProvider(name string) error {
 	}
 
 	delete(c.providers, name)
+	delete(c.credentials, name)
+	if client, ok := c.providerHTTP[name]; ok {
+		client.CloseIdleConnections()
+		delete(c.providerHTTP, name)
+	}
 	c.logger.Info("provider removed", "name", name)
 	return nil
 }
 
 // GetStats returns routing statistics for a deployment.
 func (c *Client) GetStats(deploymentID string) *DeploymentStats {
-	return c.router.GetStats(deploymentID)
+	return c.activeRouter().GetStats(deploymentID)
 }
 
 // ResilienceStats returns the resilience status for a provider key.
@@ -858,13 +1053,14 @@ func (c *Client) ResilienceStats(key string) ResilienceStats {
 		RateLimitTokens:    stats.RateLimitTokens,
 		ConcurrentCurrent:  stats.ConcurrentCurrent,
 		ConcurrentCapacity: stats.ConcurrentCapacity,
+		ConcurrentWaiting:  stats.ConcurrentWaiting,
 	}
 }
 
 // SetCooldown updates the cooldown expiration time for a deployment.
 // A zero time clears any active cooldown.
 func (c *Client) SetCooldown(deploymentID string, until time.Time) error {
-	return c.router.SetCooldown(deploymentID, until)
+	return c.activeRouter().SetCooldown(deploymentID, until)
 }
 
 // ListDeployments returns a snapshot of all deployments.
@@ -912,12 +1108,30 @@ func (c *Client) GetProviders() []string {
 	return names
 }
 
+// ReplacePlugins atomically swaps the plugins named in names for plugins,
+// without rebuilding the client. Requests in flight finish with the plugins
+// they started with.
+func (c *Client) ReplacePlugins(names []string, plugins ...plugin.Plugin) error {
+	return c.pipeline.Replace(names, plugins)
+}
+
 // Close releases all resources held by the client.
 func (c *Client) Close() error {
 	if c.cache != nil {
 		_ = c.cache.Close()
 	}
-	c.httpClient.CloseIdleConnections()
+	if c.sessionMemory != nil {
+		_ = c.sessionMemory.Close()
+	}
+	if c.longTermMemory != nil {
+		_ = c.longTermMemory.Close()
+	}
+	c.mu.RLock()
+	c.defaultHTTP.CloseIdleConnections()
+	for _, client := range c.providerHTTP {
+		client.CloseIdleConnections()
+	}
+	c.mu.RUnlock()
 	if c.pipeline != nil {
 		if err := c.pipeline.Shutdown(); err != nil {
 			c.logger.Warn("failed to shutdown plugin pipeline", "error", err)
@@ -941,38 +1155,50 @@ func (c *Client) RegisterProviderFactory(providerType string, factory ProviderFa
 
 // Private methods
 
-func (c *Client) buildRateLimitKey(model, user, apiKey string) string {
+// buildRateLimitKey returns the rate limit key of a request and, under
+// RateLimitKeyByEndUser, the key of its end user within the API key, which
+// is checked together with the API key's.
+func (c *Client) buildRateLimitKey(model, user, apiKey string) (key, endUserKey string) {
 	defaultKey := "default"
 	switch c.rateLimiterConfig.KeyStrategy {
 	case RateLimitKeyByAPIKey:
 		if apiKey != "" {
-			return apiKey
+			return apiKey, ""
 		}
-		return defaultKey
+		return defaultKey, ""
 	case RateLimitKeyByModel:
 		if model != "" {
-			return model
+			return model, ""
 		}
-		return defaultKey
+		return defaultKey, ""
 	case RateLimitKeyByAPIKeyAndModel:
 		baseKey := defaultKey
 		if apiKey != "" {
 			baseKey = apiKey
 		}
 		if model == "" {
-			return baseKey
+			return baseKey, ""
 		}
-		return baseKey + ":" + model
+		return baseKey + ":" + model, ""
+	case RateLimitKeyByEndUser:
+		baseKey := defaultKey
+		if apiKey != "" {
+			baseKey = apiKey
+		}
+		if user == "" {
+			return baseKey, ""
+		}
+		return baseKey, baseKey + ":user:" + user
 	case RateLimitKeyByUser, "":
 		if user != "" {
-			return user
+			return user, ""
 		}
-		return defaultKey
+		return defaultKey, ""
 	default:
 		if user != "" {
-			return user
+			return user, ""
 		}
-		return defaultKey
+		return defaultKey, ""
 	}
 }
 
@@ -997,13 +1223,83 @@ func (c *Client) withTenantScope(ctx context.Context) context.Context {
 	return ctx
 }
 
-func (c *Client) checkRateLimit(ctx context.Context, key, model string, estimatedTokens int) error {
+func (c *Client) checkRateLimit(ctx context.Context, key, endUserKey, model string, estimatedTokens int) error {
 	// Skip if rate limiting is disabled or limiter is nil
 	if !c.rateLimiterConfig.Enabled || c.rateLimiter == nil {
 		return nil
 	}
 
 	// Build descriptors for rate limit check
+	cfg := c.rateLimiterConfig
+	descriptors := c.rateLimitDescriptors(key, model, cfg.RPMLimit, cfg.TPMLimit, estimatedTokens)
+	if endUserKey != "" {
+		rpm, tpm := cfg.EndUserRPMLimit, cfg.EndUserTPMLimit
+		if rpm <= 0 {
+			rpm = cfg.RPMLimit
+		}
+		if tpm <= 0 {
+			tpm = cfg.TPMLimit
+		}
+		descriptors = append(descriptors, c.rateLimitDescriptors(endUserKey, model, rpm, tpm, estimatedTokens)...)
+	}
+
+	if len(descriptors) == 0 {
+		return nil // No limits configured
+	}
+
+	// Check limits
+	results, err := c.rateLimiter.CheckAllow(ctx, descriptors)
+	if err != nil || len(results) != len(descriptors) {
+		if err == nil {
+			err = fmt.Errorf("rate limiter returned %d results, expected %d", len(results), len(descriptors))
+		}
+		action := "allow"
+		if !c.rateLimiterConfig.FailOpen {
+			action = "deny"
+		}
+		metrics.RateLimiterBackendErrors.WithLabelValues("client", action).Inc()
+		c.logger.Warn("rate limiter check failed",
+			"error", err,
+			"fail_open", c.rateLimiterConfig.FailOpen,
+			"action", action,
+		)
+		if c.rateLimiterConfig.FailOpen {
+			return nil
+		}
+		return errors.NewRateLimitError("llmux", model, "rate limiter backend unavailable")
+	}
+
+	// Check if any limit was exceeded
+	var denied []resilience.Descriptor
+	deniedAt := -1
+	for i, result := range results {
+		if !result.Allowed {
+			denied = append(denied, descriptors[i])
+			if deniedAt < 0 {
+				deniedAt = i
+			}
+		}
+	}
+	if deniedAt < 0 || c.borrowReserve(ctx, model, denied) {
+		return nil
+	}
+
+	result := results[deniedAt]
+	limitType := "requests"
+	if descriptors[deniedAt].Type == resilience.LimitTypeTokens {
+		limitType = "tokens"
+	}
+	return errors.NewRateLimitError(
+		"llmux",
+		model,
+		fmt.Sprintf("rate limit exceeded: %s per minute limit reached (current: %d, limit: %d, reset at: %d)",
+			limitType, result.Current, descriptors[deniedAt].Limit, result.ResetAt),
+	)
+}
+
+// rateLimitDescriptors returns the descriptors checking key against the
+// given limits, skipping the unset ones.
+func (c *Client) rateLimitDescriptors(key, model string, rpmLimit, tpmLimit int64, estimatedTokens int) []resilience.Descriptor {
 	var descriptors []resilience.Descriptor
 
 	windowSize := c.rateLimiterConfig.WindowSize
@@ -1012,11 +1308,11 @@ func (c *Client) checkRateLimit(ctx context.Context, key, model string, estimate
 	}
 
 	// Add RPM descriptor if limit is set
-	if c.rateLimiterConfig.RPMLimit > 0 {
+	if rpmLimit > 0 {
 		descriptors = append(descriptors, resilience.Descriptor{
 			Key:       key,
 			Value:     model,
-			Limit:     c.rateLimiterConfig.RPMLimit,
+			Limit:     rpmLimit,
 			Type:      resilience.LimitTypeRequests,
 			Window:    windowSize,
 			Increment: 1,
@@ -1024,7 +1320,7 @@ func (c *Client) checkRateLimit(ctx context.Context, key, model string, estimate
 	}
 
 	// Add TPM descriptor if limit is set
-	if c.rateLimiterConfig.TPMLimit > 0 {
+	if tpmLimit > 0 {
 		inc := int64(estimatedTokens)
 		if inc <= 0 {
 			inc = 1
@@ -1032,56 +1328,44 @@ func (c *Client) checkRateLimit(ctx context.Context, key, model string, estimate
 		descriptors = append(descriptors, resilience.Descriptor{
 			Key:       key,
 			Value:     model,
-			Limit:     c.rateLimiterConfig.TPMLimit,
+			Limit:     tpmLimit,
 			Type:      resilience.LimitTypeTokens,
 			Window:    windowSize,
 			Increment: inc,
 		})
 	}
+	return descriptors
+}
 
-	if len(descriptors) == 0 {
-		return nil // No limits configured
+// borrowReserve admits a request denied by the regular limits when its
+// priority has a reserve pool with room for it. Only the exhausted limits
+// are charged to the pool.
+func (c *Client) borrowReserve(ctx context.Context, model string, denied []resilience.Descriptor) bool {
+	priority := RequestPriority(ctx)
+	share := c.rateLimiterConfig.PriorityReservations[priority]
+	if share <= 0 {
+		return false
 	}
 
-	// Check limits
-	results, err := c.rateLimiter.CheckAllow(ctx, descriptors)
-	if err != nil || len(results) != len(descriptors) {
-		if err == nil {
-			err = fmt.Errorf("rate limiter returned %d results, expected %d", len(results), len(descriptors))
-		}
-		action := "allow"
-		if !c.rateLimiterConfig.FailOpen {
-			action = "deny"
-		}
-		metrics.RateLimiterBackendErrors.WithLabelValues("client", action).Inc()
-		c.logger.Warn("rate limiter check failed",
-			"error", err,
-			"fail_open", c.rateLimiterConfig.FailOpen,
-			"action", action,
-		)
-		if c.rateLimiterConfig.FailOpen {
-			return nil
-		}
-		return errors.NewRateLimitError("llmux", model, "rate limiter backend unavailable")
+	reserve := make([]resilience.Descriptor, len(denied))
+	for i, d := range denied {
+		d.Key += ":reserve:" + priority.String()
+		d.Limit = int64(math.Ceil(float64(d.Limit) * share))
+		reserve[i] = d
 	}
-
-	// Check if any limit was exceeded
-	for i, result := range results {
+	results, err := c.rateLimiter.CheckAllow(ctx, reserve)
+	if err != nil || len(results) != len(reserve) {
+		c.logger.Warn("rate limit reserve check failed", "error", err, "priority", priority.String())
+		return false
+	}
+	for _, result := range results {
 		if !result.Allowed {
-			limitType := "requests"
-			if descriptors[i].Type == resilience.LimitTypeTokens {
-				limitType = "tokens"
-			}
-			return errors.NewRateLimitError(
-				"llmux",
-				model,
-				fmt.Sprintf("rate limit exceeded: %s per minute limit reached (current: %d, limit: %d, reset at: %d)",
-					limitType, result.Current, descriptors[i].Limit, result.ResetAt),
-			)
+			return false
 		}
 	}
-
-	return nil
+	metrics.RateLimitReserveAdmissions.WithLabelValues(priority.String()).Inc()
+	c.logger.Debug("request admitted from rate limit reserve", "model", model, "priority", priority.String())
+	return true
 }
 
 type fallbackAttempt struct {
@@ -1094,7 +1378,9 @@ func (c *Client) retryBackoff(attempt int) time.Duration {
 	if attempt <= 0 {
 		return 0
 	}
-	base := c.config.RetryBackoff
+	c.mu.RLock()
+	base, maxBackoff, jitter := c.config.RetryBackoff, c.config.RetryMaxBackoff, c.config.RetryJitter
+	c.mu.RUnlock()
 	if base <= 0 {
 		return 0
 	}
@@ -1108,12 +1394,11 @@ func (c *Client) retryBackoff(attempt int) time.Duration {
 		backoff = next
 	}
 
-	if c.config.RetryMaxBackoff > 0 && backoff > c.config.RetryMaxBackoff {
-		backoff = c.config.RetryMaxBackoff
+	if maxBackoff > 0 && backoff > maxBackoff {
+		backoff = maxBackoff
 	}
 
-	if c.config.RetryJitter > 0 && backoff > 0 {
-		jitter := c.config.RetryJitter
+	if jitter > 0 && backoff > 0 {
 		if jitter > 1 {
 			jitter = 1
 		}
@@ -1121,8 +1406,8 @@ func (c *Client) retryBackoff(attempt int) time.Duration {
 		maxFactor := 1 + jitter
 		factor := minFactor + c.randomFloat64()*(maxFactor-minFactor)
 		backoff = time.Duration(float64(backoff) * factor)
-		if c.config.RetryMaxBackoff > 0 && backoff > c.config.RetryMaxBackoff {
-			backoff = c.config.RetryMaxBackoff
+		if maxBackoff > 0 && backoff > maxBackoff {
+			backoff = maxBackoff
 		}
 	}
 
@@ -1159,8 +1444,28 @@ func (c *Client) acquireDeployment(ctx context.Context, deployment *provider.Dep
 
 	key := deployment.ProviderName
 	sem := c.resilienceManager.GetSemaphore(key, deployment.MaxConcurrent)
-	if !sem.TryAcquire() {
-		return nil, errors.NewRateLimitError(deployment.ProviderName, deployment.ModelName, "provider concurrency limit reached")
+	if c.config.AdmissionQueueSize <= 0 {
+		if !sem.TryAcquire() {
+			return nil, errors.NewRateLimitError(deployment.ProviderName, deployment.ModelName, "provider concurrency limit reached")
+		}
+	} else {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		waitCtx := ctx
+		if c.config.AdmissionQueueTimeout > 0 {
+			var cancel context.CancelFunc
+			waitCtx, cancel = context.WithTimeout(ctx, c.config.AdmissionQueueTimeout)
+			defer cancel()
+		}
+		priority := RequestPriority(ctx)
+		if err := sem.AcquireWithPriority(waitCtx, int(priority), c.config.AdmissionQueueSize); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, errors.NewRateLimitError(deployment.ProviderName, deployment.ModelName,
+				fmt.Sprintf("request shed by admission queue (priority %s)", priority))
+		}
 	}
 
 	return func() {
@@ -1182,7 +1487,8 @@ func (c *Client) executeWithRetry(
 	promptTokens := tokenizer.EstimatePromptTokens(canonicalModel, req)
 	var pendingFallback *fallbackAttempt
 
-	for attempt := 0; attempt <= c.config.RetryCount; attempt++ {
+	retryCount := c.retryCount()
+	for attempt := 0; attempt <= retryCount; attempt++ {
 		if attempt > 0 {
 			backoff := c.retryBackoff(attempt)
 			if backoff > 0 {
@@ -1196,7 +1502,9 @@ func (c *Client) executeWithRetry(
 			}
 		}
 
-		resp, err := c.executeOnce(ctx, prov, deployment, req)
+		attemptCtx, span := startAttemptSpan(ctx, deployment, attempt, pendingFallback != nil)
+		resp, err := c.executeOnce(attemptCtx, prov, deployment, req)
+		endAttemptSpan(span, err)
 		if err == nil {
 			if pendingFallback != nil {
 				c.reportFallback(ctx, pendingFallback.originalModel, pendingFallback.fallbackModel, pendingFallback.err, true)
@@ -1218,9 +1526,9 @@ func (c *Client) executeWithRetry(
 		}
 
 		// Try fallback if enabled
-		if c.config.FallbackEnabled && attempt < c.config.RetryCount {
+		if c.config.FallbackEnabled && attempt < retryCount {
 			reqCtx := buildRouterRequestContext(req, promptTokens, req.Stream)
-			newDeployment, pickErr := c.router.PickWithContext(ctx, reqCtx)
+			newDeployment, pickErr := c.pickDeployment(ctx, reqCtx)
 			if pickErr == nil && newDeployment.ID != deployment.ID {
 				pendingFallback = &fallbackAttempt{
 					originalModel: req.Model,
@@ -1259,7 +1567,11 @@ func (c *Client) executeOnce(
 		canonicalModel = originalModel
 	}
 
-	if err := c.validatePricing(canonicalModel, deployment.ProviderName); err != nil {
+	if err := c.validatePricing(deployment.ID, canonicalModel, deployment.ProviderName); err != nil {
+		return nil, err
+	}
+	upstreamReq, err := c.limitMaxTokens(deployment, canonicalModel, req)
+	if err != nil {
 		return nil, err
 	}
 
@@ -1269,17 +1581,27 @@ func (c *Client) executeOnce(
 	}
 	defer release()
 
-	httpReq, err := prov.BuildRequest(ctx, sanitizeChatRequestForProvider(req))
+	upstreamCtx, cancel := c.withUpstreamTimeout(ctx, deployment, originalModel)
+	defer cancel()
+
+	httpReq, err := prov.BuildRequest(upstreamCtx, applyPromptCacheKey(ctx, prov.Name(), sanitizeChatRequestForProvider(upstreamReq)))
 	if err != nil {
 		return nil, fmt.Errorf("build request: %w", err)
 	}
 
-	c.router.ReportRequestStart(ctx, deployment)
-	defer c.router.ReportRequestEnd(ctx, deployment)
+	if err := c.allowUpstream(deployment); err != nil {
+		return nil, err
+	}
+
+	rt := c.activeRouter()
+	rt.ReportRequestStart(ctx, deployment)
+	defer rt.ReportRequestEnd(ctx, deployment)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.httpClientFor(deployment.ProviderName).Do(httpReq)
+	c.reportCredential(deployment.ProviderName, httpReq, resp, err)
+	c.recordUpstream(ctx, deployment, err)
 	if err != nil {
-		c.router.ReportFailure(ctx, deployment, err)
+		rt.ReportFailure(ctx, deployment, err)
 		return nil, fmt.Errorf("execute request: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
@@ -1289,18 +1611,19 @@ func (c *Client) executeOnce(
 	if resp.StatusCode >= 400 {
 		body, _ := httputil.ReadLimitedBody(resp.Body, httputil.DefaultMaxResponseBodyBytes)
 		llmErr := prov.MapError(resp.StatusCode, body)
-		c.router.ReportFailure(ctx, deployment, llmErr)
+		rt.ReportFailure(ctx, deployment, llmErr)
 		return nil, llmErr
 	}
 
 	chatResp, err := prov.ParseResponse(resp)
 	if err != nil {
-		c.router.ReportFailure(ctx, deployment, err)
+		rt.ReportFailure(ctx, deployment, err)
 		return nil, fmt.Errorf("parse response: %w", err)
 	}
 	chatResp.Model = originalModel
 
-	if chatResp.Usage == nil || chatResp.Usage.TotalTokens == 0 {
+	estimated := chatResp.Usage == nil || chatResp.Usage.TotalTokens == 0
+	if estimated {
 		promptTokens := tokenizer.EstimatePromptTokens(canonicalModel, req)
 		completionTokens := tokenizer.EstimateCompletionTokens(canonicalModel, chatResp, "")
 		chatResp.Usage = &types.Usage{
@@ -1309,9 +1632,16 @@ func (c *Client) executeOnce(
 			TotalTokens:      promptTokens + completionTokens,
 		}
 	}
+	countReasoningTokens(canonicalModel, chatResp, estimated)
 	if chatResp.Usage != nil && chatResp.Usage.Provider == "" {
 		chatResp.Usage.Provider = deployment.ProviderName
 	}
+	if chatResp.Usage != nil && chatResp.Usage.Deployment == "" {
+		chatResp.Usage.Deployment = deployment.ID
+	}
+	if chatResp.Usage != nil && chatResp.Usage.Images == 0 {
+		chatResp.Usage.Images = req.ImageCount()
+	}
 
 	// Report success metrics
 	metrics := &router.ResponseMetrics{
@@ -1322,39 +1652,58 @@ func (c *Client) executeOnce(
 		metrics.OutputTokens = chatResp.Usage.CompletionTokens
 		metrics.TotalTokens = chatResp.Usage.TotalTokens
 	}
-	c.router.ReportSuccess(ctx, deployment, metrics)
+	rt.ReportSuccess(ctx, deployment, metrics)
 
 	return chatResp, nil
 }
 
-// CalculateCost computes the usage cost for a given model using loaded pricing data.
+// CalculateCost computes the usage cost for a given model using loaded pricing data,
+// preferring the price of the deployment that served it, and including any
+// auxiliary cost recorded on the usage (e.g. speculative drafts).
 // Returns 0 when pricing is unavailable or model not found.
 func (c *Client) CalculateCost(model string, usage *types.Usage) float64 {
-	if usage == nil || c.pricing == nil {
+	if usage == nil || c.pricing == nil || usage.Provider == SandboxProviderName {
 		return 0
 	}
 
-	provider := usage.Provider
-	price, ok := c.pricing.GetPrice(model, provider)
+	price, ok := c.priceFor(usage.Deployment, c.resolveModel(model), usage.Provider)
 	if !ok {
-		return 0
-	}
+		return usage.AuxiliaryCost
+	}
+	return price.Cost(pricing.Usage{
+		InputTokens:     usage.PromptTokens,
+		OutputTokens:    usage.CompletionTokens,
+		ReasoningTokens: usage.ReasoningTokens(),
+		Images:          usage.Images,
+		AudioSeconds:    usage.AudioSeconds,
+	}) + usage.AuxiliaryCost
+}
 
-	inputCost := float64(usage.PromptTokens) * price.InputCostPerToken
-	outputCost := float64(usage.CompletionTokens) * price.OutputCostPerToken
-	return inputCost + outputCost
+// HasPricing reports whether the pricing registry has an entry for model
+// served by provider, ignoring any fallback pricing policy.
+func (c *Client) HasPricing(model, provider string) bool {
+	if c.pricing == nil {
+		return false
+	}
+	_, ok := c.pricing.GetPrice(model, provider)
+	return ok
 }
 
-func (c *Client) validatePricing(model, provider string) error {
+// validatePricing rejects requests for unpriced models unless the pricing
+// fallback policy allows them.
+func (c *Client) validatePricing(deploymentID, model, provider string) error {
 	if c.pricing == nil {
 		return errors.NewInternalError(provider, model, "pricing registry unavailable")
 	}
 
-	if _, ok := c.pricing.GetPrice(model, provider); !ok {
-		return errors.NewInternalError(provider, model, "pricing not configured for model")
+	if _, ok := c.priceFor(deploymentID, model, provider); ok {
+		return nil
 	}
-
-	return nil
+	if c.PricingPolicy() == PricingPolicyWarn {
+		c.warnUnpriced(model, provider)
+		return nil
+	}
+	return errors.NewInternalError(provider, model, "pricing not configured for model")
 }
 
 func (c *Client) addProviderFromConfig(cfg ProviderConfig) error {
@@ -1363,19 +1712,36 @@ func (c *Client) addProviderFromConfig(cfg ProviderConfig) error {
 		return fmt.Errorf("unknown provider type: %s (available: %v)", cfg.Type, c.availableFactories())
 	}
 
+	transport, err := newProviderTransport(cfg)
+	if err != nil {
+		return err
+	}
+	rollover := newCredentialRollover(&cfg)
 	prov, err := factory(cfg)
 	if err != nil {
 		return err
 	}
+	if rollover != nil {
+		c.credentials[cfg.Name] = rollover
+	}
+	c.providerHTTP[cfg.Name] = newHTTPClient(transport)
 
-	return c.addProviderInstanceWithConfig(cfg.Name, prov, cfg.Models, cfg.MaxConcurrent)
+	return c.addProviderInstanceWithConfig(cfg.Name, prov, cfg.Models, cfg.MaxConcurrent, cfg.Timeout, deploymentRoutingConfig(cfg), cfg.ModelPrices)
 }
 
 func (c *Client) addProviderInstance(name string, prov provider.Provider, models []string) error {
-	return c.addProviderInstanceWithConfig(name, prov, models, 0)
+	return c.addProviderInstanceWithConfig(name, prov, models, 0, 0, nil, nil)
 }
 
-func (c *Client) addProviderInstanceWithConfig(name string, prov provider.Provider, models []string, maxConcurrent int) error {
+func (c *Client) addProviderInstanceWithConfig(
+	name string,
+	prov provider.Provider,
+	models []string,
+	maxConcurrent int,
+	timeout time.Duration,
+	routingForModel func(model string) router.DeploymentConfig,
+	prices map[string]pricing.ModelPrice,
+) error {
 	c.providers[name] = prov
 	if maxConcurrent > 0 && c.resilienceManager != nil {
 		c.resilienceManager.SetSemaphore(name, maxConcurrent)
@@ -1388,12 +1754,26 @@ func (c *Client) addProviderInstanceWithConfig(name string, prov provider.Provid
 			ProviderName:  name,
 			ModelName:     model,
 			MaxConcurrent: maxConcurrent,
+			// Whole seconds, rounded up so sub-second timeouts are kept.
+			Timeout: int((timeout + time.Second - 1) / time.Second),
 		}
 		c.deployments[model] = append(c.deployments[model], deployment)
+		if price, ok := prices[model]; ok {
+			c.pricing.SetDeploymentPrice(deployment.ID, price)
+		} else {
+			// The registry may be shared with a client built from an older config.
+			c.pricing.DeleteDeploymentPrice(deployment.ID)
+		}
+
+		var routingConfig router.DeploymentConfig
+		if routingForModel != nil {
+			routingConfig = routingForModel(model)
+			c.deploymentConfig[deployment.ID] = routingConfig
+		}
 
 		// If router is already initialized, add deployment
 		if c.router != nil {
-			c.router.AddDeployment(deployment)
+			c.router.AddDeploymentWithConfig(deployment, routingConfig)
 		}
 	}
 
@@ -1401,24 +1781,55 @@ func (c *Client) addProviderInstanceWithConfig(name string, prov provider.Provid
 	return nil
 }
 
+// deploymentRoutingConfig derives per-model routing config (quotas, tags and
+// deployment prices) from cfg.
+func deploymentRoutingConfig(cfg provider.Config) func(model string) router.DeploymentConfig {
+	return func(model string) router.DeploymentConfig {
+		limits := cfg.LimitsForModel(model)
+		price := cfg.ModelPrices[model]
+		return router.DeploymentConfig{
+			RPMLimit:           limits.RPM,
+			TPMLimit:           limits.TPM,
+			Tags:               append([]string(nil), cfg.Tags...),
+			InputCostPerToken:  price.InputCostPerToken,
+			OutputCostPerToken: price.OutputCostPerToken,
+		}
+	}
+}
+
 func (c *Client) createRouter(strategy Strategy) router.Router {
-	// Use the routers package for all strategies
-	config := router.DefaultConfig()
-	config.Strategy = strategy
-	config.CooldownPeriod = c.config.CooldownPeriod
-	config.EWMAAlpha = c.config.EWMAAlpha
-	config.LatencyBuffer = 0.1
-	config.MaxLatencyListSize = 10
-	config.PricingFile = c.config.PricingFile
-	config.DefaultProvider = c.config.DefaultProvider
-	r, err := routers.NewWithStores(config, c.config.StatsStore, c.config.RoundRobinStore)
+	r, err := newStrategyRouter(c.config, strategy)
 	if err != nil {
 		// Fallback to shuffle router if strategy is invalid
+		c.logger.Warn("falling back to simple-shuffle router", "strategy", strategy, "error", err)
 		return routers.NewShuffleRouter()
 	}
 	return r
 }
 
+// newStrategyRouter builds a router for strategy from the routing settings
+// of cfg.
+func newStrategyRouter(cfg *ClientConfig, strategy Strategy) (router.Router, error) {
+	// Use the routers package for all strategies
+	config := router.DefaultConfig()
+	config.Strategy = strategy
+	config.CooldownPeriod = cfg.CooldownPeriod
+	if cfg.CooldownMaxPeriod > 0 {
+		config.CooldownMaxPeriod = cfg.CooldownMaxPeriod
+	}
+	if cfg.HalfOpenSuccesses > 0 {
+		config.HalfOpenSuccessThreshold = cfg.HalfOpenSuccesses
+	}
+	config.EWMAAlpha = cfg.EWMAAlpha
+	config.LatencyBuffer = 0.1
+	config.MaxLatencyListSize = 10
+	config.PricingFile = cfg.PricingFile
+	config.Pricing = cfg.PricingRegistry
+	config.DefaultProvider = cfg.DefaultProvider
+	config.EnableTagFiltering = cfg.TagFiltering
+	return routers.NewWithStores(config, cfg.StatsStore, cfg.RoundRobinStore)
+}
+
 func (c *Client) registerBuiltinFactories() {
 	// Register all built-in provider factories from the providers package
 	for _, name := range providers.List() {
@@ -1457,6 +1868,10 @@ func (c *Client) getFromCache(ctx context.Context, req *ChatRequest) (*ChatRespo
 		metrics.CacheMisses.WithLabelValues(c.cacheTypeLabel, req.Model).Inc()
 		return nil, err
 	}
+	if !cachedResponseFresh(ctx, &resp) {
+		metrics.CacheMisses.WithLabelValues(c.cacheTypeLabel, req.Model).Inc()
+		return nil, nil
+	}
 
 	metrics.CacheHits.WithLabelValues(c.cacheTypeLabel, req.Model).Inc()
 	c.logger.Debug("cache hit", "model", req.Model)
@@ -1478,7 +1893,7 @@ func (c *Client) storeInCache(ctx context.Context, req *ChatRequest, resp *ChatR
 		return
 	}
 
-	if err := c.cache.Set(ctx, key, data, c.config.CacheTTL); err != nil {
+	if err := c.cache.Set(ctx, key, data, c.cacheTTL(ctx)); err != nil {
 		c.logger.Debug("cache store failed", "error", err)
 	}
 }
@@ -27,7 +27,7 @@ func TestClient_CheckRateLimit(t *testing.T) {
 		}
 		defer client.Close()
 
-		err = client.checkRateLimit(context.Background(), "test-key", "gpt-4", 0)
+		err = client.checkRateLimit(context.Background(), "test-key", "", "gpt-4", 0)
 		if err != nil {
 			t.Errorf("expected nil error, got: %v", err)
 		}
@@ -46,7 +46,7 @@ func TestClient_CheckRateLimit(t *testing.T) {
 		defer client.Close()
 
 		// Limiter is nil, so should skip
-		err = client.checkRateLimit(context.Background(), "test-key", "gpt-4", 0)
+		err = client.checkRateLimit(context.Background(), "test-key", "", "gpt-4", 0)
 		if err != nil {
 			t.Errorf("expected nil error when limiter is nil, got: %v", err)
 		}
@@ -74,7 +74,7 @@ func TestClient_CheckRateLimit(t *testing.T) {
 		}
 		defer client.Close()
 
-		err = client.checkRateLimit(context.Background(), "test-key", "gpt-4", 0)
+		err = client.checkRateLimit(context.Background(), "test-key", "", "gpt-4", 0)
 		if err != nil {
 			t.Errorf("expected nil error on fail-open, got: %v", err)
 		}
@@ -102,7 +102,7 @@ func TestClient_CheckRateLimit(t *testing.T) {
 		}
 		defer client.Close()
 
-		err = client.checkRateLimit(context.Background(), "test-key", "gpt-4", 0)
+		err = client.checkRateLimit(context.Background(), "test-key", "", "gpt-4", 0)
 		if err == nil {
 			t.Error("expected error on fail-closed, got nil")
 		}
@@ -139,7 +139,7 @@ func TestClient_CheckRateLimit(t *testing.T) {
 		}
 		defer client.Close()
 
-		err = client.checkRateLimit(context.Background(), "test-key", "gpt-4", 100)
+		err = client.checkRateLimit(context.Background(), "test-key", "", "gpt-4", 100)
 		if err != nil {
 			t.Errorf("expected nil error, got: %v", err)
 		}
@@ -185,7 +185,7 @@ func TestClient_CheckRateLimit(t *testing.T) {
 		}
 		defer client.Close()
 
-		err = client.checkRateLimit(context.Background(), "test-key", "gpt-4", 100)
+		err = client.checkRateLimit(context.Background(), "test-key", "", "gpt-4", 100)
 		if err == nil {
 			t.Error("expected rate limit error, got nil")
 		}
@@ -234,7 +234,7 @@ func TestClient_CheckRateLimit(t *testing.T) {
 		}
 		defer client.Close()
 
-		err = client.checkRateLimit(context.Background(), "test-key", "gpt-4", 1000)
+		err = client.checkRateLimit(context.Background(), "test-key", "", "gpt-4", 1000)
 		if err == nil {
 			t.Error("expected rate limit error, got nil")
 		}
@@ -277,12 +277,12 @@ func TestClient_CheckRateLimit(t *testing.T) {
 		defer client.Close()
 
 		// Test with different keys
-		_ = client.checkRateLimit(context.Background(), "api-key-123", "gpt-4", 100)
+		_ = client.checkRateLimit(context.Background(), "api-key-123", "", "gpt-4", 100)
 		if capturedKey != "api-key-123" {
 			t.Errorf("expected key 'api-key-123', got '%s'", capturedKey)
 		}
 
-		_ = client.checkRateLimit(context.Background(), "api-key-456", "claude-3", 100)
+		_ = client.checkRateLimit(context.Background(), "api-key-456", "", "claude-3", 100)
 		if capturedKey != "api-key-456" {
 			t.Errorf("expected key 'api-key-456', got '%s'", capturedKey)
 		}
@@ -393,12 +393,151 @@ func TestClient_RateLimitKeyStrategyByAPIKey_NoKeyFallbacksToDefault(t *testing.
 	}
 	defer client.Close()
 
-	key := client.buildRateLimitKey("test-model", "user-1", "")
+	key, _ := client.buildRateLimitKey("test-model", "user-1", "")
 	if key != "default" {
 		t.Fatalf("expected default key, got %q", key)
 	}
 }
 
+func TestClient_RateLimitKeyStrategyByEndUser(t *testing.T) {
+	client, err := New(
+		WithRateLimiterConfig(RateLimiterConfig{
+			Enabled:     true,
+			KeyStrategy: RateLimitKeyByEndUser,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	tests := []struct {
+		user, apiKey, wantKey, wantEndUser string
+	}{
+		{user: "customer-1", apiKey: "key-1", wantKey: "key-1", wantEndUser: "key-1:user:customer-1"},
+		{user: "customer-2", apiKey: "key-1", wantKey: "key-1", wantEndUser: "key-1:user:customer-2"},
+		{user: "customer-1", apiKey: "key-2", wantKey: "key-2", wantEndUser: "key-2:user:customer-1"},
+		{user: "", apiKey: "key-1", wantKey: "key-1", wantEndUser: ""},
+		{user: "customer-1", apiKey: "", wantKey: "default", wantEndUser: "default:user:customer-1"},
+	}
+	for _, tt := range tests {
+		key, endUser := client.buildRateLimitKey("test-model", tt.user, tt.apiKey)
+		if key != tt.wantKey || endUser != tt.wantEndUser {
+			t.Errorf("buildRateLimitKey(user=%q, apiKey=%q) = (%q, %q), want (%q, %q)",
+				tt.user, tt.apiKey, key, endUser, tt.wantKey, tt.wantEndUser)
+		}
+	}
+}
+
+func TestClient_RateLimitEndUserRotationKeepsKeyLimit(t *testing.T) {
+	counts := make(map[string]int64)
+	limiter := &mockDistributedLimiter{
+		checkAllowFunc: func(ctx context.Context, descriptors []resilience.Descriptor) ([]resilience.LimitResult, error) {
+			results := make([]resilience.LimitResult, len(descriptors))
+			for i, desc := range descriptors {
+				counts[desc.Key] += desc.Increment
+				results[i] = resilience.LimitResult{Allowed: counts[desc.Key] <= desc.Limit, Current: counts[desc.Key]}
+			}
+			return results, nil
+		},
+	}
+
+	client, err := New(
+		WithRateLimiter(limiter),
+		WithRateLimiterConfig(RateLimiterConfig{
+			Enabled:         true,
+			RPMLimit:        3,
+			EndUserRPMLimit: 2,
+			WindowSize:      time.Minute,
+			KeyStrategy:     RateLimitKeyByEndUser,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	check := func(user string) error {
+		key, endUser := client.buildRateLimitKey("gpt-4", user, "key-1")
+		return client.checkRateLimit(context.Background(), key, endUser, "gpt-4", 0)
+	}
+
+	if err := check("customer-1"); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if err := check("customer-1"); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+	if err := check("customer-1"); err == nil {
+		t.Fatal("expected the end user limit to deny customer-1's third request")
+	}
+
+	// A fresh user value must not escape the key's limit, which the three
+	// requests above already used up.
+	for _, user := range []string{"customer-2", "customer-3"} {
+		if err := check(user); err == nil {
+			t.Fatalf("expected the key limit to deny rotated user %q", user)
+		}
+	}
+}
+
+func TestClient_CheckRateLimitPriorityReservations(t *testing.T) {
+	var reserved []resilience.Descriptor
+	limiter := &mockDistributedLimiter{
+		checkAllowFunc: func(ctx context.Context, descriptors []resilience.Descriptor) ([]resilience.LimitResult, error) {
+			results := make([]resilience.LimitResult, len(descriptors))
+			for i, desc := range descriptors {
+				if strings.Contains(desc.Key, ":reserve:") {
+					reserved = append(reserved, desc)
+					results[i] = resilience.LimitResult{Allowed: true, Remaining: desc.Limit - 1}
+					continue
+				}
+				// Only the request limit is exhausted.
+				results[i] = resilience.LimitResult{Allowed: desc.Type != resilience.LimitTypeRequests}
+			}
+			return results, nil
+		},
+	}
+
+	client, err := New(
+		WithRateLimiter(limiter),
+		WithRateLimiterConfig(RateLimiterConfig{
+			Enabled:     true,
+			RPMLimit:    100,
+			TPMLimit:    10000,
+			WindowSize:  time.Minute,
+			KeyStrategy: RateLimitKeyByAPIKey,
+			PriorityReservations: map[Priority]float64{
+				PriorityHigh: 0.25,
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	high := WithRequestPriority(context.Background(), PriorityHigh)
+	if err := client.checkRateLimit(high, "test-key", "", "gpt-4", 100); err != nil {
+		t.Fatalf("expected high priority request to borrow from reserve, got: %v", err)
+	}
+	if len(reserved) != 1 {
+		t.Fatalf("expected only the exhausted limit to be charged to the reserve, got %d", len(reserved))
+	}
+	if reserved[0].Key != "test-key:reserve:high" || reserved[0].Limit != 25 {
+		t.Errorf("unexpected reserve descriptor: key=%q limit=%d", reserved[0].Key, reserved[0].Limit)
+	}
+
+	low := WithRequestPriority(context.Background(), PriorityLow)
+	err = client.checkRateLimit(low, "test-key", "", "gpt-4", 100)
+	if err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Fatalf("expected low priority request to be rate limited, got: %v", err)
+	}
+	if len(reserved) != 1 {
+		t.Errorf("expected low priority request not to touch the reserve")
+	}
+}
+
 func TestClient_RateLimitKeyStrategyByAPIKeyFromContext(t *testing.T) {
 	var capturedKey string
 	captureLimiter := &mockDistributedLimiter{
@@ -0,0 +1,88 @@
+package llmux
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/blueberrycongee/llmux/internal/metrics"
+	"github.com/blueberrycongee/llmux/internal/observability"
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+func TestClient_RoutingExplanation(t *testing.T) {
+	var hits atomic.Int32
+	upstream := newTaggedUpstream(t, &hits)
+	defer upstream.Close()
+
+	client, err := New(
+		WithProvider(ProviderConfig{
+			Name: "eu", Type: "openai", APIKey: "test-key", Models: []string{"gpt-test"},
+			BaseURL: upstream.URL, AllowPrivateBaseURL: true, Tags: []string{"eu"},
+		}),
+		WithProvider(ProviderConfig{
+			Name: "us", Type: "openai", APIKey: "test-key", Models: []string{"gpt-test"},
+			BaseURL: upstream.URL, AllowPrivateBaseURL: true, Tags: []string{"us"},
+		}),
+		withTestPricing(t, "gpt-test"),
+		WithTagFiltering(true),
+		WithRouterTracing(1),
+		WithRetry(0, 0),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	chat := func(requestID string) {
+		t.Helper()
+		ctx := observability.ContextWithRequestID(context.Background(), requestID)
+		_, err := client.ChatCompletion(ctx, &ChatRequest{
+			Model:    "gpt-test",
+			Messages: []ChatMessage{{Role: "user", Content: json.RawMessage(`"hi"`)}},
+			Tags:     []string{"us"},
+		})
+		if err != nil {
+			t.Fatalf("ChatCompletion() error = %v", err)
+		}
+	}
+
+	chat("req-1")
+	decision, ok := client.RoutingExplanation("req-1")
+	if !ok {
+		t.Fatal("expected routing decision for req-1")
+	}
+	if decision.Model != "gpt-test" || decision.Strategy != StrategySimpleShuffle || len(decision.Attempts) != 1 {
+		t.Fatalf("unexpected decision: %+v", decision)
+	}
+	attempt := decision.Attempts[0]
+	if len(attempt.Candidates) != 2 || attempt.Error != "" {
+		t.Fatalf("unexpected attempt: %+v", attempt)
+	}
+	var tagStep *router.FilterStep
+	for i := range attempt.Filters {
+		if attempt.Filters[i].Name == router.FilterTags {
+			tagStep = &attempt.Filters[i]
+		}
+	}
+	if tagStep == nil || len(tagStep.Remaining) != 1 || tagStep.Remaining[0] != attempt.Selected {
+		t.Fatalf("unexpected tag filter step %+v (selected %q)", tagStep, attempt.Selected)
+	}
+
+	// Capacity is 1, so a newer request evicts the older decision.
+	chat("req-2")
+	if _, ok := client.RoutingExplanation("req-1"); ok {
+		t.Fatal("expected req-1 to be evicted")
+	}
+	if _, ok := client.RoutingExplanation("req-2"); !ok {
+		t.Fatal("expected routing decision for req-2")
+	}
+
+	decisions := metrics.RouterDecisions.WithLabelValues(string(StrategySimpleShuffle), "gpt-test", attempt.Selected, metrics.RoutingOutcomeSelected)
+	if got := testutil.ToFloat64(decisions); got < 2 {
+		t.Fatalf("router_decisions_total = %v, want at least 2", got)
+	}
+}
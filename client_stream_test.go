@@ -6,11 +6,14 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/blueberrycongee/llmux/internal/plugin"
+	"github.com/blueberrycongee/llmux/pkg/provider"
+	"github.com/blueberrycongee/llmux/pkg/router"
 	"github.com/blueberrycongee/llmux/pkg/types"
 )
 
@@ -222,3 +225,205 @@ func TestClient_ChatCompletionStream_PluginHooks(t *testing.T) {
 		t.Error("expected PostStreamHook err to be nil")
 	}
 }
+
+// ttftRouter records the response metrics reported for streams.
+type ttftRouter struct {
+	sequenceRouter
+	mu      sync.Mutex
+	metrics *router.ResponseMetrics
+}
+
+func (r *ttftRouter) ReportSuccess(_ context.Context, _ *provider.Deployment, m *router.ResponseMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = m
+}
+
+func TestClient_ChatCompletionStream_ReportsTTFT(t *testing.T) {
+	const delay = 30 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(delay)
+		w.Write([]byte("data: {}\n\n"))
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	mock := &streamMockProvider{httpMockProvider: &httpMockProvider{
+		name: "mock-stream", models: []string{"test-model"}, baseURL: server.URL,
+	}}
+	rt := &ttftRouter{sequenceRouter: sequenceRouter{
+		deployments: []*provider.Deployment{{ID: "dep", ProviderName: "mock-stream", ModelName: "test-model"}},
+	}}
+	client, err := New(
+		WithProviderInstance("mock-stream", mock, []string{"test-model"}),
+		withTestPricing(t, "test-model"),
+		WithRouter(rt),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	stream, err := client.ChatCompletionStream(context.Background(), &ChatRequest{
+		Model:    "test-model",
+		Messages: []ChatMessage{{Role: "user", Content: json.RawMessage(`"hi"`)}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	if !stream.FirstTokenTime().IsZero() {
+		t.Fatal("expected no first token time before the first chunk")
+	}
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+	}
+
+	if ttft := stream.TTFT(); ttft < delay {
+		t.Fatalf("TTFT() = %v, want >= %v", ttft, delay)
+	}
+	if stream.FirstTokenTime().IsZero() {
+		t.Fatal("expected first token time after the first chunk")
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.metrics == nil {
+		t.Fatal("expected ReportSuccess to be called")
+	}
+	if rt.metrics.TimeToFirstToken < delay || rt.metrics.TimeToFirstToken > rt.metrics.Latency {
+		t.Fatalf("router TTFT = %v (latency %v), want between %v and latency", rt.metrics.TimeToFirstToken, rt.metrics.Latency, delay)
+	}
+}
+
+func TestClient_ChatCompletionStream_Transforms(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		for i := 0; i < 3; i++ {
+			w.Write([]byte("data: {}\n\n"))
+		}
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	mock := &streamMockProvider{httpMockProvider: &httpMockProvider{
+		name: "mock-stream", models: []string{"test-model"}, baseURL: server.URL,
+	}}
+	seen := 0
+	client, err := New(
+		WithProviderInstance("mock-stream", mock, []string{"test-model"}),
+		withTestPricing(t, "test-model"),
+		WithStreamTransform(func(c *StreamChunk) *StreamChunk {
+			seen++
+			if seen == 2 {
+				return nil
+			}
+			c.ID = "transformed"
+			return c
+		}),
+		WithStreamTransform(func(c *StreamChunk) *StreamChunk {
+			c.Model = c.ID + "-model"
+			return c
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	stream, err := client.ChatCompletionStream(context.Background(), &ChatRequest{
+		Model:    "test-model",
+		Messages: []ChatMessage{{Role: "user", Content: json.RawMessage(`"hi"`)}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	var chunks []*StreamChunk
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	if seen != 3 {
+		t.Fatalf("expected the first transform to see 3 chunks, saw %d", seen)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks after dropping one, got %d", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if chunk.ID != "transformed" || chunk.Model != "transformed-model" {
+			t.Fatalf("expected transforms to apply in order, got id=%q model=%q", chunk.ID, chunk.Model)
+		}
+	}
+}
+
+func TestClient_ChatCompletionStream_IdleTimeoutRecovers(t *testing.T) {
+	var requestCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		if atomic.AddInt32(&requestCount, 1) == 1 {
+			// Stall well past the idle timeout.
+			select {
+			case <-r.Context().Done():
+			case <-time.After(500 * time.Millisecond):
+			}
+			return
+		}
+		w.Write([]byte("data: {}\n\n"))
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	mock := &streamMockProvider{httpMockProvider: &httpMockProvider{
+		name: "mock-stream", models: []string{"test-model"}, baseURL: server.URL,
+	}}
+	client, err := New(
+		WithProviderInstance("mock-stream", mock, []string{"test-model"}),
+		withTestPricing(t, "test-model"),
+		WithStreamIdleTimeout(50*time.Millisecond),
+		WithRetry(3, time.Millisecond),
+		WithCooldown(0),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	stream, err := client.ChatCompletionStream(context.Background(), &ChatRequest{
+		Model:    "test-model",
+		Messages: []ChatMessage{{Role: "user", Content: json.RawMessage(`"hi"`)}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	start := time.Now()
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 500*time.Millisecond {
+		t.Fatalf("expected the idle stream to be aborted early, took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(&requestCount); got != 2 {
+		t.Fatalf("expected recovery to send a second request, got %d requests", got)
+	}
+}
@@ -0,0 +1,99 @@
+package llmux
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+func newTaggedUpstream(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"tags"`) {
+			http.Error(w, "tags leaked upstream", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/embeddings") {
+			_, _ = w.Write([]byte(`{"object":"list","data":[{"object":"embedding","index":0,"embedding":[0.1]}],"model":"gpt-test","usage":{"prompt_tokens":1,"total_tokens":1}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"resp-1","object":"chat.completion","model":"gpt-test","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+}
+
+func TestClient_TagRouting(t *testing.T) {
+	var euHits, usHits atomic.Int32
+	eu := newTaggedUpstream(t, &euHits)
+	defer eu.Close()
+	us := newTaggedUpstream(t, &usHits)
+	defer us.Close()
+
+	client, err := New(
+		WithProvider(ProviderConfig{
+			Name: "eu", Type: "openai", APIKey: "test-key", Models: []string{"gpt-test"},
+			BaseURL: eu.URL, AllowPrivateBaseURL: true, Tags: []string{"eu"},
+		}),
+		WithProvider(ProviderConfig{
+			Name: "us", Type: "openai", APIKey: "test-key", Models: []string{"gpt-test"},
+			BaseURL: us.URL, AllowPrivateBaseURL: true, Tags: []string{"us"},
+		}),
+		withTestPricing(t, "gpt-test"),
+		WithTagFiltering(true),
+		WithRetry(0, 0),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := client.ChatCompletion(context.Background(), &ChatRequest{
+			Model:    "gpt-test",
+			Messages: []ChatMessage{{Role: "user", Content: json.RawMessage(`"hi"`)}},
+			Tags:     []string{"eu"},
+		})
+		if err != nil {
+			t.Fatalf("ChatCompletion() error = %v", err)
+		}
+	}
+	if euHits.Load() != 5 || usHits.Load() != 0 {
+		t.Fatalf("expected all tagged requests on eu, got eu=%d us=%d", euHits.Load(), usHits.Load())
+	}
+
+	_, err = client.Embedding(context.Background(), &types.EmbeddingRequest{
+		Model: "gpt-test",
+		Input: types.NewEmbeddingInputFromString("hello"),
+		Tags:  []string{"us"},
+	})
+	if err != nil {
+		t.Fatalf("Embedding() error = %v", err)
+	}
+	if usHits.Load() != 1 {
+		t.Fatalf("expected embedding on us, got us=%d", usHits.Load())
+	}
+
+	_, err = client.ChatCompletion(context.Background(), &ChatRequest{
+		Model:    "gpt-test",
+		Messages: []ChatMessage{{Role: "user", Content: json.RawMessage(`"hi"`)}},
+		Tags:     []string{"apac"},
+	})
+	var llmErr *llmerrors.LLMError
+	if !errors.As(err, &llmErr) {
+		t.Fatalf("expected LLMError, got %v", err)
+	}
+	if llmErr.Code != llmerrors.CodeNoDeploymentsWithTag || llmErr.HTTPStatusCode() != http.StatusBadRequest {
+		t.Fatalf("unexpected error: %+v", llmErr)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -264,6 +265,30 @@ func TestOptions_WithRouterStrategy(t *testing.T) {
 	}
 }
 
+func TestOptions_WithRouterStrategy_Registered(t *testing.T) {
+	var created atomic.Bool
+	err := routers.Register("client-test-custom", func(cfg router.Config, statsStore router.StatsStore, _ router.RoundRobinStore) (router.Router, error) {
+		created.Store(true)
+		return routers.NewLeastBusyRouterWithConfig(cfg), nil
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	client, err := New(WithRouterStrategy("client-test-custom"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	if !created.Load() {
+		t.Fatal("expected registered router factory to be used")
+	}
+	if _, ok := client.router.(*routers.LeastBusyRouter); !ok {
+		t.Fatalf("expected registered router, got %T", client.router)
+	}
+}
+
 func TestOptions_WithRetry(t *testing.T) {
 	client, err := New(
 		WithRetry(5, 2*time.Second),
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/observability"
+)
+
+// buildAnomalyLogger returns the anomaly logging callback when enabled. It
+// writes to its own handler so anomaly records are emitted whatever the
+// gateway's log level.
+func buildAnomalyLogger(cfg config.LoggingConfig, w io.Writer) *observability.AnomalyLogger {
+	anomalies := cfg.Anomalies
+	if !anomalies.Enabled {
+		return nil
+	}
+	if w == nil {
+		w = os.Stdout
+	}
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var handler slog.Handler = slog.NewJSONHandler(w, opts)
+	if cfg.Format == "text" {
+		handler = slog.NewTextHandler(w, opts)
+	}
+
+	level := slog.LevelWarn
+	if anomalies.Level != "" {
+		_ = level.UnmarshalText([]byte(anomalies.Level))
+	}
+	return observability.NewAnomalyLogger(observability.AnomalyConfig{
+		Level:            level,
+		LatencyThreshold: anomalies.LatencyThreshold,
+		TTFTThreshold:    anomalies.TTFTThreshold,
+		CostThreshold:    anomalies.CostThreshold,
+		FailureThreshold: anomalies.FailureThreshold,
+		FailureWindow:    anomalies.FailureWindow,
+		SampleRate:       anomalies.SampleRate,
+		MaxPerMinute:     anomalies.MaxPerMinute,
+		IncludeMessages:  anomalies.IncludeMessages,
+	}, slog.New(handler), observability.NewRedactor())
+}
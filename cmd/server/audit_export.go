@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+// buildAuditExporters creates an exporter for every enabled audit export sink.
+func buildAuditExporters(ctx context.Context, cfg config.AuditExportConfig, logger *slog.Logger) ([]*auth.AuditExporter, error) {
+	var exporters []*auth.AuditExporter
+
+	if store := cfg.ObjectStore; store.Enabled {
+		sink, err := auth.NewObjectStoreAuditSink(ctx, auth.ObjectStoreAuditSinkConfig{
+			Provider:        store.Provider,
+			Bucket:          store.Bucket,
+			Region:          store.Region,
+			Endpoint:        store.Endpoint,
+			AccessKeyID:     store.AccessKeyID,
+			SecretAccessKey: store.SecretAccessKey,
+			PathPrefix:      store.PathPrefix,
+			Compress:        store.Compress,
+		})
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, auth.NewAuditExporter(sink, auth.AuditExporterConfig{
+			FlushInterval: store.FlushInterval,
+			BatchSize:     store.BatchSize,
+		}, logger))
+	}
+
+	if wh := cfg.Webhook; wh.Enabled {
+		sink, err := auth.NewWebhookAuditSink(auth.WebhookAuditSinkConfig{
+			URL:     wh.URL,
+			Headers: wh.Headers,
+			Timeout: wh.Timeout,
+		})
+		if err != nil {
+			shutdownAuditExporters(ctx, exporters, logger)
+			return nil, err
+		}
+		exporters = append(exporters, auth.NewAuditExporter(sink, auth.AuditExporterConfig{
+			FlushInterval: wh.FlushInterval,
+			BatchSize:     wh.BatchSize,
+		}, logger))
+	}
+
+	if sl := cfg.Syslog; sl.Enabled {
+		sink, err := auth.NewSyslogAuditSink(auth.SyslogAuditSinkConfig{
+			Network: sl.Network,
+			Address: sl.Address,
+			AppName: sl.AppName,
+		})
+		if err != nil {
+			shutdownAuditExporters(ctx, exporters, logger)
+			return nil, err
+		}
+		exporters = append(exporters, auth.NewAuditExporter(sink, auth.AuditExporterConfig{
+			FlushInterval: sl.FlushInterval,
+			BatchSize:     sl.BatchSize,
+		}, logger))
+	}
+
+	return exporters, nil
+}
+
+// shutdownAuditExporters flushes buffered events and stops the exporters.
+func shutdownAuditExporters(ctx context.Context, exporters []*auth.AuditExporter, logger *slog.Logger) {
+	for _, exp := range exporters {
+		if err := exp.Shutdown(ctx); err != nil {
+			logger.Warn("failed to flush audit export", "error", err)
+		}
+	}
+}
+
+// attachAuditExporters builds the configured exporters and attaches them to
+// auditLogger. The returned function flushes and stops them.
+func attachAuditExporters(ctx context.Context, cfg *config.Config, auditLogger *auth.AuditLogger, logger *slog.Logger) (func(context.Context), error) {
+	exporters, err := buildAuditExporters(ctx, cfg.Governance.AuditExport, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize audit export: %w", err)
+	}
+	for _, exp := range exporters {
+		auditLogger.AddExporter(exp)
+	}
+	if len(exporters) > 0 {
+		logger.Info("audit export enabled", "sinks", len(exporters))
+	}
+	return func(ctx context.Context) {
+		shutdownAuditExporters(ctx, exporters, logger)
+	}, nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+func TestBuildAuditExporters(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	exporters, err := buildAuditExporters(context.Background(), config.AuditExportConfig{}, logger)
+	if err != nil {
+		t.Fatalf("buildAuditExporters() error = %v", err)
+	}
+	if len(exporters) != 0 {
+		t.Fatalf("expected no exporters by default, got %d", len(exporters))
+	}
+
+	exporters, err = buildAuditExporters(context.Background(), config.AuditExportConfig{
+		Webhook: config.AuditWebhookExportConfig{Enabled: true, URL: "http://127.0.0.1:1/ingest"},
+		Syslog:  config.AuditSyslogExportConfig{Enabled: true, Address: "127.0.0.1:514"},
+	}, logger)
+	if err != nil {
+		t.Fatalf("buildAuditExporters() error = %v", err)
+	}
+	if len(exporters) != 2 {
+		t.Fatalf("expected 2 exporters, got %d", len(exporters))
+	}
+	shutdownAuditExporters(context.Background(), exporters, logger)
+
+	if _, err := buildAuditExporters(context.Background(), config.AuditExportConfig{
+		Webhook: config.AuditWebhookExportConfig{Enabled: true},
+	}, logger); err == nil {
+		t.Fatal("expected error for webhook without url")
+	}
+}
@@ -23,6 +23,12 @@ func initAuthStores(cfg *config.Config, logger *slog.Logger) (auth.Store, auth.A
 		if err != nil {
 			return nil, nil, fmt.Errorf("init postgres auth store: %w", err)
 		}
+		if cfg.Database.AutoMigrate {
+			if err := migrateAuthStore(store, logger); err != nil {
+				_ = store.Close()
+				return nil, nil, err
+			}
+		}
 		logger.Info("using postgres auth store",
 			"host", postgresCfg.Host,
 			"port", postgresCfg.Port,
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log/slog"
@@ -107,6 +108,61 @@ func TestInitAuthStoresPostgresError(t *testing.T) {
 	}
 }
 
+type migratingStore struct {
+	*auth.MemoryStore
+	err      error
+	migrated bool
+}
+
+func (s *migratingStore) Migrate(context.Context) (int, error) {
+	s.migrated = true
+	return auth.LatestSchemaVersion, s.err
+}
+
+func TestInitAuthStoresAutoMigrate(t *testing.T) {
+	oldPostgresStores := newPostgresStores
+	t.Cleanup(func() {
+		newPostgresStores = oldPostgresStores
+	})
+
+	for _, autoMigrate := range []bool{true, false} {
+		store := &migratingStore{MemoryStore: auth.NewMemoryStore()}
+		newPostgresStores = func(*auth.PostgresConfig) (auth.Store, auth.AuditLogStore, error) {
+			return store, auth.NewMemoryAuditLogStore(), nil
+		}
+
+		cfg := &config.Config{Database: config.DatabaseConfig{Enabled: true, AutoMigrate: autoMigrate}}
+		if _, _, err := initAuthStores(cfg, slog.New(slog.NewTextHandler(io.Discard, nil))); err != nil {
+			t.Fatalf("initAuthStores returned error: %v", err)
+		}
+		if store.migrated != autoMigrate {
+			t.Fatalf("auto_migrate=%v: migrated = %v", autoMigrate, store.migrated)
+		}
+	}
+}
+
+func TestInitAuthStoresMigrationError(t *testing.T) {
+	oldPostgresStores := newPostgresStores
+	t.Cleanup(func() {
+		newPostgresStores = oldPostgresStores
+	})
+
+	newPostgresStores = func(*auth.PostgresConfig) (auth.Store, auth.AuditLogStore, error) {
+		return &migratingStore{MemoryStore: auth.NewMemoryStore(), err: errors.New("lock timeout")}, auth.NewMemoryAuditLogStore(), nil
+	}
+
+	cfg := &config.Config{Database: config.DatabaseConfig{Enabled: true, AutoMigrate: true}}
+	if _, _, err := initAuthStores(cfg, slog.New(slog.NewTextHandler(io.Discard, nil))); err == nil {
+		t.Fatal("expected migration error, got nil")
+	}
+}
+
+func TestRunMigrateOnlyRequiresDatabase(t *testing.T) {
+	if err := runMigrateOnly(&config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil))); err == nil {
+		t.Fatal("expected error without database.enabled")
+	}
+}
+
 func TestBuildPostgresConfigDefaults(t *testing.T) {
 	got := buildPostgresConfig(config.DatabaseConfig{})
 	want := auth.DefaultPostgresConfig()
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -8,12 +9,52 @@ import (
 
 	llmux "github.com/blueberrycongee/llmux"
 	"github.com/blueberrycongee/llmux/caches/dual"
+	"github.com/blueberrycongee/llmux/caches/dynamodb"
+	"github.com/blueberrycongee/llmux/caches/encrypted"
+	"github.com/blueberrycongee/llmux/caches/memcached"
 	"github.com/blueberrycongee/llmux/caches/memory"
 	"github.com/blueberrycongee/llmux/caches/redis"
 	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/secret"
+	"github.com/blueberrycongee/llmux/pkg/encryption"
 )
 
-func buildCacheOptions(cfg *config.CacheConfig, logger *slog.Logger) ([]llmux.Option, error) {
+// buildEncryptionKeyring resolves the master keys (which may be secret
+// references) and returns nil when encryption at rest is disabled.
+func buildEncryptionKeyring(cfg config.EncryptionConfig, secretManager *secret.Manager) (*encryption.Keyring, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resolve := func(ref string) ([]byte, error) {
+		value := ref
+		if secretManager != nil {
+			var err error
+			if value, err = secretManager.Get(ctx, ref); err != nil {
+				return nil, fmt.Errorf("resolve master key: %w", err)
+			}
+		}
+		return encryption.ParseKey(value)
+	}
+
+	primary, err := resolve(cfg.MasterKey)
+	if err != nil {
+		return nil, err
+	}
+	previous := make([][]byte, 0, len(cfg.PreviousMasterKeys))
+	for _, ref := range cfg.PreviousMasterKeys {
+		key, err := resolve(ref)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, key)
+	}
+	return encryption.NewKeyring(primary, previous...)
+}
+
+func buildCacheOptions(cfg *config.CacheConfig, keyring *encryption.Keyring, logger *slog.Logger) ([]llmux.Option, error) {
 	if cfg == nil || !cfg.Enabled {
 		return nil, nil
 	}
@@ -53,14 +94,52 @@ func buildCacheOptions(cfg *config.CacheConfig, logger *slog.Logger) ([]llmux.Op
 		if cfg.Memory.DefaultTTL > 0 {
 			dualCfg.LocalTTL = cfg.Memory.DefaultTTL
 		}
+		if cfg.Dual.LocalTTL > 0 {
+			dualCfg.LocalTTL = cfg.Dual.LocalTTL
+		}
 		if cfg.TTL > 0 {
 			dualCfg.RedisTTL = cfg.TTL
 		}
+		dualCfg.WriteBehind = cfg.Dual.WriteBehind
+		if cfg.Dual.WriteQueueSize > 0 {
+			dualCfg.WriteQueueSize = cfg.Dual.WriteQueueSize
+		}
 		cacheInstance = dual.New(local, remote, dualCfg)
+	case "memcached":
+		memcachedCache, err := memcached.New(memcached.Config{
+			Addrs:        cfg.Memcached.Addrs,
+			Namespace:    cfg.Namespace,
+			DefaultTTL:   cfg.TTL,
+			Timeout:      cfg.Memcached.Timeout,
+			MaxIdleConns: cfg.Memcached.MaxIdleConns,
+		})
+		if err != nil {
+			return nil, err
+		}
+		cacheInstance = memcachedCache
+	case "dynamodb":
+		dynamoCache, err := dynamodb.New(dynamodb.Config{
+			Table:        cfg.DynamoDB.Table,
+			Region:       cfg.DynamoDB.Region,
+			Endpoint:     cfg.DynamoDB.Endpoint,
+			Namespace:    cfg.Namespace,
+			DefaultTTL:   cfg.TTL,
+			Timeout:      cfg.DynamoDB.Timeout,
+			KeyAttribute: cfg.DynamoDB.KeyAttribute,
+			TTLAttribute: cfg.DynamoDB.TTLAttribute,
+		})
+		if err != nil {
+			return nil, err
+		}
+		cacheInstance = dynamoCache
 	default:
 		return nil, fmt.Errorf("unsupported cache type: %s", cfg.Type)
 	}
 
+	if keyring != nil {
+		cacheInstance = encrypted.New(cacheInstance, keyring, nil)
+	}
+
 	opts := []llmux.Option{
 		llmux.WithCache(cacheInstance),
 		llmux.WithCacheTypeLabel(cacheType),
@@ -68,8 +147,11 @@ func buildCacheOptions(cfg *config.CacheConfig, logger *slog.Logger) ([]llmux.Op
 	if cfg.TTL > 0 {
 		opts = append(opts, llmux.WithCacheTTL(cfg.TTL))
 	}
+	if cfg.PrefixCache {
+		opts = append(opts, llmux.WithPrefixCache(true))
+	}
 
-	logger.Info("cache enabled", "type", cacheType)
+	logger.Info("cache enabled", "type", cacheType, "prefix_cache", cfg.PrefixCache, "encrypted", keyring != nil)
 	return opts, nil
 }
 
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"bytes"
+	"encoding/base64"
 	"io"
 	"log/slog"
 	"testing"
 	"time"
 
+	"github.com/blueberrycongee/llmux/caches/encrypted"
 	"github.com/blueberrycongee/llmux/caches/memory"
 	"github.com/blueberrycongee/llmux/internal/config"
 )
@@ -62,3 +65,24 @@ func TestBuildClientOptions_CacheEnabledLocal(t *testing.T) {
 		t.Fatalf("expected cache type label local, got %s", clientCfg.CacheTypeLabel)
 	}
 }
+
+func TestBuildClientOptions_CacheEncrypted(t *testing.T) {
+	cfg := &config.Config{
+		Cache: config.CacheConfig{Enabled: true, Type: "local"},
+		Encryption: config.EncryptionConfig{
+			Enabled:   true,
+			MasterKey: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32)),
+		},
+	}
+
+	clientCfg := applyOptions(buildClientOptions(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil))
+	if _, ok := clientCfg.Cache.(*encrypted.Cache); !ok {
+		t.Fatalf("expected encrypted cache, got %T", clientCfg.Cache)
+	}
+
+	cfg.Encryption.MasterKey = "too-short"
+	clientCfg = applyOptions(buildClientOptions(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil))
+	if clientCfg.Cache != nil {
+		t.Fatalf("expected cache disabled when the master key is invalid, got %T", clientCfg.Cache)
+	}
+}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log/slog"
+	"reflect"
+	"sync"
 	"sync/atomic"
 
 	llmux "github.com/blueberrycongee/llmux"
 	"github.com/blueberrycongee/llmux/internal/api"
+	"github.com/blueberrycongee/llmux/internal/auth"
 	"github.com/blueberrycongee/llmux/internal/config"
 )
 
@@ -14,6 +19,19 @@ type clientReloader struct {
 	swapper    *api.ClientSwapper
 	build      func(*config.Config) (*llmux.Client, error)
 	inProgress atomic.Bool
+
+	// mu serializes rebuilds and guards current and modelProviders.
+	mu sync.Mutex
+
+	// current is the config of the running client. When a reload changes
+	// only plugins, reloadPlugins swaps them on that client instead of
+	// rebuilding it, keeping its caches and routing state.
+	current       *config.Config
+	reloadPlugins func(client *llmux.Client, prev, next []config.PluginConfig) error
+
+	// modelProviders holds the providers managed through the /model
+	// endpoints; every rebuild adds them to the config's own.
+	modelProviders auth.ModelProviderStore
 }
 
 func newClientReloader(logger *slog.Logger, swapper *api.ClientSwapper, build func(*config.Config) (*llmux.Client, error)) *clientReloader {
@@ -27,27 +45,86 @@ func newClientReloader(logger *slog.Logger, swapper *api.ClientSwapper, build fu
 	}
 }
 
+// enablePluginReload applies plugin-only config changes through reload
+// instead of rebuilding the client. current is the running client's config.
+func (r *clientReloader) enablePluginReload(current *config.Config, reload func(client *llmux.Client, prev, next []config.PluginConfig) error) {
+	r.current = current
+	r.reloadPlugins = reload
+}
+
+// enableModelProviders makes every rebuild include the providers of store.
+// It does not rebuild the running client; call Rebuild for that.
+func (r *clientReloader) enableModelProviders(store auth.ModelProviderStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modelProviders = store
+}
+
 func (r *clientReloader) Reload(cfg *config.Config) {
 	if !r.inProgress.CompareAndSwap(false, true) {
 		r.logger.Warn("client reload already in progress")
 		return
 	}
 	defer r.inProgress.Store(false)
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	next, err := r.build(cfg)
-	if err != nil {
+	if r.reloadPlugins != nil && r.current != nil && onlyPluginsChanged(r.current, cfg) {
+		if err := r.reloadPlugins(r.swapper.Current(), r.current.Plugins, cfg.Plugins); err != nil {
+			r.logger.Error("failed to reload plugins", "error", err)
+			return
+		}
+		r.current = cfg
+		r.logger.Info("plugins reloaded", "plugins", len(cfg.Plugins))
+		return
+	}
+
+	if err := r.rebuild(context.Background(), cfg); err != nil {
 		r.logger.Error("failed to rebuild llmux client", "error", err)
 		return
 	}
+	r.current = cfg
+}
+
+// Rebuild rebuilds the client from the current config, picking up changes
+// to the stored model providers. Unlike Reload it waits for a rebuild in
+// progress and reports failure to the caller.
+func (r *clientReloader) Rebuild(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current == nil {
+		return errors.New("no current config to rebuild from")
+	}
+	return r.rebuild(ctx, r.current)
+}
+
+// rebuild builds a client from cfg plus the stored model providers and swaps
+// it in. The caller holds r.mu.
+func (r *clientReloader) rebuild(ctx context.Context, cfg *config.Config) error {
+	merged, err := withModelProviders(ctx, cfg, r.modelProviders, r.logger)
+	if err != nil {
+		return err
+	}
+	next, err := r.build(merged)
+	if err != nil {
+		return err
+	}
 	if next == nil {
-		r.logger.Error("failed to rebuild llmux client", "error", "nil client")
-		return
+		return errors.New("nil client")
 	}
 
 	r.swapper.Swap(next)
 
 	r.logger.Info("llmux client reloaded",
-		"providers", len(cfg.Providers),
-		"routing_strategy", cfg.Routing.Strategy,
+		"providers", len(merged.Providers),
+		"routing_strategy", merged.Routing.Strategy,
 	)
+	return nil
+}
+
+// onlyPluginsChanged reports whether prev and next differ in plugins alone.
+func onlyPluginsChanged(prev, next *config.Config) bool {
+	a, b := *prev, *next
+	a.Plugins, b.Plugins = nil, nil
+	return reflect.DeepEqual(a, b)
 }
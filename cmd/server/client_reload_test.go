@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log/slog"
@@ -10,6 +11,7 @@ import (
 
 	llmux "github.com/blueberrycongee/llmux"
 	"github.com/blueberrycongee/llmux/internal/api"
+	"github.com/blueberrycongee/llmux/internal/auth"
 	"github.com/blueberrycongee/llmux/internal/config"
 )
 
@@ -52,4 +54,75 @@ func TestClientReloaderKeepsClientOnFailure(t *testing.T) {
 	require.Same(t, initial, swapper.Current())
 }
 
+func TestClientReloaderReloadsPluginsInPlace(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{}))
+
+	initial, err := llmux.New()
+	require.NoError(t, err)
+
+	swapper := api.NewClientSwapper(initial)
+	t.Cleanup(swapper.Close)
+
+	builds := 0
+	reloader := newClientReloader(logger, swapper, func(*config.Config) (*llmux.Client, error) {
+		builds++
+		return llmux.New()
+	})
+	current := &config.Config{Server: config.ServerConfig{Port: 8080}}
+	reloader.enablePluginReload(current, func(client *llmux.Client, prev, next []config.PluginConfig) error {
+		return replaceConfigPlugins(client, prev, next, logger)
+	})
+
+	pluginsOnly := *current
+	pluginsOnly.Plugins = []config.PluginConfig{{Name: "policy", Type: "http", URL: "http://policy:8000/hook"}}
+	reloader.Reload(&pluginsOnly)
+
+	require.Same(t, initial, swapper.Current())
+	require.Zero(t, builds)
+
+	rebuilt := pluginsOnly
+	rebuilt.Server.Port = 9090
+	reloader.Reload(&rebuilt)
+
+	require.NotSame(t, initial, swapper.Current())
+	require.Equal(t, 1, builds)
+}
+
+func TestClientReloaderRebuildAddsModelProviders(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{}))
+
+	initial, err := llmux.New()
+	require.NoError(t, err)
+
+	swapper := api.NewClientSwapper(initial)
+	t.Cleanup(swapper.Close)
+
+	var built *config.Config
+	reloader := newClientReloader(logger, swapper, func(cfg *config.Config) (*llmux.Client, error) {
+		built = cfg
+		return llmux.New()
+	})
+	current := &config.Config{Providers: []config.ProviderConfig{{Name: "openai", Type: "openai", Models: []string{"gpt-4o"}}}}
+	reloader.enablePluginReload(current, func(*llmux.Client, []config.PluginConfig, []config.PluginConfig) error { return nil })
+
+	store := auth.NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, store.SaveModelProvider(ctx, &auth.ModelProvider{Name: "azure-east", Type: "azure", Models: []string{"gpt-4o"}}))
+	require.NoError(t, store.SaveModelProvider(ctx, &auth.ModelProvider{Name: "openai", Type: "openai", Models: []string{"o1"}}))
+	require.NoError(t, enableStoredModelProviders(ctx, reloader, store, logger))
+
+	require.NotSame(t, initial, swapper.Current())
+	require.Len(t, built.Providers, 2, "config file providers shadow stored ones")
+	require.Equal(t, "azure-east", built.Providers[1].Name)
+	require.Len(t, current.Providers, 1)
+
+	// File reloads keep the stored providers.
+	next := *current
+	next.Server.Port = 9090
+	reloader.Reload(&next)
+	require.Len(t, built.Providers, 2)
+	require.Equal(t, 9090, built.Server.Port)
+	require.Equal(t, "azure-east", built.Providers[1].Name)
+}
+
 var errTestReload = errors.New("reload failed")
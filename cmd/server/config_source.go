@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+// useDBConfigSource applies the stored config on top of the file for
+// config.source db and starts polling for versions committed elsewhere.
+func useDBConfigSource(ctx context.Context, mgr *config.Manager, authStore auth.Store, logger *slog.Logger) error {
+	versions, ok := auth.ConfigVersions(authStore)
+	if !ok {
+		return errors.New("config.source db requires a database-backed auth store")
+	}
+	if err := mgr.UseVersionStore(ctx, configVersionStore{store: versions}); err != nil {
+		return fmt.Errorf("load config from database: %w", err)
+	}
+	mgr.WatchVersions(ctx)
+	logger.Info("config loaded from database", "version", mgr.ActiveVersion().Version)
+	return nil
+}
+
+// configVersionStore adapts the auth store's config versions to
+// config.VersionStore.
+type configVersionStore struct {
+	store auth.ConfigVersionStore
+}
+
+func (s configVersionStore) LatestConfigVersion(ctx context.Context) (*config.ConfigVersion, error) {
+	v, err := s.store.LatestConfigVersion(ctx)
+	return toConfigVersion(v), err
+}
+
+func (s configVersionStore) GetConfigVersion(ctx context.Context, version int) (*config.ConfigVersion, error) {
+	v, err := s.store.GetConfigVersion(ctx, version)
+	return toConfigVersion(v), err
+}
+
+func (s configVersionStore) ListConfigVersions(ctx context.Context, limit int) ([]*config.ConfigVersion, error) {
+	list, err := s.store.ListConfigVersions(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*config.ConfigVersion, 0, len(list))
+	for _, v := range list {
+		out = append(out, toConfigVersion(v))
+	}
+	return out, nil
+}
+
+func (s configVersionStore) CreateConfigVersion(ctx context.Context, v *config.ConfigVersion) error {
+	stored := auth.ConfigVersion{
+		Document:  v.Document,
+		Checksum:  v.Checksum,
+		Comment:   v.Comment,
+		CreatedBy: v.CreatedBy,
+	}
+	if err := s.store.CreateConfigVersion(ctx, &stored); err != nil {
+		return err
+	}
+	v.Version, v.CreatedAt = stored.Version, stored.CreatedAt
+	return nil
+}
+
+func toConfigVersion(v *auth.ConfigVersion) *config.ConfigVersion {
+	if v == nil {
+		return nil
+	}
+	return &config.ConfigVersion{
+		Version:   v.Version,
+		Document:  v.Document,
+		Checksum:  v.Checksum,
+		Comment:   v.Comment,
+		CreatedBy: v.CreatedBy,
+		CreatedAt: v.CreatedAt,
+	}
+}
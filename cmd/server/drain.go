@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// drainRetryAfter is the Retry-After sent on requests rejected while
+// draining, in seconds. Another replica usually serves the retry.
+const drainRetryAfter = "5"
+
+// drainer tracks in-flight requests and streams so shutdown can let them
+// finish. Once draining, new requests are rejected with 503.
+type drainer struct {
+	draining atomic.Bool
+	requests atomic.Int64
+	streams  atomic.Int64
+}
+
+// Middleware counts requests and rejects new ones while draining.
+// /health/live stays up so the process is not restarted mid-drain;
+// /health/ready fails, taking the replica out of load balancing.
+func (d *drainer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d.requests.Add(1)
+		defer d.requests.Add(-1)
+
+		if d.draining.Load() && r.URL.Path != "/health/live" {
+			w.Header().Set("Retry-After", drainRetryAfter)
+			w.Header().Set("Connection", "close")
+			writeAuthzError(w, r, http.StatusServiceUnavailable, "server is shutting down", "service_unavailable")
+			return
+		}
+
+		rec := &drainRecorder{ResponseWriter: w, drainer: d}
+		defer rec.done()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// Drain starts rejecting new requests and waits until in-flight requests
+// finish or ctx is done. It returns the requests and streams still active.
+func (d *drainer) Drain(ctx context.Context) (requests, streams int64) {
+	d.draining.Store(true)
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if n := d.requests.Load(); n == 0 {
+			return 0, 0
+		}
+		select {
+		case <-ctx.Done():
+			return d.requests.Load(), d.streams.Load()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Active returns the in-flight requests and streams.
+func (d *drainer) Active() (requests, streams int64) {
+	return d.requests.Load(), d.streams.Load()
+}
+
+// drainRecorder counts a response as a stream once it is sent as
+// server-sent events.
+type drainRecorder struct {
+	http.ResponseWriter
+	drainer     *drainer
+	wroteHeader bool
+	stream      bool
+}
+
+func (r *drainRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+		if strings.HasPrefix(r.Header().Get("Content-Type"), "text/event-stream") {
+			r.stream = true
+			r.drainer.streams.Add(1)
+		}
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *drainRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher interface for streaming support.
+func (r *drainRecorder) Flush() {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (r *drainRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *drainRecorder) done() {
+	if r.stream {
+		r.drainer.streams.Add(-1)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainer_LetsStreamsFinishAndRejectsNewRequests(t *testing.T) {
+	d := &drainer{}
+	streaming := make(chan struct{})
+	release := make(chan struct{})
+	handler := d.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/chat/completions" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("data: {}\n\n"))
+			w.(http.Flusher).Flush()
+			close(streaming)
+			<-release
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	streamRec := httptest.NewRecorder()
+	streamDone := make(chan struct{})
+	go func() {
+		defer close(streamDone)
+		handler.ServeHTTP(streamRec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil))
+	}()
+	<-streaming
+	requests, streams := d.Active()
+	require.Equal(t, int64(1), requests)
+	require.Equal(t, int64(1), streams)
+
+	drained := make(chan int64)
+	go func() {
+		n, _ := d.Drain(context.Background())
+		drained <- n
+	}()
+	require.Eventually(t, d.draining.Load, time.Second, 5*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/models", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Equal(t, drainRetryAfter, rec.Header().Get("Retry-After"))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	close(release)
+	<-streamDone
+	require.Equal(t, int64(0), <-drained)
+	require.Contains(t, streamRec.Body.String(), "[DONE]")
+	_, streams = d.Active()
+	require.Zero(t, streams)
+}
+
+func TestDrainer_DeadlineReportsActiveRequests(t *testing.T) {
+	d := &drainer{}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	handler := d.Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		close(started)
+		<-release
+	}))
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	requests, _ := d.Drain(ctx)
+	require.Equal(t, int64(1), requests)
+}
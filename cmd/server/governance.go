@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"time"
 
@@ -24,29 +25,126 @@ func buildGovernanceEngine(cfg *config.Config, authStore auth.Store, auditLogger
 		rateLimiter = buildTenantRateLimiter(cfg, logger)
 	}
 
-	var idempotency governance.IdempotencyStore
+	var (
+		idempotency governance.IdempotencyStore
+		responses   governance.ResponseStore
+	)
 	if cfg.Governance.IdempotencyWindow > 0 {
-		if cfg.Deployment.Mode == "distributed" && (cfg.Cache.Redis.Addr != "" || len(cfg.Cache.Redis.ClusterAddrs) > 0) {
-			redisClient, _, err := newRedisUniversalClient(cfg.Cache.Redis)
-			if err != nil {
-				logger.Warn("distributed idempotency unavailable, falling back to memory", "error", err)
-			} else {
-				idempotency = governance.NewRedisIdempotencyStore(redisClient, "llmux:idempotency:")
-			}
-		}
-		if idempotency == nil {
-			idempotency = governance.NewMemoryIdempotencyStore()
-		}
+		idempotency = buildIdempotencyStore(cfg, "llmux:idempotency:", logger)
+		responses = buildResponseStore(cfg, "llmux:idempotent_response:", logger)
 	}
 
-	return governance.NewEngine(mapGovernanceConfig(cfg.Governance),
+	opts := []governance.Option{
 		governance.WithStore(authStore),
 		governance.WithRateLimiter(rateLimiter),
 		governance.WithAuditLogger(auditLogger),
 		governance.WithIdempotencyStore(idempotency),
+		governance.WithResponseStore(responses),
 		governance.WithLogger(logger),
 		governance.WithCasbinEnforcer(enforcer),
-	)
+	}
+	if alerter, err := buildBudgetAlerter(cfg, logger); err != nil {
+		logger.Warn("failed to initialize budget alerts, disabling", "error", err)
+	} else if alerter != nil {
+		opts = append(opts, governance.WithBudgetAlerter(alerter))
+	}
+	return governance.NewEngine(mapGovernanceConfig(cfg.Governance), opts...)
+}
+
+// buildIdempotencyStore shares keys through Redis in distributed mode and
+// keeps them in memory otherwise.
+func buildIdempotencyStore(cfg *config.Config, prefix string, logger *slog.Logger) governance.IdempotencyStore {
+	if cfg.Deployment.Mode == "distributed" && (cfg.Cache.Redis.Addr != "" || len(cfg.Cache.Redis.ClusterAddrs) > 0) {
+		redisClient, _, err := newRedisUniversalClient(cfg.Cache.Redis)
+		if err != nil {
+			logger.Warn("distributed idempotency unavailable, falling back to memory", "error", err, "prefix", prefix)
+		} else {
+			return governance.NewRedisIdempotencyStore(redisClient, prefix)
+		}
+	}
+	return governance.NewMemoryIdempotencyStore()
+}
+
+// buildKillSwitch shares emergency blocks through Redis in distributed mode,
+// so a block engaged on one instance applies to all of them, and keeps them
+// in memory otherwise. A distributed kill switch that cannot reach Redis is
+// fatal rather than silently local.
+func buildKillSwitch(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*governance.KillSwitch, error) {
+	if cfg.Deployment.Mode != "distributed" || (cfg.Cache.Redis.Addr == "" && len(cfg.Cache.Redis.ClusterAddrs) == 0) {
+		return governance.NewKillSwitch(), nil
+	}
+	redisClient, _, err := newRedisUniversalClient(cfg.Cache.Redis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Redis for the kill switch: %w", err)
+	}
+	initCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := redisClient.Ping(initCtx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis for the kill switch: %w", err)
+	}
+	killSwitch, err := governance.NewSharedKillSwitch(ctx, governance.NewRedisKillSwitchStore(redisClient, ""), logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize shared kill switch: %w", err)
+	}
+	logger.Info("kill switch shared through Redis")
+	return killSwitch, nil
+}
+
+// buildResponseStore keeps Idempotency-Key responses like buildIdempotencyStore
+// keeps keys: in Redis in distributed mode and in memory otherwise.
+func buildResponseStore(cfg *config.Config, prefix string, logger *slog.Logger) governance.ResponseStore {
+	if cfg.Deployment.Mode == "distributed" && (cfg.Cache.Redis.Addr != "" || len(cfg.Cache.Redis.ClusterAddrs) > 0) {
+		redisClient, _, err := newRedisUniversalClient(cfg.Cache.Redis)
+		if err != nil {
+			logger.Warn("distributed idempotent responses unavailable, falling back to memory", "error", err, "prefix", prefix)
+		} else {
+			return governance.NewRedisResponseStore(redisClient, prefix)
+		}
+	}
+	return governance.NewMemoryResponseStore()
+}
+
+// defaultBudgetAlertThresholds alert at 80% and 100% of max budget.
+var defaultBudgetAlertThresholds = []float64{0.8, 1}
+
+func buildBudgetAlerter(cfg *config.Config, logger *slog.Logger) (*governance.BudgetAlerter, error) {
+	alerts := cfg.Governance.BudgetAlerts
+	if !alerts.Enabled {
+		return nil, nil
+	}
+
+	var notifiers []governance.BudgetNotifier
+	for _, w := range alerts.Webhooks {
+		n, err := governance.NewWebhookBudgetNotifier(governance.WebhookBudgetNotifierConfig{URL: w.URL, Headers: w.Headers})
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, n)
+	}
+	if alerts.Email.Enabled {
+		n, err := governance.NewEmailBudgetNotifier(governance.EmailBudgetNotifierConfig{
+			Host:     alerts.Email.Host,
+			Port:     alerts.Email.Port,
+			Username: alerts.Email.Username,
+			Password: alerts.Email.Password,
+			From:     alerts.Email.From,
+			To:       append([]string(nil), alerts.Email.To...),
+		})
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, n)
+	}
+
+	thresholds := alerts.Thresholds
+	if len(thresholds) == 0 {
+		thresholds = defaultBudgetAlertThresholds
+	}
+	logger.Info("budget alerts enabled", "thresholds", thresholds, "notifiers", len(notifiers))
+	return governance.NewBudgetAlerter(governance.BudgetAlertConfig{
+		Thresholds:   thresholds,
+		DedupeWindow: alerts.DedupeWindow,
+	}, buildIdempotencyStore(cfg, "llmux:budget_alert:", logger), logger, notifiers...), nil
 }
 
 func mapGovernanceConfig(cfg config.GovernanceConfig) governance.Config {
@@ -55,7 +153,41 @@ func mapGovernanceConfig(cfg config.GovernanceConfig) governance.Config {
 		AsyncAccounting:   cfg.AsyncAccounting,
 		IdempotencyWindow: cfg.IdempotencyWindow,
 		AuditEnabled:      cfg.AuditEnabled,
+		ContentPolicies:   mapContentPolicies(cfg.ContentPolicies),
+		TagBudgets:        mapTagBudgets(cfg.TagBudgets),
+	}
+}
+
+func mapTagBudgets(budgets []config.TagBudgetConfig) []governance.TagBudget {
+	if len(budgets) == 0 {
+		return nil
+	}
+	out := make([]governance.TagBudget, 0, len(budgets))
+	for _, b := range budgets {
+		out = append(out, governance.TagBudget{
+			Tag:       b.Tag,
+			MaxBudget: b.MaxBudget,
+			Duration:  auth.BudgetDuration(b.BudgetDuration),
+		})
+	}
+	return out
+}
+
+func mapContentPolicies(policies []config.ContentPolicyConfig) []governance.ContentPolicy {
+	if len(policies) == 0 {
+		return nil
+	}
+	out := make([]governance.ContentPolicy, 0, len(policies))
+	for _, p := range policies {
+		out = append(out, governance.ContentPolicy{
+			TeamID:        p.TeamID,
+			Category:      p.Category,
+			RPMLimit:      p.RPMLimit,
+			AllowedModels: append([]string(nil), p.AllowedModels...),
+			Tags:          append([]string(nil), p.Tags...),
+		})
 	}
+	return out
 }
 
 func buildTenantRateLimiter(cfg *config.Config, logger *slog.Logger) *auth.TenantRateLimiter {
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/guardrails"
+)
+
+// buildGuardrailOptions builds the guardrails engine from config and installs
+// it as a pipeline plugin. It returns no options when none are configured.
+func buildGuardrailOptions(cfg []config.GuardrailConfig, logger *slog.Logger) ([]llmux.Option, error) {
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+	list := make([]*guardrails.Guardrail, 0, len(cfg))
+	for _, gc := range cfg {
+		g, err := buildGuardrail(gc)
+		if err != nil {
+			return nil, fmt.Errorf("guardrail %q: %w", gc.Name, err)
+		}
+		list = append(list, g)
+	}
+	engine, err := guardrails.NewEngine(list...)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("guardrails enabled", "count", engine.Len())
+	return []llmux.Option{llmux.WithPlugin(guardrails.NewPlugin(engine, logger))}, nil
+}
+
+func buildGuardrail(cfg config.GuardrailConfig) (*guardrails.Guardrail, error) {
+	g := &guardrails.Guardrail{
+		Name:      cfg.Name,
+		Action:    guardrails.Action(cfg.Action),
+		DefaultOn: cfg.DefaultOn,
+	}
+	switch cfg.Mode {
+	case "", "pre":
+		g.Pre = true
+	case "post":
+		g.Post = true
+	case "both":
+		g.Pre, g.Post = true, true
+	default:
+		return nil, fmt.Errorf("unknown mode %q", cfg.Mode)
+	}
+
+	var err error
+	switch cfg.Type {
+	case "regex":
+		g.Check, err = guardrails.NewRegexCheck(cfg.Patterns)
+	case "pii":
+		g.Check, err = guardrails.NewPIICheck(cfg.Entities)
+	case "prompt_injection":
+		injection := guardrails.PromptInjectionConfig{Threshold: cfg.Threshold}
+		if cfg.Classifier.URL != "" {
+			classifier, classifierErr := guardrails.NewModelClassifier(guardrails.ModelClassifierConfig{
+				URL:     cfg.Classifier.URL,
+				APIKey:  cfg.Classifier.APIKey,
+				Model:   cfg.Classifier.Model,
+				Timeout: cfg.Classifier.Timeout,
+			})
+			if classifierErr != nil {
+				return nil, classifierErr
+			}
+			injection.Classifier = classifier
+		}
+		g.Check = guardrails.NewPromptInjectionCheck(injection)
+	case "moderation":
+		g.Check, err = guardrails.NewModerationCheck(guardrails.ModerationConfig{
+			URL:        cfg.Moderation.URL,
+			APIKey:     cfg.Moderation.APIKey,
+			Model:      cfg.Moderation.Model,
+			Timeout:    cfg.Moderation.Timeout,
+			FailClosed: cfg.Moderation.FailClosed,
+		})
+	default:
+		return nil, fmt.Errorf("unknown type %q", cfg.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
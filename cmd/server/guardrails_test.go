@@ -0,0 +1,32 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+func TestBuildGuardrailOptions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	opts, err := buildGuardrailOptions(nil, logger)
+	if err != nil || len(opts) != 0 {
+		t.Fatalf("no guardrails = %d options, %v", len(opts), err)
+	}
+
+	opts, err = buildGuardrailOptions([]config.GuardrailConfig{
+		{Name: "pii", Type: "pii", Mode: "both", Action: "redact", DefaultOn: true},
+		{Name: "injection", Type: "prompt_injection", Action: "block"},
+	}, logger)
+	if err != nil || len(opts) != 1 {
+		t.Fatalf("buildGuardrailOptions = %d options, %v", len(opts), err)
+	}
+
+	if _, err := buildGuardrailOptions([]config.GuardrailConfig{
+		{Name: "pii", Type: "pii", Action: "redact", Entities: []string{"passport"}},
+	}, logger); err == nil {
+		t.Fatal("expected error for unknown pii entity")
+	}
+}
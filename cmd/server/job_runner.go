@@ -13,8 +13,17 @@ type jobRunner interface {
 	Stop()
 }
 
-func startJobRunner(cfg *config.Config, store auth.Store, logger *slog.Logger, newRunner func(*auth.JobRunnerConfig) jobRunner) jobRunner {
-	if cfg == nil || !cfg.Governance.Enabled || store == nil {
+// startJobRunner starts the budget reset and key rotation jobs when
+// governance is enabled, along with any extra jobs. When isLeader is set
+// the jobs run only while it returns true.
+func startJobRunner(cfg *config.Config, store auth.Store, logger *slog.Logger, isLeader func() bool, newRunner func(*auth.JobRunnerConfig) jobRunner, jobs ...auth.Job) jobRunner {
+	if cfg == nil {
+		return nil
+	}
+	if !cfg.Governance.Enabled {
+		store = nil
+	}
+	if store == nil && len(jobs) == 0 {
 		return nil
 	}
 	if logger == nil {
@@ -29,6 +38,8 @@ func startJobRunner(cfg *config.Config, store auth.Store, logger *slog.Logger, n
 		Store:    store,
 		Logger:   logger,
 		Interval: time.Hour,
+		Jobs:     jobs,
+		IsLeader: isLeader,
 	})
 	if runner == nil {
 		return nil
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"io"
 	"log/slog"
 	"testing"
@@ -38,11 +39,13 @@ func TestStartJobRunner_EnabledStarts(t *testing.T) {
 		return runner
 	}
 
-	job := startJobRunner(cfg, store, logger, newRunner)
+	isLeader := func() bool { return true }
+	job := startJobRunner(cfg, store, logger, isLeader, newRunner)
 	require.Equal(t, runner, job)
 	require.NotNil(t, gotCfg)
 	require.Equal(t, store, gotCfg.Store)
 	require.NotNil(t, gotCfg.Logger)
+	require.NotNil(t, gotCfg.IsLeader)
 	require.True(t, runner.started)
 }
 
@@ -67,9 +70,28 @@ func TestStartJobRunner_DisabledOrNilSkips(t *testing.T) {
 				called = true
 				return &fakeJobRunner{}
 			}
-			job := startJobRunner(tc.cfg, tc.st, logger, newRunner)
+			job := startJobRunner(tc.cfg, tc.st, logger, nil, newRunner)
 			require.Nil(t, job)
 			require.False(t, called)
 		})
 	}
 }
+
+func TestStartJobRunner_ExtraJobsRunWithoutGovernance(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	job := auth.Job{Name: "memory_retention", Run: func(context.Context) error { return nil }}
+
+	runner := &fakeJobRunner{}
+	var gotCfg *auth.JobRunnerConfig
+	newRunner := func(cfg *auth.JobRunnerConfig) jobRunner {
+		gotCfg = cfg
+		return runner
+	}
+
+	started := startJobRunner(&config.Config{}, auth.NewMemoryStore(), logger, nil, newRunner, job)
+	require.Equal(t, runner, started)
+	require.True(t, runner.started)
+	require.Nil(t, gotCfg.Store, "governance jobs stay off")
+	require.Len(t, gotCfg.Jobs, 1)
+	require.Equal(t, "memory_retention", gotCfg.Jobs[0].Name)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+// attachKeyCache wraps store with the API key cache when enabled. With
+// distributed invalidation the Redis subscription must be live before the
+// gateway serves traffic, so connection failures are fatal.
+func attachKeyCache(ctx context.Context, cfg *config.Config, store auth.Store, logger *slog.Logger) (auth.Store, error) {
+	kc := cfg.Auth.KeyCache
+	if !cfg.Auth.Enabled || !kc.Enabled || store == nil {
+		return store, nil
+	}
+
+	cacheCfg := auth.KeyCacheConfig{
+		Size:   kc.Size,
+		TTL:    kc.TTL,
+		Logger: logger,
+	}
+	if kc.Distributed {
+		redisClient, _, err := newRedisUniversalClient(cfg.Cache.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Redis for key cache invalidation: %w", err)
+		}
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		if err := redisClient.Ping(pingCtx).Err(); err != nil {
+			return nil, fmt.Errorf("failed to connect to Redis for key cache invalidation: %w", err)
+		}
+		cacheCfg.Invalidator = auth.NewRedisKeyInvalidator(redisClient, "")
+	}
+
+	cached, err := auth.NewCachedStore(store, cacheCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize key cache: %w", err)
+	}
+	logger.Info("API key cache enabled", "size", kc.Size, "ttl", kc.TTL, "distributed", kc.Distributed)
+	return cached, nil
+}
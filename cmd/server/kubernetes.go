@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/k8s"
+)
+
+// kubernetesMode is the running Kubernetes integration. isLeader is nil
+// when leader election is disabled, so every replica runs singleton jobs.
+type kubernetesMode struct {
+	isLeader func() bool
+	stop     func()
+}
+
+// startKubernetes polls the config file and the watch paths for changes,
+// which mounted ConfigMaps and Secrets receive as symlink swaps that
+// fsnotify misses, and joins leader election.
+func startKubernetes(ctx context.Context, cfg *config.Config, cfgManager *config.Manager, reloader *clientReloader, logger *slog.Logger) (*kubernetesMode, error) {
+	kc := cfg.Kubernetes
+	configPath := cfgManager.Status().Path
+	err := k8s.WatchFiles(ctx, []string{configPath}, kc.WatchInterval, logger, func() {
+		if err := cfgManager.Reload(); err != nil {
+			logger.Error("failed to reload config, keeping current", "error", err)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("watch config file: %w", err)
+	}
+	if len(kc.WatchPaths) > 0 {
+		err := k8s.WatchFiles(ctx, kc.WatchPaths, kc.WatchInterval, logger, func() {
+			logger.Info("watched files changed, rebuilding client")
+			if err := reloader.Rebuild(ctx); err != nil {
+				logger.Error("failed to rebuild llmux client", "error", err)
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("watch kubernetes.watch_paths: %w", err)
+		}
+	}
+	logger.Info("kubernetes mode enabled", "watch_paths", kc.WatchPaths, "watch_interval", kc.WatchInterval)
+
+	mode := &kubernetesMode{stop: func() {}}
+	le := kc.LeaderElection
+	if !le.Enabled {
+		return mode, nil
+	}
+
+	clientCfg, err := k8s.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("leader election: %w", err)
+	}
+	client, err := k8s.NewClient(clientCfg)
+	if err != nil {
+		return nil, fmt.Errorf("leader election: %w", err)
+	}
+	identity := le.Identity
+	if identity == "" {
+		identity = os.Getenv("POD_NAME")
+	}
+	if identity == "" {
+		if identity, err = os.Hostname(); err != nil {
+			return nil, fmt.Errorf("leader election identity: %w", err)
+		}
+	}
+	elector, err := k8s.NewLeaderElector(client, k8s.LeaderElectionConfig{
+		LeaseName:     le.LeaseName,
+		Namespace:     le.Namespace,
+		Identity:      identity,
+		LeaseDuration: le.LeaseDuration,
+		RetryPeriod:   le.RetryPeriod,
+	}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("leader election: %w", err)
+	}
+
+	electCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		elector.Run(electCtx)
+	}()
+	mode.isLeader = elector.IsLeader
+	// stop releases the lease so another replica takes over without
+	// waiting for it to expire.
+	mode.stop = func() {
+		cancel()
+		<-done
+	}
+	logger.Info("leader election started", "lease", le.LeaseName, "identity", identity)
+	return mode, nil
+}
@@ -14,6 +14,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/goccy/go-json"
 	"github.com/redis/go-redis/v9"
 
 	llmux "github.com/blueberrycongee/llmux"
@@ -22,11 +23,16 @@ import (
 	"github.com/blueberrycongee/llmux/internal/config"
 	"github.com/blueberrycongee/llmux/internal/healthcheck"
 	"github.com/blueberrycongee/llmux/internal/mcp"
+	"github.com/blueberrycongee/llmux/internal/metrics"
 	"github.com/blueberrycongee/llmux/internal/observability"
+	"github.com/blueberrycongee/llmux/internal/provenance"
 	"github.com/blueberrycongee/llmux/internal/resilience"
 	"github.com/blueberrycongee/llmux/internal/secret"
 	"github.com/blueberrycongee/llmux/internal/secret/env"
+	"github.com/blueberrycongee/llmux/internal/secret/file"
 	"github.com/blueberrycongee/llmux/internal/secret/vault"
+	"github.com/blueberrycongee/llmux/internal/tokenizer"
+	"github.com/blueberrycongee/llmux/pkg/pricing"
 	"github.com/blueberrycongee/llmux/routers"
 )
 
@@ -42,8 +48,35 @@ func main() {
 
 func run() error {
 	configPath := flag.String("config", "config/config.example.yaml", "path to configuration file")
+	migrateOnly := flag.Bool("migrate-only", false, "apply database migrations and exit")
+	validateOnly := flag.Bool("validate-config", false, "validate the configuration, probe its providers and exit")
+	replayIDs := flag.String("replay", "", "comma-separated request IDs to replay through the running gateway (admin key in "+replayAPIKeyEnv+")")
+	replayModel := flag.String("replay-model", "", "model replacing the recorded one in --replay")
+	replayDeployment := flag.String("replay-deployment", "", "deployment every --replay request is sent to")
+	replayURL := flag.String("replay-url", "", "gateway URL for --replay (default: the configured admin or data port on localhost)")
 	flag.Parse()
 
+	if *validateOnly {
+		// The report goes to stdout; keep logs out of it.
+		return runValidateConfig(context.Background(), *configPath, slog.New(slog.NewJSONHandler(os.Stderr, nil)), os.Stdout)
+	}
+	if *replayIDs != "" {
+		baseURL := *replayURL
+		if baseURL == "" {
+			var err error
+			if baseURL, err = replayGatewayURL(*configPath); err != nil {
+				return err
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+		defer cancel()
+		return runReplay(ctx, http.DefaultClient, baseURL, os.Getenv(replayAPIKeyEnv), api.ReplayRequest{
+			RequestIDs:   splitRequestIDs(*replayIDs),
+			Model:        *replayModel,
+			DeploymentID: *replayDeployment,
+		}, os.Stdout)
+	}
+
 	// Initialize structured logger
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
@@ -62,6 +95,7 @@ func run() error {
 
 	// Register 'env' provider
 	secretManager.Register("env", env.New())
+	secretManager.Register("file", file.New())
 
 	// Load configuration
 	cfgManager, err := config.NewManager(*configPath, logger)
@@ -74,40 +108,17 @@ func run() error {
 	for _, w := range cfg.Warnings() {
 		logger.Warn(w.Message, "code", w.Code)
 	}
-
-	// Register 'vault' provider if configured
-	var vConfig vault.Config
-	if cfg.Vault.Enabled {
-		vConfig = vault.Config{
-			Address:    cfg.Vault.Address,
-			AuthMethod: cfg.Vault.AuthMethod,
-			RoleID:     cfg.Vault.RoleID,
-			SecretID:   cfg.Vault.SecretID,
-			CACert:     cfg.Vault.CACert,
-			ClientCert: cfg.Vault.ClientCert,
-			ClientKey:  cfg.Vault.ClientKey,
-		}
-	} else if os.Getenv("VAULT_ADDR") != "" {
-		// Backward compatibility: Construct from Env
-		vConfig = vault.Config{
-			Address:    os.Getenv("VAULT_ADDR"),
-			AuthMethod: "approle", // Default for env var legacy
-			RoleID:     os.Getenv("VAULT_ROLE_ID"),
-			SecretID:   os.Getenv("VAULT_SECRET_ID"),
-		}
+	if *migrateOnly {
+		return runMigrateOnly(cfg, logger)
 	}
 
-	if vConfig.Address != "" {
-		logger.Info("initializing vault secret provider", "addr", vConfig.Address, "auth_method", vConfig.AuthMethod)
-		vProvider, vErr := vault.New(vConfig)
-		if vErr != nil {
-			return fmt.Errorf("failed to initialize vault provider: %w", vErr)
+	if err := registerSecretProviders(cfg, secretManager, logger); err != nil {
+		return err
+	}
+	for prefix, path := range cfg.Tokenizer.SentencePiece {
+		if err := tokenizer.LoadSentencePiece(prefix, path); err != nil {
+			return fmt.Errorf("tokenizer.sentencepiece[%s]: %w", prefix, err)
 		}
-		// Wrap with cache (TTL 5 minutes)
-		cachedVault := secret.NewCachedProvider(vProvider, 5*time.Minute)
-		secretManager.Register("vault", cachedVault)
-	} else {
-		logger.Info("vault provider disabled")
 	}
 
 	// Initialize observability manager
@@ -132,13 +143,22 @@ func run() error {
 	if len(obsCfg.EnabledCallbacks) > 0 {
 		logger.Info("observability callbacks enabled", "callbacks", obsCfg.EnabledCallbacks)
 	}
+	if anomalyLogger := buildAnomalyLogger(cfg.Logging, os.Stdout); anomalyLogger != nil {
+		obsMgr.CallbackManager().Register(anomalyLogger)
+		logger.Info("anomaly logging enabled", "level", cfg.Logging.Anomalies.Level)
+	}
 
 	// Start config watcher
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// One pricing registry for the process, so runtime price overrides and
+	// remotely refreshed prices survive config reloads.
+	pricingRegistry := startPricing(ctx, cfg, logger)
+
 	// Build llmux.Client options from config
 	opts := buildClientOptions(cfg, logger, secretManager, obsMgr)
+	opts = append(opts, llmux.WithPricingRegistry(pricingRegistry))
 
 	// Create llmux.Client
 	client, err := llmux.New(opts...)
@@ -150,8 +170,12 @@ func run() error {
 
 	reloader := newClientReloader(logger, clientSwapper, func(nextCfg *config.Config) (*llmux.Client, error) {
 		nextOpts := buildClientOptions(nextCfg, logger, secretManager, obsMgr)
+		nextOpts = append(nextOpts, llmux.WithPricingRegistry(pricingRegistry))
 		return llmux.New(nextOpts...)
 	})
+	reloader.enablePluginReload(cfg, func(client *llmux.Client, prev, next []config.PluginConfig) error {
+		return replaceConfigPlugins(client, prev, next, logger)
+	})
 	cfgManager.OnChange(reloader.Reload)
 	cfgManager.OnChange(func(nextCfg *config.Config) {
 		for _, w := range nextCfg.Warnings() {
@@ -159,7 +183,15 @@ func run() error {
 		}
 	})
 
-	if watchErr := cfgManager.Watch(ctx); watchErr != nil {
+	var isLeader func() bool
+	if cfg.Kubernetes.Enabled {
+		kube, err := startKubernetes(ctx, cfg, cfgManager, reloader, logger)
+		if err != nil {
+			return err
+		}
+		defer kube.stop()
+		isLeader = kube.isLeader
+	} else if watchErr := cfgManager.Watch(ctx); watchErr != nil {
 		logger.Warn("config hot-reload disabled", "error", watchErr)
 	}
 
@@ -169,6 +201,7 @@ func run() error {
 			Interval:       cfg.HealthCheck.Interval,
 			Timeout:        cfg.HealthCheck.Timeout,
 			CooldownPeriod: cfg.Routing.CooldownPeriod,
+			IsLeader:       isLeader,
 		}
 		prober := healthcheck.NewProber(proberCfg, swapperClientProvider{swapper: clientSwapper}, logger)
 		prober.Start(ctx)
@@ -199,8 +232,30 @@ func run() error {
 		}
 	}()
 
+	if cfg.ConfigSource.Source == config.ConfigSourceDB {
+		if err := useDBConfigSource(ctx, cfgManager, authStore, logger); err != nil {
+			return err
+		}
+		// The stored config rebuilt the client through the reloader.
+		cfg = cfgManager.Get()
+		client = clientSwapper.Current()
+	}
+
+	if err := runPreflight(ctx, cfg, client, authStore, logger, os.Stderr); err != nil {
+		return err
+	}
+
 	// Create AuditLogger
 	auditLogger := auth.NewAuditLogger(auditStore, true)
+	stopAuditExport, err := attachAuditExporters(ctx, cfg, auditLogger, logger)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		stopAuditExport(flushCtx)
+	}()
 
 	// Initialize Casbin RBAC
 	enforcer, err := initCasbin(cfg, logger)
@@ -215,7 +270,32 @@ func run() error {
 		}
 	}
 
-	runner := startJobRunner(cfg, authStore, logger, nil)
+	cachedStore, err := attachKeyCache(ctx, cfg, authStore, logger)
+	if err != nil {
+		return err
+	}
+	authStore = cachedStore
+	if err := enableStoredModelProviders(ctx, reloader, authStore, logger); err != nil {
+		return err
+	}
+
+	jobs := memoryRetentionJobs(cfg, clientSwapper, logger)
+	var payloadLogger *auth.PayloadLogger
+	if cfg.Governance.Enabled {
+		reportJobs, err := spendReportJobs(ctx, cfg, authStore, logger)
+		if err != nil {
+			logger.Error("failed to initialize spend reports, disabling", "error", err)
+		}
+		jobs = append(jobs, reportJobs...)
+
+		payloadLogger, err = buildPayloadLogger(ctx, cfg, authStore, logger)
+		if err != nil {
+			logger.Error("failed to initialize payload logging, disabling", "error", err)
+		} else if payloadLogger != nil {
+			jobs = append(jobs, payloadLogger.PayloadRetentionJob())
+		}
+	}
+	runner := startJobRunner(cfg, authStore, logger, isLeader, nil, jobs...)
 	if runner != nil {
 		defer runner.Stop()
 	}
@@ -252,7 +332,7 @@ func run() error {
 	var mcpManager mcp.Manager
 	if cfg.MCP.Enabled {
 		mcpCfg := mcp.FromConfig(cfg.MCP)
-		manager, mcpErr := mcp.NewManager(ctx, mcpCfg, logger)
+		manager, mcpErr := mcp.NewManager(ctx, mcpCfg, logger, mcp.WithSecretResolver(secretManager))
 		if mcpErr != nil {
 			return fmt.Errorf("failed to initialize MCP manager: %w", mcpErr)
 		}
@@ -264,6 +344,24 @@ func run() error {
 		}()
 	}
 
+	// Pre-aggregated usage buckets backing the dashboard time-series API
+	usageTimeSeries := metrics.NewTimeSeries(metrics.DefaultTimeSeriesConfig())
+
+	var responseSigner *provenance.Signer
+	if cfg.ResponseSigning.Enabled {
+		responseSigner, err = provenance.LoadSigner(cfg.ResponseSigning.KeyFile, cfg.ResponseSigning.KeyID)
+		if err != nil {
+			return fmt.Errorf("failed to load response signing key: %w", err)
+		}
+		logger.Info("response signing enabled", "key_id", cfg.ResponseSigning.KeyID)
+	}
+
+	// Emergency blocks managed through /control/killswitch
+	killSwitch, err := buildKillSwitch(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+
 	// Initialize API handler using ClientHandler (wraps llmux.Client)
 	// Now with Store integration for usage logging and budget tracking
 	handlerCfg := &api.ClientHandlerConfig{
@@ -271,20 +369,50 @@ func run() error {
 		MCPManager:    mcpManager,
 		Observability: obsMgr,
 		Governance:    governanceEngine,
+		TimeSeries:    usageTimeSeries,
+		Signer:        responseSigner,
+		KillSwitch:    killSwitch,
+		PayloadLogger: payloadLogger,
+
+		SSEHeartbeatInterval: cfg.Stream.HeartbeatInterval,
+		StreamBuffer:         buildStreamBuffer(cfg, logger),
+		StreamResumeTTL:      cfg.Stream.Resume.TTL,
+
+		DisableStreamPassthrough: !cfg.Stream.Passthrough,
+
+		ReadinessRequiresProvider: cfg.Kubernetes.Enabled && cfg.Kubernetes.ReadinessRequiresProvider,
+	}
+	if handlerCfg.SSEHeartbeatInterval == 0 {
+		handlerCfg.SSEHeartbeatInterval = -1 // Disabled
 	}
 	handler := api.NewClientHandlerWithSwapper(clientSwapper, logger, handlerCfg)
 
 	// Initialize ManagementHandler for enterprise API endpoints
 	mgmtHandler := api.NewManagementHandler(authStore, auditStore, logger, clientSwapper, cfgManager, auditLogger)
+	mgmtHandler.SetUsageTimeSeries(usageTimeSeries)
+	mgmtHandler.SetResponseSigner(responseSigner)
+	mgmtHandler.SetKillSwitch(killSwitch)
+	mgmtHandler.SetGovernance(governanceEngine)
+	mgmtHandler.SetRequestTail(obsMgr.RequestTail())
+	mgmtHandler.SetModelReloader(reloader.Rebuild)
+	mgmtHandler.SetConfigValidator(newConfigValidator(secretManager, logger))
+	if payloadLogger != nil {
+		mgmtHandler.SetPayloadLogStore(payloadLogger.Store())
+	}
 
 	// Initialize Invitation endpoints (LiteLLM-compatible enterprise surface)
 	var invitationStore auth.InvitationLinkStore
-	if pg, ok := authStore.(*auth.PostgresStore); ok {
+	if pg, ok := auth.UnwrapStore(authStore).(*auth.PostgresStore); ok {
 		invitationStore = pg
 	} else {
 		invitationStore = auth.NewMemoryInvitationLinkStore()
 	}
 	invitationService := auth.NewInvitationService(invitationStore, authStore, logger)
+	invitationService.SetPolicy(auth.InvitationPolicy{
+		DefaultExpiry: cfg.Auth.Invitations.DefaultExpiry,
+		MaxExpiry:     cfg.Auth.Invitations.MaxExpiry,
+	})
+	invitationService.SetAuditLogger(auditLogger)
 	invitationHandler := api.NewInvitationHandler(invitationService, invitationStore, logger)
 
 	authHandler, err := api.NewAuthHandler(mapOIDCConfig(cfg.Auth.OIDC), sessionManager, syncer, logger)
@@ -303,16 +431,25 @@ func run() error {
 		if muxes.Admin != nil {
 			mcpHandler.RegisterRoutes(muxes.Admin)
 			logger.Info("MCP management endpoints registered",
-				"endpoints", []string{"/mcp/clients", "/mcp/clients/{id}", "/mcp/tools"},
+				"endpoints", []string{"/mcp/clients", "/mcp/clients/{id}", "/mcp/clients/test", "/mcp/tools", "/mcp/resources", "/mcp/prompts"},
 				"admin_port", cfg.Server.AdminPort,
 			)
 		} else {
 			logger.Warn("MCP management endpoints disabled (set server.admin_port to enable)",
-				"endpoints", []string{"/mcp/clients", "/mcp/clients/{id}", "/mcp/tools"},
+				"endpoints", []string{"/mcp/clients", "/mcp/clients/{id}", "/mcp/clients/test", "/mcp/tools", "/mcp/resources", "/mcp/prompts"},
 			)
 		}
 	}
 
+	if mcpManager != nil && cfg.MCP.Server.Enabled {
+		gateway := mcp.NewGatewayServer(mcpManager, logger, mcp.WithRequireAllowlist(cfg.MCP.Server.RequireAllowlist))
+		muxes.Data.Handle(mcp.GatewayPath, gateway)
+		logger.Info("MCP server endpoint registered",
+			"endpoint", mcp.GatewayPath,
+			"require_allowlist", cfg.MCP.Server.RequireAllowlist,
+		)
+	}
+
 	if muxes.Admin != nil {
 		logger.Info("management endpoints registered",
 			"endpoints", []string{"/key/*", "/team/*", "/user/*", "/organization/*", "/spend/*", "/audit/*"},
@@ -324,7 +461,7 @@ func run() error {
 		)
 	}
 
-	middleware, err := buildMiddlewareStack(cfg, authStore, logger, syncer, enforcer, sessionManager)
+	middleware, err := buildMiddlewareStack(cfg, authStore, logger, syncer, enforcer, sessionManager, auditLogger)
 	if err != nil {
 		return fmt.Errorf("failed to initialize middleware stack: %w", err)
 	}
@@ -335,7 +472,20 @@ func run() error {
 		}
 	}
 
-	dataHandler := middleware(muxes.Data)
+	tlsReloader, err := startServerTLS(ctx, cfg.Server.TLS, logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize TLS: %w", err)
+	}
+	if cfg.Server.TLS.Enabled {
+		next := middleware
+		requireClientCert := clientCertMiddleware(cfg.Server.TLS.ClientCertPaths)
+		middleware = func(h http.Handler) http.Handler {
+			return requireClientCert(next(h))
+		}
+	}
+
+	drain := &drainer{}
+	dataHandler := drain.Middleware(middleware(muxes.Data))
 
 	// Create data server
 	dataServer := &http.Server{
@@ -348,7 +498,7 @@ func run() error {
 
 	var adminServer *http.Server
 	if muxes.Admin != nil {
-		adminHandler := middleware(muxes.Admin)
+		adminHandler := drain.Middleware(middleware(muxes.Admin))
 		adminServer = &http.Server{
 			Addr:         fmt.Sprintf(":%d", cfg.Server.AdminPort),
 			Handler:      adminHandler,
@@ -361,15 +511,15 @@ func run() error {
 	// Start server(s) in goroutines
 	serverErr := make(chan error, 2)
 	go func() {
-		logger.Info("server listening", "port", cfg.Server.Port)
-		if err := dataServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Info("server listening", "port", cfg.Server.Port, "tls", tlsReloader != nil)
+		if err := serve(dataServer, tlsReloader); err != nil && err != http.ErrServerClosed {
 			serverErr <- err
 		}
 	}()
 	if adminServer != nil {
 		go func() {
-			logger.Info("admin server listening", "port", cfg.Server.AdminPort)
-			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Info("admin server listening", "port", cfg.Server.AdminPort, "tls", tlsReloader != nil)
+			if err := serve(adminServer, tlsReloader); err != nil && err != http.ErrServerClosed {
 				serverErr <- err
 			}
 		}()
@@ -386,16 +536,31 @@ func run() error {
 		return fmt.Errorf("server error: %w", err)
 	}
 
+	// Let in-flight requests and streams finish before closing listeners
+	requests, streams := drain.Active()
+	logger.Info("draining in-flight requests",
+		"requests", requests,
+		"streams", streams,
+		"drain_timeout", cfg.Server.DrainTimeout,
+	)
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), cfg.Server.DrainTimeout)
+	if requests, streams := drain.Drain(drainCtx); requests > 0 {
+		logger.Warn("drain timeout reached, closing active requests", "requests", requests, "streams", streams)
+	}
+	drainCancel()
+
 	// Graceful shutdown with timeout
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 60*time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
 
 	if err := dataServer.Shutdown(shutdownCtx); err != nil {
 		logger.Error("server shutdown error", "error", err)
+		_ = dataServer.Close()
 	}
 	if adminServer != nil {
 		if err := adminServer.Shutdown(shutdownCtx); err != nil {
 			logger.Error("admin server shutdown error", "error", err)
+			_ = adminServer.Close()
 		}
 	}
 
@@ -421,6 +586,15 @@ func hasCallback(callbacks []string, names ...string) bool {
 	return false
 }
 
+// speculativeVerifier returns the draft verifier selected by cfg: an LLM
+// judge, or the mean token logprob by default.
+func speculativeVerifier(cfg config.SpeculativeVerifierConfig) llmux.DraftVerifier {
+	if cfg.Type == "judge" {
+		return &llmux.JudgeVerifier{Model: cfg.JudgeModel, MinScore: cfg.MinScore}
+	}
+	return &llmux.LogprobVerifier{MinAvgLogprob: cfg.MinAvgLogprob}
+}
+
 // buildRoutingOptions converts routing-related config to llmux.Option slice.
 func buildRoutingOptions(cfg *config.Config) []llmux.Option {
 	opts := make([]llmux.Option, 0, 4)
@@ -432,10 +606,26 @@ func buildRoutingOptions(cfg *config.Config) []llmux.Option {
 		opts = append(opts, llmux.WithDefaultProvider(cfg.Routing.DefaultProvider))
 	}
 
+	if cfg.Routing.EnableTagFiltering {
+		opts = append(opts, llmux.WithTagFiltering(true))
+	}
+
+	if cfg.Routing.DecisionTraceSize > 0 {
+		opts = append(opts, llmux.WithRouterTracing(cfg.Routing.DecisionTraceSize))
+	}
+
 	if cfg.Routing.CooldownPeriod > 0 {
 		opts = append(opts, llmux.WithCooldown(cfg.Routing.CooldownPeriod))
 	}
 
+	if cfg.Routing.CooldownMaxPeriod > 0 {
+		opts = append(opts, llmux.WithCooldownBackoff(cfg.Routing.CooldownMaxPeriod))
+	}
+
+	if cfg.Routing.HalfOpenSuccesses > 0 {
+		opts = append(opts, llmux.WithHalfOpenSuccesses(cfg.Routing.HalfOpenSuccesses))
+	}
+
 	if cfg.Routing.EWMAAlpha > 0 {
 		opts = append(opts, llmux.WithEWMAAlpha(cfg.Routing.EWMAAlpha))
 	}
@@ -444,6 +634,29 @@ func buildRoutingOptions(cfg *config.Config) []llmux.Option {
 		opts = append(opts, llmux.WithTimeout(cfg.Server.WriteTimeout))
 	}
 
+	for model, timeout := range cfg.Routing.ModelTimeouts {
+		opts = append(opts, llmux.WithModelTimeout(model, timeout))
+	}
+
+	if cfg.Routing.AdmissionQueueSize > 0 {
+		opts = append(opts, llmux.WithAdmissionQueue(cfg.Routing.AdmissionQueueSize, cfg.Routing.AdmissionQueueTimeout))
+	}
+
+	cb := cfg.Routing.CircuitBreaker
+	opts = append(opts, llmux.WithCircuitBreaker(llmux.CircuitBreakerConfig{
+		Disabled:         !cb.Enabled,
+		FailureThreshold: cb.FailureThreshold,
+		SuccessThreshold: cb.SuccessThreshold,
+		OpenTimeout:      cb.OpenTimeout,
+	}))
+
+	if spec := cfg.Routing.Speculative; spec.Enabled {
+		opts = append(opts, llmux.WithSpeculativeRouting(llmux.SpeculativeConfig{
+			Drafts:   spec.Drafts,
+			Verifier: speculativeVerifier(spec.Verifier),
+		}))
+	}
+
 	opts = append(opts,
 		llmux.WithRetry(cfg.Routing.RetryCount, cfg.Routing.RetryBackoff),
 		llmux.WithRetryMaxBackoff(cfg.Routing.RetryMaxBackoff),
@@ -454,6 +667,61 @@ func buildRoutingOptions(cfg *config.Config) []llmux.Option {
 	return opts
 }
 
+// redisStatsOptions mirrors the routing cooldown settings on the distributed
+// stats store, which decides cooldowns inside its Lua scripts.
+func redisStatsOptions(cfg config.RoutingConfig) []routers.RedisStatsOption {
+	var opts []routers.RedisStatsOption
+	if cfg.CooldownPeriod > 0 {
+		opts = append(opts, routers.WithCooldownPeriod(cfg.CooldownPeriod))
+	}
+	if cfg.CooldownMaxPeriod > 0 {
+		opts = append(opts, routers.WithCooldownMaxPeriod(cfg.CooldownMaxPeriod))
+	}
+	if cfg.HalfOpenSuccesses > 0 {
+		opts = append(opts, routers.WithHalfOpenSuccessThreshold(cfg.HalfOpenSuccesses))
+	}
+	return opts
+}
+
+// registerVaultProvider registers the 'vault' secret provider when Vault is
+// configured, either in cfg or through the legacy VAULT_* variables.
+func registerVaultProvider(cfg *config.Config, secretManager *secret.Manager, logger *slog.Logger) error {
+	var vConfig vault.Config
+	if cfg.Vault.Enabled {
+		vConfig = vault.Config{
+			Address:    cfg.Vault.Address,
+			AuthMethod: cfg.Vault.AuthMethod,
+			RoleID:     cfg.Vault.RoleID,
+			SecretID:   cfg.Vault.SecretID,
+			CACert:     cfg.Vault.CACert,
+			ClientCert: cfg.Vault.ClientCert,
+			ClientKey:  cfg.Vault.ClientKey,
+		}
+	} else if os.Getenv("VAULT_ADDR") != "" {
+		// Backward compatibility: Construct from Env
+		vConfig = vault.Config{
+			Address:    os.Getenv("VAULT_ADDR"),
+			AuthMethod: "approle", // Default for env var legacy
+			RoleID:     os.Getenv("VAULT_ROLE_ID"),
+			SecretID:   os.Getenv("VAULT_SECRET_ID"),
+		}
+	}
+
+	if vConfig.Address != "" {
+		logger.Info("initializing vault secret provider", "addr", vConfig.Address, "auth_method", vConfig.AuthMethod)
+		vProvider, vErr := vault.New(vConfig)
+		if vErr != nil {
+			return fmt.Errorf("failed to initialize vault provider: %w", vErr)
+		}
+		// Wrap with cache
+		cachedVault := secret.NewCachedProvider(vProvider, secretCacheTTL)
+		secretManager.Register("vault", cachedVault)
+	} else {
+		logger.Info("vault provider disabled")
+	}
+	return nil
+}
+
 // buildClientOptions converts config.Config to llmux.Option slice.
 func buildClientOptions(cfg *config.Config, logger *slog.Logger, secretManager *secret.Manager, obsMgr *observability.ObservabilityManager) []llmux.Option {
 	// Pre-allocate with estimated capacity
@@ -462,56 +730,89 @@ func buildClientOptions(cfg *config.Config, logger *slog.Logger, secretManager *
 	// Add logger
 	opts = append(opts, llmux.WithLogger(logger))
 
-	// Add providers from config
-	for _, provCfg := range cfg.Providers {
-		pCfg := llmux.ProviderConfig{
-			Name:                provCfg.Name,
-			Type:                provCfg.Type,
-			APIKey:              provCfg.APIKey,
-			BaseURL:             provCfg.BaseURL,
-			AllowPrivateBaseURL: provCfg.AllowPrivateBaseURL,
-			Models:              provCfg.Models,
-			Timeout:             provCfg.Timeout,
-			// MaxConcurrent is enforced by the client semaphore per deployment.
-			MaxConcurrent: provCfg.MaxConcurrent,
-			Headers:       provCfg.Headers,
-		}
-
-		// Check if APIKey is a secret URI (contains "://")
-		if strings.Contains(provCfg.APIKey, "://") {
-			pCfg.TokenSource = &SecretTokenSource{
-				mgr:  secretManager,
-				path: provCfg.APIKey,
-			}
-		}
-
-		opts = append(opts, llmux.WithProvider(pCfg))
-	}
+	opts = append(opts, buildProviderOptions(cfg, secretManager)...)
 
 	opts = append(opts, buildRoutingOptions(cfg)...)
 	if obsMgr != nil {
 		opts = append(opts, llmux.WithFallbackReporter(obsMgr.LogFallback))
 	}
 
-	// Set pricing file
-	if cfg.PricingFile != "" {
+	// Set pricing file; remote pricing is loaded by startPricing.
+	if cfg.PricingFile != "" && !pricing.IsRemote(cfg.PricingFile) {
 		opts = append(opts, llmux.WithPricingFile(cfg.PricingFile))
 	}
+	if fallback, ok := buildPricingFallback(cfg.PricingFallback); ok {
+		opts = append(opts, llmux.WithPricingFallback(fallback))
+	}
+	if cfg.MaxTokensPolicy != "" {
+		opts = append(opts, llmux.WithMaxTokensPolicy(llmux.MaxTokensPolicy(cfg.MaxTokensPolicy)))
+	}
 
 	// Stream recovery mode
 	if cfg.Stream.RecoveryMode != "" {
 		opts = append(opts, llmux.WithStreamRecoveryMode(mapStreamRecoveryMode(cfg.Stream.RecoveryMode)))
 	}
 	opts = append(opts, llmux.WithStreamRecoveryMaxAccumulatedBytes(cfg.Stream.MaxAccumulatedBytes))
+	if cfg.Stream.IdleTimeout > 0 {
+		opts = append(opts, llmux.WithStreamIdleTimeout(cfg.Stream.IdleTimeout))
+	}
 
-	// Initialize cache
-	cacheOpts, cacheErr := buildCacheOptions(&cfg.Cache, logger)
-	if cacheErr != nil {
+	// Initialize cache; never fall back to storing plaintext when encryption
+	// at rest is configured but the master key cannot be loaded.
+	keyring, keyErr := buildEncryptionKeyring(cfg.Encryption, secretManager)
+	if keyErr != nil {
+		logger.Error("failed to load encryption master key, disabling cache and memory", "error", keyErr)
+	} else if cacheOpts, cacheErr := buildCacheOptions(&cfg.Cache, keyring, logger); cacheErr != nil {
 		logger.Warn("failed to initialize cache, disabling", "error", cacheErr)
 	} else if len(cacheOpts) > 0 {
 		opts = append(opts, cacheOpts...)
 	}
 
+	if cfg.Cache.NegativeTTL > 0 {
+		opts = append(opts, llmux.WithNegativeCache(cfg.Cache.NegativeTTL))
+	}
+
+	if len(cfg.VirtualModels) > 0 {
+		if virtualModels, err := buildVirtualModels(cfg.VirtualModels); err != nil {
+			logger.Warn("failed to build virtual models, disabling", "error", err)
+		} else {
+			opts = append(opts, llmux.WithVirtualModels(virtualModels...))
+		}
+	}
+
+	if keyErr == nil {
+		if memoryOpts, memoryErr := buildSessionMemoryOptions(cfg, keyring, logger); memoryErr != nil {
+			logger.Warn("failed to initialize session memory, disabling", "error", memoryErr)
+		} else {
+			opts = append(opts, memoryOpts...)
+		}
+		if longTermOpts, longTermErr := buildLongTermMemoryOptions(cfg, keyring, logger); longTermErr != nil {
+			logger.Warn("failed to initialize long-term memory, disabling", "error", longTermErr)
+		} else {
+			opts = append(opts, longTermOpts...)
+		}
+	}
+	opts = append(opts, buildMemoryRetentionOptions(cfg)...)
+	if quotaOpts, quotaErr := buildMemoryQuotaOptions(cfg, logger); quotaErr != nil {
+		logger.Warn("failed to initialize memory quotas, disabling", "error", quotaErr)
+	} else {
+		opts = append(opts, quotaOpts...)
+	}
+	opts = append(opts, llmux.WithSandboxConfig(buildSandboxConfig(cfg.Sandbox)))
+	if cfg.StructuredOutput.Validate || cfg.StructuredOutput.Repair {
+		opts = append(opts, llmux.WithStructuredOutputValidation(llmux.StructuredOutputConfig{Repair: cfg.StructuredOutput.Repair}))
+	}
+	if guardrailOpts, guardrailErr := buildGuardrailOptions(cfg.Guardrails, logger); guardrailErr != nil {
+		logger.Error("failed to initialize guardrails, disabling", "error", guardrailErr)
+	} else {
+		opts = append(opts, guardrailOpts...)
+	}
+	if pluginOpts, pluginErr := buildPluginOptions(cfg.Plugins, logger); pluginErr != nil {
+		logger.Error("failed to initialize plugins, disabling", "error", pluginErr)
+	} else {
+		opts = append(opts, pluginOpts...)
+	}
+
 	// Initialize distributed routing
 	if cfg.Routing.Distributed {
 		if cfg.Cache.Redis.Addr != "" || len(cfg.Cache.Redis.ClusterAddrs) > 0 {
@@ -524,7 +825,7 @@ func buildClientOptions(cfg *config.Config, logger *slog.Logger, secretManager *
 				if err := redisClient.Ping(pingCtx).Err(); err != nil {
 					logger.Error("failed to connect to Redis for distributed routing", "error", err)
 				} else {
-					statsStore := routers.NewRedisStatsStore(redisClient)
+					statsStore := routers.NewRedisStatsStore(redisClient, redisStatsOptions(cfg.Routing)...)
 					opts = append(opts, llmux.WithStatsStore(statsStore))
 					rrStore := routers.NewRedisRoundRobinStore(redisClient)
 					opts = append(opts, llmux.WithRoundRobinStore(rrStore))
@@ -578,12 +879,91 @@ func buildClientOptions(cfg *config.Config, logger *slog.Logger, secretManager *
 			WindowSize:  windowSize,
 			KeyStrategy: mapKeyStrategy(cfg.RateLimit.KeyStrategy),
 			FailOpen:    cfg.RateLimit.FailOpen,
+
+			EndUserRPMLimit: cfg.RateLimit.EndUserRequestsPerMinute,
+			EndUserTPMLimit: cfg.RateLimit.EndUserTokensPerMinute,
+
+			PriorityReservations: mapPriorityReservations(cfg.RateLimit.PriorityReservations),
 		}))
 	}
 
 	return opts
 }
 
+// buildProviderOptions converts the providers of cfg to llmux options.
+func buildProviderOptions(cfg *config.Config, secretManager *secret.Manager) []llmux.Option {
+	opts := make([]llmux.Option, 0, len(cfg.Providers))
+	for _, provCfg := range cfg.Providers {
+		pCfg := llmux.ProviderConfig{
+			Name:                provCfg.Name,
+			Type:                provCfg.Type,
+			APIKey:              provCfg.APIKey,
+			BaseURL:             provCfg.BaseURL,
+			AllowPrivateBaseURL: provCfg.AllowPrivateBaseURL,
+			Models:              provCfg.Models,
+			Timeout:             provCfg.Timeout,
+			// MaxConcurrent is enforced by the client semaphore per deployment.
+			MaxConcurrent: provCfg.MaxConcurrent,
+			Headers:       provCfg.Headers,
+			APIVersion:    provCfg.APIVersion,
+			RPM:           provCfg.RPM,
+			TPM:           provCfg.TPM,
+			Tags:          provCfg.Tags,
+			// Each provider gets its own transport with these settings.
+			ProxyURL:           provCfg.ProxyURL,
+			CAFile:             provCfg.CAFile,
+			InsecureSkipVerify: provCfg.InsecureSkipVerify,
+			Transport: llmux.TransportConfig{
+				MaxIdleConns:        provCfg.Transport.MaxIdleConns,
+				MaxIdleConnsPerHost: provCfg.Transport.MaxIdleConnsPerHost,
+				MaxConnsPerHost:     provCfg.Transport.MaxConnsPerHost,
+				IdleConnTimeout:     provCfg.Transport.IdleConnTimeout,
+				DialTimeout:         provCfg.Transport.DialTimeout,
+				KeepAlive:           provCfg.Transport.KeepAlive,
+				DisableHTTP2:        provCfg.Transport.DisableHTTP2,
+			},
+		}
+		if len(provCfg.ModelLimits) > 0 {
+			pCfg.ModelLimits = make(map[string]llmux.RateLimits, len(provCfg.ModelLimits))
+			for model, limits := range provCfg.ModelLimits {
+				pCfg.ModelLimits[model] = llmux.RateLimits{RPM: limits.RPM, TPM: limits.TPM}
+			}
+		}
+		if len(provCfg.Pricing) > 0 {
+			pCfg.ModelPrices = make(map[string]pricing.ModelPrice, len(provCfg.Pricing))
+			for model, price := range provCfg.Pricing {
+				pCfg.ModelPrices[model] = buildModelPrice(provCfg.Name, price)
+			}
+		}
+
+		// Check if APIKey is a secret URI (contains "://")
+		if strings.Contains(provCfg.APIKey, "://") {
+			pCfg.TokenSource = &SecretTokenSource{
+				mgr:  secretManager,
+				path: provCfg.APIKey,
+			}
+		}
+		if provCfg.SecondaryAPIKey != "" {
+			pCfg.SecondaryAPIKey = provCfg.SecondaryAPIKey
+			if strings.Contains(provCfg.SecondaryAPIKey, "://") {
+				pCfg.SecondaryTokenSource = &SecretTokenSource{
+					mgr:  secretManager,
+					path: provCfg.SecondaryAPIKey,
+				}
+			}
+			pCfg.Rollover = llmux.RolloverSchedule{
+				SecondaryWeight:   provCfg.Rollover.SecondaryWeight,
+				Start:             provCfg.Rollover.Start,
+				End:               provCfg.Rollover.End,
+				RollbackErrorRate: provCfg.Rollover.RollbackErrorRate,
+			}
+		}
+
+		opts = append(opts, llmux.WithProvider(pCfg))
+	}
+	return opts
+}
+
 // mapStreamRecoveryMode converts config recovery mode to llmux.StreamRecoveryMode.
 func mapStreamRecoveryMode(mode string) llmux.StreamRecoveryMode {
 	switch mode {
@@ -626,6 +1006,105 @@ func newRedisUniversalClient(cfg config.RedisCacheConfig) (redis.UniversalClient
 	return redis.NewUniversalClient(options), isCluster, nil
 }
 
+// startPricing returns the process-wide pricing registry. When pricing_file
+// is an https URL it is fetched now and refreshed in the background until ctx
+// is done; a failed fetch leaves the built-in prices in place.
+func startPricing(ctx context.Context, cfg *config.Config, logger *slog.Logger) *pricing.Registry {
+	reg := pricing.NewRegistry()
+	if !pricing.IsRemote(cfg.PricingFile) {
+		return reg
+	}
+	source := pricing.NewRemoteSource(cfg.PricingFile, nil)
+	if _, err := source.Refresh(ctx, reg); err != nil {
+		logger.Warn("remote pricing unavailable, using built-in prices until the next refresh",
+			"url", cfg.PricingFile, "error", err)
+	} else {
+		logger.Info("remote pricing loaded", "url", cfg.PricingFile)
+	}
+	go source.Run(ctx, reg, cfg.PricingRefresh, logger)
+	return reg
+}
+
+// buildModelPrice maps a provider's per-model price config.
+func buildModelPrice(providerName string, cfg config.ModelPriceConfig) pricing.ModelPrice {
+	price := pricing.ModelPrice{
+		Provider:                    providerName,
+		InputCostPerToken:           cfg.InputCostPerToken,
+		OutputCostPerToken:          cfg.OutputCostPerToken,
+		CostPerRequest:              cfg.CostPerRequest,
+		CostPerImage:                cfg.CostPerImage,
+		CostPerAudioSecond:          cfg.CostPerAudioSecond,
+		OutputCostPerReasoningToken: cfg.OutputCostPerReasoningToken,
+		MaxOutputTokens:             cfg.MaxOutputTokens,
+	}
+	for _, tier := range cfg.Tiers {
+		price.Tiers = append(price.Tiers, pricing.PriceTier{
+			AboveInputTokens:   tier.AboveInputTokens,
+			InputCostPerToken:  tier.InputCostPerToken,
+			OutputCostPerToken: tier.OutputCostPerToken,
+		})
+	}
+	return price
+}
+
+// buildVirtualModels maps the virtual model presets.
+func buildVirtualModels(cfgs []config.VirtualModelConfig) ([]llmux.VirtualModel, error) {
+	models := make([]llmux.VirtualModel, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		vm := llmux.VirtualModel{
+			Name:         cfg.Name,
+			Model:        cfg.Model,
+			SystemPrompt: cfg.SystemPrompt,
+			Temperature:  cfg.Temperature,
+			TopP:         cfg.TopP,
+			MaxTokens:    cfg.MaxTokens,
+			Stop:         cfg.Stop,
+		}
+		for _, tool := range cfg.Tools {
+			fn := llmux.ToolFunction{Name: tool.Name, Description: tool.Description}
+			if tool.Parameters != nil {
+				params, err := json.Marshal(tool.Parameters)
+				if err != nil {
+					return nil, fmt.Errorf("virtual model %s: tool %s parameters: %w", cfg.Name, tool.Name, err)
+				}
+				fn.Parameters = params
+			}
+			vm.Tools = append(vm.Tools, llmux.Tool{Type: "function", Function: fn})
+		}
+		switch cfg.ToolChoice {
+		case "":
+		case "auto", "none", "required":
+			vm.ToolChoice, _ = json.Marshal(cfg.ToolChoice)
+		default:
+			vm.ToolChoice, _ = json.Marshal(map[string]any{
+				"type":     "function",
+				"function": map[string]string{"name": cfg.ToolChoice},
+			})
+		}
+		models = append(models, vm)
+	}
+	return models, nil
+}
+
+// buildPricingFallback maps the pricing fallback config; ok is false when
+// the default (reject unpriced models) applies.
+func buildPricingFallback(cfg config.PricingFallbackConfig) (llmux.PricingFallback, bool) {
+	if cfg.Policy == "" || cfg.Policy == string(llmux.PricingPolicyFail) {
+		return llmux.PricingFallback{}, false
+	}
+	fallback := llmux.PricingFallback{Policy: llmux.PricingPolicy(cfg.Policy)}
+	if len(cfg.DefaultRates) > 0 {
+		fallback.DefaultRates = make(map[string]llmux.TokenRate, len(cfg.DefaultRates))
+		for provider, rate := range cfg.DefaultRates {
+			fallback.DefaultRates[provider] = llmux.TokenRate{
+				InputCostPerToken:  rate.InputCostPerToken,
+				OutputCostPerToken: rate.OutputCostPerToken,
+			}
+		}
+	}
+	return fallback, true
+}
+
 // mapKeyStrategy converts config key strategy string to llmux.RateLimitKeyStrategy.
 func mapKeyStrategy(strategy string) llmux.RateLimitKeyStrategy {
 	switch strategy {
@@ -637,11 +1116,27 @@ func mapKeyStrategy(strategy string) llmux.RateLimitKeyStrategy {
 		return llmux.RateLimitKeyByModel
 	case "api_key_model":
 		return llmux.RateLimitKeyByAPIKeyAndModel
+	case "end_user":
+		return llmux.RateLimitKeyByEndUser
 	default:
 		return llmux.RateLimitKeyByAPIKey
 	}
 }
 
+// mapPriorityReservations converts config priority names to llmux.Priority.
+func mapPriorityReservations(reservations map[string]float64) map[llmux.Priority]float64 {
+	if len(reservations) == 0 {
+		return nil
+	}
+	out := make(map[llmux.Priority]float64, len(reservations))
+	for name, share := range reservations {
+		if priority, ok := llmux.ParsePriority(name); ok {
+			out[priority] = share
+		}
+	}
+	return out
+}
+
 // mapRoutingStrategy converts config strategy string to llmux.Strategy.
 func mapRoutingStrategy(strategy string) llmux.Strategy {
 	switch strategy {
@@ -658,6 +1153,10 @@ func mapRoutingStrategy(strategy string) llmux.Strategy {
 	case "lowest-cost", "cost":
 		return llmux.StrategyLowestCost
 	default:
+		// Remaining built-ins and strategies added with routers.Register.
+		if routers.IsValidStrategy(strategy) {
+			return llmux.Strategy(strategy)
+		}
 		return llmux.StrategyShuffle
 	}
 }
@@ -91,6 +91,56 @@ func TestManagementAuthzMiddleware_NonManagementKey_Denied(t *testing.T) {
 	}
 }
 
+func TestManagementAuthzMiddleware_DataPlaneKey_DeniedDespitePolicy(t *testing.T) {
+	enforcer, err := auth.NewCasbinEnforcer(nil)
+	if err != nil {
+		t.Fatalf("NewCasbinEnforcer() error = %v", err)
+	}
+	// A permissive policy must not lift a data-plane key onto admin routes.
+	if _, err := enforcer.AddPolicy(auth.RoleSub(string(auth.KeyTypeLLMAPI)), "*", "*"); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+
+	cfg := &config.Config{Auth: config.AuthConfig{Enabled: true}}
+	h := managementAuthzMiddleware(cfg, enforcer)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	authCtx := &auth.AuthContext{APIKey: &auth.APIKey{ID: "data-key", KeyType: auth.KeyTypeLLMAPI}}
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/key/generate", nil)
+	req = req.WithContext(context.WithValue(req.Context(), auth.AuthContextKey, authCtx))
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestManagementAuthzMiddleware_ProvenanceRequiresAdminRole(t *testing.T) {
+	cfg := &config.Config{Auth: config.AuthConfig{Enabled: true}}
+	h := managementAuthzMiddleware(cfg, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tc := range []struct {
+		method, path string
+	}{
+		{http.MethodPost, "/provenance/verify"},
+		{http.MethodGet, "/provenance/keys"},
+	} {
+		authCtx := &auth.AuthContext{User: &auth.User{ID: "u1"}, UserRole: auth.UserRoleInternalUser}
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req = req.WithContext(context.WithValue(req.Context(), auth.AuthContextKey, authCtx))
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("%s %s: expected 403, got %d", tc.method, tc.path, rr.Code)
+		}
+	}
+}
+
 func TestManagementAuthzMiddleware_AuthDisabled_BootstrapToken_Allows(t *testing.T) {
 	cfg := &config.Config{Auth: config.AuthConfig{Enabled: false, BootstrapToken: "boot"}}
 	h := managementAuthzMiddleware(cfg, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/api"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/pkg/encryption"
+)
+
+// buildSessionMemoryOptions creates the session store selected by
+// memory.session.backend, encrypting persisted turns with keyring when set.
+// It returns nil when session memory is disabled.
+func buildSessionMemoryOptions(cfg *config.Config, keyring *encryption.Keyring, logger *slog.Logger) ([]llmux.Option, error) {
+	sessionCfg := cfg.Memory.Session
+	if !sessionCfg.Enabled {
+		return nil, nil
+	}
+
+	var store llmux.SessionStore
+	switch sessionCfg.Backend {
+	case "", "memory":
+		store = llmux.NewMemorySessionStore(sessionCfg.MaxTurns)
+	case "redis":
+		client, _, err := newRedisUniversalClient(cfg.Cache.Redis)
+		if err != nil {
+			return nil, err
+		}
+		pingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Ping(pingCtx).Err(); err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("connect to redis: %w", err)
+		}
+		prefix := ""
+		if cfg.Cache.Namespace != "" {
+			prefix = cfg.Cache.Namespace + ":session:"
+		}
+		store = llmux.NewRedisSessionStore(client, llmux.RedisSessionConfig{
+			Prefix:   prefix,
+			MaxTurns: sessionCfg.MaxTurns,
+			TTL:      sessionCfg.TTL,
+			Keyring:  keyring,
+		})
+	case "postgres":
+		db, err := auth.OpenPostgres(buildPostgresConfig(cfg.Database))
+		if err != nil {
+			return nil, err
+		}
+		store = llmux.NewPostgresSessionStore(db, sessionCfg.MaxTurns).WithKeyring(keyring)
+	default:
+		return nil, fmt.Errorf("unsupported session memory backend: %s", sessionCfg.Backend)
+	}
+
+	memory := llmux.NewSessionMemory(store, llmux.SessionWindow{
+		MaxTurns:  sessionCfg.MaxTurns,
+		MaxTokens: sessionCfg.MaxTokens,
+	})
+	logger.Info("session memory enabled", "backend", sessionCfg.Backend, "max_turns", sessionCfg.MaxTurns, "max_tokens", sessionCfg.MaxTokens, "ttl", sessionCfg.TTL)
+	return []llmux.Option{llmux.WithSessionMemory(memory)}, nil
+}
+
+// buildLongTermMemoryOptions creates the vector store selected by
+// memory.long_term.backend, creating its table or collection when missing
+// and encrypting stored text and metadata with keyring when set. It returns
+// nil when long-term memory is disabled.
+func buildLongTermMemoryOptions(cfg *config.Config, keyring *encryption.Keyring, logger *slog.Logger) ([]llmux.Option, error) {
+	longTermCfg := cfg.Memory.LongTerm
+	if !longTermCfg.Enabled {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var store llmux.VectorStore
+	switch longTermCfg.Backend {
+	case "", "memory":
+		store = llmux.NewMemoryVectorStore()
+	case "pgvector":
+		db, err := auth.OpenPostgres(buildPostgresConfig(cfg.Database))
+		if err != nil {
+			return nil, err
+		}
+		pg, err := llmux.NewPgVectorStore(db, llmux.PgVectorConfig{
+			Table:     longTermCfg.PgVector.Table,
+			Dimension: longTermCfg.Dimension,
+			Keyring:   keyring,
+		})
+		if err == nil {
+			err = pg.EnsureSchema(ctx)
+		}
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+		store = pg
+	case "qdrant":
+		qdrant, err := llmux.NewQdrantVectorStore(llmux.QdrantVectorConfig{
+			APIBase:    longTermCfg.Qdrant.APIBase,
+			APIKey:     longTermCfg.Qdrant.APIKey,
+			Collection: longTermCfg.Qdrant.Collection,
+			Dimension:  longTermCfg.Dimension,
+			Timeout:    longTermCfg.Qdrant.Timeout,
+			Keyring:    keyring,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := qdrant.EnsureCollection(ctx); err != nil {
+			return nil, err
+		}
+		store = qdrant
+	case "milvus":
+		milvus, err := llmux.NewMilvusVectorStore(llmux.MilvusVectorConfig{
+			Address:    longTermCfg.Milvus.Address,
+			Token:      longTermCfg.Milvus.Token,
+			Database:   longTermCfg.Milvus.Database,
+			Collection: longTermCfg.Milvus.Collection,
+			Dimension:  longTermCfg.Dimension,
+			Timeout:    longTermCfg.Milvus.Timeout,
+			Keyring:    keyring,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := milvus.EnsureCollection(ctx); err != nil {
+			return nil, err
+		}
+		store = milvus
+	default:
+		return nil, fmt.Errorf("unsupported long-term memory backend: %s", longTermCfg.Backend)
+	}
+
+	extractors := make(map[string]llmux.Extractor, len(longTermCfg.Ingestion.Extractors))
+	for contentType, command := range longTermCfg.Ingestion.Extractors {
+		extractors[contentType] = llmux.CommandExtractor{
+			Command: command,
+			Timeout: longTermCfg.Ingestion.ExtractorTimeout,
+		}
+	}
+
+	logger.Info("long-term memory enabled", "backend", longTermCfg.Backend, "embedding_model", longTermCfg.EmbeddingModel)
+	return []llmux.Option{llmux.WithLongTermMemory(llmux.LongTermMemoryConfig{
+		Store:          store,
+		EmbeddingModel: longTermCfg.EmbeddingModel,
+		Recall: llmux.MemoryRecallConfig{
+			TopK:     longTermCfg.TopK,
+			MinScore: longTermCfg.MinScore,
+		},
+		Chunking: llmux.ChunkConfig{
+			Strategy: longTermCfg.Ingestion.Chunking.Strategy,
+			Size:     longTermCfg.Ingestion.Chunking.Size,
+			Overlap:  longTermCfg.Ingestion.Chunking.Overlap,
+		},
+		Extractors: extractors,
+	})}, nil
+}
+
+// buildMemoryQuotaOptions creates the usage ledger selected by
+// memory.quotas.backend. It returns nil when memory quotas are disabled.
+func buildMemoryQuotaOptions(cfg *config.Config, logger *slog.Logger) ([]llmux.Option, error) {
+	quotasCfg := cfg.Memory.Quotas
+	if !quotasCfg.Enabled {
+		return nil, nil
+	}
+
+	var ledger llmux.MemoryQuotaLedger
+	switch quotasCfg.Backend {
+	case "", "memory":
+		ledger = llmux.NewMemoryQuotaLedger()
+	case "redis":
+		client, _, err := newRedisUniversalClient(cfg.Cache.Redis)
+		if err != nil {
+			return nil, err
+		}
+		pingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Ping(pingCtx).Err(); err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("connect to redis: %w", err)
+		}
+		prefix := ""
+		if cfg.Cache.Namespace != "" {
+			prefix = cfg.Cache.Namespace + ":memquota:"
+		}
+		ledger = llmux.NewRedisQuotaLedger(client, prefix)
+	default:
+		return nil, fmt.Errorf("unsupported memory quota backend: %s", quotasCfg.Backend)
+	}
+
+	logger.Info("memory quotas enabled", "backend", quotasCfg.Backend,
+		"max_sessions", quotasCfg.MaxSessions, "max_vectors", quotasCfg.MaxVectors, "max_bytes", quotasCfg.MaxBytes)
+	return []llmux.Option{llmux.WithMemoryQuotas(llmux.MemoryQuotaConfig{
+		Default: llmux.MemoryQuota{
+			MaxSessions: quotasCfg.MaxSessions,
+			MaxVectors:  quotasCfg.MaxVectors,
+			MaxBytes:    quotasCfg.MaxBytes,
+		},
+		Ledger: ledger,
+	})}, nil
+}
+
+// buildMemoryRetentionOptions applies memory.session.ttl and
+// memory.long_term.retention. Redis sessions also expire natively.
+func buildMemoryRetentionOptions(cfg *config.Config) []llmux.Option {
+	retention := llmux.MemoryRetention{}
+	if cfg.Memory.Session.Enabled {
+		retention.SessionIdle = cfg.Memory.Session.TTL
+	}
+	if cfg.Memory.LongTerm.Enabled {
+		retention.VectorAge = cfg.Memory.LongTerm.Retention
+	}
+	if retention == (llmux.MemoryRetention{}) {
+		return nil
+	}
+	return []llmux.Option{llmux.WithMemoryRetention(retention)}
+}
+
+// memoryRetentionJobs returns the job pruning expired memory of the current
+// client, or nil when no retention is configured.
+func memoryRetentionJobs(cfg *config.Config, clients *api.ClientSwapper, logger *slog.Logger) []auth.Job {
+	if len(buildMemoryRetentionOptions(cfg)) == 0 {
+		return nil
+	}
+	return []auth.Job{{
+		Name: "memory_retention",
+		Run: func(ctx context.Context) error {
+			client, release := clients.Acquire()
+			defer release()
+			result, err := client.PruneMemory(ctx)
+			if err != nil {
+				return err
+			}
+			logger.Info("pruned expired memory", "sessions", result.Sessions, "vectors", result.Vectors)
+			return nil
+		},
+	}}
+}
@@ -2,19 +2,22 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/blueberrycongee/llmux/internal/auth"
 	"github.com/blueberrycongee/llmux/internal/config"
 	"github.com/blueberrycongee/llmux/internal/metrics"
 	"github.com/blueberrycongee/llmux/internal/observability"
+	"github.com/blueberrycongee/llmux/internal/plugin"
 )
 
-func buildMiddlewareStack(cfg *config.Config, authStore auth.Store, logger *slog.Logger, syncer *auth.UserTeamSyncer, enforcer *auth.CasbinEnforcer, sessionManager *auth.SessionManager) (func(http.Handler) http.Handler, error) {
+func buildMiddlewareStack(cfg *config.Config, authStore auth.Store, logger *slog.Logger, syncer *auth.UserTeamSyncer, enforcer *auth.CasbinEnforcer, sessionManager *auth.SessionManager, auditLogger *auth.AuditLogger) (func(http.Handler) http.Handler, error) {
 	if cfg == nil {
 		return nil, errNilConfig
 	}
@@ -28,6 +31,8 @@ func buildMiddlewareStack(cfg *config.Config, authStore auth.Store, logger *slog
 			Enabled:                true,
 			LastUsedUpdateInterval: cfg.Auth.LastUsedUpdateInterval,
 			Enforcer:               enforcer,
+			TrustedProxyCIDRs:      cfg.RateLimit.TrustedProxyCIDRs,
+			AuditLogger:            auditLogger,
 		})
 		logger.Info("API key authentication middleware enabled", "casbin_enabled", enforcer != nil)
 	}
@@ -45,13 +50,38 @@ func buildMiddlewareStack(cfg *config.Config, authStore auth.Store, logger *slog
 		logger.Info("OIDC authentication enabled", "issuer", cfg.Auth.OIDC.IssuerURL, "sync_enabled", syncer != nil)
 	}
 
+	var jwtAuth *auth.JWTAuthenticator
+	if cfg.Auth.Enabled && cfg.Auth.JWT.Enabled {
+		authenticator, err := auth.NewJWTAuthenticator(context.Background(), mapJWTAuthConfig(cfg.Auth.JWT), authStore, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize JWT authentication: %w", err)
+		}
+		jwtAuth = authenticator
+		logger.Info("JWT authentication enabled for data-plane routes", "issuer", cfg.Auth.JWT.Issuer)
+	}
+
+	var parallelLimiter *auth.ParallelRequestLimiter
+	if cfg.Auth.Enabled {
+		parallelLimiter = buildParallelRequestLimiter(cfg, logger)
+	}
+
+	var hostTenants *auth.HostTenantResolver
+	if len(cfg.Auth.HostTenants) > 0 {
+		hostTenants = auth.NewHostTenantResolver(mapHostTenants(cfg.Auth.HostTenants))
+		logger.Info("host-based tenant resolution enabled", "hosts", len(cfg.Auth.HostTenants))
+	}
+
 	return func(next http.Handler) http.Handler {
 		if next == nil {
 			return nil
 		}
 		handler := next
+		handler = pluginRouteMiddleware(handler)
 		handler = managementBodyLimitMiddleware(handler)
 		handler = managementAuthzMiddleware(cfg, enforcer)(handler)
+		if parallelLimiter != nil {
+			handler = parallelLimiter.Middleware(handler)
+		}
 		if authMiddleware != nil {
 			handler = authMiddleware.ModelAccessMiddleware(handler)
 			handler = authMiddleware.Authenticate(handler)
@@ -59,9 +89,15 @@ func buildMiddlewareStack(cfg *config.Config, authStore auth.Store, logger *slog
 		if oidcMiddleware != nil {
 			handler = oidcMiddleware(handler)
 		}
+		if jwtAuth != nil {
+			handler = jwtAuth.Middleware(handler)
+		}
 		if sessionManager != nil {
 			handler = auth.SessionMiddleware(sessionManager)(handler)
 		}
+		if hostTenants != nil {
+			handler = auth.HostTenantMiddleware(hostTenants)(handler)
+		}
 		handler = metrics.Middleware(handler)
 		handler = observability.RequestIDMiddleware(handler)
 		handler = corsMiddleware(cfg.CORS, handler)
@@ -70,6 +106,44 @@ func buildMiddlewareStack(cfg *config.Config, authStore auth.Store, logger *slog
 	}, nil
 }
 
+// buildParallelRequestLimiter enforces max_parallel_requests of keys and
+// teams, sharing counts through Redis when rate limiting is distributed.
+func buildParallelRequestLimiter(cfg *config.Config, logger *slog.Logger) *auth.ParallelRequestLimiter {
+	limiterCfg := auth.ParallelRequestLimiterConfig{
+		FailOpen: cfg.RateLimit.FailOpen,
+		Logger:   logger,
+	}
+	if cfg.RateLimit.Distributed && (cfg.Cache.Redis.Addr != "" || len(cfg.Cache.Redis.ClusterAddrs) > 0) {
+		redisClient, _, err := newRedisUniversalClient(cfg.Cache.Redis)
+		if err != nil {
+			logger.Warn("distributed parallel request limits unavailable, using local counters", "error", err)
+		} else {
+			pingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := redisClient.Ping(pingCtx).Err(); err != nil {
+				logger.Warn("distributed parallel request limits unavailable, using local counters", "error", err)
+				_ = redisClient.Close()
+			} else {
+				limiterCfg.Counter = auth.NewRedisConcurrencyCounter(redisClient, "", 0)
+			}
+			cancel()
+		}
+	}
+	return auth.NewParallelRequestLimiter(limiterCfg)
+}
+
+func mapHostTenants(tenants []config.HostTenantConfig) []auth.HostTenant {
+	out := make([]auth.HostTenant, 0, len(tenants))
+	for _, t := range tenants {
+		out = append(out, auth.HostTenant{
+			Host:           t.Host,
+			OrganizationID: t.OrganizationID,
+			AllowedModels:  t.AllowedModels,
+			DefaultTags:    t.DefaultTags,
+		})
+	}
+	return out
+}
+
 func detectLocaleFromRequest(r *http.Request) string {
 	if r == nil {
 		return "i18n"
@@ -122,6 +196,13 @@ func recoveryMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 	}
 }
 
+// pluginRouteMiddleware records the request path for route-scoped plugins.
+func pluginRouteMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(plugin.WithRoute(r.Context(), r.URL.Path)))
+	})
+}
+
 const maxManagementBodyBytes int64 = 1 << 20
 
 const bootstrapTokenHeader = "X-LLMux-Bootstrap-Token" // #nosec G101 -- header name, not a credential
@@ -146,6 +227,12 @@ func managementAuthzMiddleware(cfg *config.Config, enforcer *auth.CasbinEnforcer
 				return
 			}
 
+			// Key types are a hard bound: no policy lets a data-plane key reach admin routes.
+			if authCtx.APIKey != nil && !authCtx.APIKey.KeyType.AllowsRoute(r.Method, r.URL.Path) {
+				writeAuthzError(w, r, http.StatusForbidden, "management permission required", "permission_error")
+				return
+			}
+
 			if enforcer != nil {
 				var sub string
 				if authCtx.APIKey != nil {
@@ -195,13 +282,22 @@ func isManagementPath(path string) bool {
 		"/key/",
 		"/team/",
 		"/user/",
+		"/customer/",
 		"/organization/",
+		"/access_group/",
+		"/model/",
 		"/spend/",
 		"/audit/",
 		"/global/",
 		"/invitation/",
 		"/control/",
+		"/config/",
 		"/mcp/",
+		"/router/",
+		"/logs/",
+		"/admin/",
+		"/provenance/",
+		"/v1/prompts",
 	}
 	for _, prefix := range managementPrefixes {
 		if strings.HasPrefix(path, prefix) {
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+// migrationTimeout bounds applying all pending migrations, including the
+// wait for another instance holding the migration lock.
+const migrationTimeout = 5 * time.Minute
+
+// schemaMigrator is implemented by auth stores with embedded migrations.
+type schemaMigrator interface {
+	Migrate(ctx context.Context) (int, error)
+}
+
+// migrateAuthStore applies pending migrations when store supports them.
+func migrateAuthStore(store auth.Store, logger *slog.Logger) error {
+	migrator, ok := store.(schemaMigrator)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+	defer cancel()
+	version, err := migrator.Migrate(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate database: %w", err)
+	}
+	logger.Info("database schema is up to date", "schema_version", version)
+	return nil
+}
+
+// runMigrateOnly applies pending migrations and returns, for running schema
+// changes as a separate deployment step.
+func runMigrateOnly(cfg *config.Config, logger *slog.Logger) error {
+	if !cfg.Database.Enabled {
+		return fmt.Errorf("--migrate-only requires database.enabled")
+	}
+	store, _, err := newPostgresStores(buildPostgresConfig(cfg.Database))
+	if err != nil {
+		return fmt.Errorf("init postgres auth store: %w", err)
+	}
+	defer func() { _ = store.Close() }()
+	return migrateAuthStore(store, logger)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+// withModelProviders returns cfg with the providers of store appended.
+// Config file providers win over stored ones of the same name. cfg itself
+// is not modified.
+func withModelProviders(ctx context.Context, cfg *config.Config, store auth.ModelProviderStore, logger *slog.Logger) (*config.Config, error) {
+	if store == nil {
+		return cfg, nil
+	}
+	stored, err := store.ListModelProviders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load model providers: %w", err)
+	}
+	if len(stored) == 0 {
+		return cfg, nil
+	}
+
+	names := make(map[string]bool, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		names[p.Name] = true
+	}
+	merged := *cfg
+	merged.Providers = append(make([]config.ProviderConfig, 0, len(cfg.Providers)+len(stored)), cfg.Providers...)
+	for _, p := range stored {
+		if names[p.Name] {
+			logger.Warn("stored model provider shadowed by config file provider", "provider", p.Name)
+			continue
+		}
+		merged.Providers = append(merged.Providers, modelProviderConfig(p))
+	}
+	return &merged, nil
+}
+
+func modelProviderConfig(p *auth.ModelProvider) config.ProviderConfig {
+	return config.ProviderConfig{
+		Name:                p.Name,
+		Type:                p.Type,
+		APIKey:              p.APIKey,
+		BaseURL:             p.BaseURL,
+		AllowPrivateBaseURL: p.AllowPrivateBaseURL,
+		Models:              p.Models,
+		MaxConcurrent:       p.MaxConcurrent,
+		Timeout:             p.Timeout,
+		Headers:             p.Headers,
+		APIVersion:          p.APIVersion,
+		RPM:                 p.RPM,
+		TPM:                 p.TPM,
+		Tags:                p.Tags,
+	}
+}
+
+// enableStoredModelProviders makes reloader include the providers managed
+// through the /model endpoints and, when there are any, rebuilds the
+// running client to serve them.
+func enableStoredModelProviders(ctx context.Context, reloader *clientReloader, authStore auth.Store, logger *slog.Logger) error {
+	store, ok := auth.ModelProviders(authStore)
+	if !ok {
+		return nil
+	}
+	reloader.enableModelProviders(store)
+
+	stored, err := store.ListModelProviders(ctx)
+	if err != nil {
+		return fmt.Errorf("load model providers: %w", err)
+	}
+	if len(stored) == 0 {
+		return nil
+	}
+	if err := reloader.Rebuild(ctx); err != nil {
+		return fmt.Errorf("apply stored model providers: %w", err)
+	}
+	logger.Info("stored model providers loaded", "providers", len(stored))
+	return nil
+}
@@ -31,3 +31,22 @@ func mapOIDCConfig(cfg config.OIDCConfig) auth.OIDCConfig {
 		UserInfoCacheTTL:       int(cfg.OIDCUserInfoCacheTTL),
 	}
 }
+
+func mapJWTAuthConfig(cfg config.JWTAuthConfig) auth.JWTAuthConfig {
+	return auth.JWTAuthConfig{
+		Issuer:     cfg.Issuer,
+		Audiences:  cfg.Audiences,
+		JWKSURL:    cfg.JWKSURL,
+		Algorithms: cfg.Algorithms,
+		ClaimMapping: auth.JWTClaimMapping{
+			KeyIDField:     cfg.ClaimMapping.KeyIDJWTField,
+			KeyAliasField:  cfg.ClaimMapping.KeyAliasJWTField,
+			TeamIDField:    cfg.ClaimMapping.TeamIDJWTField,
+			TeamAliasMap:   cfg.ClaimMapping.TeamAliasMap,
+			UserIDField:    cfg.ClaimMapping.UserIDJWTField,
+			OrgIDField:     cfg.ClaimMapping.OrgIDJWTField,
+			ModelsField:    cfg.ClaimMapping.ModelsJWTField,
+			EndUserIDField: cfg.ClaimMapping.EndUserIDJWTField,
+		},
+	}
+}
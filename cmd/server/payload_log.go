@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/observability"
+)
+
+// defaultPayloadRedaction replaces matches of redaction rules without a
+// replacement.
+const defaultPayloadRedaction = "[REDACTED]"
+
+// buildPayloadLogger returns the payload logger when payload logging is
+// enabled. Payloads go to the object store when configured, otherwise to the
+// database, or to memory when there is none.
+func buildPayloadLogger(ctx context.Context, cfg *config.Config, store auth.Store, logger *slog.Logger) (*auth.PayloadLogger, error) {
+	logging := cfg.Governance.PayloadLogging
+	if !logging.Enabled {
+		return nil, nil
+	}
+
+	var payloadStore auth.PayloadLogStore
+	if logging.Store == "object_store" {
+		objectStore, err := auth.NewObjectStorePayloadLogStore(ctx, auth.ObjectStorePayloadLogStoreConfig{
+			Provider:        logging.ObjectStore.Provider,
+			Bucket:          logging.ObjectStore.Bucket,
+			Region:          logging.ObjectStore.Region,
+			Endpoint:        logging.ObjectStore.Endpoint,
+			AccessKeyID:     logging.ObjectStore.AccessKeyID,
+			SecretAccessKey: logging.ObjectStore.SecretAccessKey,
+			PathPrefix:      logging.ObjectStore.PathPrefix,
+		})
+		if err != nil {
+			return nil, err
+		}
+		payloadStore = objectStore
+	} else if pg, ok := auth.UnwrapStore(store).(*auth.PostgresStore); ok {
+		payloadStore = pg
+	} else {
+		logger.Warn("payload logging without a database keeps payloads in memory")
+		payloadStore = auth.NewMemoryPayloadLogStore()
+	}
+
+	payloadCfg := auth.PayloadLoggerConfig{
+		Teams:     append([]string(nil), logging.Teams...),
+		Retention: logging.Retention,
+	}
+	if redactor := buildPayloadRedactor(logging.Redaction); redactor != nil {
+		payloadCfg.Redact = redactor.Redact
+	}
+	logger.Info("payload logging enabled", "teams", logging.Teams, "retention", payloadCfg.Retention)
+	return auth.NewPayloadLogger(payloadStore, payloadCfg), nil
+}
+
+func buildPayloadRedactor(cfg config.PayloadRedactionConfig) *observability.Redactor {
+	if !cfg.Defaults && len(cfg.Rules) == 0 {
+		return nil
+	}
+	redactor := &observability.Redactor{}
+	if cfg.Defaults {
+		redactor = observability.NewRedactor()
+	}
+	for i, rule := range cfg.Rules {
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = defaultPayloadRedaction
+		}
+		redactor.AddPattern(rule.Pattern, replacement, "payload_rule_"+strconv.Itoa(i))
+	}
+	return redactor
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/plugin"
+	"github.com/blueberrycongee/llmux/internal/plugin/builtin"
+)
+
+// buildPluginOptions registers the plugins declared in cfg.
+func buildPluginOptions(cfg []config.PluginConfig, logger *slog.Logger) ([]llmux.Option, error) {
+	plugins, err := buildPlugins(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	opts := make([]llmux.Option, 0, len(plugins))
+	for _, p := range plugins {
+		opts = append(opts, llmux.WithPlugin(p))
+	}
+	return opts, nil
+}
+
+// buildPlugins creates the plugins declared in cfg, wrapped in their scope.
+func buildPlugins(cfg []config.PluginConfig, logger *slog.Logger) ([]plugin.Plugin, error) {
+	plugins := make([]plugin.Plugin, 0, len(cfg))
+	for _, pc := range cfg {
+		p, err := buildPlugin(pc, logger)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %q: %w", pc.Name, err)
+		}
+		plugins = append(plugins, plugin.Scoped(p, plugin.Scope{
+			Routes: pc.Scope.Routes,
+			Models: pc.Scope.Models,
+			Teams:  pc.Scope.Teams,
+		}))
+	}
+	return plugins, nil
+}
+
+func buildPlugin(pc config.PluginConfig, logger *slog.Logger) (plugin.Plugin, error) {
+	switch pc.Type {
+	case "http":
+		opts := []builtin.HTTPPluginOption{
+			builtin.WithHTTPPluginTimeout(pc.Timeout),
+			builtin.WithHTTPPluginHeaders(pc.Headers),
+			builtin.WithHTTPPluginConfig(pc.Config),
+			builtin.WithHTTPPluginLogger(logger),
+		}
+		if pc.FailurePolicy != "" {
+			opts = append(opts, builtin.WithHTTPPluginFailurePolicy(pc.FailurePolicy))
+		}
+		if len(pc.Hooks) > 0 {
+			opts = append(opts, builtin.WithHTTPPluginHooks(pc.Hooks...))
+		}
+		if pc.Priority != 0 {
+			opts = append(opts, builtin.WithHTTPPluginPriority(pc.Priority))
+		}
+		return builtin.NewHTTPPlugin(pc.Name, pc.URL, opts...), nil
+	case "logging":
+		var lc config.LoggingPluginConfig
+		if err := pc.DecodeConfig(&lc); err != nil {
+			return nil, err
+		}
+		opts := []builtin.LoggingOption{
+			builtin.WithLogRequestBody(lc.LogRequestBody),
+			builtin.WithLogResponseBody(lc.LogResponseBody),
+		}
+		if pc.Priority != 0 {
+			opts = append(opts, builtin.WithLoggingPriority(pc.Priority))
+		}
+		return builtin.NewLoggingPlugin(logger, opts...), nil
+	case "rate_limit":
+		var rc config.RateLimitPluginConfig
+		if err := pc.DecodeConfig(&rc); err != nil {
+			return nil, err
+		}
+		burst := rc.Burst
+		if burst == 0 {
+			burst = int(math.Ceil(rc.Rate))
+		}
+		opts := []builtin.RateLimitOption{builtin.WithRateLimitLogger(logger)}
+		if pc.Priority != 0 {
+			opts = append(opts, builtin.WithRateLimitPriority(pc.Priority))
+		}
+		return builtin.NewRateLimitPlugin(rc.Rate, burst, opts...), nil
+	case "transform":
+		var tc config.TransformPluginConfig
+		if err := pc.DecodeConfig(&tc); err != nil {
+			return nil, err
+		}
+		var opts []builtin.TransformOption
+		if pc.Priority != 0 {
+			opts = append(opts, builtin.WithTransformPriority(pc.Priority))
+		}
+		p, err := builtin.NewTransformPlugin(pc.Name, builtin.TransformConfig{
+			SystemPrompt:      tc.SystemPrompt,
+			TeamSystemPrompts: tc.TeamSystemPrompts,
+			SystemPromptMode:  tc.SystemPromptMode,
+			MessageTemplate:   tc.MessageTemplate,
+			MessageRoles:      tc.MessageRoles,
+			ResponseTemplate:  tc.ResponseTemplate,
+			MaxTemperature:    tc.MaxTemperature,
+			MaxTopP:           tc.MaxTopP,
+			MaxTokens:         tc.MaxTokens,
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown type %q", pc.Type)
+	}
+}
+
+// pluginNames returns the pipeline names of plugins.
+func pluginNames(plugins []plugin.Plugin) []string {
+	names := make([]string, 0, len(plugins))
+	for _, p := range plugins {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+// replaceConfigPlugins swaps the plugins declared in prev for those declared
+// in next on a running client.
+func replaceConfigPlugins(client *llmux.Client, prev, next []config.PluginConfig, logger *slog.Logger) error {
+	previous, err := buildPlugins(prev, logger)
+	if err != nil {
+		return err
+	}
+	plugins, err := buildPlugins(next, logger)
+	if err != nil {
+		return err
+	}
+	return client.ReplacePlugins(pluginNames(previous), plugins...)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/plugin"
+)
+
+func TestBuildPlugins(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	plugins, err := buildPlugins([]config.PluginConfig{
+		{Name: "policy", Type: "http", URL: "http://policy:8000/hook", Scope: config.PluginScopeConfig{Models: []string{"gpt-4o*"}}},
+		{Name: "limit", Type: "rate_limit", Config: map[string]any{"rate": 2}},
+		{Name: "standards", Type: "transform", Priority: 30, Config: map[string]any{"system_prompt": "Be concise."}},
+	}, logger)
+	if err != nil {
+		t.Fatalf("buildPlugins() error = %v", err)
+	}
+	if got := pluginNames(plugins); len(got) != 3 || got[0] != "policy" || got[1] != "rate-limit" || got[2] != "standards" {
+		t.Fatalf("plugin names = %v", got)
+	}
+	if _, ok := plugins[0].(plugin.StreamPlugin); !ok {
+		t.Fatal("expected the scoped http plugin to remain a stream plugin")
+	}
+	if plugins[2].Priority() != 30 {
+		t.Fatalf("priority = %d, want 30", plugins[2].Priority())
+	}
+
+	if _, err := buildPlugins([]config.PluginConfig{
+		{Name: "standards", Type: "transform", Config: map[string]any{"message_template": "{{.Content"}},
+	}, logger); err == nil {
+		t.Fatal("expected an invalid template to fail")
+	}
+}
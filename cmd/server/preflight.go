@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/preflight"
+)
+
+// runPreflight checks providers, pricing coverage and backing stores, writes
+// the consolidated report to out and returns an error when fail_on_critical
+// is set and a critical check failed.
+func runPreflight(ctx context.Context, cfg *config.Config, client *llmux.Client, authStore auth.Store, logger *slog.Logger, out io.Writer) error {
+	if !cfg.Preflight.Enabled {
+		return nil
+	}
+	timeout := cfg.Preflight.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultConfig().Preflight.Timeout
+	}
+	mode := preflight.ProviderCheckMode(cfg.Preflight.ProviderCheck)
+	if mode == "" {
+		mode = preflight.ProviderCheckDryRun
+	}
+
+	report := &preflight.Report{}
+	report.Add(preflight.CheckProviders(ctx, client, mode, timeout)...)
+	report.Add(preflight.CheckPricing(client)...)
+
+	if versioner, ok := authStore.(preflight.SchemaVersioner); ok && cfg.Database.Enabled {
+		dbCtx, cancel := context.WithTimeout(ctx, timeout)
+		report.Add(preflight.CheckDatabase(dbCtx, versioner, auth.LatestSchemaVersion))
+		cancel()
+	}
+
+	if usesRedis(cfg) {
+		if rdb, _, err := newRedisUniversalClient(cfg.Cache.Redis); err != nil {
+			report.Add(preflight.Result{
+				Check: "redis", Target: "redis", Status: preflight.StatusFail, Critical: true,
+				Detail: err.Error(), Fix: "set cache.redis.addr or cache.redis.cluster_addrs",
+			})
+		} else {
+			redisCtx, cancel := context.WithTimeout(ctx, timeout)
+			report.Add(preflight.CheckRedis(redisCtx, rdb))
+			cancel()
+			_ = rdb.Close()
+		}
+	}
+
+	if err := report.WriteTable(out); err != nil {
+		logger.Warn("failed to write preflight report", "error", err)
+	}
+
+	pass, warn, fail := report.Counts()
+	critical := report.CriticalFailures()
+	logger.Info("preflight checks completed",
+		"passed", pass,
+		"warnings", warn,
+		"failed", fail,
+		"critical", len(critical),
+	)
+	if len(critical) == 0 || !cfg.Preflight.FailOnCritical {
+		return nil
+	}
+
+	targets := make([]string, 0, len(critical))
+	for _, res := range critical {
+		targets = append(targets, res.Check+":"+res.Target)
+	}
+	return fmt.Errorf("preflight: %d critical checks failed (%s)", len(critical), strings.Join(targets, ", "))
+}
+
+// usesRedis reports whether any enabled feature depends on Redis.
+func usesRedis(cfg *config.Config) bool {
+	if cfg.Cache.Enabled {
+		switch strings.ToLower(cfg.Cache.Type) {
+		case "redis", "dual":
+			return true
+		}
+	}
+	return cfg.Routing.Distributed || (cfg.RateLimit.Enabled && cfg.RateLimit.Distributed)
+}
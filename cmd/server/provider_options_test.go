@@ -24,6 +24,12 @@ func TestBuildClientOptions_WiresProviderConfigFields(t *testing.T) {
 				Headers: map[string]string{
 					"X-Test": "1",
 				},
+				ProxyURL: "http://proxy.internal:3128",
+				Transport: config.ProviderTransportConfig{
+					MaxIdleConnsPerHost: 256,
+					DialTimeout:         5 * time.Second,
+					DisableHTTP2:        true,
+				},
 			},
 		},
 	}
@@ -48,4 +54,10 @@ func TestBuildClientOptions_WiresProviderConfigFields(t *testing.T) {
 	if got.Headers["X-Test"] != "1" {
 		t.Fatalf("expected header to be wired")
 	}
+	if got.ProxyURL != cfg.Providers[0].ProxyURL {
+		t.Fatalf("expected proxy_url %q, got %q", cfg.Providers[0].ProxyURL, got.ProxyURL)
+	}
+	if got.Transport.MaxIdleConnsPerHost != 256 || got.Transport.DialTimeout != 5*time.Second || !got.Transport.DisableHTTP2 {
+		t.Fatalf("expected transport settings to be wired, got %+v", got.Transport)
+	}
 }
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/api"
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+// replayAPIKeyEnv names the environment variable holding the admin key the
+// --replay command authenticates with. Replays are accounted against it.
+const replayAPIKeyEnv = "LLMUX_API_KEY"
+
+// replayTimeout bounds one replay batch, which waits for every upstream
+// response in turn.
+const replayTimeout = 10 * time.Minute
+
+// replayGatewayURL returns the base URL of the running gateway's management
+// routes from the configuration at path.
+func replayGatewayURL(path string) (string, error) {
+	// #nosec G304 -- path is the operator-supplied --config flag.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read config file: %w", err)
+	}
+	cfg, err := config.Parse(data)
+	if err != nil {
+		return "", err
+	}
+	scheme, port := "http", cfg.Server.Port
+	if cfg.Server.TLS.Enabled {
+		scheme = "https"
+	}
+	if cfg.Server.AdminPort > 0 {
+		port = cfg.Server.AdminPort
+	}
+	return fmt.Sprintf("%s://localhost:%d", scheme, port), nil
+}
+
+// runReplay replays retained requests through the gateway at baseURL via
+// POST /logs/replay and writes the results to out. Going through the
+// running gateway routes and accounts the replays like the HTTP endpoint.
+func runReplay(ctx context.Context, client *http.Client, baseURL, apiKey string, req api.ReplayRequest, out io.Writer) error {
+	if apiKey == "" {
+		return fmt.Errorf("--replay requires an admin key in %s", replayAPIKeyEnv)
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(baseURL, "/")+"/logs/replay", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("replay: gateway returned %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, respBody, "", "  "); err != nil {
+		return fmt.Errorf("replay: invalid response: %w", err)
+	}
+	indented.WriteByte('\n')
+	_, err = indented.WriteTo(out)
+	return err
+}
+
+// splitRequestIDs parses the comma-separated --replay flag.
+func splitRequestIDs(value string) []string {
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/api"
+)
+
+func TestRunReplay(t *testing.T) {
+	var got api.ReplayRequest
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/logs/replay", r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer admin" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"invalid api key"}}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &got))
+		_, _ = w.Write([]byte(`{"data":[],"summary":{"total":2}}`))
+	}))
+	t.Cleanup(gateway.Close)
+
+	req := api.ReplayRequest{RequestIDs: splitRequestIDs("req-1, req-2,"), Model: "gpt-4o"}
+	var out bytes.Buffer
+	require.NoError(t, runReplay(context.Background(), gateway.Client(), gateway.URL+"/", "admin", req, &out))
+	require.Equal(t, []string{"req-1", "req-2"}, got.RequestIDs)
+	require.Equal(t, "gpt-4o", got.Model)
+	require.Contains(t, out.String(), `"total": 2`)
+
+	err := runReplay(context.Background(), gateway.Client(), gateway.URL, "wrong", req, &out)
+	require.ErrorContains(t, err, "401")
+	require.ErrorContains(t, runReplay(context.Background(), gateway.Client(), gateway.URL, "", req, &out), replayAPIKeyEnv)
+}
@@ -13,6 +13,7 @@ import (
 
 type dataHandler interface {
 	HealthCheck(http.ResponseWriter, *http.Request)
+	ReadinessCheck(http.ResponseWriter, *http.Request)
 	ChatCompletions(http.ResponseWriter, *http.Request)
 	Completions(http.ResponseWriter, *http.Request)
 	Embeddings(http.ResponseWriter, *http.Request)
@@ -22,6 +23,7 @@ type dataHandler interface {
 	AudioTranslations(http.ResponseWriter, *http.Request)
 	AudioSpeech(http.ResponseWriter, *http.Request)
 	Batches(http.ResponseWriter, *http.Request)
+	IngestDocument(http.ResponseWriter, *http.Request)
 }
 
 type managementRegistrar interface {
@@ -72,7 +74,7 @@ func registerDataRoutes(mux *http.ServeMux, handler dataHandler, cfg *config.Con
 
 	// Health endpoints
 	mux.HandleFunc("GET /health/live", handler.HealthCheck)
-	mux.HandleFunc("GET /health/ready", handler.HealthCheck)
+	mux.HandleFunc("GET /health/ready", handler.ReadinessCheck)
 
 	// OpenAI-compatible endpoints
 	mux.HandleFunc("POST /v1/chat/completions", handler.ChatCompletions)
@@ -86,6 +88,9 @@ func registerDataRoutes(mux *http.ServeMux, handler dataHandler, cfg *config.Con
 	mux.HandleFunc("POST /v1/batches", handler.Batches)
 	mux.HandleFunc("GET /v1/models", handler.ListModels)
 
+	// Long-term memory
+	mux.HandleFunc("POST /v1/memory/{collection}/documents", handler.IngestDocument)
+
 	// Metrics endpoint
 	if cfg != nil && cfg.Metrics.Enabled {
 		mux.Handle("GET "+cfg.Metrics.Path, promhttp.Handler())
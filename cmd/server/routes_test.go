@@ -11,6 +11,7 @@ import (
 type fakeDataHandler struct{}
 
 func (fakeDataHandler) HealthCheck(http.ResponseWriter, *http.Request)         {}
+func (fakeDataHandler) ReadinessCheck(http.ResponseWriter, *http.Request)      {}
 func (fakeDataHandler) ChatCompletions(http.ResponseWriter, *http.Request)     {}
 func (fakeDataHandler) Completions(http.ResponseWriter, *http.Request)         {}
 func (fakeDataHandler) Embeddings(http.ResponseWriter, *http.Request)          {}
@@ -20,6 +21,7 @@ func (fakeDataHandler) AudioTranscriptions(http.ResponseWriter, *http.Request) {
 func (fakeDataHandler) AudioTranslations(http.ResponseWriter, *http.Request)   {}
 func (fakeDataHandler) AudioSpeech(http.ResponseWriter, *http.Request)         {}
 func (fakeDataHandler) Batches(http.ResponseWriter, *http.Request)             {}
+func (fakeDataHandler) IngestDocument(http.ResponseWriter, *http.Request)      {}
 
 type fakeManagementHandler struct{}
 
@@ -76,6 +78,10 @@ func TestBuildMuxes_AdminPortDisabled_RegistersOnlyDataRoutes(t *testing.T) {
 		t.Fatalf("data mux missing batch route, got pattern %q", got)
 	}
 
+	if got := routePattern(muxes.Data, http.MethodPost, "/v1/memory/docs/documents"); got != "POST /v1/memory/{collection}/documents" {
+		t.Fatalf("data mux missing document ingestion route, got pattern %q", got)
+	}
+
 	if got := routePattern(muxes.Data, http.MethodGet, "/key/list"); got != "" {
 		t.Fatalf("data mux should not have management routes, got pattern %q", got)
 	}
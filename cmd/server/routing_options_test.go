@@ -6,6 +6,8 @@ import (
 
 	llmux "github.com/blueberrycongee/llmux"
 	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/pkg/router"
+	"github.com/blueberrycongee/llmux/routers"
 )
 
 func applyOptions(opts []llmux.Option) llmux.ClientConfig {
@@ -68,3 +70,42 @@ func TestBuildRoutingOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestMapRoutingStrategy(t *testing.T) {
+	err := routers.Register("test-custom-strategy", func(cfg router.Config, _ router.StatsStore, _ router.RoundRobinStore) (router.Router, error) {
+		return routers.NewShuffleRouterWithConfig(cfg), nil
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	tests := map[string]llmux.Strategy{
+		"latency":              llmux.StrategyLowestLatency,
+		"tag-based":            llmux.StrategyTagBased,
+		"simple-shuffle":       llmux.StrategySimpleShuffle,
+		"test-custom-strategy": llmux.Strategy("test-custom-strategy"),
+		"unknown":              llmux.StrategyShuffle,
+	}
+	for input, want := range tests {
+		if got := mapRoutingStrategy(input); got != want {
+			t.Errorf("mapRoutingStrategy(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestBuildRoutingOptions_CooldownBackoff(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Routing.CooldownMaxPeriod = 5 * time.Minute
+	cfg.Routing.HalfOpenSuccesses = 2
+
+	clientCfg := applyOptions(buildRoutingOptions(cfg))
+	if clientCfg.CooldownMaxPeriod != 5*time.Minute {
+		t.Fatalf("CooldownMaxPeriod = %v, want 5m", clientCfg.CooldownMaxPeriod)
+	}
+	if clientCfg.HalfOpenSuccesses != 2 {
+		t.Fatalf("HalfOpenSuccesses = %d, want 2", clientCfg.HalfOpenSuccesses)
+	}
+	if got := len(redisStatsOptions(cfg.Routing)); got != 3 {
+		t.Fatalf("redisStatsOptions returned %d options, want 3", got)
+	}
+}
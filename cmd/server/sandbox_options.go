@@ -0,0 +1,19 @@
+package main
+
+import (
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+// buildSandboxConfig converts the sandbox section into the client's mock
+// provider settings.
+func buildSandboxConfig(cfg config.SandboxConfig) llmux.SandboxConfig {
+	out := llmux.SandboxConfig{
+		DefaultContent:      cfg.DefaultResponse,
+		EmbeddingDimensions: cfg.EmbeddingDimensions,
+	}
+	for _, r := range cfg.Responses {
+		out.Rules = append(out.Rules, llmux.SandboxRule{Match: r.Match, Content: r.Content})
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/secret"
+	"github.com/blueberrycongee/llmux/internal/secret/awssm"
+	"github.com/blueberrycongee/llmux/internal/secret/gcpsm"
+)
+
+// secretCacheTTL is how long resolved secrets from remote stores are cached.
+const secretCacheTTL = 5 * time.Minute
+
+// registerSecretProviders registers the remote secret providers cfg
+// configures: 'vault', 'awssm' (AWS Secrets Manager) and 'gcpsm' (GCP
+// Secret Manager).
+func registerSecretProviders(cfg *config.Config, secretManager *secret.Manager, logger *slog.Logger) error {
+	if err := registerVaultProvider(cfg, secretManager, logger); err != nil {
+		return err
+	}
+
+	if cfg.AWSSecrets.Enabled {
+		p, err := awssm.New(awssm.Config{
+			Region:   cfg.AWSSecrets.Region,
+			Endpoint: cfg.AWSSecrets.Endpoint,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to initialize aws secrets manager provider: %w", err)
+		}
+		secretManager.Register("awssm", secret.NewCachedProvider(p, secretCacheTTL))
+		logger.Info("aws secrets manager provider enabled", "region", cfg.AWSSecrets.Region)
+	}
+
+	if cfg.GCPSecrets.Enabled {
+		p, err := gcpsm.New(context.Background(), gcpsm.Config{
+			Project:  cfg.GCPSecrets.Project,
+			Endpoint: cfg.GCPSecrets.Endpoint,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to initialize gcp secret manager provider: %w", err)
+		}
+		secretManager.Register("gcpsm", secret.NewCachedProvider(p, secretCacheTTL))
+		logger.Info("gcp secret manager provider enabled", "project", cfg.GCPSecrets.Project)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+// spendReportClaimTTL outlasts a month so each report is claimed once.
+const spendReportClaimTTL = 32 * 24 * time.Hour
+
+// spendReportJobs returns the monthly spend report job when it is enabled.
+func spendReportJobs(ctx context.Context, cfg *config.Config, store auth.Store, logger *slog.Logger) ([]auth.Job, error) {
+	reports := cfg.Governance.SpendReports
+	if !reports.Enabled {
+		return nil, nil
+	}
+	if store == nil {
+		logger.Warn("spend reports require a database, disabling")
+		return nil, nil
+	}
+
+	var sinks []auth.SpendReportSink
+	if email := reports.Email; email.Enabled {
+		sink, err := auth.NewEmailSpendReportSink(auth.EmailSpendReportSinkConfig{
+			Host:     email.Host,
+			Port:     email.Port,
+			Username: email.Username,
+			Password: email.Password,
+			From:     email.From,
+			To:       append([]string(nil), email.To...),
+		})
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if objectStore := reports.ObjectStore; objectStore.Enabled {
+		sink, err := auth.NewObjectStoreSpendReportSink(ctx, auth.ObjectStoreSpendReportSinkConfig{
+			Provider:        objectStore.Provider,
+			Bucket:          objectStore.Bucket,
+			Region:          objectStore.Region,
+			Endpoint:        objectStore.Endpoint,
+			AccessKeyID:     objectStore.AccessKeyID,
+			SecretAccessKey: objectStore.SecretAccessKey,
+			PathPrefix:      objectStore.PathPrefix,
+		})
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	claims := buildIdempotencyStore(cfg, "llmux:spend_report:", logger)
+	job, err := auth.NewSpendReportJob(store, auth.SpendReportJobConfig{
+		GroupBy: reports.GroupBy,
+		Format:  reports.Format,
+		Sinks:   sinks,
+		Claim: func(ctx context.Context, period string) (bool, error) {
+			return claims.PutIfAbsent(ctx, period, spendReportClaimTTL)
+		},
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	return []auth.Job{job}, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+func TestSpendReportJobs(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := config.DefaultConfig()
+	store := auth.NewMemoryStore()
+
+	jobs, err := spendReportJobs(context.Background(), cfg, store, logger)
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("spendReportJobs() disabled = %d jobs, %v", len(jobs), err)
+	}
+
+	cfg.Governance.SpendReports = config.SpendReportsConfig{
+		Enabled: true,
+		Email:   config.EmailConfig{Enabled: true, Host: "smtp.example.com", From: "llmux@example.com", To: []string{"finops@example.com"}},
+	}
+	jobs, err = spendReportJobs(context.Background(), cfg, store, logger)
+	if err != nil {
+		t.Fatalf("spendReportJobs() error = %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Name != "spend_report" {
+		t.Fatalf("jobs = %+v", jobs)
+	}
+
+	cfg.Governance.SpendReports.Format = "xml"
+	if _, err := spendReportJobs(context.Background(), cfg, store, logger); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/streaming"
+)
+
+// buildStreamBuffer returns the buffer of resumable streams when enabled. It
+// shares streams through Redis in distributed mode, so clients can resume on
+// any instance, and keeps them in memory otherwise.
+func buildStreamBuffer(cfg *config.Config, logger *slog.Logger) streaming.EventBuffer {
+	resume := cfg.Stream.Resume
+	if !resume.Enabled {
+		return nil
+	}
+	if cfg.Deployment.Mode == "distributed" && (cfg.Cache.Redis.Addr != "" || len(cfg.Cache.Redis.ClusterAddrs) > 0) {
+		redisClient, _, err := newRedisUniversalClient(cfg.Cache.Redis)
+		if err != nil {
+			logger.Warn("distributed stream resume unavailable, falling back to memory", "error", err)
+		} else {
+			return streaming.NewRedisEventBuffer(redisClient, "llmux:stream:", resume.MaxEvents)
+		}
+	}
+	return streaming.NewMemoryEventBuffer(resume.MaxEvents)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/k8s"
+	"github.com/blueberrycongee/llmux/internal/servertls"
+)
+
+// startServerTLS loads the listener certificate and re-reads it whenever
+// the certificate, key or client CA files change. It returns nil when TLS
+// is disabled.
+func startServerTLS(ctx context.Context, cfg config.ServerTLSConfig, logger *slog.Logger) (*servertls.Reloader, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	reloader, err := servertls.New(mapServerTLSConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	interval := cfg.ReloadInterval
+	if interval <= 0 {
+		interval = config.DefaultConfig().Server.TLS.ReloadInterval
+	}
+	// Secret mounts swap a symlink, so contents are polled like Kubernetes
+	// mode does for config files.
+	err = k8s.WatchFiles(ctx, reloader.Files(), interval, logger, func() {
+		if err := reloader.Reload(); err != nil {
+			logger.Error("failed to reload TLS certificate, keeping the previous one", "error", err)
+			return
+		}
+		logger.Info("TLS certificate reloaded", "cert_file", cfg.CertFile)
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("TLS enabled",
+		"cert_file", cfg.CertFile,
+		"client_ca_file", cfg.ClientCAFile,
+		"client_auth", clientAuthMode(cfg),
+		"client_cert_paths", cfg.ClientCertPaths,
+	)
+	return reloader, nil
+}
+
+func mapServerTLSConfig(cfg config.ServerTLSConfig) servertls.Config {
+	out := servertls.Config{
+		CertFile:     cfg.CertFile,
+		KeyFile:      cfg.KeyFile,
+		ClientCAFile: cfg.ClientCAFile,
+		MinVersion:   tls.VersionTLS12,
+	}
+	if cfg.MinVersion == "1.3" {
+		out.MinVersion = tls.VersionTLS13
+	}
+	switch clientAuthMode(cfg) {
+	case config.ClientAuthRequire:
+		out.ClientAuth = tls.RequireAndVerifyClientCert
+	case config.ClientAuthOptional:
+		out.ClientAuth = tls.VerifyClientCertIfGiven
+	default:
+		out.ClientAuth = tls.NoClientCert
+	}
+	return out
+}
+
+// clientAuthMode resolves the default client_auth: client certificates are
+// verified when given as soon as a CA bundle is configured.
+func clientAuthMode(cfg config.ServerTLSConfig) string {
+	if cfg.ClientAuth != "" {
+		return cfg.ClientAuth
+	}
+	if cfg.ClientCAFile != "" {
+		return config.ClientAuthOptional
+	}
+	return config.ClientAuthNone
+}
+
+// clientCertMiddleware rejects requests under prefixes that did not present
+// a client certificate verified against the CA bundle.
+func clientCertMiddleware(prefixes []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(prefixes) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requiresClientCert(r.URL.Path, prefixes) && (r.TLS == nil || len(r.TLS.VerifiedChains) == 0) {
+				writeAuthzError(w, r, http.StatusUnauthorized, "client certificate required", "authentication_error")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requiresClientCert reports whether path is one of prefixes or lies below
+// it. Prefixes match whole path segments, so "/admin" does not cover
+// "/administrator"; a trailing slash on a prefix is optional.
+func requiresClientCert(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// serve runs srv over TLS when tlsReloader is set and plain HTTP otherwise.
+func serve(srv *http.Server, tlsReloader *servertls.Reloader) error {
+	if tlsReloader == nil {
+		return srv.ListenAndServe()
+	}
+	srv.TLSConfig = tlsReloader.TLSConfig()
+	return srv.ListenAndServeTLS("", "")
+}
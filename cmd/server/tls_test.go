@@ -0,0 +1,54 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+func TestClientCertMiddleware_RequiresVerifiedCertOnPrefixes(t *testing.T) {
+	handler := clientCertMiddleware([]string{"/control/", "/key/"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(path string, state *tls.ConnectionState) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.TLS = state
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve("/v1/models", nil))
+	require.Equal(t, http.StatusUnauthorized, serve("/control/routing", nil))
+	require.Equal(t, http.StatusUnauthorized, serve("/key/generate", &tls.ConnectionState{}))
+	verified := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+	require.Equal(t, http.StatusOK, serve("/control/routing", verified))
+
+	handler = clientCertMiddleware([]string{"/admin"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	require.Equal(t, http.StatusUnauthorized, serve("/admin", nil))
+	require.Equal(t, http.StatusUnauthorized, serve("/admin/users", nil))
+	require.Equal(t, http.StatusOK, serve("/administrator", nil))
+}
+
+func TestMapServerTLSConfig_ClientAuth(t *testing.T) {
+	cfg := config.ServerTLSConfig{Enabled: true, CertFile: "tls.crt", KeyFile: "tls.key"}
+	require.Equal(t, tls.NoClientCert, mapServerTLSConfig(cfg).ClientAuth)
+
+	cfg.ClientCAFile = "ca.crt"
+	require.Equal(t, tls.VerifyClientCertIfGiven, mapServerTLSConfig(cfg).ClientAuth)
+
+	cfg.ClientAuth = config.ClientAuthRequire
+	cfg.MinVersion = "1.3"
+	mapped := mapServerTLSConfig(cfg)
+	require.Equal(t, tls.RequireAndVerifyClientCert, mapped.ClientAuth)
+	require.Equal(t, uint16(tls.VersionTLS13), mapped.MinVersion)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/api"
+	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/preflight"
+	"github.com/blueberrycongee/llmux/internal/secret"
+	"github.com/blueberrycongee/llmux/internal/secret/env"
+	"github.com/blueberrycongee/llmux/internal/secret/file"
+)
+
+// validateConfigData parses and validates a candidate config. When it is
+// valid, a client is built from its providers and pricing settings and the
+// provider checks run in mode. Nothing is applied to the running gateway.
+func validateConfigData(ctx context.Context, data []byte, mode preflight.ProviderCheckMode, secretManager *secret.Manager, logger *slog.Logger) *preflight.Validation {
+	v := preflight.NewValidation()
+
+	cfg, err := config.Parse(data)
+	if err != nil {
+		v.AddError(preflight.IssueParse, err.Error())
+		return v
+	}
+	if err := cfg.Validate(); err != nil {
+		v.AddError(preflight.IssueInvalid, err.Error())
+		return v
+	}
+	for _, w := range cfg.Warnings() {
+		v.AddWarning(w.Code, w.Message)
+	}
+	if len(cfg.Providers) == 0 {
+		// config.source db: the providers live in the stored config.
+		return v
+	}
+
+	opts := append([]llmux.Option{llmux.WithLogger(logger)}, buildProviderOptions(cfg, secretManager)...)
+	if cfg.PricingFile != "" {
+		opts = append(opts, llmux.WithPricingFile(cfg.PricingFile))
+	}
+	if fallback, ok := buildPricingFallback(cfg.PricingFallback); ok {
+		opts = append(opts, llmux.WithPricingFallback(fallback))
+	}
+	client, err := llmux.New(opts...)
+	if err != nil {
+		v.AddError(preflight.IssueClient, err.Error())
+		return v
+	}
+	defer func() { _ = client.Close() }()
+
+	timeout := cfg.Preflight.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultConfig().Preflight.Timeout
+	}
+	v.AddChecks(preflight.CheckProviders(ctx, client, mode, timeout)...)
+	v.AddChecks(preflight.CheckPricing(client)...)
+	return v
+}
+
+// newConfigValidator returns the validator behind POST /config/validate.
+// Secret references in candidate configs resolve through the running
+// gateway's secret providers.
+func newConfigValidator(secretManager *secret.Manager, logger *slog.Logger) api.ConfigValidator {
+	return func(ctx context.Context, data []byte, mode preflight.ProviderCheckMode) *preflight.Validation {
+		return validateConfigData(ctx, data, mode, secretManager, logger)
+	}
+}
+
+// runValidateConfig validates the config file at path with provider probes
+// and writes the result as JSON to out. Secret references resolve through
+// the secret providers the file configures. It returns an error when the
+// config is not valid.
+func runValidateConfig(ctx context.Context, path string, logger *slog.Logger, out io.Writer) error {
+	// #nosec G304 -- path is the operator-supplied --config flag.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	secretManager := secret.NewManager()
+	defer func() { _ = secretManager.Close() }()
+	secretManager.Register("env", env.New())
+	secretManager.Register("file", file.New())
+	if cfg, parseErr := config.Parse(data); parseErr == nil && cfg.Validate() == nil {
+		if err := registerSecretProviders(cfg, secretManager, logger); err != nil {
+			return err
+		}
+	}
+
+	v := validateConfigData(ctx, data, preflight.ProviderCheckProbe, secretManager, logger)
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	if !v.Valid {
+		return fmt.Errorf("config %s is not valid", path)
+	}
+	return nil
+}
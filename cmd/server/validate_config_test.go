@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/preflight"
+	"github.com/blueberrycongee/llmux/internal/secret"
+)
+
+func candidateConfig(baseURL string) string {
+	return `
+providers:
+  - name: upstream
+    type: openai
+    api_key: test-key
+    base_url: ` + baseURL + `
+    allow_private_base_url: true
+    models: [gpt-4o]
+`
+}
+
+func TestValidateConfigData(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	sm := secret.NewManager()
+	ctx := context.Background()
+
+	v := validateConfigData(ctx, []byte("providers: [\n"), preflight.ProviderCheckOff, sm, logger)
+	if v.Valid || len(v.Errors) != 1 || v.Errors[0].Code != preflight.IssueParse {
+		t.Fatalf("parse error: %+v", v)
+	}
+
+	v = validateConfigData(ctx, []byte("routing:\n  retry_count: -1\n"), preflight.ProviderCheckOff, sm, logger)
+	if v.Valid || len(v.Errors) != 1 || v.Errors[0].Code != preflight.IssueInvalid {
+		t.Fatalf("invalid config: %+v", v)
+	}
+
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if status >= http.StatusBadRequest {
+			http.Error(w, `{"error":{"message":"denied"}}`, status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"x","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	v = validateConfigData(ctx, []byte(candidateConfig(server.URL)), preflight.ProviderCheckProbe, sm, logger)
+	if !v.Valid || len(v.Errors) != 0 || len(v.Checks) == 0 {
+		t.Fatalf("valid config: %+v", v)
+	}
+
+	status = http.StatusUnauthorized
+	v = validateConfigData(ctx, []byte(candidateConfig(server.URL)), preflight.ProviderCheckProbe, sm, logger)
+	if v.Valid {
+		t.Fatalf("expected a rejected probe to fail validation: %+v", v)
+	}
+}
+
+func TestRunValidateConfig(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("routing:\n  retry_count: -1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runValidateConfig(context.Background(), path, logger, &out); err == nil {
+		t.Fatal("expected an invalid config to return an error")
+	}
+	var v preflight.Validation
+	if err := json.Unmarshal(out.Bytes(), &v); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	if v.Valid || len(v.Errors) != 1 {
+		t.Fatalf("report = %+v", v)
+	}
+}
@@ -0,0 +1,112 @@
+package llmux
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/blueberrycongee/llmux/pkg/provider"
+)
+
+// newCredentialRollover wraps the credentials of cfg in a blue/green rollover
+// when a secondary credential is configured, installing it as cfg's token
+// source. It returns nil when no secondary credential is set.
+func newCredentialRollover(cfg *ProviderConfig) *provider.CredentialRollover {
+	secondary := cfg.SecondaryTokenSource
+	if secondary == nil && cfg.SecondaryAPIKey != "" {
+		secondary = provider.NewStaticTokenSource(cfg.SecondaryAPIKey)
+	}
+	if secondary == nil {
+		return nil
+	}
+	primary := cfg.TokenSource
+	if primary == nil {
+		primary = provider.NewStaticTokenSource(cfg.APIKey)
+	}
+	rollover := provider.NewCredentialRollover(primary, secondary, cfg.Rollover)
+	cfg.TokenSource = rollover
+	return rollover
+}
+
+// credentialFailed reports whether an upstream outcome counts against the
+// credential: transport errors, auth failures, rate limits and server errors.
+func credentialFailed(resp *http.Response, err error) bool {
+	if err != nil || resp == nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// reportCredential records the outcome of httpReq against the provider's
+// credential rollover, if any.
+func (c *Client) reportCredential(providerName string, httpReq *http.Request, resp *http.Response, err error) {
+	c.mu.RLock()
+	rollover := c.credentials[providerName]
+	c.mu.RUnlock()
+	if rollover == nil {
+		return
+	}
+	if rollover.Report(httpReq, credentialFailed(resp, err)) {
+		c.logger.Warn("credential rollover rolled back: secondary error rate above threshold", "provider", providerName)
+	}
+}
+
+// CredentialRollovers returns the rollover status of every provider with a
+// secondary credential, keyed by provider name.
+func (c *Client) CredentialRollovers() map[string]RolloverStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make(map[string]RolloverStatus, len(c.credentials))
+	for name, rollover := range c.credentials {
+		out[name] = rollover.Status()
+	}
+	return out
+}
+
+// RollbackCredentials routes all of a provider's traffic to its primary
+// credential immediately.
+func (c *Client) RollbackCredentials(providerName string) error {
+	rollover, err := c.credentialRollover(providerName)
+	if err != nil {
+		return err
+	}
+	rollover.Rollback()
+	c.logger.Warn("credential rollover rolled back", "provider", providerName)
+	return nil
+}
+
+// ResumeCredentials returns a rolled-back provider to its rollover schedule.
+func (c *Client) ResumeCredentials(providerName string) error {
+	rollover, err := c.credentialRollover(providerName)
+	if err != nil {
+		return err
+	}
+	rollover.Resume()
+	c.logger.Info("credential rollover resumed", "provider", providerName)
+	return nil
+}
+
+// SetCredentialSchedule replaces a provider's rollover schedule, resuming a
+// rolled-back rollover.
+func (c *Client) SetCredentialSchedule(providerName string, schedule RolloverSchedule) error {
+	rollover, err := c.credentialRollover(providerName)
+	if err != nil {
+		return err
+	}
+	rollover.SetSchedule(schedule)
+	c.logger.Info("credential rollover schedule updated", "provider", providerName, "secondary_weight", schedule.SecondaryWeight)
+	return nil
+}
+
+func (c *Client) credentialRollover(providerName string) (*provider.CredentialRollover, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	rollover, ok := c.credentials[providerName]
+	if !ok {
+		return nil, fmt.Errorf("provider %s has no credential rollover", providerName)
+	}
+	return rollover, nil
+}
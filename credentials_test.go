@@ -0,0 +1,89 @@
+package llmux
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+func TestCredentialRollover_SplitsAndRollsBack(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.Header.Get("Authorization")]++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			ID:      "resp",
+			Object:  "chat.completion",
+			Created: time.Now().Unix(),
+			Model:   "gpt-4o",
+			Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: jsonString("ok")}, FinishReason: "stop"}},
+			Usage:   &Usage{PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2},
+		})
+	}))
+	defer server.Close()
+
+	client, err := New(
+		WithProvider(ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "sk-old",
+			SecondaryAPIKey:     "sk-new",
+			Rollover:            RolloverSchedule{SecondaryWeight: 1},
+			BaseURL:             server.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+		withTestPricing(t, "gpt-4o"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	req := &ChatRequest{Model: "gpt-4o", Messages: []ChatMessage{{Role: "user", Content: jsonString("hi")}}}
+	if _, err := client.ChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if err := client.RollbackCredentials("openai"); err != nil {
+		t.Fatalf("RollbackCredentials() error = %v", err)
+	}
+	if _, err := client.ChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	mu.Lock()
+	if seen["Bearer sk-new"] != 1 || seen["Bearer sk-old"] != 1 {
+		t.Fatalf("upstream credentials = %v, want one request per key", seen)
+	}
+	mu.Unlock()
+	status, ok := client.CredentialRollovers()["openai"]
+	if !ok || !status.RolledBack {
+		t.Fatalf("rollover status = %+v, %v", status, ok)
+	}
+	if status.Credentials[0].Requests != 1 || status.Credentials[1].Requests != 1 {
+		t.Fatalf("credential stats = %+v", status.Credentials)
+	}
+	if err := client.RollbackCredentials("missing"); err == nil {
+		t.Fatal("expected error for provider without rollover")
+	}
+
+	if err := client.ResumeCredentials("openai"); err != nil {
+		t.Fatalf("ResumeCredentials() error = %v", err)
+	}
+	if _, err := client.ChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if seen["Bearer sk-new"] != 2 {
+		t.Fatalf("upstream credentials = %v, want the secondary key after resume", seen)
+	}
+}
@@ -0,0 +1,119 @@
+package llmux
+
+import (
+	"sort"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/observability"
+	"github.com/blueberrycongee/llmux/internal/plugin"
+)
+
+// PluginInfo describes one plugin in the client's pipeline.
+type PluginInfo struct {
+	Name      string `json:"name"`
+	Priority  int    `json:"priority"`
+	Streaming bool   `json:"streaming"`
+}
+
+// ActiveStream describes a streaming response that has not been closed yet.
+// DeploymentID is the deployment the stream started on; recovery may move it.
+type ActiveStream struct {
+	RequestID    string    `json:"request_id,omitempty"`
+	Model        string    `json:"model"`
+	Provider     string    `json:"provider,omitempty"`
+	DeploymentID string    `json:"deployment_id,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+	AgeSeconds   float64   `json:"age_seconds"`
+}
+
+// DeploymentLoad reports in-flight requests and concurrency state for a deployment.
+// Semaphore fields are shared by all deployments of the same provider.
+type DeploymentLoad struct {
+	DeploymentID      string `json:"deployment_id"`
+	Provider          string `json:"provider"`
+	Model             string `json:"model"`
+	InFlight          int64  `json:"in_flight"`
+	MaxConcurrent     int    `json:"max_concurrent"`
+	SemaphoreInUse    int    `json:"semaphore_in_use"`
+	SemaphoreCapacity int    `json:"semaphore_capacity"`
+	SemaphoreWaiting  int    `json:"semaphore_waiting"`
+}
+
+// PluginPipeline returns the registered plugins in execution order.
+func (c *Client) PluginPipeline() []PluginInfo {
+	plugins := c.pipeline.Plugins()
+	out := make([]PluginInfo, 0, len(plugins))
+	for _, p := range plugins {
+		_, streaming := p.(plugin.StreamPlugin)
+		out = append(out, PluginInfo{
+			Name:      p.Name(),
+			Priority:  p.Priority(),
+			Streaming: streaming,
+		})
+	}
+	return out
+}
+
+// ActiveStreams returns the streams currently open on this client, oldest first.
+func (c *Client) ActiveStreams() []ActiveStream {
+	now := time.Now()
+	var out []ActiveStream
+	c.activeStreams.Range(func(_, value any) bool {
+		stream := *value.(*ActiveStream)
+		stream.AgeSeconds = now.Sub(stream.StartedAt).Seconds()
+		out = append(out, stream)
+		return true
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+	return out
+}
+
+// DeploymentLoads returns in-flight and semaphore state for every deployment.
+func (c *Client) DeploymentLoads() []DeploymentLoad {
+	deployments := c.ListDeployments()
+	rt := c.activeRouter()
+	out := make([]DeploymentLoad, 0, len(deployments))
+	for _, d := range deployments {
+		if d == nil {
+			continue
+		}
+		load := DeploymentLoad{
+			DeploymentID:  d.ID,
+			Provider:      d.ProviderName,
+			Model:         d.ModelName,
+			MaxConcurrent: d.MaxConcurrent,
+		}
+		if stats := rt.GetStats(d.ID); stats != nil {
+			load.InFlight = stats.ActiveRequests
+		}
+		if d.MaxConcurrent > 0 {
+			res := c.ResilienceStats(d.ProviderName)
+			load.SemaphoreInUse = res.ConcurrentCurrent
+			load.SemaphoreCapacity = res.ConcurrentCapacity
+			load.SemaphoreWaiting = res.ConcurrentWaiting
+		}
+		out = append(out, load)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].DeploymentID < out[j].DeploymentID })
+	return out
+}
+
+// trackStream registers s as active until untrackStream is called.
+func (c *Client) trackStream(s *StreamReader) {
+	stream := &ActiveStream{
+		RequestID: observability.RequestIDFromContext(s.ctx),
+		StartedAt: s.startTime,
+	}
+	if s.originalReq != nil {
+		stream.Model = s.originalReq.Model
+	}
+	if s.deployment != nil {
+		stream.Provider = s.deployment.ProviderName
+		stream.DeploymentID = s.deployment.ID
+	}
+	c.activeStreams.Store(s, stream)
+}
+
+func (c *Client) untrackStream(s *StreamReader) {
+	c.activeStreams.Delete(s)
+}
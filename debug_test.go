@@ -0,0 +1,73 @@
+package llmux
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_ActiveStreamsAndPipeline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("data: {}\n\n"))
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	mock := &streamMockProvider{httpMockProvider: &httpMockProvider{
+		name:    "mock-debug",
+		models:  []string{"test-model"},
+		baseURL: server.URL,
+	}}
+
+	client, err := New(
+		WithProviderInstance("mock-debug", mock, []string{"test-model"}),
+		withTestPricing(t, "test-model"),
+		WithCooldown(0),
+		WithPlugin(&streamTestPlugin{}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	pipeline := client.PluginPipeline()
+	if len(pipeline) != 1 || pipeline[0].Name != "stream-test" || !pipeline[0].Streaming {
+		t.Fatalf("unexpected pipeline: %+v", pipeline)
+	}
+
+	stream, err := client.ChatCompletionStream(context.Background(), &ChatRequest{
+		Model:    "test-model",
+		Messages: []ChatMessage{{Role: "user", Content: json.RawMessage(`"Hello"`)}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+
+	active := client.ActiveStreams()
+	if len(active) != 1 {
+		t.Fatalf("expected 1 active stream, got %d", len(active))
+	}
+	if active[0].Model != "test-model" || active[0].Provider != "mock-debug" {
+		t.Errorf("unexpected active stream: %+v", active[0])
+	}
+	if active[0].AgeSeconds < 0 || active[0].AgeSeconds > time.Minute.Seconds() {
+		t.Errorf("unexpected stream age: %v", active[0].AgeSeconds)
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := client.ActiveStreams(); len(got) != 0 {
+		t.Fatalf("expected no active streams after close, got %d", len(got))
+	}
+
+	loads := client.DeploymentLoads()
+	if len(loads) != 1 || loads[0].Provider != "mock-debug" || loads[0].InFlight != 0 {
+		t.Fatalf("unexpected deployment loads: %+v", loads)
+	}
+}
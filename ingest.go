@@ -0,0 +1,144 @@
+package llmux
+
+import (
+	"context"
+	stderrors "errors"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/blueberrycongee/llmux/internal/memory"
+	"github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+// Metadata keys set on every chunk stored by Ingest.
+const (
+	MemoryMetadataDocumentID = "document_id"
+	MemoryMetadataSource     = "source"
+	MemoryMetadataChunk      = "chunk"
+)
+
+// Re-export ingestion types.
+type (
+	// Extractor turns a document's bytes into plain text.
+	Extractor = memory.Extractor
+
+	// ExtractorFunc adapts a function to the Extractor interface.
+	ExtractorFunc = memory.ExtractorFunc
+
+	// CommandExtractor converts documents with an external command.
+	CommandExtractor = memory.CommandExtractor
+
+	// ChunkConfig selects how documents are split before embedding.
+	ChunkConfig = memory.ChunkConfig
+)
+
+// Chunking strategies.
+const (
+	ChunkFixed     = memory.ChunkFixed
+	ChunkParagraph = memory.ChunkParagraph
+	ChunkMarkdown  = memory.ChunkMarkdown
+)
+
+// Document is a file to ingest into long-term memory.
+type Document struct {
+	// Name identifies the document in chunk metadata, e.g. a file name.
+	Name string
+	// ContentType selects the extractor, e.g. "text/markdown".
+	ContentType string
+	Data        []byte
+	// Metadata is copied onto every chunk.
+	Metadata map[string]string
+	// Chunking overrides the client's chunking for this document.
+	Chunking *ChunkConfig
+}
+
+// IngestResult describes an ingested document.
+type IngestResult struct {
+	DocumentID string   `json:"document_id"`
+	Chunks     int      `json:"chunks"`
+	IDs        []string `json:"ids"`
+	// Model, Usage and Cost describe the embedding calls made for the
+	// document; Cost is in USD.
+	Model string  `json:"model,omitempty"`
+	Usage Usage   `json:"usage"`
+	Cost  float64 `json:"-"`
+}
+
+// Ingest extracts the text of doc, splits it into chunks, embeds them and
+// stores them in collection for the tenant attached to ctx. Every chunk
+// carries the document's metadata plus document_id, source and chunk, so
+// the whole document can be forgotten by the returned IDs. When storing
+// fails partway, the result describing the chunks stored and the embeddings
+// made so far is returned with the error.
+func (c *Client) Ingest(ctx context.Context, collection string, doc Document) (*IngestResult, error) {
+	if c.longTermMemory == nil {
+		return nil, ErrLongTermMemoryDisabled
+	}
+	if err := validateCollection(collection); err != nil {
+		return nil, err
+	}
+
+	text, err := memory.Extract(ctx, c.ingestExtractors, doc.ContentType, doc.Data)
+	if stderrors.Is(err, memory.ErrUnsupportedContentType) {
+		return nil, errors.NewInvalidRequestError("", "", err.Error())
+	}
+	if err != nil {
+		return nil, errors.NewInvalidRequestError("", "", "failed to extract document text: "+err.Error())
+	}
+	chunking := c.ingestChunking
+	if doc.Chunking != nil {
+		chunking = *doc.Chunking
+	}
+	chunks, err := memory.Chunk(text, chunking)
+	if err != nil {
+		return nil, errors.NewInvalidRequestError("", "", err.Error())
+	}
+	if len(chunks) == 0 {
+		return nil, memoryError(memory.ErrEmptyText)
+	}
+
+	documentID := uuid.NewString()
+	metadata := make([]map[string]string, len(chunks))
+	sizes := make([]int64, len(chunks))
+	var total int64
+	for i, chunk := range chunks {
+		md := make(map[string]string, len(doc.Metadata)+3)
+		for k, v := range doc.Metadata {
+			md[k] = v
+		}
+		md[MemoryMetadataDocumentID] = documentID
+		md[MemoryMetadataChunk] = strconv.Itoa(i)
+		if doc.Name != "" {
+			md[MemoryMetadataSource] = doc.Name
+		}
+		metadata[i] = md
+		sizes[i] = memory.RecordSize(chunk, md)
+		total += sizes[i]
+	}
+
+	ctx, err = c.memoryScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkMemoryQuota(ctx, "", MemoryUsage{Vectors: len(chunks), Bytes: total}); err != nil {
+		return nil, err
+	}
+	namespace := memoryNamespace(ctx, collection)
+	usage := &embeddingUsage{}
+	ids, err := c.longTermMemory.RememberAll(context.WithValue(ctx, embeddingUsageKey{}, usage), namespace, chunks, metadata)
+	// Chunks stored before a failure still count against the quota.
+	if c.memoryQuotas != nil {
+		for i, id := range ids {
+			if trackErr := c.memoryQuotas.Ledger.Track(ctx, memoryTenant(ctx), memory.KindVector, namespace+"/"+id, sizes[i], c.memoryRetention.VectorAge); trackErr != nil {
+				c.logger.Warn("failed to track memory size", "error", trackErr)
+			}
+		}
+	}
+	result := &IngestResult{DocumentID: documentID, Chunks: len(ids), IDs: ids, Model: c.memoryModel}
+	result.Usage, result.Cost = usage.total()
+	if err != nil {
+		return result, memoryError(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,215 @@
+// Package api provides HTTP handlers for the LLM gateway API.
+// Model access group endpoints.
+package api //nolint:revive // package name is intentional
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+// ============================================================================
+// Model Access Group Endpoints
+// ============================================================================
+
+// AccessGroupRequest represents a request to create or update a model access group.
+type AccessGroupRequest struct {
+	Name        string   `json:"name"`
+	Models      []string `json:"models"`
+	Description *string  `json:"description,omitempty"`
+}
+
+// DeleteAccessGroupRequest represents a request to delete model access groups.
+type DeleteAccessGroupRequest struct {
+	Names []string `json:"names"`
+}
+
+func (h *ManagementHandler) modelAccessGroups(w http.ResponseWriter, r *http.Request) (auth.ModelAccessGroupStore, bool) {
+	groups, ok := auth.ModelAccessGroups(h.store)
+	if !ok {
+		h.writeError(w, r, http.StatusNotImplemented, "model access groups are not supported by the configured store")
+	}
+	return groups, ok
+}
+
+// NewAccessGroup handles POST /access_group/new
+func (h *ManagementHandler) NewAccessGroup(w http.ResponseWriter, r *http.Request) {
+	groups, ok := h.modelAccessGroups(w, r)
+	if !ok {
+		return
+	}
+	var req AccessGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		h.writeError(w, r, http.StatusBadRequest, "name is required")
+		return
+	}
+	if len(req.Models) == 0 {
+		h.writeError(w, r, http.StatusBadRequest, "models is required")
+		return
+	}
+
+	existing, err := groups.GetModelAccessGroup(r.Context(), req.Name)
+	if err != nil {
+		h.logger.Error("failed to get model access group", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to create access group")
+		return
+	}
+	if existing != nil {
+		h.writeError(w, r, http.StatusConflict, "access group already exists")
+		return
+	}
+
+	now := time.Now()
+	group := &auth.ModelAccessGroup{
+		Name:      req.Name,
+		Models:    req.Models,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if req.Description != nil {
+		group.Description = *req.Description
+	}
+	if err := groups.SaveModelAccessGroup(r.Context(), group); err != nil {
+		h.logger.Error("failed to create model access group", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to create access group")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, group)
+}
+
+// UpdateAccessGroup handles POST /access_group/update
+func (h *ManagementHandler) UpdateAccessGroup(w http.ResponseWriter, r *http.Request) {
+	groups, ok := h.modelAccessGroups(w, r)
+	if !ok {
+		return
+	}
+	var req AccessGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Name == "" {
+		h.writeError(w, r, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	group, err := groups.GetModelAccessGroup(r.Context(), req.Name)
+	if err != nil {
+		h.logger.Error("failed to get model access group", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to update access group")
+		return
+	}
+	if group == nil {
+		h.writeError(w, r, http.StatusNotFound, "access group not found")
+		return
+	}
+
+	if req.Models != nil {
+		if len(req.Models) == 0 {
+			h.writeError(w, r, http.StatusBadRequest, "models must not be empty")
+			return
+		}
+		group.Models = req.Models
+	}
+	if req.Description != nil {
+		group.Description = *req.Description
+	}
+	group.UpdatedAt = time.Now()
+
+	if err := groups.SaveModelAccessGroup(r.Context(), group); err != nil {
+		h.logger.Error("failed to update model access group", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to update access group")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, group)
+}
+
+// DeleteAccessGroup handles POST /access_group/delete
+func (h *ManagementHandler) DeleteAccessGroup(w http.ResponseWriter, r *http.Request) {
+	groups, ok := h.modelAccessGroups(w, r)
+	if !ok {
+		return
+	}
+	var req DeleteAccessGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Names) == 0 {
+		h.writeError(w, r, http.StatusBadRequest, "names is required")
+		return
+	}
+
+	deleted := make([]string, 0, len(req.Names))
+	for _, name := range req.Names {
+		if err := groups.DeleteModelAccessGroup(r.Context(), name); err != nil {
+			h.logger.Warn("failed to delete model access group", "name", name, "error", err)
+			continue
+		}
+		deleted = append(deleted, name)
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"deleted_access_groups": deleted,
+	})
+}
+
+// GetAccessGroupInfo handles GET /access_group/info
+func (h *ManagementHandler) GetAccessGroupInfo(w http.ResponseWriter, r *http.Request) {
+	groups, ok := h.modelAccessGroups(w, r)
+	if !ok {
+		return
+	}
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		h.writeError(w, r, http.StatusBadRequest, "name parameter is required")
+		return
+	}
+
+	group, err := groups.GetModelAccessGroup(r.Context(), name)
+	if err != nil {
+		h.logger.Error("failed to get model access group", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to get access group")
+		return
+	}
+	if group == nil {
+		h.writeError(w, r, http.StatusNotFound, "access group not found")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, group)
+}
+
+// ListAccessGroups handles GET /access_group/list
+func (h *ManagementHandler) ListAccessGroups(w http.ResponseWriter, r *http.Request) {
+	groups, ok := h.modelAccessGroups(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := groups.ListModelAccessGroups(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list model access groups", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to list access groups")
+		return
+	}
+	if list == nil {
+		list = []*auth.ModelAccessGroup{}
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"data":  list,
+		"total": len(list),
+	})
+}
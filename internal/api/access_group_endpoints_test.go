@@ -0,0 +1,63 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+func TestAccessGroupEndpoints(t *testing.T) {
+	store := auth.NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := NewManagementHandler(store, nil, logger, nil, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, target, bytes.NewBufferString(body)))
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/access_group/new", `{"name":"cheap-models","models":["gpt-4o-mini"]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(http.MethodPost, "/access_group/new", `{"name":"cheap-models","models":["gpt-4o-mini"]}`)
+	require.Equal(t, http.StatusConflict, rec.Code)
+	rec = do(http.MethodPost, "/access_group/new", `{"name":"empty"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = do(http.MethodPost, "/access_group/update", `{"name":"cheap-models","models":["gpt-4o-mini","claude-3-haiku"],"description":"low cost"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = do(http.MethodGet, "/access_group/info?name=cheap-models", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var group auth.ModelAccessGroup
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &group))
+	require.Equal(t, []string{"gpt-4o-mini", "claude-3-haiku"}, group.Models)
+	require.Equal(t, "low cost", group.Description)
+
+	// A key referencing the group is granted its models.
+	access, err := auth.NewModelAccess(context.Background(), store, &auth.AuthContext{
+		APIKey: &auth.APIKey{ID: "key-1", AllowedModels: []string{"cheap-models"}},
+	})
+	require.NoError(t, err)
+	require.True(t, access.Allows("claude-3-haiku"))
+
+	rec = do(http.MethodGet, "/access_group/list", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"total":1`)
+
+	rec = do(http.MethodPost, "/access_group/delete", `{"names":["cheap-models"]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = do(http.MethodGet, "/access_group/info?name=cheap-models", "")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,108 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/governance"
+)
+
+type bannedContentRequest struct {
+	TeamID string `json:"team_id"`
+	governance.BannedContent
+}
+
+type bannedContentResponse struct {
+	TeamID          string                   `json:"team_id"`
+	BannedContent   governance.BannedContent `json:"banned_content"`
+	AvailableTopics []string                 `json:"available_topics"`
+}
+
+// GetTeamBannedContent handles GET /team/banned_content
+func (h *ManagementHandler) GetTeamBannedContent(w http.ResponseWriter, r *http.Request) {
+	teamID := r.URL.Query().Get("team_id")
+	if teamID == "" {
+		h.writeError(w, r, http.StatusBadRequest, "team_id parameter is required")
+		return
+	}
+
+	team, err := h.store.GetTeam(r.Context(), teamID)
+	if err != nil {
+		h.logger.Error("failed to get team", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to get team")
+		return
+	}
+	if team == nil {
+		h.writeError(w, r, http.StatusNotFound, "team not found")
+		return
+	}
+
+	policy, _ := governance.BannedContentFromMetadata(team.Metadata)
+	h.writeJSON(w, http.StatusOK, bannedContentResponse{
+		TeamID:          team.ID,
+		BannedContent:   policy,
+		AvailableTopics: governance.BannedTopics(),
+	})
+}
+
+// SetTeamBannedContent handles POST /team/banned_content. The request
+// replaces the team's policy; an empty policy removes it.
+func (h *ManagementHandler) SetTeamBannedContent(w http.ResponseWriter, r *http.Request) {
+	var req bannedContentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.TeamID == "" {
+		h.writeError(w, r, http.StatusBadRequest, "team_id is required")
+		return
+	}
+	if err := req.BannedContent.Validate(); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	team, err := h.store.GetTeam(r.Context(), req.TeamID)
+	if err != nil {
+		h.logger.Error("failed to get team", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to get team")
+		return
+	}
+	if team == nil {
+		h.writeError(w, r, http.StatusNotFound, "team not found")
+		return
+	}
+
+	before, _ := governance.BannedContentFromMetadata(team.Metadata)
+	metadata := make(auth.Metadata, len(team.Metadata)+1)
+	for k, v := range team.Metadata {
+		metadata[k] = v
+	}
+	if req.BannedContent.Empty() {
+		delete(metadata, governance.BannedContentMetadataKey)
+	} else {
+		metadata[governance.BannedContentMetadataKey] = req.BannedContent
+	}
+	team.Metadata = metadata
+	team.UpdatedAt = time.Now()
+
+	if err := h.store.UpdateTeam(r.Context(), team); err != nil {
+		h.logger.Error("failed to update team banned content", "error", err)
+		h.auditControlAction(r, auth.AuditActionTeamUpdate, auth.AuditObjectTeam, team.ID, false,
+			map[string]any{governance.BannedContentMetadataKey: before}, nil, nil, err.Error())
+		h.writeError(w, r, http.StatusInternalServerError, "failed to update team")
+		return
+	}
+	h.auditControlAction(r, auth.AuditActionTeamUpdate, auth.AuditObjectTeam, team.ID, true,
+		map[string]any{governance.BannedContentMetadataKey: before},
+		map[string]any{governance.BannedContentMetadataKey: req.BannedContent}, nil, "")
+
+	h.writeJSON(w, http.StatusOK, bannedContentResponse{
+		TeamID:          team.ID,
+		BannedContent:   req.BannedContent,
+		AvailableTopics: governance.BannedTopics(),
+	})
+}
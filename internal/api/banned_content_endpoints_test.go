@@ -0,0 +1,73 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/governance"
+)
+
+func TestTeamBannedContentEndpoints(t *testing.T) {
+	store := auth.NewMemoryStore()
+	require.NoError(t, store.CreateTeam(context.Background(), &auth.Team{
+		ID: "team-1", IsActive: true, Metadata: auth.Metadata{"owner": "ops"}, CreatedAt: time.Now(),
+	}))
+	handler := NewManagementHandler(store, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil)
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	do := func(method, path string, body any) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(data)
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(method, path, reader))
+		return rr
+	}
+
+	rr := do(http.MethodPost, "/team/banned_content", map[string]any{"team_id": "team-1", "topics": []string{"astrology"}})
+	require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+	rr = do(http.MethodPost, "/team/banned_content", map[string]any{"team_id": "missing", "keywords": []string{"x"}})
+	require.Equal(t, http.StatusNotFound, rr.Code, rr.Body.String())
+
+	rr = do(http.MethodPost, "/team/banned_content", map[string]any{
+		"team_id":  "team-1",
+		"keywords": []string{"competitor"},
+		"topics":   []string{"gambling"},
+	})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	team, err := store.GetTeam(context.Background(), "team-1")
+	require.NoError(t, err)
+	require.Equal(t, "ops", team.Metadata["owner"])
+	policy, ok := governance.BannedContentFromMetadata(team.Metadata)
+	require.True(t, ok)
+	require.Equal(t, []string{"competitor"}, policy.Keywords)
+
+	rr = do(http.MethodGet, "/team/banned_content?team_id=team-1", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var got bannedContentResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	require.Equal(t, []string{"gambling"}, got.BannedContent.Topics)
+	require.Contains(t, got.AvailableTopics, "weapons")
+
+	rr = do(http.MethodPost, "/team/banned_content", map[string]any{"team_id": "team-1"})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	team, err = store.GetTeam(context.Background(), "team-1")
+	require.NoError(t, err)
+	_, ok = governance.BannedContentFromMetadata(team.Metadata)
+	require.False(t, ok)
+}
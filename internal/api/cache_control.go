@@ -0,0 +1,94 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+)
+
+// cacheExtensionField is the request body extension carrying cache controls,
+// e.g. {"cache": {"no-cache": true, "ttl": 300}}.
+const cacheExtensionField = "cache"
+
+// cacheExtension is the body form of per-request cache controls. Durations
+// are in seconds.
+type cacheExtension struct {
+	NoCache bool `json:"no-cache"`
+	NoStore bool `json:"no-store"`
+	TTL     int  `json:"ttl"`
+	SMaxAge int  `json:"s-maxage"`
+}
+
+// applyCacheControl reads cache controls from the Cache-Control header and
+// the cache body extension and attaches them to the request context. The
+// extension is removed from req.Extra so it is neither forwarded upstream
+// nor part of the cache key.
+func applyCacheControl(r *http.Request, req *llmux.ChatRequest) (*http.Request, error) {
+	ctrl, ok := parseCacheControlHeader(r.Header.Get("Cache-Control"))
+
+	if raw, exists := req.Extra[cacheExtensionField]; exists {
+		delete(req.Extra, cacheExtensionField)
+		var ext cacheExtension
+		if err := json.Unmarshal(raw, &ext); err != nil {
+			return r, err
+		}
+		if ext.TTL < 0 || ext.SMaxAge < 0 {
+			return r, strconv.ErrRange
+		}
+		ctrl.NoCache = ctrl.NoCache || ext.NoCache
+		ctrl.NoStore = ctrl.NoStore || ext.NoStore
+		if ext.TTL > 0 {
+			ctrl.TTL = time.Duration(ext.TTL) * time.Second
+		}
+		if ext.SMaxAge > 0 {
+			ctrl.MaxAge = time.Duration(ext.SMaxAge) * time.Second
+		}
+		ok = true
+	}
+
+	if !ok {
+		return r, nil
+	}
+	return r.WithContext(llmux.WithCacheControl(r.Context(), ctrl)), nil
+}
+
+// parseCacheControlHeader maps request Cache-Control directives onto cache
+// controls: no-cache, no-store, and max-age/s-maxage (max-age=0 forces a
+// fresh response). Unknown directives are ignored.
+func parseCacheControlHeader(header string) (llmux.CacheControl, bool) {
+	var ctrl llmux.CacheControl
+	found := false
+	for _, directive := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-cache":
+			ctrl.NoCache, found = true, true
+		case "no-store":
+			ctrl.NoStore, found = true, true
+		case "max-age", "s-maxage":
+			seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err != nil || seconds < 0 {
+				continue
+			}
+			if seconds == 0 {
+				ctrl.NoCache = true
+			} else {
+				ctrl.MaxAge = time.Duration(seconds) * time.Second
+			}
+			found = true
+		}
+	}
+	return ctrl, found
+}
+
+// setCacheStatusHeader reports whether the response was served from cache.
+func setCacheStatusHeader(w http.ResponseWriter, status string) {
+	if status != "" {
+		w.Header().Set(cacheStatusHeader, status)
+	}
+}
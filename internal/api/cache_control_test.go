@@ -0,0 +1,63 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+)
+
+func TestParseCacheControlHeader(t *testing.T) {
+	tests := []struct {
+		header string
+		want   llmux.CacheControl
+		found  bool
+	}{
+		{header: "", found: false},
+		{header: "private", found: false},
+		{header: "no-cache", want: llmux.CacheControl{NoCache: true}, found: true},
+		{header: "no-store, max-age=60", want: llmux.CacheControl{NoStore: true, MaxAge: time.Minute}, found: true},
+		{header: "max-age=0", want: llmux.CacheControl{NoCache: true}, found: true},
+		{header: "s-maxage=\"30\"", want: llmux.CacheControl{MaxAge: 30 * time.Second}, found: true},
+	}
+	for _, tt := range tests {
+		got, found := parseCacheControlHeader(tt.header)
+		if found != tt.found || got != tt.want {
+			t.Errorf("parseCacheControlHeader(%q) = %+v, %v; want %+v, %v", tt.header, got, found, tt.want, tt.found)
+		}
+	}
+}
+
+func TestApplyCacheControl(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	r.Header.Set("Cache-Control", "no-store")
+	req := &llmux.ChatRequest{Extra: map[string]json.RawMessage{
+		"cache": json.RawMessage(`{"no-cache": true, "ttl": 300}`),
+		"seed":  json.RawMessage(`1`),
+	}}
+
+	r, err := applyCacheControl(r, req)
+	if err != nil {
+		t.Fatalf("applyCacheControl() error = %v", err)
+	}
+	if _, ok := req.Extra["cache"]; ok {
+		t.Fatal("expected cache extension to be removed from extra fields")
+	}
+	if _, ok := req.Extra["seed"]; !ok {
+		t.Fatal("expected other extra fields to be preserved")
+	}
+	got, ok := llmux.CacheControlFromContext(r.Context())
+	want := llmux.CacheControl{NoCache: true, NoStore: true, TTL: 5 * time.Minute}
+	if !ok || got != want {
+		t.Fatalf("cache control = %+v, want %+v", got, want)
+	}
+
+	bad := &llmux.ChatRequest{Extra: map[string]json.RawMessage{"cache": json.RawMessage(`{"ttl": "soon"}`)}}
+	if _, err := applyCacheControl(httptest.NewRequest(http.MethodPost, "/", nil), bad); err == nil {
+		t.Fatal("expected error for invalid cache extension")
+	}
+}
@@ -9,18 +9,22 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/goccy/go-json"
+	"go.opentelemetry.io/otel/propagation"
 
 	llmux "github.com/blueberrycongee/llmux"
 	"github.com/blueberrycongee/llmux/internal/auth"
 	"github.com/blueberrycongee/llmux/internal/governance"
+	"github.com/blueberrycongee/llmux/internal/guardrails"
 	"github.com/blueberrycongee/llmux/internal/mcp"
 	"github.com/blueberrycongee/llmux/internal/metrics"
 	"github.com/blueberrycongee/llmux/internal/observability"
 	"github.com/blueberrycongee/llmux/internal/pool"
+	"github.com/blueberrycongee/llmux/internal/provenance"
 	"github.com/blueberrycongee/llmux/internal/streaming"
 	"github.com/blueberrycongee/llmux/internal/tokenizer"
 	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
@@ -38,6 +42,17 @@ type ClientHandler struct {
 	mcpManager  mcp.Manager
 	obs         *observability.ObservabilityManager
 	governance  *governance.Engine
+	timeSeries  *metrics.TimeSeries
+	signer      *provenance.Signer
+	killSwitch  *governance.KillSwitch
+	payloads    *auth.PayloadLogger
+
+	sseHeartbeat      time.Duration
+	streamBuffer      streaming.EventBuffer
+	streamResumeTTL   time.Duration
+	streamPassthrough bool
+
+	readinessRequiresProvider bool
 }
 
 // ClientHandlerConfig contains configuration for ClientHandler.
@@ -47,6 +62,23 @@ type ClientHandlerConfig struct {
 	MCPManager    mcp.Manager
 	Observability *observability.ObservabilityManager
 	Governance    *governance.Engine
+	TimeSeries    *metrics.TimeSeries    // Usage time series for dashboards (optional)
+	Signer        *provenance.Signer     // Signs non-streaming responses (optional)
+	KillSwitch    *governance.KillSwitch // Emergency traffic blocks (optional)
+	PayloadLogger *auth.PayloadLogger    // Retains payloads of opted-in teams (optional)
+	// SSEHeartbeatInterval is how often idle streams receive a ": ping"
+	// comment. 0 uses DefaultSSEHeartbeatInterval; negative disables.
+	SSEHeartbeatInterval time.Duration
+	// StreamBuffer makes chat completion streams resumable with
+	// Last-Event-ID (optional).
+	StreamBuffer    streaming.EventBuffer
+	StreamResumeTTL time.Duration // Default DefaultStreamResumeTTL
+	// DisableStreamPassthrough always decodes and re-encodes stream chunks,
+	// even when they could be forwarded unchanged.
+	DisableStreamPassthrough bool
+	// ReadinessRequiresProvider makes /health/ready fail while no
+	// deployment is out of cooldown.
+	ReadinessRequiresProvider bool
 }
 
 // NewClientHandler creates a new handler that wraps llmux.Client.
@@ -61,6 +93,15 @@ func NewClientHandlerWithSwapper(swapper *ClientSwapper, logger *slog.Logger, cf
 	var manager mcp.Manager
 	var obs *observability.ObservabilityManager
 	var gov *governance.Engine
+	var timeSeries *metrics.TimeSeries
+	var signer *provenance.Signer
+	var killSwitch *governance.KillSwitch
+	var payloads *auth.PayloadLogger
+	sseHeartbeat := DefaultSSEHeartbeatInterval
+	var streamBuffer streaming.EventBuffer
+	streamResumeTTL := DefaultStreamResumeTTL
+	readinessRequiresProvider := false
+	streamPassthrough := true
 	if cfg != nil {
 		if cfg.MaxBodySize > 0 {
 			maxBodySize = cfg.MaxBodySize
@@ -69,6 +110,19 @@ func NewClientHandlerWithSwapper(swapper *ClientSwapper, logger *slog.Logger, cf
 		manager = cfg.MCPManager
 		obs = cfg.Observability
 		gov = cfg.Governance
+		timeSeries = cfg.TimeSeries
+		signer = cfg.Signer
+		killSwitch = cfg.KillSwitch
+		payloads = cfg.PayloadLogger
+		if cfg.SSEHeartbeatInterval != 0 {
+			sseHeartbeat = cfg.SSEHeartbeatInterval
+		}
+		streamBuffer = cfg.StreamBuffer
+		if cfg.StreamResumeTTL > 0 {
+			streamResumeTTL = cfg.StreamResumeTTL
+		}
+		readinessRequiresProvider = cfg.ReadinessRequiresProvider
+		streamPassthrough = !cfg.DisableStreamPassthrough
 	}
 
 	return &ClientHandler{
@@ -79,6 +133,17 @@ func NewClientHandlerWithSwapper(swapper *ClientSwapper, logger *slog.Logger, cf
 		mcpManager:  manager,
 		obs:         obs,
 		governance:  gov,
+		timeSeries:  timeSeries,
+		signer:      signer,
+		killSwitch:  killSwitch,
+		payloads:    payloads,
+
+		sseHeartbeat:      sseHeartbeat,
+		streamBuffer:      streamBuffer,
+		streamResumeTTL:   streamResumeTTL,
+		streamPassthrough: streamPassthrough,
+
+		readinessRequiresProvider: readinessRequiresProvider,
 	}
 }
 
@@ -100,19 +165,25 @@ func (h *ClientHandler) getMCPManager(ctx context.Context) mcp.Manager {
 func (h *ClientHandler) ChatCompletions(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 	r, requestID := h.ensureRequestID(r)
+	r = h.applyRequestPriority(r)
+	r = applySessionID(r)
+	if lastEventID := r.Header.Get(lastEventIDHeader); lastEventID != "" && h.streamBuffer != nil {
+		h.resumeStream(w, r, lastEventID)
+		return
+	}
 
 	// Limit request body size to prevent OOM
 	limitedReader := io.LimitReader(r.Body, h.maxBodySize+1)
 	body, err := io.ReadAll(limitedReader)
 	if err != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "failed to read request body"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "failed to read request body"))
 		return
 	}
 	defer func() { _ = r.Body.Close() }()
 
 	// Check if body exceeded limit
 	if int64(len(body)) > h.maxBodySize {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "request body too large"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "request body too large"))
 		return
 	}
 
@@ -120,34 +191,90 @@ func (h *ClientHandler) ChatCompletions(w http.ResponseWriter, r *http.Request)
 	defer pool.PutChatRequest(req)
 
 	if unmarshalErr := json.Unmarshal(body, req); unmarshalErr != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "invalid JSON: "+unmarshalErr.Error()))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "invalid JSON: "+unmarshalErr.Error()))
+		return
+	}
+	req.Tags = requestTags(r, req.Tags)
+	if r, err = applyCacheControl(r, req); err != nil {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, "invalid cache field: "+err.Error()))
+		return
+	}
+	if r, err = applyStreamFormat(r, req); err != nil {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, "invalid stream_format: "+err.Error()))
+		return
+	}
+	if r, err = applyOutputTokenCap(r, req); err != nil {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, "invalid "+outputTokenCapField+": "+err.Error()))
 		return
 	}
+	capMaxTokens(r.Context(), req)
+	memoryExt, err := parseMemoryExtension(req)
+	if err != nil {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, "invalid memory field: "+err.Error()))
+		return
+	}
+	promptExt, err := parseMCPPromptExtension(req)
+	if err != nil {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, "invalid "+mcpPromptField+" field: "+err.Error()))
+		return
+	}
+	templateExt, err := parsePromptTemplateExtension(req)
+	if err != nil {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, "invalid prompt template: "+err.Error()))
+		return
+	}
+	prompt, err := h.applyPromptTemplate(r.Context(), req, templateExt)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	r = r.WithContext(withPromptRef(r.Context(), prompt))
 
 	// Validate request
 	if req.Model == "" {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "model is required"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "model is required"))
 		return
 	}
 	if validateErr := types.ValidateModelName(req.Model); validateErr != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", validateErr.Error()))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", validateErr.Error()))
+		return
+	}
+	if promptErr := h.applyMCPPrompt(r.Context(), req, promptExt); promptErr != nil {
+		h.writeError(w, r, promptErr)
 		return
 	}
 	if len(req.Messages) == 0 {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", req.Model, "messages is required"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, "messages is required"))
 		return
 	}
 
+	// Replay the stored response of a repeated Idempotency-Key instead of
+	// running and billing the request again.
+	if !req.Stream {
+		var (
+			done func()
+			ok   bool
+		)
+		if w, done, ok = h.beginIdempotentRequest(w, r, "chat", req.Model, body); !ok {
+			return
+		}
+		defer done()
+	}
+
 	payload := h.buildChatObservabilityPayload(r, req, start, requestID)
-	ctx, endSpan := h.startSpan(r.Context(), payload)
+	recordPromptRef(r.Context(), payload)
+	ctx, endSpan := h.startSpan(r, payload)
 	defer endSpan()
 	h.observePre(ctx, payload)
+	ctx, annotations := guardrails.WithAnnotations(ctx)
 
-	if evalErr := h.evaluateGovernance(ctx, r, req.Model, req.User, req.Tags, governance.CallTypeChatCompletion); evalErr != nil {
+	tags, evalErr := h.evaluateGovernance(ctx, r, req.Model, req.User, req.Tags, messagesContent(req.Messages), governance.CallTypeChatCompletion)
+	if evalErr != nil {
 		h.observePost(ctx, payload, evalErr)
-		h.writeError(w, evalErr)
+		h.writeError(w, r, evalErr)
 		return
 	}
+	req.Tags = tags
 
 	manager := h.getMCPManager(ctx)
 
@@ -156,10 +283,18 @@ func (h *ClientHandler) ChatCompletions(w http.ResponseWriter, r *http.Request)
 	if client == nil {
 		err := llmerrors.NewInternalError("", req.Model, "client not initialized")
 		h.observePost(ctx, payload, err)
-		h.writeError(w, err)
+		h.writeError(w, r, err)
 		return
 	}
 
+	chatReq, err := h.applyMemoryRetrieval(ctx, client, req, memoryExt, payload)
+	if err != nil {
+		h.observePost(ctx, payload, err)
+		h.writeError(w, r, err)
+		return
+	}
+	req = chatReq
+
 	// Handle streaming response
 	if req.Stream {
 		if manager != nil {
@@ -168,17 +303,14 @@ func (h *ClientHandler) ChatCompletions(w http.ResponseWriter, r *http.Request)
 			}
 		}
 
-		h.handleStreamResponse(ctx, w, r, client, req, start, requestID, payload)
+		h.handleStreamResponse(ctx, w, r, client, req, body, start, requestID, payload, annotations)
 		return
 	}
 
 	// Non-streaming request - use Client.ChatCompletion
 	var resp *llmux.ChatResponse
 	if manager != nil {
-		executor := mcp.NewAgentExecutor(manager, 0, h.logger)
-		resp, err = executor.Execute(ctx, req, func(execCtx context.Context, r *llmux.ChatRequest) (*llmux.ChatResponse, error) {
-			return client.ChatCompletion(execCtx, r)
-		})
+		resp, err = h.runAgentLoop(ctx, manager, client, req, requestID)
 	} else {
 		resp, err = client.ChatCompletion(ctx, req)
 	}
@@ -186,10 +318,11 @@ func (h *ClientHandler) ChatCompletions(w http.ResponseWriter, r *http.Request)
 		h.observePost(ctx, payload, err)
 		h.logger.Error("chat completion failed", "model", req.Model, "error", err)
 		if llmErr, ok := err.(*llmerrors.LLMError); ok {
-			h.writeError(w, llmErr)
+			h.writeError(w, r, llmErr)
 		} else {
-			h.writeError(w, llmerrors.NewServiceUnavailableError("", req.Model, "upstream request failed"))
+			h.writeError(w, r, llmerrors.NewServiceUnavailableError("", req.Model, "upstream request failed"))
 		}
+		h.retainPayload(ctx, requestID, req.Model, governance.CallTypeChatCompletion, body, nil, err)
 		return
 	}
 
@@ -237,10 +370,17 @@ func (h *ClientHandler) ChatCompletions(w http.ResponseWriter, r *http.Request)
 		Latency: latency,
 	})
 
+	if err := h.evaluateCompletion(ctx, req.Model, responseContent(resp)); err != nil {
+		h.observePost(ctx, payload, err)
+		h.writeError(w, r, err)
+		return
+	}
+
 	if resp.Usage != nil {
 		payload.PromptTokens = resp.Usage.PromptTokens
 		payload.CompletionTokens = resp.Usage.CompletionTokens
 		payload.TotalTokens = resp.Usage.TotalTokens
+		payload.ReasoningTokens = resp.Usage.ReasoningTokens()
 		payload.ResponseCost = cost
 		if resp.Usage.Provider != "" {
 			payload.APIProvider = resp.Usage.Provider
@@ -257,27 +397,41 @@ func (h *ClientHandler) ChatCompletions(w http.ResponseWriter, r *http.Request)
 	h.observePost(ctx, payload, nil)
 
 	// Write response
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		h.logger.Error("failed to encode response", "error", err)
-	}
+	setCacheStatusHeader(w, resp.CacheStatus)
+	setStructuredOutputHeader(w, resp.StructuredOutput)
+	setGuardrailsHeader(w, annotations)
+	h.writeJSONResponse(w, resp, responseClaims(requestID, resp.Model, resp.Usage, resp.SystemFingerprint))
+	h.retainPayload(ctx, requestID, modelName, governance.CallTypeChatCompletion, body, resp, nil)
 }
 
-func (h *ClientHandler) handleStreamResponse(ctx context.Context, w http.ResponseWriter, r *http.Request, client *llmux.Client, req *llmux.ChatRequest, start time.Time, requestID string, payload *observability.StandardLoggingPayload) {
+func (h *ClientHandler) handleStreamResponse(ctx context.Context, w http.ResponseWriter, r *http.Request, client *llmux.Client, req *llmux.ChatRequest, body []byte, start time.Time, requestID string, payload *observability.StandardLoggingPayload, annotations *guardrails.Annotations) {
+	// A resumable stream keeps generating into its buffer after the client
+	// disconnects, so it must outlive the request context.
+	resumable := h.startResumableStream(ctx, r, requestID)
+	if resumable != nil {
+		ctx = context.WithoutCancel(ctx)
+		defer resumable.finish(ctx)
+	}
+
 	stream, err := client.ChatCompletionStream(ctx, req)
 	if err != nil {
 		h.observePost(ctx, payload, err)
 		h.logger.Error("stream creation failed", "model", req.Model, "error", err)
 		if llmErr, ok := err.(*llmerrors.LLMError); ok {
-			h.writeError(w, llmErr)
+			h.writeError(w, r, llmErr)
 		} else {
-			h.writeError(w, llmerrors.NewServiceUnavailableError("", req.Model, "upstream request failed"))
+			h.writeError(w, r, llmerrors.NewServiceUnavailableError("", req.Model, "upstream request failed"))
 		}
+		h.retainPayload(ctx, requestID, req.Model, governance.CallTypeChatCompletion, body, nil, err)
 		return
 	}
 	defer func() { _ = stream.Close() }()
 
 	// Set SSE headers
+	setGuardrailsHeader(w, annotations)
+	if req.ResponseFormat != nil && req.ResponseFormat.Type == "json_schema" {
+		w.Header().Set("Trailer", structuredOutputHeader)
+	}
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
@@ -285,23 +439,63 @@ func (h *ClientHandler) handleStreamResponse(ctx context.Context, w http.Respons
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
-		h.writeError(w, llmerrors.NewInternalError("", req.Model, "streaming not supported"))
+		h.writeError(w, r, llmerrors.NewInternalError("", req.Model, "streaming not supported"))
 		return
 	}
+	sse := newSSEWriter(w, flusher, h.sseHeartbeat)
+	defer sse.Close()
 
 	var finalUsage *llmux.Usage
-	var completionContent strings.Builder
+	var completionContent, reasoningContent strings.Builder
 	var streamErr error
+	var clientGone bool
+	var partial *llmux.PartialJSONStream
+	if jsonMergePatchRequested(ctx) {
+		partial = llmux.NewPartialJSONStream()
+	}
+
+	outputCap := newOutputTokenCounter(req.Model, outputTokenCap(ctx))
+	writeDone := func() {
+		eventID := resumable.append(ctx, sseDone)
+		if clientGone {
+			return
+		}
+		if writeErr := sse.WriteDone(eventID); writeErr != nil {
+			h.logger.Debug("failed to write done marker", "error", writeErr)
+		}
+	}
+
+	piped := h.streamPassthrough && resumable == nil && partial == nil && outputCap == nil && stream.CanPipe()
+	if piped {
+		// Fast path: forward the upstream events without re-encoding them.
+		result, err := stream.Pipe(func(data []byte) error {
+			recordFirstToken(payload, stream)
+			if h.obs != nil {
+				// Checked here so the payload is not boxed when unobserved.
+				h.observeStreamEvent(ctx, payload, json.RawMessage(data))
+			}
+			return sse.WriteEvent("", data)
+		})
+		finalUsage = result.Usage
+		completionContent.WriteString(result.Content)
+		if err != nil {
+			streamErr = err
+			if r.Context().Err() != nil {
+				h.logger.Debug("client disconnected during stream", "model", req.Model)
+			} else {
+				h.logger.Error("stream pipe error", "error", err, "model", req.Model)
+			}
+		} else {
+			writeDone()
+		}
+	}
 
 	// Forward stream chunks
-	for {
+	for !piped {
 		chunk, err := stream.Recv()
 		if err == io.EOF {
 			// Send [DONE] marker
-			if _, writeErr := w.Write([]byte("data: [DONE]\n\n")); writeErr != nil {
-				h.logger.Debug("failed to write done marker", "error", writeErr)
-			}
-			flusher.Flush()
+			writeDone()
 			break
 		}
 		if err != nil {
@@ -315,6 +509,16 @@ func (h *ClientHandler) handleStreamResponse(ctx context.Context, w http.Respons
 			break
 		}
 
+		recordFirstToken(payload, stream)
+
+		// Replace the chunk that would exceed the output token cap with a
+		// synthetic length finish, and stop the upstream generation.
+		truncated := outputCap.add(chunk)
+		if truncated {
+			chunk = lengthFinishChunk(chunk)
+			_ = stream.Close()
+			h.logger.Info("stream cut off at output token cap", "model", req.Model, "cap", outputCap.limit)
+		}
 		h.observeStreamEvent(ctx, payload, chunk)
 
 		// Capture usage if present (OpenAI standard puts it in the last chunk)
@@ -325,30 +529,48 @@ func (h *ClientHandler) handleStreamResponse(ctx context.Context, w http.Respons
 		// Accumulate content for fallback token calculation
 		if len(chunk.Choices) > 0 {
 			completionContent.WriteString(chunk.Choices[0].Delta.Content)
+			if reasoning := chunk.Choices[0].Delta.Reasoning; reasoning != nil {
+				reasoningContent.WriteString(reasoning.Content)
+			}
 		}
 
-		// Marshal and send chunk
-		data, marshalErr := json.Marshal(chunk)
+		// Marshal and send chunk, as a merge patch when requested
+		var out any = chunk
+		if partial != nil {
+			patch, emit := toPatchChunk(partial, chunk)
+			if !emit {
+				continue
+			}
+			out = patch
+		}
+		buf, data, marshalErr := encodeEvent(out)
 		if marshalErr != nil {
 			h.logger.Error("failed to marshal chunk", "error", marshalErr)
 			continue
 		}
 
-		if _, writeErr := w.Write([]byte("data: ")); writeErr != nil {
-			streamErr = writeErr
-			break
+		eventID := resumable.append(ctx, data)
+		var writeErr error
+		if !clientGone {
+			writeErr = sse.WriteEvent(eventID, data)
 		}
-		if _, writeErr := w.Write(data); writeErr != nil {
-			streamErr = writeErr
-			break
+		buf.release()
+		if writeErr != nil {
+			if resumable == nil {
+				streamErr = writeErr
+				break
+			}
+			// Keep buffering for the client to resume.
+			clientGone = true
 		}
-		if _, writeErr := w.Write([]byte("\n\n")); writeErr != nil {
-			streamErr = writeErr
+		if truncated {
+			writeDone()
 			break
 		}
-		flusher.Flush()
 	}
 
+	setStructuredOutputHeader(w, stream.StructuredOutput())
+
 	// Record metrics
 	latency := time.Since(start)
 	metrics.RecordRequest("llmux", req.Model, http.StatusOK, latency)
@@ -357,10 +579,14 @@ func (h *ClientHandler) handleStreamResponse(ctx context.Context, w http.Respons
 	if finalUsage == nil {
 		promptTokens := tokenizer.EstimatePromptTokens(req.Model, req)
 		completionTokens := tokenizer.EstimateCompletionTokensFromText(req.Model, completionContent.String())
+		reasoningTokens := tokenizer.CountTextTokens(req.Model, reasoningContent.String())
 		finalUsage = &llmux.Usage{
 			PromptTokens:     promptTokens,
-			CompletionTokens: completionTokens,
-			TotalTokens:      promptTokens + completionTokens,
+			CompletionTokens: completionTokens + reasoningTokens,
+			TotalTokens:      promptTokens + completionTokens + reasoningTokens,
+		}
+		if reasoningTokens > 0 {
+			finalUsage.CompletionTokensDetails = &llmux.CompletionTokensDetails{ReasoningTokens: reasoningTokens}
 		}
 	}
 
@@ -386,11 +612,17 @@ func (h *ClientHandler) handleStreamResponse(ctx context.Context, w http.Respons
 		Latency: latency,
 	})
 
+	// The stream has already been delivered; a violation is only audited.
+	if err := h.evaluateCompletion(ctx, req.Model, completionContent.String()); err != nil {
+		h.logger.Warn("streamed completion violated content policy", "model", req.Model, "error", err)
+	}
+
 	if payload != nil {
 		if finalUsage != nil {
 			payload.PromptTokens = finalUsage.PromptTokens
 			payload.CompletionTokens = finalUsage.CompletionTokens
 			payload.TotalTokens = finalUsage.TotalTokens
+			payload.ReasoningTokens = finalUsage.ReasoningTokens()
 			payload.ResponseCost = cost
 			if finalUsage.Provider != "" {
 				payload.APIProvider = finalUsage.Provider
@@ -402,17 +634,20 @@ func (h *ClientHandler) handleStreamResponse(ctx context.Context, w http.Respons
 		payload.Response = completionContent.String()
 	}
 	h.observePost(ctx, payload, streamErr)
+	h.retainPayload(ctx, requestID, req.Model, governance.CallTypeChatCompletion, body,
+		streamedPayload{Content: completionContent.String(), Usage: finalUsage}, streamErr)
 }
 
 // Completions handles POST /v1/completions requests.
 func (h *ClientHandler) Completions(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 	r, requestID := h.ensureRequestID(r)
+	r = h.applyRequestPriority(r)
 
 	client, release := h.acquireClient()
 	defer release()
 	if client == nil {
-		h.writeError(w, llmerrors.NewInternalError("", "", "client not initialized"))
+		h.writeError(w, r, llmerrors.NewInternalError("", "", "client not initialized"))
 		return
 	}
 
@@ -420,39 +655,49 @@ func (h *ClientHandler) Completions(w http.ResponseWriter, r *http.Request) {
 	limitedReader := io.LimitReader(r.Body, h.maxBodySize+1)
 	body, err := io.ReadAll(limitedReader)
 	if err != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "failed to read request body"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "failed to read request body"))
 		return
 	}
 	defer func() { _ = r.Body.Close() }()
 
 	// Check if body exceeded limit
 	if int64(len(body)) > h.maxBodySize {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "request body too large"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "request body too large"))
 		return
 	}
 
 	var req types.CompletionRequest
 	if unmarshalErr := json.Unmarshal(body, &req); unmarshalErr != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "invalid JSON: "+unmarshalErr.Error()))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "invalid JSON: "+unmarshalErr.Error()))
 		return
 	}
 
 	if validateErr := req.Validate(); validateErr != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", req.Model, validateErr.Error()))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, validateErr.Error()))
 		return
 	}
 
 	chatReq, err := req.ToChatRequest()
 	if err != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", req.Model, err.Error()))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, err.Error()))
 		return
 	}
 
-	if evalErr := h.evaluateGovernance(r.Context(), r, chatReq.Model, req.User, nil, governance.CallTypeCompletion); evalErr != nil {
-		h.writeError(w, evalErr)
+	chatReq.Tags = requestTags(r, chatReq.Tags)
+	if r, err = applyCacheControl(r, chatReq); err != nil {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, "invalid cache field: "+err.Error()))
 		return
 	}
 
+	capMaxTokens(r.Context(), chatReq)
+
+	tags, evalErr := h.evaluateGovernance(r.Context(), r, chatReq.Model, req.User, chatReq.Tags, messagesContent(chatReq.Messages), governance.CallTypeCompletion)
+	if evalErr != nil {
+		h.writeError(w, r, evalErr)
+		return
+	}
+	chatReq.Tags = tags
+
 	// Handle streaming response
 	if chatReq.Stream {
 		h.handleCompletionStreamResponse(w, r, client, chatReq, start, requestID)
@@ -463,9 +708,9 @@ func (h *ClientHandler) Completions(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		h.logger.Error("completion failed", "model", req.Model, "error", err)
 		if llmErr, ok := err.(*llmerrors.LLMError); ok {
-			h.writeError(w, llmErr)
+			h.writeError(w, r, llmErr)
 		} else {
-			h.writeError(w, llmerrors.NewServiceUnavailableError("", req.Model, "upstream request failed"))
+			h.writeError(w, r, llmerrors.NewServiceUnavailableError("", req.Model, "upstream request failed"))
 		}
 		return
 	}
@@ -510,10 +755,13 @@ func (h *ClientHandler) Completions(w http.ResponseWriter, r *http.Request) {
 		Latency: latency,
 	})
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(completionResp); err != nil {
-		h.logger.Error("failed to encode response", "error", err)
+	if err := h.evaluateCompletion(r.Context(), req.Model, responseContent(resp)); err != nil {
+		h.writeError(w, r, err)
+		return
 	}
+
+	setCacheStatusHeader(w, resp.CacheStatus)
+	h.writeJSONResponse(w, completionResp, responseClaims(requestID, completionResp.Model, completionResp.Usage, completionResp.SystemFingerprint))
 }
 
 func (h *ClientHandler) handleCompletionStreamResponse(w http.ResponseWriter, r *http.Request, client *llmux.Client, req *llmux.ChatRequest, start time.Time, requestID string) {
@@ -521,9 +769,9 @@ func (h *ClientHandler) handleCompletionStreamResponse(w http.ResponseWriter, r
 	if err != nil {
 		h.logger.Error("stream creation failed", "model", req.Model, "error", err)
 		if llmErr, ok := err.(*llmerrors.LLMError); ok {
-			h.writeError(w, llmErr)
+			h.writeError(w, r, llmErr)
 		} else {
-			h.writeError(w, llmerrors.NewServiceUnavailableError("", req.Model, "upstream request failed"))
+			h.writeError(w, r, llmerrors.NewServiceUnavailableError("", req.Model, "upstream request failed"))
 		}
 		return
 	}
@@ -537,9 +785,11 @@ func (h *ClientHandler) handleCompletionStreamResponse(w http.ResponseWriter, r
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
-		h.writeError(w, llmerrors.NewInternalError("", req.Model, "streaming not supported"))
+		h.writeError(w, r, llmerrors.NewInternalError("", req.Model, "streaming not supported"))
 		return
 	}
+	sse := newSSEWriter(w, flusher, h.sseHeartbeat)
+	defer sse.Close()
 
 	var finalUsage *llmux.Usage
 	var completionContent strings.Builder
@@ -547,10 +797,9 @@ func (h *ClientHandler) handleCompletionStreamResponse(w http.ResponseWriter, r
 	for {
 		chunk, err := stream.Recv()
 		if err == io.EOF {
-			if _, writeErr := w.Write([]byte("data: [DONE]\n\n")); writeErr != nil {
+			if writeErr := sse.WriteDone(""); writeErr != nil {
 				h.logger.Debug("failed to write done marker", "error", writeErr)
 			}
-			flusher.Flush()
 			break
 		}
 		if err != nil {
@@ -571,22 +820,17 @@ func (h *ClientHandler) handleCompletionStreamResponse(w http.ResponseWriter, r
 		}
 
 		converted := types.CompletionStreamChunkFromChat(chunk)
-		data, marshalErr := json.Marshal(converted)
+		buf, data, marshalErr := encodeEvent(converted)
 		if marshalErr != nil {
 			h.logger.Error("failed to marshal chunk", "error", marshalErr)
 			continue
 		}
 
-		if _, writeErr := w.Write([]byte("data: ")); writeErr != nil {
+		writeErr := sse.WriteEvent("", data)
+		buf.release()
+		if writeErr != nil {
 			break
 		}
-		if _, writeErr := w.Write(data); writeErr != nil {
-			break
-		}
-		if _, writeErr := w.Write([]byte("\n\n")); writeErr != nil {
-			break
-		}
-		flusher.Flush()
 	}
 
 	latency := time.Since(start)
@@ -630,7 +874,9 @@ func firstCompletionText(resp *types.CompletionResponse) string {
 	return resp.Choices[0].Text
 }
 
-func (h *ClientHandler) writeError(w http.ResponseWriter, err error) {
+// writeError writes an OpenAI-compatible error. When the caller's team or key
+// configures error templates, the message is rendered through them.
+func (h *ClientHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
 	var llmErr *llmerrors.LLMError
 	if e, ok := err.(*llmerrors.LLMError); ok {
 		llmErr = e
@@ -644,10 +890,18 @@ func (h *ClientHandler) writeError(w http.ResponseWriter, err error) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(llmErr.HTTPStatusCode())
 
+	message := llmErr.Message
+	if r != nil {
+		if tmplCfg := errorTemplateConfigFromContext(r.Context()); tmplCfg != nil {
+			message = tmplCfg.render(r, llmErr)
+		}
+	}
+
 	resp := ErrorResponse{
 		Error: ErrorDetail{
-			Message: llmErr.Message,
+			Message: message,
 			Type:    llmErr.Type,
+			Code:    llmErr.Code,
 		},
 	}
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
@@ -655,13 +909,27 @@ func (h *ClientHandler) writeError(w http.ResponseWriter, err error) {
 	}
 }
 
-func (h *ClientHandler) evaluateGovernance(ctx context.Context, r *http.Request, model, endUser string, tags []string, callType string) error {
+// evaluateGovernance enforces host, model access and governance policy. It
+// returns the request tags extended with any routing tags required by content
+// policies.
+func (h *ClientHandler) evaluateGovernance(ctx context.Context, r *http.Request, model, endUser string, tags []string, content, callType string) ([]string, error) {
+	if err := h.checkKillSwitch(ctx, model); err != nil {
+		return tags, err
+	}
+
+	if tenant := auth.HostTenantFromContext(ctx); tenant != nil && model != "" {
+		_, canonicalModel := types.SplitProviderModel(model)
+		if !tenant.AllowsModel(model) && !tenant.AllowsModel(canonicalModel) {
+			return tags, llmerrors.NewPermissionError("gateway", model, "model not available on this host")
+		}
+	}
+
 	authCtx := auth.GetAuthContext(ctx)
 	if authCtx != nil && h.store != nil && model != "" {
 		access, err := auth.NewModelAccess(ctx, h.store, authCtx)
 		if err != nil {
 			h.logger.Error("failed to evaluate model access", "error", err)
-			return llmerrors.NewInternalError("gateway", model, "failed to evaluate model access")
+			return tags, llmerrors.NewInternalError("gateway", model, "failed to evaluate model access")
 		}
 		if access != nil {
 			_, canonicalModel := types.SplitProviderModel(model)
@@ -670,29 +938,94 @@ func (h *ClientHandler) evaluateGovernance(ctx context.Context, r *http.Request,
 				allows = allows || access.Allows(canonicalModel)
 			}
 			if !allows {
-				return llmerrors.NewPermissionError("gateway", model, "model access denied")
+				return tags, llmerrors.NewPermissionError("gateway", model, "model access denied")
 			}
 		}
 	}
 
 	if h.governance == nil {
-		return nil
+		return tags, nil
 	}
-	return h.governance.Evaluate(ctx, governance.RequestInput{
+	decision, err := h.governance.EvaluateRequest(ctx, governance.RequestInput{
 		Request:   r,
 		Model:     model,
 		CallType:  callType,
 		EndUserID: endUser,
 		Tags:      tags,
+		Content:   content,
 	})
+	if err != nil {
+		return tags, err
+	}
+	for _, tag := range decision.RouteTags {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// evaluateCompletion checks a completion against the caller's team banned
+// content policy.
+func (h *ClientHandler) evaluateCompletion(ctx context.Context, model, content string) error {
+	if h.governance == nil {
+		return nil
+	}
+	return h.governance.EvaluateCompletion(ctx, model, content)
+}
+
+// responseContent joins the message text of every choice in resp.
+func responseContent(resp *llmux.ChatResponse) string {
+	if resp == nil {
+		return ""
+	}
+	messages := make([]types.ChatMessage, 0, len(resp.Choices))
+	for _, choice := range resp.Choices {
+		messages = append(messages, choice.Message)
+	}
+	return messagesContent(messages)
+}
+
+// messagesContent joins the text of chat messages for content classification.
+func messagesContent(messages []types.ChatMessage) string {
+	var b strings.Builder
+	for _, msg := range messages {
+		text := msg.TextContent()
+		if text == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// embeddingContent joins the text inputs of an embedding request.
+func embeddingContent(input *types.EmbeddingInput) string {
+	if input == nil {
+		return ""
+	}
+	if input.Text != nil {
+		return *input.Text
+	}
+	return strings.Join(input.Texts, "\n")
 }
 
 func (h *ClientHandler) accountUsage(ctx context.Context, input governance.AccountInput) {
-	if h.governance != nil {
-		h.governance.Account(ctx, input)
+	input.Metadata = promptRefMetadata(ctx, input.Metadata)
+	recordUsage(ctx, h.governance, h.store, h.logger, input)
+}
+
+// recordUsage accounts a call against the key of ctx through gov, or logs
+// usage and spend to store directly when governance is disabled.
+func recordUsage(ctx context.Context, gov *governance.Engine, store auth.Store, logger *slog.Logger, input governance.AccountInput) {
+	if gov != nil {
+		gov.Account(ctx, input)
 		return
 	}
-	if h.store == nil {
+	if store == nil {
 		return
 	}
 
@@ -711,6 +1044,7 @@ func (h *ClientHandler) accountUsage(ctx context.Context, input governance.Accou
 		LatencyMs:    int(input.Latency.Milliseconds()),
 		RequestTags:  append([]string(nil), input.RequestTags...),
 		CacheHit:     nil,
+		Metadata:     auth.Metadata(input.Metadata),
 	}
 	if log.Provider == "" {
 		log.Provider = "llmux"
@@ -735,24 +1069,24 @@ func (h *ClientHandler) accountUsage(ctx context.Context, input governance.Accou
 		bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		if err := h.store.LogUsage(bgCtx, log); err != nil {
-			h.logger.Warn("failed to log usage", "error", err, "request_id", input.RequestID)
+		if err := store.LogUsage(bgCtx, log); err != nil {
+			logger.Warn("failed to log usage", "error", err, "request_id", input.RequestID)
 		}
 
 		if authCtx != nil && authCtx.APIKey != nil && log.Cost > 0 {
-			if err := h.store.UpdateAPIKeySpent(bgCtx, authCtx.APIKey.ID, log.Cost); err != nil {
-				h.logger.Warn("failed to update api key spend", "error", err, "key_id", authCtx.APIKey.ID)
+			if err := store.UpdateAPIKeySpent(bgCtx, authCtx.APIKey.ID, log.Cost); err != nil {
+				logger.Warn("failed to update api key spend", "error", err, "key_id", authCtx.APIKey.ID)
 			}
 			if authCtx.APIKey.TeamID != nil {
-				if err := h.store.UpdateTeamSpent(bgCtx, *authCtx.APIKey.TeamID, log.Cost); err != nil {
-					h.logger.Warn("failed to update team spend", "error", err, "team_id", *authCtx.APIKey.TeamID)
+				if err := store.UpdateTeamSpent(bgCtx, *authCtx.APIKey.TeamID, log.Cost); err != nil {
+					logger.Warn("failed to update team spend", "error", err, "team_id", *authCtx.APIKey.TeamID)
 				}
 			}
 		}
 	}()
 }
 
-// HealthCheck handles GET /health/live and /health/ready endpoints.
+// HealthCheck handles GET /health/live endpoint.
 func (h *ClientHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -761,18 +1095,55 @@ func (h *ClientHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ReadinessCheck handles GET /health/ready endpoint. When readiness
+// requires a provider, it returns 503 while every deployment is in
+// cooldown so load balancers route around the replica.
+func (h *ClientHandler) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	if !h.readinessRequiresProvider {
+		h.HealthCheck(w, r)
+		return
+	}
+
+	client, release := h.acquireClient()
+	defer release()
+	total, healthy := 0, 0
+	if client != nil {
+		now := time.Now()
+		for _, deployment := range client.ListDeployments() {
+			total++
+			if stats := client.GetStats(deployment.ID); stats == nil || !stats.CooldownUntil.After(now) {
+				healthy++
+			}
+		}
+	}
+
+	status, code := "ok", http.StatusOK
+	if healthy == 0 {
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(map[string]any{
+		"status":              status,
+		"deployments":         total,
+		"healthy_deployments": healthy,
+	}); err != nil {
+		h.logger.Error("failed to encode health response", "error", err)
+	}
+}
+
 // ListModels handles GET /v1/models endpoint.
 func (h *ClientHandler) ListModels(w http.ResponseWriter, r *http.Request) {
 	client, release := h.acquireClient()
 	defer release()
 	if client == nil {
-		h.writeError(w, llmerrors.NewInternalError("", "", "client not initialized"))
+		h.writeError(w, r, llmerrors.NewInternalError("", "", "client not initialized"))
 		return
 	}
 
 	models, err := client.ListModels(r.Context())
 	if err != nil {
-		h.writeError(w, llmerrors.NewInternalError("", "", "failed to list models: "+err.Error()))
+		h.writeError(w, r, llmerrors.NewInternalError("", "", "failed to list models: "+err.Error()))
 		return
 	}
 
@@ -781,7 +1152,7 @@ func (h *ClientHandler) ListModels(w http.ResponseWriter, r *http.Request) {
 		access, err := auth.NewModelAccess(r.Context(), h.store, authCtx)
 		if err != nil {
 			h.logger.Error("failed to evaluate model access", "error", err)
-			h.writeError(w, llmerrors.NewInternalError("", "", "failed to evaluate model access"))
+			h.writeError(w, r, llmerrors.NewInternalError("", "", "failed to evaluate model access"))
 			return
 		}
 		if access != nil {
@@ -795,6 +1166,16 @@ func (h *ClientHandler) ListModels(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if tenant := auth.HostTenantFromContext(r.Context()); tenant != nil {
+		filtered := models[:0]
+		for _, model := range models {
+			if tenant.AllowsModel(model.ID) {
+				filtered = append(filtered, model)
+			}
+		}
+		models = filtered
+	}
+
 	// Convert to OpenAI format
 	data := make([]map[string]any, 0, len(models))
 	for _, m := range models {
@@ -827,63 +1208,67 @@ func (h *ClientHandler) GetClient() *llmux.Client {
 func (h *ClientHandler) Embeddings(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 	r, requestID := h.ensureRequestID(r)
+	r = h.applyRequestPriority(r)
 
 	// Limit request body size to prevent OOM
 	limitedReader := io.LimitReader(r.Body, h.maxBodySize+1)
 	body, err := io.ReadAll(limitedReader)
 	if err != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "failed to read request body"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "failed to read request body"))
 		return
 	}
 	defer func() { _ = r.Body.Close() }()
 
 	// Check if body exceeded limit
 	if int64(len(body)) > h.maxBodySize {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "request body too large"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "request body too large"))
 		return
 	}
 
 	var req types.EmbeddingRequest
 	if unmarshalErr := json.Unmarshal(body, &req); unmarshalErr != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "invalid JSON: "+unmarshalErr.Error()))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "invalid JSON: "+unmarshalErr.Error()))
 		return
 	}
+	req.Tags = requestTags(r, req.Tags)
 
 	// Validate request
 	if req.Model == "" {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "model is required"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "model is required"))
 		return
 	}
 	if validateErr := types.ValidateModelName(req.Model); validateErr != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", validateErr.Error()))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", validateErr.Error()))
 		return
 	}
 	if req.Input == nil || req.Input.IsEmpty() {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", req.Model, "input is required"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, "input is required"))
 		return
 	}
 	if validateErr := req.Input.Validate(); validateErr != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", req.Model, validateErr.Error()))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, validateErr.Error()))
 		return
 	}
 
 	payload := h.buildEmbeddingObservabilityPayload(r, &req, start, requestID)
-	ctx, endSpan := h.startSpan(r.Context(), payload)
+	ctx, endSpan := h.startSpan(r, payload)
 	defer endSpan()
 	h.observePre(ctx, payload)
 
-	if evalErr := h.evaluateGovernance(ctx, r, req.Model, req.User, nil, governance.CallTypeEmbedding); evalErr != nil {
+	tags, evalErr := h.evaluateGovernance(ctx, r, req.Model, req.User, req.Tags, embeddingContent(req.Input), governance.CallTypeEmbedding)
+	if evalErr != nil {
 		h.observePost(ctx, payload, evalErr)
-		h.writeError(w, evalErr)
+		h.writeError(w, r, evalErr)
 		return
 	}
+	req.Tags = tags
 
 	client, release := h.acquireClient()
 	defer release()
 	if client == nil {
 		err := llmerrors.NewInternalError("", req.Model, "client not initialized")
 		h.observePost(ctx, payload, err)
-		h.writeError(w, err)
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -893,9 +1278,9 @@ func (h *ClientHandler) Embeddings(w http.ResponseWriter, r *http.Request) {
 		h.observePost(ctx, payload, err)
 		h.logger.Error("embedding failed", "model", req.Model, "error", err)
 		if llmErr, ok := err.(*llmerrors.LLMError); ok {
-			h.writeError(w, llmErr)
+			h.writeError(w, r, llmErr)
 		} else {
-			h.writeError(w, llmerrors.NewServiceUnavailableError("", req.Model, "upstream request failed"))
+			h.writeError(w, r, llmerrors.NewServiceUnavailableError("", req.Model, "upstream request failed"))
 		}
 		return
 	}
@@ -951,13 +1336,56 @@ func (h *ClientHandler) Embeddings(w http.ResponseWriter, r *http.Request) {
 	h.observePost(ctx, payload, nil)
 
 	// Write response
+	h.writeJSONResponse(w, resp, responseClaims(requestID, resp.Model, &resp.Usage, ""))
+}
+
+// writeJSONResponse writes resp as JSON. When response signing is enabled it
+// attaches provenance headers computed over the exact bytes written.
+func (h *ClientHandler) writeJSONResponse(w http.ResponseWriter, resp any, claims provenance.Claims) {
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
+	if h.signer == nil {
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			h.logger.Error("failed to encode response", "error", err)
+		}
+		return
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
 		h.logger.Error("failed to encode response", "error", err)
+		return
+	}
+	body = append(body, '\n')
+	prov, sig, err := h.signer.Sign(body, claims)
+	if err != nil {
+		h.logger.Error("failed to sign response", "error", err)
+	} else {
+		w.Header().Set(provenance.HeaderProvenance, prov)
+		w.Header().Set(provenance.HeaderSignature, sig)
+	}
+	if _, err := w.Write(body); err != nil {
+		h.logger.Error("failed to write response", "error", err)
 	}
 }
 
-func (h *ClientHandler) startSpan(ctx context.Context, payload *observability.StandardLoggingPayload) (context.Context, func()) {
+func responseClaims(requestID, model string, usage *types.Usage, fingerprint string) provenance.Claims {
+	claims := provenance.Claims{
+		RequestID:         requestID,
+		Model:             model,
+		SystemFingerprint: fingerprint,
+	}
+	if usage != nil {
+		claims.Provider = usage.Provider
+		claims.Deployment = usage.Deployment
+	}
+	return claims
+}
+
+// startSpan starts the gateway span for r. A W3C traceparent sent by the
+// caller is continued even when tracing is disabled, so upstream provider
+// requests still join the caller's trace.
+func (h *ClientHandler) startSpan(r *http.Request, payload *observability.StandardLoggingPayload) (context.Context, func()) {
+	ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
 	if h.obs == nil || payload == nil {
 		return ctx, func() {}
 	}
@@ -984,6 +1412,67 @@ func (h *ClientHandler) ensureRequestID(r *http.Request) (*http.Request, string)
 	return r, requestID
 }
 
+// applyRequestPriority honors the X-LLMux-Priority header. The header can only
+// lower the priority granted by the API key, so callers cannot jump the queue.
+func (h *ClientHandler) applyRequestPriority(r *http.Request) *http.Request {
+	requested, ok := llmux.ParsePriority(r.Header.Get(priorityHeader))
+	if !ok {
+		return r
+	}
+	if granted := llmux.RequestPriority(r.Context()); requested > granted {
+		requested = granted
+	}
+	return r.WithContext(llmux.WithRequestPriority(r.Context(), requested))
+}
+
+// applySessionID attaches the X-Session-ID header to the request context so
+// the client replays and records the session's history.
+func applySessionID(r *http.Request) *http.Request {
+	sessionID := strings.TrimSpace(r.Header.Get(sessionIDHeader))
+	if sessionID == "" {
+		return r
+	}
+	return r.WithContext(llmux.WithSessionID(r.Context(), sessionID))
+}
+
+// requestTags merges header tags into the body tags and falls back to the
+// default tags of the host tenant when the request carries none.
+func requestTags(r *http.Request, tags []string) []string {
+	tags = mergeHeaderTags(r, tags)
+	if len(tags) > 0 {
+		return tags
+	}
+	if tenant := auth.HostTenantFromContext(r.Context()); tenant != nil {
+		return append([]string(nil), tenant.DefaultTags...)
+	}
+	return tags
+}
+
+// mergeHeaderTags appends tags from the X-LLMux-Tags header to the body tags,
+// skipping blanks and duplicates.
+func mergeHeaderTags(r *http.Request, tags []string) []string {
+	header := r.Header.Get(tagsHeader)
+	if strings.TrimSpace(header) == "" {
+		return tags
+	}
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		seen[tag] = struct{}{}
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
 func (h *ClientHandler) observePre(ctx context.Context, payload *observability.StandardLoggingPayload) {
 	if h.obs == nil || payload == nil {
 		return
@@ -992,10 +1481,14 @@ func (h *ClientHandler) observePre(ctx context.Context, payload *observability.S
 }
 
 func (h *ClientHandler) observePost(ctx context.Context, payload *observability.StandardLoggingPayload, err error) {
-	if h.obs == nil || payload == nil {
+	if payload == nil {
 		return
 	}
 	payload.EndTime = time.Now()
+	h.recordTimeSeries(payload, err)
+	if h.obs == nil {
+		return
+	}
 	if err != nil {
 		payload.Status = observability.RequestStatusFailure
 		errStr := err.Error()
@@ -1013,6 +1506,37 @@ func (h *ClientHandler) observePost(ctx context.Context, payload *observability.
 	h.obs.LogSuccess(ctx, payload)
 }
 
+func (h *ClientHandler) recordTimeSeries(payload *observability.StandardLoggingPayload, err error) {
+	if h.timeSeries == nil {
+		return
+	}
+	sample := metrics.UsageSample{
+		Time:             payload.EndTime,
+		Model:            payload.Model,
+		Provider:         payload.APIProvider,
+		PromptTokens:     payload.PromptTokens,
+		CompletionTokens: payload.CompletionTokens,
+		Cost:             payload.ResponseCost,
+		Latency:          payload.EndTime.Sub(payload.StartTime),
+		Error:            err != nil,
+	}
+	if payload.Team != nil {
+		sample.Team = *payload.Team
+	}
+	h.timeSeries.Record(sample)
+}
+
+// recordFirstToken sets the payload's completion start time from the stream
+// once its first token has arrived.
+func recordFirstToken(payload *observability.StandardLoggingPayload, stream *llmux.StreamReader) {
+	if payload == nil || payload.CompletionStartTime != nil {
+		return
+	}
+	if t := stream.FirstTokenTime(); !t.IsZero() {
+		payload.CompletionStartTime = &t
+	}
+}
+
 func (h *ClientHandler) observeStreamEvent(ctx context.Context, payload *observability.StandardLoggingPayload, chunk any) {
 	if h.obs == nil || payload == nil {
 		return
@@ -1176,3 +1700,18 @@ func errorClass(err error) string {
 
 // Ensure streaming package is imported for parser registration
 var _ = streaming.GetParser
+
+// setGuardrailsHeader reports the guardrails that fired on a request that
+// was allowed through.
+// setStructuredOutputHeader reports the outcome of structured output validation.
+func setStructuredOutputHeader(w http.ResponseWriter, status string) {
+	if status != "" {
+		w.Header().Set(structuredOutputHeader, status)
+	}
+}
+
+func setGuardrailsHeader(w http.ResponseWriter, annotations *guardrails.Annotations) {
+	if names := annotations.Names(); len(names) > 0 {
+		w.Header().Set(guardrailsHeader, strings.Join(names, ","))
+	}
+}
@@ -16,7 +16,7 @@ func TestClientHandler_writeError_DoesNotLeakRawErrorMessage(t *testing.T) {
 	}
 
 	rr := httptest.NewRecorder()
-	h.writeError(rr, errors.New("LEAKME: db password=secret"))
+	h.writeError(rr, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil), errors.New("LEAKME: db password=secret"))
 
 	if rr.Code != http.StatusInternalServerError {
 		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
@@ -9,12 +9,19 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/governance"
 	"github.com/blueberrycongee/llmux/internal/mcp"
+	"github.com/blueberrycongee/llmux/internal/observability"
 	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
 	"github.com/blueberrycongee/llmux/pkg/types"
 )
@@ -259,3 +266,153 @@ func TestClientHandlerChatCompletions_MCPToolExecution(t *testing.T) {
 		t.Fatalf("response content = %s, want %q", resp.Choices[0].Message.Content, `"done"`)
 	}
 }
+
+type usageRecordingStore struct {
+	*auth.MemoryStore
+	mu   sync.Mutex
+	logs []*auth.UsageLog
+}
+
+func (s *usageRecordingStore) LogUsage(_ context.Context, log *auth.UsageLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs = append(s.logs, log)
+	return nil
+}
+
+func (s *usageRecordingStore) byRequestID() map[string]*auth.UsageLog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]*auth.UsageLog, len(s.logs))
+	for _, log := range s.logs {
+		out[log.RequestID] = log
+	}
+	return out
+}
+
+func TestClientHandlerChatCompletions_MCPToolCallAccounting(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := types.ChatResponse{
+			ID:    "resp",
+			Model: "gpt-4o",
+			Usage: &types.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+			Choices: []types.Choice{{
+				FinishReason: "stop",
+				Message:      types.ChatMessage{Role: "assistant", Content: json.RawMessage(`"done"`)},
+			}},
+		}
+		if calls.Add(1) == 1 {
+			resp.Choices[0].FinishReason = "tool_calls"
+			resp.Choices[0].Message = types.ChatMessage{
+				Role:    "assistant",
+				Content: json.RawMessage("null"),
+				ToolCalls: []types.ToolCall{{
+					ID:       "call_1",
+					Type:     "function",
+					Function: types.ToolCallFunction{Name: "tool_one", Arguments: `{"value":"secret"}`},
+				}},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client, err := llmux.New(llmux.WithProviderInstance("mock", &mcpTestProvider{baseURL: server.URL}, []string{"gpt-4o"}))
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	manager := mcp.NewMockManager()
+	manager.AddMockClient("client-1", "Client 1", mcp.ConnectionTypeHTTP, []types.Tool{
+		{Type: "function", Function: types.ToolFunction{Name: "tool_one"}},
+	})
+
+	store := &usageRecordingStore{MemoryStore: auth.NewMemoryStore()}
+	handler := NewClientHandler(client, slog.New(slog.NewTextHandler(io.Discard, nil)), &ClientHandlerConfig{
+		MCPManager: manager,
+		Store:      store,
+	})
+
+	reqBody, _ := json.Marshal(types.ChatRequest{
+		Model:    "gpt-4o",
+		Messages: []types.ChatMessage{{Role: "user", Content: json.RawMessage(`"hello"`)}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(reqBody))
+	ctx := observability.ContextWithRequestID(req.Context(), "req-1")
+	req = req.WithContext(auth.WithAuthContext(ctx, &auth.AuthContext{APIKey: &auth.APIKey{ID: "key-1"}}))
+	recorder := httptest.NewRecorder()
+	handler.ChatCompletions(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	require.Eventually(t, func() bool { return len(store.byRequestID()) == 3 }, 2*time.Second, 10*time.Millisecond)
+	logs := store.byRequestID()
+
+	tool := logs["req-1-tool-call_1"]
+	require.NotNil(t, tool, "tool call usage log")
+	assert.Equal(t, governance.CallTypeMCPToolCall, tool.CallType)
+	assert.Equal(t, "tool_one", tool.Model)
+	assert.Equal(t, "key-1", tool.APIKeyID)
+	assert.Equal(t, "req-1", tool.Metadata["parent_request_id"])
+	assert.Equal(t, mcp.ArgumentsHash(`{"value":"secret"}`), tool.Metadata["args_hash"])
+
+	step := logs["req-1-step-1"]
+	require.NotNil(t, step, "agent step usage log")
+	assert.Equal(t, governance.CallTypeChatCompletion, step.CallType)
+	assert.Equal(t, 15, step.TotalTokens)
+	assert.Equal(t, "req-1", step.Metadata["parent_request_id"])
+
+	final := logs["req-1"]
+	require.NotNil(t, final, "request usage log")
+	assert.Nil(t, final.Metadata["parent_request_id"])
+}
+
+func TestClientHandlerChatCompletions_MCPPrompt(t *testing.T) {
+	var mu sync.Mutex
+	var upstream types.ChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		_ = json.NewDecoder(r.Body).Decode(&upstream)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(types.ChatResponse{
+			ID:    "resp",
+			Model: "gpt-4o",
+			Choices: []types.Choice{{
+				FinishReason: "stop",
+				Message:      types.ChatMessage{Role: "assistant", Content: json.RawMessage(`"ok"`)},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	client, err := llmux.New(llmux.WithProviderInstance("mock", &mcpTestProvider{baseURL: server.URL}, []string{"gpt-4o"}))
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	manager := mcp.NewMockManager()
+	manager.AddMockPrompt(mcp.Prompt{ClientID: "docs", Name: "review"},
+		types.ChatMessage{Role: "user", Content: json.RawMessage(`"Review this code."`)})
+	handler := NewClientHandler(client, slog.New(slog.NewTextHandler(io.Discard, nil)), &ClientHandlerConfig{
+		MCPManager: manager,
+	})
+
+	send := func(body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ChatCompletions(recorder, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader([]byte(body))))
+		return recorder
+	}
+
+	recorder := send(`{"model":"gpt-4o","mcp_prompt":{"name":"docs/review"},"messages":[{"role":"user","content":"func main() {}"}]}`)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	mu.Lock()
+	require.Len(t, upstream.Messages, 2)
+	assert.Equal(t, `"Review this code."`, string(upstream.Messages[0].Content))
+	assert.Equal(t, `"func main() {}"`, string(upstream.Messages[1].Content))
+	assert.NotContains(t, upstream.Extra, "mcp_prompt")
+	mu.Unlock()
+
+	// The prompt alone satisfies the messages requirement.
+	assert.Equal(t, http.StatusOK, send(`{"model":"gpt-4o","mcp_prompt":{"name":"review"}}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send(`{"model":"gpt-4o","mcp_prompt":{"name":"missing"}}`).Code)
+}
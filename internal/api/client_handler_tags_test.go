@@ -0,0 +1,84 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+func TestMergeHeaderTags(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	if got := mergeHeaderTags(r, []string{"eu"}); !reflect.DeepEqual(got, []string{"eu"}) {
+		t.Fatalf("without header: got %v", got)
+	}
+
+	r.Header.Set(tagsHeader, " eu , premium,, batch ")
+	got := mergeHeaderTags(r, []string{"eu"})
+	want := []string{"eu", "premium", "batch"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestClientHandler_writeError_NoDeploymentsWithTag(t *testing.T) {
+	h := &ClientHandler{
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	rr := httptest.NewRecorder()
+	h.writeError(rr, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil),
+		llmerrors.NewNoDeploymentsWithTagError("gpt-4", []string{"apac"}))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Error.Code != llmerrors.CodeNoDeploymentsWithTag {
+		t.Fatalf("expected code %q, got %q", llmerrors.CodeNoDeploymentsWithTag, resp.Error.Code)
+	}
+}
+
+func TestRequestTags_HostTenantDefaults(t *testing.T) {
+	tenant := &auth.HostTenant{OrganizationID: "org1", DefaultTags: []string{"eu"}}
+	r := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	r = r.WithContext(auth.WithHostTenant(r.Context(), tenant))
+
+	if got := requestTags(r, nil); !reflect.DeepEqual(got, []string{"eu"}) {
+		t.Fatalf("defaults: got %v", got)
+	}
+	if got := requestTags(r, []string{"us"}); !reflect.DeepEqual(got, []string{"us"}) {
+		t.Fatalf("explicit tags: got %v", got)
+	}
+	r.Header.Set(tagsHeader, "apac")
+	if got := requestTags(r, nil); !reflect.DeepEqual(got, []string{"apac"}) {
+		t.Fatalf("header tags: got %v", got)
+	}
+}
+
+func TestClientHandler_evaluateGovernance_HostTenantModels(t *testing.T) {
+	h := &ClientHandler{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	tenant := &auth.HostTenant{OrganizationID: "org1", AllowedModels: []string{"gpt-4o-mini"}}
+	r := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	ctx := auth.WithHostTenant(r.Context(), tenant)
+
+	if _, err := h.evaluateGovernance(ctx, r, "openai/gpt-4o-mini", "", nil, "", "chat"); err != nil {
+		t.Fatalf("allowed model: %v", err)
+	}
+	_, err := h.evaluateGovernance(ctx, r, "gpt-4o", "", nil, "", "chat")
+	var llmErr *llmerrors.LLMError
+	if !errors.As(err, &llmErr) || llmErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected permission error, got %v", err)
+	}
+}
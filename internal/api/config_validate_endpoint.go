@@ -0,0 +1,58 @@
+// Package api provides HTTP handlers for the LLM gateway API.
+// Config validation dry-run endpoint.
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/preflight"
+)
+
+// ConfigValidator parses and validates a candidate config document and runs
+// the provider checks in mode without applying anything.
+type ConfigValidator func(ctx context.Context, data []byte, mode preflight.ProviderCheckMode) *preflight.Validation
+
+// ValidateConfigRequest represents a candidate config to validate. Config is
+// a full config file (YAML or JSON); ProviderCheck is probe (default),
+// dry_run or off.
+type ValidateConfigRequest struct {
+	Config        string `json:"config"`
+	ProviderCheck string `json:"provider_check,omitempty"`
+}
+
+// SetConfigValidator sets the function behind POST /config/validate.
+// Without it the endpoint is unavailable.
+func (h *ManagementHandler) SetConfigValidator(validate ConfigValidator) {
+	h.configValidator = validate
+}
+
+// ValidateConfig handles POST /config/validate
+func (h *ManagementHandler) ValidateConfig(w http.ResponseWriter, r *http.Request) {
+	if h.configValidator == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "config validation not available")
+		return
+	}
+	var req ValidateConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Config == "" {
+		h.writeError(w, r, http.StatusBadRequest, "config is required")
+		return
+	}
+	mode := preflight.ProviderCheckMode(req.ProviderCheck)
+	switch mode {
+	case "":
+		mode = preflight.ProviderCheckProbe
+	case preflight.ProviderCheckProbe, preflight.ProviderCheckDryRun, preflight.ProviderCheckOff:
+	default:
+		h.writeError(w, r, http.StatusBadRequest, "provider_check must be probe, dry_run or off")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.configValidator(r.Context(), []byte(req.Config), mode))
+}
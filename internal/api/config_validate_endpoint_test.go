@@ -0,0 +1,60 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/preflight"
+)
+
+func TestValidateConfigEndpoint(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := NewManagementHandler(nil, nil, logger, nil, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	do := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/config/validate", bytes.NewBufferString(body)))
+		return rec
+	}
+
+	rec := do(`{"config":"providers: []"}`)
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var gotData string
+	var gotMode preflight.ProviderCheckMode
+	h.SetConfigValidator(func(_ context.Context, data []byte, mode preflight.ProviderCheckMode) *preflight.Validation {
+		gotData, gotMode = string(data), mode
+		v := preflight.NewValidation()
+		v.AddWarning("w", "warning")
+		v.AddError(preflight.IssueInvalid, "bad")
+		return v
+	})
+
+	rec = do(`{"config":"providers: []"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, "providers: []", gotData)
+	require.Equal(t, preflight.ProviderCheckProbe, gotMode)
+	var v preflight.Validation
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &v))
+	require.False(t, v.Valid)
+	require.Len(t, v.Errors, 1)
+	require.Len(t, v.Warnings, 1)
+
+	rec = do(`{"config":"x: 1","provider_check":"dry_run"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, preflight.ProviderCheckDryRun, gotMode)
+
+	require.Equal(t, http.StatusBadRequest, do(`{"config":"x: 1","provider_check":"ping"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(`{}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(`not json`).Code)
+}
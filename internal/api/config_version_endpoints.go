@@ -0,0 +1,232 @@
+// Package api provides HTTP handlers for the LLM gateway API.
+// Stored config version endpoints (config.source: db).
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"gopkg.in/yaml.v3"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+const defaultConfigVersionLimit = 50
+
+// CommitConfigVersionRequest represents a request to store and apply a new
+// shared config document (providers, routing, governance as YAML or JSON).
+type CommitConfigVersionRequest struct {
+	Document string `json:"document"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// RollbackConfigRequest represents a request to re-apply an earlier version.
+type RollbackConfigRequest struct {
+	Version int `json:"version"`
+}
+
+// ListConfigVersions handles GET /control/config/versions
+func (h *ManagementHandler) ListConfigVersions(w http.ResponseWriter, r *http.Request) {
+	if h.configManager == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "config manager not available")
+		return
+	}
+	limit := defaultConfigVersionLimit
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+		limit = v
+	}
+
+	versions, err := h.configManager.ListVersions(r.Context(), limit)
+	if err != nil {
+		h.writeConfigVersionError(w, r, err, "failed to list config versions")
+		return
+	}
+	redacted := make([]*config.ConfigVersion, 0, len(versions))
+	for _, v := range versions {
+		redacted = append(redacted, redactConfigVersion(v))
+	}
+	active := 0
+	if v := h.configManager.ActiveVersion(); v != nil {
+		active = v.Version
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"data":           redacted,
+		"active_version": active,
+	})
+}
+
+// GetConfigVersion handles GET /control/config/versions/{version}
+func (h *ManagementHandler) GetConfigVersion(w http.ResponseWriter, r *http.Request) {
+	if h.configManager == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "config manager not available")
+		return
+	}
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil || version <= 0 {
+		h.writeError(w, r, http.StatusBadRequest, "invalid version")
+		return
+	}
+
+	v, err := h.configManager.GetVersion(r.Context(), version)
+	if err != nil {
+		h.writeConfigVersionError(w, r, err, "failed to get config version")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, redactConfigVersion(v))
+}
+
+// CommitConfigVersion handles POST /control/config/versions
+func (h *ManagementHandler) CommitConfigVersion(w http.ResponseWriter, r *http.Request) {
+	if h.configManager == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "config manager not available")
+		return
+	}
+	var req CommitConfigVersionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Document == "" {
+		h.writeError(w, r, http.StatusBadRequest, "document is required")
+		return
+	}
+
+	before := h.configManager.Status()
+	actor := auditActorFromContext(auth.GetAuthContext(r.Context()))
+	v, err := h.configManager.CommitVersion(r.Context(), req.Document, req.Comment, actor.id)
+	h.auditConfigVersion(r, before, err)
+	if err != nil {
+		h.writeConfigVersionError(w, r, err, "failed to commit config version")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, redactConfigVersion(v))
+}
+
+// RollbackConfig handles POST /control/config/rollback
+func (h *ManagementHandler) RollbackConfig(w http.ResponseWriter, r *http.Request) {
+	if h.configManager == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "config manager not available")
+		return
+	}
+	var req RollbackConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Version <= 0 {
+		h.writeError(w, r, http.StatusBadRequest, "version is required")
+		return
+	}
+
+	before := h.configManager.Status()
+	actor := auditActorFromContext(auth.GetAuthContext(r.Context()))
+	v, err := h.configManager.RollbackVersion(r.Context(), req.Version, actor.id)
+	h.auditConfigVersion(r, before, err)
+	if err != nil {
+		h.writeConfigVersionError(w, r, err, "failed to roll back config")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, redactConfigVersion(v))
+}
+
+func (h *ManagementHandler) auditConfigVersion(r *http.Request, before config.ConfigStatus, err error) {
+	after := h.configManager.Status()
+	beforeValue := map[string]any{"version": before.Version, "checksum": before.Checksum}
+	if err != nil {
+		h.auditControlAction(r, auth.AuditActionConfigUpdate, auth.AuditObjectConfig, "gateway", false, beforeValue, nil, nil, err.Error())
+		return
+	}
+	h.auditControlAction(r, auth.AuditActionConfigUpdate, auth.AuditObjectConfig, "gateway", true,
+		beforeValue, map[string]any{"version": after.Version, "checksum": after.Checksum}, nil, "")
+}
+
+func (h *ManagementHandler) writeConfigVersionError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, config.ErrFileConfigSource):
+		h.writeError(w, r, http.StatusConflict, err.Error())
+	case errors.Is(err, config.ErrConfigVersionNotFound):
+		h.writeError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, config.ErrInvalidConfig):
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.Error(msg, "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, msg)
+	}
+}
+
+// redactConfigVersion returns a copy of v with the provider credentials in
+// its document masked as in /model responses.
+func redactConfigVersion(v *config.ConfigVersion) *config.ConfigVersion {
+	if v == nil {
+		return nil
+	}
+	redacted := *v
+	redacted.Document = redactConfigDocument(v.Document)
+	return &redacted
+}
+
+// redactConfigDocument masks the api_key, secondary_api_key and header
+// values of every provider in a shared config document. A document that
+// cannot be parsed is withheld entirely.
+func redactConfigDocument(document string) string {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(document), &root); err != nil {
+		return ""
+	}
+	if len(root.Content) == 0 {
+		return document
+	}
+	if providers := mappingValue(root.Content[0], "providers"); providers != nil && providers.Kind == yaml.SequenceNode {
+		for _, p := range providers.Content {
+			for _, key := range []string{"api_key", "secondary_api_key"} {
+				if value := mappingValue(p, key); value != nil && value.Kind == yaml.ScalarNode {
+					value.Value = maskConfigSecret(value.Value)
+				}
+			}
+			if headers := mappingValue(p, "headers"); headers != nil && headers.Kind == yaml.MappingNode {
+				for i := 1; i < len(headers.Content); i += 2 {
+					headers.Content[i].Value = maskConfigSecret(headers.Content[i].Value)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return ""
+	}
+	if err := enc.Close(); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// mappingValue returns the value of key in a YAML mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// maskConfigSecret masks a credential like maskProviderAPIKey, also keeping
+// ${VAR} environment references, which hold no secret themselves.
+func maskConfigSecret(value string) string {
+	if strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") && !strings.Contains(value, ":") {
+		return value
+	}
+	return maskProviderAPIKey(value)
+}
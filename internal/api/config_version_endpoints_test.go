@@ -0,0 +1,135 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/config"
+)
+
+type testVersionStore struct {
+	versions []*config.ConfigVersion
+}
+
+func (s *testVersionStore) LatestConfigVersion(context.Context) (*config.ConfigVersion, error) {
+	if len(s.versions) == 0 {
+		return nil, nil
+	}
+	return s.versions[len(s.versions)-1], nil
+}
+
+func (s *testVersionStore) GetConfigVersion(_ context.Context, version int) (*config.ConfigVersion, error) {
+	if version < 1 || version > len(s.versions) {
+		return nil, nil
+	}
+	return s.versions[version-1], nil
+}
+
+func (s *testVersionStore) ListConfigVersions(_ context.Context, limit int) ([]*config.ConfigVersion, error) {
+	var out []*config.ConfigVersion
+	for i := len(s.versions) - 1; i >= 0 && len(out) < limit; i-- {
+		out = append(out, s.versions[i])
+	}
+	return out, nil
+}
+
+func (s *testVersionStore) CreateConfigVersion(_ context.Context, v *config.ConfigVersion) error {
+	v.Version = len(s.versions) + 1
+	v.CreatedAt = time.Now()
+	s.versions = append(s.versions, v)
+	return nil
+}
+
+func TestConfigVersionEndpoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+config:
+  source: db
+database:
+  enabled: true
+  host: localhost
+  user: llmux
+  database: llmux
+  ssl_mode: disable
+providers:
+  - name: openai
+    type: openai
+    api_key: sk-file-secret-1234
+    headers:
+      X-Org-Token: org-secret-value
+    models: [gpt-4o]
+`), 0o600))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mgr, err := config.NewManager(path, logger)
+	require.NoError(t, err)
+
+	h := NewManagementHandler(auth.NewMemoryStore(), nil, logger, nil, mgr, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, target, bytes.NewBufferString(body)))
+		return rec
+	}
+
+	// Version endpoints are unavailable until the stored config is in use.
+	rec := do(http.MethodGet, "/control/config/versions", "")
+	require.Equal(t, http.StatusConflict, rec.Code)
+
+	require.NoError(t, mgr.UseVersionStore(context.Background(), &testVersionStore{}))
+
+	rec = do(http.MethodPost, "/control/config/versions", `{"document":"routing:\n  retry_count: 7\n","comment":"more retries"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, 7, mgr.Get().Routing.RetryCount)
+
+	rec = do(http.MethodPost, "/control/config/versions", `{"document":"server:\n  port: 1\n"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = do(http.MethodGet, "/control/config/versions", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Data          []config.ConfigVersion `json:"data"`
+		ActiveVersion int                    `json:"active_version"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Data, 2)
+	require.Equal(t, 2, list.ActiveVersion)
+	require.Equal(t, "more retries", list.Data[0].Comment)
+
+	require.NotContains(t, rec.Body.String(), "sk-file-secret-1234")
+	require.NotContains(t, rec.Body.String(), "org-secret-value")
+
+	rec = do(http.MethodGet, "/control/config/versions/1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "seeded from")
+	require.NotContains(t, rec.Body.String(), "sk-file-secret-1234")
+	require.NotContains(t, rec.Body.String(), "org-secret-value")
+	require.Contains(t, rec.Body.String(), "****1234")
+
+	rec = do(http.MethodPost, "/control/config/versions", `{"document":"providers:\n  - name: openai\n    type: openai\n    api_key: sk-committed-5678\n    models: [gpt-4o]\n"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NotContains(t, rec.Body.String(), "sk-committed-5678")
+	rec = do(http.MethodGet, "/control/config/versions/3", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), "sk-committed-5678")
+	rec = do(http.MethodGet, "/control/config/versions/9", "")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = do(http.MethodPost, "/control/config/rollback", `{"version":1}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NotContains(t, rec.Body.String(), "sk-file-secret-1234")
+	require.Equal(t, 4, mgr.ActiveVersion().Version)
+	require.Equal(t, config.DefaultConfig().Routing.RetryCount, mgr.Get().Routing.RetryCount)
+}
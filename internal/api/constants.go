@@ -5,3 +5,35 @@ const (
 	// This accommodates large context windows while preventing abuse.
 	DefaultMaxBodySize = 10 * 1024 * 1024
 )
+
+// priorityHeader lets callers lower their request priority (low, normal, high).
+const priorityHeader = "X-LLMux-Priority"
+
+// tagsHeader carries comma-separated routing tags, merged with any body tags.
+const tagsHeader = "X-LLMux-Tags"
+
+// cacheStatusHeader reports "hit" or "miss" when the response cache was consulted.
+const cacheStatusHeader = "X-LLMux-Cache"
+
+// guardrailsHeader lists the guardrails that redacted or annotated a request.
+const guardrailsHeader = "X-LLMux-Guardrails"
+
+// structuredOutputHeader reports whether a json_schema output matched its
+// schema: "valid", "invalid" or "repaired". Streams send it as a trailer.
+const structuredOutputHeader = "X-LLMux-Structured-Output"
+
+// sessionIDHeader names the conversation whose history the gateway replays
+// into the request and extends with the response.
+const sessionIDHeader = "X-Session-ID"
+
+// idempotencyKeyHeader lets callers retry a non-streaming request without
+// running or billing it twice.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotentReplayedHeader is set to "true" on responses replayed for a
+// repeated Idempotency-Key.
+const idempotentReplayedHeader = "Idempotent-Replayed"
+
+// lastEventIDHeader carries the ID of the last SSE event a reconnecting
+// client received, so it can resume a buffered stream.
+const lastEventIDHeader = "Last-Event-ID"
@@ -1,6 +1,7 @@
 package api //nolint:revive // package name is intentional
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"time"
@@ -31,6 +32,10 @@ type cooldownRequest struct {
 	CooldownSeconds int    `json:"cooldown_seconds"`
 }
 
+type credentialRollbackRequest struct {
+	Provider string `json:"provider"`
+}
+
 type configReloadRequest struct {
 	ExpectedChecksum string `json:"expected_checksum,omitempty"`
 }
@@ -174,6 +179,74 @@ func (h *ManagementHandler) ListProviders(w http.ResponseWriter, r *http.Request
 	})
 }
 
+// GetPrefixCacheSavings reports estimated prefix cache savings per team.
+// Counters live in the active client and reset when the config is reloaded.
+func (h *ManagementHandler) GetPrefixCacheSavings(w http.ResponseWriter, r *http.Request) {
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "client not available")
+		return
+	}
+
+	savings := client.PrefixCacheSavings()
+	if savings == nil {
+		savings = []llmux.PrefixCacheSavings{}
+	}
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"enabled": client.PrefixCacheEnabled(),
+		"data":    savings,
+	})
+}
+
+// GetMissingPricing lists configured models without pricing and the
+// fallback applied to their requests.
+func (h *ManagementHandler) GetMissingPricing(w http.ResponseWriter, r *http.Request) {
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "client not available")
+		return
+	}
+
+	missing := client.MissingPricing()
+	if missing == nil {
+		missing = []llmux.MissingPricing{}
+	}
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"policy": client.PricingPolicy(),
+		"data":   missing,
+	})
+}
+
+// schemaVersioner is implemented by auth stores backed by a migrated database.
+type schemaVersioner interface {
+	SchemaVersion(ctx context.Context) (int, error)
+}
+
+// GetControlHealth reports gateway health for operators, including the
+// applied database schema version when the auth store is Postgres. The
+// status is degraded while the schema is behind or unreadable.
+func (h *ManagementHandler) GetControlHealth(w http.ResponseWriter, r *http.Request) {
+	resp := map[string]any{"status": "ok"}
+	if versioner, ok := auth.UnwrapStore(h.store).(schemaVersioner); ok {
+		database := map[string]any{"latest_schema_version": auth.LatestSchemaVersion}
+		version, err := versioner.SchemaVersion(r.Context())
+		switch {
+		case err != nil:
+			database["error"] = err.Error()
+			resp["status"] = "degraded"
+		case version < auth.LatestSchemaVersion:
+			database["schema_version"] = version
+			resp["status"] = "degraded"
+		default:
+			database["schema_version"] = version
+		}
+		resp["database"] = database
+	}
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
 func (h *ManagementHandler) GetConfigStatus(w http.ResponseWriter, r *http.Request) {
 	if h.configManager == nil {
 		h.writeError(w, r, http.StatusServiceUnavailable, "config manager not available")
@@ -314,3 +387,77 @@ func auditActorFromContext(authCtx *auth.AuthContext) auditActor {
 
 	return actor
 }
+
+// ListCredentialRollovers reports blue/green credential rollover status and
+// per-credential error rates for providers with a secondary API key.
+func (h *ManagementHandler) ListCredentialRollovers(w http.ResponseWriter, r *http.Request) {
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "client not available")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"data": client.CredentialRollovers(),
+	})
+}
+
+// RollbackCredentials routes all of a provider's traffic back to its
+// primary API key.
+func (h *ManagementHandler) RollbackCredentials(w http.ResponseWriter, r *http.Request) {
+	h.updateCredentials(w, r, "credential_rollback", (*llmux.Client).RollbackCredentials)
+}
+
+// ResumeCredentials returns a rolled-back provider to its rollover schedule.
+func (h *ManagementHandler) ResumeCredentials(w http.ResponseWriter, r *http.Request) {
+	h.updateCredentials(w, r, "credential_resume", (*llmux.Client).ResumeCredentials)
+}
+
+// updateCredentials applies a rollover action to the provider named in the
+// request body and audits the resulting change in secondary weight.
+func (h *ManagementHandler) updateCredentials(w http.ResponseWriter, r *http.Request, action string, apply func(*llmux.Client, string) error) {
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "client not available")
+		return
+	}
+
+	var req credentialRollbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Provider == "" {
+		h.writeError(w, r, http.StatusBadRequest, "provider is required")
+		return
+	}
+
+	before, ok := client.CredentialRollovers()[req.Provider]
+	if !ok {
+		h.writeError(w, r, http.StatusNotFound, "provider has no credential rollover")
+		return
+	}
+	if err := apply(client, req.Provider); err != nil {
+		h.auditControlAction(r, auth.AuditActionUpdate, auth.AuditObjectConfig, req.Provider, false, nil, nil, map[string]any{
+			"action": action,
+		}, err.Error())
+		h.writeError(w, r, http.StatusInternalServerError, "failed to update credentials")
+		return
+	}
+	after := client.CredentialRollovers()[req.Provider]
+
+	h.auditControlAction(r, auth.AuditActionUpdate, auth.AuditObjectConfig, req.Provider, true, map[string]any{
+		"secondary_weight": before.SecondaryWeight,
+	}, map[string]any{
+		"secondary_weight": after.SecondaryWeight,
+	}, map[string]any{
+		"action": action,
+	}, "")
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"provider": req.Provider,
+		"status":   after,
+	})
+}
@@ -266,3 +266,104 @@ func containsModel(models []string, model string) bool {
 	}
 	return false
 }
+
+type versionedStore struct {
+	*auth.MemoryStore
+	version int
+}
+
+func (s versionedStore) SchemaVersion(context.Context) (int, error) { return s.version, nil }
+
+func TestControlEndpoints_HealthReportsSchemaVersion(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	cases := []struct {
+		name    string
+		store   auth.Store
+		status  string
+		version float64
+	}{
+		{name: "memory store", store: auth.NewMemoryStore(), status: "ok"},
+		{name: "current schema", store: versionedStore{auth.NewMemoryStore(), auth.LatestSchemaVersion}, status: "ok", version: float64(auth.LatestSchemaVersion)},
+		{name: "behind", store: versionedStore{auth.NewMemoryStore(), 2}, status: "degraded", version: 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewManagementHandler(tc.store, nil, logger, nil, nil, nil).RegisterRoutes(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/control/health", nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+			}
+			var resp map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if resp["status"] != tc.status {
+				t.Fatalf("status = %v, want %s", resp["status"], tc.status)
+			}
+			database, ok := resp["database"].(map[string]any)
+			if tc.version == 0 {
+				if ok {
+					t.Fatalf("unexpected database section: %v", database)
+				}
+				return
+			}
+			if database["schema_version"] != tc.version || database["latest_schema_version"] != float64(auth.LatestSchemaVersion) {
+				t.Fatalf("database = %v", database)
+			}
+		})
+	}
+}
+
+func TestControlEndpoints_CredentialRollbackAndResume(t *testing.T) {
+	client, err := llmux.New(llmux.WithProvider(llmux.ProviderConfig{
+		Name:            "openai",
+		Type:            "openai",
+		APIKey:          "sk-old",
+		SecondaryAPIKey: "sk-new",
+		Rollover:        llmux.RolloverSchedule{SecondaryWeight: 1},
+		Models:          []string{"gpt-4o"},
+	}))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	auditStore := auth.NewMemoryAuditLogStore()
+	handler := NewManagementHandler(auth.NewMemoryStore(), auditStore, slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		NewClientSwapper(client), nil, auth.NewAuditLogger(auditStore, true))
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	post := func(path, body string) int {
+		req := addTestAuthContext(httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := post("/control/credentials/rollback", `{"provider":"openai"}`); code != http.StatusOK {
+		t.Fatalf("rollback status = %d", code)
+	}
+	if status := client.CredentialRollovers()["openai"]; !status.RolledBack || status.SecondaryWeight != 0 {
+		t.Fatalf("status after rollback = %+v", status)
+	}
+	if code := post("/control/credentials/resume", `{"provider":"openai"}`); code != http.StatusOK {
+		t.Fatalf("resume status = %d", code)
+	}
+	if status := client.CredentialRollovers()["openai"]; status.RolledBack || status.SecondaryWeight != 1 {
+		t.Fatalf("status after resume = %+v", status)
+	}
+	if code := post("/control/credentials/resume", `{"provider":"missing"}`); code != http.StatusNotFound {
+		t.Fatalf("resume of unknown provider status = %d, want 404", code)
+	}
+
+	logs, _, err := auditStore.ListAuditLogs(auth.AuditLogFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("list audit logs: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected audit entries for rollback and resume, got %d", len(logs))
+	}
+}
@@ -0,0 +1,341 @@
+// Package api provides HTTP handlers for the LLM gateway API.
+// End-user (customer) management endpoints.
+package api //nolint:revive // package name is intentional
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+// ============================================================================
+// Customer Management Endpoints
+// ============================================================================
+//
+// Customers are the downstream end users identified by the request "user"
+// field. Their budgets are enforced by the governance engine.
+
+// NewCustomerRequest represents a request to create a customer.
+type NewCustomerRequest struct {
+	UserID    string   `json:"user_id"`
+	Alias     *string  `json:"alias,omitempty"`
+	MaxBudget *float64 `json:"max_budget,omitempty"`
+	Blocked   bool     `json:"blocked,omitempty"`
+}
+
+// UpdateCustomerRequest represents a request to update a customer.
+type UpdateCustomerRequest struct {
+	UserID    string   `json:"user_id"`
+	Alias     *string  `json:"alias,omitempty"`
+	MaxBudget *float64 `json:"max_budget,omitempty"`
+	Blocked   *bool    `json:"blocked,omitempty"`
+}
+
+// CustomerIDsRequest identifies customers for bulk operations.
+type CustomerIDsRequest struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+// NewCustomer handles POST /customer/new
+func (h *ManagementHandler) NewCustomer(w http.ResponseWriter, r *http.Request) {
+	var req NewCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.UserID == "" {
+		h.writeError(w, r, http.StatusBadRequest, "user_id is required")
+		return
+	}
+	if req.MaxBudget != nil && *req.MaxBudget < 0 {
+		h.writeError(w, r, http.StatusBadRequest, "max_budget must be non-negative")
+		return
+	}
+
+	existing, err := h.store.GetEndUser(r.Context(), req.UserID)
+	if err != nil {
+		h.logger.Error("failed to get customer", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to create customer")
+		return
+	}
+	if existing != nil {
+		h.writeError(w, r, http.StatusConflict, "customer already exists")
+		return
+	}
+
+	endUser := &auth.EndUser{
+		UserID:  req.UserID,
+		Alias:   req.Alias,
+		Blocked: req.Blocked,
+	}
+	if req.MaxBudget != nil {
+		budget, err := h.setCustomerBudget(r, nil, *req.MaxBudget)
+		if err != nil {
+			h.logger.Error("failed to create customer budget", "error", err)
+			h.writeError(w, r, http.StatusInternalServerError, "failed to create customer")
+			return
+		}
+		endUser.BudgetID = &budget.ID
+		endUser.Budget = budget
+	}
+
+	if err := h.store.CreateEndUser(r.Context(), endUser); err != nil {
+		h.logger.Error("failed to create customer", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to create customer")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, endUser)
+}
+
+// UpdateCustomer handles POST /customer/update
+func (h *ManagementHandler) UpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	var req UpdateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.UserID == "" {
+		h.writeError(w, r, http.StatusBadRequest, "user_id is required")
+		return
+	}
+	if req.MaxBudget != nil && *req.MaxBudget < 0 {
+		h.writeError(w, r, http.StatusBadRequest, "max_budget must be non-negative")
+		return
+	}
+
+	endUser, err := h.store.GetEndUser(r.Context(), req.UserID)
+	if err != nil {
+		h.logger.Error("failed to get customer", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to update customer")
+		return
+	}
+	if endUser == nil {
+		h.writeError(w, r, http.StatusNotFound, "customer not found")
+		return
+	}
+
+	if req.Alias != nil {
+		endUser.Alias = req.Alias
+	}
+	if req.Blocked != nil {
+		endUser.Blocked = *req.Blocked
+	}
+	if req.MaxBudget != nil {
+		budget, err := h.setCustomerBudget(r, endUser.BudgetID, *req.MaxBudget)
+		if err != nil {
+			h.logger.Error("failed to update customer budget", "error", err)
+			h.writeError(w, r, http.StatusInternalServerError, "failed to update customer")
+			return
+		}
+		endUser.BudgetID = &budget.ID
+		endUser.Budget = budget
+	}
+
+	if err := h.store.UpdateEndUser(r.Context(), endUser); err != nil {
+		h.logger.Error("failed to update customer", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to update customer")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, endUser)
+}
+
+// setCustomerBudget updates the budget budgetID points at, or creates one.
+func (h *ManagementHandler) setCustomerBudget(r *http.Request, budgetID *string, maxBudget float64) (*auth.Budget, error) {
+	now := time.Now()
+	if budgetID != nil {
+		budget, err := h.store.GetBudget(r.Context(), *budgetID)
+		if err != nil {
+			return nil, err
+		}
+		if budget != nil {
+			budget.MaxBudget = &maxBudget
+			budget.UpdatedAt = now
+			return budget, h.store.UpdateBudget(r.Context(), budget)
+		}
+	}
+
+	budget := &auth.Budget{
+		ID:        auth.GenerateUUID(),
+		MaxBudget: &maxBudget,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return budget, h.store.CreateBudget(r.Context(), budget)
+}
+
+// GetCustomerInfo handles GET /customer/info
+func (h *ManagementHandler) GetCustomerInfo(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("end_user_id")
+	if userID == "" {
+		h.writeError(w, r, http.StatusBadRequest, "end_user_id parameter is required")
+		return
+	}
+
+	endUser, err := h.store.GetEndUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to get customer info", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to get customer info")
+		return
+	}
+	if endUser == nil {
+		h.writeError(w, r, http.StatusNotFound, "customer not found")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, endUser)
+}
+
+// ListCustomers handles GET /customer/list
+func (h *ManagementHandler) ListCustomers(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	filter := auth.EndUserFilter{
+		Limit:  limit,
+		Offset: offset,
+	}
+	if blocked, err := strconv.ParseBool(r.URL.Query().Get("blocked")); err == nil {
+		filter.Blocked = &blocked
+	}
+
+	endUsers, total, err := h.store.ListEndUsers(r.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to list customers", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to list customers")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"data":  endUsers,
+		"total": total,
+	})
+}
+
+// DeleteCustomer handles POST /customer/delete
+func (h *ManagementHandler) DeleteCustomer(w http.ResponseWriter, r *http.Request) {
+	var req CustomerIDsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.UserIDs) == 0 {
+		h.writeError(w, r, http.StatusBadRequest, "user_ids is required")
+		return
+	}
+
+	deleted := make([]string, 0, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		if err := h.store.DeleteEndUser(r.Context(), userID); err != nil {
+			h.logger.Warn("failed to delete customer", "user_id", userID, "error", err)
+			continue
+		}
+		deleted = append(deleted, userID)
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"deleted_customers": deleted,
+	})
+}
+
+// BlockCustomer handles POST /customer/block
+func (h *ManagementHandler) BlockCustomer(w http.ResponseWriter, r *http.Request) {
+	h.setCustomersBlocked(w, r, true)
+}
+
+// UnblockCustomer handles POST /customer/unblock
+func (h *ManagementHandler) UnblockCustomer(w http.ResponseWriter, r *http.Request) {
+	h.setCustomersBlocked(w, r, false)
+}
+
+func (h *ManagementHandler) setCustomersBlocked(w http.ResponseWriter, r *http.Request, blocked bool) {
+	var req CustomerIDsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.UserIDs) == 0 {
+		h.writeError(w, r, http.StatusBadRequest, "user_ids is required")
+		return
+	}
+
+	for _, userID := range req.UserIDs {
+		if err := h.store.BlockEndUser(r.Context(), userID, blocked); err != nil {
+			h.logger.Error("failed to update customer block status", "user_id", userID, "error", err)
+			h.writeError(w, r, http.StatusInternalServerError, "failed to update customer block status")
+			return
+		}
+	}
+
+	status := "unblocked"
+	if blocked {
+		status = "blocked"
+	}
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"status":   status,
+		"user_ids": req.UserIDs,
+	})
+}
+
+// GetCustomerSpend handles GET /customer/spend
+func (h *ManagementHandler) GetCustomerSpend(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("end_user_id")
+	if userID == "" {
+		h.writeError(w, r, http.StatusBadRequest, "end_user_id parameter is required")
+		return
+	}
+	startDate, endDate, errMsg := parseSpendDateRange(r.URL.Query().Get("start_date"), r.URL.Query().Get("end_date"))
+	if errMsg != "" {
+		h.writeError(w, r, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	endUser, err := h.store.GetEndUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to get customer", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to get customer spend")
+		return
+	}
+	if endUser == nil {
+		h.writeError(w, r, http.StatusNotFound, "customer not found")
+		return
+	}
+
+	stats, err := h.store.GetUsageStats(r.Context(), auth.UsageFilter{
+		EndUserID: &userID,
+		StartTime: startDate,
+		EndTime:   endDate,
+	})
+	if err != nil {
+		h.logger.Error("failed to get customer spend", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to get customer spend")
+		return
+	}
+
+	var maxBudget *float64
+	if endUser.Budget != nil {
+		maxBudget = endUser.Budget.MaxBudget
+	}
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"end_user_id": userID,
+		"spend":       endUser.Spend,
+		"max_budget":  maxBudget,
+		"blocked":     endUser.Blocked,
+		"summary":     stats,
+		"filters": map[string]any{
+			"start_date": startDate.Format("2006-01-02"),
+			"end_date":   endDate.Format("2006-01-02"),
+		},
+	})
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+func TestCustomerEndpoints_Lifecycle(t *testing.T) {
+	store := auth.NewMemoryStore()
+	handler := NewManagementHandler(store, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil)
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	do := func(method, path string, body any) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(data)
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(method, path, reader))
+		return rr
+	}
+
+	rr := do(http.MethodPost, "/customer/new", map[string]any{"user_id": "acme", "max_budget": 10})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = do(http.MethodPost, "/customer/new", map[string]any{"user_id": "acme"})
+	require.Equal(t, http.StatusConflict, rr.Code, rr.Body.String())
+
+	endUser, err := store.GetEndUser(context.Background(), "acme")
+	require.NoError(t, err)
+	require.NotNil(t, endUser.Budget)
+	require.InDelta(t, 10.0, *endUser.Budget.MaxBudget, 1e-9)
+
+	rr = do(http.MethodPost, "/customer/update", map[string]any{"user_id": "acme", "max_budget": 25})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	endUser, err = store.GetEndUser(context.Background(), "acme")
+	require.NoError(t, err)
+	require.InDelta(t, 25.0, *endUser.Budget.MaxBudget, 1e-9)
+
+	rr = do(http.MethodPost, "/customer/block", map[string]any{"user_ids": []string{"acme"}})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = do(http.MethodGet, "/customer/list?blocked=true", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var list struct {
+		Data  []auth.EndUser `json:"data"`
+		Total int64          `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	require.Equal(t, int64(1), list.Total)
+	require.True(t, list.Data[0].Blocked)
+
+	endUserID := "acme"
+	require.NoError(t, store.LogUsage(context.Background(), &auth.UsageLog{
+		RequestID: "req-1",
+		Model:     "gpt-4",
+		EndUserID: &endUserID,
+		Cost:      1.5,
+		StartTime: time.Now(),
+	}))
+	require.NoError(t, store.UpdateEndUserSpent(context.Background(), "acme", 1.5))
+
+	rr = do(http.MethodGet, "/customer/spend?end_user_id=acme", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var spend struct {
+		Spend     float64         `json:"spend"`
+		MaxBudget float64         `json:"max_budget"`
+		Summary   auth.UsageStats `json:"summary"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &spend))
+	require.InDelta(t, 1.5, spend.Spend, 1e-9)
+	require.InDelta(t, 25.0, spend.MaxBudget, 1e-9)
+	require.Equal(t, int64(1), spend.Summary.TotalRequests)
+
+	rr = do(http.MethodGet, "/customer/info?end_user_id=missing", nil)
+	require.Equal(t, http.StatusNotFound, rr.Code, rr.Body.String())
+}
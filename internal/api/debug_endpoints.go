@@ -0,0 +1,149 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"net/http"
+	"runtime"
+	"strings"
+
+	llmux "github.com/blueberrycongee/llmux"
+)
+
+// modulePath is stripped from package names when grouping goroutines.
+const modulePath = "github.com/blueberrycongee/llmux"
+
+// maxGoroutineDumpBytes bounds the stack dump used for goroutine grouping.
+const maxGoroutineDumpBytes = 64 << 20
+
+// GetDebugGoroutines reports goroutine counts grouped by the subsystem that owns them.
+func (h *ManagementHandler) GetDebugGoroutines(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"total":        runtime.NumGoroutine(),
+		"by_subsystem": goroutinesBySubsystem(goroutineDump()),
+	})
+}
+
+// GetDebugPipeline reports the plugin pipeline in execution order.
+func (h *ManagementHandler) GetDebugPipeline(w http.ResponseWriter, r *http.Request) {
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "client not available")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"data": client.PluginPipeline(),
+	})
+}
+
+// GetDebugStreams reports open streaming responses with their age.
+func (h *ManagementHandler) GetDebugStreams(w http.ResponseWriter, r *http.Request) {
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "client not available")
+		return
+	}
+
+	streams := client.ActiveStreams()
+	if streams == nil {
+		streams = []llmux.ActiveStream{}
+	}
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"data": streams,
+	})
+}
+
+// GetDebugDeployments reports per-deployment in-flight requests and semaphore state.
+func (h *ManagementHandler) GetDebugDeployments(w http.ResponseWriter, r *http.Request) {
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "client not available")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"data": client.DeploymentLoads(),
+	})
+}
+
+// ExplainRouting reports the candidates, filters and scores behind the
+// deployments picked for a request. Requires routing.decision_trace_size.
+func (h *ManagementHandler) ExplainRouting(w http.ResponseWriter, r *http.Request) {
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "client not available")
+		return
+	}
+
+	decision, ok := client.RoutingExplanation(r.PathValue("request_id"))
+	if !ok {
+		h.writeError(w, r, http.StatusNotFound, "no routing decision recorded for request")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, decision)
+}
+
+// goroutineDump returns the stacks of all goroutines, growing the buffer as needed.
+func goroutineDump() []byte {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxGoroutineDumpBytes {
+			return buf[:n]
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+}
+
+// goroutinesBySubsystem groups a runtime.Stack dump by subsystem. A goroutine
+// belongs to the innermost package of this module on its stack (including the
+// "created by" frame); goroutines that never touch the module are grouped by
+// the package of their top frame.
+func goroutinesBySubsystem(dump []byte) map[string]int {
+	counts := make(map[string]int)
+	for _, stack := range bytes.Split(dump, []byte("\n\n")) {
+		if len(bytes.TrimSpace(stack)) == 0 {
+			continue
+		}
+		counts[goroutineSubsystem(string(stack))]++
+	}
+	return counts
+}
+
+func goroutineSubsystem(stack string) string {
+	fallback := ""
+	for _, line := range strings.Split(stack, "\n") {
+		if line == "" || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "goroutine ") {
+			continue
+		}
+		pkg := funcPackage(strings.TrimPrefix(line, "created by "))
+		if pkg == modulePath {
+			return "llmux"
+		}
+		if strings.HasPrefix(pkg, modulePath+"/") {
+			return strings.TrimPrefix(pkg, modulePath+"/")
+		}
+		if fallback == "" {
+			fallback = pkg
+		}
+	}
+	if fallback == "" {
+		return "unknown"
+	}
+	return fallback
+}
+
+// funcPackage extracts the package path from a stack frame such as
+// "net/http.(*Server).Serve(...)" or "created by main.main in goroutine 1".
+func funcPackage(frame string) string {
+	slash := strings.LastIndex(frame, "/")
+	dot := strings.Index(frame[slash+1:], ".")
+	if dot < 0 {
+		return frame
+	}
+	return frame[:slash+1+dot]
+}
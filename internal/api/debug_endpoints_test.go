@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/observability"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+func TestGoroutinesBySubsystem(t *testing.T) {
+	dump := []byte(`goroutine 1 [running]:
+github.com/blueberrycongee/llmux/internal/api.goroutineDump()
+	/src/internal/api/debug_endpoints.go:80 +0x2a
+main.main()
+	/src/cmd/server/main.go:10 +0x1
+
+goroutine 7 [select]:
+github.com/blueberrycongee/llmux/internal/resilience.(*Semaphore).AcquireWithPriority(0xc0000a2000)
+	/src/internal/resilience/semaphore.go:70 +0x10
+created by github.com/blueberrycongee/llmux.(*Client).ChatCompletion in goroutine 1
+	/src/client.go:300 +0x55
+
+goroutine 9 [IO wait]:
+net/http.(*conn).serve(0xc000120000)
+	/usr/local/go/src/net/http/server.go:2000 +0x1
+created by net/http.(*Server).Serve in goroutine 1
+	/usr/local/go/src/net/http/server.go:3000 +0x1
+
+goroutine 11 [chan receive]:
+github.com/blueberrycongee/llmux.(*Client).watch(0xc000010000)
+	/src/client.go:400 +0x1
+`)
+
+	counts := goroutinesBySubsystem(dump)
+	want := map[string]int{
+		"internal/api":        1,
+		"internal/resilience": 1,
+		"net/http":            1,
+		"llmux":               1,
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("counts = %v, want %v", counts, want)
+	}
+	for subsystem, n := range want {
+		if counts[subsystem] != n {
+			t.Errorf("counts[%q] = %d, want %d", subsystem, counts[subsystem], n)
+		}
+	}
+}
+
+func TestDebugEndpoints(t *testing.T) {
+	mux, _, _ := newControlTestServer(t)
+
+	get := func(path string, out any) {
+		t.Helper()
+		req := addTestAuthContext(httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d, body = %s", path, rec.Code, rec.Body.String())
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("GET %s decode: %v", path, err)
+		}
+	}
+
+	var goroutines struct {
+		Total       int            `json:"total"`
+		BySubsystem map[string]int `json:"by_subsystem"`
+	}
+	get("/control/debug/goroutines", &goroutines)
+	if goroutines.Total == 0 || len(goroutines.BySubsystem) == 0 {
+		t.Fatalf("unexpected goroutine report: %+v", goroutines)
+	}
+
+	var streams struct {
+		Data []json.RawMessage `json:"data"`
+	}
+	get("/control/debug/streams", &streams)
+	if streams.Data == nil || len(streams.Data) != 0 {
+		t.Fatalf("expected empty stream list, got %v", streams.Data)
+	}
+
+	var deployments struct {
+		Data []struct {
+			DeploymentID string `json:"deployment_id"`
+			Provider     string `json:"provider"`
+			InFlight     int64  `json:"in_flight"`
+		} `json:"data"`
+	}
+	get("/control/debug/deployments", &deployments)
+	if len(deployments.Data) != 1 || deployments.Data[0].Provider != "stub" {
+		t.Fatalf("unexpected deployments: %+v", deployments.Data)
+	}
+
+	var pipeline struct {
+		Data []json.RawMessage `json:"data"`
+	}
+	get("/control/debug/pipeline", &pipeline)
+	if pipeline.Data == nil {
+		t.Fatal("expected pipeline data")
+	}
+}
+
+// offlineStubProvider fails before any upstream call is made.
+type offlineStubProvider struct {
+	stubProvider
+}
+
+func (p *offlineStubProvider) BuildRequest(context.Context, *types.ChatRequest) (*http.Request, error) {
+	return nil, errors.New("offline")
+}
+
+func TestExplainRouting(t *testing.T) {
+	provider := &offlineStubProvider{stubProvider{name: "stub", models: []string{"gpt-4"}}}
+	client, err := llmux.New(
+		llmux.WithProviderInstance(provider.name, provider, provider.models),
+		llmux.WithRouterTracing(10),
+		llmux.WithRetry(0, 0),
+	)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	defer client.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mux := http.NewServeMux()
+	NewManagementHandler(auth.NewMemoryStore(), nil, logger, NewClientSwapper(client), nil, nil).RegisterRoutes(mux)
+
+	// The request fails, but the routing decision is recorded before the
+	// upstream call.
+	ctx := observability.ContextWithRequestID(context.Background(), "req-explain")
+	_, _ = client.ChatCompletion(ctx, &llmux.ChatRequest{
+		Model:    "gpt-4",
+		Messages: []llmux.ChatMessage{{Role: "user", Content: json.RawMessage(`"hi"`)}},
+	})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, addTestAuthContext(httptest.NewRequest(http.MethodGet, "/router/explain/req-explain", http.NoBody)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var decision llmux.RoutingDecision
+	if err := json.Unmarshal(rec.Body.Bytes(), &decision); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decision.RequestID != "req-explain" || len(decision.Attempts) == 0 || decision.Attempts[0].Selected == "" {
+		t.Fatalf("unexpected decision: %+v", decision)
+	}
+	if len(decision.Attempts[0].Candidates) != 1 || len(decision.Attempts[0].Filters) == 0 {
+		t.Fatalf("unexpected attempt: %+v", decision.Attempts[0])
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, addTestAuthContext(httptest.NewRequest(http.MethodGet, "/router/explain/unknown", http.NoBody)))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown request status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
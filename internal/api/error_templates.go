@@ -0,0 +1,146 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/observability"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+// ErrorTemplateMetadataKey is the team/key metadata field holding an ErrorTemplateConfig.
+const ErrorTemplateMetadataKey = "error_templates"
+
+// ErrorTemplateConfig controls how gateway errors are presented to a tenant's end users.
+// Templates use text/template syntax over errorTemplateData, e.g.
+// "Request failed ({{.Type}}), reference {{.RequestID}}".
+//
+// Lookup order for a message template: Locales[locale][type], Locales[locale]["default"],
+// Templates[type], Templates["default"]. Without a match the original message is used.
+type ErrorTemplateConfig struct {
+	// IncludeProviderDetail keeps the upstream provider's message. When false,
+	// the message is replaced with a generic description of the error type.
+	IncludeProviderDetail *bool `json:"include_provider_detail,omitempty"`
+
+	// Templates maps an error type (e.g. "rate_limit_error") or "default" to a template.
+	Templates map[string]string `json:"templates,omitempty"`
+
+	// Locales maps a locale ("cn", "i18n") to per-type templates.
+	Locales map[string]map[string]string `json:"locales,omitempty"`
+}
+
+// errorTemplateData is the data passed to error templates.
+type errorTemplateData struct {
+	Message   string
+	Type      string
+	Status    int
+	Provider  string
+	Model     string
+	RequestID string
+}
+
+// genericErrorMessages replace provider detail when a tenant opts out of it.
+var genericErrorMessages = map[string]string{
+	llmerrors.TypeAuthentication:     "authentication failed",
+	llmerrors.TypeRateLimit:          "rate limit exceeded, please retry later",
+	llmerrors.TypeInvalidRequest:     "invalid request",
+	llmerrors.TypeNotFound:           "resource not found",
+	llmerrors.TypeTimeout:            "request timed out",
+	llmerrors.TypeServiceUnavailable: "service temporarily unavailable",
+	llmerrors.TypeInternalError:      "internal server error",
+	llmerrors.TypeContextLength:      "context length exceeded",
+	llmerrors.TypeContentPolicy:      "request rejected by content policy",
+	llmerrors.TypePermissionDenied:   "permission denied",
+	llmerrors.TypeInsufficientQuota:  "insufficient quota",
+}
+
+// errorTemplateConfigFromContext returns the template config of the caller's
+// team, falling back to the API key. Returns nil when none is configured.
+func errorTemplateConfigFromContext(ctx context.Context) *ErrorTemplateConfig {
+	authCtx := auth.GetAuthContext(ctx)
+	if authCtx == nil {
+		return nil
+	}
+	if authCtx.Team != nil {
+		if cfg := parseErrorTemplateConfig(authCtx.Team.Metadata); cfg != nil {
+			return cfg
+		}
+	}
+	if authCtx.APIKey != nil {
+		return parseErrorTemplateConfig(authCtx.APIKey.Metadata)
+	}
+	return nil
+}
+
+func parseErrorTemplateConfig(metadata auth.Metadata) *ErrorTemplateConfig {
+	raw, ok := metadata[ErrorTemplateMetadataKey]
+	if !ok || raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var cfg ErrorTemplateConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil
+	}
+	return &cfg
+}
+
+// template returns the best matching template for the locale and error type.
+func (c *ErrorTemplateConfig) template(locale, errType string) string {
+	if byType, ok := c.Locales[locale]; ok {
+		if tmpl := byType[errType]; tmpl != "" {
+			return tmpl
+		}
+		if tmpl := byType["default"]; tmpl != "" {
+			return tmpl
+		}
+	}
+	if tmpl := c.Templates[errType]; tmpl != "" {
+		return tmpl
+	}
+	return c.Templates["default"]
+}
+
+// render returns the tenant-facing message for llmErr. Template errors fall
+// back to the (possibly generic) message so a bad template never hides the error.
+func (c *ErrorTemplateConfig) render(r *http.Request, llmErr *llmerrors.LLMError) string {
+	data := errorTemplateData{
+		Message:  llmErr.Message,
+		Type:     llmErr.Type,
+		Status:   llmErr.HTTPStatusCode(),
+		Provider: llmErr.Provider,
+		Model:    llmErr.Model,
+	}
+	if c.IncludeProviderDetail != nil && !*c.IncludeProviderDetail {
+		if generic, ok := genericErrorMessages[llmErr.Type]; ok {
+			data.Message = generic
+		}
+		data.Provider = ""
+	}
+	if r != nil {
+		data.RequestID = observability.RequestIDFromContext(r.Context())
+	}
+
+	text := c.template(detectLocaleFromRequest(r), llmErr.Type)
+	if strings.TrimSpace(text) == "" {
+		return data.Message
+	}
+	tmpl, err := template.New("error").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return data.Message
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return data.Message
+	}
+	return buf.String()
+}
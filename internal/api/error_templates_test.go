@@ -0,0 +1,78 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+func writeTemplatedError(t *testing.T, metadata auth.Metadata, locale string, err error) ErrorDetail {
+	t.Helper()
+	h := &ClientHandler{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	if locale != "" {
+		req.Header.Set("X-LLMux-Locale", locale)
+	}
+	authCtx := &auth.AuthContext{
+		APIKey: &auth.APIKey{ID: "key-1"},
+		Team:   &auth.Team{ID: "team-1", Metadata: metadata},
+	}
+	req = req.WithContext(context.WithValue(req.Context(), auth.AuthContextKey, authCtx))
+
+	rr := httptest.NewRecorder()
+	h.writeError(rr, req, err)
+
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	return resp.Error
+}
+
+func TestWriteError_AppliesTeamTemplate(t *testing.T) {
+	metadata := auth.Metadata{
+		ErrorTemplateMetadataKey: map[string]any{
+			"include_provider_detail": false,
+			"templates": map[string]any{
+				"rate_limit_error": "Busy right now: {{.Message}} ({{.Status}})",
+			},
+		},
+	}
+
+	detail := writeTemplatedError(t, metadata, "", llmerrors.NewRateLimitError("openai", "gpt-4o", "org quota 123 exceeded"))
+	assert.Equal(t, "Busy right now: rate limit exceeded, please retry later (429)", detail.Message)
+	assert.Equal(t, llmerrors.TypeRateLimit, detail.Type)
+}
+
+func TestWriteError_LocalizedTemplate(t *testing.T) {
+	metadata := auth.Metadata{
+		ErrorTemplateMetadataKey: map[string]any{
+			"templates": map[string]any{"default": "failed: {{.Message}}"},
+			"locales":   map[string]any{"cn": map[string]any{"default": "请求失败：{{.Type}}"}},
+		},
+	}
+
+	err := llmerrors.NewTimeoutError("openai", "gpt-4o", "upstream timeout")
+	assert.Equal(t, "请求失败：timeout_error", writeTemplatedError(t, metadata, "zh-CN", err).Message)
+	assert.Equal(t, "failed: upstream timeout", writeTemplatedError(t, metadata, "en", err).Message)
+}
+
+func TestWriteError_InvalidTemplateFallsBackToMessage(t *testing.T) {
+	metadata := auth.Metadata{
+		ErrorTemplateMetadataKey: map[string]any{
+			"templates": map[string]any{"default": "{{.Broken"},
+		},
+	}
+
+	detail := writeTemplatedError(t, metadata, "", llmerrors.NewInvalidRequestError("", "", "model is required"))
+	assert.Equal(t, "model is required", detail.Message)
+}
@@ -0,0 +1,142 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/governance"
+	"github.com/blueberrycongee/llmux/internal/provenance"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+// maxIdempotencyKeyLength bounds the keys kept in the response store.
+const maxIdempotencyKeyLength = 255
+
+// Error codes for rejected Idempotency-Keys.
+const (
+	codeIdempotencyKeyInUse  = "idempotency_key_in_use"
+	codeIdempotencyKeyReused = "idempotency_key_reused"
+)
+
+// replayedHeaders are the response headers stored with an idempotent response.
+var replayedHeaders = []string{
+	"Content-Type",
+	cacheStatusHeader,
+	guardrailsHeader,
+	provenance.HeaderProvenance,
+	provenance.HeaderSignature,
+}
+
+// beginIdempotentRequest handles the Idempotency-Key of a request. It returns
+// ok=false when it has already written the response, either a replay of the
+// key's stored response or an error. Otherwise the returned writer records
+// the response and done must be called once the handler has written it, so
+// successful responses are stored and failed ones free the key for a retry.
+func (h *ClientHandler) beginIdempotentRequest(w http.ResponseWriter, r *http.Request, prefix, model string, body []byte) (http.ResponseWriter, func(), bool) {
+	noop := func() {}
+	key := r.Header.Get(idempotencyKeyHeader)
+	if key == "" || h.governance == nil {
+		return w, noop, true
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", model, "Idempotency-Key is too long"))
+		return w, noop, false
+	}
+
+	sum := sha256.Sum256(body)
+	pending, stored, err := h.governance.BeginIdempotentRequest(r.Context(), idempotencyScope(r)+":"+prefix+":"+key, hex.EncodeToString(sum[:]))
+	switch {
+	case errors.Is(err, governance.ErrIdempotencyInProgress):
+		llmErr := llmerrors.NewInvalidRequestError("gateway", model, err.Error())
+		llmErr.StatusCode = http.StatusConflict
+		llmErr.Code = codeIdempotencyKeyInUse
+		h.writeError(w, r, llmErr)
+		return w, noop, false
+	case errors.Is(err, governance.ErrIdempotencyKeyReused):
+		llmErr := llmerrors.NewInvalidRequestError("gateway", model, err.Error())
+		llmErr.Code = codeIdempotencyKeyReused
+		h.writeError(w, r, llmErr)
+		return w, noop, false
+	case err != nil:
+		// Serve the request without idempotency rather than failing it.
+		h.logger.Warn("idempotency store unavailable", "error", err)
+		return w, noop, true
+	}
+
+	if stored != nil {
+		for name, value := range stored.Header {
+			w.Header().Set(name, value)
+		}
+		w.Header().Set(idempotentReplayedHeader, "true")
+		w.WriteHeader(stored.Status)
+		if _, err := w.Write(stored.Body); err != nil {
+			h.logger.Error("failed to write response", "error", err)
+		}
+		return w, noop, false
+	}
+	if pending == nil {
+		return w, noop, true
+	}
+
+	rec := &idempotencyRecorder{ResponseWriter: w, status: http.StatusOK}
+	done := func() {
+		// The client may be gone; the key must still be settled.
+		ctx := context.WithoutCancel(r.Context())
+		if rec.status != http.StatusOK {
+			pending.Release(ctx)
+			return
+		}
+		header := make(map[string]string, len(replayedHeaders))
+		for _, name := range replayedHeaders {
+			if value := rec.Header().Get(name); value != "" {
+				header[name] = value
+			}
+		}
+		pending.Complete(ctx, &governance.StoredResponse{
+			Status: rec.status,
+			Header: header,
+			Body:   rec.body.Bytes(),
+		})
+	}
+	return rec, done, true
+}
+
+// idempotencyScope keeps Idempotency-Keys of different tenants apart.
+func idempotencyScope(r *http.Request) string {
+	authCtx := auth.GetAuthContext(r.Context())
+	switch {
+	case authCtx == nil:
+	case authCtx.APIKey != nil:
+		return "key:" + authCtx.APIKey.ID
+	case authCtx.User != nil:
+		return "user:" + authCtx.User.ID
+	}
+	return "anonymous"
+}
+
+// idempotencyRecorder passes a response through while keeping a copy of it.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *idempotencyRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *idempotencyRecorder) Write(p []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(p)
+	return rec.ResponseWriter.Write(p)
+}
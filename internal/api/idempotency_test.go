@@ -0,0 +1,87 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/governance"
+)
+
+func TestClientHandler_IdempotencyKeyReplay(t *testing.T) {
+	mock := newMockOpenAIServer()
+	defer mock.Close()
+	var upstreamCalls atomic.Int32
+	counting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls.Add(1)
+		mock.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer counting.Close()
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             counting.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	store := auth.NewMemoryStore()
+	apiKey := &auth.APIKey{ID: "key-1", IsActive: true}
+	require.NoError(t, store.CreateAPIKey(context.Background(), apiKey))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := governance.NewEngine(governance.Config{Enabled: true, IdempotencyWindow: time.Minute},
+		governance.WithStore(store),
+		governance.WithLogger(logger),
+		governance.WithResponseStore(governance.NewMemoryResponseStore()),
+	)
+	handler := NewClientHandler(client, logger, &ClientHandlerConfig{Store: store, Governance: engine})
+
+	send := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader([]byte(body)))
+		req.Header.Set(idempotencyKeyHeader, key)
+		req = req.WithContext(auth.WithAuthContext(req.Context(), &auth.AuthContext{APIKey: apiKey}))
+		rec := httptest.NewRecorder()
+		handler.ChatCompletions(rec, req)
+		return rec
+	}
+
+	body := `{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}]}`
+	first := send("retry-1", body)
+	require.Equal(t, http.StatusOK, first.Code, first.Body.String())
+	require.Empty(t, first.Header().Get(idempotentReplayedHeader))
+
+	replay := send("retry-1", body)
+	require.Equal(t, http.StatusOK, replay.Code)
+	require.Equal(t, "true", replay.Header().Get(idempotentReplayedHeader))
+	require.Equal(t, first.Body.String(), replay.Body.String())
+	require.Equal(t, "application/json", replay.Header().Get("Content-Type"))
+	require.Equal(t, int32(1), upstreamCalls.Load())
+
+	stats, err := store.GetUsageStats(context.Background(), auth.UsageFilter{EndTime: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), stats.TotalRequests)
+
+	reused := send("retry-1", `{"model":"gpt-4o","messages":[{"role":"user","content":"bye"}]}`)
+	require.Equal(t, http.StatusBadRequest, reused.Code)
+	require.Contains(t, reused.Body.String(), codeIdempotencyKeyReused)
+
+	send("retry-2", body)
+	require.Equal(t, int32(2), upstreamCalls.Load())
+}
@@ -3,6 +3,7 @@
 package api //nolint:revive // package name is intentional
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -48,6 +49,8 @@ func (h *InvitationHandler) RegisterInvitationRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /invitation/list", h.ListInvitations)
 	mux.HandleFunc("POST /invitation/deactivate", h.DeactivateInvitation)
 	mux.HandleFunc("POST /invitation/delete", h.DeleteInvitation)
+	mux.HandleFunc("POST /invitation/resend", h.ResendInvitation)
+	mux.HandleFunc("POST /invitation/revoke", h.RevokeInvitation)
 }
 
 // CreateInvitationRequest represents a request to create an invitation link.
@@ -110,13 +113,21 @@ func (h *InvitationHandler) CreateInvitation(w http.ResponseWriter, r *http.Requ
 	}
 
 	link, rawToken, err := h.service.CreateInvitationLink(r.Context(), createReq)
+	if errors.Is(err, auth.ErrInvitationExpiryTooLong) {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to create invitation link", "error", err)
 		h.writeError(w, r, http.StatusInternalServerError, "failed to create invitation link")
 		return
 	}
 
-	resp := CreateInvitationResponse{
+	h.writeJSON(w, http.StatusOK, newCreateInvitationResponse(link, rawToken))
+}
+
+func newCreateInvitationResponse(link *auth.InvitationLink, rawToken string) CreateInvitationResponse {
+	return CreateInvitationResponse{
 		ID:             link.ID,
 		Token:          rawToken,
 		TeamID:         link.TeamID,
@@ -128,8 +139,6 @@ func (h *InvitationHandler) CreateInvitation(w http.ResponseWriter, r *http.Requ
 		Description:    link.Description,
 		CreatedAt:      link.CreatedAt,
 	}
-
-	h.writeJSON(w, http.StatusOK, resp)
 }
 
 // AcceptInvitationRequest represents a request to accept an invitation.
@@ -290,6 +299,79 @@ func (h *InvitationHandler) DeactivateInvitation(w http.ResponseWriter, r *http.
 	})
 }
 
+// ResendInvitationRequest represents a request to reissue an invitation.
+type ResendInvitationRequest struct {
+	ID        string `json:"id"`
+	ExpiresIn int    `json:"expires_in,omitempty"` // Hours until expiration; 0 keeps the original window
+}
+
+// ResendInvitation handles POST /invitation/resend. The previous token stops
+// working and a new one is returned.
+func (h *InvitationHandler) ResendInvitation(w http.ResponseWriter, r *http.Request) {
+	var req ResendInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.ID == "" {
+		h.writeError(w, r, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	link, rawToken, err := h.service.ResendInvitation(r.Context(), req.ID, time.Duration(req.ExpiresIn)*time.Hour)
+	if errors.Is(err, auth.ErrInvitationExpiryTooLong) {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to resend invitation", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to resend invitation")
+		return
+	}
+	if link == nil {
+		h.writeError(w, r, http.StatusNotFound, "invitation not found")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newCreateInvitationResponse(link, rawToken))
+}
+
+// RevokeInvitationRequest represents a request to revoke an invitation.
+type RevokeInvitationRequest struct {
+	ID string `json:"id"`
+}
+
+// RevokeInvitation handles POST /invitation/revoke
+func (h *InvitationHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	var req RevokeInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.ID == "" {
+		h.writeError(w, r, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	link, err := h.service.RevokeInvitation(r.Context(), req.ID)
+	if err != nil {
+		h.logger.Error("failed to revoke invitation", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to revoke invitation")
+		return
+	}
+	if link == nil {
+		h.writeError(w, r, http.StatusNotFound, "invitation not found")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]string{
+		"id":     link.ID,
+		"status": "revoked",
+	})
+}
+
 // DeleteInvitationRequest represents a request to delete invitations.
 type DeleteInvitationRequest struct {
 	IDs []string `json:"ids"`
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/stretchr/testify/require"
@@ -81,3 +82,47 @@ func TestInvitationCreate_DefaultsToSystemWhenNoAuthContext(t *testing.T) {
 	require.Len(t, links, 1)
 	require.Equal(t, "system", links[0].CreatedBy)
 }
+
+func TestInvitationResendAndRevoke(t *testing.T) {
+	inviteStore := auth.NewMemoryInvitationLinkStore()
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError}))
+	service := auth.NewInvitationService(inviteStore, auth.NewMemoryStore(), logger)
+	service.SetPolicy(auth.InvitationPolicy{MaxExpiry: 24 * time.Hour})
+	handler := NewInvitationHandler(service, inviteStore, noopLogger{})
+
+	do := func(h http.HandlerFunc, path string, body any) *httptest.ResponseRecorder {
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		h(rr, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw)))
+		return rr
+	}
+
+	teamID := "team-1"
+	rr := do(handler.CreateInvitation, "/invitation/new", CreateInvitationRequest{TeamID: &teamID, ExpiresIn: 48})
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = do(handler.CreateInvitation, "/invitation/new", CreateInvitationRequest{TeamID: &teamID})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var created CreateInvitationResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	require.NotNil(t, created.ExpiresAt)
+
+	rr = do(handler.ResendInvitation, "/invitation/resend", ResendInvitationRequest{ID: created.ID})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resent CreateInvitationResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resent))
+	require.Equal(t, created.ID, resent.ID)
+	require.NotEqual(t, created.Token, resent.Token)
+
+	rr = do(handler.RevokeInvitation, "/invitation/revoke", RevokeInvitationRequest{ID: created.ID})
+	require.Equal(t, http.StatusOK, rr.Code)
+	link, err := inviteStore.GetInvitationLink(context.Background(), created.ID)
+	require.NoError(t, err)
+	require.False(t, link.IsActive)
+
+	rr = do(handler.RevokeInvitation, "/invitation/revoke", RevokeInvitationRequest{ID: "missing"})
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	rr = do(handler.ResendInvitation, "/invitation/resend", ResendInvitationRequest{ID: "missing"})
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
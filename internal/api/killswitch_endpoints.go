@@ -0,0 +1,161 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/governance"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+type killSwitchRequest struct {
+	Scope  governance.BlockScope `json:"scope"`
+	Target string                `json:"target"`
+	Reason string                `json:"reason"`
+}
+
+// SetKillSwitch sets the kill switch managed by /control/killswitch.
+func (h *ManagementHandler) SetKillSwitch(ks *governance.KillSwitch) {
+	h.killSwitch = ks
+}
+
+// ListBlocks lists active emergency blocks.
+func (h *ManagementHandler) ListBlocks(w http.ResponseWriter, r *http.Request) {
+	if h.killSwitch == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "kill switch not available")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"data": h.killSwitch.List(),
+	})
+}
+
+// CreateBlock blocks all traffic, a model group or a team immediately.
+func (h *ManagementHandler) CreateBlock(w http.ResponseWriter, r *http.Request) {
+	if h.killSwitch == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "kill switch not available")
+		return
+	}
+
+	var req killSwitchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	block, err := h.killSwitch.Block(r.Context(), governance.Block{
+		Scope:     req.Scope,
+		Target:    req.Target,
+		Reason:    req.Reason,
+		CreatedBy: auditActorFromContext(auth.GetAuthContext(r.Context())).id,
+	})
+	if errors.Is(err, governance.ErrInvalidBlock) {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to engage kill switch", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to engage kill switch")
+		return
+	}
+	h.logger.Warn("kill switch engaged", "scope", block.Scope, "target", block.Target, "reason", block.Reason, "created_by", block.CreatedBy)
+	h.auditControlAction(r, auth.AuditActionCreate, auth.AuditObjectConfig, killSwitchObjectID(block.Scope, block.Target), true, nil, map[string]any{
+		"scope":  block.Scope,
+		"target": block.Target,
+		"reason": block.Reason,
+	}, map[string]any{
+		"action": "kill_switch_block",
+	}, "")
+
+	h.writeJSON(w, http.StatusOK, block)
+}
+
+// DeleteBlock lifts an emergency block.
+func (h *ManagementHandler) DeleteBlock(w http.ResponseWriter, r *http.Request) {
+	if h.killSwitch == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "kill switch not available")
+		return
+	}
+
+	var req killSwitchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	block, ok, err := h.killSwitch.Unblock(r.Context(), req.Scope, req.Target)
+	if err != nil {
+		h.logger.Error("failed to release kill switch", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to release kill switch")
+		return
+	}
+	if !ok {
+		h.writeError(w, r, http.StatusNotFound, "block not found")
+		return
+	}
+	h.logger.Warn("kill switch released", "scope", block.Scope, "target", block.Target)
+	h.auditControlAction(r, auth.AuditActionDelete, auth.AuditObjectConfig, killSwitchObjectID(block.Scope, block.Target), true, map[string]any{
+		"scope":  block.Scope,
+		"target": block.Target,
+		"reason": block.Reason,
+	}, nil, map[string]any{
+		"action": "kill_switch_release",
+		"reason": req.Reason,
+	}, "")
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"released": block,
+	})
+}
+
+func killSwitchObjectID(scope governance.BlockScope, target string) string {
+	if target == "" {
+		return "killswitch:" + string(scope)
+	}
+	return "killswitch:" + string(scope) + ":" + target
+}
+
+// checkKillSwitch rejects requests matched by an active emergency block.
+// Team blocks are reported as permission errors, global and model blocks as
+// service unavailable; the block reason is included in the message.
+func (h *ClientHandler) checkKillSwitch(ctx context.Context, model string) error {
+	return killSwitchError(ctx, h.killSwitch, model)
+}
+
+// killSwitchError returns the error for a request to model matched by a
+// block of ks, or nil.
+func killSwitchError(ctx context.Context, ks *governance.KillSwitch, model string) error {
+	if ks == nil {
+		return nil
+	}
+	_, canonicalModel := types.SplitProviderModel(model)
+	block, ok := ks.Check(requestTeamID(auth.GetAuthContext(ctx)), model, canonicalModel)
+	if !ok {
+		return nil
+	}
+	if block.Scope == governance.BlockScopeTeam {
+		return llmerrors.NewPermissionError("gateway", model, block.Message())
+	}
+	err := llmerrors.NewServiceUnavailableError("gateway", model, block.Message())
+	err.Retryable = false
+	return err
+}
+
+// requestTeamID returns the team the request is billed to, if any.
+func requestTeamID(authCtx *auth.AuthContext) string {
+	if authCtx == nil {
+		return ""
+	}
+	if authCtx.APIKey != nil && authCtx.APIKey.TeamID != nil {
+		return *authCtx.APIKey.TeamID
+	}
+	if authCtx.Team != nil {
+		return authCtx.Team.ID
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/governance"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+func TestKillSwitchEndpoints(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ks := governance.NewKillSwitch()
+	mgmt := NewManagementHandler(auth.NewMemoryStore(), nil, logger, nil, nil, nil)
+	mgmt.SetKillSwitch(ks)
+	mux := http.NewServeMux()
+	mgmt.RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/control/killswitch",
+		strings.NewReader(`{"scope":"model","target":"gpt-4o","reason":"provider outage"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("block status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	h := &ClientHandler{logger: logger, killSwitch: ks}
+	r := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	_, err := h.evaluateGovernance(r.Context(), r, "openai/gpt-4o", "", nil, "", governance.CallTypeChatCompletion)
+	var llmErr *llmerrors.LLMError
+	if !errors.As(err, &llmErr) || llmErr.StatusCode != http.StatusServiceUnavailable || !strings.Contains(llmErr.Message, "provider outage") {
+		t.Fatalf("expected blocked request with reason, got %v", err)
+	}
+
+	teamID := "team-1"
+	ctx := auth.WithAuthContext(r.Context(), &auth.AuthContext{APIKey: &auth.APIKey{ID: "k1", TeamID: &teamID}})
+	if _, err := ks.Block(r.Context(), governance.Block{Scope: governance.BlockScopeTeam, Target: teamID, Reason: "abuse"}); err != nil {
+		t.Fatalf("Block() error = %v", err)
+	}
+	_, err = h.evaluateGovernance(ctx, r, "gpt-4o-mini", "", nil, "", governance.CallTypeChatCompletion)
+	if !errors.As(err, &llmErr) || llmErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected team block, got %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/control/killswitch",
+		strings.NewReader(`{"scope":"model","target":"gpt-4o"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("release status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if _, err := h.evaluateGovernance(r.Context(), r, "gpt-4o", "", nil, "", governance.CallTypeChatCompletion); err != nil {
+		t.Fatalf("expected traffic to resume after release, got %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/control/killswitch", strings.NewReader(`{"scope":"global"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing reason status = %d, want 400", rec.Code)
+	}
+}
@@ -3,6 +3,7 @@
 package api //nolint:revive // package name is intentional
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -12,16 +13,29 @@ import (
 
 	"github.com/blueberrycongee/llmux/internal/auth"
 	"github.com/blueberrycongee/llmux/internal/config"
+	"github.com/blueberrycongee/llmux/internal/governance"
+	"github.com/blueberrycongee/llmux/internal/mcp"
+	"github.com/blueberrycongee/llmux/internal/metrics"
+	"github.com/blueberrycongee/llmux/internal/observability"
+	"github.com/blueberrycongee/llmux/internal/provenance"
 )
 
 // ManagementHandler handles management API endpoints.
 type ManagementHandler struct {
-	store         auth.Store
-	auditStore    auth.AuditLogStore
-	auditLogger   *auth.AuditLogger
-	clientSwapper *ClientSwapper
-	configManager *config.Manager
-	logger        *slog.Logger
+	store           auth.Store
+	auditStore      auth.AuditLogStore
+	auditLogger     *auth.AuditLogger
+	clientSwapper   *ClientSwapper
+	configManager   *config.Manager
+	logger          *slog.Logger
+	timeSeries      *metrics.TimeSeries
+	signer          *provenance.Signer
+	killSwitch      *governance.KillSwitch
+	governance      *governance.Engine
+	payloadLogs     auth.PayloadLogStore
+	requestTail     *observability.RequestTail
+	modelReloader   func(ctx context.Context) error
+	configValidator ConfigValidator
 }
 
 // NewManagementHandler creates a new management handler.
@@ -36,32 +50,57 @@ func NewManagementHandler(store auth.Store, auditStore auth.AuditLogStore, logge
 	}
 }
 
+// SetUsageTimeSeries sets the usage time series served by /global/usage/timeseries.
+func (h *ManagementHandler) SetUsageTimeSeries(ts *metrics.TimeSeries) {
+	h.timeSeries = ts
+}
+
+// SetResponseSigner sets the signer whose signatures /provenance/verify checks.
+func (h *ManagementHandler) SetResponseSigner(signer *provenance.Signer) {
+	h.signer = signer
+}
+
+// SetGovernance sets the governance engine whose tag budgets /spend/tags
+// and /spend/report show.
+func (h *ManagementHandler) SetGovernance(engine *governance.Engine) {
+	h.governance = engine
+}
+
+// SetPayloadLogStore sets the store of retained payloads served by
+// /logs/requests/{request_id}.
+func (h *ManagementHandler) SetPayloadLogStore(store auth.PayloadLogStore) {
+	h.payloadLogs = store
+}
+
 // ============================================================================
 // API Key Management Endpoints
 // ============================================================================
 
 // GenerateKeyRequest represents a request to generate a new API key.
 type GenerateKeyRequest struct {
-	Name             string             `json:"key_name,omitempty"`
-	KeyAlias         *string            `json:"key_alias,omitempty"`
-	TeamID           *string            `json:"team_id,omitempty"`
-	UserID           *string            `json:"user_id,omitempty"`
-	OrganizationID   *string            `json:"organization_id,omitempty"`
-	Models           []string           `json:"models,omitempty"`
-	MaxBudget        *float64           `json:"max_budget,omitempty"`
-	SoftBudget       *float64           `json:"soft_budget,omitempty"`
-	BudgetDuration   string             `json:"budget_duration,omitempty"`
-	TPMLimit         *int64             `json:"tpm_limit,omitempty"`
-	RPMLimit         *int64             `json:"rpm_limit,omitempty"`
-	MaxParallelReqs  *int               `json:"max_parallel_requests,omitempty"`
-	ModelMaxBudget   map[string]float64 `json:"model_max_budget,omitempty"`
-	ModelTPMLimit    map[string]int64   `json:"model_tpm_limit,omitempty"`
-	ModelRPMLimit    map[string]int64   `json:"model_rpm_limit,omitempty"`
-	Duration         string             `json:"duration,omitempty"` // Key expiry duration
-	Metadata         auth.Metadata      `json:"metadata,omitempty"`
-	KeyType          string             `json:"key_type,omitempty"` // llm_api, management, read_only
-	AutoRotate       bool               `json:"auto_rotate,omitempty"`
-	RotationInterval string             `json:"rotation_interval,omitempty"` // e.g., "30d", "90d"
+	Name                   string             `json:"key_name,omitempty"`
+	KeyAlias               *string            `json:"key_alias,omitempty"`
+	TeamID                 *string            `json:"team_id,omitempty"`
+	UserID                 *string            `json:"user_id,omitempty"`
+	OrganizationID         *string            `json:"organization_id,omitempty"`
+	Models                 []string           `json:"models,omitempty"`
+	AllowedCIDRs           []string           `json:"allowed_cidrs,omitempty"` // Source IPs or CIDRs allowed to use the key
+	MaxBudget              *float64           `json:"max_budget,omitempty"`
+	SoftBudget             *float64           `json:"soft_budget,omitempty"`
+	BudgetDuration         string             `json:"budget_duration,omitempty"`
+	TPMLimit               *int64             `json:"tpm_limit,omitempty"`
+	RPMLimit               *int64             `json:"rpm_limit,omitempty"`
+	MaxParallelReqs        *int               `json:"max_parallel_requests,omitempty"`
+	ModelMaxBudget         map[string]float64 `json:"model_max_budget,omitempty"`
+	ModelTPMLimit          map[string]int64   `json:"model_tpm_limit,omitempty"`
+	ModelRPMLimit          map[string]int64   `json:"model_rpm_limit,omitempty"`
+	Duration               string             `json:"duration,omitempty"` // Key expiry duration
+	Metadata               auth.Metadata      `json:"metadata,omitempty"`
+	KeyType                string             `json:"key_type,omitempty"` // llm_api, management, read_only
+	AutoRotate             bool               `json:"auto_rotate,omitempty"`
+	RotationInterval       string             `json:"rotation_interval,omitempty"`          // e.g., "30d", "90d"
+	Sandbox                bool               `json:"sandbox,omitempty"`                    // Serve requests from the mock provider
+	MaxOutputTokensHardCap *int               `json:"max_output_tokens_hard_cap,omitempty"` // Cap completions at this many tokens
 }
 
 // GenerateKeyResponse represents the response after generating a key.
@@ -75,11 +114,14 @@ type GenerateKeyResponse struct {
 	UserID         *string    `json:"user_id,omitempty"`
 	OrganizationID *string    `json:"organization_id,omitempty"`
 	Models         []string   `json:"models,omitempty"`
+	AllowedCIDRs   []string   `json:"allowed_cidrs,omitempty"`
 	MaxBudget      float64    `json:"max_budget,omitempty"`
 	SoftBudget     *float64   `json:"soft_budget,omitempty"`
 	TPMLimit       *int64     `json:"tpm_limit,omitempty"`
 	RPMLimit       *int64     `json:"rpm_limit,omitempty"`
 	ExpiresAt      *time.Time `json:"expires,omitempty"`
+	Sandbox        bool       `json:"sandbox,omitempty"`
+	Temporary      bool       `json:"temporary,omitempty"`
 	CreatedAt      time.Time  `json:"created_at"`
 }
 
@@ -90,6 +132,18 @@ func (h *ManagementHandler) GenerateKey(w http.ResponseWriter, r *http.Request)
 		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
+	if err := auth.ValidateCIDRs(req.AllowedCIDRs); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.MaxOutputTokensHardCap != nil && *req.MaxOutputTokensHardCap < 0 {
+		h.writeError(w, r, http.StatusBadRequest, "max_output_tokens_hard_cap cannot be negative")
+		return
+	}
+	if grantsAdminMetadata(r.Context(), req.Metadata) {
+		h.writeError(w, r, http.StatusForbidden, "only proxy admins can set mcp_tools")
+		return
+	}
 
 	// Generate a new API key
 	rawKey, keyHash, err := auth.GenerateAPIKey()
@@ -110,13 +164,14 @@ func (h *ManagementHandler) GenerateKey(w http.ResponseWriter, r *http.Request)
 		UserID:              req.UserID,
 		OrganizationID:      req.OrganizationID,
 		AllowedModels:       req.Models,
+		AllowedCIDRs:        req.AllowedCIDRs,
 		TPMLimit:            req.TPMLimit,
 		RPMLimit:            req.RPMLimit,
 		MaxParallelRequests: req.MaxParallelReqs,
 		ModelMaxBudget:      req.ModelMaxBudget,
 		ModelTPMLimit:       req.ModelTPMLimit,
 		ModelRPMLimit:       req.ModelRPMLimit,
-		Metadata:            req.Metadata,
+		Metadata:            auth.WithoutReservedMetadata(req.Metadata),
 		IsActive:            true,
 		CreatedAt:           now,
 		UpdatedAt:           now,
@@ -160,6 +215,17 @@ func (h *ManagementHandler) GenerateKey(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	// Mark sandbox keys
+	if req.Sandbox {
+		key.Metadata = ensureMetadata(key.Metadata)
+		key.Metadata["sandbox"] = true
+	}
+
+	if req.MaxOutputTokensHardCap != nil && *req.MaxOutputTokensHardCap > 0 {
+		key.Metadata = ensureMetadata(key.Metadata)
+		key.Metadata["max_output_tokens_hard_cap"] = *req.MaxOutputTokensHardCap
+	}
+
 	// Save to store
 	if err := h.store.CreateAPIKey(r.Context(), key); err != nil {
 		h.logger.Error("failed to create api key", "error", err)
@@ -177,11 +243,13 @@ func (h *ManagementHandler) GenerateKey(w http.ResponseWriter, r *http.Request)
 		UserID:         key.UserID,
 		OrganizationID: key.OrganizationID,
 		Models:         key.AllowedModels,
+		AllowedCIDRs:   key.AllowedCIDRs,
 		MaxBudget:      key.MaxBudget,
 		SoftBudget:     key.SoftBudget,
 		TPMLimit:       key.TPMLimit,
 		RPMLimit:       key.RPMLimit,
 		ExpiresAt:      key.ExpiresAt,
+		Sandbox:        key.IsSandbox(),
 		CreatedAt:      key.CreatedAt,
 	}
 
@@ -190,21 +258,24 @@ func (h *ManagementHandler) GenerateKey(w http.ResponseWriter, r *http.Request)
 
 // UpdateKeyRequest represents a request to update an API key.
 type UpdateKeyRequest struct {
-	Key              string             `json:"key"` // Key ID or hash
-	Name             *string            `json:"key_name,omitempty"`
-	KeyAlias         *string            `json:"key_alias,omitempty"`
-	Models           []string           `json:"models,omitempty"`
-	MaxBudget        *float64           `json:"max_budget,omitempty"`
-	SoftBudget       *float64           `json:"soft_budget,omitempty"`
-	BudgetDuration   *string            `json:"budget_duration,omitempty"`
-	TPMLimit         *int64             `json:"tpm_limit,omitempty"`
-	RPMLimit         *int64             `json:"rpm_limit,omitempty"`
-	MaxParallelReqs  *int               `json:"max_parallel_requests,omitempty"`
-	ModelMaxBudget   map[string]float64 `json:"model_max_budget,omitempty"`
-	Metadata         auth.Metadata      `json:"metadata,omitempty"`
-	Duration         *string            `json:"duration,omitempty"`
-	AutoRotate       *bool              `json:"auto_rotate,omitempty"`
-	RotationInterval *string            `json:"rotation_interval,omitempty"`
+	Key                    string             `json:"key"` // Key ID or hash
+	Name                   *string            `json:"key_name,omitempty"`
+	KeyAlias               *string            `json:"key_alias,omitempty"`
+	Models                 []string           `json:"models,omitempty"`
+	AllowedCIDRs           []string           `json:"allowed_cidrs,omitempty"` // An empty list removes the restriction
+	MaxBudget              *float64           `json:"max_budget,omitempty"`
+	SoftBudget             *float64           `json:"soft_budget,omitempty"`
+	BudgetDuration         *string            `json:"budget_duration,omitempty"`
+	TPMLimit               *int64             `json:"tpm_limit,omitempty"`
+	RPMLimit               *int64             `json:"rpm_limit,omitempty"`
+	MaxParallelReqs        *int               `json:"max_parallel_requests,omitempty"`
+	ModelMaxBudget         map[string]float64 `json:"model_max_budget,omitempty"`
+	Metadata               auth.Metadata      `json:"metadata,omitempty"`
+	Duration               *string            `json:"duration,omitempty"`
+	AutoRotate             *bool              `json:"auto_rotate,omitempty"`
+	RotationInterval       *string            `json:"rotation_interval,omitempty"`
+	Sandbox                *bool              `json:"sandbox,omitempty"`
+	MaxOutputTokensHardCap *int               `json:"max_output_tokens_hard_cap,omitempty"` // 0 removes the cap
 }
 
 // UpdateKey handles POST /key/update
@@ -219,6 +290,14 @@ func (h *ManagementHandler) UpdateKey(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, r, http.StatusBadRequest, "key is required")
 		return
 	}
+	if req.MaxOutputTokensHardCap != nil && *req.MaxOutputTokensHardCap < 0 {
+		h.writeError(w, r, http.StatusBadRequest, "max_output_tokens_hard_cap cannot be negative")
+		return
+	}
+	if grantsAdminMetadata(r.Context(), req.Metadata) {
+		h.writeError(w, r, http.StatusForbidden, "only proxy admins can set mcp_tools")
+		return
+	}
 
 	// Get existing key
 	key, err := h.store.GetAPIKeyByID(r.Context(), req.Key)
@@ -237,6 +316,13 @@ func (h *ManagementHandler) UpdateKey(w http.ResponseWriter, r *http.Request) {
 	if req.Models != nil {
 		key.AllowedModels = req.Models
 	}
+	if req.AllowedCIDRs != nil {
+		if err := auth.ValidateCIDRs(req.AllowedCIDRs); err != nil {
+			h.writeError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		key.AllowedCIDRs = req.AllowedCIDRs
+	}
 	if req.MaxBudget != nil {
 		key.MaxBudget = *req.MaxBudget
 	}
@@ -260,7 +346,7 @@ func (h *ManagementHandler) UpdateKey(w http.ResponseWriter, r *http.Request) {
 		key.ModelMaxBudget = req.ModelMaxBudget
 	}
 	if req.Metadata != nil {
-		key.Metadata = mergeMetadata(key.Metadata, req.Metadata)
+		key.Metadata = mergeMetadata(key.Metadata, auth.WithoutReservedMetadata(req.Metadata))
 	}
 	if req.Duration != nil {
 		key.ExpiresAt = auth.ParseDuration(*req.Duration)
@@ -279,6 +365,20 @@ func (h *ManagementHandler) UpdateKey(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if req.Sandbox != nil {
+		key.Metadata = ensureMetadata(key.Metadata)
+		key.Metadata["sandbox"] = *req.Sandbox
+	}
+
+	if req.MaxOutputTokensHardCap != nil {
+		if *req.MaxOutputTokensHardCap > 0 {
+			key.Metadata = ensureMetadata(key.Metadata)
+			key.Metadata["max_output_tokens_hard_cap"] = *req.MaxOutputTokensHardCap
+		} else {
+			delete(key.Metadata, "max_output_tokens_hard_cap")
+		}
+	}
+
 	key.UpdatedAt = time.Now()
 
 	if err := h.store.UpdateAPIKey(r.Context(), key); err != nil {
@@ -493,6 +593,7 @@ func (h *ManagementHandler) RegenerateKey(w http.ResponseWriter, r *http.Request
 		UserID:         oldKey.UserID,
 		OrganizationID: oldKey.OrganizationID,
 		Models:         oldKey.AllowedModels,
+		AllowedCIDRs:   oldKey.AllowedCIDRs,
 		MaxBudget:      oldKey.MaxBudget,
 		SoftBudget:     oldKey.SoftBudget,
 		TPMLimit:       oldKey.TPMLimit,
@@ -534,6 +635,26 @@ func ensureMetadata(m auth.Metadata) auth.Metadata {
 	return m
 }
 
+// grantsAdminMetadata reports whether m sets the MCP tool allowlist for a
+// caller that does not administer the proxy. The allowlist is a grant, so a
+// user managing their own keys must not be able to widen it. Requests
+// without an auth context come from deployments with auth disabled.
+func grantsAdminMetadata(ctx context.Context, m auth.Metadata) bool {
+	if _, ok := m[mcp.AllowedToolsMetadataKey]; !ok {
+		return false
+	}
+	authCtx := auth.GetAuthContext(ctx)
+	switch {
+	case authCtx == nil:
+		return false
+	case authCtx.User != nil:
+		return authCtx.UserRole != auth.UserRoleProxyAdmin
+	case authCtx.APIKey != nil:
+		return authCtx.APIKey.KeyType != auth.KeyTypeManagement
+	}
+	return true
+}
+
 func mergeMetadata(existing, updated auth.Metadata) auth.Metadata {
 	if existing == nil {
 		return updated
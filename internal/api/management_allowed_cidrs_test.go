@@ -0,0 +1,48 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+func TestManagementKeyAllowedCIDRs(t *testing.T) {
+	store := auth.NewMemoryStore()
+	handler := NewManagementHandler(store, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil)
+
+	do := func(fn http.HandlerFunc, path string, body map[string]any) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		fn(rr, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data)))
+		return rr
+	}
+
+	rr := do(handler.GenerateKey, "/key/generate", map[string]any{"allowed_cidrs": []string{"not-a-cidr"}})
+	require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+
+	rr = do(handler.GenerateKey, "/key/generate", map[string]any{"allowed_cidrs": []string{"10.0.0.0/8", "203.0.113.7"}})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp GenerateKeyResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, []string{"10.0.0.0/8", "203.0.113.7"}, resp.AllowedCIDRs)
+
+	rr = do(handler.UpdateKey, "/key/update", map[string]any{"key": resp.KeyID, "allowed_cidrs": []string{"10.0.0.0/33"}})
+	require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+
+	rr = do(handler.UpdateKey, "/key/update", map[string]any{"key": resp.KeyID, "allowed_cidrs": []string{}})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	key, err := store.GetAPIKeyByID(context.Background(), resp.KeyID)
+	require.NoError(t, err)
+	require.Empty(t, key.AllowedCIDRs)
+	require.True(t, key.AllowsIP("192.168.1.1"))
+}
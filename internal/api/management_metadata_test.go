@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+func TestManagementKey_StripsReservedMetadata(t *testing.T) {
+	store := auth.NewMemoryStore()
+	handler := NewManagementHandler(store, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil)
+
+	do := func(fn http.HandlerFunc, path string, body map[string]any) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		fn(rr, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data)))
+		return rr
+	}
+	reserved := map[string]any{"virtual": true, "temporary": true, "sandbox": true, "team": "search"}
+	requireStripped := func(keyID string) {
+		t.Helper()
+		key, err := store.GetAPIKeyByID(context.Background(), keyID)
+		require.NoError(t, err)
+		require.False(t, key.IsVirtual())
+		require.False(t, key.IsSandbox())
+		require.NotContains(t, key.Metadata, "virtual")
+		require.Equal(t, "search", key.Metadata["team"])
+	}
+
+	rr := do(handler.GenerateKey, "/key/generate", map[string]any{"metadata": reserved})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp GenerateKeyResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	requireStripped(resp.KeyID)
+	key, err := store.GetAPIKeyByID(context.Background(), resp.KeyID)
+	require.NoError(t, err)
+	require.False(t, key.IsTemporary())
+
+	rr = do(handler.UpdateKey, "/key/update", map[string]any{"key": resp.KeyID, "metadata": reserved})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	requireStripped(resp.KeyID)
+
+	rr = postTemporaryKey(t, handler, map[string]any{"models": []string{"gpt-4"}, "duration": "1h", "metadata": reserved})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	requireStripped(resp.KeyID)
+}
+
+func TestManagementKey_OnlyAdminsSetMCPTools(t *testing.T) {
+	store := auth.NewMemoryStore()
+	handler := NewManagementHandler(store, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil)
+	existing := &auth.APIKey{ID: "key-1", KeyHash: "hash-1", IsActive: true}
+	require.NoError(t, store.CreateAPIKey(context.Background(), existing))
+
+	do := func(authCtx *auth.AuthContext, fn http.HandlerFunc, body map[string]any) int {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/key/generate", bytes.NewReader(data))
+		req = req.WithContext(auth.WithAuthContext(req.Context(), authCtx))
+		rr := httptest.NewRecorder()
+		fn(rr, req)
+		return rr.Code
+	}
+	grant := map[string]any{"mcp_tools": []string{"*"}}
+	internalUser := &auth.AuthContext{User: &auth.User{ID: "user-1"}, UserRole: auth.UserRoleInternalUser}
+	llmKey := &auth.AuthContext{APIKey: &auth.APIKey{ID: "key-2", KeyType: auth.KeyTypeLLMAPI}}
+
+	for _, caller := range []*auth.AuthContext{internalUser, llmKey} {
+		require.Equal(t, http.StatusForbidden, do(caller, handler.GenerateKey, map[string]any{"metadata": grant}))
+		require.Equal(t, http.StatusForbidden, do(caller, handler.UpdateKey, map[string]any{"key": "key-1", "metadata": grant}))
+		require.Equal(t, http.StatusForbidden, do(caller, handler.GenerateTemporaryKey,
+			map[string]any{"models": []string{"gpt-4"}, "duration": "1h", "metadata": grant}))
+		require.Equal(t, http.StatusOK, do(caller, handler.GenerateKey, map[string]any{"metadata": map[string]any{"team": "search"}}))
+	}
+
+	admin := &auth.AuthContext{User: &auth.User{ID: "admin-1"}, UserRole: auth.UserRoleProxyAdmin}
+	managementKey := &auth.AuthContext{APIKey: &auth.APIKey{ID: "key-3", KeyType: auth.KeyTypeManagement}}
+	for _, caller := range []*auth.AuthContext{admin, managementKey} {
+		require.Equal(t, http.StatusOK, do(caller, handler.UpdateKey, map[string]any{"key": "key-1", "metadata": grant}))
+	}
+	key, err := store.GetAPIKeyByID(context.Background(), "key-1")
+	require.NoError(t, err)
+	require.Equal(t, []any{"*"}, key.Metadata["mcp_tools"])
+}
@@ -0,0 +1,128 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+// maxTemporaryKeyDuration bounds the lifetime of keys minted by /key/temporary.
+const maxTemporaryKeyDuration = 7 * 24 * time.Hour
+
+// TemporaryKeyRequest mints a short-lived key for CI jobs and notebooks.
+type TemporaryKeyRequest struct {
+	Name           string        `json:"key_name,omitempty"`
+	TeamID         *string       `json:"team_id,omitempty"`
+	UserID         *string       `json:"user_id,omitempty"`
+	OrganizationID *string       `json:"organization_id,omitempty"`
+	Models         []string      `json:"models"` // Required; temporary keys are never unscoped
+	AllowedCIDRs   []string      `json:"allowed_cidrs,omitempty"`
+	Duration       string        `json:"duration"` // Required, e.g. "30m", "1h", "7d"
+	MaxBudget      *float64      `json:"max_budget,omitempty"`
+	TPMLimit       *int64        `json:"tpm_limit,omitempty"`
+	RPMLimit       *int64        `json:"rpm_limit,omitempty"`
+	Metadata       auth.Metadata `json:"metadata,omitempty"`
+}
+
+func (req *TemporaryKeyRequest) validate() (time.Duration, error) {
+	if len(req.Models) == 0 {
+		return 0, fmt.Errorf("models is required for temporary keys")
+	}
+	if req.Duration == "" {
+		return 0, fmt.Errorf("duration is required for temporary keys")
+	}
+	ttl := time.Duration(auth.DurationInSeconds(req.Duration)) * time.Second
+	if ttl <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", req.Duration)
+	}
+	if ttl > maxTemporaryKeyDuration {
+		return 0, fmt.Errorf("duration cannot exceed %s", maxTemporaryKeyDuration)
+	}
+	if req.MaxBudget != nil && *req.MaxBudget < 0 {
+		return 0, fmt.Errorf("max_budget cannot be negative")
+	}
+	if err := auth.ValidateCIDRs(req.AllowedCIDRs); err != nil {
+		return 0, err
+	}
+	return ttl, nil
+}
+
+// GenerateTemporaryKey handles POST /key/temporary. The key is scoped to
+// the requested models, expires after duration and is deleted by the job
+// runner once expired.
+func (h *ManagementHandler) GenerateTemporaryKey(w http.ResponseWriter, r *http.Request) {
+	var req TemporaryKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	ttl, err := req.validate()
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if grantsAdminMetadata(r.Context(), req.Metadata) {
+		h.writeError(w, r, http.StatusForbidden, "only proxy admins can set mcp_tools")
+		return
+	}
+
+	rawKey, keyHash, err := auth.GenerateAPIKey()
+	if err != nil {
+		h.writeError(w, r, http.StatusInternalServerError, "failed to generate api key")
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	key := &auth.APIKey{
+		ID:             auth.GenerateUUID(),
+		KeyHash:        keyHash,
+		KeyPrefix:      auth.ExtractKeyPrefix(rawKey),
+		Name:           req.Name,
+		TeamID:         req.TeamID,
+		UserID:         req.UserID,
+		OrganizationID: req.OrganizationID,
+		AllowedModels:  req.Models,
+		AllowedCIDRs:   req.AllowedCIDRs,
+		KeyType:        auth.KeyTypeLLMAPI,
+		TPMLimit:       req.TPMLimit,
+		RPMLimit:       req.RPMLimit,
+		ExpiresAt:      &expiresAt,
+		Metadata:       ensureMetadata(auth.WithoutReservedMetadata(req.Metadata)),
+		IsActive:       true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if req.MaxBudget != nil {
+		key.MaxBudget = *req.MaxBudget
+	}
+	key.Metadata["temporary"] = true
+
+	if err := h.store.CreateAPIKey(r.Context(), key); err != nil {
+		h.logger.Error("failed to create temporary api key", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to create api key")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, GenerateKeyResponse{
+		Key:            rawKey,
+		KeyID:          key.ID,
+		KeyPrefix:      key.KeyPrefix,
+		Name:           key.Name,
+		TeamID:         key.TeamID,
+		UserID:         key.UserID,
+		OrganizationID: key.OrganizationID,
+		Models:         key.AllowedModels,
+		AllowedCIDRs:   key.AllowedCIDRs,
+		MaxBudget:      key.MaxBudget,
+		TPMLimit:       key.TPMLimit,
+		RPMLimit:       key.RPMLimit,
+		ExpiresAt:      key.ExpiresAt,
+		Temporary:      true,
+		CreatedAt:      key.CreatedAt,
+	})
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+func postTemporaryKey(t *testing.T, handler *ManagementHandler, body map[string]any) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/key/temporary", bytes.NewReader(data))
+	rr := httptest.NewRecorder()
+	handler.GenerateTemporaryKey(rr, req)
+	return rr
+}
+
+func TestManagementTemporaryKey_MintsScopedExpiringKey(t *testing.T) {
+	store := auth.NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewManagementHandler(store, nil, logger, nil, nil, nil)
+
+	rr := postTemporaryKey(t, handler, map[string]any{
+		"key_name":   "ci",
+		"models":     []string{"gpt-4"},
+		"duration":   "1h",
+		"max_budget": 5,
+	})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp GenerateKeyResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.NotEmpty(t, resp.Key)
+	require.True(t, resp.Temporary)
+	require.NotNil(t, resp.ExpiresAt)
+	require.WithinDuration(t, time.Now().Add(time.Hour), *resp.ExpiresAt, time.Minute)
+
+	key, err := store.GetAPIKeyByID(context.Background(), resp.KeyID)
+	require.NoError(t, err)
+	require.True(t, key.IsTemporary())
+	require.Equal(t, []string{"gpt-4"}, key.AllowedModels)
+	require.Equal(t, auth.KeyTypeLLMAPI, key.KeyType)
+	require.InDelta(t, 5.0, key.MaxBudget, 1e-9)
+}
+
+func TestManagementTemporaryKey_Validation(t *testing.T) {
+	handler := NewManagementHandler(auth.NewMemoryStore(), nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil)
+
+	cases := map[string]map[string]any{
+		"missing models":   {"duration": "1h"},
+		"missing duration": {"models": []string{"gpt-4"}},
+		"invalid duration": {"models": []string{"gpt-4"}, "duration": "soon"},
+		"too long":         {"models": []string{"gpt-4"}, "duration": "30d"},
+		"negative budget":  {"models": []string{"gpt-4"}, "duration": "1h", "max_budget": -1},
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			rr := postTemporaryKey(t, handler, body)
+			require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/governance"
+	"github.com/blueberrycongee/llmux/internal/mcp"
+)
+
+// parentRequestIDMetadataKey links the usage logs of agent-loop LLM calls
+// and MCP tool calls to the client request that triggered them.
+const parentRequestIDMetadataKey = "parent_request_id"
+
+// runAgentLoop runs req through the MCP agent loop. Every tool call and
+// every intermediate LLM call (one that asked for tools) is logged as usage
+// of its own, attributed to requestID; the final response is accounted by
+// the caller as the request itself.
+func (h *ClientHandler) runAgentLoop(ctx context.Context, manager mcp.Manager, client *llmux.Client, req *llmux.ChatRequest, requestID string) (*llmux.ChatResponse, error) {
+	executor := mcp.NewAgentExecutor(manager, 0, h.logger,
+		mcp.WithToolCallObserver(func(ctx context.Context, call llmux.ToolCall, result mcp.ToolExecutionResult) {
+			h.accountToolCall(ctx, requestID, call, result)
+		}),
+	)
+
+	step := 0
+	return executor.Execute(ctx, req, func(execCtx context.Context, r *llmux.ChatRequest) (*llmux.ChatResponse, error) {
+		start := time.Now()
+		resp, err := client.ChatCompletion(execCtx, r)
+		if err == nil && mcp.HasToolCalls(resp) {
+			step++
+			h.accountAgentStep(execCtx, client, r, resp, requestID, step, start)
+		}
+		return resp, err
+	})
+}
+
+// accountAgentStep logs an intermediate agent-loop LLM call as
+// "<requestID>-step-<n>".
+func (h *ClientHandler) accountAgentStep(ctx context.Context, client *llmux.Client, req *llmux.ChatRequest, resp *llmux.ChatResponse, requestID string, step int, start time.Time) {
+	model := req.Model
+	if resp.Model != "" {
+		model = resp.Model
+	}
+	var usage governance.Usage
+	if resp.Usage != nil {
+		usage = governance.Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+			Cost:             client.CalculateCost(model, resp.Usage),
+			Provider:         resp.Usage.Provider,
+		}
+	}
+	h.accountUsage(ctx, governance.AccountInput{
+		RequestID:   fmt.Sprintf("%s-step-%d", requestID, step),
+		Model:       model,
+		CallType:    governance.CallTypeChatCompletion,
+		EndUserID:   req.User,
+		RequestTags: req.Tags,
+		Usage:       usage,
+		Start:       start,
+		Latency:     time.Since(start),
+		Metadata: map[string]any{
+			parentRequestIDMetadataKey: requestID,
+			"agent_step":               step,
+		},
+	})
+}
+
+// accountToolCall logs an MCP tool execution. Arguments are recorded only
+// as a hash; the calling key is taken from the auth context as for any
+// other usage.
+func (h *ClientHandler) accountToolCall(ctx context.Context, requestID string, call llmux.ToolCall, result mcp.ToolExecutionResult) {
+	status := "success"
+	if result.IsError {
+		status = "failure"
+	}
+	toolRequestID := requestID + "-tool"
+	if call.ID != "" {
+		toolRequestID += "-" + call.ID
+	}
+	argsHash := mcp.ArgumentsHash(call.Function.Arguments)
+	h.accountUsage(ctx, governance.AccountInput{
+		RequestID: toolRequestID,
+		Model:     call.Function.Name,
+		CallType:  governance.CallTypeMCPToolCall,
+		Usage:     governance.Usage{Provider: "mcp"},
+		Start:     time.Now().Add(-result.Duration),
+		Latency:   result.Duration,
+		Status:    &status,
+		Metadata: map[string]any{
+			parentRequestIDMetadataKey: requestID,
+			"tool_name":                call.Function.Name,
+			"tool_call_id":             call.ID,
+			"args_hash":                argsHash,
+		},
+	})
+
+	keyID := ""
+	if authCtx := auth.GetAuthContext(ctx); authCtx != nil && authCtx.APIKey != nil {
+		keyID = authCtx.APIKey.ID
+	}
+	h.logger.Info("mcp tool call",
+		"request_id", requestID,
+		"tool", call.Function.Name,
+		"args_hash", argsHash,
+		"duration_ms", result.Duration.Milliseconds(),
+		"is_error", result.IsError,
+		"api_key_id", keyID,
+	)
+}
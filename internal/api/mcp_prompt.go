@@ -0,0 +1,60 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+// mcpPromptField is the request body extension prepending the messages of a
+// prompt exposed by an MCP server, e.g.
+// {"mcp_prompt": {"name": "github/review_pr", "arguments": {"pr": "42"}}}.
+const mcpPromptField = "mcp_prompt"
+
+// mcpPromptExtension is the body form of a prompt reference.
+type mcpPromptExtension struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments"`
+}
+
+// parseMCPPromptExtension reads and removes the prompt body extension so it
+// is not forwarded upstream. It returns nil when the extension is absent.
+func parseMCPPromptExtension(req *llmux.ChatRequest) (*mcpPromptExtension, error) {
+	raw, exists := req.Extra[mcpPromptField]
+	if !exists {
+		return nil, nil
+	}
+	delete(req.Extra, mcpPromptField)
+
+	var ext mcpPromptExtension
+	if err := json.Unmarshal(raw, &ext); err != nil {
+		return nil, err
+	}
+	if ext.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	return &ext, nil
+}
+
+// applyMCPPrompt renders the referenced prompt and prepends its messages to
+// the request's own.
+func (h *ClientHandler) applyMCPPrompt(ctx context.Context, req *llmux.ChatRequest, ext *mcpPromptExtension) error {
+	if ext == nil {
+		return nil
+	}
+	manager := h.getMCPManager(ctx)
+	if manager == nil {
+		return llmerrors.NewInvalidRequestError("", req.Model, "mcp_prompt requires MCP to be enabled")
+	}
+	messages, err := manager.GetPrompt(ctx, ext.Name, ext.Arguments)
+	if err != nil {
+		h.logger.Warn("mcp prompt rendering failed", "prompt", ext.Name, "error", err)
+		return llmerrors.NewInvalidRequestError("", req.Model, "invalid mcp_prompt: "+err.Error())
+	}
+	req.Messages = append(messages, req.Messages...)
+	return nil
+}
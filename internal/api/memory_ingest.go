@@ -0,0 +1,156 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"encoding/base64"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/governance"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+// ingestDocumentRequest is the body of POST /v1/memory/{collection}/documents.
+// Text documents are sent in content; binary ones such as PDFs in
+// content_base64.
+type ingestDocumentRequest struct {
+	Name          string             `json:"name"`
+	ContentType   string             `json:"content_type"`
+	Content       string             `json:"content"`
+	ContentBase64 string             `json:"content_base64"`
+	Metadata      map[string]string  `json:"metadata"`
+	Chunking      *ingestChunkConfig `json:"chunking"`
+}
+
+type ingestChunkConfig struct {
+	Strategy string `json:"strategy"`
+	Size     int    `json:"size"`
+	Overlap  int    `json:"overlap"`
+}
+
+// ingestDocumentResponse describes the stored chunks.
+type ingestDocumentResponse struct {
+	Collection string `json:"collection"`
+	*llmux.IngestResult
+}
+
+// IngestDocument handles POST /v1/memory/{collection}/documents: the
+// document is extracted, chunked, embedded and stored in the caller's
+// long-term memory collection.
+func (h *ClientHandler) IngestDocument(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	r, requestID := h.ensureRequestID(r)
+	collection := r.PathValue("collection")
+	if collection == "" {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "collection is required"))
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, h.maxBodySize+1))
+	if err != nil {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "failed to read request body"))
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+	if int64(len(body)) > h.maxBodySize {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "request body too large"))
+		return
+	}
+
+	var req ingestDocumentRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "invalid JSON: "+err.Error()))
+		return
+	}
+	doc := llmux.Document{
+		Name:        req.Name,
+		ContentType: req.ContentType,
+		Metadata:    req.Metadata,
+	}
+	switch {
+	case req.Content != "" && req.ContentBase64 != "":
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "set only one of content and content_base64"))
+		return
+	case req.Content != "":
+		doc.Data = []byte(req.Content)
+		if doc.ContentType == "" {
+			doc.ContentType = "text/plain"
+		}
+	case req.ContentBase64 != "":
+		doc.Data, err = base64.StdEncoding.DecodeString(req.ContentBase64)
+		if err != nil {
+			h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "content_base64 is not valid base64"))
+			return
+		}
+		if doc.ContentType == "" {
+			h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "content_type is required with content_base64"))
+			return
+		}
+	default:
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "content or content_base64 is required"))
+		return
+	}
+	if req.Chunking != nil {
+		doc.Chunking = &llmux.ChunkConfig{
+			Strategy: req.Chunking.Strategy,
+			Size:     req.Chunking.Size,
+			Overlap:  req.Chunking.Overlap,
+		}
+	}
+
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, llmerrors.NewInternalError("", "", "client not initialized"))
+		return
+	}
+
+	model := client.MemoryEmbeddingModel()
+	if model == "" {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "long-term memory is not enabled"))
+		return
+	}
+	// Embedding the document is an upstream call like any other, so the kill
+	// switch, budgets and rate limits apply and its cost is billed.
+	ctx := r.Context()
+	if _, err := h.evaluateGovernance(ctx, r, model, "", nil, req.Content, governance.CallTypeEmbedding); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	result, err := client.Ingest(ctx, collection, doc)
+	if result != nil {
+		h.accountUsage(ctx, governance.AccountInput{
+			RequestID: requestID,
+			Model:     model,
+			CallType:  governance.CallTypeEmbedding,
+			Usage: governance.Usage{
+				PromptTokens: result.Usage.PromptTokens,
+				TotalTokens:  result.Usage.TotalTokens,
+				Cost:         result.Cost,
+				Provider:     result.Usage.Provider,
+			},
+			Start:   start,
+			Latency: time.Since(start),
+		})
+	}
+	if stderrors.Is(err, llmux.ErrLongTermMemoryDisabled) {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "long-term memory is not enabled"))
+		return
+	}
+	if err != nil {
+		h.logger.Error("document ingestion failed", "collection", collection, "error", err)
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(ingestDocumentResponse{Collection: collection, IngestResult: result}); err != nil {
+		h.logger.Error("failed to encode ingestion response", "error", err)
+	}
+}
@@ -0,0 +1,130 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/governance"
+)
+
+func newIngestTestClient(t *testing.T) *llmux.Client {
+	t.Helper()
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             "http://127.0.0.1:1",
+			AllowPrivateBaseURL: true,
+			Models:              []string{"text-embedding-3-small"},
+		}),
+		llmux.WithPricingFallback(llmux.PricingFallback{Policy: llmux.PricingPolicyWarn}),
+		llmux.WithLongTermMemory(llmux.LongTermMemoryConfig{
+			Store:          llmux.NewMemoryVectorStore(),
+			EmbeddingModel: "text-embedding-3-small",
+			Extractors: map[string]llmux.Extractor{
+				"application/pdf": llmux.ExtractorFunc(func(_ context.Context, data []byte) (string, error) {
+					return "pdf text: " + string(data), nil
+				}),
+			},
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestClientHandler_IngestDocument(t *testing.T) {
+	client := newIngestTestClient(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	handler := NewClientHandler(client, logger, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/memory/{collection}/documents", handler.IngestDocument)
+
+	// Sandbox embeddings keep the test offline.
+	ingest := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/memory/handbook/documents", bytes.NewBufferString(body))
+		req = req.WithContext(llmux.WithSandbox(req.Context()))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := ingest(`{"name":"guide.md","content_type":"text/markdown","content":"# Setup\ninstall it\n\n# Usage\nrun it","metadata":{"team":"docs"},"chunking":{"strategy":"markdown"}}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var resp struct {
+		Collection string   `json:"collection"`
+		DocumentID string   `json:"document_id"`
+		Chunks     int      `json:"chunks"`
+		IDs        []string `json:"ids"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "handbook", resp.Collection)
+	assert.Equal(t, 2, resp.Chunks)
+	assert.Len(t, resp.IDs, 2)
+
+	matches, err := client.Recall(llmux.WithSandbox(context.Background()), "handbook", "# Usage\nrun it", 1)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, resp.DocumentID, matches[0].Metadata[llmux.MemoryMetadataDocumentID])
+	assert.Equal(t, "guide.md", matches[0].Metadata[llmux.MemoryMetadataSource])
+	assert.Equal(t, "1", matches[0].Metadata[llmux.MemoryMetadataChunk])
+	assert.Equal(t, "docs", matches[0].Metadata["team"])
+
+	rec = ingest(`{"content_type":"application/pdf","content_base64":"` + base64.StdEncoding.EncodeToString([]byte("%PDF")) + `"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = ingest(`{"content_type":"application/msword","content_base64":"AAAA"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "unsupported content types are rejected")
+	rec = ingest(`{"name":"empty"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestClientHandler_IngestDocumentGovernance(t *testing.T) {
+	store := auth.NewMemoryStore()
+	killSwitch := governance.NewKillSwitch()
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	handler := NewClientHandler(newIngestTestClient(t), logger, &ClientHandlerConfig{Store: store, KillSwitch: killSwitch})
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/memory/{collection}/documents", handler.IngestDocument)
+
+	keyID := "key-1"
+	ingest := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/memory/handbook/documents",
+			bytes.NewBufferString(`{"name":"guide.txt","content":"install it"}`))
+		ctx := context.WithValue(llmux.WithSandbox(req.Context()), auth.AuthContextKey,
+			&auth.AuthContext{APIKey: &auth.APIKey{ID: keyID, IsActive: true}})
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req.WithContext(ctx))
+		return rec
+	}
+
+	rec := ingest()
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.Eventually(t, func() bool {
+		stats, err := store.GetUsageStats(context.Background(), auth.UsageFilter{
+			APIKeyID:  &keyID,
+			StartTime: time.Now().Add(-time.Hour),
+			EndTime:   time.Now().Add(time.Hour),
+		})
+		return err == nil && stats.TotalRequests == 1 && stats.InputTokens > 0
+	}, time.Second, 10*time.Millisecond, "embedding usage is logged against the key")
+
+	_, err := killSwitch.Block(context.Background(), governance.Block{Scope: governance.BlockScopeModel, Target: "text-embedding-3-small", Reason: "incident"})
+	require.NoError(t, err)
+	rec = ingest()
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "the kill switch blocks ingestion")
+}
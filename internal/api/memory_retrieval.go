@@ -0,0 +1,81 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/observability"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+// memoryExtensionField is the request body extension enabling retrieval from
+// long-term memory, e.g. {"memory": {"collection": "notes", "top_k": 3}}.
+const memoryExtensionField = "memory"
+
+// memoryExtension is the body form of a retrieval request.
+type memoryExtension struct {
+	Collection string `json:"collection"`
+	TopK       int    `json:"top_k"`
+}
+
+// parseMemoryExtension reads and removes the memory body extension so it is
+// not forwarded upstream. It returns nil when the extension is absent.
+func parseMemoryExtension(req *llmux.ChatRequest) (*memoryExtension, error) {
+	raw, exists := req.Extra[memoryExtensionField]
+	if !exists {
+		return nil, nil
+	}
+	delete(req.Extra, memoryExtensionField)
+
+	var ext memoryExtension
+	if err := json.Unmarshal(raw, &ext); err != nil {
+		return nil, err
+	}
+	if ext.Collection == "" {
+		return nil, fmt.Errorf("collection is required")
+	}
+	if ext.TopK < 0 {
+		return nil, fmt.Errorf("top_k cannot be negative")
+	}
+	return &ext, nil
+}
+
+// applyMemoryRetrieval injects the memories recalled for ext into req and
+// records them in the observability payload. Retrieval failures other than
+// invalid requests are logged and the request proceeds without memories.
+func (h *ClientHandler) applyMemoryRetrieval(
+	ctx context.Context,
+	client *llmux.Client,
+	req *llmux.ChatRequest,
+	ext *memoryExtension,
+	payload *observability.StandardLoggingPayload,
+) (*llmux.ChatRequest, error) {
+	if ext == nil {
+		return req, nil
+	}
+	augmented, matches, err := client.AugmentWithMemory(ctx, req, ext.Collection, ext.TopK)
+	if stderrors.Is(err, llmux.ErrLongTermMemoryDisabled) {
+		return nil, llmerrors.NewInvalidRequestError("", req.Model, "long-term memory is not enabled")
+	}
+	if llmErr, ok := err.(*llmerrors.LLMError); ok {
+		return nil, llmErr
+	}
+	if err != nil {
+		h.logger.Warn("memory retrieval failed, continuing without memories",
+			"collection", ext.Collection, "error", err)
+		return req, nil
+	}
+
+	for _, m := range matches {
+		payload.RecalledMemories = append(payload.RecalledMemories, observability.RecalledMemory{
+			ID:         m.ID,
+			Collection: ext.Collection,
+			Score:      m.Score,
+		})
+	}
+	return augmented, nil
+}
@@ -0,0 +1,98 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/observability"
+)
+
+func TestClientHandler_MemoryRetrieval(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		upstream []llmux.ChatMessage
+	)
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/embeddings" {
+			_, _ = w.Write([]byte(`{"object":"list","model":"text-embedding-3-small","data":[{"object":"embedding","index":0,"embedding":[1,0]}],"usage":{"prompt_tokens":1,"total_tokens":1}}`))
+			return
+		}
+		var req llmux.ChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		upstream = req.Messages
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"id":"c","object":"chat.completion","model":"gpt-4","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	t.Cleanup(mock.Close)
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             mock.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4", "text-embedding-3-small"},
+		}),
+		llmux.WithPricingFallback(llmux.PricingFallback{Policy: llmux.PricingPolicyWarn}),
+		llmux.WithLongTermMemory(llmux.LongTermMemoryConfig{Store: llmux.NewMemoryVectorStore(), EmbeddingModel: "text-embedding-3-small"}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	memoryID, err := client.Remember(context.Background(), "notes", "the user prefers tea", nil)
+	require.NoError(t, err)
+
+	obsMgr, err := observability.NewObservabilityManager(observability.ObservabilityConfig{})
+	require.NoError(t, err)
+	cb := &recordingCallback{}
+	obsMgr.CallbackManager().Register(cb)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	handler := NewClientHandler(client, logger, &ClientHandlerConfig{Observability: obsMgr})
+
+	body := `{"model":"gpt-4","messages":[{"role":"system","content":"be brief"},{"role":"user","content":"what should I drink?"}],"memory":{"collection":"notes","top_k":2}}`
+	rec := httptest.NewRecorder()
+	handler.ChatCompletions(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewBufferString(body)))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	mu.Lock()
+	require.Len(t, upstream, 3)
+	assert.Equal(t, "be brief", upstream[0].TextContent())
+	assert.Equal(t, "system", upstream[1].Role)
+	assert.True(t, strings.Contains(upstream[1].TextContent(), "the user prefers tea"))
+	assert.Equal(t, "what should I drink?", upstream[2].TextContent())
+	mu.Unlock()
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	require.Len(t, cb.successEvents, 1)
+	require.Len(t, cb.successEvents[0].RecalledMemories, 1)
+	assert.Equal(t, memoryID, cb.successEvents[0].RecalledMemories[0].ID)
+	assert.Equal(t, "notes", cb.successEvents[0].RecalledMemories[0].Collection)
+}
+
+func TestParseMemoryExtension(t *testing.T) {
+	req := &llmux.ChatRequest{Extra: map[string]json.RawMessage{"memory": json.RawMessage(`{"top_k":2}`)}}
+	_, err := parseMemoryExtension(req)
+	assert.Error(t, err, "collection is required")
+	assert.NotContains(t, req.Extra, "memory", "the extension is never forwarded upstream")
+
+	ext, err := parseMemoryExtension(&llmux.ChatRequest{})
+	require.NoError(t, err)
+	assert.Nil(t, ext)
+}
@@ -0,0 +1,416 @@
+// Package api provides HTTP handlers for the LLM gateway API.
+// Model (provider deployment) management endpoints.
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+// ============================================================================
+// Model Management Endpoints
+// ============================================================================
+
+// ModelProviderRequest represents a request to create or update a provider
+// deployment. On update, omitted fields keep their current value.
+type ModelProviderRequest struct {
+	Name                string            `json:"name"`
+	Type                *string           `json:"type,omitempty"`
+	APIKey              *string           `json:"api_key,omitempty"` // Plain key or secret reference (e.g. vault://path)
+	BaseURL             *string           `json:"base_url,omitempty"`
+	AllowPrivateBaseURL *bool             `json:"allow_private_base_url,omitempty"`
+	Models              []string          `json:"models,omitempty"`
+	MaxConcurrent       *int              `json:"max_concurrent,omitempty"`
+	Timeout             *string           `json:"timeout,omitempty"` // Go duration, e.g. "30s"
+	Headers             map[string]string `json:"headers,omitempty"`
+	APIVersion          *string           `json:"api_version,omitempty"`
+	RPM                 *int64            `json:"rpm,omitempty"`
+	TPM                 *int64            `json:"tpm,omitempty"`
+	Tags                []string          `json:"tags,omitempty"`
+}
+
+// DeleteModelProviderRequest represents a request to delete a provider deployment.
+type DeleteModelProviderRequest struct {
+	Name string `json:"name"`
+}
+
+// ModelProviderInfo describes a provider deployment managed through the API.
+// Plain API keys are masked; secret references are shown as they are.
+type ModelProviderInfo struct {
+	Name                string            `json:"name"`
+	Type                string            `json:"type"`
+	APIKey              string            `json:"api_key"`
+	BaseURL             string            `json:"base_url,omitempty"`
+	AllowPrivateBaseURL bool              `json:"allow_private_base_url,omitempty"`
+	Models              []string          `json:"models"`
+	MaxConcurrent       int               `json:"max_concurrent,omitempty"`
+	Timeout             string            `json:"timeout,omitempty"`
+	Headers             map[string]string `json:"headers,omitempty"`
+	APIVersion          string            `json:"api_version,omitempty"`
+	RPM                 int64             `json:"rpm,omitempty"`
+	TPM                 int64             `json:"tpm,omitempty"`
+	Tags                []string          `json:"tags,omitempty"`
+	CreatedAt           time.Time         `json:"created_at"`
+	UpdatedAt           time.Time         `json:"updated_at"`
+}
+
+// SetModelReloader sets the function that rebuilds the live client after a
+// provider is added, changed or removed through the /model endpoints.
+// Without it those endpoints are unavailable.
+func (h *ManagementHandler) SetModelReloader(reload func(ctx context.Context) error) {
+	h.modelReloader = reload
+}
+
+func (h *ManagementHandler) modelProviders(w http.ResponseWriter, r *http.Request) (auth.ModelProviderStore, bool) {
+	providers, ok := auth.ModelProviders(h.store)
+	if !ok {
+		h.writeError(w, r, http.StatusNotImplemented, "model management is not supported by the configured store")
+	}
+	return providers, ok
+}
+
+// NewModel handles POST /model/new
+func (h *ManagementHandler) NewModel(w http.ResponseWriter, r *http.Request) {
+	providers, ok := h.modelProviders(w, r)
+	if !ok {
+		return
+	}
+	var req ModelProviderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		h.writeError(w, r, http.StatusBadRequest, "name is required")
+		return
+	}
+	if h.isConfigProvider(req.Name) {
+		h.writeError(w, r, http.StatusConflict, "provider is defined in the config file")
+		return
+	}
+
+	existing, err := providers.GetModelProvider(r.Context(), req.Name)
+	if err != nil {
+		h.logger.Error("failed to get model provider", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to create model")
+		return
+	}
+	if existing != nil {
+		h.writeError(w, r, http.StatusConflict, "model provider already exists")
+		return
+	}
+
+	now := time.Now()
+	provider := &auth.ModelProvider{Name: req.Name, CreatedAt: now, UpdatedAt: now}
+	if err := applyModelProviderRequest(provider, &req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !h.commitModelProvider(w, r, providers, nil, provider, auth.AuditActionCreate) {
+		return
+	}
+	h.writeJSON(w, http.StatusOK, newModelProviderInfo(provider))
+}
+
+// UpdateModel handles POST /model/update
+func (h *ManagementHandler) UpdateModel(w http.ResponseWriter, r *http.Request) {
+	providers, ok := h.modelProviders(w, r)
+	if !ok {
+		return
+	}
+	var req ModelProviderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Name == "" {
+		h.writeError(w, r, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	before, err := providers.GetModelProvider(r.Context(), req.Name)
+	if err != nil {
+		h.logger.Error("failed to get model provider", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to update model")
+		return
+	}
+	if before == nil {
+		h.writeModelNotFound(w, r, req.Name)
+		return
+	}
+
+	provider := *before
+	if err := applyModelProviderRequest(&provider, &req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	provider.UpdatedAt = time.Now()
+
+	if !h.commitModelProvider(w, r, providers, before, &provider, auth.AuditActionUpdate) {
+		return
+	}
+	h.writeJSON(w, http.StatusOK, newModelProviderInfo(&provider))
+}
+
+// DeleteModel handles POST /model/delete
+func (h *ManagementHandler) DeleteModel(w http.ResponseWriter, r *http.Request) {
+	providers, ok := h.modelProviders(w, r)
+	if !ok {
+		return
+	}
+	var req DeleteModelProviderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Name == "" {
+		h.writeError(w, r, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	before, err := providers.GetModelProvider(r.Context(), req.Name)
+	if err != nil {
+		h.logger.Error("failed to get model provider", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to delete model")
+		return
+	}
+	if before == nil {
+		h.writeModelNotFound(w, r, req.Name)
+		return
+	}
+
+	if !h.commitModelProvider(w, r, providers, before, nil, auth.AuditActionDelete) {
+		return
+	}
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"deleted_model": req.Name,
+	})
+}
+
+// GetModelInfo handles GET /model/info. Without a name it lists every
+// provider managed through the API.
+func (h *ManagementHandler) GetModelInfo(w http.ResponseWriter, r *http.Request) {
+	providers, ok := h.modelProviders(w, r)
+	if !ok {
+		return
+	}
+
+	if name := r.URL.Query().Get("name"); name != "" {
+		provider, err := providers.GetModelProvider(r.Context(), name)
+		if err != nil {
+			h.logger.Error("failed to get model provider", "error", err)
+			h.writeError(w, r, http.StatusInternalServerError, "failed to get model")
+			return
+		}
+		if provider == nil {
+			h.writeModelNotFound(w, r, name)
+			return
+		}
+		h.writeJSON(w, http.StatusOK, newModelProviderInfo(provider))
+		return
+	}
+
+	list, err := providers.ListModelProviders(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list model providers", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to list models")
+		return
+	}
+	infos := make([]ModelProviderInfo, 0, len(list))
+	for _, p := range list {
+		infos = append(infos, newModelProviderInfo(p))
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"data":  infos,
+		"total": len(infos),
+	})
+}
+
+// commitModelProvider persists the change from before to after (nil for a
+// create or delete respectively) and rebuilds the live client. When the
+// rebuild fails the stored provider is restored, so the store never holds
+// a provider the client rejected.
+func (h *ManagementHandler) commitModelProvider(w http.ResponseWriter, r *http.Request, providers auth.ModelProviderStore, before, after *auth.ModelProvider, action auth.AuditAction) bool {
+	if h.modelReloader == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "model reload not available")
+		return false
+	}
+	ctx := r.Context()
+	name := modelProviderName(before, after)
+
+	if err := saveOrDeleteModelProvider(ctx, providers, name, after); err != nil {
+		h.logger.Error("failed to persist model provider", "name", name, "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to persist model")
+		return false
+	}
+
+	if err := h.modelReloader(ctx); err != nil {
+		if restoreErr := saveOrDeleteModelProvider(ctx, providers, name, before); restoreErr != nil {
+			h.logger.Error("failed to restore model provider", "name", name, "error", restoreErr)
+		}
+		h.auditControlAction(r, action, auth.AuditObjectModel, name, false, modelProviderAudit(before), modelProviderAudit(after), nil, err.Error())
+		h.writeError(w, r, http.StatusBadRequest, fmt.Sprintf("failed to apply model: %v", err))
+		return false
+	}
+
+	h.auditControlAction(r, action, auth.AuditObjectModel, name, true, modelProviderAudit(before), modelProviderAudit(after), nil, "")
+	return true
+}
+
+func saveOrDeleteModelProvider(ctx context.Context, providers auth.ModelProviderStore, name string, provider *auth.ModelProvider) error {
+	if provider == nil {
+		return providers.DeleteModelProvider(ctx, name)
+	}
+	return providers.SaveModelProvider(ctx, provider)
+}
+
+func modelProviderName(before, after *auth.ModelProvider) string {
+	if after != nil {
+		return after.Name
+	}
+	return before.Name
+}
+
+func (h *ManagementHandler) writeModelNotFound(w http.ResponseWriter, r *http.Request, name string) {
+	if h.isConfigProvider(name) {
+		h.writeError(w, r, http.StatusBadRequest, "provider is defined in the config file and cannot be managed through the API")
+		return
+	}
+	h.writeError(w, r, http.StatusNotFound, "model provider not found")
+}
+
+// isConfigProvider reports whether name is a provider of the config file.
+func (h *ManagementHandler) isConfigProvider(name string) bool {
+	if h.configManager == nil {
+		return false
+	}
+	cfg := h.configManager.Get()
+	if cfg == nil {
+		return false
+	}
+	for _, p := range cfg.Providers {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyModelProviderRequest copies the fields set in req onto p and checks
+// the result the way the config file's providers are checked.
+func applyModelProviderRequest(p *auth.ModelProvider, req *ModelProviderRequest) error {
+	if req.Type != nil {
+		p.Type = *req.Type
+	}
+	if req.APIKey != nil {
+		p.APIKey = *req.APIKey
+	}
+	if req.BaseURL != nil {
+		p.BaseURL = *req.BaseURL
+	}
+	if req.AllowPrivateBaseURL != nil {
+		p.AllowPrivateBaseURL = *req.AllowPrivateBaseURL
+	}
+	if req.Models != nil {
+		p.Models = req.Models
+	}
+	if req.MaxConcurrent != nil {
+		p.MaxConcurrent = *req.MaxConcurrent
+	}
+	if req.Timeout != nil {
+		timeout, err := time.ParseDuration(*req.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+		p.Timeout = timeout
+	}
+	if req.Headers != nil {
+		p.Headers = req.Headers
+	}
+	if req.APIVersion != nil {
+		p.APIVersion = *req.APIVersion
+	}
+	if req.RPM != nil {
+		p.RPM = *req.RPM
+	}
+	if req.TPM != nil {
+		p.TPM = *req.TPM
+	}
+	if req.Tags != nil {
+		p.Tags = req.Tags
+	}
+
+	switch {
+	case p.Type == "":
+		return fmt.Errorf("type is required")
+	case p.APIKey == "":
+		return fmt.Errorf("api_key is required")
+	case len(p.Models) == 0:
+		return fmt.Errorf("at least one model must be configured")
+	case p.Timeout < 0:
+		return fmt.Errorf("timeout cannot be negative")
+	case p.MaxConcurrent < 0:
+		return fmt.Errorf("max_concurrent cannot be negative")
+	case p.RPM < 0 || p.TPM < 0:
+		return fmt.Errorf("rpm and tpm cannot be negative")
+	}
+	return nil
+}
+
+func newModelProviderInfo(p *auth.ModelProvider) ModelProviderInfo {
+	info := ModelProviderInfo{
+		Name:                p.Name,
+		Type:                p.Type,
+		APIKey:              maskProviderAPIKey(p.APIKey),
+		BaseURL:             p.BaseURL,
+		AllowPrivateBaseURL: p.AllowPrivateBaseURL,
+		Models:              p.Models,
+		MaxConcurrent:       p.MaxConcurrent,
+		Headers:             p.Headers,
+		APIVersion:          p.APIVersion,
+		RPM:                 p.RPM,
+		TPM:                 p.TPM,
+		Tags:                p.Tags,
+		CreatedAt:           p.CreatedAt,
+		UpdatedAt:           p.UpdatedAt,
+	}
+	if p.Timeout > 0 {
+		info.Timeout = p.Timeout.String()
+	}
+	return info
+}
+
+// modelProviderAudit is the audit log view of p, without its API key.
+func modelProviderAudit(p *auth.ModelProvider) map[string]any {
+	if p == nil {
+		return nil
+	}
+	return map[string]any{
+		"type":     p.Type,
+		"base_url": p.BaseURL,
+		"models":   p.Models,
+		"tags":     p.Tags,
+	}
+}
+
+// maskProviderAPIKey keeps secret references and the last four characters
+// of plain keys.
+func maskProviderAPIKey(key string) string {
+	if strings.Contains(key, "://") {
+		return key
+	}
+	if len(key) <= 8 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
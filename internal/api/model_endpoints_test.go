@@ -0,0 +1,104 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+func TestModelEndpoints(t *testing.T) {
+	store := auth.NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := NewManagementHandler(store, nil, logger, nil, nil, nil)
+	var reloadErr error
+	reloads := 0
+	h.SetModelReloader(func(context.Context) error {
+		reloads++
+		return reloadErr
+	})
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, target, bytes.NewBufferString(body)))
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/model/new", `{"name":"azure-east","type":"azure","api_key":"sk-secret-1234","models":["gpt-4o"],"timeout":"30s"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var info ModelProviderInfo
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+	require.Equal(t, "****1234", info.APIKey)
+	require.Equal(t, "30s", info.Timeout)
+	require.Equal(t, 1, reloads)
+
+	stored, err := store.GetModelProvider(context.Background(), "azure-east")
+	require.NoError(t, err)
+	require.Equal(t, "sk-secret-1234", stored.APIKey)
+	require.Equal(t, 30*time.Second, stored.Timeout)
+
+	rec = do(http.MethodPost, "/model/new", `{"name":"azure-east","type":"azure","api_key":"k","models":["gpt-4o"]}`)
+	require.Equal(t, http.StatusConflict, rec.Code)
+	rec = do(http.MethodPost, "/model/new", `{"name":"no-models","type":"openai","api_key":"k"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = do(http.MethodPost, "/model/update", `{"name":"azure-east","models":["gpt-4o","gpt-4o-mini"],"api_key":"vault://llm/azure"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+	require.Equal(t, []string{"gpt-4o", "gpt-4o-mini"}, info.Models)
+	require.Equal(t, "vault://llm/azure", info.APIKey)
+	require.Equal(t, "azure", info.Type)
+
+	// A rebuild failure leaves the stored provider as it was.
+	reloadErr = errors.New("unknown provider type: bogus")
+	rec = do(http.MethodPost, "/model/update", `{"name":"azure-east","type":"bogus"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), "unknown provider type")
+	stored, err = store.GetModelProvider(context.Background(), "azure-east")
+	require.NoError(t, err)
+	require.Equal(t, "azure", stored.Type)
+	rec = do(http.MethodPost, "/model/new", `{"name":"broken","type":"bogus","api_key":"k","models":["m"]}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	stored, err = store.GetModelProvider(context.Background(), "broken")
+	require.NoError(t, err)
+	require.Nil(t, stored)
+	reloadErr = nil
+
+	rec = do(http.MethodGet, "/model/info", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"total":1`)
+
+	rec = do(http.MethodPost, "/model/delete", `{"name":"azure-east"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = do(http.MethodGet, "/model/info?name=azure-east", "")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	rec = do(http.MethodPost, "/model/delete", `{"name":"azure-east"}`)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestModelEndpoints_RequireReloader(t *testing.T) {
+	store := auth.NewMemoryStore()
+	h := NewManagementHandler(store, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/model/new",
+		bytes.NewBufferString(`{"name":"openai","type":"openai","api_key":"k","models":["gpt-4o"]}`)))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	providers, err := store.ListModelProviders(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, providers)
+}
@@ -0,0 +1,113 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/tokenizer"
+)
+
+// outputTokenCapField is the request body extension capping the completion
+// tokens of a request, e.g. {"max_output_tokens_hard_cap": 512}.
+const outputTokenCapField = "max_output_tokens_hard_cap"
+
+type outputTokenCapKey struct{}
+
+// applyOutputTokenCap reads and removes the output token cap extension so it
+// is not forwarded upstream, and attaches it to the request context.
+func applyOutputTokenCap(r *http.Request, req *llmux.ChatRequest) (*http.Request, error) {
+	raw, exists := req.Extra[outputTokenCapField]
+	if !exists {
+		return r, nil
+	}
+	delete(req.Extra, outputTokenCapField)
+
+	var limit int
+	if err := json.Unmarshal(raw, &limit); err != nil {
+		return r, err
+	}
+	if limit <= 0 {
+		return r, fmt.Errorf("must be positive")
+	}
+	return r.WithContext(context.WithValue(r.Context(), outputTokenCapKey{}, limit)), nil
+}
+
+// outputTokenCap returns the completion tokens a response may carry, or 0
+// when uncapped. A request can lower its API key's cap but never raise it.
+func outputTokenCap(ctx context.Context) int {
+	limit, _ := ctx.Value(outputTokenCapKey{}).(int)
+	if authCtx := auth.GetAuthContext(ctx); authCtx != nil && authCtx.APIKey != nil {
+		if keyLimit := authCtx.APIKey.MaxOutputTokensHardCap(); keyLimit > 0 && (limit == 0 || keyLimit < limit) {
+			limit = keyLimit
+		}
+	}
+	return limit
+}
+
+// capMaxTokens lowers req's max_tokens (or max_completion_tokens) to the
+// output token cap of ctx, so the provider stops generating there on both
+// streaming and non-streaming requests. Streams are also counted against the
+// cap as they are forwarded.
+func capMaxTokens(ctx context.Context, req *llmux.ChatRequest) {
+	if limit := outputTokenCap(ctx); limit > 0 && (req.MaxTokens <= 0 || req.MaxTokens > limit) {
+		req.MaxTokens = limit
+	}
+}
+
+// outputTokenCounter counts the completion tokens of streamed chunks against
+// a hard cap.
+type outputTokenCounter struct {
+	model string
+	limit int
+	count int
+}
+
+// newOutputTokenCounter returns nil when limit is 0.
+func newOutputTokenCounter(model string, limit int) *outputTokenCounter {
+	if limit <= 0 {
+		return nil
+	}
+	return &outputTokenCounter{model: model, limit: limit}
+}
+
+// add counts the tokens of chunk and reports whether forwarding it would
+// exceed the cap; such a chunk is not counted.
+func (c *outputTokenCounter) add(chunk *llmux.StreamChunk) bool {
+	if c == nil {
+		return false
+	}
+	n := 0
+	for _, choice := range chunk.Choices {
+		if choice.Delta.Content != "" {
+			n += tokenizer.CountTextTokens(c.model, choice.Delta.Content)
+		}
+		for _, call := range choice.Delta.ToolCalls {
+			if call.Function.Arguments != "" {
+				n += tokenizer.CountTextTokens(c.model, call.Function.Arguments)
+			}
+		}
+	}
+	if c.count+n > c.limit {
+		return true
+	}
+	c.count += n
+	return false
+}
+
+// lengthFinishChunk is the synthetic last chunk of a stream cut off at its
+// output token cap.
+func lengthFinishChunk(chunk *llmux.StreamChunk) *llmux.StreamChunk {
+	return &llmux.StreamChunk{
+		ID:                chunk.ID,
+		Object:            chunk.Object,
+		Created:           chunk.Created,
+		Model:             chunk.Model,
+		SystemFingerprint: chunk.SystemFingerprint,
+		Choices:           []llmux.StreamChoice{{Index: 0, FinishReason: "length"}},
+	}
+}
@@ -0,0 +1,181 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+// newRunawayStreamServer streams chunks of " word" until the client goes
+// away or 1000 chunks were sent.
+func newRunawayStreamServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		for i := 0; i < 1000; i++ {
+			if r.Context().Err() != nil {
+				return
+			}
+			_, _ = fmt.Fprintf(w, `data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"content":" word"}}]}`+"\n\n")
+			flusher.Flush()
+		}
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+		flusher.Flush()
+	}))
+}
+
+// streamedChunks decodes the data events of an SSE body, stopping at [DONE].
+func streamedChunks(t *testing.T, body string) ([]llmux.StreamChunk, bool) {
+	t.Helper()
+	var chunks []llmux.StreamChunk
+	for _, line := range strings.Split(body, "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		if data == "[DONE]" {
+			return chunks, true
+		}
+		var chunk llmux.StreamChunk
+		require.NoError(t, json.Unmarshal([]byte(data), &chunk))
+		chunks = append(chunks, chunk)
+	}
+	return chunks, false
+}
+
+func TestChatCompletionStream_OutputTokenCap(t *testing.T) {
+	mock := newRunawayStreamServer()
+	defer mock.Close()
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             mock.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	handler := NewClientHandler(client, logger, nil)
+
+	serve := func(key *auth.APIKey, extra string) *httptest.ResponseRecorder {
+		body := `{"model":"gpt-4o","stream":true,"messages":[{"role":"user","content":"hi"}]` + extra + `}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader([]byte(body)))
+		if key != nil {
+			req = req.WithContext(auth.WithAuthContext(req.Context(), &auth.AuthContext{APIKey: key}))
+		}
+		rec := httptest.NewRecorder()
+		handler.ChatCompletions(rec, req)
+		return rec
+	}
+	contentOf := func(chunks []llmux.StreamChunk) string {
+		var b strings.Builder
+		for _, c := range chunks {
+			for _, choice := range c.Choices {
+				b.WriteString(choice.Delta.Content)
+			}
+		}
+		return b.String()
+	}
+
+	t.Run("request cap", func(t *testing.T) {
+		rec := serve(nil, `,"max_output_tokens_hard_cap":10`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		chunks, done := streamedChunks(t, rec.Body.String())
+		require.True(t, done)
+		require.NotEmpty(t, chunks)
+		last := chunks[len(chunks)-1]
+		require.Len(t, last.Choices, 1)
+		require.Equal(t, "length", last.Choices[0].FinishReason)
+		// Each " word" chunk is one token.
+		require.Len(t, chunks, 11)
+		require.Equal(t, strings.Repeat(" word", 10), contentOf(chunks))
+	})
+
+	t.Run("key cap wins over a larger request cap", func(t *testing.T) {
+		key := &auth.APIKey{ID: "key-1", Metadata: auth.Metadata{"max_output_tokens_hard_cap": float64(4)}}
+		rec := serve(key, `,"max_output_tokens_hard_cap":50`)
+		chunks, done := streamedChunks(t, rec.Body.String())
+		require.True(t, done)
+		require.Equal(t, "length", chunks[len(chunks)-1].Choices[0].FinishReason)
+		require.Equal(t, strings.Repeat(" word", 4), contentOf(chunks))
+	})
+
+	t.Run("invalid cap", func(t *testing.T) {
+		rec := serve(nil, `,"max_output_tokens_hard_cap":0`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("uncapped", func(t *testing.T) {
+		rec := serve(nil, "")
+		chunks, done := streamedChunks(t, rec.Body.String())
+		require.True(t, done)
+		require.Len(t, chunks, 1000)
+	})
+}
+
+func TestChatCompletion_OutputTokenCapLimitsMaxTokens(t *testing.T) {
+	var sent struct {
+		MaxTokens           int `json:"max_tokens"`
+		MaxCompletionTokens int `json:"max_completion_tokens"`
+	}
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent.MaxTokens, sent.MaxCompletionTokens = 0, 0
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"id":"chatcmpl-1","object":"chat.completion","created":1,"model":"gpt-4o",`+
+			`"choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],`+
+			`"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+	}))
+	defer mock.Close()
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             mock.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+		llmux.WithPricingFallback(llmux.PricingFallback{Policy: llmux.PricingPolicyWarn}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	handler := NewClientHandler(client, logger, nil)
+	key := &auth.APIKey{ID: "key-1", Metadata: auth.Metadata{"max_output_tokens_hard_cap": float64(4)}}
+
+	sentLimit := func(extra string) int {
+		body := `{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}]` + extra + `}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader([]byte(body)))
+		req = req.WithContext(auth.WithAuthContext(req.Context(), &auth.AuthContext{APIKey: key}))
+		rec := httptest.NewRecorder()
+		handler.ChatCompletions(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		return max(sent.MaxTokens, sent.MaxCompletionTokens)
+	}
+
+	require.Equal(t, 4, sentLimit(""), "the key cap applies to requests without max_tokens")
+	require.Equal(t, 4, sentLimit(`,"max_tokens":100`))
+	require.Equal(t, 4, sentLimit(`,"max_completion_tokens":100`))
+	require.Equal(t, 2, sentLimit(`,"max_tokens":2`), "smaller limits are kept")
+	require.Equal(t, 3, sentLimit(`,"max_tokens":100,"max_output_tokens_hard_cap":3`))
+}
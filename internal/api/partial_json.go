@@ -0,0 +1,91 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+)
+
+// streamFormatField is the request body extension selecting the stream
+// encoding, e.g. {"stream_format": "json_merge_patch"}.
+const streamFormatField = "stream_format"
+
+// streamFormatJSONMergePatch streams structured output as RFC 7396 merge
+// patches against the partially parsed document instead of text deltas.
+const streamFormatJSONMergePatch = "json_merge_patch"
+
+// patchChunkObject is the object type of merge patch stream chunks.
+const patchChunkObject = "chat.completion.chunk.patch"
+
+type streamFormatKey struct{}
+
+// applyStreamFormat reads the stream_format body extension and attaches it
+// to the request context. The extension is removed from req.Extra so it is
+// not forwarded upstream. Merge patch streaming requires a json_schema
+// response format.
+func applyStreamFormat(r *http.Request, req *llmux.ChatRequest) (*http.Request, error) {
+	raw, exists := req.Extra[streamFormatField]
+	if !exists {
+		return r, nil
+	}
+	delete(req.Extra, streamFormatField)
+
+	var format string
+	if err := json.Unmarshal(raw, &format); err != nil {
+		return r, err
+	}
+	switch format {
+	case "", "text":
+		return r, nil
+	case streamFormatJSONMergePatch:
+		if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_schema" {
+			return r, fmt.Errorf("%s requires response_format json_schema", format)
+		}
+		return r.WithContext(context.WithValue(r.Context(), streamFormatKey{}, format)), nil
+	default:
+		return r, fmt.Errorf("unsupported value %q", format)
+	}
+}
+
+// jsonMergePatchRequested reports whether the stream should be encoded as merge patches.
+func jsonMergePatchRequested(ctx context.Context) bool {
+	format, _ := ctx.Value(streamFormatKey{}).(string)
+	return format == streamFormatJSONMergePatch
+}
+
+// patchChunk is a stream chunk carrying a merge patch for the first choice.
+type patchChunk struct {
+	ID           string          `json:"id"`
+	Object       string          `json:"object"`
+	Created      int64           `json:"created"`
+	Model        string          `json:"model"`
+	Patch        json.RawMessage `json:"patch,omitempty"`
+	FinishReason string          `json:"finish_reason,omitempty"`
+	Usage        *llmux.Usage    `json:"usage,omitempty"`
+}
+
+// toPatchChunk feeds chunk's content delta into partial and returns the
+// chunk to emit. It returns false when there is nothing new to send.
+func toPatchChunk(partial *llmux.PartialJSONStream, chunk *llmux.StreamChunk) (*patchChunk, bool) {
+	out := &patchChunk{
+		ID:      chunk.ID,
+		Object:  patchChunkObject,
+		Created: chunk.Created,
+		Model:   chunk.Model,
+		Usage:   chunk.Usage,
+	}
+	if len(chunk.Choices) > 0 {
+		out.FinishReason = chunk.Choices[0].FinishReason
+		if patch, changed := partial.Push(chunk.Choices[0].Delta.Content); changed {
+			out.Patch = patch
+		}
+	}
+	if out.Patch == nil && out.FinishReason == "" && out.Usage == nil {
+		return nil, false
+	}
+	return out, true
+}
@@ -0,0 +1,61 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+)
+
+func TestApplyStreamFormat(t *testing.T) {
+	req := &llmux.ChatRequest{
+		ResponseFormat: &llmux.ResponseFormat{Type: "json_schema"},
+		Extra:          map[string]json.RawMessage{"stream_format": json.RawMessage(`"json_merge_patch"`)},
+	}
+	r, err := applyStreamFormat(httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil), req)
+	if err != nil {
+		t.Fatalf("applyStreamFormat() error = %v", err)
+	}
+	if _, ok := req.Extra["stream_format"]; ok {
+		t.Fatal("expected stream_format extension to be removed from extra fields")
+	}
+	if !jsonMergePatchRequested(r.Context()) {
+		t.Fatal("expected merge patch streaming to be requested")
+	}
+
+	if jsonMergePatchRequested(context.Background()) {
+		t.Fatal("merge patch streaming should be opt-in")
+	}
+
+	text := &llmux.ChatRequest{Extra: map[string]json.RawMessage{"stream_format": json.RawMessage(`"json_merge_patch"`)}}
+	if _, err := applyStreamFormat(httptest.NewRequest(http.MethodPost, "/", nil), text); err == nil {
+		t.Fatal("expected error without json_schema response format")
+	}
+	unknown := &llmux.ChatRequest{Extra: map[string]json.RawMessage{"stream_format": json.RawMessage(`"xml"`)}}
+	if _, err := applyStreamFormat(httptest.NewRequest(http.MethodPost, "/", nil), unknown); err == nil {
+		t.Fatal("expected error for unsupported stream format")
+	}
+}
+
+func TestToPatchChunk(t *testing.T) {
+	partial := llmux.NewPartialJSONStream()
+	chunk := func(content, finish string) *llmux.StreamChunk {
+		return &llmux.StreamChunk{ID: "c1", Model: "gpt-4o", Choices: []llmux.StreamChoice{{Delta: llmux.StreamDelta{Content: content}, FinishReason: finish}}}
+	}
+
+	out, ok := toPatchChunk(partial, chunk(`{"city":"Paris"`, ""))
+	if !ok || string(out.Patch) != `{"city":"Paris"}` || out.Object != patchChunkObject {
+		t.Fatalf("first chunk = %+v, %v", out, ok)
+	}
+	if _, ok := toPatchChunk(partial, chunk(`,"zip`, "")); ok {
+		t.Fatal("expected no chunk while a key is incomplete")
+	}
+	out, ok = toPatchChunk(partial, chunk(`":"75001"}`, "stop"))
+	if !ok || string(out.Patch) != `{"zip":"75001"}` || out.FinishReason != "stop" {
+		t.Fatalf("final chunk = %+v, %v", out, ok)
+	}
+}
@@ -0,0 +1,75 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+// streamedPayload is the retained response of a streamed request.
+type streamedPayload struct {
+	Content string       `json:"content"`
+	Usage   *llmux.Usage `json:"usage,omitempty"`
+}
+
+// retainPayload keeps the request body and response of teams opted in to
+// payload logging. The response is encoded before returning and stored in
+// the background, so a slow store does not delay the client.
+func (h *ClientHandler) retainPayload(ctx context.Context, requestID, model, callType string, request []byte, response any, err error) {
+	if h.payloads == nil {
+		return
+	}
+	var teamID, apiKeyID string
+	if authCtx := auth.GetAuthContext(ctx); authCtx != nil {
+		if authCtx.APIKey != nil {
+			apiKeyID = authCtx.APIKey.ID
+			if authCtx.APIKey.TeamID != nil {
+				teamID = *authCtx.APIKey.TeamID
+			}
+		}
+		if authCtx.Team != nil {
+			teamID = authCtx.Team.ID
+		}
+	}
+	if !h.payloads.Enabled(teamID) {
+		return
+	}
+
+	log := &auth.PayloadLog{
+		RequestID:  requestID,
+		APIKeyID:   apiKeyID,
+		TeamID:     teamID,
+		Model:      model,
+		CallType:   callType,
+		StatusCode: http.StatusOK,
+		Request:    request,
+	}
+	if response != nil {
+		data, marshalErr := json.Marshal(response)
+		if marshalErr != nil {
+			h.logger.Warn("failed to encode payload log", "request_id", requestID, "error", marshalErr)
+			return
+		}
+		log.Response = data
+	}
+	if err != nil {
+		log.Error = err.Error()
+		log.StatusCode = http.StatusServiceUnavailable
+		var llmErr *llmerrors.LLMError
+		if errors.As(err, &llmErr) {
+			log.StatusCode = llmErr.HTTPStatusCode()
+		}
+	}
+
+	go func() {
+		if err := h.payloads.Record(context.WithoutCancel(ctx), log); err != nil {
+			h.logger.Warn("failed to retain payload", "request_id", requestID, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,24 @@
+package api //nolint:revive // package name is intentional
+
+import "net/http"
+
+// GetRequestPayloadLog handles GET /logs/requests/{request_id}, returning the
+// retained request and response payload of a request.
+func (h *ManagementHandler) GetRequestPayloadLog(w http.ResponseWriter, r *http.Request) {
+	if h.payloadLogs == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "payload logging is not enabled")
+		return
+	}
+
+	log, err := h.payloadLogs.GetPayloadLog(r.Context(), r.PathValue("request_id"))
+	if err != nil {
+		h.logger.Error("failed to get payload log", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to get payload log")
+		return
+	}
+	if log == nil {
+		h.writeError(w, r, http.StatusNotFound, "no payload retained for request")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, log)
+}
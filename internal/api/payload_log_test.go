@@ -0,0 +1,103 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/observability"
+)
+
+func TestClientHandler_RetainsPayloadOfOptedInTeam(t *testing.T) {
+	mock := newMockOpenAIServer()
+	defer mock.Close()
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             mock.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	payloadStore := auth.NewMemoryPayloadLogStore()
+	payloads := auth.NewPayloadLogger(payloadStore, auth.PayloadLoggerConfig{Teams: []string{"team-a"}})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewClientHandler(client, logger, &ClientHandlerConfig{PayloadLogger: payloads})
+
+	send := func(requestID, teamID string) {
+		body := []byte(`{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}]}`)
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(body))
+		ctx := observability.ContextWithRequestID(req.Context(), requestID)
+		req = req.WithContext(auth.WithAuthContext(ctx, &auth.AuthContext{
+			APIKey: &auth.APIKey{ID: "key-" + teamID},
+			Team:   &auth.Team{ID: teamID},
+		}))
+		rec := httptest.NewRecorder()
+		handler.ChatCompletions(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+	send("req-opted-in", "team-a")
+	send("req-opted-out", "team-b")
+
+	var retained *auth.PayloadLog
+	require.Eventually(t, func() bool {
+		retained, _ = payloadStore.GetPayloadLog(context.Background(), "req-opted-in")
+		return retained != nil
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, "team-a", retained.TeamID)
+	require.Equal(t, "key-team-a", retained.APIKeyID)
+	require.Equal(t, http.StatusOK, retained.StatusCode)
+	require.Contains(t, string(retained.Request), `"content":"hi"`)
+	require.Contains(t, string(retained.Response), `"content":"ok"`)
+
+	skipped, err := payloadStore.GetPayloadLog(context.Background(), "req-opted-out")
+	require.NoError(t, err)
+	require.Nil(t, skipped)
+}
+
+func TestGetRequestPayloadLog(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := NewManagementHandler(auth.NewMemoryStore(), nil, logger, nil, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs/requests/req-1", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	store := auth.NewMemoryPayloadLogStore()
+	require.NoError(t, store.SavePayloadLog(context.Background(), &auth.PayloadLog{
+		RequestID: "req-1",
+		TeamID:    "team-a",
+		Request:   json.RawMessage(`{"model":"gpt-4"}`),
+	}))
+	h.SetPayloadLogStore(store)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs/requests/req-1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got auth.PayloadLog
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Equal(t, "team-a", got.TeamID)
+	require.JSONEq(t, `{"model":"gpt-4"}`, string(got.Request))
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs/requests/missing", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,128 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"net/http"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/pkg/pricing"
+)
+
+type modelPriceResponse struct {
+	Model      string             `json:"model"`
+	Provider   string             `json:"provider,omitempty"`
+	Price      pricing.ModelPrice `json:"price"`
+	Overridden bool               `json:"overridden"`
+}
+
+func priceAuditValue(price pricing.ModelPrice) map[string]any {
+	return map[string]any{
+		"provider":              price.Provider,
+		"input_cost_per_token":  price.InputCostPerToken,
+		"output_cost_per_token": price.OutputCostPerToken,
+	}
+}
+
+// pricingRegistry returns the live client's pricing registry, writing an
+// error when no client is available.
+func (h *ManagementHandler) pricingRegistry(w http.ResponseWriter, r *http.Request) (*pricing.Registry, bool) {
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "client not available")
+		return nil, false
+	}
+	return client.Pricing(), true
+}
+
+// GetModelPrice returns the price of a model, optionally for the provider
+// given as ?provider=, and whether it is a runtime override.
+func (h *ManagementHandler) GetModelPrice(w http.ResponseWriter, r *http.Request) {
+	registry, ok := h.pricingRegistry(w, r)
+	if !ok {
+		return
+	}
+	model := r.PathValue("model")
+	provider := r.URL.Query().Get("provider")
+	price, overridden, found := registry.Lookup(model, provider)
+	if !found {
+		h.writeError(w, r, http.StatusNotFound, "no pricing for model")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, modelPriceResponse{
+		Model:      model,
+		Provider:   provider,
+		Price:      price,
+		Overridden: overridden,
+	})
+}
+
+// SetModelPrice overrides the price of a model, or of "provider/model", until
+// the override is deleted. Overrides outlive pricing file reloads and remote
+// refreshes.
+func (h *ManagementHandler) SetModelPrice(w http.ResponseWriter, r *http.Request) {
+	registry, ok := h.pricingRegistry(w, r)
+	if !ok {
+		return
+	}
+	model := r.PathValue("model")
+
+	var price pricing.ModelPrice
+	if err := json.NewDecoder(r.Body).Decode(&price); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if price.InputCostPerToken < 0 || price.OutputCostPerToken < 0 ||
+		price.CacheReadCostPerToken < 0 || price.CacheWriteCostPerToken < 0 || price.OutputCostPerReasoningToken < 0 {
+		h.writeError(w, r, http.StatusBadRequest, "costs cannot be negative")
+		return
+	}
+	if price.MaxOutputTokens < 0 {
+		h.writeError(w, r, http.StatusBadRequest, "max_output_tokens cannot be negative")
+		return
+	}
+
+	var before map[string]any
+	if prev, _, found := registry.Lookup(model, ""); found {
+		before = priceAuditValue(prev)
+	}
+	registry.SetOverride(model, price)
+	h.logger.Info("model price overridden", "model", model,
+		"input_cost_per_token", price.InputCostPerToken, "output_cost_per_token", price.OutputCostPerToken)
+	h.auditControlAction(r, auth.AuditActionConfigUpdate, auth.AuditObjectConfig, "pricing:"+model, true, before, priceAuditValue(price), map[string]any{
+		"action": "pricing_override",
+	}, "")
+
+	h.writeJSON(w, http.StatusOK, modelPriceResponse{Model: model, Price: price, Overridden: true})
+}
+
+// DeleteModelPrice removes a price override, restoring the loaded price.
+func (h *ManagementHandler) DeleteModelPrice(w http.ResponseWriter, r *http.Request) {
+	registry, ok := h.pricingRegistry(w, r)
+	if !ok {
+		return
+	}
+	model := r.PathValue("model")
+
+	prev, overridden := registry.Overrides()[model]
+	if !overridden || !registry.DeleteOverride(model) {
+		h.writeError(w, r, http.StatusNotFound, "no price override for model")
+		return
+	}
+	h.logger.Info("model price override removed", "model", model)
+	h.auditControlAction(r, auth.AuditActionConfigUpdate, auth.AuditObjectConfig, "pricing:"+model, true, priceAuditValue(prev), nil, map[string]any{
+		"action": "pricing_override_delete",
+	}, "")
+
+	h.writeJSON(w, http.StatusOK, map[string]any{"deleted": model})
+}
+
+// ListPriceOverrides lists the runtime price overrides by model.
+func (h *ManagementHandler) ListPriceOverrides(w http.ResponseWriter, r *http.Request) {
+	registry, ok := h.pricingRegistry(w, r)
+	if !ok {
+		return
+	}
+	h.writeJSON(w, http.StatusOK, map[string]any{"data": registry.Overrides()})
+}
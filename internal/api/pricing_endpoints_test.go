@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+func TestPricingEndpoints_OverrideModelPrice(t *testing.T) {
+	mux, client, auditStore := newControlTestServer(t)
+
+	getPrice := func() modelPriceResponse {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/control/pricing/models/gpt-4o?provider=openai", http.NoBody))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET price status = %d body=%s", rec.Code, rec.Body.String())
+		}
+		var resp modelPriceResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode price: %v", err)
+		}
+		return resp
+	}
+	before := getPrice()
+	if before.Overridden || before.Price.InputCostPerToken == 0 {
+		t.Fatalf("initial price = %+v", before)
+	}
+
+	body := []byte(`{"input_cost_per_token":0.5,"output_cost_per_token":1}`)
+	req := addTestAuthContext(httptest.NewRequest(http.MethodPut, "/control/pricing/models/gpt-4o", bytes.NewReader(body)))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT price status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	if after := getPrice(); !after.Overridden || after.Price.InputCostPerToken != 0.5 {
+		t.Fatalf("price after override = %+v", after)
+	}
+	if price, ok := client.Pricing().GetPrice("gpt-4o", "openai"); !ok || price.OutputCostPerToken != 1 {
+		t.Fatalf("client price = %+v", price)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/control/pricing/overrides", http.NoBody))
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte(`"gpt-4o"`)) {
+		t.Fatalf("GET overrides status = %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, addTestAuthContext(httptest.NewRequest(http.MethodDelete, "/control/pricing/models/gpt-4o", http.NoBody)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("DELETE price status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	if after := getPrice(); after.Overridden || after.Price.InputCostPerToken != before.Price.InputCostPerToken {
+		t.Fatalf("price after delete = %+v, want %+v", after, before)
+	}
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/control/pricing/models/gpt-4o", http.NoBody))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("second DELETE status = %d, want 404", rec.Code)
+	}
+
+	logs, _, err := auditStore.ListAuditLogs(auth.AuditLogFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("list audit logs: %v", err)
+	}
+	if len(logs) != 2 || logs[0].ObjectID != "pricing:gpt-4o" {
+		t.Fatalf("audit logs = %+v", logs)
+	}
+}
+
+func TestPricingEndpoints_RejectsInvalidPrice(t *testing.T) {
+	mux, client, _ := newControlTestServer(t)
+
+	for _, body := range []string{`{"input_cost_per_token":-1}`, `not json`} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/control/pricing/models/gpt-4o", bytes.NewReader([]byte(body))))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("PUT %s status = %d, want 400", body, rec.Code)
+		}
+	}
+	if overrides := client.Pricing().Overrides(); len(overrides) != 0 {
+		t.Fatalf("overrides = %v", overrides)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/control/pricing/models/no-such-model", http.NoBody))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("GET unknown model status = %d, want 404", rec.Code)
+	}
+}
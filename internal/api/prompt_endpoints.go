@@ -0,0 +1,209 @@
+// Package api provides HTTP handlers for the LLM gateway API.
+// Prompt template registry endpoints.
+package api //nolint:revive // package name is intentional
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+const (
+	defaultPromptVersionLimit = 50
+	maxPromptIDLength         = 255
+)
+
+// CreatePromptRequest stores a new version of a prompt template. Message
+// content may reference request variables as {{name}}.
+type CreatePromptRequest struct {
+	PromptID string               `json:"prompt_id"`
+	Messages []auth.PromptMessage `json:"messages"`
+	Model    string               `json:"model,omitempty"`
+	Comment  string               `json:"comment,omitempty"`
+}
+
+// promptResponse is a prompt version with the variables it references.
+type promptResponse struct {
+	*auth.PromptVersion
+	Variables []string `json:"variables"`
+}
+
+func newPromptResponse(v *auth.PromptVersion) promptResponse {
+	variables := v.Variables()
+	if variables == nil {
+		variables = []string{}
+	}
+	return promptResponse{PromptVersion: v, Variables: variables}
+}
+
+func (h *ManagementHandler) promptStore(w http.ResponseWriter, r *http.Request) (auth.PromptStore, bool) {
+	prompts, ok := auth.Prompts(h.store)
+	if !ok {
+		h.writeError(w, r, http.StatusNotImplemented, "prompt management is not supported by the configured store")
+	}
+	return prompts, ok
+}
+
+// ListPrompts handles GET /v1/prompts
+func (h *ManagementHandler) ListPrompts(w http.ResponseWriter, r *http.Request) {
+	prompts, ok := h.promptStore(w, r)
+	if !ok {
+		return
+	}
+	list, err := prompts.ListPrompts(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list prompts", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to list prompts")
+		return
+	}
+	data := make([]promptResponse, 0, len(list))
+	for _, v := range list {
+		data = append(data, newPromptResponse(v))
+	}
+	h.writeJSON(w, http.StatusOK, map[string]any{"data": data})
+}
+
+// CreatePrompt handles POST /v1/prompts. Each call stores the next version
+// of the prompt.
+func (h *ManagementHandler) CreatePrompt(w http.ResponseWriter, r *http.Request) {
+	prompts, ok := h.promptStore(w, r)
+	if !ok {
+		return
+	}
+	var req CreatePromptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.PromptID = strings.TrimSpace(req.PromptID)
+	if req.PromptID == "" {
+		h.writeError(w, r, http.StatusBadRequest, "prompt_id is required")
+		return
+	}
+	if len(req.PromptID) > maxPromptIDLength || strings.Contains(req.PromptID, "/") {
+		h.writeError(w, r, http.StatusBadRequest, "prompt_id must be at most 255 characters without '/'")
+		return
+	}
+	if len(req.Messages) == 0 {
+		h.writeError(w, r, http.StatusBadRequest, "messages is required")
+		return
+	}
+	for _, msg := range req.Messages {
+		switch msg.Role {
+		case "system", "developer", "user", "assistant":
+		default:
+			h.writeError(w, r, http.StatusBadRequest, "message role must be system, developer, user or assistant")
+			return
+		}
+	}
+
+	actor := auditActorFromContext(auth.GetAuthContext(r.Context()))
+	v := &auth.PromptVersion{
+		PromptID:  req.PromptID,
+		Messages:  req.Messages,
+		Model:     req.Model,
+		Comment:   req.Comment,
+		CreatedBy: actor.id,
+	}
+	if err := prompts.CreatePromptVersion(r.Context(), v); err != nil {
+		h.logger.Error("failed to create prompt version", "prompt_id", req.PromptID, "error", err)
+		h.auditControlAction(r, auth.AuditActionCreate, auth.AuditObjectPrompt, req.PromptID, false, nil, nil, nil, err.Error())
+		h.writeError(w, r, http.StatusInternalServerError, "failed to create prompt version")
+		return
+	}
+	h.auditControlAction(r, auth.AuditActionCreate, auth.AuditObjectPrompt, v.PromptID, true,
+		nil, map[string]any{"version": v.Version, "model": v.Model}, nil, "")
+	h.writeJSON(w, http.StatusCreated, newPromptResponse(v))
+}
+
+// GetPrompt handles GET /v1/prompts/{prompt_id}. ?version=N returns a
+// specific version instead of the latest.
+func (h *ManagementHandler) GetPrompt(w http.ResponseWriter, r *http.Request) {
+	prompts, ok := h.promptStore(w, r)
+	if !ok {
+		return
+	}
+	version := 0
+	if raw := r.URL.Query().Get("version"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			h.writeError(w, r, http.StatusBadRequest, "invalid version")
+			return
+		}
+		version = v
+	}
+
+	v, err := prompts.GetPromptVersion(r.Context(), r.PathValue("prompt_id"), version)
+	if err != nil {
+		h.logger.Error("failed to get prompt", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to get prompt")
+		return
+	}
+	if v == nil {
+		h.writeError(w, r, http.StatusNotFound, "prompt not found")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, newPromptResponse(v))
+}
+
+// ListPromptVersions handles GET /v1/prompts/{prompt_id}/versions
+func (h *ManagementHandler) ListPromptVersions(w http.ResponseWriter, r *http.Request) {
+	prompts, ok := h.promptStore(w, r)
+	if !ok {
+		return
+	}
+	limit := defaultPromptVersionLimit
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+		limit = v
+	}
+
+	versions, err := prompts.ListPromptVersions(r.Context(), r.PathValue("prompt_id"), limit)
+	if err != nil {
+		h.logger.Error("failed to list prompt versions", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to list prompt versions")
+		return
+	}
+	if len(versions) == 0 {
+		h.writeError(w, r, http.StatusNotFound, "prompt not found")
+		return
+	}
+	data := make([]promptResponse, 0, len(versions))
+	for _, v := range versions {
+		data = append(data, newPromptResponse(v))
+	}
+	h.writeJSON(w, http.StatusOK, map[string]any{"data": data})
+}
+
+// DeletePrompt handles DELETE /v1/prompts/{prompt_id}, removing every
+// version of the prompt.
+func (h *ManagementHandler) DeletePrompt(w http.ResponseWriter, r *http.Request) {
+	prompts, ok := h.promptStore(w, r)
+	if !ok {
+		return
+	}
+	promptID := r.PathValue("prompt_id")
+	latest, err := prompts.GetPromptVersion(r.Context(), promptID, 0)
+	if err != nil {
+		h.logger.Error("failed to get prompt", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to delete prompt")
+		return
+	}
+	if latest == nil {
+		h.writeError(w, r, http.StatusNotFound, "prompt not found")
+		return
+	}
+
+	before := map[string]any{"version": latest.Version}
+	if err := prompts.DeletePrompt(r.Context(), promptID); err != nil {
+		h.logger.Error("failed to delete prompt", "prompt_id", promptID, "error", err)
+		h.auditControlAction(r, auth.AuditActionDelete, auth.AuditObjectPrompt, promptID, false, before, nil, nil, err.Error())
+		h.writeError(w, r, http.StatusInternalServerError, "failed to delete prompt")
+		return
+	}
+	h.auditControlAction(r, auth.AuditActionDelete, auth.AuditObjectPrompt, promptID, true, before, nil, nil, "")
+	h.writeJSON(w, http.StatusOK, map[string]any{"deleted": promptID})
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+func TestPromptEndpoints_Versions(t *testing.T) {
+	mux, _, auditStore := newControlTestServer(t)
+
+	create := func(body string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, addTestAuthContext(httptest.NewRequest(http.MethodPost, "/v1/prompts", bytes.NewReader([]byte(body)))))
+		return rec
+	}
+	get := func(path string) (int, promptResponse) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		var resp promptResponse
+		if rec.Code == http.StatusOK {
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode prompt: %v", err)
+			}
+		}
+		return rec.Code, resp
+	}
+
+	rec := create(`{"prompt_id":"support","model":"gpt-4","messages":[{"role":"system","content":"Help with {{product}}"}]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	rec = create(`{"prompt_id":"support","comment":"mention tiers","messages":[{"role":"system","content":"Help {{tier}} customers with {{product}}"}]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("second POST status = %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	status, latest := get("/v1/prompts/support")
+	if status != http.StatusOK || latest.Version != 2 || len(latest.Variables) != 2 || latest.Variables[0] != "tier" {
+		t.Fatalf("GET latest = %d %+v", status, latest)
+	}
+	status, first := get("/v1/prompts/support?version=1")
+	if status != http.StatusOK || first.Version != 1 || first.Model != "gpt-4" || first.CreatedBy != "user-1" {
+		t.Fatalf("GET version 1 = %d %+v", status, first)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/prompts/support/versions", http.NoBody))
+	var versions struct {
+		Data []promptResponse `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&versions); err != nil || len(versions.Data) != 2 || versions.Data[0].Version != 2 {
+		t.Fatalf("GET versions = %d %+v (%v)", rec.Code, versions, err)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/prompts", http.NoBody))
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte(`"prompt_id":"support"`)) {
+		t.Fatalf("GET prompts = %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, addTestAuthContext(httptest.NewRequest(http.MethodDelete, "/v1/prompts/support", http.NoBody)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("DELETE status = %d body=%s", rec.Code, rec.Body.String())
+	}
+	if status, _ := get("/v1/prompts/support"); status != http.StatusNotFound {
+		t.Fatalf("GET after delete status = %d, want 404", status)
+	}
+
+	logs, _, err := auditStore.ListAuditLogs(auth.AuditLogFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("list audit logs: %v", err)
+	}
+	if len(logs) != 3 || logs[0].ObjectType != auth.AuditObjectPrompt || logs[0].Action != auth.AuditActionDelete {
+		t.Fatalf("audit logs = %+v", logs)
+	}
+}
+
+func TestPromptEndpoints_RejectsInvalidPrompt(t *testing.T) {
+	mux, _, _ := newControlTestServer(t)
+
+	for _, body := range []string{
+		`{"messages":[{"role":"system","content":"hi"}]}`,
+		`{"prompt_id":"a/b","messages":[{"role":"system","content":"hi"}]}`,
+		`{"prompt_id":"support"}`,
+		`{"prompt_id":"support","messages":[{"role":"tool","content":"hi"}]}`,
+		`not json`,
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/prompts", bytes.NewReader([]byte(body))))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("POST %s status = %d, want 400", body, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/prompts/support?version=0", http.NoBody))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("GET version 0 status = %d, want 400", rec.Code)
+	}
+}
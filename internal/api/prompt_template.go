@@ -0,0 +1,150 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/observability"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+// Request body fields referencing a stored prompt template, e.g.
+// {"prompt_id": "support", "variables": {"product": "Acme"}}. The rendered
+// messages are prepended to the request's own; prompt_version pins a
+// version instead of the latest.
+const (
+	promptIDField        = "prompt_id"
+	promptVersionField   = "prompt_version"
+	promptVariablesField = "variables"
+)
+
+// promptTemplateExtension is the body form of a prompt template reference.
+type promptTemplateExtension struct {
+	ID        string
+	Version   int
+	Variables map[string]string
+}
+
+// promptRefKey carries the prompt version that served a request.
+type promptRefKey struct{}
+
+// parsePromptTemplateExtension reads and removes the prompt template fields
+// so they are not forwarded upstream. It returns nil, leaving the body
+// untouched, when prompt_id is absent. Variables that are not strings are
+// passed as their JSON text.
+func parsePromptTemplateExtension(req *llmux.ChatRequest) (*promptTemplateExtension, error) {
+	rawID, exists := req.Extra[promptIDField]
+	if !exists {
+		return nil, nil
+	}
+	rawVersion, hasVersion := req.Extra[promptVersionField]
+	rawVariables, hasVariables := req.Extra[promptVariablesField]
+	delete(req.Extra, promptIDField)
+	delete(req.Extra, promptVersionField)
+	delete(req.Extra, promptVariablesField)
+
+	var ext promptTemplateExtension
+	if err := json.Unmarshal(rawID, &ext.ID); err != nil || ext.ID == "" {
+		return nil, fmt.Errorf("%s must be a non-empty string", promptIDField)
+	}
+	if hasVersion {
+		if err := json.Unmarshal(rawVersion, &ext.Version); err != nil || ext.Version <= 0 {
+			return nil, fmt.Errorf("%s must be a positive integer", promptVersionField)
+		}
+	}
+	if hasVariables {
+		var variables map[string]json.RawMessage
+		if err := json.Unmarshal(rawVariables, &variables); err != nil {
+			return nil, fmt.Errorf("%s must be an object", promptVariablesField)
+		}
+		ext.Variables = make(map[string]string, len(variables))
+		for name, raw := range variables {
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				s = string(raw)
+			}
+			ext.Variables[name] = s
+		}
+	}
+	return &ext, nil
+}
+
+// applyPromptTemplate renders the referenced prompt version with the
+// request variables and prepends its messages to the request's own. The
+// prompt's model is used when the request names none.
+func (h *ClientHandler) applyPromptTemplate(ctx context.Context, req *llmux.ChatRequest, ext *promptTemplateExtension) (*auth.PromptVersion, error) {
+	return applyPromptTemplate(ctx, h.store, h.logger, req, ext)
+}
+
+func applyPromptTemplate(ctx context.Context, store auth.Store, logger *slog.Logger, req *llmux.ChatRequest, ext *promptTemplateExtension) (*auth.PromptVersion, error) {
+	if ext == nil {
+		return nil, nil
+	}
+	prompts, ok := auth.Prompts(store)
+	if !ok {
+		return nil, llmerrors.NewInvalidRequestError("", req.Model, "prompt_id requires a store with prompt management")
+	}
+	prompt, err := prompts.GetPromptVersion(ctx, ext.ID, ext.Version)
+	if err != nil {
+		logger.Error("failed to load prompt", "prompt_id", ext.ID, "error", err)
+		return nil, llmerrors.NewInternalError("", req.Model, "failed to load prompt")
+	}
+	if prompt == nil {
+		return nil, llmerrors.NewInvalidRequestError("", req.Model, fmt.Sprintf("prompt %q not found", ext.ID))
+	}
+	rendered, err := prompt.Render(ext.Variables)
+	if err != nil {
+		return nil, llmerrors.NewInvalidRequestError("", req.Model, fmt.Sprintf("invalid prompt %q: %v", ext.ID, err))
+	}
+
+	messages := make([]llmux.ChatMessage, 0, len(rendered)+len(req.Messages))
+	for _, msg := range rendered {
+		content, err := json.Marshal(msg.Content)
+		if err != nil {
+			return nil, llmerrors.NewInternalError("", req.Model, "failed to render prompt")
+		}
+		messages = append(messages, llmux.ChatMessage{Role: msg.Role, Content: content})
+	}
+	req.Messages = append(messages, req.Messages...)
+	if req.Model == "" {
+		req.Model = prompt.Model
+	}
+	return prompt, nil
+}
+
+// withPromptRef records on ctx the prompt version that served the request.
+func withPromptRef(ctx context.Context, prompt *auth.PromptVersion) context.Context {
+	if prompt == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, promptRefKey{}, prompt)
+}
+
+// promptRefMetadata adds the prompt_id and prompt_version recorded on ctx to
+// metadata, so usage logs show which prompt version served each request.
+func promptRefMetadata(ctx context.Context, metadata map[string]any) map[string]any {
+	prompt, ok := ctx.Value(promptRefKey{}).(*auth.PromptVersion)
+	if !ok {
+		return metadata
+	}
+	out := make(map[string]any, len(metadata)+2)
+	for k, v := range metadata {
+		out[k] = v
+	}
+	out[promptIDField] = prompt.PromptID
+	out[promptVersionField] = prompt.Version
+	return out
+}
+
+// recordPromptRef adds the prompt reference on ctx to the observability
+// payload metadata.
+func recordPromptRef(ctx context.Context, payload *observability.StandardLoggingPayload) {
+	if payload != nil {
+		payload.Metadata = promptRefMetadata(ctx, payload.Metadata)
+	}
+}
@@ -0,0 +1,123 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/observability"
+)
+
+func TestClientHandler_PromptTemplate(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		upstream llmux.ChatRequest
+	)
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		_ = json.NewDecoder(r.Body).Decode(&upstream)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"c","object":"chat.completion","model":"gpt-4","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	t.Cleanup(mock.Close)
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             mock.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4"},
+		}),
+		llmux.WithPricingFallback(llmux.PricingFallback{Policy: llmux.PricingPolicyWarn}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	store := &usageRecordingStore{MemoryStore: auth.NewMemoryStore()}
+	ctx := context.Background()
+	require.NoError(t, store.CreatePromptVersion(ctx, &auth.PromptVersion{
+		PromptID: "support",
+		Messages: []auth.PromptMessage{{Role: "system", Content: "Old prompt for {{product}}"}},
+		Model:    "gpt-4",
+	}))
+	require.NoError(t, store.CreatePromptVersion(ctx, &auth.PromptVersion{
+		PromptID: "support",
+		Messages: []auth.PromptMessage{{Role: "system", Content: "You support {{product}} (tier {{tier}})."}},
+		Model:    "gpt-4",
+	}))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	handler := NewClientHandler(client, logger, &ClientHandlerConfig{Store: store})
+	send := func(requestID, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewBufferString(body))
+		req = req.WithContext(observability.ContextWithRequestID(req.Context(), requestID))
+		handler.ChatCompletions(rec, req)
+		return rec
+	}
+
+	// The model comes from the prompt; numbers are passed as their JSON text.
+	rec := send("req-1", `{"prompt_id":"support","variables":{"product":"Acme","tier":2},"messages":[{"role":"user","content":"hi"}]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	mu.Lock()
+	require.Len(t, upstream.Messages, 2)
+	assert.Equal(t, "You support Acme (tier 2).", upstream.Messages[0].TextContent())
+	assert.Equal(t, "hi", upstream.Messages[1].TextContent())
+	assert.NotContains(t, upstream.Extra, "prompt_id", "the extension is never forwarded upstream")
+	assert.NotContains(t, upstream.Extra, "variables")
+	mu.Unlock()
+
+	rec = send("req-2", `{"model":"gpt-4","prompt_id":"support","prompt_version":1,"variables":{"product":"Acme"}}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	mu.Lock()
+	require.Len(t, upstream.Messages, 1)
+	assert.Equal(t, "Old prompt for Acme", upstream.Messages[0].TextContent())
+	mu.Unlock()
+
+	require.Eventually(t, func() bool { return len(store.byRequestID()) == 2 }, 2*time.Second, 10*time.Millisecond)
+	logs := store.byRequestID()
+	assert.Equal(t, "support", logs["req-1"].Metadata["prompt_id"])
+	assert.Equal(t, 2, logs["req-1"].Metadata["prompt_version"])
+	assert.Equal(t, 1, logs["req-2"].Metadata["prompt_version"])
+
+	rec = send("req-3", `{"prompt_id":"support","variables":{"product":"Acme"}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "missing variables are rejected")
+	assert.Contains(t, rec.Body.String(), "tier")
+
+	rec = send("req-4", `{"model":"gpt-4","prompt_id":"missing","messages":[{"role":"user","content":"hi"}]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestParsePromptTemplateExtension(t *testing.T) {
+	req := &llmux.ChatRequest{Extra: map[string]json.RawMessage{"variables": json.RawMessage(`{"a":"b"}`)}}
+	ext, err := parsePromptTemplateExtension(req)
+	require.NoError(t, err)
+	assert.Nil(t, ext)
+	assert.Contains(t, req.Extra, "variables", "bodies without prompt_id are forwarded as is")
+
+	req = &llmux.ChatRequest{Extra: map[string]json.RawMessage{
+		"prompt_id":      json.RawMessage(`"support"`),
+		"prompt_version": json.RawMessage(`0`),
+	}}
+	_, err = parsePromptTemplateExtension(req)
+	assert.Error(t, err, "versions start at 1")
+
+	req = &llmux.ChatRequest{Extra: map[string]json.RawMessage{"prompt_id": json.RawMessage(`""`)}}
+	_, err = parsePromptTemplateExtension(req)
+	assert.Error(t, err, "prompt_id must not be empty")
+}
@@ -0,0 +1,66 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/provenance"
+)
+
+// VerifyProvenanceRequest is the body of POST /provenance/verify.
+type VerifyProvenanceRequest struct {
+	// Body is the response body exactly as received, including the trailing newline.
+	Body       string `json:"body"`
+	Provenance string `json:"provenance"` // X-LLMux-Provenance header value
+	Signature  string `json:"signature"`  // X-LLMux-Signature header value
+}
+
+// VerifyProvenanceResponse reports whether a signed response is authentic.
+type VerifyProvenanceResponse struct {
+	Valid  bool               `json:"valid"`
+	Claims *provenance.Claims `json:"claims,omitempty"`
+	Error  string             `json:"error,omitempty"`
+}
+
+// VerifyProvenance handles POST /provenance/verify.
+func (h *ManagementHandler) VerifyProvenance(w http.ResponseWriter, r *http.Request) {
+	if h.signer == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "response signing not enabled")
+		return
+	}
+
+	var req VerifyProvenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Provenance == "" || req.Signature == "" {
+		h.writeError(w, r, http.StatusBadRequest, "provenance and signature are required")
+		return
+	}
+
+	claims, err := h.signer.Verify([]byte(req.Body), req.Provenance, req.Signature)
+	resp := VerifyProvenanceResponse{Valid: err == nil}
+	switch {
+	case err == nil:
+		resp.Claims = claims
+	case errors.Is(err, provenance.ErrBodyMismatch), errors.Is(err, provenance.ErrInvalidSignature):
+		resp.Error = err.Error()
+	default:
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// GetProvenanceKeys handles GET /provenance/keys, returning the JWK set used
+// to verify response signatures offline.
+func (h *ManagementHandler) GetProvenanceKeys(w http.ResponseWriter, r *http.Request) {
+	if h.signer == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "response signing not enabled")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, h.signer.PublicKeys())
+}
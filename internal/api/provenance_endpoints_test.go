@@ -0,0 +1,113 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/provenance"
+)
+
+func newTestSigner(t *testing.T) *provenance.Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := provenance.NewSigner(key, "test-key")
+	require.NoError(t, err)
+	return signer
+}
+
+func TestClientHandler_SignsResponses(t *testing.T) {
+	mock := newMockOpenAIServer()
+	defer mock.Close()
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             mock.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	signer := newTestSigner(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewClientHandler(client, logger, &ClientHandlerConfig{Signer: signer})
+
+	body := []byte(`{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ChatCompletions(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	prov := rec.Header().Get(provenance.HeaderProvenance)
+	sig := rec.Header().Get(provenance.HeaderSignature)
+	require.NotEmpty(t, prov)
+	require.NotEmpty(t, sig)
+
+	claims, err := signer.Verify(rec.Body.Bytes(), prov, sig)
+	require.NoError(t, err)
+	require.Equal(t, "gpt-4o", claims.Model)
+	require.Equal(t, "openai", claims.Provider)
+	require.NotEmpty(t, claims.Deployment)
+	require.NotEmpty(t, claims.RequestID)
+
+	// The management endpoint verifies the same response.
+	mgmt := NewManagementHandler(auth.NewMemoryStore(), nil, slog.New(slog.NewTextHandler(os.Stderr, nil)), nil, nil, nil)
+	mgmt.SetResponseSigner(signer)
+	mux := http.NewServeMux()
+	mgmt.RegisterRoutes(mux)
+
+	verify := func(responseBody string) VerifyProvenanceResponse {
+		t.Helper()
+		payload, err := json.Marshal(VerifyProvenanceRequest{Body: responseBody, Provenance: prov, Signature: sig})
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodPost, "/provenance/verify", bytes.NewReader(payload))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, addTestAuthContext(r))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp VerifyProvenanceResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	ok := verify(rec.Body.String())
+	require.True(t, ok.Valid)
+	require.Equal(t, claims.Deployment, ok.Claims.Deployment)
+
+	tampered := verify(rec.Body.String() + " ")
+	require.False(t, tampered.Valid)
+	require.Equal(t, provenance.ErrBodyMismatch.Error(), tampered.Error)
+
+	keysReq := httptest.NewRequest(http.MethodGet, "/provenance/keys", nil)
+	keysRec := httptest.NewRecorder()
+	mux.ServeHTTP(keysRec, addTestAuthContext(keysReq))
+	require.Equal(t, http.StatusOK, keysRec.Code)
+	require.Contains(t, keysRec.Body.String(), `"kid":"test-key"`)
+}
+
+func TestVerifyProvenance_Disabled(t *testing.T) {
+	mgmt := NewManagementHandler(auth.NewMemoryStore(), nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil)
+	mux := http.NewServeMux()
+	mgmt.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/provenance/verify", bytes.NewReader([]byte(`{}`)))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, addTestAuthContext(req))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
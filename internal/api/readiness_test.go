@@ -0,0 +1,49 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+)
+
+func TestClientHandler_ReadinessCheck(t *testing.T) {
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             "http://127.0.0.1:1",
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	ready := func(h *ClientHandler) int {
+		rec := httptest.NewRecorder()
+		h.ReadinessCheck(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+		return rec.Code
+	}
+
+	plain := NewClientHandler(client, logger, nil)
+	tied := NewClientHandler(client, logger, &ClientHandlerConfig{ReadinessRequiresProvider: true})
+	require.Equal(t, http.StatusOK, ready(tied))
+
+	deployments := client.ListDeployments()
+	require.Len(t, deployments, 1)
+	require.NoError(t, client.SetCooldown(deployments[0].ID, time.Now().Add(time.Minute)))
+	require.Equal(t, http.StatusServiceUnavailable, ready(tied))
+	require.Equal(t, http.StatusOK, ready(plain))
+
+	require.NoError(t, client.SetCooldown(deployments[0].ID, time.Time{}))
+	require.Equal(t, http.StatusOK, ready(tied))
+}
@@ -0,0 +1,287 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/governance"
+	"github.com/blueberrycongee/llmux/internal/observability"
+)
+
+// maxReplayRequests bounds the requests replayed by one call, since each
+// one is sent upstream.
+const maxReplayRequests = 50
+
+// replayStrippedFields are gateway request extensions that only apply to
+// the original request and are never forwarded upstream.
+var replayStrippedFields = []string{
+	cacheExtensionField,
+	streamFormatField,
+	outputTokenCapField,
+	memoryExtensionField,
+	mcpPromptField,
+}
+
+// ReplayRequest replays retained request payloads. Model replaces the
+// recorded model; DeploymentID sends every request to that deployment
+// instead of routing it.
+type ReplayRequest struct {
+	RequestIDs   []string `json:"request_ids"`
+	Model        string   `json:"model,omitempty"`
+	DeploymentID string   `json:"deployment_id,omitempty"`
+}
+
+// ReplayResult compares the recorded response of a request with its replay.
+// Diff lists the lines of both contents prefixed with "- " when only in the
+// original, "+ " when only in the replay and "  " when in both.
+type ReplayResult struct {
+	RequestID       string       `json:"request_id"`
+	ReplayRequestID string       `json:"replay_request_id,omitempty"`
+	Model           string       `json:"model,omitempty"`
+	Deployment      string       `json:"deployment,omitempty"`
+	PromptID        string       `json:"prompt_id,omitempty"`
+	PromptVersion   int          `json:"prompt_version,omitempty"`
+	OriginalContent string       `json:"original_content"`
+	ReplayContent   string       `json:"replay_content"`
+	Identical       bool         `json:"identical"`
+	Diff            []string     `json:"diff,omitempty"`
+	OriginalUsage   *llmux.Usage `json:"original_usage,omitempty"`
+	ReplayUsage     *llmux.Usage `json:"replay_usage,omitempty"`
+	LatencyMs       int64        `json:"latency_ms,omitempty"`
+	Error           string       `json:"error,omitempty"`
+}
+
+// ReplayRequests handles POST /logs/replay. It sends retained chat
+// completion payloads upstream again, bypassing the response cache, and
+// diffs each replayed response against the recorded one, e.g. to check for
+// regressions after a provider or prompt change. Requests referencing a
+// prompt template render its current version unless they pinned one.
+// Replays are accounted like other requests, against the calling key.
+func (h *ManagementHandler) ReplayRequests(w http.ResponseWriter, r *http.Request) {
+	if h.payloadLogs == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "payload logging is not enabled")
+		return
+	}
+
+	var req ReplayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.RequestIDs) == 0 {
+		h.writeError(w, r, http.StatusBadRequest, "request_ids is required")
+		return
+	}
+	if len(req.RequestIDs) > maxReplayRequests {
+		h.writeError(w, r, http.StatusBadRequest, "at most 50 requests can be replayed at once")
+		return
+	}
+
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "client not available")
+		return
+	}
+
+	ctx := llmux.WithCacheControl(r.Context(), llmux.CacheControl{NoCache: true, NoStore: true})
+	results := make([]ReplayResult, 0, len(req.RequestIDs))
+	var identical, changed, failed int
+	for _, requestID := range req.RequestIDs {
+		result := h.replayRequest(ctx, client, requestID, req)
+		switch {
+		case result.Error != "":
+			failed++
+		case result.Identical:
+			identical++
+		default:
+			changed++
+		}
+		results = append(results, result)
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"data": results,
+		"summary": map[string]int{
+			"total":     len(results),
+			"identical": identical,
+			"changed":   changed,
+			"failed":    failed,
+		},
+	})
+}
+
+// replayRequest replays one retained request. Failures are reported on the
+// result so one bad payload does not abort the batch.
+func (h *ManagementHandler) replayRequest(ctx context.Context, client *llmux.Client, requestID string, opts ReplayRequest) ReplayResult {
+	result := ReplayResult{RequestID: requestID}
+	log, err := h.payloadLogs.GetPayloadLog(ctx, requestID)
+	if err != nil {
+		h.logger.Error("failed to get payload log", "request_id", requestID, "error", err)
+		result.Error = "failed to get payload log"
+		return result
+	}
+	if log == nil {
+		result.Error = "no payload retained for request"
+		return result
+	}
+	if log.CallType != "" && log.CallType != governance.CallTypeChatCompletion {
+		result.Error = "only chat completions can be replayed"
+		return result
+	}
+	result.OriginalContent, result.OriginalUsage = recordedCompletion(log.Response)
+
+	var chatReq llmux.ChatRequest
+	if err := json.Unmarshal(log.Request, &chatReq); err != nil {
+		result.Error = "retained request is not a chat completion: " + err.Error()
+		return result
+	}
+	for _, field := range replayStrippedFields {
+		delete(chatReq.Extra, field)
+	}
+	ext, err := parsePromptTemplateExtension(&chatReq)
+	if err != nil {
+		result.Error = "invalid prompt template: " + err.Error()
+		return result
+	}
+	prompt, err := applyPromptTemplate(ctx, h.store, h.logger, &chatReq, ext)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if prompt != nil {
+		result.PromptID, result.PromptVersion = prompt.PromptID, prompt.Version
+	}
+	if opts.Model != "" {
+		chatReq.Model = opts.Model
+	}
+	chatReq.Stream = false
+	chatReq.StreamOptions = nil
+	if err := killSwitchError(ctx, h.killSwitch, chatReq.Model); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	start := time.Now()
+	var resp *llmux.ChatResponse
+	if opts.DeploymentID != "" {
+		resp, err = client.ChatCompletionOnDeployment(ctx, opts.DeploymentID, &chatReq)
+	} else {
+		resp, err = client.ChatCompletion(ctx, &chatReq)
+	}
+	latency := time.Since(start)
+	result.LatencyMs = latency.Milliseconds()
+	result.Model = chatReq.Model
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.ReplayRequestID = observability.GenerateRequestID()
+	h.accountReplay(ctx, client, result, resp, chatReq.Tags, start, latency)
+	if len(resp.Choices) > 0 {
+		result.ReplayContent = resp.Choices[0].Message.TextContent()
+	}
+	result.ReplayUsage = resp.Usage
+	if resp.Usage != nil {
+		result.Deployment = resp.Usage.Deployment
+	}
+	result.Identical = result.OriginalContent == result.ReplayContent
+	if !result.Identical {
+		result.Diff = lineDiff(result.OriginalContent, result.ReplayContent)
+	}
+	return result
+}
+
+// accountReplay records the usage and spend of a replayed request against
+// the key that triggered the replay.
+func (h *ManagementHandler) accountReplay(ctx context.Context, client *llmux.Client, result ReplayResult, resp *llmux.ChatResponse, tags []string, start time.Time, latency time.Duration) {
+	if resp.Usage == nil {
+		return
+	}
+	model := result.Model
+	if resp.Model != "" {
+		model = resp.Model
+	}
+	recordUsage(ctx, h.governance, h.store, h.logger, governance.AccountInput{
+		RequestID:   result.ReplayRequestID,
+		Model:       model,
+		CallType:    governance.CallTypeChatCompletion,
+		RequestTags: tags,
+		Usage: governance.Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+			Cost:             client.CalculateCost(model, resp.Usage),
+			Provider:         resp.Usage.Provider,
+		},
+		Start:    start,
+		Latency:  latency,
+		Metadata: map[string]any{"replay_of": result.RequestID},
+	})
+}
+
+// recordedCompletion returns the content and usage of a retained response,
+// which is either a chat completion or the streamed payload of a stream.
+func recordedCompletion(raw json.RawMessage) (string, *llmux.Usage) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+	var resp llmux.ChatResponse
+	if err := json.Unmarshal(raw, &resp); err == nil && len(resp.Choices) > 0 {
+		return resp.Choices[0].Message.TextContent(), resp.Usage
+	}
+	var streamed streamedPayload
+	if err := json.Unmarshal(raw, &streamed); err == nil {
+		return streamed.Content, streamed.Usage
+	}
+	return "", nil
+}
+
+// lineDiff returns a line diff of a and b from their longest common
+// subsequence of lines.
+func lineDiff(a, b string) []string {
+	before, after := strings.Split(a, "\n"), strings.Split(b, "\n")
+	// lcs[i][j] is the common subsequence length of before[i:] and after[j:].
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := make([]string, 0, len(before)+len(after))
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			diff = append(diff, "  "+before[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "- "+before[i])
+			i++
+		default:
+			diff = append(diff, "+ "+after[j])
+			j++
+		}
+	}
+	for ; i < len(before); i++ {
+		diff = append(diff, "- "+before[i])
+	}
+	for ; j < len(after); j++ {
+		diff = append(diff, "+ "+after[j])
+	}
+	return diff
+}
@@ -0,0 +1,143 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+func TestReplayRequests(t *testing.T) {
+	// The mock answers with the first message, so replies follow the prompt.
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llmux.ChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp := map[string]any{
+			"id":      "c",
+			"object":  "chat.completion",
+			"model":   req.Model,
+			"choices": []map[string]any{{"index": 0, "message": map[string]any{"role": "assistant", "content": req.Messages[0].TextContent()}, "finish_reason": "stop"}},
+			"usage":   map[string]any{"prompt_tokens": 2, "completion_tokens": 1, "total_tokens": 3},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(mock.Close)
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             mock.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+		llmux.WithPricingFallback(llmux.PricingFallback{Policy: llmux.PricingPolicyWarn}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	store := auth.NewMemoryStore()
+	require.NoError(t, store.CreatePromptVersion(ctx, &auth.PromptVersion{
+		PromptID: "support",
+		Messages: []auth.PromptMessage{{Role: "system", Content: "Answer in {{language}}\nBe brief"}},
+	}))
+	payloads := auth.NewMemoryPayloadLogStore()
+	require.NoError(t, payloads.SavePayloadLog(ctx, &auth.PayloadLog{
+		RequestID: "req-prompt",
+		CallType:  "chat_completion",
+		Request:   json.RawMessage(`{"model":"gpt-4o","prompt_id":"support","variables":{"language":"French"},"cache":{"no-cache":true},"messages":[{"role":"user","content":"hi"}]}`),
+		Response:  json.RawMessage(`{"choices":[{"index":0,"message":{"role":"assistant","content":"Answer in English\nBe brief"}}],"usage":{"prompt_tokens":5,"completion_tokens":4,"total_tokens":9}}`),
+	}))
+	require.NoError(t, payloads.SavePayloadLog(ctx, &auth.PayloadLog{
+		RequestID: "req-stream",
+		CallType:  "chat_completion",
+		Request:   json.RawMessage(`{"model":"gpt-4o","stream":true,"messages":[{"role":"user","content":"hi"}]}`),
+		Response:  json.RawMessage(`{"content":"hi"}`),
+	}))
+	require.NoError(t, payloads.SavePayloadLog(ctx, &auth.PayloadLog{
+		RequestID: "req-embedding",
+		CallType:  "embedding",
+		Request:   json.RawMessage(`{"model":"text-embedding-3-small","input":"hi"}`),
+	}))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := NewManagementHandler(store, nil, logger, NewClientSwapper(client), nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	replay := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/logs/replay", bytes.NewBufferString(body))
+		req = req.WithContext(context.WithValue(req.Context(), auth.AuthContextKey, &auth.AuthContext{
+			APIKey: &auth.APIKey{ID: "admin-key"},
+		}))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := replay(`{"request_ids":["req-1"]}`)
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code, "payload logging is required")
+	h.SetPayloadLogStore(payloads)
+
+	rec = replay(`{"request_ids":["req-prompt","req-stream","req-embedding","missing"]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp struct {
+		Data    []ReplayResult `json:"data"`
+		Summary map[string]int `json:"summary"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, map[string]int{"total": 4, "identical": 1, "changed": 1, "failed": 2}, resp.Summary)
+
+	prompted := resp.Data[0]
+	require.Empty(t, prompted.Error)
+	require.False(t, prompted.Identical)
+	require.Equal(t, "support", prompted.PromptID)
+	require.Equal(t, 1, prompted.PromptVersion)
+	require.Equal(t, "Answer in French\nBe brief", prompted.ReplayContent)
+	require.Equal(t, []string{"- Answer in English", "+ Answer in French", "  Be brief"}, prompted.Diff)
+	require.Equal(t, 9, prompted.OriginalUsage.TotalTokens)
+	require.Equal(t, 3, prompted.ReplayUsage.TotalTokens)
+	require.NotEmpty(t, prompted.ReplayRequestID)
+
+	streamed := resp.Data[1]
+	require.True(t, streamed.Identical, streamed.Error)
+	require.Empty(t, streamed.Diff)
+	require.Equal(t, "only chat completions can be replayed", resp.Data[2].Error)
+	require.Equal(t, "no payload retained for request", resp.Data[3].Error)
+
+	adminKey := "admin-key"
+	require.Eventually(t, func() bool {
+		stats, err := store.GetUsageStats(ctx, auth.UsageFilter{APIKeyID: &adminKey, EndTime: time.Now().Add(time.Minute)})
+		return err == nil && stats.TotalRequests == 2 && stats.TotalTokens == 6
+	}, time.Second, 10*time.Millisecond, "replays are accounted against the calling key")
+
+	rec = replay(`{"request_ids":["req-stream"],"deployment_id":"openai-gpt-4o"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, "openai-gpt-4o", resp.Data[0].Deployment)
+	require.True(t, resp.Data[0].Identical)
+
+	rec = replay(`{"request_ids":["req-stream"],"deployment_id":"missing"}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Contains(t, resp.Data[0].Error, "not found")
+
+	rec = replay(`{"request_ids":[]}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestLineDiff(t *testing.T) {
+	require.Equal(t, []string{"  a", "- b", "+ x", "  c", "+ d"}, lineDiff("a\nb\nc", "a\nx\nc\nd"))
+	require.Equal(t, []string{"  same"}, lineDiff("same", "same"))
+}
@@ -23,55 +23,64 @@ import (
 func (h *ClientHandler) Responses(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 	r, requestID := h.ensureRequestID(r)
+	r = h.applyRequestPriority(r)
 
 	limitedReader := io.LimitReader(r.Body, h.maxBodySize+1)
 	body, err := io.ReadAll(limitedReader)
 	if err != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "failed to read request body"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "failed to read request body"))
 		return
 	}
 	defer func() { _ = r.Body.Close() }()
 
 	if int64(len(body)) > h.maxBodySize {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "request body too large"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "request body too large"))
 		return
 	}
 
 	var req types.ResponseRequest
 	if unmarshalErr := json.Unmarshal(body, &req); unmarshalErr != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "invalid JSON: "+unmarshalErr.Error()))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "invalid JSON: "+unmarshalErr.Error()))
 		return
 	}
 	if req.Model == "" {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", "model is required"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "model is required"))
 		return
 	}
 	if validateErr := types.ValidateModelName(req.Model); validateErr != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", "", validateErr.Error()))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", validateErr.Error()))
 		return
 	}
 
 	chatReq, err := req.ToChatRequest()
 	if err != nil {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", req.Model, err.Error()))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, err.Error()))
+		return
+	}
+	chatReq.Tags = requestTags(r, chatReq.Tags)
+	if r, err = applyCacheControl(r, chatReq); err != nil {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, "invalid cache field: "+err.Error()))
 		return
 	}
 	if len(chatReq.Messages) == 0 {
-		h.writeError(w, llmerrors.NewInvalidRequestError("", req.Model, "input is required"))
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", req.Model, "input is required"))
 		return
 	}
+	capMaxTokens(r.Context(), chatReq)
 
 	payload := h.buildChatObservabilityPayload(r, chatReq, start, requestID)
 	payload.CallType = observability.CallTypeResponse
-	ctx, endSpan := h.startSpan(r.Context(), payload)
+	ctx, endSpan := h.startSpan(r, payload)
 	defer endSpan()
 	h.observePre(ctx, payload)
 
-	if evalErr := h.evaluateGovernance(ctx, r, chatReq.Model, chatReq.User, chatReq.Tags, governance.CallTypeChatCompletion); evalErr != nil {
+	tags, evalErr := h.evaluateGovernance(ctx, r, chatReq.Model, chatReq.User, chatReq.Tags, messagesContent(chatReq.Messages), governance.CallTypeChatCompletion)
+	if evalErr != nil {
 		h.observePost(ctx, payload, evalErr)
-		h.writeError(w, evalErr)
+		h.writeError(w, r, evalErr)
 		return
 	}
+	chatReq.Tags = tags
 
 	manager := h.getMCPManager(ctx)
 
@@ -80,7 +89,7 @@ func (h *ClientHandler) Responses(w http.ResponseWriter, r *http.Request) {
 	if client == nil {
 		err := llmerrors.NewInternalError("", chatReq.Model, "client not initialized")
 		h.observePost(ctx, payload, err)
-		h.writeError(w, err)
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -97,10 +106,7 @@ func (h *ClientHandler) Responses(w http.ResponseWriter, r *http.Request) {
 
 	var resp *llmux.ChatResponse
 	if manager != nil {
-		executor := mcp.NewAgentExecutor(manager, 0, h.logger)
-		resp, err = executor.Execute(ctx, chatReq, func(execCtx context.Context, r *llmux.ChatRequest) (*llmux.ChatResponse, error) {
-			return client.ChatCompletion(execCtx, r)
-		})
+		resp, err = h.runAgentLoop(ctx, manager, client, chatReq, requestID)
 	} else {
 		resp, err = client.ChatCompletion(ctx, chatReq)
 	}
@@ -108,9 +114,9 @@ func (h *ClientHandler) Responses(w http.ResponseWriter, r *http.Request) {
 		h.observePost(ctx, payload, err)
 		h.logger.Error("response completion failed", "model", chatReq.Model, "error", err)
 		if llmErr, ok := err.(*llmerrors.LLMError); ok {
-			h.writeError(w, llmErr)
+			h.writeError(w, r, llmErr)
 		} else {
-			h.writeError(w, llmerrors.NewServiceUnavailableError("", chatReq.Model, err.Error()))
+			h.writeError(w, r, llmerrors.NewServiceUnavailableError("", chatReq.Model, err.Error()))
 		}
 		return
 	}
@@ -181,6 +187,7 @@ func (h *ClientHandler) Responses(w http.ResponseWriter, r *http.Request) {
 	}
 	h.observePost(ctx, payload, nil)
 
+	setCacheStatusHeader(w, resp.CacheStatus)
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.logger.Error("failed to encode response", "error", err)
@@ -193,9 +200,9 @@ func (h *ClientHandler) handleResponseStream(ctx context.Context, w http.Respons
 		h.observePost(ctx, payload, err)
 		h.logger.Error("response stream creation failed", "model", req.Model, "error", err)
 		if llmErr, ok := err.(*llmerrors.LLMError); ok {
-			h.writeError(w, llmErr)
+			h.writeError(w, r, llmErr)
 		} else {
-			h.writeError(w, llmerrors.NewServiceUnavailableError("", req.Model, err.Error()))
+			h.writeError(w, r, llmerrors.NewServiceUnavailableError("", req.Model, err.Error()))
 		}
 		return
 	}
@@ -208,9 +215,11 @@ func (h *ClientHandler) handleResponseStream(ctx context.Context, w http.Respons
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
-		h.writeError(w, llmerrors.NewInternalError("", req.Model, "streaming not supported"))
+		h.writeError(w, r, llmerrors.NewInternalError("", req.Model, "streaming not supported"))
 		return
 	}
+	sse := newSSEWriter(w, flusher, h.sseHeartbeat)
+	defer sse.Close()
 
 	var finalUsage *llmux.Usage
 	var responseID string
@@ -236,6 +245,7 @@ func (h *ClientHandler) handleResponseStream(ctx context.Context, w http.Respons
 			break
 		}
 
+		recordFirstToken(payload, stream)
 		h.observeStreamEvent(ctx, payload, chunk)
 
 		if responseID == "" && chunk.ID != "" {
@@ -256,7 +266,7 @@ func (h *ClientHandler) handleResponseStream(ctx context.Context, w http.Respons
 					Type:  "response.output_text.delta",
 					Delta: delta,
 				}
-				h.writeResponseEvent(w, flusher, event)
+				h.writeResponseEvent(sse, event)
 			}
 		}
 	}
@@ -315,30 +325,20 @@ func (h *ClientHandler) handleResponseStream(ctx context.Context, w http.Respons
 			Type:     "response.completed",
 			Response: response,
 		}
-		h.writeResponseEvent(w, flusher, event)
-		_, _ = w.Write([]byte("data: [DONE]\n\n"))
-		flusher.Flush()
+		h.writeResponseEvent(sse, event)
+		_ = sse.WriteDone("")
 	}
 
 	h.observePost(ctx, payload, streamErr)
 }
 
-func (h *ClientHandler) writeResponseEvent(w http.ResponseWriter, flusher http.Flusher, event types.ResponseStreamChunk) {
+func (h *ClientHandler) writeResponseEvent(sse *sseWriter, event types.ResponseStreamChunk) {
 	data, err := json.Marshal(event)
 	if err != nil {
 		h.logger.Error("failed to marshal response stream event", "error", err)
 		return
 	}
-	if _, err := w.Write([]byte("data: ")); err != nil {
-		return
-	}
-	if _, err := w.Write(data); err != nil {
-		return
-	}
-	if _, err := w.Write([]byte("\n\n")); err != nil {
-		return
-	}
-	flusher.Flush()
+	_ = sse.WriteEvent("", data)
 }
 
 func responseFromStream(responseID, responseModel string, created int64, fallbackModel string, content string, usage *llmux.Usage) *types.ResponseResponse {
@@ -156,6 +156,56 @@ func TestResponsesHandler_Streaming_DoesNotForceIncludeUsage(t *testing.T) {
 	require.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestResponsesHandler_StripsCacheExtension(t *testing.T) {
+	var forwarded []byte
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded, _ = io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":      "chatcmpl-1",
+			"object":  "chat.completion",
+			"created": time.Now().Unix(),
+			"model":   "gpt-4o",
+			"choices": []map[string]any{{
+				"index":         0,
+				"message":       map[string]any{"role": "assistant", "content": "ok"},
+				"finish_reason": "stop",
+			}},
+		})
+	}))
+	defer mock.Close()
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             mock.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	handler := NewClientHandler(client, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/responses",
+		strings.NewReader(`{"model":"gpt-4o","input":"hello","cache":{"no-cache":true}}`))
+	rec := httptest.NewRecorder()
+	handler.Responses(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEmpty(t, forwarded)
+	require.NotContains(t, string(forwarded), `"cache"`)
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/responses",
+		strings.NewReader(`{"model":"gpt-4o","input":"hello","cache":{"ttl":"soon"}}`))
+	rec = httptest.NewRecorder()
+	handler.Responses(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 func TestAudioAndBatchEndpoints_NotSupported(t *testing.T) {
 	mock := newResponsesMockServer()
 	defer mock.Close()
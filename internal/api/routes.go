@@ -12,6 +12,7 @@ func (h *ManagementHandler) RegisterRoutes(mux *http.ServeMux) {
 	// Key Management Routes
 	// ========================================================================
 	mux.HandleFunc("POST /key/generate", h.GenerateKey)
+	mux.HandleFunc("POST /key/temporary", h.GenerateTemporaryKey)
 	mux.HandleFunc("POST /key/update", h.UpdateKey)
 	mux.HandleFunc("POST /key/delete", h.DeleteKey)
 	mux.HandleFunc("GET /key/info", h.GetKeyInfo)
@@ -32,6 +33,8 @@ func (h *ManagementHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /team/unblock", h.UnblockTeam)
 	mux.HandleFunc("POST /team/member_add", h.AddTeamMember)
 	mux.HandleFunc("POST /team/member_delete", h.DeleteTeamMember)
+	mux.HandleFunc("GET /team/banned_content", h.GetTeamBannedContent)
+	mux.HandleFunc("POST /team/banned_content", h.SetTeamBannedContent)
 
 	// ========================================================================
 	// User Management Routes
@@ -42,6 +45,35 @@ func (h *ManagementHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /user/info", h.GetUserInfo)
 	mux.HandleFunc("GET /user/list", h.ListUsers)
 
+	// ========================================================================
+	// Model Access Group Routes
+	// ========================================================================
+	mux.HandleFunc("POST /access_group/new", h.NewAccessGroup)
+	mux.HandleFunc("POST /access_group/update", h.UpdateAccessGroup)
+	mux.HandleFunc("POST /access_group/delete", h.DeleteAccessGroup)
+	mux.HandleFunc("GET /access_group/info", h.GetAccessGroupInfo)
+	mux.HandleFunc("GET /access_group/list", h.ListAccessGroups)
+
+	// ========================================================================
+	// Model Management Routes
+	// ========================================================================
+	mux.HandleFunc("POST /model/new", h.NewModel)
+	mux.HandleFunc("POST /model/update", h.UpdateModel)
+	mux.HandleFunc("POST /model/delete", h.DeleteModel)
+	mux.HandleFunc("GET /model/info", h.GetModelInfo)
+
+	// ========================================================================
+	// Customer (End User) Management Routes
+	// ========================================================================
+	mux.HandleFunc("POST /customer/new", h.NewCustomer)
+	mux.HandleFunc("POST /customer/update", h.UpdateCustomer)
+	mux.HandleFunc("POST /customer/delete", h.DeleteCustomer)
+	mux.HandleFunc("GET /customer/info", h.GetCustomerInfo)
+	mux.HandleFunc("GET /customer/list", h.ListCustomers)
+	mux.HandleFunc("POST /customer/block", h.BlockCustomer)
+	mux.HandleFunc("POST /customer/unblock", h.UnblockCustomer)
+	mux.HandleFunc("GET /customer/spend", h.GetCustomerSpend)
+
 	// ========================================================================
 	// Organization Management Routes
 	// ========================================================================
@@ -62,6 +94,9 @@ func (h *ManagementHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /spend/keys", h.GetSpendByKeys)
 	mux.HandleFunc("GET /spend/teams", h.GetSpendByTeams)
 	mux.HandleFunc("GET /spend/users", h.GetSpendByUsers)
+	mux.HandleFunc("GET /spend/report", h.GetSpendReport)
+	mux.HandleFunc("GET /spend/tags", h.GetSpendByTags)
+	mux.HandleFunc("GET /spend/forecast", h.GetSpendForecast)
 
 	// ========================================================================
 	// Global Analytics Routes
@@ -69,6 +104,7 @@ func (h *ManagementHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /global/activity", h.GetGlobalActivity)
 	mux.HandleFunc("GET /global/spend/models", h.GetGlobalSpendByModel)
 	mux.HandleFunc("GET /global/spend/provider", h.GetGlobalSpendByProvider)
+	mux.HandleFunc("GET /global/usage/timeseries", h.GetUsageTimeSeries)
 
 	// ========================================================================
 	// Audit Log Routes
@@ -81,11 +117,54 @@ func (h *ManagementHandler) RegisterRoutes(mux *http.ServeMux) {
 	// ========================================================================
 	// Control Plane Routes
 	// ========================================================================
+	mux.HandleFunc("GET /control/health", h.GetControlHealth)
 	mux.HandleFunc("GET /control/deployments", h.ListDeployments)
 	mux.HandleFunc("POST /control/deployments/cooldown", h.UpdateDeploymentCooldown)
 	mux.HandleFunc("GET /control/providers", h.ListProviders)
+	mux.HandleFunc("GET /control/routing", h.GetRoutingPolicy)
+	mux.HandleFunc("PUT /control/routing", h.UpdateRoutingPolicy)
 	mux.HandleFunc("GET /control/config", h.GetConfigStatus)
 	mux.HandleFunc("POST /control/config/reload", h.ReloadConfig)
+	mux.HandleFunc("GET /control/config/versions", h.ListConfigVersions)
+	mux.HandleFunc("GET /control/config/versions/{version}", h.GetConfigVersion)
+	mux.HandleFunc("POST /control/config/versions", h.CommitConfigVersion)
+	mux.HandleFunc("POST /control/config/rollback", h.RollbackConfig)
+	mux.HandleFunc("POST /config/validate", h.ValidateConfig)
+	mux.HandleFunc("GET /control/cache/prefix/savings", h.GetPrefixCacheSavings)
+	mux.HandleFunc("GET /control/pricing/missing", h.GetMissingPricing)
+	mux.HandleFunc("GET /control/pricing/overrides", h.ListPriceOverrides)
+	mux.HandleFunc("GET /control/pricing/models/{model...}", h.GetModelPrice)
+	mux.HandleFunc("PUT /control/pricing/models/{model...}", h.SetModelPrice)
+	mux.HandleFunc("DELETE /control/pricing/models/{model...}", h.DeleteModelPrice)
+	mux.HandleFunc("GET /control/credentials", h.ListCredentialRollovers)
+	mux.HandleFunc("POST /control/credentials/rollback", h.RollbackCredentials)
+	mux.HandleFunc("POST /control/credentials/resume", h.ResumeCredentials)
+	mux.HandleFunc("GET /control/killswitch", h.ListBlocks)
+	mux.HandleFunc("POST /control/killswitch", h.CreateBlock)
+	mux.HandleFunc("DELETE /control/killswitch", h.DeleteBlock)
+	mux.HandleFunc("GET /control/debug/goroutines", h.GetDebugGoroutines)
+	mux.HandleFunc("GET /control/debug/pipeline", h.GetDebugPipeline)
+	mux.HandleFunc("GET /control/debug/streams", h.GetDebugStreams)
+	mux.HandleFunc("GET /control/debug/deployments", h.GetDebugDeployments)
+	mux.HandleFunc("GET /router/explain/{request_id}", h.ExplainRouting)
+	mux.HandleFunc("GET /logs/requests/{request_id}", h.GetRequestPayloadLog)
+	mux.HandleFunc("POST /logs/replay", h.ReplayRequests)
+	mux.HandleFunc("GET /admin/tail", h.TailRequests)
+
+	// ========================================================================
+	// Prompt Template Routes
+	// ========================================================================
+	mux.HandleFunc("GET /v1/prompts", h.ListPrompts)
+	mux.HandleFunc("POST /v1/prompts", h.CreatePrompt)
+	mux.HandleFunc("GET /v1/prompts/{prompt_id}", h.GetPrompt)
+	mux.HandleFunc("GET /v1/prompts/{prompt_id}/versions", h.ListPromptVersions)
+	mux.HandleFunc("DELETE /v1/prompts/{prompt_id}", h.DeletePrompt)
+
+	// ========================================================================
+	// Response Provenance Routes
+	// ========================================================================
+	mux.HandleFunc("POST /provenance/verify", h.VerifyProvenance)
+	mux.HandleFunc("GET /provenance/keys", h.GetProvenanceKeys)
 }
 
 // RouteInfo describes an API route.
@@ -101,6 +180,7 @@ func GetRoutes() []RouteInfo {
 	return []RouteInfo{
 		// Key Management
 		{Method: "POST", Path: "/key/generate", Description: "Generate a new API key", Category: "key"},
+		{Method: "POST", Path: "/key/temporary", Description: "Mint a short-lived, model-scoped API key", Category: "key"},
 		{Method: "POST", Path: "/key/update", Description: "Update an existing API key", Category: "key"},
 		{Method: "POST", Path: "/key/delete", Description: "Delete API keys", Category: "key"},
 		{Method: "GET", Path: "/key/info", Description: "Get API key information", Category: "key"},
@@ -119,6 +199,8 @@ func GetRoutes() []RouteInfo {
 		{Method: "POST", Path: "/team/unblock", Description: "Unblock a team", Category: "team"},
 		{Method: "POST", Path: "/team/member_add", Description: "Add members to a team", Category: "team"},
 		{Method: "POST", Path: "/team/member_delete", Description: "Remove members from a team", Category: "team"},
+		{Method: "GET", Path: "/team/banned_content", Description: "Get a team's banned keywords, patterns and topics", Category: "team"},
+		{Method: "POST", Path: "/team/banned_content", Description: "Set a team's banned keywords, patterns and topics", Category: "team"},
 
 		// User Management
 		{Method: "POST", Path: "/user/new", Description: "Create a new user", Category: "user"},
@@ -127,6 +209,29 @@ func GetRoutes() []RouteInfo {
 		{Method: "GET", Path: "/user/info", Description: "Get user information", Category: "user"},
 		{Method: "GET", Path: "/user/list", Description: "List users", Category: "user"},
 
+		// Customer Management
+		{Method: "POST", Path: "/customer/new", Description: "Create a customer (end user)", Category: "customer"},
+		{Method: "POST", Path: "/customer/update", Description: "Update a customer's alias, budget or block status", Category: "customer"},
+		{Method: "POST", Path: "/customer/delete", Description: "Delete customers", Category: "customer"},
+		{Method: "GET", Path: "/customer/info", Description: "Get customer information", Category: "customer"},
+		{Method: "GET", Path: "/customer/list", Description: "List customers", Category: "customer"},
+		{Method: "POST", Path: "/customer/block", Description: "Block customers", Category: "customer"},
+		{Method: "POST", Path: "/customer/unblock", Description: "Unblock customers", Category: "customer"},
+		{Method: "GET", Path: "/customer/spend", Description: "Get a customer's spend report", Category: "customer"},
+
+		// Model Access Groups
+		{Method: "POST", Path: "/access_group/new", Description: "Create a named model access group", Category: "access_group"},
+		{Method: "POST", Path: "/access_group/update", Description: "Update a model access group", Category: "access_group"},
+		{Method: "POST", Path: "/access_group/delete", Description: "Delete model access groups", Category: "access_group"},
+		{Method: "GET", Path: "/access_group/info", Description: "Get model access group information", Category: "access_group"},
+		{Method: "GET", Path: "/access_group/list", Description: "List model access groups", Category: "access_group"},
+
+		// Model Management
+		{Method: "POST", Path: "/model/new", Description: "Add a provider deployment to the live client", Category: "model"},
+		{Method: "POST", Path: "/model/update", Description: "Update a provider deployment added through the API", Category: "model"},
+		{Method: "POST", Path: "/model/delete", Description: "Remove a provider deployment added through the API", Category: "model"},
+		{Method: "GET", Path: "/model/info", Description: "Get or list provider deployments added through the API", Category: "model"},
+
 		// Organization Management
 		{Method: "POST", Path: "/organization/new", Description: "Create a new organization", Category: "organization"},
 		{Method: "PATCH", Path: "/organization/update", Description: "Update an organization", Category: "organization"},
@@ -143,11 +248,15 @@ func GetRoutes() []RouteInfo {
 		{Method: "GET", Path: "/spend/keys", Description: "Get spend by API keys", Category: "spend"},
 		{Method: "GET", Path: "/spend/teams", Description: "Get spend by teams", Category: "spend"},
 		{Method: "GET", Path: "/spend/users", Description: "Get spend by users", Category: "spend"},
+		{Method: "GET", Path: "/spend/report", Description: "Get a grouped spend report as JSON or CSV", Category: "spend"},
+		{Method: "GET", Path: "/spend/tags", Description: "Get tag budgets and their spend", Category: "spend"},
+		{Method: "GET", Path: "/spend/forecast", Description: "Project key and team spend to the end of their budget periods", Category: "spend"},
 
 		// Global Analytics
 		{Method: "GET", Path: "/global/activity", Description: "Get global activity metrics", Category: "analytics"},
 		{Method: "GET", Path: "/global/spend/models", Description: "Get spend by model", Category: "analytics"},
 		{Method: "GET", Path: "/global/spend/provider", Description: "Get spend by provider", Category: "analytics"},
+		{Method: "GET", Path: "/global/usage/timeseries", Description: "Get bucketed usage time series", Category: "analytics"},
 
 		// Audit Logs
 		{Method: "GET", Path: "/audit/logs", Description: "List audit logs", Category: "audit"},
@@ -162,13 +271,54 @@ func GetRoutes() []RouteInfo {
 		{Method: "GET", Path: "/invitation/list", Description: "List invitation links", Category: "invitation"},
 		{Method: "POST", Path: "/invitation/deactivate", Description: "Deactivate an invitation link", Category: "invitation"},
 		{Method: "POST", Path: "/invitation/delete", Description: "Delete invitation links", Category: "invitation"},
+		{Method: "POST", Path: "/invitation/resend", Description: "Reissue an invitation link with a new token", Category: "invitation"},
+		{Method: "POST", Path: "/invitation/revoke", Description: "Revoke an invitation link", Category: "invitation"},
 
 		// Control Plane
+		{Method: "GET", Path: "/control/health", Description: "Get gateway health and database schema version", Category: "control"},
 		{Method: "GET", Path: "/control/deployments", Description: "List deployments and routing status", Category: "control"},
 		{Method: "POST", Path: "/control/deployments/cooldown", Description: "Set or clear deployment cooldown", Category: "control"},
 		{Method: "GET", Path: "/control/providers", Description: "List providers and resilience stats", Category: "control"},
+		{Method: "GET", Path: "/control/routing", Description: "Get the live routing strategy, cooldown and retry policy", Category: "control"},
+		{Method: "PUT", Path: "/control/routing", Description: "Change the routing strategy, cooldown and retry policy at runtime", Category: "control"},
 		{Method: "GET", Path: "/control/config", Description: "Get current config status", Category: "control"},
 		{Method: "POST", Path: "/control/config/reload", Description: "Reload config from disk", Category: "control"},
+		{Method: "GET", Path: "/control/config/versions", Description: "List stored config versions (config.source db)", Category: "control"},
+		{Method: "GET", Path: "/control/config/versions/{version}", Description: "Get a stored config version", Category: "control"},
+		{Method: "POST", Path: "/control/config/versions", Description: "Commit and apply a new config version", Category: "control"},
+		{Method: "POST", Path: "/control/config/rollback", Description: "Re-apply an earlier config version", Category: "control"},
+		{Method: "POST", Path: "/config/validate", Description: "Validate a candidate config without applying it", Category: "control"},
+		{Method: "GET", Path: "/control/cache/prefix/savings", Description: "Get estimated prefix cache savings per team", Category: "control"},
+		{Method: "GET", Path: "/control/pricing/missing", Description: "List configured models without pricing", Category: "control"},
+		{Method: "GET", Path: "/control/pricing/overrides", Description: "List runtime model price overrides", Category: "control"},
+		{Method: "GET", Path: "/control/pricing/models/{model}", Description: "Get the price of a model", Category: "control"},
+		{Method: "PUT", Path: "/control/pricing/models/{model}", Description: "Override the price of a model at runtime", Category: "control"},
+		{Method: "DELETE", Path: "/control/pricing/models/{model}", Description: "Remove a model price override", Category: "control"},
+		{Method: "GET", Path: "/control/credentials", Description: "Get blue/green credential rollover status", Category: "control"},
+		{Method: "POST", Path: "/control/credentials/rollback", Description: "Roll back a provider to its primary API key", Category: "control"},
+		{Method: "POST", Path: "/control/credentials/resume", Description: "Resume a rolled-back credential rollover", Category: "control"},
+		{Method: "GET", Path: "/control/killswitch", Description: "List active emergency traffic blocks", Category: "control"},
+		{Method: "POST", Path: "/control/killswitch", Description: "Block all traffic, a model group or a team", Category: "control"},
+		{Method: "DELETE", Path: "/control/killswitch", Description: "Lift an emergency traffic block", Category: "control"},
+		{Method: "GET", Path: "/control/debug/goroutines", Description: "Get goroutine counts by subsystem", Category: "control"},
+		{Method: "GET", Path: "/control/debug/pipeline", Description: "Get plugin pipeline composition", Category: "control"},
+		{Method: "GET", Path: "/control/debug/streams", Description: "List active stream sessions", Category: "control"},
+		{Method: "GET", Path: "/control/debug/deployments", Description: "Get per-deployment in-flight and semaphore state", Category: "control"},
+		{Method: "GET", Path: "/router/explain/{request_id}", Description: "Explain the routing decision for a request", Category: "control"},
+		{Method: "GET", Path: "/logs/requests/{request_id}", Description: "Get the retained request and response payload of a request", Category: "control"},
+		{Method: "POST", Path: "/logs/replay", Description: "Replay retained requests against a model or deployment and diff the responses", Category: "control"},
+		{Method: "GET", Path: "/admin/tail", Description: "Stream a redacted live feed of completed requests (SSE)", Category: "control"},
+
+		// Prompt Templates
+		{Method: "GET", Path: "/v1/prompts", Description: "List prompt templates (latest versions)", Category: "prompt"},
+		{Method: "POST", Path: "/v1/prompts", Description: "Store a new version of a prompt template", Category: "prompt"},
+		{Method: "GET", Path: "/v1/prompts/{prompt_id}", Description: "Get the latest or a given version of a prompt template", Category: "prompt"},
+		{Method: "GET", Path: "/v1/prompts/{prompt_id}/versions", Description: "List the versions of a prompt template", Category: "prompt"},
+		{Method: "DELETE", Path: "/v1/prompts/{prompt_id}", Description: "Delete a prompt template and all its versions", Category: "prompt"},
+
+		// Response Provenance
+		{Method: "POST", Path: "/provenance/verify", Description: "Verify a signed response", Category: "provenance"},
+		{Method: "GET", Path: "/provenance/keys", Description: "Get the response signing public keys (JWKS)", Category: "provenance"},
 
 		// Auth
 		{Method: "GET", Path: "/auth/oidc/login", Description: "Start OIDC login", Category: "auth"},
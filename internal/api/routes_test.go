@@ -0,0 +1,25 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"testing"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+// TestGetRoutes_AreManagementRoutes guards the management prefixes of
+// auth.ClassifyRoute: a new admin route must not be reachable by data-plane
+// keys.
+func TestGetRoutes_AreManagementRoutes(t *testing.T) {
+	dataRoutes := map[string]bool{
+		"/v1/memory/{collection}/documents": true,
+	}
+	for _, route := range GetRoutes() {
+		want := auth.RouteClassManagement
+		if dataRoutes[route.Path] {
+			want = auth.RouteClassData
+		}
+		if got := auth.ClassifyRoute(route.Method, route.Path); got != want {
+			t.Errorf("ClassifyRoute(%s, %s) = %s, want %s", route.Method, route.Path, got, want)
+		}
+	}
+}
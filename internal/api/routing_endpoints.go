@@ -0,0 +1,124 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+// routingPolicyRequest updates the live routing policy. Omitted fields keep
+// their current values.
+type routingPolicyRequest struct {
+	Strategy          *string  `json:"strategy,omitempty"`
+	CooldownSeconds   *int     `json:"cooldown_seconds,omitempty"`
+	RetryCount        *int     `json:"retry_count,omitempty"`
+	RetryBackoffMs    *int     `json:"retry_backoff_ms,omitempty"`
+	RetryMaxBackoffMs *int     `json:"retry_max_backoff_ms,omitempty"`
+	RetryJitter       *float64 `json:"retry_jitter,omitempty"`
+}
+
+type routingPolicyResponse struct {
+	Strategy          string  `json:"strategy"`
+	CooldownSeconds   int     `json:"cooldown_seconds"`
+	RetryCount        int     `json:"retry_count"`
+	RetryBackoffMs    int64   `json:"retry_backoff_ms"`
+	RetryMaxBackoffMs int64   `json:"retry_max_backoff_ms"`
+	RetryJitter       float64 `json:"retry_jitter"`
+}
+
+func newRoutingPolicyResponse(p llmux.RoutingPolicy) routingPolicyResponse {
+	return routingPolicyResponse{
+		Strategy:          string(p.Strategy),
+		CooldownSeconds:   int(p.CooldownPeriod / time.Second),
+		RetryCount:        p.RetryCount,
+		RetryBackoffMs:    p.RetryBackoff.Milliseconds(),
+		RetryMaxBackoffMs: p.RetryMaxBackoff.Milliseconds(),
+		RetryJitter:       p.RetryJitter,
+	}
+}
+
+func (p routingPolicyResponse) auditValue() map[string]any {
+	return map[string]any{
+		"strategy":             p.Strategy,
+		"cooldown_seconds":     p.CooldownSeconds,
+		"retry_count":          p.RetryCount,
+		"retry_backoff_ms":     p.RetryBackoffMs,
+		"retry_max_backoff_ms": p.RetryMaxBackoffMs,
+		"retry_jitter":         p.RetryJitter,
+	}
+}
+
+// apply returns p with the request's fields set.
+func (req routingPolicyRequest) apply(p llmux.RoutingPolicy) llmux.RoutingPolicy {
+	if req.Strategy != nil {
+		p.Strategy = llmux.Strategy(*req.Strategy)
+	}
+	if req.CooldownSeconds != nil {
+		p.CooldownPeriod = time.Duration(*req.CooldownSeconds) * time.Second
+	}
+	if req.RetryCount != nil {
+		p.RetryCount = *req.RetryCount
+	}
+	if req.RetryBackoffMs != nil {
+		p.RetryBackoff = time.Duration(*req.RetryBackoffMs) * time.Millisecond
+	}
+	if req.RetryMaxBackoffMs != nil {
+		p.RetryMaxBackoff = time.Duration(*req.RetryMaxBackoffMs) * time.Millisecond
+	}
+	if req.RetryJitter != nil {
+		p.RetryJitter = *req.RetryJitter
+	}
+	return p
+}
+
+// GetRoutingPolicy reports the routing strategy, cooldown and retry policy
+// of the live client.
+func (h *ManagementHandler) GetRoutingPolicy(w http.ResponseWriter, r *http.Request) {
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "client not available")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newRoutingPolicyResponse(client.RoutingPolicy()))
+}
+
+// UpdateRoutingPolicy switches the routing strategy, cooldown and retry
+// policy of the live client without reloading the config. The change lasts
+// until the next config reload rebuilds the client.
+func (h *ManagementHandler) UpdateRoutingPolicy(w http.ResponseWriter, r *http.Request) {
+	client, release := h.acquireClient()
+	defer release()
+	if client == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "client not available")
+		return
+	}
+
+	var req routingPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	current := client.RoutingPolicy()
+	before := newRoutingPolicyResponse(current)
+	if err := client.SetRoutingPolicy(req.apply(current)); err != nil {
+		h.auditControlAction(r, auth.AuditActionConfigUpdate, auth.AuditObjectConfig, "routing", false, before.auditValue(), nil, map[string]any{
+			"action": "routing_update",
+		}, err.Error())
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	after := newRoutingPolicyResponse(client.RoutingPolicy())
+
+	h.auditControlAction(r, auth.AuditActionConfigUpdate, auth.AuditObjectConfig, "routing", true, before.auditValue(), after.auditValue(), map[string]any{
+		"action": "routing_update",
+	}, "")
+
+	h.writeJSON(w, http.StatusOK, after)
+}
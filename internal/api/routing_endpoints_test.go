@@ -0,0 +1,86 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+func TestRoutingEndpoints_UpdatePolicyAudit(t *testing.T) {
+	mux, client, auditStore := newControlTestServer(t)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/control/routing", http.NoBody))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /control/routing status = %d", rec.Code)
+	}
+	var before routingPolicyResponse
+	if err := json.NewDecoder(rec.Body).Decode(&before); err != nil {
+		t.Fatalf("decode policy: %v", err)
+	}
+	if before.Strategy != string(llmux.StrategySimpleShuffle) || before.RetryCount != 3 {
+		t.Fatalf("initial policy = %+v", before)
+	}
+
+	body := []byte(`{"strategy":"least-busy","cooldown_seconds":10,"retry_count":1,"retry_backoff_ms":50}`)
+	req := addTestAuthContext(httptest.NewRequest(http.MethodPut, "/control/routing", bytes.NewReader(body)))
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT /control/routing status = %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	policy := client.RoutingPolicy()
+	if policy.Strategy != llmux.StrategyLeastBusy || policy.CooldownPeriod != 10*time.Second ||
+		policy.RetryCount != 1 || policy.RetryBackoff != 50*time.Millisecond {
+		t.Fatalf("policy after update = %+v", policy)
+	}
+	if policy.RetryJitter != before.RetryJitter {
+		t.Fatalf("omitted retry_jitter changed: %v", policy.RetryJitter)
+	}
+
+	logs, _, err := auditStore.ListAuditLogs(auth.AuditLogFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("list audit logs: %v", err)
+	}
+	if len(logs) != 1 || !logs[0].Success || logs[0].ActorID != "user-1" || logs[0].ObjectID != "routing" {
+		t.Fatalf("audit logs = %+v", logs)
+	}
+	if logs[0].BeforeValue["strategy"] != string(llmux.StrategySimpleShuffle) || logs[0].AfterValue["strategy"] != string(llmux.StrategyLeastBusy) {
+		t.Fatalf("audit values before=%v after=%v", logs[0].BeforeValue, logs[0].AfterValue)
+	}
+}
+
+func TestRoutingEndpoints_RejectsInvalidPolicy(t *testing.T) {
+	mux, client, auditStore := newControlTestServer(t)
+
+	for _, body := range []string{
+		`{"strategy":"fastest"}`,
+		`{"retry_count":-1}`,
+		`{"retry_jitter":2}`,
+		`not json`,
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/control/routing", bytes.NewReader([]byte(body))))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("PUT %s status = %d, want 400", body, rec.Code)
+		}
+	}
+	if got := client.RoutingPolicy().Strategy; got != llmux.StrategySimpleShuffle {
+		t.Fatalf("strategy changed to %q", got)
+	}
+	logs, _, err := auditStore.ListAuditLogs(auth.AuditLogFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("list audit logs: %v", err)
+	}
+	if len(logs) != 3 || logs[0].Success {
+		t.Fatalf("expected failed audit entries, got %+v", logs)
+	}
+}
@@ -3,10 +3,14 @@
 package api //nolint:revive // package name is intentional
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/governance"
 )
 
 // ============================================================================
@@ -22,27 +26,10 @@ func (h *ManagementHandler) GetSpendLogs(w http.ResponseWriter, r *http.Request)
 	startDateStr := r.URL.Query().Get("start_date")
 	endDateStr := r.URL.Query().Get("end_date")
 
-	// Parse dates
-	var startDate, endDate time.Time
-	var err error
-	if startDateStr != "" {
-		startDate, err = time.Parse("2006-01-02", startDateStr)
-		if err != nil {
-			h.writeError(w, r, http.StatusBadRequest, "invalid start_date format, use YYYY-MM-DD")
-			return
-		}
-	} else {
-		startDate = time.Now().AddDate(0, 0, -30) // Default: last 30 days
-	}
-
-	if endDateStr != "" {
-		endDate, err = time.Parse("2006-01-02", endDateStr)
-		if err != nil {
-			h.writeError(w, r, http.StatusBadRequest, "invalid end_date format, use YYYY-MM-DD")
-			return
-		}
-	} else {
-		endDate = time.Now()
+	startDate, endDate, errMsg := parseSpendDateRange(startDateStr, endDateStr)
+	if errMsg != "" {
+		h.writeError(w, r, http.StatusBadRequest, errMsg)
+		return
 	}
 
 	filter := auth.UsageFilter{
@@ -101,6 +88,182 @@ func (h *ManagementHandler) GetSpendLogs(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// parseSpendDateRange parses YYYY-MM-DD bounds, defaulting to the last 30 days.
+// A non-empty message describes an invalid bound.
+func parseSpendDateRange(startDateStr, endDateStr string) (startDate, endDate time.Time, errMsg string) {
+	var err error
+	if startDateStr != "" {
+		startDate, err = time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			return startDate, endDate, "invalid start_date format, use YYYY-MM-DD"
+		}
+	} else {
+		startDate = time.Now().AddDate(0, 0, -30) // Default: last 30 days
+	}
+
+	if endDateStr != "" {
+		endDate, err = time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			return startDate, endDate, "invalid end_date format, use YYYY-MM-DD"
+		}
+	} else {
+		endDate = time.Now()
+	}
+	return startDate, endDate, ""
+}
+
+// GetSpendReport handles GET /spend/report. Query params: group_by (comma
+// separated key, team, model, tag, day; default team,model), start_date and
+// end_date (YYYY-MM-DD), api_key, team_id and model filters, and format
+// (json|csv).
+func (h *ManagementHandler) GetSpendReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	startDate, endDate, errMsg := parseSpendDateRange(query.Get("start_date"), query.Get("end_date"))
+	if errMsg != "" {
+		h.writeError(w, r, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	groupBy := query.Get("group_by")
+	if groupBy == "" {
+		groupBy = "team,model"
+	}
+	dims, err := auth.ParseSpendGroupBy(groupBy)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		h.writeError(w, r, http.StatusBadRequest, "format must be json or csv")
+		return
+	}
+
+	filter := auth.SpendReportFilter{
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		GroupBy:   dims,
+	}
+	if v := query.Get("api_key"); v != "" {
+		filter.APIKeyID = &v
+	}
+	if v := query.Get("team_id"); v != "" {
+		filter.TeamID = &v
+	}
+	if v := query.Get("model"); v != "" {
+		filter.Model = &v
+	}
+
+	report, err := auth.BuildSpendReport(r.Context(), h.store, filter)
+	if err != nil {
+		h.logger.Error("failed to build spend report", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to build spend report")
+		return
+	}
+	h.annotateTagBudgets(r.Context(), report)
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf(`attachment; filename="spend_report_%s_%s.csv"`, filter.StartDate, filter.EndDate))
+		w.WriteHeader(http.StatusOK)
+		if err := report.WriteCSV(w); err != nil {
+			h.logger.Error("failed to write spend report", "error", err)
+		}
+		return
+	}
+	h.writeJSON(w, http.StatusOK, report)
+}
+
+// GetSpendForecast handles GET /spend/forecast, projecting key and team spend
+// to the end of their budget periods. Query params: entity (key|team,
+// default both), api_key and team_id filters, history_days (1-365, default
+// 28) and over_budget=true to list only entities projected to exceed their
+// budget.
+func (h *ManagementHandler) GetSpendForecast(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := auth.SpendForecastFilter{Entity: query.Get("entity")}
+	if filter.Entity != "" && filter.Entity != auth.SpendGroupKey && filter.Entity != auth.SpendGroupTeam {
+		h.writeError(w, r, http.StatusBadRequest, "entity must be key or team")
+		return
+	}
+	if v := query.Get("history_days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 || days > 365 {
+			h.writeError(w, r, http.StatusBadRequest, "history_days must be between 1 and 365")
+			return
+		}
+		filter.HistoryDays = days
+	}
+	if v := query.Get("api_key"); v != "" {
+		filter.APIKeyID = &v
+	}
+	if v := query.Get("team_id"); v != "" {
+		filter.TeamID = &v
+	}
+
+	report, err := auth.BuildSpendForecast(r.Context(), h.store, filter)
+	if err != nil {
+		h.logger.Error("failed to build spend forecast", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to build spend forecast")
+		return
+	}
+	if query.Get("over_budget") == "true" {
+		flagged := report.Forecasts[:0]
+		for _, f := range report.Forecasts {
+			if f.OverBudget {
+				flagged = append(flagged, f)
+			}
+		}
+		report.Forecasts = flagged
+	}
+	h.writeJSON(w, http.StatusOK, report)
+}
+
+// GetSpendByTags handles GET /spend/tags, listing tag budgets with their
+// spend in the current budget period.
+func (h *ManagementHandler) GetSpendByTags(w http.ResponseWriter, r *http.Request) {
+	if h.governance == nil {
+		h.writeJSON(w, http.StatusOK, map[string]any{"data": []governance.TagBudgetStatus{}})
+		return
+	}
+	budgets, err := h.governance.TagBudgets(r.Context())
+	if err != nil {
+		h.logger.Error("failed to get tag budgets", "error", err)
+		h.writeError(w, r, http.StatusInternalServerError, "failed to get spend by tags")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, map[string]any{"data": budgets})
+}
+
+// annotateTagBudgets sets the budget of tag rows whose tag has one.
+func (h *ManagementHandler) annotateTagBudgets(ctx context.Context, report *auth.SpendReport) {
+	if h.governance == nil {
+		return
+	}
+	hasTag := false
+	for _, d := range report.GroupBy {
+		hasTag = hasTag || d == auth.SpendGroupTag
+	}
+	if !hasTag {
+		return
+	}
+	budgets, err := h.governance.TagBudgets(ctx)
+	if err != nil {
+		h.logger.Warn("failed to get tag budgets", "error", err)
+		return
+	}
+	limits := make(map[string]float64, len(budgets))
+	for _, b := range budgets {
+		limits[b.Tag] = b.MaxBudget
+	}
+	for i := range report.Rows {
+		if limit, ok := limits[report.Rows[i].Tag]; ok {
+			report.Rows[i].MaxBudget = &limit
+		}
+	}
+}
+
 // GetSpendByKeys handles GET /spend/keys
 func (h *ManagementHandler) GetSpendByKeys(w http.ResponseWriter, r *http.Request) {
 	keys, _, err := h.store.ListAPIKeys(r.Context(), auth.APIKeyFilter{
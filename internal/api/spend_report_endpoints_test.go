@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/governance"
+)
+
+func TestGetSpendReport(t *testing.T) {
+	store := auth.NewMemoryStore()
+	team := "team-1"
+	start := time.Date(2026, 9, 3, 10, 0, 0, 0, time.UTC)
+	for _, log := range []*auth.UsageLog{
+		{APIKeyID: "key-1", TeamID: &team, Model: "gpt-4", Cost: 1.25, RequestTags: []string{"search"}, StartTime: start},
+		{APIKeyID: "key-2", TeamID: &team, Model: "gpt-4", Cost: 0.75, StartTime: start.AddDate(0, 0, 1)},
+	} {
+		require.NoError(t, store.LogUsage(context.Background(), log))
+	}
+	handler := NewManagementHandler(store, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil)
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	rr := get("/spend/report?start_date=2026-09-01&end_date=2026-09-30&group_by=key,day")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var report auth.SpendReport
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+	require.Len(t, report.Rows, 2)
+	require.Equal(t, "key-1", report.Rows[0].Key)
+	require.Equal(t, "2026-09-03", report.Rows[0].Day)
+	require.InDelta(t, 2.0, report.TotalSpend, 1e-9)
+
+	rr = get("/spend/report?start_date=2026-09-01&end_date=2026-09-30&group_by=tag&format=csv")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+	require.Contains(t, rr.Header().Get("Content-Disposition"), "spend_report_2026-09-01_2026-09-30.csv")
+	require.True(t, strings.HasPrefix(rr.Body.String(), "tag,api_requests"), rr.Body.String())
+	require.Contains(t, rr.Body.String(), "search,1,0,0,1.250000")
+
+	rr = get("/spend/report?group_by=tag,team")
+	require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+	rr = get("/spend/report?format=xml")
+	require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+}
+
+func TestGetSpendByTags(t *testing.T) {
+	store := auth.NewMemoryStore()
+	now := time.Now().UTC()
+	require.NoError(t, store.LogUsage(context.Background(), &auth.UsageLog{
+		APIKeyID: "key-1", Model: "gpt-4", Cost: 2, RequestTags: []string{"project:alpha"}, StartTime: now,
+	}))
+	handler := NewManagementHandler(store, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil)
+	handler.SetGovernance(governance.NewEngine(governance.Config{
+		Enabled:    true,
+		TagBudgets: []governance.TagBudget{{Tag: "project:alpha", MaxBudget: 10, Duration: auth.BudgetDurationMonthly}},
+	}, governance.WithStore(store)))
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/spend/tags", nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp struct {
+		Data []governance.TagBudgetStatus `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	require.InDelta(t, 2.0, resp.Data[0].Spend, 1e-9)
+	require.InDelta(t, 8.0, resp.Data[0].Remaining, 1e-9)
+
+	day := now.Format("2006-01-02")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/spend/report?group_by=tag&start_date="+day+"&end_date="+day, nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var report auth.SpendReport
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+	require.Len(t, report.Rows, 1)
+	require.NotNil(t, report.Rows[0].MaxBudget)
+	require.InDelta(t, 10.0, *report.Rows[0].MaxBudget, 1e-9)
+}
+
+func TestGetSpendForecast(t *testing.T) {
+	store := auth.NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, store.CreateAPIKey(ctx, &auth.APIKey{ID: "hot", KeyHash: "h1", MaxBudget: 1, SpentBudget: 0.9, IsActive: true}))
+	require.NoError(t, store.CreateAPIKey(ctx, &auth.APIKey{ID: "cold", KeyHash: "h2", MaxBudget: 1000, IsActive: true}))
+	for day := 1; day <= 7; day++ {
+		at := time.Now().AddDate(0, 0, -day)
+		require.NoError(t, store.LogUsage(ctx, &auth.UsageLog{APIKeyID: "hot", Model: "gpt-4", Cost: 5, StartTime: at}))
+		require.NoError(t, store.LogUsage(ctx, &auth.UsageLog{APIKeyID: "cold", Model: "gpt-4", Cost: 0.01, StartTime: at}))
+	}
+	handler := NewManagementHandler(store, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil)
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	rr := get("/spend/forecast?entity=key&history_days=7")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var report auth.SpendForecastReport
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+	require.Len(t, report.Forecasts, 2)
+	require.Equal(t, "hot", report.Forecasts[0].ID)
+	require.True(t, report.Forecasts[0].OverBudget)
+	require.InDelta(t, 5.0, report.Forecasts[0].DailyBurnRate, 1e-9)
+
+	rr = get("/spend/forecast?over_budget=true")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+	require.Len(t, report.Forecasts, 1)
+	require.Equal(t, "hot", report.Forecasts[0].ID)
+
+	rr = get("/spend/forecast?entity=user")
+	require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+	rr = get("/spend/forecast?history_days=0")
+	require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+}
@@ -0,0 +1,159 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// DefaultSSEHeartbeatInterval is how often idle SSE responses receive a
+// ": ping" comment when ClientHandlerConfig.SSEHeartbeatInterval is unset.
+const DefaultSSEHeartbeatInterval = 15 * time.Second
+
+// maxPooledSSEBuffer caps the buffers kept for reuse so one large event does
+// not pin its memory in the pool.
+const maxPooledSSEBuffer = 64 << 10
+
+// Pre-encoded frame parts, so writing an event does not allocate them.
+var (
+	ssePing       = []byte(": ping\n\n")
+	sseDone       = []byte("[DONE]")
+	sseDoneFrame  = []byte("data: [DONE]\n\n")
+	sseIDPrefix   = []byte("id: ")
+	sseDataPrefix = []byte("data: ")
+	sseEventEnd   = []byte("\n\n")
+)
+
+// sseBuffer is a pooled scratch buffer for encoding and framing events.
+type sseBuffer struct {
+	bytes.Buffer
+	enc *json.Encoder
+}
+
+var sseBufferPool = sync.Pool{
+	New: func() any {
+		b := &sseBuffer{}
+		b.enc = json.NewEncoder(&b.Buffer)
+		return b
+	},
+}
+
+func getSSEBuffer() *sseBuffer {
+	b := sseBufferPool.Get().(*sseBuffer)
+	b.Reset()
+	return b
+}
+
+// release returns b to the pool. Bytes obtained from b must not be used
+// afterwards.
+func (b *sseBuffer) release() {
+	if b.Cap() > maxPooledSSEBuffer {
+		return
+	}
+	sseBufferPool.Put(b)
+}
+
+// encodeEvent encodes v as JSON into a pooled buffer. The returned data is
+// valid until the buffer is released.
+func encodeEvent(v any) (*sseBuffer, []byte, error) {
+	b := getSSEBuffer()
+	if err := b.enc.Encode(v); err != nil {
+		b.release()
+		return nil, nil, err
+	}
+	return b, bytes.TrimSuffix(b.Bytes(), []byte{'\n'}), nil
+}
+
+// sseWriter serializes writes to a server-sent events response and writes a
+// ": ping" comment whenever nothing was written for a heartbeat interval, so
+// proxies and clients do not close streams while the upstream is slow.
+type sseWriter struct {
+	mu        sync.Mutex
+	w         http.ResponseWriter
+	flusher   http.Flusher
+	lastWrite time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newSSEWriter wraps w. A non-positive interval disables heartbeats. Callers
+// must call Close before returning from the handler.
+func newSSEWriter(w http.ResponseWriter, flusher http.Flusher, interval time.Duration) *sseWriter {
+	s := &sseWriter{w: w, flusher: flusher, lastWrite: time.Now()}
+	if interval > 0 {
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+		go s.heartbeat(interval)
+	}
+	return s
+}
+
+func (s *sseWriter) heartbeat(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			if now.Sub(s.lastWrite) >= interval {
+				if _, err := s.w.Write(ssePing); err == nil {
+					s.flusher.Flush()
+				}
+				s.lastWrite = now
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// WriteEvent writes data as one "data:" event and flushes it. A non-empty id
+// is sent as the event's "id:" field.
+func (s *sseWriter) WriteEvent(id string, data []byte) error {
+	buf := getSSEBuffer()
+	defer buf.release()
+	if id != "" {
+		buf.Write(sseIDPrefix)
+		buf.WriteString(id)
+		buf.WriteByte('\n')
+	}
+	buf.Write(sseDataPrefix)
+	buf.Write(data)
+	buf.Write(sseEventEnd)
+	return s.writeRaw(buf.Bytes())
+}
+
+// WriteDone writes the terminating [DONE] event and flushes it.
+func (s *sseWriter) WriteDone(id string) error {
+	if id == "" {
+		return s.writeRaw(sseDoneFrame)
+	}
+	return s.WriteEvent(id, sseDone)
+}
+
+func (s *sseWriter) writeRaw(p []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastWrite = time.Now()
+	if _, err := s.w.Write(p); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// Close stops heartbeats.
+func (s *sseWriter) Close() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.stop = nil
+}
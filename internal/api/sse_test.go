@@ -0,0 +1,65 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSEWriterHeartbeat(t *testing.T) {
+	rec := httptest.NewRecorder()
+	sse := newSSEWriter(rec, rec, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	if err := sse.WriteEvent("", []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("WriteEvent: %v", err)
+	}
+	if err := sse.WriteDone(""); err != nil {
+		t.Fatalf("WriteDone: %v", err)
+	}
+	sse.Close()
+
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, ": ping\n\n") {
+		t.Fatalf("expected heartbeat before the first event, got %q", body)
+	}
+	if !strings.HasSuffix(body, "data: {\"id\":1}\n\ndata: [DONE]\n\n") {
+		t.Fatalf("expected events after heartbeats, got %q", body)
+	}
+}
+
+func TestSSEWriterHeartbeatDisabled(t *testing.T) {
+	rec := httptest.NewRecorder()
+	sse := newSSEWriter(rec, rec, -1)
+	time.Sleep(20 * time.Millisecond)
+	_ = sse.WriteDone("")
+	sse.Close()
+
+	if body := rec.Body.String(); body != "data: [DONE]\n\n" {
+		t.Fatalf("expected no heartbeat, got %q", body)
+	}
+}
+
+func TestSSEWriterEncodedEvents(t *testing.T) {
+	rec := httptest.NewRecorder()
+	sse := newSSEWriter(rec, rec, -1)
+	for i := 0; i < 2; i++ {
+		buf, data, err := encodeEvent(map[string]any{"n": i, "s": "<b>"})
+		if err != nil {
+			t.Fatalf("encodeEvent: %v", err)
+		}
+		if err := sse.WriteEvent("evt-1", data); err != nil {
+			t.Fatalf("WriteEvent: %v", err)
+		}
+		buf.release()
+	}
+	_ = sse.WriteDone("evt-2")
+	sse.Close()
+
+	want := "id: evt-1\ndata: {\"n\":0,\"s\":\"\\u003cb\\u003e\"}\n\n" +
+		"id: evt-1\ndata: {\"n\":1,\"s\":\"\\u003cb\\u003e\"}\n\n" +
+		"id: evt-2\ndata: [DONE]\n\n"
+	if body := rec.Body.String(); body != want {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
@@ -0,0 +1,58 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+)
+
+func TestChatCompletionStream_Passthrough(t *testing.T) {
+	const event = `{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"content":"hi"}}],"x_vendor":true}`
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", event)
+	}))
+	defer mock.Close()
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             mock.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	serve := func(cfg *ClientHandlerConfig) string {
+		handler := NewClientHandler(client, logger, cfg)
+		body := `{"model":"gpt-4o","stream":true,"messages":[{"role":"user","content":"hi"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader([]byte(body)))
+		rec := httptest.NewRecorder()
+		handler.ChatCompletions(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+
+	// Forwarded byte-for-byte, including fields the gateway does not know.
+	require.Equal(t, "data: "+event+"\n\ndata: [DONE]\n\n", serve(nil))
+
+	decoded := serve(&ClientHandlerConfig{DisableStreamPassthrough: true})
+	require.NotContains(t, decoded, "x_vendor")
+	chunks, done := streamedChunks(t, decoded)
+	require.True(t, done)
+	require.Len(t, chunks, 1)
+	require.Equal(t, "hi", chunks[0].Choices[0].Delta.Content)
+}
@@ -0,0 +1,137 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/streaming"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+// DefaultStreamResumeTTL is how long buffered stream events stay resumable
+// after the last event when ClientHandlerConfig.StreamResumeTTL is unset.
+const DefaultStreamResumeTTL = 5 * time.Minute
+
+// streamResumePollInterval is how often a resumed stream that is still being
+// generated checks for new events.
+const streamResumePollInterval = 100 * time.Millisecond
+
+// resumableStream buffers the events of one chat completion stream. Event IDs
+// are "<stream id>:<sequence>".
+type resumableStream struct {
+	h  *ClientHandler
+	id string
+	ok bool
+}
+
+// startResumableStream begins buffering the stream of request streamID. It
+// returns nil when resumable streams are disabled or the buffer is unavailable.
+func (h *ClientHandler) startResumableStream(ctx context.Context, r *http.Request, streamID string) *resumableStream {
+	if h.streamBuffer == nil {
+		return nil
+	}
+	if err := h.streamBuffer.Start(ctx, streamID, idempotencyScope(r), h.streamResumeTTL); err != nil {
+		h.logger.Warn("stream buffer unavailable, stream is not resumable", "error", err)
+		return nil
+	}
+	return &resumableStream{h: h, id: streamID, ok: true}
+}
+
+// append buffers data and returns its event ID, or "" once buffering failed.
+func (s *resumableStream) append(ctx context.Context, data []byte) string {
+	if s == nil || !s.ok {
+		return ""
+	}
+	seq, err := s.h.streamBuffer.Append(ctx, s.id, data)
+	if err != nil {
+		// Stop here; resumed clients receive the events buffered so far.
+		s.h.logger.Warn("stopped buffering stream", "stream_id", s.id, "error", err)
+		s.finish(ctx)
+		return ""
+	}
+	return formatEventID(s.id, seq)
+}
+
+// finish marks the stream complete so resumed clients stop waiting for it.
+func (s *resumableStream) finish(ctx context.Context) {
+	if s == nil || !s.ok {
+		return
+	}
+	s.ok = false
+	if err := s.h.streamBuffer.Finish(ctx, s.id); err != nil {
+		s.h.logger.Warn("failed to finish buffered stream", "stream_id", s.id, "error", err)
+	}
+}
+
+func formatEventID(streamID string, seq int64) string {
+	return streamID + ":" + strconv.FormatInt(seq, 10)
+}
+
+func parseEventID(id string) (string, int64, bool) {
+	i := strings.LastIndexByte(id, ':')
+	if i <= 0 {
+		return "", 0, false
+	}
+	seq, err := strconv.ParseInt(id[i+1:], 10, 64)
+	if err != nil || seq < 0 {
+		return "", 0, false
+	}
+	return id[:i], seq, true
+}
+
+// resumeStream serves a client reconnecting with Last-Event-ID: it replays
+// the buffered events after that ID and follows the stream until it ends.
+// Only the API key or user that started a stream can resume it.
+func (h *ClientHandler) resumeStream(w http.ResponseWriter, r *http.Request, lastEventID string) {
+	streamID, after, ok := parseEventID(lastEventID)
+	if !ok {
+		h.writeError(w, r, llmerrors.NewInvalidRequestError("", "", "invalid Last-Event-ID"))
+		return
+	}
+	replay, err := h.streamBuffer.Read(r.Context(), streamID, after)
+	if err != nil && !errors.Is(err, streaming.ErrStreamNotFound) {
+		h.logger.Warn("stream buffer unavailable", "error", err)
+	}
+	if err != nil || replay.Owner != idempotencyScope(r) {
+		h.writeError(w, r, llmerrors.NewNotFoundError("", "", "stream not found or expired"))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.writeError(w, r, llmerrors.NewInternalError("", "", "streaming not supported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	sse := newSSEWriter(w, flusher, h.sseHeartbeat)
+	defer sse.Close()
+
+	poll := time.NewTicker(streamResumePollInterval)
+	defer poll.Stop()
+	for {
+		for _, ev := range replay.Events {
+			if err := sse.WriteEvent(formatEventID(streamID, ev.Seq), ev.Data); err != nil {
+				return
+			}
+			after = ev.Seq
+		}
+		if replay.Done {
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-poll.C:
+		}
+		if replay, err = h.streamBuffer.Read(r.Context(), streamID, after); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/streaming"
+)
+
+// sseEventIDs returns the "id:" fields of an SSE body in order.
+func sseEventIDs(body string) []string {
+	var ids []string
+	for _, line := range strings.Split(body, "\n") {
+		if id, ok := strings.CutPrefix(line, "id: "); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func TestChatCompletionStreamResume(t *testing.T) {
+	mock := newResponsesMockServer()
+	defer mock.Close()
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             mock.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	handler := NewClientHandler(client, logger, &ClientHandlerConfig{
+		StreamBuffer: streaming.NewMemoryEventBuffer(0),
+	})
+
+	owner := &auth.AuthContext{APIKey: &auth.APIKey{ID: "key-1"}}
+	serve := func(authCtx *auth.AuthContext, lastEventID string) *httptest.ResponseRecorder {
+		body := `{"model":"gpt-4o","stream":true,"messages":[{"role":"user","content":"hi"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader([]byte(body)))
+		req = req.WithContext(auth.WithAuthContext(req.Context(), authCtx))
+		if lastEventID != "" {
+			req.Header.Set(lastEventIDHeader, lastEventID)
+		}
+		rec := httptest.NewRecorder()
+		handler.ChatCompletions(rec, req)
+		return rec
+	}
+
+	first := serve(owner, "")
+	require.Equal(t, http.StatusOK, first.Code)
+	ids := sseEventIDs(first.Body.String())
+	require.Len(t, ids, 2, first.Body.String())
+	streamID, seq, ok := parseEventID(ids[0])
+	require.True(t, ok)
+	require.Equal(t, int64(1), seq)
+	require.Equal(t, formatEventID(streamID, 2), ids[1])
+
+	// Resuming after the first event replays only the rest.
+	resumed := serve(owner, ids[0])
+	require.Equal(t, http.StatusOK, resumed.Code)
+	require.Equal(t, "id: "+ids[1]+"\ndata: [DONE]\n\n", resumed.Body.String())
+
+	// Other keys cannot resume the stream.
+	other := serve(&auth.AuthContext{APIKey: &auth.APIKey{ID: "key-2"}}, ids[0])
+	require.Equal(t, http.StatusNotFound, other.Code)
+
+	invalid := serve(owner, "not-an-id")
+	require.Equal(t, http.StatusBadRequest, invalid.Code)
+}
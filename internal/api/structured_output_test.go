@@ -0,0 +1,58 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+)
+
+func TestChatCompletionsStructuredOutputHeader(t *testing.T) {
+	mock := newResponsesMockServer()
+	defer mock.Close()
+
+	client, err := llmux.New(
+		llmux.WithProvider(llmux.ProviderConfig{
+			Name:                "openai",
+			Type:                "openai",
+			APIKey:              "test",
+			BaseURL:             mock.URL,
+			AllowPrivateBaseURL: true,
+			Models:              []string{"gpt-4o"},
+		}),
+		llmux.WithStructuredOutputValidation(llmux.StructuredOutputConfig{}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	handler := NewClientHandler(client, logger, nil)
+
+	serve := func(stream bool) *http.Response {
+		body := `{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}],` +
+			`"response_format":{"type":"json_schema","json_schema":{"name":"reply","schema":{"type":"object"}}}`
+		if stream {
+			body += `,"stream":true`
+		}
+		body += `}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader([]byte(body)))
+		rec := httptest.NewRecorder()
+		handler.ChatCompletions(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec.Result()
+	}
+
+	// The mock answers "ok" and "streamed", neither of which is a JSON object.
+	resp := serve(false)
+	require.Equal(t, llmux.StructuredOutputInvalid, resp.Header.Get(structuredOutputHeader))
+
+	resp = serve(true)
+	require.Empty(t, resp.Header.Get(structuredOutputHeader))
+	require.Equal(t, llmux.StructuredOutputInvalid, resp.Trailer.Get(structuredOutputHeader))
+}
@@ -0,0 +1,86 @@
+// Package api provides HTTP handlers for the LLM gateway API.
+// Live request tail endpoint.
+package api //nolint:revive // package name is intentional
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/observability"
+)
+
+// tailHeartbeatInterval is how often /admin/tail writes an SSE comment so
+// idle connections are not closed by proxies.
+const tailHeartbeatInterval = 15 * time.Second
+
+// SetRequestTail sets the live request feed streamed by /admin/tail.
+func (h *ManagementHandler) SetRequestTail(tail *observability.RequestTail) {
+	h.requestTail = tail
+}
+
+// TailRequests handles GET /admin/tail. It streams a redacted summary of every
+// completed request as server-sent events until the client disconnects.
+// Optional model, team and status query parameters filter the feed.
+func (h *ManagementHandler) TailRequests(w http.ResponseWriter, r *http.Request) {
+	if h.requestTail == nil {
+		h.writeError(w, r, http.StatusNotImplemented, "request tail is not enabled")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.writeError(w, r, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	query := r.URL.Query()
+	model := query.Get("model")
+	team := query.Get("team")
+	status := observability.RequestStatus(query.Get("status"))
+
+	// The tail outlives the admin server's write timeout.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	events, unsubscribe := h.requestTail.Subscribe(observability.DefaultTailBuffer)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, ": tailing requests\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(tailHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if (model != "" && ev.Model != model) || (team != "" && ev.Team != team) || (status != "" && ev.Status != status) {
+				continue
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: request\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
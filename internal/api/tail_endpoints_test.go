@@ -0,0 +1,89 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/observability"
+)
+
+func TestTailRequests(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tail := observability.NewRequestTail()
+	mgmt := NewManagementHandler(auth.NewMemoryStore(), nil, logger, nil, nil, nil)
+	mgmt.SetRequestTail(tail)
+	mux := http.NewServeMux()
+	mgmt.RegisterRoutes(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/admin/tail?model=gpt-4o", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /admin/tail: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	reader := bufio.NewReader(resp.Body)
+	if line, _ := reader.ReadString('\n'); !strings.HasPrefix(line, ":") {
+		t.Fatalf("expected opening comment, got %q", line)
+	}
+
+	team, secret := "team-a", "top secret prompt"
+	start := time.Now()
+	_ = tail.LogSuccessEvent(ctx, &observability.StandardLoggingPayload{Model: "gpt-4o-mini", Status: observability.RequestStatusSuccess})
+	_ = tail.LogSuccessEvent(ctx, &observability.StandardLoggingPayload{
+		RequestID:    "req-1",
+		Model:        "gpt-4o",
+		Status:       observability.RequestStatusSuccess,
+		Team:         &team,
+		ResponseCost: 0.01,
+		StartTime:    start,
+		EndTime:      start.Add(250 * time.Millisecond),
+		Messages:     []map[string]string{{"role": "user", "content": secret}},
+	})
+
+	var data string
+	for data == "" {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		if strings.HasPrefix(line, "data: ") {
+			data = strings.TrimSpace(strings.TrimPrefix(line, "data: "))
+		}
+	}
+	if strings.Contains(data, secret) {
+		t.Fatalf("tail event leaked request content: %s", data)
+	}
+	var ev observability.TailEvent
+	if err := json.Unmarshal([]byte(data), &ev); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if ev.RequestID != "req-1" || ev.Team != team || ev.LatencyMs != 250 || ev.Cost != 0.01 {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	cancel()
+	deadline := time.Now().Add(2 * time.Second)
+	for tail.Subscribers() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := tail.Subscribers(); n != 0 {
+		t.Fatalf("expected subscriber to be released, got %d", n)
+	}
+}
@@ -31,6 +31,7 @@ type NewTeamRequest struct {
 	ModelMaxBudget  map[string]float64 `json:"model_max_budget,omitempty"`
 	ModelTPMLimit   map[string]int64   `json:"model_tpm_limit,omitempty"`
 	ModelRPMLimit   map[string]int64   `json:"model_rpm_limit,omitempty"`
+	MaxSeats        *int               `json:"max_seats,omitempty"`
 	Metadata        auth.Metadata      `json:"metadata,omitempty"`
 	Blocked         bool               `json:"blocked,omitempty"`
 }
@@ -48,6 +49,10 @@ func (h *ManagementHandler) NewTeam(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
+	if req.MaxSeats != nil && *req.MaxSeats < 0 {
+		h.writeError(w, r, http.StatusBadRequest, "max_seats must be non-negative")
+		return
+	}
 
 	now := time.Now()
 	teamID := req.TeamID
@@ -66,6 +71,7 @@ func (h *ManagementHandler) NewTeam(w http.ResponseWriter, r *http.Request) {
 		ModelMaxBudget:      req.ModelMaxBudget,
 		ModelTPMLimit:       req.ModelTPMLimit,
 		ModelRPMLimit:       req.ModelRPMLimit,
+		MaxSeats:            req.MaxSeats,
 		Metadata:            req.Metadata,
 		IsActive:            true,
 		Blocked:             req.Blocked,
@@ -120,6 +126,7 @@ type UpdateTeamRequest struct {
 	RPMLimit        *int64             `json:"rpm_limit,omitempty"`
 	MaxParallelReqs *int               `json:"max_parallel_requests,omitempty"`
 	ModelMaxBudget  map[string]float64 `json:"model_max_budget,omitempty"`
+	MaxSeats        *int               `json:"max_seats,omitempty"`
 	Metadata        auth.Metadata      `json:"metadata,omitempty"`
 	Blocked         *bool              `json:"blocked,omitempty"`
 }
@@ -136,6 +143,10 @@ func (h *ManagementHandler) UpdateTeam(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, r, http.StatusBadRequest, "team_id is required")
 		return
 	}
+	if req.MaxSeats != nil && *req.MaxSeats < 0 {
+		h.writeError(w, r, http.StatusBadRequest, "max_seats must be non-negative")
+		return
+	}
 
 	team, err := h.store.GetTeam(r.Context(), req.TeamID)
 	if err != nil || team == nil {
@@ -169,6 +180,9 @@ func (h *ManagementHandler) UpdateTeam(w http.ResponseWriter, r *http.Request) {
 	if req.ModelMaxBudget != nil {
 		team.ModelMaxBudget = req.ModelMaxBudget
 	}
+	if req.MaxSeats != nil {
+		team.MaxSeats = req.MaxSeats
+	}
 	if req.Metadata != nil {
 		team.Metadata = mergeMetadata(team.Metadata, req.Metadata)
 	}
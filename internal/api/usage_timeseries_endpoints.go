@@ -0,0 +1,79 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/metrics"
+)
+
+// GetUsageTimeSeries handles GET /global/usage/timeseries.
+// Query params: interval (minute|hour), start/end (RFC3339), group_by
+// (model|team|provider), and model/team_id/provider filters.
+func (h *ManagementHandler) GetUsageTimeSeries(w http.ResponseWriter, r *http.Request) {
+	if h.timeSeries == nil {
+		h.writeError(w, r, http.StatusServiceUnavailable, "usage time series not enabled")
+		return
+	}
+
+	query := r.URL.Query()
+	q := metrics.TimeSeriesQuery{
+		GroupBy:  query.Get("group_by"),
+		Model:    query.Get("model"),
+		Team:     query.Get("team_id"),
+		Provider: query.Get("provider"),
+	}
+
+	interval := query.Get("interval")
+	var defaultWindow time.Duration
+	switch interval {
+	case "", "minute":
+		interval = "minute"
+		q.Resolution = metrics.ResolutionMinute
+		defaultWindow = time.Hour
+	case "hour":
+		q.Resolution = metrics.ResolutionHour
+		defaultWindow = 24 * time.Hour
+	default:
+		h.writeError(w, r, http.StatusBadRequest, "interval must be minute or hour")
+		return
+	}
+
+	q.End = time.Now()
+	if endStr := query.Get("end"); endStr != "" {
+		end, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			h.writeError(w, r, http.StatusBadRequest, "invalid end format, expected RFC3339")
+			return
+		}
+		q.End = end
+	}
+	q.Start = q.End.Add(-defaultWindow)
+	if startStr := query.Get("start"); startStr != "" {
+		start, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			h.writeError(w, r, http.StatusBadRequest, "invalid start format, expected RFC3339")
+			return
+		}
+		q.Start = start
+	}
+
+	groups, err := h.timeSeries.Query(q)
+	if err != nil {
+		if errors.Is(err, metrics.ErrTooManyPoints) {
+			h.writeError(w, r, http.StatusBadRequest, "time range too large for interval")
+			return
+		}
+		h.writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]any{
+		"interval": interval,
+		"start":    q.Start.Truncate(q.Resolution).UTC(),
+		"end":      q.End.Truncate(q.Resolution).UTC(),
+		"group_by": q.GroupBy,
+		"data":     groups,
+	})
+}
@@ -0,0 +1,103 @@
+package api //nolint:revive // package name is intentional
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/metrics"
+	"github.com/blueberrycongee/llmux/internal/observability"
+)
+
+func TestGetUsageTimeSeries(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ts := metrics.NewTimeSeries(metrics.DefaultTimeSeriesConfig())
+
+	// Requests are recorded from observePost even without an observability manager.
+	client := NewClientHandlerWithSwapper(nil, logger, &ClientHandlerConfig{TimeSeries: ts})
+	team := "team-a"
+	client.observePost(context.Background(), &observability.StandardLoggingPayload{
+		Model:        "gpt-4",
+		APIProvider:  "openai",
+		Team:         &team,
+		PromptTokens: 12,
+		ResponseCost: 0.5,
+		StartTime:    time.Now().Add(-80 * time.Millisecond),
+	}, nil)
+	client.observePost(context.Background(), &observability.StandardLoggingPayload{
+		Model:       "gpt-4",
+		APIProvider: "openai",
+		StartTime:   time.Now(),
+	}, errors.New("upstream failed"))
+
+	mgmt := NewManagementHandler(auth.NewMemoryStore(), nil, logger, nil, nil, nil)
+	mgmt.SetUsageTimeSeries(ts)
+
+	rec := httptest.NewRecorder()
+	mgmt.GetUsageTimeSeries(rec, httptest.NewRequest(http.MethodGet, "/global/usage/timeseries?group_by=team", http.NoBody))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Interval string                    `json:"interval"`
+		Data     []metrics.TimeSeriesGroup `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Interval != "minute" || len(resp.Data) != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.Data[0].Key != "" || resp.Data[1].Key != team {
+		t.Fatalf("unexpected group keys: %q, %q", resp.Data[0].Key, resp.Data[1].Key)
+	}
+	if len(resp.Data[1].Points) != 61 {
+		t.Fatalf("len(points) = %d, want 61", len(resp.Data[1].Points))
+	}
+	ok := recentPoint(resp.Data[1].Points)
+	if ok.Requests != 1 || ok.PromptTokens != 12 || ok.Cost != 0.5 || ok.LatencyP50Ms == 0 {
+		t.Fatalf("unexpected team point: %+v", ok)
+	}
+	if failed := recentPoint(resp.Data[0].Points); failed.Errors != 1 || failed.ErrorRate != 1 {
+		t.Fatalf("unexpected error point: %+v", failed)
+	}
+}
+
+// recentPoint returns the newest non-empty point.
+func recentPoint(points []metrics.TimeSeriesPoint) metrics.TimeSeriesPoint {
+	for i := len(points) - 1; i >= 0; i-- {
+		if points[i].Requests > 0 {
+			return points[i]
+		}
+	}
+	return metrics.TimeSeriesPoint{}
+}
+
+func TestGetUsageTimeSeries_BadRequest(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mgmt := NewManagementHandler(auth.NewMemoryStore(), nil, logger, nil, nil, nil)
+
+	rec := httptest.NewRecorder()
+	mgmt.GetUsageTimeSeries(rec, httptest.NewRequest(http.MethodGet, "/global/usage/timeseries", http.NoBody))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status without time series = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	mgmt.SetUsageTimeSeries(metrics.NewTimeSeries(metrics.DefaultTimeSeriesConfig()))
+	for _, query := range []string{"interval=day", "start=yesterday", "group_by=user", "start=2020-01-01T00:00:00Z"} {
+		rec := httptest.NewRecorder()
+		mgmt.GetUsageTimeSeries(rec, httptest.NewRequest(http.MethodGet, "/global/usage/timeseries?"+query, http.NoBody))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", query, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
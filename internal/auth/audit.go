@@ -28,6 +28,8 @@ const (
 	AuditActionAPIKeyRevoke  AuditAction = "api_key_revoke"  // #nosec G101 -- audit action name, not a credential.
 	AuditActionAPIKeyBlock   AuditAction = "api_key_block"   // #nosec G101 -- audit action name, not a credential.
 	AuditActionAPIKeyUnblock AuditAction = "api_key_unblock" // #nosec G101 -- audit action name, not a credential.
+	// AuditActionAPIKeyIPRejected records a request from a source IP outside the key's allowed_cidrs.
+	AuditActionAPIKeyIPRejected AuditAction = "api_key_ip_rejected" // #nosec G101 -- audit action name, not a credential.
 
 	// Team actions
 	AuditActionTeamCreate       AuditAction = "team_create"
@@ -55,9 +57,18 @@ const (
 	AuditActionBudgetReset    AuditAction = "budget_reset"
 	AuditActionBudgetUpdate   AuditAction = "budget_update"
 
+	// Content policy actions
+	AuditActionContentPolicyViolation AuditAction = "content_policy_violation"
+
 	// Configuration actions
 	AuditActionConfigUpdate AuditAction = "config_update"
 	AuditActionSSOUpdate    AuditAction = "sso_update"
+
+	// Invitation actions
+	AuditActionInvitationCreate AuditAction = "invitation_create"
+	AuditActionInvitationAccept AuditAction = "invitation_accept"
+	AuditActionInvitationResend AuditAction = "invitation_resend"
+	AuditActionInvitationRevoke AuditAction = "invitation_revoke"
 )
 
 // AuditObjectType represents the type of object being audited.
@@ -74,6 +85,9 @@ const (
 	AuditObjectSSO          AuditObjectType = "sso"
 	AuditObjectModel        AuditObjectType = "model"
 	AuditObjectMembership   AuditObjectType = "membership"
+	AuditObjectInvitation   AuditObjectType = "invitation"
+	AuditObjectTag          AuditObjectType = "tag"
+	AuditObjectPrompt       AuditObjectType = "prompt"
 )
 
 // AuditLog represents an audit log entry for compliance and security tracking.
@@ -164,8 +178,9 @@ type AuditLogStore interface {
 
 // AuditLogger provides a high-level API for recording audit events.
 type AuditLogger struct {
-	store   AuditLogStore
-	enabled bool
+	store     AuditLogStore
+	enabled   bool
+	exporters []*AuditExporter
 }
 
 // NewAuditLogger creates a new audit logger.
@@ -176,9 +191,21 @@ func NewAuditLogger(store AuditLogStore, enabled bool) *AuditLogger {
 	}
 }
 
+// AddExporter streams every recorded event to exp in addition to the store.
+// It must be called before the logger is shared between goroutines.
+func (al *AuditLogger) AddExporter(exp *AuditExporter) {
+	al.exporters = append(al.exporters, exp)
+}
+
 // Log records an audit event.
 func (al *AuditLogger) Log(log *AuditLog) error {
-	if !al.enabled || al.store == nil {
+	if !al.enabled {
+		return nil
+	}
+	for _, exp := range al.exporters {
+		exp.Enqueue(log)
+	}
+	if al.store == nil {
 		return nil
 	}
 	return al.store.CreateAuditLog(log)
@@ -0,0 +1,421 @@
+package auth
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/goccy/go-json"
+)
+
+// AuditSink delivers batches of audit events to an external system.
+type AuditSink interface {
+	Name() string
+	Export(ctx context.Context, logs []*AuditLog) error
+}
+
+// AuditExporterConfig controls how events are batched for a sink.
+type AuditExporterConfig struct {
+	FlushInterval time.Duration // Default 1m
+	BatchSize     int           // Default 500; a full batch flushes early
+	MaxQueue      int           // Default 10000; the oldest events are dropped beyond this
+}
+
+// AuditExporter buffers audit events and delivers them to a sink in batches.
+// Failed batches are retried on the next flush.
+type AuditExporter struct {
+	sink   AuditSink
+	cfg    AuditExporterConfig
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	queue   []*AuditLog
+	dropped int64
+
+	flushMu sync.Mutex
+	kick    chan struct{}
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewAuditExporter creates an exporter for sink and starts its flush loop.
+func NewAuditExporter(sink AuditSink, cfg AuditExporterConfig, logger *slog.Logger) *AuditExporter {
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.MaxQueue <= 0 {
+		cfg.MaxQueue = 10000
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	e := &AuditExporter{
+		sink:   sink,
+		cfg:    cfg,
+		logger: logger,
+		kick:   make(chan struct{}, 1),
+		stopCh: make(chan struct{}),
+	}
+	e.wg.Add(1)
+	go e.loop()
+	return e
+}
+
+// Enqueue buffers log for export.
+func (e *AuditExporter) Enqueue(log *AuditLog) {
+	e.mu.Lock()
+	e.queue = append(e.queue, log)
+	e.trimLocked()
+	full := len(e.queue) >= e.cfg.BatchSize
+	e.mu.Unlock()
+
+	if full {
+		select {
+		case e.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// trimLocked drops the oldest events beyond MaxQueue. Callers must hold e.mu.
+func (e *AuditExporter) trimLocked() {
+	if over := len(e.queue) - e.cfg.MaxQueue; over > 0 {
+		e.queue = e.queue[over:]
+		e.dropped += int64(over)
+		e.logger.Warn("audit export queue full, dropping oldest events",
+			"sink", e.sink.Name(), "dropped", over, "total_dropped", e.dropped)
+	}
+}
+
+// Flush delivers all buffered events.
+func (e *AuditExporter) Flush(ctx context.Context) error {
+	e.flushMu.Lock()
+	defer e.flushMu.Unlock()
+
+	e.mu.Lock()
+	pending := e.queue
+	e.queue = nil
+	e.mu.Unlock()
+
+	for len(pending) > 0 {
+		n := min(len(pending), e.cfg.BatchSize)
+		if err := e.sink.Export(ctx, pending[:n]); err != nil {
+			e.mu.Lock()
+			e.queue = append(pending, e.queue...)
+			e.trimLocked()
+			e.mu.Unlock()
+			return fmt.Errorf("audit export to %s: %w", e.sink.Name(), err)
+		}
+		pending = pending[n:]
+	}
+	return nil
+}
+
+// Shutdown stops the flush loop and delivers any buffered events.
+func (e *AuditExporter) Shutdown(ctx context.Context) error {
+	close(e.stopCh)
+	e.wg.Wait()
+	return e.Flush(ctx)
+}
+
+func (e *AuditExporter) loop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.kick:
+		case <-e.stopCh:
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := e.Flush(ctx); err != nil {
+			e.logger.Warn("audit export failed, will retry", "error", err)
+		}
+		cancel()
+	}
+}
+
+// ObjectStoreAuditSinkConfig configures JSONL export to S3 or GCS.
+// GCS is reached through its S3-compatible XML API with HMAC credentials.
+type ObjectStoreAuditSinkConfig struct {
+	Provider        string // "s3" (default) or "gcs"
+	Bucket          string
+	Region          string
+	Endpoint        string // Custom endpoint (e.g. MinIO); defaults to storage.googleapis.com for gcs
+	AccessKeyID     string // Uses the default credential chain when empty
+	SecretAccessKey string
+	PathPrefix      string
+	Compress        bool // gzip objects
+}
+
+// ObjectStoreAuditSink writes each batch as a JSONL object.
+type ObjectStoreAuditSink struct {
+	cfg    ObjectStoreAuditSinkConfig
+	client *s3.Client
+}
+
+// NewObjectStoreAuditSink creates an object store sink.
+func NewObjectStoreAuditSink(ctx context.Context, cfg ObjectStoreAuditSinkConfig) (*ObjectStoreAuditSink, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("audit export: bucket is required")
+	}
+	client, err := newObjectStoreClient(ctx, cfg.Provider, cfg.Region, cfg.Endpoint, cfg.AccessKeyID, cfg.SecretAccessKey)
+	if err != nil {
+		return nil, fmt.Errorf("audit export: %w", err)
+	}
+	return &ObjectStoreAuditSink{cfg: cfg, client: client}, nil
+}
+
+// newObjectStoreClient creates an S3 client, or a client for the GCS
+// S3-compatible API when provider is "gcs".
+func newObjectStoreClient(ctx context.Context, provider, region, endpoint, accessKeyID, secretAccessKey string) (*s3.Client, error) {
+	if provider == "gcs" {
+		if endpoint == "" {
+			endpoint = "https://storage.googleapis.com"
+		}
+		if region == "" {
+			region = "auto"
+		}
+	}
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	if accessKeyID != "" && secretAccessKey != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, ""),
+		))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("load AWS config: %w", err)
+	}
+
+	var s3Opts []func(*s3.Options)
+	if endpoint != "" {
+		s3Opts = append(s3Opts, func(o *s3.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		})
+	}
+	return s3.NewFromConfig(awsCfg, s3Opts...), nil
+}
+
+// Name returns the sink name.
+func (s *ObjectStoreAuditSink) Name() string {
+	if s.cfg.Provider == "gcs" {
+		return "gcs"
+	}
+	return "s3"
+}
+
+// Export uploads logs as one JSONL object.
+func (s *ObjectStoreAuditSink) Export(ctx context.Context, logs []*AuditLog) error {
+	body, err := encodeAuditJSONL(logs)
+	if err != nil {
+		return err
+	}
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(s.cfg.Bucket),
+		Key:         aws.String(auditObjectKey(s.cfg.PathPrefix, time.Now().UTC(), s.cfg.Compress)),
+		ContentType: aws.String("application/x-ndjson"),
+	}
+	if s.cfg.Compress {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(body); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+		input.ContentEncoding = aws.String("gzip")
+	}
+	input.Body = bytes.NewReader(body)
+
+	if _, err := s.client.PutObject(ctx, input); err != nil {
+		return fmt.Errorf("put object: %w", err)
+	}
+	return nil
+}
+
+// auditObjectKey partitions objects by date and hour.
+func auditObjectKey(prefix string, t time.Time, compressed bool) string {
+	datePrefix := fmt.Sprintf("year=%d/month=%02d/day=%02d/hour=%02d",
+		t.Year(), t.Month(), t.Day(), t.Hour())
+	filename := fmt.Sprintf("audit_%d.jsonl", t.UnixNano())
+	if compressed {
+		filename += ".gz"
+	}
+	return path.Join(prefix, datePrefix, filename)
+}
+
+func encodeAuditJSONL(logs []*AuditLog) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, log := range logs {
+		if err := enc.Encode(log); err != nil {
+			return nil, fmt.Errorf("encode audit log %s: %w", log.ID, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// WebhookAuditSinkConfig configures delivery to an HTTP endpoint.
+type WebhookAuditSinkConfig struct {
+	URL     string
+	Headers map[string]string // e.g. Authorization for a SIEM collector
+	Timeout time.Duration     // Default 10s
+}
+
+// WebhookAuditSink POSTs each batch as JSONL.
+type WebhookAuditSink struct {
+	cfg    WebhookAuditSinkConfig
+	client *http.Client
+}
+
+// NewWebhookAuditSink creates a webhook sink.
+func NewWebhookAuditSink(cfg WebhookAuditSinkConfig) (*WebhookAuditSink, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("audit export: webhook url is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &WebhookAuditSink{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+// Name returns the sink name.
+func (s *WebhookAuditSink) Name() string { return "webhook" }
+
+// Export POSTs logs to the webhook.
+func (s *WebhookAuditSink) Export(ctx context.Context, logs []*AuditLog) error {
+	body, err := encodeAuditJSONL(logs)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SyslogAuditSinkConfig configures RFC 5424 syslog delivery.
+type SyslogAuditSinkConfig struct {
+	Network string // "udp" (default) or "tcp"
+	Address string // host:port
+	AppName string // Default "llmux"
+	Timeout time.Duration
+}
+
+// SyslogAuditSink sends one RFC 5424 message per event with a JSON body,
+// using facility log_audit (13).
+type SyslogAuditSink struct {
+	cfg      SyslogAuditSinkConfig
+	hostname string
+}
+
+// NewSyslogAuditSink creates a syslog sink.
+func NewSyslogAuditSink(cfg SyslogAuditSinkConfig) (*SyslogAuditSink, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("audit export: syslog address is required")
+	}
+	switch cfg.Network {
+	case "":
+		cfg.Network = "udp"
+	case "udp", "tcp":
+	default:
+		return nil, fmt.Errorf("audit export: unsupported syslog network %q", cfg.Network)
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = "llmux"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogAuditSink{cfg: cfg, hostname: hostname}, nil
+}
+
+// Name returns the sink name.
+func (s *SyslogAuditSink) Name() string { return "syslog" }
+
+// Export writes logs to the syslog collector.
+func (s *SyslogAuditSink) Export(ctx context.Context, logs []*AuditLog) error {
+	dialer := net.Dialer{Timeout: s.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, s.cfg.Network, s.cfg.Address)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetWriteDeadline(time.Now().Add(s.cfg.Timeout))
+
+	for _, log := range logs {
+		msg, err := s.format(log)
+		if err != nil {
+			return err
+		}
+		if _, err := conn.Write(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const syslogFacilityAudit = 13
+
+func (s *SyslogAuditSink) format(log *AuditLog) ([]byte, error) {
+	body, err := json.Marshal(log)
+	if err != nil {
+		return nil, fmt.Errorf("encode audit log %s: %w", log.ID, err)
+	}
+	severity := 6 // informational
+	if !log.Success {
+		severity = 4 // warning
+	}
+	msg := fmt.Sprintf("<%d>1 %s %s %s - %s - %s",
+		syslogFacilityAudit*8+severity,
+		log.Timestamp.UTC().Format(time.RFC3339Nano),
+		s.hostname, s.cfg.AppName, string(log.Action), body)
+	if s.cfg.Network == "tcp" {
+		msg += "\n" // Non-transparent framing
+	}
+	return []byte(msg), nil
+}
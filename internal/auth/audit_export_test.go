@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	batches [][]*AuditLog
+	fail    bool
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Export(_ context.Context, logs []*AuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return errors.New("sink unavailable")
+	}
+	s.batches = append(s.batches, append([]*AuditLog(nil), logs...))
+	return nil
+}
+
+func testAuditLog(id string) *AuditLog {
+	return &AuditLog{
+		ID:         id,
+		Timestamp:  time.Now().UTC(),
+		ActorID:    "user-1",
+		ActorType:  "user",
+		Action:     AuditActionAPIKeyCreate,
+		ObjectType: AuditObjectAPIKey,
+		ObjectID:   "key-1",
+		Success:    true,
+	}
+}
+
+func TestAuditExporter_BatchesAndRetries(t *testing.T) {
+	sink := &recordingSink{fail: true}
+	exporter := NewAuditExporter(sink, AuditExporterConfig{FlushInterval: time.Hour, BatchSize: 2}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	logger := NewAuditLogger(NewMemoryAuditLogStore(), true)
+	logger.AddExporter(exporter)
+
+	for _, id := range []string{"a", "b", "c"} {
+		if err := logger.Log(testAuditLog(id)); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+
+	if err := exporter.Flush(context.Background()); err == nil {
+		t.Fatal("expected flush error from failing sink")
+	}
+
+	sink.mu.Lock()
+	sink.fail = false
+	sink.mu.Unlock()
+
+	if err := exporter.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	var ids []string
+	for _, batch := range sink.batches {
+		if len(batch) > 2 {
+			t.Errorf("batch of %d exceeds batch size", len(batch))
+		}
+		for _, log := range batch {
+			ids = append(ids, log.ID)
+		}
+	}
+	if strings.Join(ids, ",") != "a,b,c" {
+		t.Errorf("exported ids = %v, want a,b,c", ids)
+	}
+}
+
+func TestAuditExporter_DropsOldestBeyondMaxQueue(t *testing.T) {
+	sink := &recordingSink{}
+	exporter := NewAuditExporter(sink, AuditExporterConfig{FlushInterval: time.Hour, BatchSize: 10, MaxQueue: 2}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	for _, id := range []string{"a", "b", "c"} {
+		exporter.Enqueue(testAuditLog(id))
+	}
+	if err := exporter.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if len(sink.batches) != 1 || len(sink.batches[0]) != 2 || sink.batches[0][0].ID != "b" {
+		t.Fatalf("unexpected batches: %+v", sink.batches)
+	}
+}
+
+func TestWebhookAuditSink_PostsJSONL(t *testing.T) {
+	var gotAuth string
+	var got []AuditLog
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var log AuditLog
+			if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
+				t.Errorf("invalid JSONL line: %v", err)
+			}
+			got = append(got, log)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	sink, err := NewWebhookAuditSink(WebhookAuditSinkConfig{URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer siem"}})
+	if err != nil {
+		t.Fatalf("NewWebhookAuditSink() error = %v", err)
+	}
+	if err := sink.Export(context.Background(), []*AuditLog{testAuditLog("a"), testAuditLog("b")}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if gotAuth != "Bearer siem" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if len(got) != 2 || got[1].ID != "b" {
+		t.Errorf("received %+v", got)
+	}
+}
+
+func TestWebhookAuditSink_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	sink, _ := NewWebhookAuditSink(WebhookAuditSinkConfig{URL: srv.URL})
+	if err := sink.Export(context.Background(), []*AuditLog{testAuditLog("a")}); err == nil {
+		t.Fatal("expected error for 503 response")
+	}
+}
+
+func TestSyslogAuditSink_SendsRFC5424(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	defer conn.Close()
+
+	sink, err := NewSyslogAuditSink(SyslogAuditSinkConfig{Address: conn.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("NewSyslogAuditSink() error = %v", err)
+	}
+	log := testAuditLog("a")
+	log.Success = false
+	if err := sink.Export(context.Background(), []*AuditLog{log}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	msg := string(buf[:n])
+	// facility 13 (log_audit) * 8 + severity 4 (warning)
+	if !strings.HasPrefix(msg, "<108>1 ") {
+		t.Errorf("unexpected header: %q", msg)
+	}
+	if !strings.Contains(msg, " llmux - "+string(AuditActionAPIKeyCreate)+" - {") {
+		t.Errorf("missing app name or msgid: %q", msg)
+	}
+}
+
+func TestAuditObjectKey(t *testing.T) {
+	ts := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	key := auditObjectKey("audit", ts, true)
+	if !strings.HasPrefix(key, "audit/year=2026/month=03/day=04/hour=05/audit_") || !strings.HasSuffix(key, ".jsonl.gz") {
+		t.Errorf("unexpected key %q", key)
+	}
+}
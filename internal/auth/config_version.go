@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// ConfigVersion is one stored version of the shared gateway config document
+// used with config.source db.
+type ConfigVersion struct {
+	Version   int       `json:"version"`
+	Document  string    `json:"document"`
+	Checksum  string    `json:"checksum"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ConfigVersionStore persists the append-only history of config versions.
+type ConfigVersionStore interface {
+	// LatestConfigVersion returns the newest version, or nil when none exists.
+	LatestConfigVersion(ctx context.Context) (*ConfigVersion, error)
+	// GetConfigVersion returns the given version, or nil when it does not exist.
+	GetConfigVersion(ctx context.Context, version int) (*ConfigVersion, error)
+	// ListConfigVersions returns up to limit versions, newest first.
+	ListConfigVersions(ctx context.Context, limit int) ([]*ConfigVersion, error)
+	// CreateConfigVersion stores v, assigning its Version and CreatedAt.
+	CreateConfigVersion(ctx context.Context, v *ConfigVersion) error
+}
+
+// ConfigVersions returns the config version store backing store, if any.
+func ConfigVersions(store Store) (ConfigVersionStore, bool) {
+	if store == nil {
+		return nil, false
+	}
+	versions, ok := UnwrapStore(store).(ConfigVersionStore)
+	return versions, ok
+}
+
+// LatestConfigVersion implements ConfigVersionStore.
+func (s *MemoryStore) LatestConfigVersion(_ context.Context) (*ConfigVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.configVersions) == 0 {
+		return nil, nil
+	}
+	v := *s.configVersions[len(s.configVersions)-1]
+	return &v, nil
+}
+
+// GetConfigVersion implements ConfigVersionStore.
+func (s *MemoryStore) GetConfigVersion(_ context.Context, version int) (*ConfigVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if version < 1 || version > len(s.configVersions) {
+		return nil, nil
+	}
+	v := *s.configVersions[version-1]
+	return &v, nil
+}
+
+// ListConfigVersions implements ConfigVersionStore.
+func (s *MemoryStore) ListConfigVersions(_ context.Context, limit int) ([]*ConfigVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	versions := make([]*ConfigVersion, 0, len(s.configVersions))
+	for _, v := range s.configVersions {
+		clone := *v
+		versions = append(versions, &clone)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version > versions[j].Version })
+	if limit > 0 && len(versions) > limit {
+		versions = versions[:limit]
+	}
+	return versions, nil
+}
+
+// CreateConfigVersion implements ConfigVersionStore.
+func (s *MemoryStore) CreateConfigVersion(_ context.Context, v *ConfigVersion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v.Version = len(s.configVersions) + 1
+	v.CreatedAt = time.Now()
+	clone := *v
+	s.configVersions = append(s.configVersions, &clone)
+	return nil
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresConfigVersionStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	store := &PostgresStore{db: db}
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`INSERT INTO config_versions .* RETURNING version, created_at`).
+		WithArgs("routing: {}\n", "abc", "initial", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "created_at"}).AddRow(1, now))
+	v := &ConfigVersion{Document: "routing: {}\n", Checksum: "abc", Comment: "initial"}
+	require.NoError(t, store.CreateConfigVersion(ctx, v))
+	require.Equal(t, 1, v.Version)
+	require.Equal(t, now, v.CreatedAt)
+
+	columns := []string{"version", "document", "checksum", "comment", "created_by", "created_at"}
+	mock.ExpectQuery(`SELECT .* FROM config_versions\s+ORDER BY version DESC\s+LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "routing: {}\n", "def", nil, "admin", now))
+	latest, err := store.LatestConfigVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, latest.Version)
+	require.Equal(t, "admin", latest.CreatedBy)
+
+	mock.ExpectQuery(`SELECT .* FROM config_versions\s+WHERE version = \$1`).WithArgs(9).
+		WillReturnRows(sqlmock.NewRows(columns))
+	missing, err := store.GetConfigVersion(ctx, 9)
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	mock.ExpectQuery(`SELECT .* FROM config_versions\s+ORDER BY version DESC\s+LIMIT \$1`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(2, "b", "def", nil, nil, now).
+			AddRow(1, "a", "abc", "initial", nil, now))
+	versions, err := store.ListConfigVersions(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, "initial", versions[1].Comment)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryConfigVersionStore(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	latest, err := store.LatestConfigVersion(ctx)
+	require.NoError(t, err)
+	require.Nil(t, latest)
+
+	for _, doc := range []string{"a", "b", "c"} {
+		require.NoError(t, store.CreateConfigVersion(ctx, &ConfigVersion{Document: doc}))
+	}
+	latest, err = store.LatestConfigVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, latest.Version)
+
+	versions, err := store.ListConfigVersions(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, "c", versions[0].Document)
+
+	v, err := store.GetConfigVersion(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "a", v.Document)
+}
@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// hostTenantContextKey is the context key for the HostTenant resolved from the Host header.
+const hostTenantContextKey contextKey = "host_tenant"
+
+// HostTenant binds a data-plane host to an organization and request defaults.
+type HostTenant struct {
+	// Host is an exact host ("org1.gateway.example.com") or a wildcard
+	// ("*.gateway.example.com") matching a single leading label.
+	Host string
+	// OrganizationID is the tenant's organization. For wildcard hosts an empty
+	// value takes the organization ID from the matched subdomain label.
+	OrganizationID string
+	// AllowedModels restricts models served on this host (empty = no restriction).
+	AllowedModels []string
+	// DefaultTags are applied to requests that carry no routing tags.
+	DefaultTags []string
+}
+
+// AllowsModel reports whether the host serves model.
+func (t *HostTenant) AllowsModel(model string) bool {
+	if t == nil || len(t.AllowedModels) == 0 {
+		return true
+	}
+	for _, allowed := range t.AllowedModels {
+		if allowed == model || allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// HostTenantResolver maps request hosts to tenants.
+type HostTenantResolver struct {
+	exact     map[string]HostTenant
+	wildcards map[string]HostTenant // keyed by parent domain, e.g. "gateway.example.com"
+}
+
+// NewHostTenantResolver builds a resolver. Hosts are matched case-insensitively
+// and without port; exact hosts take precedence over wildcards.
+func NewHostTenantResolver(tenants []HostTenant) *HostTenantResolver {
+	r := &HostTenantResolver{
+		exact:     make(map[string]HostTenant),
+		wildcards: make(map[string]HostTenant),
+	}
+	for _, tenant := range tenants {
+		host := normalizeHost(tenant.Host)
+		if parent, ok := strings.CutPrefix(host, "*."); ok {
+			r.wildcards[parent] = tenant
+			continue
+		}
+		r.exact[host] = tenant
+	}
+	return r
+}
+
+// Resolve returns the tenant bound to host, if any.
+func (r *HostTenantResolver) Resolve(host string) (*HostTenant, bool) {
+	if r == nil {
+		return nil, false
+	}
+	host = normalizeHost(host)
+	if tenant, ok := r.exact[host]; ok {
+		return &tenant, true
+	}
+	label, parent, ok := strings.Cut(host, ".")
+	if !ok || label == "" {
+		return nil, false
+	}
+	tenant, ok := r.wildcards[parent]
+	if !ok {
+		return nil, false
+	}
+	if tenant.OrganizationID == "" {
+		tenant.OrganizationID = label
+	}
+	return &tenant, true
+}
+
+// HostTenantMiddleware stores the tenant resolved from the Host header in the
+// request context. It must run before authentication so keys can be checked
+// against the host's organization.
+func HostTenantMiddleware(resolver *HostTenantResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tenant, ok := resolver.Resolve(r.Host); ok {
+				r = r.WithContext(WithHostTenant(r.Context(), tenant))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithHostTenant returns a context carrying tenant.
+func WithHostTenant(ctx context.Context, tenant *HostTenant) context.Context {
+	return context.WithValue(ctx, hostTenantContextKey, tenant)
+}
+
+// HostTenantFromContext returns the tenant resolved from the Host header, if any.
+func HostTenantFromContext(ctx context.Context) *HostTenant {
+	if ctx == nil {
+		return nil
+	}
+	tenant, _ := ctx.Value(hostTenantContextKey).(*HostTenant)
+	return tenant
+}
+
+// hostTenantAllowsKey reports whether a key (or its team) may be used on the
+// tenant's host. Keys not bound to any organization are accepted.
+func hostTenantAllowsKey(tenant *HostTenant, key *APIKey, team *Team) bool {
+	if tenant == nil || tenant.OrganizationID == "" {
+		return true
+	}
+	var orgID *string
+	if key != nil && key.OrganizationID != nil {
+		orgID = key.OrganizationID
+	} else if team != nil && team.OrganizationID != nil {
+		orgID = team.OrganizationID
+	}
+	return orgID == nil || *orgID == "" || *orgID == tenant.OrganizationID
+}
+
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHostTenantResolver(t *testing.T) {
+	resolver := NewHostTenantResolver([]HostTenant{
+		{Host: "acme.gateway.example.com", OrganizationID: "org-acme", DefaultTags: []string{"eu"}},
+		{Host: "*.gateway.example.com"},
+		{Host: "*.tenants.example.com", OrganizationID: "shared"},
+	})
+
+	tests := []struct {
+		host   string
+		wantOK bool
+		wantID string
+	}{
+		{host: "acme.gateway.example.com", wantOK: true, wantID: "org-acme"},
+		{host: "ACME.Gateway.Example.com:8443", wantOK: true, wantID: "org-acme"},
+		{host: "org1.gateway.example.com", wantOK: true, wantID: "org1"},
+		{host: "x.tenants.example.com", wantOK: true, wantID: "shared"},
+		{host: "a.b.gateway.example.com", wantOK: false},
+		{host: "gateway.example.com", wantOK: false},
+		{host: "localhost:8080", wantOK: false},
+	}
+	for _, tt := range tests {
+		tenant, ok := resolver.Resolve(tt.host)
+		if ok != tt.wantOK {
+			t.Fatalf("Resolve(%q) ok = %v, want %v", tt.host, ok, tt.wantOK)
+		}
+		if ok && tenant.OrganizationID != tt.wantID {
+			t.Fatalf("Resolve(%q) org = %q, want %q", tt.host, tenant.OrganizationID, tt.wantID)
+		}
+	}
+}
+
+func TestHostTenant_AllowsModel(t *testing.T) {
+	tenant := &HostTenant{AllowedModels: []string{"gpt-4o-mini"}}
+	if !tenant.AllowsModel("gpt-4o-mini") || tenant.AllowsModel("gpt-4o") {
+		t.Fatal("unexpected allowed models evaluation")
+	}
+	if !(&HostTenant{}).AllowsModel("anything") {
+		t.Fatal("empty allowed models should allow all")
+	}
+}
+
+func TestMiddleware_Authenticate_HostTenantBinding(t *testing.T) {
+	store := NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+
+	orgA, orgB := "org-a", "org-b"
+	newKey := func(id string, orgID *string) string {
+		fullKey, hash, _ := GenerateAPIKey()
+		if err := store.CreateAPIKey(ctx, &APIKey{
+			ID:             id,
+			KeyHash:        hash,
+			KeyPrefix:      ExtractKeyPrefix(fullKey),
+			OrganizationID: orgID,
+			IsActive:       true,
+			CreatedAt:      time.Now(),
+		}); err != nil {
+			t.Fatalf("CreateAPIKey: %v", err)
+		}
+		return fullKey
+	}
+	keyA := newKey("key-a", &orgA)
+	keyB := newKey("key-b", &orgB)
+	unbound := newKey("key-unbound", nil)
+
+	middleware := NewMiddleware(&MiddlewareConfig{Store: store, Logger: logger, Enabled: true})
+	resolver := NewHostTenantResolver([]HostTenant{{Host: "*.gateway.example.com"}})
+	handler := HostTenantMiddleware(resolver)(middleware.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if HostTenantFromContext(r.Context()) == nil {
+			t.Error("expected host tenant in context")
+		}
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	tests := []struct {
+		name string
+		key  string
+		want int
+	}{
+		{name: "matching organization", key: keyA, want: http.StatusOK},
+		{name: "other organization", key: keyB, want: http.StatusForbidden},
+		{name: "key without organization", key: unbound, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+			req.Host = "org-a.gateway.example.com"
+			req.Header.Set("Authorization", "Bearer "+tt.key)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			if rr.Code != tt.want {
+				t.Fatalf("status = %d, want %d (body %s)", rr.Code, tt.want, rr.Body.String())
+			}
+		})
+	}
+}
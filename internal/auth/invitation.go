@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"log/slog"
 	"sync"
 	"time"
@@ -94,11 +95,22 @@ type InvitationLinkFilter struct {
 	Offset         int
 }
 
+// InvitationPolicy bounds how long invitation links stay valid.
+type InvitationPolicy struct {
+	DefaultExpiry time.Duration // Applied when a request sets no expiry (0 = never expires)
+	MaxExpiry     time.Duration // Longest expiry a request may set (0 = unbounded)
+}
+
+// ErrInvitationExpiryTooLong is returned when a requested expiry exceeds InvitationPolicy.MaxExpiry.
+var ErrInvitationExpiryTooLong = errors.New("invitation expiry exceeds the maximum allowed")
+
 // InvitationService handles invitation link operations.
 type InvitationService struct {
-	store     InvitationLinkStore
-	authStore Store
-	logger    *slog.Logger
+	store       InvitationLinkStore
+	authStore   Store
+	logger      *slog.Logger
+	policy      InvitationPolicy
+	auditLogger *AuditLogger
 }
 
 // NewInvitationService creates a new invitation service.
@@ -110,6 +122,33 @@ func NewInvitationService(store InvitationLinkStore, authStore Store, logger *sl
 	}
 }
 
+// SetPolicy sets the expiry policy applied to new and resent invitations.
+func (s *InvitationService) SetPolicy(policy InvitationPolicy) {
+	s.policy = policy
+}
+
+// SetAuditLogger enables audit events for invitation lifecycle changes.
+func (s *InvitationService) SetAuditLogger(auditLogger *AuditLogger) {
+	s.auditLogger = auditLogger
+}
+
+// expiry resolves a requested lifetime against the policy; 0 means the
+// invitation never expires.
+func (s *InvitationService) expiry(requested time.Duration) (time.Duration, error) {
+	if requested <= 0 {
+		requested = s.policy.DefaultExpiry
+	}
+	if s.policy.MaxExpiry > 0 {
+		if requested > s.policy.MaxExpiry {
+			return 0, ErrInvitationExpiryTooLong
+		}
+		if requested <= 0 {
+			requested = s.policy.MaxExpiry
+		}
+	}
+	return requested, nil
+}
+
 // CreateInvitationRequest contains parameters for creating an invitation link.
 type CreateInvitationRequest struct {
 	TeamID         *string  `json:"team_id,omitempty"`
@@ -124,6 +163,11 @@ type CreateInvitationRequest struct {
 
 // CreateInvitationLink creates a new invitation link.
 func (s *InvitationService) CreateInvitationLink(ctx context.Context, req *CreateInvitationRequest) (*InvitationLink, string, error) {
+	expiresIn, err := s.expiry(time.Duration(req.ExpiresIn) * time.Hour)
+	if err != nil {
+		return nil, "", err
+	}
+
 	// Generate secure token
 	token, err := generateInvitationToken()
 	if err != nil {
@@ -146,8 +190,8 @@ func (s *InvitationService) CreateInvitationLink(ctx context.Context, req *Creat
 		Description:    req.Description,
 	}
 
-	if req.ExpiresIn > 0 {
-		expiresAt := now.Add(time.Duration(req.ExpiresIn) * time.Hour)
+	if expiresIn > 0 {
+		expiresAt := now.Add(expiresIn)
 		link.ExpiresAt = &expiresAt
 	}
 
@@ -155,6 +199,7 @@ func (s *InvitationService) CreateInvitationLink(ctx context.Context, req *Creat
 		return nil, "", err
 	}
 
+	s.audit(ctx, AuditActionInvitationCreate, link, true, "")
 	return link, token, nil
 }
 
@@ -190,12 +235,27 @@ func (s *InvitationService) AcceptInvitation(ctx context.Context, req *AcceptInv
 		}, nil
 	}
 
+	ctx = withInvitationActor(ctx, req.UserID)
+
 	// Validate invitation
 	if !link.IsValid() {
-		return &AcceptInvitationResult{
-			Success: false,
-			Message: "invitation is expired or has reached maximum uses",
-		}, nil
+		return s.rejectAcceptance(ctx, link, "invitation is expired or has reached maximum uses"), nil
+	}
+
+	// Check for existing membership; new members need a free seat.
+	joinTeam := false
+	if link.TeamID != nil {
+		existing, _ := s.authStore.GetTeamMembership(ctx, req.UserID, *link.TeamID)
+		if existing == nil {
+			full, err := s.teamFull(ctx, *link.TeamID)
+			if err != nil {
+				return nil, err
+			}
+			if full {
+				return s.rejectAcceptance(ctx, link, "team has no available seats"), nil
+			}
+			joinTeam = true
+		}
 	}
 
 	result := &AcceptInvitationResult{
@@ -206,7 +266,7 @@ func (s *InvitationService) AcceptInvitation(ctx context.Context, req *AcceptInv
 	}
 
 	// Add user to team
-	if link.TeamID != nil {
+	if joinTeam {
 		now := time.Now()
 		membership := &TeamMembership{
 			UserID:   req.UserID,
@@ -214,13 +274,8 @@ func (s *InvitationService) AcceptInvitation(ctx context.Context, req *AcceptInv
 			Role:     link.Role,
 			JoinedAt: &now,
 		}
-
-		// Check for existing membership
-		existing, _ := s.authStore.GetTeamMembership(ctx, req.UserID, *link.TeamID)
-		if existing == nil {
-			if err := s.authStore.CreateTeamMembership(ctx, membership); err != nil {
-				return nil, err
-			}
+		if err := s.authStore.CreateTeamMembership(ctx, membership); err != nil {
+			return nil, err
 		}
 	}
 
@@ -249,10 +304,94 @@ func (s *InvitationService) AcceptInvitation(ctx context.Context, req *AcceptInv
 		s.logger.Error("failed to increment invitation link uses", "error", err, "invitation_id", link.ID)
 	}
 
+	s.audit(ctx, AuditActionInvitationAccept, link, true, "")
 	result.Message = "invitation accepted successfully"
 	return result, nil
 }
 
+// teamFull reports whether the team has reached its seat limit.
+func (s *InvitationService) teamFull(ctx context.Context, teamID string) (bool, error) {
+	team, err := s.authStore.GetTeam(ctx, teamID)
+	if err != nil {
+		return false, err
+	}
+	if team == nil || team.MaxSeats == nil || *team.MaxSeats <= 0 {
+		return false, nil
+	}
+	members, err := s.authStore.ListTeamMembers(ctx, teamID)
+	if err != nil {
+		return false, err
+	}
+	return len(members) >= *team.MaxSeats, nil
+}
+
+func (s *InvitationService) rejectAcceptance(ctx context.Context, link *InvitationLink, message string) *AcceptInvitationResult {
+	s.audit(ctx, AuditActionInvitationAccept, link, false, message)
+	return &AcceptInvitationResult{
+		Success: false,
+		Message: message,
+	}
+}
+
+// ResendInvitation rotates the token of an invitation, reactivates it and
+// restarts its expiry window. expiresIn of 0 reuses the link's original
+// window, or the policy default when it had none. It returns a nil link when
+// the invitation does not exist.
+func (s *InvitationService) ResendInvitation(ctx context.Context, id string, expiresIn time.Duration) (*InvitationLink, string, error) {
+	link, err := s.store.GetInvitationLink(ctx, id)
+	if err != nil || link == nil {
+		return nil, "", err
+	}
+
+	if expiresIn <= 0 && link.ExpiresAt != nil {
+		expiresIn = link.ExpiresAt.Sub(link.CreatedAt)
+	}
+	expiresIn, err = s.expiry(expiresIn)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := generateInvitationToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	link.Token = hashInvitationToken(token)
+	link.IsActive = true
+	link.ExpiresAt = nil
+	if expiresIn > 0 {
+		expiresAt := now.Add(expiresIn)
+		link.ExpiresAt = &expiresAt
+	}
+	link.UpdatedAt = now
+
+	if err := s.store.UpdateInvitationLink(ctx, link); err != nil {
+		return nil, "", err
+	}
+
+	s.audit(ctx, AuditActionInvitationResend, link, true, "")
+	return link, token, nil
+}
+
+// RevokeInvitation deactivates an invitation so its token can no longer be
+// accepted. It returns a nil link when the invitation does not exist.
+func (s *InvitationService) RevokeInvitation(ctx context.Context, id string) (*InvitationLink, error) {
+	link, err := s.store.GetInvitationLink(ctx, id)
+	if err != nil || link == nil {
+		return nil, err
+	}
+
+	link.IsActive = false
+	link.UpdatedAt = time.Now()
+	if err := s.store.UpdateInvitationLink(ctx, link); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, AuditActionInvitationRevoke, link, true, "")
+	return link, nil
+}
+
 // DeactivateInvitation deactivates an invitation link.
 func (s *InvitationService) DeactivateInvitation(ctx context.Context, id string) error {
 	link, err := s.store.GetInvitationLink(ctx, id)
@@ -274,6 +413,61 @@ func (s *InvitationService) ListInvitations(ctx context.Context, filter Invitati
 	return s.store.ListInvitationLinks(ctx, filter)
 }
 
+type invitationActorKey struct{}
+
+// withInvitationActor records the user accepting an invitation, who is not
+// necessarily the authenticated caller.
+func withInvitationActor(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, invitationActorKey{}, userID)
+}
+
+// invitationActor identifies who acted on an invitation for audit logs.
+func invitationActor(ctx context.Context) (id, actorType string) {
+	if userID, ok := ctx.Value(invitationActorKey{}).(string); ok && userID != "" {
+		return userID, "user"
+	}
+	if authCtx := GetAuthContext(ctx); authCtx != nil {
+		if authCtx.User != nil && authCtx.User.ID != "" {
+			return authCtx.User.ID, "user"
+		}
+		if authCtx.APIKey != nil && authCtx.APIKey.ID != "" {
+			return authCtx.APIKey.ID, "api_key"
+		}
+	}
+	return "system", "system"
+}
+
+func (s *InvitationService) audit(ctx context.Context, action AuditAction, link *InvitationLink, success bool, errMsg string) {
+	if s.auditLogger == nil {
+		return
+	}
+	actorID, actorType := invitationActor(ctx)
+	after := map[string]any{
+		"is_active":    link.IsActive,
+		"current_uses": link.CurrentUses,
+	}
+	if link.ExpiresAt != nil {
+		after["expires_at"] = link.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	err := s.auditLogger.Log(&AuditLog{
+		ID:             generateAuditID(),
+		Timestamp:      time.Now().UTC(),
+		ActorID:        actorID,
+		ActorType:      actorType,
+		Action:         action,
+		ObjectType:     AuditObjectInvitation,
+		ObjectID:       link.ID,
+		TeamID:         link.TeamID,
+		OrganizationID: link.OrganizationID,
+		AfterValue:     after,
+		Success:        success,
+		Error:          errMsg,
+	})
+	if err != nil {
+		s.logger.Warn("failed to audit invitation event", "error", err, "invitation_id", link.ID, "action", action)
+	}
+}
+
 // generateInvitationToken generates a secure random token.
 func generateInvitationToken() (string, error) {
 	bytes := make([]byte, 32)
@@ -360,3 +360,123 @@ func TestIncrementInvitationLinkUses(t *testing.T) {
 		t.Errorf("Expected 3 uses, got %d", updated.CurrentUses)
 	}
 }
+
+func TestInvitationService_SeatLimit(t *testing.T) {
+	invStore := NewMemoryInvitationLinkStore()
+	authStore := NewMemoryStore()
+	auditStore := NewMemoryAuditLogStore()
+	service := NewInvitationService(invStore, authStore, slog.Default())
+	service.SetAuditLogger(NewAuditLogger(auditStore, true))
+	ctx := context.Background()
+
+	seats := 1
+	teamID := "team-seats"
+	if err := authStore.CreateTeam(ctx, &Team{ID: teamID, IsActive: true, MaxSeats: &seats}); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+	_, token, err := service.CreateInvitationLink(ctx, &CreateInvitationRequest{TeamID: &teamID, CreatedBy: "admin"})
+	if err != nil {
+		t.Fatalf("CreateInvitationLink failed: %v", err)
+	}
+
+	result, err := service.AcceptInvitation(ctx, &AcceptInvitationRequest{Token: token, UserID: "user-1"})
+	if err != nil || !result.Success {
+		t.Fatalf("first accept = %+v, %v", result, err)
+	}
+
+	// An existing member re-accepting does not need a new seat.
+	result, err = service.AcceptInvitation(ctx, &AcceptInvitationRequest{Token: token, UserID: "user-1"})
+	if err != nil || !result.Success {
+		t.Fatalf("re-accept by member = %+v, %v", result, err)
+	}
+
+	result, err = service.AcceptInvitation(ctx, &AcceptInvitationRequest{Token: token, UserID: "user-2"})
+	if err != nil {
+		t.Fatalf("AcceptInvitation failed: %v", err)
+	}
+	if result.Success || result.Message != "team has no available seats" {
+		t.Fatalf("expected seat limit rejection, got %+v", result)
+	}
+	if m, _ := authStore.GetTeamMembership(ctx, "user-2", teamID); m != nil {
+		t.Fatal("user-2 should not have joined a full team")
+	}
+
+	action := AuditActionInvitationAccept
+	success := false
+	logs, _, err := auditStore.ListAuditLogs(AuditLogFilter{Action: &action, Success: &success})
+	if err != nil {
+		t.Fatalf("ListAuditLogs failed: %v", err)
+	}
+	if len(logs) != 1 || logs[0].ActorID != "user-2" || logs[0].Error != "team has no available seats" {
+		t.Fatalf("unexpected rejection audit logs: %+v", logs)
+	}
+}
+
+func TestInvitationService_ExpiryPolicy(t *testing.T) {
+	service := NewInvitationService(NewMemoryInvitationLinkStore(), NewMemoryStore(), slog.Default())
+	service.SetPolicy(InvitationPolicy{DefaultExpiry: 24 * time.Hour, MaxExpiry: 72 * time.Hour})
+	ctx := context.Background()
+	teamID := "team-1"
+
+	link, _, err := service.CreateInvitationLink(ctx, &CreateInvitationRequest{TeamID: &teamID})
+	if err != nil {
+		t.Fatalf("CreateInvitationLink failed: %v", err)
+	}
+	if link.ExpiresAt == nil || link.ExpiresAt.Sub(link.CreatedAt) != 24*time.Hour {
+		t.Fatalf("expected default 24h expiry, got %v", link.ExpiresAt)
+	}
+
+	if _, _, err := service.CreateInvitationLink(ctx, &CreateInvitationRequest{TeamID: &teamID, ExpiresIn: 96}); err != ErrInvitationExpiryTooLong {
+		t.Fatalf("expected ErrInvitationExpiryTooLong, got %v", err)
+	}
+}
+
+func TestInvitationService_ResendAndRevoke(t *testing.T) {
+	invStore := NewMemoryInvitationLinkStore()
+	authStore := NewMemoryStore()
+	auditStore := NewMemoryAuditLogStore()
+	service := NewInvitationService(invStore, authStore, slog.Default())
+	service.SetAuditLogger(NewAuditLogger(auditStore, true))
+	ctx := context.Background()
+	teamID := "team-1"
+	if err := authStore.CreateTeam(ctx, &Team{ID: teamID, IsActive: true}); err != nil {
+		t.Fatalf("CreateTeam failed: %v", err)
+	}
+
+	link, oldToken, err := service.CreateInvitationLink(ctx, &CreateInvitationRequest{TeamID: &teamID, ExpiresIn: 48})
+	if err != nil {
+		t.Fatalf("CreateInvitationLink failed: %v", err)
+	}
+
+	resent, newToken, err := service.ResendInvitation(ctx, link.ID, 0)
+	if err != nil || resent == nil {
+		t.Fatalf("ResendInvitation = %v, %v", resent, err)
+	}
+	if newToken == oldToken {
+		t.Fatal("resend should rotate the token")
+	}
+	if got := resent.ExpiresAt.Sub(resent.UpdatedAt); got != 48*time.Hour {
+		t.Fatalf("resend should keep the 48h window, got %v", got)
+	}
+	if result, _ := service.AcceptInvitation(ctx, &AcceptInvitationRequest{Token: oldToken, UserID: "user-1"}); result.Success {
+		t.Fatal("old token should no longer be accepted")
+	}
+
+	if revoked, err := service.RevokeInvitation(ctx, link.ID); err != nil || revoked == nil || revoked.IsActive {
+		t.Fatalf("RevokeInvitation = %+v, %v", revoked, err)
+	}
+	if result, _ := service.AcceptInvitation(ctx, &AcceptInvitationRequest{Token: newToken, UserID: "user-1"}); result.Success {
+		t.Fatal("revoked invitation should not be accepted")
+	}
+	if missing, err := service.RevokeInvitation(ctx, "missing"); err != nil || missing != nil {
+		t.Fatalf("RevokeInvitation(missing) = %+v, %v", missing, err)
+	}
+
+	for _, action := range []AuditAction{AuditActionInvitationCreate, AuditActionInvitationResend, AuditActionInvitationRevoke} {
+		a := action
+		logs, _, _ := auditStore.ListAuditLogs(AuditLogFilter{Action: &a})
+		if len(logs) != 1 || logs[0].ObjectID != link.ID {
+			t.Errorf("expected one %s audit event, got %d", action, len(logs))
+		}
+	}
+}
@@ -8,22 +8,35 @@ import (
 )
 
 // ============================================================================
-// Background Jobs for Budget Reset and Key Rotation
+// Background Jobs for Budget Reset, Key Rotation and Key Cleanup
 // ============================================================================
 
-// JobRunner manages background jobs for budget reset and key rotation.
+// JobRunner manages background jobs for budget reset, key rotation and
+// cleanup of expired temporary keys.
 type JobRunner struct {
 	store    Store
 	logger   *slog.Logger
 	interval time.Duration
+	jobs     []Job
+	isLeader func() bool
 	stopCh   chan struct{}
 }
 
+// Job is an additional periodic job run after the built-in ones.
+type Job struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
 // JobRunnerConfig contains configuration for the job runner.
 type JobRunnerConfig struct {
-	Store    Store
+	Store    Store // Budget reset, key rotation and key cleanup are skipped when nil
 	Logger   *slog.Logger
 	Interval time.Duration // How often to run jobs (default: 1 hour)
+	Jobs     []Job
+	// IsLeader, when set, limits the jobs to the replica for which it
+	// returns true, so they run once across a cluster.
+	IsLeader func() bool
 }
 
 // NewJobRunner creates a new job runner.
@@ -37,6 +50,8 @@ func NewJobRunner(cfg *JobRunnerConfig) *JobRunner {
 		store:    cfg.Store,
 		logger:   cfg.Logger,
 		interval: interval,
+		jobs:     cfg.Jobs,
+		isLeader: cfg.IsLeader,
 		stopCh:   make(chan struct{}),
 	}
 }
@@ -70,17 +85,35 @@ func (j *JobRunner) run() {
 }
 
 func (j *JobRunner) runJobs() {
+	if j.isLeader != nil && !j.isLeader() {
+		j.logger.Debug("skipping background jobs, not the leader")
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
 	j.logger.Debug("running background jobs")
 
-	// Run budget reset job
-	j.resetBudgets(ctx)
+	if j.store != nil {
+		// Run budget reset job
+		j.resetBudgets(ctx)
 
-	// Run key rotation job
-	if err := j.rotateKeys(ctx); err != nil {
-		j.logger.Error("key rotation job failed", "error", err)
+		// Run key rotation job
+		if err := j.rotateKeys(ctx); err != nil {
+			j.logger.Error("key rotation job failed", "error", err)
+		}
+
+		// Run temporary key cleanup job
+		if err := j.deleteExpiredTemporaryKeys(ctx); err != nil {
+			j.logger.Error("temporary key cleanup job failed", "error", err)
+		}
+	}
+
+	for _, job := range j.jobs {
+		if err := job.Run(ctx); err != nil {
+			j.logger.Error("background job failed", "job", job.Name, "error", err)
+		}
 	}
 }
 
@@ -195,6 +228,51 @@ func (j *JobRunner) rotateKeys(ctx context.Context) error {
 	return nil
 }
 
+// ============================================================================
+// Temporary Key Cleanup Job
+// ============================================================================
+
+// temporaryKeyPageSize is the number of active keys listed per page when
+// looking for expired temporary keys.
+const temporaryKeyPageSize = 1000
+
+func (j *JobRunner) deleteExpiredTemporaryKeys(ctx context.Context) error {
+	// Collect every page before deleting: deleted keys leave the active
+	// listing and would shift later pages.
+	var expired []*APIKey
+	for offset := 0; ; offset += temporaryKeyPageSize {
+		keys, _, err := j.store.ListAPIKeys(ctx, APIKeyFilter{
+			IsActive: boolPtr(true),
+			Limit:    temporaryKeyPageSize,
+			Offset:   offset,
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if key.IsTemporary() && key.IsExpired() {
+				expired = append(expired, key)
+			}
+		}
+		if len(keys) < temporaryKeyPageSize {
+			break
+		}
+	}
+
+	deleted := 0
+	for _, key := range expired {
+		if err := j.store.DeleteAPIKey(ctx, key.ID); err != nil {
+			j.logger.Warn("failed to delete expired temporary key", "key_id", key.ID, "error", err)
+			continue
+		}
+		deleted++
+	}
+	if deleted > 0 {
+		j.logger.Info("deleted expired temporary keys", "count", deleted)
+	}
+	return nil
+}
+
 func (j *JobRunner) findKeysNeedingRotation(ctx context.Context) ([]*APIKey, error) {
 	// Get all active keys
 	keys, _, err := j.store.ListAPIKeys(ctx, APIKeyFilter{
@@ -261,6 +339,7 @@ func (j *JobRunner) rotateKey(ctx context.Context, oldKey *APIKey) error {
 		UserID:              oldKey.UserID,
 		OrganizationID:      oldKey.OrganizationID,
 		AllowedModels:       oldKey.AllowedModels,
+		AllowedCIDRs:        oldKey.AllowedCIDRs,
 		KeyType:             oldKey.KeyType,
 		TPMLimit:            oldKey.TPMLimit,
 		RPMLimit:            oldKey.RPMLimit,
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRunner_DeletesExpiredTemporaryKeys(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	keys := []*APIKey{
+		{ID: "expired-temp", KeyHash: "h1", ExpiresAt: &past, IsActive: true, Metadata: Metadata{"temporary": true}},
+		{ID: "live-temp", KeyHash: "h2", ExpiresAt: &future, IsActive: true, Metadata: Metadata{"temporary": true}},
+		{ID: "expired-regular", KeyHash: "h3", ExpiresAt: &past, IsActive: true},
+	}
+	for _, key := range keys {
+		require.NoError(t, store.CreateAPIKey(ctx, key))
+	}
+
+	runner := NewJobRunner(&JobRunnerConfig{Store: store, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	require.NoError(t, runner.deleteExpiredTemporaryKeys(ctx))
+
+	deleted, err := store.GetAPIKeyByID(ctx, "expired-temp")
+	require.NoError(t, err)
+	require.False(t, deleted.IsActive)
+	for _, id := range []string{"live-temp", "expired-regular"} {
+		key, err := store.GetAPIKeyByID(ctx, id)
+		require.NoError(t, err)
+		require.True(t, key.IsActive, id)
+	}
+}
+
+func TestJobRunner_DeletesExpiredTemporaryKeysPastFirstPage(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	past := time.Now().Add(-time.Minute)
+
+	total := temporaryKeyPageSize + 5
+	for i := 0; i < total; i++ {
+		require.NoError(t, store.CreateAPIKey(ctx, &APIKey{
+			ID:        fmt.Sprintf("temp-%d", i),
+			KeyHash:   fmt.Sprintf("h%d", i),
+			ExpiresAt: &past,
+			IsActive:  true,
+			Metadata:  Metadata{"temporary": true},
+		}))
+	}
+
+	runner := NewJobRunner(&JobRunnerConfig{Store: store, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	require.NoError(t, runner.deleteExpiredTemporaryKeys(ctx))
+
+	remaining, count, err := store.ListAPIKeys(ctx, APIKeyFilter{IsActive: boolPtr(true)})
+	require.NoError(t, err)
+	require.Zero(t, count)
+	require.Empty(t, remaining)
+}
+
+func TestJobRunner_RunsOnlyOnLeader(t *testing.T) {
+	runs := 0
+	leader := false
+	runner := NewJobRunner(&JobRunnerConfig{
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Jobs:     []Job{{Name: "count", Run: func(context.Context) error { runs++; return nil }}},
+		IsLeader: func() bool { return leader },
+	})
+
+	runner.runJobs()
+	require.Equal(t, 0, runs)
+	leader = true
+	runner.runJobs()
+	require.Equal(t, 1, runs)
+}
@@ -0,0 +1,248 @@
+// Package auth provides API key authentication and multi-tenant support.
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+// JWTAuthConfig configures machine-to-machine JWT authentication for
+// data-plane requests.
+type JWTAuthConfig struct {
+	Issuer    string
+	Audiences []string // Accepted "aud" values; empty skips the audience check
+	JWKSURL   string   // Discovered from the issuer when empty
+	// Algorithms restricts the accepted signing algorithms (default RS256).
+	Algorithms   []string
+	ClaimMapping JWTClaimMapping
+}
+
+// JWTClaimMapping maps token claims to the virtual key a request runs as.
+type JWTClaimMapping struct {
+	// KeyIDField names the virtual key (default "sub").
+	KeyIDField string
+	// KeyAliasField binds the token to a stored key by alias; the request
+	// then runs with that key's budget, limits and models.
+	KeyAliasField string
+	TeamIDField   string
+	TeamAliasMap  map[string]string
+	UserIDField   string
+	OrgIDField    string
+	// ModelsField holds the allowed models as a list or space-separated string.
+	ModelsField    string
+	EndUserIDField string
+}
+
+// JWTAuthenticator validates bearer JWTs issued to services and maps them to
+// an AuthContext.
+type JWTAuthenticator struct {
+	cfg      JWTAuthConfig
+	verifier *oidc.IDTokenVerifier
+	store    Store
+	logger   *slog.Logger
+}
+
+// NewJWTAuthenticator creates an authenticator verifying tokens against the
+// issuer's JWKS.
+func NewJWTAuthenticator(ctx context.Context, cfg JWTAuthConfig, store Store, logger *slog.Logger) (*JWTAuthenticator, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cfg.Issuer == "" {
+		return nil, fmt.Errorf("jwt issuer is required")
+	}
+	jwksURL := cfg.JWKSURL
+	if jwksURL == "" {
+		provider, err := oidc.NewProvider(ctx, cfg.Issuer)
+		if err != nil {
+			return nil, fmt.Errorf("discover jwks: %w", err)
+		}
+		var meta struct {
+			JWKSURL string `json:"jwks_uri"`
+		}
+		if err := provider.Claims(&meta); err != nil || meta.JWKSURL == "" {
+			return nil, fmt.Errorf("issuer %s does not advertise jwks_uri", cfg.Issuer)
+		}
+		jwksURL = meta.JWKSURL
+	}
+
+	keySet := oidc.NewRemoteKeySet(context.WithoutCancel(ctx), jwksURL)
+	verifier := oidc.NewVerifier(cfg.Issuer, keySet, &oidc.Config{
+		SkipClientIDCheck:    true, // Audiences are checked against the whole list below
+		SupportedSigningAlgs: cfg.Algorithms,
+	})
+	return &JWTAuthenticator{cfg: cfg, verifier: verifier, store: store, logger: logger}, nil
+}
+
+// Handles reports whether rawToken is a JWT from the configured issuer.
+// Tokens from other issuers are left to the remaining authenticators.
+func (a *JWTAuthenticator) Handles(rawToken string) bool {
+	if !isLikelyJWT(rawToken) {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.Split(rawToken, ".")[1])
+	if err != nil {
+		return false
+	}
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return false
+	}
+	return claims.Issuer == a.cfg.Issuer
+}
+
+// Authenticate verifies rawToken and returns the AuthContext it maps to.
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, rawToken string) (*AuthContext, error) {
+	token, err := a.verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired token")
+	}
+	if len(a.cfg.Audiences) > 0 && !slices.ContainsFunc(token.Audience, func(aud string) bool {
+		return slices.Contains(a.cfg.Audiences, aud)
+	}) {
+		return nil, fmt.Errorf("token audience not accepted")
+	}
+
+	var claims map[string]any
+	if err := token.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to parse claims")
+	}
+	mapping := a.cfg.ClaimMapping
+
+	key, err := a.resolveKey(ctx, claims, token.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	var team *Team
+	if key.TeamID != nil {
+		team, err = a.store.GetTeam(ctx, *key.TeamID)
+		if err != nil {
+			return nil, fmt.Errorf("lookup team: %w", err)
+		}
+		if team == nil {
+			return nil, fmt.Errorf("invalid team")
+		}
+		if team.IsBlocked() {
+			return nil, fmt.Errorf("team is blocked")
+		}
+	}
+
+	authCtx := &AuthContext{
+		APIKey:    key,
+		Team:      team,
+		EndUserID: extractStringClaim(claims, mapping.EndUserIDField),
+		SSOUserID: token.Subject,
+	}
+	if key.OrganizationID != nil {
+		authCtx.JWTOrgID = *key.OrganizationID
+	}
+	return authCtx, nil
+}
+
+// resolveKey returns the stored key bound by the alias claim, or a virtual
+// key built from the token's claims.
+func (a *JWTAuthenticator) resolveKey(ctx context.Context, claims map[string]any, subject string) (*APIKey, error) {
+	mapping := a.cfg.ClaimMapping
+	if alias := extractStringClaim(claims, mapping.KeyAliasField); alias != "" {
+		key, err := a.store.GetAPIKeyByAlias(ctx, alias)
+		if err != nil {
+			return nil, fmt.Errorf("lookup api key: %w", err)
+		}
+		switch {
+		case key == nil:
+			return nil, fmt.Errorf("invalid api key")
+		case !key.IsActive:
+			return nil, fmt.Errorf("api key is inactive")
+		case key.IsExpired():
+			return nil, fmt.Errorf("api key has expired")
+		case key.Blocked:
+			return nil, fmt.Errorf("api key is blocked")
+		}
+		return key, nil
+	}
+
+	keyID := subject
+	if mapping.KeyIDField != "" {
+		keyID = extractStringClaim(claims, mapping.KeyIDField)
+	}
+	if keyID == "" {
+		return nil, fmt.Errorf("token has no key id claim")
+	}
+
+	key := &APIKey{
+		ID:             "jwt:" + keyID,
+		Name:           keyID,
+		UserID:         strPtr(extractStringClaim(claims, mapping.UserIDField)),
+		OrganizationID: strPtr(extractStringClaim(claims, mapping.OrgIDField)),
+		AllowedModels:  extractListClaim(claims, mapping.ModelsField),
+		KeyType:        KeyTypeLLMAPI,
+		Metadata:       Metadata{"jwt_issuer": a.cfg.Issuer},
+		IsActive:       true,
+		Virtual:        true,
+	}
+	if teamID := extractStringClaim(claims, mapping.TeamIDField); teamID != "" {
+		if mapped, ok := mapping.TeamAliasMap[teamID]; ok {
+			teamID = mapped
+		}
+		key.TeamID = &teamID
+	}
+	return key, nil
+}
+
+// Middleware authenticates /v1/* requests carrying a JWT from the configured
+// issuer. Other requests pass through to the remaining authenticators.
+// Rejections carry a fixed message; the reason is only logged, as it may
+// wrap store errors.
+func (a *JWTAuthenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if GetAuthContext(r.Context()) != nil || !strings.HasPrefix(r.URL.Path, "/v1/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rawToken, err := ParseAuthHeader(r.Header.Get("Authorization"))
+		if err != nil || !a.Handles(rawToken) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		authCtx, err := a.Authenticate(r.Context(), rawToken)
+		if err != nil {
+			a.logger.Warn("jwt authentication failed", "error", err, "path", r.URL.Path)
+			writeJSONError(w, http.StatusUnauthorized, "authentication_error", "invalid or unauthorized token")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), AuthContextKey, authCtx)))
+	})
+}
+
+// extractListClaim reads a list claim or a space-separated string claim,
+// as used by the OAuth "scope" claim.
+func extractListClaim(claims map[string]any, field string) []string {
+	if field == "" {
+		return nil
+	}
+	switch v := claims[field].(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const testJWTIssuer = "https://issuer.example.com"
+
+type testJWTIssuerServer struct {
+	server *httptest.Server
+	signer jose.Signer
+}
+
+func newTestJWTIssuer(t *testing.T) *testJWTIssuerServer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, (&jose.SignerOptions{}).WithHeader("kid", "k1"))
+	require.NoError(t, err)
+
+	jwks := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: "k1", Algorithm: "RS256", Use: "sig"}}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	t.Cleanup(server.Close)
+	return &testJWTIssuerServer{server: server, signer: signer}
+}
+
+func (s *testJWTIssuerServer) token(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	base := map[string]any{
+		"iss": testJWTIssuer,
+		"sub": "ci-runner",
+		"aud": "llmux",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iat": time.Now().Unix(),
+	}
+	for k, v := range claims {
+		base[k] = v
+	}
+	payload, err := json.Marshal(base)
+	require.NoError(t, err)
+	obj, err := s.signer.Sign(payload)
+	require.NoError(t, err)
+	raw, err := obj.CompactSerialize()
+	require.NoError(t, err)
+	return raw
+}
+
+func newTestJWTAuthenticator(t *testing.T, issuer *testJWTIssuerServer, store Store, mapping JWTClaimMapping) *JWTAuthenticator {
+	t.Helper()
+	authenticator, err := NewJWTAuthenticator(context.Background(), JWTAuthConfig{
+		Issuer:       testJWTIssuer,
+		Audiences:    []string{"llmux"},
+		JWKSURL:      issuer.server.URL,
+		ClaimMapping: mapping,
+	}, store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	return authenticator
+}
+
+func TestJWTAuthenticator_MapsClaimsToVirtualKey(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	require.NoError(t, store.CreateTeam(ctx, &Team{ID: "team-1", IsActive: true}))
+	issuer := newTestJWTIssuer(t)
+	authenticator := newTestJWTAuthenticator(t, issuer, store, JWTClaimMapping{
+		TeamIDField:  "team",
+		TeamAliasMap: map[string]string{"platform": "team-1"},
+		ModelsField:  "models",
+	})
+
+	authCtx, err := authenticator.Authenticate(ctx, issuer.token(t, map[string]any{
+		"team":   "platform",
+		"models": "gpt-4 claude-3",
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "jwt:ci-runner", authCtx.APIKey.ID)
+	require.True(t, authCtx.APIKey.IsVirtual())
+	require.Equal(t, []string{"gpt-4", "claude-3"}, authCtx.APIKey.AllowedModels)
+	require.NotNil(t, authCtx.Team)
+	require.Equal(t, "team-1", authCtx.Team.ID)
+}
+
+func TestJWTAuthenticator_BindsStoredKeyByAlias(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	alias := "ci"
+	require.NoError(t, store.CreateAPIKey(ctx, &APIKey{ID: "key-1", KeyHash: "h", KeyAlias: &alias, MaxBudget: 10, IsActive: true}))
+	issuer := newTestJWTIssuer(t)
+	authenticator := newTestJWTAuthenticator(t, issuer, store, JWTClaimMapping{KeyAliasField: "key_alias"})
+
+	authCtx, err := authenticator.Authenticate(ctx, issuer.token(t, map[string]any{"key_alias": "ci"}))
+	require.NoError(t, err)
+	require.Equal(t, "key-1", authCtx.APIKey.ID)
+	require.False(t, authCtx.APIKey.IsVirtual())
+
+	_, err = authenticator.Authenticate(ctx, issuer.token(t, map[string]any{"key_alias": "missing"}))
+	require.Error(t, err)
+}
+
+func TestJWTAuthenticator_RejectsInvalidTokens(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestJWTIssuer(t)
+	authenticator := newTestJWTAuthenticator(t, issuer, NewMemoryStore(), JWTClaimMapping{})
+
+	cases := map[string]map[string]any{
+		"expired":        {"exp": time.Now().Add(-time.Hour).Unix()},
+		"wrong audience": {"aud": "other"},
+		"wrong issuer":   {"iss": "https://other.example.com"},
+	}
+	for name, claims := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := authenticator.Authenticate(ctx, issuer.token(t, claims))
+			require.Error(t, err)
+		})
+	}
+
+	forged := newTestJWTIssuer(t)
+	_, err := authenticator.Authenticate(ctx, forged.token(t, nil))
+	require.Error(t, err)
+}
+
+func TestJWTAuthenticator_MiddlewareScopesToDataPlane(t *testing.T) {
+	issuer := newTestJWTIssuer(t)
+	authenticator := newTestJWTAuthenticator(t, issuer, NewMemoryStore(), JWTClaimMapping{})
+
+	var got *AuthContext
+	handler := authenticator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = GetAuthContext(r.Context())
+	}))
+	serve := func(path, token string) int {
+		got = nil
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve("/v1/chat/completions", issuer.token(t, nil)))
+	require.NotNil(t, got)
+	require.Equal(t, "jwt:ci-runner", got.APIKey.ID)
+
+	require.Equal(t, http.StatusOK, serve("/key/list", issuer.token(t, nil)))
+	require.Nil(t, got, "management routes are not authenticated by service JWTs")
+
+	require.Equal(t, http.StatusOK, serve("/v1/chat/completions", issuer.token(t, map[string]any{"iss": "https://sso.example.com"})))
+	require.Nil(t, got, "tokens from other issuers fall through")
+
+	require.Equal(t, http.StatusUnauthorized, serve("/v1/chat/completions", issuer.token(t, map[string]any{"aud": "other"})))
+}
+
+func TestJWTAuthenticator_BoundKeyKeepsSourceChecks(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	alias, org := "ci", "org-a"
+	require.NoError(t, store.CreateAPIKey(ctx, &APIKey{
+		ID:             "key-1",
+		KeyHash:        "h",
+		KeyAlias:       &alias,
+		OrganizationID: &org,
+		AllowedCIDRs:   []string{"10.0.0.0/8"},
+		IsActive:       true,
+	}))
+	issuer := newTestJWTIssuer(t)
+	authenticator := newTestJWTAuthenticator(t, issuer, store, JWTClaimMapping{KeyAliasField: "key_alias"})
+	middleware := NewMiddleware(&MiddlewareConfig{
+		Store:   store,
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Enabled: true,
+	})
+	handler := authenticator.Middleware(middleware.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	serve := func(remoteAddr string, tenant *HostTenant) int {
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Authorization", "Bearer "+issuer.token(t, map[string]any{"key_alias": "ci"}))
+		if tenant != nil {
+			req = req.WithContext(WithHostTenant(req.Context(), tenant))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve("10.1.2.3:1234", nil))
+	require.Equal(t, http.StatusForbidden, serve("192.168.1.1:1234", nil), "allowed_cidrs apply to JWT-bound keys")
+	require.Equal(t, http.StatusOK, serve("10.1.2.3:1234", &HostTenant{OrganizationID: "org-a"}))
+	require.Equal(t, http.StatusForbidden, serve("10.1.2.3:1234", &HostTenant{OrganizationID: "org-b"}),
+		"host tenants apply to JWT-bound keys")
+}
+
+// aliasLookupFailingStore fails alias lookups with an error that must not
+// reach clients.
+type aliasLookupFailingStore struct {
+	Store
+}
+
+func (s aliasLookupFailingStore) GetAPIKeyByAlias(context.Context, string) (*APIKey, error) {
+	return nil, errors.New(`pq: relation "api_keys" does not exist`)
+}
+
+func TestJWTAuthenticator_MiddlewareHidesErrorDetails(t *testing.T) {
+	issuer := newTestJWTIssuer(t)
+	authenticator := newTestJWTAuthenticator(t, issuer, aliasLookupFailingStore{Store: NewMemoryStore()}, JWTClaimMapping{
+		KeyAliasField: "key_alias",
+	})
+	handler := authenticator.Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Fatal("request must not pass")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	req.Header.Set("Authorization", "Bearer "+issuer.token(t, map[string]any{"key_alias": "ci"}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, "authentication_error", body.Error.Type)
+	require.NotContains(t, rec.Body.String(), "api_keys")
+}
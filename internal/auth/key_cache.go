@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// KeyCacheInvalidator fans out key invalidations to other gateway instances.
+type KeyCacheInvalidator interface {
+	// Publish announces that keyID changed.
+	Publish(ctx context.Context, keyID string) error
+	// Subscribe calls fn for every key ID published by any instance until ctx is done.
+	Subscribe(ctx context.Context, fn func(keyID string)) error
+}
+
+// KeyCacheConfig configures a CachedStore.
+type KeyCacheConfig struct {
+	Size        int           // Maximum cached keys (default 10000)
+	TTL         time.Duration // Maximum age of a cached key (default 30s)
+	Invalidator KeyCacheInvalidator
+	Logger      *slog.Logger
+}
+
+type keyCacheEntry struct {
+	hash      string
+	key       *APIKey
+	expiresAt time.Time
+}
+
+// CachedStore wraps a Store with an in-process LRU for GetAPIKeyByHash so the
+// auth hot path avoids a database round trip per request.
+//
+// Blocks, deletions and updates made through the store evict the key locally
+// and are published to the invalidator so other instances evict it too.
+// Spend increments update the local copy in place so billed keys stay
+// cached; on other instances spend may lag by up to TTL.
+type CachedStore struct {
+	Store
+
+	size        int
+	ttl         time.Duration
+	invalidator KeyCacheInvalidator
+	logger      *slog.Logger
+	now         func() time.Time
+
+	mu     sync.Mutex
+	lru    *list.List
+	byHash map[string]*list.Element
+	byID   map[string]string // key ID -> hash
+
+	cancel context.CancelFunc
+}
+
+// NewCachedStore wraps store with a key cache. When cfg.Invalidator is set,
+// it subscribes immediately and fails if the subscription cannot be started.
+func NewCachedStore(store Store, cfg KeyCacheConfig) (*CachedStore, error) {
+	if cfg.Size <= 0 {
+		cfg.Size = 10000
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 30 * time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	s := &CachedStore{
+		Store:       store,
+		size:        cfg.Size,
+		ttl:         cfg.TTL,
+		invalidator: cfg.Invalidator,
+		logger:      cfg.Logger,
+		now:         time.Now,
+		lru:         list.New(),
+		byHash:      make(map[string]*list.Element),
+		byID:        make(map[string]string),
+	}
+	if s.invalidator != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		if err := s.invalidator.Subscribe(ctx, s.Invalidate); err != nil {
+			cancel()
+			return nil, err
+		}
+		s.cancel = cancel
+	}
+	return s, nil
+}
+
+// Unwrap returns the underlying store.
+func (s *CachedStore) Unwrap() Store {
+	return s.Store
+}
+
+// UnwrapStore strips cache wrappers so callers can type-assert on the
+// concrete backing store.
+func UnwrapStore(store Store) Store {
+	for {
+		u, ok := store.(interface{ Unwrap() Store })
+		if !ok {
+			return store
+		}
+		store = u.Unwrap()
+	}
+}
+
+// GetAPIKeyByHash serves the key from cache, falling back to the store.
+// Misses are not cached so newly created keys are usable immediately.
+func (s *CachedStore) GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error) {
+	if key := s.get(hash); key != nil {
+		return key, nil
+	}
+	key, err := s.Store.GetAPIKeyByHash(ctx, hash)
+	if err != nil || key == nil {
+		return key, err
+	}
+	s.put(hash, key)
+	return key.Clone(), nil
+}
+
+// Invalidate evicts keyID from the local cache.
+func (s *CachedStore) Invalidate(keyID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hash, ok := s.byID[keyID]
+	if !ok {
+		return
+	}
+	if elem, ok := s.byHash[hash]; ok {
+		s.removeElement(elem)
+	}
+}
+
+// Len returns the number of cached keys.
+func (s *CachedStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
+
+// UpdateAPIKey updates the key and invalidates it on all instances.
+func (s *CachedStore) UpdateAPIKey(ctx context.Context, key *APIKey) error {
+	if err := s.Store.UpdateAPIKey(ctx, key); err != nil {
+		return err
+	}
+	s.invalidateEverywhere(ctx, key.ID)
+	return nil
+}
+
+// DeleteAPIKey deletes the key and invalidates it on all instances.
+func (s *CachedStore) DeleteAPIKey(ctx context.Context, keyID string) error {
+	if err := s.Store.DeleteAPIKey(ctx, keyID); err != nil {
+		return err
+	}
+	s.invalidateEverywhere(ctx, keyID)
+	return nil
+}
+
+// BlockAPIKey blocks or unblocks the key and invalidates it on all instances.
+func (s *CachedStore) BlockAPIKey(ctx context.Context, keyID string, blocked bool) error {
+	if err := s.Store.BlockAPIKey(ctx, keyID, blocked); err != nil {
+		return err
+	}
+	s.invalidateEverywhere(ctx, keyID)
+	return nil
+}
+
+// ResetAPIKeyBudget resets the key's spend and invalidates it on all instances.
+func (s *CachedStore) ResetAPIKeyBudget(ctx context.Context, keyID string) error {
+	if err := s.Store.ResetAPIKeyBudget(ctx, keyID); err != nil {
+		return err
+	}
+	s.invalidateEverywhere(ctx, keyID)
+	return nil
+}
+
+// UpdateAPIKeySpent records spend and adds it to the local copy.
+func (s *CachedStore) UpdateAPIKeySpent(ctx context.Context, keyID string, amount float64) error {
+	if err := s.Store.UpdateAPIKeySpent(ctx, keyID, amount); err != nil {
+		return err
+	}
+	s.updateCached(keyID, func(key *APIKey) {
+		key.SpentBudget += amount
+	})
+	return nil
+}
+
+// UpdateAPIKeyModelSpent records per-model spend and adds it to the local copy.
+func (s *CachedStore) UpdateAPIKeyModelSpent(ctx context.Context, keyID, model string, amount float64) error {
+	if err := s.Store.UpdateAPIKeyModelSpent(ctx, keyID, model, amount); err != nil {
+		return err
+	}
+	s.updateCached(keyID, func(key *APIKey) {
+		if key.ModelSpend == nil {
+			key.ModelSpend = make(map[string]float64)
+		}
+		key.ModelSpend[model] += amount
+	})
+	return nil
+}
+
+// Close stops the invalidation subscription and closes the underlying store.
+func (s *CachedStore) Close() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return s.Store.Close()
+}
+
+func (s *CachedStore) invalidateEverywhere(ctx context.Context, keyID string) {
+	s.Invalidate(keyID)
+	if s.invalidator == nil {
+		return
+	}
+	if err := s.invalidator.Publish(ctx, keyID); err != nil {
+		s.logger.Warn("failed to publish key invalidation", "key_id", keyID, "error", err)
+	}
+}
+
+func (s *CachedStore) get(hash string) *APIKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.byHash[hash]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*keyCacheEntry)
+	if s.now().After(entry.expiresAt) {
+		s.removeElement(elem)
+		return nil
+	}
+	s.lru.MoveToFront(elem)
+	return entry.key.Clone()
+}
+
+// updateCached applies fn to the cached copy of keyID, if any. Readers only
+// ever see clones, so the entry can be mutated under s.mu.
+func (s *CachedStore) updateCached(keyID string, fn func(*APIKey)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.byHash[s.byID[keyID]]; ok {
+		fn(elem.Value.(*keyCacheEntry).key)
+	}
+}
+
+func (s *CachedStore) put(hash string, key *APIKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.byHash[hash]; ok {
+		s.removeElement(elem)
+	}
+	entry := &keyCacheEntry{hash: hash, key: key.Clone(), expiresAt: s.now().Add(s.ttl)}
+	s.byHash[hash] = s.lru.PushFront(entry)
+	s.byID[key.ID] = hash
+	for s.lru.Len() > s.size {
+		s.removeElement(s.lru.Back())
+	}
+}
+
+// removeElement must be called with s.mu held.
+func (s *CachedStore) removeElement(elem *list.Element) {
+	entry := elem.Value.(*keyCacheEntry)
+	s.lru.Remove(elem)
+	delete(s.byHash, entry.hash)
+	if s.byID[entry.key.ID] == entry.hash {
+		delete(s.byID, entry.key.ID)
+	}
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultKeyInvalidationChannel is the Redis channel used for key invalidations.
+const DefaultKeyInvalidationChannel = "llmux:auth:key_invalidate"
+
+// RedisKeyInvalidator propagates key invalidations over Redis pub/sub.
+type RedisKeyInvalidator struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+// NewRedisKeyInvalidator creates an invalidator on channel (default
+// DefaultKeyInvalidationChannel).
+func NewRedisKeyInvalidator(client redis.UniversalClient, channel string) *RedisKeyInvalidator {
+	if channel == "" {
+		channel = DefaultKeyInvalidationChannel
+	}
+	return &RedisKeyInvalidator{client: client, channel: channel}
+}
+
+// Publish implements KeyCacheInvalidator.
+func (r *RedisKeyInvalidator) Publish(ctx context.Context, keyID string) error {
+	return r.client.Publish(ctx, r.channel, keyID).Err()
+}
+
+// Subscribe implements KeyCacheInvalidator. It returns once the subscription
+// is confirmed and delivers messages in the background until ctx is done.
+func (r *RedisKeyInvalidator) Subscribe(ctx context.Context, fn func(keyID string)) error {
+	sub := r.client.Subscribe(ctx, r.channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return fmt.Errorf("subscribe to %s: %w", r.channel, err)
+	}
+	go func() {
+		defer sub.Close()
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				fn(msg.Payload)
+			}
+		}
+	}()
+	return nil
+}
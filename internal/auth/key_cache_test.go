@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type countingKeyStore struct {
+	*MemoryStore
+	lookups int
+}
+
+func (s *countingKeyStore) GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error) {
+	s.lookups++
+	return s.MemoryStore.GetAPIKeyByHash(ctx, hash)
+}
+
+func newCachedTestKey(t *testing.T, store Store, id string) *APIKey {
+	t.Helper()
+	key := &APIKey{ID: id, KeyHash: "hash-" + id, CreatedAt: time.Now()}
+	if err := store.CreateAPIKey(context.Background(), key); err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	return key
+}
+
+func TestCachedStore_HitAvoidsStore(t *testing.T) {
+	inner := &countingKeyStore{MemoryStore: NewMemoryStore()}
+	cached, err := NewCachedStore(inner, KeyCacheConfig{})
+	if err != nil {
+		t.Fatalf("NewCachedStore: %v", err)
+	}
+	key := newCachedTestKey(t, inner, "k1")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		got, err := cached.GetAPIKeyByHash(ctx, key.KeyHash)
+		if err != nil || got == nil || got.ID != "k1" {
+			t.Fatalf("GetAPIKeyByHash = %v, %v", got, err)
+		}
+		got.Blocked = true // must not leak into the cache
+	}
+	if inner.lookups != 1 {
+		t.Fatalf("store lookups = %d, want 1", inner.lookups)
+	}
+	got, _ := cached.GetAPIKeyByHash(ctx, key.KeyHash)
+	if got.Blocked {
+		t.Fatal("caller mutation leaked into cached key")
+	}
+
+	if got, _ := cached.GetAPIKeyByHash(ctx, "missing"); got != nil {
+		t.Fatalf("missing key = %v, want nil", got)
+	}
+	if cached.Len() != 1 {
+		t.Fatalf("Len = %d, want 1 (misses are not cached)", cached.Len())
+	}
+}
+
+func TestCachedStore_EvictionAndTTL(t *testing.T) {
+	inner := &countingKeyStore{MemoryStore: NewMemoryStore()}
+	cached, err := NewCachedStore(inner, KeyCacheConfig{Size: 2, TTL: time.Minute})
+	if err != nil {
+		t.Fatalf("NewCachedStore: %v", err)
+	}
+	now := time.Now()
+	cached.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for _, id := range []string{"a", "b", "c"} {
+		key := newCachedTestKey(t, inner, id)
+		_, _ = cached.GetAPIKeyByHash(ctx, key.KeyHash)
+	}
+	if cached.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", cached.Len())
+	}
+	inner.lookups = 0
+	_, _ = cached.GetAPIKeyByHash(ctx, "hash-a")
+	if inner.lookups != 1 {
+		t.Fatalf("least recently used key should have been evicted")
+	}
+
+	now = now.Add(2 * time.Minute)
+	inner.lookups = 0
+	_, _ = cached.GetAPIKeyByHash(ctx, "hash-c")
+	if inner.lookups != 1 {
+		t.Fatalf("expired key should be reloaded")
+	}
+}
+
+func TestCachedStore_BlockAndDeleteInvalidate(t *testing.T) {
+	cached, err := NewCachedStore(NewMemoryStore(), KeyCacheConfig{})
+	if err != nil {
+		t.Fatalf("NewCachedStore: %v", err)
+	}
+	key := &APIKey{ID: "k1", KeyHash: "hash-k1", IsActive: true, CreatedAt: time.Now()}
+	ctx := context.Background()
+	if err := cached.CreateAPIKey(ctx, key); err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	_, _ = cached.GetAPIKeyByHash(ctx, key.KeyHash)
+	if err := cached.BlockAPIKey(ctx, key.ID, true); err != nil {
+		t.Fatalf("BlockAPIKey: %v", err)
+	}
+	got, _ := cached.GetAPIKeyByHash(ctx, key.KeyHash)
+	if got == nil || !got.Blocked {
+		t.Fatalf("blocked key served stale: %+v", got)
+	}
+
+	if err := cached.DeleteAPIKey(ctx, key.ID); err != nil {
+		t.Fatalf("DeleteAPIKey: %v", err)
+	}
+	if got, _ := cached.GetAPIKeyByHash(ctx, key.KeyHash); got != nil && got.IsActive {
+		t.Fatalf("deleted key still served as active: %+v", got)
+	}
+}
+
+func TestCachedStore_SpendUpdatesCachedKey(t *testing.T) {
+	inner := &countingKeyStore{MemoryStore: NewMemoryStore()}
+	cached, err := NewCachedStore(inner, KeyCacheConfig{})
+	if err != nil {
+		t.Fatalf("NewCachedStore: %v", err)
+	}
+	key := newCachedTestKey(t, inner, "k1")
+	ctx := context.Background()
+
+	_, _ = cached.GetAPIKeyByHash(ctx, key.KeyHash)
+	if err := cached.UpdateAPIKeySpent(ctx, key.ID, 1.5); err != nil {
+		t.Fatalf("UpdateAPIKeySpent: %v", err)
+	}
+	if err := cached.UpdateAPIKeyModelSpent(ctx, key.ID, "gpt-4", 1.5); err != nil {
+		t.Fatalf("UpdateAPIKeyModelSpent: %v", err)
+	}
+
+	got, _ := cached.GetAPIKeyByHash(ctx, key.KeyHash)
+	if inner.lookups != 1 {
+		t.Fatalf("store lookups = %d, want 1 (billed key should stay cached)", inner.lookups)
+	}
+	if got.SpentBudget != 1.5 || got.ModelSpend["gpt-4"] != 1.5 {
+		t.Fatalf("cached spend = %v, %v; want 1.5, 1.5", got.SpentBudget, got.ModelSpend)
+	}
+}
+
+func TestCachedStore_RedisInvalidationAcrossInstances(t *testing.T) {
+	mr := miniredis.RunT(t)
+	shared := NewMemoryStore()
+	key := newCachedTestKey(t, shared, "k1")
+	ctx := context.Background()
+
+	newInstance := func() *CachedStore {
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+		cached, err := NewCachedStore(shared, KeyCacheConfig{
+			TTL:         time.Hour,
+			Invalidator: NewRedisKeyInvalidator(client, ""),
+		})
+		if err != nil {
+			t.Fatalf("NewCachedStore: %v", err)
+		}
+		t.Cleanup(func() { cached.cancel() })
+		return cached
+	}
+	a, b := newInstance(), newInstance()
+
+	_, _ = a.GetAPIKeyByHash(ctx, key.KeyHash)
+	_, _ = b.GetAPIKeyByHash(ctx, key.KeyHash)
+
+	if err := a.BlockAPIKey(ctx, key.ID, true); err != nil {
+		t.Fatalf("BlockAPIKey: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for b.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("instance b did not receive the invalidation")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	got, _ := b.GetAPIKeyByHash(ctx, key.KeyHash)
+	if got == nil || !got.Blocked {
+		t.Fatalf("instance b served stale key: %+v", got)
+	}
+}
+
+func TestUnwrapStore(t *testing.T) {
+	inner := NewMemoryStore()
+	cached, err := NewCachedStore(inner, KeyCacheConfig{})
+	if err != nil {
+		t.Fatalf("NewCachedStore: %v", err)
+	}
+	if UnwrapStore(cached) != Store(inner) {
+		t.Fatal("UnwrapStore did not return the inner store")
+	}
+	if UnwrapStore(inner) != Store(inner) {
+		t.Fatal("UnwrapStore changed an unwrapped store")
+	}
+}
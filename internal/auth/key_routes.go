@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RouteClass groups routes by the kind of key allowed to call them.
+type RouteClass string
+
+const (
+	RouteClassData       RouteClass = "data"       // OpenAI-compatible LLM API routes
+	RouteClassInfo       RouteClass = "info"       // Read-only discovery and monitoring routes
+	RouteClassManagement RouteClass = "management" // Admin API routes, including /key/*
+)
+
+// managementPrefixes are the leading path segments of the management API.
+var managementPrefixes = []string{
+	"/access_group", "/admin", "/api", "/audit", "/auth", "/config", "/control",
+	"/customer", "/global", "/invitation", "/key", "/logs", "/model",
+	"/organization", "/provenance", "/router", "/spend", "/team", "/user",
+}
+
+// ClassifyRoute returns the route class of a request. Routes outside the
+// data, info and management APIs, such as UI assets, are data routes.
+func ClassifyRoute(method, path string) RouteClass {
+	switch {
+	case isInfoRoute(method, path):
+		return RouteClassInfo
+	case hasPathPrefix(path, "/v1/prompts"):
+		return RouteClassManagement
+	case strings.HasPrefix(path, "/v1/"), path == "/embeddings":
+		return RouteClassData
+	}
+	for _, prefix := range managementPrefixes {
+		if hasPathPrefix(path, prefix) {
+			return RouteClassManagement
+		}
+	}
+	return RouteClassData
+}
+
+func isInfoRoute(method, path string) bool {
+	if method != http.MethodGet && method != http.MethodHead {
+		return false
+	}
+	return path == "/v1/models" || path == "/metrics" || strings.HasPrefix(path, "/health/")
+}
+
+// hasPathPrefix reports whether path is prefix or lies below it.
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// AllowsRoute reports whether a key of this type may call method on path.
+// Management keys may call any route, read-only keys only info routes, and
+// all other key types only data-plane and info routes.
+func (t KeyType) AllowsRoute(method, path string) bool {
+	class := ClassifyRoute(method, path)
+	switch t {
+	case KeyTypeManagement:
+		return true
+	case KeyTypeReadOnly:
+		return class == RouteClassInfo
+	default:
+		return class != RouteClassManagement
+	}
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestKeyType_AllowsRoute(t *testing.T) {
+	tests := []struct {
+		keyType KeyType
+		method  string
+		path    string
+		want    bool
+	}{
+		{KeyTypeManagement, http.MethodPost, "/key/generate", true},
+		{KeyTypeManagement, http.MethodPost, "/v1/chat/completions", true},
+		{KeyTypeLLMAPI, http.MethodPost, "/v1/chat/completions", true},
+		{KeyTypeLLMAPI, http.MethodPost, "/embeddings", true},
+		{KeyTypeLLMAPI, http.MethodGet, "/v1/models", true},
+		{KeyTypeLLMAPI, http.MethodPost, "/key/generate", false},
+		{KeyTypeLLMAPI, http.MethodGet, "/control/config", false},
+		{KeyTypeLLMAPI, http.MethodPost, "/provenance/verify", false},
+		{KeyTypeLLMAPI, http.MethodPost, "/v1/prompts", false},
+		{KeyTypeLLMAPI, http.MethodGet, "/v1/prompts/support", false},
+		{KeyTypeManagement, http.MethodPost, "/v1/prompts", true},
+		{KeyTypeDefault, http.MethodGet, "/key/list", false},
+		{"", http.MethodPost, "/v1/responses", true},
+		{"", http.MethodGet, "/team/list", false},
+		{KeyTypeReadOnly, http.MethodGet, "/v1/models", true},
+		{KeyTypeReadOnly, http.MethodPost, "/v1/chat/completions", false},
+		{KeyTypeReadOnly, http.MethodGet, "/key/list", false},
+		{KeyTypeLLMAPI, http.MethodGet, "/metrics", true},
+		{KeyTypeReadOnly, http.MethodGet, "/metrics", true},
+		{KeyTypeReadOnly, http.MethodGet, "/health/ready", true},
+		{KeyTypeLLMAPI, http.MethodGet, "/health/live", true},
+		{KeyTypeLLMAPI, http.MethodGet, "/keys-and-more", true},
+		{KeyTypeLLMAPI, http.MethodGet, "/key", false},
+		{KeyTypeLLMAPI, http.MethodGet, "/admin/tail", false},
+		{KeyTypeLLMAPI, http.MethodPost, "/logs/replay", false},
+		{KeyTypeLLMAPI, http.MethodPost, "/v1/memory/docs/documents", true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.keyType.AllowsRoute(tt.method, tt.path); got != tt.want {
+			t.Errorf("%q.AllowsRoute(%s, %s) = %v, want %v", tt.keyType, tt.method, tt.path, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +21,10 @@ type MemoryStore struct {
 	users           map[string]*User
 	endUsers        map[string]*EndUser
 	usageLogs       []*UsageLog
+	modelGroups     map[string]*ModelAccessGroup
+	modelProviders  map[string]*ModelProvider
+	configVersions  []*ConfigVersion
+	prompts         map[string][]*PromptVersion // prompt ID -> versions, oldest first
 }
 
 // NewMemoryStore creates a new in-memory store.
@@ -35,6 +40,9 @@ func NewMemoryStore() *MemoryStore {
 		users:           make(map[string]*User),
 		endUsers:        make(map[string]*EndUser),
 		usageLogs:       make([]*UsageLog, 0),
+		modelGroups:     make(map[string]*ModelAccessGroup),
+		modelProviders:  make(map[string]*ModelProvider),
+		prompts:         make(map[string][]*PromptVersion),
 	}
 }
 
@@ -169,6 +177,13 @@ func (s *MemoryStore) ListAPIKeys(_ context.Context, filter APIKeyFilter) ([]*AP
 		}
 		result = append(result, key.Clone())
 	}
+	// Match the Postgres ordering so pages are stable across calls.
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
 
 	total := int64(len(result))
 	if filter.Offset >= len(result) {
@@ -433,6 +448,9 @@ func (s *MemoryStore) GetUsageStats(_ context.Context, filter UsageFilter) (*Usa
 		if filter.TeamID != nil && (log.TeamID == nil || *log.TeamID != *filter.TeamID) {
 			continue
 		}
+		if filter.EndUserID != nil && (log.EndUserID == nil || *log.EndUserID != *filter.EndUserID) {
+			continue
+		}
 		if filter.Model != nil && log.Model != *filter.Model {
 			continue
 		}
@@ -463,8 +481,101 @@ func (s *MemoryStore) GetUsageStats(_ context.Context, filter UsageFilter) (*Usa
 	return stats, nil
 }
 
-func (s *MemoryStore) GetDailyUsage(_ context.Context, _ DailyUsageFilter) ([]*DailyUsage, error) {
-	return []*DailyUsage{}, nil
+// GetDailyUsage aggregates usage logs by day, key, team, model and provider.
+func (s *MemoryStore) GetDailyUsage(_ context.Context, filter DailyUsageFilter) ([]*DailyUsage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type dailyKey struct {
+		date, apiKeyID, teamID, model, provider string
+	}
+	groups := make(map[dailyKey]*DailyUsage)
+	result := []*DailyUsage{}
+	for _, log := range s.usageLogs {
+		date, ok := matchDailyFilter(log, filter)
+		if !ok {
+			continue
+		}
+		teamID := ""
+		if log.TeamID != nil {
+			teamID = *log.TeamID
+		}
+		k := dailyKey{date, log.APIKeyID, teamID, log.Model, log.Provider}
+		usage, ok := groups[k]
+		if !ok {
+			model, provider := log.Model, log.Provider
+			usage = &DailyUsage{Date: date, APIKeyID: log.APIKeyID, Model: &model, Provider: &provider}
+			if log.TeamID != nil {
+				usage.TeamID = &teamID
+			}
+			groups[k] = usage
+			result = append(result, usage)
+		}
+		usage.InputTokens += int64(log.InputTokens)
+		usage.OutputTokens += int64(log.OutputTokens)
+		usage.Spend += log.Cost
+		usage.APIRequests++
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Date > result[j].Date })
+	return result, nil
+}
+
+// GetDailyTagUsage aggregates usage logs by day and request tag.
+func (s *MemoryStore) GetDailyTagUsage(_ context.Context, filter DailyUsageFilter) ([]*DailyTagUsage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type tagKey struct{ date, tag string }
+	groups := make(map[tagKey]*DailyTagUsage)
+	result := []*DailyTagUsage{}
+	for _, log := range s.usageLogs {
+		date, ok := matchDailyFilter(log, filter)
+		if !ok {
+			continue
+		}
+		for _, tag := range log.RequestTags {
+			if filter.Tag != nil && tag != *filter.Tag {
+				continue
+			}
+			k := tagKey{date, tag}
+			usage, ok := groups[k]
+			if !ok {
+				usage = &DailyTagUsage{Date: date, Tag: tag}
+				groups[k] = usage
+				result = append(result, usage)
+			}
+			usage.InputTokens += int64(log.InputTokens)
+			usage.OutputTokens += int64(log.OutputTokens)
+			usage.Spend += log.Cost
+			usage.APIRequests++
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Date > result[j].Date })
+	return result, nil
+}
+
+// matchDailyFilter reports whether log matches filter and returns its UTC date.
+func matchDailyFilter(log *UsageLog, filter DailyUsageFilter) (string, bool) {
+	date := log.StartTime.UTC().Format("2006-01-02")
+	if filter.StartDate != "" && date < filter.StartDate {
+		return "", false
+	}
+	if filter.EndDate != "" && date > filter.EndDate {
+		return "", false
+	}
+	if filter.APIKeyID != nil && log.APIKeyID != *filter.APIKeyID {
+		return "", false
+	}
+	if filter.TeamID != nil && (log.TeamID == nil || *log.TeamID != *filter.TeamID) {
+		return "", false
+	}
+	if filter.Model != nil && log.Model != *filter.Model {
+		return "", false
+	}
+	if filter.Provider != nil && log.Provider != *filter.Provider {
+		return "", false
+	}
+	return date, true
 }
 
 // Budget operations
@@ -715,7 +826,19 @@ func (s *MemoryStore) GetEndUser(_ context.Context, userID string) (*EndUser, er
 	if !ok {
 		return nil, nil
 	}
-	return eu.Clone(), nil
+	return s.endUserWithBudget(eu), nil
+}
+
+// endUserWithBudget returns a copy of eu with its linked budget attached.
+// Callers must hold s.mu.
+func (s *MemoryStore) endUserWithBudget(eu *EndUser) *EndUser {
+	clone := eu.Clone()
+	if clone.BudgetID != nil {
+		if b, ok := s.budgets[*clone.BudgetID]; ok {
+			clone.Budget = b.Clone()
+		}
+	}
+	return clone
 }
 
 func (s *MemoryStore) CreateEndUser(_ context.Context, endUser *EndUser) error {
@@ -725,6 +848,13 @@ func (s *MemoryStore) CreateEndUser(_ context.Context, endUser *EndUser) error {
 	return nil
 }
 
+func (s *MemoryStore) UpdateEndUser(_ context.Context, endUser *EndUser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.endUsers[endUser.UserID] = endUser.Clone()
+	return nil
+}
+
 func (s *MemoryStore) UpdateEndUserSpent(_ context.Context, userID string, amount float64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -750,6 +880,30 @@ func (s *MemoryStore) DeleteEndUser(_ context.Context, userID string) error {
 	return nil
 }
 
+func (s *MemoryStore) ListEndUsers(_ context.Context, filter EndUserFilter) ([]*EndUser, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*EndUser, 0, len(s.endUsers))
+	for _, eu := range s.endUsers {
+		if filter.Blocked != nil && eu.Blocked != *filter.Blocked {
+			continue
+		}
+		result = append(result, s.endUserWithBudget(eu))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserID < result[j].UserID })
+
+	total := int64(len(result))
+	if filter.Offset >= len(result) {
+		return []*EndUser{}, total, nil
+	}
+	end := filter.Offset + filter.Limit
+	if end > len(result) || filter.Limit == 0 {
+		end = len(result)
+	}
+	return result[filter.Offset:end], total, nil
+}
+
 // Budget reset operations
 
 func (s *MemoryStore) GetKeysNeedingBudgetReset(_ context.Context) ([]*APIKey, error) {
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"time"
 )
@@ -28,6 +29,8 @@ type Middleware struct {
 	enabled                bool
 	lastUsedUpdateInterval time.Duration
 	enforcer               *CasbinEnforcer
+	trustedProxies         []*net.IPNet
+	auditLogger            *AuditLogger
 }
 
 // MiddlewareConfig contains configuration for the auth middleware.
@@ -38,6 +41,11 @@ type MiddlewareConfig struct {
 	Enabled                bool
 	LastUsedUpdateInterval time.Duration
 	Enforcer               *CasbinEnforcer
+	// TrustedProxyCIDRs lists proxies whose forwarding headers identify the
+	// client IP checked against a key's allowed_cidrs.
+	TrustedProxyCIDRs []string
+	// AuditLogger records requests rejected by allowed_cidrs (optional).
+	AuditLogger *AuditLogger
 }
 
 // NewMiddleware creates a new authentication middleware.
@@ -46,6 +54,7 @@ func NewMiddleware(cfg *MiddlewareConfig) *Middleware {
 	for _, path := range cfg.SkipPaths {
 		skipPaths[path] = true
 	}
+	trustedProxies, _ := parseTrustedProxyCIDRs(cfg.TrustedProxyCIDRs)
 
 	return &Middleware{
 		store:                  cfg.Store,
@@ -54,6 +63,8 @@ func NewMiddleware(cfg *MiddlewareConfig) *Middleware {
 		enabled:                cfg.Enabled,
 		lastUsedUpdateInterval: cfg.LastUsedUpdateInterval,
 		enforcer:               cfg.Enforcer,
+		trustedProxies:         trustedProxies,
+		auditLogger:            cfg.AuditLogger,
 	}
 }
 
@@ -72,9 +83,13 @@ func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
-		// If another auth mechanism already authenticated this request (e.g. OIDC),
-		// do not force API key authentication.
-		if GetAuthContext(r.Context()) != nil {
+		// If another auth mechanism already authenticated this request (e.g. OIDC
+		// or JWT), do not force API key authentication, but still bind the
+		// identity to its allowed sources and host.
+		if authCtx := GetAuthContext(r.Context()); authCtx != nil {
+			if !m.checkRequestSource(w, r, authCtx) {
+				return
+			}
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -136,6 +151,22 @@ func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 			}
 		}
 
+		// Create auth context
+		authCtx := &AuthContext{
+			APIKey: key,
+			Team:   team,
+		}
+		if !m.checkRequestSource(w, r, authCtx) {
+			return
+		}
+
+		// Key types bound the routes a key may reach regardless of policy, so a
+		// data-plane key never reaches admin routes.
+		if !key.KeyType.AllowsRoute(r.Method, r.URL.Path) {
+			m.writePermissionDenied(w, "api key type does not permit this route")
+			return
+		}
+
 		// Enforce permissions via Casbin if available.
 		if m.enforcer != nil {
 			sub := KeySub(key.ID)
@@ -155,16 +186,6 @@ func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 				m.writePermissionDenied(w, "access denied by policy")
 				return
 			}
-		} else if key.KeyType == KeyTypeReadOnly {
-			// Legacy hardcoded key type restrictions.
-			if r.Method != http.MethodGet && r.Method != http.MethodHead {
-				m.writePermissionDenied(w, "read-only key")
-				return
-			}
-			if r.URL.Path != "/v1/models" {
-				m.writePermissionDenied(w, "read-only key")
-				return
-			}
 		}
 
 		now := time.Now()
@@ -176,18 +197,64 @@ func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 			}
 		}
 
-		// Create auth context
-		authCtx := &AuthContext{
-			APIKey: key,
-			Team:   team,
-		}
-
 		// Add auth context to request context
 		ctx := context.WithValue(r.Context(), AuthContextKey, authCtx)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// checkRequestSource rejects requests whose key does not allow the client IP
+// or whose identity belongs to another organization than the host tenant.
+// It applies to every authentication method and writes the error response
+// when it returns false.
+func (m *Middleware) checkRequestSource(w http.ResponseWriter, r *http.Request, authCtx *AuthContext) bool {
+	tenant := HostTenantFromContext(r.Context())
+	if tenant != nil && authCtx.JWTOrgID != "" &&
+		tenant.OrganizationID != "" && authCtx.JWTOrgID != tenant.OrganizationID {
+		m.writePermissionDenied(w, "identity is not valid for this host")
+		return false
+	}
+
+	key := authCtx.APIKey
+	if key == nil {
+		return true
+	}
+	if sourceIP := requestClientIP(r, m.trustedProxies); !key.AllowsIP(sourceIP) {
+		m.auditIPRejected(r, key, sourceIP)
+		m.writePermissionDenied(w, "source ip is not allowed for this api key")
+		return false
+	}
+	if !hostTenantAllowsKey(tenant, key, authCtx.Team) {
+		m.writePermissionDenied(w, "api key is not valid for this host")
+		return false
+	}
+	return true
+}
+
+func (m *Middleware) auditIPRejected(r *http.Request, key *APIKey, sourceIP string) {
+	if m.auditLogger == nil {
+		return
+	}
+	err := m.auditLogger.Log(&AuditLog{
+		ID:         generateAuditID(),
+		Timestamp:  time.Now().UTC(),
+		ActorID:    key.ID,
+		ActorType:  "api_key",
+		ActorIP:    sourceIP,
+		Action:     AuditActionAPIKeyIPRejected,
+		ObjectType: AuditObjectAPIKey,
+		ObjectID:   key.ID,
+		TeamID:     key.TeamID,
+		UserAgent:  r.UserAgent(),
+		RequestURI: r.URL.Path,
+		Success:    false,
+		Error:      "source ip not in allowed_cidrs",
+	})
+	if err != nil {
+		m.logger.Warn("failed to audit rejected source ip", "error", err, "key_id", key.ID)
+	}
+}
+
 func (m *Middleware) shouldUpdateLastUsed(lastUsed *time.Time, now time.Time) bool {
 	if m.lastUsedUpdateInterval <= 0 {
 		return true
@@ -214,9 +281,17 @@ func (m *Middleware) writeUnauthorized(w http.ResponseWriter, message string) {
 }
 
 func (m *Middleware) writeError(w http.ResponseWriter, status int, message string) {
+	writeJSONError(w, status, "authentication_error", message)
+}
+
+// writeJSONError writes an OpenAI-style error body of errType.
+func writeJSONError(w http.ResponseWriter, status int, errType, message string) {
+	body, _ := json.Marshal(map[string]any{
+		"error": map[string]string{"message": message, "type": errType},
+	})
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	_, _ = w.Write([]byte(`{"error":{"message":"` + message + `","type":"authentication_error"}}`))
+	_, _ = w.Write(body)
 }
 
 func (m *Middleware) writePermissionDenied(w http.ResponseWriter, message string) {
@@ -249,6 +324,7 @@ func (m *Middleware) ModelAccessMiddleware(next http.Handler) http.Handler {
 			m.writeUnauthorized(w, "authentication required")
 			return
 		}
+		r = r.WithContext(WithModelAccessGroups(r.Context()))
 
 		origBody := r.Body
 		limitedBody := io.LimitReader(origBody, maxModelAccessBodyBytes+1)
@@ -289,7 +365,13 @@ func (m *Middleware) ModelAccessMiddleware(next http.Handler) http.Handler {
 			// In a real production system, these should be synced to the database
 			// or loaded via a custom adapter.
 			if len(authCtx.APIKey.AllowedModels) > 0 {
-				for _, am := range authCtx.APIKey.AllowedModels {
+				allowedModels, err := ExpandModelAccessGroups(r.Context(), m.store, authCtx.APIKey.AllowedModels)
+				if err != nil {
+					m.logger.Error("failed to expand model access groups", "error", err)
+					m.writeError(w, http.StatusInternalServerError, "internal error")
+					return
+				}
+				for _, am := range allowedModels {
 					_, _ = m.enforcer.AddPolicy(sub, ModelObj(am), act)
 				}
 			} else {
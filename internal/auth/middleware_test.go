@@ -279,6 +279,49 @@ func TestMiddleware_Authenticate_ReadOnlyKey_AllowsModelsButDeniesWrites(t *test
 	})
 }
 
+func TestMiddleware_Authenticate_LLMAPIKey_DeniedOnAdminRoutes(t *testing.T) {
+	store := NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	fullKey, hash, _ := GenerateAPIKey()
+	testKey := &APIKey{
+		ID:        "llm-key-id",
+		KeyHash:   hash,
+		KeyPrefix: ExtractKeyPrefix(fullKey),
+		Name:      "LLM API Key",
+		IsActive:  true,
+		KeyType:   KeyTypeLLMAPI,
+		CreatedAt: time.Now(),
+	}
+	if err := store.CreateAPIKey(context.Background(), testKey); err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+
+	middleware := NewMiddleware(&MiddlewareConfig{
+		Store:   store,
+		Logger:  logger,
+		Enabled: true,
+	})
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for path, want := range map[string]int{
+		"/v1/chat/completions": http.StatusOK,
+		"/key/generate":        http.StatusForbidden,
+		"/control/config":      http.StatusForbidden,
+	} {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer "+fullKey)
+		rr := httptest.NewRecorder()
+		middleware.Authenticate(okHandler).ServeHTTP(rr, req)
+		if rr.Code != want {
+			t.Errorf("%s: expected status %d, got %d", path, want, rr.Code)
+		}
+	}
+}
+
 func TestMiddleware_LastUsedUpdateWindow(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError}))
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -439,6 +482,78 @@ func TestMiddleware_ExpiredKey(t *testing.T) {
 	}
 }
 
+func TestMiddleware_AllowedCIDRs(t *testing.T) {
+	store := NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	auditStore := NewMemoryAuditLogStore()
+
+	fullKey, hash, _ := GenerateAPIKey()
+	testKey := &APIKey{
+		ID:           "cidr-key-id",
+		KeyHash:      hash,
+		KeyPrefix:    ExtractKeyPrefix(fullKey),
+		Name:         "CIDR Key",
+		AllowedCIDRs: []string{"10.0.0.0/8"},
+		IsActive:     true,
+		CreatedAt:    time.Now(),
+	}
+	store.CreateAPIKey(context.Background(), testKey)
+
+	middleware := NewMiddleware(&MiddlewareConfig{
+		Store:             store,
+		Logger:            logger,
+		Enabled:           true,
+		TrustedProxyCIDRs: []string{"127.0.0.1/32"},
+		AuditLogger:       NewAuditLogger(auditStore, true),
+	})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		wantStatus int
+	}{
+		{name: "allowed source", remoteAddr: "10.1.2.3:1234", wantStatus: http.StatusOK},
+		{name: "rejected source", remoteAddr: "192.168.1.1:1234", wantStatus: http.StatusForbidden},
+		{name: "forwarded by trusted proxy", remoteAddr: "127.0.0.1:1234", forwarded: "10.9.9.9", wantStatus: http.StatusOK},
+		{name: "forwarded by untrusted proxy", remoteAddr: "192.168.1.1:1234", forwarded: "10.9.9.9", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("Authorization", "Bearer "+fullKey)
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+
+			rr := httptest.NewRecorder()
+			middleware.Authenticate(handler).ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+
+	action := AuditActionAPIKeyIPRejected
+	logs, total, err := auditStore.ListAuditLogs(AuditLogFilter{Action: &action})
+	if err != nil {
+		t.Fatalf("ListAuditLogs() error = %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("expected 2 rejected source audit entries, got %d", total)
+	}
+	if logs[0].ObjectID != testKey.ID || logs[0].ActorIP != "192.168.1.1" {
+		t.Errorf("unexpected audit entry: object=%s ip=%s", logs[0].ObjectID, logs[0].ActorIP)
+	}
+}
+
 func TestMiddleware_InactiveKey(t *testing.T) {
 	store := NewMemoryStore()
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
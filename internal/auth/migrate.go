@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID is the Postgres advisory lock key held while migrating so
+// concurrently starting instances apply each migration once.
+const migrationLockID int64 = 0x6c6c6d7578 // "llmux"
+
+// legacySchemaVersion is 001_init.sql, a minimal schema superseded by 002.
+// It is recorded as applied without being executed.
+const legacySchemaVersion = 1
+
+// Migration is one embedded SQL migration.
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// Migrations returns the embedded migrations ordered by version.
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]Migration, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: name must start with a version", name)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version: %w", name, err)
+		}
+		data, err := migrationFiles.ReadFile(path.Join("migrations", name))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{Version: version, Name: name, SQL: string(data)})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrate applies pending embedded migrations in order and returns the
+// resulting schema version. It holds an advisory lock for the duration, so
+// instances starting together wait for the first one. Databases migrated by
+// hand before versions were recorded are baselined from their tables.
+func (s *PostgresStore) Migrate(ctx context.Context) (int, error) {
+	return migrate(ctx, s.db)
+}
+
+func migrate(ctx context.Context, db *sql.DB) (int, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0, fmt.Errorf("load migrations: %w", err)
+	}
+
+	// Advisory locks belong to a session, so lock and migrate on one connection.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("acquire connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return 0, fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+	}()
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		)`); err != nil {
+		return 0, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	current, err := recordedSchemaVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if current == 0 {
+		baseline, err := detectSchemaVersion(ctx, conn)
+		if err != nil {
+			return 0, err
+		}
+		for _, m := range migrations {
+			if m.Version > baseline {
+				break
+			}
+			if err := recordMigration(ctx, conn, m); err != nil {
+				return 0, err
+			}
+			current = m.Version
+		}
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		if err := applyMigration(ctx, conn, m); err != nil {
+			return current, err
+		}
+		current = m.Version
+	}
+	return current, nil
+}
+
+func applyMigration(ctx context.Context, conn *sql.Conn, m Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin migration %s: %w", m.Name, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if m.Version != legacySchemaVersion {
+		if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+			return fmt.Errorf("apply migration %s: %w", m.Name, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name); err != nil {
+		return fmt.Errorf("record migration %s: %w", m.Name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %s: %w", m.Name, err)
+	}
+	return nil
+}
+
+func recordMigration(ctx context.Context, conn *sql.Conn, m Migration) error {
+	if _, err := conn.ExecContext(ctx, `
+		INSERT INTO schema_migrations (version, name) VALUES ($1, $2)
+		ON CONFLICT (version) DO NOTHING`, m.Version, m.Name); err != nil {
+		return fmt.Errorf("record migration %s: %w", m.Name, err)
+	}
+	return nil
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// recordedSchemaVersion returns the highest version in schema_migrations, or
+// 0 when none is recorded.
+func recordedSchemaVersion(ctx context.Context, db queryRower) (int, error) {
+	var version int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("load schema version: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations_OrderedAndMatchSchemaVersions(t *testing.T) {
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	require.Len(t, migrations, len(schemaMigrations))
+	for i, m := range migrations {
+		require.Equal(t, schemaMigrations[i].version, m.Version, m.Name)
+		require.NotEmpty(t, m.SQL)
+	}
+	require.Equal(t, LatestSchemaVersion, migrations[len(migrations)-1].Version)
+}
+
+func expectMigrationPrelude(mock sqlmock.Sqlmock, recorded int) {
+	mock.ExpectExec(`SELECT pg_advisory_lock`).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(recorded))
+}
+
+func expectTables(mock sqlmock.Sqlmock, present int) {
+	for i, m := range schemaMigrations {
+		rows := sqlmock.NewRows([]string{"exists"}).AddRow(i < present)
+		if m.column != "" {
+			mock.ExpectQuery(`information_schema.columns`).WithArgs(m.table, m.column).WillReturnRows(rows)
+		} else {
+			mock.ExpectQuery(`SELECT to_regclass`).WithArgs(m.table).WillReturnRows(rows)
+		}
+		if i >= present {
+			return
+		}
+	}
+}
+
+func expectApply(mock sqlmock.Sqlmock, m Migration) {
+	mock.ExpectBegin()
+	if m.Version != legacySchemaVersion {
+		mock.ExpectExec(regexp.QuoteMeta(m.SQL)).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectExec(`INSERT INTO schema_migrations`).WithArgs(m.Version, m.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+func TestMigrate_FreshDatabaseAppliesAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrations, err := Migrations()
+	require.NoError(t, err)
+
+	expectMigrationPrelude(mock, 0)
+	expectTables(mock, 0)
+	for _, m := range migrations {
+		expectApply(mock, m)
+	}
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	version, err := (&PostgresStore{db: db}).Migrate(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, LatestSchemaVersion, version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrate_BaselinesHandMigratedDatabase(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrations, err := Migrations()
+	require.NoError(t, err)
+
+	expectMigrationPrelude(mock, 0)
+	expectTables(mock, 4)
+	for _, m := range migrations[:4] {
+		mock.ExpectExec(`INSERT INTO schema_migrations .* ON CONFLICT`).WithArgs(m.Version, m.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	for _, m := range migrations[4:] {
+		expectApply(mock, m)
+	}
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	version, err := migrate(context.Background(), db)
+	require.NoError(t, err)
+	require.Equal(t, LatestSchemaVersion, version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrate_UpToDateAppliesNothing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	expectMigrationPrelude(mock, LatestSchemaVersion)
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	version, err := migrate(context.Background(), db)
+	require.NoError(t, err)
+	require.Equal(t, LatestSchemaVersion, version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSchemaVersion_PrefersRecordedVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	mock.ExpectQuery(`SELECT to_regclass\('schema_migrations'\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+
+	version, err := (&PostgresStore{db: db}).SchemaVersion(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,13 @@
+-- LLMux Session Memory
+-- Conversation turns replayed for requests carrying X-Session-ID.
+
+CREATE TABLE IF NOT EXISTS session_turns (
+    id BIGSERIAL PRIMARY KEY,
+    session_id VARCHAR(512) NOT NULL,
+    messages JSONB NOT NULL,
+    tokens INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_turns_session_id ON session_turns(session_id, id);
+CREATE INDEX IF NOT EXISTS idx_session_turns_created_at ON session_turns(created_at);
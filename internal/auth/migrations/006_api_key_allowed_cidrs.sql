@@ -0,0 +1,4 @@
+-- LLMux API key IP allowlists
+-- Source IPs or CIDRs allowed to use a key; an empty list allows any source.
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS allowed_cidrs JSONB DEFAULT '[]';
@@ -0,0 +1,5 @@
+-- LLMux team seat limits
+-- Maximum members a team may hold; NULL or 0 means unlimited. Enforced when
+-- invitations are accepted.
+
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS max_seats INT;
@@ -0,0 +1,18 @@
+-- LLMux Payload Retention
+-- Full request and response payloads of teams opted in to payload logging,
+-- pruned after the configured retention.
+
+CREATE TABLE IF NOT EXISTS payload_logs (
+    request_id VARCHAR(255) PRIMARY KEY,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    api_key_id VARCHAR(255),
+    team_id VARCHAR(255),
+    model VARCHAR(255),
+    call_type VARCHAR(64),
+    status_code INT NOT NULL DEFAULT 0,
+    request JSONB,
+    response JSONB,
+    error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_payload_logs_timestamp ON payload_logs(timestamp);
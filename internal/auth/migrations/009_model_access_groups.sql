@@ -0,0 +1,11 @@
+-- LLMux Model Access Groups
+-- Named sets of models that keys, teams, users and organizations reference
+-- from their model lists.
+
+CREATE TABLE IF NOT EXISTS model_access_groups (
+    name VARCHAR(255) PRIMARY KEY,
+    models JSONB NOT NULL DEFAULT '[]',
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,10 @@
+-- LLMux Model Providers
+-- Provider deployments managed through the /model endpoints, served
+-- alongside the providers of the config file.
+
+CREATE TABLE IF NOT EXISTS model_providers (
+    name VARCHAR(255) PRIMARY KEY,
+    params JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,12 @@
+-- LLMux Config Versions
+-- Append-only history of the shared config document (providers, routing,
+-- governance) used with config.source: db. The highest version is active.
+
+CREATE TABLE IF NOT EXISTS config_versions (
+    version SERIAL PRIMARY KEY,
+    document TEXT NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    comment TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,15 @@
+-- LLMux Prompt Versions
+-- Append-only history of prompt templates managed through /v1/prompts and
+-- referenced by chat requests with prompt_id. The highest version of a
+-- prompt is served unless a request pins one.
+
+CREATE TABLE IF NOT EXISTS prompt_versions (
+    prompt_id VARCHAR(255) NOT NULL,
+    version INTEGER NOT NULL,
+    messages JSONB NOT NULL,
+    model VARCHAR(255),
+    comment TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (prompt_id, version)
+);
@@ -8,10 +8,13 @@ type ModelAccess struct {
 	team         *Team
 	user         *User
 	organization *Organization
+	// groups maps model access group names to their models.
+	groups map[string][]string
 }
 
 // NewModelAccess builds a model access evaluator from the auth context.
-// It loads user/org details from the store when IDs are present.
+// It loads user/org details from the store when IDs are present, and the
+// model access groups referenced by restricted scopes.
 func NewModelAccess(ctx context.Context, store Store, authCtx *AuthContext) (*ModelAccess, error) {
 	if authCtx == nil {
 		return nil, nil
@@ -47,25 +50,55 @@ func NewModelAccess(ctx context.Context, store Store, authCtx *AuthContext) (*Mo
 		access.organization = org
 	}
 
+	if access.restricted() {
+		groups, err := loadModelAccessGroups(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+		access.groups = groups
+	}
+
 	return access, nil
 }
 
+// restricted reports whether any scope limits the models it may use.
+func (a *ModelAccess) restricted() bool {
+	return (a.apiKey != nil && len(a.apiKey.AllowedModels) > 0) ||
+		(a.team != nil && len(a.team.Models) > 0) ||
+		(a.user != nil && len(a.user.Models) > 0) ||
+		(a.organization != nil && len(a.organization.Models) > 0)
+}
+
 // Allows returns true if all configured scopes allow the model.
 func (a *ModelAccess) Allows(model string) bool {
 	if a == nil || model == "" {
 		return true
 	}
-	if a.apiKey != nil && !a.apiKey.CanAccessModel(model) {
+	if a.apiKey != nil && !a.scopeAllows(a.apiKey.AllowedModels, model) {
 		return false
 	}
-	if a.team != nil && !a.team.CanAccessModel(model) {
+	if a.team != nil && !a.scopeAllows(a.team.Models, model) {
 		return false
 	}
-	if a.user != nil && !a.user.CanAccessModel(model) {
+	if a.user != nil && !a.scopeAllows(a.user.Models, model) {
 		return false
 	}
-	if a.organization != nil && !a.organization.CanAccessModel(model) {
+	if a.organization != nil && !a.scopeAllows(a.organization.Models, model) {
 		return false
 	}
 	return true
 }
+
+// scopeAllows checks model against a scope's list after expanding group
+// references. An empty list places no restriction.
+func (a *ModelAccess) scopeAllows(models []string, model string) bool {
+	if len(models) == 0 {
+		return true
+	}
+	for _, m := range expandModels(models, a.groups) {
+		if m == model || m == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// modelAccessGroupsContextKey is the context key for the groups loaded for
+// the current request.
+const modelAccessGroupsContextKey contextKey = "model_access_groups"
+
+// ModelAccessGroup is a named set of models. Keys, teams, users and
+// organizations reference a group by listing its name among their models;
+// the reference grants every model in the group.
+type ModelAccessGroup struct {
+	Name        string    `json:"name"`
+	Models      []string  `json:"models"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ModelAccessGroupStore persists model access groups. Stores implementing it
+// have group references expanded by NewModelAccess.
+type ModelAccessGroupStore interface {
+	// GetModelAccessGroup returns the named group, or nil when it does not exist.
+	GetModelAccessGroup(ctx context.Context, name string) (*ModelAccessGroup, error)
+	// SaveModelAccessGroup creates or replaces a group.
+	SaveModelAccessGroup(ctx context.Context, group *ModelAccessGroup) error
+	// DeleteModelAccessGroup removes a group.
+	DeleteModelAccessGroup(ctx context.Context, name string) error
+	// ListModelAccessGroups returns all groups ordered by name.
+	ListModelAccessGroups(ctx context.Context) ([]*ModelAccessGroup, error)
+}
+
+// ModelAccessGroups returns the group store backing store, if any.
+func ModelAccessGroups(store Store) (ModelAccessGroupStore, bool) {
+	if store == nil {
+		return nil, false
+	}
+	groups, ok := UnwrapStore(store).(ModelAccessGroupStore)
+	return groups, ok
+}
+
+// requestModelAccessGroups holds the groups loaded once for a request.
+type requestModelAccessGroups struct {
+	once   sync.Once
+	groups map[string][]string
+	err    error
+}
+
+// WithModelAccessGroups returns a context under which model access groups
+// are listed from the store at most once, so the checks of one request (the
+// model access middleware, governance, handlers) share a single load.
+func WithModelAccessGroups(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(modelAccessGroupsContextKey).(*requestModelAccessGroups); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, modelAccessGroupsContextKey, &requestModelAccessGroups{})
+}
+
+// loadModelAccessGroups returns the models of every group in store, keyed by
+// group name, reusing the groups already loaded for the request if any.
+func loadModelAccessGroups(ctx context.Context, store Store) (map[string][]string, error) {
+	loaded, ok := ctx.Value(modelAccessGroupsContextKey).(*requestModelAccessGroups)
+	if !ok {
+		return listModelAccessGroups(ctx, store)
+	}
+	loaded.once.Do(func() {
+		loaded.groups, loaded.err = listModelAccessGroups(ctx, store)
+	})
+	return loaded.groups, loaded.err
+}
+
+func listModelAccessGroups(ctx context.Context, store Store) (map[string][]string, error) {
+	groupStore, ok := ModelAccessGroups(store)
+	if !ok {
+		return nil, nil
+	}
+	groups, err := groupStore.ListModelAccessGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	out := make(map[string][]string, len(groups))
+	for _, g := range groups {
+		out[g.Name] = g.Models
+	}
+	return out, nil
+}
+
+// ExpandModelAccessGroups replaces group references in models with the
+// models of the group. Entries that name no group are kept as they are.
+func ExpandModelAccessGroups(ctx context.Context, store Store, models []string) ([]string, error) {
+	if len(models) == 0 {
+		return models, nil
+	}
+	groups, err := loadModelAccessGroups(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	return expandModels(models, groups), nil
+}
+
+func expandModels(models []string, groups map[string][]string) []string {
+	if len(groups) == 0 {
+		return models
+	}
+	out := make([]string, 0, len(models))
+	for _, m := range models {
+		if members, ok := groups[m]; ok {
+			out = append(out, members...)
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// GetModelAccessGroup implements ModelAccessGroupStore.
+func (s *MemoryStore) GetModelAccessGroup(_ context.Context, name string) (*ModelAccessGroup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	group, ok := s.modelGroups[name]
+	if !ok {
+		return nil, nil
+	}
+	return cloneModelAccessGroup(group), nil
+}
+
+// SaveModelAccessGroup implements ModelAccessGroupStore.
+func (s *MemoryStore) SaveModelAccessGroup(_ context.Context, group *ModelAccessGroup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.modelGroups[group.Name] = cloneModelAccessGroup(group)
+	return nil
+}
+
+// DeleteModelAccessGroup implements ModelAccessGroupStore.
+func (s *MemoryStore) DeleteModelAccessGroup(_ context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.modelGroups, name)
+	return nil
+}
+
+// ListModelAccessGroups implements ModelAccessGroupStore.
+func (s *MemoryStore) ListModelAccessGroups(_ context.Context) ([]*ModelAccessGroup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	groups := make([]*ModelAccessGroup, 0, len(s.modelGroups))
+	for _, g := range s.modelGroups {
+		groups = append(groups, cloneModelAccessGroup(g))
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+func cloneModelAccessGroup(g *ModelAccessGroup) *ModelAccessGroup {
+	clone := *g
+	clone.Models = append([]string(nil), g.Models...)
+	return &clone
+}
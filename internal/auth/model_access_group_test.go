@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewModelAccess_ExpandsAccessGroups(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, store.SaveModelAccessGroup(ctx, &ModelAccessGroup{
+		Name:   "cheap-models",
+		Models: []string{"gpt-4o-mini", "claude-3-haiku"},
+	}))
+	require.NoError(t, store.SaveModelAccessGroup(ctx, &ModelAccessGroup{
+		Name:   "gpt4-class",
+		Models: []string{"gpt-4o", "gpt-4o-mini"},
+	}))
+
+	// Groups are found behind the key cache wrapper.
+	cached, err := NewCachedStore(store, KeyCacheConfig{})
+	require.NoError(t, err)
+	access, err := NewModelAccess(ctx, cached, &AuthContext{
+		APIKey: &APIKey{ID: "key-1", AllowedModels: []string{"cheap-models", "o1"}},
+		Team:   &Team{ID: "team-1", Models: []string{"gpt4-class", "cheap-models", "o1"}},
+	})
+	require.NoError(t, err)
+	require.True(t, access.Allows("gpt-4o-mini"))
+	require.True(t, access.Allows("claude-3-haiku"))
+	require.True(t, access.Allows("o1"))
+	require.False(t, access.Allows("gpt-4o"), "key does not reference gpt4-class")
+
+	// Group changes apply to existing keys.
+	require.NoError(t, store.DeleteModelAccessGroup(ctx, "cheap-models"))
+	access, err = NewModelAccess(ctx, store, &AuthContext{
+		APIKey: &APIKey{ID: "key-1", AllowedModels: []string{"cheap-models"}},
+	})
+	require.NoError(t, err)
+	require.False(t, access.Allows("gpt-4o-mini"))
+	require.True(t, access.Allows("cheap-models"), "unknown names are matched literally")
+}
+
+// listCountingStore counts ListModelAccessGroups calls.
+type listCountingStore struct {
+	*MemoryStore
+	lists int
+}
+
+func (s *listCountingStore) ListModelAccessGroups(ctx context.Context) ([]*ModelAccessGroup, error) {
+	s.lists++
+	return s.MemoryStore.ListModelAccessGroups(ctx)
+}
+
+func TestModelAccessMiddleware_LoadsAccessGroupsOncePerRequest(t *testing.T) {
+	store := &listCountingStore{MemoryStore: NewMemoryStore()}
+	ctx := context.Background()
+	require.NoError(t, store.SaveModelAccessGroup(ctx, &ModelAccessGroup{
+		Name:   "cheap-models",
+		Models: []string{"gpt-4o-mini"},
+	}))
+	fullKey, hash, err := GenerateAPIKey()
+	require.NoError(t, err)
+	require.NoError(t, store.CreateAPIKey(ctx, &APIKey{
+		ID:            "key-1",
+		KeyHash:       hash,
+		KeyPrefix:     ExtractKeyPrefix(fullKey),
+		IsActive:      true,
+		AllowedModels: []string{"cheap-models"},
+	}))
+	middleware := NewMiddleware(&MiddlewareConfig{
+		Store:   store,
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Enabled: true,
+	})
+
+	// Later checks of the request, such as governance, reuse the groups.
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		access, err := NewModelAccess(r.Context(), store, GetAuthContext(r.Context()))
+		require.NoError(t, err)
+		require.True(t, access.Allows("gpt-4o-mini"))
+		w.WriteHeader(http.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(`{"model":"gpt-4o-mini"}`))
+	req.Header.Set("Authorization", "Bearer "+fullKey)
+	rr := httptest.NewRecorder()
+	middleware.Authenticate(middleware.ModelAccessMiddleware(handler)).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, 1, store.lists)
+}
+
+func TestPostgresModelAccessGroupStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	store := &PostgresStore{db: db}
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO model_access_groups`).
+		WithArgs("cheap-models", `["gpt-4o-mini"]`, nil, now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, store.SaveModelAccessGroup(ctx, &ModelAccessGroup{
+		Name: "cheap-models", Models: []string{"gpt-4o-mini"}, CreatedAt: now, UpdatedAt: now,
+	}))
+
+	columns := []string{"name", "models", "description", "created_at", "updated_at"}
+	mock.ExpectQuery(`SELECT .* FROM model_access_groups\s+ORDER BY name`).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("cheap-models", []byte(`["gpt-4o-mini"]`), nil, now, now).
+			AddRow("gpt4-class", []byte(`["gpt-4o"]`), "frontier", now, now))
+	groups, err := store.ListModelAccessGroups(ctx)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	require.Equal(t, []string{"gpt-4o"}, groups[1].Models)
+	require.Equal(t, "frontier", groups[1].Description)
+
+	mock.ExpectQuery(`SELECT .* FROM model_access_groups\s+WHERE name = \$1`).WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(columns))
+	group, err := store.GetModelAccessGroup(ctx, "missing")
+	require.NoError(t, err)
+	require.Nil(t, group)
+
+	mock.ExpectExec(`DELETE FROM model_access_groups WHERE name = \$1`).WithArgs("cheap-models").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, store.DeleteModelAccessGroup(ctx, "cheap-models"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
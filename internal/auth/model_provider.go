@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// ModelProvider is a provider deployment managed through the /model
+// endpoints instead of the config file. Its fields mirror the provider
+// entries of the config file; APIKey may be a secret reference.
+type ModelProvider struct {
+	Name                string            `json:"name"`
+	Type                string            `json:"type"`
+	APIKey              string            `json:"api_key"`
+	BaseURL             string            `json:"base_url,omitempty"`
+	AllowPrivateBaseURL bool              `json:"allow_private_base_url,omitempty"`
+	Models              []string          `json:"models"`
+	MaxConcurrent       int               `json:"max_concurrent,omitempty"`
+	Timeout             time.Duration     `json:"timeout,omitempty"`
+	Headers             map[string]string `json:"headers,omitempty"`
+	APIVersion          string            `json:"api_version,omitempty"`
+	RPM                 int64             `json:"rpm,omitempty"`
+	TPM                 int64             `json:"tpm,omitempty"`
+	Tags                []string          `json:"tags,omitempty"`
+	CreatedAt           time.Time         `json:"created_at"`
+	UpdatedAt           time.Time         `json:"updated_at"`
+}
+
+// ModelProviderStore persists providers managed through the API. The server
+// adds them to the providers of the config file whenever it builds its client.
+type ModelProviderStore interface {
+	// GetModelProvider returns the named provider, or nil when it does not exist.
+	GetModelProvider(ctx context.Context, name string) (*ModelProvider, error)
+	// SaveModelProvider creates or replaces a provider.
+	SaveModelProvider(ctx context.Context, provider *ModelProvider) error
+	// DeleteModelProvider removes a provider.
+	DeleteModelProvider(ctx context.Context, name string) error
+	// ListModelProviders returns all providers ordered by name.
+	ListModelProviders(ctx context.Context) ([]*ModelProvider, error)
+}
+
+// ModelProviders returns the provider store backing store, if any.
+func ModelProviders(store Store) (ModelProviderStore, bool) {
+	if store == nil {
+		return nil, false
+	}
+	providers, ok := UnwrapStore(store).(ModelProviderStore)
+	return providers, ok
+}
+
+// GetModelProvider implements ModelProviderStore.
+func (s *MemoryStore) GetModelProvider(_ context.Context, name string) (*ModelProvider, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	provider, ok := s.modelProviders[name]
+	if !ok {
+		return nil, nil
+	}
+	return cloneModelProvider(provider), nil
+}
+
+// SaveModelProvider implements ModelProviderStore.
+func (s *MemoryStore) SaveModelProvider(_ context.Context, provider *ModelProvider) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.modelProviders[provider.Name] = cloneModelProvider(provider)
+	return nil
+}
+
+// DeleteModelProvider implements ModelProviderStore.
+func (s *MemoryStore) DeleteModelProvider(_ context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.modelProviders, name)
+	return nil
+}
+
+// ListModelProviders implements ModelProviderStore.
+func (s *MemoryStore) ListModelProviders(_ context.Context) ([]*ModelProvider, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	providers := make([]*ModelProvider, 0, len(s.modelProviders))
+	for _, p := range s.modelProviders {
+		providers = append(providers, cloneModelProvider(p))
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Name < providers[j].Name })
+	return providers, nil
+}
+
+func cloneModelProvider(p *ModelProvider) *ModelProvider {
+	clone := *p
+	clone.Models = append([]string(nil), p.Models...)
+	clone.Tags = append([]string(nil), p.Tags...)
+	if p.Headers != nil {
+		clone.Headers = make(map[string]string, len(p.Headers))
+		for k, v := range p.Headers {
+			clone.Headers[k] = v
+		}
+	}
+	return &clone
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresModelProviderStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	store := &PostgresStore{db: db}
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO model_providers`).
+		WithArgs("azure-east", sqlmock.AnyArg(), now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, store.SaveModelProvider(ctx, &ModelProvider{
+		Name: "azure-east", Type: "azure", APIKey: "vault://llm/azure", Models: []string{"gpt-4o"},
+		CreatedAt: now, UpdatedAt: now,
+	}))
+
+	columns := []string{"name", "params", "created_at", "updated_at"}
+	mock.ExpectQuery(`SELECT .* FROM model_providers\s+ORDER BY name`).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("azure-east", []byte(`{"type":"azure","api_key":"vault://llm/azure","models":["gpt-4o"],"timeout":30000000000}`), now, now))
+	providers, err := store.ListModelProviders(ctx)
+	require.NoError(t, err)
+	require.Len(t, providers, 1)
+	require.Equal(t, "azure-east", providers[0].Name)
+	require.Equal(t, "azure", providers[0].Type)
+	require.Equal(t, 30*time.Second, providers[0].Timeout)
+	require.Equal(t, now, providers[0].CreatedAt)
+
+	mock.ExpectQuery(`SELECT .* FROM model_providers\s+WHERE name = \$1`).WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(columns))
+	provider, err := store.GetModelProvider(ctx, "missing")
+	require.NoError(t, err)
+	require.Nil(t, provider)
+
+	mock.ExpectExec(`DELETE FROM model_providers WHERE name = \$1`).WithArgs("azure-east").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, store.DeleteModelProvider(ctx, "azure-east"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
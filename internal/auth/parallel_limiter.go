@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ConcurrencyCounter tracks in-flight requests per key.
+type ConcurrencyCounter interface {
+	// Acquire takes a slot for key, reporting false when limit slots are
+	// already held.
+	Acquire(ctx context.Context, key string, limit int) (bool, error)
+	// Release returns a slot taken by Acquire.
+	Release(ctx context.Context, key string) error
+}
+
+// MemoryConcurrencyCounter is an in-process ConcurrencyCounter.
+type MemoryConcurrencyCounter struct {
+	mu       sync.Mutex
+	inFlight map[string]int
+}
+
+// NewMemoryConcurrencyCounter creates an in-process counter.
+func NewMemoryConcurrencyCounter() *MemoryConcurrencyCounter {
+	return &MemoryConcurrencyCounter{inFlight: make(map[string]int)}
+}
+
+// Acquire implements ConcurrencyCounter.
+func (c *MemoryConcurrencyCounter) Acquire(_ context.Context, key string, limit int) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.inFlight[key] >= limit {
+		return false, nil
+	}
+	c.inFlight[key]++
+	return true, nil
+}
+
+// Release implements ConcurrencyCounter.
+func (c *MemoryConcurrencyCounter) Release(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.inFlight[key] <= 1 {
+		delete(c.inFlight, key)
+		return nil
+	}
+	c.inFlight[key]--
+	return nil
+}
+
+// DefaultConcurrencySlotTTL bounds how long a Redis counter outlives its last
+// acquisition, so slots held by a crashed instance are eventually freed.
+const DefaultConcurrencySlotTTL = 10 * time.Minute
+
+var acquireSlotScript = redis.NewScript(`
+local current = redis.call("INCR", KEYS[1])
+if current > tonumber(ARGV[1]) then
+  redis.call("DECR", KEYS[1])
+  return 0
+end
+redis.call("PEXPIRE", KEYS[1], ARGV[2])
+return 1
+`)
+
+var releaseSlotScript = redis.NewScript(`
+local current = redis.call("DECR", KEYS[1])
+if current <= 0 then
+  redis.call("DEL", KEYS[1])
+end
+return current
+`)
+
+// RedisConcurrencyCounter shares in-flight counts across gateway instances.
+type RedisConcurrencyCounter struct {
+	client redis.UniversalClient
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisConcurrencyCounter creates a Redis-backed counter. Counters expire
+// ttl (default DefaultConcurrencySlotTTL) after the last acquisition.
+func NewRedisConcurrencyCounter(client redis.UniversalClient, prefix string, ttl time.Duration) *RedisConcurrencyCounter {
+	if prefix == "" {
+		prefix = "llmux:parallel:"
+	}
+	if ttl <= 0 {
+		ttl = DefaultConcurrencySlotTTL
+	}
+	return &RedisConcurrencyCounter{client: client, prefix: prefix, ttl: ttl}
+}
+
+// Acquire implements ConcurrencyCounter.
+func (c *RedisConcurrencyCounter) Acquire(ctx context.Context, key string, limit int) (bool, error) {
+	res, err := acquireSlotScript.Run(ctx, c.client, []string{c.prefix + key}, limit, c.ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+// Release implements ConcurrencyCounter.
+func (c *RedisConcurrencyCounter) Release(ctx context.Context, key string) error {
+	return releaseSlotScript.Run(ctx, c.client, []string{c.prefix + key}).Err()
+}
+
+// ParallelRequestLimiter enforces the max_parallel_requests of API keys and
+// teams.
+type ParallelRequestLimiter struct {
+	counter  ConcurrencyCounter
+	failOpen bool
+	logger   *slog.Logger
+}
+
+// ParallelRequestLimiterConfig configures a ParallelRequestLimiter.
+type ParallelRequestLimiterConfig struct {
+	Counter  ConcurrencyCounter // Defaults to an in-process counter
+	FailOpen bool               // Allow requests when the counter backend fails
+	Logger   *slog.Logger
+}
+
+// NewParallelRequestLimiter creates a parallel request limiter.
+func NewParallelRequestLimiter(cfg ParallelRequestLimiterConfig) *ParallelRequestLimiter {
+	if cfg.Counter == nil {
+		cfg.Counter = NewMemoryConcurrencyCounter()
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &ParallelRequestLimiter{counter: cfg.Counter, failOpen: cfg.FailOpen, logger: cfg.Logger}
+}
+
+// Middleware holds a slot for the authenticated key and its team while the
+// request is served, answering 429 when either is at its limit. It must run
+// after authentication.
+func (l *ParallelRequestLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authCtx := GetAuthContext(r.Context())
+		if authCtx == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var held []string
+		defer func() {
+			// Release even when the client has gone away.
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 2*time.Second)
+			defer cancel()
+			for _, key := range held {
+				if err := l.counter.Release(ctx, key); err != nil {
+					l.logger.Warn("failed to release parallel request slot", "key", key, "error", err)
+				}
+			}
+		}()
+
+		if team := authCtx.Team; team != nil && team.MaxParallelRequests != nil && *team.MaxParallelRequests > 0 {
+			key := "team:" + team.ID
+			if !l.acquire(r.Context(), key, *team.MaxParallelRequests, &held) {
+				writeParallelLimitExceeded(w, "team parallel request limit exceeded")
+				return
+			}
+		}
+		if key := authCtx.APIKey; key != nil && key.MaxParallelRequests != nil && *key.MaxParallelRequests > 0 {
+			if !l.acquire(r.Context(), "key:"+key.ID, *key.MaxParallelRequests, &held) {
+				writeParallelLimitExceeded(w, "parallel request limit exceeded")
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *ParallelRequestLimiter) acquire(ctx context.Context, key string, limit int, held *[]string) bool {
+	ok, err := l.counter.Acquire(ctx, key, limit)
+	if err != nil {
+		l.logger.Warn("parallel request limiter check failed", "key", key, "error", err, "fail_open", l.failOpen)
+		return l.failOpen
+	}
+	if ok {
+		*held = append(*held, key)
+	}
+	return ok
+}
+
+func writeParallelLimitExceeded(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", "1")
+	w.WriteHeader(http.StatusTooManyRequests)
+	_, _ = w.Write([]byte(`{"error":{"message":"` + message + `","type":"rate_limit_error"}}`))
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelRequestLimiter_Middleware(t *testing.T) {
+	limiter := NewParallelRequestLimiter(ParallelRequestLimiterConfig{})
+	one, two := 1, 2
+
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("block") != "" {
+			entered <- struct{}{}
+			<-unblock
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(authCtx *AuthContext, target string) int {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		req = req.WithContext(WithAuthContext(req.Context(), authCtx))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	keyA := &AuthContext{
+		APIKey: &APIKey{ID: "key-a", MaxParallelRequests: &one},
+		Team:   &Team{ID: "team-1", MaxParallelRequests: &two},
+	}
+	keyB := &AuthContext{
+		APIKey: &APIKey{ID: "key-b"},
+		Team:   &Team{ID: "team-1", MaxParallelRequests: &two},
+	}
+
+	done := make(chan int)
+	go func() { done <- serve(keyA, "/v1/chat/completions?block=1") }()
+	<-entered
+
+	// The key's only slot is held; the team still has room.
+	require.Equal(t, http.StatusTooManyRequests, serve(keyA, "/v1/chat/completions"))
+	require.Equal(t, http.StatusOK, serve(keyB, "/v1/chat/completions"))
+
+	go func() { done <- serve(keyB, "/v1/chat/completions?block=1") }()
+	<-entered
+	require.Equal(t, http.StatusTooManyRequests, serve(keyB, "/v1/chat/completions"))
+
+	close(unblock)
+	require.Equal(t, http.StatusOK, <-done)
+	require.Equal(t, http.StatusOK, <-done)
+
+	// Slots are returned once requests finish.
+	require.Equal(t, http.StatusOK, serve(keyA, "/v1/chat/completions"))
+	require.Equal(t, http.StatusOK, serve(nil, "/v1/chat/completions"))
+}
+
+func TestRedisConcurrencyCounter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	first := NewRedisConcurrencyCounter(client, "", 0)
+	second := NewRedisConcurrencyCounter(client, "", 0)
+
+	ok, err := first.Acquire(ctx, "key:a", 2)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = second.Acquire(ctx, "key:a", 2)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = first.Acquire(ctx, "key:a", 2)
+	require.NoError(t, err)
+	require.False(t, ok, "slots are shared across instances")
+
+	require.NoError(t, second.Release(ctx, "key:a"))
+	ok, err = first.Acquire(ctx, "key:a", 2)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, first.Release(ctx, "key:a"))
+	require.NoError(t, first.Release(ctx, "key:a"))
+	require.False(t, server.Exists("llmux:parallel:key:a"))
+}
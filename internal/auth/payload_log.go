@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// ============================================================================
+// Payload Retention
+// ============================================================================
+
+// DefaultPayloadRetention is how long payloads are kept when no retention is
+// configured.
+const DefaultPayloadRetention = 30 * 24 * time.Hour
+
+// PayloadLog is the full request and response of one request, retained for
+// debugging and compliance review.
+type PayloadLog struct {
+	RequestID  string          `json:"request_id"`
+	Timestamp  time.Time       `json:"timestamp"`
+	APIKeyID   string          `json:"api_key_id,omitempty"`
+	TeamID     string          `json:"team_id,omitempty"`
+	Model      string          `json:"model,omitempty"`
+	CallType   string          `json:"call_type,omitempty"`
+	StatusCode int             `json:"status_code"`
+	Request    json.RawMessage `json:"request"`
+	Response   json.RawMessage `json:"response,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// PayloadLogStore persists retained payloads.
+type PayloadLogStore interface {
+	// SavePayloadLog stores a payload log, replacing one with the same
+	// request ID.
+	SavePayloadLog(ctx context.Context, log *PayloadLog) error
+	// GetPayloadLog returns the payload log of a request, or nil when there
+	// is none.
+	GetPayloadLog(ctx context.Context, requestID string) (*PayloadLog, error)
+	// DeletePayloadLogs deletes payload logs recorded before olderThan and
+	// returns how many were deleted.
+	DeletePayloadLogs(ctx context.Context, olderThan time.Time) (int64, error)
+}
+
+// PayloadLoggerConfig configures which payloads are retained and how.
+type PayloadLoggerConfig struct {
+	// Teams opts teams in to payload retention; "*" retains every request,
+	// including those without a team.
+	Teams []string
+	// Retention is how long payloads are kept (default DefaultPayloadRetention).
+	Retention time.Duration
+	// Redact rewrites every string in retained payloads, e.g. to mask
+	// secrets and personal data. Nil keeps payloads as sent.
+	Redact func(string) string
+}
+
+// PayloadLogger retains the payloads of opted-in teams.
+type PayloadLogger struct {
+	store     PayloadLogStore
+	teams     map[string]bool
+	allTeams  bool
+	retention time.Duration
+	redact    func(string) string
+	now       func() time.Time
+}
+
+// NewPayloadLogger creates a payload logger writing to store.
+func NewPayloadLogger(store PayloadLogStore, cfg PayloadLoggerConfig) *PayloadLogger {
+	l := &PayloadLogger{
+		store:     store,
+		teams:     make(map[string]bool, len(cfg.Teams)),
+		retention: cfg.Retention,
+		redact:    cfg.Redact,
+		now:       time.Now,
+	}
+	if l.retention <= 0 {
+		l.retention = DefaultPayloadRetention
+	}
+	for _, team := range cfg.Teams {
+		if team == "*" {
+			l.allTeams = true
+		}
+		l.teams[team] = true
+	}
+	return l
+}
+
+// Store returns the store payloads are written to.
+func (l *PayloadLogger) Store() PayloadLogStore {
+	return l.store
+}
+
+// Enabled reports whether payloads of teamID are retained.
+func (l *PayloadLogger) Enabled(teamID string) bool {
+	if l == nil {
+		return false
+	}
+	return l.allTeams || (teamID != "" && l.teams[teamID])
+}
+
+// Record redacts and stores log when its team has opted in.
+func (l *PayloadLogger) Record(ctx context.Context, log *PayloadLog) error {
+	if !l.Enabled(log.TeamID) {
+		return nil
+	}
+	if log.Timestamp.IsZero() {
+		log.Timestamp = l.now().UTC()
+	}
+	if l.redact != nil {
+		log.Request = redactJSON(log.Request, l.redact)
+		log.Response = redactJSON(log.Response, l.redact)
+		log.Error = l.redact(log.Error)
+	}
+	return l.store.SavePayloadLog(ctx, log)
+}
+
+// Prune deletes payloads older than the retention period.
+func (l *PayloadLogger) Prune(ctx context.Context) (int64, error) {
+	return l.store.DeletePayloadLogs(ctx, l.now().Add(-l.retention))
+}
+
+// PayloadRetentionJob returns a job that prunes expired payloads.
+func (l *PayloadLogger) PayloadRetentionJob() Job {
+	return Job{
+		Name: "payload_retention",
+		Run: func(ctx context.Context) error {
+			_, err := l.Prune(ctx)
+			return err
+		},
+	}
+}
+
+// redactJSON applies redact to every string in a JSON document. Documents
+// that are not valid JSON are redacted as a whole and stored as a string.
+func redactJSON(raw json.RawMessage, redact func(string) string) json.RawMessage {
+	if len(raw) == 0 {
+		return raw
+	}
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		out, _ := json.Marshal(redact(string(raw)))
+		return out
+	}
+	out, err := json.Marshal(redactValue(doc, redact))
+	if err != nil {
+		return raw
+	}
+	return out
+}
+
+func redactValue(v any, redact func(string) string) any {
+	switch v := v.(type) {
+	case string:
+		return redact(v)
+	case map[string]any:
+		for k, item := range v {
+			v[k] = redactValue(item, redact)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item, redact)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// MemoryPayloadLogStore keeps payload logs in memory.
+type MemoryPayloadLogStore struct {
+	mu   sync.RWMutex
+	logs map[string]*PayloadLog
+}
+
+// NewMemoryPayloadLogStore creates an in-memory payload log store.
+func NewMemoryPayloadLogStore() *MemoryPayloadLogStore {
+	return &MemoryPayloadLogStore{logs: make(map[string]*PayloadLog)}
+}
+
+// SavePayloadLog implements PayloadLogStore.
+func (s *MemoryPayloadLogStore) SavePayloadLog(_ context.Context, log *PayloadLog) error {
+	logCopy := *log
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs[log.RequestID] = &logCopy
+	return nil
+}
+
+// GetPayloadLog implements PayloadLogStore.
+func (s *MemoryPayloadLogStore) GetPayloadLog(_ context.Context, requestID string) (*PayloadLog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	log, ok := s.logs[requestID]
+	if !ok {
+		return nil, nil
+	}
+	logCopy := *log
+	return &logCopy, nil
+}
+
+// DeletePayloadLogs implements PayloadLogStore.
+func (s *MemoryPayloadLogStore) DeletePayloadLogs(_ context.Context, olderThan time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var deleted int64
+	for id, log := range s.logs {
+		if log.Timestamp.Before(olderThan) {
+			delete(s.logs, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/goccy/go-json"
+)
+
+// ObjectStorePayloadLogStoreConfig configures payload retention in S3 or GCS.
+// GCS is reached through its S3-compatible XML API with HMAC credentials.
+type ObjectStorePayloadLogStoreConfig struct {
+	Provider        string // "s3" (default) or "gcs"
+	Bucket          string
+	Region          string
+	Endpoint        string
+	AccessKeyID     string // Uses the default credential chain when empty
+	SecretAccessKey string
+	PathPrefix      string
+}
+
+// ObjectStorePayloadLogStore keeps each payload log as the JSON object
+// <prefix>/<request id>.json. Expired objects are found by their last
+// modified time, so a bucket lifecycle rule may prune them instead.
+type ObjectStorePayloadLogStore struct {
+	cfg    ObjectStorePayloadLogStoreConfig
+	client *s3.Client
+}
+
+// NewObjectStorePayloadLogStore creates an object store payload log store.
+func NewObjectStorePayloadLogStore(ctx context.Context, cfg ObjectStorePayloadLogStoreConfig) (*ObjectStorePayloadLogStore, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("payload logs: bucket is required")
+	}
+	client, err := newObjectStoreClient(ctx, cfg.Provider, cfg.Region, cfg.Endpoint, cfg.AccessKeyID, cfg.SecretAccessKey)
+	if err != nil {
+		return nil, fmt.Errorf("payload logs: %w", err)
+	}
+	return &ObjectStorePayloadLogStore{cfg: cfg, client: client}, nil
+}
+
+func (s *ObjectStorePayloadLogStore) objectKey(requestID string) string {
+	return path.Join(s.cfg.PathPrefix, path.Base("/"+requestID)+".json")
+}
+
+// SavePayloadLog implements PayloadLogStore.
+func (s *ObjectStorePayloadLogStore) SavePayloadLog(ctx context.Context, log *PayloadLog) error {
+	data, err := json.Marshal(log)
+	if err != nil {
+		return fmt.Errorf("encode payload log: %w", err)
+	}
+	if _, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.cfg.Bucket),
+		Key:         aws.String(s.objectKey(log.RequestID)),
+		ContentType: aws.String("application/json"),
+		Body:        bytes.NewReader(data),
+	}); err != nil {
+		return fmt.Errorf("put payload log: %w", err)
+	}
+	return nil
+}
+
+// GetPayloadLog implements PayloadLogStore.
+func (s *ObjectStorePayloadLogStore) GetPayloadLog(ctx context.Context, requestID string) (*PayloadLog, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.cfg.Bucket),
+		Key:    aws.String(s.objectKey(requestID)),
+	})
+	var noSuchKey *s3types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get payload log: %w", err)
+	}
+	defer func() { _ = out.Body.Close() }()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read payload log: %w", err)
+	}
+	var log PayloadLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("decode payload log: %w", err)
+	}
+	return &log, nil
+}
+
+// DeletePayloadLogs implements PayloadLogStore. Objects are deleted one at a
+// time because the GCS XML API has no batch delete.
+func (s *ObjectStorePayloadLogStore) DeletePayloadLogs(ctx context.Context, olderThan time.Time) (int64, error) {
+	prefix := s.cfg.PathPrefix
+	if prefix != "" {
+		prefix = path.Clean(prefix) + "/"
+	}
+	pages := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.cfg.Bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	var deleted int64
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return deleted, fmt.Errorf("list payload logs: %w", err)
+		}
+		for _, obj := range page.Contents {
+			if obj.LastModified == nil || !obj.LastModified.Before(olderThan) {
+				continue
+			}
+			if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+				Bucket: aws.String(s.cfg.Bucket),
+				Key:    obj.Key,
+			}); err != nil {
+				return deleted, fmt.Errorf("delete payload log: %w", err)
+			}
+			deleted++
+		}
+	}
+	return deleted, nil
+}
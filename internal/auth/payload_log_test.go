@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadLogger_OptInAndRedaction(t *testing.T) {
+	store := NewMemoryPayloadLogStore()
+	logger := NewPayloadLogger(store, PayloadLoggerConfig{
+		Teams:  []string{"team-a"},
+		Redact: func(s string) string { return strings.ReplaceAll(s, "secret", "[REDACTED]") },
+	})
+	ctx := context.Background()
+
+	require.False(t, logger.Enabled(""))
+	require.False(t, logger.Enabled("team-b"))
+	require.True(t, logger.Enabled("team-a"))
+
+	require.NoError(t, logger.Record(ctx, &PayloadLog{RequestID: "req-b", TeamID: "team-b", Request: []byte(`{}`)}))
+	got, err := store.GetPayloadLog(ctx, "req-b")
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	require.NoError(t, logger.Record(ctx, &PayloadLog{
+		RequestID: "req-a",
+		TeamID:    "team-a",
+		Request:   []byte(`{"messages":[{"role":"user","content":"my secret"}],"n":1}`),
+		Response:  []byte(`{"choices":[{"message":{"content":"the secret is safe"}}]}`),
+	}))
+	got, err = store.GetPayloadLog(ctx, "req-a")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.False(t, got.Timestamp.IsZero())
+	require.JSONEq(t, `{"messages":[{"role":"user","content":"my [REDACTED]"}],"n":1}`, string(got.Request))
+	require.JSONEq(t, `{"choices":[{"message":{"content":"the [REDACTED] is safe"}}]}`, string(got.Response))
+
+	all := NewPayloadLogger(store, PayloadLoggerConfig{Teams: []string{"*"}})
+	require.True(t, all.Enabled(""))
+}
+
+func TestPayloadLogger_Prune(t *testing.T) {
+	store := NewMemoryPayloadLogStore()
+	logger := NewPayloadLogger(store, PayloadLoggerConfig{Teams: []string{"*"}, Retention: time.Hour})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	logger.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, logger.Record(ctx, &PayloadLog{RequestID: "old", Timestamp: now.Add(-2 * time.Hour)}))
+	require.NoError(t, logger.Record(ctx, &PayloadLog{RequestID: "new"}))
+
+	deleted, err := logger.Prune(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+	old, _ := store.GetPayloadLog(ctx, "old")
+	require.Nil(t, old)
+	recent, _ := store.GetPayloadLog(ctx, "new")
+	require.NotNil(t, recent)
+}
+
+func TestPostgresPayloadLogStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	store := &PostgresStore{db: db}
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO payload_logs`).
+		WithArgs("req-1", now, "key-1", "team-a", "gpt-4", "chat_completion", 200, `{"a":1}`, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, store.SavePayloadLog(ctx, &PayloadLog{
+		RequestID: "req-1", Timestamp: now, APIKeyID: "key-1", TeamID: "team-a",
+		Model: "gpt-4", CallType: "chat_completion", StatusCode: 200, Request: []byte(`{"a":1}`),
+	}))
+
+	mock.ExpectQuery(`SELECT .* FROM payload_logs`).WithArgs("req-1").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"request_id", "timestamp", "api_key_id", "team_id", "model", "call_type",
+			"status_code", "request", "response", "error",
+		}).AddRow("req-1", now, "key-1", "team-a", "gpt-4", "chat_completion", 200, []byte(`{"a":1}`), nil, nil))
+	got, err := store.GetPayloadLog(ctx, "req-1")
+	require.NoError(t, err)
+	require.Equal(t, "team-a", got.TeamID)
+	require.JSONEq(t, `{"a":1}`, string(got.Request))
+
+	mock.ExpectQuery(`SELECT .* FROM payload_logs`).WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"request_id"}))
+	got, err = store.GetPayloadLog(ctx, "missing")
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	mock.ExpectExec(`DELETE FROM payload_logs WHERE timestamp < \$1`).WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	deleted, err := store.DeletePayloadLogs(ctx, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), deleted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -44,6 +44,15 @@ func DefaultPostgresConfig() *PostgresConfig {
 
 // NewPostgresStore creates a new PostgreSQL store.
 func NewPostgresStore(cfg *PostgresConfig) (*PostgresStore, error) {
+	db, err := OpenPostgres(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &PostgresStore{db: db}, nil
+}
+
+// OpenPostgres opens a connection pool with cfg and verifies connectivity.
+func OpenPostgres(cfg *PostgresConfig) (*sql.DB, error) {
 	dsn := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database, cfg.SSLMode,
@@ -62,10 +71,11 @@ func NewPostgresStore(cfg *PostgresConfig) (*PostgresStore, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
-	return &PostgresStore{db: db}, nil
+	return db, nil
 }
 
 // Ping checks database connectivity.
@@ -89,14 +99,15 @@ func (s *PostgresStore) GetAPIKeyByHash(ctx context.Context, hash string) (*APIK
 		SELECT id, key_hash, key_prefix, name, key_alias, team_id, user_id, organization_id,
 		       allowed_models, tpm_limit, rpm_limit, max_budget, soft_budget, spent_budget,
 		       model_max_budget, model_spend, budget_duration, budget_reset_at,
-		       metadata, created_at, updated_at, expires_at, last_used_at, is_active, blocked
+		       metadata, created_at, updated_at, expires_at, last_used_at, is_active, blocked, allowed_cidrs,
+		       key_type, max_parallel_requests
 		FROM api_keys
 		WHERE key_hash = $1`
 
 	var key APIKey
-	var allowedModels, modelMaxBudget, modelSpend, metadataJSON sql.NullString
-	var keyAlias, teamID, userID, orgID sql.NullString
-	var tpmLimit, rpmLimit sql.NullInt64
+	var allowedModels, allowedCIDRs, modelMaxBudget, modelSpend, metadataJSON sql.NullString
+	var keyAlias, teamID, userID, orgID, keyType sql.NullString
+	var tpmLimit, rpmLimit, maxParallel sql.NullInt64
 	var softBudget sql.NullFloat64
 	var budgetDuration sql.NullString
 	var budgetResetAt, expiresAt, lastUsedAt sql.NullTime
@@ -107,7 +118,7 @@ func (s *PostgresStore) GetAPIKeyByHash(ctx context.Context, hash string) (*APIK
 		&key.MaxBudget, &softBudget, &key.SpentBudget,
 		&modelMaxBudget, &modelSpend, &budgetDuration, &budgetResetAt,
 		&metadataJSON, &key.CreatedAt, &key.UpdatedAt, &expiresAt, &lastUsedAt,
-		&key.IsActive, &key.Blocked,
+		&key.IsActive, &key.Blocked, &allowedCIDRs, &keyType, &maxParallel,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -120,6 +131,9 @@ func (s *PostgresStore) GetAPIKeyByHash(ctx context.Context, hash string) (*APIK
 	if keyAlias.Valid {
 		key.KeyAlias = &keyAlias.String
 	}
+	if keyType.Valid {
+		key.KeyType = KeyType(keyType.String)
+	}
 	if teamID.Valid {
 		key.TeamID = &teamID.String
 	}
@@ -135,6 +149,10 @@ func (s *PostgresStore) GetAPIKeyByHash(ctx context.Context, hash string) (*APIK
 	if rpmLimit.Valid {
 		key.RPMLimit = &rpmLimit.Int64
 	}
+	if maxParallel.Valid {
+		parallel := int(maxParallel.Int64)
+		key.MaxParallelRequests = &parallel
+	}
 	if softBudget.Valid {
 		key.SoftBudget = &softBudget.Float64
 	}
@@ -158,6 +176,11 @@ func (s *PostgresStore) GetAPIKeyByHash(ctx context.Context, hash string) (*APIK
 			key.AllowedModels = nil
 		}
 	}
+	if allowedCIDRs.Valid && allowedCIDRs.String != "" {
+		if err := json.Unmarshal([]byte(allowedCIDRs.String), &key.AllowedCIDRs); err != nil {
+			key.AllowedCIDRs = nil
+		}
+	}
 	if modelMaxBudget.Valid && modelMaxBudget.String != "" {
 		if err := json.Unmarshal([]byte(modelMaxBudget.String), &key.ModelMaxBudget); err != nil {
 			key.ModelMaxBudget = nil
@@ -195,13 +218,18 @@ func (s *PostgresStore) CreateAPIKey(ctx context.Context, key *APIKey) error {
 	if err != nil {
 		metadataJSON = []byte("{}")
 	}
+	allowedCIDRsJSON, err := json.Marshal(key.AllowedCIDRs)
+	if err != nil {
+		allowedCIDRsJSON = []byte("[]")
+	}
 
 	query := `
 		INSERT INTO api_keys (id, key_hash, key_prefix, name, key_alias, team_id, user_id, organization_id,
 		                      allowed_models, tpm_limit, rpm_limit, max_budget, soft_budget, spent_budget,
 		                      model_max_budget, model_spend, budget_duration, budget_reset_at,
-		                      metadata, created_at, updated_at, expires_at, is_active, blocked)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)`
+		                      metadata, created_at, updated_at, expires_at, is_active, blocked, allowed_cidrs,
+		                      key_type, max_parallel_requests)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27)`
 
 	_, err = s.db.ExecContext(ctx, query,
 		key.ID, key.KeyHash, key.KeyPrefix, key.Name, key.KeyAlias,
@@ -211,7 +239,8 @@ func (s *PostgresStore) CreateAPIKey(ctx context.Context, key *APIKey) error {
 		string(modelMaxBudgetJSON), string(modelSpendJSON),
 		string(key.BudgetDuration), key.BudgetResetAt,
 		string(metadataJSON), key.CreatedAt, key.UpdatedAt, key.ExpiresAt,
-		key.IsActive, key.Blocked,
+		key.IsActive, key.Blocked, string(allowedCIDRsJSON),
+		string(key.KeyType), key.MaxParallelRequests,
 	)
 	if err != nil {
 		return fmt.Errorf("insert api key: %w", err)
@@ -244,7 +273,8 @@ func (s *PostgresStore) DeleteAPIKey(ctx context.Context, keyID string) error {
 func (s *PostgresStore) ListAPIKeys(ctx context.Context, filter APIKeyFilter) ([]*APIKey, int64, error) {
 	query := `
 		SELECT id, key_prefix, name, team_id, user_id, organization_id, tpm_limit, rpm_limit, max_budget, 
-		       spent_budget, created_at, expires_at, last_used_at, is_active, blocked
+		       spent_budget, created_at, expires_at, last_used_at, is_active, blocked, key_type,
+		       max_parallel_requests, metadata
 		FROM api_keys
 		WHERE 1=1`
 	countQuery := `SELECT COUNT(*) FROM api_keys WHERE 1=1`
@@ -310,14 +340,15 @@ func (s *PostgresStore) ListAPIKeys(ctx context.Context, filter APIKeyFilter) ([
 	var keys []*APIKey
 	for rows.Next() {
 		var key APIKey
-		var teamIDVal, userIDVal, orgIDVal sql.NullString
-		var tpmLimit, rpmLimit sql.NullInt64
+		var teamIDVal, userIDVal, orgIDVal, keyType, metadataJSON sql.NullString
+		var tpmLimit, rpmLimit, maxParallel sql.NullInt64
 		var expiresAt, lastUsedAt sql.NullTime
 
 		if err := rows.Scan(
 			&key.ID, &key.KeyPrefix, &key.Name, &teamIDVal, &userIDVal, &orgIDVal,
 			&tpmLimit, &rpmLimit, &key.MaxBudget, &key.SpentBudget,
-			&key.CreatedAt, &expiresAt, &lastUsedAt, &key.IsActive, &key.Blocked,
+			&key.CreatedAt, &expiresAt, &lastUsedAt, &key.IsActive, &key.Blocked, &keyType,
+			&maxParallel, &metadataJSON,
 		); err != nil {
 			return nil, 0, fmt.Errorf("scan api key: %w", err)
 		}
@@ -325,6 +356,9 @@ func (s *PostgresStore) ListAPIKeys(ctx context.Context, filter APIKeyFilter) ([
 		if teamIDVal.Valid {
 			key.TeamID = &teamIDVal.String
 		}
+		if keyType.Valid {
+			key.KeyType = KeyType(keyType.String)
+		}
 		if userIDVal.Valid {
 			key.UserID = &userIDVal.String
 		}
@@ -337,12 +371,21 @@ func (s *PostgresStore) ListAPIKeys(ctx context.Context, filter APIKeyFilter) ([
 		if rpmLimit.Valid {
 			key.RPMLimit = &rpmLimit.Int64
 		}
+		if maxParallel.Valid {
+			parallel := int(maxParallel.Int64)
+			key.MaxParallelRequests = &parallel
+		}
 		if expiresAt.Valid {
 			key.ExpiresAt = &expiresAt.Time
 		}
 		if lastUsedAt.Valid {
 			key.LastUsedAt = &lastUsedAt.Time
 		}
+		if metadataJSON.Valid && metadataJSON.String != "" {
+			if err := json.Unmarshal([]byte(metadataJSON.String), &key.Metadata); err != nil {
+				key.Metadata = nil
+			}
+		}
 		keys = append(keys, &key)
 	}
 	return keys, total, rows.Err()
@@ -352,19 +395,20 @@ func (s *PostgresStore) ListAPIKeys(ctx context.Context, filter APIKeyFilter) ([
 func (s *PostgresStore) GetTeam(ctx context.Context, teamID string) (*Team, error) {
 	query := `
 		SELECT id, team_alias, organization_id, max_budget, spend, 
-		       tpm_limit, rpm_limit, models, metadata, created_at, updated_at, is_active, blocked
+		       tpm_limit, rpm_limit, models, metadata, created_at, updated_at, is_active, blocked, max_seats,
+		       max_parallel_requests
 		FROM teams
 		WHERE id = $1`
 
 	var team Team
 	var alias, orgID sql.NullString
-	var tpmLimit, rpmLimit sql.NullInt64
+	var tpmLimit, rpmLimit, maxSeats, maxParallel sql.NullInt64
 	var models, metadataJSON sql.NullString
 
 	err := s.db.QueryRowContext(ctx, query, teamID).Scan(
 		&team.ID, &alias, &orgID, &team.MaxBudget, &team.SpentBudget,
 		&tpmLimit, &rpmLimit, &models, &metadataJSON,
-		&team.CreatedAt, &team.UpdatedAt, &team.IsActive, &team.Blocked,
+		&team.CreatedAt, &team.UpdatedAt, &team.IsActive, &team.Blocked, &maxSeats, &maxParallel,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -385,6 +429,14 @@ func (s *PostgresStore) GetTeam(ctx context.Context, teamID string) (*Team, erro
 	if rpmLimit.Valid {
 		team.RPMLimit = &rpmLimit.Int64
 	}
+	if maxSeats.Valid {
+		seats := int(maxSeats.Int64)
+		team.MaxSeats = &seats
+	}
+	if maxParallel.Valid {
+		parallel := int(maxParallel.Int64)
+		team.MaxParallelRequests = &parallel
+	}
 	if models.Valid && models.String != "" {
 		if err := json.Unmarshal([]byte(models.String), &team.Models); err != nil {
 			team.Models = nil
@@ -412,13 +464,15 @@ func (s *PostgresStore) CreateTeam(ctx context.Context, team *Team) error {
 
 	query := `
 		INSERT INTO teams (id, team_alias, organization_id, max_budget, spend, 
-		                   tpm_limit, rpm_limit, models, metadata, created_at, updated_at, is_active, blocked)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+		                   tpm_limit, rpm_limit, models, metadata, created_at, updated_at, is_active, blocked, max_seats,
+		                   max_parallel_requests)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
 
 	_, err = s.db.ExecContext(ctx, query,
 		team.ID, team.Alias, team.OrganizationID, team.MaxBudget, team.SpentBudget,
 		team.TPMLimit, team.RPMLimit, string(modelsJSON), string(metadataJSON),
-		team.CreatedAt, team.UpdatedAt, team.IsActive, team.Blocked,
+		team.CreatedAt, team.UpdatedAt, team.IsActive, team.Blocked, team.MaxSeats,
+		team.MaxParallelRequests,
 	)
 	return err
 }
@@ -440,7 +494,8 @@ func (s *PostgresStore) DeleteTeam(ctx context.Context, teamID string) error {
 // ListTeams returns teams with pagination.
 func (s *PostgresStore) ListTeams(ctx context.Context, filter TeamFilter) ([]*Team, int64, error) {
 	query := `
-		SELECT id, team_alias, organization_id, max_budget, spend, tpm_limit, rpm_limit, created_at, is_active, blocked
+		SELECT id, team_alias, organization_id, max_budget, spend, tpm_limit, rpm_limit, created_at, is_active, blocked, max_seats,
+		       max_parallel_requests
 		FROM teams
 		WHERE 1=1`
 	countQuery := `SELECT COUNT(*) FROM teams WHERE 1=1`
@@ -495,10 +550,10 @@ func (s *PostgresStore) ListTeams(ctx context.Context, filter TeamFilter) ([]*Te
 	for rows.Next() {
 		var team Team
 		var alias, orgID sql.NullString
-		var tpmLimit, rpmLimit sql.NullInt64
+		var tpmLimit, rpmLimit, maxSeats, maxParallel sql.NullInt64
 		if err := rows.Scan(
 			&team.ID, &alias, &orgID, &team.MaxBudget, &team.SpentBudget,
-			&tpmLimit, &rpmLimit, &team.CreatedAt, &team.IsActive, &team.Blocked,
+			&tpmLimit, &rpmLimit, &team.CreatedAt, &team.IsActive, &team.Blocked, &maxSeats, &maxParallel,
 		); err != nil {
 			return nil, 0, fmt.Errorf("scan team: %w", err)
 		}
@@ -514,6 +569,14 @@ func (s *PostgresStore) ListTeams(ctx context.Context, filter TeamFilter) ([]*Te
 		if rpmLimit.Valid {
 			team.RPMLimit = &rpmLimit.Int64
 		}
+		if maxSeats.Valid {
+			seats := int(maxSeats.Int64)
+			team.MaxSeats = &seats
+		}
+		if maxParallel.Valid {
+			parallel := int(maxParallel.Int64)
+			team.MaxParallelRequests = &parallel
+		}
 		teams = append(teams, &team)
 	}
 	return teams, total, rows.Err()
@@ -679,12 +742,13 @@ func (s *PostgresStore) GetUsageStats(ctx context.Context, filter UsageFilter) (
 			AND ($3::text IS NULL OR api_key = $3)
 			AND ($4::text IS NULL OR team_id = $4)
 			AND ($5::text IS NULL OR model = $5)
-			AND ($6::text IS NULL OR custom_llm_provider = $6)`
+			AND ($6::text IS NULL OR custom_llm_provider = $6)
+			AND ($7::text IS NULL OR end_user = $7)`
 
 	var stats UsageStats
 	err := s.db.QueryRowContext(ctx, query,
 		filter.StartTime, filter.EndTime,
-		filter.APIKeyID, filter.TeamID, filter.Model, filter.Provider,
+		filter.APIKeyID, filter.TeamID, filter.Model, filter.Provider, filter.EndUserID,
 	).Scan(
 		&stats.TotalRequests, &stats.TotalTokens, &stats.InputTokens,
 		&stats.OutputTokens, &stats.TotalCost, &stats.AvgLatencyMs,
@@ -707,14 +771,15 @@ func (s *PostgresStore) GetAPIKeyByID(ctx context.Context, keyID string) (*APIKe
 		SELECT id, key_hash, key_prefix, name, key_alias, team_id, user_id, organization_id,
 		       allowed_models, tpm_limit, rpm_limit, max_budget, soft_budget, spent_budget,
 		       model_max_budget, model_spend, budget_duration, budget_reset_at,
-		       metadata, created_at, updated_at, expires_at, last_used_at, is_active, blocked
+		       metadata, created_at, updated_at, expires_at, last_used_at, is_active, blocked, allowed_cidrs,
+		       key_type, max_parallel_requests
 		FROM api_keys
 		WHERE id = $1`
 
 	var key APIKey
-	var allowedModels, modelMaxBudget, modelSpend, metadataJSON sql.NullString
-	var keyAlias, teamID, userID, orgID sql.NullString
-	var tpmLimit, rpmLimit sql.NullInt64
+	var allowedModels, allowedCIDRs, modelMaxBudget, modelSpend, metadataJSON sql.NullString
+	var keyAlias, teamID, userID, orgID, keyType sql.NullString
+	var tpmLimit, rpmLimit, maxParallel sql.NullInt64
 	var softBudget sql.NullFloat64
 	var budgetDuration sql.NullString
 	var budgetResetAt, expiresAt, lastUsedAt sql.NullTime
@@ -725,7 +790,7 @@ func (s *PostgresStore) GetAPIKeyByID(ctx context.Context, keyID string) (*APIKe
 		&key.MaxBudget, &softBudget, &key.SpentBudget,
 		&modelMaxBudget, &modelSpend, &budgetDuration, &budgetResetAt,
 		&metadataJSON, &key.CreatedAt, &key.UpdatedAt, &expiresAt, &lastUsedAt,
-		&key.IsActive, &key.Blocked,
+		&key.IsActive, &key.Blocked, &allowedCIDRs, &keyType, &maxParallel,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -738,6 +803,9 @@ func (s *PostgresStore) GetAPIKeyByID(ctx context.Context, keyID string) (*APIKe
 	if keyAlias.Valid {
 		key.KeyAlias = &keyAlias.String
 	}
+	if keyType.Valid {
+		key.KeyType = KeyType(keyType.String)
+	}
 	if teamID.Valid {
 		key.TeamID = &teamID.String
 	}
@@ -753,6 +821,10 @@ func (s *PostgresStore) GetAPIKeyByID(ctx context.Context, keyID string) (*APIKe
 	if rpmLimit.Valid {
 		key.RPMLimit = &rpmLimit.Int64
 	}
+	if maxParallel.Valid {
+		parallel := int(maxParallel.Int64)
+		key.MaxParallelRequests = &parallel
+	}
 	if softBudget.Valid {
 		key.SoftBudget = &softBudget.Float64
 	}
@@ -773,6 +845,11 @@ func (s *PostgresStore) GetAPIKeyByID(ctx context.Context, keyID string) (*APIKe
 	if allowedModels.Valid && allowedModels.String != "" {
 		_ = json.Unmarshal([]byte(allowedModels.String), &key.AllowedModels)
 	}
+	if allowedCIDRs.Valid && allowedCIDRs.String != "" {
+		if err := json.Unmarshal([]byte(allowedCIDRs.String), &key.AllowedCIDRs); err != nil {
+			key.AllowedCIDRs = nil
+		}
+	}
 	if modelMaxBudget.Valid && modelMaxBudget.String != "" {
 		_ = json.Unmarshal([]byte(modelMaxBudget.String), &key.ModelMaxBudget)
 	}
@@ -792,14 +869,15 @@ func (s *PostgresStore) GetAPIKeyByAlias(ctx context.Context, alias string) (*AP
 		SELECT id, key_hash, key_prefix, name, key_alias, team_id, user_id, organization_id,
 		       allowed_models, tpm_limit, rpm_limit, max_budget, soft_budget, spent_budget,
 		       model_max_budget, model_spend, budget_duration, budget_reset_at,
-		       metadata, created_at, updated_at, expires_at, last_used_at, is_active, blocked
+		       metadata, created_at, updated_at, expires_at, last_used_at, is_active, blocked, allowed_cidrs,
+		       key_type, max_parallel_requests
 		FROM api_keys
 		WHERE key_alias = $1`
 
 	var key APIKey
-	var allowedModels, modelMaxBudget, modelSpend, metadataJSON sql.NullString
-	var keyAlias, teamID, userID, orgID sql.NullString
-	var tpmLimit, rpmLimit sql.NullInt64
+	var allowedModels, allowedCIDRs, modelMaxBudget, modelSpend, metadataJSON sql.NullString
+	var keyAlias, teamID, userID, orgID, keyType sql.NullString
+	var tpmLimit, rpmLimit, maxParallel sql.NullInt64
 	var softBudget sql.NullFloat64
 	var budgetDuration sql.NullString
 	var budgetResetAt, expiresAt, lastUsedAt sql.NullTime
@@ -810,7 +888,7 @@ func (s *PostgresStore) GetAPIKeyByAlias(ctx context.Context, alias string) (*AP
 		&key.MaxBudget, &softBudget, &key.SpentBudget,
 		&modelMaxBudget, &modelSpend, &budgetDuration, &budgetResetAt,
 		&metadataJSON, &key.CreatedAt, &key.UpdatedAt, &expiresAt, &lastUsedAt,
-		&key.IsActive, &key.Blocked,
+		&key.IsActive, &key.Blocked, &allowedCIDRs, &keyType, &maxParallel,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -823,6 +901,9 @@ func (s *PostgresStore) GetAPIKeyByAlias(ctx context.Context, alias string) (*AP
 	if keyAlias.Valid {
 		key.KeyAlias = &keyAlias.String
 	}
+	if keyType.Valid {
+		key.KeyType = KeyType(keyType.String)
+	}
 	if teamID.Valid {
 		key.TeamID = &teamID.String
 	}
@@ -838,6 +919,10 @@ func (s *PostgresStore) GetAPIKeyByAlias(ctx context.Context, alias string) (*AP
 	if rpmLimit.Valid {
 		key.RPMLimit = &rpmLimit.Int64
 	}
+	if maxParallel.Valid {
+		parallel := int(maxParallel.Int64)
+		key.MaxParallelRequests = &parallel
+	}
 	if softBudget.Valid {
 		key.SoftBudget = &softBudget.Float64
 	}
@@ -858,6 +943,11 @@ func (s *PostgresStore) GetAPIKeyByAlias(ctx context.Context, alias string) (*AP
 	if allowedModels.Valid && allowedModels.String != "" {
 		_ = json.Unmarshal([]byte(allowedModels.String), &key.AllowedModels)
 	}
+	if allowedCIDRs.Valid && allowedCIDRs.String != "" {
+		if err := json.Unmarshal([]byte(allowedCIDRs.String), &key.AllowedCIDRs); err != nil {
+			key.AllowedCIDRs = nil
+		}
+	}
 	if modelMaxBudget.Valid && modelMaxBudget.String != "" {
 		_ = json.Unmarshal([]byte(modelMaxBudget.String), &key.ModelMaxBudget)
 	}
@@ -877,21 +967,23 @@ func (s *PostgresStore) UpdateAPIKey(ctx context.Context, key *APIKey) error {
 	modelMaxBudgetJSON, _ := json.Marshal(key.ModelMaxBudget)
 	modelSpendJSON, _ := json.Marshal(key.ModelSpend)
 	metadataJSON, _ := json.Marshal(key.Metadata)
+	allowedCIDRsJSON, _ := json.Marshal(key.AllowedCIDRs)
 
 	query := `
 		UPDATE api_keys SET
 			key_prefix = $1, name = $2, key_alias = $3, team_id = $4, user_id = $5, organization_id = $6,
 			allowed_models = $7, tpm_limit = $8, rpm_limit = $9, max_budget = $10, soft_budget = $11,
 			model_max_budget = $12, model_spend = $13, budget_duration = $14, budget_reset_at = $15,
-			metadata = $16, updated_at = $17, expires_at = $18, is_active = $19, blocked = $20
-		WHERE id = $21`
+			metadata = $16, updated_at = $17, expires_at = $18, is_active = $19, blocked = $20,
+			allowed_cidrs = $21, key_type = $22, max_parallel_requests = $23
+		WHERE id = $24`
 
 	_, err := s.db.ExecContext(ctx, query,
 		key.KeyPrefix, key.Name, key.KeyAlias, key.TeamID, key.UserID, key.OrganizationID,
 		string(allowedModelsJSON), key.TPMLimit, key.RPMLimit, key.MaxBudget, key.SoftBudget,
 		string(modelMaxBudgetJSON), string(modelSpendJSON), string(key.BudgetDuration), key.BudgetResetAt,
 		string(metadataJSON), time.Now(), key.ExpiresAt, key.IsActive, key.Blocked,
-		key.ID,
+		string(allowedCIDRsJSON), string(key.KeyType), key.MaxParallelRequests, key.ID,
 	)
 	return err
 }
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+// apiKeyColumns are the columns the single-key lookups select.
+var apiKeyColumns = []string{
+	"id", "key_hash", "key_prefix", "name", "key_alias", "team_id", "user_id", "organization_id",
+	"allowed_models", "tpm_limit", "rpm_limit", "max_budget", "soft_budget", "spent_budget",
+	"model_max_budget", "model_spend", "budget_duration", "budget_reset_at",
+	"metadata", "created_at", "updated_at", "expires_at", "last_used_at", "is_active", "blocked", "allowed_cidrs",
+	"key_type", "max_parallel_requests",
+}
+
+func apiKeyRow(key *APIKey) []driver.Value {
+	return []driver.Value{
+		key.ID, key.KeyHash, key.KeyPrefix, key.Name, nil, nil, nil, nil,
+		`[]`, nil, nil, key.MaxBudget, nil, key.SpentBudget,
+		`{}`, `{}`, nil, nil,
+		`{}`, key.CreatedAt, key.UpdatedAt, nil, nil, key.IsActive, key.Blocked, `[]`,
+		string(key.KeyType), key.MaxParallelRequests,
+	}
+}
+
+// argsWith returns n placeholder arguments with want at index i.
+func argsWith(n, i int, want driver.Value) []driver.Value {
+	args := make([]driver.Value, n)
+	for j := range args {
+		args[j] = sqlmock.AnyArg()
+	}
+	args[i] = want
+	return args
+}
+
+func TestPostgresStore_APIKeyType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	store := &PostgresStore{db: db}
+	ctx := context.Background()
+
+	now := time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC)
+	key := &APIKey{
+		ID:        "key-1",
+		KeyHash:   "hash-1",
+		KeyPrefix: "sk-1",
+		Name:      "admin",
+		KeyType:   KeyTypeManagement,
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	mock.ExpectExec(`INSERT INTO api_keys .*key_type`).
+		WithArgs(argsWith(27, 25, "management")...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	require.NoError(t, store.CreateAPIKey(ctx, key))
+
+	mock.ExpectQuery(`SELECT .*key_type.*FROM api_keys\s+WHERE key_hash = \$1`).
+		WithArgs(key.KeyHash).
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns).AddRow(apiKeyRow(key)...))
+	got, err := store.GetAPIKeyByHash(ctx, key.KeyHash)
+	require.NoError(t, err)
+	require.Equal(t, KeyTypeManagement, got.KeyType)
+	require.True(t, got.KeyType.AllowsRoute("POST", "/key/generate"))
+
+	key.KeyType = KeyTypeReadOnly
+	mock.ExpectExec(`UPDATE api_keys SET .*key_type = \$22, .*WHERE id = \$24`).
+		WithArgs(argsWith(24, 21, "read_only")...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	require.NoError(t, store.UpdateAPIKey(ctx, key))
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM api_keys`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT .*key_type.*FROM api_keys`).
+		WithArgs(10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "key_prefix", "name", "team_id", "user_id", "organization_id", "tpm_limit", "rpm_limit", "max_budget",
+			"spent_budget", "created_at", "expires_at", "last_used_at", "is_active", "blocked", "key_type",
+			"max_parallel_requests", "metadata",
+		}).AddRow(key.ID, key.KeyPrefix, key.Name, nil, nil, nil, nil, nil, 0.0, 0.0, now, nil, nil, true, false, "read_only", nil,
+			`{"temporary":true}`))
+	keys, total, err := store.ListAPIKeys(ctx, APIKeyFilter{Limit: 10})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Equal(t, KeyTypeReadOnly, keys[0].KeyType)
+	require.True(t, keys[0].IsTemporary())
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresStore_MaxParallelRequests(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	store := &PostgresStore{db: db}
+	ctx := context.Background()
+
+	now := time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC)
+	parallel := 3
+	key := &APIKey{ID: "key-1", KeyHash: "hash-1", KeyPrefix: "sk-1", MaxParallelRequests: &parallel, IsActive: true, CreatedAt: now, UpdatedAt: now}
+
+	mock.ExpectExec(`INSERT INTO api_keys .*max_parallel_requests`).
+		WithArgs(argsWith(27, 26, &parallel)...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	require.NoError(t, store.CreateAPIKey(ctx, key))
+
+	mock.ExpectQuery(`SELECT .*max_parallel_requests\s+FROM api_keys\s+WHERE id = \$1`).
+		WithArgs(key.ID).
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns).AddRow(apiKeyRow(key)...))
+	got, err := store.GetAPIKeyByID(ctx, key.ID)
+	require.NoError(t, err)
+	require.Equal(t, 3, *got.MaxParallelRequests)
+
+	mock.ExpectExec(`UPDATE api_keys SET .*max_parallel_requests = \$23`).
+		WithArgs(argsWith(24, 22, &parallel)...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	require.NoError(t, store.UpdateAPIKey(ctx, key))
+
+	team := &Team{ID: "team-1", MaxParallelRequests: &parallel, IsActive: true, CreatedAt: now, UpdatedAt: now}
+	mock.ExpectExec(`INSERT INTO teams .*max_parallel_requests`).
+		WithArgs(argsWith(15, 14, &parallel)...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	require.NoError(t, store.CreateTeam(ctx, team))
+
+	mock.ExpectQuery(`SELECT .*max_parallel_requests\s+FROM teams\s+WHERE id = \$1`).
+		WithArgs(team.ID).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "team_alias", "organization_id", "max_budget", "spend",
+			"tpm_limit", "rpm_limit", "models", "metadata", "created_at", "updated_at", "is_active", "blocked", "max_seats",
+			"max_parallel_requests",
+		}).AddRow(team.ID, nil, nil, 0.0, 0.0, nil, nil, `[]`, `{}`, now, now, true, false, nil, 5))
+	gotTeam, err := store.GetTeam(ctx, team.ID)
+	require.NoError(t, err)
+	require.Equal(t, 5, *gotTeam.MaxParallelRequests)
+
+	mock.ExpectExec(`UPDATE teams SET .*max_parallel_requests = \$17`).
+		WithArgs(argsWith(18, 16, &parallel)...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	require.NoError(t, store.UpdateTeam(ctx, team))
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM teams`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT .*max_parallel_requests\s+FROM teams`).
+		WithArgs(10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "team_alias", "organization_id", "max_budget", "spend", "tpm_limit", "rpm_limit",
+			"created_at", "is_active", "blocked", "max_seats", "max_parallel_requests",
+		}).AddRow(team.ID, nil, nil, 0.0, 0.0, nil, nil, now, true, false, nil, 2))
+	teams, _, err := store.ListTeams(ctx, TeamFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 2, *teams[0].MaxParallelRequests)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
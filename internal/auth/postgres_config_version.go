@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// LatestConfigVersion implements ConfigVersionStore.
+func (s *PostgresStore) LatestConfigVersion(ctx context.Context) (*ConfigVersion, error) {
+	query := `
+		SELECT version, document, checksum, comment, created_by, created_at
+		FROM config_versions
+		ORDER BY version DESC
+		LIMIT 1`
+
+	v, err := scanConfigVersion(s.db.QueryRowContext(ctx, query))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get latest config version: %w", err)
+	}
+	return v, nil
+}
+
+// GetConfigVersion implements ConfigVersionStore.
+func (s *PostgresStore) GetConfigVersion(ctx context.Context, version int) (*ConfigVersion, error) {
+	query := `
+		SELECT version, document, checksum, comment, created_by, created_at
+		FROM config_versions
+		WHERE version = $1`
+
+	v, err := scanConfigVersion(s.db.QueryRowContext(ctx, query, version))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get config version: %w", err)
+	}
+	return v, nil
+}
+
+// ListConfigVersions implements ConfigVersionStore.
+func (s *PostgresStore) ListConfigVersions(ctx context.Context, limit int) ([]*ConfigVersion, error) {
+	query := `
+		SELECT version, document, checksum, comment, created_by, created_at
+		FROM config_versions
+		ORDER BY version DESC
+		LIMIT $1`
+
+	rows, err := s.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list config versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []*ConfigVersion
+	for rows.Next() {
+		v, err := scanConfigVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan config version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// CreateConfigVersion implements ConfigVersionStore.
+func (s *PostgresStore) CreateConfigVersion(ctx context.Context, v *ConfigVersion) error {
+	query := `
+		INSERT INTO config_versions (document, checksum, comment, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING version, created_at`
+
+	err := s.db.QueryRowContext(ctx, query,
+		v.Document, v.Checksum, nullString(v.Comment), nullString(v.CreatedBy),
+	).Scan(&v.Version, &v.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create config version: %w", err)
+	}
+	return nil
+}
+
+func scanConfigVersion(row interface{ Scan(...any) error }) (*ConfigVersion, error) {
+	var (
+		v         ConfigVersion
+		comment   sql.NullString
+		createdBy sql.NullString
+	)
+	if err := row.Scan(&v.Version, &v.Document, &v.Checksum, &comment, &createdBy, &v.CreatedAt); err != nil {
+		return nil, err
+	}
+	v.Comment = comment.String
+	v.CreatedBy = createdBy.String
+	return &v, nil
+}
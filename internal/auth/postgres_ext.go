@@ -143,14 +143,15 @@ func (s *PostgresStore) UpdateTeam(ctx context.Context, team *Team) error {
 			team_alias = $1, organization_id = $2, max_budget = $3, spend = $4,
 			model_max_budget = $5, model_spend = $6, budget_duration = $7, budget_reset_at = $8,
 			tpm_limit = $9, rpm_limit = $10, models = $11, metadata = $12,
-			updated_at = $13, is_active = $14, blocked = $15
-		WHERE id = $16`
+			updated_at = $13, is_active = $14, blocked = $15, max_seats = $16,
+			max_parallel_requests = $17
+		WHERE id = $18`
 
 	_, err := s.db.ExecContext(ctx, query,
 		team.Alias, team.OrganizationID, team.MaxBudget, team.SpentBudget,
 		string(modelMaxBudgetJSON), string(modelSpendJSON), string(team.BudgetDuration), team.BudgetResetAt,
 		team.TPMLimit, team.RPMLimit, string(modelsJSON), string(metadataJSON),
-		time.Now(), team.IsActive, team.Blocked, team.ID,
+		time.Now(), team.IsActive, team.Blocked, team.MaxSeats, team.MaxParallelRequests, team.ID,
 	)
 	return err
 }
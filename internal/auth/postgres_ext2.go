@@ -332,35 +332,50 @@ func (s *PostgresStore) ListUserOrganizationMemberships(ctx context.Context, use
 // End User Operations
 // ========================================================================
 
+// endUserColumns selects an end user with its budget limit joined in.
+const endUserColumns = `
+		SELECT e.user_id, e.alias, e.spend, e.budget_id, e.blocked, b.max_budget, b.soft_budget
+		FROM end_users e
+		LEFT JOIN budgets b ON b.id = e.budget_id`
+
 // GetEndUser retrieves an end user by ID.
 func (s *PostgresStore) GetEndUser(ctx context.Context, userID string) (*EndUser, error) {
-	query := `
-		SELECT user_id, alias, spend, budget_id, blocked, created_at, updated_at
-		FROM end_users
-		WHERE user_id = $1`
-
-	var endUser EndUser
-	var alias, budgetID sql.NullString
-	var createdAt, updatedAt sql.NullTime
-
-	err := s.db.QueryRowContext(ctx, query, userID).Scan(
-		&endUser.UserID, &alias, &endUser.Spend, &budgetID, &endUser.Blocked,
-		&createdAt, &updatedAt,
-	)
+	endUser, err := scanEndUser(s.db.QueryRowContext(ctx, endUserColumns+`
+		WHERE e.user_id = $1`, userID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("query end user: %w", err)
 	}
+	return endUser, nil
+}
+
+func scanEndUser(row interface{ Scan(dest ...any) error }) (*EndUser, error) {
+	var endUser EndUser
+	var alias, budgetID sql.NullString
+	var maxBudget, softBudget sql.NullFloat64
+
+	if err := row.Scan(
+		&endUser.UserID, &alias, &endUser.Spend, &budgetID, &endUser.Blocked,
+		&maxBudget, &softBudget,
+	); err != nil {
+		return nil, err
+	}
 
 	if alias.Valid {
 		endUser.Alias = &alias.String
 	}
 	if budgetID.Valid {
 		endUser.BudgetID = &budgetID.String
+		endUser.Budget = &Budget{ID: budgetID.String}
+		if maxBudget.Valid {
+			endUser.Budget.MaxBudget = &maxBudget.Float64
+		}
+		if softBudget.Valid {
+			endUser.Budget.SoftBudget = &softBudget.Float64
+		}
 	}
-
 	return &endUser, nil
 }
 
@@ -379,6 +394,13 @@ func (s *PostgresStore) CreateEndUser(ctx context.Context, endUser *EndUser) err
 	return err
 }
 
+// UpdateEndUser updates an end user's alias, budget link and blocked flag.
+func (s *PostgresStore) UpdateEndUser(ctx context.Context, endUser *EndUser) error {
+	query := `UPDATE end_users SET alias = $1, budget_id = $2, blocked = $3, updated_at = $4 WHERE user_id = $5`
+	_, err := s.db.ExecContext(ctx, query, endUser.Alias, endUser.BudgetID, endUser.Blocked, time.Now(), endUser.UserID)
+	return err
+}
+
 // UpdateEndUserSpent updates the spent amount for an end user.
 func (s *PostgresStore) UpdateEndUserSpent(ctx context.Context, userID string, amount float64) error {
 	query := `UPDATE end_users SET spend = spend + $1, updated_at = $2 WHERE user_id = $3`
@@ -400,6 +422,55 @@ func (s *PostgresStore) DeleteEndUser(ctx context.Context, userID string) error
 	return err
 }
 
+// ListEndUsers lists end users with optional filters.
+func (s *PostgresStore) ListEndUsers(ctx context.Context, filter EndUserFilter) ([]*EndUser, int64, error) {
+	query := endUserColumns + `
+		WHERE 1=1`
+	countQuery := `SELECT COUNT(*) FROM end_users e WHERE 1=1`
+
+	args := []interface{}{}
+	argIdx := 1
+
+	if filter.Blocked != nil {
+		query += fmt.Sprintf(" AND e.blocked = $%d", argIdx)
+		countQuery += fmt.Sprintf(" AND e.blocked = $%d", argIdx)
+		args = append(args, *filter.Blocked)
+		argIdx++
+	}
+
+	var total int64
+	if err := s.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count end users: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	query += " ORDER BY e.user_id"
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	args = append(args, limit, filter.Offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query end users: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var endUsers []*EndUser
+	for rows.Next() {
+		endUser, err := scanEndUser(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan end user: %w", err)
+		}
+		endUsers = append(endUsers, endUser)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate end users: %w", err)
+	}
+	return endUsers, total, nil
+}
+
 // ========================================================================
 // Daily Usage Operations
 // ========================================================================
@@ -479,6 +550,75 @@ func (s *PostgresStore) GetDailyUsage(ctx context.Context, filter DailyUsageFilt
 	return usages, rows.Err()
 }
 
+// GetDailyTagUsage aggregates usage logs by day and request tag.
+func (s *PostgresStore) GetDailyTagUsage(ctx context.Context, filter DailyUsageFilter) ([]*DailyTagUsage, error) {
+	query := `
+		SELECT TO_CHAR(DATE("startTime"), 'YYYY-MM-DD') AS day, tag,
+		       COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0),
+		       COALESCE(SUM(spend), 0), COUNT(*)
+		FROM usage_logs, jsonb_array_elements_text(request_tags) AS tag
+		WHERE 1=1`
+
+	args := []interface{}{}
+	argIdx := 1
+
+	if filter.StartDate != "" {
+		query += fmt.Sprintf(` AND DATE("startTime") >= $%d`, argIdx)
+		args = append(args, filter.StartDate)
+		argIdx++
+	}
+	if filter.EndDate != "" {
+		query += fmt.Sprintf(` AND DATE("startTime") <= $%d`, argIdx)
+		args = append(args, filter.EndDate)
+		argIdx++
+	}
+	if filter.APIKeyID != nil {
+		query += fmt.Sprintf(" AND api_key = $%d", argIdx)
+		args = append(args, *filter.APIKeyID)
+		argIdx++
+	}
+	if filter.TeamID != nil {
+		query += fmt.Sprintf(" AND team_id = $%d", argIdx)
+		args = append(args, *filter.TeamID)
+		argIdx++
+	}
+	if filter.Model != nil {
+		query += fmt.Sprintf(" AND model = $%d", argIdx)
+		args = append(args, *filter.Model)
+		argIdx++
+	}
+	if filter.Provider != nil {
+		query += fmt.Sprintf(" AND custom_llm_provider = $%d", argIdx)
+		args = append(args, *filter.Provider)
+		argIdx++
+	}
+	if filter.Tag != nil {
+		query += fmt.Sprintf(" AND tag = $%d", argIdx)
+		args = append(args, *filter.Tag)
+	}
+
+	query += " GROUP BY day, tag ORDER BY day DESC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query daily tag usage: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var usages []*DailyTagUsage
+	for rows.Next() {
+		var usage DailyTagUsage
+		if err := rows.Scan(
+			&usage.Date, &usage.Tag, &usage.InputTokens, &usage.OutputTokens, &usage.Spend, &usage.APIRequests,
+		); err != nil {
+			return nil, fmt.Errorf("scan daily tag usage: %w", err)
+		}
+		usages = append(usages, &usage)
+	}
+
+	return usages, rows.Err()
+}
+
 // ========================================================================
 // Budget Reset Operations
 // ========================================================================
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// GetModelAccessGroup implements ModelAccessGroupStore.
+func (s *PostgresStore) GetModelAccessGroup(ctx context.Context, name string) (*ModelAccessGroup, error) {
+	query := `
+		SELECT name, models, description, created_at, updated_at
+		FROM model_access_groups
+		WHERE name = $1`
+
+	group, err := scanModelAccessGroup(s.db.QueryRowContext(ctx, query, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get model access group: %w", err)
+	}
+	return group, nil
+}
+
+// SaveModelAccessGroup implements ModelAccessGroupStore.
+func (s *PostgresStore) SaveModelAccessGroup(ctx context.Context, group *ModelAccessGroup) error {
+	models, err := json.Marshal(group.Models)
+	if err != nil {
+		return fmt.Errorf("marshal models: %w", err)
+	}
+	query := `
+		INSERT INTO model_access_groups (name, models, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name) DO UPDATE SET
+			models = EXCLUDED.models,
+			description = EXCLUDED.description,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = s.db.ExecContext(ctx, query,
+		group.Name, string(models), nullString(group.Description), group.CreatedAt, group.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save model access group: %w", err)
+	}
+	return nil
+}
+
+// DeleteModelAccessGroup implements ModelAccessGroupStore.
+func (s *PostgresStore) DeleteModelAccessGroup(ctx context.Context, name string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM model_access_groups WHERE name = $1`, name); err != nil {
+		return fmt.Errorf("delete model access group: %w", err)
+	}
+	return nil
+}
+
+// ListModelAccessGroups implements ModelAccessGroupStore.
+func (s *PostgresStore) ListModelAccessGroups(ctx context.Context) ([]*ModelAccessGroup, error) {
+	query := `
+		SELECT name, models, description, created_at, updated_at
+		FROM model_access_groups
+		ORDER BY name`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list model access groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []*ModelAccessGroup
+	for rows.Next() {
+		group, err := scanModelAccessGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan model access group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+	return groups, rows.Err()
+}
+
+func scanModelAccessGroup(row interface{ Scan(...any) error }) (*ModelAccessGroup, error) {
+	var (
+		group       ModelAccessGroup
+		models      []byte
+		description sql.NullString
+	)
+	if err := row.Scan(&group.Name, &models, &description, &group.CreatedAt, &group.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if len(models) > 0 {
+		if err := json.Unmarshal(models, &group.Models); err != nil {
+			return nil, fmt.Errorf("decode models: %w", err)
+		}
+	}
+	group.Description = description.String
+	return &group, nil
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// GetModelProvider implements ModelProviderStore.
+func (s *PostgresStore) GetModelProvider(ctx context.Context, name string) (*ModelProvider, error) {
+	query := `
+		SELECT name, params, created_at, updated_at
+		FROM model_providers
+		WHERE name = $1`
+
+	provider, err := scanModelProvider(s.db.QueryRowContext(ctx, query, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get model provider: %w", err)
+	}
+	return provider, nil
+}
+
+// SaveModelProvider implements ModelProviderStore. Everything but the name
+// and timestamps is stored as one JSON document.
+func (s *PostgresStore) SaveModelProvider(ctx context.Context, provider *ModelProvider) error {
+	params, err := json.Marshal(provider)
+	if err != nil {
+		return fmt.Errorf("marshal params: %w", err)
+	}
+	query := `
+		INSERT INTO model_providers (name, params, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO UPDATE SET
+			params = EXCLUDED.params,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = s.db.ExecContext(ctx, query, provider.Name, string(params), provider.CreatedAt, provider.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("save model provider: %w", err)
+	}
+	return nil
+}
+
+// DeleteModelProvider implements ModelProviderStore.
+func (s *PostgresStore) DeleteModelProvider(ctx context.Context, name string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM model_providers WHERE name = $1`, name); err != nil {
+		return fmt.Errorf("delete model provider: %w", err)
+	}
+	return nil
+}
+
+// ListModelProviders implements ModelProviderStore.
+func (s *PostgresStore) ListModelProviders(ctx context.Context) ([]*ModelProvider, error) {
+	query := `
+		SELECT name, params, created_at, updated_at
+		FROM model_providers
+		ORDER BY name`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list model providers: %w", err)
+	}
+	defer rows.Close()
+
+	var providers []*ModelProvider
+	for rows.Next() {
+		provider, err := scanModelProvider(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan model provider: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, rows.Err()
+}
+
+func scanModelProvider(row interface{ Scan(...any) error }) (*ModelProvider, error) {
+	var (
+		provider ModelProvider
+		name     string
+		params   []byte
+	)
+	if err := row.Scan(&name, &params, &provider.CreatedAt, &provider.UpdatedAt); err != nil {
+		return nil, err
+	}
+	createdAt, updatedAt := provider.CreatedAt, provider.UpdatedAt
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &provider); err != nil {
+			return nil, fmt.Errorf("decode params: %w", err)
+		}
+	}
+	provider.Name, provider.CreatedAt, provider.UpdatedAt = name, createdAt, updatedAt
+	return &provider, nil
+}
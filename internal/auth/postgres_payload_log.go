@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SavePayloadLog implements PayloadLogStore.
+func (s *PostgresStore) SavePayloadLog(ctx context.Context, log *PayloadLog) error {
+	query := `
+		INSERT INTO payload_logs (
+			request_id, timestamp, api_key_id, team_id, model, call_type,
+			status_code, request, response, error
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (request_id) DO UPDATE SET
+			timestamp = EXCLUDED.timestamp,
+			status_code = EXCLUDED.status_code,
+			request = EXCLUDED.request,
+			response = EXCLUDED.response,
+			error = EXCLUDED.error`
+
+	_, err := s.db.ExecContext(ctx, query,
+		log.RequestID, log.Timestamp, nullString(log.APIKeyID), nullString(log.TeamID),
+		nullString(log.Model), nullString(log.CallType), log.StatusCode,
+		nullJSON(log.Request), nullJSON(log.Response), nullString(log.Error),
+	)
+	if err != nil {
+		return fmt.Errorf("insert payload log: %w", err)
+	}
+	return nil
+}
+
+// GetPayloadLog implements PayloadLogStore.
+func (s *PostgresStore) GetPayloadLog(ctx context.Context, requestID string) (*PayloadLog, error) {
+	query := `
+		SELECT request_id, timestamp, api_key_id, team_id, model, call_type,
+		       status_code, request, response, error
+		FROM payload_logs
+		WHERE request_id = $1`
+
+	var (
+		log                                    PayloadLog
+		apiKeyID, teamID, model, callType, msg sql.NullString
+		request, response                      []byte
+	)
+	err := s.db.QueryRowContext(ctx, query, requestID).Scan(
+		&log.RequestID, &log.Timestamp, &apiKeyID, &teamID, &model, &callType,
+		&log.StatusCode, &request, &response, &msg,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get payload log: %w", err)
+	}
+	log.APIKeyID = apiKeyID.String
+	log.TeamID = teamID.String
+	log.Model = model.String
+	log.CallType = callType.String
+	log.Error = msg.String
+	log.Request = request
+	log.Response = response
+	return &log, nil
+}
+
+// DeletePayloadLogs implements PayloadLogStore.
+func (s *PostgresStore) DeletePayloadLogs(ctx context.Context, olderThan time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM payload_logs WHERE timestamp < $1`, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("delete payload logs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// GetPromptVersion implements PromptStore.
+func (s *PostgresStore) GetPromptVersion(ctx context.Context, promptID string, version int) (*PromptVersion, error) {
+	query := `
+		SELECT prompt_id, version, messages, model, comment, created_by, created_at
+		FROM prompt_versions
+		WHERE prompt_id = $1 AND version = $2`
+	args := []any{promptID, version}
+	if version == 0 {
+		query = `
+		SELECT prompt_id, version, messages, model, comment, created_by, created_at
+		FROM prompt_versions
+		WHERE prompt_id = $1
+		ORDER BY version DESC
+		LIMIT 1`
+		args = args[:1]
+	}
+
+	v, err := scanPromptVersion(s.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get prompt version: %w", err)
+	}
+	return v, nil
+}
+
+// ListPromptVersions implements PromptStore.
+func (s *PostgresStore) ListPromptVersions(ctx context.Context, promptID string, limit int) ([]*PromptVersion, error) {
+	query := `
+		SELECT prompt_id, version, messages, model, comment, created_by, created_at
+		FROM prompt_versions
+		WHERE prompt_id = $1
+		ORDER BY version DESC
+		LIMIT $2`
+
+	rows, err := s.db.QueryContext(ctx, query, promptID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list prompt versions: %w", err)
+	}
+	return scanPromptVersions(rows)
+}
+
+// ListPrompts implements PromptStore.
+func (s *PostgresStore) ListPrompts(ctx context.Context) ([]*PromptVersion, error) {
+	query := `
+		SELECT DISTINCT ON (prompt_id) prompt_id, version, messages, model, comment, created_by, created_at
+		FROM prompt_versions
+		ORDER BY prompt_id, version DESC`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list prompts: %w", err)
+	}
+	return scanPromptVersions(rows)
+}
+
+// CreatePromptVersion implements PromptStore. Concurrent writers of the same
+// prompt conflict on the primary key rather than sharing a version.
+func (s *PostgresStore) CreatePromptVersion(ctx context.Context, v *PromptVersion) error {
+	messages, err := json.Marshal(v.Messages)
+	if err != nil {
+		return fmt.Errorf("marshal messages: %w", err)
+	}
+	query := `
+		INSERT INTO prompt_versions (prompt_id, version, messages, model, comment, created_by)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4, $5
+		FROM prompt_versions
+		WHERE prompt_id = $1
+		RETURNING version, created_at`
+
+	err = s.db.QueryRowContext(ctx, query,
+		v.PromptID, string(messages), nullString(v.Model), nullString(v.Comment), nullString(v.CreatedBy),
+	).Scan(&v.Version, &v.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create prompt version: %w", err)
+	}
+	return nil
+}
+
+// DeletePrompt implements PromptStore.
+func (s *PostgresStore) DeletePrompt(ctx context.Context, promptID string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM prompt_versions WHERE prompt_id = $1`, promptID); err != nil {
+		return fmt.Errorf("delete prompt: %w", err)
+	}
+	return nil
+}
+
+func scanPromptVersions(rows *sql.Rows) ([]*PromptVersion, error) {
+	defer rows.Close()
+	var versions []*PromptVersion
+	for rows.Next() {
+		v, err := scanPromptVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan prompt version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+func scanPromptVersion(row interface{ Scan(...any) error }) (*PromptVersion, error) {
+	var (
+		v         PromptVersion
+		messages  []byte
+		model     sql.NullString
+		comment   sql.NullString
+		createdBy sql.NullString
+	)
+	if err := row.Scan(&v.PromptID, &v.Version, &messages, &model, &comment, &createdBy, &v.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(messages, &v.Messages); err != nil {
+		return nil, fmt.Errorf("decode messages: %w", err)
+	}
+	v.Model = model.String
+	v.Comment = comment.String
+	v.CreatedBy = createdBy.String
+	return &v, nil
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// promptVariablePattern matches a {{name}} placeholder in prompt content.
+var promptVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+// PromptMessage is one message of a prompt template. Content may reference
+// request variables as {{name}}.
+type PromptMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// PromptVersion is one stored version of a prompt template. Versions of a
+// prompt are append-only; requests get the latest version unless they pin
+// one.
+type PromptVersion struct {
+	PromptID string          `json:"prompt_id"`
+	Version  int             `json:"version"`
+	Messages []PromptMessage `json:"messages"`
+	// Model is used for requests that name no model.
+	Model     string    `json:"model,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Variables returns the names of the variables the template references, in
+// order of first use.
+func (v *PromptVersion) Variables() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, msg := range v.Messages {
+		for _, match := range promptVariablePattern.FindAllStringSubmatch(msg.Content, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+	}
+	return names
+}
+
+// Render returns the template's messages with every {{name}} replaced by
+// vars[name]. A referenced variable missing from vars is an error.
+func (v *PromptVersion) Render(vars map[string]string) ([]PromptMessage, error) {
+	var missing []string
+	messages := make([]PromptMessage, len(v.Messages))
+	for i, msg := range v.Messages {
+		content := promptVariablePattern.ReplaceAllStringFunc(msg.Content, func(placeholder string) string {
+			name := promptVariablePattern.FindStringSubmatch(placeholder)[1]
+			value, ok := vars[name]
+			if !ok {
+				missing = append(missing, name)
+				return placeholder
+			}
+			return value
+		})
+		messages[i] = PromptMessage{Role: msg.Role, Content: content}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing variables: %s", strings.Join(missing, ", "))
+	}
+	return messages, nil
+}
+
+// PromptStore persists versioned prompt templates.
+type PromptStore interface {
+	// GetPromptVersion returns the given version of a prompt, or its latest
+	// version when version is 0. It returns nil when it does not exist.
+	GetPromptVersion(ctx context.Context, promptID string, version int) (*PromptVersion, error)
+	// ListPromptVersions returns up to limit versions of a prompt, newest first.
+	ListPromptVersions(ctx context.Context, promptID string, limit int) ([]*PromptVersion, error)
+	// ListPrompts returns the latest version of every prompt ordered by ID.
+	ListPrompts(ctx context.Context) ([]*PromptVersion, error)
+	// CreatePromptVersion stores v as the next version of its prompt,
+	// assigning its Version and CreatedAt.
+	CreatePromptVersion(ctx context.Context, v *PromptVersion) error
+	// DeletePrompt removes every version of a prompt.
+	DeletePrompt(ctx context.Context, promptID string) error
+}
+
+// Prompts returns the prompt store backing store, if any.
+func Prompts(store Store) (PromptStore, bool) {
+	if store == nil {
+		return nil, false
+	}
+	prompts, ok := UnwrapStore(store).(PromptStore)
+	return prompts, ok
+}
+
+// GetPromptVersion implements PromptStore.
+func (s *MemoryStore) GetPromptVersion(_ context.Context, promptID string, version int) (*PromptVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	versions := s.prompts[promptID]
+	if version == 0 {
+		version = len(versions)
+	}
+	if version < 1 || version > len(versions) {
+		return nil, nil
+	}
+	return clonePromptVersion(versions[version-1]), nil
+}
+
+// ListPromptVersions implements PromptStore.
+func (s *MemoryStore) ListPromptVersions(_ context.Context, promptID string, limit int) ([]*PromptVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	versions := s.prompts[promptID]
+	list := make([]*PromptVersion, 0, len(versions))
+	for i := len(versions) - 1; i >= 0; i-- {
+		if limit > 0 && len(list) == limit {
+			break
+		}
+		list = append(list, clonePromptVersion(versions[i]))
+	}
+	return list, nil
+}
+
+// ListPrompts implements PromptStore.
+func (s *MemoryStore) ListPrompts(_ context.Context) ([]*PromptVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*PromptVersion, 0, len(s.prompts))
+	for _, versions := range s.prompts {
+		list = append(list, clonePromptVersion(versions[len(versions)-1]))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].PromptID < list[j].PromptID })
+	return list, nil
+}
+
+// CreatePromptVersion implements PromptStore.
+func (s *MemoryStore) CreatePromptVersion(_ context.Context, v *PromptVersion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v.Version = len(s.prompts[v.PromptID]) + 1
+	v.CreatedAt = time.Now()
+	s.prompts[v.PromptID] = append(s.prompts[v.PromptID], clonePromptVersion(v))
+	return nil
+}
+
+// DeletePrompt implements PromptStore.
+func (s *MemoryStore) DeletePrompt(_ context.Context, promptID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.prompts, promptID)
+	return nil
+}
+
+func clonePromptVersion(v *PromptVersion) *PromptVersion {
+	clone := *v
+	clone.Messages = append([]PromptMessage(nil), v.Messages...)
+	return &clone
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptVersion_Render(t *testing.T) {
+	v := &PromptVersion{Messages: []PromptMessage{
+		{Role: "system", Content: "You support {{ product }} customers in {{language}}."},
+		{Role: "user", Content: "Order {{order_id}} for {{product}}"},
+	}}
+	require.Equal(t, []string{"product", "language", "order_id"}, v.Variables())
+
+	messages, err := v.Render(map[string]string{"product": "Acme", "language": "French", "order_id": "42"})
+	require.NoError(t, err)
+	require.Equal(t, "You support Acme customers in French.", messages[0].Content)
+	require.Equal(t, "Order 42 for Acme", messages[1].Content)
+	require.Equal(t, "You support {{ product }} customers in {{language}}.", v.Messages[0].Content)
+
+	_, err = v.Render(map[string]string{"product": "Acme"})
+	require.ErrorContains(t, err, "language, order_id")
+}
+
+func TestPostgresPromptStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	store := &PostgresStore{db: db}
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`INSERT INTO prompt_versions .* COALESCE\(MAX\(version\), 0\) \+ 1.* RETURNING version, created_at`).
+		WithArgs("support", `[{"role":"system","content":"Hi {{name}}"}]`, "gpt-4o", nil, "admin").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "created_at"}).AddRow(3, now))
+	v := &PromptVersion{PromptID: "support", Messages: []PromptMessage{{Role: "system", Content: "Hi {{name}}"}}, Model: "gpt-4o", CreatedBy: "admin"}
+	require.NoError(t, store.CreatePromptVersion(ctx, v))
+	require.Equal(t, 3, v.Version)
+
+	columns := []string{"prompt_id", "version", "messages", "model", "comment", "created_by", "created_at"}
+	mock.ExpectQuery(`SELECT .* FROM prompt_versions\s+WHERE prompt_id = \$1\s+ORDER BY version DESC\s+LIMIT 1`).WithArgs("support").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("support", 3, []byte(`[{"role":"system","content":"Hi"}]`), "gpt-4o", nil, "admin", now))
+	latest, err := store.GetPromptVersion(ctx, "support", 0)
+	require.NoError(t, err)
+	require.Equal(t, 3, latest.Version)
+	require.Equal(t, "Hi", latest.Messages[0].Content)
+
+	mock.ExpectQuery(`SELECT .* FROM prompt_versions\s+WHERE prompt_id = \$1 AND version = \$2`).WithArgs("support", 9).
+		WillReturnRows(sqlmock.NewRows(columns))
+	missing, err := store.GetPromptVersion(ctx, "support", 9)
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	mock.ExpectQuery(`SELECT DISTINCT ON \(prompt_id\) .* FROM prompt_versions`).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("billing", 1, []byte(`[]`), nil, "initial", nil, now).
+			AddRow("support", 3, []byte(`[]`), "gpt-4o", nil, nil, now))
+	prompts, err := store.ListPrompts(ctx)
+	require.NoError(t, err)
+	require.Len(t, prompts, 2)
+	require.Equal(t, "initial", prompts[0].Comment)
+
+	mock.ExpectExec(`DELETE FROM prompt_versions WHERE prompt_id = \$1`).WithArgs("support").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	require.NoError(t, store.DeletePrompt(ctx, "support"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryPromptStore(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	for _, content := range []string{"a", "b", "c"} {
+		require.NoError(t, store.CreatePromptVersion(ctx, &PromptVersion{PromptID: "support", Messages: []PromptMessage{{Role: "system", Content: content}}}))
+	}
+	require.NoError(t, store.CreatePromptVersion(ctx, &PromptVersion{PromptID: "billing"}))
+
+	latest, err := store.GetPromptVersion(ctx, "support", 0)
+	require.NoError(t, err)
+	require.Equal(t, 3, latest.Version)
+	require.Equal(t, "c", latest.Messages[0].Content)
+
+	first, err := store.GetPromptVersion(ctx, "support", 1)
+	require.NoError(t, err)
+	require.Equal(t, "a", first.Messages[0].Content)
+
+	versions, err := store.ListPromptVersions(ctx, "support", 2)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, 3, versions[0].Version)
+
+	prompts, err := store.ListPrompts(ctx)
+	require.NoError(t, err)
+	require.Len(t, prompts, 2)
+	require.Equal(t, "billing", prompts[0].PromptID)
+
+	require.NoError(t, store.DeletePrompt(ctx, "support"))
+	missing, err := store.GetPromptVersion(ctx, "support", 0)
+	require.NoError(t, err)
+	require.Nil(t, missing)
+}
@@ -332,6 +332,12 @@ func (trl *TenantRateLimiter) BurstForRate(rpm int, minBurst int) int {
 }
 
 func anonymousRateLimitKey(r *http.Request, trustedProxies []*net.IPNet) string {
+	return requestClientIP(r, trustedProxies)
+}
+
+// requestClientIP returns the client address of r, honoring forwarding
+// headers only when the direct peer is a trusted proxy.
+func requestClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
 	if r == nil {
 		return ""
 	}
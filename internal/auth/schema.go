@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+)
+
+// schemaMigrations lists, in order, a table (or column) introduced by each
+// migration in internal/auth/migrations. The applied schema version is the
+// highest migration whose object (and every earlier one) exists.
+var schemaMigrations = []struct {
+	version int
+	table   string
+	column  string
+}{
+	{version: 1, table: "api_keys"},
+	{version: 2, table: "daily_usage"},
+	{version: 3, table: "audit_logs"},
+	{version: 4, table: "invitation_links"},
+	{version: 5, table: "session_turns"},
+	{version: 6, table: "api_keys", column: "allowed_cidrs"},
+	{version: 7, table: "teams", column: "max_seats"},
+	{version: 8, table: "payload_logs"},
+	{version: 9, table: "model_access_groups"},
+	{version: 10, table: "model_providers"},
+	{version: 11, table: "config_versions"},
+	{version: 12, table: "prompt_versions"},
+}
+
+// LatestSchemaVersion is the schema version this build expects.
+var LatestSchemaVersion = schemaMigrations[len(schemaMigrations)-1].version
+
+// SchemaVersion returns the applied migration level of the database: the
+// latest recorded migration, or for databases migrated by hand the level
+// inferred from their tables.
+func (s *PostgresStore) SchemaVersion(ctx context.Context) (int, error) {
+	var tracked bool
+	if err := s.db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&tracked); err != nil {
+		return 0, fmt.Errorf("check table schema_migrations: %w", err)
+	}
+	if tracked {
+		version, err := recordedSchemaVersion(ctx, s.db)
+		if err != nil || version > 0 {
+			return version, err
+		}
+	}
+	return detectSchemaVersion(ctx, s.db)
+}
+
+// detectSchemaVersion infers the schema version from the tables present.
+func detectSchemaVersion(ctx context.Context, db queryRower) (int, error) {
+	version := 0
+	for _, m := range schemaMigrations {
+		var exists bool
+		if m.column != "" {
+			if err := db.QueryRowContext(ctx, `
+				SELECT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = $1 AND column_name = $2)`,
+				m.table, m.column).Scan(&exists); err != nil {
+				return 0, fmt.Errorf("check column %s.%s: %w", m.table, m.column, err)
+			}
+		} else if err := db.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, m.table).Scan(&exists); err != nil {
+			return 0, fmt.Errorf("check table %s: %w", m.table, err)
+		}
+		if !exists {
+			return version, nil
+		}
+		version = m.version
+	}
+	return version, nil
+}
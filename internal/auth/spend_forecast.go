@@ -0,0 +1,355 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ============================================================================
+// Spend Forecasts
+// ============================================================================
+
+// DefaultForecastHistoryDays is the number of days of usage a forecast is
+// fitted to by default.
+const DefaultForecastHistoryDays = 28
+
+// SpendForecastFilter selects the keys and teams to forecast.
+type SpendForecastFilter struct {
+	// Entity is SpendGroupKey, SpendGroupTeam, or empty for both.
+	Entity   string
+	APIKeyID *string
+	TeamID   *string
+	// HistoryDays is the number of complete days before today the forecast
+	// is fitted to (default DefaultForecastHistoryDays).
+	HistoryDays int
+	// Now is when the forecast is made (default time.Now).
+	Now time.Time
+}
+
+// SpendForecast projects the spend of a key or team to the end of its
+// budget period.
+type SpendForecast struct {
+	Entity         string         `json:"entity"`
+	ID             string         `json:"id"`
+	Name           string         `json:"name,omitempty"`
+	Spend          float64        `json:"spend"`
+	MaxBudget      float64        `json:"max_budget,omitempty"`
+	BudgetDuration BudgetDuration `json:"budget_duration,omitempty"`
+	// PeriodEnd is the next budget reset, or the end of the calendar month
+	// (UTC) for entities without a budget duration.
+	PeriodEnd time.Time `json:"period_end"`
+	// DailyBurnRate is the mean daily spend over the last 7 days.
+	DailyBurnRate  float64 `json:"daily_burn_rate"`
+	ProjectedSpend float64 `json:"projected_spend"`
+	// BudgetExhaustedAt is when the projection crosses MaxBudget, if it does
+	// before PeriodEnd.
+	BudgetExhaustedAt *time.Time `json:"budget_exhausted_at,omitempty"`
+	// OverBudget reports whether ProjectedSpend exceeds MaxBudget.
+	OverBudget bool `json:"over_budget"`
+}
+
+// SpendForecastReport lists spend forecasts, entities projected over budget
+// first.
+type SpendForecastReport struct {
+	HistoryStart string          `json:"history_start"`
+	HistoryEnd   string          `json:"history_end"`
+	Forecasts    []SpendForecast `json:"forecasts"`
+	GeneratedAt  time.Time       `json:"generated_at"`
+}
+
+// BuildSpendForecast projects the spend of keys and teams from their daily
+// usage: a linear trend with a day-of-week seasonal index, fitted to the
+// last HistoryDays complete days. Entities with neither a budget nor any
+// spend are left out.
+func BuildSpendForecast(ctx context.Context, store Store, filter SpendForecastFilter) (*SpendForecastReport, error) {
+	switch filter.Entity {
+	case "", SpendGroupKey, SpendGroupTeam:
+	default:
+		return nil, fmt.Errorf("unknown entity %q (use key or team)", filter.Entity)
+	}
+	now := filter.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	now = now.UTC()
+	historyDays := filter.HistoryDays
+	if historyDays <= 0 {
+		historyDays = DefaultForecastHistoryDays
+	}
+	today := startOfDay(now)
+	start := today.AddDate(0, 0, -historyDays)
+	end := today.AddDate(0, 0, -1)
+
+	usage, err := store.GetDailyUsage(ctx, DailyUsageFilter{
+		APIKeyID:  filter.APIKeyID,
+		TeamID:    filter.TeamID,
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.Format("2006-01-02"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	keySpend := make(map[string][]float64)
+	teamSpend := make(map[string][]float64)
+	addSpend := func(series map[string][]float64, id string, day int, spend float64) {
+		if series[id] == nil {
+			series[id] = make([]float64, historyDays)
+		}
+		series[id][day] += spend
+	}
+	for _, u := range usage {
+		date, err := time.Parse("2006-01-02", u.Date)
+		if err != nil {
+			continue
+		}
+		day := int(date.Sub(start).Hours() / 24)
+		if day < 0 || day >= historyDays {
+			continue
+		}
+		addSpend(keySpend, u.APIKeyID, day, u.Spend)
+		if u.TeamID != nil {
+			addSpend(teamSpend, *u.TeamID, day, u.Spend)
+		}
+	}
+
+	report := &SpendForecastReport{
+		HistoryStart: start.Format("2006-01-02"),
+		HistoryEnd:   end.Format("2006-01-02"),
+		Forecasts:    []SpendForecast{},
+		GeneratedAt:  now,
+	}
+	forecast := func(f SpendForecast, resetAt *time.Time, history []float64) {
+		if f.MaxBudget <= 0 && f.Spend == 0 && history == nil {
+			return
+		}
+		if history == nil {
+			history = make([]float64, historyDays)
+		}
+		f.PeriodEnd = forecastPeriodEnd(now, resetAt)
+		f.DailyBurnRate = meanSpend(history[max(0, len(history)-7):])
+		f.ProjectedSpend, f.BudgetExhaustedAt = fitSpendModel(start, history).project(now, f.PeriodEnd, f.Spend, f.MaxBudget)
+		f.OverBudget = f.MaxBudget > 0 && f.ProjectedSpend > f.MaxBudget
+		report.Forecasts = append(report.Forecasts, f)
+	}
+
+	if filter.Entity != SpendGroupTeam {
+		keys, err := forecastKeys(ctx, store, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			forecast(SpendForecast{
+				Entity:         SpendGroupKey,
+				ID:             k.ID,
+				Name:           k.Name,
+				Spend:          k.SpentBudget,
+				MaxBudget:      k.MaxBudget,
+				BudgetDuration: k.BudgetDuration,
+			}, k.BudgetResetAt, keySpend[k.ID])
+		}
+	}
+	if filter.Entity != SpendGroupKey {
+		teams, err := forecastTeams(ctx, store, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range teams {
+			f := SpendForecast{
+				Entity:         SpendGroupTeam,
+				ID:             t.ID,
+				Spend:          t.SpentBudget,
+				MaxBudget:      t.MaxBudget,
+				BudgetDuration: t.BudgetDuration,
+			}
+			if t.Alias != nil {
+				f.Name = *t.Alias
+			}
+			forecast(f, t.BudgetResetAt, teamSpend[t.ID])
+		}
+	}
+
+	sort.SliceStable(report.Forecasts, func(i, j int) bool {
+		a, b := report.Forecasts[i], report.Forecasts[j]
+		if a.OverBudget != b.OverBudget {
+			return a.OverBudget
+		}
+		if a.ProjectedSpend != b.ProjectedSpend {
+			return a.ProjectedSpend > b.ProjectedSpend
+		}
+		return a.Entity+a.ID < b.Entity+b.ID
+	})
+	return report, nil
+}
+
+func forecastKeys(ctx context.Context, store Store, filter SpendForecastFilter) ([]*APIKey, error) {
+	if filter.APIKeyID != nil {
+		key, err := store.GetAPIKeyByID(ctx, *filter.APIKeyID)
+		if err != nil || key == nil {
+			return nil, err
+		}
+		return []*APIKey{key}, nil
+	}
+	var keys []*APIKey
+	for offset := 0; ; offset += 100 {
+		page, _, err := store.ListAPIKeys(ctx, APIKeyFilter{TeamID: filter.TeamID, Limit: 100, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, page...)
+		if len(page) < 100 {
+			return keys, nil
+		}
+	}
+}
+
+func forecastTeams(ctx context.Context, store Store, filter SpendForecastFilter) ([]*Team, error) {
+	if filter.TeamID != nil {
+		team, err := store.GetTeam(ctx, *filter.TeamID)
+		if err != nil || team == nil {
+			return nil, err
+		}
+		return []*Team{team}, nil
+	}
+	if filter.APIKeyID != nil {
+		return nil, nil
+	}
+	var teams []*Team
+	for offset := 0; ; offset += 100 {
+		page, _, err := store.ListTeams(ctx, TeamFilter{Limit: 100, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, page...)
+		if len(page) < 100 {
+			return teams, nil
+		}
+	}
+}
+
+// forecastPeriodEnd returns the upcoming budget reset, or the start of the
+// next calendar month.
+func forecastPeriodEnd(now time.Time, resetAt *time.Time) time.Time {
+	if resetAt != nil && resetAt.After(now) {
+		return resetAt.UTC()
+	}
+	return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// spendModel forecasts daily spend as a linear trend scaled by a
+// day-of-week seasonal index.
+type spendModel struct {
+	start            time.Time // day 0 of the fit
+	intercept, slope float64
+	season           [7]float64 // by time.Weekday; 1 is an average day
+}
+
+// fitSpendModel fits a spendModel to daily spend starting on start. The
+// seasonal index is each weekday's spend relative to the centered 7-day
+// moving average, and the trend is fitted to the deseasonalized series.
+func fitSpendModel(start time.Time, daily []float64) spendModel {
+	m := spendModel{start: start}
+	for w := range m.season {
+		m.season[w] = 1
+	}
+	// A weekly pattern needs two full weeks to tell it from noise.
+	if len(daily) >= 14 {
+		var actual, average [7]float64
+		var window float64
+		for i := 0; i < 7; i++ {
+			window += daily[i]
+		}
+		for i := 3; i+3 < len(daily); i++ {
+			if i > 3 {
+				window += daily[i+3] - daily[i-4]
+			}
+			w := start.AddDate(0, 0, i).Weekday()
+			actual[w] += daily[i]
+			average[w] += window / 7
+		}
+		var sum float64
+		for w := range actual {
+			if average[w] > 0 {
+				m.season[w] = actual[w] / average[w]
+			}
+			sum += m.season[w]
+		}
+		if sum > 0 {
+			for w := range m.season {
+				m.season[w] *= 7 / sum
+			}
+		}
+	}
+	m.fitTrend(daily)
+	return m
+}
+
+// fitTrend fits the trend by least squares to daily spend divided by the
+// seasonal index. Weekdays without any spend carry no information about it.
+func (m *spendModel) fitTrend(daily []float64) {
+	var n, sx, sy, sxx, sxy float64
+	for i, spend := range daily {
+		s := m.season[m.start.AddDate(0, 0, i).Weekday()]
+		if s == 0 {
+			continue
+		}
+		x, y := float64(i), spend/s
+		n++
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+	}
+	if n == 0 {
+		return
+	}
+	if den := n*sxx - sx*sx; den != 0 {
+		m.slope = (n*sxy - sx*sy) / den
+	}
+	m.intercept = (sy - m.slope*sx) / n
+}
+
+// at returns the forecast spend of the day starting at day.
+func (m spendModel) at(day time.Time) float64 {
+	x := day.Sub(m.start).Hours() / 24
+	return math.Max(0, m.intercept+m.slope*x) * m.season[day.Weekday()]
+}
+
+// project adds the forecast spend from now to end to spend, and returns when
+// it crosses budget, if it does.
+func (m spendModel) project(now, end time.Time, spend, budget float64) (float64, *time.Time) {
+	var exhausted *time.Time
+	for day := startOfDay(now); day.Before(end); day = day.AddDate(0, 0, 1) {
+		from, to := day, day.AddDate(0, 0, 1)
+		if from.Before(now) {
+			from = now
+		}
+		if to.After(end) {
+			to = end
+		}
+		next := spend + m.at(day)*to.Sub(from).Hours()/24
+		if budget > 0 && exhausted == nil && spend < budget && next >= budget {
+			at := from.Add(time.Duration(float64(to.Sub(from)) * (budget - spend) / (next - spend)))
+			exhausted = &at
+		}
+		spend = next
+	}
+	return spend, exhausted
+}
+
+func meanSpend(daily []float64) float64 {
+	if len(daily) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, spend := range daily {
+		sum += spend
+	}
+	return sum / float64(len(daily))
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
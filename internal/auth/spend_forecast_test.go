@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFitSpendModel(t *testing.T) {
+	start := time.Date(2026, 8, 3, 0, 0, 0, 0, time.UTC) // a Monday
+
+	growing := make([]float64, 28)
+	for i := range growing {
+		growing[i] = 1 + 0.5*float64(i)
+	}
+	m := fitSpendModel(start, growing)
+	if got := m.at(start.AddDate(0, 0, 28)); math.Abs(got-15) > 1e-9 {
+		t.Fatalf("linear forecast = %v, want 15", got)
+	}
+
+	// Weekdays only: the seasonal index keeps weekends at zero.
+	weekly := make([]float64, 28)
+	for i := range weekly {
+		if w := start.AddDate(0, 0, i).Weekday(); w != time.Saturday && w != time.Sunday {
+			weekly[i] = 3
+		}
+	}
+	m = fitSpendModel(start, weekly)
+	if got := m.at(start.AddDate(0, 0, 28)); math.Abs(got-3) > 1e-9 {
+		t.Fatalf("Monday forecast = %v, want 3", got)
+	}
+	if got := m.at(start.AddDate(0, 0, 33)); got != 0 {
+		t.Fatalf("Saturday forecast = %v, want 0", got)
+	}
+}
+
+func TestBuildSpendForecast(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	team := "team-a"
+	alias := "Search"
+	if err := store.CreateTeam(ctx, &Team{ID: team, Alias: &alias, IsActive: true}); err != nil {
+		t.Fatalf("CreateTeam() error = %v", err)
+	}
+	for _, key := range []*APIKey{
+		{ID: "key-1", KeyHash: "h1", TeamID: &team, MaxBudget: 40, SpentBudget: 29, IsActive: true},
+		{ID: "key-2", KeyHash: "h2", MaxBudget: 100, SpentBudget: 1, IsActive: true},
+		{ID: "idle", KeyHash: "h3", IsActive: true},
+	} {
+		if err := store.CreateAPIKey(ctx, key); err != nil {
+			t.Fatalf("CreateAPIKey() error = %v", err)
+		}
+	}
+	now := time.Date(2026, 9, 15, 12, 0, 0, 0, time.UTC)
+	for day := 1; day <= 28; day++ {
+		at := now.AddDate(0, 0, -day)
+		for _, log := range []*UsageLog{
+			{APIKeyID: "key-1", TeamID: &team, Model: "gpt-4", Cost: 2, StartTime: at},
+			{APIKeyID: "key-2", Model: "gpt-4", Cost: 0.1, StartTime: at},
+		} {
+			if err := store.LogUsage(ctx, log); err != nil {
+				t.Fatalf("LogUsage() error = %v", err)
+			}
+		}
+	}
+
+	report, err := BuildSpendForecast(ctx, store, SpendForecastFilter{Now: now})
+	if err != nil {
+		t.Fatalf("BuildSpendForecast() error = %v", err)
+	}
+	if len(report.Forecasts) != 3 {
+		t.Fatalf("forecasts = %+v, want key-1, key-2 and team-a", report.Forecasts)
+	}
+
+	// $2/day for the 15.5 days left in September on top of $29.
+	f := report.Forecasts[0]
+	if f.ID != "key-1" || !f.OverBudget || math.Abs(f.ProjectedSpend-60) > 1e-9 || math.Abs(f.DailyBurnRate-2) > 1e-9 {
+		t.Fatalf("key-1 forecast = %+v", f)
+	}
+	if want := time.Date(2026, 9, 21, 0, 0, 0, 0, time.UTC); f.BudgetExhaustedAt == nil || !f.BudgetExhaustedAt.Equal(want) {
+		t.Fatalf("key-1 budget exhausted at %v, want %v", f.BudgetExhaustedAt, want)
+	}
+	if f := report.Forecasts[1]; f.Entity != SpendGroupTeam || f.Name != "Search" || f.OverBudget {
+		t.Fatalf("team forecast = %+v", f)
+	}
+	if f := report.Forecasts[2]; f.ID != "key-2" || f.OverBudget || f.BudgetExhaustedAt != nil {
+		t.Fatalf("key-2 forecast = %+v", f)
+	}
+
+	keyID := "key-2"
+	report, err = BuildSpendForecast(ctx, store, SpendForecastFilter{APIKeyID: &keyID, Now: now})
+	if err != nil {
+		t.Fatalf("BuildSpendForecast() error = %v", err)
+	}
+	if len(report.Forecasts) != 1 || report.Forecasts[0].ID != "key-2" {
+		t.Fatalf("filtered forecasts = %+v", report.Forecasts)
+	}
+
+	if _, err := BuildSpendForecast(ctx, store, SpendForecastFilter{Entity: "user"}); err == nil {
+		t.Fatal("expected an error for an unknown entity")
+	}
+}
@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Spend Reports
+// ============================================================================
+
+// Spend report dimensions.
+const (
+	SpendGroupKey   = "key"
+	SpendGroupTeam  = "team"
+	SpendGroupModel = "model"
+	SpendGroupTag   = "tag"
+	SpendGroupDay   = "day"
+)
+
+// DailyTagUsage contains daily usage attributed to one request tag. A request
+// with several tags counts toward each of them.
+type DailyTagUsage struct {
+	Date         string
+	Tag          string
+	InputTokens  int64
+	OutputTokens int64
+	Spend        float64
+	APIRequests  int64
+}
+
+// SpendReportFilter selects the usage covered by a spend report.
+type SpendReportFilter struct {
+	StartDate string // YYYY-MM-DD, inclusive
+	EndDate   string // YYYY-MM-DD, inclusive
+	APIKeyID  *string
+	TeamID    *string
+	Model     *string
+	GroupBy   []string
+}
+
+// SpendReportRow is the usage of one group. Only the fields of the report's
+// dimensions are set.
+type SpendReportRow struct {
+	Key          string  `json:"key,omitempty"`
+	Team         string  `json:"team,omitempty"`
+	Model        string  `json:"model,omitempty"`
+	Tag          string  `json:"tag,omitempty"`
+	Day          string  `json:"day,omitempty"`
+	APIRequests  int64   `json:"api_requests"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	Spend        float64 `json:"spend"`
+	// MaxBudget is the budget of the row's tag, when it has one.
+	MaxBudget *float64 `json:"max_budget,omitempty"`
+}
+
+// SpendReport is spend over a date range grouped by one or more dimensions.
+type SpendReport struct {
+	StartDate   string           `json:"start_date"`
+	EndDate     string           `json:"end_date"`
+	GroupBy     []string         `json:"group_by"`
+	Rows        []SpendReportRow `json:"rows"`
+	TotalSpend  float64          `json:"total_spend"`
+	Summary     *UsageStats      `json:"summary,omitempty"`
+	GeneratedAt time.Time        `json:"generated_at"`
+}
+
+// ParseSpendGroupBy parses a comma-separated list of dimensions. Tags cannot
+// be combined with key, team or model because tag usage is only aggregated
+// per day.
+func ParseSpendGroupBy(s string) ([]string, error) {
+	var dims []string
+	for _, d := range strings.Split(s, ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			dims = append(dims, d)
+		}
+	}
+	if len(dims) == 0 {
+		return nil, fmt.Errorf("group_by is required")
+	}
+	return dims, ValidateSpendGroupBy(dims)
+}
+
+// ValidateSpendGroupBy checks that dims are known and can be combined.
+func ValidateSpendGroupBy(dims []string) error {
+	seen := make(map[string]bool, len(dims))
+	for _, d := range dims {
+		switch d {
+		case SpendGroupKey, SpendGroupTeam, SpendGroupModel, SpendGroupTag, SpendGroupDay:
+		default:
+			return fmt.Errorf("unknown group_by %q (use key, team, model, tag or day)", d)
+		}
+		if seen[d] {
+			return fmt.Errorf("duplicate group_by %q", d)
+		}
+		seen[d] = true
+	}
+	if seen[SpendGroupTag] && (seen[SpendGroupKey] || seen[SpendGroupTeam] || seen[SpendGroupModel]) {
+		return fmt.Errorf("group_by tag can only be combined with day")
+	}
+	return nil
+}
+
+// BuildSpendReport aggregates daily usage into a report. Totals come from
+// GetUsageStats, so tag reports, where requests count once per tag, may sum
+// to more than the total.
+func BuildSpendReport(ctx context.Context, store Store, filter SpendReportFilter) (*SpendReport, error) {
+	if err := ValidateSpendGroupBy(filter.GroupBy); err != nil {
+		return nil, err
+	}
+	dims := make(map[string]bool, len(filter.GroupBy))
+	for _, d := range filter.GroupBy {
+		dims[d] = true
+	}
+
+	daily := DailyUsageFilter{
+		APIKeyID:  filter.APIKeyID,
+		TeamID:    filter.TeamID,
+		Model:     filter.Model,
+		StartDate: filter.StartDate,
+		EndDate:   filter.EndDate,
+		GroupBy:   filter.GroupBy,
+	}
+	groups := make(map[SpendReportRow]*SpendReportRow)
+	add := func(key SpendReportRow, requests, input, output int64, spend float64) {
+		row, ok := groups[key]
+		if !ok {
+			row = &SpendReportRow{Key: key.Key, Team: key.Team, Model: key.Model, Tag: key.Tag, Day: key.Day}
+			groups[key] = row
+		}
+		row.APIRequests += requests
+		row.InputTokens += input
+		row.OutputTokens += output
+		row.Spend += spend
+	}
+
+	if dims[SpendGroupTag] {
+		usage, err := store.GetDailyTagUsage(ctx, daily)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range usage {
+			key := SpendReportRow{Tag: u.Tag}
+			if dims[SpendGroupDay] {
+				key.Day = u.Date
+			}
+			add(key, u.APIRequests, u.InputTokens, u.OutputTokens, u.Spend)
+		}
+	} else {
+		usage, err := store.GetDailyUsage(ctx, daily)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range usage {
+			var key SpendReportRow
+			if dims[SpendGroupKey] {
+				key.Key = u.APIKeyID
+			}
+			if dims[SpendGroupTeam] && u.TeamID != nil {
+				key.Team = *u.TeamID
+			}
+			if dims[SpendGroupModel] && u.Model != nil {
+				key.Model = *u.Model
+			}
+			if dims[SpendGroupDay] {
+				key.Day = u.Date
+			}
+			add(key, u.APIRequests, u.InputTokens, u.OutputTokens, u.Spend)
+		}
+	}
+
+	report := &SpendReport{
+		StartDate:   filter.StartDate,
+		EndDate:     filter.EndDate,
+		GroupBy:     append([]string(nil), filter.GroupBy...),
+		Rows:        make([]SpendReportRow, 0, len(groups)),
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, row := range groups {
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Spend != b.Spend {
+			return a.Spend > b.Spend
+		}
+		return a.Key+a.Team+a.Model+a.Tag < b.Key+b.Team+b.Model+b.Tag
+	})
+
+	statsFilter := UsageFilter{APIKeyID: filter.APIKeyID, TeamID: filter.TeamID, Model: filter.Model}
+	if start, err := time.Parse("2006-01-02", filter.StartDate); err == nil {
+		statsFilter.StartTime = start
+	}
+	if end, err := time.Parse("2006-01-02", filter.EndDate); err == nil {
+		statsFilter.EndTime = end.Add(24*time.Hour - time.Nanosecond)
+	} else {
+		statsFilter.EndTime = time.Now()
+	}
+	stats, err := store.GetUsageStats(ctx, statsFilter)
+	if err != nil {
+		return nil, err
+	}
+	report.Summary = stats
+	report.TotalSpend = stats.TotalCost
+	return report, nil
+}
+
+// WriteCSV writes the report rows as CSV with one column per dimension.
+func (r *SpendReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := append(append([]string(nil), r.GroupBy...), "api_requests", "input_tokens", "output_tokens", "spend")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		record := make([]string, 0, len(header))
+		for _, d := range r.GroupBy {
+			record = append(record, row.dimension(d))
+		}
+		record = append(record,
+			strconv.FormatInt(row.APIRequests, 10),
+			strconv.FormatInt(row.InputTokens, 10),
+			strconv.FormatInt(row.OutputTokens, 10),
+			strconv.FormatFloat(row.Spend, 'f', 6, 64),
+		)
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (r SpendReportRow) dimension(d string) string {
+	switch d {
+	case SpendGroupKey:
+		return r.Key
+	case SpendGroupTeam:
+		return r.Team
+	case SpendGroupModel:
+		return r.Model
+	case SpendGroupTag:
+		return r.Tag
+	case SpendGroupDay:
+		return r.Day
+	}
+	return ""
+}
@@ -0,0 +1,313 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/goccy/go-json"
+)
+
+// ============================================================================
+// Scheduled Spend Report Job
+// ============================================================================
+
+// SpendReportFile is an encoded monthly spend report.
+type SpendReportFile struct {
+	Period      string // YYYY-MM
+	Name        string
+	ContentType string
+	Data        []byte
+	Report      *SpendReport
+}
+
+// SpendReportSink delivers scheduled spend reports.
+type SpendReportSink interface {
+	Name() string
+	Deliver(ctx context.Context, file *SpendReportFile) error
+}
+
+// SpendReportJobConfig configures the monthly spend report job.
+type SpendReportJobConfig struct {
+	GroupBy []string // Default team, model
+	Format  string   // "csv" (default) or "json"
+	Sinks   []SpendReportSink
+	// Claim reports whether this instance should send the report for period,
+	// so that replicas send it once. By default each period is sent once per
+	// process.
+	Claim func(ctx context.Context, period string) (bool, error)
+}
+
+type spendReportJob struct {
+	store  Store
+	cfg    SpendReportJobConfig
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu   sync.Mutex
+	sent map[string]bool
+}
+
+// NewSpendReportJob returns a job that, on the first day of each month,
+// reports the previous month's spend to the configured sinks. The job runner
+// runs it hourly, so it fires at least once on that day.
+func NewSpendReportJob(store Store, cfg SpendReportJobConfig, logger *slog.Logger) (Job, error) {
+	j, err := newSpendReportJob(store, cfg, logger)
+	if err != nil {
+		return Job{}, err
+	}
+	return Job{Name: "spend_report", Run: j.run}, nil
+}
+
+func newSpendReportJob(store Store, cfg SpendReportJobConfig, logger *slog.Logger) (*spendReportJob, error) {
+	if store == nil {
+		return nil, fmt.Errorf("spend report: store is required")
+	}
+	if len(cfg.GroupBy) == 0 {
+		cfg.GroupBy = []string{SpendGroupTeam, SpendGroupModel}
+	}
+	if err := ValidateSpendGroupBy(cfg.GroupBy); err != nil {
+		return nil, fmt.Errorf("spend report: %w", err)
+	}
+	switch cfg.Format {
+	case "":
+		cfg.Format = "csv"
+	case "csv", "json":
+	default:
+		return nil, fmt.Errorf("spend report: unknown format %q", cfg.Format)
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &spendReportJob{store: store, cfg: cfg, logger: logger, now: time.Now, sent: make(map[string]bool)}, nil
+}
+
+func (j *spendReportJob) run(ctx context.Context) error {
+	now := j.now().UTC()
+	if now.Day() != 1 {
+		return nil
+	}
+	start := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, -1)
+	period := start.Format("2006-01")
+
+	j.mu.Lock()
+	done := j.sent[period]
+	j.sent[period] = true
+	j.mu.Unlock()
+	if done {
+		return nil
+	}
+	if j.cfg.Claim != nil {
+		ok, err := j.cfg.Claim(ctx, period)
+		if err != nil {
+			j.forget(period)
+			return fmt.Errorf("claim spend report %s: %w", period, err)
+		}
+		if !ok {
+			return nil
+		}
+	}
+
+	report, err := BuildSpendReport(ctx, j.store, SpendReportFilter{
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.Format("2006-01-02"),
+		GroupBy:   j.cfg.GroupBy,
+	})
+	if err != nil {
+		j.forget(period)
+		return fmt.Errorf("build spend report %s: %w", period, err)
+	}
+	file, err := encodeSpendReport(period, j.cfg.Format, report)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sink := range j.cfg.Sinks {
+		if err := sink.Deliver(ctx, file); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+			continue
+		}
+		j.logger.Info("spend report delivered", "period", period, "sink", sink.Name(), "rows", len(report.Rows))
+	}
+	return errors.Join(errs...)
+}
+
+func (j *spendReportJob) forget(period string) {
+	j.mu.Lock()
+	delete(j.sent, period)
+	j.mu.Unlock()
+}
+
+func encodeSpendReport(period, format string, report *SpendReport) (*SpendReportFile, error) {
+	file := &SpendReportFile{Period: period, Report: report}
+	if format == "json" {
+		data, err := json.Marshal(report)
+		if err != nil {
+			return nil, fmt.Errorf("encode spend report: %w", err)
+		}
+		file.Name = "spend_report_" + period + ".json"
+		file.ContentType = "application/json"
+		file.Data = data
+		return file, nil
+	}
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		return nil, fmt.Errorf("encode spend report: %w", err)
+	}
+	file.Name = "spend_report_" + period + ".csv"
+	file.ContentType = "text/csv"
+	file.Data = buf.Bytes()
+	return file, nil
+}
+
+// ObjectStoreSpendReportSinkConfig configures upload of spend reports to S3
+// or GCS. GCS is reached through its S3-compatible XML API with HMAC
+// credentials.
+type ObjectStoreSpendReportSinkConfig struct {
+	Provider        string // "s3" (default) or "gcs"
+	Bucket          string
+	Region          string
+	Endpoint        string
+	AccessKeyID     string // Uses the default credential chain when empty
+	SecretAccessKey string
+	PathPrefix      string
+}
+
+// ObjectStoreSpendReportSink uploads each report as <prefix>/<file name>.
+type ObjectStoreSpendReportSink struct {
+	cfg    ObjectStoreSpendReportSinkConfig
+	client *s3.Client
+}
+
+// NewObjectStoreSpendReportSink creates an object store sink.
+func NewObjectStoreSpendReportSink(ctx context.Context, cfg ObjectStoreSpendReportSinkConfig) (*ObjectStoreSpendReportSink, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("spend report: bucket is required")
+	}
+	client, err := newObjectStoreClient(ctx, cfg.Provider, cfg.Region, cfg.Endpoint, cfg.AccessKeyID, cfg.SecretAccessKey)
+	if err != nil {
+		return nil, fmt.Errorf("spend report: %w", err)
+	}
+	return &ObjectStoreSpendReportSink{cfg: cfg, client: client}, nil
+}
+
+// Name returns the sink name.
+func (s *ObjectStoreSpendReportSink) Name() string {
+	if s.cfg.Provider == "gcs" {
+		return "gcs"
+	}
+	return "s3"
+}
+
+// Deliver uploads the report.
+func (s *ObjectStoreSpendReportSink) Deliver(ctx context.Context, file *SpendReportFile) error {
+	if _, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.cfg.Bucket),
+		Key:         aws.String(path.Join(s.cfg.PathPrefix, file.Name)),
+		ContentType: aws.String(file.ContentType),
+		Body:        bytes.NewReader(file.Data),
+	}); err != nil {
+		return fmt.Errorf("put object: %w", err)
+	}
+	return nil
+}
+
+// EmailSpendReportSinkConfig configures SMTP delivery of spend reports.
+type EmailSpendReportSinkConfig struct {
+	Host     string
+	Port     int // Default 587
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// EmailSpendReportSink emails each report as an attachment, using STARTTLS
+// when the server offers it and PLAIN auth when a username is set.
+type EmailSpendReportSink struct {
+	cfg      EmailSpendReportSinkConfig
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailSpendReportSink creates an email sink.
+func NewEmailSpendReportSink(cfg EmailSpendReportSinkConfig) (*EmailSpendReportSink, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("spend report: smtp host is required")
+	}
+	if cfg.From == "" || len(cfg.To) == 0 {
+		return nil, fmt.Errorf("spend report: email from and to are required")
+	}
+	if cfg.Port <= 0 {
+		cfg.Port = 587
+	}
+	return &EmailSpendReportSink{cfg: cfg, sendMail: smtp.SendMail}, nil
+}
+
+// Name returns the sink name.
+func (s *EmailSpendReportSink) Name() string { return "email" }
+
+// Deliver emails the report. The context only bounds the wait; an SMTP
+// exchange already in progress is not interrupted.
+func (s *EmailSpendReportSink) Deliver(ctx context.Context, file *SpendReportFile) error {
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	msg := s.message(file)
+
+	done := make(chan error, 1)
+	go func() { done <- s.sendMail(addr, auth, s.cfg.From, s.cfg.To, msg) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *EmailSpendReportSink) message(file *SpendReportFile) []byte {
+	const boundary = "llmux-spend-report"
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: [LLMux] Spend report for %s\r\n", file.Period)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(&b, "Spend report for %s to %s.\r\n", file.Report.StartDate, file.Report.EndDate)
+	fmt.Fprintf(&b, "Total spend: $%.2f\r\n", file.Report.TotalSpend)
+	if file.Report.Summary != nil {
+		fmt.Fprintf(&b, "Requests: %d\r\n", file.Report.Summary.TotalRequests)
+	}
+	fmt.Fprintf(&b, "Grouped by: %s\r\n\r\n", strings.Join(file.Report.GroupBy, ", "))
+
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	fmt.Fprintf(&b, "Content-Type: %s; name=%q\r\n", file.ContentType, file.Name)
+	b.WriteString("Content-Transfer-Encoding: base64\r\n")
+	fmt.Fprintf(&b, "Content-Disposition: attachment; filename=%q\r\n\r\n", file.Name)
+	encoded := base64.StdEncoding.EncodeToString(file.Data)
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded + "\r\n")
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return []byte(b.String())
+}
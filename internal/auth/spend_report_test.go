@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func seedSpendLogs(t *testing.T, store *MemoryStore) {
+	t.Helper()
+	teamA, teamB := "team-a", "team-b"
+	logs := []*UsageLog{
+		{APIKeyID: "key-1", TeamID: &teamA, Model: "gpt-4", Provider: "openai", InputTokens: 10, OutputTokens: 5, Cost: 1.5,
+			RequestTags: []string{"search", "prod"}, StartTime: time.Date(2026, 9, 3, 10, 0, 0, 0, time.UTC)},
+		{APIKeyID: "key-1", TeamID: &teamA, Model: "gpt-4", Provider: "openai", InputTokens: 20, OutputTokens: 5, Cost: 2.5,
+			RequestTags: []string{"search"}, StartTime: time.Date(2026, 9, 4, 10, 0, 0, 0, time.UTC)},
+		{APIKeyID: "key-2", TeamID: &teamB, Model: "gpt-3.5-turbo", Provider: "openai", InputTokens: 5, OutputTokens: 5, Cost: 0.5,
+			StartTime: time.Date(2026, 9, 4, 12, 0, 0, 0, time.UTC)},
+		{APIKeyID: "key-2", TeamID: &teamB, Model: "gpt-4", Provider: "openai", Cost: 9,
+			StartTime: time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, log := range logs {
+		if err := store.LogUsage(context.Background(), log); err != nil {
+			t.Fatalf("LogUsage() error = %v", err)
+		}
+	}
+}
+
+func TestBuildSpendReport(t *testing.T) {
+	store := NewMemoryStore()
+	seedSpendLogs(t, store)
+	ctx := context.Background()
+
+	report, err := BuildSpendReport(ctx, store, SpendReportFilter{
+		StartDate: "2026-09-01",
+		EndDate:   "2026-09-30",
+		GroupBy:   []string{SpendGroupTeam, SpendGroupModel},
+	})
+	if err != nil {
+		t.Fatalf("BuildSpendReport() error = %v", err)
+	}
+	if len(report.Rows) != 2 {
+		t.Fatalf("rows = %+v, want 2", report.Rows)
+	}
+	if row := report.Rows[0]; row.Team != "team-a" || row.Model != "gpt-4" || row.Spend != 4 || row.APIRequests != 2 || row.InputTokens != 30 {
+		t.Fatalf("first row = %+v", row)
+	}
+	if report.TotalSpend != 4.5 {
+		t.Fatalf("total spend = %v, want 4.5", report.TotalSpend)
+	}
+
+	report, err = BuildSpendReport(ctx, store, SpendReportFilter{
+		StartDate: "2026-09-01",
+		EndDate:   "2026-09-30",
+		GroupBy:   []string{SpendGroupTag},
+	})
+	if err != nil {
+		t.Fatalf("BuildSpendReport(tag) error = %v", err)
+	}
+	if len(report.Rows) != 2 || report.Rows[0].Tag != "search" || report.Rows[0].Spend != 4 || report.Rows[1].Tag != "prod" {
+		t.Fatalf("tag rows = %+v", report.Rows)
+	}
+
+	var csv strings.Builder
+	if err := report.WriteCSV(&csv); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want := "tag,api_requests,input_tokens,output_tokens,spend\nsearch,2,30,10,4.000000\nprod,1,10,5,1.500000\n"
+	if csv.String() != want {
+		t.Fatalf("csv = %q, want %q", csv.String(), want)
+	}
+}
+
+func TestParseSpendGroupBy(t *testing.T) {
+	if dims, err := ParseSpendGroupBy("team, day"); err != nil || len(dims) != 2 {
+		t.Fatalf("ParseSpendGroupBy() = %v, %v", dims, err)
+	}
+	for _, s := range []string{"", "region", "tag,model", "day,day"} {
+		if _, err := ParseSpendGroupBy(s); err == nil {
+			t.Errorf("ParseSpendGroupBy(%q) expected error", s)
+		}
+	}
+}
+
+type recordingSpendReportSink struct {
+	mu    sync.Mutex
+	files []*SpendReportFile
+}
+
+func (s *recordingSpendReportSink) Name() string { return "recording" }
+
+func (s *recordingSpendReportSink) Deliver(_ context.Context, file *SpendReportFile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files = append(s.files, file)
+	return nil
+}
+
+func TestSpendReportJob(t *testing.T) {
+	store := NewMemoryStore()
+	seedSpendLogs(t, store)
+	sink := &recordingSpendReportSink{}
+	claims := 0
+	job, err := newSpendReportJob(store, SpendReportJobConfig{
+		Sinks: []SpendReportSink{sink},
+		Claim: func(_ context.Context, period string) (bool, error) {
+			claims++
+			return period == "2026-09", nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("newSpendReportJob() error = %v", err)
+	}
+
+	now := time.Date(2026, 9, 30, 12, 0, 0, 0, time.UTC)
+	job.now = func() time.Time { return now }
+	if err := job.run(context.Background()); err != nil || len(sink.files) != 0 {
+		t.Fatalf("run() before month end = %v, files = %d", err, len(sink.files))
+	}
+
+	now = time.Date(2026, 10, 1, 0, 30, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		if err := job.run(context.Background()); err != nil {
+			t.Fatalf("run() error = %v", err)
+		}
+	}
+	if len(sink.files) != 1 || claims != 1 {
+		t.Fatalf("files = %d, claims = %d, want 1 and 1", len(sink.files), claims)
+	}
+	file := sink.files[0]
+	if file.Period != "2026-09" || file.Name != "spend_report_2026-09.csv" || file.Report.EndDate != "2026-09-30" {
+		t.Fatalf("file = %+v", file)
+	}
+	if !strings.HasPrefix(string(file.Data), "team,model,") {
+		t.Fatalf("data = %q", file.Data)
+	}
+
+	// Another replica claimed the period.
+	now = time.Date(2026, 11, 1, 0, 30, 0, 0, time.UTC)
+	if err := job.run(context.Background()); err != nil || len(sink.files) != 1 {
+		t.Fatalf("run() unclaimed = %v, files = %d", err, len(sink.files))
+	}
+}
+
+func TestEmailSpendReportSink(t *testing.T) {
+	sink, err := NewEmailSpendReportSink(EmailSpendReportSinkConfig{
+		Host: "smtp.example.com",
+		From: "llmux@example.com",
+		To:   []string{"finops@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("NewEmailSpendReportSink() error = %v", err)
+	}
+	var gotAddr, gotMsg string
+	sink.sendMail = func(addr string, a smtp.Auth, _ string, _ []string, msg []byte) error {
+		if a != nil {
+			t.Error("expected no smtp auth without username")
+		}
+		gotAddr, gotMsg = addr, string(msg)
+		return nil
+	}
+
+	report := &SpendReport{StartDate: "2026-09-01", EndDate: "2026-09-30", GroupBy: []string{"team"}, TotalSpend: 4.5}
+	file, err := encodeSpendReport("2026-09", "csv", report)
+	if err != nil {
+		t.Fatalf("encodeSpendReport() error = %v", err)
+	}
+	if err := sink.Deliver(context.Background(), file); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" {
+		t.Fatalf("addr = %q", gotAddr)
+	}
+	for _, want := range []string{"Subject: [LLMux] Spend report for 2026-09", "Total spend: $4.50", `filename="spend_report_2026-09.csv"`} {
+		if !strings.Contains(gotMsg, want) {
+			t.Fatalf("message missing %q: %q", want, gotMsg)
+		}
+	}
+}
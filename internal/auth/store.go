@@ -96,9 +96,11 @@ type Store interface {
 	// ========================================================================
 	GetEndUser(ctx context.Context, userID string) (*EndUser, error)
 	CreateEndUser(ctx context.Context, endUser *EndUser) error
+	UpdateEndUser(ctx context.Context, endUser *EndUser) error
 	UpdateEndUserSpent(ctx context.Context, userID string, amount float64) error
 	BlockEndUser(ctx context.Context, userID string, blocked bool) error
 	DeleteEndUser(ctx context.Context, userID string) error
+	ListEndUsers(ctx context.Context, filter EndUserFilter) ([]*EndUser, int64, error)
 
 	// ========================================================================
 	// Usage Logging and Analytics
@@ -106,6 +108,7 @@ type Store interface {
 	LogUsage(ctx context.Context, log *UsageLog) error
 	GetUsageStats(ctx context.Context, filter UsageFilter) (*UsageStats, error)
 	GetDailyUsage(ctx context.Context, filter DailyUsageFilter) ([]*DailyUsage, error)
+	GetDailyTagUsage(ctx context.Context, filter DailyUsageFilter) ([]*DailyTagUsage, error)
 
 	// ========================================================================
 	// Budget Reset Job Queries
@@ -153,10 +156,18 @@ type UserFilter struct {
 	Offset         int
 }
 
+// EndUserFilter contains filter options for listing end users.
+type EndUserFilter struct {
+	Blocked *bool
+	Limit   int
+	Offset  int
+}
+
 // UsageFilter contains filter options for usage queries.
 type UsageFilter struct {
 	APIKeyID  *string
 	TeamID    *string
+	EndUserID *string
 	Model     *string
 	Provider  *string
 	StartTime time.Time
@@ -169,6 +180,7 @@ type DailyUsageFilter struct {
 	TeamID    *string
 	Model     *string
 	Provider  *string
+	Tag       *string // Only applies to GetDailyTagUsage
 	StartDate string
 	EndDate   string
 	GroupBy   []string
@@ -2,6 +2,9 @@
 package auth
 
 import (
+	"fmt"
+	"net"
+	"strings"
 	"time"
 )
 
@@ -31,6 +34,7 @@ type APIKey struct {
 	// Access control
 	AllowedModels []string `json:"allowed_models,omitempty"` // Empty = all models
 	KeyType       KeyType  `json:"key_type,omitempty"`
+	AllowedCIDRs  []string `json:"allowed_cidrs,omitempty"` // Source IPs or CIDRs; empty = any
 
 	// Rate limiting (LiteLLM compatible)
 	TPMLimit            *int64           `json:"tpm_limit,omitempty"`             // Tokens per minute
@@ -54,6 +58,9 @@ type APIKey struct {
 	// Status
 	IsActive bool `json:"is_active"`
 	Blocked  bool `json:"blocked"` // Explicitly blocked
+	// Virtual marks keys derived from a JWT's claims. It is never persisted
+	// or accepted from callers.
+	Virtual bool `json:"-"`
 
 	// Timestamps
 	CreatedAt  time.Time  `json:"created_at"`
@@ -87,6 +94,9 @@ type Team struct {
 	// Access control
 	Models []string `json:"models,omitempty"`
 
+	// Membership
+	MaxSeats *int `json:"max_seats,omitempty"` // Maximum members (nil or 0 = unlimited)
+
 	// Status
 	IsActive bool `json:"is_active"`
 	Blocked  bool `json:"blocked"`
@@ -230,6 +240,11 @@ func (k *APIKey) Clone() *APIKey {
 		copy(clone.AllowedModels, k.AllowedModels)
 	}
 
+	if k.AllowedCIDRs != nil {
+		clone.AllowedCIDRs = make([]string, len(k.AllowedCIDRs))
+		copy(clone.AllowedCIDRs, k.AllowedCIDRs)
+	}
+
 	if k.ModelTPMLimit != nil {
 		clone.ModelTPMLimit = make(map[string]int64, len(k.ModelTPMLimit))
 		for k, v := range k.ModelTPMLimit {
@@ -377,6 +392,28 @@ func (k *APIKey) CanAccessModel(model string) bool {
 	return false
 }
 
+// AllowsIP checks if the API key may be used from the source IP ip.
+// Entries that fail to parse never match.
+func (k *APIKey) AllowsIP(ip string) bool {
+	if len(k.AllowedCIDRs) == 0 {
+		return true
+	}
+	parsed := normalizeIP(net.ParseIP(ip))
+	if parsed == nil {
+		return false
+	}
+	allowed, _ := parseTrustedProxyCIDRs(k.AllowedCIDRs)
+	return ipInNets(parsed, allowed)
+}
+
+// ValidateCIDRs reports entries of cidrs that are neither an IP nor a CIDR.
+func ValidateCIDRs(cidrs []string) error {
+	if _, invalid := parseTrustedProxyCIDRs(cidrs); len(invalid) > 0 {
+		return fmt.Errorf("invalid allowed_cidrs entries: %s", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
 // IsExpired checks if the API key has expired.
 func (k *APIKey) IsExpired() bool {
 	if k.ExpiresAt == nil {
@@ -406,6 +443,53 @@ func (k *APIKey) IsBlocked() bool {
 	return k.Blocked || !k.IsActive
 }
 
+// IsSandbox reports whether the key is a test key whose requests are served
+// by the gateway's mock provider instead of real providers.
+func (k *APIKey) IsSandbox() bool {
+	sandbox, _ := k.Metadata["sandbox"].(bool)
+	return sandbox
+}
+
+// IsVirtual reports whether the key was derived from a JWT's claims rather
+// than loaded from the store, so it has no stored spend to update.
+func (k *APIKey) IsVirtual() bool {
+	return k.Virtual
+}
+
+// reservedKeyMetadata are the metadata keys the gateway sets itself to mark
+// how a key is served and managed.
+var reservedKeyMetadata = []string{"virtual", "temporary", "sandbox"}
+
+// WithoutReservedMetadata returns m without the keys the gateway reserves,
+// so caller-supplied metadata cannot mark a key sandboxed or temporary.
+func WithoutReservedMetadata(m Metadata) Metadata {
+	for _, key := range reservedKeyMetadata {
+		delete(m, key)
+	}
+	return m
+}
+
+// IsTemporary reports whether the key is a short-lived key minted through
+// /key/temporary, which the job runner deletes once it expires.
+func (k *APIKey) IsTemporary() bool {
+	temporary, _ := k.Metadata["temporary"].(bool)
+	return temporary
+}
+
+// MaxOutputTokensHardCap returns the most completion tokens the gateway
+// allows for one request made with the key, or 0 when uncapped.
+func (k *APIKey) MaxOutputTokensHardCap() int {
+	switch v := k.Metadata["max_output_tokens_hard_cap"].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
 // NeedsBudgetReset checks if the API key budget needs to be reset.
 func (k *APIKey) NeedsBudgetReset() bool {
 	if k.BudgetResetAt == nil {
@@ -3,38 +3,61 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"net"
+	"net/url"
 	"os"
+	"path"
+	"regexp"
 	"strings"
+	"text/template"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/blueberrycongee/llmux/internal/governance"
 	"github.com/blueberrycongee/llmux/internal/observability"
 )
 
 // Config represents the complete gateway configuration.
 type Config struct {
-	Server        ServerConfig                      `yaml:"server"`
-	Deployment    DeploymentConfig                  `yaml:"deployment"`
-	Providers     []ProviderConfig                  `yaml:"providers"`
-	Routing       RoutingConfig                     `yaml:"routing"`
-	Stream        StreamConfig                      `yaml:"stream"`
-	RateLimit     RateLimitConfig                   `yaml:"rate_limit"`
-	Governance    GovernanceConfig                  `yaml:"governance"`
-	Logging       LoggingConfig                     `yaml:"logging"`
-	Metrics       MetricsConfig                     `yaml:"metrics"`
-	Tracing       TracingConfig                     `yaml:"tracing"`
-	Observability observability.ObservabilityConfig `yaml:"observability"`
-	CORS          CORSConfig                        `yaml:"cors"`
-	Auth          AuthConfig                        `yaml:"auth"`
-	Database      DatabaseConfig                    `yaml:"database"`
-	Cache         CacheConfig                       `yaml:"cache"`
-	HealthCheck   HealthCheckConfig                 `yaml:"healthcheck"`
-	MCP           MCPConfig                         `yaml:"mcp"`
-	Vault         VaultConfig                       `yaml:"vault"`
-	PricingFile   string                            `yaml:"pricing_file"`
+	Server           ServerConfig                      `yaml:"server"`
+	ConfigSource     ConfigSourceConfig                `yaml:"config"`
+	Deployment       DeploymentConfig                  `yaml:"deployment"`
+	Providers        []ProviderConfig                  `yaml:"providers"`
+	Routing          RoutingConfig                     `yaml:"routing"`
+	Stream           StreamConfig                      `yaml:"stream"`
+	RateLimit        RateLimitConfig                   `yaml:"rate_limit"`
+	Governance       GovernanceConfig                  `yaml:"governance"`
+	Logging          LoggingConfig                     `yaml:"logging"`
+	Metrics          MetricsConfig                     `yaml:"metrics"`
+	Tracing          TracingConfig                     `yaml:"tracing"`
+	Observability    observability.ObservabilityConfig `yaml:"observability"`
+	CORS             CORSConfig                        `yaml:"cors"`
+	Auth             AuthConfig                        `yaml:"auth"`
+	Database         DatabaseConfig                    `yaml:"database"`
+	Cache            CacheConfig                       `yaml:"cache"`
+	Memory           MemoryConfig                      `yaml:"memory"`
+	Sandbox          SandboxConfig                     `yaml:"sandbox"`
+	StructuredOutput StructuredOutputConfig            `yaml:"structured_output"`
+	HealthCheck      HealthCheckConfig                 `yaml:"healthcheck"`
+	Kubernetes       KubernetesConfig                  `yaml:"kubernetes"`
+	Preflight        PreflightConfig                   `yaml:"preflight"`
+	ResponseSigning  ResponseSigningConfig             `yaml:"response_signing"`
+	Encryption       EncryptionConfig                  `yaml:"encryption"`
+	MCP              MCPConfig                         `yaml:"mcp"`
+	Vault            VaultConfig                       `yaml:"vault"`
+	AWSSecrets       AWSSecretsManagerConfig           `yaml:"aws_secrets_manager"`
+	GCPSecrets       GCPSecretManagerConfig            `yaml:"gcp_secret_manager"`
+	PricingFile      string                            `yaml:"pricing_file"`
+	PricingRefresh   time.Duration                     `yaml:"pricing_refresh_interval"`
+	PricingFallback  PricingFallbackConfig             `yaml:"pricing_fallback"`
+	MaxTokensPolicy  string                            `yaml:"max_tokens_policy"` // clamp (default) or reject
+	Tokenizer        TokenizerConfig                   `yaml:"tokenizer"`
+	VirtualModels    []VirtualModelConfig              `yaml:"virtual_models"`
+	Guardrails       []GuardrailConfig                 `yaml:"guardrails"`
+	Plugins          []PluginConfig                    `yaml:"plugins"`
 }
 
 type Warning struct {
@@ -43,7 +66,8 @@ type Warning struct {
 }
 
 const (
-	WarningCacheWithoutAuth = "cache_without_auth"
+	WarningCacheWithoutAuth       = "cache_without_auth"
+	WarningProviderInsecureVerify = "provider_insecure_skip_verify"
 )
 
 func (c *Config) Warnings() []Warning {
@@ -59,6 +83,14 @@ func (c *Config) Warnings() []Warning {
 				"this is OK for single-tenant/trusted deployments, but unsafe for multi-tenant or untrusted callers",
 		})
 	}
+	for _, p := range c.Providers {
+		if p.InsecureSkipVerify {
+			out = append(out, Warning{
+				Code:    WarningProviderInsecureVerify,
+				Message: fmt.Sprintf("provider %q: insecure_skip_verify=true disables upstream certificate verification", p.Name),
+			})
+		}
+	}
 	return out
 }
 
@@ -84,12 +116,40 @@ type VaultConfig struct {
 	ClientKey  string `yaml:"client_key"`
 }
 
+// AWSSecretsManagerConfig contains AWS Secrets Manager settings. When
+// enabled, awssm://<secret-id>[#key] references resolve through it using
+// the default AWS credential chain.
+type AWSSecretsManagerConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Region   string `yaml:"region"`   // Default: from AWS config
+	Endpoint string `yaml:"endpoint"` // Custom endpoint (e.g., LocalStack)
+}
+
+// GCPSecretManagerConfig contains GCP Secret Manager settings. When
+// enabled, gcpsm://<secret>[/version][#key] references resolve through it
+// using Application Default Credentials.
+type GCPSecretManagerConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Project  string `yaml:"project"`  // Default: from the credentials
+	Endpoint string `yaml:"endpoint"` // Custom endpoint
+}
+
 // MCPConfig contains MCP (Model Context Protocol) settings.
 type MCPConfig struct {
 	Enabled                  bool              `yaml:"enabled"`
 	Clients                  []MCPClientConfig `yaml:"clients"`
 	DefaultConnectionTimeout time.Duration     `yaml:"default_connection_timeout"`
 	DefaultExecutionTimeout  time.Duration     `yaml:"default_execution_timeout"`
+	Server                   MCPServerConfig   `yaml:"server"`
+}
+
+// MCPServerConfig exposes the aggregated tools of all MCP clients as an MCP
+// server at /v1/mcp (streamable HTTP). API keys list the tools they may use
+// in their "mcp_tools" metadata, which only proxy admins and management keys
+// may set; keys without it see every tool unless RequireAllowlist is set.
+type MCPServerConfig struct {
+	Enabled          bool `yaml:"enabled"`
+	RequireAllowlist bool `yaml:"require_allowlist"`
 }
 
 // MCPClientConfig defines a single MCP client configuration.
@@ -102,19 +162,148 @@ type MCPClientConfig struct {
 	Args              []string          `yaml:"args,omitempty"`
 	Envs              []string          `yaml:"envs,omitempty"`
 	Headers           map[string]string `yaml:"headers,omitempty"`
+	OAuth             *MCPOAuthConfig   `yaml:"oauth,omitempty"`
 	ToolsToExecute    []string          `yaml:"tools_to_execute,omitempty"`
 	ConnectionTimeout time.Duration     `yaml:"connection_timeout,omitempty"`
 	ExecutionTimeout  time.Duration     `yaml:"execution_timeout,omitempty"`
 }
 
+// MCPOAuthConfig configures OAuth2 for an http or sse MCP client. The
+// client_credentials grant is used unless RefreshToken is set. ClientSecret
+// and RefreshToken accept secret references (env://, vault://).
+type MCPOAuthConfig struct {
+	TokenURL     string   `yaml:"token_url"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret,omitempty"`
+	RefreshToken string   `yaml:"refresh_token,omitempty"`
+	Scopes       []string `yaml:"scopes,omitempty"`
+	Audience     string   `yaml:"audience,omitempty"`
+	Header       string   `yaml:"header,omitempty"` // default: Authorization
+}
+
 // CacheConfig contains caching settings.
 type CacheConfig struct {
-	Enabled   bool              `yaml:"enabled"`
-	Type      string            `yaml:"type"`      // local, redis, dual
-	Namespace string            `yaml:"namespace"` // Key namespace prefix
-	TTL       time.Duration     `yaml:"ttl"`       // Default TTL
-	Memory    MemoryCacheConfig `yaml:"memory"`    // In-memory cache config
-	Redis     RedisCacheConfig  `yaml:"redis"`     // Redis cache config
+	Enabled   bool                 `yaml:"enabled"`
+	Type      string               `yaml:"type"`      // local, redis, dual, memcached, dynamodb
+	Namespace string               `yaml:"namespace"` // Key namespace prefix
+	TTL       time.Duration        `yaml:"ttl"`       // Default TTL
+	Memory    MemoryCacheConfig    `yaml:"memory"`    // In-memory cache config
+	Redis     RedisCacheConfig     `yaml:"redis"`     // Redis cache config
+	Dual      DualCacheConfig      `yaml:"dual"`      // Dual (local + Redis) cache config
+	Memcached MemcachedCacheConfig `yaml:"memcached"` // Memcached cache config
+	DynamoDB  DynamoDBCacheConfig  `yaml:"dynamodb"`  // DynamoDB cache config
+	// PrefixCache enables the experimental normalized-prompt/prefix cache.
+	PrefixCache bool `yaml:"prefix_cache"`
+	// NegativeTTL remembers deterministic client errors (invalid request,
+	// model not found, context too long) per request hash for this long.
+	// It applies even when response caching is disabled; 0 disables it.
+	NegativeTTL time.Duration `yaml:"negative_ttl"`
+}
+
+// MemoryConfig contains conversation memory settings.
+type MemoryConfig struct {
+	Session  SessionMemoryConfig  `yaml:"session"`
+	LongTerm LongTermMemoryConfig `yaml:"long_term"`
+	Quotas   MemoryQuotaConfig    `yaml:"quotas"`
+}
+
+// MemoryQuotaConfig limits the memory each team (or API key without a team)
+// may store. Teams override the limits with memory_max_sessions,
+// memory_max_vectors and memory_max_bytes metadata.
+type MemoryQuotaConfig struct {
+	Enabled     bool  `yaml:"enabled"`
+	MaxSessions int   `yaml:"max_sessions"` // Stored sessions (0 = unlimited)
+	MaxVectors  int   `yaml:"max_vectors"`  // Stored long-term memories (0 = unlimited)
+	MaxBytes    int64 `yaml:"max_bytes"`    // Stored text across both (0 = unlimited)
+	// Backend is "memory" or "redis" (uses cache.redis; shares usage across instances).
+	Backend string `yaml:"backend"`
+}
+
+// SessionMemoryConfig enables replaying conversation history for requests
+// carrying an X-Session-ID header.
+type SessionMemoryConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Backend is "memory", "redis" (uses cache.redis) or "postgres" (uses database).
+	Backend   string        `yaml:"backend"`
+	MaxTurns  int           `yaml:"max_turns"`  // Turns replayed and retained per session (0 = unlimited)
+	MaxTokens int           `yaml:"max_tokens"` // Token budget for replayed history (0 = unlimited)
+	TTL       time.Duration `yaml:"ttl"`        // Idle session expiry (0 = never); redis expires natively, other backends by the retention job
+}
+
+// LongTermMemoryConfig enables embedding-based long-term memory.
+type LongTermMemoryConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Backend is "memory", "pgvector" (uses database), "qdrant" or "milvus".
+	Backend        string               `yaml:"backend"`
+	EmbeddingModel string               `yaml:"embedding_model"` // Model memories are embedded with
+	Dimension      int                  `yaml:"dimension"`       // Embedding size, used to create the table or collection
+	TopK           int                  `yaml:"top_k"`           // Memories recalled by default
+	MinScore       float64              `yaml:"min_score"`       // Minimum cosine similarity of recalled memories (0 = any)
+	Retention      time.Duration        `yaml:"retention"`       // Memories older than this are deleted by the retention job (0 = never)
+	PgVector       PgVectorMemoryConfig `yaml:"pgvector"`
+	Qdrant         QdrantMemoryConfig   `yaml:"qdrant"`
+	Milvus         MilvusMemoryConfig   `yaml:"milvus"`
+	Ingestion      IngestionConfig      `yaml:"ingestion"`
+}
+
+// IngestionConfig configures POST /v1/memory/{collection}/documents.
+type IngestionConfig struct {
+	Chunking ChunkingConfig `yaml:"chunking"`
+	// Extractors maps content types to commands converting the document on
+	// stdin to text on stdout, e.g. application/pdf: [pdftotext, -, -].
+	Extractors map[string][]string `yaml:"extractors"`
+	// ExtractorTimeout bounds each extractor command (default 60s).
+	ExtractorTimeout time.Duration `yaml:"extractor_timeout"`
+}
+
+// ChunkingConfig selects how ingested documents are split.
+type ChunkingConfig struct {
+	Strategy string `yaml:"strategy"` // fixed, paragraph or markdown (default paragraph)
+	Size     int    `yaml:"size"`     // Maximum characters per chunk (default 1000)
+	Overlap  int    `yaml:"overlap"`  // Characters shared by consecutive fixed chunks
+}
+
+// PgVectorMemoryConfig configures the pgvector backend.
+type PgVectorMemoryConfig struct {
+	Table string `yaml:"table"`
+}
+
+// QdrantMemoryConfig configures the Qdrant backend.
+type QdrantMemoryConfig struct {
+	APIBase    string        `yaml:"api_base"`
+	APIKey     string        `yaml:"api_key"`
+	Collection string        `yaml:"collection"`
+	Timeout    time.Duration `yaml:"timeout"`
+}
+
+// MilvusMemoryConfig configures the Milvus backend.
+type MilvusMemoryConfig struct {
+	Address    string        `yaml:"address"`
+	Token      string        `yaml:"token"`
+	Database   string        `yaml:"database"`
+	Collection string        `yaml:"collection"`
+	Timeout    time.Duration `yaml:"timeout"`
+}
+
+// StructuredOutputConfig configures validation of json_schema structured
+// outputs against their schema.
+type StructuredOutputConfig struct {
+	Validate bool `yaml:"validate"`
+	Repair   bool `yaml:"repair"` // Retry invalid non-streaming outputs once
+}
+
+// SandboxConfig configures the mock responses served to sandbox API keys.
+type SandboxConfig struct {
+	DefaultResponse     string                  `yaml:"default_response"`
+	Responses           []SandboxResponseConfig `yaml:"responses"`            // First match wins
+	EmbeddingDimensions int                     `yaml:"embedding_dimensions"` // Size of mock embedding vectors
+}
+
+// SandboxResponseConfig returns Content for requests whose last user
+// message contains Match.
+type SandboxResponseConfig struct {
+	Match   string `yaml:"match"`
+	Content string `yaml:"content"`
 }
 
 // HealthCheckConfig contains proactive health probe settings.
@@ -124,6 +313,289 @@ type HealthCheckConfig struct {
 	Timeout  time.Duration `yaml:"timeout"`
 }
 
+// KubernetesConfig enables Kubernetes mode: the config file and WatchPaths
+// (mounted ConfigMaps and Secrets) are polled for changes, singleton jobs
+// run only on the elected leader, and /health/ready reflects provider
+// health.
+type KubernetesConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// WatchPaths are files or directories whose changes rebuild the client,
+	// re-resolving file:// secrets.
+	WatchPaths     []string             `yaml:"watch_paths"`
+	WatchInterval  time.Duration        `yaml:"watch_interval"`
+	LeaderElection LeaderElectionConfig `yaml:"leader_election"`
+	// ReadinessRequiresProvider makes /health/ready return 503 while every
+	// deployment is in cooldown.
+	ReadinessRequiresProvider bool `yaml:"readiness_requires_provider"`
+}
+
+// LeaderElectionConfig contains Lease-based leader election settings. The
+// leader runs budget resets, key rotation, retention jobs and the health
+// prober.
+type LeaderElectionConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	LeaseName     string        `yaml:"lease_name"`
+	Namespace     string        `yaml:"namespace"` // Default: the pod's namespace
+	Identity      string        `yaml:"identity"`  // Default: POD_NAME, then the hostname
+	LeaseDuration time.Duration `yaml:"lease_duration"`
+	RetryPeriod   time.Duration `yaml:"retry_period"`
+}
+
+// PreflightConfig controls the checks run once at startup.
+type PreflightConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// ProviderCheck is "probe" (one-token authenticated call), "dry_run"
+	// (build the request only) or "off".
+	ProviderCheck string `yaml:"provider_check"`
+	// FailOnCritical refuses to start when a critical check fails.
+	FailOnCritical bool          `yaml:"fail_on_critical"`
+	Timeout        time.Duration `yaml:"timeout"`
+}
+
+// ResponseSigningConfig enables signed provenance headers on responses.
+type ResponseSigningConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	KeyFile string `yaml:"key_file"` // PEM private key (Ed25519, ECDSA P-256 or RSA)
+	KeyID   string `yaml:"key_id"`   // "kid" published in the JWS header and JWKS
+}
+
+// EncryptionConfig enables envelope encryption of data at rest (cached
+// responses, session turns and long-term memories) with per-tenant data
+// keys wrapped by a master key.
+type EncryptionConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MasterKey is a base64-encoded 32-byte key or a secret reference
+	// (e.g. env://LLMUX_MASTER_KEY, vault://secret/llmux#master_key).
+	MasterKey string `yaml:"master_key"`
+	// PreviousMasterKeys can still decrypt existing data during key rotation.
+	PreviousMasterKeys []string `yaml:"previous_master_keys"`
+}
+
+// PricingFallbackConfig controls requests for models missing from the pricing data.
+type PricingFallbackConfig struct {
+	// Policy is "fail" (reject, default), "warn" (zero cost) or "default_rate".
+	Policy string `yaml:"policy"`
+	// DefaultRates maps provider names, or "*" for any provider, to the
+	// per-token rate used by the default_rate policy.
+	DefaultRates map[string]TokenRateConfig `yaml:"default_rates"`
+}
+
+// TokenizerConfig selects the tokenizers used to count tokens for cost and
+// rate limits. OpenAI and Claude models are counted without configuration.
+type TokenizerConfig struct {
+	// SentencePiece maps model name prefixes to SentencePiece model files
+	// (tokenizer.model) for open models such as Gemma.
+	SentencePiece map[string]string `yaml:"sentencepiece"`
+}
+
+// ModelPriceConfig is a deployment's price in USD, with the keys of
+// pricing_file entries.
+type ModelPriceConfig struct {
+	InputCostPerToken  float64 `yaml:"input_cost_per_token"`
+	OutputCostPerToken float64 `yaml:"output_cost_per_token"`
+	CostPerRequest     float64 `yaml:"cost_per_request"`                // Flat surcharge per request
+	CostPerImage       float64 `yaml:"input_cost_per_image"`            // Per input image
+	CostPerAudioSecond float64 `yaml:"input_cost_per_audio_per_second"` // Per second of input audio
+	// OutputCostPerReasoningToken prices reasoning tokens; 0 uses the output rate.
+	OutputCostPerReasoningToken float64 `yaml:"output_cost_per_reasoning_token"`
+	// MaxOutputTokens is the model's output token limit; 0 keeps the
+	// pricing data's.
+	MaxOutputTokens int `yaml:"max_output_tokens"`
+	// Tiers replace the token rates for prompts longer than above_input_tokens.
+	Tiers []PriceTierConfig `yaml:"tiers"`
+}
+
+func (p ModelPriceConfig) validate() error {
+	if p.InputCostPerToken < 0 || p.OutputCostPerToken < 0 || p.CostPerRequest < 0 ||
+		p.CostPerImage < 0 || p.CostPerAudioSecond < 0 || p.OutputCostPerReasoningToken < 0 {
+		return fmt.Errorf("costs cannot be negative")
+	}
+	if p.MaxOutputTokens < 0 {
+		return fmt.Errorf("max_output_tokens cannot be negative")
+	}
+	for _, tier := range p.Tiers {
+		if tier.AboveInputTokens <= 0 {
+			return fmt.Errorf("tiers need a positive above_input_tokens")
+		}
+		if tier.InputCostPerToken < 0 || tier.OutputCostPerToken < 0 {
+			return fmt.Errorf("costs cannot be negative")
+		}
+	}
+	return nil
+}
+
+// PriceTierConfig prices requests whose prompt exceeds AboveInputTokens.
+type PriceTierConfig struct {
+	AboveInputTokens   int     `yaml:"above_input_tokens"`
+	InputCostPerToken  float64 `yaml:"input_cost_per_token"`
+	OutputCostPerToken float64 `yaml:"output_cost_per_token"` // 0 keeps the base output rate
+}
+
+// TokenRateConfig is a per-token price in USD.
+type TokenRateConfig struct {
+	InputCostPerToken  float64 `yaml:"input_cost_per_token"`
+	OutputCostPerToken float64 `yaml:"output_cost_per_token"`
+}
+
+// VirtualModelConfig defines a model name that expands into a request for
+// Model with a fixed system prompt and parameters. Set fields override the
+// caller's values.
+type VirtualModelConfig struct {
+	Name         string              `yaml:"name"`
+	Model        string              `yaml:"model"`
+	SystemPrompt string              `yaml:"system_prompt"` // Sent ahead of the caller's messages
+	Temperature  *float64            `yaml:"temperature"`
+	TopP         *float64            `yaml:"top_p"`
+	MaxTokens    int                 `yaml:"max_tokens"`
+	Stop         []string            `yaml:"stop"`
+	Tools        []VirtualToolConfig `yaml:"tools"`       // Replace the caller's tools
+	ToolChoice   string              `yaml:"tool_choice"` // auto, none, required, or a tool name
+}
+
+// VirtualToolConfig is a function tool offered by a virtual model.
+type VirtualToolConfig struct {
+	Name        string         `yaml:"name"`
+	Description string         `yaml:"description"`
+	Parameters  map[string]any `yaml:"parameters"` // JSON schema of the arguments
+}
+
+func (v VirtualModelConfig) validate() error {
+	if v.Model == "" {
+		return fmt.Errorf("model is required")
+	}
+	if v.Temperature != nil && (*v.Temperature < 0 || *v.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2")
+	}
+	if v.TopP != nil && (*v.TopP < 0 || *v.TopP > 1) {
+		return fmt.Errorf("top_p must be between 0 and 1")
+	}
+	if v.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens cannot be negative")
+	}
+	tools := make(map[string]bool, len(v.Tools))
+	for i, tool := range v.Tools {
+		if tool.Name == "" {
+			return fmt.Errorf("tools[%d]: name is required", i)
+		}
+		tools[tool.Name] = true
+	}
+	switch v.ToolChoice {
+	case "", "auto", "none", "required":
+	default:
+		if !tools[v.ToolChoice] {
+			return fmt.Errorf("tool_choice %q is not one of its tools", v.ToolChoice)
+		}
+	}
+	return nil
+}
+
+// GuardrailConfig defines a guardrail that screens requests and/or
+// responses. Guardrails with DefaultOn apply to every request; others apply
+// when listed in an API key's or team's "guardrails" metadata.
+type GuardrailConfig struct {
+	Name       string                    `yaml:"name"`
+	Type       string                    `yaml:"type"`   // regex, pii, prompt_injection, moderation
+	Mode       string                    `yaml:"mode"`   // pre (default), post, both
+	Action     string                    `yaml:"action"` // block, redact (regex and pii only), annotate
+	DefaultOn  bool                      `yaml:"default_on"`
+	Patterns   []string                  `yaml:"patterns"`  // regex: deny patterns
+	Entities   []string                  `yaml:"entities"`  // pii: entity types (empty = all)
+	Threshold  float64                   `yaml:"threshold"` // prompt_injection: score in [0, 1] (default 0.5)
+	Classifier GuardrailClassifierConfig `yaml:"classifier"`
+	Moderation GuardrailModerationConfig `yaml:"moderation"`
+}
+
+// PluginConfig defines a plugin registered from configuration. An http
+// plugin POSTs each request (pre hook) and response (post hook) to URL and
+// applies the reply, so guardrails and transformations can run outside the
+// gateway; its Config block is forwarded to the service. logging,
+// rate_limit and transform plugins read Config as LoggingPluginConfig,
+// RateLimitPluginConfig and TransformPluginConfig. Plugin changes are applied on reload without
+// rebuilding the client.
+type PluginConfig struct {
+	Name          string            `yaml:"name"`
+	Type          string            `yaml:"type"` // http, logging, rate_limit, transform
+	URL           string            `yaml:"url"`
+	Timeout       time.Duration     `yaml:"timeout"`        // default 5s
+	FailurePolicy string            `yaml:"failure_policy"` // fail_open (default), fail_closed
+	Hooks         []string          `yaml:"hooks"`          // pre, post (default both)
+	Headers       map[string]string `yaml:"headers"`
+	Priority      int               `yaml:"priority"` // lower runs first (default depends on type)
+	Scope         PluginScopeConfig `yaml:"scope"`
+	Config        map[string]any    `yaml:"config"`
+}
+
+// PluginScopeConfig limits a plugin to matching requests. Every non-empty
+// list must match: routes and models are glob patterns ("/v1/chat/*",
+// "gpt-4o*"), teams are team IDs. Empty applies to every request.
+type PluginScopeConfig struct {
+	Routes []string `yaml:"routes"`
+	Models []string `yaml:"models"`
+	Teams  []string `yaml:"teams"`
+}
+
+// LoggingPluginConfig is the config block of a logging plugin.
+type LoggingPluginConfig struct {
+	LogRequestBody  bool `yaml:"log_request_body"`
+	LogResponseBody bool `yaml:"log_response_body"`
+}
+
+// RateLimitPluginConfig is the config block of a rate_limit plugin.
+type RateLimitPluginConfig struct {
+	Rate  float64 `yaml:"rate"`  // requests per second per API key
+	Burst int     `yaml:"burst"` // default: rate rounded up
+}
+
+// TransformPluginConfig is the config block of a transform plugin, which
+// enforces prompt standards centrally. Prompts and templates are Go
+// templates over .Content, .Role, .Model, .APIKeyID, .TeamID and .UserID.
+type TransformPluginConfig struct {
+	SystemPrompt      string            `yaml:"system_prompt"`
+	TeamSystemPrompts map[string]string `yaml:"team_system_prompts"` // team ID -> prompt, replacing system_prompt
+	SystemPromptMode  string            `yaml:"system_prompt_mode"`  // prepend (default), append, replace
+	MessageTemplate   string            `yaml:"message_template"`
+	MessageRoles      []string          `yaml:"message_roles"` // default [user]
+	ResponseTemplate  string            `yaml:"response_template"`
+	MaxTemperature    *float64          `yaml:"max_temperature"`
+	MaxTopP           *float64          `yaml:"max_top_p"`
+	MaxTokens         int               `yaml:"max_tokens"`
+}
+
+// DecodeConfig decodes the plugin's config block into out, rejecting
+// unknown fields.
+func (p PluginConfig) DecodeConfig(out any) error {
+	if len(p.Config) == 0 {
+		return nil
+	}
+	data, err := yaml.Marshal(p.Config)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	return dec.Decode(out)
+}
+
+// GuardrailClassifierConfig backs a prompt_injection guardrail with a
+// classifier model behind an OpenAI-compatible chat completions endpoint.
+// It scores prompts the heuristics alone do not flag.
+type GuardrailClassifierConfig struct {
+	URL     string        `yaml:"url"`
+	APIKey  string        `yaml:"api_key"`
+	Model   string        `yaml:"model"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// GuardrailModerationConfig points a moderation guardrail at an
+// OpenAI-compatible moderation endpoint.
+type GuardrailModerationConfig struct {
+	URL        string        `yaml:"url"`
+	APIKey     string        `yaml:"api_key"`
+	Model      string        `yaml:"model"`
+	Timeout    time.Duration `yaml:"timeout"`
+	FailClosed bool          `yaml:"fail_closed"` // Flag requests when the API is unavailable
+}
+
 // MemoryCacheConfig contains in-memory cache settings.
 type MemoryCacheConfig struct {
 	MaxSize         int           `yaml:"max_size"`         // Maximum number of items
@@ -132,6 +604,33 @@ type MemoryCacheConfig struct {
 	CleanupInterval time.Duration `yaml:"cleanup_interval"` // Cleanup interval
 }
 
+// DualCacheConfig contains settings for the local + Redis cache.
+type DualCacheConfig struct {
+	// LocalTTL bounds how long entries live in the local tier; it must not
+	// exceed cache.ttl (0 = memory.default_ttl).
+	LocalTTL       time.Duration `yaml:"local_ttl"`
+	WriteBehind    bool          `yaml:"write_behind"`     // Write to Redis asynchronously
+	WriteQueueSize int           `yaml:"write_queue_size"` // Pending write-behind bound (0 = 1024)
+}
+
+// MemcachedCacheConfig contains Memcached cache settings.
+type MemcachedCacheConfig struct {
+	Addrs        []string      `yaml:"addrs"`          // Server addresses; keys are sharded across them
+	Timeout      time.Duration `yaml:"timeout"`        // Dial and I/O timeout
+	MaxIdleConns int           `yaml:"max_idle_conns"` // Idle connections kept per server
+}
+
+// DynamoDBCacheConfig contains DynamoDB cache settings. Credentials come
+// from the default AWS config chain.
+type DynamoDBCacheConfig struct {
+	Table        string        `yaml:"table"`         // Table with a string partition key
+	Region       string        `yaml:"region"`        // AWS region (default: from AWS config)
+	Endpoint     string        `yaml:"endpoint"`      // Custom endpoint (e.g., DynamoDB Local)
+	Timeout      time.Duration `yaml:"timeout"`       // Per-call timeout
+	KeyAttribute string        `yaml:"key_attribute"` // Partition key attribute (default: key)
+	TTLAttribute string        `yaml:"ttl_attribute"` // Expiry attribute for DynamoDB TTL (default: expires_at)
+}
+
 // RedisCacheConfig contains Redis cache settings.
 type RedisCacheConfig struct {
 	Addr           string        `yaml:"addr"`            // Redis address
@@ -150,13 +649,66 @@ type RedisCacheConfig struct {
 
 // AuthConfig contains authentication settings.
 type AuthConfig struct {
-	Enabled                bool              `yaml:"enabled"`
-	SkipPaths              []string          `yaml:"skip_paths"` // Paths to skip authentication
-	LastUsedUpdateInterval time.Duration     `yaml:"last_used_update_interval"`
-	BootstrapToken         string            `yaml:"bootstrap_token"` // Optional bootstrap token for management endpoints
-	OIDC                   OIDCConfig        `yaml:"oidc"`            // OIDC configuration
-	Session                AuthSessionConfig `yaml:"session"`         // Session configuration
-	Casbin                 CasbinConfig      `yaml:"casbin"`          // Casbin configuration
+	Enabled                bool               `yaml:"enabled"`
+	SkipPaths              []string           `yaml:"skip_paths"` // Paths to skip authentication
+	LastUsedUpdateInterval time.Duration      `yaml:"last_used_update_interval"`
+	BootstrapToken         string             `yaml:"bootstrap_token"` // Optional bootstrap token for management endpoints
+	OIDC                   OIDCConfig         `yaml:"oidc"`            // OIDC configuration
+	Session                AuthSessionConfig  `yaml:"session"`         // Session configuration
+	Casbin                 CasbinConfig       `yaml:"casbin"`          // Casbin configuration
+	HostTenants            []HostTenantConfig `yaml:"host_tenants"`    // Host-based tenant resolution
+	JWT                    JWTAuthConfig      `yaml:"jwt"`             // Machine-to-machine JWTs on /v1/* routes
+	KeyCache               KeyCacheConfig     `yaml:"key_cache"`       // In-process cache for API key lookups
+	Invitations            InvitationConfig   `yaml:"invitations"`     // Invitation link expiry policy
+}
+
+// InvitationConfig bounds invitation link lifetimes.
+type InvitationConfig struct {
+	DefaultExpiry time.Duration `yaml:"default_expiry"` // Applied when a request sets no expiry (0 = never)
+	MaxExpiry     time.Duration `yaml:"max_expiry"`     // Longest expiry a request may set (0 = unbounded)
+}
+
+// KeyCacheConfig caches hashed API key lookups in process. With Distributed,
+// blocks, deletions and updates are broadcast over Redis pub/sub (using
+// cache.redis) so every instance evicts the key within seconds.
+type KeyCacheConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	Size        int           `yaml:"size"`        // Maximum cached keys
+	TTL         time.Duration `yaml:"ttl"`         // Maximum staleness without an invalidation
+	Distributed bool          `yaml:"distributed"` // Propagate invalidations via Redis
+}
+
+// JWTAuthConfig accepts bearer JWTs from a trusted issuer on data-plane
+// routes as an alternative to API keys.
+type JWTAuthConfig struct {
+	Enabled      bool            `yaml:"enabled"`
+	Issuer       string          `yaml:"issuer"`
+	Audiences    []string        `yaml:"audiences"`  // Accepted "aud" values; empty skips the check
+	JWKSURL      string          `yaml:"jwks_url"`   // Discovered from the issuer when empty
+	Algorithms   []string        `yaml:"algorithms"` // Default RS256
+	ClaimMapping JWTClaimMapping `yaml:"claim_mapping"`
+}
+
+// JWTClaimMapping maps token claims to the virtual key a request runs as.
+type JWTClaimMapping struct {
+	KeyIDJWTField     string            `yaml:"key_id_jwt_field"`    // Virtual key ID (default: "sub")
+	KeyAliasJWTField  string            `yaml:"key_alias_jwt_field"` // Bind to a stored key by alias, using its budget and limits
+	TeamIDJWTField    string            `yaml:"team_id_jwt_field"`
+	TeamAliasMap      map[string]string `yaml:"team_alias_map"`
+	UserIDJWTField    string            `yaml:"user_id_jwt_field"`
+	OrgIDJWTField     string            `yaml:"org_id_jwt_field"`
+	ModelsJWTField    string            `yaml:"models_jwt_field"` // List or space-separated string
+	EndUserIDJWTField string            `yaml:"end_user_id_jwt_field"`
+}
+
+// HostTenantConfig binds a data-plane host to an organization and request defaults.
+type HostTenantConfig struct {
+	// Host is an exact host or a "*.parent.domain" wildcard matching one subdomain label.
+	Host string `yaml:"host"`
+	// OrganizationID is required for exact hosts; wildcards default to the subdomain label.
+	OrganizationID string   `yaml:"organization_id"`
+	AllowedModels  []string `yaml:"allowed_models"`
+	DefaultTags    []string `yaml:"default_tags"`
 }
 
 // AuthSessionConfig contains browser session settings.
@@ -259,6 +811,8 @@ type DatabaseConfig struct {
 	MaxOpenConns int           `yaml:"max_open_conns"`
 	MaxIdleConns int           `yaml:"max_idle_conns"`
 	ConnLifetime time.Duration `yaml:"conn_lifetime"`
+	// AutoMigrate applies pending schema migrations on startup.
+	AutoMigrate bool `yaml:"auto_migrate"`
 }
 
 // ServerConfig contains HTTP server settings.
@@ -268,12 +822,55 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `yaml:"read_timeout"`
 	WriteTimeout time.Duration `yaml:"write_timeout"`
 	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// DrainTimeout is how long shutdown waits for in-flight requests and
+	// streams to finish. New requests get 503 with Retry-After meanwhile.
+	DrainTimeout time.Duration `yaml:"drain_timeout"`
+	// TLS terminates TLS on both the data and admin listeners.
+	TLS ServerTLSConfig `yaml:"tls"`
+}
+
+// Client certificate modes of ServerTLSConfig.ClientAuth.
+const (
+	ClientAuthNone     = "none"     // Do not ask for client certificates
+	ClientAuthOptional = "optional" // Verify client certificates when presented
+	ClientAuthRequire  = "require"  // Reject connections without a valid client certificate
+)
+
+// ServerTLSConfig enables native TLS and, with a client CA bundle, mTLS.
+// Certificate, key and CA files are re-read when they change on disk, so
+// rotated certificates apply without a restart.
+type ServerTLSConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	ClientCAFile string `yaml:"client_ca_file"` // PEM bundle trusted for client certificates
+	ClientAuth   string `yaml:"client_auth"`    // none, optional, require; default optional with a CA bundle
+	// ClientCertPaths lists path prefixes that require a verified client
+	// certificate even when client_auth is optional. Prefixes match whole
+	// path segments.
+	ClientCertPaths []string      `yaml:"client_cert_paths"`
+	MinVersion      string        `yaml:"min_version"`     // 1.2 or 1.3; default 1.2
+	ReloadInterval  time.Duration `yaml:"reload_interval"` // How often files are checked for rotation; default 30s
 }
 
 // StreamConfig contains stream-specific behavior.
 type StreamConfig struct {
-	RecoveryMode        string `yaml:"recovery_mode"`         // off, append, retry
-	MaxAccumulatedBytes int    `yaml:"max_accumulated_bytes"` // 0 = unlimited (not recommended)
+	RecoveryMode        string             `yaml:"recovery_mode"`         // off, append, retry
+	MaxAccumulatedBytes int                `yaml:"max_accumulated_bytes"` // 0 = unlimited (not recommended)
+	HeartbeatInterval   time.Duration      `yaml:"heartbeat_interval"`    // ": ping" on idle SSE responses; 0 = disabled
+	IdleTimeout         time.Duration      `yaml:"idle_timeout"`          // Abort silent upstream streams; 0 = disabled
+	Passthrough         bool               `yaml:"passthrough"`           // Forward OpenAI-format upstream events unchanged when possible
+	Resume              StreamResumeConfig `yaml:"resume"`
+}
+
+// StreamResumeConfig buffers chat completion stream events so clients that
+// reconnect with Last-Event-ID resume the generation. Streams are buffered in
+// Redis in distributed mode and in memory otherwise. A resumable stream keeps
+// generating after its client disconnects.
+type StreamResumeConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	TTL       time.Duration `yaml:"ttl"`        // Resumable after the last event; default 5m
+	MaxEvents int           `yaml:"max_events"` // Per stream; default 10000, 0 = unlimited
 }
 
 // ProviderConfig defines a single LLM provider configuration.
@@ -287,6 +884,57 @@ type ProviderConfig struct {
 	MaxConcurrent       int               `yaml:"max_concurrent"`
 	Timeout             time.Duration     `yaml:"timeout"`
 	Headers             map[string]string `yaml:"headers"`
+	APIVersion          string            `yaml:"api_version"` // Upstream API version; empty uses the provider default
+
+	// RPM/TPM are upstream per-minute quotas applied to each model deployment (0 = unlimited).
+	RPM         int64                       `yaml:"rpm"`
+	TPM         int64                       `yaml:"tpm"`
+	ModelLimits map[string]ModelLimitConfig `yaml:"model_limits"` // Per-model overrides of rpm/tpm
+
+	// Pricing prices this provider's deployments per model, ahead of pricing_file and the built-in prices.
+	Pricing map[string]ModelPriceConfig `yaml:"pricing"`
+
+	// Tags label this provider's deployments for tag-based routing ("default" = untagged requests).
+	Tags []string `yaml:"tags"`
+
+	// SecondaryAPIKey enables blue/green credential rollover between api_key and this key
+	// (not supported by bedrock and vertex_ai).
+	SecondaryAPIKey string                   `yaml:"secondary_api_key"`
+	Rollover        CredentialRolloverConfig `yaml:"rollover"`
+
+	// Upstream connection settings; each provider gets its own transport.
+	ProxyURL           string                  `yaml:"proxy_url"`            // http://, https:// or socks5:// proxy; empty connects directly
+	CAFile             string                  `yaml:"ca_file"`              // PEM bundle trusted in addition to the system roots
+	InsecureSkipVerify bool                    `yaml:"insecure_skip_verify"` // Disable upstream certificate verification (testing only)
+	Transport          ProviderTransportConfig `yaml:"transport"`
+}
+
+// ProviderTransportConfig tunes a provider's upstream connection pool.
+// Zero values use the client defaults.
+type ProviderTransportConfig struct {
+	MaxIdleConns        int           `yaml:"max_idle_conns"`          // Default 1000
+	MaxIdleConnsPerHost int           `yaml:"max_idle_conns_per_host"` // Default 100
+	MaxConnsPerHost     int           `yaml:"max_conns_per_host"`      // 0 = unlimited
+	IdleConnTimeout     time.Duration `yaml:"idle_conn_timeout"`       // Default 90s
+	DialTimeout         time.Duration `yaml:"dial_timeout"`            // Default 30s
+	KeepAlive           time.Duration `yaml:"keep_alive"`              // Default 30s; negative disables
+	DisableHTTP2        bool          `yaml:"disable_http2"`
+}
+
+// CredentialRolloverConfig schedules the shift from api_key to secondary_api_key.
+type CredentialRolloverConfig struct {
+	SecondaryWeight float64 `yaml:"secondary_weight"` // Fixed fraction of requests on the secondary key (0-1)
+	// Start/End ramp the secondary weight linearly from 0 to 1; overrides secondary_weight.
+	Start time.Time `yaml:"start"`
+	End   time.Time `yaml:"end"`
+	// RollbackErrorRate rolls back to api_key when the secondary error rate exceeds it (0 = disabled).
+	RollbackErrorRate float64 `yaml:"rollback_error_rate"`
+}
+
+// ModelLimitConfig overrides provider-level rpm/tpm for one model.
+type ModelLimitConfig struct {
+	RPM int64 `yaml:"rpm"`
+	TPM int64 `yaml:"tpm"`
 }
 
 // RoutingConfig contains routing and load balancing settings.
@@ -299,8 +947,61 @@ type RoutingConfig struct {
 	RetryMaxBackoff time.Duration `yaml:"retry_max_backoff"`
 	RetryJitter     float64       `yaml:"retry_jitter"`
 	CooldownPeriod  time.Duration `yaml:"cooldown_period"`
-	Distributed     bool          `yaml:"distributed"` // Enable Redis-backed distributed routing stats
-	EWMAAlpha       float64       `yaml:"ewma_alpha"`
+	// CooldownMaxPeriod caps the exponential cooldown (0 = 10m default).
+	CooldownMaxPeriod time.Duration `yaml:"cooldown_max_period"`
+	// HalfOpenSuccesses is the consecutive probe successes needed to reopen
+	// a deployment after cooldown (0 = 3 default).
+	HalfOpenSuccesses int     `yaml:"half_open_successes"`
+	Distributed       bool    `yaml:"distributed"` // Enable Redis-backed distributed routing stats
+	EWMAAlpha         float64 `yaml:"ewma_alpha"`
+
+	// EnableTagFiltering applies request tags with any strategy (tag-based always filters).
+	EnableTagFiltering bool `yaml:"enable_tag_filtering"`
+
+	// DecisionTraceSize keeps routing decisions for the last N request IDs,
+	// served by GET /router/explain/{request_id} (0 = disabled).
+	DecisionTraceSize int `yaml:"decision_trace_size"`
+
+	// AdmissionQueueSize queues up to N requests per saturated provider (0 = reject immediately).
+	AdmissionQueueSize    int           `yaml:"admission_queue_size"`
+	AdmissionQueueTimeout time.Duration `yaml:"admission_queue_timeout"`
+
+	// ModelTimeouts overrides the upstream request timeout per model group,
+	// taking precedence over provider timeouts. An API key's "timeout"
+	// metadata overrides both.
+	ModelTimeouts map[string]time.Duration `yaml:"model_timeouts"`
+
+	// CircuitBreaker fails requests to a provider fast after repeated
+	// connection failures.
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker"`
+
+	// Speculative sends requests to a cheap draft model first and escalates
+	// to the requested model when the verifier rejects the draft.
+	Speculative SpeculativeConfig `yaml:"speculative"`
+}
+
+// CircuitBreakerConfig configures the per-provider circuit breaker.
+type CircuitBreakerConfig struct {
+	Enabled          bool          `yaml:"enabled"`
+	FailureThreshold int           `yaml:"failure_threshold"` // consecutive connection failures that open the circuit (0 = 5)
+	SuccessThreshold int           `yaml:"success_threshold"` // successful probes that close it (0 = 2)
+	OpenTimeout      time.Duration `yaml:"open_timeout"`      // time open before probing (0 = 30s)
+}
+
+// SpeculativeConfig configures speculative draft routing.
+type SpeculativeConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Drafts maps a requested model to its draft model.
+	Drafts   map[string]string         `yaml:"drafts"`
+	Verifier SpeculativeVerifierConfig `yaml:"verifier"`
+}
+
+// SpeculativeVerifierConfig selects how drafts are verified.
+type SpeculativeVerifierConfig struct {
+	Type          string  `yaml:"type"`            // logprob, judge
+	MinAvgLogprob float64 `yaml:"min_avg_logprob"` // logprob: accept at or above this mean token logprob
+	JudgeModel    string  `yaml:"judge_model"`     // judge: model scoring the draft 1-10
+	MinScore      float64 `yaml:"min_score"`       // judge: accept at or above this score (0 = 7)
 }
 
 // RateLimitConfig defines rate limiting parameters.
@@ -310,9 +1011,17 @@ type RateLimitConfig struct {
 	TokensPerMinute   int64         `yaml:"tokens_per_minute"`   // TPM limit
 	BurstSize         int           `yaml:"burst_size"`
 	WindowSize        time.Duration `yaml:"window_size"`         // Sliding window duration (default: 1m)
-	KeyStrategy       string        `yaml:"key_strategy"`        // api_key, user, model, api_key_model
+	KeyStrategy       string        `yaml:"key_strategy"`        // api_key, user, model, api_key_model, end_user
 	FailOpen          bool          `yaml:"fail_open"`           // Allow requests when limiter backend fails
 	TrustedProxyCIDRs []string      `yaml:"trusted_proxy_cidrs"` // Trusted proxies for forwarded headers
+	// EndUserRequestsPerMinute and EndUserTokensPerMinute limit each end user
+	// within an API key under the end_user strategy (0 = the key's limits).
+	EndUserRequestsPerMinute int64 `yaml:"end_user_requests_per_minute"`
+	EndUserTokensPerMinute   int64 `yaml:"end_user_tokens_per_minute"`
+	// PriorityReservations maps a request priority (low, normal, high) to the
+	// share of the limits held in reserve for it. Requests of that priority
+	// borrow from the reserve once the regular limit is exhausted.
+	PriorityReservations map[string]float64 `yaml:"priority_reservations"`
 
 	// Distributed rate limiting (Redis-backed)
 	Distributed bool `yaml:"distributed"` // Enable Redis-backed distributed rate limiting
@@ -324,12 +1033,195 @@ type GovernanceConfig struct {
 	AsyncAccounting   bool          `yaml:"async_accounting"`
 	IdempotencyWindow time.Duration `yaml:"idempotency_window"`
 	AuditEnabled      bool          `yaml:"audit_enabled"`
+	// AuditExport streams audit events to external systems such as a SIEM.
+	AuditExport AuditExportConfig `yaml:"audit_export"`
+	// ContentPolicies apply stricter limits by detected content category.
+	ContentPolicies []ContentPolicyConfig `yaml:"content_policies"`
+	// TagBudgets cap spend per request tag, independent of key or team.
+	TagBudgets []TagBudgetConfig `yaml:"tag_budgets"`
+	// BudgetAlerts notify when key or team spend nears its budget.
+	BudgetAlerts BudgetAlertsConfig `yaml:"budget_alerts"`
+	// SpendReports send the previous month's spend report on the first day
+	// of each month.
+	SpendReports SpendReportsConfig `yaml:"spend_reports"`
+	// PayloadLogging retains full request and response payloads of opted-in
+	// teams, readable at /logs/requests/{id}.
+	PayloadLogging PayloadLoggingConfig `yaml:"payload_logging"`
+}
+
+// PayloadLoggingConfig retains the prompts and completions of opted-in teams
+// for debugging and compliance review. The database store uses the payload_logs
+// table when database is enabled and memory otherwise.
+type PayloadLoggingConfig struct {
+	Enabled     bool                     `yaml:"enabled"`
+	Store       string                   `yaml:"store"`     // database (default), object_store
+	Teams       []string                 `yaml:"teams"`     // Opted-in team IDs; "*" retains every request
+	Retention   time.Duration            `yaml:"retention"` // Default 720h
+	Redaction   PayloadRedactionConfig   `yaml:"redaction"`
+	ObjectStore PayloadObjectStoreConfig `yaml:"object_store"`
+}
+
+// PayloadRedactionConfig masks sensitive data before payloads are stored.
+type PayloadRedactionConfig struct {
+	// Defaults masks API keys, bearer tokens, emails, phone, card and social
+	// security numbers.
+	Defaults bool                   `yaml:"defaults"`
+	Rules    []PayloadRedactionRule `yaml:"rules"`
+}
+
+// PayloadRedactionRule replaces matches of a regular expression.
+type PayloadRedactionRule struct {
+	Pattern     string `yaml:"pattern"`
+	Replacement string `yaml:"replacement"` // Default [REDACTED]
+}
+
+// PayloadObjectStoreConfig stores payloads in S3 or GCS. GCS uses its
+// S3-compatible API with HMAC keys.
+type PayloadObjectStoreConfig struct {
+	Provider        string `yaml:"provider"` // s3 (default), gcs
+	Bucket          string `yaml:"bucket"`
+	Region          string `yaml:"region"`
+	Endpoint        string `yaml:"endpoint"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	PathPrefix      string `yaml:"path_prefix"`
+}
+
+// SpendReportsConfig emails or uploads monthly spend reports. In distributed
+// mode replicas agree on a single sender through cache.redis.
+type SpendReportsConfig struct {
+	Enabled     bool                         `yaml:"enabled"`
+	GroupBy     []string                     `yaml:"group_by"` // key, team, model, tag, day (default team, model)
+	Format      string                       `yaml:"format"`   // csv (default), json
+	Email       EmailConfig                  `yaml:"email"`
+	ObjectStore SpendReportObjectStoreConfig `yaml:"object_store"`
+}
+
+// SpendReportObjectStoreConfig uploads spend reports to S3 or GCS. GCS uses
+// its S3-compatible API with HMAC keys.
+type SpendReportObjectStoreConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	Provider        string `yaml:"provider"` // s3 (default), gcs
+	Bucket          string `yaml:"bucket"`
+	Region          string `yaml:"region"`
+	Endpoint        string `yaml:"endpoint"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	PathPrefix      string `yaml:"path_prefix"`
+}
+
+// BudgetAlertsConfig fires webhook and email alerts when a key reaches its
+// soft budget or a key or team reaches a share of its max budget. Repeats of
+// the same alert are suppressed for DedupeWindow; in distributed mode the
+// window is shared through cache.redis.
+type BudgetAlertsConfig struct {
+	Enabled      bool                       `yaml:"enabled"`
+	Thresholds   []float64                  `yaml:"thresholds"`    // Shares of max budget in (0, 1] (default [0.8, 1])
+	DedupeWindow time.Duration              `yaml:"dedupe_window"` // Default 24h
+	Webhooks     []BudgetAlertWebhookConfig `yaml:"webhooks"`
+	Email        EmailConfig                `yaml:"email"`
+}
+
+// BudgetAlertWebhookConfig posts Slack-compatible alert messages to URL.
+type BudgetAlertWebhookConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+// EmailConfig sends messages through an SMTP server.
+type EmailConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"` // Default 587
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// AuditExportConfig configures audit event export sinks.
+type AuditExportConfig struct {
+	ObjectStore AuditObjectStoreExportConfig `yaml:"object_store"`
+	Webhook     AuditWebhookExportConfig     `yaml:"webhook"`
+	Syslog      AuditSyslogExportConfig      `yaml:"syslog"`
+}
+
+// AuditObjectStoreExportConfig periodically writes audit events to S3 or GCS
+// as JSONL objects. GCS uses its S3-compatible API with HMAC keys.
+type AuditObjectStoreExportConfig struct {
+	Enabled         bool          `yaml:"enabled"`
+	Provider        string        `yaml:"provider"` // s3 (default), gcs
+	Bucket          string        `yaml:"bucket"`
+	Region          string        `yaml:"region"`
+	Endpoint        string        `yaml:"endpoint"`
+	AccessKeyID     string        `yaml:"access_key_id"`
+	SecretAccessKey string        `yaml:"secret_access_key"`
+	PathPrefix      string        `yaml:"path_prefix"`
+	Compress        bool          `yaml:"compress"`
+	FlushInterval   time.Duration `yaml:"flush_interval"`
+	BatchSize       int           `yaml:"batch_size"`
+}
+
+// AuditWebhookExportConfig streams audit events to an HTTP collector as JSONL.
+type AuditWebhookExportConfig struct {
+	Enabled       bool              `yaml:"enabled"`
+	URL           string            `yaml:"url"`
+	Headers       map[string]string `yaml:"headers"`
+	Timeout       time.Duration     `yaml:"timeout"`
+	FlushInterval time.Duration     `yaml:"flush_interval"`
+	BatchSize     int               `yaml:"batch_size"`
+}
+
+// AuditSyslogExportConfig streams audit events to a syslog collector (RFC 5424).
+type AuditSyslogExportConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	Network       string        `yaml:"network"` // udp (default), tcp
+	Address       string        `yaml:"address"`
+	AppName       string        `yaml:"app_name"`
+	FlushInterval time.Duration `yaml:"flush_interval"`
+	BatchSize     int           `yaml:"batch_size"`
+}
+
+// ContentPolicyConfig limits requests whose content falls into a category.
+// Policies without a team_id apply to all teams; a team-specific policy for
+// the same category replaces the default.
+type ContentPolicyConfig struct {
+	TeamID        string   `yaml:"team_id"`
+	Category      string   `yaml:"category"` // general, code_generation, pii
+	RPMLimit      int      `yaml:"rpm_limit"`
+	AllowedModels []string `yaml:"allowed_models"`
+	Tags          []string `yaml:"tags"` // routing tags added to matching requests
+}
+
+// TagBudgetConfig caps the spend of requests tagged Tag (e.g. "project:alpha")
+// over each calendar aligned budget period.
+type TagBudgetConfig struct {
+	Tag            string  `yaml:"tag"`
+	MaxBudget      float64 `yaml:"max_budget"`
+	BudgetDuration string  `yaml:"budget_duration"` // 1d, 7d, 30d; empty never resets
 }
 
 // LoggingConfig contains logging settings.
 type LoggingConfig struct {
-	Level  string `yaml:"level"`  // debug, info, warn, error
-	Format string `yaml:"format"` // json, text
+	Level     string               `yaml:"level"`  // debug, info, warn, error
+	Format    string               `yaml:"format"` // json, text
+	Anomalies AnomalyLoggingConfig `yaml:"anomalies"`
+}
+
+// AnomalyLoggingConfig logs the full context of slow, expensive or repeatedly
+// failing requests on a dedicated "anomaly" channel, regardless of the global
+// log level. Zero thresholds disable the corresponding check.
+type AnomalyLoggingConfig struct {
+	Enabled          bool          `yaml:"enabled"`
+	Level            string        `yaml:"level"`             // Default warn
+	LatencyThreshold time.Duration `yaml:"latency_threshold"` // Total request latency
+	TTFTThreshold    time.Duration `yaml:"ttft_threshold"`    // Time to first streamed token
+	CostThreshold    float64       `yaml:"cost_threshold"`    // USD per request
+	FailureThreshold int           `yaml:"failure_threshold"` // Failures per key and model within failure_window
+	FailureWindow    time.Duration `yaml:"failure_window"`    // Default 1m
+	SampleRate       float64       `yaml:"sample_rate"`       // Fraction of anomalies logged, default 1
+	MaxPerMinute     int           `yaml:"max_per_minute"`    // 0 = unlimited
+	IncludeMessages  bool          `yaml:"include_messages"`  // Log redacted prompts and responses
 }
 
 // MetricsConfig contains Prometheus metrics settings.
@@ -376,6 +1268,15 @@ func DefaultConfig() *Config {
 			ReadTimeout:  30 * time.Second,
 			WriteTimeout: 120 * time.Second,
 			IdleTimeout:  60 * time.Second,
+			DrainTimeout: 60 * time.Second,
+			TLS: ServerTLSConfig{
+				MinVersion:     "1.2",
+				ReloadInterval: 30 * time.Second,
+			},
+		},
+		ConfigSource: ConfigSourceConfig{
+			Source:       ConfigSourceFile,
+			PollInterval: 30 * time.Second,
 		},
 		Deployment: DeploymentConfig{
 			Mode: "standalone",
@@ -389,10 +1290,19 @@ func DefaultConfig() *Config {
 			RetryJitter:     0.2,
 			CooldownPeriod:  60 * time.Second,
 			EWMAAlpha:       0.1,
+			CircuitBreaker: CircuitBreakerConfig{
+				Enabled: true,
+			},
 		},
 		Stream: StreamConfig{
 			RecoveryMode:        "retry",
 			MaxAccumulatedBytes: 1 << 20, // 1MiB
+			HeartbeatInterval:   15 * time.Second,
+			Passthrough:         true,
+			Resume: StreamResumeConfig{
+				TTL:       5 * time.Minute,
+				MaxEvents: 10000,
+			},
 		},
 		RateLimit: RateLimitConfig{
 			Enabled:           false,
@@ -410,10 +1320,34 @@ func DefaultConfig() *Config {
 			AsyncAccounting:   true,
 			IdempotencyWindow: 10 * time.Minute,
 			AuditEnabled:      true,
+			AuditExport: AuditExportConfig{
+				ObjectStore: AuditObjectStoreExportConfig{
+					Provider:      "s3",
+					Compress:      true,
+					FlushInterval: 5 * time.Minute,
+					BatchSize:     1000,
+				},
+				Webhook: AuditWebhookExportConfig{
+					Timeout:       10 * time.Second,
+					FlushInterval: 5 * time.Second,
+					BatchSize:     100,
+				},
+				Syslog: AuditSyslogExportConfig{
+					Network:       "udp",
+					AppName:       "llmux",
+					FlushInterval: 5 * time.Second,
+					BatchSize:     100,
+				},
+			},
 		},
 		Logging: LoggingConfig{
 			Level:  "info",
 			Format: "json",
+			Anomalies: AnomalyLoggingConfig{
+				Level:         "warn",
+				FailureWindow: time.Minute,
+				SampleRate:    1,
+			},
 		},
 		Metrics: MetricsConfig{
 			Enabled: true,
@@ -441,12 +1375,14 @@ func DefaultConfig() *Config {
 				"/key/",
 				"/team/",
 				"/user/",
+				"/customer/",
 				"/organization/",
 				"/spend/",
 				"/audit/",
 				"/global/",
 				"/invitation/",
 				"/control/",
+				"/admin/",
 				"/metrics",
 				"/auth/",
 				"/api/auth/",
@@ -467,6 +1403,10 @@ func DefaultConfig() *Config {
 				"/api/auth/logout",
 			},
 			LastUsedUpdateInterval: time.Minute,
+			KeyCache: KeyCacheConfig{
+				Size: 10000,
+				TTL:  30 * time.Second,
+			},
 			Session: AuthSessionConfig{
 				Enabled:         false,
 				CookieName:      "llmux_session",
@@ -487,6 +1427,7 @@ func DefaultConfig() *Config {
 			MaxOpenConns: 25,
 			MaxIdleConns: 5,
 			ConnLifetime: 5 * time.Minute,
+			AutoMigrate:  true,
 		},
 		Cache: CacheConfig{
 			Enabled:   false,
@@ -510,11 +1451,40 @@ func DefaultConfig() *Config {
 				MaxRetries:   3,
 			},
 		},
+		Memory: MemoryConfig{
+			Session: SessionMemoryConfig{
+				Backend:  "memory",
+				MaxTurns: 20,
+				TTL:      30 * 24 * time.Hour,
+			},
+			LongTerm: LongTermMemoryConfig{
+				Backend:  "memory",
+				TopK:     5,
+				PgVector: PgVectorMemoryConfig{Table: "memory_vectors"},
+				Qdrant:   QdrantMemoryConfig{Collection: "llmux_memory", Timeout: 30 * time.Second},
+				Milvus:   MilvusMemoryConfig{Collection: "llmux_memory", Timeout: 30 * time.Second},
+			},
+		},
 		HealthCheck: HealthCheckConfig{
 			Enabled:  false,
 			Interval: 30 * time.Second,
 			Timeout:  10 * time.Second,
 		},
+		Kubernetes: KubernetesConfig{
+			WatchInterval: 10 * time.Second,
+			LeaderElection: LeaderElectionConfig{
+				Enabled:       true,
+				LeaseName:     "llmux-leader",
+				LeaseDuration: 15 * time.Second,
+				RetryPeriod:   2 * time.Second,
+			},
+			ReadinessRequiresProvider: true,
+		},
+		Preflight: PreflightConfig{
+			Enabled:       true,
+			ProviderCheck: "dry_run",
+			Timeout:       10 * time.Second,
+		},
 		MCP: MCPConfig{
 			Enabled:                  false,
 			Clients:                  []MCPClientConfig{},
@@ -533,12 +1503,35 @@ func LoadFromFile(path string) (*Config, error) {
 		return nil, fmt.Errorf("read config file: %w", err)
 	}
 
-	// Expand environment variables.
-	//
-	// Supports:
-	// - ${VAR_NAME}
-	// - ${VAR_NAME:default} (use default when VAR_NAME is unset or empty)
-	expanded := os.Expand(string(data), func(key string) string {
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Parse parses a YAML config document on top of the defaults, expanding
+// environment variables, without validating it.
+func Parse(data []byte) (*Config, error) {
+	cfg := DefaultConfig()
+	if err := yaml.Unmarshal([]byte(expandEnv(string(data))), cfg); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	return cfg, nil
+}
+
+// expandEnv expands environment variables in a config document.
+//
+// Supports:
+// - ${VAR_NAME}
+// - ${VAR_NAME:default} (use default when VAR_NAME is unset or empty)
+func expandEnv(data string) string {
+	return os.Expand(data, func(key string) string {
 		name := key
 		def := ""
 		if idx := strings.IndexByte(key, ':'); idx >= 0 {
@@ -554,17 +1547,6 @@ func LoadFromFile(path string) (*Config, error) {
 		}
 		return ""
 	})
-
-	cfg := DefaultConfig()
-	if err := yaml.Unmarshal([]byte(expanded), cfg); err != nil {
-		return nil, fmt.Errorf("parse config: %w", err)
-	}
-
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("validate config: %w", err)
-	}
-
-	return cfg, nil
 }
 
 // Validate checks the configuration for errors.
@@ -590,9 +1572,13 @@ func (c *Config) Validate() error {
 		}
 	}
 
-	if len(c.Providers) == 0 {
+	// With config.source db the providers may all live in the stored config.
+	if len(c.Providers) == 0 && c.ConfigSource.Source != ConfigSourceDB {
 		return fmt.Errorf("at least one provider must be configured")
 	}
+	if err := c.validateConfigSource(); err != nil {
+		return err
+	}
 
 	for i, p := range c.Providers {
 		if p.Name == "" {
@@ -613,6 +1599,48 @@ func (c *Config) Validate() error {
 		if p.MaxConcurrent < 0 {
 			return fmt.Errorf("provider[%d] %q: max_concurrent cannot be negative", i, p.Name)
 		}
+		if p.RPM < 0 || p.TPM < 0 {
+			return fmt.Errorf("provider[%d] %q: rpm and tpm cannot be negative", i, p.Name)
+		}
+		for model, limits := range p.ModelLimits {
+			if limits.RPM < 0 || limits.TPM < 0 {
+				return fmt.Errorf("provider[%d] %q: model_limits[%q]: rpm and tpm cannot be negative", i, p.Name, model)
+			}
+			if !containsString(p.Models, model) {
+				return fmt.Errorf("provider[%d] %q: model_limits[%q]: model is not configured", i, p.Name, model)
+			}
+		}
+		if p.ProxyURL != "" {
+			u, err := url.Parse(p.ProxyURL)
+			if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+				return fmt.Errorf("provider[%d] %q: proxy_url must be an http, https or socks5 URL", i, p.Name)
+			}
+		}
+		tr := p.Transport
+		if tr.MaxIdleConns < 0 || tr.MaxIdleConnsPerHost < 0 || tr.MaxConnsPerHost < 0 {
+			return fmt.Errorf("provider[%d] %q: transport connection limits cannot be negative", i, p.Name)
+		}
+		if tr.IdleConnTimeout < 0 || tr.DialTimeout < 0 {
+			return fmt.Errorf("provider[%d] %q: transport.idle_conn_timeout and transport.dial_timeout cannot be negative", i, p.Name)
+		}
+		// Bedrock and Vertex AI sign requests with their own cloud
+		// credentials, so a secondary api_key would never be used.
+		if p.SecondaryAPIKey != "" && (p.Type == "bedrock" || p.Type == "vertexai" || p.Type == "vertex_ai") {
+			return fmt.Errorf("provider[%d] %q: secondary_api_key is not supported for type %q", i, p.Name, p.Type)
+		}
+		ro := p.Rollover
+		if p.SecondaryAPIKey == "" && ro != (CredentialRolloverConfig{}) {
+			return fmt.Errorf("provider[%d] %q: rollover requires secondary_api_key", i, p.Name)
+		}
+		if ro.SecondaryWeight < 0 || ro.SecondaryWeight > 1 {
+			return fmt.Errorf("provider[%d] %q: rollover.secondary_weight must be between 0 and 1", i, p.Name)
+		}
+		if ro.RollbackErrorRate < 0 || ro.RollbackErrorRate > 1 {
+			return fmt.Errorf("provider[%d] %q: rollover.rollback_error_rate must be between 0 and 1", i, p.Name)
+		}
+		if ro.Start.IsZero() != ro.End.IsZero() || (!ro.Start.IsZero() && !ro.End.After(ro.Start)) {
+			return fmt.Errorf("provider[%d] %q: rollover.start and rollover.end must both be set with end after start", i, p.Name)
+		}
 	}
 
 	// Validate routing config
@@ -631,12 +1659,193 @@ func (c *Config) Validate() error {
 	if c.Routing.CooldownPeriod < 0 {
 		return fmt.Errorf("routing.cooldown_period cannot be negative")
 	}
+	if c.Routing.CooldownMaxPeriod < 0 {
+		return fmt.Errorf("routing.cooldown_max_period cannot be negative")
+	}
+	if c.Routing.HalfOpenSuccesses < 0 {
+		return fmt.Errorf("routing.half_open_successes cannot be negative")
+	}
+	for model, timeout := range c.Routing.ModelTimeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("routing.model_timeouts[%s] must be positive", model)
+		}
+	}
+	if cb := c.Routing.CircuitBreaker; cb.FailureThreshold < 0 || cb.SuccessThreshold < 0 || cb.OpenTimeout < 0 {
+		return fmt.Errorf("routing.circuit_breaker thresholds and open_timeout cannot be negative")
+	}
+	if spec := c.Routing.Speculative; spec.Enabled {
+		if len(spec.Drafts) == 0 {
+			return fmt.Errorf("routing.speculative.drafts is required when speculative routing is enabled")
+		}
+		switch spec.Verifier.Type {
+		case "logprob":
+		case "judge":
+			if spec.Verifier.JudgeModel == "" {
+				return fmt.Errorf("routing.speculative.verifier.judge_model is required for the judge verifier")
+			}
+		default:
+			return fmt.Errorf("routing.speculative.verifier.type must be logprob or judge")
+		}
+	}
+	if c.Routing.AdmissionQueueSize < 0 {
+		return fmt.Errorf("routing.admission_queue_size cannot be negative")
+	}
+	if c.Routing.AdmissionQueueTimeout < 0 {
+		return fmt.Errorf("routing.admission_queue_timeout cannot be negative")
+	}
+	if c.Routing.DecisionTraceSize < 0 {
+		return fmt.Errorf("routing.decision_trace_size cannot be negative")
+	}
+	if c.Cache.NegativeTTL < 0 {
+		return fmt.Errorf("cache.negative_ttl cannot be negative")
+	}
+	if c.Cache.Dual.LocalTTL < 0 || c.Cache.Dual.WriteQueueSize < 0 {
+		return fmt.Errorf("cache.dual.local_ttl and cache.dual.write_queue_size cannot be negative")
+	}
+	if c.Cache.TTL > 0 && c.Cache.Dual.LocalTTL > c.Cache.TTL {
+		return fmt.Errorf("cache.dual.local_ttl cannot exceed cache.ttl")
+	}
+	if c.Cache.Enabled {
+		switch strings.ToLower(c.Cache.Type) {
+		case "memcached":
+			if len(c.Cache.Memcached.Addrs) == 0 {
+				return fmt.Errorf("cache.memcached.addrs is required for memcached cache")
+			}
+		case "dynamodb":
+			if c.Cache.DynamoDB.Table == "" {
+				return fmt.Errorf("cache.dynamodb.table is required for dynamodb cache")
+			}
+		}
+	}
+	if err := c.validateJWTAuth(); err != nil {
+		return err
+	}
+	if err := c.validateKeyCache(); err != nil {
+		return err
+	}
+	if err := c.validateInvitations(); err != nil {
+		return err
+	}
+	if err := c.validateGuardrails(); err != nil {
+		return err
+	}
+	if err := c.validatePlugins(); err != nil {
+		return err
+	}
+	if c.MCP.Server.Enabled && !c.MCP.Enabled {
+		return fmt.Errorf("mcp.server.enabled requires mcp.enabled")
+	}
+	if err := c.validateAuditExport(); err != nil {
+		return err
+	}
+	if err := c.validateBudgetAlerts(); err != nil {
+		return err
+	}
+	if err := c.validateSpendReports(); err != nil {
+		return err
+	}
+	if err := c.validatePayloadLogging(); err != nil {
+		return err
+	}
+	if err := c.validateAnomalyLogging(); err != nil {
+		return err
+	}
+	if err := c.validateSessionMemory(); err != nil {
+		return err
+	}
+	if err := c.validateLongTermMemory(); err != nil {
+		return err
+	}
+	if err := c.validateMemoryQuotas(); err != nil {
+		return err
+	}
+	if c.Sandbox.EmbeddingDimensions < 0 {
+		return fmt.Errorf("sandbox.embedding_dimensions cannot be negative")
+	}
+	if c.Server.DrainTimeout < 0 {
+		return fmt.Errorf("server.drain_timeout cannot be negative")
+	}
+	if err := c.Server.TLS.validate(); err != nil {
+		return err
+	}
 	if c.HealthCheck.Interval < 0 {
 		return fmt.Errorf("healthcheck.interval cannot be negative")
 	}
 	if c.HealthCheck.Timeout < 0 {
 		return fmt.Errorf("healthcheck.timeout cannot be negative")
 	}
+	if err := c.Kubernetes.validate(); err != nil {
+		return err
+	}
+	switch c.Preflight.ProviderCheck {
+	case "", "probe", "dry_run", "off":
+	default:
+		return fmt.Errorf("preflight.provider_check must be one of probe, dry_run, off")
+	}
+	if c.Preflight.Timeout < 0 {
+		return fmt.Errorf("preflight.timeout cannot be negative")
+	}
+	if strings.HasPrefix(c.PricingFile, "http://") {
+		return fmt.Errorf("pricing_file must be a local path or an https URL")
+	}
+	if c.PricingRefresh < 0 {
+		return fmt.Errorf("pricing_refresh_interval cannot be negative")
+	}
+	switch c.PricingFallback.Policy {
+	case "", "fail", "warn":
+	case "default_rate":
+		if len(c.PricingFallback.DefaultRates) == 0 {
+			return fmt.Errorf("pricing_fallback.default_rates is required for the default_rate policy")
+		}
+	default:
+		return fmt.Errorf("pricing_fallback.policy must be one of fail, warn, default_rate")
+	}
+	switch c.MaxTokensPolicy {
+	case "", "clamp", "reject":
+	default:
+		return fmt.Errorf("max_tokens_policy must be one of clamp, reject")
+	}
+	for provider, rate := range c.PricingFallback.DefaultRates {
+		if rate.InputCostPerToken < 0 || rate.OutputCostPerToken < 0 {
+			return fmt.Errorf("pricing_fallback.default_rates[%s] cannot be negative", provider)
+		}
+	}
+	for _, p := range c.Providers {
+		for model, price := range p.Pricing {
+			if err := price.validate(); err != nil {
+				return fmt.Errorf("provider %q: pricing[%s]: %w", p.Name, model, err)
+			}
+		}
+	}
+	virtualModels := make(map[string]bool, len(c.VirtualModels))
+	for i, vm := range c.VirtualModels {
+		if vm.Name == "" {
+			return fmt.Errorf("virtual_models[%d]: name is required", i)
+		}
+		if virtualModels[vm.Name] {
+			return fmt.Errorf("virtual_models: duplicate name %q", vm.Name)
+		}
+		virtualModels[vm.Name] = true
+		if err := vm.validate(); err != nil {
+			return fmt.Errorf("virtual_models[%s]: %w", vm.Name, err)
+		}
+	}
+	for _, vm := range c.VirtualModels {
+		if virtualModels[vm.Model] {
+			return fmt.Errorf("virtual_models[%s]: model %q is itself a virtual model", vm.Name, vm.Model)
+		}
+	}
+	for prefix, path := range c.Tokenizer.SentencePiece {
+		if prefix == "" || path == "" {
+			return fmt.Errorf("tokenizer.sentencepiece entries need a model prefix and a file path")
+		}
+	}
+	if c.Encryption.Enabled && c.Encryption.MasterKey == "" {
+		return fmt.Errorf("encryption.master_key is required when encryption is enabled")
+	}
+	if c.ResponseSigning.Enabled && c.ResponseSigning.KeyFile == "" {
+		return fmt.Errorf("response_signing.key_file is required when response signing is enabled")
+	}
 	switch c.Stream.RecoveryMode {
 	case "", "off", "append", "retry":
 	default:
@@ -645,6 +1854,18 @@ func (c *Config) Validate() error {
 	if c.Stream.MaxAccumulatedBytes < 0 {
 		return fmt.Errorf("stream.max_accumulated_bytes cannot be negative")
 	}
+	if c.Stream.HeartbeatInterval < 0 {
+		return fmt.Errorf("stream.heartbeat_interval cannot be negative")
+	}
+	if c.Stream.IdleTimeout < 0 {
+		return fmt.Errorf("stream.idle_timeout cannot be negative")
+	}
+	if c.Stream.Resume.TTL < 0 {
+		return fmt.Errorf("stream.resume.ttl cannot be negative")
+	}
+	if c.Stream.Resume.MaxEvents < 0 {
+		return fmt.Errorf("stream.resume.max_events cannot be negative")
+	}
 
 	if c.CORS.MaxAge < 0 {
 		return fmt.Errorf("cors.max_age cannot be negative")
@@ -652,6 +1873,35 @@ func (c *Config) Validate() error {
 	if c.Governance.IdempotencyWindow < 0 {
 		return fmt.Errorf("governance.idempotency_window cannot be negative")
 	}
+	for i, policy := range c.Governance.ContentPolicies {
+		if !governance.IsContentCategory(policy.Category) {
+			return fmt.Errorf("governance.content_policies[%d].category must be one of general, code_generation, pii", i)
+		}
+		if policy.RPMLimit < 0 {
+			return fmt.Errorf("governance.content_policies[%d].rpm_limit cannot be negative", i)
+		}
+		if policy.RPMLimit > 0 && !c.RateLimit.Enabled {
+			return fmt.Errorf("governance.content_policies[%d].rpm_limit requires rate_limit.enabled", i)
+		}
+	}
+	seenTags := make(map[string]bool, len(c.Governance.TagBudgets))
+	for i, budget := range c.Governance.TagBudgets {
+		if budget.Tag == "" {
+			return fmt.Errorf("governance.tag_budgets[%d].tag is required", i)
+		}
+		if seenTags[budget.Tag] {
+			return fmt.Errorf("governance.tag_budgets[%d]: duplicate tag %q", i, budget.Tag)
+		}
+		seenTags[budget.Tag] = true
+		if budget.MaxBudget <= 0 {
+			return fmt.Errorf("governance.tag_budgets[%d].max_budget must be positive", i)
+		}
+		switch budget.BudgetDuration {
+		case "", "1d", "7d", "30d":
+		default:
+			return fmt.Errorf("governance.tag_budgets[%d].budget_duration must be one of 1d, 7d, 30d", i)
+		}
+	}
 	if !c.CORS.AllowAllOrigins {
 		if containsWildcard(c.CORS.DataOrigins.Allowlist) {
 			return fmt.Errorf("cors.data_origins.allowlist cannot include wildcard when allow_all_origins is false")
@@ -665,6 +1915,16 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("rate_limit.trusted_proxy_cidrs[%d] must be a valid IP or CIDR", i)
 		}
 	}
+	for priority, share := range c.RateLimit.PriorityReservations {
+		switch priority {
+		case "low", "normal", "high":
+		default:
+			return fmt.Errorf("rate_limit.priority_reservations: unknown priority %q (use low, normal or high)", priority)
+		}
+		if share <= 0 || share > 1 {
+			return fmt.Errorf("rate_limit.priority_reservations.%s must be in (0, 1]", priority)
+		}
+	}
 
 	if c.Database.Enabled {
 		if c.Database.Host == "" {
@@ -711,6 +1971,24 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	seenHosts := make(map[string]struct{}, len(c.Auth.HostTenants))
+	for i, tenant := range c.Auth.HostTenants {
+		host := strings.ToLower(strings.TrimSpace(tenant.Host))
+		if host == "" {
+			return fmt.Errorf("auth.host_tenants[%d].host is required", i)
+		}
+		if strings.Contains(strings.TrimPrefix(host, "*."), "*") {
+			return fmt.Errorf("auth.host_tenants[%d].host: wildcard is only allowed as a leading \"*.\" label", i)
+		}
+		if !strings.HasPrefix(host, "*.") && strings.TrimSpace(tenant.OrganizationID) == "" {
+			return fmt.Errorf("auth.host_tenants[%d].organization_id is required for exact hosts", i)
+		}
+		if _, ok := seenHosts[host]; ok {
+			return fmt.Errorf("auth.host_tenants[%d].host %q is duplicated", i, tenant.Host)
+		}
+		seenHosts[host] = struct{}{}
+	}
+
 	if c.Auth.Session.Enabled {
 		if strings.TrimSpace(c.Auth.Session.Secret) == "" {
 			return fmt.Errorf("auth.session.secret is required when auth.session.enabled is true")
@@ -742,10 +2020,479 @@ func normalizeDeploymentMode(mode string) (string, error) {
 	}
 }
 
+func (c *Config) validateSessionMemory() error {
+	session := c.Memory.Session
+	if session.MaxTurns < 0 || session.MaxTokens < 0 || session.TTL < 0 {
+		return fmt.Errorf("memory.session.max_turns, max_tokens and ttl cannot be negative")
+	}
+	if !session.Enabled {
+		return nil
+	}
+	switch session.Backend {
+	case "", "memory":
+	case "redis":
+		if !hasRedisConfig(c.Cache.Redis) {
+			return fmt.Errorf("memory.session.backend redis requires cache.redis.addr or cache.redis.cluster_addrs")
+		}
+	case "postgres":
+		if !c.Database.Enabled {
+			return fmt.Errorf("memory.session.backend postgres requires database.enabled")
+		}
+	default:
+		return fmt.Errorf("memory.session.backend must be memory, redis or postgres")
+	}
+	return nil
+}
+
+func (c *Config) validateLongTermMemory() error {
+	longTerm := c.Memory.LongTerm
+	if longTerm.Dimension < 0 || longTerm.TopK < 0 || longTerm.MinScore < 0 || longTerm.Retention < 0 {
+		return fmt.Errorf("memory.long_term.dimension, top_k, min_score and retention cannot be negative")
+	}
+	chunking := longTerm.Ingestion.Chunking
+	switch chunking.Strategy {
+	case "", "fixed", "paragraph", "markdown":
+	default:
+		return fmt.Errorf("memory.long_term.ingestion.chunking.strategy must be fixed, paragraph or markdown")
+	}
+	if chunking.Size < 0 || chunking.Overlap < 0 {
+		return fmt.Errorf("memory.long_term.ingestion.chunking.size and overlap cannot be negative")
+	}
+	chunkSize := chunking.Size
+	if chunkSize == 0 {
+		chunkSize = 1000
+	}
+	if chunking.Overlap >= chunkSize {
+		return fmt.Errorf("memory.long_term.ingestion.chunking.overlap must be smaller than size")
+	}
+	for contentType, command := range longTerm.Ingestion.Extractors {
+		if len(command) == 0 {
+			return fmt.Errorf("memory.long_term.ingestion.extractors.%s: command is required", contentType)
+		}
+	}
+	if !longTerm.Enabled {
+		return nil
+	}
+	if longTerm.EmbeddingModel == "" {
+		return fmt.Errorf("memory.long_term.embedding_model is required")
+	}
+	switch longTerm.Backend {
+	case "", "memory":
+		return nil
+	case "pgvector":
+		if !c.Database.Enabled {
+			return fmt.Errorf("memory.long_term.backend pgvector requires database.enabled")
+		}
+	case "qdrant":
+		if longTerm.Qdrant.APIBase == "" {
+			return fmt.Errorf("memory.long_term.qdrant.api_base is required")
+		}
+	case "milvus":
+		if longTerm.Milvus.Address == "" {
+			return fmt.Errorf("memory.long_term.milvus.address is required")
+		}
+	default:
+		return fmt.Errorf("memory.long_term.backend must be memory, pgvector, qdrant or milvus")
+	}
+	if longTerm.Dimension == 0 {
+		return fmt.Errorf("memory.long_term.dimension is required for the %s backend", longTerm.Backend)
+	}
+	return nil
+}
+
+func (c *Config) validateJWTAuth() error {
+	jwt := c.Auth.JWT
+	if !jwt.Enabled {
+		return nil
+	}
+	if !c.Auth.Enabled {
+		return fmt.Errorf("auth.jwt requires auth.enabled")
+	}
+	if jwt.Issuer == "" {
+		return fmt.Errorf("auth.jwt.issuer is required")
+	}
+	if jwt.JWKSURL != "" && !strings.HasPrefix(jwt.JWKSURL, "https://") && !strings.HasPrefix(jwt.JWKSURL, "http://") {
+		return fmt.Errorf("auth.jwt.jwks_url must be an http(s) URL")
+	}
+	return nil
+}
+
+func (c *Config) validateKeyCache() error {
+	kc := c.Auth.KeyCache
+	if !kc.Enabled {
+		return nil
+	}
+	if kc.Size < 0 {
+		return fmt.Errorf("auth.key_cache.size must be non-negative")
+	}
+	if kc.TTL < 0 {
+		return fmt.Errorf("auth.key_cache.ttl must be non-negative")
+	}
+	if kc.Distributed && c.Cache.Redis.Addr == "" && len(c.Cache.Redis.ClusterAddrs) == 0 {
+		return fmt.Errorf("auth.key_cache.distributed requires cache.redis.addr or cache.redis.cluster_addrs")
+	}
+	return nil
+}
+
+func (c *Config) validateInvitations() error {
+	inv := c.Auth.Invitations
+	if inv.DefaultExpiry < 0 || inv.MaxExpiry < 0 {
+		return fmt.Errorf("auth.invitations.default_expiry and max_expiry cannot be negative")
+	}
+	if inv.MaxExpiry > 0 && inv.DefaultExpiry > inv.MaxExpiry {
+		return fmt.Errorf("auth.invitations.default_expiry cannot exceed max_expiry")
+	}
+	return nil
+}
+
+func (c *Config) validateGuardrails() error {
+	seen := make(map[string]bool, len(c.Guardrails))
+	for i, g := range c.Guardrails {
+		if g.Name == "" {
+			return fmt.Errorf("guardrails[%d]: name is required", i)
+		}
+		if seen[g.Name] {
+			return fmt.Errorf("guardrails[%d]: duplicate name %q", i, g.Name)
+		}
+		seen[g.Name] = true
+		switch g.Mode {
+		case "", "pre", "post", "both":
+		default:
+			return fmt.Errorf("guardrails[%d] %q: mode must be pre, post or both", i, g.Name)
+		}
+		switch g.Action {
+		case "block", "annotate":
+		case "redact":
+			if g.Type != "regex" && g.Type != "pii" {
+				return fmt.Errorf("guardrails[%d] %q: redact is only supported for regex and pii guardrails", i, g.Name)
+			}
+		default:
+			return fmt.Errorf("guardrails[%d] %q: action must be block, redact or annotate", i, g.Name)
+		}
+		switch g.Type {
+		case "regex":
+			if len(g.Patterns) == 0 {
+				return fmt.Errorf("guardrails[%d] %q: patterns are required for regex guardrails", i, g.Name)
+			}
+			for _, p := range g.Patterns {
+				if _, err := regexp.Compile(p); err != nil {
+					return fmt.Errorf("guardrails[%d] %q: invalid pattern %q: %w", i, g.Name, p, err)
+				}
+			}
+		case "pii":
+		case "prompt_injection":
+			if g.Threshold < 0 || g.Threshold > 1 {
+				return fmt.Errorf("guardrails[%d] %q: threshold must be between 0 and 1", i, g.Name)
+			}
+			if c := g.Classifier; c.URL != "" {
+				if !strings.HasPrefix(c.URL, "https://") && !strings.HasPrefix(c.URL, "http://") {
+					return fmt.Errorf("guardrails[%d] %q: classifier.url must be an http(s) URL", i, g.Name)
+				}
+				if c.Model == "" {
+					return fmt.Errorf("guardrails[%d] %q: classifier.model is required", i, g.Name)
+				}
+			}
+		case "moderation":
+			if !strings.HasPrefix(g.Moderation.URL, "https://") && !strings.HasPrefix(g.Moderation.URL, "http://") {
+				return fmt.Errorf("guardrails[%d] %q: moderation.url must be an http(s) URL", i, g.Name)
+			}
+		default:
+			return fmt.Errorf("guardrails[%d] %q: type must be regex, pii, prompt_injection or moderation", i, g.Name)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validatePlugins() error {
+	seen := make(map[string]bool, len(c.Plugins))
+	builtins := make(map[string]bool)
+	for i, p := range c.Plugins {
+		if p.Name == "" {
+			return fmt.Errorf("plugins[%d]: name is required", i)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("plugins[%d]: duplicate name %q", i, p.Name)
+		}
+		seen[p.Name] = true
+		switch p.Type {
+		case "http":
+			if !strings.HasPrefix(p.URL, "https://") && !strings.HasPrefix(p.URL, "http://") {
+				return fmt.Errorf("plugins[%d] %q: url must be an http(s) URL", i, p.Name)
+			}
+			if p.Timeout < 0 {
+				return fmt.Errorf("plugins[%d] %q: timeout cannot be negative", i, p.Name)
+			}
+			switch p.FailurePolicy {
+			case "", "fail_open", "fail_closed":
+			default:
+				return fmt.Errorf("plugins[%d] %q: failure_policy must be fail_open or fail_closed", i, p.Name)
+			}
+			for _, hook := range p.Hooks {
+				if hook != "pre" && hook != "post" {
+					return fmt.Errorf("plugins[%d] %q: hooks must be pre or post", i, p.Name)
+				}
+			}
+		case "logging", "rate_limit":
+			// Built-in plugins have fixed names in the pipeline.
+			if builtins[p.Type] {
+				return fmt.Errorf("plugins[%d] %q: only one %s plugin is supported", i, p.Name, p.Type)
+			}
+			builtins[p.Type] = true
+			if p.Type == "logging" {
+				var lc LoggingPluginConfig
+				if err := p.DecodeConfig(&lc); err != nil {
+					return fmt.Errorf("plugins[%d] %q: invalid config: %w", i, p.Name, err)
+				}
+				break
+			}
+			var rc RateLimitPluginConfig
+			if err := p.DecodeConfig(&rc); err != nil {
+				return fmt.Errorf("plugins[%d] %q: invalid config: %w", i, p.Name, err)
+			}
+			if rc.Rate <= 0 {
+				return fmt.Errorf("plugins[%d] %q: config.rate must be positive", i, p.Name)
+			}
+			if rc.Burst < 0 {
+				return fmt.Errorf("plugins[%d] %q: config.burst cannot be negative", i, p.Name)
+			}
+		case "transform":
+			var tc TransformPluginConfig
+			if err := p.DecodeConfig(&tc); err != nil {
+				return fmt.Errorf("plugins[%d] %q: invalid config: %w", i, p.Name, err)
+			}
+			if err := tc.validate(); err != nil {
+				return fmt.Errorf("plugins[%d] %q: %w", i, p.Name, err)
+			}
+		default:
+			return fmt.Errorf("plugins[%d] %q: type must be http, logging, rate_limit or transform", i, p.Name)
+		}
+		for _, pattern := range append(append([]string(nil), p.Scope.Routes...), p.Scope.Models...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("plugins[%d] %q: invalid scope pattern %q", i, p.Name, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+func (tc TransformPluginConfig) validate() error {
+	switch tc.SystemPromptMode {
+	case "", "prepend", "append", "replace":
+	default:
+		return fmt.Errorf("config.system_prompt_mode must be prepend, append or replace")
+	}
+	templates := map[string]string{
+		"system_prompt":     tc.SystemPrompt,
+		"message_template":  tc.MessageTemplate,
+		"response_template": tc.ResponseTemplate,
+	}
+	for team, prompt := range tc.TeamSystemPrompts {
+		templates["team_system_prompts."+team] = prompt
+	}
+	for name, text := range templates {
+		if _, err := template.New(name).Parse(text); err != nil {
+			return fmt.Errorf("config.%s: %w", name, err)
+		}
+	}
+	if (tc.MaxTemperature != nil && *tc.MaxTemperature < 0) || (tc.MaxTopP != nil && *tc.MaxTopP < 0) || tc.MaxTokens < 0 {
+		return fmt.Errorf("config.max_temperature, max_top_p and max_tokens cannot be negative")
+	}
+	return nil
+}
+
+func (c *Config) validateAuditExport() error {
+	export := c.Governance.AuditExport
+	if export.ObjectStore.Enabled {
+		switch export.ObjectStore.Provider {
+		case "", "s3", "gcs":
+		default:
+			return fmt.Errorf("governance.audit_export.object_store.provider must be s3 or gcs")
+		}
+		if export.ObjectStore.Bucket == "" {
+			return fmt.Errorf("governance.audit_export.object_store.bucket is required")
+		}
+	}
+	if export.Webhook.Enabled && !strings.HasPrefix(export.Webhook.URL, "https://") && !strings.HasPrefix(export.Webhook.URL, "http://") {
+		return fmt.Errorf("governance.audit_export.webhook.url must be an http(s) URL")
+	}
+	if export.Syslog.Enabled {
+		switch export.Syslog.Network {
+		case "", "udp", "tcp":
+		default:
+			return fmt.Errorf("governance.audit_export.syslog.network must be udp or tcp")
+		}
+		if export.Syslog.Address == "" {
+			return fmt.Errorf("governance.audit_export.syslog.address is required")
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateBudgetAlerts() error {
+	alerts := c.Governance.BudgetAlerts
+	if !alerts.Enabled {
+		return nil
+	}
+	if alerts.DedupeWindow < 0 {
+		return fmt.Errorf("governance.budget_alerts.dedupe_window cannot be negative")
+	}
+	for _, t := range alerts.Thresholds {
+		if t <= 0 || t > 1 {
+			return fmt.Errorf("governance.budget_alerts.thresholds must be between 0 and 1, got %v", t)
+		}
+	}
+	for i, w := range alerts.Webhooks {
+		if !strings.HasPrefix(w.URL, "https://") && !strings.HasPrefix(w.URL, "http://") {
+			return fmt.Errorf("governance.budget_alerts.webhooks[%d].url must be an http(s) URL", i)
+		}
+	}
+	if alerts.Email.Enabled {
+		if alerts.Email.Host == "" || alerts.Email.From == "" || len(alerts.Email.To) == 0 {
+			return fmt.Errorf("governance.budget_alerts.email requires host, from and to")
+		}
+	}
+	if len(alerts.Webhooks) == 0 && !alerts.Email.Enabled {
+		return fmt.Errorf("governance.budget_alerts requires at least one webhook or email")
+	}
+	return nil
+}
+
+func (c *Config) validateSpendReports() error {
+	reports := c.Governance.SpendReports
+	if !reports.Enabled {
+		return nil
+	}
+	dims := make(map[string]bool, len(reports.GroupBy))
+	for _, d := range reports.GroupBy {
+		switch d {
+		case "key", "team", "model", "tag", "day":
+		default:
+			return fmt.Errorf("governance.spend_reports.group_by: unknown dimension %q (use key, team, model, tag or day)", d)
+		}
+		dims[d] = true
+	}
+	if dims["tag"] && (dims["key"] || dims["team"] || dims["model"]) {
+		return fmt.Errorf("governance.spend_reports.group_by: tag can only be combined with day")
+	}
+	switch reports.Format {
+	case "", "csv", "json":
+	default:
+		return fmt.Errorf("governance.spend_reports.format must be csv or json")
+	}
+	if reports.Email.Enabled {
+		if reports.Email.Host == "" || reports.Email.From == "" || len(reports.Email.To) == 0 {
+			return fmt.Errorf("governance.spend_reports.email requires host, from and to")
+		}
+	}
+	if store := reports.ObjectStore; store.Enabled {
+		if store.Bucket == "" {
+			return fmt.Errorf("governance.spend_reports.object_store.bucket is required")
+		}
+		switch store.Provider {
+		case "", "s3", "gcs":
+		default:
+			return fmt.Errorf("governance.spend_reports.object_store.provider must be s3 or gcs")
+		}
+	}
+	if !reports.Email.Enabled && !reports.ObjectStore.Enabled {
+		return fmt.Errorf("governance.spend_reports requires email or object_store")
+	}
+	return nil
+}
+
+func (c *Config) validateAnomalyLogging() error {
+	anomalies := c.Logging.Anomalies
+	if !anomalies.Enabled {
+		return nil
+	}
+	switch anomalies.Level {
+	case "", "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("logging.anomalies.level must be debug, info, warn or error")
+	}
+	if anomalies.LatencyThreshold < 0 || anomalies.TTFTThreshold < 0 || anomalies.CostThreshold < 0 ||
+		anomalies.FailureThreshold < 0 || anomalies.FailureWindow < 0 || anomalies.MaxPerMinute < 0 {
+		return fmt.Errorf("logging.anomalies thresholds must be non-negative")
+	}
+	if anomalies.LatencyThreshold == 0 && anomalies.TTFTThreshold == 0 &&
+		anomalies.CostThreshold == 0 && anomalies.FailureThreshold == 0 {
+		return fmt.Errorf("logging.anomalies requires at least one threshold")
+	}
+	if anomalies.SampleRate < 0 || anomalies.SampleRate > 1 {
+		return fmt.Errorf("logging.anomalies.sample_rate must be between 0 and 1")
+	}
+	return nil
+}
+
+func (c *Config) validatePayloadLogging() error {
+	logging := c.Governance.PayloadLogging
+	if !logging.Enabled {
+		return nil
+	}
+	if len(logging.Teams) == 0 {
+		return fmt.Errorf("governance.payload_logging.teams is required (use \"*\" for all teams)")
+	}
+	if logging.Retention < 0 {
+		return fmt.Errorf("governance.payload_logging.retention must be non-negative")
+	}
+	for i, rule := range logging.Redaction.Rules {
+		if rule.Pattern == "" {
+			return fmt.Errorf("governance.payload_logging.redaction.rules[%d].pattern is required", i)
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("governance.payload_logging.redaction.rules[%d].pattern: %w", i, err)
+		}
+	}
+	switch logging.Store {
+	case "", "database":
+	case "object_store":
+		if logging.ObjectStore.Bucket == "" {
+			return fmt.Errorf("governance.payload_logging.object_store.bucket is required")
+		}
+		switch logging.ObjectStore.Provider {
+		case "", "s3", "gcs":
+		default:
+			return fmt.Errorf("governance.payload_logging.object_store.provider must be s3 or gcs")
+		}
+	default:
+		return fmt.Errorf("governance.payload_logging.store must be database or object_store")
+	}
+	return nil
+}
+
+func (c *Config) validateMemoryQuotas() error {
+	quotas := c.Memory.Quotas
+	if quotas.MaxSessions < 0 || quotas.MaxVectors < 0 || quotas.MaxBytes < 0 {
+		return fmt.Errorf("memory.quotas.max_sessions, max_vectors and max_bytes cannot be negative")
+	}
+	if !quotas.Enabled {
+		return nil
+	}
+	switch quotas.Backend {
+	case "", "memory":
+	case "redis":
+		if !hasRedisConfig(c.Cache.Redis) {
+			return fmt.Errorf("memory.quotas.backend redis requires cache.redis.addr or cache.redis.cluster_addrs")
+		}
+	default:
+		return fmt.Errorf("memory.quotas.backend must be memory or redis")
+	}
+	return nil
+}
+
 func hasRedisConfig(cfg RedisCacheConfig) bool {
 	return cfg.Addr != "" || len(cfg.ClusterAddrs) > 0
 }
 
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
 func containsWildcard(values []string) bool {
 	for _, value := range values {
 		if value == "*" {
@@ -775,3 +2522,55 @@ func isValidSameSite(value string) bool {
 		return false
 	}
 }
+
+func (t ServerTLSConfig) validate() error {
+	if !t.Enabled {
+		return nil
+	}
+	if t.CertFile == "" || t.KeyFile == "" {
+		return fmt.Errorf("server.tls.cert_file and server.tls.key_file are required")
+	}
+	switch t.ClientAuth {
+	case "", ClientAuthNone, ClientAuthOptional, ClientAuthRequire:
+	default:
+		return fmt.Errorf("invalid server.tls.client_auth: %s (must be none, optional or require)", t.ClientAuth)
+	}
+	if t.ClientCAFile == "" && (t.ClientAuth == ClientAuthOptional || t.ClientAuth == ClientAuthRequire) {
+		return fmt.Errorf("server.tls.client_auth %s requires server.tls.client_ca_file", t.ClientAuth)
+	}
+	if len(t.ClientCertPaths) > 0 && (t.ClientCAFile == "" || t.ClientAuth == ClientAuthNone) {
+		return fmt.Errorf("server.tls.client_cert_paths requires server.tls.client_ca_file and client certificate verification")
+	}
+	switch t.MinVersion {
+	case "", "1.2", "1.3":
+	default:
+		return fmt.Errorf("invalid server.tls.min_version: %s (must be 1.2 or 1.3)", t.MinVersion)
+	}
+	if t.ReloadInterval < 0 {
+		return fmt.Errorf("server.tls.reload_interval cannot be negative")
+	}
+	return nil
+}
+
+func (k KubernetesConfig) validate() error {
+	if !k.Enabled {
+		return nil
+	}
+	if k.WatchInterval <= 0 {
+		return fmt.Errorf("kubernetes.watch_interval must be positive")
+	}
+	le := k.LeaderElection
+	if !le.Enabled {
+		return nil
+	}
+	if le.LeaseName == "" {
+		return fmt.Errorf("kubernetes.leader_election.lease_name is required")
+	}
+	if le.LeaseDuration < time.Second {
+		return fmt.Errorf("kubernetes.leader_election.lease_duration must be at least 1s")
+	}
+	if le.RetryPeriod <= 0 || le.RetryPeriod >= le.LeaseDuration {
+		return fmt.Errorf("kubernetes.leader_election.retry_period must be positive and shorter than lease_duration")
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -36,3 +37,37 @@ pricing_file: "/path/to/pricing.json"
 	// Verify PricingFile
 	assert.Equal(t, "/path/to/pricing.json", cfg.PricingFile)
 }
+
+func TestValidatePricingRefresh(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Providers = []config.ProviderConfig{{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}}}
+
+	cfg.PricingFile = "https://example.com/prices.json"
+	cfg.PricingRefresh = 15 * time.Minute
+	assert.NoError(t, cfg.Validate())
+
+	cfg.PricingRefresh = -time.Minute
+	assert.Error(t, cfg.Validate())
+
+	cfg.PricingRefresh = 0
+	cfg.PricingFile = "http://example.com/prices.json"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestValidateProviderPricing(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Providers = []config.ProviderConfig{{
+		Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"},
+		Pricing: map[string]config.ModelPriceConfig{"gpt-4": {
+			InputCostPerToken: 0.001,
+			Tiers:             []config.PriceTierConfig{{AboveInputTokens: 128000, InputCostPerToken: 0.002}},
+		}},
+	}}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Providers[0].Pricing["gpt-4"] = config.ModelPriceConfig{Tiers: []config.PriceTierConfig{{InputCostPerToken: 0.002}}}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Providers[0].Pricing["gpt-4"] = config.ModelPriceConfig{CostPerRequest: -1}
+	assert.Error(t, cfg.Validate())
+}
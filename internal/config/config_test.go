@@ -27,6 +27,10 @@ func TestDefaultConfig(t *testing.T) {
 		t.Errorf("default strategy = %s, want simple-shuffle", cfg.Routing.Strategy)
 	}
 
+	if !cfg.Routing.CircuitBreaker.Enabled {
+		t.Error("circuit breaker should be enabled by default")
+	}
+
 	if !cfg.Metrics.Enabled {
 		t.Error("metrics should be enabled by default")
 	}
@@ -47,100 +51,675 @@ func TestConfigValidation(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "valid config",
+			name: "valid config",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid port zero",
+			cfg: &Config{
+				Server: ServerConfig{Port: 0},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid port too high",
+			cfg: &Config{
+				Server: ServerConfig{Port: 70000},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid admin port negative",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080, AdminPort: -1},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid admin port too high",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080, AdminPort: 70000},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "admin port matches server port",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080, AdminPort: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "no providers",
+			cfg: &Config{
+				Server:    ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "provider missing name",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "provider missing type",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "provider missing api_key",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rollover without secondary api_key",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}, Rollover: CredentialRolloverConfig{SecondaryWeight: 0.5}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rollover weight above one",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", SecondaryAPIKey: "sk-next", Models: []string{"gpt-4"}, Rollover: CredentialRolloverConfig{SecondaryWeight: 1.5}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "credential rollover on bedrock",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "bedrock", Type: "bedrock", APIKey: "aws", SecondaryAPIKey: "aws-next", Models: []string{"claude-3"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "credential rollover on vertex ai",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "vertex", Type: "vertex_ai", APIKey: "gcp", SecondaryAPIKey: "gcp-next", Models: []string{"gemini-pro"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid credential rollover",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", SecondaryAPIKey: "sk-next", Models: []string{"gpt-4"}, Rollover: CredentialRolloverConfig{SecondaryWeight: 0.1, RollbackErrorRate: 0.2}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "dual cache local ttl exceeds ttl",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Cache: CacheConfig{Type: "dual", TTL: time.Minute, Dual: DualCacheConfig{LocalTTL: time.Hour}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "postgres session memory without database",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Memory: MemoryConfig{Session: SessionMemoryConfig{Enabled: true, Backend: "postgres"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "qdrant long-term memory without dimension",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Memory: MemoryConfig{LongTerm: LongTermMemoryConfig{
+					Enabled: true, Backend: "qdrant", EmbeddingModel: "text-embedding-3-small",
+					Qdrant: QdrantMemoryConfig{APIBase: "http://localhost:6333"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "redis memory quotas without redis",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Memory: MemoryConfig{Quotas: MemoryQuotaConfig{Enabled: true, Backend: "redis", MaxSessions: 10}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown ingestion chunking strategy",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Memory: MemoryConfig{LongTerm: LongTermMemoryConfig{
+					Ingestion: IngestionConfig{Chunking: ChunkingConfig{Strategy: "sentences"}},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "jwt auth without issuer",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Auth: AuthConfig{Enabled: true, JWT: JWTAuthConfig{Enabled: true}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "audit export object store without bucket",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Auth: AuthConfig{Enabled: true},
+				Governance: GovernanceConfig{AuditExport: AuditExportConfig{
+					ObjectStore: AuditObjectStoreExportConfig{Enabled: true, Provider: "gcs"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "audit export syslog with unsupported network",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Auth: AuthConfig{Enabled: true},
+				Governance: GovernanceConfig{AuditExport: AuditExportConfig{
+					Syslog: AuditSyslogExportConfig{Enabled: true, Network: "unix", Address: "/dev/log"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "distributed key cache without redis",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Auth: AuthConfig{Enabled: true, KeyCache: KeyCacheConfig{Enabled: true, Distributed: true}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invitation default expiry above max",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Auth: AuthConfig{Invitations: InvitationConfig{DefaultExpiry: 72 * time.Hour, MaxExpiry: 24 * time.Hour}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "guardrail redact on prompt injection",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Guardrails: []GuardrailConfig{{Name: "injection", Type: "prompt_injection", Action: "redact"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "guardrail classifier without model",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Guardrails: []GuardrailConfig{{Name: "injection", Type: "prompt_injection", Action: "block", Classifier: GuardrailClassifierConfig{URL: "https://api.openai.com/v1/chat/completions"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "guardrail invalid regex",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Guardrails: []GuardrailConfig{{Name: "deny", Type: "regex", Action: "block", Patterns: []string{"("}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid guardrails",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Guardrails: []GuardrailConfig{
+					{Name: "pii", Type: "pii", Mode: "both", Action: "redact", DefaultOn: true},
+					{Name: "moderation", Type: "moderation", Action: "block", Moderation: GuardrailModerationConfig{URL: "https://api.openai.com/v1/moderations"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "http plugin without url",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Plugins: []PluginConfig{{Name: "policy", Type: "http"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "http plugin invalid failure policy",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Plugins: []PluginConfig{{Name: "policy", Type: "http", URL: "http://policy:8000/hook", FailurePolicy: "retry"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rate_limit plugin without rate",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Plugins: []PluginConfig{{Name: "limit", Type: "rate_limit", Config: map[string]any{"burst": 5}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plugin config with unknown field",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Plugins: []PluginConfig{{Name: "log", Type: "logging", Config: map[string]any{"log_bodies": true}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "plugin invalid scope pattern",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Plugins: []PluginConfig{{Name: "log", Type: "logging", Scope: PluginScopeConfig{Models: []string{"gpt-["}}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid scoped builtin plugins",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Plugins: []PluginConfig{
+					{Name: "log", Type: "logging", Config: map[string]any{"log_request_body": true}, Scope: PluginScopeConfig{Routes: []string{"/v1/chat/*"}}},
+					{Name: "limit", Type: "rate_limit", Config: map[string]any{"rate": 2.5}, Scope: PluginScopeConfig{Teams: []string{"team-a"}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "transform plugin invalid template",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Plugins: []PluginConfig{{Name: "standards", Type: "transform", Config: map[string]any{"message_template": "{{.Content"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid transform plugin",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Plugins: []PluginConfig{{Name: "standards", Type: "transform", Config: map[string]any{
+					"system_prompt":       "Be concise.",
+					"team_system_prompts": map[string]any{"legal": "Cite sources."},
+					"max_temperature":     0.7,
+				}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid http plugin",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Plugins: []PluginConfig{{Name: "policy", Type: "http", URL: "http://policy:8000/hook", FailurePolicy: "fail_closed", Hooks: []string{"pre"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "budget alerts without sink",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Governance: GovernanceConfig{BudgetAlerts: BudgetAlertsConfig{Enabled: true}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "budget alert threshold above 1",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Governance: GovernanceConfig{BudgetAlerts: BudgetAlertsConfig{
+					Enabled:    true,
+					Thresholds: []float64{0.8, 1.5},
+					Webhooks:   []BudgetAlertWebhookConfig{{URL: "https://hooks.slack.com/services/x"}},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid budget alerts",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Governance: GovernanceConfig{BudgetAlerts: BudgetAlertsConfig{
+					Enabled:    true,
+					Thresholds: []float64{0.8, 1},
+					Email:      EmailConfig{Enabled: true, Host: "smtp.example.com", From: "llmux@example.com", To: []string{"finops@example.com"}},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "spend reports without sink",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Governance: GovernanceConfig{SpendReports: SpendReportsConfig{Enabled: true}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "spend reports tag with team",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Governance: GovernanceConfig{SpendReports: SpendReportsConfig{
+					Enabled:     true,
+					GroupBy:     []string{"tag", "team"},
+					ObjectStore: SpendReportObjectStoreConfig{Enabled: true, Bucket: "reports"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid spend reports",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Governance: GovernanceConfig{SpendReports: SpendReportsConfig{
+					Enabled:     true,
+					GroupBy:     []string{"tag", "day"},
+					Format:      "json",
+					ObjectStore: SpendReportObjectStoreConfig{Enabled: true, Provider: "gcs", Bucket: "reports"},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "tag budget invalid duration",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Governance: GovernanceConfig{TagBudgets: []TagBudgetConfig{{Tag: "project:alpha", MaxBudget: 10, BudgetDuration: "2w"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tag budget duplicate tag",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Governance: GovernanceConfig{TagBudgets: []TagBudgetConfig{
+					{Tag: "project:alpha", MaxBudget: 10},
+					{Tag: "project:alpha", MaxBudget: 20},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid tag budgets",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Governance: GovernanceConfig{TagBudgets: []TagBudgetConfig{{Tag: "project:alpha", MaxBudget: 10, BudgetDuration: "30d"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "payload logging without teams",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Governance: GovernanceConfig{PayloadLogging: PayloadLoggingConfig{Enabled: true}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "payload logging invalid redaction pattern",
 			cfg: &Config{
 				Server: ServerConfig{Port: 8080},
 				Providers: []ProviderConfig{
 					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
 				},
+				Governance: GovernanceConfig{PayloadLogging: PayloadLoggingConfig{Enabled: true, Teams: []string{"team-a"}, Redaction: PayloadRedactionConfig{Rules: []PayloadRedactionRule{{Pattern: "("}}}}},
 			},
-			wantErr: false,
+			wantErr: true,
 		},
 		{
-			name: "invalid port zero",
+			name: "payload logging object store without bucket",
 			cfg: &Config{
-				Server: ServerConfig{Port: 0},
+				Server: ServerConfig{Port: 8080},
 				Providers: []ProviderConfig{
 					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
 				},
+				Governance: GovernanceConfig{PayloadLogging: PayloadLoggingConfig{Enabled: true, Teams: []string{"*"}, Store: "object_store"}},
 			},
 			wantErr: true,
 		},
 		{
-			name: "invalid port too high",
+			name: "valid payload logging",
 			cfg: &Config{
-				Server: ServerConfig{Port: 70000},
+				Server: ServerConfig{Port: 8080},
 				Providers: []ProviderConfig{
 					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
 				},
+				Governance: GovernanceConfig{PayloadLogging: PayloadLoggingConfig{Enabled: true, Teams: []string{"team-a"}, Retention: 24 * time.Hour, Redaction: PayloadRedactionConfig{Defaults: true, Rules: []PayloadRedactionRule{{Pattern: `acct-\d+`}}}}},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
-			name: "invalid admin port negative",
+			name: "anomaly logging without thresholds",
 			cfg: &Config{
-				Server: ServerConfig{Port: 8080, AdminPort: -1},
+				Server: ServerConfig{Port: 8080},
 				Providers: []ProviderConfig{
 					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
 				},
+				Logging: LoggingConfig{Anomalies: AnomalyLoggingConfig{Enabled: true}},
 			},
 			wantErr: true,
 		},
 		{
-			name: "invalid admin port too high",
+			name: "anomaly logging sample rate out of range",
 			cfg: &Config{
-				Server: ServerConfig{Port: 8080, AdminPort: 70000},
+				Server: ServerConfig{Port: 8080},
 				Providers: []ProviderConfig{
 					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
 				},
+				Logging: LoggingConfig{Anomalies: AnomalyLoggingConfig{Enabled: true, LatencyThreshold: time.Second, SampleRate: 2}},
 			},
 			wantErr: true,
 		},
 		{
-			name: "admin port matches server port",
+			name: "valid anomaly logging",
 			cfg: &Config{
-				Server: ServerConfig{Port: 8080, AdminPort: 8080},
+				Server: ServerConfig{Port: 8080},
 				Providers: []ProviderConfig{
 					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
 				},
+				Logging: LoggingConfig{Anomalies: AnomalyLoggingConfig{Enabled: true, Level: "error", CostThreshold: 0.5, FailureThreshold: 3, SampleRate: 0.1}},
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
-			name: "no providers",
+			name: "rate limit priority reservation with unknown priority",
 			cfg: &Config{
-				Server:    ServerConfig{Port: 8080},
-				Providers: []ProviderConfig{},
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				RateLimit: RateLimitConfig{PriorityReservations: map[string]float64{"urgent": 0.2}},
 			},
 			wantErr: true,
 		},
 		{
-			name: "provider missing name",
+			name: "rate limit priority reservation out of range",
 			cfg: &Config{
 				Server: ServerConfig{Port: 8080},
 				Providers: []ProviderConfig{
-					{Name: "", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
 				},
+				RateLimit: RateLimitConfig{PriorityReservations: map[string]float64{"high": 1.5}},
 			},
 			wantErr: true,
 		},
 		{
-			name: "provider missing type",
+			name: "valid rate limit priority reservation",
 			cfg: &Config{
 				Server: ServerConfig{Port: 8080},
 				Providers: []ProviderConfig{
-					{Name: "openai", Type: "", APIKey: "sk-test", Models: []string{"gpt-4"}},
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				RateLimit: RateLimitConfig{PriorityReservations: map[string]float64{"high": 0.2}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "memcached cache without addrs",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
 				},
+				Cache: CacheConfig{Enabled: true, Type: "memcached"},
 			},
 			wantErr: true,
 		},
 		{
-			name: "provider missing api_key",
+			name: "dynamodb cache without table",
 			cfg: &Config{
 				Server: ServerConfig{Port: 8080},
 				Providers: []ProviderConfig{
-					{Name: "openai", Type: "openai", APIKey: "", Models: []string{"gpt-4"}},
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
 				},
+				Cache: CacheConfig{Enabled: true, Type: "dynamodb"},
 			},
 			wantErr: true,
 		},
@@ -197,6 +776,28 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "non-positive model timeout",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Routing: RoutingConfig{ModelTimeouts: map[string]time.Duration{"o1": 0}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative circuit breaker threshold",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Routing: RoutingConfig{CircuitBreaker: CircuitBreakerConfig{Enabled: true, FailureThreshold: -1}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "retry jitter out of range",
 			cfg: &Config{
@@ -230,6 +831,51 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "kubernetes retry period not shorter than lease",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+				Kubernetes: KubernetesConfig{
+					Enabled:       true,
+					WatchInterval: 10 * time.Second,
+					LeaderElection: LeaderElectionConfig{
+						Enabled:       true,
+						LeaseName:     "llmux-leader",
+						LeaseDuration: 15 * time.Second,
+						RetryPeriod:   15 * time.Second,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "provider proxy url without scheme",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}, ProxyURL: "proxy.internal:3128"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tls client cert paths without client CA",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080, TLS: ServerTLSConfig{
+					Enabled:         true,
+					CertFile:        "tls.crt",
+					KeyFile:         "tls.key",
+					ClientCertPaths: []string{"/control/"},
+				}},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "database enabled missing user",
 			cfg: &Config{
@@ -310,6 +956,230 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "provider rate limits",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{
+						Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"},
+						RPM: 500, TPM: 90000,
+						ModelLimits: map[string]ModelLimitConfig{"gpt-4": {RPM: 100}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative provider rpm",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}, RPM: -1},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "model limits for unknown model",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Providers: []ProviderConfig{
+					{
+						Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"},
+						ModelLimits: map[string]ModelLimitConfig{"gpt-3.5-turbo": {TPM: 1000}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "host tenants",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Auth: AuthConfig{HostTenants: []HostTenantConfig{
+					{Host: "org1.gateway.example.com", OrganizationID: "org1", DefaultTags: []string{"eu"}},
+					{Host: "*.gateway.example.com"},
+				}},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "exact host tenant without organization",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Auth:   AuthConfig{HostTenants: []HostTenantConfig{{Host: "org1.gateway.example.com"}}},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate host tenant",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Auth: AuthConfig{HostTenants: []HostTenantConfig{
+					{Host: "*.gateway.example.com"},
+					{Host: "*.Gateway.example.com"},
+				}},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "response signing without key file",
+			cfg: &Config{
+				Server:          ServerConfig{Port: 8080},
+				ResponseSigning: ResponseSigningConfig{Enabled: true},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid preflight provider check",
+			cfg: &Config{
+				Server:    ServerConfig{Port: 8080},
+				Preflight: PreflightConfig{Enabled: true, ProviderCheck: "ping"},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "default_rate pricing fallback without rates",
+			cfg: &Config{
+				Server:          ServerConfig{Port: 8080},
+				PricingFallback: PricingFallbackConfig{Policy: "default_rate"},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid max tokens policy",
+			cfg: &Config{
+				Server:          ServerConfig{Port: 8080},
+				MaxTokensPolicy: "truncate",
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sentencepiece tokenizer without path",
+			cfg: &Config{
+				Server:    ServerConfig{Port: 8080},
+				Tokenizer: TokenizerConfig{SentencePiece: map[string]string{"gemma": ""}},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "encryption without master key",
+			cfg: &Config{
+				Server:     ServerConfig{Port: 8080},
+				Encryption: EncryptionConfig{Enabled: true},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "speculative judge without model",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Routing: RoutingConfig{Speculative: SpeculativeConfig{
+					Enabled:  true,
+					Drafts:   map[string]string{"gpt-4": "gpt-4o-mini"},
+					Verifier: SpeculativeVerifierConfig{Type: "judge"},
+				}},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown content policy category",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Governance: GovernanceConfig{
+					ContentPolicies: []ContentPolicyConfig{{Category: "malware"}},
+				},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid virtual model",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				VirtualModels: []VirtualModelConfig{{
+					Name:       "support-bot",
+					Model:      "gpt-4",
+					Tools:      []VirtualToolConfig{{Name: "lookup_order"}},
+					ToolChoice: "lookup_order",
+				}},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "virtual model targeting a virtual model",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				VirtualModels: []VirtualModelConfig{
+					{Name: "support-bot", Model: "sales-bot"},
+					{Name: "sales-bot", Model: "gpt-4"},
+				},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "virtual model tool choice not among its tools",
+			cfg: &Config{
+				Server:        ServerConfig{Port: 8080},
+				VirtualModels: []VirtualModelConfig{{Name: "support-bot", Model: "gpt-4", ToolChoice: "lookup_order"}},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "content policy rpm without rate limiting",
+			cfg: &Config{
+				Server: ServerConfig{Port: 8080},
+				Governance: GovernanceConfig{
+					ContentPolicies: []ContentPolicyConfig{{Category: "code_generation", RPMLimit: 10}},
+				},
+				Providers: []ProviderConfig{
+					{Name: "openai", Type: "openai", APIKey: "sk-test", Models: []string{"gpt-4"}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
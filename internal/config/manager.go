@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"log/slog"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -23,6 +25,13 @@ type Manager struct {
 	checksum    atomic.Value
 	loadedAt    atomic.Value
 	reloadCount atomic.Uint64
+
+	// mu serializes config changes. file is the parsed config file; with
+	// config.source db the active stored version is applied on top of it.
+	mu       sync.Mutex
+	file     *Config
+	versions VersionStore
+	version  atomic.Pointer[ConfigVersion]
 }
 
 // NewManager creates a new configuration manager.
@@ -35,6 +44,7 @@ func NewManager(path string, logger *slog.Logger) (*Manager, error) {
 	m := &Manager{
 		path:   path,
 		logger: logger,
+		file:   cfg,
 	}
 	if err := m.storeConfig(cfg); err != nil {
 		return nil, err
@@ -57,6 +67,8 @@ func (m *Manager) OnChange(fn func(*Config)) {
 // ConfigStatus contains the current config metadata.
 type ConfigStatus struct {
 	Path        string    `json:"path"`
+	Source      string    `json:"source"`
+	Version     int       `json:"version,omitempty"` // Active stored version with source db
 	Checksum    string    `json:"checksum"`
 	LoadedAt    time.Time `json:"loaded_at"`
 	ReloadCount uint64    `json:"reload_count"`
@@ -66,8 +78,13 @@ type ConfigStatus struct {
 func (m *Manager) Status() ConfigStatus {
 	status := ConfigStatus{
 		Path:        m.path,
+		Source:      ConfigSourceFile,
 		ReloadCount: m.reloadCount.Load(),
 	}
+	if v := m.version.Load(); v != nil {
+		status.Source = ConfigSourceDB
+		status.Version = v.Version
+	}
 	if value, ok := m.checksum.Load().(string); ok {
 		status.Checksum = value
 	}
@@ -135,12 +152,23 @@ func (m *Manager) watchLoop(ctx context.Context) {
 	}
 }
 
-// Reload forces a configuration reload from disk.
+// Reload forces a configuration reload from disk. With config.source db
+// the active stored version is applied on top of the new file.
 func (m *Manager) Reload() error {
-	newCfg, err := LoadFromFile(m.path)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fileCfg, err := LoadFromFile(m.path)
 	if err != nil {
 		return err
 	}
+	newCfg := fileCfg
+	if v := m.version.Load(); v != nil {
+		if newCfg, err = fileCfg.WithDocument(v.Document); err != nil {
+			return fmt.Errorf("config version %d: %w", v.Version, err)
+		}
+	}
+	m.file = fileCfg
 
 	// Atomic swap
 	if err := m.storeConfig(newCfg); err != nil {
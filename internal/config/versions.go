@@ -0,0 +1,345 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config sources.
+const (
+	ConfigSourceFile = "file"
+	ConfigSourceDB   = "db"
+)
+
+var (
+	// ErrFileConfigSource is returned by version operations when the config
+	// is read from the file only.
+	ErrFileConfigSource = errors.New("config versions require config.source db")
+	// ErrInvalidConfig wraps validation failures of a stored config document.
+	ErrInvalidConfig = errors.New("invalid config")
+	// ErrConfigVersionNotFound is returned when a config version does not exist.
+	ErrConfigVersionNotFound = errors.New("config version not found")
+)
+
+// ConfigSourceConfig selects where the shared part of the config comes from.
+// With source db, providers, routing and governance are read from versioned
+// documents in the database, so every instance serves the same settings;
+// the file still supplies everything else, including the database itself.
+type ConfigSourceConfig struct {
+	Source       string        `yaml:"source"`        // file (default) or db
+	PollInterval time.Duration `yaml:"poll_interval"` // How often instances check for a new version (db only)
+}
+
+func (c *Config) validateConfigSource() error {
+	switch c.ConfigSource.Source {
+	case "", ConfigSourceFile:
+		return nil
+	case ConfigSourceDB:
+		if !c.Database.Enabled {
+			return fmt.Errorf("config.source db requires database.enabled")
+		}
+		if c.ConfigSource.PollInterval < 0 {
+			return fmt.Errorf("config.poll_interval cannot be negative")
+		}
+		return nil
+	default:
+		return fmt.Errorf("config.source must be %q or %q", ConfigSourceFile, ConfigSourceDB)
+	}
+}
+
+// SharedConfig is the part of the config stored in the database with
+// config.source db. Documents may reference environment variables as
+// ${VAR}, expanded each time the document is applied; API keys should be
+// such references or secret references rather than literal keys.
+type SharedConfig struct {
+	Providers  []ProviderConfig `yaml:"providers"`
+	Routing    RoutingConfig    `yaml:"routing"`
+	Governance GovernanceConfig `yaml:"governance"`
+}
+
+// ConfigVersion is one stored version of the shared config document.
+// Versions are append-only; a rollback stores an old document again.
+type ConfigVersion struct {
+	Version   int       `json:"version"`
+	Document  string    `json:"document"` // YAML (or JSON) SharedConfig
+	Checksum  string    `json:"checksum"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// VersionStore persists config versions.
+type VersionStore interface {
+	// LatestConfigVersion returns the newest version, or nil when none exists.
+	LatestConfigVersion(ctx context.Context) (*ConfigVersion, error)
+	// GetConfigVersion returns the given version, or nil when it does not exist.
+	GetConfigVersion(ctx context.Context, version int) (*ConfigVersion, error)
+	// ListConfigVersions returns up to limit versions, newest first.
+	ListConfigVersions(ctx context.Context, limit int) ([]*ConfigVersion, error)
+	// CreateConfigVersion stores v, assigning its Version and CreatedAt.
+	CreateConfigVersion(ctx context.Context, v *ConfigVersion) error
+}
+
+// Shared returns the shared part of c.
+func (c *Config) Shared() SharedConfig {
+	return SharedConfig{
+		Providers:  c.Providers,
+		Routing:    c.Routing,
+		Governance: c.Governance,
+	}
+}
+
+// WithDocument returns a copy of c with the shared config document applied
+// and validated, expanding environment variables in it. Sections and keys
+// the document omits keep the values of c; keys outside the shared config
+// are rejected.
+func (c *Config) WithDocument(document string) (*Config, error) {
+	// Start from a copy of c's sections so decoding into maps leaves c intact.
+	base, err := MarshalSharedConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("marshal shared config: %w", err)
+	}
+	var shared SharedConfig
+	if err := yaml.Unmarshal([]byte(base), &shared); err != nil {
+		return nil, fmt.Errorf("copy shared config: %w", err)
+	}
+
+	dec := yaml.NewDecoder(strings.NewReader(expandEnv(document)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&shared); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: parse document: %v", ErrInvalidConfig, err)
+	}
+
+	merged := *c
+	merged.Providers = shared.Providers
+	merged.Routing = shared.Routing
+	merged.Governance = shared.Governance
+	if len(merged.Providers) == 0 {
+		return nil, fmt.Errorf("%w: at least one provider must be configured", ErrInvalidConfig)
+	}
+	if err := merged.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return &merged, nil
+}
+
+// MarshalSharedConfig renders the shared part of c as a YAML document.
+func MarshalSharedConfig(c *Config) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c.Shared()); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// sharedSections are the top-level keys of the config file that make up
+// SharedConfig.
+var sharedSections = []string{"providers", "routing", "governance"}
+
+// rawSharedDocument returns the shared sections of the config file at path
+// as written, leaving ${VAR} references unexpanded so secrets resolved from
+// the environment are never stored and keep following the environment.
+func rawSharedDocument(path string) (string, error) {
+	// #nosec G304 -- path is the config file the manager was created with.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read config file: %w", err)
+	}
+	var file map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return "", fmt.Errorf("parse config: %w", err)
+	}
+	shared := make(map[string]*yaml.Node, len(sharedSections))
+	for _, key := range sharedSections {
+		if node, ok := file[key]; ok {
+			shared[key] = &node
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(shared); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// DocumentChecksum returns the checksum stored with a config version.
+func DocumentChecksum(document string) string {
+	sum := sha256.Sum256([]byte(document))
+	return hex.EncodeToString(sum[:])
+}
+
+// UseVersionStore switches m to config.source db: the latest stored version
+// is applied on top of the file and listeners are notified. An empty store
+// is seeded with the shared sections of the file as written, before
+// environment expansion.
+func (m *Manager) UseVersionStore(ctx context.Context, store VersionStore) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	latest, err := store.LatestConfigVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("load config version: %w", err)
+	}
+	if latest == nil {
+		document, err := rawSharedDocument(m.path)
+		if err != nil {
+			return fmt.Errorf("read shared config: %w", err)
+		}
+		latest = &ConfigVersion{
+			Document: document,
+			Checksum: DocumentChecksum(document),
+			Comment:  "seeded from " + m.path,
+		}
+		if err := store.CreateConfigVersion(ctx, latest); err != nil {
+			return fmt.Errorf("seed config version: %w", err)
+		}
+		m.logger.Info("seeded stored config from file", "version", latest.Version)
+	}
+
+	m.versions = store
+	return m.applyVersion(latest)
+}
+
+// WatchVersions polls the version store for versions committed by other
+// instances until ctx is done. It does nothing unless UseVersionStore was
+// called.
+func (m *Manager) WatchVersions(ctx context.Context) {
+	if m.versions == nil {
+		return
+	}
+	interval := m.file.ConfigSource.PollInterval
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := m.syncVersion(ctx); err != nil {
+					m.logger.Error("failed to sync stored config, keeping current", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+func (m *Manager) syncVersion(ctx context.Context) error {
+	latest, err := m.versions.LatestConfigVersion(ctx)
+	if err != nil || latest == nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if current := m.version.Load(); current != nil && current.Version >= latest.Version {
+		return nil
+	}
+	return m.applyVersion(latest)
+}
+
+// ActiveVersion returns the stored config version in effect, or nil with
+// config.source file.
+func (m *Manager) ActiveVersion() *ConfigVersion {
+	return m.version.Load()
+}
+
+// ListVersions returns up to limit stored versions, newest first.
+func (m *Manager) ListVersions(ctx context.Context, limit int) ([]*ConfigVersion, error) {
+	if m.versions == nil {
+		return nil, ErrFileConfigSource
+	}
+	return m.versions.ListConfigVersions(ctx, limit)
+}
+
+// GetVersion returns a stored version.
+func (m *Manager) GetVersion(ctx context.Context, version int) (*ConfigVersion, error) {
+	if m.versions == nil {
+		return nil, ErrFileConfigSource
+	}
+	v, err := m.versions.GetConfigVersion(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, ErrConfigVersionNotFound
+	}
+	return v, nil
+}
+
+// CommitVersion validates document against the file, stores it as a new
+// version and applies it.
+func (m *Manager) CommitVersion(ctx context.Context, document, comment, createdBy string) (*ConfigVersion, error) {
+	if m.versions == nil {
+		return nil, ErrFileConfigSource
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.file.WithDocument(document); err != nil {
+		return nil, err
+	}
+	v := &ConfigVersion{
+		Document:  document,
+		Checksum:  DocumentChecksum(document),
+		Comment:   comment,
+		CreatedBy: createdBy,
+	}
+	if err := m.versions.CreateConfigVersion(ctx, v); err != nil {
+		return nil, fmt.Errorf("store config version: %w", err)
+	}
+	if err := m.applyVersion(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// RollbackVersion commits the document of an earlier version again.
+func (m *Manager) RollbackVersion(ctx context.Context, version int, createdBy string) (*ConfigVersion, error) {
+	target, err := m.GetVersion(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	return m.CommitVersion(ctx, target.Document, fmt.Sprintf("rollback to version %d", version), createdBy)
+}
+
+// applyVersion makes v the active version. The caller holds m.mu.
+func (m *Manager) applyVersion(v *ConfigVersion) error {
+	cfg, err := m.file.WithDocument(v.Document)
+	if err != nil {
+		return fmt.Errorf("config version %d: %w", v.Version, err)
+	}
+	if err := m.storeConfig(cfg); err != nil {
+		return err
+	}
+	m.version.Store(v)
+	m.logger.Info("stored config applied", "version", v.Version)
+
+	for _, fn := range m.onChange {
+		fn(cfg)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryVersionStore is an in-memory VersionStore.
+type memoryVersionStore struct {
+	versions []*ConfigVersion
+}
+
+func (s *memoryVersionStore) LatestConfigVersion(context.Context) (*ConfigVersion, error) {
+	if len(s.versions) == 0 {
+		return nil, nil
+	}
+	return s.versions[len(s.versions)-1], nil
+}
+
+func (s *memoryVersionStore) GetConfigVersion(_ context.Context, version int) (*ConfigVersion, error) {
+	if version < 1 || version > len(s.versions) {
+		return nil, nil
+	}
+	return s.versions[version-1], nil
+}
+
+func (s *memoryVersionStore) ListConfigVersions(_ context.Context, limit int) ([]*ConfigVersion, error) {
+	var out []*ConfigVersion
+	for i := len(s.versions) - 1; i >= 0 && len(out) < limit; i-- {
+		out = append(out, s.versions[i])
+	}
+	return out, nil
+}
+
+func (s *memoryVersionStore) CreateConfigVersion(_ context.Context, v *ConfigVersion) error {
+	v.Version = len(s.versions) + 1
+	v.CreatedAt = time.Now()
+	s.versions = append(s.versions, v)
+	return nil
+}
+
+const dbSourceConfig = `
+config:
+  source: db
+database:
+  enabled: true
+  host: localhost
+  user: llmux
+  database: llmux
+  ssl_mode: disable
+providers:
+  - name: file-provider
+    type: openai
+    api_key: test-key
+    models:
+      - gpt-4
+routing:
+  retry_count: 2
+`
+
+func TestManager_VersionStore(t *testing.T) {
+	path := writeConfigFile(t, dbSourceConfig)
+	mgr, err := NewManager(path, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if _, err := mgr.ListVersions(context.Background(), 10); !errors.Is(err, ErrFileConfigSource) {
+		t.Fatalf("ListVersions() before UseVersionStore error = %v", err)
+	}
+	var applied []*Config
+	mgr.OnChange(func(cfg *Config) { applied = append(applied, cfg) })
+
+	// An empty store is seeded from the file.
+	store := &memoryVersionStore{}
+	ctx := context.Background()
+	if err := mgr.UseVersionStore(ctx, store); err != nil {
+		t.Fatalf("UseVersionStore() error = %v", err)
+	}
+	if len(store.versions) != 1 || mgr.ActiveVersion().Version != 1 {
+		t.Fatalf("expected a seeded version, got %+v", store.versions)
+	}
+	if got := mgr.Get().Providers[0].Name; got != "file-provider" {
+		t.Fatalf("seeded provider = %q", got)
+	}
+
+	// Keys the document omits keep the file's values.
+	v, err := mgr.CommitVersion(ctx, `
+providers:
+  - name: shared-provider
+    type: anthropic
+    api_key: vault://llm/anthropic
+    models: [claude-3-5-sonnet]
+`, "switch provider", "admin")
+	if err != nil {
+		t.Fatalf("CommitVersion() error = %v", err)
+	}
+	if v.Version != 2 || v.CreatedBy != "admin" || v.Checksum == "" {
+		t.Fatalf("committed version = %+v", v)
+	}
+	cfg := mgr.Get()
+	if len(cfg.Providers) != 1 || cfg.Providers[0].Name != "shared-provider" || cfg.Routing.RetryCount != 2 {
+		t.Fatalf("config after commit: providers=%+v retry_count=%d", cfg.Providers, cfg.Routing.RetryCount)
+	}
+	if len(applied) != 2 || applied[1] != cfg {
+		t.Fatalf("listeners notified %d times", len(applied))
+	}
+	if status := mgr.Status(); status.Source != ConfigSourceDB || status.Version != 2 {
+		t.Fatalf("Status() = %+v", status)
+	}
+
+	for _, doc := range []string{
+		"database:\n  enabled: false\n",
+		"providers: []\n",
+		"routing:\n  retry_count: -1\n",
+	} {
+		if _, err := mgr.CommitVersion(ctx, doc, "", ""); !errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("CommitVersion(%q) error = %v, want ErrInvalidConfig", doc, err)
+		}
+	}
+	if len(store.versions) != 2 {
+		t.Fatalf("invalid documents were stored: %d versions", len(store.versions))
+	}
+
+	// A rollback stores the old document as a new version.
+	v, err = mgr.RollbackVersion(ctx, 1, "admin")
+	if err != nil {
+		t.Fatalf("RollbackVersion() error = %v", err)
+	}
+	if v.Version != 3 || v.Document != store.versions[0].Document || mgr.Get().Providers[0].Name != "file-provider" {
+		t.Fatalf("rollback version = %+v", v)
+	}
+	if _, err := mgr.RollbackVersion(ctx, 42, ""); !errors.Is(err, ErrConfigVersionNotFound) {
+		t.Fatalf("RollbackVersion(42) error = %v", err)
+	}
+
+	// Versions committed by another instance are picked up.
+	other := &ConfigVersion{Document: "routing:\n  retry_count: 5\n"}
+	if err := store.CreateConfigVersion(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.syncVersion(ctx); err != nil {
+		t.Fatalf("syncVersion() error = %v", err)
+	}
+	if mgr.ActiveVersion().Version != 4 || mgr.Get().Routing.RetryCount != 5 {
+		t.Fatalf("sync did not apply version 4: %+v", mgr.ActiveVersion())
+	}
+
+	// File reloads keep the stored version on top.
+	if err := os.WriteFile(path, []byte(dbSourceConfig+"server:\n  port: 9090\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if cfg := mgr.Get(); cfg.Server.Port != 9090 || cfg.Routing.RetryCount != 5 {
+		t.Fatalf("reload: port=%d retry_count=%d", cfg.Server.Port, cfg.Routing.RetryCount)
+	}
+}
+
+func TestManager_VersionStoreSeedKeepsEnvReferences(t *testing.T) {
+	t.Setenv("LLMUX_TEST_SEED_KEY", "sk-from-env")
+	path := writeConfigFile(t, strings.Replace(dbSourceConfig, "api_key: test-key", "api_key: ${LLMUX_TEST_SEED_KEY}", 1))
+	mgr, err := NewManager(path, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	store := &memoryVersionStore{}
+	if err := mgr.UseVersionStore(context.Background(), store); err != nil {
+		t.Fatalf("UseVersionStore() error = %v", err)
+	}
+
+	seeded := store.versions[0].Document
+	if strings.Contains(seeded, "sk-from-env") || !strings.Contains(seeded, "${LLMUX_TEST_SEED_KEY}") {
+		t.Fatalf("seeded document stores the expanded key:\n%s", seeded)
+	}
+	if strings.Contains(seeded, "database") {
+		t.Fatalf("seeded document includes non-shared sections:\n%s", seeded)
+	}
+	if got := mgr.Get().Providers[0].APIKey; got != "sk-from-env" {
+		t.Fatalf("applied api_key = %q", got)
+	}
+
+	// A rotated environment variable applies on the next reload.
+	t.Setenv("LLMUX_TEST_SEED_KEY", "sk-rotated")
+	if err := mgr.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := mgr.Get().Providers[0].APIKey; got != "sk-rotated" {
+		t.Fatalf("api_key after rotation = %q", got)
+	}
+}
+
+func TestValidate_ConfigSource(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ConfigSource.Source = ConfigSourceDB
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected config.source db without a database to fail")
+	}
+	cfg.Database = DatabaseConfig{Enabled: true, Host: "localhost", Port: 5432, User: "llmux", Database: "llmux", SSLMode: "disable"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("config.source db without file providers: %v", err)
+	}
+	cfg.ConfigSource.Source = "etcd"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected unknown config.source to fail")
+	}
+}
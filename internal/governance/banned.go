@@ -0,0 +1,227 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+// BannedContentMetadataKey is the team metadata field holding the team's
+// BannedContent policy.
+const BannedContentMetadataKey = "banned_content"
+
+// Stages at which banned content is evaluated.
+const (
+	StagePrompt     = "prompt"
+	StageCompletion = "completion"
+)
+
+// BannedContent lists keywords, regular expressions and topics that a team's
+// prompts and completions may not contain. Keywords match whole words,
+// case-insensitively; topics name entries of the built-in topic catalog.
+type BannedContent struct {
+	Keywords []string `json:"keywords,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+	Topics   []string `json:"topics,omitempty"`
+}
+
+// Violation describes the first banned item found in a text.
+type Violation struct {
+	Kind  string `json:"kind"` // "keyword", "pattern" or "topic"
+	Value string `json:"value"`
+}
+
+// bannedTopics maps topic names to the phrases that indicate them.
+var bannedTopics = map[string][]string{
+	"weapons":          {"firearm", "firearms", "gun", "guns", "rifle", "ammunition", "explosive", "explosives", "bomb", "grenade"},
+	"drugs":            {"cocaine", "heroin", "methamphetamine", "fentanyl", "narcotics", "illegal drugs", "lsd", "mdma"},
+	"gambling":         {"gambling", "casino", "sports betting", "sportsbook", "poker", "slot machine", "roulette"},
+	"politics":         {"election", "elections", "political party", "democrat", "democrats", "republican", "republicans", "campaign donation", "vote for"},
+	"medical_advice":   {"diagnose", "diagnosis", "prescription", "dosage", "medication", "symptoms"},
+	"legal_advice":     {"lawsuit", "sue", "legal advice", "litigation", "attorney", "plea deal"},
+	"financial_advice": {"stock tip", "stock tips", "investment advice", "financial advice", "which stocks", "buy shares"},
+	"self_harm":        {"suicide", "self-harm", "self harm", "kill myself", "end my life"},
+	"violence":         {"murder", "assault", "kill someone", "torture", "massacre"},
+}
+
+// BannedTopics returns the names of the built-in topics.
+func BannedTopics() []string {
+	names := make([]string, 0, len(bannedTopics))
+	for name := range bannedTopics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// bannedRegexps caches compiled keyword, topic and pattern expressions,
+// keyed by their source. Policies change rarely, so the set stays small.
+var bannedRegexps sync.Map
+
+func compileBanned(expr string) (*regexp.Regexp, error) {
+	if re, ok := bannedRegexps.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	bannedRegexps.Store(expr, re)
+	return re, nil
+}
+
+func keywordExpr(words ...string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = regexp.QuoteMeta(strings.TrimSpace(w))
+	}
+	return `(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`
+}
+
+// Empty reports whether the policy bans nothing.
+func (b BannedContent) Empty() bool {
+	return len(b.Keywords) == 0 && len(b.Patterns) == 0 && len(b.Topics) == 0
+}
+
+// Validate checks that keywords are non-empty, patterns compile and topics
+// are known.
+func (b BannedContent) Validate() error {
+	for _, k := range b.Keywords {
+		if strings.TrimSpace(k) == "" {
+			return fmt.Errorf("keywords cannot be empty")
+		}
+	}
+	for _, p := range b.Patterns {
+		if _, err := compileBanned(p); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	for _, t := range b.Topics {
+		if _, ok := bannedTopics[t]; !ok {
+			return fmt.Errorf("unknown topic %q (known topics: %s)", t, strings.Join(BannedTopics(), ", "))
+		}
+	}
+	return nil
+}
+
+// Match returns the first banned keyword, pattern or topic found in text.
+func (b BannedContent) Match(text string) *Violation {
+	if text == "" {
+		return nil
+	}
+	for _, k := range b.Keywords {
+		if re, err := compileBanned(keywordExpr(k)); err == nil && re.MatchString(text) {
+			return &Violation{Kind: "keyword", Value: k}
+		}
+	}
+	for _, p := range b.Patterns {
+		if re, err := compileBanned(p); err == nil && re.MatchString(text) {
+			return &Violation{Kind: "pattern", Value: p}
+		}
+	}
+	for _, t := range b.Topics {
+		phrases, ok := bannedTopics[t]
+		if !ok {
+			continue
+		}
+		if re, err := compileBanned(keywordExpr(phrases...)); err == nil && re.MatchString(text) {
+			return &Violation{Kind: "topic", Value: t}
+		}
+	}
+	return nil
+}
+
+// BannedContentFromMetadata reads a team's policy from its metadata.
+func BannedContentFromMetadata(m auth.Metadata) (BannedContent, bool) {
+	raw, ok := m[BannedContentMetadataKey]
+	if !ok || raw == nil {
+		return BannedContent{}, false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return BannedContent{}, false
+	}
+	var policy BannedContent
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return BannedContent{}, false
+	}
+	return policy, !policy.Empty()
+}
+
+// EvaluateCompletion checks completion text against the caller's team
+// banned content policy.
+func (e *Engine) EvaluateCompletion(ctx context.Context, model, content string) error {
+	if e == nil {
+		return nil
+	}
+	cfg := e.loadConfig()
+	if !cfg.Enabled || strings.TrimSpace(content) == "" {
+		return nil
+	}
+	authCtx := auth.GetAuthContext(ctx)
+	team, err := e.resolveTeam(ctx, authCtx)
+	if err != nil {
+		e.logger.Warn("failed to resolve team for banned content", "error", err)
+		return nil
+	}
+	return e.checkBannedContent(StageCompletion, model, content, authCtx, team)
+}
+
+func (e *Engine) resolveTeam(ctx context.Context, authCtx *auth.AuthContext) (*auth.Team, error) {
+	if authCtx == nil {
+		return nil, nil
+	}
+	if authCtx.Team != nil {
+		return authCtx.Team, nil
+	}
+	if e.store == nil || authCtx.APIKey == nil || authCtx.APIKey.TeamID == nil {
+		return nil, nil
+	}
+	return e.store.GetTeam(ctx, *authCtx.APIKey.TeamID)
+}
+
+// checkBannedContent rejects text that violates the team's banned content
+// policy and records the violation in the audit log.
+func (e *Engine) checkBannedContent(stage, model, content string, authCtx *auth.AuthContext, team *auth.Team) error {
+	if team == nil || strings.TrimSpace(content) == "" {
+		return nil
+	}
+	policy, ok := BannedContentFromMetadata(team.Metadata)
+	if !ok {
+		return nil
+	}
+	violation := policy.Match(content)
+	if violation == nil {
+		return nil
+	}
+
+	e.auditBannedContent(authCtx, team.ID, model, stage, violation)
+	return llmerrors.NewContentPolicyError("gateway", model,
+		fmt.Sprintf("%s violates team content policy: banned %s %q", stage, violation.Kind, violation.Value))
+}
+
+func (e *Engine) auditBannedContent(authCtx *auth.AuthContext, teamID, model, stage string, violation *Violation) {
+	cfg := e.loadConfig()
+	if !cfg.AuditEnabled || e.auditLogger == nil {
+		return
+	}
+
+	actorID, actorType := auditActor(authCtx)
+	before := map[string]any{
+		"model": model,
+		"stage": stage,
+		"kind":  violation.Kind,
+		"value": violation.Value,
+	}
+	if err := e.auditLogger.LogAction(actorID, actorType, auth.AuditActionContentPolicyViolation, auth.AuditObjectTeam, teamID, false, before, nil); err != nil {
+		e.logger.Warn("failed to log content policy audit event", "error", err)
+	}
+}
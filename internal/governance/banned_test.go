@@ -0,0 +1,98 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+func TestBannedContentMatch(t *testing.T) {
+	policy := BannedContent{
+		Keywords: []string{"Project Falcon"},
+		Patterns: []string{`(?i)acct-\d{6}`},
+		Topics:   []string{"gambling"},
+	}
+	tests := []struct {
+		text string
+		want *Violation
+	}{
+		{"status of project falcon?", &Violation{Kind: "keyword", Value: "Project Falcon"}},
+		{"look up ACCT-123456", &Violation{Kind: "pattern", Value: `(?i)acct-\d{6}`}},
+		{"best online casino bonuses", &Violation{Kind: "topic", Value: "gambling"}},
+		{"falconry tips", nil},
+		{"pokerface lyrics", nil},
+	}
+	for _, tt := range tests {
+		got := policy.Match(tt.text)
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("Match(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestBannedContentValidate(t *testing.T) {
+	if err := (BannedContent{Patterns: []string{"("}}).Validate(); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+	if err := (BannedContent{Topics: []string{"astrology"}}).Validate(); err == nil {
+		t.Error("expected error for unknown topic")
+	}
+	if err := (BannedContent{Keywords: []string{" "}}).Validate(); err == nil {
+		t.Error("expected error for empty keyword")
+	}
+	if err := (BannedContent{Keywords: []string{"x"}, Topics: BannedTopics()}).Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestEngine_BannedContentPromptAndCompletion(t *testing.T) {
+	auditStore := auth.NewMemoryAuditLogStore()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := NewEngine(Config{Enabled: true, AuditEnabled: true},
+		WithLogger(logger),
+		WithAuditLogger(auth.NewAuditLogger(auditStore, true)),
+	)
+
+	team := &auth.Team{ID: "team-1", IsActive: true, Metadata: auth.Metadata{
+		// Stored metadata round-trips through JSON, so lists arrive as []any.
+		BannedContentMetadataKey: map[string]any{"keywords": []any{"competitor"}},
+	}}
+	apiKey := &auth.APIKey{ID: "key-1", TeamID: &team.ID, IsActive: true}
+	ctx := auth.WithAuthContext(context.Background(), &auth.AuthContext{APIKey: apiKey, Team: team})
+
+	err := engine.Evaluate(ctx, RequestInput{Model: "gpt-4", Content: "compare us with Competitor pricing"})
+	var llmErr *llmerrors.LLMError
+	if !errors.As(err, &llmErr) || llmErr.Type != llmerrors.TypeContentPolicy {
+		t.Fatalf("Evaluate = %v, want content policy error", err)
+	}
+	if err := engine.Evaluate(ctx, RequestInput{Model: "gpt-4", Content: "hello"}); err != nil {
+		t.Fatalf("Evaluate clean prompt: %v", err)
+	}
+	if err := engine.EvaluateCompletion(ctx, "gpt-4", "our competitor is cheaper"); err == nil {
+		t.Fatal("expected completion violation")
+	}
+
+	action := auth.AuditActionContentPolicyViolation
+	logs, _, err := auditStore.ListAuditLogs(auth.AuditLogFilter{Action: &action})
+	if err != nil {
+		t.Fatalf("ListAuditLogs: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("audit logs = %d, want 2", len(logs))
+	}
+	stages := map[any]bool{}
+	for _, l := range logs {
+		if l.ObjectID != team.ID || l.Success {
+			t.Fatalf("unexpected audit log %+v", l)
+		}
+		stages[l.BeforeValue["stage"]] = true
+	}
+	if !stages[StagePrompt] || !stages[StageCompletion] {
+		t.Fatalf("audited stages = %v", stages)
+	}
+}
@@ -0,0 +1,204 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+// Budget alert thresholds that are not a share of max budget.
+const budgetThresholdSoft = "soft_budget"
+
+// BudgetAlert reports a key or team whose spend reached an alert threshold.
+type BudgetAlert struct {
+	EntityType  string    `json:"entity_type"` // "api_key" or "team"
+	EntityID    string    `json:"entity_id"`
+	EntityAlias string    `json:"entity_alias,omitempty"`
+	Threshold   string    `json:"threshold"` // "soft_budget" or a percentage of max budget such as "80%"
+	Spend       float64   `json:"spend"`
+	Limit       float64   `json:"limit"` // Spend at which the threshold is reached
+	MaxBudget   float64   `json:"max_budget,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Message summarizes the alert for chat and email notifications.
+func (a BudgetAlert) Message() string {
+	name := a.EntityID
+	if a.EntityAlias != "" {
+		name = fmt.Sprintf("%s (%s)", a.EntityAlias, a.EntityID)
+	}
+	if a.Threshold == budgetThresholdSoft {
+		return fmt.Sprintf("Budget alert: %s %s reached its soft budget: $%.2f spent of $%.2f", a.EntityType, name, a.Spend, a.Limit)
+	}
+	return fmt.Sprintf("Budget alert: %s %s reached %s of its max budget: $%.2f spent of $%.2f", a.EntityType, name, a.Threshold, a.Spend, a.MaxBudget)
+}
+
+// BudgetNotifier delivers budget alerts. Implementations must be safe for
+// concurrent use.
+type BudgetNotifier interface {
+	Name() string
+	Notify(ctx context.Context, alert BudgetAlert) error
+}
+
+// BudgetAlertConfig controls when budget alerts fire.
+type BudgetAlertConfig struct {
+	// Thresholds are shares of max budget in (0, 1] that trigger an alert.
+	// Keys with a soft budget also alert when it is reached.
+	Thresholds []float64
+	// DedupeWindow suppresses repeats of the same alert (default 24h).
+	DedupeWindow time.Duration
+	// Timeout bounds delivery to each notifier (default 10s).
+	Timeout time.Duration
+}
+
+// BudgetAlerter fires budget alerts, deduplicating them per entity and
+// threshold across the dedupe window.
+type BudgetAlerter struct {
+	cfg       BudgetAlertConfig
+	dedupe    IdempotencyStore
+	notifiers []BudgetNotifier
+	logger    *slog.Logger
+	now       func() time.Time
+	// deliver sends an alert; replaced in tests to run synchronously.
+	deliver func(BudgetAlert)
+}
+
+// NewBudgetAlerter creates an alerter. A nil dedupe store uses an in-memory
+// store, which only deduplicates alerts within one instance.
+func NewBudgetAlerter(cfg BudgetAlertConfig, dedupe IdempotencyStore, logger *slog.Logger, notifiers ...BudgetNotifier) *BudgetAlerter {
+	if cfg.DedupeWindow <= 0 {
+		cfg.DedupeWindow = 24 * time.Hour
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	thresholds := append([]float64(nil), cfg.Thresholds...)
+	sort.Float64s(thresholds)
+	cfg.Thresholds = thresholds
+	if dedupe == nil {
+		dedupe = NewMemoryIdempotencyStore()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	a := &BudgetAlerter{cfg: cfg, dedupe: dedupe, notifiers: notifiers, logger: logger, now: time.Now}
+	a.deliver = func(alert BudgetAlert) { go a.send(alert) }
+	return a
+}
+
+// CheckKey alerts when a key's spend reached its soft budget or a max
+// budget threshold.
+func (a *BudgetAlerter) CheckKey(ctx context.Context, key *auth.APIKey, spend float64) {
+	if a == nil || key == nil {
+		return
+	}
+	alias := ""
+	if key.KeyAlias != nil {
+		alias = *key.KeyAlias
+	}
+	soft := 0.0
+	if key.SoftBudget != nil {
+		soft = *key.SoftBudget
+	}
+	a.check(ctx, "api_key", key.ID, alias, spend, soft, key.MaxBudget, key.BudgetResetAt)
+}
+
+// CheckTeam alerts when a team's spend reached a max budget threshold.
+func (a *BudgetAlerter) CheckTeam(ctx context.Context, team *auth.Team, spend float64) {
+	if a == nil || team == nil {
+		return
+	}
+	alias := ""
+	if team.Alias != nil {
+		alias = *team.Alias
+	}
+	a.check(ctx, "team", team.ID, alias, spend, 0, team.MaxBudget, team.BudgetResetAt)
+}
+
+// check fires the highest max budget threshold reached and, independently,
+// the soft budget, each at most once per dedupe window.
+func (a *BudgetAlerter) check(ctx context.Context, entityType, id, alias string, spend, soft, maxBudget float64, resetAt *time.Time) {
+	base := BudgetAlert{
+		EntityType:  entityType,
+		EntityID:    id,
+		EntityAlias: alias,
+		Spend:       spend,
+		MaxBudget:   maxBudget,
+	}
+	if maxBudget > 0 {
+		for i := len(a.cfg.Thresholds) - 1; i >= 0; i-- {
+			t := a.cfg.Thresholds[i]
+			if spend >= maxBudget*t {
+				alert := base
+				alert.Threshold = strconv.FormatFloat(t*100, 'f', -1, 64) + "%"
+				alert.Limit = maxBudget * t
+				a.fire(ctx, alert, resetAt)
+				break
+			}
+		}
+	}
+	if soft > 0 && spend >= soft {
+		alert := base
+		alert.Threshold = budgetThresholdSoft
+		alert.Limit = soft
+		a.fire(ctx, alert, resetAt)
+	}
+}
+
+func (a *BudgetAlerter) fire(ctx context.Context, alert BudgetAlert, resetAt *time.Time) {
+	// Budget resets start a new period, so include the reset time in the key.
+	key := "budget_alert:" + alert.EntityType + ":" + alert.EntityID + ":" + alert.Threshold
+	if resetAt != nil {
+		key += ":" + strconv.FormatInt(resetAt.Unix(), 10)
+	}
+	first, err := a.dedupe.PutIfAbsent(ctx, key, a.cfg.DedupeWindow)
+	if err != nil {
+		a.logger.Warn("budget alert dedupe failed", "error", err, "entity_type", alert.EntityType, "entity_id", alert.EntityID)
+		return
+	}
+	if !first {
+		return
+	}
+	alert.Timestamp = a.now().UTC()
+	a.deliver(alert)
+}
+
+func (a *BudgetAlerter) send(alert BudgetAlert) {
+	for _, n := range a.notifiers {
+		ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Timeout)
+		if err := n.Notify(ctx, alert); err != nil {
+			a.logger.Warn("budget alert delivery failed", "notifier", n.Name(), "error", err,
+				"entity_type", alert.EntityType, "entity_id", alert.EntityID)
+		}
+		cancel()
+	}
+}
+
+// checkBudgetAlerts runs after spend has been recorded. Entities from the
+// auth context carry the spend observed at authentication, so cost is added
+// to it; a team loaded from the store already includes it.
+func (e *Engine) checkBudgetAlerts(ctx context.Context, authCtx *auth.AuthContext, cost float64) {
+	if e.budgetAlerter == nil || authCtx == nil {
+		return
+	}
+	if authCtx.APIKey != nil && !authCtx.APIKey.IsVirtual() {
+		e.budgetAlerter.CheckKey(ctx, authCtx.APIKey, authCtx.APIKey.SpentBudget+cost)
+	}
+	if authCtx.Team != nil {
+		e.budgetAlerter.CheckTeam(ctx, authCtx.Team, authCtx.Team.SpentBudget+cost)
+		return
+	}
+	team, err := e.resolveTeam(ctx, authCtx)
+	if err != nil {
+		e.logger.Warn("failed to resolve team for budget alerts", "error", err)
+		return
+	}
+	if team != nil {
+		e.budgetAlerter.CheckTeam(ctx, team, team.SpentBudget)
+	}
+}
@@ -0,0 +1,209 @@
+package governance
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+type recordingNotifier struct {
+	mu     sync.Mutex
+	alerts []BudgetAlert
+}
+
+func (n *recordingNotifier) Name() string { return "recording" }
+
+func (n *recordingNotifier) Notify(_ context.Context, alert BudgetAlert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+func (n *recordingNotifier) thresholds() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	out := make([]string, len(n.alerts))
+	for i, a := range n.alerts {
+		out[i] = a.Threshold
+	}
+	return out
+}
+
+func newTestAlerter(cfg BudgetAlertConfig, notifier BudgetNotifier) *BudgetAlerter {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	a := NewBudgetAlerter(cfg, nil, logger, notifier)
+	a.deliver = a.send
+	return a
+}
+
+func TestBudgetAlerter_ThresholdsAndDedupe(t *testing.T) {
+	notifier := &recordingNotifier{}
+	alerter := newTestAlerter(BudgetAlertConfig{Thresholds: []float64{1, 0.5, 0.8}}, notifier)
+	soft := 7.0
+	key := &auth.APIKey{ID: "key-1", MaxBudget: 10, SoftBudget: &soft}
+	ctx := context.Background()
+
+	alerter.CheckKey(ctx, key, 4)
+	alerter.CheckKey(ctx, key, 5)
+	alerter.CheckKey(ctx, key, 6)
+	alerter.CheckKey(ctx, key, 7.5)
+	alerter.CheckKey(ctx, key, 8.5)
+	alerter.CheckKey(ctx, key, 9)
+	alerter.CheckKey(ctx, key, 10)
+
+	got := strings.Join(notifier.thresholds(), ",")
+	if want := "50%,soft_budget,80%,100%"; got != want {
+		t.Fatalf("alerts = %s, want %s", got, want)
+	}
+
+	// A budget reset starts a new alert period.
+	resetAt := time.Now().Add(24 * time.Hour)
+	key.BudgetResetAt = &resetAt
+	alerter.CheckKey(ctx, key, 5)
+	if n := len(notifier.thresholds()); n != 5 {
+		t.Fatalf("alerts after reset = %d, want 5", n)
+	}
+}
+
+func TestBudgetAlerter_TeamWithoutMaxBudget(t *testing.T) {
+	notifier := &recordingNotifier{}
+	alerter := newTestAlerter(BudgetAlertConfig{Thresholds: []float64{0.8}}, notifier)
+
+	alerter.CheckTeam(context.Background(), &auth.Team{ID: "team-1"}, 100)
+	if n := len(notifier.thresholds()); n != 0 {
+		t.Fatalf("alerts = %d, want 0", n)
+	}
+}
+
+func TestEngineAccount_BudgetAlerts(t *testing.T) {
+	store := auth.NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	notifier := &recordingNotifier{}
+	alerter := newTestAlerter(BudgetAlertConfig{Thresholds: []float64{0.8}}, notifier)
+	engine := NewEngine(Config{Enabled: true}, WithStore(store), WithLogger(logger), WithBudgetAlerter(alerter))
+
+	alias := "search"
+	team := &auth.Team{ID: "team-1", Alias: &alias, MaxBudget: 10, SpentBudget: 7, IsActive: true}
+	apiKey := &auth.APIKey{ID: "key-1", TeamID: &team.ID, MaxBudget: 100, SpentBudget: 7, IsActive: true}
+	if err := store.CreateTeam(context.Background(), team); err != nil {
+		t.Fatalf("CreateTeam() error = %v", err)
+	}
+	if err := store.CreateAPIKey(context.Background(), apiKey); err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+
+	ctx := auth.WithAuthContext(context.Background(), &auth.AuthContext{APIKey: apiKey, Team: team})
+	engine.Account(ctx, AccountInput{
+		RequestID: "req-1",
+		Model:     "gpt-4",
+		Usage:     Usage{TotalTokens: 10, Cost: 1.5},
+		Start:     time.Now(),
+		Latency:   time.Second,
+	})
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	if len(notifier.alerts) != 1 {
+		t.Fatalf("alerts = %+v, want one team alert", notifier.alerts)
+	}
+	alert := notifier.alerts[0]
+	if alert.EntityType != "team" || alert.EntityID != "team-1" || alert.Threshold != "80%" || alert.Spend != 8.5 {
+		t.Fatalf("alert = %+v", alert)
+	}
+	if !strings.Contains(alert.Message(), "search (team-1)") {
+		t.Fatalf("message = %q", alert.Message())
+	}
+}
+
+func TestWebhookBudgetNotifier(t *testing.T) {
+	var (
+		payload map[string]any
+		header  string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Token")
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier, err := NewWebhookBudgetNotifier(WebhookBudgetNotifierConfig{URL: server.URL, Headers: map[string]string{"X-Token": "secret"}})
+	if err != nil {
+		t.Fatalf("NewWebhookBudgetNotifier() error = %v", err)
+	}
+	alert := BudgetAlert{EntityType: "api_key", EntityID: "key-1", Threshold: "100%", Spend: 10, Limit: 10, MaxBudget: 10, Timestamp: time.Now()}
+	if err := notifier.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if header != "secret" {
+		t.Fatalf("X-Token = %q", header)
+	}
+	if text, _ := payload["text"].(string); text != alert.Message() {
+		t.Fatalf("text = %q, want %q", text, alert.Message())
+	}
+	structured, _ := payload["budget_alert"].(map[string]any)
+	if structured["entity_id"] != "key-1" {
+		t.Fatalf("budget_alert = %+v", structured)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	notifier, _ = NewWebhookBudgetNotifier(WebhookBudgetNotifierConfig{URL: failing.URL})
+	if err := notifier.Notify(context.Background(), alert); err == nil {
+		t.Fatal("expected error for non-2xx response")
+	}
+}
+
+func TestEmailBudgetNotifier(t *testing.T) {
+	notifier, err := NewEmailBudgetNotifier(EmailBudgetNotifierConfig{
+		Host:     "smtp.example.com",
+		Username: "alerts",
+		Password: "secret",
+		From:     "llmux@example.com",
+		To:       []string{"finops@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("NewEmailBudgetNotifier() error = %v", err)
+	}
+
+	var (
+		gotAddr string
+		gotTo   []string
+		gotMsg  string
+	)
+	notifier.sendMail = func(addr string, a smtp.Auth, _ string, to []string, msg []byte) error {
+		if a == nil {
+			t.Error("expected smtp auth")
+		}
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+	alert := BudgetAlert{EntityType: "team", EntityID: "team-1", Threshold: budgetThresholdSoft, Spend: 5, Limit: 5, Timestamp: time.Now()}
+	if err := notifier.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" || len(gotTo) != 1 {
+		t.Fatalf("addr = %q, to = %v", gotAddr, gotTo)
+	}
+	if !strings.Contains(gotMsg, "Subject: [LLMux] Budget alert for team team-1") || !strings.Contains(gotMsg, "soft budget") {
+		t.Fatalf("message = %q", gotMsg)
+	}
+
+	if _, err := NewEmailBudgetNotifier(EmailBudgetNotifierConfig{Host: "smtp.example.com"}); err == nil {
+		t.Fatal("expected error without recipients")
+	}
+}
@@ -0,0 +1,181 @@
+package governance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// WebhookBudgetNotifierConfig configures a budget alert webhook.
+type WebhookBudgetNotifierConfig struct {
+	URL     string
+	Headers map[string]string
+	Timeout time.Duration // Default 10s
+}
+
+// WebhookBudgetNotifier POSTs alerts as Slack-compatible messages. The
+// structured alert is included under "budget_alert" for other consumers.
+type WebhookBudgetNotifier struct {
+	cfg    WebhookBudgetNotifierConfig
+	client *http.Client
+}
+
+// NewWebhookBudgetNotifier creates a webhook notifier.
+func NewWebhookBudgetNotifier(cfg WebhookBudgetNotifierConfig) (*WebhookBudgetNotifier, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("budget alerts: webhook url is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &WebhookBudgetNotifier{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+// Name returns the notifier name.
+func (n *WebhookBudgetNotifier) Name() string { return "webhook" }
+
+type budgetWebhookField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+type budgetWebhookAttachment struct {
+	Color     string               `json:"color"`
+	Fields    []budgetWebhookField `json:"fields"`
+	Timestamp int64                `json:"ts"`
+}
+
+type budgetWebhookPayload struct {
+	Text        string                    `json:"text"`
+	Attachments []budgetWebhookAttachment `json:"attachments"`
+	BudgetAlert BudgetAlert               `json:"budget_alert"`
+}
+
+// Notify implements BudgetNotifier.
+func (n *WebhookBudgetNotifier) Notify(ctx context.Context, alert BudgetAlert) error {
+	color := "warning"
+	if alert.MaxBudget > 0 && alert.Spend >= alert.MaxBudget {
+		color = "danger"
+	}
+	fields := []budgetWebhookField{
+		{Title: "Entity", Value: alert.EntityType + " " + alert.EntityID, Short: true},
+		{Title: "Threshold", Value: alert.Threshold, Short: true},
+		{Title: "Spend", Value: fmt.Sprintf("$%.4f", alert.Spend), Short: true},
+		{Title: "Limit", Value: fmt.Sprintf("$%.4f", alert.Limit), Short: true},
+	}
+	if alert.EntityAlias != "" {
+		fields = append(fields, budgetWebhookField{Title: "Alias", Value: alert.EntityAlias, Short: true})
+	}
+	body, err := json.Marshal(budgetWebhookPayload{
+		Text:        alert.Message(),
+		Attachments: []budgetWebhookAttachment{{Color: color, Fields: fields, Timestamp: alert.Timestamp.Unix()}},
+		BudgetAlert: alert,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("budget alert webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("budget alert webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// EmailBudgetNotifierConfig configures SMTP delivery of budget alerts.
+type EmailBudgetNotifierConfig struct {
+	Host     string
+	Port     int // Default 587
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// EmailBudgetNotifier sends alerts by SMTP, using STARTTLS when the server
+// offers it and PLAIN auth when a username is set.
+type EmailBudgetNotifier struct {
+	cfg      EmailBudgetNotifierConfig
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailBudgetNotifier creates an email notifier.
+func NewEmailBudgetNotifier(cfg EmailBudgetNotifierConfig) (*EmailBudgetNotifier, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("budget alerts: smtp host is required")
+	}
+	if cfg.From == "" || len(cfg.To) == 0 {
+		return nil, fmt.Errorf("budget alerts: email from and to are required")
+	}
+	if cfg.Port <= 0 {
+		cfg.Port = 587
+	}
+	return &EmailBudgetNotifier{cfg: cfg, sendMail: smtp.SendMail}, nil
+}
+
+// Name returns the notifier name.
+func (n *EmailBudgetNotifier) Name() string { return "email" }
+
+// Notify implements BudgetNotifier. The context only bounds the wait; an
+// SMTP exchange already in progress is not interrupted.
+func (n *EmailBudgetNotifier) Notify(ctx context.Context, alert BudgetAlert) error {
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+	addr := net.JoinHostPort(n.cfg.Host, strconv.Itoa(n.cfg.Port))
+	msg := n.message(alert)
+
+	done := make(chan error, 1)
+	go func() { done <- n.sendMail(addr, auth, n.cfg.From, n.cfg.To, msg) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("budget alert email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("budget alert email: %w", ctx.Err())
+	}
+}
+
+func (n *EmailBudgetNotifier) message(alert BudgetAlert) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(n.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: [LLMux] Budget alert for %s %s\r\n", alert.EntityType, alert.EntityID)
+	fmt.Fprintf(&b, "Date: %s\r\n", alert.Timestamp.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(alert.Message() + "\r\n\r\n")
+	fmt.Fprintf(&b, "Threshold: %s\r\n", alert.Threshold)
+	fmt.Fprintf(&b, "Spend: $%.4f\r\n", alert.Spend)
+	fmt.Fprintf(&b, "Limit: $%.4f\r\n", alert.Limit)
+	if alert.MaxBudget > 0 {
+		fmt.Fprintf(&b, "Max budget: $%.4f\r\n", alert.MaxBudget)
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,219 @@
+package governance
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/metrics"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// Content categories detected by ClassifyContent.
+const (
+	ContentCategoryGeneral = "general"
+	ContentCategoryCode    = "code_generation"
+	ContentCategoryPII     = "pii"
+)
+
+// Content policy outcomes reported in metrics.
+const (
+	contentOutcomeAllowed   = "allowed"
+	contentOutcomeThrottled = "throttled"
+	contentOutcomeDenied    = "denied"
+)
+
+// ContentPolicy applies stricter limits to requests whose content falls into a
+// category. A policy with an empty TeamID is the default for all teams; a
+// team-specific policy for the same category replaces it.
+type ContentPolicy struct {
+	TeamID        string
+	Category      string
+	RPMLimit      int
+	AllowedModels []string
+	Tags          []string
+}
+
+// IsContentCategory reports whether category is a known content category.
+func IsContentCategory(category string) bool {
+	switch category {
+	case ContentCategoryGeneral, ContentCategoryCode, ContentCategoryPII:
+		return true
+	default:
+		return false
+	}
+}
+
+// allowsModel reports whether the policy permits model. Like key and team
+// model checks, a "provider/model" name also matches its bare model.
+func (p ContentPolicy) allowsModel(model string) bool {
+	if len(p.AllowedModels) == 0 {
+		return true
+	}
+	_, canonicalModel := types.SplitProviderModel(model)
+	for _, m := range p.AllowedModels {
+		if m == model || m == canonicalModel || m == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	codePattern = regexp.MustCompile("(?i)```|" +
+		`\b(def|func|fn)\s+\w+\s*\(|\bfunction\s*\w*\s*\(|#include\s*<|\bpublic\s+static\s+|` +
+		`\bconsole\.log\(|\bimport\s+[\w.]+|\bpackage\s+main\b|` +
+		`\b(write|implement|generate|fix|refactor|debug)\s+(a|an|the|this|some|my)?\s*` +
+		`(function|method|class|script|program|code|query|regex|unit tests?)\b`)
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	ssnPattern        = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+	phonePattern      = regexp.MustCompile(`(?:\+\d{1,3}[\s.\-]?)?\(?\b\d{3}\)?[\s.\-]\d{3}[\s.\-]\d{4}\b`)
+	cardNumberPattern = regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`)
+)
+
+// ClassifyContent returns the content categories detected in text. Detection
+// is heuristic; text matching no specific category is "general".
+func ClassifyContent(text string) []string {
+	var categories []string
+	if codePattern.MatchString(text) {
+		categories = append(categories, ContentCategoryCode)
+	}
+	if containsPII(text) {
+		categories = append(categories, ContentCategoryPII)
+	}
+	if len(categories) == 0 {
+		categories = append(categories, ContentCategoryGeneral)
+	}
+	return categories
+}
+
+func containsPII(text string) bool {
+	if emailPattern.MatchString(text) || ssnPattern.MatchString(text) || phonePattern.MatchString(text) {
+		return true
+	}
+	for _, candidate := range cardNumberPattern.FindAllString(text, -1) {
+		if luhnValid(candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func luhnValid(number string) bool {
+	sum := 0
+	digits := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+		double = !double
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+// contentPolicyFor returns the policy for category, preferring a
+// team-specific policy over the default.
+func contentPolicyFor(policies []ContentPolicy, teamID, category string) (ContentPolicy, bool) {
+	var fallback *ContentPolicy
+	for i := range policies {
+		p := &policies[i]
+		if p.Category != category {
+			continue
+		}
+		if p.TeamID == "" {
+			if fallback == nil {
+				fallback = p
+			}
+			continue
+		}
+		if teamID != "" && p.TeamID == teamID {
+			return *p, true
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return ContentPolicy{}, false
+}
+
+func (e *Engine) checkContentPolicies(ctx context.Context, cfg Config, input RequestInput, authCtx *auth.AuthContext, resolved resolvedEntities) (Decision, error) {
+	var decision Decision
+	if len(cfg.ContentPolicies) == 0 || strings.TrimSpace(input.Content) == "" {
+		return decision, nil
+	}
+
+	teamID := teamIDFromAuth(authCtx)
+	if resolved.team != nil {
+		teamID = resolved.team.ID
+	}
+
+	decision.ContentCategories = ClassifyContent(input.Content)
+	for _, category := range decision.ContentCategories {
+		policy, ok := contentPolicyFor(cfg.ContentPolicies, teamID, category)
+		if !ok {
+			metrics.RecordContentCategory(category, contentOutcomeAllowed)
+			continue
+		}
+
+		if !policy.allowsModel(input.Model) {
+			metrics.RecordContentCategory(category, contentOutcomeDenied)
+			return decision, llmerrors.NewPermissionError("gateway", input.Model, "model not allowed for "+category+" content")
+		}
+
+		if policy.RPMLimit > 0 && e.rateLimiter != nil {
+			if scope := e.contentRateLimitScope(input, authCtx, teamID); scope != "" {
+				key := "content:" + category + ":" + scope
+				allowed, _ := e.rateLimiter.Check(ctx, key, policy.RPMLimit, e.rateLimiter.BurstForRate(policy.RPMLimit, 1))
+				if !allowed {
+					metrics.RecordContentCategory(category, contentOutcomeThrottled)
+					return decision, llmerrors.NewRateLimitError("gateway", input.Model, category+" content rate limit exceeded")
+				}
+			}
+		}
+
+		metrics.RecordContentCategory(category, contentOutcomeAllowed)
+		decision.RouteTags = appendUnique(decision.RouteTags, policy.Tags...)
+	}
+	return decision, nil
+}
+
+// contentRateLimitScope buckets content limits per team, falling back to the
+// API key, user or anonymous client.
+func (e *Engine) contentRateLimitScope(input RequestInput, authCtx *auth.AuthContext, teamID string) string {
+	if teamID != "" {
+		return "team:" + teamID
+	}
+	if authCtx != nil && authCtx.APIKey != nil {
+		return "key:" + authCtx.APIKey.ID
+	}
+	if userID := userIDFromAuth(authCtx); userID != "" {
+		return "user:" + userID
+	}
+	if input.Request != nil {
+		return e.rateLimiter.AnonymousKey(input.Request)
+	}
+	return ""
+}
+
+func appendUnique(dst []string, values ...string) []string {
+	for _, v := range values {
+		if v != "" && !slices.Contains(dst, v) {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
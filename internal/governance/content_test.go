@@ -0,0 +1,109 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+func TestClassifyContent(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "general", text: "What is the capital of France?", want: []string{ContentCategoryGeneral}},
+		{name: "code fence", text: "Why does this fail?\n```go\nx := 1\n```", want: []string{ContentCategoryCode}},
+		{name: "code request", text: "Write a function that reverses a string", want: []string{ContentCategoryCode}},
+		{name: "email", text: "Contact me at jane.doe@example.com", want: []string{ContentCategoryPII}},
+		{name: "ssn", text: "My SSN is 123-45-6789", want: []string{ContentCategoryPII}},
+		{name: "card number", text: "Card 4111 1111 1111 1111 please", want: []string{ContentCategoryPII}},
+		{name: "non-luhn digits", text: "Order 1234 5678 9012 3456 shipped", want: []string{ContentCategoryGeneral}},
+		{name: "code with pii", text: "def send(): mail('bob@example.com')", want: []string{ContentCategoryCode, ContentCategoryPII}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyContent(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ClassifyContent(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngineEvaluateRequest_ContentPolicies(t *testing.T) {
+	limiter := auth.NewTenantRateLimiter(&auth.TenantRateLimiterConfig{
+		DefaultRPM:   600,
+		DefaultBurst: 100,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	engine := NewEngine(Config{
+		Enabled: true,
+		ContentPolicies: []ContentPolicy{
+			{Category: ContentCategoryCode, RPMLimit: 1},
+			{TeamID: "team-research", Category: ContentCategoryCode, RPMLimit: 600},
+			{Category: ContentCategoryPII, AllowedModels: []string{"gpt-4o"}, Tags: []string{"pii-safe"}},
+		},
+	}, WithRateLimiter(limiter))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	keyRPM := int64(6000)
+	teamID := "team-a"
+	ctx := auth.WithAuthContext(context.Background(), &auth.AuthContext{
+		APIKey: &auth.APIKey{ID: "key-1", TeamID: &teamID, RPMLimit: &keyRPM, IsActive: true},
+	})
+	code := RequestInput{Request: req, Model: "gpt-4o", Content: "Write a function to parse CSV"}
+
+	if _, err := engine.EvaluateRequest(ctx, code); err != nil {
+		t.Fatalf("first code request: %v", err)
+	}
+	_, err := engine.EvaluateRequest(ctx, code)
+	var llmErr *llmerrors.LLMError
+	if !errors.As(err, &llmErr) || llmErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected content rate limit error, got %v", err)
+	}
+
+	if _, err := engine.EvaluateRequest(ctx, RequestInput{Request: req, Model: "gpt-4o", Content: "Summarize this article"}); err != nil {
+		t.Fatalf("general request should not be throttled: %v", err)
+	}
+
+	researchID := "team-research"
+	researchCtx := auth.WithAuthContext(context.Background(), &auth.AuthContext{
+		APIKey: &auth.APIKey{ID: "key-2", TeamID: &researchID, RPMLimit: &keyRPM, IsActive: true},
+	})
+	for i := 0; i < 3; i++ {
+		if _, err := engine.EvaluateRequest(researchCtx, code); err != nil {
+			t.Fatalf("team override request %d: %v", i, err)
+		}
+	}
+
+	pii := RequestInput{Request: req, Model: "gpt-4o-mini", Content: "Email alice@example.com the invoice"}
+	_, err = engine.EvaluateRequest(ctx, pii)
+	if !errors.As(err, &llmErr) || llmErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected permission error for pii model, got %v", err)
+	}
+
+	pii.Model = "openai/gpt-4o"
+	if _, err := engine.EvaluateRequest(ctx, pii); err != nil {
+		t.Fatalf("pii request on provider-qualified allowed model: %v", err)
+	}
+
+	pii.Model = "gpt-4o"
+	decision, err := engine.EvaluateRequest(ctx, pii)
+	if err != nil {
+		t.Fatalf("pii request on allowed model: %v", err)
+	}
+	if !reflect.DeepEqual(decision.ContentCategories, []string{ContentCategoryPII}) {
+		t.Fatalf("unexpected categories: %v", decision.ContentCategories)
+	}
+	if !reflect.DeepEqual(decision.RouteTags, []string{"pii-safe"}) {
+		t.Fatalf("unexpected route tags: %v", decision.RouteTags)
+	}
+}
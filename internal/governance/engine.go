@@ -22,12 +22,18 @@ type Engine struct {
 	logger      *slog.Logger
 	config      atomic.Value
 	enforcer    *auth.CasbinEnforcer
+	// budgetAlerter notifies when spend reaches alert thresholds (optional).
+	budgetAlerter *BudgetAlerter
+	tagSpend      *tagSpendTracker
+	// responses keeps responses replayed for repeated Idempotency-Keys (optional).
+	responses ResponseStore
 }
 
 // NewEngine creates a governance engine with the provided config.
 func NewEngine(cfg Config, opts ...Option) *Engine {
 	engine := &Engine{
-		logger: slog.Default(),
+		logger:   slog.Default(),
+		tagSpend: newTagSpendTracker(),
 	}
 	engine.config.Store(cfg)
 	for _, opt := range opts {
@@ -49,33 +55,48 @@ func (e *Engine) UpdateConfig(cfg Config) {
 
 // Evaluate enforces governance checks before request execution.
 func (e *Engine) Evaluate(ctx context.Context, input RequestInput) error {
+	_, err := e.EvaluateRequest(ctx, input)
+	return err
+}
+
+// EvaluateRequest enforces governance checks before request execution and
+// returns the decision, including any routing tags required by content policies.
+func (e *Engine) EvaluateRequest(ctx context.Context, input RequestInput) (Decision, error) {
 	if e == nil {
-		return nil
+		return Decision{}, nil
 	}
 	cfg := e.loadConfig()
 	if !cfg.Enabled {
-		return nil
+		return Decision{}, nil
 	}
 
 	authCtx := auth.GetAuthContext(ctx)
 	resolved, err := e.resolveEntities(ctx, authCtx, input.EndUserID)
 	if err != nil {
-		return llmerrors.NewInternalError("gateway", input.Model, "failed to resolve auth context")
+		return Decision{}, llmerrors.NewInternalError("gateway", input.Model, "failed to resolve auth context")
 	}
 
 	if err := e.checkModelAccess(ctx, input.Model, authCtx); err != nil {
-		return err
+		return Decision{}, err
 	}
 
 	if err := e.checkBudgets(input.Model, authCtx, resolved); err != nil {
-		return err
+		return Decision{}, err
+	}
+
+	if err := e.checkTagBudgets(ctx, cfg, input.Model, input.Tags, authCtx); err != nil {
+		return Decision{}, err
 	}
 
 	if err := e.checkRateLimit(ctx, input, authCtx, resolved); err != nil {
-		return err
+		return Decision{}, err
 	}
 
-	return nil
+	if err := e.checkBannedContent(StagePrompt, input.Model, input.Content, authCtx, resolved.team); err != nil {
+		return Decision{}, err
+	}
+
+	return e.checkContentPolicies(ctx, cfg, input, authCtx, resolved)
 }
 
 // Account records usage and spend updates after request completion.
@@ -135,6 +156,7 @@ func (e *Engine) account(ctx context.Context, input AccountInput) {
 		EndTime:      endTime,
 		LatencyMs:    int(latency.Milliseconds()),
 		RequestTags:  append([]string(nil), input.RequestTags...),
+		Metadata:     auth.Metadata(input.Metadata),
 	}
 	if input.StatusCode != nil {
 		log.StatusCode = input.StatusCode
@@ -184,7 +206,7 @@ func (e *Engine) account(ctx context.Context, input AccountInput) {
 		return
 	}
 
-	if authCtx != nil && authCtx.APIKey != nil {
+	if authCtx != nil && authCtx.APIKey != nil && !authCtx.APIKey.IsVirtual() {
 		if err := e.store.UpdateAPIKeySpent(bgCtx, authCtx.APIKey.ID, input.Usage.Cost); err != nil {
 			e.logger.Warn("failed to update api key spend", "error", err, "key_id", authCtx.APIKey.ID)
 		}
@@ -236,6 +258,9 @@ func (e *Engine) account(ctx context.Context, input AccountInput) {
 			e.logger.Warn("failed to update end user spend", "error", err, "end_user_id", endUserID)
 		}
 	}
+
+	e.accountTagSpend(cfg, input.RequestTags, input.Usage.Cost)
+	e.checkBudgetAlerts(bgCtx, authCtx, input.Usage.Cost)
 }
 
 type resolvedEntities struct {
@@ -313,7 +338,12 @@ func (e *Engine) checkModelAccess(ctx context.Context, model string, authCtx *au
 
 		// Legacy support for allowed_models
 		if len(authCtx.APIKey.AllowedModels) > 0 {
-			for _, am := range authCtx.APIKey.AllowedModels {
+			allowedModels, err := auth.ExpandModelAccessGroups(ctx, e.store, authCtx.APIKey.AllowedModels)
+			if err != nil {
+				e.logger.Error("failed to expand model access groups", "error", err)
+				return llmerrors.NewInternalError("gateway", model, "failed to evaluate model access")
+			}
+			for _, am := range allowedModels {
 				_, _ = e.enforcer.AddPolicy(sub, auth.ModelObj(am), act)
 			}
 		} else {
@@ -86,6 +86,34 @@ func TestEngineEvaluate_BudgetExceeded(t *testing.T) {
 	}
 }
 
+func TestEngineEvaluate_EndUserBudgetExceeded(t *testing.T) {
+	store := auth.NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := NewEngine(Config{Enabled: true}, WithStore(store), WithLogger(logger))
+
+	maxBudget := 5.0
+	budget := &auth.Budget{ID: "budget-1", MaxBudget: &maxBudget}
+	if err := store.CreateBudget(context.Background(), budget); err != nil {
+		t.Fatalf("CreateBudget() error = %v", err)
+	}
+	if err := store.CreateEndUser(context.Background(), &auth.EndUser{UserID: "customer-1", Spend: 5, BudgetID: &budget.ID}); err != nil {
+		t.Fatalf("CreateEndUser() error = %v", err)
+	}
+
+	ctx := auth.WithAuthContext(context.Background(), &auth.AuthContext{APIKey: &auth.APIKey{ID: "key-1", IsActive: true}})
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+
+	err := engine.Evaluate(ctx, RequestInput{Request: req, Model: "gpt-4", EndUserID: "customer-1"})
+	var llmErr *llmerrors.LLMError
+	if !errors.As(err, &llmErr) || llmErr.Type != llmerrors.TypeInsufficientQuota {
+		t.Fatalf("expected quota error, got %v", err)
+	}
+
+	if err := engine.Evaluate(ctx, RequestInput{Request: req, Model: "gpt-4", EndUserID: "customer-2"}); err != nil {
+		t.Fatalf("unknown customer should not be limited, got %v", err)
+	}
+}
+
 func TestEngineEvaluate_RateLimitFailCloseDenies(t *testing.T) {
 	limiter := auth.NewTenantRateLimiter(&auth.TenantRateLimiterConfig{
 		DefaultRPM:   60,
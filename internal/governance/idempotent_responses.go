@@ -0,0 +1,217 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/redis/go-redis/v9"
+)
+
+// StoredResponse is a response kept for replay to requests that repeat an
+// Idempotency-Key.
+type StoredResponse struct {
+	// RequestHash fingerprints the request body, so a key reused with a
+	// different request can be rejected instead of replayed.
+	RequestHash string            `json:"request_hash"`
+	Status      int               `json:"status"`
+	Header      map[string]string `json:"header,omitempty"`
+	Body        []byte            `json:"body"`
+}
+
+// ResponseStore reserves Idempotency-Keys and keeps their responses.
+type ResponseStore interface {
+	// Reserve claims key for a new request. It returns the stored response
+	// when the key completed earlier, and reserved=false without a response
+	// while another request holding the key is in flight.
+	Reserve(ctx context.Context, key string, ttl time.Duration) (stored *StoredResponse, reserved bool, err error)
+	// Complete stores the response of the request holding key.
+	Complete(ctx context.Context, key string, resp *StoredResponse, ttl time.Duration) error
+	// Release frees key after a failed request so it can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// IdempotentRequest is a reserved Idempotency-Key.
+type IdempotentRequest struct {
+	engine *Engine
+	key    string
+	hash   string
+	ttl    time.Duration
+}
+
+// Errors returned by BeginIdempotentRequest.
+var (
+	ErrIdempotencyInProgress = errors.New("a request with this Idempotency-Key is in progress")
+	ErrIdempotencyKeyReused  = errors.New("Idempotency-Key was used with a different request")
+)
+
+// BeginIdempotentRequest reserves key for a request whose body hashes to
+// requestHash. It returns the stored response when key already completed
+// with the same request, and (nil, nil, nil) when idempotent responses are
+// disabled. Keys are held for the configured idempotency window.
+func (e *Engine) BeginIdempotentRequest(ctx context.Context, key, requestHash string) (*IdempotentRequest, *StoredResponse, error) {
+	if e == nil || e.responses == nil || key == "" {
+		return nil, nil, nil
+	}
+	cfg := e.loadConfig()
+	if !cfg.Enabled || cfg.IdempotencyWindow <= 0 {
+		return nil, nil, nil
+	}
+
+	stored, reserved, err := e.responses.Reserve(ctx, key, cfg.IdempotencyWindow)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stored != nil {
+		if stored.RequestHash != requestHash {
+			return nil, nil, ErrIdempotencyKeyReused
+		}
+		return nil, stored, nil
+	}
+	if !reserved {
+		return nil, nil, ErrIdempotencyInProgress
+	}
+	return &IdempotentRequest{engine: e, key: key, hash: requestHash, ttl: cfg.IdempotencyWindow}, nil, nil
+}
+
+// Complete stores resp for replay.
+func (r *IdempotentRequest) Complete(ctx context.Context, resp *StoredResponse) {
+	resp.RequestHash = r.hash
+	if err := r.engine.responses.Complete(ctx, r.key, resp, r.ttl); err != nil {
+		r.engine.logger.Warn("failed to store idempotent response", "error", err)
+	}
+}
+
+// Release frees the key without storing a response.
+func (r *IdempotentRequest) Release(ctx context.Context) {
+	if err := r.engine.responses.Release(ctx, r.key); err != nil {
+		r.engine.logger.Warn("failed to release idempotency key", "error", err)
+	}
+}
+
+type memoryResponseEntry struct {
+	resp      *StoredResponse // nil while in flight
+	expiresAt time.Time
+}
+
+// MemoryResponseStore keeps idempotent responses in memory.
+type MemoryResponseStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryResponseEntry
+	nextSweep time.Time
+}
+
+// NewMemoryResponseStore creates an in-memory response store.
+func NewMemoryResponseStore() *MemoryResponseStore {
+	return &MemoryResponseStore{entries: make(map[string]memoryResponseEntry)}
+}
+
+// Reserve implements ResponseStore.
+func (s *MemoryResponseStore) Reserve(_ context.Context, key string, ttl time.Duration) (*StoredResponse, bool, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweepLocked(now)
+
+	if entry, ok := s.entries[key]; ok && entry.expiresAt.After(now) {
+		return entry.resp, false, nil
+	}
+	s.entries[key] = memoryResponseEntry{expiresAt: now.Add(ttl)}
+	return nil, true, nil
+}
+
+// Complete implements ResponseStore.
+func (s *MemoryResponseStore) Complete(_ context.Context, key string, resp *StoredResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = memoryResponseEntry{resp: resp, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Release implements ResponseStore.
+func (s *MemoryResponseStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.entries[key]; ok && entry.resp == nil {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// sweepLocked drops expired entries at most once a minute.
+func (s *MemoryResponseStore) sweepLocked(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(time.Minute)
+	for key, entry := range s.entries {
+		if !entry.expiresAt.After(now) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// redisPendingResponse marks a key whose request is in flight.
+const redisPendingResponse = "pending"
+
+// RedisResponseStore shares idempotent responses across instances.
+type RedisResponseStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisResponseStore creates a Redis-backed response store.
+func NewRedisResponseStore(client redis.UniversalClient, prefix string) *RedisResponseStore {
+	return &RedisResponseStore{client: client, prefix: prefix}
+}
+
+// Reserve implements ResponseStore.
+func (s *RedisResponseStore) Reserve(ctx context.Context, key string, ttl time.Duration) (*StoredResponse, bool, error) {
+	ok, err := s.client.SetNX(ctx, s.prefix+key, redisPendingResponse, ttl).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if ok {
+		return nil, true, nil
+	}
+	data, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// Released or expired between the two calls; let the caller retry.
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if string(data) == redisPendingResponse {
+		return nil, false, nil
+	}
+	var resp StoredResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false, err
+	}
+	return &resp, false, nil
+}
+
+// Complete implements ResponseStore.
+func (s *RedisResponseStore) Complete(ctx context.Context, key string, resp *StoredResponse, ttl time.Duration) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.prefix+key, data, ttl).Err()
+}
+
+// releaseScript deletes a key only while it is still pending.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Release implements ResponseStore.
+func (s *RedisResponseStore) Release(ctx context.Context, key string) error {
+	return releaseScript.Run(ctx, s.client, []string{s.prefix + key}, redisPendingResponse).Err()
+}
@@ -0,0 +1,71 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestMemoryResponseStore(t *testing.T) {
+	store := NewMemoryResponseStore()
+	ctx := context.Background()
+
+	stored, reserved, err := store.Reserve(ctx, "k", time.Minute)
+	if err != nil || stored != nil || !reserved {
+		t.Fatalf("first Reserve() = %v, %v, %v", stored, reserved, err)
+	}
+	if stored, reserved, _ = store.Reserve(ctx, "k", time.Minute); stored != nil || reserved {
+		t.Fatalf("in-flight Reserve() = %v, %v", stored, reserved)
+	}
+
+	// A released key can be reserved again.
+	_ = store.Release(ctx, "k")
+	if _, reserved, _ = store.Reserve(ctx, "k", time.Minute); !reserved {
+		t.Fatal("expected released key to be reservable")
+	}
+
+	_ = store.Complete(ctx, "k", &StoredResponse{Status: 200, Body: []byte("ok")}, time.Minute)
+	_ = store.Release(ctx, "k")
+	stored, reserved, _ = store.Reserve(ctx, "k", time.Minute)
+	if reserved || stored == nil || string(stored.Body) != "ok" {
+		t.Fatalf("completed Reserve() = %v, %v", stored, reserved)
+	}
+
+	// Expired keys are reserved afresh.
+	_ = store.Complete(ctx, "expired", &StoredResponse{Status: 200}, -time.Second)
+	if stored, reserved, _ = store.Reserve(ctx, "expired", time.Minute); stored != nil || !reserved {
+		t.Fatalf("expired Reserve() = %v, %v", stored, reserved)
+	}
+}
+
+func TestEngineBeginIdempotentRequest(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := NewEngine(Config{Enabled: true, IdempotencyWindow: time.Minute},
+		WithLogger(logger), WithResponseStore(NewMemoryResponseStore()))
+	ctx := context.Background()
+
+	pending, stored, err := engine.BeginIdempotentRequest(ctx, "key-1", "hash-a")
+	if err != nil || stored != nil || pending == nil {
+		t.Fatalf("BeginIdempotentRequest() = %v, %v, %v", pending, stored, err)
+	}
+	if _, _, err := engine.BeginIdempotentRequest(ctx, "key-1", "hash-a"); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Fatalf("in-flight error = %v", err)
+	}
+
+	pending.Complete(ctx, &StoredResponse{Status: 200, Body: []byte(`{"id":"1"}`)})
+	_, stored, err = engine.BeginIdempotentRequest(ctx, "key-1", "hash-a")
+	if err != nil || stored == nil || string(stored.Body) != `{"id":"1"}` {
+		t.Fatalf("replay = %v, %v", stored, err)
+	}
+	if _, _, err := engine.BeginIdempotentRequest(ctx, "key-1", "hash-b"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("reused key error = %v", err)
+	}
+
+	disabled := NewEngine(Config{Enabled: true}, WithLogger(logger), WithResponseStore(NewMemoryResponseStore()))
+	if pending, stored, err := disabled.BeginIdempotentRequest(ctx, "key-1", "hash-a"); pending != nil || stored != nil || err != nil {
+		t.Fatalf("without window = %v, %v, %v", pending, stored, err)
+	}
+}
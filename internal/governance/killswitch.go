@@ -0,0 +1,234 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BlockScope selects which traffic an emergency block applies to.
+type BlockScope string
+
+const (
+	// BlockScopeGlobal blocks all traffic.
+	BlockScopeGlobal BlockScope = "global"
+	// BlockScopeModel blocks traffic to one model group.
+	BlockScopeModel BlockScope = "model"
+	// BlockScopeTeam blocks traffic from one team.
+	BlockScopeTeam BlockScope = "team"
+)
+
+// Block is an active emergency block.
+type Block struct {
+	Scope     BlockScope `json:"scope"`
+	Target    string     `json:"target,omitempty"`
+	Reason    string     `json:"reason"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Message describes the block for error responses.
+func (b Block) Message() string {
+	if b.Scope == BlockScopeGlobal {
+		return "traffic blocked by kill switch: " + b.Reason
+	}
+	return fmt.Sprintf("traffic blocked by kill switch (%s %s): %s", b.Scope, b.Target, b.Reason)
+}
+
+type blockKey struct {
+	scope  BlockScope
+	target string
+}
+
+// ErrInvalidBlock is returned by KillSwitch.Block for malformed blocks.
+var ErrInvalidBlock = errors.New("invalid block")
+
+// killSwitchResyncInterval bounds how long an instance that missed a change
+// notification keeps stale blocks.
+const killSwitchResyncInterval = 30 * time.Second
+
+// KillSwitchStore shares blocks between gateway instances.
+type KillSwitchStore interface {
+	// Save stores b, replacing the block of the same scope and target, and
+	// notifies subscribers.
+	Save(ctx context.Context, b Block) error
+	// Delete removes the block of scope and target, notifies subscribers
+	// and reports whether it existed.
+	Delete(ctx context.Context, scope BlockScope, target string) (bool, error)
+	// Load returns all stored blocks.
+	Load(ctx context.Context) ([]Block, error)
+	// Subscribe calls fn whenever an instance changes the blocks. It
+	// returns once subscribed and delivers notifications until ctx is done.
+	Subscribe(ctx context.Context, fn func()) error
+}
+
+// KillSwitch holds emergency traffic blocks that take effect immediately,
+// without a config reload. Blocks are checked in memory; with a store they
+// are shared by every instance, which reloads them on each change.
+type KillSwitch struct {
+	mu     sync.RWMutex
+	blocks map[blockKey]Block
+	store  KillSwitchStore
+	logger *slog.Logger
+}
+
+// NewKillSwitch creates a kill switch with no active blocks, held in memory
+// on this instance only.
+func NewKillSwitch() *KillSwitch {
+	return &KillSwitch{blocks: make(map[blockKey]Block)}
+}
+
+// NewSharedKillSwitch creates a kill switch whose blocks are kept in store.
+// It loads the stored blocks and follows changes until ctx is done.
+func NewSharedKillSwitch(ctx context.Context, store KillSwitchStore, logger *slog.Logger) (*KillSwitch, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	k := &KillSwitch{blocks: make(map[blockKey]Block), store: store, logger: logger}
+	if err := store.Subscribe(ctx, func() { k.reload(ctx) }); err != nil {
+		return nil, err
+	}
+	blocks, err := store.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load kill switch blocks: %w", err)
+	}
+	k.replace(blocks)
+	go k.resync(ctx)
+	return k, nil
+}
+
+// Block activates (or replaces) a block. The target must be empty for the
+// global scope and set for the model and team scopes.
+func (k *KillSwitch) Block(ctx context.Context, b Block) (Block, error) {
+	switch b.Scope {
+	case BlockScopeGlobal:
+		if b.Target != "" {
+			return Block{}, fmt.Errorf("%w: target must be empty for global scope", ErrInvalidBlock)
+		}
+	case BlockScopeModel, BlockScopeTeam:
+		if b.Target == "" {
+			return Block{}, fmt.Errorf("%w: target is required for %s scope", ErrInvalidBlock, b.Scope)
+		}
+	default:
+		return Block{}, fmt.Errorf("%w: unknown scope %q", ErrInvalidBlock, b.Scope)
+	}
+	if b.Reason == "" {
+		return Block{}, fmt.Errorf("%w: reason is required", ErrInvalidBlock)
+	}
+	if b.CreatedAt.IsZero() {
+		b.CreatedAt = time.Now()
+	}
+	if k.store != nil {
+		if err := k.store.Save(ctx, b); err != nil {
+			return Block{}, fmt.Errorf("save kill switch block: %w", err)
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.blocks[blockKey{b.Scope, b.Target}] = b
+	return b, nil
+}
+
+// Unblock removes a block and reports whether it was active.
+func (k *KillSwitch) Unblock(ctx context.Context, scope BlockScope, target string) (Block, bool, error) {
+	key := blockKey{scope, target}
+	k.mu.RLock()
+	b, ok := k.blocks[key]
+	k.mu.RUnlock()
+	if k.store != nil {
+		deleted, err := k.store.Delete(ctx, scope, target)
+		if err != nil {
+			return Block{}, false, fmt.Errorf("delete kill switch block: %w", err)
+		}
+		if deleted && !ok {
+			b, ok = Block{Scope: scope, Target: target}, true
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.blocks, key)
+	return b, ok, nil
+}
+
+// Check returns the block applying to a request for any of models from
+// teamID, checking the global block first.
+func (k *KillSwitch) Check(teamID string, models ...string) (Block, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if len(k.blocks) == 0 {
+		return Block{}, false
+	}
+	if b, ok := k.blocks[blockKey{BlockScopeGlobal, ""}]; ok {
+		return b, true
+	}
+	for _, model := range models {
+		if model == "" {
+			continue
+		}
+		if b, ok := k.blocks[blockKey{BlockScopeModel, model}]; ok {
+			return b, true
+		}
+	}
+	if teamID != "" {
+		if b, ok := k.blocks[blockKey{BlockScopeTeam, teamID}]; ok {
+			return b, true
+		}
+	}
+	return Block{}, false
+}
+
+// List returns the active blocks ordered by creation time.
+func (k *KillSwitch) List() []Block {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	out := make([]Block, 0, len(k.blocks))
+	for _, b := range k.blocks {
+		out = append(out, b)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// reload replaces the blocks with the stored ones. On failure the current
+// blocks are kept until the next change or resync.
+func (k *KillSwitch) reload(ctx context.Context) {
+	blocks, err := k.store.Load(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			k.logger.Warn("failed to reload kill switch blocks", "error", err)
+		}
+		return
+	}
+	k.replace(blocks)
+}
+
+func (k *KillSwitch) replace(blocks []Block) {
+	next := make(map[blockKey]Block, len(blocks))
+	for _, b := range blocks {
+		next[blockKey{b.Scope, b.Target}] = b
+	}
+	k.mu.Lock()
+	k.blocks = next
+	k.mu.Unlock()
+}
+
+// resync periodically reloads the blocks, since pub/sub notifications are
+// lost while an instance is disconnected.
+func (k *KillSwitch) resync(ctx context.Context) {
+	ticker := time.NewTicker(killSwitchResyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			k.reload(ctx)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goccy/go-json"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultKillSwitchKey is the Redis hash holding shared kill switch blocks;
+// changes are announced on the channel of the same name.
+const DefaultKillSwitchKey = "llmux:killswitch"
+
+// RedisKillSwitchStore keeps kill switch blocks in a Redis hash and
+// announces changes over pub/sub.
+type RedisKillSwitchStore struct {
+	client redis.UniversalClient
+	key    string
+}
+
+// NewRedisKillSwitchStore creates a store on key (default
+// DefaultKillSwitchKey).
+func NewRedisKillSwitchStore(client redis.UniversalClient, key string) *RedisKillSwitchStore {
+	if key == "" {
+		key = DefaultKillSwitchKey
+	}
+	return &RedisKillSwitchStore{client: client, key: key}
+}
+
+// Save implements KillSwitchStore.
+func (r *RedisKillSwitchStore) Save(ctx context.Context, b Block) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	if err := r.client.HSet(ctx, r.key, blockField(b.Scope, b.Target), data).Err(); err != nil {
+		return err
+	}
+	return r.client.Publish(ctx, r.key, "save").Err()
+}
+
+// Delete implements KillSwitchStore.
+func (r *RedisKillSwitchStore) Delete(ctx context.Context, scope BlockScope, target string) (bool, error) {
+	deleted, err := r.client.HDel(ctx, r.key, blockField(scope, target)).Result()
+	if err != nil {
+		return false, err
+	}
+	if deleted == 0 {
+		return false, nil
+	}
+	return true, r.client.Publish(ctx, r.key, "delete").Err()
+}
+
+// Load implements KillSwitchStore.
+func (r *RedisKillSwitchStore) Load(ctx context.Context) ([]Block, error) {
+	fields, err := r.client.HGetAll(ctx, r.key).Result()
+	if err != nil {
+		return nil, err
+	}
+	blocks := make([]Block, 0, len(fields))
+	for field, data := range fields {
+		var b Block
+		if err := json.Unmarshal([]byte(data), &b); err != nil {
+			return nil, fmt.Errorf("decode block %s: %w", field, err)
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, nil
+}
+
+// Subscribe implements KillSwitchStore. It returns once the subscription is
+// confirmed and delivers notifications in the background until ctx is done.
+func (r *RedisKillSwitchStore) Subscribe(ctx context.Context, fn func()) error {
+	sub := r.client.Subscribe(ctx, r.key)
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return fmt.Errorf("subscribe to %s: %w", r.key, err)
+	}
+	go func() {
+		defer sub.Close()
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-ch:
+				if !ok {
+					return
+				}
+				fn()
+			}
+		}
+	}()
+	return nil
+}
+
+func blockField(scope BlockScope, target string) string {
+	return string(scope) + ":" + target
+}
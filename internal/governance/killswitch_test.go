@@ -0,0 +1,109 @@
+package governance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestKillSwitch_Check(t *testing.T) {
+	ks := NewKillSwitch()
+	if _, ok := ks.Check("team-a", "gpt-4o"); ok {
+		t.Fatal("expected no block on an empty kill switch")
+	}
+
+	if _, err := ks.Block(context.Background(), Block{Scope: BlockScopeModel, Target: "gpt-4o", Reason: "runaway cost"}); err != nil {
+		t.Fatalf("Block() error = %v", err)
+	}
+	if _, err := ks.Block(context.Background(), Block{Scope: BlockScopeTeam, Target: "team-b", Reason: "abuse"}); err != nil {
+		t.Fatalf("Block() error = %v", err)
+	}
+
+	if b, ok := ks.Check("team-a", "gpt-4o"); !ok || b.Scope != BlockScopeModel {
+		t.Fatalf("model block = %+v, %v", b, ok)
+	}
+	if _, ok := ks.Check("team-a", "gpt-4o-mini"); ok {
+		t.Fatal("other models must not be blocked")
+	}
+	if b, ok := ks.Check("team-b", "gpt-4o-mini"); !ok || b.Message() != "traffic blocked by kill switch (team team-b): abuse" {
+		t.Fatalf("team block = %+v, %v", b, ok)
+	}
+
+	if _, err := ks.Block(context.Background(), Block{Scope: BlockScopeGlobal, Reason: "incident 42"}); err != nil {
+		t.Fatalf("Block() error = %v", err)
+	}
+	if b, ok := ks.Check("", "anything"); !ok || b.Scope != BlockScopeGlobal {
+		t.Fatalf("global block = %+v, %v", b, ok)
+	}
+	if _, ok, err := ks.Unblock(context.Background(), BlockScopeGlobal, ""); err != nil || !ok {
+		t.Fatal("expected global block to be released")
+	}
+	if got := len(ks.List()); got != 2 {
+		t.Fatalf("List() len = %d, want 2", got)
+	}
+}
+
+func TestKillSwitch_BlockValidation(t *testing.T) {
+	ks := NewKillSwitch()
+	for _, b := range []Block{
+		{Scope: BlockScopeGlobal, Target: "x", Reason: "r"},
+		{Scope: BlockScopeModel, Reason: "r"},
+		{Scope: BlockScopeTeam, Target: "t"},
+		{Scope: "region", Target: "eu", Reason: "r"},
+	} {
+		if _, err := ks.Block(context.Background(), b); err == nil {
+			t.Errorf("Block(%+v) expected error", b)
+		}
+	}
+}
+
+func TestKillSwitch_SharedAcrossInstances(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	newInstance := func() *KillSwitch {
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+		ks, err := NewSharedKillSwitch(ctx, NewRedisKillSwitchStore(client, ""), nil)
+		if err != nil {
+			t.Fatalf("NewSharedKillSwitch: %v", err)
+		}
+		return ks
+	}
+	a, b := newInstance(), newInstance()
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("instance b did not see the %s", what)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if _, err := a.Block(ctx, Block{Scope: BlockScopeModel, Target: "gpt-4o", Reason: "incident"}); err != nil {
+		t.Fatalf("Block() error = %v", err)
+	}
+	waitFor("block", func() bool {
+		_, ok := b.Check("", "gpt-4o")
+		return ok
+	})
+
+	if c := newInstance(); len(c.List()) != 1 {
+		t.Fatalf("new instance loaded %d blocks, want 1", len(c.List()))
+	}
+
+	if _, ok, err := b.Unblock(ctx, BlockScopeModel, "gpt-4o"); err != nil || !ok {
+		t.Fatalf("Unblock() = %v, %v", ok, err)
+	}
+	waitFor("release", func() bool {
+		_, ok := a.Check("", "gpt-4o")
+		return !ok
+	})
+}
@@ -44,9 +44,25 @@ func WithIdempotencyStore(store IdempotencyStore) Option {
 	}
 }
 
+// WithResponseStore sets the store that keeps responses for replay to
+// requests repeating an Idempotency-Key.
+func WithResponseStore(store ResponseStore) Option {
+	return func(e *Engine) {
+		e.responses = store
+	}
+}
+
 // WithCasbinEnforcer sets the Casbin enforcer for governance checks.
 func WithCasbinEnforcer(enforcer *auth.CasbinEnforcer) Option {
 	return func(e *Engine) {
 		e.enforcer = enforcer
 	}
 }
+
+// WithBudgetAlerter sets the alerter notified when spend reaches budget
+// alert thresholds.
+func WithBudgetAlerter(alerter *BudgetAlerter) Option {
+	return func(e *Engine) {
+		e.budgetAlerter = alerter
+	}
+}
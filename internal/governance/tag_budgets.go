@@ -0,0 +1,194 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+// tagSpendRefresh is how long tag spend loaded from usage logs is trusted
+// before it is reloaded. Spend recorded by this instance is added in between,
+// so only other instances' spend lags.
+const tagSpendRefresh = time.Minute
+
+// TagBudget caps the spend of requests carrying Tag, independent of the key
+// or team that made them. Periods are calendar aligned in UTC: "1d" resets
+// at midnight, "7d" on Monday and "30d" on the first of the month. An empty
+// duration never resets.
+type TagBudget struct {
+	Tag       string
+	MaxBudget float64
+	Duration  auth.BudgetDuration
+}
+
+// TagBudgetStatus reports a tag budget's spend in its current period.
+type TagBudgetStatus struct {
+	Tag            string              `json:"tag"`
+	MaxBudget      float64             `json:"max_budget"`
+	BudgetDuration auth.BudgetDuration `json:"budget_duration,omitempty"`
+	PeriodStart    *time.Time          `json:"period_start,omitempty"`
+	Spend          float64             `json:"spend"`
+	Remaining      float64             `json:"remaining"`
+	Exceeded       bool                `json:"exceeded"`
+}
+
+// periodStart returns the start of the budget period containing now, or the
+// zero time for budgets that never reset.
+func (b TagBudget) periodStart(now time.Time) time.Time {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch b.Duration {
+	case auth.BudgetDurationDaily:
+		return day
+	case auth.BudgetDurationWeekly:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case auth.BudgetDurationMonthly:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Time{}
+	}
+}
+
+type tagSpendEntry struct {
+	periodStart time.Time
+	spend       float64
+	loadedAt    time.Time
+}
+
+// tagSpendTracker caches the current period spend of budgeted tags.
+type tagSpendTracker struct {
+	mu      sync.Mutex
+	entries map[string]*tagSpendEntry
+	now     func() time.Time
+}
+
+func newTagSpendTracker() *tagSpendTracker {
+	return &tagSpendTracker{entries: make(map[string]*tagSpendEntry), now: time.Now}
+}
+
+// spend returns the tag's spend in the budget's current period, reloading it
+// from the store's usage logs when stale.
+func (t *tagSpendTracker) spend(ctx context.Context, store auth.Store, budget TagBudget) (float64, error) {
+	now := t.now()
+	start := budget.periodStart(now)
+
+	t.mu.Lock()
+	entry, ok := t.entries[budget.Tag]
+	if ok && entry.periodStart.Equal(start) && now.Sub(entry.loadedAt) < tagSpendRefresh {
+		spend := entry.spend
+		t.mu.Unlock()
+		return spend, nil
+	}
+	t.mu.Unlock()
+
+	filter := auth.DailyUsageFilter{Tag: &budget.Tag}
+	if !start.IsZero() {
+		filter.StartDate = start.Format("2006-01-02")
+	}
+	usage, err := store.GetDailyTagUsage(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	var spend float64
+	for _, u := range usage {
+		spend += u.Spend
+	}
+
+	t.mu.Lock()
+	t.entries[budget.Tag] = &tagSpendEntry{periodStart: start, spend: spend, loadedAt: now}
+	t.mu.Unlock()
+	return spend, nil
+}
+
+// add records spend of this instance until the next reload.
+func (t *tagSpendTracker) add(tag string, cost float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if entry, ok := t.entries[tag]; ok {
+		entry.spend += cost
+	}
+}
+
+func tagBudgetIndex(budgets []TagBudget) map[string]TagBudget {
+	index := make(map[string]TagBudget, len(budgets))
+	for _, b := range budgets {
+		index[b.Tag] = b
+	}
+	return index
+}
+
+// checkTagBudgets rejects requests carrying a tag whose budget is spent.
+func (e *Engine) checkTagBudgets(ctx context.Context, cfg Config, model string, tags []string, authCtx *auth.AuthContext) error {
+	if len(cfg.TagBudgets) == 0 || len(tags) == 0 || e.store == nil {
+		return nil
+	}
+	budgets := tagBudgetIndex(cfg.TagBudgets)
+	for _, tag := range tags {
+		budget, ok := budgets[tag]
+		if !ok || budget.MaxBudget <= 0 {
+			continue
+		}
+		spend, err := e.tagSpend.spend(ctx, e.store, budget)
+		if err != nil {
+			e.logger.Warn("failed to load tag spend", "tag", tag, "error", err)
+			continue
+		}
+		if spend >= budget.MaxBudget {
+			e.auditBudgetExceeded(authCtx, auth.AuditObjectTag, tag, model)
+			return llmerrors.NewInsufficientQuotaError("gateway", model, fmt.Sprintf("budget exceeded for tag %q", tag))
+		}
+	}
+	return nil
+}
+
+// accountTagSpend adds cost to the cached spend of budgeted tags.
+func (e *Engine) accountTagSpend(cfg Config, tags []string, cost float64) {
+	if len(cfg.TagBudgets) == 0 || cost <= 0 {
+		return
+	}
+	budgets := tagBudgetIndex(cfg.TagBudgets)
+	for _, tag := range tags {
+		if _, ok := budgets[tag]; ok {
+			e.tagSpend.add(tag, cost)
+		}
+	}
+}
+
+// TagBudgets returns the spend of every configured tag budget in its current
+// period, sorted by tag.
+func (e *Engine) TagBudgets(ctx context.Context) ([]TagBudgetStatus, error) {
+	cfg := e.loadConfig()
+	out := make([]TagBudgetStatus, 0, len(cfg.TagBudgets))
+	if e.store == nil {
+		return out, nil
+	}
+	now := e.tagSpend.now()
+	for _, b := range cfg.TagBudgets {
+		spend, err := e.tagSpend.spend(ctx, e.store, b)
+		if err != nil {
+			return nil, err
+		}
+		status := TagBudgetStatus{
+			Tag:            b.Tag,
+			MaxBudget:      b.MaxBudget,
+			BudgetDuration: b.Duration,
+			Spend:          spend,
+			Remaining:      b.MaxBudget - spend,
+			Exceeded:       b.MaxBudget > 0 && spend >= b.MaxBudget,
+		}
+		if start := b.periodStart(now); !start.IsZero() {
+			status.PeriodStart = &start
+		}
+		if status.Remaining < 0 {
+			status.Remaining = 0
+		}
+		out = append(out, status)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Tag < out[j].Tag })
+	return out, nil
+}
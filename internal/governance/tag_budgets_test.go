@@ -0,0 +1,105 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+func TestTagBudgetPeriodStart(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 30, 0, 0, time.UTC) // Friday
+	tests := []struct {
+		duration auth.BudgetDuration
+		want     time.Time
+	}{
+		{auth.BudgetDurationDaily, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{auth.BudgetDurationWeekly, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)},
+		{auth.BudgetDurationMonthly, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{auth.BudgetDurationNever, time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := (TagBudget{Duration: tt.duration}).periodStart(now); !got.Equal(tt.want) {
+			t.Errorf("periodStart(%q) = %v, want %v", tt.duration, got, tt.want)
+		}
+	}
+}
+
+func TestEngine_TagBudgets(t *testing.T) {
+	store := auth.NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := NewEngine(Config{
+		Enabled:    true,
+		TagBudgets: []TagBudget{{Tag: "project:alpha", MaxBudget: 5, Duration: auth.BudgetDurationMonthly}},
+	}, WithStore(store), WithLogger(logger))
+
+	ctx := context.Background()
+	// Spend from an earlier month does not count toward this period.
+	if err := store.LogUsage(ctx, &auth.UsageLog{
+		Model: "gpt-4", Cost: 100, RequestTags: []string{"project:alpha"},
+		StartTime: time.Now().UTC().AddDate(0, -2, 0),
+	}); err != nil {
+		t.Fatalf("LogUsage() error = %v", err)
+	}
+
+	alpha := RequestInput{Model: "gpt-4", Tags: []string{"project:alpha"}}
+	if err := engine.Evaluate(ctx, alpha); err != nil {
+		t.Fatalf("Evaluate() under budget error = %v", err)
+	}
+
+	engine.Account(ctx, AccountInput{
+		RequestID:   "req-1",
+		Model:       "gpt-4",
+		Usage:       Usage{TotalTokens: 10, Cost: 5},
+		RequestTags: []string{"project:alpha", "team:search"},
+		Start:       time.Now(),
+		Latency:     time.Second,
+	})
+
+	err := engine.Evaluate(ctx, alpha)
+	var llmErr *llmerrors.LLMError
+	if !errors.As(err, &llmErr) || llmErr.Type != llmerrors.TypeInsufficientQuota {
+		t.Fatalf("Evaluate() over budget = %v, want insufficient quota error", err)
+	}
+	if err := engine.Evaluate(ctx, RequestInput{Model: "gpt-4", Tags: []string{"team:search"}}); err != nil {
+		t.Fatalf("Evaluate() unbudgeted tag error = %v", err)
+	}
+
+	status, err := engine.TagBudgets(ctx)
+	if err != nil {
+		t.Fatalf("TagBudgets() error = %v", err)
+	}
+	if len(status) != 1 || status[0].Spend != 5 || !status[0].Exceeded || status[0].Remaining != 0 || status[0].PeriodStart == nil {
+		t.Fatalf("TagBudgets() = %+v", status)
+	}
+}
+
+func TestTagSpendTracker_Refresh(t *testing.T) {
+	store := auth.NewMemoryStore()
+	tracker := newTagSpendTracker()
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+	budget := TagBudget{Tag: "batch", MaxBudget: 10}
+	ctx := context.Background()
+
+	if spend, err := tracker.spend(ctx, store, budget); err != nil || spend != 0 {
+		t.Fatalf("spend() = %v, %v", spend, err)
+	}
+	// Another instance records spend; it shows up after the refresh interval.
+	if err := store.LogUsage(ctx, &auth.UsageLog{Cost: 3, RequestTags: []string{"batch"}, StartTime: now}); err != nil {
+		t.Fatalf("LogUsage() error = %v", err)
+	}
+	tracker.add("batch", 1)
+	if spend, _ := tracker.spend(ctx, store, budget); spend != 1 {
+		t.Fatalf("cached spend = %v, want 1", spend)
+	}
+	now = now.Add(tagSpendRefresh)
+	if spend, _ := tracker.spend(ctx, store, budget); spend != 3 {
+		t.Fatalf("refreshed spend = %v, want 3", spend)
+	}
+}
@@ -10,6 +10,7 @@ const (
 	CallTypeChatCompletion = "chat_completion"
 	CallTypeCompletion     = "completion"
 	CallTypeEmbedding      = "embedding"
+	CallTypeMCPToolCall    = "mcp_tool_call"
 )
 
 // Config controls governance behavior.
@@ -18,6 +19,10 @@ type Config struct {
 	AsyncAccounting   bool
 	IdempotencyWindow time.Duration
 	AuditEnabled      bool
+	// ContentPolicies apply per-category limits to classified request content.
+	ContentPolicies []ContentPolicy
+	// TagBudgets cap the spend of requests carrying a tag.
+	TagBudgets []TagBudget
 }
 
 // RequestInput captures request context for governance evaluation.
@@ -27,6 +32,16 @@ type RequestInput struct {
 	CallType  string
 	EndUserID string
 	Tags      []string
+	// Content is the prompt text classified by content policies.
+	Content string
+}
+
+// Decision reports the outcome of a successful governance evaluation.
+type Decision struct {
+	// ContentCategories lists the categories detected in the request content.
+	ContentCategories []string
+	// RouteTags are routing tags required by matching content policies.
+	RouteTags []string
 }
 
 // Usage captures token and cost information for accounting.
@@ -50,4 +65,7 @@ type AccountInput struct {
 	Latency     time.Duration
 	StatusCode  *int
 	Status      *string
+	// Metadata is stored with the usage log, e.g. the parent_request_id of
+	// agent-loop calls.
+	Metadata map[string]any
 }
@@ -0,0 +1,215 @@
+package guardrails
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RegexCheck matches text against a deny list of regular expressions.
+type RegexCheck struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRegexCheck compiles patterns into a RegexCheck.
+func NewRegexCheck(patterns []string) (*RegexCheck, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("at least one pattern is required")
+	}
+	c := &RegexCheck{patterns: make([]*regexp.Regexp, 0, len(patterns))}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		c.patterns = append(c.patterns, re)
+	}
+	return c, nil
+}
+
+// Inspect implements Check.
+func (c *RegexCheck) Inspect(_ context.Context, text string) (*Match, error) {
+	var spans []Span
+	var matched []string
+	for _, re := range c.patterns {
+		locs := re.FindAllStringIndex(text, -1)
+		if len(locs) == 0 {
+			continue
+		}
+		matched = append(matched, re.String())
+		for _, loc := range locs {
+			spans = append(spans, Span{Start: loc[0], End: loc[1]})
+		}
+	}
+	if len(spans) == 0 {
+		return nil, nil
+	}
+	return &Match{Reason: "matched deny pattern " + strings.Join(matched, ", "), Spans: spans}, nil
+}
+
+// PII entity types detected by PIICheck.
+const (
+	PIIEmail      = "email"
+	PIIPhone      = "phone"
+	PIISSN        = "ssn"
+	PIICreditCard = "credit_card"
+	PIIIPAddress  = "ip_address"
+)
+
+var piiPatterns = map[string]*regexp.Regexp{
+	PIIEmail:      regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	PIIPhone:      regexp.MustCompile(`(?:\+\d{1,3}[\s.\-]?)?\(?\d{3}\)?[\s.\-]\d{3}[\s.\-]\d{4}\b`),
+	PIISSN:        regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+	PIICreditCard: regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`),
+	PIIIPAddress:  regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`),
+}
+
+// PIIEntities lists every entity type PIICheck understands.
+var PIIEntities = []string{PIIEmail, PIIPhone, PIISSN, PIICreditCard, PIIIPAddress}
+
+// PIICheck detects personal data such as emails, phone numbers, SSNs,
+// payment card numbers and IP addresses.
+type PIICheck struct {
+	entities []string
+}
+
+// NewPIICheck detects the given entity types, or all of them when empty.
+func NewPIICheck(entities []string) (*PIICheck, error) {
+	if len(entities) == 0 {
+		entities = PIIEntities
+	}
+	for _, e := range entities {
+		if _, ok := piiPatterns[e]; !ok {
+			return nil, fmt.Errorf("unknown pii entity %q", e)
+		}
+	}
+	return &PIICheck{entities: entities}, nil
+}
+
+// Inspect implements Check.
+func (c *PIICheck) Inspect(_ context.Context, text string) (*Match, error) {
+	var spans []Span
+	var found []string
+	for _, entity := range c.entities {
+		hit := false
+		for _, loc := range piiPatterns[entity].FindAllStringIndex(text, -1) {
+			if entity == PIICreditCard && !luhnValid(text[loc[0]:loc[1]]) {
+				continue
+			}
+			spans = append(spans, Span{Start: loc[0], End: loc[1], Label: strings.ToUpper(entity)})
+			hit = true
+		}
+		if hit {
+			found = append(found, entity)
+		}
+	}
+	if len(spans) == 0 {
+		return nil, nil
+	}
+	return &Match{Reason: "detected pii: " + strings.Join(found, ", "), Spans: spans}, nil
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+type injectionPattern struct {
+	re     *regexp.Regexp
+	weight float64
+	label  string
+}
+
+var injectionPatterns = []injectionPattern{
+	{regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\b.{0,40}\b(previous|prior|above|earlier|all|your|the)\b.{0,20}\b(instructions?|prompts?|rules|directions|guidelines)\b`), 0.6, "instruction override"},
+	{regexp.MustCompile(`(?i)\b(reveal|show|print|repeat|output|leak)\b.{0,30}\b(system|hidden|initial|original)\s+(prompt|instructions?|message)`), 0.5, "system prompt extraction"},
+	{regexp.MustCompile(`(?i)\byou are (now|no longer)\b`), 0.3, "persona reassignment"},
+	{regexp.MustCompile(`(?i)\b(DAN|do anything now|developer mode|jailbreak(ed)?|god mode)\b`), 0.5, "jailbreak keyword"},
+	{regexp.MustCompile(`(?i)\b(without|no|ignore)\b.{0,20}\b(restrictions|limitations|filters|censorship|safety)\b`), 0.3, "restriction bypass"},
+	{regexp.MustCompile(`(?i)\bpretend\b.{0,40}\b(no rules|unrestricted|unfiltered)\b`), 0.4, "role-play bypass"},
+	{regexp.MustCompile(`(?i)(^|\n)\s*(system|assistant)\s*:`), 0.3, "role injection"},
+	{regexp.MustCompile(`(?i)<\s*/?\s*(system|im_start|im_end)\s*>|\[/?INST\]`), 0.4, "chat template injection"},
+}
+
+// PromptInjectionConfig configures a PromptInjectionCheck.
+type PromptInjectionConfig struct {
+	// Threshold is the score in (0, 1] at which text is flagged (default 0.5).
+	Threshold float64
+	// Classifier, when set, scores text the heuristics alone do not flag.
+	// The higher of the two scores is used.
+	Classifier Classifier
+}
+
+// PromptInjectionCheck scores text against common prompt-injection and
+// jailbreak phrasings, optionally backed by a classifier model, and flags it
+// when the score reaches a threshold.
+type PromptInjectionCheck struct {
+	threshold  float64
+	classifier Classifier
+}
+
+// NewPromptInjectionCheck creates a prompt injection check.
+func NewPromptInjectionCheck(cfg PromptInjectionConfig) *PromptInjectionCheck {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 0.5
+	}
+	return &PromptInjectionCheck{threshold: cfg.Threshold, classifier: cfg.Classifier}
+}
+
+// Score returns the heuristic injection score of text in [0, 1] and the
+// labels of the patterns that contributed.
+func (c *PromptInjectionCheck) Score(text string) (float64, []string) {
+	score := 0.0
+	var labels []string
+	for _, p := range injectionPatterns {
+		if p.re.MatchString(text) {
+			score += p.weight
+			labels = append(labels, p.label)
+		}
+	}
+	if score > 1 {
+		score = 1
+	}
+	return score, labels
+}
+
+// Inspect implements Check. The classifier is only called when the
+// heuristic score is below the threshold; a classifier error is returned
+// without flagging the text.
+func (c *PromptInjectionCheck) Inspect(ctx context.Context, text string) (*Match, error) {
+	score, labels := c.Score(text)
+	if score < c.threshold && c.classifier != nil {
+		classified, err := c.classifier.Classify(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		if classified > score {
+			score = classified
+			labels = append(labels, "classifier")
+		}
+	}
+	if score < c.threshold {
+		return nil, nil
+	}
+	return &Match{
+		Reason: fmt.Sprintf("prompt injection score %.2f (%s)", score, strings.Join(labels, ", ")),
+		Score:  score,
+	}, nil
+}
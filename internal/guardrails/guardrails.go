@@ -0,0 +1,245 @@
+// Package guardrails screens chat requests and responses with configurable
+// checks (regex deny lists, PII detection, prompt-injection heuristics and
+// external moderation APIs). Each guardrail can block, redact, or annotate
+// the content it matches, and runs inside the plugin pipeline.
+package guardrails
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+// Action is what a guardrail does when its check matches.
+type Action string
+
+const (
+	// ActionBlock rejects the request or response.
+	ActionBlock Action = "block"
+	// ActionRedact replaces the matched spans and lets the request continue.
+	ActionRedact Action = "redact"
+	// ActionAnnotate records the finding and lets the request continue unchanged.
+	ActionAnnotate Action = "annotate"
+)
+
+// MetadataKey is the key/team metadata field listing guardrails to apply in
+// addition to the default-on ones.
+const MetadataKey = "guardrails"
+
+// Check inspects a piece of text. Implementations must be safe for
+// concurrent use.
+type Check interface {
+	// Inspect returns nil when text passes.
+	Inspect(ctx context.Context, text string) (*Match, error)
+}
+
+// Match describes why a check flagged text.
+type Match struct {
+	Reason string
+	Score  float64 // Optional confidence in [0, 1]
+	Spans  []Span  // Byte ranges to redact; required for ActionRedact
+}
+
+// Span is a byte range of matched text.
+type Span struct {
+	Start, End int
+	Label      string // Replacement label, e.g. "EMAIL"; empty uses "REDACTED"
+}
+
+// Guardrail binds a check to the stages it runs in and the action it takes.
+type Guardrail struct {
+	Name      string
+	Check     Check
+	Pre       bool // Run on request messages
+	Post      bool // Run on response messages
+	Action    Action
+	DefaultOn bool // Apply to every request; otherwise keys and teams opt in
+}
+
+// Finding records a guardrail that fired.
+type Finding struct {
+	Guardrail string  `json:"guardrail"`
+	Action    Action  `json:"action"`
+	Reason    string  `json:"reason"`
+	Score     float64 `json:"score,omitempty"`
+}
+
+// Engine holds the configured guardrails.
+type Engine struct {
+	guardrails []*Guardrail
+	byName     map[string]*Guardrail
+}
+
+// NewEngine validates and indexes guardrails. Order is preserved and
+// determines evaluation order.
+func NewEngine(guardrails ...*Guardrail) (*Engine, error) {
+	e := &Engine{byName: make(map[string]*Guardrail, len(guardrails))}
+	for _, g := range guardrails {
+		if g == nil || g.Check == nil {
+			return nil, fmt.Errorf("guardrail %q has no check", nameOf(g))
+		}
+		if g.Name == "" {
+			return nil, fmt.Errorf("guardrail name is required")
+		}
+		if _, dup := e.byName[g.Name]; dup {
+			return nil, fmt.Errorf("duplicate guardrail %q", g.Name)
+		}
+		switch g.Action {
+		case ActionBlock, ActionRedact, ActionAnnotate:
+		default:
+			return nil, fmt.Errorf("guardrail %q: unknown action %q", g.Name, g.Action)
+		}
+		e.guardrails = append(e.guardrails, g)
+		e.byName[g.Name] = g
+	}
+	return e, nil
+}
+
+func nameOf(g *Guardrail) string {
+	if g == nil {
+		return ""
+	}
+	return g.Name
+}
+
+// Len returns the number of configured guardrails.
+func (e *Engine) Len() int {
+	return len(e.guardrails)
+}
+
+// Active returns the guardrails that apply to a request: every default-on
+// guardrail plus those named. Unknown names are ignored.
+func (e *Engine) Active(names []string) []*Guardrail {
+	requested := make(map[string]bool, len(names))
+	for _, n := range names {
+		requested[n] = true
+	}
+	var active []*Guardrail
+	for _, g := range e.guardrails {
+		if g.DefaultOn || requested[g.Name] {
+			active = append(active, g)
+		}
+	}
+	return active
+}
+
+// RequestedGuardrails returns the guardrail names listed in the key and team
+// metadata of authCtx.
+func RequestedGuardrails(authCtx *auth.AuthContext) []string {
+	if authCtx == nil {
+		return nil
+	}
+	var names []string
+	if authCtx.APIKey != nil {
+		names = append(names, metadataNames(authCtx.APIKey.Metadata)...)
+	}
+	if authCtx.Team != nil {
+		names = append(names, metadataNames(authCtx.Team.Metadata)...)
+	}
+	return names
+}
+
+func metadataNames(m auth.Metadata) []string {
+	switch v := m[MetadataKey].(type) {
+	case []string:
+		return v
+	case []any:
+		names := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	case string:
+		return strings.Split(v, ",")
+	}
+	return nil
+}
+
+// Result accumulates findings across the texts of one request or response.
+type Result struct {
+	Findings []Finding
+	Blocked  *Finding
+}
+
+// Apply runs guardrails over text in order and returns the possibly
+// redacted text. Evaluation stops at the first blocking match. Check errors
+// are returned alongside the text and do not block.
+func Apply(ctx context.Context, guardrails []*Guardrail, text string, result *Result) (string, error) {
+	var errs []string
+	for _, g := range guardrails {
+		if text == "" {
+			break
+		}
+		match, err := g.Check.Inspect(ctx, text)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", g.Name, err))
+			continue
+		}
+		if match == nil {
+			continue
+		}
+		finding := Finding{Guardrail: g.Name, Action: g.Action, Reason: match.Reason, Score: match.Score}
+		switch g.Action {
+		case ActionBlock:
+			result.Blocked = &finding
+			result.Findings = append(result.Findings, finding)
+			return text, joinErrors(errs)
+		case ActionRedact:
+			text = Redact(text, match.Spans)
+		}
+		result.Findings = append(result.Findings, finding)
+	}
+	return text, joinErrors(errs)
+}
+
+func joinErrors(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("guardrail checks failed: %s", strings.Join(errs, "; "))
+}
+
+// Redact replaces spans in text with "[LABEL]" placeholders. Overlapping
+// spans are merged.
+func Redact(text string, spans []Span) string {
+	if len(spans) == 0 {
+		return text
+	}
+	sorted := make([]Span, 0, len(spans))
+	for _, s := range spans {
+		if s.Start < 0 || s.End > len(text) || s.Start >= s.End {
+			continue
+		}
+		sorted = append(sorted, s)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var b strings.Builder
+	pos := 0
+	for i := 0; i < len(sorted); i++ {
+		span := sorted[i]
+		for i+1 < len(sorted) && sorted[i+1].Start < span.End {
+			if sorted[i+1].End > span.End {
+				span.End = sorted[i+1].End
+			}
+			i++
+		}
+		if span.Start < pos {
+			continue
+		}
+		label := span.Label
+		if label == "" {
+			label = "REDACTED"
+		}
+		b.WriteString(text[pos:span.Start])
+		b.WriteString("[" + label + "]")
+		pos = span.End
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package guardrails
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// ModerationConfig configures a call to an OpenAI-compatible moderation API.
+type ModerationConfig struct {
+	URL        string // e.g. https://api.openai.com/v1/moderations
+	APIKey     string
+	Model      string
+	Timeout    time.Duration // Default 5s
+	FailClosed bool          // Treat API failures as a match instead of passing
+	HTTPClient *http.Client
+}
+
+// ModerationCheck flags text that an external moderation API marks as flagged.
+type ModerationCheck struct {
+	cfg    ModerationConfig
+	client *http.Client
+}
+
+// NewModerationCheck creates a moderation check.
+func NewModerationCheck(cfg ModerationConfig) (*ModerationCheck, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("moderation url is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+	return &ModerationCheck{cfg: cfg, client: client}, nil
+}
+
+type moderationResponse struct {
+	Results []struct {
+		Flagged        bool               `json:"flagged"`
+		Categories     map[string]bool    `json:"categories"`
+		CategoryScores map[string]float64 `json:"category_scores"`
+	} `json:"results"`
+}
+
+// Inspect implements Check.
+func (c *ModerationCheck) Inspect(ctx context.Context, text string) (*Match, error) {
+	resp, err := c.moderate(ctx, text)
+	if err != nil {
+		if c.cfg.FailClosed {
+			return &Match{Reason: "moderation unavailable"}, nil
+		}
+		return nil, err
+	}
+
+	for _, result := range resp.Results {
+		if !result.Flagged {
+			continue
+		}
+		var categories []string
+		score := 0.0
+		for name, flagged := range result.Categories {
+			if flagged {
+				categories = append(categories, name)
+				if s := result.CategoryScores[name]; s > score {
+					score = s
+				}
+			}
+		}
+		sort.Strings(categories)
+		reason := "flagged by moderation"
+		if len(categories) > 0 {
+			reason += ": " + strings.Join(categories, ", ")
+		}
+		return &Match{Reason: reason, Score: score}, nil
+	}
+	return nil, nil
+}
+
+func (c *ModerationCheck) moderate(ctx context.Context, text string) (*moderationResponse, error) {
+	payload := map[string]any{"input": text}
+	if c.cfg.Model != "" {
+		payload["model"] = c.cfg.Model
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("moderation request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("moderation api returned status %d", resp.StatusCode)
+	}
+
+	var out moderationResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode moderation response: %w", err)
+	}
+	return &out, nil
+}
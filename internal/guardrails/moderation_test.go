@@ -0,0 +1,64 @@
+package guardrails
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func TestModerationCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk-mod" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Input string `json:"input"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		flagged := strings.Contains(body.Input, "bad")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"results": []map[string]any{{
+				"flagged":         flagged,
+				"categories":      map[string]bool{"violence": flagged, "hate": false},
+				"category_scores": map[string]float64{"violence": 0.92, "hate": 0.01},
+			}},
+		})
+	}))
+	defer srv.Close()
+
+	c, err := NewModerationCheck(ModerationConfig{URL: srv.URL, APIKey: "sk-mod"})
+	if err != nil {
+		t.Fatalf("NewModerationCheck: %v", err)
+	}
+	m, err := c.Inspect(context.Background(), "something bad")
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if m == nil || m.Reason != "flagged by moderation: violence" || m.Score != 0.92 {
+		t.Fatalf("match = %+v", m)
+	}
+	if m, err := c.Inspect(context.Background(), "hello"); m != nil || err != nil {
+		t.Fatalf("benign Inspect = %+v, %v", m, err)
+	}
+}
+
+func TestModerationCheck_Failure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	open, _ := NewModerationCheck(ModerationConfig{URL: srv.URL})
+	if m, err := open.Inspect(context.Background(), "x"); m != nil || err == nil {
+		t.Fatalf("fail-open Inspect = %+v, %v; want error", m, err)
+	}
+	closed, _ := NewModerationCheck(ModerationConfig{URL: srv.URL, FailClosed: true})
+	if m, err := closed.Inspect(context.Background(), "x"); m == nil || err != nil {
+		t.Fatalf("fail-closed Inspect = %+v, %v; want match", m, err)
+	}
+}
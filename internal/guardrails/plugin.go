@@ -0,0 +1,330 @@
+package guardrails
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/metrics"
+	"github.com/blueberrycongee/llmux/internal/plugin"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// PluginName is the name of the guardrails plugin in the pipeline.
+const PluginName = "guardrails"
+
+// findingsKey stores the request's []Finding in the plugin context.
+const findingsKey = "guardrails.findings"
+
+// Plugin runs an Engine as a pipeline plugin. Pre-request guardrails run for
+// both regular and streaming requests. Post-response guardrails need the
+// whole response, so streaming requests are rejected while any apply.
+type Plugin struct {
+	engine   *Engine
+	logger   *slog.Logger
+	priority int
+}
+
+// NewPlugin wraps engine in a plugin. It runs at priority 8: after rate
+// limiting and before response caching.
+func NewPlugin(engine *Engine, logger *slog.Logger) *Plugin {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Plugin{engine: engine, logger: logger, priority: 8}
+}
+
+func (p *Plugin) Name() string  { return PluginName }
+func (p *Plugin) Priority() int { return p.priority }
+
+func (p *Plugin) PreHook(ctx *plugin.Context, req *types.ChatRequest) (*types.ChatRequest, *plugin.ShortCircuit, error) {
+	req, err := p.checkRequest(ctx, req)
+	if err != nil {
+		return req, &plugin.ShortCircuit{Error: err}, nil
+	}
+	return req, nil, nil
+}
+
+func (p *Plugin) PostHook(ctx *plugin.Context, resp *types.ChatResponse, err error) (*types.ChatResponse, error, error) {
+	if err != nil || resp == nil {
+		return resp, err, nil
+	}
+	authCtx := p.authContext(ctx)
+	guardrails := p.active(authCtx, false)
+	if len(guardrails) == 0 {
+		return resp, nil, nil
+	}
+
+	result := &Result{}
+	choices := make([]types.Choice, len(resp.Choices))
+	copy(choices, resp.Choices)
+	for i := range choices {
+		content, checkErr := p.applyContent(ctx, guardrails, choices[i].Message.Content, result)
+		if checkErr != nil {
+			p.logger.Warn("guardrail check error", "request_id", ctx.RequestID, "error", checkErr)
+		}
+		if result.Blocked != nil {
+			p.record(ctx, authCtx, "post", guardrails, result)
+			return nil, blockedError(resp.Model, "response", result.Blocked), nil
+		}
+		choices[i].Message.Content = content
+	}
+	p.record(ctx, authCtx, "post", guardrails, result)
+	if len(result.Findings) == 0 {
+		return resp, nil, nil
+	}
+	out := *resp
+	out.Choices = choices
+	return &out, nil, nil
+}
+
+func (p *Plugin) Cleanup() error { return nil }
+
+func (p *Plugin) PreStreamHook(ctx *plugin.Context, req *types.ChatRequest) (*types.ChatRequest, *plugin.StreamShortCircuit, error) {
+	if post := p.active(p.authContext(ctx), false); len(post) > 0 {
+		model := ""
+		if req != nil {
+			model = req.Model
+		}
+		return req, &plugin.StreamShortCircuit{Error: llmerrors.NewInvalidRequestError("", model,
+			fmt.Sprintf("streaming is not supported while response guardrail %q applies; retry with stream=false", post[0].Name))}, nil
+	}
+	req, err := p.checkRequest(ctx, req)
+	if err != nil {
+		return req, &plugin.StreamShortCircuit{Error: err}, nil
+	}
+	return req, nil, nil
+}
+
+func (p *Plugin) OnStreamChunk(_ *plugin.Context, chunk *types.StreamChunk) (*types.StreamChunk, error) {
+	return chunk, nil
+}
+
+func (p *Plugin) PostStreamHook(_ *plugin.Context, err error) error {
+	return err
+}
+
+// checkRequest runs pre-request guardrails over every message. It returns a
+// copy of req when content was redacted, and a content policy error when a
+// guardrail blocks.
+func (p *Plugin) checkRequest(ctx *plugin.Context, req *types.ChatRequest) (*types.ChatRequest, error) {
+	authCtx := p.authContext(ctx)
+	guardrails := p.active(authCtx, true)
+	if len(guardrails) == 0 || req == nil {
+		return req, nil
+	}
+
+	result := &Result{}
+	messages := make([]types.ChatMessage, len(req.Messages))
+	copy(messages, req.Messages)
+	for i := range messages {
+		content, err := p.applyContent(ctx, guardrails, messages[i].Content, result)
+		if err != nil {
+			p.logger.Warn("guardrail check error", "request_id", ctx.RequestID, "error", err)
+		}
+		if result.Blocked != nil {
+			p.record(ctx, authCtx, "pre", guardrails, result)
+			return req, blockedError(req.Model, "request", result.Blocked)
+		}
+		messages[i].Content = content
+	}
+	p.record(ctx, authCtx, "pre", guardrails, result)
+	if len(result.Findings) == 0 {
+		return req, nil
+	}
+	out := *req
+	out.Messages = messages
+	return &out, nil
+}
+
+func (p *Plugin) authContext(ctx *plugin.Context) *auth.AuthContext {
+	if ctx.Auth != nil {
+		return ctx.Auth
+	}
+	return auth.GetAuthContext(ctx.Context)
+}
+
+func (p *Plugin) active(authCtx *auth.AuthContext, pre bool) []*Guardrail {
+	var out []*Guardrail
+	for _, g := range p.engine.Active(RequestedGuardrails(authCtx)) {
+		if (pre && g.Pre) || (!pre && g.Post) {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// applyContent runs guardrails over the text of a message content, which
+// is either a JSON string or an array of content parts.
+func (p *Plugin) applyContent(ctx context.Context, guardrails []*Guardrail, content json.RawMessage, result *Result) (json.RawMessage, error) {
+	if len(content) == 0 {
+		return content, nil
+	}
+
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		out, checkErr := Apply(ctx, guardrails, text, result)
+		if out == text {
+			return content, checkErr
+		}
+		encoded, err := json.Marshal(out)
+		if err != nil {
+			return content, err
+		}
+		return encoded, checkErr
+	}
+
+	var parts []map[string]any
+	if err := json.Unmarshal(content, &parts); err != nil {
+		return content, nil
+	}
+	changed := false
+	var checkErr error
+	for _, part := range parts {
+		if typ, _ := part["type"].(string); typ != "" && typ != "text" {
+			continue
+		}
+		partText, ok := part["text"].(string)
+		if !ok {
+			continue
+		}
+		out, err := Apply(ctx, guardrails, partText, result)
+		if err != nil {
+			checkErr = err
+		}
+		if result.Blocked != nil {
+			return content, checkErr
+		}
+		if out != partText {
+			part["text"] = out
+			changed = true
+		}
+	}
+	if !changed {
+		return content, checkErr
+	}
+	encoded, err := json.Marshal(parts)
+	if err != nil {
+		return content, err
+	}
+	return encoded, checkErr
+}
+
+// record counts the outcome of each guardrail for the request's team, logs
+// findings and publishes them to the plugin context and any Annotations
+// collector on the request context. When a guardrail blocked, guardrails
+// that did not fire are not counted as they may not have seen every message.
+func (p *Plugin) record(ctx *plugin.Context, authCtx *auth.AuthContext, stage string, guardrails []*Guardrail, result *Result) {
+	team := ""
+	if authCtx != nil && authCtx.Team != nil {
+		team = authCtx.Team.ID
+	}
+	fired := make(map[string]Action, len(result.Findings))
+	for _, f := range result.Findings {
+		fired[f.Guardrail] = f.Action
+	}
+	for _, g := range guardrails {
+		action, ok := fired[g.Name]
+		if !ok && result.Blocked != nil {
+			continue
+		}
+		metrics.RecordGuardrailEvaluation(g.Name, stage, team, outcome(action, ok))
+	}
+
+	if len(result.Findings) == 0 {
+		return
+	}
+	for _, f := range result.Findings {
+		p.logger.Info("guardrail triggered",
+			"request_id", ctx.RequestID,
+			"guardrail", f.Guardrail,
+			"action", f.Action,
+			"reason", f.Reason,
+		)
+	}
+	var all []Finding
+	if prev, ok := ctx.Get(findingsKey); ok {
+		all, _ = prev.([]Finding)
+	}
+	ctx.Set(findingsKey, append(all, result.Findings...))
+	if a := annotationsFrom(ctx.Context); a != nil {
+		a.add(result.Findings)
+	}
+}
+
+func outcome(action Action, fired bool) string {
+	if !fired {
+		return "passed"
+	}
+	switch action {
+	case ActionBlock:
+		return "blocked"
+	case ActionRedact:
+		return "redacted"
+	default:
+		return "annotated"
+	}
+}
+
+func blockedError(model, stage string, f *Finding) error {
+	return llmerrors.NewContentPolicyError("", model, fmt.Sprintf("%s blocked by guardrail %q: %s", stage, f.Guardrail, f.Reason))
+}
+
+// Annotations collects the findings of one request so callers outside the
+// pipeline, such as HTTP handlers, can report them.
+type Annotations struct {
+	mu       sync.Mutex
+	findings []Finding
+}
+
+type annotationsKey struct{}
+
+// WithAnnotations returns a context whose guardrail findings are collected
+// into the returned Annotations.
+func WithAnnotations(ctx context.Context) (context.Context, *Annotations) {
+	a := &Annotations{}
+	return context.WithValue(ctx, annotationsKey{}, a), a
+}
+
+func annotationsFrom(ctx context.Context) *Annotations {
+	if ctx == nil {
+		return nil
+	}
+	a, _ := ctx.Value(annotationsKey{}).(*Annotations)
+	return a
+}
+
+func (a *Annotations) add(findings []Finding) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.findings = append(a.findings, findings...)
+}
+
+// Findings returns the findings recorded so far.
+func (a *Annotations) Findings() []Finding {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Finding(nil), a.findings...)
+}
+
+// Names returns the distinct guardrails that fired, in order.
+func (a *Annotations) Names() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	seen := make(map[string]bool, len(a.findings))
+	var names []string
+	for _, f := range a.findings {
+		if !seen[f.Guardrail] {
+			seen[f.Guardrail] = true
+			names = append(names, f.Guardrail)
+		}
+	}
+	return names
+}
+
+var _ plugin.StreamPlugin = (*Plugin)(nil)
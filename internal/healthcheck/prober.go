@@ -29,6 +29,9 @@ type Config struct {
 	Interval       time.Duration
 	Timeout        time.Duration
 	CooldownPeriod time.Duration
+	// IsLeader, when set, limits probing to the replica for which it
+	// returns true.
+	IsLeader func() bool
 }
 
 // ClientProvider supplies the current llmux client.
@@ -119,6 +122,9 @@ func (p *Prober) run(ctx context.Context) {
 }
 
 func (p *Prober) runOnce(ctx context.Context) {
+	if p.cfg.IsLeader != nil && !p.cfg.IsLeader() {
+		return
+	}
 	client, release := p.provider.Acquire()
 	if client == nil {
 		return
@@ -153,14 +159,18 @@ func (p *Prober) runOnce(ctx context.Context) {
 func (p *Prober) probeDeployment(ctx context.Context, prov provider.Provider, deployment *provider.Deployment) error {
 	probeCtx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
 	defer cancel()
+	return Probe(probeCtx, p.client, prov, deployment)
+}
 
-	req := buildProbeRequest(deployment.ModelName)
-	httpReq, err := prov.BuildRequest(probeCtx, req)
+// Probe sends a minimal authenticated request (one output token) for the
+// deployment's model and returns the mapped provider error on failure.
+func Probe(ctx context.Context, httpClient *http.Client, prov provider.Provider, deployment *provider.Deployment) error {
+	httpReq, err := prov.BuildRequest(ctx, ProbeRequest(deployment.ModelName))
 	if err != nil {
 		return err
 	}
 
-	resp, err := p.client.Do(httpReq)
+	resp, err := httpClient.Do(httpReq)
 	if err != nil {
 		return err
 	}
@@ -243,7 +253,8 @@ func (p *Prober) handleSuccess(client *llmux.Client, deployment *provider.Deploy
 	p.clearCooldown(deployment.ID)
 }
 
-func buildProbeRequest(model string) *types.ChatRequest {
+// ProbeRequest returns the minimal chat request used to probe model.
+func ProbeRequest(model string) *types.ChatRequest {
 	return &types.ChatRequest{
 		Model: model,
 		Messages: []types.ChatMessage{
@@ -174,3 +174,29 @@ func TestProber_RunOnce_DoesNotClearExternalCooldown(t *testing.T) {
 	require.NotNil(t, stats)
 	require.True(t, stats.CooldownUntil.Equal(existing))
 }
+
+func TestProber_RunOnce_SkipsWhenNotLeader(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "fail", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	prov := openai.New(
+		openai.WithBaseURL(server.URL),
+		openai.WithModels("gpt-4o"),
+	)
+	client, err := llmux.New(llmux.WithProviderInstance("openai", prov, []string{"gpt-4o"}))
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	prober := NewProber(
+		Config{Enabled: true, IsLeader: func() bool { return false }},
+		StaticClientProvider{Client: client},
+		nil,
+	)
+
+	prober.runOnce(context.Background())
+	require.Zero(t, calls.Load())
+}
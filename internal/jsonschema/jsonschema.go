@@ -0,0 +1,443 @@
+// Package jsonschema validates JSON documents against the subset of JSON
+// Schema used by structured outputs: types, enums, object properties, array
+// items, string, number and size bounds, combinators and local $refs.
+// Unsupported keywords are ignored.
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/goccy/go-json"
+)
+
+// maxProblems bounds the problems reported for one document.
+const maxProblems = 10
+
+// ValidationError lists why a document does not match a schema.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "document does not match schema: " + strings.Join(e.Problems, "; ")
+}
+
+// Schema is a parsed JSON schema.
+type Schema struct {
+	root any
+
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+// Compile parses a JSON schema.
+func Compile(raw []byte) (*Schema, error) {
+	var root any
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	switch root.(type) {
+	case map[string]any, bool:
+	default:
+		return nil, fmt.Errorf("invalid schema: must be an object or boolean")
+	}
+	return &Schema{root: root, patterns: make(map[string]*regexp.Regexp)}, nil
+}
+
+// Validate checks that data is a JSON document matching the schema. It
+// returns a *ValidationError when the document is invalid JSON or does not
+// match.
+func (s *Schema) Validate(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return &ValidationError{Problems: []string{"invalid JSON: " + err.Error()}}
+	}
+	if dec.More() {
+		return &ValidationError{Problems: []string{"invalid JSON: trailing data"}}
+	}
+	v := &validator{schema: s}
+	v.validate(s.root, doc, "$", 0)
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// maxDepth bounds $ref recursion.
+const maxDepth = 64
+
+type validator struct {
+	schema   *Schema
+	problems []string
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	if len(v.problems) < maxProblems {
+		v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+	}
+}
+
+// matches reports whether doc matches schema without recording problems.
+func (v *validator) matches(schema, doc any, path string, depth int) bool {
+	sub := &validator{schema: v.schema}
+	sub.validate(schema, doc, path, depth)
+	return len(sub.problems) == 0
+}
+
+func (v *validator) validate(schema, doc any, path string, depth int) {
+	if depth > maxDepth {
+		v.fail(path, "schema nesting too deep")
+		return
+	}
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			v.fail(path, "no value is allowed")
+		}
+		return
+	case map[string]any:
+		v.validateObject(s, doc, path, depth)
+	}
+}
+
+func (v *validator) validateObject(s map[string]any, doc any, path string, depth int) {
+	if ref, ok := s["$ref"].(string); ok {
+		target, err := v.resolve(ref)
+		if err != nil {
+			v.fail(path, "%v", err)
+			return
+		}
+		v.validate(target, doc, path, depth+1)
+	}
+
+	if t, ok := s["type"]; ok && !matchesType(t, doc) {
+		v.fail(path, "expected %s, got %s", typeNames(t), jsonType(doc))
+		return
+	}
+	if enum, ok := s["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if equal(e, doc) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(path, "value is not one of the allowed values")
+		}
+	}
+	if c, ok := s["const"]; ok && !equal(c, doc) {
+		v.fail(path, "value does not equal the constant")
+	}
+
+	switch d := doc.(type) {
+	case map[string]any:
+		v.validateProperties(s, d, path, depth)
+	case []any:
+		v.validateItems(s, d, path, depth)
+	case string:
+		v.validateString(s, d, path)
+	case json.Number:
+		v.validateNumber(s, d, path)
+	}
+
+	if all, ok := s["allOf"].([]any); ok {
+		for _, sub := range all {
+			v.validate(sub, doc, path, depth+1)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if v.matches(sub, doc, path, depth+1) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "value does not match any allowed schema")
+		}
+	}
+	if oneOf, ok := s["oneOf"].([]any); ok {
+		n := 0
+		for _, sub := range oneOf {
+			if v.matches(sub, doc, path, depth+1) {
+				n++
+			}
+		}
+		if n != 1 {
+			v.fail(path, "value matches %d schemas, expected exactly one", n)
+		}
+	}
+	if not, ok := s["not"]; ok && v.matches(not, doc, path, depth+1) {
+		v.fail(path, "value matches a disallowed schema")
+	}
+}
+
+func (v *validator) validateProperties(s map[string]any, doc map[string]any, path string, depth int) {
+	if required, ok := s["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, present := doc[name]; !present {
+				v.fail(path, "missing required property %q", name)
+			}
+		}
+	}
+	props, _ := s["properties"].(map[string]any)
+	additional, hasAdditional := s["additionalProperties"]
+
+	names := make([]string, 0, len(doc))
+	for name := range doc {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		childPath := path + "." + name
+		if sub, ok := props[name]; ok {
+			v.validate(sub, doc[name], childPath, depth+1)
+		} else if hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				v.fail(path, "unexpected property %q", name)
+			} else {
+				v.validate(additional, doc[name], childPath, depth+1)
+			}
+		}
+	}
+	if n, ok := intKeyword(s, "minProperties"); ok && len(doc) < n {
+		v.fail(path, "expected at least %d properties", n)
+	}
+	if n, ok := intKeyword(s, "maxProperties"); ok && len(doc) > n {
+		v.fail(path, "expected at most %d properties", n)
+	}
+}
+
+func (v *validator) validateItems(s map[string]any, doc []any, path string, depth int) {
+	if items, ok := s["items"]; ok {
+		for i, item := range doc {
+			v.validate(items, item, path+"["+strconv.Itoa(i)+"]", depth+1)
+		}
+	}
+	if n, ok := intKeyword(s, "minItems"); ok && len(doc) < n {
+		v.fail(path, "expected at least %d items", n)
+	}
+	if n, ok := intKeyword(s, "maxItems"); ok && len(doc) > n {
+		v.fail(path, "expected at most %d items", n)
+	}
+	if unique, _ := s["uniqueItems"].(bool); unique {
+		for i := range doc {
+			for j := i + 1; j < len(doc); j++ {
+				if equal(doc[i], doc[j]) {
+					v.fail(path, "items %d and %d are equal", i, j)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) validateString(s map[string]any, doc, path string) {
+	length := utf8.RuneCountInString(doc)
+	if n, ok := intKeyword(s, "minLength"); ok && length < n {
+		v.fail(path, "expected at least %d characters", n)
+	}
+	if n, ok := intKeyword(s, "maxLength"); ok && length > n {
+		v.fail(path, "expected at most %d characters", n)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		re, err := v.schema.pattern(pattern)
+		if err != nil {
+			v.fail(path, "invalid pattern %q", pattern)
+		} else if !re.MatchString(doc) {
+			v.fail(path, "does not match pattern %q", pattern)
+		}
+	}
+}
+
+func (v *validator) validateNumber(s map[string]any, doc json.Number, path string) {
+	n, err := doc.Float64()
+	if err != nil {
+		v.fail(path, "invalid number")
+		return
+	}
+	if m, ok := numberKeyword(s, "minimum"); ok && n < m {
+		v.fail(path, "must be >= %v", m)
+	}
+	if m, ok := numberKeyword(s, "maximum"); ok && n > m {
+		v.fail(path, "must be <= %v", m)
+	}
+	if m, ok := numberKeyword(s, "exclusiveMinimum"); ok && n <= m {
+		v.fail(path, "must be > %v", m)
+	}
+	if m, ok := numberKeyword(s, "exclusiveMaximum"); ok && n >= m {
+		v.fail(path, "must be < %v", m)
+	}
+	if m, ok := numberKeyword(s, "multipleOf"); ok && m > 0 {
+		if q := n / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.fail(path, "must be a multiple of %v", m)
+		}
+	}
+}
+
+// resolve follows a local reference such as "#/$defs/item".
+func (v *validator) resolve(ref string) (any, error) {
+	if ref == "#" {
+		return v.schema.root, nil
+	}
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	node := v.schema.root
+	for _, token := range strings.Split(pointer, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		obj, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		if node, ok = obj[token]; !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+	}
+	return node, nil
+}
+
+func (s *Schema) pattern(expr string) (*regexp.Regexp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if re, ok := s.patterns[expr]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	s.patterns[expr] = re
+	return re, nil
+}
+
+func matchesType(t, doc any) bool {
+	switch t := t.(type) {
+	case string:
+		return isType(t, doc)
+	case []any:
+		for _, name := range t {
+			if s, ok := name.(string); ok && isType(s, doc) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func isType(name string, doc any) bool {
+	switch name {
+	case "integer":
+		n, ok := doc.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	case "number":
+		_, ok := doc.(json.Number)
+		return ok
+	default:
+		return jsonType(doc) == name
+	}
+}
+
+func jsonType(doc any) string {
+	switch doc.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "unknown"
+}
+
+func typeNames(t any) string {
+	if list, ok := t.([]any); ok {
+		names := make([]string, 0, len(list))
+		for _, n := range list {
+			names = append(names, fmt.Sprint(n))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+func intKeyword(s map[string]any, key string) (int, bool) {
+	f, ok := numberKeyword(s, key)
+	return int(f), ok
+}
+
+func numberKeyword(s map[string]any, key string) (float64, bool) {
+	f, ok := s[key].(float64)
+	return f, ok
+}
+
+// equal compares two decoded JSON values; numbers compare by value.
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number, float64:
+		x, okA := toFloat(a)
+		y, okB := toFloat(b)
+		return okA && okB && x == y
+	case []any:
+		bs, ok := b.([]any)
+		if !ok || len(a) != len(bs) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], bs[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		bm, ok := b.(map[string]any)
+		if !ok || len(a) != len(bm) {
+			return false
+		}
+		for k, av := range a {
+			bv, present := bm[k]
+			if !present || !equal(av, bv) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
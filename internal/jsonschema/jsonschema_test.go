@@ -0,0 +1,95 @@
+package jsonschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const personSchema = `{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"age": {"type": "integer", "minimum": 0},
+		"role": {"enum": ["admin", "user"]},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+		"address": {"$ref": "#/$defs/address"},
+		"nickname": {"anyOf": [{"type": "string"}, {"type": "null"}]}
+	},
+	"required": ["name", "age"],
+	"additionalProperties": false,
+	"$defs": {
+		"address": {
+			"type": "object",
+			"properties": {"zip": {"type": "string", "pattern": "^[0-9]{5}$"}},
+			"required": ["zip"]
+		}
+	}
+}`
+
+func TestSchemaValidate(t *testing.T) {
+	schema, err := Compile([]byte(personSchema))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		doc     string
+		problem string
+	}{
+		{name: "valid", doc: `{"name":"Ada","age":36,"role":"admin","tags":["a"],"address":{"zip":"12345"},"nickname":null}`},
+		{name: "invalid json", doc: `{"name":`, problem: "invalid JSON"},
+		{name: "trailing data", doc: `{"name":"Ada","age":1} {}`, problem: "trailing data"},
+		{name: "missing required", doc: `{"name":"Ada"}`, problem: `$: missing required property "age"`},
+		{name: "wrong type", doc: `{"name":"Ada","age":"old"}`, problem: "$.age: expected integer, got string"},
+		{name: "not an integer", doc: `{"name":"Ada","age":1.5}`, problem: "$.age: expected integer"},
+		{name: "below minimum", doc: `{"name":"Ada","age":-1}`, problem: "$.age: must be >= 0"},
+		{name: "enum", doc: `{"name":"Ada","age":1,"role":"root"}`, problem: "$.role: value is not one of the allowed values"},
+		{name: "additional property", doc: `{"name":"Ada","age":1,"extra":true}`, problem: `unexpected property "extra"`},
+		{name: "items", doc: `{"name":"Ada","age":1,"tags":[1]}`, problem: "$.tags[0]: expected string"},
+		{name: "max items", doc: `{"name":"Ada","age":1,"tags":["a","b","c"]}`, problem: "$.tags: expected at most 2 items"},
+		{name: "ref", doc: `{"name":"Ada","age":1,"address":{"zip":"abc"}}`, problem: "$.address.zip: does not match pattern"},
+		{name: "any of", doc: `{"name":"Ada","age":1,"nickname":3}`, problem: "$.nickname: value does not match any allowed schema"},
+		{name: "min length", doc: `{"name":"","age":1}`, problem: "$.name: expected at least 1 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.doc))
+			if tt.problem == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate error = %v, want *ValidationError", err)
+			}
+			if !strings.Contains(verr.Error(), tt.problem) {
+				t.Fatalf("Validate error = %q, want it to contain %q", verr.Error(), tt.problem)
+			}
+		})
+	}
+}
+
+func TestCompileRejectsInvalidSchema(t *testing.T) {
+	for _, raw := range []string{`not json`, `"string"`, `[]`} {
+		if _, err := Compile([]byte(raw)); err == nil {
+			t.Fatalf("Compile(%s) succeeded", raw)
+		}
+	}
+}
+
+func TestSchemaValidate_BooleanSchemas(t *testing.T) {
+	schema, err := Compile([]byte(`{"properties": {"a": true, "b": false}}`))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if err := schema.Validate([]byte(`{"a": [1, 2]}`)); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if err := schema.Validate([]byte(`{"b": 1}`)); err == nil {
+		t.Fatal("expected property b to be rejected")
+	}
+}
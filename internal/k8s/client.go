@@ -0,0 +1,171 @@
+// Package k8s integrates the gateway with Kubernetes without client-go: it
+// elects a leader through a coordination.k8s.io Lease and watches mounted
+// ConfigMaps and Secrets for changes.
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// Default in-cluster service account paths.
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	tokenFile         = serviceAccountDir + "/token"
+	caFile            = serviceAccountDir + "/ca.crt"
+	namespaceFile     = serviceAccountDir + "/namespace"
+)
+
+// ErrNotInCluster is returned by InClusterConfig outside a pod.
+var ErrNotInCluster = errors.New("k8s: not running in a cluster (KUBERNETES_SERVICE_HOST is unset)")
+
+// Config holds how to reach the Kubernetes API server.
+type Config struct {
+	Host      string // API server URL, e.g. https://10.0.0.1:443
+	TokenFile string // Bearer token file, re-read on every request (optional)
+	CAFile    string // CA bundle for the API server (optional)
+	Namespace string // Namespace of the running pod
+	Timeout   time.Duration
+}
+
+// InClusterConfig returns the config of the pod's service account.
+func InClusterConfig() (Config, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return Config{}, ErrNotInCluster
+	}
+	ns, err := os.ReadFile(namespaceFile)
+	if err != nil {
+		return Config{}, fmt.Errorf("k8s: read namespace: %w", err)
+	}
+	return Config{
+		Host:      "https://" + net.JoinHostPort(host, port),
+		TokenFile: tokenFile,
+		CAFile:    caFile,
+		Namespace: strings.TrimSpace(string(ns)),
+	}, nil
+}
+
+// Client is a minimal JSON client for the Kubernetes API.
+type Client struct {
+	cfg        Config
+	httpClient *http.Client
+}
+
+// NewClient creates a client for cfg.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("k8s: host is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	cfg.Host = strings.TrimRight(cfg.Host, "/")
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.CAFile != "" {
+		// #nosec G304 -- the CA path comes from the service account mount.
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("k8s: read ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("k8s: no certificates in ca file")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &Client{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: cfg.Timeout, Transport: transport},
+	}, nil
+}
+
+// Namespace returns the configured namespace.
+func (c *Client) Namespace() string {
+	return c.cfg.Namespace
+}
+
+// StatusError is a non-2xx response from the API server.
+type StatusError struct {
+	StatusCode int
+	Reason     string `json:"reason"`
+	Message    string `json:"message"`
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("k8s: %d %s: %s", e.StatusCode, e.Reason, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 from the API server.
+func IsNotFound(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.StatusCode == http.StatusNotFound
+}
+
+// IsConflict reports whether err is a 409 from the API server.
+func IsConflict(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.StatusCode == http.StatusConflict
+}
+
+// do sends a JSON request to path and decodes the response into out.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader = http.NoBody
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.cfg.Host+path, body)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.cfg.TokenFile != "" {
+		// Projected tokens rotate, so the file is read for every request.
+		token, err := os.ReadFile(c.cfg.TokenFile)
+		if err != nil {
+			return fmt.Errorf("k8s: read token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		se := &StatusError{StatusCode: resp.StatusCode}
+		_ = json.Unmarshal(data, se)
+		return se
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,259 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultLeaseDuration = 15 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+
+	// microTimeFormat is the wire format of metav1.MicroTime.
+	microTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// LeaderElectionConfig configures Lease-based leader election.
+type LeaderElectionConfig struct {
+	LeaseName     string
+	Namespace     string        // Default: the client's namespace
+	Identity      string        // Unique per replica, e.g. the pod name
+	LeaseDuration time.Duration // How long a lease is valid without renewal (default: 15s)
+	RetryPeriod   time.Duration // How often to acquire or renew (default: 2s)
+}
+
+type lease struct {
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Metadata   leaseMetadata `json:"metadata"`
+	Spec       leaseSpec     `json:"spec"`
+}
+
+type leaseMetadata struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+type leaseSpec struct {
+	HolderIdentity       string `json:"holderIdentity,omitempty"`
+	LeaseDurationSeconds int    `json:"leaseDurationSeconds,omitempty"`
+	AcquireTime          string `json:"acquireTime,omitempty"`
+	RenewTime            string `json:"renewTime,omitempty"`
+	LeaseTransitions     int    `json:"leaseTransitions,omitempty"`
+}
+
+// LeaderElector holds a coordination.k8s.io/v1 Lease while it is the
+// leader. Expiry of another holder's lease is judged by the local clock
+// from when its record last changed, so replicas need not agree on time.
+type LeaderElector struct {
+	client *Client
+	cfg    LeaderElectionConfig
+	logger *slog.Logger
+	now    func() time.Time
+
+	leader atomic.Bool
+
+	// Used only by the Run goroutine.
+	lastRenew  time.Time
+	observed   leaseSpec
+	observedAt time.Time
+}
+
+// NewLeaderElector creates a leader elector. Call Run to take part.
+func NewLeaderElector(client *Client, cfg LeaderElectionConfig, logger *slog.Logger) (*LeaderElector, error) {
+	if client == nil {
+		return nil, errors.New("k8s: client is required")
+	}
+	if cfg.LeaseName == "" {
+		return nil, errors.New("k8s: lease name is required")
+	}
+	if cfg.Identity == "" {
+		return nil, errors.New("k8s: identity is required")
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = client.Namespace()
+	}
+	if cfg.Namespace == "" {
+		return nil, errors.New("k8s: namespace is required")
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = defaultLeaseDuration
+	}
+	if cfg.RetryPeriod <= 0 {
+		cfg.RetryPeriod = defaultRetryPeriod
+	}
+	if cfg.RetryPeriod >= cfg.LeaseDuration {
+		return nil, errors.New("k8s: retry period must be shorter than the lease duration")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &LeaderElector{client: client, cfg: cfg, logger: logger, now: time.Now}, nil
+}
+
+// IsLeader reports whether this replica currently holds the lease.
+func (e *LeaderElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run acquires and renews the lease every retry period until ctx is
+// canceled, then releases it if held.
+func (e *LeaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.RetryPeriod)
+	defer ticker.Stop()
+
+	for {
+		e.step(ctx)
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// step makes one acquire or renew attempt and updates leadership.
+func (e *LeaderElector) step(ctx context.Context) {
+	ok, err := e.tryAcquireOrRenew(ctx)
+	now := e.now()
+	switch {
+	case ok:
+		e.lastRenew = now
+		e.setLeader(true)
+	case err == nil:
+		// Another replica holds the lease.
+		e.setLeader(false)
+	case e.leader.Load() && now.Sub(e.lastRenew) >= e.renewDeadline():
+		e.logger.Warn("failed to renew leader lease", "lease", e.cfg.LeaseName, "error", err)
+		e.setLeader(false)
+	default:
+		if ctx.Err() == nil {
+			e.logger.Warn("leader election attempt failed", "lease", e.cfg.LeaseName, "error", err)
+		}
+	}
+}
+
+// renewDeadline is how long a leader keeps leading without a successful
+// renewal. It is shorter than the lease so it steps down before another
+// replica can take over.
+func (e *LeaderElector) renewDeadline() time.Duration {
+	return e.cfg.LeaseDuration * 2 / 3
+}
+
+func (e *LeaderElector) setLeader(leader bool) {
+	if e.leader.Swap(leader) == leader {
+		return
+	}
+	if leader {
+		e.logger.Info("became leader", "lease", e.cfg.LeaseName, "identity", e.cfg.Identity)
+	} else {
+		e.logger.Info("lost leadership", "lease", e.cfg.LeaseName, "identity", e.cfg.Identity)
+	}
+}
+
+func (e *LeaderElector) collectionPath() string {
+	return fmt.Sprintf("/apis/coordination.k8s.io/v1/namespaces/%s/leases", e.cfg.Namespace)
+}
+
+func (e *LeaderElector) leasePath() string {
+	return e.collectionPath() + "/" + e.cfg.LeaseName
+}
+
+// tryAcquireOrRenew returns true when this replica holds the lease after
+// the call. A lease held by another replica or a lost write race is
+// reported as false with a nil error.
+func (e *LeaderElector) tryAcquireOrRenew(ctx context.Context) (bool, error) {
+	now := e.now()
+	stamp := now.UTC().Format(microTimeFormat)
+	seconds := int(e.cfg.LeaseDuration / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	var current lease
+	err := e.client.do(ctx, http.MethodGet, e.leasePath(), nil, &current)
+	if IsNotFound(err) {
+		created := lease{
+			APIVersion: "coordination.k8s.io/v1",
+			Kind:       "Lease",
+			Metadata:   leaseMetadata{Name: e.cfg.LeaseName, Namespace: e.cfg.Namespace},
+			Spec: leaseSpec{
+				HolderIdentity:       e.cfg.Identity,
+				LeaseDurationSeconds: seconds,
+				AcquireTime:          stamp,
+				RenewTime:            stamp,
+			},
+		}
+		if err := e.client.do(ctx, http.MethodPost, e.collectionPath(), created, nil); err != nil {
+			if IsConflict(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		e.observed, e.observedAt = created.Spec, now
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if current.Spec != e.observed {
+		e.observed, e.observedAt = current.Spec, now
+	}
+	holder := current.Spec.HolderIdentity
+	held := time.Duration(current.Spec.LeaseDurationSeconds) * time.Second
+	if holder != "" && holder != e.cfg.Identity && e.observedAt.Add(held).After(now) {
+		return false, nil
+	}
+
+	spec := current.Spec
+	if holder != e.cfg.Identity {
+		spec.AcquireTime = stamp
+		spec.LeaseTransitions++
+	}
+	spec.HolderIdentity = e.cfg.Identity
+	spec.LeaseDurationSeconds = seconds
+	spec.RenewTime = stamp
+	current.Spec = spec
+	if err := e.client.do(ctx, http.MethodPut, e.leasePath(), current, nil); err != nil {
+		if IsConflict(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	e.observed, e.observedAt = spec, now
+	return true, nil
+}
+
+// release gives up a held lease so another replica can take over without
+// waiting for it to expire.
+func (e *LeaderElector) release() {
+	if !e.leader.Load() {
+		return
+	}
+	e.setLeader(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.RetryPeriod)
+	defer cancel()
+	var current lease
+	if err := e.client.do(ctx, http.MethodGet, e.leasePath(), nil, &current); err != nil {
+		e.logger.Warn("failed to release leader lease", "lease", e.cfg.LeaseName, "error", err)
+		return
+	}
+	if current.Spec.HolderIdentity != e.cfg.Identity {
+		return
+	}
+	current.Spec.HolderIdentity = ""
+	current.Spec.LeaseDurationSeconds = 1
+	current.Spec.RenewTime = e.now().UTC().Format(microTimeFormat)
+	if err := e.client.do(ctx, http.MethodPut, e.leasePath(), current, nil); err != nil {
+		e.logger.Warn("failed to release leader lease", "lease", e.cfg.LeaseName, "error", err)
+	}
+}
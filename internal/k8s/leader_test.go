@@ -0,0 +1,185 @@
+package k8s
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLeases is an in-memory Lease API with resourceVersion checks.
+type fakeLeases struct {
+	t       *testing.T
+	mu      sync.Mutex
+	leases  map[string]lease
+	version int
+}
+
+func (f *fakeLeases) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	require.Equal(f.t, "Bearer test-token", r.Header.Get("Authorization"))
+	const prefix = "/apis/coordination.k8s.io/v1/namespaces/llmux/leases"
+	require.True(f.t, strings.HasPrefix(r.URL.Path, prefix), r.URL.Path)
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	writeStatus := func(code int, reason string) {
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(`{"kind":"Status","reason":"` + reason + `","message":"` + reason + `"}`))
+	}
+
+	var in lease
+	if r.Method != http.MethodGet {
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(f.t, json.Unmarshal(body, &in))
+	}
+	switch r.Method {
+	case http.MethodGet:
+		l, ok := f.leases[name]
+		if !ok {
+			writeStatus(http.StatusNotFound, "NotFound")
+			return
+		}
+		_ = json.NewEncoder(w).Encode(l)
+	case http.MethodPost:
+		if _, ok := f.leases[in.Metadata.Name]; ok {
+			writeStatus(http.StatusConflict, "AlreadyExists")
+			return
+		}
+		f.store(in)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodPut:
+		if f.leases[name].Metadata.ResourceVersion != in.Metadata.ResourceVersion {
+			writeStatus(http.StatusConflict, "Conflict")
+			return
+		}
+		f.store(in)
+	}
+}
+
+func (f *fakeLeases) store(l lease) {
+	f.version++
+	l.Metadata.ResourceVersion = strconv.Itoa(f.version)
+	f.leases[l.Metadata.Name] = l
+}
+
+func (f *fakeLeases) holder() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.leases["llmux-leader"].Spec.HolderIdentity
+}
+
+func newTestElector(t *testing.T, server *httptest.Server, identity string, now *time.Time) *LeaderElector {
+	t.Helper()
+	tokenFile := t.TempDir() + "/token"
+	require.NoError(t, writeFile(tokenFile, "test-token\n"))
+	client, err := NewClient(Config{Host: server.URL, TokenFile: tokenFile, Namespace: "llmux"})
+	require.NoError(t, err)
+	e, err := NewLeaderElector(client, LeaderElectionConfig{
+		LeaseName:     "llmux-leader",
+		Identity:      identity,
+		LeaseDuration: 15 * time.Second,
+		RetryPeriod:   2 * time.Second,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	e.now = func() time.Time { return *now }
+	return e
+}
+
+func TestLeaderElector(t *testing.T) {
+	fake := &fakeLeases{t: t, leases: map[string]lease{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	now := time.Now()
+	a := newTestElector(t, server, "pod-a", &now)
+	b := newTestElector(t, server, "pod-b", &now)
+	ctx := context.Background()
+
+	// The first replica creates and holds the lease.
+	a.step(ctx)
+	require.True(t, a.IsLeader())
+	b.step(ctx)
+	require.False(t, b.IsLeader())
+	require.Equal(t, "pod-a", fake.holder())
+
+	// Renewals keep it; the follower waits while the record keeps changing.
+	for i := 0; i < 10; i++ {
+		now = now.Add(2 * time.Second)
+		a.step(ctx)
+		b.step(ctx)
+	}
+	require.True(t, a.IsLeader())
+	require.False(t, b.IsLeader())
+
+	// Once the leader stops renewing, the lease expires and is taken over.
+	now = now.Add(10 * time.Second)
+	b.step(ctx)
+	require.False(t, b.IsLeader(), "lease not yet expired")
+	now = now.Add(6 * time.Second)
+	b.step(ctx)
+	require.True(t, b.IsLeader())
+	require.Equal(t, "pod-b", fake.holder())
+	require.Equal(t, 1, fake.leases["llmux-leader"].Spec.LeaseTransitions)
+
+	// The old leader steps down on its next attempt.
+	a.step(ctx)
+	require.False(t, a.IsLeader())
+
+	// Releasing lets the other replica take over immediately.
+	b.release()
+	require.False(t, b.IsLeader())
+	require.Equal(t, "", fake.holder())
+	a.step(ctx)
+	require.True(t, a.IsLeader())
+}
+
+func TestLeaderElector_StepsDownWhenRenewalsFail(t *testing.T) {
+	fake := &fakeLeases{t: t, leases: map[string]lease{}}
+	var down bool
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		failing := down
+		mu.Unlock()
+		if failing {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	now := time.Now()
+	e := newTestElector(t, server, "pod-a", &now)
+	e.step(context.Background())
+	require.True(t, e.IsLeader())
+
+	mu.Lock()
+	down = true
+	mu.Unlock()
+	now = now.Add(5 * time.Second)
+	e.step(context.Background())
+	require.True(t, e.IsLeader(), "within the renew deadline")
+	now = now.Add(6 * time.Second)
+	e.step(context.Background())
+	require.False(t, e.IsLeader())
+}
+
+func TestNewLeaderElector_Validates(t *testing.T) {
+	client, err := NewClient(Config{Host: "http://localhost", Namespace: "llmux"})
+	require.NoError(t, err)
+	_, err = NewLeaderElector(client, LeaderElectionConfig{LeaseName: "l"}, nil)
+	require.Error(t, err)
+	_, err = NewLeaderElector(client, LeaderElectionConfig{LeaseName: "l", Identity: "a", LeaseDuration: time.Second, RetryPeriod: 2 * time.Second}, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WatchFiles polls paths every interval and calls onChange when the
+// content of any of them changes. A path may be a file or a directory;
+// a directory covers its files, like a mounted ConfigMap or Secret.
+//
+// Kubernetes updates mounts by swapping a ..data symlink, which an
+// fsnotify watch on the old target misses, so contents are compared
+// instead. The initial state is read before WatchFiles returns.
+func WatchFiles(ctx context.Context, paths []string, interval time.Duration, logger *slog.Logger, onChange func()) error {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	last, err := fingerprint(paths)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			sum, err := fingerprint(paths)
+			if err != nil {
+				// Mid-update states can be briefly unreadable; retry next tick.
+				logger.Warn("failed to read watched files", "error", err)
+				continue
+			}
+			if sum == last {
+				continue
+			}
+			last = sum
+			onChange()
+		}
+	}()
+	return nil
+}
+
+// fingerprint hashes the names and contents of paths.
+func fingerprint(paths []string) ([sha256.Size]byte, error) {
+	h := sha256.New()
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return [sha256.Size]byte{}, err
+		}
+		files := []string{path}
+		if info.IsDir() {
+			if files, err = dirFiles(path); err != nil {
+				return [sha256.Size]byte{}, err
+			}
+		}
+		for _, file := range files {
+			// #nosec G304 -- paths are operator-configured mounts.
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return [sha256.Size]byte{}, err
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00", file, len(data))
+			h.Write(data)
+		}
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
+// dirFiles lists the regular files of dir, following symlinks and skipping
+// the ..data style entries of a Kubernetes volume.
+func dirFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "..") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
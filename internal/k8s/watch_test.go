@@ -0,0 +1,65 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(path, content string) error {
+	return os.WriteFile(path, []byte(content), 0o600)
+}
+
+// swapMount mimics how the kubelet updates a mounted volume: the new data
+// is written to a fresh directory and the ..data symlink is replaced.
+func swapMount(t *testing.T, dir, version string, files map[string]string) {
+	t.Helper()
+	dataDir := filepath.Join(dir, "..data_"+version)
+	require.NoError(t, os.Mkdir(dataDir, 0o700))
+	for name, content := range files {
+		require.NoError(t, writeFile(filepath.Join(dataDir, name), content))
+	}
+	tmp := filepath.Join(dir, "..data_tmp")
+	require.NoError(t, os.Symlink(filepath.Base(dataDir), tmp))
+	require.NoError(t, os.Rename(tmp, filepath.Join(dir, "..data")))
+	for name := range files {
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); os.IsNotExist(err) {
+			require.NoError(t, os.Symlink(filepath.Join("..data", name), link))
+		}
+	}
+}
+
+func TestWatchFiles_DetectsMountSwaps(t *testing.T) {
+	dir := t.TempDir()
+	swapMount(t, dir, "1", map[string]string{"openai": "sk-1"})
+
+	changes := make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, WatchFiles(ctx, []string{dir, filepath.Join(dir, "openai")}, 10*time.Millisecond, nil, func() {
+		changes <- struct{}{}
+	}))
+
+	select {
+	case <-changes:
+		t.Fatal("unexpected change before an update")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	swapMount(t, dir, "2", map[string]string{"openai": "sk-2"})
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("mount swap was not detected")
+	}
+}
+
+func TestWatchFiles_MissingPath(t *testing.T) {
+	err := WatchFiles(context.Background(), []string{filepath.Join(t.TempDir(), "missing")}, time.Second, nil, func() {})
+	require.Error(t, err)
+}
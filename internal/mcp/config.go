@@ -46,6 +46,10 @@ type ClientConfig struct {
 	// Headers are HTTP headers for HTTP/SSE connections.
 	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
 
+	// OAuth obtains an access token for HTTP/SSE connections and injects it
+	// into every request, refreshing it before it expires.
+	OAuth *OAuthConfig `yaml:"oauth,omitempty" json:"oauth,omitempty"`
+
 	// ToolsToExecute defines which tools to expose from this client.
 	// Semantics:
 	//   - nil or omitted: no tools exposed (safe default)
@@ -61,6 +65,35 @@ type ClientConfig struct {
 	ExecutionTimeout time.Duration `yaml:"execution_timeout,omitempty" json:"execution_timeout,omitempty"`
 }
 
+// OAuthConfig defines OAuth2 credentials for an MCP client. ClientSecret and
+// RefreshToken may be secret references (e.g. "vault://secret/data/github")
+// resolved through the manager's SecretResolver on every (re)connect.
+type OAuthConfig struct {
+	// TokenURL is the authorization server's token endpoint.
+	TokenURL string `yaml:"token_url" json:"token_url"`
+
+	// ClientID is the OAuth2 client identifier.
+	ClientID string `yaml:"client_id" json:"client_id"`
+
+	// ClientSecret is the OAuth2 client secret.
+	ClientSecret string `yaml:"client_secret,omitempty" json:"client_secret,omitempty"`
+
+	// RefreshToken selects the refresh_token grant. Without it the
+	// client_credentials grant is used.
+	RefreshToken string `yaml:"refresh_token,omitempty" json:"refresh_token,omitempty"`
+
+	// Scopes are the requested scopes.
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+
+	// Audience is sent as the "audience" parameter of client_credentials
+	// token requests when set.
+	Audience string `yaml:"audience,omitempty" json:"audience,omitempty"`
+
+	// Header is the header carrying the token (default: Authorization, as
+	// "Bearer <token>"). Other headers receive the bare token.
+	Header string `yaml:"header,omitempty" json:"header,omitempty"`
+}
+
 // DefaultConfig returns the default MCP configuration.
 func DefaultConfig() Config {
 	return Config{
@@ -117,6 +150,15 @@ func (c *ClientConfig) Validate() error {
 		return fmt.Errorf("unknown connection type: %s", c.Type)
 	}
 
+	if c.OAuth != nil {
+		if c.Type != ConnectionTypeHTTP && c.Type != ConnectionTypeSSE {
+			return fmt.Errorf("oauth is only supported for http and sse connections")
+		}
+		if err := c.OAuth.Validate(); err != nil {
+			return fmt.Errorf("oauth: %w", err)
+		}
+	}
+
 	if c.ConnectionTimeout < 0 {
 		return fmt.Errorf("connection_timeout cannot be negative")
 	}
@@ -127,6 +169,20 @@ func (c *ClientConfig) Validate() error {
 	return nil
 }
 
+// Validate checks the OAuth configuration for errors.
+func (c *OAuthConfig) Validate() error {
+	if c.TokenURL == "" {
+		return fmt.Errorf("token_url is required")
+	}
+	if c.ClientID == "" {
+		return fmt.Errorf("client_id is required")
+	}
+	if c.RefreshToken == "" && c.ClientSecret == "" {
+		return fmt.Errorf("client_secret is required for the client_credentials grant")
+	}
+	return nil
+}
+
 // GetConnectionTimeout returns the effective connection timeout.
 func (c *ClientConfig) GetConnectionTimeout(defaultTimeout time.Duration) time.Duration {
 	if c.ConnectionTimeout > 0 {
@@ -42,6 +42,39 @@ func TestClientConfigValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid HTTP config with OAuth",
+			cfg: ClientConfig{
+				ID:    "test",
+				Name:  "Test",
+				Type:  ConnectionTypeHTTP,
+				URL:   "http://localhost:3000",
+				OAuth: &OAuthConfig{TokenURL: "http://localhost:4000/token", ClientID: "llmux", ClientSecret: "env://MCP_SECRET"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OAuth on STDIO config",
+			cfg: ClientConfig{
+				ID:      "test",
+				Name:    "Test",
+				Type:    ConnectionTypeSTDIO,
+				Command: "npx",
+				OAuth:   &OAuthConfig{TokenURL: "http://localhost:4000/token", ClientID: "llmux", ClientSecret: "x"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OAuth missing credentials",
+			cfg: ClientConfig{
+				ID:    "test",
+				Name:  "Test",
+				Type:  ConnectionTypeHTTP,
+				URL:   "http://localhost:3000",
+				OAuth: &OAuthConfig{TokenURL: "http://localhost:4000/token", ClientID: "llmux"},
+			},
+			wantErr: true,
+		},
 		{
 			name: "missing ID",
 			cfg: ClientConfig{
@@ -25,6 +25,17 @@ func FromConfig(cfg config.MCPConfig) Config {
 			ConnectionTimeout: c.ConnectionTimeout,
 			ExecutionTimeout:  c.ExecutionTimeout,
 		}
+		if c.OAuth != nil {
+			clients[i].OAuth = &OAuthConfig{
+				TokenURL:     c.OAuth.TokenURL,
+				ClientID:     c.OAuth.ClientID,
+				ClientSecret: c.OAuth.ClientSecret,
+				RefreshToken: c.OAuth.RefreshToken,
+				Scopes:       c.OAuth.Scopes,
+				Audience:     c.OAuth.Audience,
+				Header:       c.OAuth.Header,
+			}
+		}
 	}
 
 	connTimeout := cfg.DefaultConnectionTimeout
@@ -2,6 +2,8 @@ package mcp
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 
@@ -11,15 +13,30 @@ import (
 // SendFunc is a function type for sending chat requests.
 type SendFunc func(ctx context.Context, req *types.ChatRequest) (*types.ChatResponse, error)
 
+// ToolCallObserver is notified of every tool call the executor runs, after
+// it completed. It is used to audit and attribute tool usage.
+type ToolCallObserver func(ctx context.Context, call types.ToolCall, result ToolExecutionResult)
+
 // AgentExecutor handles the agentic loop for tool execution.
 type AgentExecutor struct {
 	manager       Manager
 	maxIterations int
 	logger        *slog.Logger
+	observer      ToolCallObserver
+}
+
+// ExecutorOption configures the AgentExecutor.
+type ExecutorOption func(*AgentExecutor)
+
+// WithToolCallObserver registers fn to be called for every executed tool.
+func WithToolCallObserver(fn ToolCallObserver) ExecutorOption {
+	return func(e *AgentExecutor) {
+		e.observer = fn
+	}
 }
 
 // NewAgentExecutor creates a new agent executor.
-func NewAgentExecutor(manager Manager, maxIterations int, logger *slog.Logger) *AgentExecutor {
+func NewAgentExecutor(manager Manager, maxIterations int, logger *slog.Logger, opts ...ExecutorOption) *AgentExecutor {
 	if maxIterations <= 0 {
 		maxIterations = MaxToolIterations
 	}
@@ -27,11 +44,15 @@ func NewAgentExecutor(manager Manager, maxIterations int, logger *slog.Logger) *
 		logger = slog.Default()
 	}
 
-	return &AgentExecutor{
+	e := &AgentExecutor{
 		manager:       manager,
 		maxIterations: maxIterations,
 		logger:        logger,
 	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
 }
 
 // Execute runs the agentic loop, executing tools until completion or max iterations.
@@ -68,7 +89,7 @@ func (e *AgentExecutor) Execute(ctx context.Context, req *types.ChatRequest, sen
 			"count", len(toolCalls),
 		)
 
-		results := e.manager.ExecuteToolCalls(ctx, toolCalls)
+		results := e.executeToolCalls(ctx, toolCalls)
 
 		// Append results to conversation
 		AppendToolResults(req, resp.Choices[0].Message, results)
@@ -85,7 +106,25 @@ func (e *AgentExecutor) ExecuteOnce(ctx context.Context, resp *types.ChatRespons
 	}
 
 	toolCalls := GetToolCalls(resp)
-	results := e.manager.ExecuteToolCalls(ctx, toolCalls)
+	results := e.executeToolCalls(ctx, toolCalls)
 
 	return results, true
 }
+
+func (e *AgentExecutor) executeToolCalls(ctx context.Context, toolCalls []types.ToolCall) []ToolExecutionResult {
+	results := e.manager.ExecuteToolCalls(ctx, toolCalls)
+	if e.observer != nil {
+		for i := range results {
+			e.observer(ctx, toolCalls[i], results[i])
+		}
+	}
+	return results
+}
+
+// ArgumentsHash returns a stable digest of tool call arguments, so audit
+// records can correlate calls without storing their (possibly sensitive)
+// arguments.
+func ArgumentsHash(arguments string) string {
+	sum := sha256.Sum256([]byte(arguments))
+	return hex.EncodeToString(sum[:])
+}
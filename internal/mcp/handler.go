@@ -21,9 +21,14 @@ func (h *HTTPHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /mcp/clients", h.ListClients)
 	mux.HandleFunc("GET /mcp/clients/{id}", h.GetClient)
 	mux.HandleFunc("POST /mcp/clients", h.AddClient)
+	mux.HandleFunc("POST /mcp/clients/test", h.TestClient)
+	mux.HandleFunc("PUT /mcp/clients/{id}", h.UpdateClient)
 	mux.HandleFunc("DELETE /mcp/clients/{id}", h.RemoveClient)
 	mux.HandleFunc("POST /mcp/clients/{id}/reconnect", h.ReconnectClient)
 	mux.HandleFunc("GET /mcp/tools", h.ListTools)
+	mux.HandleFunc("GET /mcp/resources", h.ListResources)
+	mux.HandleFunc("GET /mcp/resources/read", h.ReadResource)
+	mux.HandleFunc("GET /mcp/prompts", h.ListPrompts)
 }
 
 // ListClients handles GET /mcp/clients
@@ -76,6 +81,76 @@ func (h *HTTPHandler) AddClient(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// UpdateClient handles PUT /mcp/clients/{id}
+func (h *HTTPHandler) UpdateClient(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	var cfg ClientConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if cfg.ID == "" {
+		cfg.ID = id
+	}
+	if cfg.ID != id {
+		http.Error(w, "id in body does not match path", http.StatusBadRequest)
+		return
+	}
+	if err := cfg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, err := h.manager.GetClient(id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if err := h.manager.UpdateClient(cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"status": "updated",
+		"id":     id,
+	})
+}
+
+// TestClient handles POST /mcp/clients/test. The id and name may be
+// omitted as the client is not added.
+func (h *HTTPHandler) TestClient(w http.ResponseWriter, r *http.Request) {
+	var cfg ClientConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if cfg.ID == "" {
+		cfg.ID = "connection-test"
+	}
+	if cfg.Name == "" {
+		cfg.Name = cfg.ID
+	}
+	if err := cfg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.manager.TestClient(cfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
 // RemoveClient handles DELETE /mcp/clients/{id}
 func (h *HTTPHandler) RemoveClient(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
@@ -128,3 +203,54 @@ func (h *HTTPHandler) ListTools(w http.ResponseWriter, r *http.Request) {
 		"count": len(tools),
 	})
 }
+
+// ListResources handles GET /mcp/resources
+func (h *HTTPHandler) ListResources(w http.ResponseWriter, r *http.Request) {
+	resources := h.manager.ListResources(r.Context())
+	if resources == nil {
+		resources = []Resource{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"resources": resources,
+		"count":     len(resources),
+	})
+}
+
+// ReadResource handles GET /mcp/resources/read?client_id=...&uri=...
+func (h *HTTPHandler) ReadResource(w http.ResponseWriter, r *http.Request) {
+	clientID := r.URL.Query().Get("client_id")
+	uri := r.URL.Query().Get("uri")
+	if clientID == "" || uri == "" {
+		http.Error(w, "client_id and uri are required", http.StatusBadRequest)
+		return
+	}
+
+	contents, err := h.manager.ReadResource(r.Context(), clientID, uri)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"client_id": clientID,
+		"uri":       uri,
+		"contents":  contents,
+	})
+}
+
+// ListPrompts handles GET /mcp/prompts
+func (h *HTTPHandler) ListPrompts(w http.ResponseWriter, r *http.Request) {
+	prompts := h.manager.ListPrompts(r.Context())
+	if prompts == nil {
+		prompts = []Prompt{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"prompts": prompts,
+		"count":   len(prompts),
+	})
+}
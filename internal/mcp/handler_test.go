@@ -3,6 +3,7 @@ package mcp
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/goccy/go-json"
@@ -60,3 +61,89 @@ func TestHTTPHandlerRegisterRoutes(t *testing.T) {
 		t.Fatalf("tools = %v, want tool_one and tool_two", payload.Tools)
 	}
 }
+
+func TestHTTPHandlerResourcesAndPrompts(t *testing.T) {
+	manager := NewMockManager()
+	manager.AddMockResource(Resource{ClientID: "docs", URI: "docs://readme", Name: "README"},
+		ResourceContent{URI: "docs://readme", Text: "# LLMux"})
+	manager.AddMockPrompt(Prompt{ClientID: "docs", Name: "review"})
+
+	handler := NewHTTPHandler(manager)
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest("GET", target, nil))
+		return recorder
+	}
+
+	var resources struct {
+		Resources []Resource `json:"resources"`
+		Count     int        `json:"count"`
+	}
+	if err := json.NewDecoder(get("/mcp/resources").Body).Decode(&resources); err != nil || resources.Count != 1 {
+		t.Fatalf("resources = %+v, err = %v", resources, err)
+	}
+
+	var read struct {
+		Contents []ResourceContent `json:"contents"`
+	}
+	if err := json.NewDecoder(get("/mcp/resources/read?client_id=docs&uri=docs://readme").Body).Decode(&read); err != nil ||
+		len(read.Contents) != 1 || read.Contents[0].Text != "# LLMux" {
+		t.Fatalf("read = %+v, err = %v", read, err)
+	}
+	if code := get("/mcp/resources/read?client_id=docs").Code; code != http.StatusBadRequest {
+		t.Fatalf("read without uri status = %d, want 400", code)
+	}
+
+	var prompts struct {
+		Prompts []Prompt `json:"prompts"`
+	}
+	if err := json.NewDecoder(get("/mcp/prompts").Body).Decode(&prompts); err != nil ||
+		len(prompts.Prompts) != 1 || prompts.Prompts[0].Name != "review" {
+		t.Fatalf("prompts = %+v, err = %v", prompts, err)
+	}
+}
+
+func TestHTTPHandlerUpdateAndTestClient(t *testing.T) {
+	manager := NewMockManager()
+	manager.AddMockClient("client-1", "Client 1", ConnectionTypeHTTP, nil)
+
+	handler := NewHTTPHandler(manager)
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	send := func(method, target, body string) int {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return recorder.Code
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   int
+	}{
+		{"update", "PUT", "/mcp/clients/client-1", `{"name":"Renamed","type":"http","url":"http://localhost:3000"}`, http.StatusOK},
+		{"update unknown client", "PUT", "/mcp/clients/missing", `{"name":"Missing","type":"http","url":"http://localhost:3000"}`, http.StatusNotFound},
+		{"update id mismatch", "PUT", "/mcp/clients/client-1", `{"id":"other","name":"X","type":"http","url":"http://localhost:3000"}`, http.StatusBadRequest},
+		{"update invalid config", "PUT", "/mcp/clients/client-1", `{"name":"X","type":"http"}`, http.StatusBadRequest},
+		{"test", "POST", "/mcp/clients/test", `{"type":"http","url":"http://localhost:3000"}`, http.StatusOK},
+		{"test invalid config", "POST", "/mcp/clients/test", `{"type":"stdio"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := send(tt.method, tt.target, tt.body); got != tt.want {
+				t.Fatalf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	info, err := manager.GetClient("client-1")
+	if err != nil || info.Name != "Renamed" {
+		t.Fatalf("client after update = %+v, %v", info, err)
+	}
+}
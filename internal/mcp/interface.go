@@ -22,6 +22,21 @@ type Manager interface {
 	// ExecuteToolCalls executes multiple tool calls concurrently.
 	ExecuteToolCalls(ctx context.Context, toolCalls []types.ToolCall) []ToolExecutionResult
 
+	// ========== Resource and Prompt Operations ==========
+
+	// ListResources returns the resources of all connected MCP clients.
+	ListResources(ctx context.Context) []Resource
+
+	// ReadResource reads a resource from a specific client.
+	ReadResource(ctx context.Context, clientID, uri string) ([]ResourceContent, error)
+
+	// ListPrompts returns the prompts of all connected MCP clients.
+	ListPrompts(ctx context.Context) []Prompt
+
+	// GetPrompt renders a prompt, referenced as "clientID/name" or by bare
+	// name, into chat messages.
+	GetPrompt(ctx context.Context, name string, args map[string]string) ([]types.ChatMessage, error)
+
 	// ========== Client Management ==========
 
 	// AddClient adds a new MCP client with the given configuration.
@@ -30,9 +45,16 @@ type Manager interface {
 	// RemoveClient removes an MCP client by ID.
 	RemoveClient(id string) error
 
+	// UpdateClient replaces the configuration of an existing client.
+	UpdateClient(cfg ClientConfig) error
+
 	// ReconnectClient attempts to reconnect a disconnected client.
 	ReconnectClient(id string) error
 
+	// TestClient checks that a client configuration can connect, without
+	// adding it.
+	TestClient(cfg ClientConfig) (*ConnectionTestResult, error)
+
 	// GetClients returns information about all managed clients.
 	GetClients() []ClientInfo
 
@@ -19,10 +19,28 @@ type MCPManager struct {
 	mu      sync.RWMutex
 	config  Config
 	logger  *slog.Logger
+	secrets SecretResolver
+}
+
+// SecretResolver resolves secret references such as "env://NAME" or
+// "vault://path"; *secret.Manager implements it.
+type SecretResolver interface {
+	Get(ctx context.Context, ref string) (string, error)
+}
+
+// ManagerOption configures the MCPManager.
+type ManagerOption func(*MCPManager)
+
+// WithSecretResolver resolves the OAuth credentials of MCP clients through
+// resolver. Without it credentials are used as literal values.
+func WithSecretResolver(resolver SecretResolver) ManagerOption {
+	return func(m *MCPManager) {
+		m.secrets = resolver
+	}
 }
 
 // NewManager creates a new MCP manager instance.
-func NewManager(ctx context.Context, cfg Config, logger *slog.Logger) (*MCPManager, error) {
+func NewManager(ctx context.Context, cfg Config, logger *slog.Logger, opts ...ManagerOption) (*MCPManager, error) {
 	if logger == nil {
 		logger = slog.Default()
 	}
@@ -40,6 +58,9 @@ func NewManager(ctx context.Context, cfg Config, logger *slog.Logger) (*MCPManag
 		config:  cfg,
 		logger:  logger,
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
 
 	// Initialize configured clients
 	for i := range cfg.Clients {
@@ -167,6 +188,7 @@ func (m *MCPManager) ExecuteToolCalls(ctx context.Context, toolCalls []types.Too
 		go func(idx int, call types.ToolCall) {
 			defer wg.Done()
 
+			start := time.Now()
 			result, err := m.ExecuteToolCall(ctx, call)
 			if err != nil {
 				results[idx] = ToolExecutionResult{
@@ -174,6 +196,7 @@ func (m *MCPManager) ExecuteToolCalls(ctx context.Context, toolCalls []types.Too
 					ToolName:   call.Function.Name,
 					Content:    fmt.Sprintf("Error: %s", err.Error()),
 					IsError:    true,
+					Duration:   time.Since(start),
 				}
 			} else if result != nil {
 				results[idx] = *result
@@ -296,6 +319,86 @@ func (m *MCPManager) ReconnectClient(id string) error {
 	return nil
 }
 
+// UpdateClient replaces the configuration of an existing client. The new
+// configuration is connected before the old connection is closed, so a
+// failed update leaves the client untouched.
+func (m *MCPManager) UpdateClient(cfg ClientConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	m.mu.RLock()
+	old, exists := m.clients[cfg.ID]
+	m.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("client %q not found", cfg.ID)
+	}
+
+	client := &Client{
+		ID:     cfg.ID,
+		Name:   cfg.Name,
+		Config: cfg,
+		Tools:  make(map[string]types.Tool),
+	}
+	if err := m.connectClient(client); err != nil {
+		m.cleanupClient(client)
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+
+	m.mu.Lock()
+	if current := m.clients[cfg.ID]; current != old {
+		m.mu.Unlock()
+		m.cleanupClient(client)
+		return fmt.Errorf("client %q was modified concurrently", cfg.ID)
+	}
+	m.clients[cfg.ID] = client
+	m.cleanupClient(old)
+	m.mu.Unlock()
+
+	m.logger.Info(MCPLogPrefix+" client updated",
+		"id", cfg.ID,
+		"name", cfg.Name,
+		"type", cfg.Type,
+	)
+
+	return nil
+}
+
+// TestClient connects to the server described by cfg, lists its tools and
+// disconnects again, without adding the client.
+func (m *MCPManager) TestClient(cfg ClientConfig) (*ConnectionTestResult, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	client := &Client{
+		ID:     cfg.ID,
+		Name:   cfg.Name,
+		Config: cfg,
+		Tools:  make(map[string]types.Tool),
+	}
+	start := time.Now()
+	err := m.connectClient(client)
+	latency := time.Since(start)
+	defer m.cleanupClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	result := &ConnectionTestResult{
+		Tools:     make([]string, 0, len(client.Tools)),
+		Resources: client.Capabilities.Resources != nil,
+		Prompts:   client.Capabilities.Prompts != nil,
+		LatencyMs: latency.Milliseconds(),
+	}
+	for name := range client.Tools {
+		result.Tools = append(result.Tools, name)
+	}
+	slices.Sort(result.Tools)
+	result.ToolCount = len(result.Tools)
+	return result, nil
+}
+
 // GetClients returns information about all managed clients.
 func (m *MCPManager) GetClients() []ClientInfo {
 	m.mu.RLock()
@@ -2,7 +2,13 @@ package mcp
 
 import (
 	"context"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/blueberrycongee/llmux/pkg/types"
 )
@@ -159,3 +165,77 @@ func TestBuildClientInfo(t *testing.T) {
 		t.Errorf("ToolCount = %d, want %d", info.ToolCount, 2)
 	}
 }
+
+// newToolServer serves an MCP server offering the named no-op tools and
+// returns its endpoint URL.
+func newToolServer(t *testing.T, tools ...string) string {
+	t.Helper()
+	srv := server.NewMCPServer("tools", "1.0.0")
+	for _, name := range tools {
+		srv.AddTool(mcp.NewTool(name), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+	}
+	httpServer := httptest.NewServer(server.NewStreamableHTTPServer(srv))
+	t.Cleanup(httpServer.Close)
+	return httpServer.URL + "/mcp"
+}
+
+func TestManager_UpdateAndTestClient(t *testing.T) {
+	m, err := NewManager(context.Background(), Config{
+		DefaultConnectionTimeout: 2 * time.Second,
+		DefaultExecutionTimeout:  2 * time.Second,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+
+	cfg := ClientConfig{ID: "svc", Name: "Service", Type: ConnectionTypeHTTP, URL: newToolServer(t, "old_tool"), ToolsToExecute: []string{"*"}}
+	if err := m.AddClient(cfg); err != nil {
+		t.Fatalf("AddClient() error = %v", err)
+	}
+
+	toolNames := func() string {
+		var names []string
+		for _, tool := range m.GetAvailableTools(context.Background()) {
+			names = append(names, tool.Function.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	cfg.URL = newToolServer(t, "new_tool")
+	if err := m.UpdateClient(cfg); err != nil {
+		t.Fatalf("UpdateClient() error = %v", err)
+	}
+	if got := toolNames(); got != "new_tool" {
+		t.Fatalf("tools after update = %s, want new_tool", got)
+	}
+
+	// A failed update keeps the working connection.
+	broken := cfg
+	broken.URL = "http://127.0.0.1:1/mcp"
+	if err := m.UpdateClient(broken); err == nil {
+		t.Fatal("expected UpdateClient() to fail for an unreachable server")
+	}
+	if got := toolNames(); got != "new_tool" {
+		t.Fatalf("tools after failed update = %s, want new_tool", got)
+	}
+	if err := m.UpdateClient(ClientConfig{ID: "missing", Name: "Missing", Type: ConnectionTypeHTTP, URL: cfg.URL}); err == nil {
+		t.Fatal("expected UpdateClient() to fail for an unknown client")
+	}
+
+	result, err := m.TestClient(ClientConfig{ID: "probe", Name: "Probe", Type: ConnectionTypeHTTP, URL: newToolServer(t, "b", "a")})
+	if err != nil {
+		t.Fatalf("TestClient() error = %v", err)
+	}
+	if strings.Join(result.Tools, ",") != "a,b" || result.ToolCount != 2 {
+		t.Fatalf("TestClient() = %+v", result)
+	}
+	if _, err := m.GetClient("probe"); err == nil {
+		t.Fatal("TestClient() must not add the client")
+	}
+	if _, err := m.TestClient(broken); err == nil {
+		t.Fatal("expected TestClient() to fail for an unreachable server")
+	}
+}
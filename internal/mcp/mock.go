@@ -14,6 +14,11 @@ type MockManager struct {
 	clients map[string]*MockClient
 	tools   []types.Tool
 
+	resources []Resource
+	contents  map[string][]ResourceContent // uri -> contents
+	prompts   []Prompt
+	messages  map[string][]types.ChatMessage // clientID/name -> messages
+
 	// Hooks for customizing behavior
 	ExecuteFunc func(ctx context.Context, toolCall types.ToolCall) (*ToolExecutionResult, error)
 }
@@ -30,8 +35,10 @@ type MockClient struct {
 // NewMockManager creates a new mock manager for testing.
 func NewMockManager() *MockManager {
 	return &MockManager{
-		clients: make(map[string]*MockClient),
-		tools:   []types.Tool{},
+		clients:  make(map[string]*MockClient),
+		tools:    []types.Tool{},
+		contents: make(map[string][]ResourceContent),
+		messages: make(map[string][]types.ChatMessage),
 	}
 }
 
@@ -55,6 +62,23 @@ func (m *MockManager) AddMockClient(id, name string, connType ConnectionType, to
 	}
 }
 
+// AddMockResource adds a resource with its contents.
+func (m *MockManager) AddMockResource(resource Resource, contents ...ResourceContent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resources = append(m.resources, resource)
+	m.contents[resource.URI] = contents
+}
+
+// AddMockPrompt adds a prompt that renders to messages regardless of its
+// arguments.
+func (m *MockManager) AddMockPrompt(prompt Prompt, messages ...types.ChatMessage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prompts = append(m.prompts, prompt)
+	m.messages[prompt.ClientID+"/"+prompt.Name] = messages
+}
+
 // SetExecuteFunc sets a custom function for tool execution.
 func (m *MockManager) SetExecuteFunc(fn func(ctx context.Context, toolCall types.ToolCall) (*ToolExecutionResult, error)) {
 	m.ExecuteFunc = fn
@@ -102,6 +126,40 @@ func (m *MockManager) ExecuteToolCalls(ctx context.Context, toolCalls []types.To
 	return results
 }
 
+func (m *MockManager) ListResources(ctx context.Context) []Resource {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Resource(nil), m.resources...)
+}
+
+func (m *MockManager) ReadResource(ctx context.Context, clientID, uri string) ([]ResourceContent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, r := range m.resources {
+		if r.ClientID == clientID && r.URI == uri {
+			return m.contents[uri], nil
+		}
+	}
+	return nil, fmt.Errorf("resource %q not found in client %q", uri, clientID)
+}
+
+func (m *MockManager) ListPrompts(ctx context.Context) []Prompt {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Prompt(nil), m.prompts...)
+}
+
+func (m *MockManager) GetPrompt(ctx context.Context, name string, args map[string]string) ([]types.ChatMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, p := range m.prompts {
+		if name == p.Name || name == p.ClientID+"/"+p.Name {
+			return m.messages[p.ClientID+"/"+p.Name], nil
+		}
+	}
+	return nil, fmt.Errorf("prompt %q not found in any MCP client", name)
+}
+
 func (m *MockManager) AddClient(cfg ClientConfig) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -133,6 +191,27 @@ func (m *MockManager) RemoveClient(id string) error {
 	return nil
 }
 
+func (m *MockManager) UpdateClient(cfg ClientConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	client, exists := m.clients[cfg.ID]
+	if !exists {
+		return fmt.Errorf("client %q not found", cfg.ID)
+	}
+
+	client.Name = cfg.Name
+	client.Type = cfg.Type
+	return nil
+}
+
+func (m *MockManager) TestClient(cfg ClientConfig) (*ConnectionTestResult, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return &ConnectionTestResult{Tools: []string{}}, nil
+}
+
 func (m *MockManager) ReconnectClient(id string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// newOAuthTokenSource resolves the client's OAuth credentials and returns a
+// token source that caches the access token and refreshes it on expiry.
+// The first token is fetched eagerly so bad credentials fail the connect.
+func (m *MCPManager) newOAuthTokenSource(cfg ClientConfig) (oauth2.TokenSource, error) {
+	oc := cfg.OAuth
+	timeout := cfg.GetConnectionTimeout(m.config.DefaultConnectionTimeout)
+	ctx := context.WithValue(m.ctx, oauth2.HTTPClient, &http.Client{Timeout: timeout})
+
+	clientSecret, err := m.resolveSecret(ctx, oc.ClientSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve client_secret: %w", err)
+	}
+
+	var ts oauth2.TokenSource
+	if oc.RefreshToken != "" {
+		refreshToken, err := m.resolveSecret(ctx, oc.RefreshToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve refresh_token: %w", err)
+		}
+		conf := &oauth2.Config{
+			ClientID:     oc.ClientID,
+			ClientSecret: clientSecret,
+			Endpoint:     oauth2.Endpoint{TokenURL: oc.TokenURL},
+			Scopes:       oc.Scopes,
+		}
+		ts = conf.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken})
+	} else {
+		conf := &clientcredentials.Config{
+			ClientID:     oc.ClientID,
+			ClientSecret: clientSecret,
+			TokenURL:     oc.TokenURL,
+			Scopes:       oc.Scopes,
+		}
+		if oc.Audience != "" {
+			conf.EndpointParams = url.Values{"audience": {oc.Audience}}
+		}
+		ts = conf.TokenSource(ctx)
+	}
+
+	if _, err := ts.Token(); err != nil {
+		return nil, fmt.Errorf("failed to obtain oauth token: %w", err)
+	}
+	return ts, nil
+}
+
+// oauthHeaderFunc injects the current access token into every request of
+// an HTTP or SSE transport.
+func (m *MCPManager) oauthHeaderFunc(cfg ClientConfig, ts oauth2.TokenSource) transport.HTTPHeaderFunc {
+	header := cfg.OAuth.Header
+	if header == "" {
+		header = "Authorization"
+	}
+	return func(context.Context) map[string]string {
+		token, err := ts.Token()
+		if err != nil {
+			m.logger.Warn(MCPLogPrefix+" failed to refresh oauth token",
+				"client", cfg.Name,
+				"error", err,
+			)
+			return nil
+		}
+		value := token.AccessToken
+		if http.CanonicalHeaderKey(header) == "Authorization" {
+			value = token.Type() + " " + value
+		}
+		return map[string]string{header: value}
+	}
+}
+
+// resolveSecret resolves ref through the secret resolver, if any.
+func (m *MCPManager) resolveSecret(ctx context.Context, ref string) (string, error) {
+	if ref == "" || m.secrets == nil {
+		return ref, nil
+	}
+	return m.secrets.Get(ctx, ref)
+}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// mapSecrets mirrors secret.Manager: references without a scheme are
+// literal values.
+type mapSecrets map[string]string
+
+func (s mapSecrets) Get(_ context.Context, ref string) (string, error) {
+	if !strings.Contains(ref, "://") {
+		return ref, nil
+	}
+	if v, ok := s[ref]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("secret %q not found", ref)
+}
+
+// newTokenServer issues tokens "tok-1", "tok-2", ... that expire after
+// expiresIn seconds and records the form of the last token request.
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32, *atomic.Value) {
+	t.Helper()
+	var issued atomic.Int32
+	var lastForm atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if id, secret, _ := r.BasicAuth(); id != "llmux" || secret != "s3cret" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		lastForm.Store(r.PostForm)
+		n := issued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("tok-%d", n),
+			"token_type":   "bearer",
+			"expires_in":   expiresIn,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued, &lastForm
+}
+
+func newOAuthTestManager(t *testing.T) *MCPManager {
+	t.Helper()
+	m, err := NewManager(context.Background(), Config{DefaultConnectionTimeout: time.Second}, nil,
+		WithSecretResolver(mapSecrets{"vault://mcp/secret": "s3cret", "vault://mcp/refresh": "rt-1"}))
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+	return m
+}
+
+func TestOAuth_ClientCredentials(t *testing.T) {
+	server, issued, lastForm := newTokenServer(t, 3600)
+	m := newOAuthTestManager(t)
+	cfg := ClientConfig{Name: "GitHub", OAuth: &OAuthConfig{
+		TokenURL:     server.URL,
+		ClientID:     "llmux",
+		ClientSecret: "vault://mcp/secret",
+		Scopes:       []string{"repo"},
+		Audience:     "https://api.example.com",
+	}}
+
+	ts, err := m.newOAuthTokenSource(cfg)
+	if err != nil {
+		t.Fatalf("newOAuthTokenSource() error = %v", err)
+	}
+	headers := m.oauthHeaderFunc(cfg, ts)
+	for i := 0; i < 3; i++ {
+		if got := headers(context.Background())["Authorization"]; got != "Bearer tok-1" {
+			t.Fatalf("Authorization = %q, want Bearer tok-1", got)
+		}
+	}
+	if n := issued.Load(); n != 1 {
+		t.Fatalf("token requests = %d, want 1 (cached)", n)
+	}
+
+	form := lastForm.Load().(url.Values)
+	if form["grant_type"][0] != "client_credentials" || form["scope"][0] != "repo" || form["audience"][0] != "https://api.example.com" {
+		t.Fatalf("token request form = %v", form)
+	}
+}
+
+func TestOAuth_RefreshTokenAndCustomHeader(t *testing.T) {
+	// Tokens expire immediately, so every header lookup refreshes.
+	server, issued, lastForm := newTokenServer(t, 1)
+	m := newOAuthTestManager(t)
+	cfg := ClientConfig{Name: "Jira", OAuth: &OAuthConfig{
+		TokenURL:     server.URL,
+		ClientID:     "llmux",
+		ClientSecret: "vault://mcp/secret",
+		RefreshToken: "vault://mcp/refresh",
+		Header:       "X-Api-Token",
+	}}
+
+	ts, err := m.newOAuthTokenSource(cfg)
+	if err != nil {
+		t.Fatalf("newOAuthTokenSource() error = %v", err)
+	}
+	form := lastForm.Load().(url.Values)
+	if form["grant_type"][0] != "refresh_token" || form["refresh_token"][0] != "rt-1" {
+		t.Fatalf("token request form = %v", form)
+	}
+
+	got := m.oauthHeaderFunc(cfg, ts)(context.Background())
+	if !strings.HasPrefix(got["X-Api-Token"], "tok-") || len(got) != 1 {
+		t.Fatalf("headers = %v, want the bare token in X-Api-Token", got)
+	}
+	if n := issued.Load(); n < 2 {
+		t.Fatalf("token requests = %d, want a refresh after expiry", n)
+	}
+}
+
+func TestOAuth_ConnectFailsOnBadCredentials(t *testing.T) {
+	server, _, _ := newTokenServer(t, 3600)
+	m := newOAuthTestManager(t)
+
+	_, err := m.newOAuthTokenSource(ClientConfig{Name: "GitHub", OAuth: &OAuthConfig{
+		TokenURL: server.URL, ClientID: "llmux", ClientSecret: "wrong",
+	}})
+	if err == nil || !strings.Contains(err.Error(), "oauth token") {
+		t.Fatalf("error = %v, want a token error", err)
+	}
+
+	_, err = m.newOAuthTokenSource(ClientConfig{Name: "GitHub", OAuth: &OAuthConfig{
+		TokenURL: server.URL, ClientID: "llmux", ClientSecret: "vault://missing",
+	}})
+	if err == nil || !strings.Contains(err.Error(), "client_secret") {
+		t.Fatalf("error = %v, want a secret resolution error", err)
+	}
+}
@@ -0,0 +1,233 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// Resource is a resource offered by an MCP client.
+type Resource struct {
+	ClientID    string `json:"client_id"`
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MIMEType    string `json:"mime_type,omitempty"`
+}
+
+// ResourceContent is one item of a read resource. Exactly one of Text and
+// Blob (base64) is set.
+type ResourceContent struct {
+	URI      string `json:"uri"`
+	MIMEType string `json:"mime_type,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// Prompt is a prompt template offered by an MCP client.
+type Prompt struct {
+	ClientID    string           `json:"client_id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument is an argument of a prompt template.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// ListResources returns the resources of all connected clients that
+// support them. Clients failing to list are logged and skipped.
+func (m *MCPManager) ListResources(ctx context.Context) []Resource {
+	var resources []Resource
+	for _, client := range m.capableClients(ctx, func(c mcp.ServerCapabilities) bool { return c.Resources != nil }) {
+		execCtx, cancel := m.clientContext(ctx, client)
+		resp, err := client.Conn.ListResources(execCtx, mcp.ListResourcesRequest{})
+		cancel()
+		if err != nil {
+			m.logger.Warn(MCPLogPrefix+" failed to list resources", "client", client.Name, "error", err)
+			continue
+		}
+		for _, r := range resp.Resources {
+			resources = append(resources, Resource{
+				ClientID:    client.ID,
+				URI:         r.URI,
+				Name:        r.Name,
+				Description: r.Description,
+				MIMEType:    r.MIMEType,
+			})
+		}
+	}
+	return resources
+}
+
+// ReadResource reads a resource from the given client.
+func (m *MCPManager) ReadResource(ctx context.Context, clientID, uri string) ([]ResourceContent, error) {
+	client, err := m.connectedClient(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	execCtx, cancel := m.clientContext(ctx, client)
+	defer cancel()
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = uri
+	resp, err := client.Conn.ReadResource(execCtx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource %q: %w", uri, err)
+	}
+
+	contents := make([]ResourceContent, 0, len(resp.Contents))
+	for _, c := range resp.Contents {
+		switch rc := c.(type) {
+		case mcp.TextResourceContents:
+			contents = append(contents, ResourceContent{URI: rc.URI, MIMEType: rc.MIMEType, Text: rc.Text})
+		case mcp.BlobResourceContents:
+			contents = append(contents, ResourceContent{URI: rc.URI, MIMEType: rc.MIMEType, Blob: rc.Blob})
+		}
+	}
+	return contents, nil
+}
+
+// ListPrompts returns the prompts of all connected clients that support
+// them. Clients failing to list are logged and skipped.
+func (m *MCPManager) ListPrompts(ctx context.Context) []Prompt {
+	var prompts []Prompt
+	for _, client := range m.capableClients(ctx, func(c mcp.ServerCapabilities) bool { return c.Prompts != nil }) {
+		execCtx, cancel := m.clientContext(ctx, client)
+		resp, err := client.Conn.ListPrompts(execCtx, mcp.ListPromptsRequest{})
+		cancel()
+		if err != nil {
+			m.logger.Warn(MCPLogPrefix+" failed to list prompts", "client", client.Name, "error", err)
+			continue
+		}
+		for _, p := range resp.Prompts {
+			prompts = append(prompts, convertMCPPrompt(client.ID, p))
+		}
+	}
+	return prompts
+}
+
+// GetPrompt renders a prompt into chat messages. name is either
+// "clientID/prompt" or a bare prompt name, which resolves to the first
+// client (by ID) offering it.
+func (m *MCPManager) GetPrompt(ctx context.Context, name string, args map[string]string) ([]types.ChatMessage, error) {
+	clientID, promptName, qualified := strings.Cut(name, "/")
+	if !qualified {
+		promptName = name
+		clientID = ""
+		for _, p := range m.ListPrompts(ctx) {
+			if p.Name == promptName {
+				clientID = p.ClientID
+				break
+			}
+		}
+		if clientID == "" {
+			return nil, fmt.Errorf("prompt %q not found in any MCP client", name)
+		}
+	}
+
+	client, err := m.connectedClient(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	execCtx, cancel := m.clientContext(ctx, client)
+	defer cancel()
+	req := mcp.GetPromptRequest{}
+	req.Params.Name = promptName
+	req.Params.Arguments = args
+	resp, err := client.Conn.GetPrompt(execCtx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt %q: %w", name, err)
+	}
+	return ConvertPromptMessages(resp.Messages), nil
+}
+
+// capableClients returns the connected clients passing the request-level
+// client filter whose server announced the capability, sorted by ID.
+func (m *MCPManager) capableClients(ctx context.Context, capable func(mcp.ServerCapabilities) bool) []*Client {
+	includeClients := getIncludeClients(ctx)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	clients := make([]*Client, 0, len(m.clients))
+	for id, client := range m.clients {
+		if client.Conn == nil || !capable(client.Capabilities) || !m.shouldIncludeClient(id, includeClients) {
+			continue
+		}
+		clients = append(clients, client)
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+	return clients
+}
+
+func (m *MCPManager) connectedClient(id string) (*Client, error) {
+	m.mu.RLock()
+	client, exists := m.clients[id]
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("client %q not found", id)
+	}
+	if client.Conn == nil {
+		return nil, fmt.Errorf("client %q not connected", client.Name)
+	}
+	return client, nil
+}
+
+func (m *MCPManager) clientContext(ctx context.Context, client *Client) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, client.Config.GetExecutionTimeout(m.config.DefaultExecutionTimeout))
+}
+
+func convertMCPPrompt(clientID string, p mcp.Prompt) Prompt {
+	prompt := Prompt{ClientID: clientID, Name: p.Name, Description: p.Description}
+	for _, arg := range p.Arguments {
+		prompt.Arguments = append(prompt.Arguments, PromptArgument{
+			Name:        arg.Name,
+			Description: arg.Description,
+			Required:    arg.Required,
+		})
+	}
+	return prompt
+}
+
+// ConvertPromptMessages converts rendered MCP prompt messages to chat
+// messages. Embedded text resources are inlined; other non-text content
+// is replaced by a placeholder, as for tool results.
+func ConvertPromptMessages(messages []mcp.PromptMessage) []types.ChatMessage {
+	out := make([]types.ChatMessage, 0, len(messages))
+	for _, msg := range messages {
+		var text string
+		switch c := msg.Content.(type) {
+		case mcp.TextContent:
+			text = c.Text
+		case mcp.EmbeddedResource:
+			if rc, ok := c.Resource.(mcp.TextResourceContents); ok {
+				text = rc.Text
+			} else {
+				text = fmt.Sprintf("[Resource: %s]", c.Type)
+			}
+		case mcp.ImageContent:
+			text = fmt.Sprintf("[Image: %s]", c.MIMEType)
+		case mcp.AudioContent:
+			text = fmt.Sprintf("[Audio: %s]", c.MIMEType)
+		}
+		content, err := json.Marshal(text)
+		if err != nil {
+			continue
+		}
+		out = append(out, types.ChatMessage{Role: string(msg.Role), Content: content})
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newResourceTestManager connects a manager to an MCP server offering a
+// resource and a prompt template.
+func newResourceTestManager(t *testing.T) *MCPManager {
+	t.Helper()
+	srv := server.NewMCPServer("docs", "1.0.0",
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+	)
+	srv.AddResource(mcp.NewResource("docs://readme", "README", mcp.WithMIMEType("text/markdown")),
+		func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "text/markdown", Text: "# LLMux"}}, nil
+		})
+	srv.AddPrompt(mcp.NewPrompt("review", mcp.WithPromptDescription("Review code"), mcp.WithArgument("lang", mcp.RequiredArgument())),
+		func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult("Review code", []mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("Review this "+req.Params.Arguments["lang"]+" code.")),
+				mcp.NewPromptMessage(mcp.RoleAssistant, mcp.NewEmbeddedResource(mcp.TextResourceContents{URI: "docs://style", Text: "Style guide"})),
+			}), nil
+		})
+	httpServer := httptest.NewServer(server.NewStreamableHTTPServer(srv))
+	t.Cleanup(httpServer.Close)
+
+	m, err := NewManager(context.Background(), Config{
+		Enabled:                  true,
+		DefaultConnectionTimeout: 5 * time.Second,
+		DefaultExecutionTimeout:  5 * time.Second,
+		Clients: []ClientConfig{
+			{ID: "docs", Name: "Docs", Type: ConnectionTypeHTTP, URL: httpServer.URL + "/mcp"},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+	if info, err := m.GetClient("docs"); err != nil || info.State != StateConnected {
+		t.Fatalf("client not connected: %+v, %v", info, err)
+	}
+	return m
+}
+
+func TestManager_Resources(t *testing.T) {
+	m := newResourceTestManager(t)
+	ctx := context.Background()
+
+	resources := m.ListResources(ctx)
+	if len(resources) != 1 || resources[0].ClientID != "docs" || resources[0].URI != "docs://readme" || resources[0].MIMEType != "text/markdown" {
+		t.Fatalf("resources = %+v", resources)
+	}
+
+	contents, err := m.ReadResource(ctx, "docs", "docs://readme")
+	if err != nil {
+		t.Fatalf("ReadResource() error = %v", err)
+	}
+	if len(contents) != 1 || contents[0].Text != "# LLMux" {
+		t.Fatalf("contents = %+v", contents)
+	}
+
+	if _, err := m.ReadResource(ctx, "missing", "docs://readme"); err == nil {
+		t.Fatal("expected an error for an unknown client")
+	}
+}
+
+func TestManager_Prompts(t *testing.T) {
+	m := newResourceTestManager(t)
+	ctx := context.Background()
+
+	prompts := m.ListPrompts(ctx)
+	if len(prompts) != 1 || prompts[0].Name != "review" || len(prompts[0].Arguments) != 1 || !prompts[0].Arguments[0].Required {
+		t.Fatalf("prompts = %+v", prompts)
+	}
+
+	for _, name := range []string{"review", "docs/review"} {
+		messages, err := m.GetPrompt(ctx, name, map[string]string{"lang": "Go"})
+		if err != nil {
+			t.Fatalf("GetPrompt(%q) error = %v", name, err)
+		}
+		if len(messages) != 2 ||
+			messages[0].Role != "user" || string(messages[0].Content) != `"Review this Go code."` ||
+			messages[1].Role != "assistant" || string(messages[1].Content) != `"Style guide"` {
+			t.Fatalf("GetPrompt(%q) = %+v", name, messages)
+		}
+	}
+
+	if _, err := m.GetPrompt(ctx, "missing", nil); err == nil {
+		t.Fatal("expected an error for an unknown prompt")
+	}
+}
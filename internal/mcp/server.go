@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+const (
+	// MCPServerName is the name LLMux presents to agents connecting to its
+	// MCP server endpoint.
+	MCPServerName = "LLMux-MCP-Gateway"
+
+	// GatewayPath is the data-plane route of the MCP server endpoint.
+	GatewayPath = "/v1/mcp"
+
+	// AllowedToolsMetadataKey is the API key metadata field listing the
+	// tools the key may list and call through the gateway, as names or
+	// path.Match patterns (e.g. "github_*").
+	AllowedToolsMetadataKey = "mcp_tools"
+)
+
+// GatewayServer exposes the aggregated tools of all MCP clients as a single
+// MCP server over streamable HTTP, so agents can use LLMux as their MCP hub.
+// Tool calls are forwarded to the client that provides the tool.
+type GatewayServer struct {
+	manager          Manager
+	logger           *slog.Logger
+	requireAllowlist bool
+
+	server *server.MCPServer
+	http   *server.StreamableHTTPServer
+
+	mu        sync.Mutex
+	signature string // tool set last registered with server
+}
+
+// GatewayOption configures the GatewayServer.
+type GatewayOption func(*GatewayServer)
+
+// WithRequireAllowlist hides all tools from API keys without an
+// AllowedToolsMetadataKey allowlist. By default such keys see every tool.
+func WithRequireAllowlist(require bool) GatewayOption {
+	return func(g *GatewayServer) {
+		g.requireAllowlist = require
+	}
+}
+
+// NewGatewayServer creates the MCP server endpoint over manager.
+func NewGatewayServer(manager Manager, logger *slog.Logger, opts ...GatewayOption) *GatewayServer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	g := &GatewayServer{manager: manager, logger: logger}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	// Tools come and go with MCP clients, so the registered set is synced
+	// with the manager before every listing and call.
+	hooks := &server.Hooks{}
+	hooks.AddBeforeListTools(func(ctx context.Context, _ any, _ *mcp.ListToolsRequest) {
+		g.sync(ctx)
+	})
+	hooks.AddBeforeCallTool(func(ctx context.Context, _ any, _ *mcp.CallToolRequest) {
+		g.sync(ctx)
+	})
+	g.server = server.NewMCPServer(MCPServerName, MCPVersion,
+		server.WithToolCapabilities(false),
+		server.WithToolFilter(g.filter),
+		server.WithHooks(hooks),
+		server.WithRecovery(),
+	)
+	g.http = server.NewStreamableHTTPServer(g.server,
+		server.WithEndpointPath(GatewayPath),
+		server.WithStateLess(true),
+	)
+	return g
+}
+
+// ServeHTTP handles MCP requests. The request context must carry the
+// caller's auth context for allowlists to apply.
+func (g *GatewayServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.http.ServeHTTP(w, r)
+}
+
+// sync registers the manager's current tools with the MCP server when they
+// changed since the last sync.
+func (g *GatewayServer) sync(ctx context.Context) {
+	tools := append([]types.Tool(nil), g.manager.GetAvailableTools(ctx)...)
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Function.Name < tools[j].Function.Name })
+
+	var b strings.Builder
+	for _, tool := range tools {
+		b.WriteString(tool.Function.Name)
+		b.WriteByte(0)
+		b.WriteString(tool.Function.Description)
+		b.WriteByte(0)
+		b.Write(tool.Function.Parameters)
+		b.WriteByte(0)
+	}
+	signature := b.String()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if signature == g.signature {
+		return
+	}
+	serverTools := make([]server.ServerTool, 0, len(tools))
+	for _, tool := range tools {
+		schema := tool.Function.Parameters
+		if len(schema) == 0 {
+			schema = json.RawMessage(`{"type":"object"}`)
+		}
+		serverTools = append(serverTools, server.ServerTool{
+			Tool:    mcp.NewToolWithRawSchema(tool.Function.Name, tool.Function.Description, schema),
+			Handler: g.call,
+		})
+	}
+	g.server.SetTools(serverTools...)
+	g.signature = signature
+	g.logger.Debug(MCPLogPrefix+" gateway tools synced", "tools", len(serverTools))
+}
+
+// filter hides tools the caller's API key may not use.
+func (g *GatewayServer) filter(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	allowed := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if g.allowed(ctx, tool.Name) {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
+func (g *GatewayServer) call(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := req.Params.Name
+	if !g.allowed(ctx, name) {
+		return nil, fmt.Errorf("tool %q is not allowed for this API key", name)
+	}
+	args, err := json.Marshal(req.GetArguments())
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	result, err := g.manager.ExecuteToolCall(ctx, types.ToolCall{
+		Type:     "function",
+		Function: types.ToolCallFunction{Name: name, Arguments: string(args)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return mcp.NewToolResultError(result.Content), nil
+	}
+	return mcp.NewToolResultText(result.Content), nil
+}
+
+// allowed reports whether the API key of ctx may use the tool. Requests
+// without an API key (auth disabled) may use every tool.
+func (g *GatewayServer) allowed(ctx context.Context, tool string) bool {
+	authCtx := auth.GetAuthContext(ctx)
+	if authCtx == nil || authCtx.APIKey == nil {
+		return true
+	}
+	patterns, ok := allowedTools(authCtx.APIKey.Metadata)
+	if !ok {
+		return !g.requireAllowlist
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, tool); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedTools returns the key's tool allowlist and whether it has one.
+func allowedTools(m auth.Metadata) ([]string, bool) {
+	switch v := m[AllowedToolsMetadataKey].(type) {
+	case []string:
+		return v, true
+	case []any:
+		names := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names, true
+	case string:
+		return strings.Split(v, ","), true
+	}
+	return nil, false
+}
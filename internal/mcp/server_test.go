@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// newGatewayTestServer serves the gateway behind a stub of the auth
+// middleware: the X-Test-Key header selects the caller's key.
+func newGatewayTestServer(t *testing.T, manager Manager, keys map[string]*auth.APIKey, opts ...GatewayOption) string {
+	t.Helper()
+	gateway := NewGatewayServer(manager, nil, opts...)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key, ok := keys[r.Header.Get("X-Test-Key")]; ok {
+			r = r.WithContext(auth.WithAuthContext(r.Context(), &auth.AuthContext{APIKey: key}))
+		}
+		gateway.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + GatewayPath
+}
+
+func connectGateway(t *testing.T, url, key string) *client.Client {
+	t.Helper()
+	c, err := client.NewStreamableHttpClient(url, transport.WithHTTPHeaders(map[string]string{"X-Test-Key": key}))
+	if err != nil {
+		t.Fatalf("NewStreamableHttpClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, err := c.Initialize(ctx, mcp.InitializeRequest{Params: mcp.InitializeParams{ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION}}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	return c
+}
+
+func listToolNames(t *testing.T, c *client.Client) []string {
+	t.Helper()
+	resp, err := c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	names := make([]string, 0, len(resp.Tools))
+	for _, tool := range resp.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func gatewayTestManager() *MockManager {
+	manager := NewMockManager()
+	manager.AddMockClient("github", "GitHub", ConnectionTypeHTTP, []types.Tool{
+		{Type: "function", Function: types.ToolFunction{Name: "github_search", Parameters: []byte(`{"type":"object","properties":{"q":{"type":"string"}}}`)}},
+		{Type: "function", Function: types.ToolFunction{Name: "github_merge"}},
+	})
+	manager.AddMockClient("jira", "Jira", ConnectionTypeHTTP, []types.Tool{
+		{Type: "function", Function: types.ToolFunction{Name: "jira_create"}},
+	})
+	manager.SetExecuteFunc(func(ctx context.Context, call types.ToolCall) (*ToolExecutionResult, error) {
+		return &ToolExecutionResult{ToolName: call.Function.Name, Content: call.Function.Name + " " + call.Function.Arguments}, nil
+	})
+	return manager
+}
+
+func TestGatewayServer_ListAndCall(t *testing.T) {
+	url := newGatewayTestServer(t, gatewayTestManager(), nil)
+	c := connectGateway(t, url, "")
+
+	if got := strings.Join(listToolNames(t, c), ","); got != "github_merge,github_search,jira_create" {
+		t.Fatalf("tools = %s", got)
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "github_search"
+	req.Params.Arguments = map[string]any{"q": "llmux"}
+	result, err := c.CallTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok || text.Text != `github_search {"q":"llmux"}` || result.IsError {
+		t.Fatalf("result = %+v", result)
+	}
+}
+
+func TestGatewayServer_Allowlist(t *testing.T) {
+	keys := map[string]*auth.APIKey{
+		"limited": {ID: "limited", Metadata: auth.Metadata{AllowedToolsMetadataKey: []any{"github_*"}}},
+		"open":    {ID: "open"},
+	}
+	url := newGatewayTestServer(t, gatewayTestManager(), keys)
+
+	limited := connectGateway(t, url, "limited")
+	if got := strings.Join(listToolNames(t, limited), ","); got != "github_merge,github_search" {
+		t.Fatalf("limited tools = %s", got)
+	}
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "jira_create"
+	if _, err := limited.CallTool(context.Background(), req); err == nil {
+		t.Fatal("expected a tool outside the allowlist to be rejected")
+	}
+
+	open := connectGateway(t, url, "open")
+	if got := len(listToolNames(t, open)); got != 3 {
+		t.Fatalf("keys without an allowlist see %d tools, want 3", got)
+	}
+
+	strict := newGatewayTestServer(t, gatewayTestManager(), keys, WithRequireAllowlist(true))
+	if got := len(listToolNames(t, connectGateway(t, strict, "open"))); got != 0 {
+		t.Fatalf("with require_allowlist keys without an allowlist see %d tools, want 0", got)
+	}
+}
+
+func TestGatewayServer_SyncsNewTools(t *testing.T) {
+	manager := gatewayTestManager()
+	url := newGatewayTestServer(t, manager, nil)
+	c := connectGateway(t, url, "")
+	_ = listToolNames(t, c)
+
+	manager.AddMockClient("slack", "Slack", ConnectionTypeHTTP, []types.Tool{
+		{Type: "function", Function: types.ToolFunction{Name: "slack_post"}},
+	})
+	if got := len(listToolNames(t, c)); got != 4 {
+		t.Fatalf("tools after adding a client = %d, want 4", got)
+	}
+}
@@ -58,12 +58,15 @@ func (m *MCPManager) connectClient(c *Client) error {
 		},
 	}
 
-	if _, err := mcpClient.Initialize(ctx, initReq); err != nil {
+	initResult, err := mcpClient.Initialize(ctx, initReq)
+	if err != nil {
+		_ = mcpClient.Close()
 		return fmt.Errorf("failed to initialize: %w", err)
 	}
 
 	// Update client with connection
 	c.Conn = mcpClient
+	c.Capabilities = initResult.Capabilities
 	connInfo.ConnectedAt = time.Now()
 	c.ConnectionInfo = connInfo
 
@@ -85,10 +88,16 @@ func (m *MCPManager) createHTTPConnection(cfg ClientConfig) (*client.Client, Cli
 		URL:  cfg.URL,
 	}
 
-	httpTransport, err := transport.NewStreamableHTTP(
-		cfg.URL,
-		transport.WithHTTPHeaders(cfg.Headers),
-	)
+	opts := []transport.StreamableHTTPCOption{transport.WithHTTPHeaders(cfg.Headers)}
+	if cfg.OAuth != nil {
+		ts, err := m.newOAuthTokenSource(cfg)
+		if err != nil {
+			return nil, connInfo, err
+		}
+		opts = append(opts, transport.WithHTTPHeaderFunc(m.oauthHeaderFunc(cfg, ts)))
+	}
+
+	httpTransport, err := transport.NewStreamableHTTP(cfg.URL, opts...)
 	if err != nil {
 		return nil, connInfo, fmt.Errorf("failed to create HTTP transport: %w", err)
 	}
@@ -125,10 +134,16 @@ func (m *MCPManager) createSSEConnection(cfg ClientConfig) (*client.Client, Clie
 		URL:  cfg.URL,
 	}
 
-	sseTransport, err := transport.NewSSE(
-		cfg.URL,
-		transport.WithHeaders(cfg.Headers),
-	)
+	opts := []transport.ClientOption{transport.WithHeaders(cfg.Headers)}
+	if cfg.OAuth != nil {
+		ts, err := m.newOAuthTokenSource(cfg)
+		if err != nil {
+			return nil, connInfo, nil, err
+		}
+		opts = append(opts, transport.WithHeaderFunc(m.oauthHeaderFunc(cfg, ts)))
+	}
+
+	sseTransport, err := transport.NewSSE(cfg.URL, opts...)
 	if err != nil {
 		return nil, connInfo, nil, fmt.Errorf("failed to create SSE transport: %w", err)
 	}
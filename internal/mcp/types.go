@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
 
 	"github.com/blueberrycongee/llmux/pkg/types"
 )
@@ -97,14 +98,15 @@ const (
 
 // Client represents a connected MCP client with its configuration and tools.
 type Client struct {
-	ID             string                // Unique identifier
-	Name           string                // Human-readable name
-	Config         ClientConfig          // Client configuration
-	Conn           *client.Client        // Active MCP client connection
-	Tools          map[string]types.Tool // Available tools mapped by name
-	ConnectionInfo ClientConnectionInfo  // Connection metadata
-	cancelFunc     context.CancelFunc    // Cancel function for SSE connections
-	mu             sync.RWMutex          // Mutex for thread-safe tool access
+	ID             string                 // Unique identifier
+	Name           string                 // Human-readable name
+	Config         ClientConfig           // Client configuration
+	Conn           *client.Client         // Active MCP client connection
+	Tools          map[string]types.Tool  // Available tools mapped by name
+	Capabilities   mcp.ServerCapabilities // Capabilities announced by the server
+	ConnectionInfo ClientConnectionInfo   // Connection metadata
+	cancelFunc     context.CancelFunc     // Cancel function for SSE connections
+	mu             sync.RWMutex           // Mutex for thread-safe tool access
 }
 
 // ClientConnectionInfo stores metadata about how a client is connected.
@@ -126,6 +128,15 @@ type ClientInfo struct {
 	ConnectedAt *time.Time      `json:"connected_at,omitempty"`
 }
 
+// ConnectionTestResult reports a successful connection test.
+type ConnectionTestResult struct {
+	Tools     []string `json:"tools"`
+	ToolCount int      `json:"tool_count"`
+	Resources bool     `json:"resources"` // Server offers resources
+	Prompts   bool     `json:"prompts"`   // Server offers prompts
+	LatencyMs int64    `json:"latency_ms"`
+}
+
 // ============================================================================
 // TOOL EXECUTION TYPES
 // ============================================================================
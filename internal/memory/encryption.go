@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/pkg/encryption"
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+// sealPayload encrypts data with the data key of the request's tenant (the
+// routing tenant scope, as for encrypted caches) when keyring is set.
+func sealPayload(ctx context.Context, keyring *encryption.Keyring, data []byte) ([]byte, error) {
+	if keyring == nil {
+		return data, nil
+	}
+	return keyring.Encrypt(router.TenantScopeFromContext(ctx), data)
+}
+
+// openPayload decrypts data sealed by sealPayload. Plaintext written before
+// encryption was enabled is returned unchanged; data that cannot be
+// decrypted for the request's tenant returns ok false, so callers skip it
+// like missing data.
+func openPayload(ctx context.Context, keyring *encryption.Keyring, data []byte) (plaintext []byte, ok bool, err error) {
+	if keyring == nil || !encryption.IsEncrypted(data) {
+		return data, true, nil
+	}
+	plaintext, err = keyring.Decrypt(router.TenantScopeFromContext(ctx), data)
+	switch {
+	case err == nil:
+		return plaintext, true, nil
+	case errors.Is(err, encryption.ErrDecrypt), errors.Is(err, encryption.ErrUnknownKey):
+		return nil, false, nil
+	default:
+		return nil, false, err
+	}
+}
+
+// sealedRecord is the plaintext of an encrypted record's text field.
+type sealedRecord struct {
+	Text     string            `json:"text"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// sealRecord returns the text and metadata a vector store persists for r.
+// With a keyring, both are encrypted together into the text as base64 and
+// no metadata is stored; the embedding stays plaintext for search.
+func sealRecord(ctx context.Context, keyring *encryption.Keyring, r Record) (string, map[string]string, error) {
+	if keyring == nil {
+		return r.Text, r.Metadata, nil
+	}
+	data, err := json.Marshal(sealedRecord{Text: r.Text, Metadata: r.Metadata})
+	if err != nil {
+		return "", nil, fmt.Errorf("marshal memory: %w", err)
+	}
+	sealed, err := sealPayload(ctx, keyring, data)
+	if err != nil {
+		return "", nil, fmt.Errorf("encrypt memory: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil, nil
+}
+
+// openRecord restores the text and metadata of r sealed by sealRecord.
+// Records stored before encryption was enabled are left unchanged; ok is
+// false for records that cannot be decrypted for the request's tenant.
+func openRecord(ctx context.Context, keyring *encryption.Keyring, r *Record) (ok bool, err error) {
+	sealed, err := base64.StdEncoding.DecodeString(r.Text)
+	if err != nil || !encryption.IsEncrypted(sealed) {
+		return true, nil
+	}
+	if keyring == nil {
+		return false, fmt.Errorf("memory is encrypted and no keyring is configured")
+	}
+	data, ok, err := openPayload(ctx, keyring, sealed)
+	if !ok || err != nil {
+		return false, err
+	}
+	var opened sealedRecord
+	if err := json.Unmarshal(data, &opened); err != nil {
+		return false, fmt.Errorf("decode memory: %w", err)
+	}
+	r.Text, r.Metadata = opened.Text, opened.Metadata
+	return true, nil
+}
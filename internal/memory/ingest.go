@@ -0,0 +1,285 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnsupportedContentType is returned when no extractor handles a
+// document's content type.
+var ErrUnsupportedContentType = errors.New("unsupported document content type")
+
+// Chunking strategies.
+const (
+	// ChunkFixed cuts text into windows of Size characters, each starting
+	// Overlap characters before the end of the previous one.
+	ChunkFixed = "fixed"
+	// ChunkParagraph packs blank-line separated paragraphs into chunks of at
+	// most Size characters.
+	ChunkParagraph = "paragraph"
+	// ChunkMarkdown starts a new chunk at every heading and packs the
+	// paragraphs of each section like ChunkParagraph.
+	ChunkMarkdown = "markdown"
+)
+
+const (
+	defaultChunkSize = 1000
+	// embedBatchSize bounds the texts embedded per request by RememberAll.
+	embedBatchSize = 64
+)
+
+// ChunkConfig selects how documents are split before embedding.
+type ChunkConfig struct {
+	Strategy string // fixed, paragraph or markdown (default paragraph)
+	Size     int    // Maximum characters per chunk (default 1000)
+	Overlap  int    // Characters shared by consecutive fixed chunks; must be below Size
+}
+
+// Validate reports whether the configuration is usable.
+func (c ChunkConfig) Validate() error {
+	switch c.Strategy {
+	case "", ChunkFixed, ChunkParagraph, ChunkMarkdown:
+	default:
+		return fmt.Errorf("unknown chunking strategy %q", c.Strategy)
+	}
+	if c.Size < 0 || c.Overlap < 0 {
+		return fmt.Errorf("chunk size and overlap cannot be negative")
+	}
+	size := c.Size
+	if size == 0 {
+		size = defaultChunkSize
+	}
+	if c.Overlap >= size {
+		return fmt.Errorf("chunk overlap must be smaller than chunk size")
+	}
+	return nil
+}
+
+// Chunk splits text with cfg, dropping blank chunks.
+func Chunk(text string, cfg ChunkConfig) ([]string, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Size == 0 {
+		cfg.Size = defaultChunkSize
+	}
+	switch cfg.Strategy {
+	case ChunkFixed:
+		return chunkFixed(text, cfg.Size, cfg.Overlap), nil
+	case ChunkMarkdown:
+		var chunks []string
+		for _, section := range splitMarkdownSections(text) {
+			chunks = append(chunks, packParagraphs(section, cfg.Size)...)
+		}
+		return chunks, nil
+	default:
+		return packParagraphs(text, cfg.Size), nil
+	}
+}
+
+func chunkFixed(text string, size, overlap int) []string {
+	runes := []rune(text)
+	var chunks []string
+	for start := 0; start < len(runes); start += size - overlap {
+		end := min(start+size, len(runes))
+		if chunk := strings.TrimSpace(string(runes[start:end])); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		if end == len(runes) {
+			break
+		}
+	}
+	return chunks
+}
+
+var paragraphBreak = regexp.MustCompile(`\n\s*\n`)
+
+// packParagraphs joins consecutive paragraphs while they fit in size and
+// cuts longer paragraphs into fixed windows.
+func packParagraphs(text string, size int) []string {
+	var (
+		chunks  []string
+		current strings.Builder
+	)
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+	}
+	for _, para := range paragraphBreak.Split(text, -1) {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		n := utf8.RuneCountInString(para)
+		if n > size {
+			flush()
+			chunks = append(chunks, chunkFixed(para, size, 0)...)
+			continue
+		}
+		if current.Len() > 0 && utf8.RuneCountInString(current.String())+2+n > size {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(para)
+	}
+	flush()
+	return chunks
+}
+
+var markdownHeading = regexp.MustCompile(`(?m)^#{1,6}\s`)
+
+func splitMarkdownSections(text string) []string {
+	starts := markdownHeading.FindAllStringIndex(text, -1)
+	if len(starts) == 0 {
+		return []string{text}
+	}
+	var sections []string
+	if starts[0][0] > 0 {
+		sections = append(sections, text[:starts[0][0]])
+	}
+	for i, loc := range starts {
+		end := len(text)
+		if i+1 < len(starts) {
+			end = starts[i+1][0]
+		}
+		sections = append(sections, text[loc[0]:end])
+	}
+	return sections
+}
+
+// Extractor turns a document's bytes into plain text.
+type Extractor interface {
+	Extract(ctx context.Context, data []byte) (string, error)
+}
+
+// ExtractorFunc adapts a function to the Extractor interface.
+type ExtractorFunc func(ctx context.Context, data []byte) (string, error)
+
+// Extract calls f.
+func (f ExtractorFunc) Extract(ctx context.Context, data []byte) (string, error) {
+	return f(ctx, data)
+}
+
+// PlainTextExtractor returns UTF-8 documents as they are.
+var PlainTextExtractor = ExtractorFunc(func(_ context.Context, data []byte) (string, error) {
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("document is not valid UTF-8")
+	}
+	return string(data), nil
+})
+
+// CommandExtractor runs an external converter with the document on stdin
+// and reads the text from stdout, e.g. {"pdftotext", "-layout", "-", "-"}
+// for PDFs.
+type CommandExtractor struct {
+	Command []string
+	Timeout time.Duration // Default 60s
+}
+
+// Extract runs the command.
+func (e CommandExtractor) Extract(ctx context.Context, data []byte) (string, error) {
+	if len(e.Command) == 0 {
+		return "", fmt.Errorf("extractor command is empty")
+	}
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.Command[0], e.Command[1:]...) //nolint:gosec // command comes from operator configuration
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("run %s: %w: %s", e.Command[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return PlainTextExtractor(ctx, stdout.Bytes())
+}
+
+// DefaultExtractors returns the extractors for the content types supported
+// without configuration: text/plain and text/markdown.
+func DefaultExtractors() map[string]Extractor {
+	return map[string]Extractor{
+		"text/plain":    PlainTextExtractor,
+		"text/markdown": PlainTextExtractor,
+	}
+}
+
+// Extract converts data with the extractor registered for contentType,
+// ignoring media type parameters such as charset.
+func Extract(ctx context.Context, extractors map[string]Extractor, contentType string, data []byte) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	extractor, ok := extractors[mediaType]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedContentType, mediaType)
+	}
+	return extractor.Extract(ctx, data)
+}
+
+// RememberAll embeds texts in batches and stores them in namespace,
+// returning the new records' IDs in order. metadata is either empty or holds
+// one entry per text.
+func (m *LongTermMemory) RememberAll(ctx context.Context, namespace string, texts []string, metadata []map[string]string) ([]string, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if len(metadata) != 0 && len(metadata) != len(texts) {
+		return nil, fmt.Errorf("got %d metadata entries for %d texts", len(metadata), len(texts))
+	}
+	for _, text := range texts {
+		if text == "" {
+			return nil, ErrEmptyText
+		}
+	}
+
+	ids := make([]string, 0, len(texts))
+	now := time.Now().UTC()
+	for start := 0; start < len(texts); start += embedBatchSize {
+		end := min(start+embedBatchSize, len(texts))
+		vectors, err := m.embed(ctx, texts[start:end])
+		if err != nil {
+			return ids, err
+		}
+		records := make([]Record, 0, end-start)
+		for i, vector := range vectors {
+			record := Record{
+				ID:        uuid.NewString(),
+				Namespace: namespace,
+				Text:      texts[start+i],
+				Vector:    vector,
+				CreatedAt: now,
+			}
+			if len(metadata) != 0 {
+				record.Metadata = metadata[start+i]
+			}
+			records = append(records, record)
+		}
+		if err := m.store.Upsert(ctx, records); err != nil {
+			return ids, fmt.Errorf("store memory: %w", err)
+		}
+		for _, r := range records {
+			ids = append(ids, r.ID)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunk(t *testing.T) {
+	chunks, err := Chunk("abcdefghij", ChunkConfig{Strategy: ChunkFixed, Size: 4, Overlap: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abcd", "defg", "ghij"}, chunks)
+
+	text := "first paragraph\n\nsecond one\n\n\n" + strings.Repeat("x", 25)
+	chunks, err = Chunk(text, ChunkConfig{Size: 30})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first paragraph\n\nsecond one", strings.Repeat("x", 25)}, chunks)
+
+	markdown := "intro\n# One\nalpha\n\n## Two\nbeta"
+	chunks, err = Chunk(markdown, ChunkConfig{Strategy: ChunkMarkdown, Size: 100})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"intro", "# One\nalpha", "## Two\nbeta"}, chunks)
+
+	_, err = Chunk("x", ChunkConfig{Strategy: "sentences"})
+	assert.Error(t, err)
+	_, err = Chunk("x", ChunkConfig{Strategy: ChunkFixed, Size: 4, Overlap: 4})
+	assert.Error(t, err)
+}
+
+func TestExtract(t *testing.T) {
+	ctx := context.Background()
+	extractors := DefaultExtractors()
+
+	text, err := Extract(ctx, extractors, "text/markdown; charset=utf-8", []byte("# Title"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Title", text)
+
+	_, err = Extract(ctx, extractors, "application/pdf", []byte("%PDF"))
+	assert.True(t, errors.Is(err, ErrUnsupportedContentType))
+
+	extractors["application/x-upper"] = CommandExtractor{Command: []string{"tr", "a-z", "A-Z"}}
+	text, err = Extract(ctx, extractors, "application/x-upper", []byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO", text)
+}
+
+func TestLongTermMemory_RememberAll(t *testing.T) {
+	ctx := context.Background()
+	m := NewLongTermMemory(NewMemoryVectorStore(), keywordEmbedder, LongTermConfig{})
+
+	texts := make([]string, embedBatchSize+1)
+	metadata := make([]map[string]string, len(texts))
+	for i := range texts {
+		texts[i] = "fish"
+		metadata[i] = map[string]string{"i": "x"}
+	}
+	texts[len(texts)-1] = "cat"
+
+	ids, err := m.RememberAll(ctx, "ns", texts, metadata)
+	require.NoError(t, err)
+	require.Len(t, ids, len(texts))
+
+	matches, err := m.Recall(ctx, "ns", "cat", 1)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, ids[len(ids)-1], matches[0].ID)
+	assert.Equal(t, "x", matches[0].Metadata["i"])
+
+	_, err = m.RememberAll(ctx, "ns", []string{"a"}, metadata)
+	assert.Error(t, err, "metadata must match texts")
+}
@@ -0,0 +1,212 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidNamespace is returned for empty or oversized memory namespaces.
+var ErrInvalidNamespace = errors.New("invalid memory namespace")
+
+// ErrEmptyText is returned when asked to remember or recall empty text.
+var ErrEmptyText = errors.New("memory text is empty")
+
+// MaxNamespaceLength bounds namespaces accepted by LongTermMemory.
+const MaxNamespaceLength = 512
+
+// defaultRecallTopK is the number of memories recalled when none is requested.
+const defaultRecallTopK = 5
+
+// Record is a unit of long-term memory: a piece of text and its embedding,
+// isolated by namespace.
+type Record struct {
+	ID        string            `json:"id"`
+	Namespace string            `json:"namespace"`
+	Text      string            `json:"text"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Vector    []float64         `json:"-"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// Match is a record returned by a similarity query.
+type Match struct {
+	Record
+	// Score is the cosine similarity to the query (1 = identical).
+	Score float64 `json:"score"`
+}
+
+// VectorStore persists records and answers nearest-neighbour queries.
+// Implementations must never return records from another namespace.
+type VectorStore interface {
+	// Upsert stores records, replacing any with the same namespace and ID.
+	Upsert(ctx context.Context, records []Record) error
+	// Query returns up to topK records of namespace most similar to vector,
+	// best match first.
+	Query(ctx context.Context, namespace string, vector []float64, topK int) ([]Match, error)
+	// Delete removes records of namespace by ID.
+	Delete(ctx context.Context, namespace string, ids []string) error
+	// Close releases the store's resources.
+	Close() error
+}
+
+// Embedder turns texts into embedding vectors, one per text in order.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// EmbedderFunc adapts a function to the Embedder interface.
+type EmbedderFunc func(ctx context.Context, texts []string) ([][]float64, error)
+
+// Embed calls f.
+func (f EmbedderFunc) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	return f(ctx, texts)
+}
+
+// LongTermConfig tunes recall.
+type LongTermConfig struct {
+	TopK     int     // Memories recalled when the caller does not ask for a count (default 5)
+	MinScore float64 // Matches scoring below this are dropped (0 = keep all)
+}
+
+// LongTermMemory remembers texts as embeddings and recalls the ones most
+// similar to a query.
+type LongTermMemory struct {
+	store    VectorStore
+	embedder Embedder
+	cfg      LongTermConfig
+}
+
+// NewLongTermMemory creates a long-term memory that embeds with embedder and
+// persists to store.
+func NewLongTermMemory(store VectorStore, embedder Embedder, cfg LongTermConfig) *LongTermMemory {
+	if cfg.TopK <= 0 {
+		cfg.TopK = defaultRecallTopK
+	}
+	return &LongTermMemory{store: store, embedder: embedder, cfg: cfg}
+}
+
+// Remember embeds text and stores it in namespace, returning the new
+// record's ID.
+func (m *LongTermMemory) Remember(ctx context.Context, namespace, text string, metadata map[string]string) (string, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return "", err
+	}
+	if text == "" {
+		return "", ErrEmptyText
+	}
+	vectors, err := m.embed(ctx, []string{text})
+	if err != nil {
+		return "", err
+	}
+	record := Record{
+		ID:        uuid.NewString(),
+		Namespace: namespace,
+		Text:      text,
+		Metadata:  metadata,
+		Vector:    vectors[0],
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := m.store.Upsert(ctx, []Record{record}); err != nil {
+		return "", fmt.Errorf("store memory: %w", err)
+	}
+	return record.ID, nil
+}
+
+// Recall returns the memories of namespace most similar to query, best match
+// first. topK <= 0 uses the configured default.
+func (m *LongTermMemory) Recall(ctx context.Context, namespace, query string, topK int) ([]Match, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if query == "" {
+		return nil, ErrEmptyText
+	}
+	if topK <= 0 {
+		topK = m.cfg.TopK
+	}
+	vectors, err := m.embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	matches, err := m.store.Query(ctx, namespace, vectors[0], topK)
+	if err != nil {
+		return nil, fmt.Errorf("query memories: %w", err)
+	}
+	if m.cfg.MinScore > 0 {
+		kept := matches[:0]
+		for _, match := range matches {
+			if match.Score >= m.cfg.MinScore {
+				kept = append(kept, match)
+			}
+		}
+		matches = kept
+	}
+	return matches, nil
+}
+
+// Forget removes memories of namespace by ID.
+func (m *LongTermMemory) Forget(ctx context.Context, namespace string, ids ...string) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return m.store.Delete(ctx, namespace, ids)
+}
+
+// Close releases the underlying store.
+func (m *LongTermMemory) Close() error {
+	return m.store.Close()
+}
+
+func (m *LongTermMemory) embed(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors, err := m.embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed memory: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("embed memory: got %d vectors for %d texts", len(vectors), len(texts))
+	}
+	return vectors, nil
+}
+
+// RecordSize returns the bytes a record of text and metadata counts against
+// a quota.
+func RecordSize(text string, metadata map[string]string) int64 {
+	size := int64(len(text))
+	for k, v := range metadata {
+		size += int64(len(k) + len(v))
+	}
+	return size
+}
+
+func validateNamespace(namespace string) error {
+	if namespace == "" || len(namespace) > MaxNamespaceLength {
+		return ErrInvalidNamespace
+	}
+	return nil
+}
+
+// cosineSimilarity returns the cosine of the angle between a and b, or 0
+// when their sizes differ or either is zero.
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
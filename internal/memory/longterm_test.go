@@ -0,0 +1,277 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+// keywordEmbedder maps each text onto fixed axes by keyword so similarity is
+// predictable.
+var keywordEmbedder = EmbedderFunc(func(_ context.Context, texts []string) ([][]float64, error) {
+	out := make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		v := []float64{0.01, 0.01, 0.01}
+		for i, word := range []string{"cat", "dog", "fish"} {
+			if strings.Contains(text, word) {
+				v[i] = 1
+			}
+		}
+		out = append(out, v)
+	}
+	return out, nil
+})
+
+func TestLongTermMemory_RememberRecallForget(t *testing.T) {
+	ctx := context.Background()
+	m := NewLongTermMemory(NewMemoryVectorStore(), keywordEmbedder, LongTermConfig{MinScore: 0.5})
+
+	catID, err := m.Remember(ctx, "tenant-a", "my cat is called Tom", map[string]string{"source": "chat"})
+	require.NoError(t, err)
+	_, err = m.Remember(ctx, "tenant-a", "the dog barks", nil)
+	require.NoError(t, err)
+	_, err = m.Remember(ctx, "tenant-b", "another cat", nil)
+	require.NoError(t, err)
+
+	matches, err := m.Recall(ctx, "tenant-a", "what is my cat's name?", 0)
+	require.NoError(t, err)
+	require.Len(t, matches, 1, "dissimilar memories fall below min_score")
+	assert.Equal(t, catID, matches[0].ID)
+	assert.Equal(t, "chat", matches[0].Metadata["source"])
+	assert.InDelta(t, 1.0, matches[0].Score, 0.01)
+
+	require.NoError(t, m.Forget(ctx, "tenant-a", catID))
+	matches, err = m.Recall(ctx, "tenant-a", "cat", 0)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	matches, err = m.Recall(ctx, "tenant-b", "cat", 0)
+	require.NoError(t, err)
+	assert.Len(t, matches, 1, "other namespaces are untouched")
+}
+
+func TestLongTermMemory_Validation(t *testing.T) {
+	ctx := context.Background()
+	m := NewLongTermMemory(NewMemoryVectorStore(), keywordEmbedder, LongTermConfig{})
+
+	_, err := m.Remember(ctx, "", "text", nil)
+	assert.ErrorIs(t, err, ErrInvalidNamespace)
+	_, err = m.Remember(ctx, "ns", "", nil)
+	assert.ErrorIs(t, err, ErrEmptyText)
+	_, err = m.Recall(ctx, strings.Repeat("x", MaxNamespaceLength+1), "q", 1)
+	assert.ErrorIs(t, err, ErrInvalidNamespace)
+}
+
+func TestQdrantVectorStore(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		points = map[string]qdrantMemoryPoint{}
+		search map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("api-key"))
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/collections/mem/points":
+			var body struct {
+				Points []qdrantMemoryPoint `json:"points"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			for _, p := range body.Points {
+				points[p.ID] = p
+			}
+		case r.URL.Path == "/collections/mem/points/search":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&search))
+			var result []map[string]any
+			for _, p := range points {
+				result = append(result, map[string]any{"id": p.ID, "score": 0.9, "payload": p.Payload})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"result": result})
+			return
+		case r.URL.Path == "/collections/mem/points/delete":
+			var body struct {
+				Points []string `json:"points"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			for _, id := range body.Points {
+				delete(points, id)
+			}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"result":true}`))
+	}))
+	defer server.Close()
+
+	store, err := NewQdrantVectorStore(QdrantVectorConfig{APIBase: server.URL, APIKey: "secret", Collection: "mem"})
+	require.NoError(t, err)
+	ctx := context.Background()
+	created := time.Unix(1700000000, 0).UTC()
+
+	require.NoError(t, store.Upsert(ctx, []Record{{ID: "r1", Namespace: "ns", Text: "hello", Vector: []float64{1, 0}, CreatedAt: created}}))
+	matches, err := store.Query(ctx, "ns", []float64{1, 0}, 3)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "r1", matches[0].ID)
+	assert.Equal(t, "hello", matches[0].Text)
+	assert.Equal(t, created, matches[0].CreatedAt)
+	assert.Contains(t, search, "filter")
+
+	require.NoError(t, store.Delete(ctx, "ns", []string{"r1"}))
+	assert.Empty(t, points)
+}
+
+func TestMilvusVectorStore(t *testing.T) {
+	var requests []string
+	var searchFilter string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer root:Milvus", r.Header.Get("Authorization"))
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "mem", body["collectionName"])
+		requests = append(requests, r.URL.Path)
+
+		switch r.URL.Path {
+		case "/v2/vectordb/collections/has":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"has":false}}`))
+		case "/v2/vectordb/entities/search":
+			searchFilter, _ = body["filter"].(string)
+			_, _ = w.Write([]byte(`{"code":0,"data":[{"distance":0.8,"namespace":"ns","record_id":"r1","text":"hello","metadata":{"k":"v"},"created_at":1700000000}]}`))
+		case "/v2/vectordb/entities/delete":
+			_, _ = w.Write([]byte(`{"code":1100,"message":"collection not loaded"}`))
+		default:
+			_, _ = w.Write([]byte(`{"code":0,"data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	store, err := NewMilvusVectorStore(MilvusVectorConfig{Address: server.URL, Token: "root:Milvus", Collection: "mem", Dimension: 2})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, store.EnsureCollection(ctx))
+	require.NoError(t, store.Upsert(ctx, []Record{{ID: "r1", Namespace: "ns", Text: "hello", Vector: []float64{1, 0}}}))
+	matches, err := store.Query(ctx, "ns", []float64{1, 0}, 3)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "r1", matches[0].ID)
+	assert.Equal(t, "v", matches[0].Metadata["k"])
+	assert.InDelta(t, 0.8, matches[0].Score, 1e-9)
+	assert.Equal(t, `namespace == "ns"`, searchFilter)
+
+	err = store.Delete(ctx, "ns", []string{"r1"})
+	assert.ErrorContains(t, err, "collection not loaded")
+	assert.Equal(t, []string{
+		"/v2/vectordb/collections/has",
+		"/v2/vectordb/collections/create",
+		"/v2/vectordb/entities/upsert",
+		"/v2/vectordb/entities/search",
+		"/v2/vectordb/entities/delete",
+	}, requests)
+}
+
+func TestPgVectorStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	store, err := NewPgVectorStore(db, PgVectorConfig{Dimension: 2})
+	require.NoError(t, err)
+	ctx := context.Background()
+	created := time.Unix(1700000000, 0).UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO memory_vectors")).
+		WithArgs("ns", "r1", "hello", `{}`, "[1,0.5]", created).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	require.NoError(t, store.Upsert(ctx, []Record{{ID: "r1", Namespace: "ns", Text: "hello", Vector: []float64{1, 0.5}, CreatedAt: created}}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM memory_vectors WHERE namespace = $1")).
+		WithArgs("ns", "[1,0.5]", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "text", "metadata", "created_at", "score"}).
+			AddRow("r1", "hello", `{"k":"v"}`, created, 0.97))
+	matches, err := store.Query(ctx, "ns", []float64{1, 0.5}, 3)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "ns", matches[0].Namespace)
+	assert.Equal(t, "v", matches[0].Metadata["k"])
+	assert.InDelta(t, 0.97, matches[0].Score, 1e-9)
+
+	_, err = NewPgVectorStore(db, PgVectorConfig{Table: "bad; DROP"})
+	assert.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQdrantVectorStore_Encrypted(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		points = map[string]qdrantMemoryPoint{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/collections/mem/points":
+			var body struct {
+				Points []qdrantMemoryPoint `json:"points"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			for _, p := range body.Points {
+				points[p.ID] = p
+			}
+		case "/collections/mem/points/search":
+			var result []map[string]any
+			for _, p := range points {
+				result = append(result, map[string]any{"id": p.ID, "score": 0.9, "payload": p.Payload})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"result": result})
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":true}`))
+	}))
+	defer server.Close()
+
+	store, err := NewQdrantVectorStore(QdrantVectorConfig{APIBase: server.URL, Collection: "mem", Keyring: testKeyring(t)})
+	require.NoError(t, err)
+	ctx := router.WithTenantScope(context.Background(), "key-a")
+
+	require.NoError(t, store.Upsert(ctx, []Record{{
+		ID: "r1", Namespace: "ns", Text: "hello", Metadata: map[string]string{"source": "notes"}, Vector: []float64{1, 0},
+	}}))
+	require.Len(t, points, 1)
+	for _, p := range points {
+		assert.NotContains(t, p.Payload.Text, "hello")
+		assert.Empty(t, p.Payload.Metadata)
+		assert.Equal(t, []float64{1, 0}, p.Vector, "embeddings stay searchable")
+	}
+
+	matches, err := store.Query(ctx, "ns", []float64{1, 0}, 3)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "hello", matches[0].Text)
+	assert.Equal(t, "notes", matches[0].Metadata["source"])
+
+	matches, err = store.Query(router.WithTenantScope(context.Background(), "key-b"), "ns", []float64{1, 0}, 3)
+	require.NoError(t, err)
+	assert.Empty(t, matches, "records of another tenant are not readable")
+}
+
+func TestOpenRecord_Plaintext(t *testing.T) {
+	record := Record{Text: "hello", Metadata: map[string]string{"k": "v"}}
+	ok, err := openRecord(context.Background(), testKeyring(t), &record)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, Record{Text: "hello", Metadata: map[string]string{"k": "v"}}, record)
+}
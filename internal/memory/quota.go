@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded is matched by every QuotaExceededError.
+var ErrQuotaExceeded = errors.New("memory quota exceeded")
+
+// Kind distinguishes the items counted against a memory quota.
+type Kind string
+
+const (
+	// KindSession is a conversation session of SessionMemory.
+	KindSession Kind = "session"
+	// KindVector is a record of LongTermMemory.
+	KindVector Kind = "vector"
+)
+
+// Quota bounds the memory a tenant may hold. Zero fields are unlimited.
+type Quota struct {
+	MaxSessions int   // Stored conversation sessions
+	MaxVectors  int   // Stored long-term memory records
+	MaxBytes    int64 // Stored text across sessions and records
+}
+
+// Usage is the memory a tenant currently holds.
+type Usage struct {
+	Sessions int   `json:"sessions"`
+	Vectors  int   `json:"vectors"`
+	Bytes    int64 `json:"bytes"`
+}
+
+// QuotaExceededError reports the limit an operation would exceed.
+type QuotaExceededError struct {
+	Tenant   string
+	Resource string // "sessions", "vectors" or "bytes"
+	Limit    int64
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("memory quota exceeded: %s limit %d", e.Resource, e.Limit)
+}
+
+// Is reports whether target is ErrQuotaExceeded.
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// Check returns a QuotaExceededError when usage grown by add would exceed q.
+// Dimensions add does not grow are not checked, so a tenant over its limit
+// can still shrink or rewrite existing items.
+func (q Quota) Check(tenant string, usage, add Usage) error {
+	switch {
+	case q.MaxSessions > 0 && add.Sessions > 0 && usage.Sessions+add.Sessions > q.MaxSessions:
+		return &QuotaExceededError{Tenant: tenant, Resource: "sessions", Limit: int64(q.MaxSessions)}
+	case q.MaxVectors > 0 && add.Vectors > 0 && usage.Vectors+add.Vectors > q.MaxVectors:
+		return &QuotaExceededError{Tenant: tenant, Resource: "vectors", Limit: int64(q.MaxVectors)}
+	case q.MaxBytes > 0 && add.Bytes > 0 && usage.Bytes+add.Bytes > q.MaxBytes:
+		return &QuotaExceededError{Tenant: tenant, Resource: "bytes", Limit: q.MaxBytes}
+	}
+	return nil
+}
+
+// QuotaLedger tracks the size of every memory item per tenant so usage can
+// be checked against a Quota before writes.
+type QuotaLedger interface {
+	// Usage returns the tenant's current totals.
+	Usage(ctx context.Context, tenant string) (Usage, error)
+	// Size returns the tracked size of an item and whether it is tracked.
+	Size(ctx context.Context, tenant string, kind Kind, id string) (int64, bool, error)
+	// Track records an item's size, replacing any earlier size. A positive
+	// ttl stops counting the item once it elapses, matching stores that
+	// expire idle data.
+	Track(ctx context.Context, tenant string, kind Kind, id string, bytes int64, ttl time.Duration) error
+	// Untrack stops counting items.
+	Untrack(ctx context.Context, tenant string, kind Kind, ids ...string) error
+}
+
+type ledgerItem struct {
+	bytes     int64
+	expiresAt time.Time
+}
+
+// MemoryQuotaLedger is an in-process QuotaLedger. Usage is per instance and
+// lost on restart.
+type MemoryQuotaLedger struct {
+	mu      sync.Mutex
+	tenants map[string]map[Kind]map[string]ledgerItem
+	now     func() time.Time
+}
+
+// NewMemoryQuotaLedger creates an empty in-memory ledger.
+func NewMemoryQuotaLedger() *MemoryQuotaLedger {
+	return &MemoryQuotaLedger{tenants: make(map[string]map[Kind]map[string]ledgerItem), now: time.Now}
+}
+
+// Usage returns the tenant's current totals, dropping expired items.
+func (l *MemoryQuotaLedger) Usage(_ context.Context, tenant string) (Usage, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var usage Usage
+	now := l.now()
+	for kind, items := range l.tenants[tenant] {
+		for id, item := range items {
+			if !item.expiresAt.IsZero() && now.After(item.expiresAt) {
+				delete(items, id)
+				continue
+			}
+			usage.Bytes += item.bytes
+			switch kind {
+			case KindSession:
+				usage.Sessions++
+			case KindVector:
+				usage.Vectors++
+			}
+		}
+	}
+	return usage, nil
+}
+
+// Size returns the tracked size of an item.
+func (l *MemoryQuotaLedger) Size(_ context.Context, tenant string, kind Kind, id string) (int64, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	item, ok := l.tenants[tenant][kind][id]
+	if !ok || (!item.expiresAt.IsZero() && l.now().After(item.expiresAt)) {
+		return 0, false, nil
+	}
+	return item.bytes, true, nil
+}
+
+// Track records an item's size.
+func (l *MemoryQuotaLedger) Track(_ context.Context, tenant string, kind Kind, id string, bytes int64, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	kinds := l.tenants[tenant]
+	if kinds == nil {
+		kinds = make(map[Kind]map[string]ledgerItem)
+		l.tenants[tenant] = kinds
+	}
+	items := kinds[kind]
+	if items == nil {
+		items = make(map[string]ledgerItem)
+		kinds[kind] = items
+	}
+	item := ledgerItem{bytes: bytes}
+	if ttl > 0 {
+		item.expiresAt = l.now().Add(ttl)
+	}
+	items[id] = item
+	return nil
+}
+
+// Untrack stops counting items.
+func (l *MemoryQuotaLedger) Untrack(_ context.Context, tenant string, kind Kind, ids ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	items := l.tenants[tenant][kind]
+	for _, id := range ids {
+		delete(items, id)
+	}
+	return nil
+}
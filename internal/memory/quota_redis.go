@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// RedisQuotaLedger keeps per-tenant item sizes in Redis so quotas hold
+// across gateway instances. Each tenant and kind uses a hash of item sizes
+// and a sorted set of item expiries (+inf when the item never expires).
+type RedisQuotaLedger struct {
+	client goredis.UniversalClient
+	prefix string
+}
+
+// NewRedisQuotaLedger creates a Redis-backed ledger. prefix defaults to
+// "llmux:memquota:".
+func NewRedisQuotaLedger(client goredis.UniversalClient, prefix string) *RedisQuotaLedger {
+	if prefix == "" {
+		prefix = "llmux:memquota:"
+	}
+	return &RedisQuotaLedger{client: client, prefix: prefix}
+}
+
+// The hash and sorted set of one tenant and kind share a hash tag so the
+// scripts work on Redis Cluster.
+func (l *RedisQuotaLedger) keys(tenant string, kind Kind) []string {
+	base := l.prefix + "{" + tenant + "}:" + string(kind)
+	return []string{base + ":size", base + ":exp"}
+}
+
+// pruneScript removes expired items and returns the count and total size of
+// the remaining ones. KEYS: size hash, expiry zset. ARGV: now (unix ms).
+var pruneScript = goredis.NewScript(`
+local expired = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', '(' .. ARGV[1])
+for _, id in ipairs(expired) do
+	redis.call('HDEL', KEYS[1], id)
+end
+if #expired > 0 then
+	redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', '(' .. ARGV[1])
+end
+local total = 0
+for _, v in ipairs(redis.call('HVALS', KEYS[1])) do
+	total = total + tonumber(v)
+end
+return {redis.call('HLEN', KEYS[1]), total}
+`)
+
+// Usage returns the tenant's current totals, dropping expired items.
+func (l *RedisQuotaLedger) Usage(ctx context.Context, tenant string) (Usage, error) {
+	var usage Usage
+	now := time.Now().UnixMilli()
+	for _, kind := range []Kind{KindSession, KindVector} {
+		res, err := pruneScript.Run(ctx, l.client, l.keys(tenant, kind), now).Int64Slice()
+		if err != nil {
+			return Usage{}, fmt.Errorf("load memory usage: %w", err)
+		}
+		if kind == KindSession {
+			usage.Sessions = int(res[0])
+		} else {
+			usage.Vectors = int(res[0])
+		}
+		usage.Bytes += res[1]
+	}
+	return usage, nil
+}
+
+// Size returns the tracked size of an item.
+func (l *RedisQuotaLedger) Size(ctx context.Context, tenant string, kind Kind, id string) (int64, bool, error) {
+	keys := l.keys(tenant, kind)
+	pipe := l.client.Pipeline()
+	sizeCmd := pipe.HGet(ctx, keys[0], id)
+	expCmd := pipe.ZScore(ctx, keys[1], id)
+	if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+		return 0, false, fmt.Errorf("load memory item size: %w", err)
+	}
+	size, err := sizeCmd.Int64()
+	if err == goredis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("load memory item size: %w", err)
+	}
+	if exp, expErr := expCmd.Result(); expErr == nil && exp < float64(time.Now().UnixMilli()) {
+		return 0, false, nil
+	}
+	return size, true, nil
+}
+
+// Track records an item's size.
+func (l *RedisQuotaLedger) Track(ctx context.Context, tenant string, kind Kind, id string, bytes int64, ttl time.Duration) error {
+	keys := l.keys(tenant, kind)
+	expiry := math.Inf(1)
+	if ttl > 0 {
+		expiry = float64(time.Now().Add(ttl).UnixMilli())
+	}
+	pipe := l.client.TxPipeline()
+	pipe.HSet(ctx, keys[0], id, bytes)
+	pipe.ZAdd(ctx, keys[1], goredis.Z{Score: expiry, Member: id})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("track memory item: %w", err)
+	}
+	return nil
+}
+
+// Untrack stops counting items.
+func (l *RedisQuotaLedger) Untrack(ctx context.Context, tenant string, kind Kind, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	keys := l.keys(tenant, kind)
+	members := make([]any, len(ids))
+	for i, id := range ids {
+		members[i] = id
+	}
+	pipe := l.client.TxPipeline()
+	pipe.HDel(ctx, keys[0], ids...)
+	pipe.ZRem(ctx, keys[1], members...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("untrack memory items: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaCheck(t *testing.T) {
+	q := Quota{MaxSessions: 2, MaxVectors: 1, MaxBytes: 100}
+	usage := Usage{Sessions: 2, Vectors: 1, Bytes: 90}
+
+	err := q.Check("team:a", usage, Usage{Sessions: 1})
+	var exceeded *QuotaExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, "sessions", exceeded.Resource)
+	assert.True(t, errors.Is(err, ErrQuotaExceeded))
+
+	err = q.Check("team:a", usage, Usage{Bytes: 11})
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, "bytes", exceeded.Resource)
+
+	assert.NoError(t, q.Check("team:a", usage, Usage{Bytes: 10}))
+	assert.NoError(t, q.Check("team:a", Usage{Sessions: 5}, Usage{Bytes: 1}), "dimensions that do not grow are not checked")
+	assert.NoError(t, Quota{}.Check("team:a", usage, Usage{Sessions: 100, Vectors: 100, Bytes: 1 << 30}))
+}
+
+func testQuotaLedger(t *testing.T, ledger QuotaLedger, expire func(time.Duration)) {
+	t.Helper()
+	ctx := context.Background()
+
+	require.NoError(t, ledger.Track(ctx, "team:a", KindSession, "s1", 10, time.Minute))
+	require.NoError(t, ledger.Track(ctx, "team:a", KindSession, "s1", 15, time.Minute))
+	require.NoError(t, ledger.Track(ctx, "team:a", KindVector, "v1", 5, 0))
+	require.NoError(t, ledger.Track(ctx, "team:a", KindVector, "v2", 7, 0))
+	require.NoError(t, ledger.Track(ctx, "team:b", KindVector, "v1", 100, 0))
+
+	usage, err := ledger.Usage(ctx, "team:a")
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Sessions: 1, Vectors: 2, Bytes: 27}, usage)
+
+	size, tracked, err := ledger.Size(ctx, "team:a", KindSession, "s1")
+	require.NoError(t, err)
+	assert.True(t, tracked)
+	assert.Equal(t, int64(15), size)
+
+	require.NoError(t, ledger.Untrack(ctx, "team:a", KindVector, "v1"))
+	usage, err = ledger.Usage(ctx, "team:a")
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Sessions: 1, Vectors: 1, Bytes: 22}, usage)
+
+	expire(2 * time.Minute)
+	_, tracked, err = ledger.Size(ctx, "team:a", KindSession, "s1")
+	require.NoError(t, err)
+	assert.False(t, tracked, "expired sessions are no longer counted")
+	usage, err = ledger.Usage(ctx, "team:a")
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Vectors: 1, Bytes: 7}, usage)
+
+	usage, err = ledger.Usage(ctx, "team:b")
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Vectors: 1, Bytes: 100}, usage)
+}
+
+func TestMemoryQuotaLedger(t *testing.T) {
+	ledger := NewMemoryQuotaLedger()
+	now := time.Now()
+	ledger.now = func() time.Time { return now }
+	testQuotaLedger(t, ledger, func(d time.Duration) { now = now.Add(d) })
+}
+
+func TestRedisQuotaLedger(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	ledger := NewRedisQuotaLedger(client, "")
+
+	// Expiry is stored as a timestamp, so rewrite the session's score into
+	// the past rather than waiting.
+	testQuotaLedger(t, ledger, func(time.Duration) {
+		_, err := mr.ZAdd("llmux:memquota:{team:a}:session:exp", float64(time.Now().Add(-time.Minute).UnixMilli()), "s1")
+		require.NoError(t, err)
+	})
+}
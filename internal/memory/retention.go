@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+// SessionPruner is implemented by session stores that can drop idle
+// sessions. Stores with native expiry (Redis) do not need it.
+type SessionPruner interface {
+	// PruneSessions deletes sessions whose last turn is older than
+	// idleBefore and returns how many were deleted.
+	PruneSessions(ctx context.Context, idleBefore time.Time) (int, error)
+}
+
+// VectorPruner is implemented by vector stores that can drop old records.
+type VectorPruner interface {
+	// PruneVectors deletes records created before createdBefore across all
+	// namespaces and returns how many were deleted.
+	PruneVectors(ctx context.Context, createdBefore time.Time) (int, error)
+}
+
+// Prune deletes sessions idle since before idleBefore. It returns 0 when the
+// store expires sessions itself or cannot prune.
+func (m *SessionMemory) Prune(ctx context.Context, idleBefore time.Time) (int, error) {
+	pruner, ok := m.store.(SessionPruner)
+	if !ok {
+		return 0, nil
+	}
+	return pruner.PruneSessions(ctx, idleBefore)
+}
+
+// Prune deletes records created before createdBefore. It returns 0 when the
+// store cannot prune.
+func (m *LongTermMemory) Prune(ctx context.Context, createdBefore time.Time) (int, error) {
+	pruner, ok := m.store.(VectorPruner)
+	if !ok {
+		return 0, nil
+	}
+	return pruner.PruneVectors(ctx, createdBefore)
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+func TestSessionMemory_Prune(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySessionStore(0)
+	m := NewSessionMemory(store, Window{})
+	now := time.Now()
+
+	require.NoError(t, store.AppendTurn(ctx, "idle", Turn{Messages: []types.ChatMessage{{Role: "user"}}, CreatedAt: now.Add(-48 * time.Hour)}))
+	require.NoError(t, store.AppendTurn(ctx, "active", Turn{Messages: []types.ChatMessage{{Role: "user"}}, CreatedAt: now.Add(-48 * time.Hour)}))
+	require.NoError(t, store.AppendTurn(ctx, "active", Turn{Messages: []types.ChatMessage{{Role: "user"}}, CreatedAt: now}))
+
+	pruned, err := m.Prune(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+
+	turns, err := store.Turns(ctx, "idle", 0)
+	require.NoError(t, err)
+	assert.Empty(t, turns)
+	turns, err = store.Turns(ctx, "active", 0)
+	require.NoError(t, err)
+	assert.Len(t, turns, 2, "a recent turn keeps the whole session")
+}
+
+func TestLongTermMemory_Prune(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryVectorStore()
+	m := NewLongTermMemory(store, keywordEmbedder, LongTermConfig{})
+	now := time.Now()
+
+	require.NoError(t, store.Upsert(ctx, []Record{
+		{ID: "old", Namespace: "a", Text: "cat", Vector: []float64{1, 0, 0}, CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: "new", Namespace: "a", Text: "cat", Vector: []float64{1, 0, 0}, CreatedAt: now},
+		{ID: "old", Namespace: "b", Text: "cat", Vector: []float64{1, 0, 0}, CreatedAt: now.Add(-48 * time.Hour)},
+	}))
+
+	pruned, err := m.Prune(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, pruned)
+
+	matches, err := m.Recall(ctx, "a", "cat", 0)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "new", matches[0].ID)
+}
+
+func TestPostgresRetention(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	ctx := context.Background()
+	cutoff := time.Unix(1700000000, 0).UTC()
+
+	mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM session_turns WHERE session_id IN")).
+		WithArgs(cutoff).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	pruned, err := NewPostgresSessionStore(db, 0).PruneSessions(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, 3, pruned)
+
+	store, err := NewPgVectorStore(db, PgVectorConfig{Dimension: 2})
+	require.NoError(t, err)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM memory_vectors WHERE created_at < $1")).
+		WithArgs(cutoff).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	pruned, err = store.PruneVectors(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, 5, pruned)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQdrantVectorStore_PruneVectors(t *testing.T) {
+	var deleteFilter map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/collections/mem/points/count":
+			_, _ = w.Write([]byte(`{"result":{"count":2}}`))
+		case "/collections/mem/points/delete":
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			deleteFilter, _ = body["filter"].(map[string]any)
+			_, _ = w.Write([]byte(`{"result":true}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	store, err := NewQdrantVectorStore(QdrantVectorConfig{APIBase: server.URL, Collection: "mem"})
+	require.NoError(t, err)
+	pruned, err := store.PruneVectors(context.Background(), time.Unix(1700000000, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 2, pruned)
+	require.NotNil(t, deleteFilter, "points are deleted by filter")
+	assert.Contains(t, deleteFilter["must"].([]any)[0].(map[string]any), "range")
+}
@@ -0,0 +1,170 @@
+// Package memory provides conversation memory for LLM sessions.
+//
+// SessionMemory keeps the recent turns of a conversation keyed by session ID
+// so they can be replayed as history on the next request. Turns are persisted
+// through a SessionStore (in-memory, Redis or Postgres).
+//
+// LongTermMemory remembers texts as embeddings and recalls the ones most
+// similar to a query, persisting them through a VectorStore (in-memory,
+// pgvector, Qdrant or Milvus).
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/tokenizer"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// ErrInvalidSessionID is returned for empty or oversized session IDs.
+var ErrInvalidSessionID = errors.New("invalid session id")
+
+// MaxSessionIDLength bounds session IDs accepted by SessionMemory.
+const MaxSessionIDLength = 256
+
+// tokensPerMessage approximates the per-message overhead of chat formatting.
+const tokensPerMessage = 3
+
+// Turn is one exchange of a conversation: the messages the caller sent and
+// the assistant reply.
+type Turn struct {
+	Messages  []types.ChatMessage `json:"messages"`
+	Tokens    int                 `json:"tokens"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// SessionStore persists conversation turns.
+type SessionStore interface {
+	// AppendTurn adds a turn to the end of a session.
+	AppendTurn(ctx context.Context, sessionID string, turn Turn) error
+	// Turns returns the most recent limit turns of a session in chronological
+	// order (limit <= 0 returns all retained turns).
+	Turns(ctx context.Context, sessionID string, limit int) ([]Turn, error)
+	// DeleteSession removes all turns of a session.
+	DeleteSession(ctx context.Context, sessionID string) error
+	// Close releases the store's resources.
+	Close() error
+}
+
+// Window bounds the history replayed into a request. When both limits are
+// set the stricter one applies; older turns are dropped first.
+type Window struct {
+	MaxTurns  int // Most recent turns to replay (0 = unlimited)
+	MaxTokens int // Token budget for replayed turns (0 = unlimited)
+}
+
+// SessionMemory stores and replays conversation history within a window.
+type SessionMemory struct {
+	store  SessionStore
+	window Window
+}
+
+// NewSessionMemory creates a session memory over store.
+func NewSessionMemory(store SessionStore, window Window) *SessionMemory {
+	return &SessionMemory{store: store, window: window}
+}
+
+// Window returns the configured history window.
+func (m *SessionMemory) Window() Window {
+	return m.window
+}
+
+// History returns the messages of the session's turns that fit the window,
+// oldest first.
+func (m *SessionMemory) History(ctx context.Context, sessionID string) ([]types.ChatMessage, error) {
+	if err := validateSessionID(sessionID); err != nil {
+		return nil, err
+	}
+	turns, err := m.store.Turns(ctx, sessionID, m.window.MaxTurns)
+	if err != nil {
+		return nil, err
+	}
+
+	start := len(turns)
+	budget := m.window.MaxTokens
+	for start > 0 {
+		tokens := turns[start-1].Tokens
+		if budget > 0 && tokens > budget {
+			break
+		}
+		budget -= tokens
+		start--
+	}
+
+	var history []types.ChatMessage
+	for _, turn := range turns[start:] {
+		history = append(history, turn.Messages...)
+	}
+	return history, nil
+}
+
+// Record appends a turn made of messages to the session.
+func (m *SessionMemory) Record(ctx context.Context, sessionID string, messages []types.ChatMessage) error {
+	if err := validateSessionID(sessionID); err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return m.store.AppendTurn(ctx, sessionID, Turn{
+		Messages:  messages,
+		Tokens:    CountTokens(messages),
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+// Size returns the bytes of message content retained for the session.
+func (m *SessionMemory) Size(ctx context.Context, sessionID string) (int64, error) {
+	if err := validateSessionID(sessionID); err != nil {
+		return 0, err
+	}
+	turns, err := m.store.Turns(ctx, sessionID, 0)
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, turn := range turns {
+		size += MessagesSize(turn.Messages)
+	}
+	return size, nil
+}
+
+// MessagesSize returns the bytes of message content in messages.
+func MessagesSize(messages []types.ChatMessage) int64 {
+	var size int64
+	for _, msg := range messages {
+		size += int64(len(msg.Content))
+	}
+	return size
+}
+
+// Clear deletes the session's history.
+func (m *SessionMemory) Clear(ctx context.Context, sessionID string) error {
+	if err := validateSessionID(sessionID); err != nil {
+		return err
+	}
+	return m.store.DeleteSession(ctx, sessionID)
+}
+
+// Close closes the underlying store.
+func (m *SessionMemory) Close() error {
+	return m.store.Close()
+}
+
+// CountTokens estimates the prompt tokens used by messages.
+func CountTokens(messages []types.ChatMessage) int {
+	total := 0
+	for _, msg := range messages {
+		total += tokensPerMessage + tokenizer.CountTextTokens("", msg.TextContent())
+	}
+	return total
+}
+
+func validateSessionID(id string) error {
+	if id == "" || len(id) > MaxSessionIDLength {
+		return ErrInvalidSessionID
+	}
+	return nil
+}
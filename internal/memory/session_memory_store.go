@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemorySessionStore is an in-process SessionStore for single instances and
+// tests. History is lost on restart.
+type MemorySessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string][]Turn
+	maxTurns int
+}
+
+// NewMemorySessionStore creates an in-memory store retaining at most
+// maxTurns turns per session (0 = unlimited).
+func NewMemorySessionStore(maxTurns int) *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[string][]Turn),
+		maxTurns: maxTurns,
+	}
+}
+
+// AppendTurn adds a turn to the end of a session.
+func (s *MemorySessionStore) AppendTurn(_ context.Context, sessionID string, turn Turn) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	turns := append(s.sessions[sessionID], turn)
+	if s.maxTurns > 0 && len(turns) > s.maxTurns {
+		turns = append([]Turn(nil), turns[len(turns)-s.maxTurns:]...)
+	}
+	s.sessions[sessionID] = turns
+	return nil
+}
+
+// Turns returns the most recent limit turns of a session.
+func (s *MemorySessionStore) Turns(_ context.Context, sessionID string, limit int) ([]Turn, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	turns := s.sessions[sessionID]
+	if limit > 0 && len(turns) > limit {
+		turns = turns[len(turns)-limit:]
+	}
+	return append([]Turn(nil), turns...), nil
+}
+
+// DeleteSession removes all turns of a session.
+func (s *MemorySessionStore) DeleteSession(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+	return nil
+}
+
+// PruneSessions deletes sessions whose last turn is older than idleBefore.
+func (s *MemorySessionStore) PruneSessions(_ context.Context, idleBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pruned := 0
+	for id, turns := range s.sessions {
+		if len(turns) == 0 || turns[len(turns)-1].CreatedAt.Before(idleBefore) {
+			delete(s.sessions, id)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// Close is a no-op for the in-memory store.
+func (s *MemorySessionStore) Close() error {
+	return nil
+}
@@ -0,0 +1,180 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/pkg/encryption"
+)
+
+// PostgresSessionStore keeps turns in the session_turns table
+// (internal/auth/migrations/005_session_memory.sql).
+type PostgresSessionStore struct {
+	db       *sql.DB
+	maxTurns int
+	keyring  *encryption.Keyring
+}
+
+// NewPostgresSessionStore creates a Postgres-backed session store retaining
+// at most maxTurns turns per session (0 = unlimited).
+func NewPostgresSessionStore(db *sql.DB, maxTurns int) *PostgresSessionStore {
+	return &PostgresSessionStore{db: db, maxTurns: maxTurns}
+}
+
+// WithKeyring encrypts the messages of turns at rest with the tenant's data
+// key. They are stored as a base64 JSON string in the messages column.
+func (s *PostgresSessionStore) WithKeyring(keyring *encryption.Keyring) *PostgresSessionStore {
+	s.keyring = keyring
+	return s
+}
+
+// encodeMessages returns the messages column value of a turn.
+func (s *PostgresSessionStore) encodeMessages(ctx context.Context, messages []byte) (string, error) {
+	if s.keyring == nil {
+		return string(messages), nil
+	}
+	sealed, err := sealPayload(ctx, s.keyring, messages)
+	if err != nil {
+		return "", err
+	}
+	encoded, err := json.Marshal(base64.StdEncoding.EncodeToString(sealed))
+	return string(encoded), err
+}
+
+// decodeMessages reverses encodeMessages, accepting plaintext turns stored
+// before encryption was enabled. ok is false for turns that cannot be
+// decrypted for the request's tenant.
+func (s *PostgresSessionStore) decodeMessages(ctx context.Context, column string) (messages []byte, ok bool, err error) {
+	var encoded string
+	if json.Unmarshal([]byte(column), &encoded) != nil {
+		return []byte(column), true, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false, err
+	}
+	if s.keyring == nil {
+		return nil, false, fmt.Errorf("turn is encrypted and no keyring is configured")
+	}
+	return openPayload(ctx, s.keyring, sealed)
+}
+
+// AppendTurn adds a turn to the end of a session and prunes turns beyond
+// the retention limit.
+func (s *PostgresSessionStore) AppendTurn(ctx context.Context, sessionID string, turn Turn) error {
+	data, err := json.Marshal(turn.Messages)
+	if err != nil {
+		return fmt.Errorf("marshal turn: %w", err)
+	}
+	messages, err := s.encodeMessages(ctx, data)
+	if err != nil {
+		return fmt.Errorf("encrypt turn: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO session_turns (session_id, messages, tokens, created_at)
+		VALUES ($1, $2, $3, $4)`,
+		sessionID, messages, turn.Tokens, turn.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert session turn: %w", err)
+	}
+
+	if s.maxTurns > 0 {
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM session_turns
+			WHERE session_id = $1 AND id NOT IN (
+				SELECT id FROM session_turns WHERE session_id = $1 ORDER BY id DESC LIMIT $2
+			)`,
+			sessionID, s.maxTurns,
+		)
+		if err != nil {
+			return fmt.Errorf("prune session turns: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Turns returns the most recent limit turns of a session.
+func (s *PostgresSessionStore) Turns(ctx context.Context, sessionID string, limit int) ([]Turn, error) {
+	query := `
+		SELECT messages, tokens, created_at FROM (
+			SELECT id, messages, tokens, created_at FROM session_turns
+			WHERE session_id = $1 ORDER BY id DESC LIMIT $2
+		) recent ORDER BY id ASC`
+	var queryLimit any
+	if limit > 0 {
+		queryLimit = limit
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, sessionID, queryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("load session turns: %w", err)
+	}
+	defer rows.Close()
+
+	var turns []Turn
+	for rows.Next() {
+		var turn Turn
+		var messages string
+		if err := rows.Scan(&messages, &turn.Tokens, &turn.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan session turn: %w", err)
+		}
+		data, ok, err := s.decodeMessages(ctx, messages)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt session turn: %w", err)
+		}
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(data, &turn.Messages); err != nil {
+			return nil, fmt.Errorf("decode session turn: %w", err)
+		}
+		turns = append(turns, turn)
+	}
+	return turns, rows.Err()
+}
+
+// DeleteSession removes all turns of a session.
+func (s *PostgresSessionStore) DeleteSession(ctx context.Context, sessionID string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM session_turns WHERE session_id = $1`, sessionID); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	return nil
+}
+
+// PruneSessions deletes sessions whose last turn is older than idleBefore.
+func (s *PostgresSessionStore) PruneSessions(ctx context.Context, idleBefore time.Time) (int, error) {
+	var pruned int
+	err := s.db.QueryRowContext(ctx, `
+		WITH idle AS (
+			SELECT session_id FROM session_turns
+			GROUP BY session_id HAVING MAX(created_at) < $1
+		), deleted AS (
+			DELETE FROM session_turns WHERE session_id IN (SELECT session_id FROM idle)
+			RETURNING session_id
+		)
+		SELECT COUNT(DISTINCT session_id) FROM deleted`,
+		idleBefore,
+	).Scan(&pruned)
+	if err != nil {
+		return 0, fmt.Errorf("prune idle sessions: %w", err)
+	}
+	return pruned, nil
+}
+
+// Close closes the database handle.
+func (s *PostgresSessionStore) Close() error {
+	return s.db.Close()
+}
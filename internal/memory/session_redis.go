@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-json"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/blueberrycongee/llmux/pkg/encryption"
+)
+
+// RedisSessionConfig configures the Redis session store.
+type RedisSessionConfig struct {
+	Prefix   string        // Key prefix (default: "llmux:session:")
+	MaxTurns int           // Turns retained per session (0 = unlimited)
+	TTL      time.Duration // Idle expiry of a session (0 = never)
+	// Keyring encrypts turns at rest with the tenant's data key (optional).
+	Keyring *encryption.Keyring
+}
+
+// RedisSessionStore keeps each session as a Redis list of JSON-encoded turns,
+// so history is shared by every gateway instance.
+type RedisSessionStore struct {
+	client goredis.UniversalClient
+	cfg    RedisSessionConfig
+}
+
+// NewRedisSessionStore creates a Redis-backed session store.
+func NewRedisSessionStore(client goredis.UniversalClient, cfg RedisSessionConfig) *RedisSessionStore {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "llmux:session:"
+	}
+	return &RedisSessionStore{client: client, cfg: cfg}
+}
+
+func (s *RedisSessionStore) key(sessionID string) string {
+	return s.cfg.Prefix + sessionID
+}
+
+// AppendTurn adds a turn to the end of a session, trimming old turns and
+// refreshing the idle expiry.
+func (s *RedisSessionStore) AppendTurn(ctx context.Context, sessionID string, turn Turn) error {
+	data, err := json.Marshal(turn)
+	if err != nil {
+		return fmt.Errorf("marshal turn: %w", err)
+	}
+	if data, err = sealPayload(ctx, s.cfg.Keyring, data); err != nil {
+		return fmt.Errorf("encrypt turn: %w", err)
+	}
+	key := s.key(sessionID)
+	pipe := s.client.TxPipeline()
+	pipe.RPush(ctx, key, data)
+	if s.cfg.MaxTurns > 0 {
+		pipe.LTrim(ctx, key, int64(-s.cfg.MaxTurns), -1)
+	}
+	if s.cfg.TTL > 0 {
+		pipe.Expire(ctx, key, s.cfg.TTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("append session turn: %w", err)
+	}
+	return nil
+}
+
+// Turns returns the most recent limit turns of a session.
+func (s *RedisSessionStore) Turns(ctx context.Context, sessionID string, limit int) ([]Turn, error) {
+	start := int64(0)
+	if limit > 0 {
+		start = int64(-limit)
+	}
+	values, err := s.client.LRange(ctx, s.key(sessionID), start, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("load session turns: %w", err)
+	}
+	turns := make([]Turn, 0, len(values))
+	for _, v := range values {
+		data, ok, err := openPayload(ctx, s.cfg.Keyring, []byte(v))
+		if err != nil {
+			return nil, fmt.Errorf("decrypt session turn: %w", err)
+		}
+		if !ok {
+			continue
+		}
+		var turn Turn
+		if err := json.Unmarshal(data, &turn); err != nil {
+			return nil, fmt.Errorf("decode session turn: %w", err)
+		}
+		turns = append(turns, turn)
+	}
+	return turns, nil
+}
+
+// DeleteSession removes all turns of a session.
+func (s *RedisSessionStore) DeleteSession(ctx context.Context, sessionID string) error {
+	if err := s.client.Del(ctx, s.key(sessionID)).Err(); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	return nil
+}
+
+// Close closes the Redis client.
+func (s *RedisSessionStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,207 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/encryption"
+	"github.com/blueberrycongee/llmux/pkg/router"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+func message(role, text string) types.ChatMessage {
+	return types.ChatMessage{Role: role, Content: []byte(fmt.Sprintf("%q", text))}
+}
+
+func exchange(i int) []types.ChatMessage {
+	return []types.ChatMessage{
+		message("user", fmt.Sprintf("question %d", i)),
+		message("assistant", fmt.Sprintf("answer %d", i)),
+	}
+}
+
+func contents(messages []types.ChatMessage) []string {
+	out := make([]string, 0, len(messages))
+	for _, m := range messages {
+		out = append(out, m.TextContent())
+	}
+	return out
+}
+
+func TestSessionMemory_WindowByTurns(t *testing.T) {
+	ctx := context.Background()
+	m := NewSessionMemory(NewMemorySessionStore(0), Window{MaxTurns: 2})
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, m.Record(ctx, "s1", exchange(i)))
+	}
+
+	history, err := m.History(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"question 2", "answer 2", "question 3", "answer 3"}, contents(history))
+
+	other, err := m.History(ctx, "s2")
+	require.NoError(t, err)
+	assert.Empty(t, other)
+}
+
+func TestSessionMemory_WindowByTokens(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySessionStore(0)
+	m := NewSessionMemory(store, Window{})
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, m.Record(ctx, "s1", exchange(i)))
+	}
+	turns, err := store.Turns(ctx, "s1", 0)
+	require.NoError(t, err)
+	require.Len(t, turns, 3)
+	perTurn := turns[0].Tokens
+	require.Positive(t, perTurn)
+
+	budgeted := NewSessionMemory(store, Window{MaxTokens: 2*perTurn + 1})
+	history, err := budgeted.History(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"question 2", "answer 2", "question 3", "answer 3"}, contents(history))
+
+	tiny := NewSessionMemory(store, Window{MaxTokens: 1})
+	history, err = tiny.History(ctx, "s1")
+	require.NoError(t, err)
+	assert.Empty(t, history, "a turn larger than the budget is never replayed partially")
+}
+
+func TestSessionMemory_ClearAndInvalidID(t *testing.T) {
+	ctx := context.Background()
+	m := NewSessionMemory(NewMemorySessionStore(0), Window{})
+
+	require.NoError(t, m.Record(ctx, "s1", exchange(1)))
+	require.NoError(t, m.Clear(ctx, "s1"))
+	history, err := m.History(ctx, "s1")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	_, err = m.History(ctx, "")
+	assert.ErrorIs(t, err, ErrInvalidSessionID)
+	assert.ErrorIs(t, m.Record(ctx, strings.Repeat("x", MaxSessionIDLength+1), exchange(1)), ErrInvalidSessionID)
+}
+
+func TestMemorySessionStore_RetainsMaxTurns(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySessionStore(2)
+	m := NewSessionMemory(store, Window{})
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, m.Record(ctx, "s1", exchange(i)))
+	}
+	turns, err := store.Turns(ctx, "s1", 0)
+	require.NoError(t, err)
+	require.Len(t, turns, 2)
+	assert.Equal(t, "question 4", turns[0].Messages[0].TextContent())
+}
+
+func TestRedisSessionStore(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	store := NewRedisSessionStore(client, RedisSessionConfig{MaxTurns: 3, TTL: time.Hour})
+	t.Cleanup(func() { _ = store.Close() })
+	m := NewSessionMemory(store, Window{MaxTurns: 2})
+
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, m.Record(ctx, "s1", exchange(i)))
+	}
+
+	history, err := m.History(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"question 3", "answer 3", "question 4", "answer 4"}, contents(history))
+
+	all, err := store.Turns(ctx, "s1", 0)
+	require.NoError(t, err)
+	assert.Len(t, all, 3, "turns beyond max_turns are trimmed")
+	assert.Equal(t, time.Hour, mr.TTL("llmux:session:s1"))
+
+	require.NoError(t, m.Clear(ctx, "s1"))
+	assert.False(t, mr.Exists("llmux:session:s1"))
+}
+
+func testKeyring(t *testing.T) *encryption.Keyring {
+	t.Helper()
+	keyring, err := encryption.NewKeyring(bytes.Repeat([]byte{1}, encryption.KeySize))
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestRedisSessionStore_Encrypted(t *testing.T) {
+	ctx := router.WithTenantScope(context.Background(), "key-a")
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	store := NewRedisSessionStore(client, RedisSessionConfig{Keyring: testKeyring(t)})
+	t.Cleanup(func() { _ = store.Close() })
+
+	require.NoError(t, store.AppendTurn(ctx, "s1", Turn{Messages: exchange(1)}))
+	raw, err := mr.List("llmux:session:s1")
+	require.NoError(t, err)
+	require.Len(t, raw, 1)
+	assert.True(t, encryption.IsEncrypted([]byte(raw[0])))
+	assert.NotContains(t, raw[0], "question 1")
+
+	turns, err := store.Turns(ctx, "s1", 0)
+	require.NoError(t, err)
+	require.Len(t, turns, 1)
+	assert.Equal(t, []string{"question 1", "answer 1"}, contents(turns[0].Messages))
+
+	other, err := store.Turns(router.WithTenantScope(context.Background(), "key-b"), "s1", 0)
+	require.NoError(t, err)
+	assert.Empty(t, other, "turns of another tenant are not readable")
+}
+
+func TestPostgresSessionStore_Encrypted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	ctx := router.WithTenantScope(context.Background(), "key-a")
+	store := NewPostgresSessionStore(db, 0).WithKeyring(testKeyring(t))
+
+	var stored string
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO session_turns").
+		WithArgs("s1", capture(&stored), 0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	require.NoError(t, store.AppendTurn(ctx, "s1", Turn{Messages: exchange(1)}))
+	assert.NotContains(t, stored, "question 1")
+
+	plaintext := `[{"role":"user","content":"legacy"}]`
+	mock.ExpectQuery("SELECT messages, tokens, created_at").
+		WithArgs("s1", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"messages", "tokens", "created_at"}).
+			AddRow(plaintext, 0, time.Now()).
+			AddRow(stored, 0, time.Now()))
+	turns, err := store.Turns(ctx, "s1", 0)
+	require.NoError(t, err)
+	require.Len(t, turns, 2)
+	assert.Equal(t, "legacy", turns[0].Messages[0].TextContent(), "plaintext turns stay readable")
+	assert.Equal(t, []string{"question 1", "answer 1"}, contents(turns[1].Messages))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// captureArg records the value a sqlmock argument was called with.
+type captureArg struct{ value *string }
+
+func capture(value *string) captureArg { return captureArg{value: value} }
+
+func (c captureArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	*c.value = s
+	return ok
+}
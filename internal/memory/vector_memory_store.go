@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryVectorStore is an in-process VectorStore that scans every record of
+// a namespace per query. It suits single instances, small memories and
+// tests; records are lost on restart.
+type MemoryVectorStore struct {
+	mu         sync.RWMutex
+	namespaces map[string]map[string]Record
+}
+
+// NewMemoryVectorStore creates an empty in-memory vector store.
+func NewMemoryVectorStore() *MemoryVectorStore {
+	return &MemoryVectorStore{namespaces: make(map[string]map[string]Record)}
+}
+
+// Upsert stores records, replacing any with the same namespace and ID.
+func (s *MemoryVectorStore) Upsert(_ context.Context, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range records {
+		ns := s.namespaces[r.Namespace]
+		if ns == nil {
+			ns = make(map[string]Record)
+			s.namespaces[r.Namespace] = ns
+		}
+		r.Vector = append([]float64(nil), r.Vector...)
+		ns[r.ID] = r
+	}
+	return nil
+}
+
+// Query returns the records of namespace most similar to vector.
+func (s *MemoryVectorStore) Query(_ context.Context, namespace string, vector []float64, topK int) ([]Match, error) {
+	s.mu.RLock()
+	matches := make([]Match, 0, len(s.namespaces[namespace]))
+	for _, r := range s.namespaces[namespace] {
+		matches = append(matches, Match{Record: r, Score: cosineSimilarity(vector, r.Vector)})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if topK > 0 && len(matches) > topK {
+		matches = matches[:topK]
+	}
+	return matches, nil
+}
+
+// Delete removes records of namespace by ID.
+func (s *MemoryVectorStore) Delete(_ context.Context, namespace string, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ns := s.namespaces[namespace]
+	for _, id := range ids {
+		delete(ns, id)
+	}
+	if len(ns) == 0 {
+		delete(s.namespaces, namespace)
+	}
+	return nil
+}
+
+// PruneVectors deletes records created before createdBefore.
+func (s *MemoryVectorStore) PruneVectors(_ context.Context, createdBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pruned := 0
+	for namespace, ns := range s.namespaces {
+		for id, r := range ns {
+			if r.CreatedAt.Before(createdBefore) {
+				delete(ns, id)
+				pruned++
+			}
+		}
+		if len(ns) == 0 {
+			delete(s.namespaces, namespace)
+		}
+	}
+	return pruned, nil
+}
+
+// Close is a no-op for the in-memory store.
+func (s *MemoryVectorStore) Close() error {
+	return nil
+}
@@ -0,0 +1,266 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/pkg/encryption"
+)
+
+// MilvusVectorConfig configures the Milvus store.
+type MilvusVectorConfig struct {
+	Address    string // REST endpoint, e.g. http://localhost:19530
+	Token      string // "user:password" or an API key
+	Database   string
+	Collection string
+	Dimension  int // Embedding size; required by EnsureCollection
+	Timeout    time.Duration
+	// Keyring, when set, encrypts record text and metadata with the
+	// tenant's data key.
+	Keyring *encryption.Keyring
+}
+
+// MilvusVectorStore keeps memories in one Milvus collection through the v2
+// REST API, isolating namespaces with a scalar filter.
+type MilvusVectorStore struct {
+	client     *http.Client
+	address    string
+	token      string
+	database   string
+	collection string
+	dimension  int
+	keyring    *encryption.Keyring
+}
+
+// milvusOutputFields are the scalar fields returned with search hits.
+var milvusOutputFields = []string{"namespace", "record_id", "text", "metadata", "created_at"}
+
+// NewMilvusVectorStore creates a Milvus-backed store. Call EnsureCollection
+// once to create and load the collection.
+func NewMilvusVectorStore(cfg MilvusVectorConfig) (*MilvusVectorStore, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("milvus address is required")
+	}
+	if cfg.Collection == "" {
+		return nil, fmt.Errorf("milvus collection is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &MilvusVectorStore{
+		client:     &http.Client{Timeout: cfg.Timeout},
+		address:    strings.TrimRight(cfg.Address, "/"),
+		token:      cfg.Token,
+		database:   cfg.Database,
+		collection: cfg.Collection,
+		dimension:  cfg.Dimension,
+		keyring:    cfg.Keyring,
+	}, nil
+}
+
+// EnsureCollection creates the collection with a COSINE vector index when
+// missing. Milvus loads new collections automatically.
+func (m *MilvusVectorStore) EnsureCollection(ctx context.Context) error {
+	var has struct {
+		Has bool `json:"has"`
+	}
+	if err := m.do(ctx, "/collections/has", map[string]any{}, &has); err != nil {
+		return fmt.Errorf("check milvus collection: %w", err)
+	}
+	if has.Has {
+		return nil
+	}
+	if m.dimension <= 0 {
+		return fmt.Errorf("milvus dimension is required")
+	}
+
+	varchar := func(name string, maxLength int, primary bool) map[string]any {
+		field := map[string]any{
+			"fieldName":         name,
+			"dataType":          "VarChar",
+			"elementTypeParams": map[string]any{"max_length": strconv.Itoa(maxLength)},
+		}
+		if primary {
+			field["isPrimary"] = true
+		}
+		return field
+	}
+	create := map[string]any{
+		"schema": map[string]any{
+			"autoId": false,
+			"fields": []map[string]any{
+				varchar("pk", 64, true),
+				varchar("namespace", MaxNamespaceLength, false),
+				varchar("record_id", 256, false),
+				varchar("text", 65535, false),
+				{"fieldName": "metadata", "dataType": "JSON"},
+				{"fieldName": "created_at", "dataType": "Int64"},
+				{
+					"fieldName":         "vector",
+					"dataType":          "FloatVector",
+					"elementTypeParams": map[string]any{"dim": strconv.Itoa(m.dimension)},
+				},
+			},
+		},
+		"indexParams": []map[string]any{
+			{"fieldName": "vector", "indexName": "vector_idx", "metricType": "COSINE"},
+		},
+	}
+	if err := m.do(ctx, "/collections/create", create, nil); err != nil {
+		return fmt.Errorf("create milvus collection: %w", err)
+	}
+	return nil
+}
+
+// Upsert stores records, replacing any with the same namespace and ID.
+func (m *MilvusVectorStore) Upsert(ctx context.Context, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	rows := make([]map[string]any, 0, len(records))
+	for _, r := range records {
+		text, metadata, err := sealRecord(ctx, m.keyring, r)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, map[string]any{
+			"pk":         pointID(r.Namespace, r.ID),
+			"namespace":  r.Namespace,
+			"record_id":  r.ID,
+			"text":       text,
+			"metadata":   nonNilMetadata(metadata),
+			"created_at": r.CreatedAt.Unix(),
+			"vector":     r.Vector,
+		})
+	}
+	if err := m.do(ctx, "/entities/upsert", map[string]any{"data": rows}, nil); err != nil {
+		return fmt.Errorf("milvus upsert: %w", err)
+	}
+	return nil
+}
+
+// Query returns the records of namespace most similar to vector. With the
+// COSINE metric Milvus reports similarity in the distance field.
+func (m *MilvusVectorStore) Query(ctx context.Context, namespace string, vector []float64, topK int) ([]Match, error) {
+	body := map[string]any{
+		"data":         [][]float64{vector},
+		"annsField":    "vector",
+		"filter":       "namespace == " + strconv.Quote(namespace),
+		"limit":        topK,
+		"outputFields": milvusOutputFields,
+	}
+	var hits []struct {
+		Distance  float64           `json:"distance"`
+		Namespace string            `json:"namespace"`
+		RecordID  string            `json:"record_id"`
+		Text      string            `json:"text"`
+		Metadata  map[string]string `json:"metadata"`
+		CreatedAt int64             `json:"created_at"`
+	}
+	if err := m.do(ctx, "/entities/search", body, &hits); err != nil {
+		return nil, fmt.Errorf("milvus search: %w", err)
+	}
+
+	matches := make([]Match, 0, len(hits))
+	for _, h := range hits {
+		if h.Namespace != namespace {
+			continue
+		}
+		match := Match{
+			Record: Record{
+				ID:        h.RecordID,
+				Namespace: h.Namespace,
+				Text:      h.Text,
+				Metadata:  h.Metadata,
+				CreatedAt: time.Unix(h.CreatedAt, 0).UTC(),
+			},
+			Score: h.Distance,
+		}
+		if ok, err := openRecord(ctx, m.keyring, &match.Record); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// Delete removes records of namespace by ID.
+func (m *MilvusVectorStore) Delete(ctx context.Context, namespace string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	pks := make([]string, 0, len(ids))
+	for _, id := range ids {
+		pks = append(pks, strconv.Quote(pointID(namespace, id)))
+	}
+	filter := "pk in [" + strings.Join(pks, ",") + "]"
+	if err := m.do(ctx, "/entities/delete", map[string]any{"filter": filter}, nil); err != nil {
+		return fmt.Errorf("milvus delete: %w", err)
+	}
+	return nil
+}
+
+// PruneVectors deletes records created before createdBefore. The entities
+// are counted first since Milvus does not report how many a delete removed.
+func (m *MilvusVectorStore) PruneVectors(ctx context.Context, createdBefore time.Time) (int, error) {
+	filter := fmt.Sprintf("created_at < %d", createdBefore.Unix())
+	var rows []struct {
+		Count int `json:"count(*)"`
+	}
+	if err := m.do(ctx, "/entities/query", map[string]any{"filter": filter, "outputFields": []string{"count(*)"}}, &rows); err != nil {
+		return 0, fmt.Errorf("milvus count: %w", err)
+	}
+	if len(rows) == 0 || rows[0].Count == 0 {
+		return 0, nil
+	}
+	if err := m.do(ctx, "/entities/delete", map[string]any{"filter": filter}, nil); err != nil {
+		return 0, fmt.Errorf("milvus delete: %w", err)
+	}
+	return rows[0].Count, nil
+}
+
+// Close releases idle connections.
+func (m *MilvusVectorStore) Close() error {
+	m.client.CloseIdleConnections()
+	return nil
+}
+
+// do calls a v2 REST endpoint. Milvus reports failures in the body's code
+// field with HTTP 200, so both are checked.
+func (m *MilvusVectorStore) do(ctx context.Context, path string, body map[string]any, out any) error {
+	body["collectionName"] = m.collection
+	if m.database != "" {
+		body["dbName"] = m.database
+	}
+	var headers map[string]string
+	if m.token != "" {
+		headers = map[string]string{"Authorization": "Bearer " + m.token}
+	}
+
+	var resp struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := doJSON(ctx, m.client, http.MethodPost, m.address+"/v2/vectordb"+path, headers, body, &resp); err != nil {
+		return err
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("code=%d, message=%s", resp.Code, resp.Message)
+	}
+	if out == nil || len(resp.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("decode response data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/lib/pq"
+
+	"github.com/blueberrycongee/llmux/pkg/encryption"
+)
+
+// defaultPgVectorTable is the table used when PgVectorConfig.Table is empty.
+const defaultPgVectorTable = "memory_vectors"
+
+var pgIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// PgVectorConfig configures the pgvector store.
+type PgVectorConfig struct {
+	Table     string // Table holding the memories (default "memory_vectors")
+	Dimension int    // Embedding size; required by EnsureSchema
+	// Keyring, when set, encrypts record text and metadata with the
+	// tenant's data key.
+	Keyring *encryption.Keyring
+}
+
+// PgVectorStore keeps memories in a Postgres table with a pgvector column and
+// ranks them by cosine distance.
+type PgVectorStore struct {
+	db        *sql.DB
+	table     string
+	dimension int
+	keyring   *encryption.Keyring
+}
+
+// NewPgVectorStore creates a pgvector-backed store on db. Call EnsureSchema
+// once to create the extension, table and index.
+func NewPgVectorStore(db *sql.DB, cfg PgVectorConfig) (*PgVectorStore, error) {
+	table := cfg.Table
+	if table == "" {
+		table = defaultPgVectorTable
+	}
+	if !pgIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("invalid pgvector table name %q", table)
+	}
+	return &PgVectorStore{db: db, table: table, dimension: cfg.Dimension, keyring: cfg.Keyring}, nil
+}
+
+// EnsureSchema creates the vector extension, the memory table and its HNSW
+// cosine index when missing. The extension must be installable by the
+// connecting role.
+func (s *PgVectorStore) EnsureSchema(ctx context.Context) error {
+	if s.dimension <= 0 {
+		return fmt.Errorf("pgvector dimension is required")
+	}
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS vector`,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			namespace TEXT NOT NULL,
+			id TEXT NOT NULL,
+			text TEXT NOT NULL,
+			metadata JSONB NOT NULL DEFAULT '{}',
+			embedding vector(%d) NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (namespace, id)
+		)`, s.table, s.dimension),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s_embedding_idx ON %s USING hnsw (embedding vector_cosine_ops)`, s.table, s.table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s_created_at_idx ON %s (created_at)`, s.table, s.table),
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("ensure pgvector schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// Upsert stores records, replacing any with the same namespace and ID.
+func (s *PgVectorStore) Upsert(ctx context.Context, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (namespace, id, text, metadata, embedding, created_at)
+		VALUES ($1, $2, $3, $4, $5::vector, $6)
+		ON CONFLICT (namespace, id) DO UPDATE SET
+			text = EXCLUDED.text,
+			metadata = EXCLUDED.metadata,
+			embedding = EXCLUDED.embedding,
+			created_at = EXCLUDED.created_at`, s.table)
+	for _, r := range records {
+		text, meta, err := sealRecord(ctx, s.keyring, r)
+		if err != nil {
+			return err
+		}
+		metadata, err := json.Marshal(nonNilMetadata(meta))
+		if err != nil {
+			return fmt.Errorf("marshal metadata: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query,
+			r.Namespace, r.ID, text, string(metadata), vectorLiteral(r.Vector), r.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("upsert memory: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Query returns the records of namespace nearest to vector by cosine distance.
+func (s *PgVectorStore) Query(ctx context.Context, namespace string, vector []float64, topK int) ([]Match, error) {
+	query := fmt.Sprintf(`
+		SELECT id, text, metadata, created_at, 1 - (embedding <=> $2::vector) AS score
+		FROM %s WHERE namespace = $1
+		ORDER BY embedding <=> $2::vector
+		LIMIT $3`, s.table)
+	rows, err := s.db.QueryContext(ctx, query, namespace, vectorLiteral(vector), topK)
+	if err != nil {
+		return nil, fmt.Errorf("query memories: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []Match
+	for rows.Next() {
+		match := Match{Record: Record{Namespace: namespace}}
+		var metadata string
+		if err := rows.Scan(&match.ID, &match.Text, &metadata, &match.CreatedAt, &match.Score); err != nil {
+			return nil, fmt.Errorf("scan memory: %w", err)
+		}
+		if err := json.Unmarshal([]byte(metadata), &match.Metadata); err != nil {
+			return nil, fmt.Errorf("decode memory metadata: %w", err)
+		}
+		if ok, err := openRecord(ctx, s.keyring, &match.Record); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
+// Delete removes records of namespace by ID.
+func (s *PgVectorStore) Delete(ctx context.Context, namespace string, ids []string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE namespace = $1 AND id = ANY($2)`, s.table)
+	if _, err := s.db.ExecContext(ctx, query, namespace, pq.Array(ids)); err != nil {
+		return fmt.Errorf("delete memories: %w", err)
+	}
+	return nil
+}
+
+// PruneVectors deletes records created before createdBefore.
+func (s *PgVectorStore) PruneVectors(ctx context.Context, createdBefore time.Time) (int, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE created_at < $1`, s.table)
+	res, err := s.db.ExecContext(ctx, query, createdBefore)
+	if err != nil {
+		return 0, fmt.Errorf("prune memories: %w", err)
+	}
+	pruned, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("prune memories: %w", err)
+	}
+	return int(pruned), nil
+}
+
+// Close closes the database handle.
+func (s *PgVectorStore) Close() error {
+	return s.db.Close()
+}
+
+// vectorLiteral formats v in pgvector's text representation, e.g. "[1,2.5]".
+func vectorLiteral(v []float64) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(x, 'g', -1, 64))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+func nonNilMetadata(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
@@ -0,0 +1,233 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blueberrycongee/llmux/pkg/encryption"
+)
+
+// QdrantVectorConfig configures the Qdrant store.
+type QdrantVectorConfig struct {
+	APIBase    string
+	APIKey     string
+	Collection string
+	Dimension  int // Embedding size; required by EnsureCollection
+	Timeout    time.Duration
+	// Keyring, when set, encrypts record text and metadata with the
+	// tenant's data key.
+	Keyring *encryption.Keyring
+}
+
+// QdrantVectorStore keeps memories as points of one Qdrant collection,
+// isolating namespaces with a keyword-indexed payload filter.
+type QdrantVectorStore struct {
+	client     *http.Client
+	apiBase    string
+	apiKey     string
+	collection string
+	dimension  int
+	keyring    *encryption.Keyring
+}
+
+// NewQdrantVectorStore creates a Qdrant-backed store. Call EnsureCollection
+// once to create the collection and its namespace index.
+func NewQdrantVectorStore(cfg QdrantVectorConfig) (*QdrantVectorStore, error) {
+	if cfg.APIBase == "" {
+		return nil, fmt.Errorf("qdrant api_base is required")
+	}
+	if cfg.Collection == "" {
+		return nil, fmt.Errorf("qdrant collection is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &QdrantVectorStore{
+		client:     &http.Client{Timeout: cfg.Timeout},
+		apiBase:    strings.TrimRight(cfg.APIBase, "/"),
+		apiKey:     cfg.APIKey,
+		collection: cfg.Collection,
+		dimension:  cfg.Dimension,
+		keyring:    cfg.Keyring,
+	}, nil
+}
+
+// EnsureCollection creates the collection with cosine distance and a keyword
+// index on the namespace payload field when missing.
+func (q *QdrantVectorStore) EnsureCollection(ctx context.Context) error {
+	var exists struct {
+		Result struct {
+			Exists bool `json:"exists"`
+		} `json:"result"`
+	}
+	if err := q.do(ctx, http.MethodGet, "/exists", nil, &exists); err != nil {
+		return fmt.Errorf("check qdrant collection: %w", err)
+	}
+	if exists.Result.Exists {
+		return nil
+	}
+	if q.dimension <= 0 {
+		return fmt.Errorf("qdrant dimension is required")
+	}
+
+	create := map[string]any{
+		"vectors": map[string]any{"size": q.dimension, "distance": "Cosine"},
+	}
+	if err := q.do(ctx, http.MethodPut, "", create, nil); err != nil {
+		return fmt.Errorf("create qdrant collection: %w", err)
+	}
+	index := map[string]any{"field_name": "namespace", "field_schema": "keyword"}
+	if err := q.do(ctx, http.MethodPut, "/index", index, nil); err != nil {
+		return fmt.Errorf("create qdrant namespace index: %w", err)
+	}
+	return nil
+}
+
+// Upsert stores records, replacing any with the same namespace and ID.
+func (q *QdrantVectorStore) Upsert(ctx context.Context, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	points := make([]qdrantMemoryPoint, 0, len(records))
+	for _, r := range records {
+		text, metadata, err := sealRecord(ctx, q.keyring, r)
+		if err != nil {
+			return err
+		}
+		points = append(points, qdrantMemoryPoint{
+			ID:     pointID(r.Namespace, r.ID),
+			Vector: r.Vector,
+			Payload: qdrantMemoryPayload{
+				Namespace: r.Namespace,
+				ID:        r.ID,
+				Text:      text,
+				Metadata:  metadata,
+				CreatedAt: r.CreatedAt.Unix(),
+			},
+		})
+	}
+	if err := q.do(ctx, http.MethodPut, "/points?wait=true", map[string]any{"points": points}, nil); err != nil {
+		return fmt.Errorf("qdrant upsert: %w", err)
+	}
+	return nil
+}
+
+// Query returns the records of namespace most similar to vector.
+func (q *QdrantVectorStore) Query(ctx context.Context, namespace string, vector []float64, topK int) ([]Match, error) {
+	body := map[string]any{
+		"vector":       vector,
+		"limit":        topK,
+		"with_payload": true,
+		"filter":       namespaceFilter(namespace),
+	}
+	var resp struct {
+		Result []struct {
+			Score   float64             `json:"score"`
+			Payload qdrantMemoryPayload `json:"payload"`
+		} `json:"result"`
+	}
+	if err := q.do(ctx, http.MethodPost, "/points/search", body, &resp); err != nil {
+		return nil, fmt.Errorf("qdrant search: %w", err)
+	}
+
+	matches := make([]Match, 0, len(resp.Result))
+	for _, r := range resp.Result {
+		if r.Payload.Namespace != namespace {
+			continue
+		}
+		match := Match{
+			Record: Record{
+				ID:        r.Payload.ID,
+				Namespace: r.Payload.Namespace,
+				Text:      r.Payload.Text,
+				Metadata:  r.Payload.Metadata,
+				CreatedAt: time.Unix(r.Payload.CreatedAt, 0).UTC(),
+			},
+			Score: r.Score,
+		}
+		if ok, err := openRecord(ctx, q.keyring, &match.Record); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// Delete removes records of namespace by ID.
+func (q *QdrantVectorStore) Delete(ctx context.Context, namespace string, ids []string) error {
+	points := make([]string, 0, len(ids))
+	for _, id := range ids {
+		points = append(points, pointID(namespace, id))
+	}
+	if err := q.do(ctx, http.MethodPost, "/points/delete?wait=true", map[string]any{"points": points}, nil); err != nil {
+		return fmt.Errorf("qdrant delete: %w", err)
+	}
+	return nil
+}
+
+// PruneVectors deletes records created before createdBefore. The points are
+// counted first since Qdrant does not report how many a delete removed.
+func (q *QdrantVectorStore) PruneVectors(ctx context.Context, createdBefore time.Time) (int, error) {
+	filter := map[string]any{
+		"must": []map[string]any{
+			{"key": "created_at", "range": map[string]any{"lt": createdBefore.Unix()}},
+		},
+	}
+	var count struct {
+		Result struct {
+			Count int `json:"count"`
+		} `json:"result"`
+	}
+	if err := q.do(ctx, http.MethodPost, "/points/count", map[string]any{"filter": filter, "exact": true}, &count); err != nil {
+		return 0, fmt.Errorf("qdrant count: %w", err)
+	}
+	if count.Result.Count == 0 {
+		return 0, nil
+	}
+	if err := q.do(ctx, http.MethodPost, "/points/delete?wait=true", map[string]any{"filter": filter}, nil); err != nil {
+		return 0, fmt.Errorf("qdrant delete: %w", err)
+	}
+	return count.Result.Count, nil
+}
+
+// Close releases idle connections.
+func (q *QdrantVectorStore) Close() error {
+	q.client.CloseIdleConnections()
+	return nil
+}
+
+func (q *QdrantVectorStore) do(ctx context.Context, method, path string, body, out any) error {
+	var headers map[string]string
+	if q.apiKey != "" {
+		headers = map[string]string{"api-key": q.apiKey}
+	}
+	url := fmt.Sprintf("%s/collections/%s%s", q.apiBase, q.collection, path)
+	return doJSON(ctx, q.client, method, url, headers, body, out)
+}
+
+func namespaceFilter(namespace string) map[string]any {
+	return map[string]any{
+		"must": []map[string]any{
+			{"key": "namespace", "match": map[string]any{"value": namespace}},
+		},
+	}
+}
+
+type qdrantMemoryPoint struct {
+	ID      string              `json:"id"`
+	Vector  []float64           `json:"vector"`
+	Payload qdrantMemoryPayload `json:"payload"`
+}
+
+type qdrantMemoryPayload struct {
+	Namespace string            `json:"namespace"`
+	ID        string            `json:"record_id"`
+	Text      string            `json:"text"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	CreatedAt int64             `json:"created_at"`
+}
@@ -190,6 +190,42 @@ func (c *Collector) RecordActiveRequest(deploymentID, model, provider string, de
 	ActiveRequests.WithLabelValues(deploymentID, model, provider).Add(delta)
 }
 
+// RecordRouterResponse observes the latency and time to first token of a
+// successful request reported to the router.
+func (c *Collector) RecordRouterResponse(deploymentID, model, provider string, latency, ttft time.Duration) {
+	RouterDeploymentLatency.WithLabelValues(deploymentID, model, provider).Observe(latency.Seconds())
+	if ttft > 0 {
+		RouterDeploymentTTFT.WithLabelValues(deploymentID, model, provider).Observe(ttft.Seconds())
+	}
+}
+
+// UpdateRouterDeploymentStats updates the router's view of a deployment:
+// its EWMA latency and TTFT in milliseconds and whether it is cooling down.
+func (c *Collector) UpdateRouterDeploymentStats(deploymentID, model, provider string, ewmaLatencyMs, ewmaTTFTMs float64, coolingDown bool) {
+	RouterDeploymentEWMALatency.WithLabelValues(deploymentID, model, provider).Set(ewmaLatencyMs / 1000)
+	RouterDeploymentEWMATTFT.WithLabelValues(deploymentID, model, provider).Set(ewmaTTFTMs / 1000)
+	c.UpdateRouterCooldown(deploymentID, model, provider, coolingDown)
+}
+
+// UpdateRouterCooldown sets whether the router has a deployment in cooldown.
+func (c *Collector) UpdateRouterCooldown(deploymentID, model, provider string, coolingDown bool) {
+	var v float64
+	if coolingDown {
+		v = 1
+	}
+	RouterDeploymentCooldown.WithLabelValues(deploymentID, model, provider).Set(v)
+}
+
+// UpdateRouterFailureRate sets a deployment's failure rate over the router's
+// failure window.
+func (c *Collector) UpdateRouterFailureRate(deploymentID, model, provider string, successes, failures int64) {
+	var rate float64
+	if total := successes + failures; total > 0 {
+		rate = float64(failures) / float64(total)
+	}
+	RouterDeploymentFailureRate.WithLabelValues(deploymentID, model, provider).Set(rate)
+}
+
 // UpdateBudgetMetrics updates budget-related gauge metrics.
 func (c *Collector) UpdateBudgetMetrics(budgetType string, labels []string, remaining, maxBudget, remainingHours float64) {
 	switch budgetType {
@@ -0,0 +1,29 @@
+// Package metrics provides governance-related Prometheus metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// =============================================================================
+// Content Category Metrics
+// =============================================================================
+
+var (
+	// ContentCategoryRequests counts requests by detected content category and
+	// the outcome of content policy evaluation.
+	ContentCategoryRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "content_category_requests_total",
+			Help:      "Total requests by detected content category and policy outcome",
+		},
+		[]string{"category", "outcome"}, // outcome: "allowed", "throttled" or "denied"
+	)
+)
+
+// RecordContentCategory records a content policy decision for a category.
+func RecordContentCategory(category, outcome string) {
+	ContentCategoryRequests.WithLabelValues(category, outcome).Inc()
+}
@@ -0,0 +1,31 @@
+// Package metrics provides guardrail Prometheus metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// =============================================================================
+// Guardrail Metrics
+// =============================================================================
+
+var (
+	// GuardrailEvaluations counts guardrail evaluations per tenant. The
+	// detection rate of a guardrail for a team is the share of evaluations
+	// whose outcome is not "passed".
+	GuardrailEvaluations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "guardrail_evaluations_total",
+			Help:      "Total guardrail evaluations by guardrail, team and outcome",
+		},
+		[]string{"guardrail", "stage", "team", "outcome"}, // outcome: "passed", "blocked", "redacted" or "annotated"
+	)
+)
+
+// RecordGuardrailEvaluation records the outcome of one guardrail for one
+// request or response.
+func RecordGuardrailEvaluation(guardrail, stage, team, outcome string) {
+	GuardrailEvaluations.WithLabelValues(guardrail, stage, team, outcome).Inc()
+}
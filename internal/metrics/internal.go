@@ -50,6 +50,17 @@ var (
 		},
 		[]string{"callback_name"},
 	)
+
+	// CallbackDeliveriesDropped counts callback payloads dropped without
+	// delivery.
+	CallbackDeliveriesDropped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "callback_deliveries_dropped_total",
+			Help:      "Total callback payloads dropped without delivery",
+		},
+		[]string{"callback_name", "reason"}, // reason: queue_full, rejected, retries_exhausted, shutdown, encode_failed, upload_failed
+	)
 )
 
 // =============================================================================
@@ -206,4 +217,15 @@ var (
 		},
 		[]string{"component", "action"}, // component: gateway/client, action: allow/deny
 	)
+
+	// RateLimitReserveAdmissions tracks requests admitted from a priority's
+	// reserve pool after the regular rate limit was exhausted.
+	RateLimitReserveAdmissions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limit_reserve_admissions_total",
+			Help:      "Total requests admitted from a priority rate limit reserve",
+		},
+		[]string{"priority"},
+	)
 )
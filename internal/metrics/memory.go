@@ -0,0 +1,42 @@
+// Package metrics provides memory retention metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// =============================================================================
+// Memory Retention Metrics
+// =============================================================================
+
+var (
+	// MemoryPrunedTotal counts memory items deleted by the retention job.
+	MemoryPrunedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "memory_pruned_total",
+			Help:      "Total memory items deleted by retention",
+		},
+		[]string{"kind"}, // kind: "session" or "vector"
+	)
+
+	// MemoryPruneErrors counts failed retention runs.
+	MemoryPruneErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "memory_prune_errors_total",
+			Help:      "Total failed memory retention runs",
+		},
+		[]string{"kind"},
+	)
+)
+
+// RecordMemoryPrune records the outcome of pruning one kind of memory.
+func RecordMemoryPrune(kind string, pruned int, err error) {
+	if err != nil {
+		MemoryPruneErrors.WithLabelValues(kind).Inc()
+		return
+	}
+	MemoryPrunedTotal.WithLabelValues(kind).Add(float64(pruned))
+}
@@ -0,0 +1,94 @@
+// Package metrics provides router-internal Prometheus metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Routing decision outcomes.
+const (
+	RoutingOutcomeSelected     = "selected"
+	RoutingOutcomeNoDeployment = "no_deployment"
+)
+
+// =============================================================================
+// Router Internal Metrics
+// =============================================================================
+
+var (
+	// RouterDeploymentEWMALatency exposes the EWMA latency the router uses
+	// to rank a deployment.
+	RouterDeploymentEWMALatency = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "router_deployment_ewma_latency_seconds",
+			Help:      "Exponentially weighted moving average latency per deployment as seen by the router",
+		},
+		[]string{"deployment_id", "model", "api_provider"},
+	)
+
+	// RouterDeploymentEWMATTFT exposes the EWMA time to first token the
+	// router uses to rank a deployment.
+	RouterDeploymentEWMATTFT = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "router_deployment_ewma_ttft_seconds",
+			Help:      "Exponentially weighted moving average time to first token per deployment as seen by the router",
+		},
+		[]string{"deployment_id", "model", "api_provider"},
+	)
+
+	// RouterDeploymentLatency observes the latency reported to the router.
+	RouterDeploymentLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "router_deployment_latency_seconds",
+			Help:      "Latency of successful requests reported to the router per deployment",
+			Buckets:   LatencyBuckets,
+		},
+		[]string{"deployment_id", "model", "api_provider"},
+	)
+
+	// RouterDeploymentTTFT observes the time to first token reported to the router.
+	RouterDeploymentTTFT = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "router_deployment_ttft_seconds",
+			Help:      "Time to first token of successful requests reported to the router per deployment",
+			Buckets:   LatencyBuckets,
+		},
+		[]string{"deployment_id", "model", "api_provider"},
+	)
+
+	// RouterDeploymentCooldown is 1 while a deployment is cooling down.
+	RouterDeploymentCooldown = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "router_deployment_cooldown",
+			Help:      "Whether the router has the deployment in cooldown (1) or not (0)",
+		},
+		[]string{"deployment_id", "model", "api_provider"},
+	)
+
+	// RouterDeploymentFailureRate exposes the failure rate over the
+	// router's cooldown window.
+	RouterDeploymentFailureRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "router_deployment_failure_rate",
+			Help:      "Failure rate of the deployment over the router's failure window (0-1)",
+		},
+		[]string{"deployment_id", "model", "api_provider"},
+	)
+
+	// RouterDecisions counts routing decisions per strategy.
+	RouterDecisions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "router_decisions_total",
+			Help:      "Routing decisions per strategy, model and selected deployment",
+		},
+		[]string{"strategy", "model", "deployment_id", "outcome"},
+	)
+)
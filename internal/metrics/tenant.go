@@ -0,0 +1,169 @@
+// Package metrics provides opt-in per-tenant Prometheus metrics.
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Tenant types used as the tenant_type label.
+const (
+	TenantTypeTeam = "team"
+	TenantTypeKey  = "key"
+)
+
+// TenantOverflowLabel replaces tenants that are not allowlisted or arrive
+// after the cardinality cap was reached.
+const TenantOverflowLabel = "__other__"
+
+// DefaultMaxTenants is the default number of distinct tenants tracked per
+// tenant type.
+const DefaultMaxTenants = 100
+
+// =============================================================================
+// Tenant Metrics
+// =============================================================================
+
+var (
+	// TenantRequests counts requests per tenant.
+	TenantRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tenant_requests_total",
+			Help:      "Total requests per team or API key",
+		},
+		[]string{"tenant_type", "tenant", "model", "status"},
+	)
+
+	// TenantTokens counts tokens per tenant.
+	TenantTokens = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tenant_tokens_total",
+			Help:      "Total tokens per team or API key",
+		},
+		[]string{"tenant_type", "tenant", "model", "token_type"},
+	)
+
+	// TenantSpend tracks spend per tenant.
+	TenantSpend = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tenant_spend_total",
+			Help:      "Total spend in USD per team or API key",
+		},
+		[]string{"tenant_type", "tenant", "model"},
+	)
+
+	// TenantOverflow counts requests folded into TenantOverflowLabel.
+	TenantOverflow = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tenant_metrics_overflow_total",
+			Help:      "Requests recorded under the overflow tenant because of the allowlist or cardinality cap",
+		},
+		[]string{"tenant_type", "reason"},
+	)
+)
+
+// TenantLabelerConfig configures which tenants get their own series.
+type TenantLabelerConfig struct {
+	// MaxTenants caps the distinct tenants per tenant type (default 100).
+	MaxTenants int
+	// Allowlist restricts tenants per tenant type. Types without an entry
+	// admit any tenant up to MaxTenants.
+	Allowlist map[string][]string
+}
+
+// TenantLabeler maps tenant IDs to bounded label values. The first
+// MaxTenants allowed tenants of each type keep their own label; all others
+// share TenantOverflowLabel.
+type TenantLabeler struct {
+	maxTenants int
+	allowlist  map[string]map[string]struct{}
+
+	mu   sync.Mutex
+	seen map[string]map[string]struct{}
+}
+
+// NewTenantLabeler creates a labeler.
+func NewTenantLabeler(cfg TenantLabelerConfig) *TenantLabeler {
+	if cfg.MaxTenants <= 0 {
+		cfg.MaxTenants = DefaultMaxTenants
+	}
+	l := &TenantLabeler{
+		maxTenants: cfg.MaxTenants,
+		allowlist:  make(map[string]map[string]struct{}, len(cfg.Allowlist)),
+		seen:       make(map[string]map[string]struct{}),
+	}
+	for tenantType, ids := range cfg.Allowlist {
+		if len(ids) == 0 {
+			continue
+		}
+		set := make(map[string]struct{}, len(ids))
+		for _, id := range ids {
+			set[id] = struct{}{}
+		}
+		l.allowlist[tenantType] = set
+	}
+	return l
+}
+
+// Label returns the label value for a tenant. aliases are alternative names
+// (e.g. a key alias) that also match the allowlist.
+func (l *TenantLabeler) Label(tenantType, id string, aliases ...string) string {
+	if allowed, ok := l.allowlist[tenantType]; ok && !matchesAny(allowed, id, aliases) {
+		TenantOverflow.WithLabelValues(tenantType, "not_allowlisted").Inc()
+		return TenantOverflowLabel
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	seen := l.seen[tenantType]
+	if seen == nil {
+		seen = make(map[string]struct{})
+		l.seen[tenantType] = seen
+	}
+	if _, ok := seen[id]; ok {
+		return id
+	}
+	if len(seen) >= l.maxTenants {
+		TenantOverflow.WithLabelValues(tenantType, "cardinality_cap").Inc()
+		return TenantOverflowLabel
+	}
+	seen[id] = struct{}{}
+	return id
+}
+
+func matchesAny(set map[string]struct{}, id string, aliases []string) bool {
+	if _, ok := set[id]; ok {
+		return true
+	}
+	for _, a := range aliases {
+		if _, ok := set[a]; a != "" && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordTenantRequest records request, token and spend metrics for one tenant.
+func (c *Collector) RecordTenantRequest(tenantType, tenant string, m *RequestMetrics) {
+	status := "success"
+	if !m.Success {
+		status = "failure"
+	}
+	model := m.Labels.Model
+	TenantRequests.WithLabelValues(tenantType, tenant, model, status).Inc()
+	if m.InputTokens > 0 {
+		TenantTokens.WithLabelValues(tenantType, tenant, model, "input").Add(float64(m.InputTokens))
+	}
+	if m.OutputTokens > 0 {
+		TenantTokens.WithLabelValues(tenantType, tenant, model, "output").Add(float64(m.OutputTokens))
+	}
+	if m.Cost > 0 {
+		TenantSpend.WithLabelValues(tenantType, tenant, model).Add(m.Cost)
+	}
+}
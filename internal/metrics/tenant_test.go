@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestTenantLabeler_CardinalityCap(t *testing.T) {
+	overflow := TenantOverflow.WithLabelValues(TenantTypeTeam, "cardinality_cap")
+	base := testutil.ToFloat64(overflow)
+
+	l := NewTenantLabeler(TenantLabelerConfig{MaxTenants: 2})
+	for _, id := range []string{"team-a", "team-b", "team-a"} {
+		if got := l.Label(TenantTypeTeam, id); got != id {
+			t.Fatalf("Label(%q) = %q, want %q", id, got, id)
+		}
+	}
+	if got := l.Label(TenantTypeTeam, "team-c"); got != TenantOverflowLabel {
+		t.Fatalf("Label(team-c) = %q, want overflow", got)
+	}
+	// The cap is per tenant type.
+	if got := l.Label(TenantTypeKey, "key-a"); got != "key-a" {
+		t.Fatalf("Label(key-a) = %q, want key-a", got)
+	}
+	if got := testutil.ToFloat64(overflow) - base; got != 1 {
+		t.Fatalf("overflow = %v, want 1", got)
+	}
+}
+
+func TestTenantLabeler_Allowlist(t *testing.T) {
+	l := NewTenantLabeler(TenantLabelerConfig{
+		Allowlist: map[string][]string{TenantTypeKey: {"prod-key"}},
+	})
+	if got := l.Label(TenantTypeKey, "hash-1", "prod-key"); got != "hash-1" {
+		t.Fatalf("allowlisted alias: got %q", got)
+	}
+	if got := l.Label(TenantTypeKey, "hash-2", "dev-key"); got != TenantOverflowLabel {
+		t.Fatalf("unlisted key: got %q, want overflow", got)
+	}
+	if got := l.Label(TenantTypeTeam, "any-team"); got != "any-team" {
+		t.Fatalf("team without allowlist: got %q", got)
+	}
+}
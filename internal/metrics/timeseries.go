@@ -0,0 +1,368 @@
+// Package metrics provides pre-aggregated usage time series for dashboards.
+package metrics
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Time series resolutions.
+const (
+	ResolutionMinute = time.Minute
+	ResolutionHour   = time.Hour
+)
+
+// Group-by dimensions supported by TimeSeries.Query.
+const (
+	GroupByNone     = ""
+	GroupByModel    = "model"
+	GroupByTeam     = "team"
+	GroupByProvider = "provider"
+)
+
+// overflowDimension replaces dimensions once a bucket reaches MaxSeriesPerBucket.
+const overflowDimension = "__other__"
+
+// maxQueryPoints bounds the number of points returned per group.
+const maxQueryPoints = 1500
+
+// latencyBoundsMs are the upper bounds of the latency histogram buckets.
+var latencyBoundsMs = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000}
+
+// ErrTooManyPoints is returned when a query spans too many buckets.
+var ErrTooManyPoints = errors.New("time range too large for resolution")
+
+// TimeSeriesConfig configures retention and cardinality of usage time series.
+type TimeSeriesConfig struct {
+	// MinuteRetention is how long per-minute buckets are kept (default 24h).
+	MinuteRetention time.Duration
+	// HourRetention is how long per-hour buckets are kept (default 30 days).
+	HourRetention time.Duration
+	// MaxSeriesPerBucket caps distinct model/team/provider combinations per
+	// bucket; extra combinations are folded into "__other__" (default 1000).
+	MaxSeriesPerBucket int
+}
+
+// DefaultTimeSeriesConfig returns the default time series configuration.
+func DefaultTimeSeriesConfig() TimeSeriesConfig {
+	return TimeSeriesConfig{
+		MinuteRetention:    24 * time.Hour,
+		HourRetention:      30 * 24 * time.Hour,
+		MaxSeriesPerBucket: 1000,
+	}
+}
+
+// UsageSample is one completed request.
+type UsageSample struct {
+	Time             time.Time
+	Model            string
+	Team             string
+	Provider         string
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64
+	Latency          time.Duration
+	Error            bool
+}
+
+// TimeSeriesQuery selects buckets from a TimeSeries.
+type TimeSeriesQuery struct {
+	Resolution time.Duration
+	Start      time.Time
+	End        time.Time
+	GroupBy    string
+	Model      string
+	Team       string
+	Provider   string
+}
+
+// TimeSeriesPoint holds the aggregates of one bucket.
+type TimeSeriesPoint struct {
+	Time             time.Time `json:"time"`
+	Requests         int64     `json:"requests"`
+	Errors           int64     `json:"errors"`
+	ErrorRate        float64   `json:"error_rate"`
+	PromptTokens     int64     `json:"prompt_tokens"`
+	CompletionTokens int64     `json:"completion_tokens"`
+	TotalTokens      int64     `json:"total_tokens"`
+	Cost             float64   `json:"cost"`
+	LatencyP50Ms     float64   `json:"latency_p50_ms"`
+	LatencyP90Ms     float64   `json:"latency_p90_ms"`
+	LatencyP99Ms     float64   `json:"latency_p99_ms"`
+}
+
+// TimeSeriesGroup is a dense series of points for one group-by value.
+type TimeSeriesGroup struct {
+	Key    string            `json:"key"`
+	Points []TimeSeriesPoint `json:"points"`
+}
+
+type seriesKey struct {
+	model    string
+	team     string
+	provider string
+}
+
+type seriesCell struct {
+	requests         int64
+	errors           int64
+	promptTokens     int64
+	completionTokens int64
+	cost             float64
+	maxLatencyMs     float64
+	latency          []uint64 // len(latencyBoundsMs)+1, last is overflow
+}
+
+func newSeriesCell() *seriesCell {
+	return &seriesCell{latency: make([]uint64, len(latencyBoundsMs)+1)}
+}
+
+func (c *seriesCell) add(s UsageSample) {
+	c.requests++
+	if s.Error {
+		c.errors++
+	}
+	c.promptTokens += int64(s.PromptTokens)
+	c.completionTokens += int64(s.CompletionTokens)
+	c.cost += s.Cost
+
+	ms := float64(s.Latency) / float64(time.Millisecond)
+	if ms > c.maxLatencyMs {
+		c.maxLatencyMs = ms
+	}
+	c.latency[sort.SearchFloat64s(latencyBoundsMs, ms)]++
+}
+
+func (c *seriesCell) merge(other *seriesCell) {
+	c.requests += other.requests
+	c.errors += other.errors
+	c.promptTokens += other.promptTokens
+	c.completionTokens += other.completionTokens
+	c.cost += other.cost
+	if other.maxLatencyMs > c.maxLatencyMs {
+		c.maxLatencyMs = other.maxLatencyMs
+	}
+	for i, n := range other.latency {
+		c.latency[i] += n
+	}
+}
+
+// percentile returns the upper bound of the histogram bucket containing q,
+// capped by the largest observed latency.
+func (c *seriesCell) percentile(q float64) float64 {
+	var total uint64
+	for _, n := range c.latency {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(total)))
+	var seen uint64
+	for i, n := range c.latency {
+		seen += n
+		if seen >= rank {
+			if i < len(latencyBoundsMs) {
+				return math.Min(latencyBoundsMs[i], c.maxLatencyMs)
+			}
+			return c.maxLatencyMs
+		}
+	}
+	return c.maxLatencyMs
+}
+
+type resolutionSeries struct {
+	resolution time.Duration
+	retention  time.Duration
+	buckets    map[int64]map[seriesKey]*seriesCell
+}
+
+// TimeSeries keeps per-minute and per-hour usage aggregates in memory so
+// dashboards can query them without scanning raw usage logs.
+type TimeSeries struct {
+	mu       sync.Mutex
+	config   TimeSeriesConfig
+	series   []*resolutionSeries
+	lastTrim time.Time
+}
+
+// NewTimeSeries creates an in-memory usage time series store.
+func NewTimeSeries(cfg TimeSeriesConfig) *TimeSeries {
+	defaults := DefaultTimeSeriesConfig()
+	if cfg.MinuteRetention <= 0 {
+		cfg.MinuteRetention = defaults.MinuteRetention
+	}
+	if cfg.HourRetention <= 0 {
+		cfg.HourRetention = defaults.HourRetention
+	}
+	if cfg.MaxSeriesPerBucket <= 0 {
+		cfg.MaxSeriesPerBucket = defaults.MaxSeriesPerBucket
+	}
+	return &TimeSeries{
+		config: cfg,
+		series: []*resolutionSeries{
+			{resolution: ResolutionMinute, retention: cfg.MinuteRetention, buckets: make(map[int64]map[seriesKey]*seriesCell)},
+			{resolution: ResolutionHour, retention: cfg.HourRetention, buckets: make(map[int64]map[seriesKey]*seriesCell)},
+		},
+	}
+}
+
+// Record adds a sample to every resolution.
+func (t *TimeSeries) Record(s UsageSample) {
+	if t == nil {
+		return
+	}
+	if s.Time.IsZero() {
+		s.Time = time.Now()
+	}
+	key := seriesKey{model: s.Model, team: s.Team, provider: s.Provider}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, rs := range t.series {
+		bucketStart := s.Time.Truncate(rs.resolution).Unix()
+		cells, ok := rs.buckets[bucketStart]
+		if !ok {
+			cells = make(map[seriesKey]*seriesCell)
+			rs.buckets[bucketStart] = cells
+		}
+		cellKey := key
+		if _, exists := cells[cellKey]; !exists && len(cells) >= t.config.MaxSeriesPerBucket {
+			cellKey = seriesKey{model: overflowDimension, team: overflowDimension, provider: overflowDimension}
+		}
+		cell, ok := cells[cellKey]
+		if !ok {
+			cell = newSeriesCell()
+			cells[cellKey] = cell
+		}
+		cell.add(s)
+	}
+
+	if now := time.Now(); now.Sub(t.lastTrim) >= time.Minute {
+		t.trimLocked(now)
+		t.lastTrim = now
+	}
+}
+
+func (t *TimeSeries) trimLocked(now time.Time) {
+	for _, rs := range t.series {
+		cutoff := now.Add(-rs.retention).Truncate(rs.resolution).Unix()
+		for bucketStart := range rs.buckets {
+			if bucketStart < cutoff {
+				delete(rs.buckets, bucketStart)
+			}
+		}
+	}
+}
+
+// Query returns dense series (one point per bucket, zero-filled) for each
+// group-by value, sorted by key.
+func (t *TimeSeries) Query(q TimeSeriesQuery) ([]TimeSeriesGroup, error) {
+	if t == nil {
+		return []TimeSeriesGroup{}, nil
+	}
+	var rs *resolutionSeries
+	for _, candidate := range t.series {
+		if candidate.resolution == q.Resolution {
+			rs = candidate
+		}
+	}
+	if rs == nil {
+		return nil, errors.New("unsupported resolution")
+	}
+	switch q.GroupBy {
+	case GroupByNone, GroupByModel, GroupByTeam, GroupByProvider:
+	default:
+		return nil, errors.New("unsupported group_by")
+	}
+	if !q.End.After(q.Start) {
+		return nil, errors.New("end must be after start")
+	}
+
+	first := q.Start.Truncate(rs.resolution)
+	last := q.End.Truncate(rs.resolution)
+	count := int(last.Sub(first)/rs.resolution) + 1
+	if count > maxQueryPoints {
+		return nil, ErrTooManyPoints
+	}
+
+	t.mu.Lock()
+	grouped := make(map[string][]*seriesCell)
+	for i := 0; i < count; i++ {
+		bucketStart := first.Add(time.Duration(i) * rs.resolution).Unix()
+		for key, cell := range rs.buckets[bucketStart] {
+			if !q.matches(key) {
+				continue
+			}
+			group := key.group(q.GroupBy)
+			cells, ok := grouped[group]
+			if !ok {
+				cells = make([]*seriesCell, count)
+				grouped[group] = cells
+			}
+			if cells[i] == nil {
+				cells[i] = newSeriesCell()
+			}
+			cells[i].merge(cell)
+		}
+	}
+	t.mu.Unlock()
+
+	if len(grouped) == 0 && q.GroupBy == GroupByNone {
+		grouped[""] = make([]*seriesCell, count)
+	}
+
+	out := make([]TimeSeriesGroup, 0, len(grouped))
+	for group, cells := range grouped {
+		points := make([]TimeSeriesPoint, count)
+		for i, cell := range cells {
+			points[i] = cell.point(first.Add(time.Duration(i) * rs.resolution))
+		}
+		out = append(out, TimeSeriesGroup{Key: group, Points: points})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+func (q TimeSeriesQuery) matches(key seriesKey) bool {
+	return (q.Model == "" || key.model == q.Model) &&
+		(q.Team == "" || key.team == q.Team) &&
+		(q.Provider == "" || key.provider == q.Provider)
+}
+
+func (k seriesKey) group(groupBy string) string {
+	switch groupBy {
+	case GroupByModel:
+		return k.model
+	case GroupByTeam:
+		return k.team
+	case GroupByProvider:
+		return k.provider
+	default:
+		return ""
+	}
+}
+
+func (c *seriesCell) point(bucket time.Time) TimeSeriesPoint {
+	p := TimeSeriesPoint{Time: bucket.UTC()}
+	if c == nil {
+		return p
+	}
+	p.Requests = c.requests
+	p.Errors = c.errors
+	if c.requests > 0 {
+		p.ErrorRate = float64(c.errors) / float64(c.requests)
+	}
+	p.PromptTokens = c.promptTokens
+	p.CompletionTokens = c.completionTokens
+	p.TotalTokens = c.promptTokens + c.completionTokens
+	p.Cost = c.cost
+	p.LatencyP50Ms = c.percentile(0.50)
+	p.LatencyP90Ms = c.percentile(0.90)
+	p.LatencyP99Ms = c.percentile(0.99)
+	return p
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeSeries_QueryGroupsAndFillsGaps(t *testing.T) {
+	ts := NewTimeSeries(DefaultTimeSeriesConfig())
+	base := time.Now().Truncate(time.Hour).Add(-time.Hour)
+
+	ts.Record(UsageSample{Time: base, Model: "gpt-4o", Team: "a", Provider: "openai", PromptTokens: 10, CompletionTokens: 5, Cost: 0.01, Latency: 40 * time.Millisecond})
+	ts.Record(UsageSample{Time: base.Add(10 * time.Second), Model: "gpt-4o", Team: "b", Provider: "openai", PromptTokens: 20, CompletionTokens: 5, Cost: 0.02, Latency: 400 * time.Millisecond, Error: true})
+	ts.Record(UsageSample{Time: base.Add(2 * time.Minute), Model: "claude", Team: "a", Provider: "anthropic", PromptTokens: 1, Latency: 2 * time.Second})
+
+	groups, err := ts.Query(TimeSeriesQuery{
+		Resolution: ResolutionMinute,
+		Start:      base,
+		End:        base.Add(2 * time.Minute),
+		GroupBy:    GroupByModel,
+	})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(groups) != 2 || groups[0].Key != "claude" || groups[1].Key != "gpt-4o" {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+
+	gpt := groups[1].Points
+	if len(gpt) != 3 {
+		t.Fatalf("len(points) = %d, want 3", len(gpt))
+	}
+	if gpt[0].Requests != 2 || gpt[0].Errors != 1 || gpt[0].ErrorRate != 0.5 {
+		t.Fatalf("unexpected first point: %+v", gpt[0])
+	}
+	if gpt[0].TotalTokens != 40 || gpt[0].PromptTokens != 30 {
+		t.Fatalf("unexpected tokens: %+v", gpt[0])
+	}
+	if gpt[0].LatencyP50Ms != 50 || gpt[0].LatencyP99Ms != 400 {
+		t.Fatalf("unexpected latency percentiles: %+v", gpt[0])
+	}
+	if gpt[1].Requests != 0 || !gpt[1].Time.Equal(base.Add(time.Minute)) {
+		t.Fatalf("expected zero-filled gap, got %+v", gpt[1])
+	}
+}
+
+func TestTimeSeries_FiltersAndHourResolution(t *testing.T) {
+	ts := NewTimeSeries(DefaultTimeSeriesConfig())
+	base := time.Now().Truncate(time.Hour).Add(-time.Hour)
+
+	ts.Record(UsageSample{Time: base.Add(time.Minute), Team: "a", Provider: "openai", Cost: 1})
+	ts.Record(UsageSample{Time: base.Add(30 * time.Minute), Team: "b", Provider: "openai", Cost: 2})
+
+	groups, err := ts.Query(TimeSeriesQuery{
+		Resolution: ResolutionHour,
+		Start:      base,
+		End:        base.Add(time.Minute),
+		Team:       "b",
+	})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Points) != 1 {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+	if p := groups[0].Points[0]; p.Requests != 1 || p.Cost != 2 {
+		t.Fatalf("unexpected point: %+v", p)
+	}
+}
+
+func TestTimeSeries_CardinalityCap(t *testing.T) {
+	ts := NewTimeSeries(TimeSeriesConfig{MaxSeriesPerBucket: 1})
+	now := time.Now()
+
+	ts.Record(UsageSample{Time: now, Model: "a"})
+	ts.Record(UsageSample{Time: now, Model: "b"})
+
+	groups, err := ts.Query(TimeSeriesQuery{
+		Resolution: ResolutionMinute,
+		Start:      now,
+		End:        now.Add(time.Second),
+		GroupBy:    GroupByModel,
+	})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(groups) != 2 || groups[0].Key != overflowDimension || groups[1].Key != "a" {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+}
+
+func TestTimeSeries_QueryValidation(t *testing.T) {
+	ts := NewTimeSeries(DefaultTimeSeriesConfig())
+	now := time.Now()
+
+	tests := []TimeSeriesQuery{
+		{Resolution: time.Second, Start: now.Add(-time.Minute), End: now},
+		{Resolution: ResolutionMinute, Start: now.Add(-time.Minute), End: now, GroupBy: "user"},
+		{Resolution: ResolutionMinute, Start: now, End: now.Add(-time.Minute)},
+		{Resolution: ResolutionMinute, Start: now.Add(-30 * 24 * time.Hour), End: now},
+	}
+	for i, q := range tests {
+		if _, err := ts.Query(q); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+// Package observability provides logging of slow, expensive and repeatedly
+// failing requests.
+package observability
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// Anomaly reasons reported in the "reasons" field of anomaly log records.
+const (
+	AnomalySlow             = "slow"
+	AnomalySlowTTFT         = "slow_ttft"
+	AnomalyExpensive        = "expensive"
+	AnomalyRepeatedFailures = "repeated_failures"
+)
+
+// AnomalyConfig configures an AnomalyLogger. Zero thresholds disable the
+// corresponding check.
+type AnomalyConfig struct {
+	Level            slog.Level    // Level of anomaly records
+	LatencyThreshold time.Duration // Requests slower than this are logged
+	TTFTThreshold    time.Duration // Streams with a slower first token are logged
+	CostThreshold    float64       // Requests costing more (USD) are logged
+	FailureThreshold int           // Failures per key and model within FailureWindow
+	FailureWindow    time.Duration // Default 1m
+	SampleRate       float64       // Fraction of anomalies logged (default 1)
+	MaxPerMinute     int           // Cap on anomaly records per minute (0 = unlimited)
+	IncludeMessages  bool          // Log redacted prompts and responses
+}
+
+type failureCount struct {
+	start time.Time
+	count int
+}
+
+// AnomalyLogger implements Callback by logging the full context of requests
+// that cross the configured thresholds on a dedicated logger, independent of
+// the gateway's log level. Sampling and a per-minute cap keep bursts quiet.
+type AnomalyLogger struct {
+	cfg      AnomalyConfig
+	logger   *slog.Logger
+	redactor *Redactor
+
+	mu       sync.Mutex
+	failures map[string]*failureCount
+	minute   int64
+	logged   int
+	rand     func() float64
+	now      func() time.Time
+}
+
+// NewAnomalyLogger creates an anomaly logger writing to logger. Records carry
+// channel=anomaly. A nil redactor leaves context unredacted.
+func NewAnomalyLogger(cfg AnomalyConfig, logger *slog.Logger, redactor *Redactor) *AnomalyLogger {
+	if cfg.FailureWindow <= 0 {
+		cfg.FailureWindow = time.Minute
+	}
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		cfg.SampleRate = 1
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &AnomalyLogger{
+		cfg:      cfg,
+		logger:   logger.With("channel", "anomaly"),
+		redactor: redactor,
+		failures: make(map[string]*failureCount),
+		rand:     rand.Float64,
+		now:      time.Now,
+	}
+}
+
+// Name returns the callback name.
+func (a *AnomalyLogger) Name() string {
+	return "anomaly"
+}
+
+// LogPreAPICall is a no-op for anomaly logging.
+func (a *AnomalyLogger) LogPreAPICall(ctx context.Context, payload *StandardLoggingPayload) error {
+	return nil
+}
+
+// LogPostAPICall is a no-op for anomaly logging.
+func (a *AnomalyLogger) LogPostAPICall(ctx context.Context, payload *StandardLoggingPayload) error {
+	return nil
+}
+
+// LogStreamEvent is a no-op for anomaly logging.
+func (a *AnomalyLogger) LogStreamEvent(ctx context.Context, payload *StandardLoggingPayload, chunk any) error {
+	return nil
+}
+
+// LogSuccessEvent logs a successful request that crossed a threshold.
+func (a *AnomalyLogger) LogSuccessEvent(ctx context.Context, payload *StandardLoggingPayload) error {
+	if payload == nil {
+		return nil
+	}
+	a.emit(ctx, payload, nil, a.thresholdReasons(payload))
+	return nil
+}
+
+// LogFailureEvent logs a failed request that crossed a threshold or repeats
+// recent failures of the same key and model.
+func (a *AnomalyLogger) LogFailureEvent(ctx context.Context, payload *StandardLoggingPayload, err error) error {
+	if payload == nil {
+		return nil
+	}
+	reasons := a.thresholdReasons(payload)
+	if n := a.recordFailure(payload); n > 0 {
+		reasons = append(reasons, AnomalyRepeatedFailures)
+	}
+	a.emit(ctx, payload, err, reasons)
+	return nil
+}
+
+// LogFallbackEvent is a no-op for anomaly logging.
+func (a *AnomalyLogger) LogFallbackEvent(ctx context.Context, originalModel, fallbackModel string, err error, success bool) error {
+	return nil
+}
+
+// Shutdown is a no-op for anomaly logging.
+func (a *AnomalyLogger) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (a *AnomalyLogger) thresholdReasons(payload *StandardLoggingPayload) []string {
+	var reasons []string
+	if a.cfg.LatencyThreshold > 0 && payload.EndTime.Sub(payload.StartTime) > a.cfg.LatencyThreshold {
+		reasons = append(reasons, AnomalySlow)
+	}
+	if a.cfg.TTFTThreshold > 0 && payload.CompletionStartTime != nil &&
+		payload.CompletionStartTime.Sub(payload.StartTime) > a.cfg.TTFTThreshold {
+		reasons = append(reasons, AnomalySlowTTFT)
+	}
+	if a.cfg.CostThreshold > 0 && payload.ResponseCost > a.cfg.CostThreshold {
+		reasons = append(reasons, AnomalyExpensive)
+	}
+	return reasons
+}
+
+// recordFailure counts a failure of the payload's key and model and returns
+// the count once it reaches FailureThreshold within FailureWindow, else 0.
+// The window restarts after each report.
+func (a *AnomalyLogger) recordFailure(payload *StandardLoggingPayload) int {
+	if a.cfg.FailureThreshold <= 0 {
+		return 0
+	}
+	key := payload.Model
+	if payload.HashedAPIKey != nil {
+		key = *payload.HashedAPIKey + "/" + key
+	}
+	now := a.now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	fc := a.failures[key]
+	if fc == nil || now.Sub(fc.start) > a.cfg.FailureWindow {
+		if len(a.failures) >= 10000 {
+			a.pruneFailuresLocked(now)
+		}
+		fc = &failureCount{start: now}
+		a.failures[key] = fc
+	}
+	fc.count++
+	if fc.count < a.cfg.FailureThreshold {
+		return 0
+	}
+	delete(a.failures, key)
+	return fc.count
+}
+
+func (a *AnomalyLogger) pruneFailuresLocked(now time.Time) {
+	for k, fc := range a.failures {
+		if now.Sub(fc.start) > a.cfg.FailureWindow {
+			delete(a.failures, k)
+		}
+	}
+}
+
+// admit applies sampling and the per-minute cap.
+func (a *AnomalyLogger) admit() bool {
+	if a.cfg.SampleRate < 1 && a.rand() >= a.cfg.SampleRate {
+		return false
+	}
+	if a.cfg.MaxPerMinute <= 0 {
+		return true
+	}
+	minute := a.now().Unix() / 60
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if minute != a.minute {
+		a.minute = minute
+		a.logged = 0
+	}
+	if a.logged >= a.cfg.MaxPerMinute {
+		return false
+	}
+	a.logged++
+	return true
+}
+
+func (a *AnomalyLogger) emit(ctx context.Context, payload *StandardLoggingPayload, err error, reasons []string) {
+	if len(reasons) == 0 || !a.admit() {
+		return
+	}
+
+	attrs := []any{
+		"reasons", reasons,
+		"request_id", payload.RequestID,
+		"call_type", payload.CallType,
+		"status", payload.Status,
+		"model", payload.Model,
+		"requested_model", payload.RequestedModel,
+		"provider", payload.APIProvider,
+		"api_base", payload.APIBase,
+		"latency_ms", payload.EndTime.Sub(payload.StartTime).Milliseconds(),
+		"prompt_tokens", payload.PromptTokens,
+		"completion_tokens", payload.CompletionTokens,
+		"cost", payload.ResponseCost,
+	}
+	if payload.CompletionStartTime != nil {
+		attrs = append(attrs, "ttft_ms", payload.CompletionStartTime.Sub(payload.StartTime).Milliseconds())
+	}
+	for _, f := range []struct {
+		key   string
+		value *string
+	}{
+		{"deployment_id", payload.ModelID},
+		{"model_group", payload.ModelGroup},
+		{"team", payload.Team},
+		{"team_alias", payload.TeamAlias},
+		{"key_alias", payload.APIKeyAlias},
+		{"hashed_api_key", payload.HashedAPIKey},
+		{"user", payload.User},
+		{"end_user", payload.EndUser},
+		{"exception_class", payload.ExceptionClass},
+	} {
+		if f.value != nil && *f.value != "" {
+			attrs = append(attrs, f.key, *f.value)
+		}
+	}
+	if payload.CacheHit != nil {
+		attrs = append(attrs, "cache_hit", *payload.CacheHit)
+	}
+	if err != nil {
+		attrs = append(attrs, "error", a.redact(err.Error()))
+	} else if payload.ErrorStr != nil {
+		attrs = append(attrs, "error", a.redact(*payload.ErrorStr))
+	}
+	if len(payload.ModelParameters) > 0 {
+		params := payload.ModelParameters
+		if a.redactor != nil {
+			params = a.redactor.RedactMap(params)
+		}
+		attrs = append(attrs, "model_parameters", params)
+	}
+	if a.cfg.IncludeMessages {
+		if payload.Messages != nil {
+			attrs = append(attrs, "messages", a.redactJSON(payload.Messages))
+		}
+		if payload.Response != nil {
+			attrs = append(attrs, "response", a.redactJSON(payload.Response))
+		}
+	}
+
+	a.logger.Log(ctx, a.cfg.Level, "anomalous request", attrs...)
+}
+
+func (a *AnomalyLogger) redact(s string) string {
+	if a.redactor == nil {
+		return s
+	}
+	return a.redactor.Redact(s)
+}
+
+func (a *AnomalyLogger) redactJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return a.redact(string(data))
+}
@@ -0,0 +1,129 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+func newTestAnomalyLogger(cfg AnomalyConfig) (*AnomalyLogger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return NewAnomalyLogger(cfg, logger, NewRedactor()), &buf
+}
+
+func anomalyRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestAnomalyLoggerThresholds(t *testing.T) {
+	a, buf := newTestAnomalyLogger(AnomalyConfig{
+		Level:            slog.LevelWarn,
+		LatencyThreshold: time.Second,
+		CostThreshold:    0.5,
+		IncludeMessages:  true,
+	})
+	start := time.Now()
+	team := "team-a"
+
+	fast := &StandardLoggingPayload{Model: "gpt-4", StartTime: start, EndTime: start.Add(100 * time.Millisecond), ResponseCost: 0.01}
+	if err := a.LogSuccessEvent(context.Background(), fast); err != nil {
+		t.Fatalf("LogSuccessEvent: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected no record for a normal request, got %s", buf.String())
+	}
+
+	slow := &StandardLoggingPayload{
+		RequestID:    "req-1",
+		Model:        "gpt-4",
+		StartTime:    start,
+		EndTime:      start.Add(2 * time.Second),
+		ResponseCost: 0.75,
+		Team:         &team,
+		Messages:     []map[string]string{{"role": "user", "content": "my key is sk-abcdefghijklmnopqrstuvwx"}},
+	}
+	if err := a.LogSuccessEvent(context.Background(), slow); err != nil {
+		t.Fatalf("LogSuccessEvent: %v", err)
+	}
+
+	records := anomalyRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	rec := records[0]
+	if rec["level"] != "WARN" || rec["channel"] != "anomaly" || rec["request_id"] != "req-1" || rec["team"] != "team-a" {
+		t.Fatalf("unexpected record: %v", rec)
+	}
+	reasons, _ := rec["reasons"].([]any)
+	if len(reasons) != 2 || reasons[0] != AnomalySlow || reasons[1] != AnomalyExpensive {
+		t.Fatalf("unexpected reasons: %v", rec["reasons"])
+	}
+	if messages, _ := rec["messages"].(string); messages == "" || strings.Contains(messages, "sk-abcdefghijklmnopqrstuvwx") {
+		t.Fatalf("expected messages to be redacted, got %q", messages)
+	}
+}
+
+func TestAnomalyLoggerRepeatedFailures(t *testing.T) {
+	a, buf := newTestAnomalyLogger(AnomalyConfig{FailureThreshold: 3, FailureWindow: time.Minute})
+	key := "hash-1"
+	payload := &StandardLoggingPayload{Model: "gpt-4", HashedAPIKey: &key}
+
+	for i := 0; i < 2; i++ {
+		_ = a.LogFailureEvent(context.Background(), payload, errors.New("upstream error"))
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected no record below the failure threshold, got %s", buf.String())
+	}
+	_ = a.LogFailureEvent(context.Background(), payload, errors.New("upstream error"))
+
+	records := anomalyRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if reasons, _ := records[0]["reasons"].([]any); len(reasons) != 1 || reasons[0] != AnomalyRepeatedFailures {
+		t.Fatalf("unexpected reasons: %v", records[0]["reasons"])
+	}
+	if records[0]["error"] != "upstream error" {
+		t.Fatalf("unexpected error: %v", records[0]["error"])
+	}
+}
+
+func TestAnomalyLoggerSamplingAndCap(t *testing.T) {
+	a, buf := newTestAnomalyLogger(AnomalyConfig{CostThreshold: 0.1, SampleRate: 0.5, MaxPerMinute: 2})
+	draws := []float64{0.9, 0.1, 0.2, 0.3}
+	a.rand = func() float64 {
+		v := draws[0]
+		draws = draws[1:]
+		return v
+	}
+	now := time.Unix(600, 0)
+	a.now = func() time.Time { return now }
+
+	payload := &StandardLoggingPayload{Model: "gpt-4", ResponseCost: 1}
+	for i := 0; i < 4; i++ {
+		_ = a.LogSuccessEvent(context.Background(), payload)
+	}
+	// The first anomaly is sampled out, the fourth exceeds the cap.
+	if got := len(anomalyRecords(t, buf)); got != 2 {
+		t.Fatalf("expected 2 records, got %d", got)
+	}
+}
@@ -54,6 +54,7 @@ type StandardLoggingPayload struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+	ReasoningTokens  int `json:"reasoning_tokens,omitempty"` // Part of CompletionTokens
 
 	// Cost
 	ResponseCost                 float64        `json:"response_cost"`
@@ -89,12 +90,22 @@ type StandardLoggingPayload struct {
 	CacheHit *bool   `json:"cache_hit,omitempty"`
 	CacheKey *string `json:"cache_key,omitempty"`
 
+	// Memory
+	RecalledMemories []RecalledMemory `json:"recalled_memories,omitempty"`
+
 	// Metadata
 	RequestTags        []string       `json:"request_tags,omitempty"`
 	RequesterIPAddress *string        `json:"requester_ip_address,omitempty"`
 	Metadata           map[string]any `json:"metadata,omitempty"`
 }
 
+// RecalledMemory identifies a long-term memory injected into a request.
+type RecalledMemory struct {
+	ID         string  `json:"id"`
+	Collection string  `json:"collection"`
+	Score      float64 `json:"score"`
+}
+
 // Callback defines the interface for observability callbacks.
 // Implementations can log to various backends (Prometheus, OTEL, Langfuse, etc.)
 type Callback interface {
@@ -10,12 +10,13 @@ import (
 
 // ObservabilityConfig contains configuration for all observability integrations.
 type ObservabilityConfig struct {
-	// Callbacks to enable (comma-separated: "prometheus,otel,langfuse,s3,slack,datadog,datadog_llm_obs,otel_metrics,otel_logs")
+	// Callbacks to enable (comma-separated: "prometheus,otel,langfuse,s3,slack,datadog,datadog_llm_obs,otel_metrics,otel_logs,webhook,gcs")
 	EnabledCallbacks []string `yaml:"enabled_callbacks" json:"enabled_callbacks"`
 
 	// Prometheus configuration
 	Prometheus struct {
-		Enabled bool `yaml:"enabled" json:"enabled"`
+		Enabled bool                `yaml:"enabled" json:"enabled"`
+		Tenants TenantMetricsConfig `yaml:"tenants" json:"tenants"`
 	} `yaml:"prometheus" json:"prometheus"`
 
 	// OpenTelemetry Tracing configuration
@@ -33,12 +34,18 @@ type ObservabilityConfig struct {
 	// S3 logging configuration
 	S3 S3Config `yaml:"s3" json:"s3"`
 
+	// GCS logging configuration (S3Config with Provider "gcs")
+	GCS S3Config `yaml:"gcs" json:"gcs"`
+
 	// Slack alerting configuration
 	Slack SlackConfig `yaml:"slack" json:"slack"`
 
 	// Datadog logging configuration
 	Datadog DatadogConfig `yaml:"datadog" json:"datadog"`
 
+	// Generic webhook logging configuration
+	Webhook WebhookConfig `yaml:"webhook" json:"webhook"`
+
 	// Datadog LLM Observability configuration
 	DatadogLLMObs DDLLMObsConfig `yaml:"datadog_llm_obs" json:"datadog_llm_obs"`
 
@@ -83,6 +90,9 @@ func DefaultObservabilityConfig() ObservabilityConfig {
 	// S3
 	cfg.S3 = DefaultS3Config()
 
+	// GCS
+	cfg.GCS = DefaultGCSConfig()
+
 	// Slack
 	cfg.Slack = DefaultSlackConfig()
 
@@ -92,6 +102,9 @@ func DefaultObservabilityConfig() ObservabilityConfig {
 	// Datadog LLM Observability
 	cfg.DatadogLLMObs = DefaultDDLLMObsConfig()
 
+	// Webhook
+	cfg.Webhook = DefaultWebhookConfig()
+
 	// Content filter defaults
 	cfg.ContentFilter.FilterBase64 = envBool("LLMUX_FILTER_BASE64", false)
 	cfg.ContentFilter.MaxContentLength = 10000
@@ -106,6 +119,7 @@ type ObservabilityManager struct {
 	tracerProvider  *TracerProvider
 	contentFilter   *ContentFilter
 	labelFilter     *LabelFilterManager
+	requestTail     *RequestTail
 }
 
 // NewObservabilityManager creates a new observability manager.
@@ -133,14 +147,35 @@ func NewObservabilityManager(cfg ObservabilityConfig) (*ObservabilityManager, er
 		}
 	}
 
+	// The request tail only does work while /admin/tail has subscribers.
+	mgr.requestTail = NewRequestTail()
+	mgr.callbackManager.Register(mgr.requestTail)
+
 	// Always enable Prometheus if configured
 	if cfg.Prometheus.Enabled {
-		mgr.callbackManager.Register(NewPrometheusCallback())
+		mgr.callbackManager.Register(NewPrometheusCallbackWithTenants(cfg.Prometheus.Tenants))
+	}
+
+	// Export OTel cost, token and latency metrics whenever they are enabled,
+	// not only when "otel_metrics" is listed explicitly.
+	if cfg.OTelMetrics.Enabled && !callbackListed(cfg.EnabledCallbacks, "otel_metrics") {
+		if err := mgr.enableCallback("otel_metrics"); err != nil {
+			return nil, fmt.Errorf("failed to enable callback otel_metrics: %w", err)
+		}
 	}
 
 	return mgr, nil
 }
 
+func callbackListed(callbacks []string, name string) bool {
+	for _, c := range callbacks {
+		if strings.EqualFold(strings.TrimSpace(c), name) {
+			return true
+		}
+	}
+	return false
+}
+
 // enableCallback enables a specific callback by name.
 func (m *ObservabilityManager) enableCallback(name string) error {
 	switch strings.ToLower(name) {
@@ -200,6 +235,17 @@ func (m *ObservabilityManager) enableCallback(name string) error {
 			m.callbackManager.Register(cb)
 		}
 
+	case "gcs":
+		if m.config.GCS.BucketName != "" {
+			gcsCfg := m.config.GCS
+			gcsCfg.Provider = "gcs"
+			cb, err := NewS3Callback(gcsCfg)
+			if err != nil {
+				return err
+			}
+			m.callbackManager.Register(cb)
+		}
+
 	case "slack":
 		if m.config.Slack.WebhookURL != "" {
 			cb, err := NewSlackCallback(m.config.Slack)
@@ -227,6 +273,15 @@ func (m *ObservabilityManager) enableCallback(name string) error {
 			m.callbackManager.Register(cb)
 		}
 
+	case "webhook":
+		if len(m.config.Webhook.URLs) > 0 {
+			cb, err := NewWebhookCallback(m.config.Webhook)
+			if err != nil {
+				return err
+			}
+			m.callbackManager.Register(cb)
+		}
+
 	default:
 		return fmt.Errorf("unknown callback: %s", name)
 	}
@@ -254,6 +309,11 @@ func (m *ObservabilityManager) LabelFilter() *LabelFilterManager {
 	return m.labelFilter
 }
 
+// RequestTail returns the live request feed.
+func (m *ObservabilityManager) RequestTail() *RequestTail {
+	return m.requestTail
+}
+
 // LogSuccess logs a successful request through all callbacks.
 func (m *ObservabilityManager) LogSuccess(ctx context.Context, payload *StandardLoggingPayload) {
 	// Apply content filtering
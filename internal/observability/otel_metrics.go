@@ -101,18 +101,18 @@ func InitOTelMetrics(ctx context.Context, cfg OTelMetricsConfig) (*OTelMetricsPr
 	)
 
 	otel.SetMeterProvider(provider)
-	meter := provider.Meter("llmux")
+	return newOTelMetricsProvider(provider)
+}
 
-	// Create metrics
+// newOTelMetricsProvider creates the gen_ai instruments on provider.
+func newOTelMetricsProvider(provider *sdkmetric.MeterProvider) (*OTelMetricsProvider, error) {
 	omp := &OTelMetricsProvider{
 		provider: provider,
-		meter:    meter,
+		meter:    provider.Meter("llmux"),
 	}
-
 	if err := omp.initMetrics(); err != nil {
 		return nil, err
 	}
-
 	return omp, nil
 }
 
@@ -143,8 +143,8 @@ func (o *OTelMetricsProvider) initMetrics() error {
 	// gen_ai.client.token.cost - Token cost
 	o.tokenCost, err = o.meter.Float64Counter(
 		"gen_ai.client.token.cost",
-		metric.WithDescription("Cost of tokens used"),
-		metric.WithUnit("{currency}"),
+		metric.WithDescription("Cost of tokens used in USD"),
+		metric.WithUnit("USD"),
 	)
 	if err != nil {
 		return err
@@ -209,6 +209,9 @@ func (o *OTelMetricsProvider) RecordRequest(ctx context.Context, payload *Standa
 	if payload.Team != nil {
 		attrs = append(attrs, attribute.String("llmux.team", *payload.Team))
 	}
+	if payload.TeamAlias != nil {
+		attrs = append(attrs, attribute.String("llmux.team_alias", *payload.TeamAlias))
+	}
 	if payload.User != nil {
 		attrs = append(attrs, attribute.String("llmux.user", *payload.User))
 	}
@@ -227,7 +230,9 @@ func (o *OTelMetricsProvider) RecordRequest(ctx context.Context, payload *Standa
 	o.tokenUsage.Add(ctx, int64(payload.CompletionTokens), metric.WithAttributes(outputAttrs...))
 
 	// Record cost
-	o.tokenCost.Add(ctx, payload.ResponseCost, metric.WithAttributes(attrs...))
+	if payload.ResponseCost > 0 {
+		o.tokenCost.Add(ctx, payload.ResponseCost, metric.WithAttributes(attrs...))
+	}
 
 	// Record TTFT if available
 	if payload.CompletionStartTime != nil {
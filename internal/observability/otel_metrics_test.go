@@ -0,0 +1,82 @@
+package observability
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestOTelMetricsProvider_RecordRequest(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp, err := newOTelMetricsProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatalf("newOTelMetricsProvider: %v", err)
+	}
+
+	team := "team-a"
+	start := time.Now()
+	firstToken := start.Add(100 * time.Millisecond)
+	mp.RecordRequest(context.Background(), &StandardLoggingPayload{
+		Model:               "gpt-4o",
+		APIProvider:         "openai",
+		CallType:            CallTypeCompletion,
+		Team:                &team,
+		PromptTokens:        12,
+		CompletionTokens:    8,
+		ResponseCost:        0.5,
+		StartTime:           start,
+		CompletionStartTime: &firstToken,
+		EndTime:             start.Add(time.Second),
+		Status:              RequestStatusSuccess,
+	})
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	got := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			got[m.Name] = m
+		}
+	}
+
+	cost, ok := got["gen_ai.client.token.cost"]
+	if !ok || cost.Unit != "USD" {
+		t.Fatalf("expected USD cost counter, got %+v", cost)
+	}
+	costPoints := cost.Data.(metricdata.Sum[float64]).DataPoints
+	if len(costPoints) != 1 || costPoints[0].Value != 0.5 {
+		t.Fatalf("unexpected cost points: %+v", costPoints)
+	}
+	for _, kv := range []attribute.KeyValue{
+		attribute.String("gen_ai.request.model", "gpt-4o"),
+		attribute.String("gen_ai.system", "openai"),
+		attribute.String("llmux.team", "team-a"),
+	} {
+		if v, ok := costPoints[0].Attributes.Value(kv.Key); !ok || v != kv.Value {
+			t.Errorf("cost attribute %s = %v, want %v", kv.Key, v.Emit(), kv.Value.Emit())
+		}
+	}
+
+	tokens := got["gen_ai.client.token.usage"].Data.(metricdata.Sum[int64]).DataPoints
+	byType := make(map[string]int64)
+	for _, dp := range tokens {
+		v, _ := dp.Attributes.Value("gen_ai.token.type")
+		byType[v.AsString()] = dp.Value
+	}
+	if byType["input"] != 12 || byType["output"] != 8 {
+		t.Errorf("unexpected token usage: %v", byType)
+	}
+
+	for _, name := range []string{"gen_ai.client.operation.duration", "gen_ai.client.response.time_to_first_token"} {
+		hist, ok := got[name].Data.(metricdata.Histogram[float64])
+		if !ok || len(hist.DataPoints) != 1 || hist.DataPoints[0].Count != 1 {
+			t.Errorf("expected one %s observation, got %+v", name, got[name].Data)
+		}
+	}
+}
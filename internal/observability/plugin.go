@@ -71,6 +71,7 @@ func (p *ObservabilityPlugin) PostHook(ctx *plugin.Context, resp *types.ChatResp
 			payload.PromptTokens = resp.Usage.PromptTokens
 			payload.CompletionTokens = resp.Usage.CompletionTokens
 			payload.TotalTokens = resp.Usage.TotalTokens
+			payload.ReasoningTokens = resp.Usage.ReasoningTokens()
 		}
 	}
 
@@ -7,9 +7,23 @@ import (
 	"github.com/blueberrycongee/llmux/internal/metrics"
 )
 
+// TenantMetricsConfig enables per-team and per-key request, token and spend
+// metrics. Series are bounded by MaxTenants per tenant type and, when set,
+// the allowlists; other tenants are recorded as "__other__".
+type TenantMetricsConfig struct {
+	Enabled      bool     `yaml:"enabled" json:"enabled"`
+	Teams        bool     `yaml:"teams" json:"teams"`                 // Emit per-team series
+	Keys         bool     `yaml:"keys" json:"keys"`                   // Emit per-key series (labelled by alias, else hashed key)
+	MaxTenants   int      `yaml:"max_tenants" json:"max_tenants"`     // Distinct tenants per type (default 100)
+	AllowedTeams []string `yaml:"allowed_teams" json:"allowed_teams"` // Team IDs or aliases; empty admits all
+	AllowedKeys  []string `yaml:"allowed_keys" json:"allowed_keys"`   // Key aliases or hashed keys; empty admits all
+}
+
 // PrometheusCallback implements Callback for Prometheus metrics.
 type PrometheusCallback struct {
 	collector *metrics.Collector
+	tenants   *metrics.TenantLabeler
+	tenantCfg TenantMetricsConfig
 }
 
 // NewPrometheusCallback creates a new Prometheus callback.
@@ -19,6 +33,24 @@ func NewPrometheusCallback() *PrometheusCallback {
 	}
 }
 
+// NewPrometheusCallbackWithTenants creates a Prometheus callback that also
+// records per-tenant metrics as configured by cfg.
+func NewPrometheusCallbackWithTenants(cfg TenantMetricsConfig) *PrometheusCallback {
+	p := NewPrometheusCallback()
+	if !cfg.Enabled || (!cfg.Teams && !cfg.Keys) {
+		return p
+	}
+	p.tenantCfg = cfg
+	p.tenants = metrics.NewTenantLabeler(metrics.TenantLabelerConfig{
+		MaxTenants: cfg.MaxTenants,
+		Allowlist: map[string][]string{
+			metrics.TenantTypeTeam: cfg.AllowedTeams,
+			metrics.TenantTypeKey:  cfg.AllowedKeys,
+		},
+	})
+	return p
+}
+
 // Name returns the callback name.
 func (p *PrometheusCallback) Name() string {
 	return "prometheus"
@@ -57,6 +89,7 @@ func (p *PrometheusCallback) LogSuccessEvent(ctx context.Context, payload *Stand
 	m := p.payloadToMetrics(payload)
 	m.Success = true
 	p.collector.RecordRequest(m)
+	p.recordTenants(m)
 	return nil
 }
 
@@ -68,9 +101,32 @@ func (p *PrometheusCallback) LogFailureEvent(ctx context.Context, payload *Stand
 		m.Labels.ExceptionClass = *payload.ExceptionClass
 	}
 	p.collector.RecordRequest(m)
+	p.recordTenants(m)
 	return nil
 }
 
+// recordTenants records per-tenant metrics when enabled.
+func (p *PrometheusCallback) recordTenants(m *metrics.RequestMetrics) {
+	if p.tenants == nil {
+		return
+	}
+	labels := m.Labels
+	if p.tenantCfg.Teams && labels.Team != "" {
+		tenant := p.tenants.Label(metrics.TenantTypeTeam, labels.Team, labels.TeamAlias)
+		p.collector.RecordTenantRequest(metrics.TenantTypeTeam, tenant, m)
+	}
+	if p.tenantCfg.Keys {
+		id := labels.APIKeyAlias
+		if id == "" {
+			id = labels.HashedAPIKey
+		}
+		if id != "" {
+			tenant := p.tenants.Label(metrics.TenantTypeKey, id, labels.HashedAPIKey)
+			p.collector.RecordTenantRequest(metrics.TenantTypeKey, tenant, m)
+		}
+	}
+}
+
 // LogFallbackEvent records fallback metrics.
 func (p *PrometheusCallback) LogFallbackEvent(ctx context.Context, originalModel, fallbackModel string, err error, success bool) error {
 	exceptionStatus := ""
@@ -0,0 +1,56 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/blueberrycongee/llmux/internal/metrics"
+)
+
+func TestPrometheusCallback_TenantMetrics(t *testing.T) {
+	cb := NewPrometheusCallbackWithTenants(TenantMetricsConfig{
+		Enabled:      true,
+		Teams:        true,
+		Keys:         true,
+		AllowedTeams: []string{"team-tenant-a"},
+	})
+
+	team, alias, hash := "team-tenant-a", "tenant-key", "tenant-hash"
+	otherTeam := "team-tenant-b"
+	payload := &StandardLoggingPayload{
+		Model:            "gpt-tenant",
+		Team:             &team,
+		APIKeyAlias:      &alias,
+		HashedAPIKey:     &hash,
+		PromptTokens:     10,
+		CompletionTokens: 5,
+		ResponseCost:     0.25,
+		Status:           RequestStatusSuccess,
+	}
+	if err := cb.LogSuccessEvent(context.Background(), payload); err != nil {
+		t.Fatalf("LogSuccessEvent: %v", err)
+	}
+	other := *payload
+	other.Team = &otherTeam
+	if err := cb.LogFailureEvent(context.Background(), &other, nil); err != nil {
+		t.Fatalf("LogFailureEvent: %v", err)
+	}
+
+	if got := testutil.ToFloat64(metrics.TenantRequests.WithLabelValues("team", team, "gpt-tenant", "success")); got != 1 {
+		t.Errorf("team requests = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.TenantRequests.WithLabelValues("team", metrics.TenantOverflowLabel, "gpt-tenant", "failure")); got < 1 {
+		t.Errorf("unlisted team should be recorded as overflow, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.TenantRequests.WithLabelValues("key", alias, "gpt-tenant", "success")); got != 1 {
+		t.Errorf("key requests = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.TenantTokens.WithLabelValues("key", alias, "gpt-tenant", "output")); got != 10 {
+		t.Errorf("key output tokens = %v, want 10", got)
+	}
+	if got := testutil.ToFloat64(metrics.TenantSpend.WithLabelValues("team", team, "gpt-tenant")); got != 0.25 {
+		t.Errorf("team spend = %v, want 0.25", got)
+	}
+}
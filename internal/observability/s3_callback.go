@@ -1,8 +1,10 @@
-// Package observability provides an S3 callback for logging LLM requests to AWS S3.
+// Package observability provides an S3 callback for logging LLM requests to
+// AWS S3 or Google Cloud Storage.
 package observability
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"os"
@@ -16,10 +18,14 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/blueberrycongee/llmux/internal/metrics"
 )
 
-// S3Config contains configuration for S3 logging.
+// S3Config contains configuration for S3 logging. GCS buckets are written
+// through the S3-compatible XML API with HMAC credentials.
 type S3Config struct {
+	Provider      string        // "s3" (default) or "gcs"
 	BucketName    string        // S3 bucket name
 	Region        string        // AWS region
 	AccessKeyID   string        // AWS access key (optional, uses default credentials if empty)
@@ -28,6 +34,7 @@ type S3Config struct {
 	PathPrefix    string        // Prefix for S3 keys (e.g., "llmux/logs")
 	FlushInterval time.Duration // Flush interval for batching
 	BatchSize     int           // Max batch size before flush
+	MaxBatchBytes int           // Max uncompressed batch bytes before flush
 	Compression   bool          // Enable gzip compression
 }
 
@@ -42,6 +49,22 @@ func DefaultS3Config() S3Config {
 		PathPrefix:    os.Getenv("S3_PATH_PREFIX"),
 		FlushInterval: 10 * time.Second,
 		BatchSize:     100,
+		MaxBatchBytes: 5 << 20,
+		Compression:   true,
+	}
+}
+
+// DefaultGCSConfig returns default GCS configuration from environment.
+func DefaultGCSConfig() S3Config {
+	return S3Config{
+		Provider:      "gcs",
+		BucketName:    os.Getenv("GCS_BUCKET_NAME"),
+		AccessKeyID:   os.Getenv("GCS_HMAC_ACCESS_ID"),
+		SecretKey:     os.Getenv("GCS_HMAC_SECRET"),
+		PathPrefix:    os.Getenv("GCS_PATH_PREFIX"),
+		FlushInterval: 10 * time.Second,
+		BatchSize:     100,
+		MaxBatchBytes: 5 << 20,
 		Compression:   true,
 	}
 }
@@ -71,14 +94,17 @@ type S3LogEntry struct {
 	Metadata         map[string]interface{} `json:"metadata,omitempty"`
 }
 
-// S3Callback implements Callback for S3 logging.
+// S3Callback implements Callback for S3 logging. Entries are buffered as
+// JSONL and uploaded as one object when the batch reaches BatchSize entries
+// or MaxBatchBytes, and every FlushInterval.
 type S3Callback struct {
-	config   S3Config
-	client   *s3.Client
-	logQueue []S3LogEntry
-	mu       sync.Mutex
-	stopCh   chan struct{}
-	wg       sync.WaitGroup
+	config  S3Config
+	client  *s3.Client
+	buf     bytes.Buffer
+	entries int
+	mu      sync.Mutex
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
 }
 
 // NewS3Callback creates a new S3 callback.
@@ -86,6 +112,20 @@ func NewS3Callback(cfg S3Config) (*S3Callback, error) {
 	if cfg.BucketName == "" {
 		return nil, fmt.Errorf("s3: bucket_name is required")
 	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 10 * time.Second
+	}
+	if cfg.Provider == "gcs" {
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = "https://storage.googleapis.com"
+		}
+		if cfg.Region == "" {
+			cfg.Region = "auto"
+		}
+	}
 
 	// Build AWS config
 	var awsCfg aws.Config
@@ -120,10 +160,9 @@ func NewS3Callback(cfg S3Config) (*S3Callback, error) {
 	client := s3.NewFromConfig(awsCfg, s3Opts...)
 
 	cb := &S3Callback{
-		config:   cfg,
-		client:   client,
-		logQueue: make([]S3LogEntry, 0, cfg.BatchSize),
-		stopCh:   make(chan struct{}),
+		config: cfg,
+		client: client,
+		stopCh: make(chan struct{}),
 	}
 
 	// Start background flush goroutine
@@ -135,6 +174,9 @@ func NewS3Callback(cfg S3Config) (*S3Callback, error) {
 
 // Name returns the callback name.
 func (s *S3Callback) Name() string {
+	if s.config.Provider == "gcs" {
+		return "gcs"
+	}
 	return "s3"
 }
 
@@ -243,14 +285,24 @@ func (s *S3Callback) payloadToEntry(payload *StandardLoggingPayload) S3LogEntry
 	return entry
 }
 
-// enqueue adds a log entry to the queue.
+// enqueue appends a log entry to the pending batch.
 func (s *S3Callback) enqueue(entry S3LogEntry) {
+	line, err := json.Marshal(&entry)
+	if err != nil {
+		metrics.CallbackDeliveriesDropped.WithLabelValues(s.Name(), "encode_failed").Inc()
+		return
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.logQueue = append(s.logQueue, entry)
+	s.buf.Write(line)
+	s.buf.WriteByte('\n')
+	s.entries++
 
-	if len(s.logQueue) >= s.config.BatchSize {
+	full := s.entries >= s.config.BatchSize ||
+		(s.config.MaxBatchBytes > 0 && s.buf.Len() >= s.config.MaxBatchBytes)
+	if full {
 		go func() {
 			_ = s.flush(context.Background())
 		}()
@@ -280,38 +332,39 @@ func (s *S3Callback) flushLoop() {
 // flush uploads queued logs to S3.
 func (s *S3Callback) flush(ctx context.Context) error {
 	s.mu.Lock()
-	if len(s.logQueue) == 0 {
+	if s.entries == 0 {
 		s.mu.Unlock()
 		return nil
 	}
 
-	entries := s.logQueue
-	s.logQueue = make([]S3LogEntry, 0, s.config.BatchSize)
+	content := bytes.Clone(s.buf.Bytes())
+	entries := s.entries
+	s.buf.Reset()
+	s.entries = 0
 	s.mu.Unlock()
 
-	// Build JSONL content
-	var buf bytes.Buffer
-	encoder := json.NewEncoder(&buf)
-	for i := range entries {
-		if err := encoder.Encode(&entries[i]); err != nil {
-			continue
-		}
-	}
-
-	// Generate S3 key
-	now := time.Now().UTC()
-	key := s.generateKey(now)
-
-	// Upload to S3
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+	input := &s3.PutObjectInput{
 		Bucket:      aws.String(s.config.BucketName),
-		Key:         aws.String(key),
-		Body:        bytes.NewReader(buf.Bytes()),
+		Key:         aws.String(s.generateKey(time.Now().UTC())),
 		ContentType: aws.String("application/x-ndjson"),
-	})
+	}
+	if s.config.Compression {
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		if _, err := gz.Write(content); err != nil {
+			return fmt.Errorf("%s: failed to compress logs: %w", s.Name(), err)
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("%s: failed to compress logs: %w", s.Name(), err)
+		}
+		content = compressed.Bytes()
+		input.ContentEncoding = aws.String("gzip")
+	}
+	input.Body = bytes.NewReader(content)
 
-	if err != nil {
-		return fmt.Errorf("s3: failed to upload logs: %w", err)
+	if _, err := s.client.PutObject(ctx, input); err != nil {
+		metrics.CallbackDeliveriesDropped.WithLabelValues(s.Name(), "upload_failed").Add(float64(entries))
+		return fmt.Errorf("%s: failed to upload logs: %w", s.Name(), err)
 	}
 
 	return nil
@@ -319,11 +372,14 @@ func (s *S3Callback) flush(ctx context.Context) error {
 
 // generateKey generates an S3 key with date partitioning.
 func (s *S3Callback) generateKey(t time.Time) string {
-	// Format: prefix/year=YYYY/month=MM/day=DD/hour=HH/logs_timestamp.jsonl
+	// Format: prefix/year=YYYY/month=MM/day=DD/hour=HH/logs_timestamp.jsonl[.gz]
 	datePrefix := fmt.Sprintf("year=%d/month=%02d/day=%02d/hour=%02d",
 		t.Year(), t.Month(), t.Day(), t.Hour())
 
 	filename := fmt.Sprintf("logs_%d.jsonl", t.UnixNano())
+	if s.config.Compression {
+		filename += ".gz"
+	}
 
 	if s.config.PathPrefix != "" {
 		return path.Join(s.config.PathPrefix, datePrefix, filename)
@@ -0,0 +1,151 @@
+package observability
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+type capturedObject struct {
+	path            string
+	contentEncoding string
+	body            []byte
+}
+
+func newObjectStoreServer(t *testing.T) (*httptest.Server, func() []capturedObject) {
+	t.Helper()
+	var mu sync.Mutex
+	var objects []capturedObject
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPut {
+			mu.Lock()
+			objects = append(objects, capturedObject{
+				path:            r.URL.Path,
+				contentEncoding: r.Header.Get("Content-Encoding"),
+				body:            body,
+			})
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []capturedObject {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]capturedObject(nil), objects...)
+	}
+}
+
+func readJSONL(t *testing.T, obj capturedObject) []S3LogEntry {
+	t.Helper()
+	var r io.Reader = bytes.NewReader(obj.body)
+	if obj.contentEncoding == "gzip" {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			t.Fatalf("gzip reader: %v", err)
+		}
+		r = gz
+	}
+	var entries []S3LogEntry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var entry S3LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("decode line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestS3Callback_FlushesCompressedJSONL(t *testing.T) {
+	srv, objects := newObjectStoreServer(t)
+	cb, err := NewS3Callback(S3Config{
+		Provider:      "gcs",
+		BucketName:    "logs",
+		Endpoint:      srv.URL,
+		AccessKeyID:   "id",
+		SecretKey:     "secret",
+		PathPrefix:    "llmux",
+		FlushInterval: time.Hour,
+		BatchSize:     100,
+		Compression:   true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Callback: %v", err)
+	}
+	if cb.Name() != "gcs" {
+		t.Errorf("Name() = %q, want gcs", cb.Name())
+	}
+
+	ctx := context.Background()
+	_ = cb.LogSuccessEvent(ctx, &StandardLoggingPayload{RequestID: "req-1", Model: "gpt-4o"})
+	_ = cb.LogFailureEvent(ctx, &StandardLoggingPayload{RequestID: "req-2", Model: "gpt-4o"}, io.EOF)
+	if err := cb.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	got := objects()
+	if len(got) != 1 {
+		t.Fatalf("expected one object, got %d", len(got))
+	}
+	if !strings.HasPrefix(got[0].path, "/logs/llmux/year=") || !strings.HasSuffix(got[0].path, ".jsonl.gz") {
+		t.Errorf("unexpected object path %q", got[0].path)
+	}
+	entries := readJSONL(t, got[0])
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].RequestID != "req-1" || entries[0].Status != "success" {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Status != "failure" || entries[1].Error != io.EOF.Error() {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestS3Callback_FlushesOnBatchBytes(t *testing.T) {
+	srv, objects := newObjectStoreServer(t)
+	cb, err := NewS3Callback(S3Config{
+		BucketName:    "logs",
+		Region:        "us-east-1",
+		Endpoint:      srv.URL,
+		AccessKeyID:   "id",
+		SecretKey:     "secret",
+		FlushInterval: time.Hour,
+		BatchSize:     1000,
+		MaxBatchBytes: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Callback: %v", err)
+	}
+	defer func() { _ = cb.Shutdown(context.Background()) }()
+
+	_ = cb.LogSuccessEvent(context.Background(), &StandardLoggingPayload{RequestID: "req-1"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(objects()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	got := objects()
+	if len(got) != 1 {
+		t.Fatalf("expected size trigger to flush one object, got %d", len(got))
+	}
+	if got[0].contentEncoding != "" || !strings.HasSuffix(got[0].path, ".jsonl") {
+		t.Errorf("expected uncompressed object, got encoding %q path %q", got[0].contentEncoding, got[0].path)
+	}
+	if entries := readJSONL(t, got[0]); len(entries) != 1 || entries[0].RequestID != "req-1" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
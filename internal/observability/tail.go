@@ -0,0 +1,180 @@
+// Package observability provides a live feed of completed requests.
+package observability
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/metrics"
+)
+
+// DefaultTailBuffer is the number of events buffered per tail subscriber.
+const DefaultTailBuffer = 256
+
+// TailEvent is the redacted summary of one completed request. It carries no
+// prompts, responses, raw error messages or user identities.
+type TailEvent struct {
+	Time             time.Time     `json:"time"`
+	RequestID        string        `json:"request_id,omitempty"`
+	CallType         CallType      `json:"call_type,omitempty"`
+	Model            string        `json:"model"`
+	Provider         string        `json:"provider,omitempty"`
+	Status           RequestStatus `json:"status"`
+	LatencyMs        int64         `json:"latency_ms"`
+	TTFTMs           int64         `json:"ttft_ms,omitempty"`
+	PromptTokens     int           `json:"prompt_tokens"`
+	CompletionTokens int           `json:"completion_tokens"`
+	Cost             float64       `json:"cost"`
+	CacheHit         bool          `json:"cache_hit,omitempty"`
+	Team             string        `json:"team,omitempty"`
+	KeyAlias         string        `json:"key_alias,omitempty"`
+	ErrorClass       string        `json:"error_class,omitempty"`
+}
+
+// newTailEvent summarizes payload.
+func newTailEvent(payload *StandardLoggingPayload) TailEvent {
+	ev := TailEvent{
+		Time:             payload.EndTime,
+		RequestID:        payload.RequestID,
+		CallType:         payload.CallType,
+		Model:            payload.Model,
+		Provider:         payload.APIProvider,
+		Status:           payload.Status,
+		PromptTokens:     payload.PromptTokens,
+		CompletionTokens: payload.CompletionTokens,
+		Cost:             payload.ResponseCost,
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if !payload.StartTime.IsZero() && !payload.EndTime.IsZero() {
+		ev.LatencyMs = payload.EndTime.Sub(payload.StartTime).Milliseconds()
+	}
+	if payload.CompletionStartTime != nil && !payload.StartTime.IsZero() {
+		ev.TTFTMs = payload.CompletionStartTime.Sub(payload.StartTime).Milliseconds()
+	}
+	if payload.CacheHit != nil {
+		ev.CacheHit = *payload.CacheHit
+	}
+	if payload.TeamAlias != nil && *payload.TeamAlias != "" {
+		ev.Team = *payload.TeamAlias
+	} else if payload.Team != nil {
+		ev.Team = *payload.Team
+	}
+	if payload.APIKeyAlias != nil {
+		ev.KeyAlias = *payload.APIKeyAlias
+	}
+	if payload.ExceptionClass != nil {
+		ev.ErrorClass = *payload.ExceptionClass
+	}
+	return ev
+}
+
+// RequestTail implements Callback by broadcasting a TailEvent for every
+// completed request to its subscribers. Publishing never blocks: events are
+// dropped for subscribers that fall behind.
+type RequestTail struct {
+	mu   sync.RWMutex
+	subs map[chan TailEvent]struct{}
+}
+
+// NewRequestTail creates a request tail without subscribers.
+func NewRequestTail() *RequestTail {
+	return &RequestTail{subs: make(map[chan TailEvent]struct{})}
+}
+
+// Subscribe registers a subscriber with the given buffer size and returns
+// its event channel and a function that unsubscribes and closes it.
+func (t *RequestTail) Subscribe(buffer int) (<-chan TailEvent, func()) {
+	if buffer <= 0 {
+		buffer = DefaultTailBuffer
+	}
+	ch := make(chan TailEvent, buffer)
+	t.mu.Lock()
+	t.subs[ch] = struct{}{}
+	t.mu.Unlock()
+
+	return ch, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if _, ok := t.subs[ch]; ok {
+			delete(t.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribers returns the number of active subscribers.
+func (t *RequestTail) Subscribers() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.subs)
+}
+
+func (t *RequestTail) publish(payload *StandardLoggingPayload) {
+	if payload == nil {
+		return
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if len(t.subs) == 0 {
+		return
+	}
+	ev := newTailEvent(payload)
+	for ch := range t.subs {
+		select {
+		case ch <- ev:
+		default:
+			metrics.CallbackDeliveriesDropped.WithLabelValues(t.Name(), "slow_subscriber").Inc()
+		}
+	}
+}
+
+// Name returns the callback name.
+func (t *RequestTail) Name() string {
+	return "tail"
+}
+
+// LogPreAPICall is a no-op for the tail.
+func (t *RequestTail) LogPreAPICall(ctx context.Context, payload *StandardLoggingPayload) error {
+	return nil
+}
+
+// LogPostAPICall is a no-op for the tail.
+func (t *RequestTail) LogPostAPICall(ctx context.Context, payload *StandardLoggingPayload) error {
+	return nil
+}
+
+// LogStreamEvent is a no-op for the tail.
+func (t *RequestTail) LogStreamEvent(ctx context.Context, payload *StandardLoggingPayload, chunk any) error {
+	return nil
+}
+
+// LogSuccessEvent publishes a successful request.
+func (t *RequestTail) LogSuccessEvent(ctx context.Context, payload *StandardLoggingPayload) error {
+	t.publish(payload)
+	return nil
+}
+
+// LogFailureEvent publishes a failed request.
+func (t *RequestTail) LogFailureEvent(ctx context.Context, payload *StandardLoggingPayload, err error) error {
+	t.publish(payload)
+	return nil
+}
+
+// LogFallbackEvent is a no-op for the tail.
+func (t *RequestTail) LogFallbackEvent(ctx context.Context, originalModel, fallbackModel string, err error, success bool) error {
+	return nil
+}
+
+// Shutdown disconnects all subscribers.
+func (t *RequestTail) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for ch := range t.subs {
+		delete(t.subs, ch)
+		close(ch)
+	}
+	return nil
+}
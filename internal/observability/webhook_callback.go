@@ -0,0 +1,294 @@
+// Package observability provides a generic webhook callback that delivers
+// logging payloads to arbitrary HTTP endpoints.
+package observability
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/metrics"
+)
+
+// Webhook delivery headers.
+const (
+	WebhookSignatureHeader = "X-LLMux-Signature"
+	WebhookTimestampHeader = "X-LLMux-Timestamp"
+	WebhookEventHeader     = "X-LLMux-Event"
+)
+
+// WebhookConfig contains configuration for webhook logging.
+type WebhookConfig struct {
+	URLs         []string          `yaml:"urls" json:"urls"`                   // Endpoints receiving every payload
+	Secret       string            `yaml:"secret" json:"secret"`               // HMAC-SHA256 signing secret (optional)
+	Headers      map[string]string `yaml:"headers" json:"headers"`             // Extra request headers
+	QueueSize    int               `yaml:"queue_size" json:"queue_size"`       // Pending deliveries before dropping
+	Workers      int               `yaml:"workers" json:"workers"`             // Concurrent deliveries
+	MaxRetries   int               `yaml:"max_retries" json:"max_retries"`     // Retries after the first attempt
+	RetryBackoff time.Duration     `yaml:"retry_backoff" json:"retry_backoff"` // Initial backoff, doubled per retry
+	Timeout      time.Duration     `yaml:"timeout" json:"timeout"`             // Per-attempt timeout
+}
+
+// DefaultWebhookConfig returns default configuration from environment.
+func DefaultWebhookConfig() WebhookConfig {
+	var urls []string
+	for _, u := range strings.Split(os.Getenv("LLMUX_WEBHOOK_URLS"), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return WebhookConfig{
+		URLs:         urls,
+		Secret:       os.Getenv("LLMUX_WEBHOOK_SECRET"),
+		QueueSize:    1000,
+		Workers:      2,
+		MaxRetries:   3,
+		RetryBackoff: 500 * time.Millisecond,
+		Timeout:      10 * time.Second,
+	}
+}
+
+// webhookDelivery is a payload pending delivery to one URL.
+type webhookDelivery struct {
+	url   string
+	event string
+	body  []byte
+}
+
+// WebhookCallback implements Callback by POSTing each success and failure
+// payload as JSON to the configured URLs. Deliveries are queued and sent in
+// the background; when the queue is full new payloads are dropped.
+type WebhookCallback struct {
+	config WebhookConfig
+	client *http.Client
+	queue  chan webhookDelivery
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+	now    func() time.Time
+}
+
+// NewWebhookCallback creates a webhook callback and starts its workers.
+func NewWebhookCallback(cfg WebhookConfig) (*WebhookCallback, error) {
+	if len(cfg.URLs) == 0 {
+		return nil, fmt.Errorf("webhook: at least one url is required")
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1000
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 2
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 500 * time.Millisecond
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	w := &WebhookCallback{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		queue:  make(chan webhookDelivery, cfg.QueueSize),
+		stopCh: make(chan struct{}),
+		now:    time.Now,
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		w.wg.Add(1)
+		go w.worker()
+	}
+	return w, nil
+}
+
+// Name returns the callback name.
+func (w *WebhookCallback) Name() string {
+	return "webhook"
+}
+
+// LogPreAPICall is a no-op for webhooks.
+func (w *WebhookCallback) LogPreAPICall(ctx context.Context, payload *StandardLoggingPayload) error {
+	return nil
+}
+
+// LogPostAPICall is a no-op for webhooks.
+func (w *WebhookCallback) LogPostAPICall(ctx context.Context, payload *StandardLoggingPayload) error {
+	return nil
+}
+
+// LogStreamEvent is a no-op for webhooks.
+func (w *WebhookCallback) LogStreamEvent(ctx context.Context, payload *StandardLoggingPayload, chunk any) error {
+	return nil
+}
+
+// LogSuccessEvent queues the payload of a successful request.
+func (w *WebhookCallback) LogSuccessEvent(ctx context.Context, payload *StandardLoggingPayload) error {
+	return w.enqueue("success", payload)
+}
+
+// LogFailureEvent queues the payload of a failed request.
+func (w *WebhookCallback) LogFailureEvent(ctx context.Context, payload *StandardLoggingPayload, err error) error {
+	return w.enqueue("failure", payload)
+}
+
+// LogFallbackEvent is a no-op for webhooks.
+func (w *WebhookCallback) LogFallbackEvent(ctx context.Context, originalModel, fallbackModel string, err error, success bool) error {
+	return nil
+}
+
+// Shutdown stops accepting payloads and waits for queued deliveries to
+// finish until ctx is done.
+func (w *WebhookCallback) Shutdown(ctx context.Context) error {
+	w.once.Do(func() { close(w.stopCh) })
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *WebhookCallback) enqueue(event string, payload *StandardLoggingPayload) error {
+	if payload == nil {
+		return nil
+	}
+	select {
+	case <-w.stopCh:
+		w.drop("shutdown", len(w.config.URLs))
+		return nil
+	default:
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("webhook: failed to marshal payload: %w", err)
+	}
+	for _, url := range w.config.URLs {
+		select {
+		case w.queue <- webhookDelivery{url: url, event: event, body: body}:
+		default:
+			w.drop("queue_full", 1)
+		}
+	}
+	metrics.CallbackQueueSize.WithLabelValues(w.Name()).Set(float64(len(w.queue)))
+	return nil
+}
+
+func (w *WebhookCallback) worker() {
+	defer w.wg.Done()
+	for {
+		select {
+		case d := <-w.queue:
+			w.deliver(d)
+		case <-w.stopCh:
+			// Drain what is already queued, then exit.
+			for {
+				select {
+				case d := <-w.queue:
+					w.deliver(d)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver sends d, retrying with exponential backoff on transport errors,
+// 429 and 5xx responses.
+func (w *WebhookCallback) deliver(d webhookDelivery) {
+	defer metrics.CallbackQueueSize.WithLabelValues(w.Name()).Set(float64(len(w.queue)))
+
+	backoff := w.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := w.send(d)
+		if err == nil {
+			return
+		}
+		if !retry {
+			w.drop("rejected", 1)
+			return
+		}
+		if attempt >= w.config.MaxRetries {
+			w.drop("retries_exhausted", 1)
+			return
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-w.stopCh:
+			// Shutting down: use the remaining attempts without waiting.
+			timer.Stop()
+		}
+		backoff *= 2
+	}
+}
+
+func (w *WebhookCallback) send(d webhookDelivery) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, d.url, bytes.NewReader(d.body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, d.event)
+	for k, v := range w.config.Headers {
+		req.Header.Set(k, v)
+	}
+	if w.config.Secret != "" {
+		timestamp := strconv.FormatInt(w.now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(w.config.Secret, timestamp, d.body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("webhook: %s returned status %d", d.url, resp.StatusCode)
+	default:
+		return false, fmt.Errorf("webhook: %s returned status %d", d.url, resp.StatusCode)
+	}
+}
+
+func (w *WebhookCallback) drop(reason string, n int) {
+	metrics.CallbackDeliveriesDropped.WithLabelValues(w.Name(), reason).Add(float64(n))
+}
+
+// SignWebhookPayload returns the X-LLMux-Signature value for body: the hex
+// HMAC-SHA256 of "<timestamp>.<body>" under secret, prefixed with "sha256=".
+// Receivers recompute it with the X-LLMux-Timestamp header to authenticate
+// the delivery and reject replays.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
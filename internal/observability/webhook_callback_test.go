@@ -0,0 +1,138 @@
+package observability
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/blueberrycongee/llmux/internal/metrics"
+)
+
+func TestWebhookCallback_SignsAndRetries(t *testing.T) {
+	var attempts atomic.Int32
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	cb, err := NewWebhookCallback(WebhookConfig{
+		URLs:         []string{server.URL},
+		Secret:       "s3cret",
+		Headers:      map[string]string{"X-Source": "llmux"},
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewWebhookCallback: %v", err)
+	}
+
+	payload := &StandardLoggingPayload{RequestID: "req-1", Model: "gpt-4o", Status: RequestStatusSuccess}
+	if err := cb.LogSuccessEvent(context.Background(), payload); err != nil {
+		t.Fatalf("LogSuccessEvent: %v", err)
+	}
+
+	var req *http.Request
+	var body []byte
+	select {
+	case req = <-received:
+		body = <-bodies
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("expected delivery on the third attempt, got %d attempts", attempts.Load())
+	}
+	if got := req.Header.Get(WebhookEventHeader); got != "success" {
+		t.Errorf("event header = %q, want success", got)
+	}
+	if got := req.Header.Get("X-Source"); got != "llmux" {
+		t.Errorf("custom header = %q, want llmux", got)
+	}
+	want := SignWebhookPayload("s3cret", req.Header.Get(WebhookTimestampHeader), body)
+	if got := req.Header.Get(WebhookSignatureHeader); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+
+	var decoded StandardLoggingPayload
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if decoded.RequestID != "req-1" || decoded.Model != "gpt-4o" {
+		t.Errorf("unexpected payload: %+v", decoded)
+	}
+
+	if err := cb.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
+
+func TestWebhookCallback_Drops(t *testing.T) {
+	rejected := metrics.CallbackDeliveriesDropped.WithLabelValues("webhook", "rejected")
+	exhausted := metrics.CallbackDeliveriesDropped.WithLabelValues("webhook", "retries_exhausted")
+	queueFull := metrics.CallbackDeliveriesDropped.WithLabelValues("webhook", "queue_full")
+	baseRejected := testutil.ToFloat64(rejected)
+	baseExhausted := testutil.ToFloat64(exhausted)
+	baseQueueFull := testutil.ToFloat64(queueFull)
+
+	badRequest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer badRequest.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	cb, err := NewWebhookCallback(WebhookConfig{
+		URLs:         []string{badRequest.URL, failing.URL},
+		MaxRetries:   1,
+		RetryBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewWebhookCallback: %v", err)
+	}
+	_ = cb.LogFailureEvent(context.Background(), &StandardLoggingPayload{RequestID: "req-1"}, nil)
+	if err := cb.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := testutil.ToFloat64(rejected) - baseRejected; got != 1 {
+		t.Errorf("rejected drops = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(exhausted) - baseExhausted; got != 1 {
+		t.Errorf("retries_exhausted drops = %v, want 1", got)
+	}
+
+	// A callback whose workers are busy drops payloads beyond its queue.
+	block := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer slow.Close()
+	defer close(block)
+
+	cb, err = NewWebhookCallback(WebhookConfig{URLs: []string{slow.URL}, QueueSize: 1, Workers: 1})
+	if err != nil {
+		t.Fatalf("NewWebhookCallback: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		_ = cb.LogSuccessEvent(context.Background(), &StandardLoggingPayload{RequestID: "req"})
+	}
+	if got := testutil.ToFloat64(queueFull) - baseQueueFull; got < 3 {
+		t.Errorf("queue_full drops = %v, want at least 3", got)
+	}
+}
@@ -5,6 +5,8 @@
 //   - RateLimitPlugin: Request rate limiting per client/API key
 //   - MetricsPlugin: Request metrics collection
 //   - CachePlugin: Response caching with TTL
+//   - HTTPPlugin: Hooks delegated to an external HTTP service
+//   - TransformPlugin: System prompt injection, message templating and parameter clamping
 //
 // Example usage:
 //
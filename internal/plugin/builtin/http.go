@@ -0,0 +1,338 @@
+package builtin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/plugin"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// HTTP plugin failure policies.
+const (
+	// HTTPFailOpen continues the request unchanged when the service fails.
+	HTTPFailOpen = "fail_open"
+	// HTTPFailClosed rejects the request when the service fails.
+	HTTPFailClosed = "fail_closed"
+)
+
+// HTTP plugin hooks.
+const (
+	HTTPHookPre  = "pre"
+	HTTPHookPost = "post"
+)
+
+// maxHTTPPluginResponse bounds the reply read from an HTTP plugin service.
+const maxHTTPPluginResponse = 10 << 20
+
+// HTTPHookRequest is the JSON body POSTed to an HTTP plugin service.
+type HTTPHookRequest struct {
+	Hook      string              `json:"hook"` // "pre" or "post"
+	RequestID string              `json:"request_id"`
+	Model     string              `json:"model,omitempty"`
+	Stream    bool                `json:"stream,omitempty"`
+	APIKeyID  string              `json:"api_key_id,omitempty"`
+	TeamID    string              `json:"team_id,omitempty"`
+	UserID    string              `json:"user_id,omitempty"`
+	Request   *types.ChatRequest  `json:"request,omitempty"`
+	Response  *types.ChatResponse `json:"response,omitempty"`
+	Error     string              `json:"error,omitempty"`
+	// Config is the plugin's config block, for services shared by several
+	// plugin declarations.
+	Config map[string]any `json:"config,omitempty"`
+}
+
+// HTTPHookResponse is the JSON reply of an HTTP plugin service. An empty
+// reply continues unchanged. Reject stops the request with an error; in a
+// pre hook, Response answers it without calling a provider, and Request
+// replaces it. In a post hook, Response replaces the response.
+type HTTPHookResponse struct {
+	Request  *types.ChatRequest  `json:"request,omitempty"`
+	Response *types.ChatResponse `json:"response,omitempty"`
+	Reject   *HTTPHookReject     `json:"reject,omitempty"`
+}
+
+// HTTPHookReject describes why a service rejected a request.
+type HTTPHookReject struct {
+	Message string `json:"message"`
+	// Type is "content_policy" (default), "permission" or "invalid_request".
+	Type string `json:"type,omitempty"`
+}
+
+// HTTPPlugin delegates PreHook and PostHook to an external HTTP service, so
+// guardrails and transformations can be written in any language. Each hook
+// POSTs an HTTPHookRequest and applies the HTTPHookResponse.
+type HTTPPlugin struct {
+	name     string
+	url      string
+	headers  map[string]string
+	config   map[string]any
+	client   *http.Client
+	priority int
+	policy   string
+	pre      bool
+	post     bool
+	logger   *slog.Logger
+}
+
+// HTTPPluginOption configures the HTTPPlugin.
+type HTTPPluginOption func(*HTTPPlugin)
+
+// WithHTTPPluginPriority sets the plugin priority.
+func WithHTTPPluginPriority(priority int) HTTPPluginOption {
+	return func(p *HTTPPlugin) {
+		p.priority = priority
+	}
+}
+
+// WithHTTPPluginTimeout bounds each call to the service (default 5s).
+func WithHTTPPluginTimeout(timeout time.Duration) HTTPPluginOption {
+	return func(p *HTTPPlugin) {
+		if timeout > 0 {
+			p.client.Timeout = timeout
+		}
+	}
+}
+
+// WithHTTPPluginHeaders sets headers sent with each call, e.g. Authorization.
+func WithHTTPPluginHeaders(headers map[string]string) HTTPPluginOption {
+	return func(p *HTTPPlugin) {
+		p.headers = headers
+	}
+}
+
+// WithHTTPPluginConfig sets the config block sent with each call.
+func WithHTTPPluginConfig(config map[string]any) HTTPPluginOption {
+	return func(p *HTTPPlugin) {
+		p.config = config
+	}
+}
+
+// WithHTTPPluginFailurePolicy sets what happens when the service times out,
+// is unreachable or replies with an error: HTTPFailOpen (default) or
+// HTTPFailClosed.
+func WithHTTPPluginFailurePolicy(policy string) HTTPPluginOption {
+	return func(p *HTTPPlugin) {
+		p.policy = policy
+	}
+}
+
+// WithHTTPPluginHooks selects the hooks sent to the service (default both).
+func WithHTTPPluginHooks(hooks ...string) HTTPPluginOption {
+	return func(p *HTTPPlugin) {
+		p.pre, p.post = false, false
+		for _, hook := range hooks {
+			switch hook {
+			case HTTPHookPre:
+				p.pre = true
+			case HTTPHookPost:
+				p.post = true
+			}
+		}
+	}
+}
+
+// WithHTTPPluginLogger sets the logger.
+func WithHTTPPluginLogger(logger *slog.Logger) HTTPPluginOption {
+	return func(p *HTTPPlugin) {
+		if logger != nil {
+			p.logger = logger
+		}
+	}
+}
+
+// NewHTTPPlugin creates a plugin calling the service at url.
+// Default priority is 50 (runs before most plugins, like guardrails).
+func NewHTTPPlugin(name, url string, opts ...HTTPPluginOption) *HTTPPlugin {
+	p := &HTTPPlugin{
+		name:     name,
+		url:      url,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		priority: 50,
+		policy:   HTTPFailOpen,
+		pre:      true,
+		post:     true,
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *HTTPPlugin) Name() string  { return p.name }
+func (p *HTTPPlugin) Priority() int { return p.priority }
+
+func (p *HTTPPlugin) PreHook(ctx *plugin.Context, req *types.ChatRequest) (*types.ChatRequest, *plugin.ShortCircuit, error) {
+	if !p.pre {
+		return req, nil, nil
+	}
+	hookReq := p.hookRequest(ctx, HTTPHookPre, req.Model)
+	hookReq.Stream = req.Stream
+	hookReq.Request = req
+	reply, err := p.call(ctx, hookReq)
+	if err != nil {
+		if p.policy == HTTPFailClosed {
+			return req, &plugin.ShortCircuit{Error: p.unavailable(req.Model)}, err
+		}
+		return req, nil, err
+	}
+	if reply.Reject != nil {
+		return req, &plugin.ShortCircuit{Error: p.rejection(req.Model, reply.Reject)}, nil
+	}
+	if reply.Response != nil {
+		return req, &plugin.ShortCircuit{Response: reply.Response}, nil
+	}
+	if reply.Request != nil {
+		return reply.Request, nil, nil
+	}
+	return req, nil, nil
+}
+
+func (p *HTTPPlugin) PostHook(ctx *plugin.Context, resp *types.ChatResponse, err error) (*types.ChatResponse, error, error) {
+	if !p.post {
+		return resp, err, nil
+	}
+	hookReq := p.hookRequest(ctx, HTTPHookPost, ctx.Model)
+	hookReq.Response = resp
+	if err != nil {
+		hookReq.Error = err.Error()
+	}
+	model := ctx.Model
+	if resp != nil && resp.Model != "" {
+		model = resp.Model
+	}
+
+	reply, callErr := p.call(ctx, hookReq)
+	if callErr != nil {
+		if p.policy == HTTPFailClosed && err == nil {
+			return nil, p.unavailable(model), callErr
+		}
+		return resp, err, callErr
+	}
+	if reply.Reject != nil {
+		return nil, p.rejection(model, reply.Reject), nil
+	}
+	if reply.Response != nil {
+		return reply.Response, nil, nil
+	}
+	return resp, err, nil
+}
+
+// PreStreamHook sends the pre hook for streaming requests. A Response reply
+// cannot answer a stream and is ignored.
+func (p *HTTPPlugin) PreStreamHook(ctx *plugin.Context, req *types.ChatRequest) (*types.ChatRequest, *plugin.StreamShortCircuit, error) {
+	out, sc, err := p.PreHook(ctx, req)
+	if sc != nil && sc.Error != nil {
+		return out, &plugin.StreamShortCircuit{Error: sc.Error}, err
+	}
+	if sc != nil && sc.Response != nil {
+		p.logger.Warn("http plugin response ignored for streaming request", "plugin", p.name)
+	}
+	return out, nil, err
+}
+
+// OnStreamChunk passes chunks through; streams are not sent to the service.
+func (p *HTTPPlugin) OnStreamChunk(ctx *plugin.Context, chunk *types.StreamChunk) (*types.StreamChunk, error) {
+	return chunk, nil
+}
+
+// PostStreamHook is a no-op.
+func (p *HTTPPlugin) PostStreamHook(ctx *plugin.Context, err error) error {
+	return nil
+}
+
+func (p *HTTPPlugin) Cleanup() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
+
+func (p *HTTPPlugin) hookRequest(ctx *plugin.Context, hook, model string) *HTTPHookRequest {
+	hookReq := &HTTPHookRequest{Hook: hook, RequestID: ctx.RequestID, Model: model, Config: p.config}
+	hookReq.APIKeyID, hookReq.TeamID, hookReq.UserID = identity(ctx)
+	return hookReq
+}
+
+// identity returns the API key, team and user of the request, if known.
+func identity(ctx *plugin.Context) (keyID, teamID, userID string) {
+	authCtx := ctx.Auth
+	if authCtx == nil {
+		authCtx = auth.GetAuthContext(ctx)
+	}
+	if authCtx == nil || authCtx.APIKey == nil {
+		return "", "", ""
+	}
+	keyID = authCtx.APIKey.ID
+	if authCtx.APIKey.TeamID != nil {
+		teamID = *authCtx.APIKey.TeamID
+	}
+	if authCtx.APIKey.UserID != nil {
+		userID = *authCtx.APIKey.UserID
+	}
+	return keyID, teamID, userID
+}
+
+// call POSTs hookReq to the service. Replies other than 2xx are failures.
+func (p *HTTPPlugin) call(ctx context.Context, hookReq *HTTPHookRequest) (*HTTPHookResponse, error) {
+	body, err := json.Marshal(hookReq)
+	if err != nil {
+		return nil, fmt.Errorf("http plugin %s: encode request: %w", p.name, err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("http plugin %s: %w", p.name, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range p.headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("http plugin %s: %w", p.name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPPluginResponse))
+	if err != nil {
+		return nil, fmt.Errorf("http plugin %s: read reply: %w", p.name, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("http plugin %s: service returned status %d", p.name, resp.StatusCode)
+	}
+
+	var reply HTTPHookResponse
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &reply); err != nil {
+			return nil, fmt.Errorf("http plugin %s: decode reply: %w", p.name, err)
+		}
+	}
+	return &reply, nil
+}
+
+func (p *HTTPPlugin) unavailable(model string) error {
+	return llmerrors.NewServiceUnavailableError("", model, "plugin "+p.name+" is unavailable")
+}
+
+func (p *HTTPPlugin) rejection(model string, reject *HTTPHookReject) error {
+	msg := reject.Message
+	if msg == "" {
+		msg = "request rejected by plugin " + p.name
+	}
+	switch reject.Type {
+	case "permission":
+		return llmerrors.NewPermissionError("", model, msg)
+	case "invalid_request":
+		return llmerrors.NewInvalidRequestError("", model, msg)
+	default:
+		return llmerrors.NewContentPolicyError("", model, msg)
+	}
+}
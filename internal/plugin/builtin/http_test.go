@@ -0,0 +1,142 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/plugin"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// newHookServer answers every hook with reply and records the requests.
+func newHookServer(t *testing.T, reply string) (*httptest.Server, *[]HTTPHookRequest) {
+	t.Helper()
+	var received []HTTPHookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req HTTPHookRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		received = append(received, req)
+		_, _ = w.Write([]byte(reply))
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func hookChatRequest() *types.ChatRequest {
+	return &types.ChatRequest{
+		Model:    "gpt-4o",
+		Messages: []types.ChatMessage{{Role: "user", Content: json.RawMessage(`"hi"`)}},
+	}
+}
+
+func TestHTTPPlugin_PreHookModifiesRequest(t *testing.T) {
+	server, received := newHookServer(t, `{"request":{"model":"gpt-4o-mini","messages":[{"role":"user","content":"hello"}]}}`)
+	p := NewHTTPPlugin("policy", server.URL)
+
+	out, sc, err := p.PreHook(plugin.NewContext(context.Background(), "req-1"), hookChatRequest())
+	if err != nil || sc != nil {
+		t.Fatalf("PreHook() = %v, %v", sc, err)
+	}
+	if out.Model != "gpt-4o-mini" {
+		t.Fatalf("model = %q, want the replaced request", out.Model)
+	}
+	if len(*received) != 1 || (*received)[0].Hook != HTTPHookPre || (*received)[0].RequestID != "req-1" || (*received)[0].Request == nil {
+		t.Fatalf("unexpected hook request %+v", *received)
+	}
+}
+
+func TestHTTPPlugin_PreHookReject(t *testing.T) {
+	server, _ := newHookServer(t, `{"reject":{"message":"no secrets","type":"permission"}}`)
+	p := NewHTTPPlugin("policy", server.URL)
+
+	_, sc, err := p.PreHook(plugin.NewContext(context.Background(), "req-1"), hookChatRequest())
+	if err != nil {
+		t.Fatalf("PreHook() error = %v", err)
+	}
+	if sc == nil || sc.Error == nil {
+		t.Fatal("expected a short-circuit error")
+	}
+	var llmErr *llmerrors.LLMError
+	if !errors.As(sc.Error, &llmErr) || llmErr.StatusCode != http.StatusForbidden || llmErr.Message != "no secrets" {
+		t.Fatalf("error = %v, want a permission error", sc.Error)
+	}
+}
+
+func TestHTTPPlugin_PreHookRespond(t *testing.T) {
+	server, _ := newHookServer(t, `{"response":{"id":"canned","choices":[{"message":{"role":"assistant","content":"cached"}}]}}`)
+	p := NewHTTPPlugin("policy", server.URL)
+
+	_, sc, err := p.PreHook(plugin.NewContext(context.Background(), "req-1"), hookChatRequest())
+	if err != nil || sc == nil || sc.Response == nil || sc.Response.ID != "canned" {
+		t.Fatalf("PreHook() = %+v, %v, want a short-circuit response", sc, err)
+	}
+
+	req := hookChatRequest()
+	req.Stream = true
+	out, ssc, err := p.PreStreamHook(plugin.NewContext(context.Background(), "req-2"), req)
+	if err != nil || ssc != nil || out != req {
+		t.Fatalf("PreStreamHook() = %+v, %v, want the response ignored", ssc, err)
+	}
+}
+
+func TestHTTPPlugin_PostHook(t *testing.T) {
+	server, received := newHookServer(t, `{"response":{"id":"rewritten"}}`)
+	p := NewHTTPPlugin("policy", server.URL, WithHTTPPluginHooks(HTTPHookPost))
+	ctx := plugin.NewContext(context.Background(), "req-1")
+	ctx.Model = "gpt-4o"
+
+	req := hookChatRequest()
+	if out, sc, err := p.PreHook(ctx, req); out != req || sc != nil || err != nil {
+		t.Fatal("expected the pre hook to be skipped")
+	}
+	resp, respErr, err := p.PostHook(ctx, &types.ChatResponse{ID: "original"}, nil)
+	if err != nil || respErr != nil || resp.ID != "rewritten" {
+		t.Fatalf("PostHook() = %+v, %v, %v", resp, respErr, err)
+	}
+	if len(*received) != 1 || (*received)[0].Hook != HTTPHookPost || (*received)[0].Model != "gpt-4o" || (*received)[0].Response.ID != "original" {
+		t.Fatalf("unexpected hook requests %+v", *received)
+	}
+}
+
+func TestHTTPPlugin_FailurePolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	t.Cleanup(server.Close)
+
+	open := NewHTTPPlugin("policy", server.URL, WithHTTPPluginTimeout(20*time.Millisecond))
+	req := hookChatRequest()
+	out, sc, err := open.PreHook(plugin.NewContext(context.Background(), "req-1"), req)
+	if err == nil || sc != nil || out != req {
+		t.Fatalf("fail_open PreHook() = %+v, %v, want the request to continue", sc, err)
+	}
+
+	closed := NewHTTPPlugin("policy", server.URL, WithHTTPPluginTimeout(20*time.Millisecond), WithHTTPPluginFailurePolicy(HTTPFailClosed))
+	_, sc, err = closed.PreHook(plugin.NewContext(context.Background(), "req-1"), req)
+	if err == nil || sc == nil || sc.Error == nil {
+		t.Fatalf("fail_closed PreHook() = %+v, %v, want a short-circuit error", sc, err)
+	}
+	resp, respErr, err := closed.PostHook(plugin.NewContext(context.Background(), "req-1"), &types.ChatResponse{ID: "original"}, nil)
+	if err == nil || resp != nil || respErr == nil {
+		t.Fatalf("fail_closed PostHook() = %+v, %v, %v, want an error", resp, respErr, err)
+	}
+}
+
+func TestHTTPPlugin_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	p := NewHTTPPlugin("policy", server.URL, WithHTTPPluginFailurePolicy(HTTPFailClosed))
+	if _, sc, err := p.PreHook(plugin.NewContext(context.Background(), "req-1"), hookChatRequest()); err == nil || sc == nil {
+		t.Fatalf("PreHook() = %+v, %v, want the 500 treated as a failure", sc, err)
+	}
+}
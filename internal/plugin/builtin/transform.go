@@ -0,0 +1,335 @@
+package builtin
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/plugin"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// System prompt modes of the TransformPlugin.
+const (
+	SystemPromptPrepend = "prepend"
+	SystemPromptAppend  = "append"
+	SystemPromptReplace = "replace"
+)
+
+// TransformConfig configures a TransformPlugin. Prompts and templates are
+// Go text/templates executed with TransformData.
+type TransformConfig struct {
+	// SystemPrompt is injected into every request.
+	SystemPrompt string
+	// TeamSystemPrompts replace SystemPrompt for the listed team IDs.
+	TeamSystemPrompts map[string]string
+	// SystemPromptMode places the prompt relative to an existing system
+	// message: SystemPromptPrepend (default), SystemPromptAppend or
+	// SystemPromptReplace. Without a system message one is added.
+	SystemPromptMode string
+
+	// MessageTemplate rewrites the text of messages whose role is in
+	// MessageRoles (default "user").
+	MessageTemplate string
+	MessageRoles    []string
+
+	// ResponseTemplate rewrites the text of non-streaming responses.
+	ResponseTemplate string
+
+	// MaxTemperature, MaxTopP and MaxTokens clamp sampling parameters. An
+	// unset parameter is set to the maximum, since provider defaults may
+	// exceed it.
+	MaxTemperature *float64
+	MaxTopP        *float64
+	MaxTokens      int
+}
+
+// TransformData is the data templates are executed with.
+type TransformData struct {
+	Content  string // message or response text; empty for system prompts
+	Role     string
+	Model    string
+	APIKeyID string
+	TeamID   string
+	UserID   string
+}
+
+// TransformPlugin applies organization-wide transformations to requests and
+// responses: system prompt injection per team, message rewriting through
+// templates and parameter clamping.
+type TransformPlugin struct {
+	name     string
+	priority int
+
+	systemPrompt *template.Template
+	teamPrompts  map[string]*template.Template
+	systemMode   string
+	message      *template.Template
+	roles        map[string]bool
+	response     *template.Template
+
+	maxTemperature *float64
+	maxTopP        *float64
+	maxTokens      int
+}
+
+// TransformOption configures the TransformPlugin.
+type TransformOption func(*TransformPlugin)
+
+// WithTransformPriority sets the plugin priority.
+func WithTransformPriority(priority int) TransformOption {
+	return func(p *TransformPlugin) {
+		p.priority = priority
+	}
+}
+
+// NewTransformPlugin creates a transformation plugin. It fails when a
+// template does not parse.
+// Default priority is 20 (after rate limiting and guardrails, so those see
+// the caller's request).
+func NewTransformPlugin(name string, cfg TransformConfig, opts ...TransformOption) (*TransformPlugin, error) {
+	p := &TransformPlugin{
+		name:           name,
+		priority:       20,
+		systemMode:     cfg.SystemPromptMode,
+		maxTemperature: cfg.MaxTemperature,
+		maxTopP:        cfg.MaxTopP,
+		maxTokens:      cfg.MaxTokens,
+	}
+	switch p.systemMode {
+	case "":
+		p.systemMode = SystemPromptPrepend
+	case SystemPromptPrepend, SystemPromptAppend, SystemPromptReplace:
+	default:
+		return nil, fmt.Errorf("unknown system prompt mode %q", cfg.SystemPromptMode)
+	}
+
+	var err error
+	if p.systemPrompt, err = parseTransformTemplate("system_prompt", cfg.SystemPrompt); err != nil {
+		return nil, err
+	}
+	if len(cfg.TeamSystemPrompts) > 0 {
+		p.teamPrompts = make(map[string]*template.Template, len(cfg.TeamSystemPrompts))
+		for team, text := range cfg.TeamSystemPrompts {
+			if p.teamPrompts[team], err = parseTransformTemplate("team_system_prompts."+team, text); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if p.message, err = parseTransformTemplate("message_template", cfg.MessageTemplate); err != nil {
+		return nil, err
+	}
+	if p.response, err = parseTransformTemplate("response_template", cfg.ResponseTemplate); err != nil {
+		return nil, err
+	}
+	roles := cfg.MessageRoles
+	if len(roles) == 0 {
+		roles = []string{"user"}
+	}
+	p.roles = make(map[string]bool, len(roles))
+	for _, role := range roles {
+		p.roles[role] = true
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+func parseTransformTemplate(name, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func (p *TransformPlugin) Name() string  { return p.name }
+func (p *TransformPlugin) Priority() int { return p.priority }
+
+// PreHook transforms a copy of the request; the caller's request is not
+// modified.
+func (p *TransformPlugin) PreHook(ctx *plugin.Context, req *types.ChatRequest) (*types.ChatRequest, *plugin.ShortCircuit, error) {
+	out, err := p.transformRequest(ctx, req)
+	if err != nil {
+		return req, nil, err
+	}
+	return out, nil, nil
+}
+
+// PostHook applies the response template.
+func (p *TransformPlugin) PostHook(ctx *plugin.Context, resp *types.ChatResponse, err error) (*types.ChatResponse, error, error) {
+	if p.response == nil || err != nil || resp == nil {
+		return resp, err, nil
+	}
+	data := p.data(ctx, resp.Model)
+	data.Role = "assistant"
+	choices := make([]types.Choice, len(resp.Choices))
+	copy(choices, resp.Choices)
+	for i := range choices {
+		content, rewriteErr := rewriteContent(p.response, data, choices[i].Message.Content)
+		if rewriteErr != nil {
+			return resp, err, rewriteErr
+		}
+		choices[i].Message.Content = content
+	}
+	out := *resp
+	out.Choices = choices
+	return &out, nil, nil
+}
+
+// PreStreamHook transforms streaming requests. The response template does
+// not apply to streams.
+func (p *TransformPlugin) PreStreamHook(ctx *plugin.Context, req *types.ChatRequest) (*types.ChatRequest, *plugin.StreamShortCircuit, error) {
+	out, err := p.transformRequest(ctx, req)
+	if err != nil {
+		return req, nil, err
+	}
+	return out, nil, nil
+}
+
+// OnStreamChunk passes chunks through.
+func (p *TransformPlugin) OnStreamChunk(ctx *plugin.Context, chunk *types.StreamChunk) (*types.StreamChunk, error) {
+	return chunk, nil
+}
+
+// PostStreamHook is a no-op.
+func (p *TransformPlugin) PostStreamHook(ctx *plugin.Context, err error) error {
+	return nil
+}
+
+func (p *TransformPlugin) Cleanup() error { return nil }
+
+func (p *TransformPlugin) data(ctx *plugin.Context, model string) TransformData {
+	data := TransformData{Model: model}
+	data.APIKeyID, data.TeamID, data.UserID = identity(ctx)
+	return data
+}
+
+// transformRequest returns a transformed copy of req. A template error
+// leaves the request unchanged.
+func (p *TransformPlugin) transformRequest(ctx *plugin.Context, req *types.ChatRequest) (*types.ChatRequest, error) {
+	out := *req
+	out.Messages = make([]types.ChatMessage, len(req.Messages))
+	copy(out.Messages, req.Messages)
+	data := p.data(ctx, req.Model)
+
+	if p.message != nil {
+		for i, msg := range out.Messages {
+			if !p.roles[msg.Role] {
+				continue
+			}
+			msgData := data
+			msgData.Role = msg.Role
+			content, err := rewriteContent(p.message, msgData, msg.Content)
+			if err != nil {
+				return req, err
+			}
+			out.Messages[i].Content = content
+		}
+	}
+
+	prompt := p.systemPrompt
+	if team, ok := p.teamPrompts[data.TeamID]; ok && data.TeamID != "" {
+		prompt = team
+	}
+	if prompt != nil {
+		systemData := data
+		systemData.Role = "system"
+		text, err := execTransform(prompt, systemData)
+		if err != nil {
+			return req, err
+		}
+		out.Messages = p.injectSystemPrompt(out.Messages, text)
+	}
+
+	out.Temperature = clampFloat(out.Temperature, p.maxTemperature)
+	out.TopP = clampFloat(out.TopP, p.maxTopP)
+	if p.maxTokens > 0 && (out.MaxTokens == 0 || out.MaxTokens > p.maxTokens) {
+		out.MaxTokens = p.maxTokens
+	}
+	return &out, nil
+}
+
+func (p *TransformPlugin) injectSystemPrompt(messages []types.ChatMessage, prompt string) []types.ChatMessage {
+	for i, msg := range messages {
+		if msg.Role != "system" && msg.Role != "developer" {
+			continue
+		}
+		existing := msg.TextContent()
+		switch p.systemMode {
+		case SystemPromptAppend:
+			prompt = existing + "\n\n" + prompt
+		case SystemPromptPrepend:
+			prompt = prompt + "\n\n" + existing
+		}
+		messages[i].Content = transformString(prompt)
+		return messages
+	}
+	system := types.ChatMessage{Role: "system", Content: transformString(prompt)}
+	return append([]types.ChatMessage{system}, messages...)
+}
+
+// rewriteContent executes tmpl on the text of content: a string, or each
+// text part of multi-part content. Other content is returned unchanged.
+func rewriteContent(tmpl *template.Template, data TransformData, content json.RawMessage) (json.RawMessage, error) {
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		data.Content = text
+		rewritten, err := execTransform(tmpl, data)
+		if err != nil {
+			return content, err
+		}
+		return transformString(rewritten), nil
+	}
+
+	var parts []map[string]any
+	if err := json.Unmarshal(content, &parts); err != nil {
+		return content, nil
+	}
+	for _, part := range parts {
+		partType, _ := part["type"].(string)
+		text, ok := part["text"].(string)
+		if !ok || (partType != "" && partType != "text") {
+			continue
+		}
+		data.Content = text
+		rewritten, err := execTransform(tmpl, data)
+		if err != nil {
+			return content, err
+		}
+		part["text"] = rewritten
+	}
+	out, err := json.Marshal(parts)
+	if err != nil {
+		return content, err
+	}
+	return out, nil
+}
+
+func execTransform(tmpl *template.Template, data TransformData) (string, error) {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("execute %s: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+func transformString(s string) json.RawMessage {
+	data, _ := json.Marshal(s)
+	return data
+}
+
+func clampFloat(value, maxValue *float64) *float64 {
+	if maxValue == nil || (value != nil && *value <= *maxValue) {
+		return value
+	}
+	clamped := *maxValue
+	return &clamped
+}
@@ -0,0 +1,152 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/plugin"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+func teamContext(team string) *plugin.Context {
+	ctx := auth.WithAuthContext(context.Background(), &auth.AuthContext{APIKey: &auth.APIKey{ID: "key-1", TeamID: &team}})
+	return plugin.NewContext(ctx, "req-1")
+}
+
+func TestTransformPlugin_SystemPrompt(t *testing.T) {
+	p, err := NewTransformPlugin("standards", TransformConfig{
+		SystemPrompt:      "Be concise.",
+		TeamSystemPrompts: map[string]string{"legal": "Cite sources for {{.Model}}."},
+	})
+	if err != nil {
+		t.Fatalf("NewTransformPlugin() error = %v", err)
+	}
+
+	req := hookChatRequest()
+	out, _, err := p.PreHook(teamContext("support"), req)
+	if err != nil {
+		t.Fatalf("PreHook() error = %v", err)
+	}
+	if len(out.Messages) != 2 || out.Messages[0].Role != "system" || out.Messages[0].TextContent() != "Be concise." {
+		t.Fatalf("messages = %+v, want the system prompt first", out.Messages)
+	}
+	if len(req.Messages) != 1 {
+		t.Fatal("expected the caller's request to be left unmodified")
+	}
+
+	out, _, _ = p.PreHook(teamContext("legal"), req)
+	if got := out.Messages[0].TextContent(); got != "Cite sources for gpt-4o." {
+		t.Fatalf("team prompt = %q", got)
+	}
+}
+
+func TestTransformPlugin_SystemPromptModes(t *testing.T) {
+	for mode, want := range map[string]string{
+		SystemPromptPrepend: "Org rules.\n\nYou are a bot.",
+		SystemPromptAppend:  "You are a bot.\n\nOrg rules.",
+		SystemPromptReplace: "Org rules.",
+	} {
+		p, err := NewTransformPlugin("standards", TransformConfig{SystemPrompt: "Org rules.", SystemPromptMode: mode})
+		if err != nil {
+			t.Fatalf("NewTransformPlugin(%s) error = %v", mode, err)
+		}
+		req := hookChatRequest()
+		req.Messages = append([]types.ChatMessage{{Role: "system", Content: json.RawMessage(`"You are a bot."`)}}, req.Messages...)
+		out, _, _ := p.PreHook(plugin.NewContext(context.Background(), "req-1"), req)
+		if len(out.Messages) != 2 || out.Messages[0].TextContent() != want {
+			t.Fatalf("%s: system prompt = %q, want %q", mode, out.Messages[0].TextContent(), want)
+		}
+	}
+}
+
+func TestTransformPlugin_MessageTemplate(t *testing.T) {
+	p, err := NewTransformPlugin("standards", TransformConfig{MessageTemplate: "[{{.TeamID}}] {{.Content}}"})
+	if err != nil {
+		t.Fatalf("NewTransformPlugin() error = %v", err)
+	}
+	req := hookChatRequest()
+	req.Messages = append(req.Messages,
+		types.ChatMessage{Role: "assistant", Content: json.RawMessage(`"hello"`)},
+		types.ChatMessage{Role: "user", Content: json.RawMessage(`[{"type":"text","text":"look"},{"type":"image_url","image_url":{"url":"https://x"}}]`)},
+	)
+
+	out, _, err := p.PreHook(teamContext("a"), req)
+	if err != nil {
+		t.Fatalf("PreHook() error = %v", err)
+	}
+	if got := out.Messages[0].TextContent(); got != "[a] hi" {
+		t.Fatalf("user message = %q", got)
+	}
+	if got := out.Messages[1].TextContent(); got != "hello" {
+		t.Fatalf("assistant message = %q, want it untouched", got)
+	}
+	var parts []map[string]any
+	_ = json.Unmarshal(out.Messages[2].Content, &parts)
+	if len(parts) != 2 || parts[0]["text"] != "[a] look" || parts[1]["type"] != "image_url" {
+		t.Fatalf("multi-part message = %s", out.Messages[2].Content)
+	}
+}
+
+func TestTransformPlugin_Clamp(t *testing.T) {
+	maxTemp, maxTopP := 0.5, 0.9
+	p, err := NewTransformPlugin("clamp", TransformConfig{MaxTemperature: &maxTemp, MaxTopP: &maxTopP, MaxTokens: 100})
+	if err != nil {
+		t.Fatalf("NewTransformPlugin() error = %v", err)
+	}
+
+	high, low := 1.5, 0.2
+	req := hookChatRequest()
+	req.Temperature = &high
+	req.TopP = &low
+	req.MaxTokens = 500
+	out, _, _ := p.PreHook(plugin.NewContext(context.Background(), "req-1"), req)
+	if *out.Temperature != 0.5 || *out.TopP != 0.2 || out.MaxTokens != 100 {
+		t.Fatalf("clamped = %v/%v/%d", *out.Temperature, *out.TopP, out.MaxTokens)
+	}
+	if *req.Temperature != 1.5 {
+		t.Fatal("expected the caller's temperature to be left unmodified")
+	}
+
+	out, _, _ = p.PreHook(plugin.NewContext(context.Background(), "req-2"), hookChatRequest())
+	if out.Temperature == nil || *out.Temperature != 0.5 || out.MaxTokens != 100 {
+		t.Fatal("expected unset parameters to be set to the maximum")
+	}
+}
+
+func TestTransformPlugin_ResponseTemplate(t *testing.T) {
+	p, err := NewTransformPlugin("footer", TransformConfig{ResponseTemplate: "{{.Content}}\n-- {{.Model}}"})
+	if err != nil {
+		t.Fatalf("NewTransformPlugin() error = %v", err)
+	}
+	resp := &types.ChatResponse{Model: "gpt-4o", Choices: []types.Choice{{Message: types.ChatMessage{Role: "assistant", Content: json.RawMessage(`"answer"`)}}}}
+
+	out, respErr, err := p.PostHook(plugin.NewContext(context.Background(), "req-1"), resp, nil)
+	if err != nil || respErr != nil {
+		t.Fatalf("PostHook() = %v, %v", respErr, err)
+	}
+	if got := out.Choices[0].Message.TextContent(); got != "answer\n-- gpt-4o" {
+		t.Fatalf("response = %q", got)
+	}
+	if resp.Choices[0].Message.TextContent() != "answer" {
+		t.Fatal("expected the original response to be left unmodified")
+	}
+}
+
+func TestTransformPlugin_InvalidTemplate(t *testing.T) {
+	if _, err := NewTransformPlugin("bad", TransformConfig{MessageTemplate: "{{.Content"}); err == nil {
+		t.Fatal("expected a template parse error")
+	}
+	if _, err := NewTransformPlugin("bad", TransformConfig{SystemPromptMode: "insert"}); err == nil {
+		t.Fatal("expected an unknown mode to be rejected")
+	}
+
+	p, _ := NewTransformPlugin("missing", TransformConfig{MessageTemplate: "{{.Missing}}"})
+	req := hookChatRequest()
+	out, _, err := p.PreHook(plugin.NewContext(context.Background(), "req-1"), req)
+	if err == nil || out != req {
+		t.Fatalf("PreHook() = %v, want the request unchanged on template errors", err)
+	}
+}
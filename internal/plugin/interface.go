@@ -122,6 +122,9 @@ type Context struct {
 	// Auth contains authentication context if auth is enabled.
 	Auth *auth.AuthContext
 
+	// plugins is the pipeline's plugin list when the request started.
+	plugins []Plugin
+
 	// values stores plugin-shared key-value pairs.
 	values map[string]any
 	mu     sync.RWMutex
@@ -200,6 +203,7 @@ func (c *Context) Reset() {
 	c.IsStreaming = false
 	c.StartTime = time.Time{}
 	c.Auth = nil
+	c.plugins = nil
 	// Clear map but keep capacity
 	for k := range c.values {
 		delete(c.values, k)
@@ -110,15 +110,7 @@ func (p *Pipeline) Register(plugin Plugin) error {
 		return ErrTooManyPlugins
 	}
 
-	// Insert in priority order (binary search)
-	idx := sort.Search(len(p.plugins), func(i int) bool {
-		return p.plugins[i].Priority() > plugin.Priority()
-	})
-
-	// Insert at position idx
-	p.plugins = append(p.plugins, nil)
-	copy(p.plugins[idx+1:], p.plugins[idx:])
-	p.plugins[idx] = plugin
+	p.plugins = insertByPriority(p.plugins, plugin)
 
 	p.logger.Info("plugin registered",
 		"name", plugin.Name(),
@@ -140,7 +132,9 @@ func (p *Pipeline) Unregister(name string) error {
 
 	for i, plugin := range p.plugins {
 		if plugin.Name() == name {
-			p.plugins = append(p.plugins[:i], p.plugins[i+1:]...)
+			next := make([]Plugin, 0, len(p.plugins)-1)
+			next = append(next, p.plugins[:i]...)
+			p.plugins = append(next, p.plugins[i+1:]...)
 			p.logger.Info("plugin unregistered", "name", name)
 			return nil
 		}
@@ -149,6 +143,89 @@ func (p *Pipeline) Unregister(name string) error {
 	return ErrPluginNotFound
 }
 
+// Replace atomically unregisters the plugins named in names and registers
+// plugins in their place, so a reload never exposes a partial set. Names
+// that are not registered are ignored. On error nothing changes. Removed
+// plugins are cleaned up; requests already running keep the plugins they
+// started with.
+func (p *Pipeline) Replace(names []string, plugins []Plugin) error {
+	if p.closed.Load() {
+		return ErrPipelineClosed
+	}
+
+	remove := make(map[string]bool, len(names))
+	for _, name := range names {
+		remove[name] = true
+	}
+
+	p.mu.Lock()
+	next := make([]Plugin, 0, len(p.plugins)+len(plugins))
+	var removed []Plugin
+	for _, existing := range p.plugins {
+		if remove[existing.Name()] {
+			removed = append(removed, existing)
+			continue
+		}
+		next = append(next, existing)
+	}
+	for _, plugin := range plugins {
+		if plugin == nil {
+			p.mu.Unlock()
+			return ErrNilPlugin
+		}
+		for _, existing := range next {
+			if existing.Name() == plugin.Name() {
+				p.mu.Unlock()
+				return ErrDuplicatePlugin
+			}
+		}
+		if len(next) >= p.config.MaxPlugins {
+			p.mu.Unlock()
+			return ErrTooManyPlugins
+		}
+		next = insertByPriority(next, plugin)
+	}
+	p.plugins = next
+	p.mu.Unlock()
+
+	for _, plugin := range removed {
+		if err := plugin.Cleanup(); err != nil {
+			p.logger.Warn("plugin cleanup failed", "plugin", plugin.Name(), "error", err)
+		}
+	}
+	p.logger.Info("plugins replaced",
+		"removed", len(removed),
+		"added", len(plugins),
+		"total_plugins", len(next),
+	)
+	return nil
+}
+
+// insertByPriority returns a copy of plugins with plugin inserted in
+// priority order. The slice is never modified in place because requests
+// hold snapshots of it.
+func insertByPriority(plugins []Plugin, plugin Plugin) []Plugin {
+	idx := sort.Search(len(plugins), func(i int) bool {
+		return plugins[i].Priority() > plugin.Priority()
+	})
+	next := make([]Plugin, 0, len(plugins)+1)
+	next = append(next, plugins[:idx]...)
+	next = append(next, plugin)
+	return append(next, plugins[idx:]...)
+}
+
+// pluginsFor returns the plugins a request runs: the snapshot taken when its
+// context was acquired, so the hooks of one request stay consistent when
+// plugins are replaced while it is in flight.
+func (p *Pipeline) pluginsFor(ctx *Context) []Plugin {
+	if ctx != nil && ctx.plugins != nil {
+		return ctx.plugins
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.plugins
+}
+
 // Plugins returns a copy of the registered plugins.
 func (p *Pipeline) Plugins() []Plugin {
 	p.mu.RLock()
@@ -166,6 +243,17 @@ func (p *Pipeline) PluginCount() int {
 	return len(p.plugins)
 }
 
+// HasStreamPlugins reports whether any plugin of the request implements
+// StreamPlugin and so must see decoded stream chunks.
+func (p *Pipeline) HasStreamPlugins(ctx *Context) bool {
+	for _, plugin := range p.pluginsFor(ctx) {
+		if _, ok := plugin.(StreamPlugin); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // GetContext acquires a context from the pool.
 func (p *Pipeline) GetContext(ctx context.Context, requestID string) *Context {
 	poolCtx := p.ctxPool.Get()
@@ -178,6 +266,9 @@ func (p *Pipeline) GetContext(ctx context.Context, requestID string) *Context {
 	pluginCtx.Context = ctx
 	pluginCtx.RequestID = requestID
 	pluginCtx.StartTime = time.Now()
+	p.mu.RLock()
+	pluginCtx.plugins = p.plugins
+	p.mu.RUnlock()
 	return pluginCtx
 }
 
@@ -194,9 +285,7 @@ func (p *Pipeline) RunPreHooks(
 	ctx *Context,
 	req *types.ChatRequest,
 ) (*types.ChatRequest, *ShortCircuit, int) {
-	p.mu.RLock()
-	plugins := p.plugins
-	p.mu.RUnlock()
+	plugins := p.pluginsFor(ctx)
 
 	if len(plugins) == 0 {
 		return req, nil, 0
@@ -271,9 +360,7 @@ func (p *Pipeline) RunPostHooks(
 	respErr error,
 	runFrom int,
 ) (*types.ChatResponse, error) {
-	p.mu.RLock()
-	plugins := p.plugins
-	p.mu.RUnlock()
+	plugins := p.pluginsFor(ctx)
 
 	if len(plugins) == 0 {
 		return resp, respErr
@@ -335,9 +422,7 @@ func (p *Pipeline) RunStreamPreHooks(
 	ctx *Context,
 	req *types.ChatRequest,
 ) (*types.ChatRequest, *StreamShortCircuit, int) {
-	p.mu.RLock()
-	plugins := p.plugins
-	p.mu.RUnlock()
+	plugins := p.pluginsFor(ctx)
 
 	if len(plugins) == 0 {
 		return req, nil, 0
@@ -400,9 +485,7 @@ func (p *Pipeline) RunOnStreamChunk(
 	ctx *Context,
 	chunk *types.StreamChunk,
 ) (*types.StreamChunk, error) {
-	p.mu.RLock()
-	plugins := p.plugins
-	p.mu.RUnlock()
+	plugins := p.pluginsFor(ctx)
 
 	if len(plugins) == 0 || chunk == nil {
 		return chunk, nil
@@ -440,9 +523,7 @@ func (p *Pipeline) RunStreamPostHooks(
 	err error,
 	runFrom int,
 ) error {
-	p.mu.RLock()
-	plugins := p.plugins
-	p.mu.RUnlock()
+	plugins := p.pluginsFor(ctx)
 
 	if len(plugins) == 0 {
 		return err
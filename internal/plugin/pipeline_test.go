@@ -288,6 +288,76 @@ func TestPipeline_Unregister_NotFound(t *testing.T) {
 	}
 }
 
+func TestPipeline_Replace(t *testing.T) {
+	p := NewPipeline(nil, DefaultPipelineConfig())
+	static := newMockPlugin("static", 1)
+	old := newMockPlugin("old", 20)
+	_ = p.Register(static)
+	_ = p.Register(old)
+
+	err := p.Replace([]string{"old", "missing"}, []Plugin{newMockPlugin("b", 30), newMockPlugin("a", 10)})
+
+	if err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	plugins := p.Plugins()
+	if len(plugins) != 3 || plugins[0].Name() != "static" || plugins[1].Name() != "a" || plugins[2].Name() != "b" {
+		t.Fatalf("plugins = %v, want [static a b]", pluginNamesOf(plugins))
+	}
+	if !old.cleanupCalled.Load() {
+		t.Error("removed plugin was not cleaned up")
+	}
+	if static.cleanupCalled.Load() {
+		t.Error("kept plugin was cleaned up")
+	}
+}
+
+func TestPipeline_Replace_DuplicateKeepsPlugins(t *testing.T) {
+	p := NewPipeline(nil, DefaultPipelineConfig())
+	_ = p.Register(newMockPlugin("static", 1))
+	_ = p.Register(newMockPlugin("old", 20))
+
+	err := p.Replace([]string{"old"}, []Plugin{newMockPlugin("static", 10)})
+
+	if !errors.Is(err, ErrDuplicatePlugin) {
+		t.Fatalf("err = %v, want ErrDuplicatePlugin", err)
+	}
+	if names := pluginNamesOf(p.Plugins()); len(names) != 2 || names[1] != "old" {
+		t.Fatalf("plugins = %v, want the original set", names)
+	}
+}
+
+func TestPipeline_Replace_InFlightRequestKeepsPlugins(t *testing.T) {
+	p := NewPipeline(nil, DefaultPipelineConfig())
+	old := newMockPlugin("old", 10)
+	_ = p.Register(old)
+
+	ctx := p.GetContext(context.Background(), "test-123")
+	defer p.PutContext(ctx)
+	_, _, runFrom := p.RunPreHooks(ctx, &types.ChatRequest{Model: "gpt-4"})
+
+	replacement := newMockPlugin("new", 10)
+	if err := p.Replace([]string{"old"}, []Plugin{replacement}); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	_, _ = p.RunPostHooks(ctx, &types.ChatResponse{}, nil, runFrom)
+
+	if !old.postHookCalled.Load() {
+		t.Error("PostHook of the plugin the request started with was not called")
+	}
+	if replacement.postHookCalled.Load() {
+		t.Error("PostHook of the replacement ran for a request it did not see")
+	}
+}
+
+func pluginNamesOf(plugins []Plugin) []string {
+	names := make([]string, 0, len(plugins))
+	for _, plugin := range plugins {
+		names = append(names, plugin.Name())
+	}
+	return names
+}
+
 // =============================================================================
 // PreHook Execution Tests
 // =============================================================================
@@ -0,0 +1,139 @@
+package plugin
+
+import (
+	"context"
+	"path"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+type routeContextKey struct{}
+
+// WithRoute records the HTTP route serving a request, for route-scoped
+// plugins.
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeContextKey{}, route)
+}
+
+// RouteFromContext returns the route recorded by WithRoute.
+func RouteFromContext(ctx context.Context) string {
+	route, _ := ctx.Value(routeContextKey{}).(string)
+	return route
+}
+
+// Scope limits the requests a plugin applies to. Each non-empty list must
+// match: Routes and Models are path.Match patterns (e.g. "/v1/chat/*",
+// "gpt-4o*") and Teams are team IDs. An empty Scope matches every request.
+type Scope struct {
+	Routes []string
+	Models []string
+	Teams  []string
+}
+
+// IsZero reports whether the scope matches every request.
+func (s Scope) IsZero() bool {
+	return len(s.Routes) == 0 && len(s.Models) == 0 && len(s.Teams) == 0
+}
+
+// Matches reports whether a request for model, served by ctx, is in scope.
+func (s Scope) Matches(ctx *Context, model string) bool {
+	if len(s.Routes) > 0 && !matchAny(s.Routes, RouteFromContext(ctx)) {
+		return false
+	}
+	if len(s.Models) > 0 && !matchAny(s.Models, model) {
+		return false
+	}
+	if len(s.Teams) > 0 {
+		authCtx := ctx.Auth
+		if authCtx == nil {
+			authCtx = auth.GetAuthContext(ctx)
+		}
+		if authCtx == nil || authCtx.APIKey == nil || authCtx.APIKey.TeamID == nil {
+			return false
+		}
+		if !matchAny(s.Teams, *authCtx.APIKey.TeamID) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(patterns []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Scoped wraps p so its hooks only run for requests in scope. The decision
+// is made once, in the pre hook, and remembered for the post hooks of the
+// same request. Stream plugins stay stream plugins.
+func Scoped(p Plugin, scope Scope) Plugin {
+	if scope.IsZero() {
+		return p
+	}
+	scoped := &scopedPlugin{Plugin: p, scope: scope, key: "plugin_scope:" + p.Name()}
+	if sp, ok := p.(StreamPlugin); ok {
+		return &scopedStreamPlugin{scopedPlugin: scoped, stream: sp}
+	}
+	return scoped
+}
+
+type scopedPlugin struct {
+	Plugin
+	scope Scope
+	key   string
+}
+
+func (s *scopedPlugin) PreHook(ctx *Context, req *types.ChatRequest) (*types.ChatRequest, *ShortCircuit, error) {
+	if !s.enter(ctx, req) {
+		return req, nil, nil
+	}
+	return s.Plugin.PreHook(ctx, req)
+}
+
+func (s *scopedPlugin) PostHook(ctx *Context, resp *types.ChatResponse, err error) (*types.ChatResponse, error, error) {
+	if !ctx.GetBool(s.key) {
+		return resp, err, nil
+	}
+	return s.Plugin.PostHook(ctx, resp, err)
+}
+
+// enter decides whether the request is in scope and records the decision.
+func (s *scopedPlugin) enter(ctx *Context, req *types.ChatRequest) bool {
+	in := s.scope.Matches(ctx, req.Model)
+	ctx.Set(s.key, in)
+	return in
+}
+
+type scopedStreamPlugin struct {
+	*scopedPlugin
+	stream StreamPlugin
+}
+
+func (s *scopedStreamPlugin) PreStreamHook(ctx *Context, req *types.ChatRequest) (*types.ChatRequest, *StreamShortCircuit, error) {
+	if !s.enter(ctx, req) {
+		return req, nil, nil
+	}
+	return s.stream.PreStreamHook(ctx, req)
+}
+
+func (s *scopedStreamPlugin) OnStreamChunk(ctx *Context, chunk *types.StreamChunk) (*types.StreamChunk, error) {
+	if !ctx.GetBool(s.key) {
+		return chunk, nil
+	}
+	return s.stream.OnStreamChunk(ctx, chunk)
+}
+
+func (s *scopedStreamPlugin) PostStreamHook(ctx *Context, err error) error {
+	if !ctx.GetBool(s.key) {
+		return nil
+	}
+	return s.stream.PostStreamHook(ctx, err)
+}
@@ -0,0 +1,87 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+func TestScoped_ZeroScopeReturnsPlugin(t *testing.T) {
+	plugin := newMockPlugin("test", 10)
+	if Scoped(plugin, Scope{}) != Plugin(plugin) {
+		t.Error("an empty scope should not wrap the plugin")
+	}
+}
+
+func TestScoped_Models(t *testing.T) {
+	plugin := newMockPlugin("test", 10)
+	scoped := Scoped(plugin, Scope{Models: []string{"gpt-4o*"}})
+
+	ctx := NewContext(context.Background(), "req-1")
+	_, _, _ = scoped.PreHook(ctx, &types.ChatRequest{Model: "claude-3"})
+	_, _, _ = scoped.PostHook(ctx, &types.ChatResponse{}, nil)
+	if plugin.preHookCalled.Load() || plugin.postHookCalled.Load() {
+		t.Fatal("hooks ran for a model out of scope")
+	}
+
+	ctx = NewContext(context.Background(), "req-2")
+	_, _, _ = scoped.PreHook(ctx, &types.ChatRequest{Model: "gpt-4o-mini"})
+	_, _, _ = scoped.PostHook(ctx, &types.ChatResponse{}, nil)
+	if !plugin.preHookCalled.Load() || !plugin.postHookCalled.Load() {
+		t.Fatal("hooks did not run for a model in scope")
+	}
+}
+
+func TestScope_Matches(t *testing.T) {
+	team := "team-a"
+	authed := auth.WithAuthContext(context.Background(), &auth.AuthContext{APIKey: &auth.APIKey{ID: "key", TeamID: &team}})
+	routed := WithRoute(authed, "/v1/chat/completions")
+
+	tests := []struct {
+		name  string
+		scope Scope
+		ctx   context.Context
+		want  bool
+	}{
+		{"route match", Scope{Routes: []string{"/v1/chat/*"}}, routed, true},
+		{"route mismatch", Scope{Routes: []string{"/v1/embeddings"}}, routed, false},
+		{"route unknown", Scope{Routes: []string{"/v1/chat/*"}}, authed, false},
+		{"team match", Scope{Teams: []string{"team-a"}}, authed, true},
+		{"team mismatch", Scope{Teams: []string{"team-b"}}, authed, false},
+		{"no team", Scope{Teams: []string{"team-a"}}, context.Background(), false},
+		{"all match", Scope{Routes: []string{"/v1/*/*"}, Models: []string{"gpt-4o"}, Teams: []string{"team-a"}}, routed, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scope.Matches(NewContext(tt.ctx, "req"), "gpt-4o"); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoped_StreamPlugin(t *testing.T) {
+	plugin := newMockStreamPlugin("stream", 10)
+	scoped, ok := Scoped(plugin, Scope{Models: []string{"gpt-4o"}}).(StreamPlugin)
+	if !ok {
+		t.Fatal("scoped stream plugin should implement StreamPlugin")
+	}
+
+	ctx := NewContext(context.Background(), "req-1")
+	_, _, _ = scoped.PreStreamHook(ctx, &types.ChatRequest{Model: "other"})
+	_, _ = scoped.OnStreamChunk(ctx, &types.StreamChunk{})
+	_ = scoped.PostStreamHook(ctx, nil)
+	if plugin.preStreamHookCalled.Load() || plugin.onStreamChunkCalled.Load() != 0 || plugin.postStreamHookCalled.Load() {
+		t.Fatal("stream hooks ran for a model out of scope")
+	}
+
+	ctx = NewContext(context.Background(), "req-2")
+	_, _, _ = scoped.PreStreamHook(ctx, &types.ChatRequest{Model: "gpt-4o"})
+	_, _ = scoped.OnStreamChunk(ctx, &types.StreamChunk{})
+	_ = scoped.PostStreamHook(ctx, nil)
+	if !plugin.preStreamHookCalled.Load() || plugin.onStreamChunkCalled.Load() != 1 || !plugin.postStreamHookCalled.Load() {
+		t.Fatal("stream hooks did not run for a model in scope")
+	}
+}
@@ -0,0 +1,309 @@
+// Package preflight runs startup checks against the configured providers,
+// pricing data and backing stores and reports them as a single table.
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/internal/healthcheck"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/provider"
+)
+
+// Status is the outcome of a single check.
+type Status string
+
+const (
+	StatusPass Status = "PASS"
+	StatusWarn Status = "WARN"
+	StatusFail Status = "FAIL"
+)
+
+// ProviderCheckMode selects how providers are verified.
+type ProviderCheckMode string
+
+const (
+	// ProviderCheckProbe sends a one-token authenticated request per provider.
+	ProviderCheckProbe ProviderCheckMode = "probe"
+	// ProviderCheckDryRun only builds the request, verifying credentials can
+	// be resolved without calling the provider.
+	ProviderCheckDryRun ProviderCheckMode = "dry_run"
+	// ProviderCheckOff skips provider checks.
+	ProviderCheckOff ProviderCheckMode = "off"
+)
+
+// MinRedisVersion is the oldest Redis server version the gateway supports.
+const MinRedisVersion = "6.0"
+
+// Result is one row of the preflight report.
+type Result struct {
+	Check  string `json:"check"`
+	Target string `json:"target"`
+	Status Status `json:"status"`
+	// Critical failures abort startup when the report is enforced.
+	Critical bool   `json:"critical,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	// Fix suggests how to resolve a warning or failure.
+	Fix string `json:"fix,omitempty"`
+}
+
+// Report collects check results.
+type Report struct {
+	Results []Result
+}
+
+// Add appends results to the report.
+func (r *Report) Add(results ...Result) {
+	r.Results = append(r.Results, results...)
+}
+
+// CriticalFailures returns the failed checks marked critical.
+func (r *Report) CriticalFailures() []Result {
+	var failed []Result
+	for _, res := range r.Results {
+		if res.Status == StatusFail && res.Critical {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Counts returns the number of passed, warned and failed checks.
+func (r *Report) Counts() (pass, warn, fail int) {
+	for _, res := range r.Results {
+		switch res.Status {
+		case StatusPass:
+			pass++
+		case StatusWarn:
+			warn++
+		case StatusFail:
+			fail++
+		}
+	}
+	return pass, warn, fail
+}
+
+// WriteTable writes the report as an aligned table followed by a summary line.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tTARGET\tSTATUS\tDETAIL\tFIX")
+	for _, res := range r.Results {
+		status := string(res.Status)
+		if res.Status == StatusFail && res.Critical {
+			status += " (critical)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", res.Check, res.Target, status, res.Detail, dash(res.Fix))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	pass, warn, fail := r.Counts()
+	_, err := fmt.Fprintf(w, "preflight: %d passed, %d warnings, %d failed (%d critical)\n",
+		pass, warn, fail, len(r.CriticalFailures()))
+	return err
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// CheckProviders verifies one deployment per registered provider. Provider
+// failures are critical: every request routed to the provider would fail.
+func CheckProviders(ctx context.Context, client *llmux.Client, mode ProviderCheckMode, timeout time.Duration) []Result {
+	if mode == ProviderCheckOff {
+		return nil
+	}
+	httpClient := &http.Client{Timeout: timeout}
+
+	byProvider := make(map[string]*provider.Deployment)
+	for _, d := range client.ListDeployments() {
+		if cur, ok := byProvider[d.ProviderName]; !ok || d.ModelName < cur.ModelName {
+			byProvider[d.ProviderName] = d
+		}
+	}
+	names := make([]string, 0, len(byProvider))
+	for name := range byProvider {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]Result, 0, len(names))
+	for _, name := range names {
+		deployment := byProvider[name]
+		res := Result{Check: "provider", Target: name, Critical: true}
+		prov, ok := client.GetProvider(name)
+		if !ok {
+			res.Status = StatusFail
+			res.Detail = "provider not registered"
+			res.Fix = "check the providers section of the config"
+			results = append(results, res)
+			continue
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		var err error
+		if mode == ProviderCheckDryRun {
+			_, err = prov.BuildRequest(checkCtx, healthcheck.ProbeRequest(deployment.ModelName))
+		} else {
+			err = healthcheck.Probe(checkCtx, httpClient, prov, deployment)
+		}
+		cancel()
+
+		if err != nil {
+			res.Status = StatusFail
+			res.Detail = fmt.Sprintf("%s %s: %v", mode, deployment.ModelName, err)
+			res.Fix = providerFix(name, err)
+		} else {
+			res.Status = StatusPass
+			res.Detail = fmt.Sprintf("%s %s ok", mode, deployment.ModelName)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func providerFix(name string, err error) string {
+	var llmErr *llmerrors.LLMError
+	if errors.As(err, &llmErr) {
+		switch llmErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return fmt.Sprintf("check the api_key for provider %q", name)
+		case http.StatusNotFound:
+			return fmt.Sprintf("check base_url and model names for provider %q", name)
+		case http.StatusTooManyRequests:
+			return "provider is rate limiting; retry or lower startup probing"
+		}
+	}
+	return fmt.Sprintf("check network access and base_url for provider %q", name)
+}
+
+// CheckPricing reports deployments whose model has no pricing. Gaps are
+// critical when the pricing policy rejects requests for unpriced models and
+// warnings when a fallback applies.
+func CheckPricing(client *llmux.Client) []Result {
+	total := len(client.ListDeployments())
+	missing := client.MissingPricing()
+
+	results := []Result{{
+		Check:  "pricing",
+		Target: "models",
+		Status: StatusPass,
+		Detail: fmt.Sprintf("%d/%d deployments priced (policy %s)", total-len(missing), total, client.PricingPolicy()),
+	}}
+	if len(missing) == total && total > 0 {
+		results[0].Status = StatusWarn
+	}
+	for _, m := range missing {
+		res := Result{
+			Check:  "pricing",
+			Target: m.Provider + "/" + m.Model,
+			Status: StatusWarn,
+			Detail: "no pricing configured; requests use " + m.Fallback,
+			Fix:    "add the model to pricing_file",
+		}
+		if m.Fallback == "reject" {
+			res.Status = StatusFail
+			res.Critical = true
+			res.Detail = "no pricing configured; requests are rejected"
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// SchemaVersioner reports the applied database schema version.
+type SchemaVersioner interface {
+	SchemaVersion(ctx context.Context) (int, error)
+}
+
+// CheckDatabase compares the applied schema version with want.
+func CheckDatabase(ctx context.Context, db SchemaVersioner, want int) Result {
+	res := Result{Check: "database", Target: "postgres", Critical: true}
+	got, err := db.SchemaVersion(ctx)
+	switch {
+	case err != nil:
+		res.Status = StatusFail
+		res.Detail = err.Error()
+		res.Fix = "check database credentials and connectivity"
+	case got < want:
+		res.Status = StatusFail
+		res.Detail = fmt.Sprintf("schema version %d, want %d", got, want)
+		res.Fix = fmt.Sprintf("apply migrations %03d..%03d from internal/auth/migrations", got+1, want)
+	default:
+		res.Status = StatusPass
+		res.Detail = fmt.Sprintf("schema version %d", got)
+	}
+	return res
+}
+
+// CheckRedis pings Redis and verifies the server version.
+func CheckRedis(ctx context.Context, rdb redis.UniversalClient) Result {
+	res := Result{Check: "redis", Target: "redis", Critical: true}
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		res.Status = StatusFail
+		res.Detail = err.Error()
+		res.Fix = "check cache.redis address and password"
+		return res
+	}
+
+	info, err := rdb.Info(ctx, "server").Result()
+	version := parseRedisVersion(info)
+	switch {
+	case err != nil || version == "":
+		res.Status = StatusWarn
+		res.Detail = "reachable; server version unknown"
+	case compareVersions(version, MinRedisVersion) < 0:
+		res.Status = StatusFail
+		res.Detail = fmt.Sprintf("server version %s, want >= %s", version, MinRedisVersion)
+		res.Fix = "upgrade Redis to " + MinRedisVersion + " or later"
+	default:
+		res.Status = StatusPass
+		res.Detail = "server version " + version
+	}
+	return res
+}
+
+func parseRedisVersion(info string) string {
+	for _, line := range strings.Split(info, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "redis_version:"); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// compareVersions compares dotted numeric versions.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,133 @@
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	llmux "github.com/blueberrycongee/llmux"
+	"github.com/blueberrycongee/llmux/providers/openai"
+)
+
+func newTestClient(t *testing.T, status int, models ...string) *llmux.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status >= http.StatusBadRequest {
+			http.Error(w, `{"error":{"message":"denied"}}`, status)
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	prov := openai.New(
+		openai.WithBaseURL(server.URL),
+		openai.WithModels(models...),
+	)
+	client, err := llmux.New(llmux.WithProviderInstance("openai", prov, models))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestCheckProviders(t *testing.T) {
+	t.Run("probe success", func(t *testing.T) {
+		client := newTestClient(t, http.StatusOK, "gpt-4o")
+		results := CheckProviders(context.Background(), client, ProviderCheckProbe, time.Second)
+		require.Len(t, results, 1)
+		require.Equal(t, StatusPass, results[0].Status)
+		require.Equal(t, "openai", results[0].Target)
+	})
+
+	t.Run("probe unauthorized", func(t *testing.T) {
+		client := newTestClient(t, http.StatusUnauthorized, "gpt-4o")
+		results := CheckProviders(context.Background(), client, ProviderCheckProbe, time.Second)
+		require.Len(t, results, 1)
+		require.Equal(t, StatusFail, results[0].Status)
+		require.True(t, results[0].Critical)
+		require.Contains(t, results[0].Fix, "api_key")
+	})
+
+	t.Run("dry run does not call provider", func(t *testing.T) {
+		client := newTestClient(t, http.StatusUnauthorized, "gpt-4o")
+		results := CheckProviders(context.Background(), client, ProviderCheckDryRun, time.Second)
+		require.Len(t, results, 1)
+		require.Equal(t, StatusPass, results[0].Status)
+	})
+
+	t.Run("off", func(t *testing.T) {
+		client := newTestClient(t, http.StatusOK, "gpt-4o")
+		require.Empty(t, CheckProviders(context.Background(), client, ProviderCheckOff, time.Second))
+	})
+}
+
+func TestCheckPricing(t *testing.T) {
+	client := newTestClient(t, http.StatusOK, "gpt-4o", "in-house-model")
+	results := CheckPricing(client)
+	require.Len(t, results, 2)
+	require.Equal(t, StatusPass, results[0].Status)
+	require.Equal(t, "1/2 deployments priced (policy fail)", results[0].Detail)
+	require.Equal(t, StatusFail, results[1].Status)
+	require.Equal(t, "openai/in-house-model", results[1].Target)
+}
+
+type fakeVersioner struct {
+	version int
+	err     error
+}
+
+func (f fakeVersioner) SchemaVersion(context.Context) (int, error) { return f.version, f.err }
+
+func TestCheckDatabase(t *testing.T) {
+	require.Equal(t, StatusPass, CheckDatabase(context.Background(), fakeVersioner{version: 4}, 4).Status)
+
+	res := CheckDatabase(context.Background(), fakeVersioner{version: 2}, 4)
+	require.Equal(t, StatusFail, res.Status)
+	require.Equal(t, "apply migrations 003..004 from internal/auth/migrations", res.Fix)
+
+	res = CheckDatabase(context.Background(), fakeVersioner{err: errors.New("connection refused")}, 4)
+	require.Equal(t, StatusFail, res.Status)
+}
+
+func TestCheckRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer func() { _ = rdb.Close() }()
+	res := CheckRedis(context.Background(), rdb)
+	require.NotEqual(t, StatusFail, res.Status)
+
+	mr.Close()
+	res = CheckRedis(context.Background(), rdb)
+	require.Equal(t, StatusFail, res.Status)
+	require.True(t, res.Critical)
+}
+
+func TestCompareVersions(t *testing.T) {
+	require.Equal(t, 0, compareVersions("6.0", "6.0.0"))
+	require.Equal(t, -1, compareVersions("5.0.14", "6.0"))
+	require.Equal(t, 1, compareVersions("7.2.4", "6.0"))
+	require.Equal(t, "7.2.4", parseRedisVersion("# Server\r\nredis_version:7.2.4\r\nredis_mode:standalone\r\n"))
+}
+
+func TestReportWriteTable(t *testing.T) {
+	report := &Report{}
+	report.Add(
+		Result{Check: "provider", Target: "openai", Status: StatusPass, Detail: "probe ok"},
+		Result{Check: "pricing", Target: "openai/x", Status: StatusFail, Critical: true, Detail: "no pricing", Fix: "add it"},
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteTable(&buf))
+	out := buf.String()
+	require.Contains(t, out, "FAIL (critical)")
+	require.Contains(t, out, "preflight: 1 passed, 0 warnings, 1 failed (1 critical)")
+	require.Len(t, report.CriticalFailures(), 1)
+}
@@ -0,0 +1,57 @@
+package preflight
+
+// Issue codes of a config validation.
+const (
+	IssueParse   = "parse"   // The document is not valid YAML for the config
+	IssueInvalid = "invalid" // The config failed validation
+	IssueClient  = "client"  // No client could be built from the config
+)
+
+// Issue is an error or warning found while validating a config.
+type Issue struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Validation is the outcome of validating a candidate config without
+// applying it. Checks holds the provider and pricing checks run against a
+// client built from the config. The config is valid when there are no
+// errors and no critical check failed.
+type Validation struct {
+	Valid    bool     `json:"valid"`
+	Errors   []Issue  `json:"errors"`
+	Warnings []Issue  `json:"warnings"`
+	Checks   []Result `json:"checks"`
+}
+
+// NewValidation returns an empty, valid validation.
+func NewValidation() *Validation {
+	return &Validation{
+		Valid:    true,
+		Errors:   []Issue{},
+		Warnings: []Issue{},
+		Checks:   []Result{},
+	}
+}
+
+// AddError records an error and marks the config invalid.
+func (v *Validation) AddError(code, message string) {
+	v.Errors = append(v.Errors, Issue{Code: code, Message: message})
+	v.Valid = false
+}
+
+// AddWarning records a warning.
+func (v *Validation) AddWarning(code, message string) {
+	v.Warnings = append(v.Warnings, Issue{Code: code, Message: message})
+}
+
+// AddChecks records check results; a critical failure marks the config
+// invalid.
+func (v *Validation) AddChecks(results ...Result) {
+	for _, res := range results {
+		if res.Status == StatusFail && res.Critical {
+			v.Valid = false
+		}
+	}
+	v.Checks = append(v.Checks, results...)
+}
@@ -0,0 +1,183 @@
+// Package provenance signs gateway responses so downstream systems can prove
+// which deployment, model and provider produced a given output.
+//
+// A signed response carries two headers:
+//   - X-LLMux-Provenance: base64url-encoded JSON Claims, including the SHA-256
+//     of the response body
+//   - X-LLMux-Signature: a detached compact JWS (RFC 7515 Appendix F) over the
+//     claims
+//
+// Verifiers recompute the body hash and check the signature with the public
+// key published by the gateway.
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// Response headers carrying provenance data.
+const (
+	HeaderProvenance = "X-LLMux-Provenance"
+	HeaderSignature  = "X-LLMux-Signature"
+)
+
+var (
+	// ErrInvalidSignature is returned when a signature does not verify.
+	ErrInvalidSignature = errors.New("invalid provenance signature")
+	// ErrBodyMismatch is returned when the body does not match the signed hash.
+	ErrBodyMismatch = errors.New("response body does not match signed hash")
+)
+
+// Claims is the signed provenance metadata of a response.
+type Claims struct {
+	BodySHA256        string `json:"body_sha256"`
+	RequestID         string `json:"request_id,omitempty"`
+	Model             string `json:"model,omitempty"`
+	Provider          string `json:"provider,omitempty"`
+	Deployment        string `json:"deployment,omitempty"`
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+	IssuedAt          int64  `json:"iat"`
+}
+
+// Signer signs and verifies response provenance.
+type Signer struct {
+	signer    jose.Signer
+	algorithm jose.SignatureAlgorithm
+	public    jose.JSONWebKey
+}
+
+// NewSigner creates a signer from an Ed25519, ECDSA P-256 or RSA private key.
+func NewSigner(key crypto.Signer, keyID string) (*Signer, error) {
+	alg, err := algorithmFor(key)
+	if err != nil {
+		return nil, err
+	}
+	jwk := jose.JSONWebKey{Key: key, KeyID: keyID, Algorithm: string(alg), Use: "sig"}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: jwk}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create signer: %w", err)
+	}
+	return &Signer{signer: signer, algorithm: alg, public: jwk.Public()}, nil
+}
+
+// LoadSigner reads a PEM-encoded PKCS#8, PKCS#1 or SEC 1 private key.
+func LoadSigner(path, keyID string) (*Signer, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from operator config.
+	if err != nil {
+		return nil, fmt.Errorf("read signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("signing key is not PEM encoded")
+	}
+	key, err := parsePrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(key, keyID)
+}
+
+// Sign returns the provenance and signature header values for body.
+// BodySHA256 and IssuedAt are filled in by Sign.
+func (s *Signer) Sign(body []byte, claims Claims) (provenance, signature string, err error) {
+	claims.BodySHA256 = bodyHash(body)
+	if claims.IssuedAt == 0 {
+		claims.IssuedAt = time.Now().Unix()
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", "", err
+	}
+	jws, err := s.signer.Sign(payload)
+	if err != nil {
+		return "", "", fmt.Errorf("sign provenance: %w", err)
+	}
+	signature, err = jws.DetachedCompactSerialize()
+	if err != nil {
+		return "", "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload), signature, nil
+}
+
+// Verify checks the signature over the provenance claims and that the claims
+// match body. It returns the verified claims.
+func (s *Signer) Verify(body []byte, provenance, signature string) (*Claims, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(provenance)
+	if err != nil {
+		return nil, fmt.Errorf("decode provenance: %w", err)
+	}
+	jws, err := jose.ParseDetached(signature, payload, []jose.SignatureAlgorithm{s.algorithm})
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	if _, err := jws.Verify(s.public); err != nil {
+		return nil, ErrInvalidSignature
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("decode provenance: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.BodySHA256), []byte(bodyHash(body))) != 1 {
+		return &claims, ErrBodyMismatch
+	}
+	return &claims, nil
+}
+
+// PublicKeys returns the verification key as a JWK set.
+func (s *Signer) PublicKeys() jose.JSONWebKeySet {
+	return jose.JSONWebKeySet{Keys: []jose.JSONWebKey{s.public}}
+}
+
+func bodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func algorithmFor(key crypto.Signer) (jose.SignatureAlgorithm, error) {
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return jose.EdDSA, nil
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return "", errors.New("only P-256 ECDSA signing keys are supported")
+		}
+		return jose.ES256, nil
+	case *rsa.PrivateKey:
+		return jose.RS256, nil
+	default:
+		return "", fmt.Errorf("unsupported signing key type %T", key)
+	}
+}
+
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported signing key type %T", key)
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unsupported signing key format")
+}
@@ -0,0 +1,96 @@
+package provenance
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	signer, err := NewSigner(key, "k1")
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+
+	body := []byte(`{"id":"chatcmpl-1"}`)
+	prov, sig, err := signer.Sign(body, Claims{Model: "gpt-4o", Provider: "openai", Deployment: "openai-1", SystemFingerprint: "fp_1"})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	claims, err := signer.Verify(body, prov, sig)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.Deployment != "openai-1" || claims.Model != "gpt-4o" || claims.SystemFingerprint != "fp_1" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+	if claims.IssuedAt == 0 {
+		t.Fatalf("expected iat to be set")
+	}
+
+	if _, err := signer.Verify([]byte(`{"id":"chatcmpl-2"}`), prov, sig); !errors.Is(err, ErrBodyMismatch) {
+		t.Fatalf("tampered body: got %v, want ErrBodyMismatch", err)
+	}
+
+	otherProv, _, err := signer.Sign(body, Claims{Model: "other"})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if _, err := signer.Verify(body, otherProv, sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("swapped claims: got %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestLoadSigner_ECDSA(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+
+	signer, err := LoadSigner(path, "ec")
+	if err != nil {
+		t.Fatalf("LoadSigner: %v", err)
+	}
+	keys := signer.PublicKeys()
+	if len(keys.Keys) != 1 || keys.Keys[0].KeyID != "ec" || !keys.Keys[0].IsPublic() {
+		t.Fatalf("unexpected public keys: %+v", keys)
+	}
+
+	prov, sig, err := signer.Sign([]byte("body"), Claims{})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if _, err := signer.Verify([]byte("body"), prov, sig); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestLoadSigner_RejectsNonPEM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	if err := os.WriteFile(path, []byte("not a key"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	if _, err := LoadSigner(path, ""); err == nil {
+		t.Fatalf("expected error for non-PEM key")
+	}
+}
@@ -26,6 +26,7 @@ func ToPkgConfig(cfg ProviderConfig) pkgprovider.Config {
 		MaxConcurrent:       cfg.MaxConcurrent,
 		Timeout:             time.Duration(cfg.TimeoutSec) * time.Second,
 		Headers:             cfg.Headers,
+		APIVersion:          cfg.APIVersion,
 	}
 }
 
@@ -88,4 +88,5 @@ type ProviderConfig struct {
 	MaxConcurrent       int
 	TimeoutSec          int
 	Headers             map[string]string
+	APIVersion          string
 }
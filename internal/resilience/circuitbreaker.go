@@ -1,20 +1,17 @@
 // Package resilience provides high-availability patterns for the LLM gateway.
 // It includes circuit breaker, rate limiting, and concurrency control.
 //
-// IMPORTANT: Circuit Breaker Status
-// =================================
-// The CircuitBreaker implementation in this file is a REFERENCE IMPLEMENTATION
-// that is NOT currently integrated into the production router.
+// Circuit Breaker
+// ===============
+// The client keeps one CircuitBreaker per provider around upstream calls.
+// It counts connection-level failures only (dial errors, resets, timeouts),
+// so an unreachable provider fails fast locally instead of burning retries.
 //
-// The router (routers/base.go) uses a LiteLLM-style failure-rate based cooldown
-// mechanism instead, which is more suitable for LLM APIs with bursty error patterns:
+// HTTP error responses are left to the router (routers/base.go), which uses a
+// LiteLLM-style failure-rate based cooldown per deployment:
 //   - Immediate cooldown on 429 (Rate Limit)
 //   - Immediate cooldown on 401/404 (Non-retryable)
 //   - Failure rate threshold (default 50%, min 5 requests)
-//
-// This traditional circuit breaker implementation may be integrated in the future
-// if more sophisticated patterns (half-open state, gradual recovery) are needed.
-// See: routers/base.go ReportFailure() for the currently active logic.
 package resilience
 
 import (
@@ -175,6 +172,17 @@ func (cb *CircuitBreaker) RecordFailure() {
 	}
 }
 
+// Cancel releases a half-open probe slot taken by Allow for a request whose
+// outcome says nothing about the service, such as one canceled by the caller.
+func (cb *CircuitBreaker) Cancel() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateHalfOpen && cb.halfOpenCount > 0 {
+		cb.halfOpenCount--
+	}
+}
+
 // State returns the current circuit state.
 func (cb *CircuitBreaker) State() CircuitState {
 	cb.mu.RLock()
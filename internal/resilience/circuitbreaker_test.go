@@ -208,6 +208,35 @@ func TestCircuitBreaker_HalfOpenLimitsRequests(t *testing.T) {
 	}
 }
 
+func TestCircuitBreaker_CancelFreesHalfOpenSlot(t *testing.T) {
+	cfg := CircuitBreakerConfig{
+		FailureThreshold:    1,
+		SuccessThreshold:    1,
+		Timeout:             50 * time.Millisecond,
+		HalfOpenMaxRequests: 1,
+	}
+	cb := NewCircuitBreaker("test", cfg)
+
+	cb.Allow()
+	cb.RecordFailure()
+	time.Sleep(60 * time.Millisecond)
+
+	if !cb.Allow() {
+		t.Fatal("should allow probe in half-open")
+	}
+	if cb.Allow() {
+		t.Fatal("should block second probe while the first is in flight")
+	}
+	cb.Cancel()
+	if !cb.Allow() {
+		t.Fatal("canceled probe should free its slot")
+	}
+	cb.RecordSuccess()
+	if cb.State() != StateClosed {
+		t.Errorf("expected closed, got %v", cb.State())
+	}
+}
+
 func TestCircuitBreaker_Reset(t *testing.T) {
 	cfg := CircuitBreakerConfig{
 		FailureThreshold:    2,
@@ -3,11 +3,10 @@
 // Usage Status:
 //   - RateLimiter, RedisLimiter: ACTIVE - Used for distributed rate limiting
 //   - Semaphore: ACTIVE - Used for concurrency control
-//   - CircuitBreaker: NOT INTEGRATED - See circuitbreaker.go for details
-//   - Manager.GetCircuitBreaker: NOT USED IN PRODUCTION
+//   - CircuitBreaker: ACTIVE - Per-provider breaker for connection failures
 //
-// The router uses LiteLLM-style failure-rate cooldown instead of traditional
-// circuit breaker pattern. See routers/base.go for the active implementation.
+// HTTP error responses are handled by the router's LiteLLM-style failure-rate
+// cooldown. See routers/base.go for that implementation.
 package resilience
 
 import (
@@ -17,8 +16,6 @@ import (
 )
 
 // Manager coordinates resilience components for multiple providers/deployments.
-// NOTE: The CircuitBreaker functionality in this Manager is NOT used in production.
-// Only RateLimiter and Semaphore features are actively used.
 type Manager struct {
 	mu              sync.RWMutex
 	circuitBreakers map[string]*CircuitBreaker
@@ -206,6 +203,7 @@ func (m *Manager) Stats(key string) ResilienceStats {
 	if s, ok := m.semaphores[key]; ok {
 		stats.ConcurrentCurrent = s.Current()
 		stats.ConcurrentCapacity = s.Capacity()
+		stats.ConcurrentWaiting = s.Waiting()
 	}
 
 	return stats
@@ -218,6 +216,7 @@ type ResilienceStats struct {
 	RateLimitTokens    float64
 	ConcurrentCurrent  int
 	ConcurrentCapacity int
+	ConcurrentWaiting  int
 }
 
 // ErrRateLimited is returned when rate limit is exceeded.
@@ -10,12 +10,20 @@ import (
 var ErrSemaphoreFull = errors.New("semaphore is full")
 
 // Semaphore implements a counting semaphore for concurrency control.
-// It limits the number of concurrent operations.
+// It limits the number of concurrent operations. Waiters are woken in
+// priority order (FIFO among equal priorities).
 type Semaphore struct {
 	mu       sync.Mutex
 	capacity int
 	current  int
-	waiters  []chan struct{}
+	waiters  []*semaphoreWaiter
+}
+
+// semaphoreWaiter is a queued Acquire call.
+type semaphoreWaiter struct {
+	ready    chan struct{}
+	priority int
+	shed     bool
 }
 
 // NewSemaphore creates a new semaphore with the given capacity.
@@ -25,7 +33,7 @@ func NewSemaphore(capacity int) *Semaphore {
 	}
 	return &Semaphore{
 		capacity: capacity,
-		waiters:  make([]chan struct{}, 0),
+		waiters:  make([]*semaphoreWaiter, 0),
 	}
 }
 
@@ -44,35 +52,71 @@ func (s *Semaphore) TryAcquire() bool {
 
 // Acquire acquires a permit, blocking until one is available or context is canceled.
 func (s *Semaphore) Acquire(ctx context.Context) error {
-	// Try non-blocking first
-	if s.TryAcquire() {
-		return nil
-	}
+	return s.AcquireWithPriority(ctx, 0, 0)
+}
 
-	// Create a waiter channel
+// AcquireWithPriority acquires a permit, queueing behind higher-priority waiters.
+// maxQueue bounds the number of waiters (0 = unbounded). When the queue is full,
+// the caller is rejected with ErrSemaphoreFull unless it outranks the lowest
+// priority waiter, in which case that waiter is shed instead.
+func (s *Semaphore) AcquireWithPriority(ctx context.Context, priority, maxQueue int) error {
 	s.mu.Lock()
-	waiter := make(chan struct{})
+	if s.current < s.capacity {
+		s.current++
+		s.mu.Unlock()
+		return nil
+	}
+	if maxQueue > 0 && len(s.waiters) >= maxQueue {
+		victim := s.lowestWaiterLocked()
+		if victim < 0 || s.waiters[victim].priority >= priority {
+			s.mu.Unlock()
+			return ErrSemaphoreFull
+		}
+		shed := s.waiters[victim]
+		s.waiters = append(s.waiters[:victim], s.waiters[victim+1:]...)
+		shed.shed = true
+		close(shed.ready)
+	}
+	waiter := &semaphoreWaiter{ready: make(chan struct{}), priority: priority}
 	s.waiters = append(s.waiters, waiter)
 	s.mu.Unlock()
 
 	// Wait for permit or context cancellation
 	select {
-	case <-waiter:
+	case <-waiter.ready:
+		if waiter.shed {
+			return ErrSemaphoreFull
+		}
 		return nil
 	case <-ctx.Done():
-		// Remove ourselves from waiters
 		s.mu.Lock()
 		for i, w := range s.waiters {
 			if w == waiter {
 				s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
-				break
+				s.mu.Unlock()
+				return ctx.Err()
 			}
 		}
 		s.mu.Unlock()
+		// The permit was handed over (or we were shed) concurrently with cancellation.
+		if !waiter.shed {
+			s.Release()
+		}
 		return ctx.Err()
 	}
 }
 
+// lowestWaiterLocked returns the index of the newest waiter with the lowest priority.
+func (s *Semaphore) lowestWaiterLocked() int {
+	idx := -1
+	for i, w := range s.waiters {
+		if idx < 0 || w.priority <= s.waiters[idx].priority {
+			idx = i
+		}
+	}
+	return idx
+}
+
 // Release releases a permit, potentially waking a waiter.
 func (s *Semaphore) Release() {
 	s.mu.Lock()
@@ -82,11 +126,17 @@ func (s *Semaphore) Release() {
 		return // Nothing to release
 	}
 
-	// If there are waiters, wake one up
+	// If there are waiters, wake the oldest one with the highest priority
 	if len(s.waiters) > 0 {
-		waiter := s.waiters[0]
-		s.waiters = s.waiters[1:]
-		close(waiter) // Signal the waiter
+		next := 0
+		for i, w := range s.waiters {
+			if w.priority > s.waiters[next].priority {
+				next = i
+			}
+		}
+		waiter := s.waiters[next]
+		s.waiters = append(s.waiters[:next], s.waiters[next+1:]...)
+		close(waiter.ready) // Signal the waiter
 		// Don't decrement current since we're transferring the permit
 		return
 	}
@@ -94,6 +144,13 @@ func (s *Semaphore) Release() {
 	s.current--
 }
 
+// Waiting returns the number of queued Acquire calls.
+func (s *Semaphore) Waiting() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.waiters)
+}
+
 // Current returns the current number of acquired permits.
 func (s *Semaphore) Current() int {
 	s.mu.Lock()
@@ -230,3 +230,71 @@ func TestSemaphore_WaiterWakeup(t *testing.T) {
 		t.Errorf("expected 3 waiters to complete, got %d", count)
 	}
 }
+
+func waitForWaiters(t *testing.T, s *Semaphore, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for s.Waiting() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d waiters, got %d", n, s.Waiting())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSemaphore_PriorityOrder(t *testing.T) {
+	s := NewSemaphore(1)
+	s.TryAcquire()
+
+	order := make(chan int, 2)
+	for _, prio := range []int{0, 2} {
+		go func(p int) {
+			if err := s.AcquireWithPriority(context.Background(), p, 0); err != nil {
+				return
+			}
+			order <- p
+			s.Release()
+		}(prio)
+		waitForWaiters(t, s, 1+prio/2)
+	}
+
+	s.Release()
+	if first := <-order; first != 2 {
+		t.Errorf("expected high priority waiter first, got %d", first)
+	}
+	<-order
+}
+
+func TestSemaphore_QueueFullShedsLowerPriority(t *testing.T) {
+	s := NewSemaphore(1)
+	s.TryAcquire()
+
+	lowErr := make(chan error, 1)
+	go func() {
+		lowErr <- s.AcquireWithPriority(context.Background(), 0, 1)
+	}()
+	waitForWaiters(t, s, 1)
+
+	// Same priority cannot displace the queued waiter.
+	if err := s.AcquireWithPriority(context.Background(), 0, 1); err != ErrSemaphoreFull {
+		t.Fatalf("expected ErrSemaphoreFull, got %v", err)
+	}
+
+	highErr := make(chan error, 1)
+	go func() {
+		highErr <- s.AcquireWithPriority(context.Background(), 2, 1)
+	}()
+
+	if err := <-lowErr; err != ErrSemaphoreFull {
+		t.Fatalf("expected low priority waiter to be shed, got %v", err)
+	}
+	waitForWaiters(t, s, 1)
+
+	s.Release()
+	if err := <-highErr; err != nil {
+		t.Fatalf("expected high priority waiter to acquire, got %v", err)
+	}
+	if s.Current() != 1 {
+		t.Errorf("expected 1 permit held, got %d", s.Current())
+	}
+}
@@ -0,0 +1,187 @@
+// Package awssm implements a secret provider that reads from AWS Secrets
+// Manager. It calls the Secrets Manager JSON API directly with SigV4-signed
+// requests.
+package awssm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/goccy/go-json"
+)
+
+const targetPrefix = "secretsmanager."
+
+// Provider implements the secret.Provider interface for AWS Secrets Manager.
+type Provider struct {
+	cfg        Config
+	awsCfg     aws.Config
+	httpClient *http.Client
+	signer     *v4.Signer
+	endpoint   string
+}
+
+// Config holds configuration for the AWS Secrets Manager provider.
+type Config struct {
+	Region   string        // AWS region (default: from AWS config)
+	Endpoint string        // Custom endpoint (e.g., LocalStack)
+	Timeout  time.Duration // Per-call timeout (default: 5 seconds)
+}
+
+// New creates a provider using credentials from the default AWS config
+// chain.
+func New(cfg Config) (*Provider, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if cfg.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.Region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	return NewWithAWSConfig(cfg, awsCfg, nil)
+}
+
+// NewWithAWSConfig creates a provider from an explicit AWS config.
+// If httpClient is nil, a client with the configured timeout is used.
+func NewWithAWSConfig(cfg Config, awsCfg aws.Config, httpClient *http.Client) (*Provider, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.Region == "" {
+		cfg.Region = awsCfg.Region
+	}
+	if cfg.Region == "" {
+		return nil, errors.New("awssm: region is required")
+	}
+	if awsCfg.Credentials == nil {
+		return nil, errors.New("awssm: AWS credentials are not configured")
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", cfg.Region)
+	}
+
+	return &Provider{
+		cfg:        cfg,
+		awsCfg:     awsCfg,
+		httpClient: httpClient,
+		signer:     v4.NewSigner(),
+		endpoint:   endpoint,
+	}, nil
+}
+
+// apiError is an error response from the Secrets Manager API.
+type apiError struct {
+	StatusCode int
+	Type       string `json:"__type"`
+	Message    string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("awssm: %d %s: %s", e.StatusCode, e.Type, e.Message)
+}
+
+// Get retrieves a secret from AWS Secrets Manager.
+// Path format: "secret-id#key", where secret-id is the secret name or ARN.
+// Without #key the whole secret string is returned; with it the secret
+// string is read as a JSON object and the key's value is returned.
+func (p *Provider) Get(ctx context.Context, path string) (string, error) {
+	secretID := path
+	key := ""
+	if idx := strings.LastIndex(path, "#"); idx != -1 {
+		secretID = path[:idx]
+		key = path[idx+1:]
+	}
+	if secretID == "" {
+		return "", errors.New("awssm: secret id is required")
+	}
+
+	var out struct {
+		SecretString *string `json:"SecretString"`
+		SecretBinary []byte  `json:"SecretBinary"`
+	}
+	if err := p.call(ctx, "GetSecretValue", map[string]string{"SecretId": secretID}, &out); err != nil {
+		return "", fmt.Errorf("read aws secret %q: %w", secretID, err)
+	}
+
+	value := string(out.SecretBinary)
+	if out.SecretString != nil {
+		value = *out.SecretString
+	}
+	if key == "" {
+		return value, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("secret %q is not a JSON object: %w", secretID, err)
+	}
+	val, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in secret %q", key, secretID)
+	}
+	return fmt.Sprintf("%v", val), nil
+}
+
+// Close is a no-op for the AWS Secrets Manager provider.
+func (p *Provider) Close() error {
+	return nil
+}
+
+func (p *Provider) call(ctx context.Context, op string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", targetPrefix+op)
+
+	creds, err := p.awsCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieve credentials: %w", err)
+	}
+	payloadHash := sha256.Sum256(body)
+	if err := p.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), "secretsmanager", p.cfg.Region, time.Now()); err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &apiError{StatusCode: resp.StatusCode}
+		_ = json.Unmarshal(data, apiErr)
+		return apiErr
+	}
+	return json.Unmarshal(data, out)
+}
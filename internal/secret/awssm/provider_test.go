@@ -0,0 +1,78 @@
+package awssm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T, secrets map[string]string) *Provider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Authorization"), "AWS4-HMAC-SHA256")
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-1/secretsmanager/")
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		body, _ := io.ReadAll(r.Body)
+		var in struct{ SecretId string }
+		require.NoError(t, json.Unmarshal(body, &in))
+
+		val, ok := secrets[in.SecretId]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"Name": in.SecretId, "SecretString": val})
+	}))
+	t.Cleanup(server.Close)
+
+	awsCfg := aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	}
+	p, err := NewWithAWSConfig(Config{Endpoint: server.URL}, awsCfg, server.Client())
+	require.NoError(t, err)
+	return p
+}
+
+func TestProvider_Get(t *testing.T) {
+	p := newTestProvider(t, map[string]string{
+		"llm/openai": "sk-plain",
+		"arn:aws:secretsmanager:us-east-1:123456789012:secret:llm/keys-AbCdEf": `{"anthropic":"sk-ant","port":5432}`,
+	})
+	ctx := context.Background()
+
+	val, err := p.Get(ctx, "llm/openai")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-plain", val)
+
+	val, err = p.Get(ctx, "arn:aws:secretsmanager:us-east-1:123456789012:secret:llm/keys-AbCdEf#anthropic")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-ant", val)
+
+	val, err = p.Get(ctx, "arn:aws:secretsmanager:us-east-1:123456789012:secret:llm/keys-AbCdEf#port")
+	require.NoError(t, err)
+	assert.Equal(t, "5432", val)
+
+	_, err = p.Get(ctx, "llm/openai#key")
+	assert.ErrorContains(t, err, "not a JSON object")
+	_, err = p.Get(ctx, "arn:aws:secretsmanager:us-east-1:123456789012:secret:llm/keys-AbCdEf#missing")
+	assert.ErrorContains(t, err, `key "missing" not found`)
+	_, err = p.Get(ctx, "llm/missing")
+	assert.ErrorContains(t, err, "ResourceNotFoundException")
+}
+
+func TestNewWithAWSConfig_RequiresRegionAndCredentials(t *testing.T) {
+	_, err := NewWithAWSConfig(Config{}, aws.Config{Credentials: credentials.NewStaticCredentialsProvider("a", "b", "")}, nil)
+	assert.Error(t, err)
+	_, err = NewWithAWSConfig(Config{Region: "us-east-1"}, aws.Config{}, nil)
+	assert.Error(t, err)
+}
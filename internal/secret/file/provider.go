@@ -0,0 +1,34 @@
+// Package file implements a secret provider that reads files, such as keys
+// of a Kubernetes Secret mounted as a volume.
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Provider implements the secret.Provider interface for files.
+type Provider struct{}
+
+// New creates a new File provider.
+func New() *Provider {
+	return &Provider{}
+}
+
+// Get returns the content of the file at path with surrounding whitespace
+// trimmed. Path format: "/etc/llmux/secrets/openai-key" (file:///etc/...).
+func (p *Provider) Get(ctx context.Context, path string) (string, error) {
+	// #nosec G304 -- secret paths are operator-configured.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read secret file %q: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Close is a no-op for the File provider.
+func (p *Provider) Close() error {
+	return nil
+}
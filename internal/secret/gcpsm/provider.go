@@ -0,0 +1,176 @@
+// Package gcpsm implements a secret provider that reads from Google Cloud
+// Secret Manager through its REST API.
+package gcpsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const defaultEndpoint = "https://secretmanager.googleapis.com"
+
+// Provider implements the secret.Provider interface for GCP Secret Manager.
+type Provider struct {
+	cfg        Config
+	tokenSrc   oauth2.TokenSource
+	httpClient *http.Client
+}
+
+// Config holds configuration for the GCP Secret Manager provider.
+type Config struct {
+	Project  string        // Project for secret paths that don't name one
+	Endpoint string        // Custom endpoint (default: secretmanager.googleapis.com)
+	Timeout  time.Duration // Per-call timeout (default: 5 seconds)
+}
+
+// New creates a provider using Application Default Credentials. When
+// Project is empty, the credentials' project is used.
+func New(ctx context.Context, cfg Config) (*Provider, error) {
+	creds, err := google.FindDefaultCredentials(ctx, "https://www.googleapis.com/auth/cloud-platform")
+	if err != nil {
+		return nil, fmt.Errorf("find default credentials: %w", err)
+	}
+	if cfg.Project == "" {
+		cfg.Project = creds.ProjectID
+	}
+	return NewWithTokenSource(cfg, creds.TokenSource, nil), nil
+}
+
+// NewWithTokenSource creates a provider from an explicit token source.
+// If httpClient is nil, a client with the configured timeout is used.
+func NewWithTokenSource(cfg Config, tokenSrc oauth2.TokenSource, httpClient *http.Client) *Provider {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = defaultEndpoint
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: cfg.Timeout}
+	}
+	return &Provider{cfg: cfg, tokenSrc: tokenSrc, httpClient: httpClient}
+}
+
+// Get retrieves a secret version from GCP Secret Manager.
+// Path format: "secret[/version]#key" in the configured project, or a full
+// "projects/p/secrets/s[/versions/v]#key" resource name. The version
+// defaults to "latest". With #key the payload is read as a JSON object and
+// the key's value is returned.
+func (p *Provider) Get(ctx context.Context, path string) (string, error) {
+	ref := path
+	key := ""
+	if idx := strings.LastIndex(path, "#"); idx != -1 {
+		ref = path[:idx]
+		key = path[idx+1:]
+	}
+	name, err := p.versionName(ref)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := p.access(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("read gcp secret %q: %w", name, err)
+	}
+	if key == "" {
+		return string(payload), nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return "", fmt.Errorf("secret %q is not a JSON object: %w", name, err)
+	}
+	val, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in secret %q", key, name)
+	}
+	return fmt.Sprintf("%v", val), nil
+}
+
+// Close is a no-op for the GCP Secret Manager provider.
+func (p *Provider) Close() error {
+	return nil
+}
+
+// versionName expands ref to a secret version resource name.
+func (p *Provider) versionName(ref string) (string, error) {
+	if strings.HasPrefix(ref, "projects/") {
+		parts := strings.Split(ref, "/")
+		switch {
+		case len(parts) == 4 && parts[2] == "secrets" && parts[3] != "":
+			return ref + "/versions/latest", nil
+		case len(parts) == 6 && parts[2] == "secrets" && parts[4] == "versions" && parts[5] != "":
+			return ref, nil
+		default:
+			return "", fmt.Errorf("gcpsm: invalid secret name %q", ref)
+		}
+	}
+
+	secretName, version, _ := strings.Cut(ref, "/")
+	if secretName == "" || strings.Contains(version, "/") {
+		return "", fmt.Errorf("gcpsm: invalid secret name %q", ref)
+	}
+	if version == "" {
+		version = "latest"
+	}
+	if p.cfg.Project == "" {
+		return "", errors.New("gcpsm: project is required for secret names without one")
+	}
+	return fmt.Sprintf("projects/%s/secrets/%s/versions/%s", p.cfg.Project, secretName, version), nil
+}
+
+func (p *Provider) access(ctx context.Context, name string) ([]byte, error) {
+	token, err := p.tokenSrc.Token()
+	if err != nil {
+		return nil, fmt.Errorf("get token: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.Endpoint+"/v1/"+name+":access", http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	token.SetAuthHeader(req)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		return nil, fmt.Errorf("gcpsm: %d %s: %s", resp.StatusCode, apiErr.Error.Status, apiErr.Error.Message)
+	}
+
+	var out struct {
+		Payload struct {
+			Data []byte `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out.Payload.Data, nil
+}
@@ -0,0 +1,70 @@
+package gcpsm
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func newTestProvider(t *testing.T, project string, secrets map[string]string) *Provider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/"), ":access")
+		val, ok := secrets[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"status":"NOT_FOUND","message":"Secret not found"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"` + name + `","payload":{"data":"` + base64.StdEncoding.EncodeToString([]byte(val)) + `"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	tokenSrc := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token", TokenType: "Bearer"})
+	return NewWithTokenSource(Config{Project: project, Endpoint: server.URL}, tokenSrc, server.Client())
+}
+
+func TestProvider_Get(t *testing.T) {
+	p := newTestProvider(t, "my-project", map[string]string{
+		"projects/my-project/secrets/openai/versions/latest": "sk-plain",
+		"projects/my-project/secrets/openai/versions/3":      "sk-old",
+		"projects/other/secrets/keys/versions/latest":        `{"anthropic":"sk-ant"}`,
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"openai", "sk-plain"},
+		{"openai/3", "sk-old"},
+		{"projects/my-project/secrets/openai/versions/3", "sk-old"},
+		{"projects/other/secrets/keys#anthropic", "sk-ant"},
+	}
+	for _, tt := range tests {
+		got, err := p.Get(ctx, tt.path)
+		require.NoError(t, err, tt.path)
+		assert.Equal(t, tt.want, got, tt.path)
+	}
+
+	_, err := p.Get(ctx, "missing")
+	assert.ErrorContains(t, err, "NOT_FOUND")
+	_, err = p.Get(ctx, "projects/other/secrets/keys#missing")
+	assert.ErrorContains(t, err, `key "missing" not found`)
+	for _, path := range []string{"", "a/b/c", "projects/p", "projects/p/secrets/s/versions"} {
+		_, err = p.Get(ctx, path)
+		assert.ErrorContains(t, err, "invalid secret name", path)
+	}
+
+	noProject := NewWithTokenSource(Config{}, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "x"}), nil)
+	_, err = noProject.Get(ctx, "openai")
+	assert.ErrorContains(t, err, "project is required")
+}
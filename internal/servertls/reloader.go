@@ -0,0 +1,103 @@
+// Package servertls serves TLS certificates that can be rotated on disk
+// without restarting the gateway.
+package servertls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// Config describes the files and client verification of a TLS listener.
+type Config struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string // PEM bundle trusted for client certificates; empty disables mTLS
+	ClientAuth   tls.ClientAuthType
+	MinVersion   uint16
+}
+
+// Reloader holds the current certificate and client CA pool. Reload swaps
+// them atomically; handshakes after a reload use the new files while
+// established connections keep theirs.
+type Reloader struct {
+	cfg   Config
+	state atomic.Pointer[state]
+}
+
+type state struct {
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+// New loads the files of cfg. It fails when they cannot be read, so a
+// misconfigured listener is caught at startup.
+func New(cfg Config) (*Reloader, error) {
+	r := &Reloader{cfg: cfg}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Files returns the files the reloader reads.
+func (r *Reloader) Files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	return files
+}
+
+// Reload re-reads the certificate, key and client CA bundle. On error the
+// previous ones stay in use.
+func (r *Reloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load TLS certificate: %w", err)
+	}
+	next := &state{cert: &cert}
+	if r.cfg.ClientCAFile != "" {
+		// #nosec G304 -- path is operator-configured.
+		data, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("read client CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("client CA bundle %s contains no PEM certificates", r.cfg.ClientCAFile)
+		}
+		next.clientCAs = pool
+	}
+	r.state.Store(next)
+	return nil
+}
+
+// Certificate returns the certificate currently served.
+func (r *Reloader) Certificate() *tls.Certificate {
+	return r.state.Load().cert
+}
+
+// TLSConfig returns a server config that reads the current certificate and
+// client CA pool on every handshake.
+func (r *Reloader) TLSConfig() *tls.Config {
+	minVersion := r.cfg.MinVersion
+	if minVersion == 0 {
+		minVersion = tls.VersionTLS12
+	}
+	return &tls.Config{
+		MinVersion: minVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			s := r.state.Load()
+			return &tls.Config{
+				MinVersion:   minVersion,
+				Certificates: []tls.Certificate{*s.cert},
+				ClientAuth:   r.cfg.ClientAuth,
+				ClientCAs:    s.clientCAs,
+				NextProtos:   []string{"h2", "http/1.1"},
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,178 @@
+package servertls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCert(t *testing.T, cn string, parent *testCert, isCA bool) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCert{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (c *testCert) keyPEM(t *testing.T) []byte {
+	t.Helper()
+	der, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func (c *testCert) tlsCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	cert, err := tls.X509KeyPair(c.pem, c.keyPEM(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// handshake dials a TLS listener served with cfg and returns the server
+// certificate's common name.
+func handshake(t *testing.T, cfg *tls.Config, client *tls.Config) (string, error) {
+	t.Helper()
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_ = conn.(*tls.Conn).Handshake()
+		_, _ = conn.Write([]byte("ok"))
+		_ = conn.Close()
+	}()
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), client)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	// TLS 1.3 reports client certificate rejection on the first read.
+	if _, err := conn.Read(make([]byte, 2)); err != nil {
+		return "", err
+	}
+	return conn.ConnectionState().PeerCertificates[0].Subject.CommonName, nil
+}
+
+func TestReloader_MutualTLSAndRotation(t *testing.T) {
+	ca := newTestCert(t, "test-ca", nil, true)
+	server := newTestCert(t, "server-1", ca, false)
+	client := newTestCert(t, "client", ca, false)
+
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	writeFile(t, certFile, server.pem)
+	writeFile(t, keyFile, server.keyPEM(t))
+	writeFile(t, caFile, ca.pem)
+
+	r, err := New(Config{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuth: tls.RequireAndVerifyClientCert})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := r.Files(); len(got) != 3 {
+		t.Fatalf("Files() = %v", got)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientCfg := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{client.tlsCertificate(t)}}
+
+	cn, err := handshake(t, r.TLSConfig(), clientCfg)
+	if err != nil || cn != "server-1" {
+		t.Fatalf("handshake = %q, %v", cn, err)
+	}
+	if _, err := handshake(t, r.TLSConfig(), &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}); err == nil {
+		t.Fatal("expected handshake without client certificate to fail")
+	}
+
+	// A rotated certificate is served after Reload without a new listener config.
+	rotated := newTestCert(t, "server-2", ca, false)
+	writeFile(t, certFile, rotated.pem)
+	writeFile(t, keyFile, rotated.keyPEM(t))
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	cn, err = handshake(t, r.TLSConfig(), clientCfg)
+	if err != nil || cn != "server-2" {
+		t.Fatalf("handshake after rotation = %q, %v", cn, err)
+	}
+
+	// A half-written rotation keeps the previous certificate.
+	writeFile(t, keyFile, server.keyPEM(t))
+	if err := r.Reload(); err == nil {
+		t.Fatal("expected mismatched key to fail")
+	}
+	if got := r.Certificate().Leaf.Subject.CommonName; got != "server-2" {
+		t.Fatalf("certificate after failed reload = %q", got)
+	}
+}
+
+func TestNew_InvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := New(Config{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: filepath.Join(dir, "missing.key")}); err == nil {
+		t.Fatal("expected missing certificate to fail")
+	}
+
+	server := newTestCert(t, "server", nil, false)
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	writeFile(t, certFile, server.pem)
+	writeFile(t, keyFile, server.keyPEM(t))
+	writeFile(t, caFile, []byte("not a certificate"))
+	if _, err := New(Config{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile}); err == nil {
+		t.Fatal("expected empty CA bundle to fail")
+	}
+}
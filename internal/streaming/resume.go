@@ -0,0 +1,247 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Errors returned by EventBuffer.
+var (
+	ErrStreamNotFound   = errors.New("stream not found or expired")
+	ErrStreamBufferFull = errors.New("stream buffer is full")
+)
+
+// Event is one buffered SSE event. Seq starts at 1 within a stream.
+type Event struct {
+	Seq  int64
+	Data []byte
+}
+
+// Replay is the result of reading a buffered stream.
+type Replay struct {
+	Owner  string
+	Events []Event
+	// Done reports that the stream has finished; Events then holds every
+	// remaining event.
+	Done bool
+}
+
+// EventBuffer keeps the recent events of streams so clients that reconnect
+// with Last-Event-ID can resume them.
+type EventBuffer interface {
+	// Start begins buffering streamID for owner. Buffered streams expire ttl
+	// after their last event.
+	Start(ctx context.Context, streamID, owner string, ttl time.Duration) error
+	// Append buffers data and returns its sequence number.
+	Append(ctx context.Context, streamID string, data []byte) (int64, error)
+	// Finish marks streamID as complete.
+	Finish(ctx context.Context, streamID string) error
+	// Read returns the events of streamID after sequence number after.
+	Read(ctx context.Context, streamID string, after int64) (*Replay, error)
+}
+
+type memoryStream struct {
+	owner     string
+	events    [][]byte
+	done      bool
+	ttl       time.Duration
+	expiresAt time.Time
+}
+
+// MemoryEventBuffer keeps stream events in memory.
+type MemoryEventBuffer struct {
+	maxEvents int
+
+	mu        sync.Mutex
+	streams   map[string]*memoryStream
+	nextSweep time.Time
+}
+
+// NewMemoryEventBuffer creates an in-memory event buffer holding at most
+// maxEvents events per stream (0 = unlimited).
+func NewMemoryEventBuffer(maxEvents int) *MemoryEventBuffer {
+	return &MemoryEventBuffer{maxEvents: maxEvents, streams: make(map[string]*memoryStream)}
+}
+
+// Start implements EventBuffer.
+func (b *MemoryEventBuffer) Start(_ context.Context, streamID, owner string, ttl time.Duration) error {
+	now := time.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sweepLocked(now)
+	b.streams[streamID] = &memoryStream{owner: owner, ttl: ttl, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Append implements EventBuffer.
+func (b *MemoryEventBuffer) Append(_ context.Context, streamID string, data []byte) (int64, error) {
+	now := time.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.streams[streamID]
+	if !ok || !s.expiresAt.After(now) {
+		return 0, ErrStreamNotFound
+	}
+	if b.maxEvents > 0 && len(s.events) >= b.maxEvents {
+		return 0, ErrStreamBufferFull
+	}
+	s.events = append(s.events, append([]byte(nil), data...))
+	s.expiresAt = now.Add(s.ttl)
+	return int64(len(s.events)), nil
+}
+
+// Finish implements EventBuffer.
+func (b *MemoryEventBuffer) Finish(_ context.Context, streamID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.streams[streamID]
+	if !ok {
+		return ErrStreamNotFound
+	}
+	s.done = true
+	return nil
+}
+
+// Read implements EventBuffer.
+func (b *MemoryEventBuffer) Read(_ context.Context, streamID string, after int64) (*Replay, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.streams[streamID]
+	if !ok || !s.expiresAt.After(time.Now()) {
+		return nil, ErrStreamNotFound
+	}
+	replay := &Replay{Owner: s.owner, Done: s.done}
+	for i := max(after, 0); i < int64(len(s.events)); i++ {
+		replay.Events = append(replay.Events, Event{Seq: i + 1, Data: s.events[i]})
+	}
+	return replay, nil
+}
+
+// sweepLocked drops expired streams at most once a minute.
+func (b *MemoryEventBuffer) sweepLocked(now time.Time) {
+	if now.Before(b.nextSweep) {
+		return
+	}
+	b.nextSweep = now.Add(time.Minute)
+	for id, s := range b.streams {
+		if !s.expiresAt.After(now) {
+			delete(b.streams, id)
+		}
+	}
+}
+
+// RedisEventBuffer shares stream events across instances, so a client can
+// resume on any of them. Each stream is a list of events and a hash holding
+// its owner and completion.
+type RedisEventBuffer struct {
+	client    redis.UniversalClient
+	prefix    string
+	maxEvents int
+}
+
+// NewRedisEventBuffer creates a Redis-backed event buffer holding at most
+// maxEvents events per stream (0 = unlimited).
+func NewRedisEventBuffer(client redis.UniversalClient, prefix string, maxEvents int) *RedisEventBuffer {
+	return &RedisEventBuffer{client: client, prefix: prefix, maxEvents: maxEvents}
+}
+
+func (b *RedisEventBuffer) keys(streamID string) (meta, events string) {
+	// The hash tag keeps both keys in one cluster slot.
+	base := b.prefix + "{" + streamID + "}"
+	return base + ":meta", base + ":events"
+}
+
+// Start implements EventBuffer.
+func (b *RedisEventBuffer) Start(ctx context.Context, streamID, owner string, ttl time.Duration) error {
+	meta, events := b.keys(streamID)
+	_, err := b.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.Del(ctx, events)
+		p.HSet(ctx, meta, "owner", owner, "done", "0", "ttl", strconv.FormatInt(int64(ttl), 10))
+		p.PExpire(ctx, meta, ttl)
+		return nil
+	})
+	return err
+}
+
+// Append implements EventBuffer.
+func (b *RedisEventBuffer) Append(ctx context.Context, streamID string, data []byte) (int64, error) {
+	meta, events := b.keys(streamID)
+	raw, err := b.client.HGet(ctx, meta, "ttl").Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, ErrStreamNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	ttl, _ := strconv.ParseInt(raw, 10, 64)
+
+	var push *redis.IntCmd
+	_, err = b.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		push = p.RPush(ctx, events, data)
+		p.PExpire(ctx, events, time.Duration(ttl))
+		p.PExpire(ctx, meta, time.Duration(ttl))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	seq := push.Val()
+	if b.maxEvents > 0 && seq > int64(b.maxEvents) {
+		return 0, ErrStreamBufferFull
+	}
+	return seq, nil
+}
+
+// finishScript marks a stream done only while it has not expired.
+var finishScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("HSET", KEYS[1], "done", "1") + 1
+end
+return 0
+`)
+
+// Finish implements EventBuffer.
+func (b *RedisEventBuffer) Finish(ctx context.Context, streamID string) error {
+	meta, _ := b.keys(streamID)
+	updated, err := finishScript.Run(ctx, b.client, []string{meta}).Int()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrStreamNotFound
+	}
+	return nil
+}
+
+// Read implements EventBuffer.
+func (b *RedisEventBuffer) Read(ctx context.Context, streamID string, after int64) (*Replay, error) {
+	meta, events := b.keys(streamID)
+	// Read completion before events, so a finished stream is never missing
+	// its last events.
+	fields, err := b.client.HGetAll(ctx, meta).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, ErrStreamNotFound
+	}
+	after = max(after, 0)
+	stop := int64(-1)
+	if b.maxEvents > 0 {
+		stop = int64(b.maxEvents) - 1
+	}
+	values, err := b.client.LRange(ctx, events, after, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+	replay := &Replay{Owner: fields["owner"], Done: fields["done"] == "1"}
+	for i, v := range values {
+		replay.Events = append(replay.Events, Event{Seq: after + int64(i) + 1, Data: []byte(v)})
+	}
+	return replay, nil
+}
@@ -0,0 +1,73 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestEventBuffers(t *testing.T) {
+	buffers := map[string]func(t *testing.T) EventBuffer{
+		"memory": func(t *testing.T) EventBuffer { return NewMemoryEventBuffer(3) },
+		"redis": func(t *testing.T) EventBuffer {
+			server := miniredis.RunT(t)
+			client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+			t.Cleanup(func() { _ = client.Close() })
+			return NewRedisEventBuffer(client, "test:", 3)
+		},
+	}
+	for name, newBuffer := range buffers {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			buf := newBuffer(t)
+
+			if _, err := buf.Read(ctx, "missing", 0); !errors.Is(err, ErrStreamNotFound) {
+				t.Fatalf("Read(missing) error = %v, want ErrStreamNotFound", err)
+			}
+			if _, err := buf.Append(ctx, "missing", []byte("x")); !errors.Is(err, ErrStreamNotFound) {
+				t.Fatalf("Append(missing) error = %v, want ErrStreamNotFound", err)
+			}
+
+			if err := buf.Start(ctx, "req-1", "key:k1", time.Minute); err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			for i, data := range []string{"a", "b"} {
+				seq, err := buf.Append(ctx, "req-1", []byte(data))
+				if err != nil || seq != int64(i+1) {
+					t.Fatalf("Append(%q) = %d, %v; want %d", data, seq, err, i+1)
+				}
+			}
+
+			replay, err := buf.Read(ctx, "req-1", 1)
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if replay.Owner != "key:k1" || replay.Done || len(replay.Events) != 1 ||
+				replay.Events[0].Seq != 2 || string(replay.Events[0].Data) != "b" {
+				t.Fatalf("unexpected replay: %+v", replay)
+			}
+
+			if _, err := buf.Append(ctx, "req-1", []byte("c")); err != nil {
+				t.Fatalf("Append: %v", err)
+			}
+			if _, err := buf.Append(ctx, "req-1", []byte("d")); !errors.Is(err, ErrStreamBufferFull) {
+				t.Fatalf("Append past max error = %v, want ErrStreamBufferFull", err)
+			}
+			if err := buf.Finish(ctx, "req-1"); err != nil {
+				t.Fatalf("Finish: %v", err)
+			}
+
+			replay, err = buf.Read(ctx, "req-1", 0)
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if !replay.Done || len(replay.Events) != 3 || string(replay.Events[2].Data) != "c" {
+				t.Fatalf("unexpected replay after finish: %+v", replay)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package tokenizer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/eliben/go-sentencepiece"
+	"github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+// anthropicTokenPercent scales cl100k_base counts for Claude models.
+// Anthropic does not publish the tokenizer of current Claude models, and its
+// smaller vocabulary splits text into roughly 10-20% more tokens than
+// cl100k_base; the upper end is used so budgets and rate limits overcount
+// rather than undercount.
+const anthropicTokenPercent = 120
+
+// o200kPrefixes lists OpenAI model families on o200k_base that tiktoken-go
+// does not map.
+var o200kPrefixes = []string{"gpt-4o", "gpt-4.1", "gpt-4.5", "gpt-5", "chatgpt-4o", "gpt-oss", "o1", "o3", "o4"}
+
+var (
+	counterCache sync.Map // normalized model name -> counter
+	defaultOnce  sync.Once
+	defaultEnc   *tiktoken.Tiktoken
+
+	sentencePieceMu     sync.RWMutex
+	sentencePieceModels = map[string]*sentencepiece.Processor{}
+)
+
+func init() {
+	// Read BPE ranks from the embedded files instead of downloading them on
+	// first use, so counts do not depend on network access.
+	tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+}
+
+// counter counts the tokens of text for one model family.
+type counter interface {
+	count(text string) int
+}
+
+// tiktokenCounter counts with an OpenAI BPE encoding.
+type tiktokenCounter struct {
+	enc *tiktoken.Tiktoken
+}
+
+func (c tiktokenCounter) count(text string) int {
+	return len(c.enc.Encode(text, nil, nil))
+}
+
+// anthropicCounter approximates Claude's tokenizer from cl100k_base.
+type anthropicCounter struct {
+	enc *tiktoken.Tiktoken
+}
+
+func (c anthropicCounter) count(text string) int {
+	n := len(c.enc.Encode(text, nil, nil))
+	return (n*anthropicTokenPercent + 99) / 100
+}
+
+// sentencePieceCounter counts with a SentencePiece model, as used by open
+// models such as Gemma.
+type sentencePieceCounter struct {
+	proc *sentencepiece.Processor
+}
+
+func (c sentencePieceCounter) count(text string) int {
+	return len(c.proc.Encode(text))
+}
+
+// heuristicCounter is the fallback when no tokenizer is available.
+type heuristicCounter struct{}
+
+func (heuristicCounter) count(text string) int {
+	return len(text) / 4
+}
+
+// LoadSentencePiece counts the tokens of models whose name starts with prefix
+// (case-insensitive, ignoring any "provider/" part) with the SentencePiece
+// model file at path, usually named tokenizer.model. Only BPE models without
+// normalizer options, such as Gemma's, are supported. When several prefixes
+// match a model the longest wins.
+func LoadSentencePiece(prefix, path string) (err error) {
+	defer func() {
+		// The decoder dereferences optional normalizer fields, so model files
+		// that leave them unset panic instead of failing.
+		if r := recover(); r != nil {
+			err = fmt.Errorf("load sentencepiece model %q: unsupported model: %v", path, r)
+		}
+	}()
+	proc, err := sentencepiece.NewProcessorFromPath(path)
+	if err != nil {
+		return fmt.Errorf("load sentencepiece model %q: %w", path, err)
+	}
+
+	sentencePieceMu.Lock()
+	sentencePieceModels[strings.ToLower(prefix)] = proc
+	sentencePieceMu.Unlock()
+	counterCache.Clear()
+	return nil
+}
+
+// counterFor returns the counter for model's family: a configured
+// SentencePiece model, the Claude approximation, or the model's tiktoken
+// encoding (cl100k_base for unknown models), falling back to a len/4
+// estimate when no encoding loads.
+func counterFor(model string) counter {
+	base := strings.ToLower(normalizeModelName(model))
+	if cached, ok := counterCache.Load(base); ok {
+		return cached.(counter)
+	}
+
+	var c counter = heuristicCounter{}
+	if proc := sentencePieceFor(base); proc != nil {
+		c = sentencePieceCounter{proc: proc}
+	} else if strings.Contains(base, "claude") {
+		if enc := getDefaultEncoding(); enc != nil {
+			c = anthropicCounter{enc: enc}
+		}
+	} else if enc := getEncoding(base); enc != nil {
+		c = tiktokenCounter{enc: enc}
+	}
+	counterCache.Store(base, c)
+	return c
+}
+
+func sentencePieceFor(base string) *sentencepiece.Processor {
+	sentencePieceMu.RLock()
+	defer sentencePieceMu.RUnlock()
+	var match *sentencepiece.Processor
+	matchLen := -1
+	for prefix, proc := range sentencePieceModels {
+		if strings.HasPrefix(base, prefix) && len(prefix) > matchLen {
+			match, matchLen = proc, len(prefix)
+		}
+	}
+	return match
+}
+
+func getEncoding(base string) *tiktoken.Tiktoken {
+	for _, prefix := range o200kPrefixes {
+		if strings.HasPrefix(base, prefix) {
+			if enc, err := tiktoken.GetEncoding(tiktoken.MODEL_O200K_BASE); err == nil {
+				return enc
+			}
+			break
+		}
+	}
+
+	if enc, err := tiktoken.EncodingForModel(base); err == nil {
+		return enc
+	}
+	return getDefaultEncoding()
+}
+
+func getDefaultEncoding() *tiktoken.Tiktoken {
+	defaultOnce.Do(func() {
+		enc, err := tiktoken.GetEncoding(tiktoken.MODEL_CL100K_BASE)
+		if err == nil {
+			defaultEnc = enc
+		}
+	})
+	return defaultEnc
+}
@@ -0,0 +1,117 @@
+package tokenizer
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestCountTextTokens_OpenAIEncodings(t *testing.T) {
+	tests := []struct {
+		model string
+		text  string
+		want  int
+	}{
+		// "hello world" is two tokens in both encodings; the emoji differs.
+		{model: "gpt-4", text: "hello world", want: 2},
+		{model: "openai/gpt-4o-mini", text: "hello world", want: 2},
+		{model: "gpt-4", text: "🤗", want: 3},
+		{model: "gpt-4o", text: "🤗", want: 2},
+		{model: "o3-mini", text: "🤗", want: 2},
+		{model: "GPT-5", text: "🤗", want: 2},
+	}
+	for _, tt := range tests {
+		if got := CountTextTokens(tt.model, tt.text); got != tt.want {
+			t.Errorf("CountTextTokens(%q, %q) = %d, want %d", tt.model, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCountTextTokens_Claude(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog."
+	base := CountTextTokens("gpt-4", text)
+	for _, model := range []string{"claude-sonnet-4-5", "anthropic.claude-3-haiku-20240307-v1:0"} {
+		got := CountTextTokens(model, text)
+		if want := (base*anthropicTokenPercent + 99) / 100; got != want {
+			t.Errorf("CountTextTokens(%q) = %d, want %d", model, got, want)
+		}
+	}
+}
+
+func TestLoadSentencePiece(t *testing.T) {
+	t.Cleanup(func() {
+		sentencePieceMu.Lock()
+		clear(sentencePieceModels)
+		sentencePieceMu.Unlock()
+		counterCache.Clear()
+	})
+
+	// Cached before loading, so loading must reset the selection.
+	before := CountTextTokens("gemma-2-9b-it", "ab ab")
+
+	path := filepath.Join(t.TempDir(), "tokenizer.model")
+	if err := os.WriteFile(path, testSentencePieceModel(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadSentencePiece("Gemma", path); err != nil {
+		t.Fatalf("LoadSentencePiece: %v", err)
+	}
+
+	// "ab ab" is "ab▁ab" after normalization: ab, ▁, ab.
+	if got := CountTextTokens("google/gemma-2-9b-it", "ab ab"); got != 3 {
+		t.Fatalf("CountTextTokens(gemma) = %d, want 3 (before loading: %d)", got, before)
+	}
+	if _, ok := counterFor("gpt-4").(tiktokenCounter); !ok {
+		t.Fatal("expected other models to keep their tokenizer")
+	}
+
+	if err := LoadSentencePiece("llama", filepath.Join(t.TempDir(), "missing.model")); err == nil {
+		t.Fatal("expected an error for a missing model file")
+	}
+}
+
+// testSentencePieceModel encodes a minimal BPE SentencePiece model proto
+// with the pieces <unk>, ▁, a, b and ab.
+func testSentencePieceModel() []byte {
+	piece := func(text string, score float32, typ uint64) []byte {
+		var b []byte
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, text)
+		b = protowire.AppendTag(b, 2, protowire.Fixed32Type)
+		b = protowire.AppendFixed32(b, math.Float32bits(score))
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		return protowire.AppendVarint(b, typ)
+	}
+	const normal, unknown = 1, 2
+
+	var model []byte
+	for _, p := range [][]byte{
+		piece("<unk>", 0, unknown),
+		piece("▁", -1, normal),
+		piece("a", -2, normal),
+		piece("b", -3, normal),
+		piece("ab", -0.5, normal),
+	} {
+		model = protowire.AppendTag(model, 1, protowire.BytesType)
+		model = protowire.AppendBytes(model, p)
+	}
+
+	// trainer_spec.model_type = BPE
+	var trainer []byte
+	trainer = protowire.AppendTag(trainer, 3, protowire.VarintType)
+	trainer = protowire.AppendVarint(trainer, 2)
+	model = protowire.AppendTag(model, 2, protowire.BytesType)
+	model = protowire.AppendBytes(model, trainer)
+
+	// normalizer_spec.add_dummy_prefix = false, remove_extra_whitespaces = false
+	var normalizer []byte
+	normalizer = protowire.AppendTag(normalizer, 3, protowire.VarintType)
+	normalizer = protowire.AppendVarint(normalizer, 0)
+	normalizer = protowire.AppendTag(normalizer, 4, protowire.VarintType)
+	normalizer = protowire.AppendVarint(normalizer, 0)
+	model = protowire.AppendTag(model, 3, protowire.BytesType)
+	return protowire.AppendBytes(model, normalizer)
+}
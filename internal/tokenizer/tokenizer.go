@@ -8,19 +8,10 @@ import (
 	"fmt"
 	"sort"
 	"strings"
-	"sync"
-
-	"github.com/pkoukk/tiktoken-go"
 
 	"github.com/blueberrycongee/llmux/pkg/types"
 )
 
-var (
-	encodingCache sync.Map
-	defaultOnce   sync.Once
-	defaultEnc    *tiktoken.Tiktoken
-)
-
 const (
 	replyPrimerTokenCount        = 3
 	functionDefinitionTokenCount = 9
@@ -61,17 +52,13 @@ func newMessageCountParams(model string) messageCountParams {
 	return params
 }
 
-// CountTextTokens returns the token count for the given text using tiktoken.
-// If no encoding is available, it falls back to a conservative len/4 estimate.
+// CountTextTokens returns the token count for the given text using the
+// tokenizer of the model's family (see counterFor).
 func CountTextTokens(model, text string) int {
 	if text == "" {
 		return 0
 	}
-	enc := getEncoding(model)
-	if enc == nil {
-		return len(text) / 4
-	}
-	return len(enc.Encode(text, nil, nil))
+	return counterFor(model).count(text)
 }
 
 // EstimatePromptTokens estimates prompt tokens for chat requests.
@@ -676,42 +663,6 @@ func formatEnum(values []any) string {
 	return strings.Join(parts, " | ")
 }
 
-func getEncoding(model string) *tiktoken.Tiktoken {
-	base := normalizeModelName(model)
-	if cached, ok := encodingCache.Load(base); ok {
-		if enc, ok := cached.(*tiktoken.Tiktoken); ok {
-			return enc
-		}
-		return getDefaultEncoding()
-	}
-
-	if strings.Contains(base, "gpt-4o") {
-		if enc, err := tiktoken.GetEncoding("o200k_base"); err == nil {
-			encodingCache.Store(base, enc)
-			return enc
-		}
-	}
-
-	enc, err := tiktoken.EncodingForModel(base)
-	if err != nil {
-		enc = getDefaultEncoding()
-	}
-	if enc != nil {
-		encodingCache.Store(base, enc)
-	}
-	return enc
-}
-
-func getDefaultEncoding() *tiktoken.Tiktoken {
-	defaultOnce.Do(func() {
-		enc, err := tiktoken.GetEncoding("cl100k_base")
-		if err == nil {
-			defaultEnc = enc
-		}
-	})
-	return defaultEnc
-}
-
 func normalizeModelName(model string) string {
 	if model == "" {
 		return model
@@ -70,6 +70,12 @@ type (
 	// Usage contains token usage statistics for the request.
 	Usage = types.Usage
 
+	// CompletionTokensDetails breaks down completion tokens, e.g. reasoning tokens.
+	CompletionTokensDetails = types.CompletionTokensDetails
+
+	// Reasoning is the reasoning a model produced before its answer.
+	Reasoning = types.Reasoning
+
 	// Choice represents a single completion choice.
 	Choice = types.Choice
 
@@ -82,6 +88,9 @@ type (
 	// ResponseFormat specifies the output format for the model.
 	ResponseFormat = types.ResponseFormat
 
+	// JSONSchema describes the structured output of a json_schema response format.
+	JSONSchema = types.JSONSchema
+
 	// StreamOptions specifies options for streaming responses.
 	StreamOptions = types.StreamOptions
 )
@@ -97,6 +106,18 @@ type (
 	// ProviderConfig contains provider-specific configuration.
 	ProviderConfig = provider.Config
 
+	// RateLimits holds per-minute upstream quota for a deployment.
+	RateLimits = provider.RateLimits
+
+	// RolloverSchedule controls blue/green credential rollover for a provider.
+	RolloverSchedule = provider.RolloverSchedule
+
+	// TransportConfig tunes the connection pool of a provider.
+	TransportConfig = provider.TransportConfig
+
+	// RolloverStatus is a snapshot of a provider's credential rollover.
+	RolloverStatus = provider.RolloverStatus
+
 	// ProviderFactory creates provider instances from configuration.
 	ProviderFactory = provider.Factory
 )
@@ -123,6 +144,9 @@ type (
 
 	// RouterConfig contains router configuration options.
 	RouterConfig = router.Config
+
+	// DecisionTrace records candidates, filters and scores for one routing pick.
+	DecisionTrace = router.DecisionTrace
 )
 
 // Re-export cache types.
@@ -150,6 +174,7 @@ type ResilienceStats struct {
 	RateLimitTokens    float64 `json:"rate_limit_tokens"`
 	ConcurrentCurrent  int     `json:"concurrent_current"`
 	ConcurrentCapacity int     `json:"concurrent_capacity"`
+	ConcurrentWaiting  int     `json:"concurrent_waiting"`
 }
 
 // Re-export plugin types.
@@ -0,0 +1,298 @@
+package llmux
+
+import (
+	"context"
+	stderrors "errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/memory"
+	"github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/router"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// ErrLongTermMemoryDisabled is returned by Remember, Recall and Forget when
+// the client has no long-term memory.
+var ErrLongTermMemoryDisabled = stderrors.New("long-term memory is not configured")
+
+// Re-export long-term memory types.
+type (
+	// LongTermMemory remembers texts as embeddings and recalls similar ones.
+	LongTermMemory = memory.LongTermMemory
+
+	// VectorStore persists memories and answers similarity queries.
+	VectorStore = memory.VectorStore
+
+	// MemoryRecord is a remembered text and its embedding.
+	MemoryRecord = memory.Record
+
+	// MemoryMatch is a recalled memory with its similarity score.
+	MemoryMatch = memory.Match
+
+	// MemoryRecallConfig tunes recall.
+	MemoryRecallConfig = memory.LongTermConfig
+
+	// PgVectorConfig configures the pgvector store.
+	PgVectorConfig = memory.PgVectorConfig
+
+	// QdrantVectorConfig configures the Qdrant store.
+	QdrantVectorConfig = memory.QdrantVectorConfig
+
+	// MilvusVectorConfig configures the Milvus store.
+	MilvusVectorConfig = memory.MilvusVectorConfig
+)
+
+// Long-term memory constructors.
+var (
+	// NewLongTermMemory creates a long-term memory over a store and embedder.
+	NewLongTermMemory = memory.NewLongTermMemory
+	// NewMemoryVectorStore creates an in-process vector store.
+	NewMemoryVectorStore = memory.NewMemoryVectorStore
+	// NewPgVectorStore creates a pgvector-backed store.
+	NewPgVectorStore = memory.NewPgVectorStore
+	// NewQdrantVectorStore creates a Qdrant-backed store.
+	NewQdrantVectorStore = memory.NewQdrantVectorStore
+	// NewMilvusVectorStore creates a Milvus-backed store.
+	NewMilvusVectorStore = memory.NewMilvusVectorStore
+)
+
+// LongTermMemoryConfig configures the client's long-term memory.
+type LongTermMemoryConfig struct {
+	// Store persists the memories.
+	Store VectorStore
+	// EmbeddingModel is the model texts are embedded with.
+	EmbeddingModel string
+	// Recall tunes how many memories are recalled and how similar they must be.
+	Recall MemoryRecallConfig
+	// Chunking splits documents passed to Ingest (default: paragraphs of up
+	// to 1000 characters).
+	Chunking ChunkConfig
+	// Extractors add or replace the extractors Ingest uses per content type,
+	// e.g. a CommandExtractor running pdftotext for "application/pdf".
+	// text/plain and text/markdown are supported by default.
+	Extractors map[string]Extractor
+}
+
+// memoryEmbedder embeds texts through the client's Embedding path, so
+// routing, rate limits and sandbox mode apply to memory as to any request.
+func (c *Client) memoryEmbedder(model string) memory.Embedder {
+	return memory.EmbedderFunc(func(ctx context.Context, texts []string) ([][]float64, error) {
+		resp, err := c.Embedding(ctx, &types.EmbeddingRequest{
+			Model: model,
+			Input: types.NewEmbeddingInputFromStrings(texts),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if usage, ok := ctx.Value(embeddingUsageKey{}).(*embeddingUsage); ok {
+			usage.add(resp.Usage, c.CalculateCost(resp.Model, &resp.Usage))
+		}
+		data := append([]types.EmbeddingObject(nil), resp.Data...)
+		sort.Slice(data, func(i, j int) bool { return data[i].Index < data[j].Index })
+		vectors := make([][]float64, 0, len(data))
+		for _, d := range data {
+			vectors = append(vectors, d.Embedding)
+		}
+		return vectors, nil
+	})
+}
+
+// embeddingUsageKey is the context key of the embeddingUsage that memory
+// embeddings are added to.
+type embeddingUsageKey struct{}
+
+// embeddingUsage totals the embedding calls of one memory operation.
+type embeddingUsage struct {
+	mu    sync.Mutex
+	usage Usage
+	cost  float64
+}
+
+func (u *embeddingUsage) add(usage Usage, cost float64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.usage.PromptTokens += usage.PromptTokens
+	u.usage.TotalTokens += usage.TotalTokens
+	u.usage.Provider = usage.Provider
+	u.cost += cost
+}
+
+func (u *embeddingUsage) total() (Usage, float64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.usage, u.cost
+}
+
+// MemoryEmbeddingModel returns the model long-term memory embeds with, or ""
+// when long-term memory is disabled.
+func (c *Client) MemoryEmbeddingModel() string {
+	return c.memoryModel
+}
+
+// validateCollection rejects empty collection names and names containing
+// "/", which separates the tenant scope from the collection in a namespace.
+func validateCollection(collection string) error {
+	if collection == "" || strings.Contains(collection, "/") {
+		return memoryError(memory.ErrInvalidNamespace)
+	}
+	return nil
+}
+
+// memoryScope attaches the caller's tenant scope to ctx for a memory
+// operation. Callers authenticated without an API key, such as SSO users,
+// are scoped by user; an authenticated caller with neither is refused rather
+// than given unscoped memories.
+func (c *Client) memoryScope(ctx context.Context) (context.Context, error) {
+	ctx = c.withTenantScope(ctx)
+	if router.TenantScopeFromContext(ctx) != "" {
+		return ctx, nil
+	}
+	authCtx := auth.GetAuthContext(ctx)
+	if authCtx == nil {
+		return ctx, nil
+	}
+	if authCtx.User != nil && authCtx.User.ID != "" {
+		return router.WithTenantScope(ctx, "user:"+authCtx.User.ID), nil
+	}
+	return nil, errors.NewPermissionError("", "", "memory requires an API key or user identity")
+}
+
+// memoryNamespace scopes a collection to the caller's tenant so memories of
+// different API keys and users never mix.
+func memoryNamespace(ctx context.Context, collection string) string {
+	if scope := router.TenantScopeFromContext(ctx); scope != "" {
+		return scope + "/" + collection
+	}
+	return collection
+}
+
+// memoryError maps validation failures to invalid request errors.
+func memoryError(err error) error {
+	switch {
+	case stderrors.Is(err, memory.ErrInvalidNamespace):
+		return errors.NewInvalidRequestError("", "", "invalid memory collection")
+	case stderrors.Is(err, memory.ErrEmptyText):
+		return errors.NewInvalidRequestError("", "", "memory text is required")
+	}
+	return err
+}
+
+// Remember stores text in collection for the tenant attached to ctx and
+// returns the memory's ID.
+func (c *Client) Remember(ctx context.Context, collection, text string, metadata map[string]string) (string, error) {
+	if c.longTermMemory == nil {
+		return "", ErrLongTermMemoryDisabled
+	}
+	if err := validateCollection(collection); err != nil {
+		return "", err
+	}
+	ctx, err := c.memoryScope(ctx)
+	if err != nil {
+		return "", err
+	}
+	size := memory.RecordSize(text, metadata)
+	if err := c.checkMemoryQuota(ctx, "", MemoryUsage{Vectors: 1, Bytes: size}); err != nil {
+		return "", err
+	}
+	namespace := memoryNamespace(ctx, collection)
+	id, err := c.longTermMemory.Remember(ctx, namespace, text, metadata)
+	if err != nil {
+		return "", memoryError(err)
+	}
+	if c.memoryQuotas != nil {
+		if err := c.memoryQuotas.Ledger.Track(ctx, memoryTenant(ctx), memory.KindVector, namespace+"/"+id, size, c.memoryRetention.VectorAge); err != nil {
+			c.logger.Warn("failed to track memory size", "error", err)
+		}
+	}
+	return id, nil
+}
+
+// Recall returns up to topK memories of collection most similar to query for
+// the tenant attached to ctx, best match first. topK <= 0 uses the
+// configured default.
+func (c *Client) Recall(ctx context.Context, collection, query string, topK int) ([]MemoryMatch, error) {
+	if c.longTermMemory == nil {
+		return nil, ErrLongTermMemoryDisabled
+	}
+	if err := validateCollection(collection); err != nil {
+		return nil, err
+	}
+	ctx, err := c.memoryScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := c.longTermMemory.Recall(ctx, memoryNamespace(ctx, collection), query, topK)
+	if err != nil {
+		return nil, memoryError(err)
+	}
+	return matches, nil
+}
+
+// Forget removes memories of collection by ID for the tenant attached to ctx.
+func (c *Client) Forget(ctx context.Context, collection string, ids ...string) error {
+	if c.longTermMemory == nil {
+		return ErrLongTermMemoryDisabled
+	}
+	if err := validateCollection(collection); err != nil {
+		return err
+	}
+	ctx, err := c.memoryScope(ctx)
+	if err != nil {
+		return err
+	}
+	namespace := memoryNamespace(ctx, collection)
+	if err := c.longTermMemory.Forget(ctx, namespace, ids...); err != nil {
+		return memoryError(err)
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = namespace + "/" + id
+	}
+	c.untrackMemory(ctx, memory.KindVector, keys...)
+	return nil
+}
+
+// memoryContextHeader introduces recalled memories in the injected system message.
+const memoryContextHeader = "Relevant context from memory:"
+
+// AugmentWithMemory recalls up to topK memories of collection related to the
+// last user message of req and returns a copy of req with them injected as a
+// system message after its leading system messages, along with the memories
+// used. req is returned unchanged when nothing relevant is recalled.
+func (c *Client) AugmentWithMemory(ctx context.Context, req *ChatRequest, collection string, topK int) (*ChatRequest, []MemoryMatch, error) {
+	query := ""
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == "user" {
+			query = req.Messages[i].TextContent()
+			break
+		}
+	}
+	if query == "" {
+		return req, nil, nil
+	}
+
+	matches, err := c.Recall(ctx, collection, query, topK)
+	if err != nil || len(matches) == 0 {
+		return req, nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString(memoryContextHeader)
+	for _, m := range matches {
+		b.WriteString("\n- ")
+		b.WriteString(m.Text)
+	}
+	split := 0
+	for split < len(req.Messages) && req.Messages[split].Role == "system" {
+		split++
+	}
+	cloned := *req
+	cloned.Messages = make([]ChatMessage, 0, len(req.Messages)+1)
+	cloned.Messages = append(cloned.Messages, req.Messages[:split]...)
+	cloned.Messages = append(cloned.Messages, ChatMessage{Role: "system", Content: jsonString(b.String())})
+	cloned.Messages = append(cloned.Messages, req.Messages[split:]...)
+	return &cloned, matches, nil
+}
@@ -0,0 +1,119 @@
+package llmux
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+func TestLongTermMemory_RememberAndRecallPerTenant(t *testing.T) {
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"embed"}}, []string{"embed"}),
+		withTestPricing(t, "embed"),
+		WithSandboxConfig(SandboxConfig{EmbeddingDimensions: 16}),
+		WithLongTermMemory(LongTermMemoryConfig{Store: NewMemoryVectorStore(), EmbeddingModel: "embed"}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	// Sandbox embeddings are deterministic, so identical texts match exactly.
+	tenantA := WithSandbox(router.WithTenantScope(context.Background(), "key-a"))
+	tenantB := WithSandbox(router.WithTenantScope(context.Background(), "key-b"))
+
+	id, err := client.Remember(tenantA, "notes", "prefers metric units", map[string]string{"kind": "preference"})
+	if err != nil {
+		t.Fatalf("Remember() error = %v", err)
+	}
+	if _, err := client.Remember(tenantA, "notes", "lives in Lisbon", nil); err != nil {
+		t.Fatalf("Remember() error = %v", err)
+	}
+
+	matches, err := client.Recall(tenantA, "notes", "prefers metric units", 1)
+	if err != nil {
+		t.Fatalf("Recall() error = %v", err)
+	}
+	if len(matches) != 1 || matches[0].ID != id || matches[0].Metadata["kind"] != "preference" {
+		t.Fatalf("Recall() = %+v, want the remembered preference", matches)
+	}
+
+	other, err := client.Recall(tenantB, "notes", "prefers metric units", 5)
+	if err != nil {
+		t.Fatalf("Recall() error = %v", err)
+	}
+	if len(other) != 0 {
+		t.Fatalf("another tenant recalled %d memories", len(other))
+	}
+
+	if err := client.Forget(tenantA, "notes", id); err != nil {
+		t.Fatalf("Forget() error = %v", err)
+	}
+	matches, err = client.Recall(tenantA, "notes", "prefers metric units", 5)
+	if err != nil {
+		t.Fatalf("Recall() error = %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("Recall() after Forget returned %d memories, want 1", len(matches))
+	}
+}
+
+func TestLongTermMemory_CallersWithoutKeyCannotReachOtherTenants(t *testing.T) {
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"embed"}}, []string{"embed"}),
+		withTestPricing(t, "embed"),
+		WithSandboxConfig(SandboxConfig{EmbeddingDimensions: 16}),
+		WithLongTermMemory(LongTermMemoryConfig{Store: NewMemoryVectorStore(), EmbeddingModel: "embed"}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	victim := WithSandbox(auth.WithAuthContext(context.Background(), &auth.AuthContext{APIKey: &auth.APIKey{ID: "victim-key"}}))
+	if _, err := client.Remember(victim, "notes", "the vault code is 1234", nil); err != nil {
+		t.Fatalf("Remember() error = %v", err)
+	}
+
+	// An SSO user has no API key, so it is scoped by user.
+	user := WithSandbox(auth.WithAuthContext(context.Background(), &auth.AuthContext{User: &auth.User{ID: "user-1"}}))
+	var llmErr *errors.LLMError
+	if _, err := client.Recall(user, "victim-key/notes", "the vault code is 1234", 5); !stderrors.As(err, &llmErr) || llmErr.StatusCode != 400 {
+		t.Fatalf("Recall() of a scoped collection name error = %v, want invalid request", err)
+	}
+	if _, err := client.Remember(user, "victim-key/notes", "planted", nil); err == nil {
+		t.Fatal("Remember() into another key's namespace succeeded")
+	}
+	matches, err := client.Recall(user, "notes", "the vault code is 1234", 5)
+	if err != nil {
+		t.Fatalf("Recall() error = %v", err)
+	}
+	if len(matches) != 0 {
+		t.Fatalf("user recalled %d memories of another key", len(matches))
+	}
+
+	// Authenticated callers with no identity to scope by are refused.
+	anonymous := WithSandbox(auth.WithAuthContext(context.Background(), &auth.AuthContext{}))
+	if _, err := client.Recall(anonymous, "notes", "the vault code is 1234", 5); !stderrors.As(err, &llmErr) || llmErr.StatusCode != 403 {
+		t.Fatalf("Recall() without identity error = %v, want permission denied", err)
+	}
+}
+
+func TestLongTermMemory_Disabled(t *testing.T) {
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"m"}}, []string{"m"}),
+		withTestPricing(t, "m"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Recall(context.Background(), "notes", "q", 1); err != ErrLongTermMemoryDisabled {
+		t.Fatalf("Recall() error = %v, want ErrLongTermMemoryDisabled", err)
+	}
+}
@@ -0,0 +1,69 @@
+package llmux
+
+import (
+	"fmt"
+
+	"github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/provider"
+)
+
+// MaxTokensPolicy controls requests whose max_tokens exceed the output limit
+// of the model in the pricing data.
+type MaxTokensPolicy string
+
+const (
+	// MaxTokensPolicyClamp lowers max_tokens to the model's limit (default).
+	MaxTokensPolicyClamp MaxTokensPolicy = "clamp"
+	// MaxTokensPolicyReject rejects the request with a 400 naming the limit.
+	MaxTokensPolicyReject MaxTokensPolicy = "reject"
+)
+
+func validateMaxTokensPolicy(policy MaxTokensPolicy) error {
+	switch policy {
+	case "", MaxTokensPolicyClamp, MaxTokensPolicyReject:
+		return nil
+	default:
+		return fmt.Errorf("unknown max tokens policy %q", policy)
+	}
+}
+
+// maxOutputTokens returns the output limit of model on the deployment, or 0
+// when the pricing data has none. A deployment price without a limit keeps
+// the model's.
+func (c *Client) maxOutputTokens(deploymentID, model, provider string) int {
+	if c.pricing == nil {
+		return 0
+	}
+	if price, ok := c.pricing.LookupDeployment(deploymentID, model, provider); ok && price.MaxOutputTokens > 0 {
+		return price.MaxOutputTokens
+	}
+	price, _ := c.pricing.GetPrice(model, provider)
+	return price.MaxOutputTokens
+}
+
+// limitMaxTokens checks req's max_tokens against the output limit of model
+// on deployment before it is sent there, so the client gets a descriptive
+// error instead of the provider's. A clamped request is a copy, leaving req
+// intact for fallback deployments with other limits.
+func (c *Client) limitMaxTokens(deployment *provider.Deployment, model string, req *ChatRequest) (*ChatRequest, error) {
+	if req.MaxTokens <= 0 {
+		return req, nil
+	}
+	limit := c.maxOutputTokens(deployment.ID, model, deployment.ProviderName)
+	if limit <= 0 || req.MaxTokens <= limit {
+		return req, nil
+	}
+	if c.config.MaxTokensPolicy == MaxTokensPolicyReject {
+		return nil, errors.NewInvalidRequestError(deployment.ProviderName, model,
+			fmt.Sprintf("max_tokens %d exceeds the %d output tokens supported by model %s", req.MaxTokens, limit, model))
+	}
+	c.logger.Debug("clamping max_tokens to model output limit",
+		"model", model,
+		"deployment", deployment.ID,
+		"max_tokens", req.MaxTokens,
+		"limit", limit,
+	)
+	clamped := *req
+	clamped.MaxTokens = limit
+	return &clamped, nil
+}
@@ -0,0 +1,96 @@
+package llmux
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blueberrycongee/llmux/pkg/pricing"
+)
+
+func TestLimitMaxTokens(t *testing.T) {
+	var sent atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		sent.Store(int64(req.MaxTokens))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			ID:      "resp",
+			Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: jsonString("ok")}, FinishReason: "stop"}},
+			Usage:   &Usage{PromptTokens: 3, CompletionTokens: 1, TotalTokens: 4},
+		})
+	}))
+	defer server.Close()
+
+	newClient := func(opts ...Option) *Client {
+		t.Helper()
+		opts = append([]Option{
+			WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"m"}, baseURL: server.URL}, []string{"m"}),
+			withTestPricing(t, "m"),
+			WithTimeout(5 * time.Second),
+		}, opts...)
+		client, err := New(opts...)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		t.Cleanup(func() { _ = client.Close() })
+		client.Pricing().SetOverride("m", pricing.ModelPrice{InputCostPerToken: 0.00001, OutputCostPerToken: 0.00002, MaxOutputTokens: 100})
+		return client
+	}
+	chat := func(client *Client, maxTokens int) (*ChatRequest, error) {
+		req := &ChatRequest{Model: "m", MaxTokens: maxTokens, Messages: []ChatMessage{{Role: "user", Content: jsonString("hi")}}}
+		_, err := client.ChatCompletion(context.Background(), req)
+		return req, err
+	}
+
+	client := newClient()
+	for _, tc := range []struct{ maxTokens, want int }{{500, 100}, {100, 100}, {20, 20}, {0, 0}} {
+		req, err := chat(client, tc.maxTokens)
+		if err != nil {
+			t.Fatalf("max_tokens %d: error = %v", tc.maxTokens, err)
+		}
+		if got := int(sent.Load()); got != tc.want {
+			t.Fatalf("max_tokens %d: sent %d, want %d", tc.maxTokens, got, tc.want)
+		}
+		if req.MaxTokens != tc.maxTokens {
+			t.Fatalf("request was modified: max_tokens = %d", req.MaxTokens)
+		}
+	}
+
+	// A deployment price without a limit keeps the model's.
+	client.Pricing().SetDeploymentPrice("mock-m", pricing.ModelPrice{InputCostPerToken: 0.00001, OutputCostPerToken: 0.00002})
+	if _, err := chat(client, 500); err != nil || sent.Load() != 100 {
+		t.Fatalf("deployment price: sent %d, error = %v", sent.Load(), err)
+	}
+	client.Pricing().SetDeploymentPrice("mock-m", pricing.ModelPrice{InputCostPerToken: 0.00001, OutputCostPerToken: 0.00002, MaxOutputTokens: 300})
+	if _, err := chat(client, 500); err != nil || sent.Load() != 300 {
+		t.Fatalf("deployment limit: sent %d, error = %v", sent.Load(), err)
+	}
+
+	rejecting := newClient(WithMaxTokensPolicy(MaxTokensPolicyReject))
+	sent.Store(-1)
+	_, err := chat(rejecting, 500)
+	var llmErr *LLMError
+	if !stderrors.As(err, &llmErr) || llmErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("error = %v, want a 400", err)
+	}
+	if want := "max_tokens 500 exceeds the 100 output tokens supported by model m"; llmErr.Message != want {
+		t.Fatalf("message = %q, want %q", llmErr.Message, want)
+	}
+	if sent.Load() != -1 {
+		t.Fatal("rejected request was sent upstream")
+	}
+	if _, err := chat(rejecting, 100); err != nil {
+		t.Fatalf("max_tokens at the limit: error = %v", err)
+	}
+
+	if _, err := New(WithMaxTokensPolicy("truncate")); err == nil {
+		t.Fatal("expected error for unknown policy")
+	}
+}
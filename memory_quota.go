@@ -0,0 +1,174 @@
+package llmux
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/memory"
+	"github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+// Team metadata keys overriding the default memory quota for one team.
+const (
+	TeamMetadataMemoryMaxSessions = "memory_max_sessions"
+	TeamMetadataMemoryMaxVectors  = "memory_max_vectors"
+	TeamMetadataMemoryMaxBytes    = "memory_max_bytes"
+)
+
+// Re-export memory quota types.
+type (
+	// MemoryQuota bounds the memory a team may hold. Zero fields are unlimited.
+	MemoryQuota = memory.Quota
+
+	// MemoryUsage is the memory a team currently holds.
+	MemoryUsage = memory.Usage
+
+	// MemoryQuotaLedger tracks memory usage per team.
+	MemoryQuotaLedger = memory.QuotaLedger
+)
+
+// Memory quota ledger constructors.
+var (
+	// NewMemoryQuotaLedger creates an in-process ledger.
+	NewMemoryQuotaLedger = memory.NewMemoryQuotaLedger
+	// NewRedisQuotaLedger creates a Redis-backed ledger shared by instances.
+	NewRedisQuotaLedger = memory.NewRedisQuotaLedger
+)
+
+// MemoryQuotaConfig configures per-team memory quotas.
+type MemoryQuotaConfig struct {
+	// Default applies to every team without a metadata override.
+	Default MemoryQuota
+	// Ledger tracks usage (default: in-process).
+	Ledger MemoryQuotaLedger
+	// SessionTTL stops counting sessions idle for this long; set it to the
+	// session store's TTL. It defaults to the session retention; zero counts
+	// sessions until they are cleared.
+	SessionTTL time.Duration
+}
+
+// memoryTenant returns the tenant memory usage is accounted to: the caller's
+// team, or its API key when it has no team.
+func memoryTenant(ctx context.Context) string {
+	if authCtx := auth.GetAuthContext(ctx); authCtx != nil {
+		if authCtx.Team != nil && authCtx.Team.ID != "" {
+			return "team:" + authCtx.Team.ID
+		}
+		if authCtx.APIKey != nil && authCtx.APIKey.TeamID != nil && *authCtx.APIKey.TeamID != "" {
+			return "team:" + *authCtx.APIKey.TeamID
+		}
+	}
+	return router.TenantScopeFromContext(ctx)
+}
+
+// memoryQuota returns the quota of the caller's team, applying its metadata
+// overrides to the default.
+func (c *Client) memoryQuota(ctx context.Context) MemoryQuota {
+	quota := c.memoryQuotas.Default
+	authCtx := auth.GetAuthContext(ctx)
+	if authCtx == nil || authCtx.Team == nil {
+		return quota
+	}
+	if v, ok := metadataInt(authCtx.Team.Metadata, TeamMetadataMemoryMaxSessions); ok {
+		quota.MaxSessions = int(v)
+	}
+	if v, ok := metadataInt(authCtx.Team.Metadata, TeamMetadataMemoryMaxVectors); ok {
+		quota.MaxVectors = int(v)
+	}
+	if v, ok := metadataInt(authCtx.Team.Metadata, TeamMetadataMemoryMaxBytes); ok {
+		quota.MaxBytes = v
+	}
+	return quota
+}
+
+func metadataInt(metadata auth.Metadata, key string) (int64, bool) {
+	switch v := metadata[key].(type) {
+	case float64:
+		return int64(v), v >= 0
+	case int:
+		return int64(v), v >= 0
+	case int64:
+		return v, v >= 0
+	}
+	return 0, false
+}
+
+// checkMemoryQuota fails with an insufficient quota error when storing add
+// would exceed the caller's memory quota. Ledger failures are logged and the
+// write is allowed.
+func (c *Client) checkMemoryQuota(ctx context.Context, model string, add MemoryUsage) error {
+	if c.memoryQuotas == nil {
+		return nil
+	}
+	tenant := memoryTenant(ctx)
+	usage, err := c.memoryQuotas.Ledger.Usage(ctx, tenant)
+	if err != nil {
+		c.logger.Warn("failed to load memory usage", "error", err)
+		return nil
+	}
+	err = c.memoryQuota(ctx).Check(tenant, usage, add)
+	var exceeded *memory.QuotaExceededError
+	if stderrors.As(err, &exceeded) {
+		return errors.NewInsufficientQuotaError("gateway", model, fmt.Sprintf("memory %s quota exceeded", exceeded.Resource))
+	}
+	return err
+}
+
+// checkSessionQuota checks the caller may append turn to the session key,
+// counting a new session when the ledger does not track it yet.
+func (c *Client) checkSessionQuota(ctx context.Context, model, key string, turn []ChatMessage) error {
+	if c.memoryQuotas == nil {
+		return nil
+	}
+	add := MemoryUsage{Bytes: memory.MessagesSize(turn)}
+	_, tracked, err := c.memoryQuotas.Ledger.Size(ctx, memoryTenant(ctx), memory.KindSession, key)
+	if err != nil {
+		c.logger.Warn("failed to load session size", "error", err)
+		return nil
+	}
+	if !tracked {
+		add.Sessions = 1
+	}
+	return c.checkMemoryQuota(ctx, model, add)
+}
+
+// trackSession records the stored size of the session key.
+func (c *Client) trackSession(ctx context.Context, key string) {
+	if c.memoryQuotas == nil {
+		return
+	}
+	size, err := c.sessionMemory.Size(ctx, key)
+	if err == nil {
+		err = c.memoryQuotas.Ledger.Track(ctx, memoryTenant(ctx), memory.KindSession, key, size, c.memoryQuotas.SessionTTL)
+	}
+	if err != nil {
+		c.logger.Warn("failed to track session size", "error", err)
+	}
+}
+
+// untrackMemory stops counting items of kind against the caller's quota.
+func (c *Client) untrackMemory(ctx context.Context, kind memory.Kind, ids ...string) {
+	if c.memoryQuotas == nil {
+		return
+	}
+	if err := c.memoryQuotas.Ledger.Untrack(ctx, memoryTenant(ctx), kind, ids...); err != nil {
+		c.logger.Warn("failed to untrack memory", "kind", kind, "error", err)
+	}
+}
+
+// MemoryUsage returns the memory held by the team (or API key) attached to
+// ctx. It returns zero usage when memory quotas are not configured.
+func (c *Client) MemoryUsage(ctx context.Context) (MemoryUsage, error) {
+	if c.memoryQuotas == nil {
+		return MemoryUsage{}, nil
+	}
+	ctx, err := c.memoryScope(ctx)
+	if err != nil {
+		return MemoryUsage{}, err
+	}
+	return c.memoryQuotas.Ledger.Usage(ctx, memoryTenant(ctx))
+}
@@ -0,0 +1,101 @@
+package llmux
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+func TestMemoryQuotas_PerTeamLimits(t *testing.T) {
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"m", "embed"}}, []string{"m", "embed"}),
+		withTestPricing(t, "m"),
+		withTestPricing(t, "embed"),
+		WithSessionMemory(NewSessionMemory(NewMemorySessionStore(0), SessionWindow{})),
+		WithLongTermMemory(LongTermMemoryConfig{Store: NewMemoryVectorStore(), EmbeddingModel: "embed"}),
+		WithMemoryQuotas(MemoryQuotaConfig{Default: MemoryQuota{MaxSessions: 1, MaxVectors: 1}}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	teamCtx := func(keyID string, team *auth.Team) context.Context {
+		return WithSandbox(auth.WithAuthContext(context.Background(), &auth.AuthContext{
+			APIKey: &auth.APIKey{ID: keyID, TeamID: &team.ID},
+			Team:   team,
+		}))
+	}
+	teamA := &auth.Team{ID: "team-a"}
+	keyA1 := teamCtx("key-a1", teamA)
+	keyA2 := teamCtx("key-a2", teamA)
+	teamB := teamCtx("key-b", &auth.Team{ID: "team-b", Metadata: auth.Metadata{TeamMetadataMemoryMaxVectors: float64(2)}})
+
+	chat := func(ctx context.Context, sessionID string) error {
+		_, err := client.ChatCompletion(WithSessionID(ctx, sessionID), &ChatRequest{
+			Model:    "m",
+			Messages: []ChatMessage{{Role: "user", Content: jsonString("hello")}},
+		})
+		return err
+	}
+	wantQuotaError := func(err error, resource string) {
+		t.Helper()
+		llmErr, ok := err.(*errors.LLMError)
+		if !ok || llmErr.StatusCode != http.StatusPaymentRequired || llmErr.Type != errors.TypeInsufficientQuota {
+			t.Fatalf("error = %v, want insufficient quota for %s", err, resource)
+		}
+	}
+
+	if err := chat(keyA1, "s1"); err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if err := chat(keyA1, "s1"); err != nil {
+		t.Fatalf("continuing a session error = %v", err)
+	}
+	// Keys of one team share its quota.
+	wantQuotaError(chat(keyA2, "s2"), "sessions")
+	if err := chat(teamB, "s1"); err != nil {
+		t.Fatalf("other team ChatCompletion() error = %v", err)
+	}
+
+	usage, err := client.MemoryUsage(keyA2)
+	if err != nil {
+		t.Fatalf("MemoryUsage() error = %v", err)
+	}
+	if usage.Sessions != 1 || usage.Bytes == 0 {
+		t.Fatalf("MemoryUsage() = %+v, want one session with content", usage)
+	}
+
+	// Clearing a session frees its slot.
+	if err := client.ClearSession(keyA1, "s1"); err != nil {
+		t.Fatalf("ClearSession() error = %v", err)
+	}
+	if err := chat(keyA2, "s2"); err != nil {
+		t.Fatalf("ChatCompletion() after clear error = %v", err)
+	}
+
+	id, err := client.Remember(keyA1, "notes", "first", nil)
+	if err != nil {
+		t.Fatalf("Remember() error = %v", err)
+	}
+	_, err = client.Remember(keyA2, "notes", "second", nil)
+	wantQuotaError(err, "vectors")
+	if err := client.Forget(keyA1, "notes", id); err != nil {
+		t.Fatalf("Forget() error = %v", err)
+	}
+	if _, err := client.Remember(keyA2, "notes", "second", nil); err != nil {
+		t.Fatalf("Remember() after forget error = %v", err)
+	}
+
+	// Team metadata overrides the default.
+	for _, text := range []string{"one", "two"} {
+		if _, err := client.Remember(teamB, "notes", text, nil); err != nil {
+			t.Fatalf("Remember(%q) error = %v", text, err)
+		}
+	}
+	_, err = client.Remember(teamB, "notes", "three", nil)
+	wantQuotaError(err, "vectors")
+}
@@ -0,0 +1,49 @@
+package llmux
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/metrics"
+)
+
+// MemoryRetention bounds how long memory is kept. Zero fields keep it
+// until it is cleared.
+type MemoryRetention struct {
+	// SessionIdle deletes sessions without a new turn for this long.
+	SessionIdle time.Duration
+	// VectorAge deletes long-term memories this long after they were stored.
+	VectorAge time.Duration
+}
+
+// MemoryPruneResult reports what PruneMemory deleted.
+type MemoryPruneResult struct {
+	Sessions int `json:"sessions"`
+	Vectors  int `json:"vectors"`
+}
+
+// PruneMemory deletes the sessions and long-term memories past the
+// configured retention. Stores that expire data natively are skipped. It is
+// meant to run periodically from a background job and reports metrics for
+// every run.
+func (c *Client) PruneMemory(ctx context.Context) (MemoryPruneResult, error) {
+	var (
+		result MemoryPruneResult
+		errs   []error
+	)
+	now := time.Now()
+	if c.sessionMemory != nil && c.memoryRetention.SessionIdle > 0 {
+		pruned, err := c.sessionMemory.Prune(ctx, now.Add(-c.memoryRetention.SessionIdle))
+		metrics.RecordMemoryPrune("session", pruned, err)
+		result.Sessions = pruned
+		errs = append(errs, err)
+	}
+	if c.longTermMemory != nil && c.memoryRetention.VectorAge > 0 {
+		pruned, err := c.longTermMemory.Prune(ctx, now.Add(-c.memoryRetention.VectorAge))
+		metrics.RecordMemoryPrune("vector", pruned, err)
+		result.Vectors = pruned
+		errs = append(errs, err)
+	}
+	return result, stderrors.Join(errs...)
+}
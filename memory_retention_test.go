@@ -0,0 +1,49 @@
+package llmux
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPruneMemory_DeletesExpiredMemory(t *testing.T) {
+	sessions := NewMemorySessionStore(0)
+	vectors := NewMemoryVectorStore()
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"embed"}}, []string{"embed"}),
+		withTestPricing(t, "embed"),
+		WithSessionMemory(NewSessionMemory(sessions, SessionWindow{})),
+		WithLongTermMemory(LongTermMemoryConfig{Store: vectors, EmbeddingModel: "embed"}),
+		WithMemoryRetention(MemoryRetention{SessionIdle: time.Hour, VectorAge: 24 * time.Hour}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	old := time.Now().Add(-48 * time.Hour)
+	if err := sessions.AppendTurn(ctx, "idle", SessionTurn{Messages: []ChatMessage{{Role: "user"}}, CreatedAt: old}); err != nil {
+		t.Fatalf("AppendTurn() error = %v", err)
+	}
+	if err := sessions.AppendTurn(ctx, "active", SessionTurn{Messages: []ChatMessage{{Role: "user"}}, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AppendTurn() error = %v", err)
+	}
+	if err := vectors.Upsert(ctx, []MemoryRecord{
+		{ID: "old", Namespace: "ns", Text: "old", Vector: []float64{1}, CreatedAt: old},
+		{ID: "new", Namespace: "ns", Text: "new", Vector: []float64{1}, CreatedAt: time.Now()},
+	}); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	result, err := client.PruneMemory(ctx)
+	if err != nil {
+		t.Fatalf("PruneMemory() error = %v", err)
+	}
+	if result.Sessions != 1 || result.Vectors != 1 {
+		t.Fatalf("PruneMemory() = %+v, want one session and one vector", result)
+	}
+	if turns, _ := sessions.Turns(ctx, "active", 0); len(turns) != 1 {
+		t.Fatalf("active session turns = %d, want 1", len(turns))
+	}
+}
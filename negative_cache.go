@@ -0,0 +1,119 @@
+package llmux
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/metrics"
+	"github.com/blueberrycongee/llmux/pkg/errors"
+)
+
+// maxNegativeCacheEntries bounds the number of remembered errors.
+const maxNegativeCacheEntries = 10000
+
+// negativeCacheLabel is the cache_type label for negative cache metrics.
+const negativeCacheLabel = "negative"
+
+// negativeCache remembers deterministic client errors (invalid request,
+// model not found, context too long) per request hash for a short TTL, so
+// identical retries from misconfigured clients fail fast without reaching
+// the router or providers.
+type negativeCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]negativeEntry
+}
+
+type negativeEntry struct {
+	err       errors.LLMError
+	expiresAt time.Time
+}
+
+func newNegativeCache(ttl time.Duration) *negativeCache {
+	return &negativeCache{ttl: ttl, entries: make(map[string]negativeEntry)}
+}
+
+func (n *negativeCache) get(key string, now time.Time) (*errors.LLMError, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	entry, ok := n.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !now.Before(entry.expiresAt) {
+		delete(n.entries, key)
+		return nil, false
+	}
+	err := entry.err
+	return &err, true
+}
+
+func (n *negativeCache) put(key string, err *errors.LLMError, now time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.entries) >= maxNegativeCacheEntries {
+		for k, entry := range n.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(n.entries, k)
+			}
+		}
+		if len(n.entries) >= maxNegativeCacheEntries {
+			clear(n.entries)
+		}
+	}
+	n.entries[key] = negativeEntry{err: *err, expiresAt: now.Add(n.ttl)}
+}
+
+// isDeterministicClientError reports whether err will recur for an identical request.
+func isDeterministicClientError(err error) (*errors.LLMError, bool) {
+	var llmErr *errors.LLMError
+	if !stderrors.As(err, &llmErr) || llmErr.Retryable {
+		return nil, false
+	}
+	if llmErr.StatusCode < 400 || llmErr.StatusCode >= 500 {
+		return nil, false
+	}
+	switch llmErr.Type {
+	case errors.TypeInvalidRequest, errors.TypeNotFound, errors.TypeContextLength:
+		return llmErr, true
+	default:
+		return nil, false
+	}
+}
+
+// cachedClientError returns a remembered deterministic error for req.
+func (c *Client) cachedClientError(ctx context.Context, req *ChatRequest) error {
+	if c.negativeCache == nil {
+		return nil
+	}
+	key, err := c.generateCacheKey(ctx, req)
+	if err != nil {
+		return nil
+	}
+	llmErr, ok := c.negativeCache.get(key, time.Now())
+	if !ok {
+		return nil
+	}
+	metrics.CacheHits.WithLabelValues(negativeCacheLabel, req.Model).Inc()
+	c.logger.Debug("negative cache hit", "model", req.Model, "error_type", llmErr.Type)
+	return llmErr
+}
+
+// rememberClientError records err for req when it is a deterministic client error.
+func (c *Client) rememberClientError(ctx context.Context, req *ChatRequest, err error) {
+	if c.negativeCache == nil {
+		return
+	}
+	llmErr, ok := isDeterministicClientError(err)
+	if !ok {
+		return
+	}
+	key, keyErr := c.generateCacheKey(ctx, req)
+	if keyErr != nil {
+		return
+	}
+	c.negativeCache.put(key, llmErr, time.Now())
+}
@@ -0,0 +1,81 @@
+package llmux
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// invalidRequestProvider maps every upstream error to a 400 invalid request.
+type invalidRequestProvider struct {
+	httpMockProvider
+}
+
+func (p *invalidRequestProvider) MapError(statusCode int, body []byte) error {
+	return NewInvalidRequestError(p.name, "", "context too long")
+}
+
+func TestNegativeCache_FailsFastOnRepeatedClientError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, `{"error":{"message":"context too long"}}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	prov := &invalidRequestProvider{httpMockProvider{name: "mock", models: []string{"m"}, baseURL: server.URL}}
+	client, err := New(
+		WithProviderInstance("mock", prov, []string{"m"}),
+		withTestPricing(t, "m"),
+		WithNegativeCache(time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		_, err := client.ChatCompletion(context.Background(), cacheControlRequest())
+		llmErr, ok := err.(*LLMError)
+		if !ok || llmErr.StatusCode != http.StatusBadRequest {
+			t.Fatalf("attempt %d: expected 400 LLMError, got %v", i, err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("upstream calls = %d, want 1", got)
+	}
+
+	other := cacheControlRequest()
+	other.MaxTokens = 10
+	if _, err := client.ChatCompletion(context.Background(), other); err == nil {
+		t.Fatal("expected error for different request")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("upstream calls = %d, want 2 after a different request", got)
+	}
+}
+
+func TestNegativeCache_IgnoresTransientErrors(t *testing.T) {
+	if _, ok := isDeterministicClientError(NewRateLimitError("p", "m", "slow down")); ok {
+		t.Fatal("rate limit errors must not be negatively cached")
+	}
+	if _, ok := isDeterministicClientError(NewServiceUnavailableError("p", "m", "down")); ok {
+		t.Fatal("server errors must not be negatively cached")
+	}
+	if _, ok := isDeterministicClientError(NewNotFoundError("p", "m", "model not found")); !ok {
+		t.Fatal("model not found should be negatively cached")
+	}
+
+	cache := newNegativeCache(time.Second)
+	now := time.Now()
+	cache.put("k", NewInvalidRequestError("p", "m", "bad"), now)
+	if _, ok := cache.get("k", now.Add(500*time.Millisecond)); !ok {
+		t.Fatal("expected entry before expiry")
+	}
+	if _, ok := cache.get("k", now.Add(2*time.Second)); ok {
+		t.Fatal("expected entry to expire")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/blueberrycongee/llmux/internal/observability"
 	"github.com/blueberrycongee/llmux/internal/plugin"
 	"github.com/blueberrycongee/llmux/internal/resilience"
+	"github.com/blueberrycongee/llmux/pkg/pricing"
 	"github.com/blueberrycongee/llmux/pkg/router"
 )
 
@@ -23,6 +24,11 @@ const (
 	RateLimitKeyByModel RateLimitKeyStrategy = "model"
 	// RateLimitKeyByAPIKeyAndModel uses both API key and model as the rate limit key.
 	RateLimitKeyByAPIKeyAndModel RateLimitKeyStrategy = "api_key_model" // #nosec G101 -- identifier value, not a credential.
+	// RateLimitKeyByEndUser limits each end user, named by the request's user
+	// field, within an API key, on top of the API key's own limit, so one end
+	// user cannot use up the tenant's quota and rotating user values cannot
+	// exceed it.
+	RateLimitKeyByEndUser RateLimitKeyStrategy = "end_user"
 )
 
 // RateLimiterConfig holds configuration for rate limiting.
@@ -33,12 +39,20 @@ type RateLimiterConfig struct {
 	RPMLimit int64
 	// TPMLimit is the tokens per minute limit.
 	TPMLimit int64
+	// EndUserRPMLimit and EndUserTPMLimit are the limits of each end user
+	// under RateLimitKeyByEndUser (0 = RPMLimit and TPMLimit).
+	EndUserRPMLimit int64
+	EndUserTPMLimit int64
 	// WindowSize is the sliding window duration (default: 1 minute).
 	WindowSize time.Duration
 	// KeyStrategy defines how to derive the rate limit key.
 	KeyStrategy RateLimitKeyStrategy
 	// FailOpen allows requests when the rate limiter backend fails.
 	FailOpen bool
+	// PriorityReservations sizes, as a share of the RPM and TPM limits, a
+	// reserve pool per request priority. Requests the regular limits deny
+	// borrow from their priority's pool; priorities without one get 429.
+	PriorityReservations map[Priority]float64
 }
 
 // FallbackReporter receives fallback outcomes for observability.
@@ -53,17 +67,32 @@ type ClientConfig struct {
 	ProviderInstances []providerInstance
 
 	// Routing
-	RouterStrategy   Strategy
-	Router           Router // Custom router (overrides RouterStrategy)
-	FallbackEnabled  bool
-	RetryCount       int
-	RetryBackoff     time.Duration
-	RetryMaxBackoff  time.Duration
-	RetryJitter      float64
-	CooldownPeriod   time.Duration
-	EWMAAlpha        float64
-	DefaultProvider  string
-	FallbackReporter FallbackReporter
+	RouterStrategy  Strategy
+	Router          Router // Custom router (overrides RouterStrategy)
+	FallbackEnabled bool
+	RetryCount      int
+	RetryBackoff    time.Duration
+	RetryMaxBackoff time.Duration
+	RetryJitter     float64
+	CooldownPeriod  time.Duration
+	// CooldownMaxPeriod caps exponential cooldown growth (0 = router default).
+	CooldownMaxPeriod time.Duration
+	// HalfOpenSuccesses is the number of consecutive successful probes needed
+	// to reopen a deployment after cooldown (0 = router default).
+	HalfOpenSuccesses int
+	EWMAAlpha         float64
+	DefaultProvider   string
+	FallbackReporter  FallbackReporter
+	TagFiltering      bool
+	// RouterTraceCapacity is the number of recent requests whose routing
+	// decisions are kept for RoutingExplanation; 0 disables tracing.
+	RouterTraceCapacity int
+	// Speculative enables draft-then-verify routing (see WithSpeculativeRouting).
+	Speculative *SpeculativeConfig
+
+	// VirtualModels maps a virtual model name to its preset (see
+	// WithVirtualModels).
+	VirtualModels map[string]VirtualModel
 
 	// Distributed Routing Stats (for multi-instance deployments)
 	StatsStore router.StatsStore
@@ -76,9 +105,13 @@ type ClientConfig struct {
 	Cache          Cache // Custom cache implementation
 	CacheTTL       time.Duration
 	CacheTypeLabel string
+	// PrefixCacheEnabled turns on the experimental prefix cache (see WithPrefixCache).
+	PrefixCacheEnabled bool
 
 	// HTTP
 	Timeout time.Duration
+	// ModelTimeouts overrides Timeout per model group (see WithModelTimeout).
+	ModelTimeouts map[string]time.Duration
 
 	// Logging
 	Logger *slog.Logger
@@ -87,8 +120,35 @@ type ClientConfig struct {
 	Plugins      []plugin.Plugin
 	PluginConfig *plugin.PipelineConfig
 
+	// NegativeCacheTTL remembers deterministic client errors (invalid
+	// request, model not found, context too long) per request for this long.
+	// Zero disables negative caching.
+	NegativeCacheTTL time.Duration
+
+	// SessionMemory replays conversation history for requests carrying a
+	// session ID (see WithSessionID).
+	SessionMemory *SessionMemory
+
+	// LongTermMemory backs Remember and Recall.
+	LongTermMemory LongTermMemoryConfig
+
+	// MemoryQuotas bounds the session and long-term memory each team holds.
+	MemoryQuotas *MemoryQuotaConfig
+
+	// MemoryRetention bounds how long memory is kept (see PruneMemory).
+	MemoryRetention MemoryRetention
+
+	// Sandbox configures the mock provider that answers sandbox requests
+	// (see WithSandbox).
+	Sandbox SandboxConfig
+
 	// Pricing
-	PricingFile string
+	PricingFile     string
+	PricingFallback PricingFallback
+	// MaxTokensPolicy handles max_tokens above a model's output limit.
+	MaxTokensPolicy MaxTokensPolicy
+	// PricingRegistry replaces the client's own registry (see WithPricingRegistry).
+	PricingRegistry *pricing.Registry
 
 	// Stream recovery
 	StreamRecoveryMode StreamRecoveryMode
@@ -96,12 +156,32 @@ type ClientConfig struct {
 	// Set to 0 to disable the cap (not recommended).
 	StreamRecoveryMaxAccumulatedBytes int
 
+	// StreamIdleTimeout aborts upstream streams that send no data for this
+	// long (see WithStreamIdleTimeout). 0 disables it.
+	StreamIdleTimeout time.Duration
+
+	// StreamTransforms modify or drop streamed chunks (see WithStreamTransform).
+	StreamTransforms []StreamTransform
+
+	// StructuredOutput validates json_schema outputs when set (see
+	// WithStructuredOutputValidation).
+	StructuredOutput *StructuredOutputConfig
+
 	// Observability
 	OTelMetricsConfig observability.OTelMetricsConfig
 
 	// Rate Limiting (Distributed)
 	RateLimiter       resilience.DistributedLimiter
 	RateLimiterConfig RateLimiterConfig
+
+	// CircuitBreaker tunes the per-provider breaker for connection failures
+	// (see WithCircuitBreaker).
+	CircuitBreaker CircuitBreakerConfig
+
+	// Admission queue for provider concurrency (see WithAdmissionQueue).
+	// AdmissionQueueSize of 0 rejects immediately when a provider is saturated.
+	AdmissionQueueSize    int
+	AdmissionQueueTimeout time.Duration
 }
 
 // providerInstance holds a pre-configured provider with its models.
@@ -202,6 +282,9 @@ func WithRouter(r Router) Option {
 //   - StrategyLowestTPMRPM: Select deployment with lowest token/request usage
 //   - StrategyLowestCost: Select deployment with lowest cost
 //   - StrategyTagBased: Filter deployments by tags
+//
+// Custom strategies registered with routers.Register are selected by name,
+// e.g. WithRouterStrategy("my-strategy").
 func WithRouterStrategy(strategy Strategy) Option {
 	return func(c *ClientConfig) {
 		c.RouterStrategy = strategy
@@ -215,6 +298,64 @@ func WithDefaultProvider(provider string) Option {
 	}
 }
 
+// WithTagFiltering applies request tags when routing with any strategy.
+// Deployments are tagged through ProviderConfig.Tags; StrategyTagBased
+// always filters by tags regardless of this option.
+func WithTagFiltering(enabled bool) Option {
+	return func(c *ClientConfig) {
+		c.TagFiltering = enabled
+	}
+}
+
+// WithRouterTracing records why each routed request was sent to its deployment
+// (candidates, filters applied and strategy scores) for the last capacity
+// request IDs. Decisions are retrieved with Client.RoutingExplanation.
+// Tracing allocates on every pick, so enable it only while troubleshooting.
+func WithRouterTracing(capacity int) Option {
+	return func(c *ClientConfig) {
+		c.RouterTraceCapacity = capacity
+	}
+}
+
+// WithSpeculativeRouting sends requests for the configured target models to a
+// cheaper draft model first and escalates to the target only when the
+// verifier rejects the draft.
+//
+// Example:
+//
+//	llmux.WithSpeculativeRouting(llmux.SpeculativeConfig{
+//	    Drafts:   map[string]string{"gpt-4o": "gpt-4o-mini"},
+//	    Verifier: &llmux.LogprobVerifier{MinAvgLogprob: -0.4},
+//	})
+func WithSpeculativeRouting(cfg SpeculativeConfig) Option {
+	return func(c *ClientConfig) {
+		c.Speculative = &cfg
+	}
+}
+
+// WithVirtualModels registers virtual models: chat requests naming one are
+// sent to its target model with the preset's system prompt and parameters.
+// Virtual models cannot target other virtual models.
+//
+// Example:
+//
+//	llmux.WithVirtualModels(llmux.VirtualModel{
+//	    Name:         "support-bot",
+//	    Model:        "gpt-4o-mini",
+//	    SystemPrompt: "You are a friendly support agent.",
+//	    Temperature:  &temperature,
+//	})
+func WithVirtualModels(models ...VirtualModel) Option {
+	return func(c *ClientConfig) {
+		if c.VirtualModels == nil {
+			c.VirtualModels = make(map[string]VirtualModel, len(models))
+		}
+		for _, vm := range models {
+			c.VirtualModels[vm.Name] = vm
+		}
+	}
+}
+
 // WithFallback enables/disables fallback on failure.
 // When enabled, failed requests will be retried on different deployments.
 func WithFallback(enabled bool) Option {
@@ -263,6 +404,35 @@ func WithCooldown(d time.Duration) Option {
 	}
 }
 
+// WithCooldownBackoff caps the exponential cooldown. Each consecutive
+// cooldown of a deployment without recovery doubles the cooldown period
+// until it reaches maxPeriod; a maxPeriod at or below the cooldown period
+// keeps the cooldown fixed.
+func WithCooldownBackoff(maxPeriod time.Duration) Option {
+	return func(c *ClientConfig) {
+		c.CooldownMaxPeriod = maxPeriod
+	}
+}
+
+// WithHalfOpenSuccesses sets how many consecutive successful probe requests a
+// deployment must serve after cooldown before it is fully reopened. Until then
+// it receives a single request at a time.
+func WithHalfOpenSuccesses(n int) Option {
+	return func(c *ClientConfig) {
+		c.HalfOpenSuccesses = n
+	}
+}
+
+// WithCircuitBreaker configures the per-provider circuit breaker. After
+// FailureThreshold consecutive connection failures to a provider, requests to
+// it fail immediately until OpenTimeout elapses. Zero fields keep the defaults;
+// set Disabled to turn the breaker off.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) Option {
+	return func(c *ClientConfig) {
+		c.CircuitBreaker = cfg
+	}
+}
+
 // WithEWMAAlpha sets the smoothing factor for EWMA calculations.
 // alpha should be between 0 and 1. A higher alpha discounts older observations faster.
 func WithEWMAAlpha(alpha float64) Option {
@@ -284,6 +454,60 @@ func WithCache(cache Cache) Option {
 	}
 }
 
+// WithNegativeCache fails identical requests fast with the remembered error
+// for ttl after a deterministic client error. It is independent of the
+// response cache.
+func WithNegativeCache(ttl time.Duration) Option {
+	return func(c *ClientConfig) {
+		c.NegativeCacheTTL = ttl
+	}
+}
+
+// WithSessionMemory enables conversation memory: requests whose context
+// carries a session ID (see WithSessionID) get the session's history
+// prepended, and each completed exchange is appended to the session.
+// The client closes the memory's store on Close.
+func WithSessionMemory(m *SessionMemory) Option {
+	return func(c *ClientConfig) {
+		c.SessionMemory = m
+	}
+}
+
+// WithLongTermMemory enables Remember and Recall: texts are embedded with
+// cfg.EmbeddingModel through the client's Embedding path and persisted in
+// cfg.Store. The client closes the store on Close.
+func WithLongTermMemory(cfg LongTermMemoryConfig) Option {
+	return func(c *ClientConfig) {
+		c.LongTermMemory = cfg
+	}
+}
+
+// WithMemoryQuotas limits the sessions, long-term memories and bytes of
+// text each team (or API key without a team) may store. Writes that would
+// exceed a limit fail with an insufficient quota error.
+func WithMemoryQuotas(cfg MemoryQuotaConfig) Option {
+	return func(c *ClientConfig) {
+		c.MemoryQuotas = &cfg
+	}
+}
+
+// WithMemoryRetention sets how long sessions and long-term memories are
+// kept. Expired memory is deleted by PruneMemory, which the caller runs
+// periodically.
+func WithMemoryRetention(r MemoryRetention) Option {
+	return func(c *ClientConfig) {
+		c.MemoryRetention = r
+	}
+}
+
+// WithSandboxConfig configures the deterministic responses returned to
+// sandbox requests.
+func WithSandboxConfig(cfg SandboxConfig) Option {
+	return func(c *ClientConfig) {
+		c.Sandbox = cfg
+	}
+}
+
 // WithCacheTTL sets the default cache TTL.
 // This is used when no TTL is specified in the cache control.
 func WithCacheTTL(ttl time.Duration) Option {
@@ -299,14 +523,38 @@ func WithCacheTypeLabel(label string) Option {
 	}
 }
 
-// WithTimeout sets the HTTP request timeout.
-// This applies to all provider API calls.
+// WithPrefixCache enables the experimental prefix cache for non-streaming requests.
+// Prompts are normalized (whitespace collapsed) so near-identical requests share a
+// cached response, and the system/few-shot prefix is tracked so repeated prefixes
+// carry a prompt_cache_key hint to providers that support one. Estimated savings
+// per team are available from Client.PrefixCacheSavings.
+func WithPrefixCache(enabled bool) Option {
+	return func(c *ClientConfig) {
+		c.PrefixCacheEnabled = enabled
+	}
+}
+
+// WithTimeout sets the default upstream request timeout. For streams it
+// bounds only the wait for the first response bytes.
+// Provider, model and API key timeouts override it (see WithModelTimeout).
 func WithTimeout(d time.Duration) Option {
 	return func(c *ClientConfig) {
 		c.Timeout = d
 	}
 }
 
+// WithModelTimeout sets the upstream request timeout for a model group, e.g.
+// a longer one for reasoning models. It overrides the provider's Timeout;
+// the API key's "timeout" metadata and WithRequestTimeout override it.
+func WithModelTimeout(model string, d time.Duration) Option {
+	return func(c *ClientConfig) {
+		if c.ModelTimeouts == nil {
+			c.ModelTimeouts = make(map[string]time.Duration)
+		}
+		c.ModelTimeouts[model] = d
+	}
+}
+
 // WithLogger sets the logger for the client.
 // The logger is used for debug, info, and error messages.
 func WithLogger(logger *slog.Logger) Option {
@@ -330,13 +578,42 @@ func WithPluginConfig(config plugin.PipelineConfig) Option {
 	}
 }
 
-// WithPricingFile sets the path to the custom pricing JSON file.
+// WithPricingFile sets the path to the custom pricing JSON file. An
+// https:// URL is fetched once when the client is created; refresh it with a
+// pricing.RemoteSource on a registry passed to WithPricingRegistry.
 func WithPricingFile(path string) Option {
 	return func(c *ClientConfig) {
 		c.PricingFile = path
 	}
 }
 
+// WithPricingRegistry makes the client price requests and cost-based routing
+// from reg instead of a registry of its own, so clients rebuilt on config
+// reload keep runtime price overrides and remotely refreshed prices.
+// PricingFile is still loaded into reg.
+func WithPricingRegistry(reg *pricing.Registry) Option {
+	return func(c *ClientConfig) {
+		c.PricingRegistry = reg
+	}
+}
+
+// WithPricingFallback sets how requests for models without pricing are
+// handled. By default they are rejected.
+func WithPricingFallback(fallback PricingFallback) Option {
+	return func(c *ClientConfig) {
+		c.PricingFallback = fallback
+	}
+}
+
+// WithMaxTokensPolicy sets how requests whose max_tokens exceed the output
+// limit of the model in the pricing data are handled. By default max_tokens
+// is clamped to the limit.
+func WithMaxTokensPolicy(policy MaxTokensPolicy) Option {
+	return func(c *ClientConfig) {
+		c.MaxTokensPolicy = policy
+	}
+}
+
 // WithOTelMetrics configures OpenTelemetry metrics.
 func WithOTelMetrics(config observability.OTelMetricsConfig) Option {
 	return func(c *ClientConfig) {
@@ -413,3 +690,53 @@ func WithStreamRecoveryMaxAccumulatedBytes(maxBytes int) Option {
 		c.StreamRecoveryMaxAccumulatedBytes = maxBytes
 	}
 }
+
+// WithStreamIdleTimeout aborts upstream streams that send no data for d,
+// including while waiting for the first chunk. The stream then fails with a
+// retryable timeout error and is recovered per the stream recovery mode.
+// A value of 0 disables the timeout.
+func WithStreamIdleTimeout(d time.Duration) Option {
+	return func(c *ClientConfig) {
+		c.StreamIdleTimeout = d
+	}
+}
+
+// StreamTransform modifies a streamed chunk before StreamReader.Recv returns
+// it. Returning nil drops the chunk.
+type StreamTransform func(*StreamChunk) *StreamChunk
+
+// WithStreamTransform appends a transform applied to every chunk of every
+// stream, after plugins and in the order added. Transforms run on the
+// goroutine calling Recv and must not retain the chunk.
+//
+// Example:
+//
+//	llmux.WithStreamTransform(func(c *llmux.StreamChunk) *llmux.StreamChunk {
+//	    for i := range c.Choices {
+//	        c.Choices[i].Delta.Content = strings.ReplaceAll(c.Choices[i].Delta.Content, "internal-host", "[host]")
+//	    }
+//	    return c
+//	})
+func WithStreamTransform(transform StreamTransform) Option {
+	return func(c *ClientConfig) {
+		if transform != nil {
+			c.StreamTransforms = append(c.StreamTransforms, transform)
+		}
+	}
+}
+
+// WithAdmissionQueue queues requests that hit a provider's max_concurrent limit
+// instead of rejecting them immediately. Up to size requests wait per provider,
+// ordered by Priority; once full, lower-priority requests are shed with a rate
+// limit error. timeout bounds the wait (0 = until the request context ends).
+//
+// Example:
+//
+//	llmux.WithAdmissionQueue(100, 5*time.Second)
+//	ctx = llmux.WithRequestPriority(ctx, llmux.PriorityHigh)
+func WithAdmissionQueue(size int, timeout time.Duration) Option {
+	return func(c *ClientConfig) {
+		c.AdmissionQueueSize = size
+		c.AdmissionQueueTimeout = timeout
+	}
+}
@@ -0,0 +1,504 @@
+package llmux
+
+import (
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// PartialJSONStream turns the content deltas of a structured-output stream
+// into JSON merge patches (RFC 7396), so clients can render the document
+// progressively instead of re-parsing raw text on every delta.
+//
+// Each patch applied in order to an empty document yields the best-effort
+// parse of all content seen so far, as CompletePartialJSON would close it.
+// Objects are diffed key by key; arrays, strings and other scalars are
+// replaced as a whole. Content is parsed incrementally, so each Push only
+// scans its delta and visits the values that changed.
+type PartialJSONStream struct {
+	buf    strings.Builder
+	parser partialJSONParser
+}
+
+// NewPartialJSONStream returns an empty partial JSON stream.
+func NewPartialJSONStream() *PartialJSONStream {
+	return &PartialJSONStream{}
+}
+
+// Push appends a content delta and returns the merge patch from the previous
+// document to the current one. It returns false when the accumulated content
+// does not yet parse or the document did not change.
+func (p *PartialJSONStream) Push(delta string) (json.RawMessage, bool) {
+	p.buf.WriteString(delta)
+	patch, ok := p.parser.push(p.buf.String())
+	if !ok {
+		return nil, false
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Content returns the raw content accumulated so far.
+func (p *PartialJSONStream) Content() string {
+	return p.buf.String()
+}
+
+// partialNode is a value of a streamed document. Objects record the keys
+// changed since the last patch and arrays whether they changed at all;
+// strings and other scalars live in scalar.
+type partialNode struct {
+	kind    byte // '{', '[' or 0 for scalars
+	fields  map[string]*partialNode
+	items   []*partialNode
+	scalar  any
+	changed map[string]struct{}
+	dirty   bool
+	emitted bool
+}
+
+// patch returns the merge patch from the last emitted state of n to its
+// current one. Objects emitted before are patched by changed key; anything
+// else is replaced whole.
+func (n *partialNode) patch() any {
+	if n.kind != '{' || !n.emitted {
+		return n.value()
+	}
+	patch := make(map[string]any, len(n.changed))
+	for key := range n.changed {
+		patch[key] = n.fields[key].patch()
+	}
+	clear(n.changed)
+	return patch
+}
+
+// value returns n as a decoded JSON value and marks it emitted.
+func (n *partialNode) value() any {
+	n.emitted, n.dirty = true, false
+	switch n.kind {
+	case '{':
+		clear(n.changed)
+		obj := make(map[string]any, len(n.fields))
+		for key, field := range n.fields {
+			obj[key] = field.value()
+		}
+		return obj
+	case '[':
+		arr := make([]any, len(n.items))
+		for i, item := range n.items {
+			arr[i] = item.value()
+		}
+		return arr
+	}
+	return n.scalar
+}
+
+// partialContainer is an open object or array. expect is the next token:
+// 'k' a key, ':' a colon, 'v' a value or ',' a comma or the closer.
+type partialContainer struct {
+	node   *partialNode
+	key    string
+	expect byte
+	empty  bool
+}
+
+// partialJSONParser parses a growing JSON document into partialNodes,
+// resuming where the previous push stopped.
+type partialJSONParser struct {
+	pos     int
+	stack   []*partialContainer
+	root    *partialNode
+	done    bool // the root value is complete
+	failed  bool // the content is not a JSON prefix
+	changed bool // the document changed since the last patch
+
+	inString    bool
+	stringIsKey bool
+	stringStart int
+	escaped     bool
+	unicodeAt   int
+	unicodeLeft int
+	str         *partialNode // open string value
+
+	inScalar    bool
+	scalarStart int
+	scalar      *partialNode // open scalar, once a prefix of it parses
+}
+
+// push scans s from where the previous push stopped and returns the merge
+// patch for the changes, or false when there are none.
+func (p *partialJSONParser) push(s string) (any, bool) {
+	for ; p.pos < len(s) && !p.failed; p.pos++ {
+		p.scan(s, p.pos)
+	}
+	if !p.failed {
+		p.showOpenValue(s)
+	}
+	if p.failed || !p.changed {
+		return nil, false
+	}
+	p.changed = false
+	return p.root.patch(), true
+}
+
+func (p *partialJSONParser) scan(s string, i int) {
+	ch := s[i]
+	if p.inString {
+		switch {
+		case p.unicodeLeft > 0:
+			p.unicodeLeft--
+		case p.escaped:
+			p.escaped = false
+			if ch == 'u' {
+				p.unicodeAt, p.unicodeLeft = i-1, 4
+			}
+		case ch == '\\':
+			p.escaped = true
+		case ch == '"':
+			p.inString = false
+			p.endString(s[p.stringStart:i])
+		}
+		return
+	}
+	if p.inScalar {
+		if !isJSONDelimiter(ch) {
+			return
+		}
+		p.inScalar = false
+		p.endScalar(s[p.scalarStart:i])
+		if p.failed {
+			return
+		}
+	}
+
+	switch ch {
+	case ' ', '\t', '\n', '\r':
+	case '{', '[':
+		if !p.startValue() {
+			return
+		}
+		node := &partialNode{kind: ch}
+		expect := byte('v')
+		if ch == '{' {
+			node.fields = make(map[string]*partialNode)
+			node.changed = make(map[string]struct{})
+			expect = 'k'
+		}
+		p.attach(node)
+		p.stack = append(p.stack, &partialContainer{node: node, expect: expect, empty: true})
+	case '}', ']':
+		top := p.top()
+		if top == nil || (ch == '}') != (top.node.kind == '{') || (top.expect != ',' && !top.empty) {
+			p.failed = true
+			return
+		}
+		p.stack = p.stack[:len(p.stack)-1]
+		p.endValue()
+	case ',':
+		top := p.top()
+		if top == nil || top.expect != ',' {
+			p.failed = true
+			return
+		}
+		top.expect = 'v'
+		if top.node.kind == '{' {
+			top.expect = 'k'
+		}
+	case ':':
+		top := p.top()
+		if top == nil || top.expect != ':' {
+			p.failed = true
+			return
+		}
+		top.expect = 'v'
+	case '"':
+		p.inString, p.escaped, p.unicodeLeft = true, false, 0
+		p.stringStart = i + 1
+		if top := p.top(); top != nil && top.expect == 'k' {
+			p.stringIsKey, top.empty = true, false
+			return
+		}
+		p.stringIsKey = false
+		if p.startValue() {
+			p.str = &partialNode{scalar: ""}
+			p.attach(p.str)
+		}
+	default:
+		if p.startValue() {
+			p.inScalar, p.scalarStart = true, i
+		}
+	}
+}
+
+// showOpenValue updates the value still being scanned at the end of s: an
+// open string shows its complete characters and a number cut mid-token
+// ("12.", "1e") its parsed prefix.
+func (p *partialJSONParser) showOpenValue(s string) {
+	switch {
+	case p.inString && !p.stringIsKey:
+		end := len(s)
+		if p.unicodeLeft > 0 {
+			end = p.unicodeAt
+		} else if p.escaped {
+			end--
+		}
+		var v string
+		if err := json.Unmarshal([]byte(`"`+s[p.stringStart:end]+`"`), &v); err != nil {
+			p.failed = true
+			return
+		}
+		p.setScalar(p.str, v)
+	case p.inScalar:
+		var v any
+		if err := json.Unmarshal([]byte(strings.TrimRight(s[p.scalarStart:], ".eE+-")), &v); err == nil {
+			p.showScalar(v)
+		}
+	}
+}
+
+func (p *partialJSONParser) endString(raw string) {
+	var v string
+	if err := json.Unmarshal([]byte(`"`+raw+`"`), &v); err != nil {
+		p.failed = true
+		return
+	}
+	if p.stringIsKey {
+		top := p.top()
+		top.key, top.expect = v, ':'
+		return
+	}
+	p.setScalar(p.str, v)
+	p.str = nil
+	p.endValue()
+}
+
+func (p *partialJSONParser) endScalar(token string) {
+	var v any
+	if err := json.Unmarshal([]byte(token), &v); err != nil {
+		p.failed = true
+		return
+	}
+	p.showScalar(v)
+	p.scalar = nil
+	p.endValue()
+}
+
+// showScalar attaches the open scalar on first sight, then updates it.
+func (p *partialJSONParser) showScalar(v any) {
+	if p.scalar == nil {
+		p.scalar = &partialNode{scalar: v}
+		p.attach(p.scalar)
+		return
+	}
+	p.setScalar(p.scalar, v)
+}
+
+func (p *partialJSONParser) setScalar(n *partialNode, v any) {
+	if n.scalar != v {
+		n.scalar = v
+		p.touch()
+	}
+}
+
+// startValue reports whether a value may start here, failing the parse
+// when it may not.
+func (p *partialJSONParser) startValue() bool {
+	top := p.top()
+	switch {
+	case top == nil && p.root == nil && !p.done:
+		return true
+	case top != nil && top.expect == 'v':
+		top.empty = false
+		return true
+	}
+	p.failed = true
+	return false
+}
+
+// attach adds n as the value being parsed in the innermost container.
+func (p *partialJSONParser) attach(n *partialNode) {
+	top := p.top()
+	switch {
+	case top == nil:
+		p.root = n
+	case top.node.kind == '{':
+		top.node.fields[top.key] = n
+	default:
+		top.node.items = append(top.node.items, n)
+	}
+	p.touch()
+}
+
+func (p *partialJSONParser) endValue() {
+	if top := p.top(); top != nil {
+		top.expect = ','
+		return
+	}
+	p.done = true
+}
+
+// touch records a change to the value being parsed on each open container,
+// stopping at the first one that already has it recorded.
+func (p *partialJSONParser) touch() {
+	p.changed = true
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		c := p.stack[i]
+		if c.node.kind == '[' {
+			if c.node.dirty {
+				return
+			}
+			c.node.dirty = true
+			continue
+		}
+		if _, ok := c.node.changed[c.key]; ok {
+			return
+		}
+		c.node.changed[c.key] = struct{}{}
+	}
+}
+
+func (p *partialJSONParser) top() *partialContainer {
+	if len(p.stack) == 0 {
+		return nil
+	}
+	return p.stack[len(p.stack)-1]
+}
+
+func isJSONDelimiter(ch byte) bool {
+	switch ch {
+	case ' ', '\t', '\n', '\r', ',', ':', '{', '}', '[', ']', '"':
+		return true
+	}
+	return false
+}
+
+// partialJSONFrame is an open object or array while scanning partial JSON.
+type partialJSONFrame struct {
+	closer  byte
+	keyNext bool
+}
+
+// CompletePartialJSON closes a truncated JSON document into the longest
+// well-formed prefix: open strings in value position are terminated, open
+// objects and arrays are closed, and dangling keys, separators or partial
+// literals are dropped. It returns false when no well-formed prefix exists.
+func CompletePartialJSON(s string) (string, bool) {
+	var (
+		stack     []partialJSONFrame
+		safeLen   = -1
+		safeStack []partialJSONFrame
+
+		inString    bool
+		stringIsKey bool
+		escaped     bool
+		unicodeAt   = -1
+		unicodeLeft int
+
+		scalarStart = -1
+	)
+	markSafe := func(n int) {
+		safeLen = n
+		safeStack = append(safeStack[:0], stack...)
+	}
+	endScalar := func(end int) {
+		if scalarStart < 0 {
+			return
+		}
+		if end == len(s) {
+			// A number cut mid-token ("12.", "1e") keeps its parsed prefix.
+			end = scalarStart + len(strings.TrimRight(s[scalarStart:end], ".eE+-"))
+		}
+		if end > scalarStart && json.Valid([]byte(s[scalarStart:end])) {
+			markSafe(end)
+		}
+		scalarStart = -1
+	}
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if inString {
+			switch {
+			case unicodeLeft > 0:
+				unicodeLeft--
+			case escaped:
+				escaped = false
+				if ch == 'u' {
+					unicodeAt, unicodeLeft = i-1, 4
+				}
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+				if !stringIsKey {
+					markSafe(i + 1)
+				}
+			}
+			continue
+		}
+
+		switch ch {
+		case ' ', '\t', '\n', '\r':
+			endScalar(i)
+		case '{', '[':
+			endScalar(i)
+			closer := byte('}')
+			if ch == '[' {
+				closer = ']'
+			}
+			stack = append(stack, partialJSONFrame{closer: closer, keyNext: ch == '{'})
+			markSafe(i + 1)
+		case '}', ']':
+			endScalar(i)
+			if len(stack) == 0 || stack[len(stack)-1].closer != ch {
+				return "", false
+			}
+			stack = stack[:len(stack)-1]
+			markSafe(i + 1)
+		case ',':
+			endScalar(i)
+			if len(stack) > 0 && stack[len(stack)-1].closer == '}' {
+				stack[len(stack)-1].keyNext = true
+			}
+		case ':':
+			endScalar(i)
+		case '"':
+			endScalar(i)
+			inString, escaped, unicodeLeft = true, false, 0
+			stringIsKey = len(stack) > 0 && stack[len(stack)-1].keyNext
+			if stringIsKey {
+				stack[len(stack)-1].keyNext = false
+			}
+		default:
+			if scalarStart < 0 {
+				scalarStart = i
+			}
+		}
+	}
+	endScalar(len(s))
+
+	var b strings.Builder
+	frames := safeStack
+	switch {
+	case inString && !stringIsKey:
+		body := s
+		if unicodeLeft > 0 {
+			body = s[:unicodeAt]
+		} else if escaped {
+			body = s[:len(s)-1]
+		}
+		b.WriteString(body)
+		b.WriteByte('"')
+		frames = stack
+	case safeLen >= 0:
+		b.WriteString(s[:safeLen])
+	default:
+		return "", false
+	}
+	for i := len(frames) - 1; i >= 0; i-- {
+		b.WriteByte(frames[i].closer)
+	}
+	completed := b.String()
+	return completed, json.Valid([]byte(completed))
+}
@@ -0,0 +1,139 @@
+package llmux
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func TestCompletePartialJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{in: "", ok: false},
+		{in: "{", want: "{}", ok: true},
+		{in: `{"na`, want: "{}", ok: true},
+		{in: `{"name":`, want: "{}", ok: true},
+		{in: `{"name":"Ad`, want: `{"name":"Ad"}`, ok: true},
+		{in: `{"name":"a\`, want: `{"name":"a"}`, ok: true},
+		{in: `{"name":"a\u00`, want: `{"name":"a"}`, ok: true},
+		{in: `{"n":12.`, want: `{"n":12}`, ok: true},
+		{in: `{"ok":tr`, want: "{}", ok: true},
+		{in: `{"tags":["a","b`, want: `{"tags":["a","b"]}`, ok: true},
+		{in: `{"tags":["a",`, want: `{"tags":["a"]}`, ok: true},
+		{in: `{"a":{"b":[1,{"c":true}`, want: `{"a":{"b":[1,{"c":true}]}}`, ok: true},
+		{in: `{"a":1}`, want: `{"a":1}`, ok: true},
+		{in: `{"a":1]`, ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := CompletePartialJSON(tt.in)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("CompletePartialJSON(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPartialJSONStream_MergePatches(t *testing.T) {
+	stream := NewPartialJSONStream()
+	deltas := []string{`{"title":"He`, `llo","items":[`, `1,`, `2]`, `,"done":true}`}
+
+	var doc any
+	var patches []string
+	for _, delta := range deltas {
+		patch, ok := stream.Push(delta)
+		if !ok {
+			continue
+		}
+		patches = append(patches, string(patch))
+		var p any
+		if err := json.Unmarshal(patch, &p); err != nil {
+			t.Fatalf("invalid patch %q: %v", patch, err)
+		}
+		doc = applyMergePatch(doc, p)
+	}
+
+	want := []string{`{"title":"He"}`, `{"items":[],"title":"Hello"}`, `{"items":[1]}`, `{"items":[1,2]}`, `{"done":true}`}
+	if len(patches) != len(want) {
+		t.Fatalf("patches = %v, want %v", patches, want)
+	}
+	for i := range want {
+		if patches[i] != want[i] {
+			t.Fatalf("patch %d = %s, want %s", i, patches[i], want[i])
+		}
+	}
+
+	got, _ := json.Marshal(doc)
+	if string(got) != `{"done":true,"items":[1,2],"title":"Hello"}` {
+		t.Fatalf("document = %s", got)
+	}
+	if _, ok := stream.Push(" "); ok {
+		t.Fatal("expected no patch for whitespace delta")
+	}
+}
+
+func TestPartialJSONStream_MatchesCompletePartialJSON(t *testing.T) {
+	content := `{"title":"caf\u00e9 \ud83d\ude00","n":-12.5e3,"ok":true,` +
+		`"items":[{"id":1,"tags":["a","b\"c"]},{"id":2,"nested":{"x":[]}}],"empty":{},"last":"done"}`
+
+	for _, size := range []int{1, 3, 7, len(content)} {
+		stream := NewPartialJSONStream()
+		var doc any
+		for start := 0; start < len(content); start += size {
+			end := min(start+size, len(content))
+			if patch, ok := stream.Push(content[start:end]); ok {
+				var p any
+				if err := json.Unmarshal(patch, &p); err != nil {
+					t.Fatalf("invalid patch %q: %v", patch, err)
+				}
+				doc = applyMergePatch(doc, p)
+			}
+
+			completed, ok := CompletePartialJSON(content[:end])
+			if !ok {
+				continue
+			}
+			var want any
+			if err := json.Unmarshal([]byte(completed), &want); err != nil {
+				t.Fatalf("unmarshal %q: %v", completed, err)
+			}
+			if !reflect.DeepEqual(doc, want) {
+				t.Fatalf("chunk size %d, prefix %q: document = %v, want %v", size, content[:end], doc, want)
+			}
+		}
+	}
+}
+
+func TestPartialJSONStream_StopsOnInvalidContent(t *testing.T) {
+	stream := NewPartialJSONStream()
+	if _, ok := stream.Push(`{"a":1`); !ok {
+		t.Fatal("expected a patch for the valid prefix")
+	}
+	for _, delta := range []string{`]`, `}`, `{"b":2}`} {
+		if patch, ok := stream.Push(delta); ok {
+			t.Fatalf("Push(%q) = %s after invalid content", delta, patch)
+		}
+	}
+}
+
+// applyMergePatch applies an RFC 7396 merge patch to target.
+func applyMergePatch(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = map[string]any{}
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = applyMergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
@@ -12,10 +12,12 @@ import (
 type Type string
 
 const (
-	TypeLocal    Type = "local"    // In-memory cache
-	TypeRedis    Type = "redis"    // Redis cache
-	TypeDual     Type = "dual"     // In-memory + Redis dual cache
-	TypeSemantic Type = "semantic" // Semantic cache with vector similarity
+	TypeLocal     Type = "local"     // In-memory cache
+	TypeRedis     Type = "redis"     // Redis cache
+	TypeDual      Type = "dual"      // In-memory + Redis dual cache
+	TypeSemantic  Type = "semantic"  // Semantic cache with vector similarity
+	TypeMemcached Type = "memcached" // Memcached cache
+	TypeDynamoDB  Type = "dynamodb"  // DynamoDB cache
 )
 
 // Cache defines the interface for all cache implementations.
@@ -0,0 +1,246 @@
+// Package encryption provides envelope encryption for data persisted at rest,
+// such as cached responses.
+//
+// Each tenant gets a random AES-256 data key (DEK). The DEK encrypts the
+// payload and is itself wrapped by the master key (KEK); the wrapped DEK is
+// stored alongside every ciphertext, so any instance holding the master key
+// can decrypt without a separate key store. Tenant IDs are bound as
+// additional authenticated data, so a blob cannot be replayed under another
+// tenant.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// KeySize is the master and data key size in bytes (AES-256).
+const KeySize = 32
+
+// maxCachedDataKeys bounds the number of unwrapped data keys kept in memory.
+const maxCachedDataKeys = 4096
+
+var magic = []byte("LXE1")
+
+var (
+	// ErrNotEncrypted is returned by Decrypt for data without the envelope header.
+	ErrNotEncrypted = errors.New("data is not envelope encrypted")
+	// ErrUnknownKey is returned when the data was wrapped by an unknown master key.
+	ErrUnknownKey = errors.New("data encrypted with unknown master key")
+	// ErrDecrypt is returned when authentication of the ciphertext fails.
+	ErrDecrypt = errors.New("failed to decrypt data")
+)
+
+// Keyring encrypts and decrypts data with per-tenant data keys wrapped by a
+// master key. It is safe for concurrent use.
+type Keyring struct {
+	primary kek
+	keks    map[[4]byte]kek
+
+	mu        sync.Mutex
+	dataKeys  map[string]dataKey // tenant -> current data key
+	unwrapped map[string][]byte  // wrapped key -> data key
+}
+
+type kek struct {
+	id   [4]byte
+	aead cipher.AEAD
+}
+
+type dataKey struct {
+	key     []byte
+	wrapped []byte
+}
+
+// NewKeyring creates a keyring that encrypts with primary and can also
+// decrypt data wrapped by any of the previous master keys (for rotation).
+func NewKeyring(primary []byte, previous ...[]byte) (*Keyring, error) {
+	p, err := newKEK(primary)
+	if err != nil {
+		return nil, err
+	}
+	k := &Keyring{
+		primary:   p,
+		keks:      map[[4]byte]kek{p.id: p},
+		dataKeys:  make(map[string]dataKey),
+		unwrapped: make(map[string][]byte),
+	}
+	for _, key := range previous {
+		prev, err := newKEK(key)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := k.keks[prev.id]; !exists {
+			k.keks[prev.id] = prev
+		}
+	}
+	return k, nil
+}
+
+// ParseKey decodes a base64-encoded (standard or URL alphabet) 32-byte
+// master key, e.g. the output of "openssl rand -base64 32".
+func ParseKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(s); err == nil && len(key) == KeySize {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("master key must be %d bytes, base64 encoded", KeySize)
+}
+
+func newKEK(key []byte) (kek, error) {
+	if len(key) != KeySize {
+		return kek{}, fmt.Errorf("master key must be %d bytes, got %d", KeySize, len(key))
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return kek{}, err
+	}
+	sum := sha256.Sum256(key)
+	var id [4]byte
+	copy(id[:], sum[:4])
+	return kek{id: id, aead: aead}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt seals plaintext with the tenant's data key.
+//
+// Layout: magic | kek id (4) | wrapped key len (2) | wrapped key | nonce | ciphertext.
+func (k *Keyring) Encrypt(tenant string, plaintext []byte) ([]byte, error) {
+	dk, err := k.dataKeyFor(tenant)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dk.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(magic)+4+2+len(dk.wrapped)+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, magic...)
+	out = append(out, k.primary.id[:]...)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(dk.wrapped))) // #nosec G115 -- wrapped keys are 60 bytes.
+	out = append(out, dk.wrapped...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, payloadAAD(tenant)), nil
+}
+
+// Decrypt opens data produced by Encrypt for the same tenant.
+func (k *Keyring) Decrypt(tenant string, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, ErrNotEncrypted
+	}
+	rest := data[len(magic):]
+	if len(rest) < 6 {
+		return nil, ErrDecrypt
+	}
+	var id [4]byte
+	copy(id[:], rest[:4])
+	wrappedLen := int(binary.BigEndian.Uint16(rest[4:6]))
+	rest = rest[6:]
+	if len(rest) < wrappedLen {
+		return nil, ErrDecrypt
+	}
+	wrapped, rest := rest[:wrappedLen], rest[wrappedLen:]
+
+	key, err := k.unwrap(id, tenant, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	nonce, ciphertext := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, payloadAAD(tenant))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// IsEncrypted reports whether data carries the envelope header.
+func IsEncrypted(data []byte) bool {
+	return len(data) >= len(magic) && string(data[:len(magic)]) == string(magic)
+}
+
+func (k *Keyring) dataKeyFor(tenant string) (dataKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if dk, ok := k.dataKeys[tenant]; ok {
+		return dk, nil
+	}
+
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return dataKey{}, err
+	}
+	nonce := make([]byte, k.primary.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return dataKey{}, err
+	}
+	wrapped := k.primary.aead.Seal(append([]byte(nil), nonce...), nonce, key, keyAAD(tenant))
+	dk := dataKey{key: key, wrapped: wrapped}
+	if len(k.dataKeys) >= maxCachedDataKeys {
+		clear(k.dataKeys)
+	}
+	k.dataKeys[tenant] = dk
+	return dk, nil
+}
+
+func (k *Keyring) unwrap(id [4]byte, tenant string, wrapped []byte) ([]byte, error) {
+	cacheKey := tenant + "\x00" + string(wrapped)
+	k.mu.Lock()
+	if key, ok := k.unwrapped[cacheKey]; ok {
+		k.mu.Unlock()
+		return key, nil
+	}
+	k.mu.Unlock()
+
+	wrapper, ok := k.keks[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	nonceSize := wrapper.aead.NonceSize()
+	if len(wrapped) < nonceSize {
+		return nil, ErrDecrypt
+	}
+	key, err := wrapper.aead.Open(nil, wrapped[:nonceSize], wrapped[nonceSize:], keyAAD(tenant))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	k.mu.Lock()
+	if len(k.unwrapped) >= maxCachedDataKeys {
+		clear(k.unwrapped)
+	}
+	k.unwrapped[cacheKey] = key
+	k.mu.Unlock()
+	return key, nil
+}
+
+func keyAAD(tenant string) []byte     { return []byte("llmux-dek:" + tenant) }
+func payloadAAD(tenant string) []byte { return []byte("llmux-data:" + tenant) }
@@ -0,0 +1,85 @@
+package encryption
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func TestKeyringRoundTrip(t *testing.T) {
+	k, err := NewKeyring(testKey(1))
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+
+	plaintext := []byte(`{"choices":[{"message":{"content":"secret"}}]}`)
+	sealed, err := k.Encrypt("tenant-a", plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("secret")) {
+		t.Fatalf("ciphertext contains plaintext")
+	}
+	if !IsEncrypted(sealed) {
+		t.Fatalf("expected envelope header")
+	}
+
+	got, err := k.Decrypt("tenant-a", sealed)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("Decrypt = %q, want %q", got, plaintext)
+	}
+
+	if _, err := k.Decrypt("tenant-b", sealed); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("cross-tenant decrypt: got %v, want ErrDecrypt", err)
+	}
+	if _, err := k.Decrypt("tenant-a", plaintext); !errors.Is(err, ErrNotEncrypted) {
+		t.Fatalf("plaintext decrypt: got %v, want ErrNotEncrypted", err)
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	old, err := NewKeyring(testKey(1))
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	sealed, err := old.Encrypt("tenant-a", []byte("hello"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	rotated, err := NewKeyring(testKey(2), testKey(1))
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	if got, err := rotated.Decrypt("tenant-a", sealed); err != nil || string(got) != "hello" {
+		t.Fatalf("Decrypt with previous key = %q, %v", got, err)
+	}
+
+	fresh, err := NewKeyring(testKey(2))
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	if _, err := fresh.Decrypt("tenant-a", sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Decrypt without previous key: got %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	if _, err := ParseKey("c2hvcnQ="); err == nil {
+		t.Fatalf("expected error for short key")
+	}
+	key, err := ParseKey("BwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwc=")
+	if err != nil {
+		t.Fatalf("ParseKey: %v", err)
+	}
+	if !bytes.Equal(key, testKey(7)) {
+		t.Fatalf("unexpected key %x", key)
+	}
+}
@@ -5,6 +5,7 @@ package errors //nolint:revive // package name is intentional
 import (
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // LLMError represents a standardized error from an LLM provider.
@@ -15,7 +16,9 @@ type LLMError struct {
 	Type       string `json:"type"`
 	Provider   string `json:"provider"`
 	Model      string `json:"model"`
-	Retryable  bool   `json:"-"`
+	// Code is an optional machine-readable reason, e.g. CodeNoDeploymentsWithTag.
+	Code      string `json:"code,omitempty"`
+	Retryable bool   `json:"-"`
 }
 
 // Error implements the error interface.
@@ -47,6 +50,9 @@ const (
 	TypeInsufficientQuota  = "insufficient_quota"
 )
 
+// CodeNoDeploymentsWithTag marks requests whose tags match no deployment.
+const CodeNoDeploymentsWithTag = "no_deployments_with_tag"
+
 // NewAuthenticationError creates an authentication error (401).
 func NewAuthenticationError(provider, model, message string) *LLMError {
 	return &LLMError{
@@ -107,6 +113,32 @@ func NewInvalidRequestError(provider, model, message string) *LLMError {
 	}
 }
 
+// NewContentPolicyError creates a content policy error (400) for requests or
+// responses rejected by a guardrail or upstream moderation.
+func NewContentPolicyError(provider, model, message string) *LLMError {
+	return &LLMError{
+		StatusCode: http.StatusBadRequest,
+		Message:    message,
+		Type:       TypeContentPolicy,
+		Provider:   provider,
+		Model:      model,
+		Retryable:  false,
+	}
+}
+
+// NewNoDeploymentsWithTagError creates an invalid request error (400) for
+// requests whose tags do not match any deployment of the model.
+func NewNoDeploymentsWithTagError(model string, tags []string) *LLMError {
+	return &LLMError{
+		StatusCode: http.StatusBadRequest,
+		Message:    fmt.Sprintf("no deployments for model %s match tags %s", model, strings.Join(tags, ",")),
+		Type:       TypeInvalidRequest,
+		Model:      model,
+		Code:       CodeNoDeploymentsWithTag,
+		Retryable:  false,
+	}
+}
+
 // NewNotFoundError creates a not found error (404).
 func NewNotFoundError(provider, model, message string) *LLMError {
 	return &LLMError{
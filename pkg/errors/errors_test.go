@@ -66,6 +66,7 @@ func TestLLMError(t *testing.T) {
 			{"rate limit", NewRateLimitError("p", "m", "msg"), 429},
 			{"insufficient quota", NewInsufficientQuotaError("p", "m", "msg"), 402},
 			{"bad request", NewInvalidRequestError("p", "m", "msg"), 400},
+			{"content policy", NewContentPolicyError("p", "m", "msg"), 400},
 			{"not found", NewNotFoundError("p", "m", "msg"), 404},
 			{"timeout", NewTimeoutError("p", "m", "msg"), 408},
 			{"unavailable", NewServiceUnavailableError("p", "m", "msg"), 503},
@@ -98,6 +99,7 @@ func TestLLMError(t *testing.T) {
 			NewAuthenticationError,
 			NewPermissionError,
 			NewInvalidRequestError,
+			NewContentPolicyError,
 			NewInsufficientQuotaError,
 			NewNotFoundError,
 			NewInternalError,
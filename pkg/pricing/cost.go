@@ -0,0 +1,55 @@
+package pricing
+
+// PriceTier prices requests whose prompt is longer than AboveInputTokens, as
+// providers do for long-context requests. The tier rates apply to the whole
+// request; a zero OutputCostPerToken keeps the base output rate.
+type PriceTier struct {
+	AboveInputTokens   int     `json:"above_input_tokens"`
+	InputCostPerToken  float64 `json:"input_cost_per_token"`
+	OutputCostPerToken float64 `json:"output_cost_per_token,omitempty"`
+}
+
+// Usage is what a request consumed, in the units a ModelPrice charges for.
+// ReasoningTokens are the part of OutputTokens spent on reasoning.
+type Usage struct {
+	InputTokens     int
+	OutputTokens    int
+	ReasoningTokens int
+	Images          int
+	AudioSeconds    float64
+}
+
+// Cost returns the cost in USD of u at price p.
+func (p ModelPrice) Cost(u Usage) float64 {
+	input, output := p.TokenRates(u.InputTokens)
+	reasoning := min(max(u.ReasoningTokens, 0), u.OutputTokens)
+	reasoningRate := output
+	if p.OutputCostPerReasoningToken > 0 {
+		reasoningRate = p.OutputCostPerReasoningToken
+	}
+	return float64(u.InputTokens)*input +
+		float64(u.OutputTokens-reasoning)*output +
+		float64(reasoning)*reasoningRate +
+		float64(u.Images)*p.CostPerImage +
+		u.AudioSeconds*p.CostPerAudioSecond +
+		p.CostPerRequest
+}
+
+// TokenRates returns the per-token input and output rates for a request with
+// inputTokens prompt tokens: those of the highest tier it exceeds, or the
+// base rates.
+func (p ModelPrice) TokenRates(inputTokens int) (input, output float64) {
+	input, output = p.InputCostPerToken, p.OutputCostPerToken
+	threshold := -1
+	for _, tier := range p.Tiers {
+		if inputTokens <= tier.AboveInputTokens || tier.AboveInputTokens <= threshold {
+			continue
+		}
+		threshold = tier.AboveInputTokens
+		input, output = tier.InputCostPerToken, p.OutputCostPerToken
+		if tier.OutputCostPerToken > 0 {
+			output = tier.OutputCostPerToken
+		}
+	}
+	return input, output
+}
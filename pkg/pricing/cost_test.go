@@ -0,0 +1,61 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelPrice_Cost(t *testing.T) {
+	price := ModelPrice{
+		InputCostPerToken:  0.001,
+		OutputCostPerToken: 0.002,
+		Tiers: []PriceTier{
+			{AboveInputTokens: 1000, InputCostPerToken: 0.003},
+			{AboveInputTokens: 200, InputCostPerToken: 0.0015, OutputCostPerToken: 0.004},
+		},
+		CostPerRequest:     0.5,
+		CostPerImage:       0.25,
+		CostPerAudioSecond: 0.01,
+	}
+
+	tests := []struct {
+		name  string
+		usage Usage
+		want  float64
+	}{
+		{name: "base rates", usage: Usage{InputTokens: 200, OutputTokens: 10}, want: 0.2 + 0.02 + 0.5},
+		{name: "first tier", usage: Usage{InputTokens: 201, OutputTokens: 10}, want: 0.3015 + 0.04 + 0.5},
+		{name: "highest tier keeps base output rate", usage: Usage{InputTokens: 2000, OutputTokens: 10}, want: 6 + 0.02 + 0.5},
+		{name: "units", usage: Usage{Images: 2, AudioSeconds: 30}, want: 0.5 + 0.3 + 0.5},
+		{name: "reasoning at output rate", usage: Usage{InputTokens: 200, OutputTokens: 10, ReasoningTokens: 6}, want: 0.2 + 0.02 + 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, price.Cost(tt.usage), 1e-12)
+		})
+	}
+
+	// Reasoning tokens are part of the output tokens, billed at their own rate.
+	price.OutputCostPerReasoningToken = 0.003
+	assert.InDelta(t, 0.2+4*0.002+6*0.003+0.5, price.Cost(Usage{InputTokens: 200, OutputTokens: 10, ReasoningTokens: 6}), 1e-12)
+	assert.InDelta(t, 0.2+10*0.003+0.5, price.Cost(Usage{InputTokens: 200, OutputTokens: 10, ReasoningTokens: 50}), 1e-12)
+}
+
+func TestRegistry_LookupDeployment(t *testing.T) {
+	r := NewRegistry()
+
+	r.SetDeploymentPrice("azure-gpt-4o", ModelPrice{InputCostPerToken: 1})
+	price, ok := r.LookupDeployment("azure-gpt-4o", "gpt-4o", "azure")
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, price.InputCostPerToken)
+
+	// Other deployments of the model keep the model price.
+	price, ok = r.LookupDeployment("openai-gpt-4o", "gpt-4o", "openai")
+	assert.True(t, ok)
+	assert.Equal(t, 0.000005, price.InputCostPerToken)
+
+	r.DeleteDeploymentPrice("azure-gpt-4o")
+	price, _ = r.LookupDeployment("azure-gpt-4o", "gpt-4o", "azure")
+	assert.Equal(t, 0.000005, price.InputCostPerToken)
+}
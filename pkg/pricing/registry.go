@@ -2,6 +2,7 @@
 package pricing
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
@@ -19,16 +20,38 @@ type ModelPrice struct {
 	CacheReadCostPerToken  float64 `json:"cache_read_input_token_cost,omitempty"`
 	CacheWriteCostPerToken float64 `json:"cache_creation_input_token_cost,omitempty"`
 	Mode                   string  `json:"mode"`
+
+	// Tiers replace the token rates for requests with long prompts.
+	Tiers []PriceTier `json:"tiers,omitempty"`
+	// CostPerRequest is a flat surcharge added to every request.
+	CostPerRequest float64 `json:"cost_per_request,omitempty"`
+	// CostPerImage and CostPerAudioSecond price input images and seconds of
+	// input audio.
+	CostPerImage       float64 `json:"input_cost_per_image,omitempty"`
+	CostPerAudioSecond float64 `json:"input_cost_per_audio_per_second,omitempty"`
+	// OutputCostPerReasoningToken prices the reasoning part of the output
+	// tokens; zero bills it at the output rate.
+	OutputCostPerReasoningToken float64 `json:"output_cost_per_reasoning_token,omitempty"`
+	// MaxOutputTokens is the most tokens the model generates per request;
+	// larger max_tokens are clamped or rejected before reaching it. Zero
+	// means unknown.
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
 }
 
 type Registry struct {
 	prices map[string]ModelPrice
-	mu     sync.RWMutex
+	// overrides are set at runtime and take precedence over loaded prices.
+	overrides map[string]ModelPrice
+	// deployments price single deployments, ahead of any model price.
+	deployments map[string]ModelPrice
+	mu          sync.RWMutex
 }
 
 func NewRegistry() *Registry {
 	r := &Registry{
-		prices: make(map[string]ModelPrice),
+		prices:      make(map[string]ModelPrice),
+		overrides:   make(map[string]ModelPrice),
+		deployments: make(map[string]ModelPrice),
 	}
 	// Load defaults
 	if err := r.loadBytes(defaultPrices); err != nil {
@@ -39,7 +62,13 @@ func NewRegistry() *Registry {
 	return r
 }
 
+// Load merges the pricing JSON at path into the registry. An https:// path
+// is fetched once; use RemoteSource to keep it refreshed.
 func (r *Registry) Load(path string) error {
+	if IsRemote(path) {
+		_, err := NewRemoteSource(path, nil).Refresh(context.Background(), r)
+		return err
+	}
 	// #nosec G304 -- path is user-configured; reading pricing files is expected.
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -64,21 +93,87 @@ func (r *Registry) loadBytes(data []byte) error {
 }
 
 func (r *Registry) GetPrice(model, provider string) (ModelPrice, bool) {
+	price, _, ok := r.Lookup(model, provider)
+	return price, ok
+}
+
+// Lookup is GetPrice that also reports whether the price is a runtime
+// override.
+func (r *Registry) Lookup(model, provider string) (price ModelPrice, overridden, ok bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// 1. Try "provider/model"
-	// Some keys in the registry might be stored as "provider/model"
-	key := fmt.Sprintf("%s/%s", provider, model)
-	if p, ok := r.prices[key]; ok {
-		return p, true
+	if p, ok := lookupKey(r.overrides, model, provider); ok {
+		return p, true, true
 	}
+	p, ok := lookupKey(r.prices, model, provider)
+	return p, false, ok
+}
+
+// LookupDeployment returns the price of deploymentID when it has its own,
+// falling back to the price of model served by provider.
+func (r *Registry) LookupDeployment(deploymentID, model, provider string) (ModelPrice, bool) {
+	if deploymentID != "" {
+		r.mu.RLock()
+		price, ok := r.deployments[deploymentID]
+		r.mu.RUnlock()
+		if ok {
+			return price, true
+		}
+	}
+	return r.GetPrice(model, provider)
+}
+
+// SetDeploymentPrice prices deploymentID at price regardless of its model.
+func (r *Registry) SetDeploymentPrice(deploymentID string, price ModelPrice) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deployments[deploymentID] = price
+}
+
+// DeleteDeploymentPrice returns deploymentID to its model's price.
+func (r *Registry) DeleteDeploymentPrice(deploymentID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.deployments, deploymentID)
+}
 
-	// 2. Try "model"
-	// Most keys are just "model"
-	if p, ok := r.prices[model]; ok {
+// lookupKey tries "provider/model", which some keys are stored as, before
+// the plain "model" most keys use.
+func lookupKey(prices map[string]ModelPrice, model, provider string) (ModelPrice, bool) {
+	if p, ok := prices[fmt.Sprintf("%s/%s", provider, model)]; ok {
 		return p, true
 	}
+	p, ok := prices[model]
+	return p, ok
+}
+
+// SetOverride prices key, a model name or "provider/model", at price until
+// the override is deleted. Overrides take precedence over loaded prices and
+// survive later loads and refreshes.
+func (r *Registry) SetOverride(key string, price ModelPrice) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides[key] = price
+}
 
-	return ModelPrice{}, false
+// DeleteOverride removes the override for key and reports whether there was
+// one.
+func (r *Registry) DeleteOverride(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.overrides[key]
+	delete(r.overrides, key)
+	return ok
+}
+
+// Overrides returns a copy of the runtime overrides by key.
+func (r *Registry) Overrides() map[string]ModelPrice {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]ModelPrice, len(r.overrides))
+	for k, v := range r.overrides {
+		out[k] = v
+	}
+	return out
 }
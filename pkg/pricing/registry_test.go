@@ -113,3 +113,26 @@ func TestRegistry_Load(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, 0.99, price.InputCostPerToken)
 }
+
+func TestRegistry_Overrides(t *testing.T) {
+	r := NewRegistry()
+
+	r.SetOverride("gpt-4o", ModelPrice{InputCostPerToken: 1, OutputCostPerToken: 2})
+	price, overridden, ok := r.Lookup("gpt-4o", "openai")
+	require.True(t, ok)
+	assert.True(t, overridden)
+	assert.Equal(t, 1.0, price.InputCostPerToken)
+
+	// Overrides outlive reloads of the loaded prices.
+	require.NoError(t, r.loadBytes([]byte(`{"gpt-4o": {"input_cost_per_token": 3}}`)))
+	price, _ = r.GetPrice("gpt-4o", "openai")
+	assert.Equal(t, 1.0, price.InputCostPerToken)
+	assert.Contains(t, r.Overrides(), "gpt-4o")
+
+	assert.True(t, r.DeleteOverride("gpt-4o"))
+	assert.False(t, r.DeleteOverride("gpt-4o"))
+	price, overridden, ok = r.Lookup("gpt-4o", "openai")
+	require.True(t, ok)
+	assert.False(t, overridden)
+	assert.Equal(t, 3.0, price.InputCostPerToken)
+}
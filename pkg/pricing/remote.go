@@ -0,0 +1,150 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRefreshInterval is how often RemoteSource.Run fetches the source
+	// when no interval is given.
+	DefaultRefreshInterval = time.Hour
+
+	// maxRemoteSize bounds a remote pricing document.
+	maxRemoteSize = 32 << 20
+)
+
+// IsRemote reports whether source is an HTTPS URL rather than a file path.
+func IsRemote(source string) bool {
+	return strings.HasPrefix(source, "https://")
+}
+
+// RemoteSource fetches pricing JSON over HTTPS. It sends the ETag of the last
+// loaded response so unchanged data is not downloaded again, and a failed or
+// invalid fetch leaves the registry with the last good prices.
+type RemoteSource struct {
+	url    string
+	client *http.Client
+
+	mu          sync.Mutex
+	etag        string
+	lastSuccess time.Time
+	lastErr     error
+}
+
+// RemoteStatus describes the last refresh of a RemoteSource.
+type RemoteStatus struct {
+	URL         string    `json:"url"`
+	ETag        string    `json:"etag,omitempty"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// NewRemoteSource returns a source for url. A nil client uses one with a
+// 30s timeout.
+func NewRemoteSource(url string, client *http.Client) *RemoteSource {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &RemoteSource{url: url, client: client}
+}
+
+// Refresh fetches the source and merges it into r. It reports false when the
+// server answered 304 Not Modified.
+func (s *RemoteSource) Refresh(ctx context.Context, r *Registry) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated, err := s.fetch(ctx, r)
+	if err != nil {
+		s.lastErr = err
+		return false, err
+	}
+	s.lastErr = nil
+	s.lastSuccess = time.Now()
+	return updated, nil
+}
+
+func (s *RemoteSource) fetch(ctx context.Context, r *Registry) (bool, error) {
+	if !IsRemote(s.url) {
+		return false, fmt.Errorf("pricing source %q is not an https URL", s.url)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, http.NoBody)
+	if err != nil {
+		return false, err
+	}
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("fetch pricing: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return false, nil
+	default:
+		return false, fmt.Errorf("fetch pricing: unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSize+1))
+	if err != nil {
+		return false, fmt.Errorf("read pricing: %w", err)
+	}
+	if len(data) > maxRemoteSize {
+		return false, fmt.Errorf("pricing document exceeds %d bytes", maxRemoteSize)
+	}
+	if err := r.loadBytes(data); err != nil {
+		return false, fmt.Errorf("parse pricing: %w", err)
+	}
+	s.etag = resp.Header.Get("ETag")
+	return true, nil
+}
+
+// Run refreshes r every interval until ctx is done. Failures are logged and
+// retried at the next interval. A non-positive interval uses
+// DefaultRefreshInterval.
+func (s *RemoteSource) Run(ctx context.Context, r *Registry, interval time.Duration, logger *slog.Logger) {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			updated, err := s.Refresh(ctx, r)
+			switch {
+			case err != nil:
+				logger.Warn("pricing refresh failed, keeping last known prices", "url", s.url, "error", err)
+			case updated:
+				logger.Info("pricing refreshed", "url", s.url)
+			}
+		}
+	}
+}
+
+// Status returns the outcome of the last refresh.
+func (s *RemoteSource) Status() RemoteStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := RemoteStatus{URL: s.url, ETag: s.etag, LastSuccess: s.lastSuccess}
+	if s.lastErr != nil {
+		status.LastError = s.lastErr.Error()
+	}
+	return status
+}
@@ -0,0 +1,75 @@
+package pricing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteSource_Refresh(t *testing.T) {
+	var body atomic.Value
+	body.Store(`{"remote-model": {"input_cost_per_token": 0.001, "output_cost_per_token": 0.002}}`)
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if code := int(status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(body.Load().(string)))
+	}))
+	defer server.Close()
+
+	r := NewRegistry()
+	source := NewRemoteSource(server.URL, server.Client())
+
+	updated, err := source.Refresh(context.Background(), r)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	price, ok := r.GetPrice("remote-model", "")
+	require.True(t, ok)
+	assert.Equal(t, 0.001, price.InputCostPerToken)
+	assert.Equal(t, `"v1"`, source.Status().ETag)
+
+	// The stored ETag makes an unchanged document a 304.
+	updated, err = source.Refresh(context.Background(), r)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	// Failures keep the last good prices.
+	status.Store(http.StatusInternalServerError)
+	_, err = source.Refresh(context.Background(), r)
+	require.Error(t, err)
+	assert.NotEmpty(t, source.Status().LastError)
+	_, ok = r.GetPrice("remote-model", "")
+	assert.True(t, ok)
+
+	status.Store(http.StatusOK)
+	source = NewRemoteSource(server.URL, server.Client())
+	body.Store(`not json`)
+	_, err = source.Refresh(context.Background(), r)
+	require.Error(t, err)
+	assert.Empty(t, source.Status().ETag)
+	price, ok = r.GetPrice("remote-model", "")
+	require.True(t, ok)
+	assert.Equal(t, 0.001, price.InputCostPerToken)
+}
+
+func TestRemoteSource_RequiresHTTPS(t *testing.T) {
+	assert.False(t, IsRemote("http://example.com/prices.json"))
+	assert.False(t, IsRemote("/etc/llmux/prices.json"))
+
+	_, err := NewRemoteSource("http://example.com/prices.json", nil).Refresh(context.Background(), NewRegistry())
+	assert.Error(t, err)
+}
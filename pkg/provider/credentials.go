@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credential identifies one of the two credentials of a rollover.
+type Credential string
+
+const (
+	// CredentialPrimary is the credential being rotated out.
+	CredentialPrimary Credential = "primary"
+	// CredentialSecondary is the credential being rotated in.
+	CredentialSecondary Credential = "secondary"
+)
+
+// minRollbackSamples is the number of secondary requests required before the
+// error rate can trigger an automatic rollback.
+const minRollbackSamples = 20
+
+// RolloverSchedule controls how traffic shifts from the primary to the
+// secondary credential.
+type RolloverSchedule struct {
+	// SecondaryWeight is the fraction (0-1) of requests using the secondary
+	// credential when no time window is set.
+	SecondaryWeight float64
+	// Start and End define a time window over which the secondary weight ramps
+	// linearly from 0 to 1. Before Start all traffic uses the primary, after
+	// End all traffic uses the secondary.
+	Start time.Time
+	End   time.Time
+	// RollbackErrorRate automatically rolls back to the primary when the
+	// secondary error rate exceeds it (0 = disabled).
+	RollbackErrorRate float64
+}
+
+// CredentialStats reports the observed outcomes of one credential.
+type CredentialStats struct {
+	Credential Credential `json:"credential"`
+	Requests   int64      `json:"requests"`
+	Errors     int64      `json:"errors"`
+	ErrorRate  float64    `json:"error_rate"`
+}
+
+// RolloverStatus is a snapshot of a credential rollover.
+type RolloverStatus struct {
+	SecondaryWeight float64           `json:"secondary_weight"`
+	RolledBack      bool              `json:"rolled_back"`
+	Credentials     []CredentialStats `json:"credentials"`
+}
+
+// CredentialRollover is a TokenSource that splits requests between a primary
+// and a secondary credential for gradual (blue/green) API key rotation.
+// Outcomes reported per request are tracked per credential, and the rollover
+// can be rolled back to the primary instantly.
+type CredentialRollover struct {
+	primary   TokenSource
+	secondary TokenSource
+	now       func() time.Time
+
+	mu         sync.Mutex
+	schedule   RolloverSchedule
+	rolledBack bool
+	issued     [2]string
+	stats      [2]CredentialStats
+}
+
+// NewCredentialRollover creates a rollover between two token sources.
+func NewCredentialRollover(primary, secondary TokenSource, schedule RolloverSchedule) *CredentialRollover {
+	return &CredentialRollover{
+		primary:   primary,
+		secondary: secondary,
+		now:       time.Now,
+		schedule:  schedule,
+		stats: [2]CredentialStats{
+			{Credential: CredentialPrimary},
+			{Credential: CredentialSecondary},
+		},
+	}
+}
+
+// Token returns the token of the credential selected for this request.
+func (r *CredentialRollover) Token() (string, error) {
+	r.mu.Lock()
+	useSecondary := rand.Float64() < r.weightLocked() //nolint:gosec // traffic split, not security sensitive
+	r.mu.Unlock()
+
+	idx, source := 0, r.primary
+	if useSecondary {
+		idx, source = 1, r.secondary
+	}
+	token, err := source.Token()
+	if err != nil {
+		return "", err
+	}
+	r.mu.Lock()
+	r.issued[idx] = token
+	r.mu.Unlock()
+	return token, nil
+}
+
+// weightLocked returns the current secondary weight. Caller must hold r.mu.
+func (r *CredentialRollover) weightLocked() float64 {
+	if r.rolledBack {
+		return 0
+	}
+	s := r.schedule
+	if s.Start.IsZero() || !s.End.After(s.Start) {
+		return min(max(s.SecondaryWeight, 0), 1)
+	}
+	now := r.now()
+	switch {
+	case now.Before(s.Start):
+		return 0
+	case !now.Before(s.End):
+		return 1
+	default:
+		return float64(now.Sub(s.Start)) / float64(s.End.Sub(s.Start))
+	}
+}
+
+// CredentialFor identifies which credential authenticated req by matching the
+// issued tokens against its headers and query parameters.
+func (r *CredentialRollover) CredentialFor(req *http.Request) (Credential, bool) {
+	r.mu.Lock()
+	issued := r.issued
+	r.mu.Unlock()
+
+	for i := len(issued) - 1; i >= 0; i-- {
+		token := issued[i]
+		if token == "" {
+			continue
+		}
+		if requestCarriesToken(req, token) {
+			return r.stats[i].Credential, true
+		}
+	}
+	return "", false
+}
+
+func requestCarriesToken(req *http.Request, token string) bool {
+	for _, values := range req.Header {
+		for _, v := range values {
+			if strings.Contains(v, token) {
+				return true
+			}
+		}
+	}
+	return req.URL != nil && strings.Contains(req.URL.RawQuery, token)
+}
+
+// Report records the outcome of req against the credential that authenticated
+// it, rolling back automatically when the secondary error rate exceeds the
+// configured threshold. It returns true when this report triggered the rollback.
+func (r *CredentialRollover) Report(req *http.Request, failed bool) bool {
+	cred, ok := r.CredentialFor(req)
+	if !ok {
+		return false
+	}
+	idx := 0
+	if cred == CredentialSecondary {
+		idx = 1
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st := &r.stats[idx]
+	st.Requests++
+	if failed {
+		st.Errors++
+	}
+	st.ErrorRate = float64(st.Errors) / float64(st.Requests)
+
+	threshold := r.schedule.RollbackErrorRate
+	if idx == 1 && !r.rolledBack && threshold > 0 && st.Requests >= minRollbackSamples && st.ErrorRate > threshold {
+		r.rolledBack = true
+		return true
+	}
+	return false
+}
+
+// Rollback routes all traffic to the primary credential immediately.
+func (r *CredentialRollover) Rollback() {
+	r.mu.Lock()
+	r.rolledBack = true
+	r.mu.Unlock()
+}
+
+// Resume clears a rollback, returning traffic to the schedule. The secondary
+// stats start over so the error rate that triggered the rollback does not
+// trigger it again.
+func (r *CredentialRollover) Resume() {
+	r.mu.Lock()
+	r.rolledBack = false
+	r.stats[1] = CredentialStats{Credential: CredentialSecondary}
+	r.mu.Unlock()
+}
+
+// SetSchedule replaces the rollover schedule and clears a previous rollback.
+func (r *CredentialRollover) SetSchedule(schedule RolloverSchedule) {
+	r.mu.Lock()
+	r.schedule = schedule
+	r.rolledBack = false
+	r.mu.Unlock()
+}
+
+// Status returns the current secondary weight and per-credential stats.
+func (r *CredentialRollover) Status() RolloverStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return RolloverStatus{
+		SecondaryWeight: r.weightLocked(),
+		RolledBack:      r.rolledBack,
+		Credentials:     []CredentialStats{r.stats[0], r.stats[1]},
+	}
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCredentialRollover_Schedule(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewCredentialRollover(NewStaticTokenSource("old"), NewStaticTokenSource("new"), RolloverSchedule{
+		Start: start,
+		End:   start.Add(time.Hour),
+	})
+
+	for _, tt := range []struct {
+		at   time.Time
+		want float64
+	}{
+		{at: start.Add(-time.Minute), want: 0},
+		{at: start.Add(15 * time.Minute), want: 0.25},
+		{at: start.Add(2 * time.Hour), want: 1},
+	} {
+		r.now = func() time.Time { return tt.at }
+		if got := r.Status().SecondaryWeight; got != tt.want {
+			t.Errorf("weight at %v = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+
+	r.now = func() time.Time { return start.Add(2 * time.Hour) }
+	if token, _ := r.Token(); token != "new" {
+		t.Fatalf("Token() = %q after rollover window, want new", token)
+	}
+	r.Rollback()
+	if token, _ := r.Token(); token != "old" {
+		t.Fatalf("Token() = %q after rollback, want old", token)
+	}
+}
+
+func TestCredentialRollover_ReportAndAutoRollback(t *testing.T) {
+	r := NewCredentialRollover(NewStaticTokenSource("old"), NewStaticTokenSource("new"), RolloverSchedule{
+		SecondaryWeight:   1,
+		RollbackErrorRate: 0.5,
+	})
+
+	request := func() *http.Request {
+		token, err := r.Token()
+		if err != nil {
+			t.Fatalf("Token() error = %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return req
+	}
+
+	var rolledBack bool
+	for i := 0; i < minRollbackSamples; i++ {
+		rolledBack = r.Report(request(), true) || rolledBack
+	}
+	if !rolledBack {
+		t.Fatal("expected automatic rollback once the secondary error rate exceeded the threshold")
+	}
+
+	status := r.Status()
+	if !status.RolledBack || status.SecondaryWeight != 0 {
+		t.Fatalf("status = %+v, want rolled back", status)
+	}
+	if secondary := status.Credentials[1]; secondary.Requests != minRollbackSamples || secondary.ErrorRate != 1 {
+		t.Fatalf("secondary stats = %+v", secondary)
+	}
+
+	r.Report(request(), false)
+	if primary := r.Status().Credentials[0]; primary.Requests != 1 || primary.Errors != 0 {
+		t.Fatalf("primary stats = %+v", primary)
+	}
+
+	r.Resume()
+	if r.Report(request(), true) {
+		t.Fatal("resumed rollover rolled back on the errors seen before the rollback")
+	}
+	status = r.Status()
+	if status.RolledBack || status.SecondaryWeight != 1 {
+		t.Fatalf("status = %+v, want resumed", status)
+	}
+	if secondary := status.Credentials[1]; secondary.Requests != 1 {
+		t.Fatalf("secondary stats = %+v, want reset on resume", secondary)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/blueberrycongee/llmux/pkg/pricing"
 	"github.com/blueberrycongee/llmux/pkg/types"
 )
 
@@ -49,6 +50,15 @@ type Provider interface {
 	ParseEmbeddingResponse(resp *http.Response) (*types.EmbeddingResponse, error)
 }
 
+// StreamPassthrough is implemented by providers whose streaming responses are
+// already in the unified (OpenAI) chunk format, so their SSE events can be
+// forwarded to clients without decoding and re-encoding each chunk.
+type StreamPassthrough interface {
+	// StreamPassthrough reports whether upstream stream events can be
+	// forwarded unchanged.
+	StreamPassthrough() bool
+}
+
 // StreamHandler handles streaming responses from LLM providers.
 // It provides an iterator-like interface for processing SSE events.
 type StreamHandler interface {
@@ -120,6 +130,74 @@ type Config struct {
 	MaxConcurrent       int
 	Timeout             time.Duration
 	Headers             map[string]string
+	// APIVersion pins the upstream API version. Providers translate the unified
+	// request/response format to and from the pinned version, so upgrading it
+	// does not require synchronized client changes. Empty uses the provider default.
+	APIVersion string
+	// RPM and TPM are the upstream requests/tokens per minute quota applied to
+	// each model deployment of this provider (0 = unlimited). The router skips
+	// deployments that would exceed them.
+	RPM int64
+	TPM int64
+	// ModelLimits overrides RPM/TPM for specific models.
+	ModelLimits map[string]RateLimits
+	// ModelPrices prices this provider's deployments of specific models,
+	// taking precedence over the pricing registry's model prices.
+	ModelPrices map[string]pricing.ModelPrice
+	// Tags label every deployment of this provider for tag-based routing.
+	// A "default" tag marks deployments used when a request carries no tags.
+	Tags []string
+	// SecondaryAPIKey (or SecondaryTokenSource) enables blue/green credential
+	// rollover: requests are split between the primary and secondary
+	// credential according to Rollover.
+	SecondaryAPIKey      string
+	SecondaryTokenSource TokenSource
+	Rollover             RolloverSchedule
+	// ProxyURL sends this provider's upstream requests through an HTTP(S) or
+	// SOCKS5 proxy. Empty connects directly.
+	ProxyURL string
+	// CAFile is a PEM bundle trusted for the upstream's certificate in
+	// addition to the system roots, e.g. for a TLS-inspecting egress proxy.
+	CAFile string
+	// InsecureSkipVerify disables upstream certificate verification.
+	// Only for testing against endpoints with self-signed certificates.
+	InsecureSkipVerify bool
+	// Transport tunes this provider's connection pool.
+	Transport TransportConfig
+}
+
+// TransportConfig tunes the connection pool of a provider's transport.
+// Zero values use defaults sized for a gateway sending many concurrent
+// requests to a few upstream hosts.
+type TransportConfig struct {
+	MaxIdleConns        int           // Idle connections across hosts; default 1000
+	MaxIdleConnsPerHost int           // Idle connections kept per host; default 100
+	MaxConnsPerHost     int           // Total connections per host; 0 = unlimited
+	IdleConnTimeout     time.Duration // Default 90s
+	DialTimeout         time.Duration // TCP connect timeout; default 30s
+	KeepAlive           time.Duration // TCP keep-alive interval; default 30s, negative disables
+	DisableHTTP2        bool          // Use HTTP/1.1 only
+}
+
+// RateLimits holds per-minute upstream quota for a deployment (0 = unlimited).
+type RateLimits struct {
+	RPM int64
+	TPM int64
+}
+
+// LimitsForModel returns the effective RPM/TPM for model, applying ModelLimits
+// overrides on top of the provider-wide values.
+func (c Config) LimitsForModel(model string) RateLimits {
+	limits := RateLimits{RPM: c.RPM, TPM: c.TPM}
+	if override, ok := c.ModelLimits[model]; ok {
+		if override.RPM > 0 {
+			limits.RPM = override.RPM
+		}
+		if override.TPM > 0 {
+			limits.TPM = override.TPM
+		}
+	}
+	return limits
 }
 
 // Factory creates provider instances from configuration.
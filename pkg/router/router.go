@@ -7,6 +7,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/blueberrycongee/llmux/pkg/pricing"
 	"github.com/blueberrycongee/llmux/pkg/provider"
 )
 
@@ -106,6 +107,9 @@ type RequestContext struct {
 
 	// Metadata contains additional request metadata
 	Metadata map[string]string
+
+	// Trace, when set, records candidates, filters and scores for this pick.
+	Trace *DecisionTrace
 }
 
 // ResponseMetrics contains metrics from a completed request.
@@ -155,6 +159,13 @@ type DeploymentStats struct {
 	// Timing
 	LastRequestTime time.Time
 	CooldownUntil   time.Time
+
+	// Half-open circuit state. CooldownTrips counts consecutive cooldowns
+	// without recovery; while it is non-zero and CooldownUntil has passed the
+	// deployment is half-open and serves one probe request at a time.
+	CooldownTrips     int
+	HalfOpenSuccesses int
+	ProbeStartedAt    time.Time
 }
 
 // DeploymentConfig contains deployment-specific configuration for routing.
@@ -186,6 +197,17 @@ type Config struct {
 	// CooldownPeriod is how long to wait before retrying a failed deployment
 	CooldownPeriod time.Duration
 
+	// CooldownMaxPeriod caps the exponential cooldown: each consecutive trip
+	// without recovery doubles CooldownPeriod up to this value.
+	// Values <= CooldownPeriod keep the cooldown fixed.
+	CooldownMaxPeriod time.Duration
+
+	// HalfOpenSuccessThreshold is the number of consecutive successful probes
+	// required before a deployment leaving cooldown is fully reopened.
+	// Until then it receives a single probe request at a time.
+	// Default: 3.
+	HalfOpenSuccessThreshold int
+
 	// LatencyBuffer for lowest-latency: select randomly within this % of lowest
 	// e.g., 0.1 means select from deployments within 10% of the lowest latency
 	LatencyBuffer float64
@@ -205,6 +227,10 @@ type Config struct {
 	// PricingFile is the path to the custom pricing JSON file
 	PricingFile string
 
+	// Pricing is a registry to share with the caller, so runtime overrides and
+	// refreshed prices apply to routing. When set, PricingFile is not loaded.
+	Pricing *pricing.Registry
+
 	// --- Circuit Breaker / Cooldown Configuration (LiteLLM-style) ---
 
 	// FailureThresholdPercent is the failure rate threshold for triggering cooldown.
@@ -232,15 +258,17 @@ type Config struct {
 // DefaultConfig returns sensible default router configuration.
 func DefaultConfig() Config {
 	return Config{
-		Strategy:                StrategySimpleShuffle,
-		CooldownPeriod:          60 * time.Second,
-		LatencyBuffer:           0.1, // 10% buffer
-		MaxLatencyListSize:      10,
-		MetricsTTL:              1 * time.Hour,
-		EnableTagFiltering:      false,
-		FailureThresholdPercent: 0.5,  // 50% failure rate
-		MinRequestsForThreshold: 5,    // Minimum 5 requests before checking rate
-		ImmediateCooldownOn429:  true, // Immediate cooldown on rate limit
-		EWMAAlpha:               0.1,  // Default EWMA alpha
+		Strategy:                 StrategySimpleShuffle,
+		CooldownPeriod:           60 * time.Second,
+		CooldownMaxPeriod:        10 * time.Minute,
+		HalfOpenSuccessThreshold: 3,
+		LatencyBuffer:            0.1, // 10% buffer
+		MaxLatencyListSize:       10,
+		MetricsTTL:               1 * time.Hour,
+		EnableTagFiltering:       false,
+		FailureThresholdPercent:  0.5,  // 50% failure rate
+		MinRequestsForThreshold:  5,    // Minimum 5 requests before checking rate
+		ImmediateCooldownOn429:   true, // Immediate cooldown on rate limit
+		EWMAAlpha:                0.1,  // Default EWMA alpha
 	}
 }
@@ -0,0 +1,71 @@
+package router
+
+// Filter names recorded in DecisionTrace.Filters by the built-in routers.
+const (
+	FilterHealth          = "health"
+	FilterTags            = "tags"
+	FilterTPMRPM          = "tpm_rpm"
+	FilterDefaultProvider = "default_provider"
+)
+
+// DecisionTrace records why a router chose a deployment. Routers fill it in
+// when RequestContext.Trace is set; all methods are no-ops on a nil trace.
+type DecisionTrace struct {
+	// Candidates are the deployment IDs serving the model before filtering.
+	Candidates []string `json:"candidates"`
+
+	// Filters are the filter steps applied, in order.
+	Filters []FilterStep `json:"filters"`
+
+	// ScoreKind names the metric in Scores (e.g. "latency_ms", "cost_per_token").
+	ScoreKind string `json:"score_kind,omitempty"`
+
+	// Scores maps deployment ID to the strategy's score; lower is not always better,
+	// see ScoreKind.
+	Scores map[string]float64 `json:"scores,omitempty"`
+}
+
+// FilterStep is one filter applied during a pick.
+type FilterStep struct {
+	Name      string   `json:"name"`
+	Remaining []string `json:"remaining"`
+	Removed   []string `json:"removed,omitempty"`
+}
+
+// SetCandidates records the deployment IDs considered for the pick.
+func (t *DecisionTrace) SetCandidates(ids []string) {
+	if t == nil {
+		return
+	}
+	t.Candidates = ids
+}
+
+// AddFilter records a filter step given the IDs before and after it ran.
+func (t *DecisionTrace) AddFilter(name string, before, after []string) {
+	if t == nil {
+		return
+	}
+	kept := make(map[string]struct{}, len(after))
+	for _, id := range after {
+		kept[id] = struct{}{}
+	}
+	var removed []string
+	for _, id := range before {
+		if _, ok := kept[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	t.Filters = append(t.Filters, FilterStep{Name: name, Remaining: after, Removed: removed})
+}
+
+// SetScore records the strategy score of a deployment.
+func (t *DecisionTrace) SetScore(kind, deploymentID string, score float64) {
+	if t == nil {
+		return
+	}
+	if t.Scores == nil {
+		t.Scores = make(map[string]float64)
+	}
+	t.ScoreKind = kind
+	t.Scores[deploymentID] = score
+}
@@ -185,6 +185,9 @@ type EmbeddingRequest struct {
 	// Dimensions is the number of dimensions the resulting output embeddings should have.
 	// Only supported in text-embedding-3 and later models.
 	Dimensions int `json:"dimensions,omitempty"`
+
+	// Tags are request-level tags for routing decisions. They are not sent upstream.
+	Tags []string `json:"tags,omitempty"`
 }
 
 // Validate checks if the embedding request is valid.
@@ -0,0 +1,64 @@
+package types //nolint:revive // package name is intentional
+
+import (
+	"bytes"
+
+	"github.com/goccy/go-json"
+)
+
+// reasoningContentField is where OpenAI-compatible providers such as
+// DeepSeek return reasoning, on the message or stream delta.
+var reasoningContentField = []byte(`"reasoning_content"`)
+
+// reasoningEnvelope captures the reasoning_content of a response or chunk.
+type reasoningEnvelope struct {
+	Choices []struct {
+		Message struct {
+			ReasoningContent string `json:"reasoning_content"`
+		} `json:"message"`
+		Delta struct {
+			ReasoningContent string `json:"reasoning_content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+// ApplyReasoningContent moves the reasoning_content of the messages in raw
+// onto the Reasoning of resp's choices.
+func ApplyReasoningContent(resp *ChatResponse, raw []byte) {
+	env, ok := decodeReasoningEnvelope(raw)
+	if !ok {
+		return
+	}
+	for i := range resp.Choices {
+		if i < len(env.Choices) && env.Choices[i].Message.ReasoningContent != "" && resp.Choices[i].Reasoning == nil {
+			resp.Choices[i].Reasoning = &Reasoning{Content: env.Choices[i].Message.ReasoningContent}
+		}
+	}
+}
+
+// ApplyChunkReasoningContent is the streaming counterpart of
+// ApplyReasoningContent.
+func ApplyChunkReasoningContent(chunk *StreamChunk, raw []byte) {
+	env, ok := decodeReasoningEnvelope(raw)
+	if !ok {
+		return
+	}
+	for i := range chunk.Choices {
+		if i < len(env.Choices) && env.Choices[i].Delta.ReasoningContent != "" && chunk.Choices[i].Delta.Reasoning == nil {
+			chunk.Choices[i].Delta.Reasoning = &Reasoning{Content: env.Choices[i].Delta.ReasoningContent}
+		}
+	}
+}
+
+// decodeReasoningEnvelope decodes raw only when it carries reasoning_content,
+// so responses without it are not decoded twice.
+func decodeReasoningEnvelope(raw []byte) (reasoningEnvelope, bool) {
+	var env reasoningEnvelope
+	if !bytes.Contains(raw, reasoningContentField) {
+		return env, false
+	}
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return env, false
+	}
+	return env, true
+}
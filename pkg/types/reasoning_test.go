@@ -0,0 +1,28 @@
+package types //nolint:revive // package name is intentional
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyReasoningContent(t *testing.T) {
+	raw := []byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"4","reasoning_content":"2+2=4"}}],"usage":{"prompt_tokens":5,"completion_tokens":12,"total_tokens":17,"completion_tokens_details":{"reasoning_tokens":9}}}`)
+	var resp ChatResponse
+	require.NoError(t, json.Unmarshal(raw, &resp))
+	ApplyReasoningContent(&resp, raw)
+	require.Equal(t, &Reasoning{Content: "2+2=4"}, resp.Choices[0].Reasoning)
+	require.Equal(t, 9, resp.Usage.ReasoningTokens())
+
+	raw = []byte(`{"choices":[{"index":0,"delta":{"reasoning_content":"2+2"}}]}`)
+	var chunk StreamChunk
+	require.NoError(t, json.Unmarshal(raw, &chunk))
+	ApplyChunkReasoningContent(&chunk, raw)
+	require.Equal(t, &Reasoning{Content: "2+2"}, chunk.Choices[0].Delta.Reasoning)
+
+	var plain ChatResponse
+	ApplyReasoningContent(&plain, []byte(`{"choices":[]}`))
+	require.Empty(t, plain.Choices)
+	require.Zero(t, plain.Usage.ReasoningTokens(), "usage without details reports no reasoning")
+}
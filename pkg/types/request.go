@@ -2,7 +2,11 @@
 // All types are designed to be compatible with OpenAI's Chat Completion API format.
 package types //nolint:revive // package name is intentional
 
-import "github.com/goccy/go-json"
+import (
+	"bytes"
+
+	"github.com/goccy/go-json"
+)
 
 // ChatRequest represents an OpenAI-compatible chat completion request.
 // It serves as the unified input format for all LLM providers.
@@ -190,6 +194,31 @@ type ChatMessage struct {
 	ToolCallID string          `json:"tool_call_id,omitempty"`
 }
 
+// TextContent returns the text of the message content, concatenating text
+// parts of multi-part content.
+func (m ChatMessage) TextContent() string {
+	return extractMessageText(m)
+}
+
+// ImageCount returns the number of image parts in the message content.
+func (m ChatMessage) ImageCount() int {
+	content := bytes.TrimSpace(m.Content)
+	if len(content) == 0 || content[0] != '[' {
+		return 0
+	}
+	var parts []contentPart
+	if err := json.Unmarshal(content, &parts); err != nil {
+		return 0
+	}
+	n := 0
+	for _, part := range parts {
+		if part.Type == "image_url" {
+			n++
+		}
+	}
+	return n
+}
+
 // Tool represents a function that the model can call.
 type Tool struct {
 	Type     string       `json:"type"`
@@ -203,8 +232,11 @@ type ToolFunction struct {
 	Parameters  json.RawMessage `json:"parameters,omitempty"`
 }
 
-// ToolCall represents a function call made by the model.
+// ToolCall represents a function call made by the model. Index is only set
+// on stream deltas, where it identifies the call that argument fragments
+// belong to.
 type ToolCall struct {
+	Index    *int             `json:"index,omitempty"`
 	ID       string           `json:"id"`
 	Type     string           `json:"type"`
 	Function ToolCallFunction `json:"function"`
@@ -218,7 +250,26 @@ type ToolCallFunction struct {
 
 // ResponseFormat specifies the output format for the model.
 type ResponseFormat struct {
-	Type string `json:"type"`
+	Type       string      `json:"type"` // "text", "json_object" or "json_schema"
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
+}
+
+// JSONSchema describes the structured output required by a json_schema
+// response format.
+type JSONSchema struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Schema      json.RawMessage `json:"schema,omitempty"`
+	Strict      *bool           `json:"strict,omitempty"`
+}
+
+// ImageCount returns the number of images across the request messages.
+func (r *ChatRequest) ImageCount() int {
+	n := 0
+	for _, m := range r.Messages {
+		n += m.ImageCount()
+	}
+	return n
 }
 
 // Reset clears the ChatRequest for reuse.
@@ -10,6 +10,15 @@ type ChatResponse struct {
 	Choices           []Choice `json:"choices"`
 	Usage             *Usage   `json:"usage,omitempty"`
 	SystemFingerprint string   `json:"system_fingerprint,omitempty"`
+
+	// CacheStatus is "hit" or "miss" when the response cache was consulted,
+	// and empty otherwise. It is not serialized.
+	CacheStatus string `json:"-"`
+
+	// StructuredOutput is "valid", "invalid" or "repaired" when the output of
+	// a json_schema response format was validated, and empty otherwise. It is
+	// not serialized.
+	StructuredOutput string `json:"-"`
 }
 
 // Choice represents a single completion choice.
@@ -18,6 +27,19 @@ type Choice struct {
 	Message      ChatMessage `json:"message"`
 	FinishReason string      `json:"finish_reason"`
 	Logprobs     *Logprobs   `json:"logprobs,omitempty"`
+	Reasoning    *Reasoning  `json:"reasoning,omitempty"`
+}
+
+// Reasoning is the reasoning a model produced before its answer, such as
+// Anthropic thinking blocks or the reasoning_content of OpenAI-compatible
+// providers. In a stream chunk it holds the increment of the reasoning.
+type Reasoning struct {
+	Content string `json:"content,omitempty"`
+	// Signature verifies Anthropic thinking when it is sent back in a
+	// later turn.
+	Signature string `json:"signature,omitempty"`
+	// Redacted holds the encrypted thinking blocks a provider withheld.
+	Redacted []string `json:"redacted,omitempty"`
 }
 
 // Usage contains token usage statistics for the request.
@@ -26,6 +48,30 @@ type Usage struct {
 	CompletionTokens int    `json:"completion_tokens"`
 	TotalTokens      int    `json:"total_tokens"`
 	Provider         string `json:"-"`
+	Deployment       string `json:"-"` // ID of the deployment that served the request
+	// AuxiliaryCost is the cost in USD of additional upstream calls made to
+	// produce this response, such as rejected speculative drafts.
+	AuxiliaryCost float64 `json:"-"`
+	// Images and AudioSeconds are the image and audio inputs of the request,
+	// for models priced per unit.
+	Images       int     `json:"-"`
+	AudioSeconds float64 `json:"-"`
+	// CompletionTokensDetails breaks down the completion tokens, which
+	// include any reasoning tokens.
+	CompletionTokensDetails *CompletionTokensDetails `json:"completion_tokens_details,omitempty"`
+}
+
+// CompletionTokensDetails breaks down completion tokens as OpenAI reports them.
+type CompletionTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+// ReasoningTokens returns the completion tokens spent on reasoning.
+func (u *Usage) ReasoningTokens() int {
+	if u == nil || u.CompletionTokensDetails == nil {
+		return 0
+	}
+	return u.CompletionTokensDetails.ReasoningTokens
 }
 
 // Logprobs contains log probability information.
@@ -63,6 +109,7 @@ type StreamDelta struct {
 	Role      string     `json:"role,omitempty"`
 	Content   string     `json:"content,omitempty"`
 	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	Reasoning *Reasoning `json:"reasoning,omitempty"`
 }
 
 // Reset clears the ChatResponse for reuse.
@@ -74,4 +121,6 @@ func (r *ChatResponse) Reset() {
 	r.Choices = r.Choices[:0]
 	r.Usage = nil
 	r.SystemFingerprint = ""
+	r.CacheStatus = ""
+	r.StructuredOutput = ""
 }
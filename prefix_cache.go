@@ -0,0 +1,281 @@
+package llmux
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/tokenizer"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// promptCacheKeyProviders lists provider types that accept a prompt_cache_key
+// hint for routing requests with a shared prefix to the same upstream cache.
+var promptCacheKeyProviders = map[string]struct{}{
+	"openai": {},
+	"azure":  {},
+}
+
+// prefixCacheMaxPrefixes bounds the number of tracked prompt prefixes.
+const prefixCacheMaxPrefixes = 10000
+
+// PrefixCacheSavings summarizes estimated prefix cache savings for one team.
+// Requests without a team are reported under an empty TeamID.
+type PrefixCacheSavings struct {
+	TeamID string `json:"team_id"`
+	// ResponseHits counts requests served from a cached response whose
+	// normalized prompt matched a previous request.
+	ResponseHits int64 `json:"response_hits"`
+	// PrefixReuses counts upstream requests whose system/few-shot prefix was
+	// already seen and is therefore eligible for provider-side prompt caching.
+	PrefixReuses int64 `json:"prefix_reuses"`
+	// SavedTokens is the number of tokens not billed at the full input rate.
+	SavedTokens int64 `json:"saved_tokens"`
+	// EstimatedSavings is the estimated saved cost in USD.
+	EstimatedSavings float64 `json:"estimated_savings_usd"`
+}
+
+// prefixCache tracks seen prompt prefixes and per-team savings.
+type prefixCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	prefixes map[string]time.Time
+	savings  map[string]*PrefixCacheSavings
+}
+
+func newPrefixCache(ttl time.Duration) *prefixCache {
+	return &prefixCache{
+		ttl:      ttl,
+		prefixes: make(map[string]time.Time),
+		savings:  make(map[string]*PrefixCacheSavings),
+	}
+}
+
+// observePrefix records a prefix and reports whether it was seen within the TTL.
+func (p *prefixCache) observePrefix(hash string, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	last, seen := p.prefixes[hash]
+	seen = seen && (p.ttl <= 0 || now.Sub(last) < p.ttl)
+	if _, exists := p.prefixes[hash]; !exists && len(p.prefixes) >= prefixCacheMaxPrefixes {
+		p.evictOldestLocked()
+	}
+	p.prefixes[hash] = now
+	return seen
+}
+
+func (p *prefixCache) evictOldestLocked() {
+	var oldestKey string
+	var oldest time.Time
+	for k, t := range p.prefixes {
+		if oldestKey == "" || t.Before(oldest) {
+			oldestKey, oldest = k, t
+		}
+	}
+	delete(p.prefixes, oldestKey)
+}
+
+func (p *prefixCache) record(teamID string, responseHit bool, tokens int, savings float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.savings[teamID]
+	if !ok {
+		s = &PrefixCacheSavings{TeamID: teamID}
+		p.savings[teamID] = s
+	}
+	if responseHit {
+		s.ResponseHits++
+	} else {
+		s.PrefixReuses++
+	}
+	s.SavedTokens += int64(tokens)
+	s.EstimatedSavings += savings
+}
+
+func (p *prefixCache) snapshot() []PrefixCacheSavings {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]PrefixCacheSavings, 0, len(p.savings))
+	for _, s := range p.savings {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TeamID < out[j].TeamID })
+	return out
+}
+
+// PrefixCacheEnabled reports whether the experimental prefix cache is on.
+func (c *Client) PrefixCacheEnabled() bool {
+	return c.prefixCache != nil
+}
+
+// PrefixCacheSavings returns estimated prefix cache savings grouped by team.
+// Returns nil when the prefix cache is disabled.
+func (c *Client) PrefixCacheSavings() []PrefixCacheSavings {
+	if c.prefixCache == nil {
+		return nil
+	}
+	return c.prefixCache.snapshot()
+}
+
+// splitPromptPrefix returns the reusable prefix (system messages and the
+// few-shot block before the final user turn) and the remaining tail.
+func splitPromptPrefix(messages []ChatMessage) (prefix, tail []ChatMessage) {
+	last := -1
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			last = i
+			break
+		}
+	}
+	if last <= 0 {
+		return nil, messages
+	}
+	return messages[:last], messages[last:]
+}
+
+// normalizeMessages collapses whitespace in string contents so prompts that
+// differ only in formatting produce the same key.
+func normalizeMessages(messages []ChatMessage) []ChatMessage {
+	out := make([]ChatMessage, len(messages))
+	for i, msg := range messages {
+		out[i] = msg
+		var text string
+		if err := json.Unmarshal(msg.Content, &text); err != nil {
+			continue
+		}
+		normalized, err := json.Marshal(strings.Join(strings.Fields(text), " "))
+		if err != nil {
+			continue
+		}
+		out[i].Content = normalized
+	}
+	return out
+}
+
+func hashPromptPart(model string, messages []ChatMessage) (string, error) {
+	data, err := json.Marshal(struct {
+		Model    string        `json:"model"`
+		Messages []ChatMessage `json:"messages"`
+	}{Model: model, Messages: messages})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%x", sum[:]), nil
+}
+
+// prefixCacheKey builds a cache key from the normalized prompt, keeping the
+// sampling parameters that change the response distribution.
+func (c *Client) prefixCacheKey(ctx context.Context, req *ChatRequest) (string, error) {
+	normalized := *req
+	normalized.Messages = normalizeMessages(req.Messages)
+	key, err := c.generateCacheKey(ctx, &normalized)
+	if err != nil {
+		return "", err
+	}
+	return "prefix:" + key, nil
+}
+
+// prefixCacheLookup serves near-identical prompts from the cache.
+func (c *Client) prefixCacheLookup(ctx context.Context, req *ChatRequest) *ChatResponse {
+	key, err := c.prefixCacheKey(ctx, req)
+	if err != nil {
+		return nil
+	}
+	data, err := c.cache.Get(ctx, key)
+	if err != nil || data == nil {
+		return nil
+	}
+	var resp ChatResponse
+	if err := json.Unmarshal(data, &resp); err != nil || !cachedResponseFresh(ctx, &resp) {
+		return nil
+	}
+	tokens := 0
+	if resp.Usage != nil {
+		tokens = resp.Usage.TotalTokens
+	}
+	c.prefixCache.record(prefixCacheTeam(ctx), true, tokens, c.CalculateCost(req.Model, resp.Usage))
+	return &resp
+}
+
+func (c *Client) prefixCacheStore(ctx context.Context, req *ChatRequest, resp *ChatResponse) {
+	key, err := c.prefixCacheKey(ctx, req)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	if err := c.cache.Set(ctx, key, data, c.cacheTTL(ctx)); err != nil {
+		c.logger.Debug("prefix cache store failed", "error", err)
+	}
+}
+
+// observePromptPrefix records the request's prefix, accounts estimated savings
+// when it was already seen, and returns a context carrying the prefix hash for
+// providers that accept prompt cache hints.
+func (c *Client) observePromptPrefix(ctx context.Context, req *ChatRequest, canonicalModel string) context.Context {
+	prefix, _ := splitPromptPrefix(req.Messages)
+	if len(prefix) == 0 {
+		return ctx
+	}
+	hash, err := hashPromptPart(canonicalModel, normalizeMessages(prefix))
+	if err != nil {
+		return ctx
+	}
+	if c.prefixCache.observePrefix(hash, time.Now()) {
+		tokens := tokenizer.EstimatePromptTokens(canonicalModel, &types.ChatRequest{Model: canonicalModel, Messages: prefix})
+		savings := 0.0
+		if price, ok := c.pricing.GetPrice(canonicalModel, ""); ok && price.CacheReadCostPerToken > 0 {
+			savings = float64(tokens) * (price.InputCostPerToken - price.CacheReadCostPerToken)
+		}
+		c.prefixCache.record(prefixCacheTeam(ctx), false, tokens, savings)
+	}
+	return context.WithValue(ctx, promptCacheKeyContextKey{}, hash)
+}
+
+type promptCacheKeyContextKey struct{}
+
+// applyPromptCacheKey adds a prompt_cache_key hint for providers that support it.
+// Caller-provided hints are never overwritten.
+func applyPromptCacheKey(ctx context.Context, providerName string, req *types.ChatRequest) *types.ChatRequest {
+	hash, ok := ctx.Value(promptCacheKeyContextKey{}).(string)
+	if !ok || hash == "" {
+		return req
+	}
+	if _, supported := promptCacheKeyProviders[providerName]; !supported {
+		return req
+	}
+	if _, exists := req.Extra["prompt_cache_key"]; exists {
+		return req
+	}
+	value, err := json.Marshal("llmux-" + hash[:32])
+	if err != nil {
+		return req
+	}
+	cloned := *req
+	cloned.Extra = make(map[string]json.RawMessage, len(req.Extra)+1)
+	for k, v := range req.Extra {
+		cloned.Extra[k] = v
+	}
+	cloned.Extra["prompt_cache_key"] = value
+	return &cloned
+}
+
+func prefixCacheTeam(ctx context.Context) string {
+	if authCtx := auth.GetAuthContext(ctx); authCtx != nil && authCtx.APIKey != nil && authCtx.APIKey.TeamID != nil {
+		return *authCtx.APIKey.TeamID
+	}
+	return ""
+}
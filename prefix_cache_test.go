@@ -0,0 +1,73 @@
+package llmux
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/pkg/pricing"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+func fewShotMessages(question string) []ChatMessage {
+	return []ChatMessage{
+		{Role: "system", Content: json.RawMessage(`"You are a   classifier."`)},
+		{Role: "user", Content: json.RawMessage(`"great product"`)},
+		{Role: "assistant", Content: json.RawMessage(`"positive"`)},
+		{Role: "user", Content: json.RawMessage(question)},
+	}
+}
+
+func TestSplitPromptPrefix(t *testing.T) {
+	prefix, tail := splitPromptPrefix(fewShotMessages(`"awful"`))
+	require.Len(t, prefix, 3)
+	require.Len(t, tail, 1)
+
+	prefix, tail = splitPromptPrefix([]ChatMessage{{Role: "user", Content: json.RawMessage(`"hi"`)}})
+	assert.Empty(t, prefix)
+	assert.Len(t, tail, 1)
+}
+
+func TestPrefixCacheKey_IgnoresWhitespaceDifferences(t *testing.T) {
+	c := &Client{}
+	ctx := context.Background()
+
+	a, err := c.prefixCacheKey(ctx, &ChatRequest{Model: "gpt-4o", Messages: fewShotMessages(`"awful  service\n"`)})
+	require.NoError(t, err)
+	b, err := c.prefixCacheKey(ctx, &ChatRequest{Model: "gpt-4o", Messages: fewShotMessages(`"awful service"`)})
+	require.NoError(t, err)
+	other, err := c.prefixCacheKey(ctx, &ChatRequest{Model: "gpt-4o", Messages: fewShotMessages(`"good service"`)})
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, other)
+}
+
+func TestObservePromptPrefix_RecordsSavingsPerTeam(t *testing.T) {
+	c := &Client{prefixCache: newPrefixCache(time.Hour), pricing: pricing.NewRegistry()}
+	team := "team-a"
+	ctx := context.WithValue(context.Background(), auth.AuthContextKey, &auth.AuthContext{
+		APIKey: &auth.APIKey{ID: "k", TeamID: &team},
+	})
+
+	hinted := c.observePromptPrefix(ctx, &ChatRequest{Model: "gpt-4o", Messages: fewShotMessages(`"one"`)}, "gpt-4o")
+	assert.Empty(t, c.PrefixCacheSavings(), "first sighting is not a reuse")
+
+	c.observePromptPrefix(ctx, &ChatRequest{Model: "gpt-4o", Messages: fewShotMessages(`"two"`)}, "gpt-4o")
+	savings := c.PrefixCacheSavings()
+	require.Len(t, savings, 1)
+	assert.Equal(t, "team-a", savings[0].TeamID)
+	assert.Equal(t, int64(1), savings[0].PrefixReuses)
+	assert.Positive(t, savings[0].SavedTokens)
+
+	req := &types.ChatRequest{Model: "gpt-4o"}
+	withKey := applyPromptCacheKey(hinted, "openai", req)
+	assert.Contains(t, withKey.Extra, "prompt_cache_key")
+	assert.Nil(t, req.Extra, "original request must not be mutated")
+	assert.Same(t, req, applyPromptCacheKey(hinted, "anthropic", req))
+}
@@ -0,0 +1,135 @@
+package llmux
+
+import (
+	"sort"
+
+	"github.com/blueberrycongee/llmux/pkg/pricing"
+)
+
+// PricingPolicy controls how requests for models without pricing are handled.
+type PricingPolicy string
+
+const (
+	// PricingPolicyFail rejects requests for unpriced models (default).
+	PricingPolicyFail PricingPolicy = "fail"
+	// PricingPolicyWarn serves unpriced models at zero cost and logs a warning.
+	PricingPolicyWarn PricingPolicy = "warn"
+	// PricingPolicyDefaultRate prices unpriced models at the provider's
+	// default rate, falling back to the "*" rate; without a matching rate the
+	// request is rejected.
+	PricingPolicyDefaultRate PricingPolicy = "default_rate"
+)
+
+// DefaultRateAllProviders is the DefaultRates key applied to any provider
+// without its own default rate.
+const DefaultRateAllProviders = "*"
+
+// TokenRate is a per-token price in USD.
+type TokenRate struct {
+	InputCostPerToken  float64 `json:"input_cost_per_token"`
+	OutputCostPerToken float64 `json:"output_cost_per_token"`
+}
+
+// PricingFallback configures pricing for models missing from the registry.
+type PricingFallback struct {
+	Policy PricingPolicy
+	// DefaultRates maps provider names (or "*") to the rate used by
+	// PricingPolicyDefaultRate.
+	DefaultRates map[string]TokenRate
+}
+
+// MissingPricing describes a configured deployment whose model has no pricing.
+type MissingPricing struct {
+	Provider     string `json:"provider"`
+	Model        string `json:"model"`
+	DeploymentID string `json:"deployment_id"`
+	// Fallback is how requests are priced: "reject", "zero_cost" or "default_rate".
+	Fallback string `json:"fallback"`
+}
+
+// priceFor returns the registry price for the deployment or its model, or
+// the fallback rate when the policy allows one.
+func (c *Client) priceFor(deploymentID, model, provider string) (pricing.ModelPrice, bool) {
+	if c.pricing == nil {
+		return pricing.ModelPrice{}, false
+	}
+	if price, ok := c.pricing.LookupDeployment(deploymentID, model, provider); ok {
+		return price, true
+	}
+	if rate, ok := c.defaultRate(provider); ok {
+		return pricing.ModelPrice{
+			Provider:           provider,
+			InputCostPerToken:  rate.InputCostPerToken,
+			OutputCostPerToken: rate.OutputCostPerToken,
+		}, true
+	}
+	return pricing.ModelPrice{}, false
+}
+
+func (c *Client) defaultRate(provider string) (TokenRate, bool) {
+	fallback := c.config.PricingFallback
+	if fallback.Policy != PricingPolicyDefaultRate {
+		return TokenRate{}, false
+	}
+	if rate, ok := fallback.DefaultRates[provider]; ok {
+		return rate, true
+	}
+	rate, ok := fallback.DefaultRates[DefaultRateAllProviders]
+	return rate, ok
+}
+
+// warnUnpriced logs the first request served without pricing per model and provider.
+func (c *Client) warnUnpriced(model, provider string) {
+	if _, loaded := c.unpricedWarned.LoadOrStore(provider+"/"+model, struct{}{}); loaded {
+		return
+	}
+	c.logger.Warn("serving model without pricing at zero cost",
+		"model", model,
+		"provider", provider,
+		"pricing_policy", string(PricingPolicyWarn),
+	)
+}
+
+// Pricing returns the registry the client prices requests and cost-based
+// routing from, for viewing and overriding model prices at runtime.
+func (c *Client) Pricing() *pricing.Registry {
+	return c.pricing
+}
+
+// PricingPolicy returns the configured policy for unpriced models.
+func (c *Client) PricingPolicy() PricingPolicy {
+	if c.config.PricingFallback.Policy == "" {
+		return PricingPolicyFail
+	}
+	return c.config.PricingFallback.Policy
+}
+
+// MissingPricing lists deployments whose model is not in the pricing
+// registry, sorted by deployment ID, with the fallback that applies.
+func (c *Client) MissingPricing() []MissingPricing {
+	deployments := c.ListDeployments()
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].ID < deployments[j].ID })
+
+	var missing []MissingPricing
+	for _, d := range deployments {
+		if _, ok := c.pricing.LookupDeployment(d.ID, d.ModelName, d.ProviderName); ok {
+			continue
+		}
+		fallback := "reject"
+		switch c.PricingPolicy() {
+		case PricingPolicyWarn:
+			fallback = "zero_cost"
+		case PricingPolicyDefaultRate:
+			if _, ok := c.defaultRate(d.ProviderName); ok {
+				fallback = "default_rate"
+			}
+		}
+		missing = append(missing, MissingPricing{
+			Provider:     d.ProviderName,
+			Model:        d.ModelName,
+			DeploymentID: d.ID,
+			Fallback:     fallback,
+		})
+	}
+	return missing
+}
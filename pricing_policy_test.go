@@ -0,0 +1,151 @@
+package llmux
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/pkg/pricing"
+)
+
+func newPricingPolicyClient(t *testing.T, fallback *PricingFallback) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			ID:      "priced",
+			Object:  "chat.completion",
+			Created: time.Now().Unix(),
+			Model:   "in-house",
+			Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: jsonString("ok")}, FinishReason: "stop"}},
+			Usage:   &Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	models := []string{"priced", "in-house"}
+	opts := []Option{
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: models, baseURL: server.URL}, models),
+		withTestPricing(t, "priced"),
+		WithRetry(0, 0),
+	}
+	if fallback != nil {
+		opts = append(opts, WithPricingFallback(*fallback))
+	}
+	client, err := New(opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func unpricedRequest() *ChatRequest {
+	return &ChatRequest{Model: "in-house", Messages: []ChatMessage{{Role: "user", Content: jsonString("hi")}}}
+}
+
+func TestPricingPolicy_FailByDefault(t *testing.T) {
+	client := newPricingPolicyClient(t, nil)
+
+	if _, err := client.ChatCompletion(context.Background(), unpricedRequest()); err == nil {
+		t.Fatal("expected unpriced model to be rejected")
+	}
+	missing := client.MissingPricing()
+	if len(missing) != 1 || missing[0].Model != "in-house" || missing[0].Fallback != "reject" {
+		t.Fatalf("MissingPricing() = %+v", missing)
+	}
+}
+
+func TestPricingPolicy_Warn(t *testing.T) {
+	client := newPricingPolicyClient(t, &PricingFallback{Policy: PricingPolicyWarn})
+
+	resp, err := client.ChatCompletion(context.Background(), unpricedRequest())
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if cost := client.CalculateCost(resp.Model, resp.Usage); cost != 0 {
+		t.Fatalf("cost = %v, want 0", cost)
+	}
+	if missing := client.MissingPricing(); len(missing) != 1 || missing[0].Fallback != "zero_cost" {
+		t.Fatalf("MissingPricing() = %+v", missing)
+	}
+}
+
+func TestPricingPolicy_DefaultRate(t *testing.T) {
+	client := newPricingPolicyClient(t, &PricingFallback{
+		Policy:       PricingPolicyDefaultRate,
+		DefaultRates: map[string]TokenRate{DefaultRateAllProviders: {InputCostPerToken: 0.001, OutputCostPerToken: 0.002}},
+	})
+
+	resp, err := client.ChatCompletion(context.Background(), unpricedRequest())
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if cost := client.CalculateCost(resp.Model, resp.Usage); math.Abs(cost-0.02) > 1e-12 {
+		t.Fatalf("cost = %v, want 0.02", cost)
+	}
+	if client.HasPricing("in-house", "mock") {
+		t.Fatal("HasPricing should ignore fallback rates")
+	}
+	if missing := client.MissingPricing(); len(missing) != 1 || missing[0].Fallback != "default_rate" {
+		t.Fatalf("MissingPricing() = %+v", missing)
+	}
+}
+
+func TestCalculateCost_DeploymentPrice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			ID:      "deployment-priced",
+			Object:  "chat.completion",
+			Created: time.Now().Unix(),
+			Model:   "in-house",
+			Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: jsonString("ok")}, FinishReason: "stop"}},
+			Usage:   &Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(
+		WithProvider(ProviderConfig{
+			Name: "local", Type: "openai", APIKey: "test-key", Models: []string{"in-house"},
+			BaseURL: server.URL, AllowPrivateBaseURL: true,
+			ModelPrices: map[string]pricing.ModelPrice{"in-house": {
+				InputCostPerToken:  0.001,
+				OutputCostPerToken: 0.002,
+				CostPerRequest:     0.1,
+				CostPerImage:       0.05,
+			}},
+		}),
+		WithRetry(0, 0),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	// The model has no registry price, so only the deployment price lets the
+	// request through the default fail policy.
+	req := &ChatRequest{Model: "in-house", Messages: []ChatMessage{{
+		Role:    "user",
+		Content: json.RawMessage(`[{"type":"text","text":"what is this?"},{"type":"image_url","image_url":{"url":"https://example.com/a.png"}}]`),
+	}}}
+	resp, err := client.ChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.Usage.Deployment != "local-in-house" || resp.Usage.Images != 1 {
+		t.Fatalf("usage = %+v", resp.Usage)
+	}
+	if cost := client.CalculateCost(resp.Model, resp.Usage); math.Abs(cost-0.17) > 1e-12 {
+		t.Fatalf("cost = %v, want 0.17", cost)
+	}
+	if missing := client.MissingPricing(); len(missing) != 0 {
+		t.Fatalf("MissingPricing() = %+v", missing)
+	}
+}
@@ -0,0 +1,75 @@
+package llmux
+
+import (
+	"context"
+	"strings"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+// Priority ranks requests competing for provider concurrency.
+// When the admission queue is enabled, higher priorities are admitted first
+// and lower priorities are shed once the queue is full.
+type Priority int
+
+const (
+	PriorityLow    Priority = 0
+	PriorityNormal Priority = 1
+	PriorityHigh   Priority = 2
+)
+
+// PriorityMetadataKey is the API key metadata field that sets a default priority.
+const PriorityMetadataKey = "priority"
+
+// ParsePriority parses "low", "normal" or "high" (case-insensitive).
+func ParsePriority(s string) (Priority, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "low":
+		return PriorityLow, true
+	case "normal":
+		return PriorityNormal, true
+	case "high":
+		return PriorityHigh, true
+	default:
+		return PriorityNormal, false
+	}
+}
+
+// String returns the priority name.
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
+type priorityContextKey struct{}
+
+// WithRequestPriority stores the request priority in the context.
+// It takes precedence over the priority derived from API key metadata.
+func WithRequestPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, p)
+}
+
+// RequestPriority resolves the priority from the context, then from the
+// authenticated API key's metadata, defaulting to PriorityNormal.
+func RequestPriority(ctx context.Context) Priority {
+	if ctx == nil {
+		return PriorityNormal
+	}
+	if p, ok := ctx.Value(priorityContextKey{}).(Priority); ok {
+		return p
+	}
+	if authCtx := auth.GetAuthContext(ctx); authCtx != nil && authCtx.APIKey != nil {
+		if raw, ok := authCtx.APIKey.Metadata[PriorityMetadataKey].(string); ok {
+			if p, ok := ParsePriority(raw); ok {
+				return p
+			}
+		}
+	}
+	return PriorityNormal
+}
@@ -0,0 +1,31 @@
+package llmux
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+)
+
+func TestRequestPriority(t *testing.T) {
+	assert.Equal(t, PriorityNormal, RequestPriority(context.Background()))
+
+	keyCtx := context.WithValue(context.Background(), auth.AuthContextKey, &auth.AuthContext{
+		APIKey: &auth.APIKey{ID: "k1", Metadata: auth.Metadata{PriorityMetadataKey: "high"}},
+	})
+	assert.Equal(t, PriorityHigh, RequestPriority(keyCtx))
+
+	// Explicit context priority wins over key metadata.
+	assert.Equal(t, PriorityLow, RequestPriority(WithRequestPriority(keyCtx, PriorityLow)))
+}
+
+func TestParsePriority(t *testing.T) {
+	p, ok := ParsePriority(" HIGH ")
+	assert.True(t, ok)
+	assert.Equal(t, PriorityHigh, p)
+
+	_, ok = ParsePriority("urgent")
+	assert.False(t, ok)
+}
@@ -66,10 +66,14 @@ func New(opts ...Option) *Provider {
 
 // NewFromConfig creates a provider from a Config struct.
 func NewFromConfig(cfg provider.Config) (provider.Provider, error) {
+	if err := validateAPIVersion(cfg.APIVersion); err != nil {
+		return nil, err
+	}
 	opts := []Option{
 		WithAPIKey(cfg.APIKey),
 		WithBaseURL(cfg.BaseURL),
 		WithModels(cfg.Models...),
+		WithAPIVersion(cfg.APIVersion),
 	}
 	if cfg.TokenSource != nil {
 		opts = append(opts, WithTokenSource(cfg.TokenSource))
@@ -131,6 +135,9 @@ type contentBlock struct {
 	Input     any    `json:"input,omitempty"`
 	ToolUseID string `json:"tool_use_id,omitempty"`
 	Content   string `json:"content,omitempty"`
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Data      string `json:"data,omitempty"`
 }
 
 type metadata struct {
@@ -179,7 +186,15 @@ type anthropicUsage struct {
 
 // BuildRequest creates an HTTP request for the Anthropic API.
 func (p *Provider) BuildRequest(ctx context.Context, req *types.ChatRequest) (*http.Request, error) {
-	anthropicReq, err := p.transformRequest(req)
+	var anthropicReq any
+	var err error
+	path := "/v1/messages"
+	if usesLegacyCompletions(p.apiVersion) {
+		anthropicReq, err = p.transformCompletionRequest(req)
+		path = "/v1/complete"
+	} else {
+		anthropicReq, err = p.transformRequest(req)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("transform request: %w", err)
 	}
@@ -189,7 +204,7 @@ func (p *Provider) BuildRequest(ctx context.Context, req *types.ChatRequest) (*h
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	url := strings.TrimSuffix(p.baseURL, "/") + "/v1/messages"
+	url := strings.TrimSuffix(p.baseURL, "/") + path
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
@@ -293,6 +308,9 @@ func (p *Provider) transformMessages(messages []types.ChatMessage) ([]anthropicM
 
 		if role == "assistant" && len(msg.ToolCalls) > 0 {
 			var blocks []contentBlock
+			if text := msg.TextContent(); text != "" {
+				blocks = append(blocks, contentBlock{Type: "text", Text: text})
+			}
 			for _, tc := range msg.ToolCalls {
 				var input any
 				if err := json.Unmarshal([]byte(tc.Function.Arguments), &input); err != nil {
@@ -417,6 +435,9 @@ func (p *Provider) ParseResponse(resp *http.Response) (*types.ChatResponse, erro
 	if err != nil {
 		return nil, fmt.Errorf("read response: %w", err)
 	}
+	if usesLegacyCompletions(p.apiVersion) {
+		return parseCompletionResponse(body)
+	}
 
 	var anthropicResp anthropicResponse
 	if err := json.Unmarshal(body, &anthropicResp); err != nil {
@@ -429,11 +450,23 @@ func (p *Provider) ParseResponse(resp *http.Response) (*types.ChatResponse, erro
 func (p *Provider) transformResponse(resp *anthropicResponse) *types.ChatResponse {
 	var textContent string
 	var toolCalls []types.ToolCall
+	var reasoning *types.Reasoning
 
 	for _, block := range resp.Content {
 		switch block.Type {
 		case "text":
 			textContent += block.Text
+		case "thinking":
+			if reasoning == nil {
+				reasoning = &types.Reasoning{}
+			}
+			reasoning.Content += block.Thinking
+			reasoning.Signature = block.Signature
+		case "redacted_thinking":
+			if reasoning == nil {
+				reasoning = &types.Reasoning{}
+			}
+			reasoning.Redacted = append(reasoning.Redacted, block.Data)
 		case "tool_use":
 			inputJSON, err := json.Marshal(block.Input)
 			if err != nil {
@@ -469,6 +502,7 @@ func (p *Provider) transformResponse(resp *anthropicResponse) *types.ChatRespons
 			Index:        0,
 			Message:      message,
 			FinishReason: finishReason,
+			Reasoning:    reasoning,
 		}},
 		Usage: &types.Usage{
 			PromptTokens:     resp.Usage.InputTokens,
@@ -528,7 +562,8 @@ func (p *Provider) ParseStreamChunk(data []byte) (*types.StreamChunk, error) {
 		if !ok {
 			return nil, nil
 		}
-		if delta["type"] == "text_delta" {
+		switch delta["type"] {
+		case "text_delta":
 			text, ok := delta["text"].(string)
 			if !ok {
 				return nil, nil
@@ -540,6 +575,71 @@ func (p *Provider) ParseStreamChunk(data []byte) (*types.StreamChunk, error) {
 					Delta: types.StreamDelta{Content: text},
 				}},
 			}, nil
+		case "input_json_delta":
+			partial, _ := delta["partial_json"].(string)
+			if partial == "" {
+				return nil, nil
+			}
+			index := blockIndex(event)
+			return &types.StreamChunk{
+				Object: "chat.completion.chunk",
+				Choices: []types.StreamChoice{{
+					Index: 0,
+					Delta: types.StreamDelta{ToolCalls: []types.ToolCall{{
+						Index:    &index,
+						Function: types.ToolCallFunction{Arguments: partial},
+					}}},
+				}},
+			}, nil
+		case "thinking_delta", "signature_delta":
+			thinking, _ := delta["thinking"].(string)
+			signature, _ := delta["signature"].(string)
+			if thinking == "" && signature == "" {
+				return nil, nil
+			}
+			return &types.StreamChunk{
+				Object: "chat.completion.chunk",
+				Choices: []types.StreamChoice{{
+					Index: 0,
+					Delta: types.StreamDelta{Reasoning: &types.Reasoning{Content: thinking, Signature: signature}},
+				}},
+			}, nil
+		}
+
+	case "content_block_start":
+		block, ok := event["content_block"].(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		switch block["type"] {
+		case "tool_use":
+			// The call opens here; its input follows as input_json_delta
+			// fragments sharing the content block index.
+			id, _ := block["id"].(string)
+			name, _ := block["name"].(string)
+			index := blockIndex(event)
+			return &types.StreamChunk{
+				Object: "chat.completion.chunk",
+				Choices: []types.StreamChoice{{
+					Index: 0,
+					Delta: types.StreamDelta{ToolCalls: []types.ToolCall{{
+						Index:    &index,
+						ID:       id,
+						Type:     "function",
+						Function: types.ToolCallFunction{Name: name},
+					}}},
+				}},
+			}, nil
+		case "redacted_thinking":
+			// Redacted thinking arrives whole rather than as deltas.
+			data, _ := block["data"].(string)
+			return &types.StreamChunk{
+				Object: "chat.completion.chunk",
+				Choices: []types.StreamChoice{{
+					Index: 0,
+					Delta: types.StreamDelta{Reasoning: &types.Reasoning{Redacted: []string{data}}},
+				}},
+			}, nil
 		}
 
 	case "message_start":
@@ -618,3 +718,10 @@ func (p *Provider) MapError(statusCode int, body []byte) error {
 		return errors.NewInternalError(ProviderName, "", message)
 	}
 }
+
+// blockIndex returns the content block index of a stream event, which
+// identifies a tool call across its start and input deltas.
+func blockIndex(event map[string]any) int {
+	index, _ := event["index"].(float64)
+	return int(index)
+}
@@ -61,3 +61,46 @@ func TestTransformRequest_ThinkingMissingTypeIgnored(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, anthropicReq.Thinking)
 }
+
+func TestParseResponse_ThinkingBlocks(t *testing.T) {
+	p := New()
+	resp := p.transformResponse(&anthropicResponse{
+		ID:    "msg_1",
+		Model: "claude-sonnet-4-5",
+		Content: []contentBlock{
+			{Type: "thinking", Thinking: "Let me add 2 and 2.", Signature: "sig-1"},
+			{Type: "redacted_thinking", Data: "encrypted"},
+			{Type: "text", Text: "4"},
+		},
+		StopReason: "end_turn",
+		Usage:      anthropicUsage{InputTokens: 10, OutputTokens: 20},
+	})
+
+	require.Equal(t, "4", resp.Choices[0].Message.TextContent())
+	require.Equal(t, &types.Reasoning{
+		Content:   "Let me add 2 and 2.",
+		Signature: "sig-1",
+		Redacted:  []string{"encrypted"},
+	}, resp.Choices[0].Reasoning)
+}
+
+func TestParseStreamChunk_ThinkingDeltas(t *testing.T) {
+	p := New()
+
+	chunk, err := p.ParseStreamChunk([]byte(`data: {"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"Let me"}}`))
+	require.NoError(t, err)
+	require.Equal(t, &types.Reasoning{Content: "Let me"}, chunk.Choices[0].Delta.Reasoning)
+	require.Empty(t, chunk.Choices[0].Delta.Content)
+
+	chunk, err = p.ParseStreamChunk([]byte(`data: {"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig-1"}}`))
+	require.NoError(t, err)
+	require.Equal(t, &types.Reasoning{Signature: "sig-1"}, chunk.Choices[0].Delta.Reasoning)
+
+	chunk, err = p.ParseStreamChunk([]byte(`data: {"type":"content_block_start","index":1,"content_block":{"type":"redacted_thinking","data":"encrypted"}}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"encrypted"}, chunk.Choices[0].Delta.Reasoning.Redacted)
+
+	chunk, err = p.ParseStreamChunk([]byte(`data: {"type":"content_block_start","index":2,"content_block":{"type":"text","text":""}}`))
+	require.NoError(t, err)
+	require.Nil(t, chunk)
+}
@@ -0,0 +1,64 @@
+package anthropic
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+func TestParseStreamChunk_ToolUse(t *testing.T) {
+	p := New()
+
+	chunk, err := p.ParseStreamChunk([]byte(`data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{}}}`))
+	require.NoError(t, err)
+	require.Len(t, chunk.Choices[0].Delta.ToolCalls, 1)
+	call := chunk.Choices[0].Delta.ToolCalls[0]
+	require.Equal(t, 1, *call.Index)
+	require.Equal(t, "toolu_1", call.ID)
+	require.Equal(t, "function", call.Type)
+	require.Equal(t, "get_weather", call.Function.Name)
+
+	chunk, err = p.ParseStreamChunk([]byte(`data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`))
+	require.NoError(t, err)
+	call = chunk.Choices[0].Delta.ToolCalls[0]
+	require.Equal(t, 1, *call.Index)
+	require.Empty(t, call.ID)
+	require.Equal(t, `{"city":`, call.Function.Arguments)
+
+	chunk, err = p.ParseStreamChunk([]byte(`data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":""}}`))
+	require.NoError(t, err)
+	require.Nil(t, chunk)
+
+	chunk, err = p.ParseStreamChunk([]byte(`data: {"type":"message_delta","delta":{"stop_reason":"tool_use"}}`))
+	require.NoError(t, err)
+	require.Equal(t, "tool_calls", chunk.Choices[0].FinishReason)
+}
+
+func TestTransformRequest_AssistantToolCallKeepsText(t *testing.T) {
+	p := New()
+	req := &types.ChatRequest{
+		Model: "claude-3-5-sonnet",
+		Messages: []types.ChatMessage{
+			{Role: "user", Content: json.RawMessage(`"weather in Paris?"`)},
+			{Role: "assistant", Content: json.RawMessage(`"Checking."`), ToolCalls: []types.ToolCall{{
+				ID:       "call_1",
+				Type:     "function",
+				Function: types.ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+			}}},
+			{Role: "tool", ToolCallID: "call_1", Content: json.RawMessage(`"sunny"`)},
+		},
+	}
+
+	anthropicReq, err := p.transformRequest(req)
+	require.NoError(t, err)
+	blocks, ok := anthropicReq.Messages[1].Content.([]contentBlock)
+	require.True(t, ok)
+	require.Len(t, blocks, 2)
+	require.Equal(t, "text", blocks[0].Type)
+	require.Equal(t, "Checking.", blocks[0].Text)
+	require.Equal(t, "tool_use", blocks[1].Type)
+	require.Equal(t, "call_1", blocks[1].ID)
+}
@@ -0,0 +1,120 @@
+package anthropic
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// MessagesAPIVersion is the first api_version serving the Messages API.
+// Earlier versions such as "2023-01-01" only serve the legacy Text
+// Completions API, to which requests are translated.
+const MessagesAPIVersion = "2023-06-01"
+
+// datedAPIVersion matches Anthropic versions such as "2023-06-01".
+var datedAPIVersion = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// validateAPIVersion reports an error for an api_version that is not a date.
+func validateAPIVersion(version string) error {
+	if version != "" && !datedAPIVersion.MatchString(version) {
+		return fmt.Errorf("unsupported api_version %q", version)
+	}
+	return nil
+}
+
+// usesLegacyCompletions reports whether version predates the Messages API.
+// Dates are zero-padded, so they order as strings.
+func usesLegacyCompletions(version string) bool {
+	return version < MessagesAPIVersion
+}
+
+// completionRequest is a legacy Text Completions API request.
+type completionRequest struct {
+	Model             string    `json:"model"`
+	Prompt            string    `json:"prompt"`
+	MaxTokensToSample int       `json:"max_tokens_to_sample"`
+	StopSequences     []string  `json:"stop_sequences,omitempty"`
+	Temperature       *float64  `json:"temperature,omitempty"`
+	TopP              *float64  `json:"top_p,omitempty"`
+	Metadata          *metadata `json:"metadata,omitempty"`
+}
+
+// completionResponse is a legacy Text Completions API response.
+type completionResponse struct {
+	ID         string `json:"id"`
+	Completion string `json:"completion"`
+	StopReason string `json:"stop_reason"`
+	Model      string `json:"model"`
+}
+
+// transformCompletionRequest converts a chat request into a legacy
+// completion prompt of alternating Human and Assistant turns, with system
+// text ahead of the first turn. Tools have no legacy equivalent, and legacy
+// streams send cumulative text rather than deltas, so both are rejected.
+func (p *Provider) transformCompletionRequest(req *types.ChatRequest) (*completionRequest, error) {
+	if len(req.Tools) > 0 {
+		return nil, errors.NewInvalidRequestError(ProviderName, req.Model,
+			fmt.Sprintf("tools are not supported by api_version %s", p.apiVersion))
+	}
+	if req.Stream {
+		return nil, errors.NewInvalidRequestError(ProviderName, req.Model,
+			fmt.Sprintf("streaming is not supported by api_version %s", p.apiVersion))
+	}
+
+	var system, turns strings.Builder
+	for _, msg := range req.Messages {
+		text := msg.TextContent()
+		switch msg.Role {
+		case "system":
+			system.WriteString(text)
+		case "assistant":
+			turns.WriteString("\n\nAssistant: " + text)
+		default:
+			turns.WriteString("\n\nHuman: " + text)
+		}
+	}
+	turns.WriteString("\n\nAssistant:")
+
+	completionReq := &completionRequest{
+		Model:             req.Model,
+		Prompt:            system.String() + turns.String(),
+		MaxTokensToSample: DefaultMaxTokens,
+		StopSequences:     req.Stop,
+		Temperature:       req.Temperature,
+		TopP:              req.TopP,
+	}
+	if req.MaxTokens > 0 {
+		completionReq.MaxTokensToSample = req.MaxTokens
+	}
+	if req.User != "" {
+		completionReq.Metadata = &metadata{UserID: req.User}
+	}
+	return completionReq, nil
+}
+
+// parseCompletionResponse converts a legacy completion into a chat response.
+// The legacy API reports no token usage.
+func parseCompletionResponse(body []byte) (*types.ChatResponse, error) {
+	var resp completionResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	content, err := json.Marshal(strings.TrimPrefix(resp.Completion, " "))
+	if err != nil {
+		return nil, fmt.Errorf("marshal completion: %w", err)
+	}
+	return &types.ChatResponse{
+		ID:     resp.ID,
+		Object: "chat.completion",
+		Model:  resp.Model,
+		Choices: []types.Choice{{
+			Message:      types.ChatMessage{Role: "assistant", Content: content},
+			FinishReason: mapStopReason(resp.StopReason),
+		}},
+	}, nil
+}
@@ -0,0 +1,81 @@
+package anthropic
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/provider"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+func TestLegacyCompletions_RequestAndResponse(t *testing.T) {
+	p := New(WithAPIKey("k"), WithAPIVersion("2023-01-01"))
+	temperature := 0.5
+
+	httpReq, err := p.BuildRequest(context.Background(), &types.ChatRequest{
+		Model:       "claude-2",
+		MaxTokens:   100,
+		Temperature: &temperature,
+		Stop:        []string{"END"},
+		Messages: []types.ChatMessage{
+			{Role: "system", Content: json.RawMessage(`"Be brief."`)},
+			{Role: "user", Content: json.RawMessage(`"Hi"`)},
+			{Role: "assistant", Content: json.RawMessage(`"Hello"`)},
+			{Role: "user", Content: json.RawMessage(`"Bye"`)},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/v1/complete", httpReq.URL.Path)
+	require.Equal(t, "2023-01-01", httpReq.Header.Get("anthropic-version"))
+
+	body, err := io.ReadAll(httpReq.Body)
+	require.NoError(t, err)
+	var sent completionRequest
+	require.NoError(t, json.Unmarshal(body, &sent))
+	require.Equal(t, "Be brief.\n\nHuman: Hi\n\nAssistant: Hello\n\nHuman: Bye\n\nAssistant:", sent.Prompt)
+	require.Equal(t, 100, sent.MaxTokensToSample)
+	require.Equal(t, []string{"END"}, sent.StopSequences)
+	require.Equal(t, 0.5, *sent.Temperature)
+
+	resp, err := p.ParseResponse(&http.Response{Body: io.NopCloser(strings.NewReader(
+		`{"id":"compl_1","completion":" Goodbye","stop_reason":"stop_sequence","model":"claude-2"}`))})
+	require.NoError(t, err)
+	require.Equal(t, "compl_1", resp.ID)
+	require.Equal(t, "Goodbye", resp.Choices[0].Message.TextContent())
+	require.Equal(t, "stop", resp.Choices[0].FinishReason)
+}
+
+func TestLegacyCompletions_RejectsToolsAndStreaming(t *testing.T) {
+	p := New(WithAPIVersion("2023-01-01"))
+	messages := []types.ChatMessage{{Role: "user", Content: json.RawMessage(`"Hi"`)}}
+
+	_, err := p.BuildRequest(context.Background(), &types.ChatRequest{Model: "claude-2", Messages: messages, Stream: true})
+	require.ErrorContains(t, err, "streaming is not supported")
+
+	_, err = p.BuildRequest(context.Background(), &types.ChatRequest{
+		Model:    "claude-2",
+		Messages: messages,
+		Tools:    []types.Tool{{Type: "function", Function: types.ToolFunction{Name: "f"}}},
+	})
+	require.ErrorContains(t, err, "tools are not supported")
+}
+
+func TestNewFromConfig_APIVersion(t *testing.T) {
+	p, err := NewFromConfig(provider.Config{APIKey: "k", APIVersion: "2023-06-01"})
+	require.NoError(t, err)
+	httpReq, err := p.BuildRequest(context.Background(), &types.ChatRequest{
+		Model:    "claude-3-haiku-20240307",
+		Messages: []types.ChatMessage{{Role: "user", Content: json.RawMessage(`"Hi"`)}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/v1/messages", httpReq.URL.Path)
+
+	_, err = NewFromConfig(provider.Config{APIVersion: "v2"})
+	require.ErrorContains(t, err, "unsupported api_version")
+}
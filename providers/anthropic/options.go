@@ -36,7 +36,8 @@ func WithModels(models ...string) Option {
 	}
 }
 
-// WithAPIVersion sets the Anthropic API version.
+// WithAPIVersion sets the Anthropic API version. Versions before
+// MessagesAPIVersion are served through the legacy Text Completions API.
 func WithAPIVersion(version string) Option {
 	return func(p *Provider) {
 		if version != "" {
@@ -17,6 +17,7 @@ import (
 	"github.com/blueberrycongee/llmux/pkg/errors"
 	"github.com/blueberrycongee/llmux/pkg/provider"
 	"github.com/blueberrycongee/llmux/pkg/types"
+	"github.com/blueberrycongee/llmux/providers/openai"
 )
 
 const (
@@ -52,12 +53,13 @@ func NewFromConfig(cfg provider.Config) (provider.Provider, error) {
 		WithAPIKey(cfg.APIKey),
 		WithBaseURL(cfg.BaseURL),
 		WithModels(cfg.Models...),
+		WithAPIVersion(cfg.APIVersion),
 	}
 	if cfg.TokenSource != nil {
 		opts = append(opts, WithTokenSource(cfg.TokenSource))
 	}
 	p := New(opts...)
-	if v, ok := cfg.Headers["api-version"]; ok {
+	if v, ok := cfg.Headers["api-version"]; ok && cfg.APIVersion == "" {
 		p.apiVersion = v
 	}
 	for k, v := range cfg.Headers {
@@ -91,7 +93,12 @@ func (p *Provider) BuildRequest(ctx context.Context, req *types.ChatRequest) (*h
 	q.Set("api-version", p.apiVersion)
 	base.RawQuery = q.Encode()
 
-	body, err := json.Marshal(req)
+	var body []byte
+	if p.usesLegacyFunctions() {
+		body, err = openai.MarshalLegacyFunctionsRequest(req)
+	} else {
+		body, err = json.Marshal(req)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
@@ -124,9 +131,20 @@ func (p *Provider) ParseResponse(resp *http.Response) (*types.ChatResponse, erro
 	if err := json.Unmarshal(body, &chatResp); err != nil {
 		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
+	if p.usesLegacyFunctions() {
+		if err := openai.UpgradeLegacyResponse(&chatResp, body); err != nil {
+			return nil, fmt.Errorf("upgrade legacy response: %w", err)
+		}
+	}
 	return &chatResp, nil
 }
 
+// StreamPassthrough reports whether stream events can be forwarded unchanged.
+// Chunks of legacy function-calling versions must be upgraded first.
+func (p *Provider) StreamPassthrough() bool {
+	return !p.usesLegacyFunctions()
+}
+
 func (p *Provider) ParseStreamChunk(data []byte) (*types.StreamChunk, error) {
 	trimmed := bytes.TrimSpace(data)
 	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("[DONE]")) {
@@ -142,9 +160,21 @@ func (p *Provider) ParseStreamChunk(data []byte) (*types.StreamChunk, error) {
 	if err := json.Unmarshal(trimmed, &chunk); err != nil {
 		return nil, fmt.Errorf("unmarshal chunk: %w", err)
 	}
+	if p.usesLegacyFunctions() {
+		if err := openai.UpgradeLegacyChunk(&chunk, trimmed); err != nil {
+			return nil, fmt.Errorf("upgrade legacy chunk: %w", err)
+		}
+	}
 	return &chunk, nil
 }
 
+// usesLegacyFunctions reports whether the pinned api-version predates tool
+// calling. Versions that are not dated, such as "v1", support tools.
+func (p *Provider) usesLegacyFunctions() bool {
+	format, err := openai.ResolveAPIVersion(p.apiVersion)
+	return err == nil && format == openai.APIVersionLegacyFunctions
+}
+
 func (p *Provider) MapError(statusCode int, body []byte) error {
 	var errResp struct {
 		Error struct {
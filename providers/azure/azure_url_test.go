@@ -2,6 +2,7 @@ package azure
 
 import (
 	"context"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -32,3 +33,25 @@ func TestBuildRequest_EscapesDeploymentNameAndUsesQueryParams(t *testing.T) {
 	require.Len(t, req.URL.Query(), 1)
 	require.Equal(t, "bar", req.Header.Get("X-Foo"))
 }
+
+func TestBuildRequest_LegacyAPIVersionUsesFunctions(t *testing.T) {
+	pAny, err := NewFromConfig(provider.Config{
+		APIKey:     "k",
+		BaseURL:    "https://example.com",
+		APIVersion: "2023-07-01-preview",
+	})
+	require.NoError(t, err)
+	p := pAny.(*Provider)
+
+	req, err := p.BuildRequest(context.Background(), &types.ChatRequest{
+		Model: "dep",
+		Tools: []types.Tool{{Type: "function", Function: types.ToolFunction{Name: "f"}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "2023-07-01-preview", req.URL.Query().Get("api-version"))
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `"functions"`)
+	require.NotContains(t, string(body), `"tools"`)
+}
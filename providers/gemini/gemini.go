@@ -51,6 +51,7 @@ func NewFromConfig(cfg provider.Config) (provider.Provider, error) {
 		WithAPIKey(cfg.APIKey),
 		WithBaseURL(cfg.BaseURL),
 		WithModels(cfg.Models...),
+		WithAPIVersion(cfg.APIVersion),
 	}
 	if cfg.TokenSource != nil {
 		opts = append(opts, WithTokenSource(cfg.TokenSource))
@@ -76,6 +77,8 @@ func (p *Provider) SupportsModel(model string) bool {
 type geminiRequest struct {
 	Contents          []geminiContent   `json:"contents"`
 	SystemInstruction *geminiContent    `json:"systemInstruction,omitempty"`
+	Tools             []geminiTool      `json:"tools,omitempty"`
+	ToolConfig        *toolConfig       `json:"toolConfig,omitempty"`
 	GenerationConfig  *generationConfig `json:"generationConfig,omitempty"`
 }
 
@@ -85,7 +88,9 @@ type geminiContent struct {
 }
 
 type geminiPart struct {
-	Text string `json:"text,omitempty"`
+	Text             string            `json:"text,omitempty"`
+	FunctionCall     *functionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *functionResponse `json:"functionResponse,omitempty"`
 }
 
 type generationConfig struct {
@@ -162,7 +167,15 @@ func (p *Provider) transformRequest(req *types.ChatRequest) *geminiRequest {
 	if len(req.Stop) > 0 {
 		geminiReq.GenerationConfig.StopSequences = req.Stop
 	}
+	if len(req.Tools) > 0 {
+		geminiReq.Tools = transformTools(req.Tools)
+	}
+	if len(req.ToolChoice) > 0 {
+		geminiReq.ToolConfig = transformToolChoice(req.ToolChoice)
+	}
 
+	// Function responses carry the function name rather than the call ID.
+	toolNames := make(map[string]string)
 	for _, msg := range req.Messages {
 		if msg.Role == "system" {
 			var content string
@@ -171,6 +184,20 @@ func (p *Provider) transformRequest(req *types.ChatRequest) *geminiRequest {
 			}
 			continue
 		}
+		if msg.Role == "assistant" && len(msg.ToolCalls) > 0 {
+			geminiReq.Contents = append(geminiReq.Contents, functionCallContent(msg, toolNames))
+			continue
+		}
+		if msg.Role == "tool" {
+			part := geminiPart{FunctionResponse: toFunctionResponse(msg, toolNames)}
+			// Results of parallel calls must share one turn.
+			if n := len(geminiReq.Contents); n > 0 && isFunctionResponseTurn(geminiReq.Contents[n-1]) {
+				geminiReq.Contents[n-1].Parts = append(geminiReq.Contents[n-1].Parts, part)
+			} else {
+				geminiReq.Contents = append(geminiReq.Contents, geminiContent{Role: "user", Parts: []geminiPart{part}})
+			}
+			continue
+		}
 		role := msg.Role
 		if role == "assistant" {
 			role = "model"
@@ -205,10 +232,15 @@ func (p *Provider) transformResponse(resp *geminiResponse) *types.ChatResponse {
 		for _, part := range c.Content.Parts {
 			text += part.Text
 		}
+		toolCalls := toToolCalls(c.Content.Parts)
 		choices = append(choices, types.Choice{
-			Index:        i,
-			Message:      types.ChatMessage{Role: "assistant", Content: json.RawMessage(fmt.Sprintf("%q", text))},
-			FinishReason: mapFinishReason(c.FinishReason),
+			Index: i,
+			Message: types.ChatMessage{
+				Role:      "assistant",
+				Content:   json.RawMessage(fmt.Sprintf("%q", text)),
+				ToolCalls: toolCalls,
+			},
+			FinishReason: finishReason(c.FinishReason, len(toolCalls) > 0),
 		})
 	}
 	chatResp := &types.ChatResponse{Object: "chat.completion", Choices: choices}
@@ -222,6 +254,15 @@ func (p *Provider) transformResponse(resp *geminiResponse) *types.ChatResponse {
 	return chatResp
 }
 
+// finishReason maps reason, reporting a turn that ended in function calls
+// the way OpenAI does.
+func finishReason(reason string, hasToolCalls bool) string {
+	if hasToolCalls && (reason == "STOP" || reason == "") {
+		return "tool_calls"
+	}
+	return mapFinishReason(reason)
+}
+
 func mapFinishReason(reason string) string {
 	switch reason {
 	case "STOP":
@@ -252,12 +293,19 @@ func (p *Provider) ParseStreamChunk(data []byte) (*types.StreamChunk, error) {
 	for _, part := range c.Content.Parts {
 		text += part.Text
 	}
+	// Gemini streams each function call whole, so every call is a complete
+	// delta indexed by its position in the chunk.
+	toolCalls := toToolCalls(c.Content.Parts)
+	for i := range toolCalls {
+		index := i
+		toolCalls[i].Index = &index
+	}
 	chunk := &types.StreamChunk{
 		Object:  "chat.completion.chunk",
-		Choices: []types.StreamChoice{{Index: 0, Delta: types.StreamDelta{Content: text}}},
+		Choices: []types.StreamChoice{{Index: 0, Delta: types.StreamDelta{Content: text, ToolCalls: toolCalls}}},
 	}
 	if c.FinishReason != "" {
-		chunk.Choices[0].FinishReason = mapFinishReason(c.FinishReason)
+		chunk.Choices[0].FinishReason = finishReason(c.FinishReason, len(toolCalls) > 0)
 	}
 	return chunk, nil
 }
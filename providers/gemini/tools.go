@@ -0,0 +1,257 @@
+package gemini
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+type geminiTool struct {
+	FunctionDeclarations []functionDeclaration `json:"functionDeclarations"`
+}
+
+type functionDeclaration struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+type toolConfig struct {
+	FunctionCallingConfig functionCallingConfig `json:"functionCallingConfig"`
+}
+
+type functionCallingConfig struct {
+	Mode                 string   `json:"mode"`
+	AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
+}
+
+type functionCall struct {
+	ID   string          `json:"id,omitempty"`
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type functionResponse struct {
+	ID       string          `json:"id,omitempty"`
+	Name     string          `json:"name"`
+	Response json.RawMessage `json:"response"`
+}
+
+// schemaFields are the JSON Schema keywords Gemini accepts in function
+// parameters; it rejects requests carrying any other, such as
+// additionalProperties or $schema.
+var schemaFields = map[string]bool{
+	"type":             true,
+	"format":           true,
+	"title":            true,
+	"description":      true,
+	"nullable":         true,
+	"enum":             true,
+	"items":            true,
+	"minItems":         true,
+	"maxItems":         true,
+	"properties":       true,
+	"required":         true,
+	"propertyOrdering": true,
+	"minimum":          true,
+	"maximum":          true,
+	"minLength":        true,
+	"maxLength":        true,
+	"pattern":          true,
+	"anyOf":            true,
+}
+
+// transformTools converts OpenAI function tools into Gemini function
+// declarations.
+func transformTools(tools []types.Tool) []geminiTool {
+	declarations := make([]functionDeclaration, 0, len(tools))
+	for _, tool := range tools {
+		if tool.Type != "function" {
+			continue
+		}
+		decl := functionDeclaration{Name: tool.Function.Name, Description: tool.Function.Description}
+		var params map[string]any
+		if err := json.Unmarshal(tool.Function.Parameters, &params); err == nil {
+			// Gemini rejects object parameters without properties.
+			if props, _ := params["properties"].(map[string]any); len(props) > 0 {
+				decl.Parameters = cleanSchema(params)
+			}
+		}
+		declarations = append(declarations, decl)
+	}
+	if len(declarations) == 0 {
+		return nil
+	}
+	return []geminiTool{{FunctionDeclarations: declarations}}
+}
+
+// cleanSchema returns schema restricted to the keywords Gemini accepts. A
+// nullable type union such as ["string", "null"] becomes a nullable type.
+func cleanSchema(schema map[string]any) map[string]any {
+	cleaned := make(map[string]any, len(schema))
+	for key, value := range schema {
+		if !schemaFields[key] {
+			continue
+		}
+		switch key {
+		case "type":
+			if union, ok := value.([]any); ok {
+				for _, t := range union {
+					if t == "null" {
+						cleaned["nullable"] = true
+					} else if _, set := cleaned["type"]; !set {
+						cleaned["type"] = t
+					}
+				}
+				continue
+			}
+		case "items":
+			if items, ok := value.(map[string]any); ok {
+				value = cleanSchema(items)
+			}
+		case "properties":
+			if props, ok := value.(map[string]any); ok {
+				cleanedProps := make(map[string]any, len(props))
+				for name, prop := range props {
+					if propSchema, ok := prop.(map[string]any); ok {
+						cleanedProps[name] = cleanSchema(propSchema)
+					}
+				}
+				value = cleanedProps
+			}
+		case "anyOf":
+			if variants, ok := value.([]any); ok {
+				cleanedVariants := make([]any, 0, len(variants))
+				for _, variant := range variants {
+					if variantSchema, ok := variant.(map[string]any); ok {
+						cleanedVariants = append(cleanedVariants, cleanSchema(variantSchema))
+					}
+				}
+				value = cleanedVariants
+			}
+		}
+		cleaned[key] = value
+	}
+	return cleaned
+}
+
+// transformToolChoice converts an OpenAI tool_choice into a Gemini function
+// calling mode.
+func transformToolChoice(raw json.RawMessage) *toolConfig {
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		switch str {
+		case "auto":
+			return &toolConfig{FunctionCallingConfig: functionCallingConfig{Mode: "AUTO"}}
+		case "required":
+			return &toolConfig{FunctionCallingConfig: functionCallingConfig{Mode: "ANY"}}
+		case "none":
+			return &toolConfig{FunctionCallingConfig: functionCallingConfig{Mode: "NONE"}}
+		}
+		return nil
+	}
+
+	var obj struct {
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil || obj.Function.Name == "" {
+		return nil
+	}
+	return &toolConfig{FunctionCallingConfig: functionCallingConfig{
+		Mode:                 "ANY",
+		AllowedFunctionNames: []string{obj.Function.Name},
+	}}
+}
+
+// functionCallContent converts an assistant message with tool calls into a
+// model turn of function calls, recording the name of each call ID in
+// toolNames.
+func functionCallContent(msg types.ChatMessage, toolNames map[string]string) geminiContent {
+	content := geminiContent{Role: "model"}
+	if text := msg.TextContent(); text != "" {
+		content.Parts = append(content.Parts, geminiPart{Text: text})
+	}
+	for _, tc := range msg.ToolCalls {
+		toolNames[tc.ID] = tc.Function.Name
+		args := json.RawMessage(strings.TrimSpace(tc.Function.Arguments))
+		if !isJSONObject(args) {
+			args = json.RawMessage("{}")
+		}
+		content.Parts = append(content.Parts, geminiPart{FunctionCall: &functionCall{
+			ID:   tc.ID,
+			Name: tc.Function.Name,
+			Args: args,
+		}})
+	}
+	return content
+}
+
+// toFunctionResponse converts a tool message into a function response.
+// Gemini expects an object, so other results are wrapped in one.
+func toFunctionResponse(msg types.ChatMessage, toolNames map[string]string) *functionResponse {
+	text := msg.TextContent()
+	response := json.RawMessage(strings.TrimSpace(text))
+	if !isJSONObject(response) {
+		wrapped, err := json.Marshal(map[string]string{"content": text})
+		if err != nil {
+			wrapped = []byte("{}")
+		}
+		response = wrapped
+	}
+	return &functionResponse{
+		ID:       msg.ToolCallID,
+		Name:     toolNames[msg.ToolCallID],
+		Response: response,
+	}
+}
+
+func isFunctionResponseTurn(content geminiContent) bool {
+	if content.Role != "user" || len(content.Parts) == 0 {
+		return false
+	}
+	for _, part := range content.Parts {
+		if part.FunctionResponse == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// toToolCalls converts the function calls among parts into tool calls.
+// Gemini only returns call IDs on some models, so missing ones are
+// generated for the tool results to reference.
+func toToolCalls(parts []geminiPart) []types.ToolCall {
+	var calls []types.ToolCall
+	for _, part := range parts {
+		if part.FunctionCall == nil {
+			continue
+		}
+		id := part.FunctionCall.ID
+		if id == "" {
+			id = "call_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+		}
+		args := string(part.FunctionCall.Args)
+		if args == "" || args == "null" {
+			args = "{}"
+		}
+		calls = append(calls, types.ToolCall{
+			ID:   id,
+			Type: "function",
+			Function: types.ToolCallFunction{
+				Name:      part.FunctionCall.Name,
+				Arguments: args,
+			},
+		})
+	}
+	return calls
+}
+
+func isJSONObject(raw json.RawMessage) bool {
+	return bytes.HasPrefix(raw, []byte("{")) && json.Valid(raw)
+}
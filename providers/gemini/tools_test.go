@@ -0,0 +1,125 @@
+package gemini
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+func TestTransformRequest_Tools(t *testing.T) {
+	p := New()
+	req := &types.ChatRequest{
+		Model: "gemini-2.0-flash",
+		Tools: []types.Tool{
+			{Type: "function", Function: types.ToolFunction{
+				Name:        "get_weather",
+				Description: "Current weather",
+				Parameters: json.RawMessage(`{"$schema":"http://json-schema.org/draft-07/schema#","type":"object","additionalProperties":false,` +
+					`"properties":{"city":{"type":"string"},"unit":{"type":["string","null"],"enum":["c","f"]},"days":{"type":"array","items":{"type":"integer","additionalProperties":false}}},"required":["city"]}`),
+			}},
+			{Type: "function", Function: types.ToolFunction{Name: "get_time", Parameters: json.RawMessage(`{"type":"object","properties":{}}`)}},
+		},
+		ToolChoice: json.RawMessage(`{"type":"function","function":{"name":"get_weather"}}`),
+		Messages: []types.ChatMessage{
+			{Role: "user", Content: json.RawMessage(`"weather and time in Paris?"`)},
+			{Role: "assistant", Content: json.RawMessage(`null`), ToolCalls: []types.ToolCall{
+				{ID: "call_1", Type: "function", Function: types.ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+				{ID: "call_2", Type: "function", Function: types.ToolCallFunction{Name: "get_time", Arguments: ``}},
+			}},
+			{Role: "tool", ToolCallID: "call_1", Content: json.RawMessage(`"{\"temp\":21}"`)},
+			{Role: "tool", ToolCallID: "call_2", Content: json.RawMessage(`"10:00"`)},
+		},
+	}
+
+	geminiReq := p.transformRequest(req)
+
+	require.Len(t, geminiReq.Tools, 1)
+	decls := geminiReq.Tools[0].FunctionDeclarations
+	require.Len(t, decls, 2)
+	require.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"city": map[string]any{"type": "string"},
+			"unit": map[string]any{"type": "string", "nullable": true, "enum": []any{"c", "f"}},
+			"days": map[string]any{"type": "array", "items": map[string]any{"type": "integer"}},
+		},
+		"required": []any{"city"},
+	}, decls[0].Parameters)
+	require.Nil(t, decls[1].Parameters)
+	require.Equal(t, &toolConfig{FunctionCallingConfig: functionCallingConfig{Mode: "ANY", AllowedFunctionNames: []string{"get_weather"}}}, geminiReq.ToolConfig)
+
+	require.Len(t, geminiReq.Contents, 3)
+	calls := geminiReq.Contents[1]
+	require.Equal(t, "model", calls.Role)
+	require.Len(t, calls.Parts, 2)
+	require.Equal(t, "get_weather", calls.Parts[0].FunctionCall.Name)
+	require.JSONEq(t, `{"city":"Paris"}`, string(calls.Parts[0].FunctionCall.Args))
+	require.JSONEq(t, `{}`, string(calls.Parts[1].FunctionCall.Args))
+
+	results := geminiReq.Contents[2]
+	require.Equal(t, "user", results.Role)
+	require.Len(t, results.Parts, 2)
+	require.Equal(t, "get_weather", results.Parts[0].FunctionResponse.Name)
+	require.JSONEq(t, `{"temp":21}`, string(results.Parts[0].FunctionResponse.Response))
+	require.Equal(t, "get_time", results.Parts[1].FunctionResponse.Name)
+	require.JSONEq(t, `{"content":"10:00"}`, string(results.Parts[1].FunctionResponse.Response))
+}
+
+func TestTransformToolChoice(t *testing.T) {
+	require.Equal(t, "AUTO", transformToolChoice(json.RawMessage(`"auto"`)).FunctionCallingConfig.Mode)
+	require.Equal(t, "ANY", transformToolChoice(json.RawMessage(`"required"`)).FunctionCallingConfig.Mode)
+	require.Equal(t, "NONE", transformToolChoice(json.RawMessage(`"none"`)).FunctionCallingConfig.Mode)
+	require.Nil(t, transformToolChoice(json.RawMessage(`"unknown"`)))
+	require.Nil(t, transformToolChoice(json.RawMessage(`{"type":"function"}`)))
+}
+
+func TestParseResponse_FunctionCalls(t *testing.T) {
+	p := New()
+	body := `{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"get_weather","args":{"city":"Paris"}}},{"functionCall":{"id":"fc-2","name":"get_time"}}]},"finishReason":"STOP"}]}`
+	resp, err := p.ParseResponse(&http.Response{Body: io.NopCloser(strings.NewReader(body))})
+	require.NoError(t, err)
+
+	choice := resp.Choices[0]
+	require.Equal(t, "tool_calls", choice.FinishReason)
+	require.Len(t, choice.Message.ToolCalls, 2)
+	first := choice.Message.ToolCalls[0]
+	require.True(t, strings.HasPrefix(first.ID, "call_"))
+	require.Equal(t, "function", first.Type)
+	require.Equal(t, "get_weather", first.Function.Name)
+	require.JSONEq(t, `{"city":"Paris"}`, first.Function.Arguments)
+	require.Nil(t, first.Index)
+	require.Equal(t, "fc-2", choice.Message.ToolCalls[1].ID)
+	require.Equal(t, "{}", choice.Message.ToolCalls[1].Function.Arguments)
+
+	// The generated IDs map tool results back to their function names.
+	geminiReq := p.transformRequest(&types.ChatRequest{Messages: []types.ChatMessage{
+		choice.Message,
+		{Role: "tool", ToolCallID: first.ID, Content: json.RawMessage(`"sunny"`)},
+	}})
+	require.Equal(t, "get_weather", geminiReq.Contents[1].Parts[0].FunctionResponse.Name)
+}
+
+func TestParseStreamChunk_FunctionCalls(t *testing.T) {
+	p := New()
+	chunk, err := p.ParseStreamChunk([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"a","args":{}}},{"functionCall":{"name":"b","args":{"x":1}}}]},"finishReason":"STOP"}]}`))
+	require.NoError(t, err)
+
+	choice := chunk.Choices[0]
+	require.Equal(t, "tool_calls", choice.FinishReason)
+	require.Len(t, choice.Delta.ToolCalls, 2)
+	require.Equal(t, 0, *choice.Delta.ToolCalls[0].Index)
+	require.Equal(t, 1, *choice.Delta.ToolCalls[1].Index)
+	require.Equal(t, "b", choice.Delta.ToolCalls[1].Function.Name)
+	require.JSONEq(t, `{"x":1}`, choice.Delta.ToolCalls[1].Function.Arguments)
+
+	encoded, err := json.Marshal(chunk)
+	require.NoError(t, err)
+	require.True(t, bytes.Contains(encoded, []byte(`"index":1`)))
+}
@@ -0,0 +1,219 @@
+package openai
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+const (
+	// APIVersionTools is the current wire format using tools/tool_calls.
+	APIVersionTools = "tools"
+
+	// APIVersionLegacyFunctions is the deprecated wire format using
+	// functions/function_call. Requests are still accepted in the unified
+	// tools format and translated on the way out and back in.
+	APIVersionLegacyFunctions = "functions"
+
+	// DefaultAPIVersion is the wire format used when none is configured.
+	DefaultAPIVersion = APIVersionTools
+
+	// ToolsAPIVersionDate is the first dated api_version accepting
+	// tools/tool_calls, as on Azure OpenAI.
+	ToolsAPIVersionDate = "2023-12-01"
+)
+
+// datedAPIVersion matches dated versions such as "2024-06-01" and
+// "2024-02-15-preview".
+var datedAPIVersion = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(-preview)?$`)
+
+// ResolveAPIVersion returns the wire format of an api_version: a format name
+// or a dated version, where dates before ToolsAPIVersionDate select the
+// legacy functions format. Empty selects DefaultAPIVersion.
+func ResolveAPIVersion(version string) (string, error) {
+	switch {
+	case version == "":
+		return DefaultAPIVersion, nil
+	case version == APIVersionTools, version == APIVersionLegacyFunctions:
+		return version, nil
+	case datedAPIVersion.MatchString(version):
+		// Dates are zero-padded, so they order as strings.
+		if version[:len(ToolsAPIVersionDate)] < ToolsAPIVersionDate {
+			return APIVersionLegacyFunctions, nil
+		}
+		return APIVersionTools, nil
+	default:
+		return "", fmt.Errorf("unsupported api_version %q", version)
+	}
+}
+
+// legacyFunctionCall is the single function call emitted by the legacy format.
+type legacyFunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+// legacyMessage is a chat message in the legacy functions format.
+type legacyMessage struct {
+	Role         string              `json:"role"`
+	Content      json.RawMessage     `json:"content"`
+	Name         string              `json:"name,omitempty"`
+	FunctionCall *legacyFunctionCall `json:"function_call,omitempty"`
+}
+
+// legacyEnvelope captures the function_call fields of a legacy response or chunk.
+type legacyEnvelope struct {
+	Choices []struct {
+		Message struct {
+			FunctionCall *legacyFunctionCall `json:"function_call"`
+		} `json:"message"`
+		Delta struct {
+			FunctionCall *legacyFunctionCall `json:"function_call"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+// MarshalLegacyFunctionsRequest encodes a unified request using the legacy
+// functions/function_call format. Only the first tool call of an assistant
+// message is kept because the legacy format cannot express parallel calls.
+func MarshalLegacyFunctionsRequest(req *types.ChatRequest) ([]byte, error) {
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, err
+	}
+
+	delete(payload, "tools")
+	delete(payload, "tool_choice")
+
+	if len(req.Tools) > 0 {
+		functions := make([]types.ToolFunction, 0, len(req.Tools))
+		for _, tool := range req.Tools {
+			functions = append(functions, tool.Function)
+		}
+		if payload["functions"], err = json.Marshal(functions); err != nil {
+			return nil, err
+		}
+	}
+
+	if functionCall := legacyFunctionChoice(req.ToolChoice); functionCall != nil {
+		payload["function_call"] = functionCall
+	}
+
+	callNames := make(map[string]string)
+	messages := make([]legacyMessage, 0, len(req.Messages))
+	for _, msg := range req.Messages {
+		lm := legacyMessage{Role: msg.Role, Content: msg.Content, Name: msg.Name}
+		switch {
+		case len(msg.ToolCalls) > 0:
+			for _, call := range msg.ToolCalls {
+				callNames[call.ID] = call.Function.Name
+			}
+			first := msg.ToolCalls[0].Function
+			lm.FunctionCall = &legacyFunctionCall{Name: first.Name, Arguments: first.Arguments}
+		case msg.Role == "tool":
+			lm.Role = "function"
+			if lm.Name == "" {
+				lm.Name = callNames[msg.ToolCallID]
+			}
+		}
+		messages = append(messages, lm)
+	}
+	if payload["messages"], err = json.Marshal(messages); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(payload)
+}
+
+// legacyFunctionChoice maps a tool_choice value onto function_call.
+// "required" has no legacy equivalent and is dropped, leaving the default.
+func legacyFunctionChoice(choice json.RawMessage) json.RawMessage {
+	if len(choice) == 0 {
+		return nil
+	}
+	var mode string
+	if err := json.Unmarshal(choice, &mode); err == nil {
+		if mode == "auto" || mode == "none" {
+			return choice
+		}
+		return nil
+	}
+	var named struct {
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(choice, &named); err != nil || named.Function.Name == "" {
+		return nil
+	}
+	out, err := json.Marshal(map[string]string{"name": named.Function.Name})
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// UpgradeLegacyResponse rewrites function_call fields found in raw into
+// tool_calls on resp, so callers only ever see the current format.
+func UpgradeLegacyResponse(resp *types.ChatResponse, raw []byte) error {
+	var env legacyEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return err
+	}
+	for i := range resp.Choices {
+		if i >= len(env.Choices) {
+			break
+		}
+		call := env.Choices[i].Message.FunctionCall
+		if call == nil || len(resp.Choices[i].Message.ToolCalls) > 0 {
+			continue
+		}
+		resp.Choices[i].Message.ToolCalls = []types.ToolCall{{
+			ID:       legacyCallID(resp.ID, resp.Choices[i].Index),
+			Type:     "function",
+			Function: types.ToolCallFunction{Name: call.Name, Arguments: call.Arguments},
+		}}
+		if resp.Choices[i].FinishReason == "function_call" {
+			resp.Choices[i].FinishReason = "tool_calls"
+		}
+	}
+	return nil
+}
+
+// UpgradeLegacyChunk is the streaming counterpart of UpgradeLegacyResponse.
+// The call ID is only attached to the delta that opens the call.
+func UpgradeLegacyChunk(chunk *types.StreamChunk, raw []byte) error {
+	var env legacyEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return err
+	}
+	for i := range chunk.Choices {
+		if i < len(env.Choices) {
+			if call := env.Choices[i].Delta.FunctionCall; call != nil && len(chunk.Choices[i].Delta.ToolCalls) == 0 {
+				tc := types.ToolCall{
+					Type:     "function",
+					Function: types.ToolCallFunction{Name: call.Name, Arguments: call.Arguments},
+				}
+				if call.Name != "" {
+					tc.ID = legacyCallID(chunk.ID, chunk.Choices[i].Index)
+				}
+				chunk.Choices[i].Delta.ToolCalls = []types.ToolCall{tc}
+			}
+		}
+		if chunk.Choices[i].FinishReason == "function_call" {
+			chunk.Choices[i].FinishReason = "tool_calls"
+		}
+	}
+	return nil
+}
+
+func legacyCallID(responseID string, index int) string {
+	return fmt.Sprintf("call_%s_%d", responseID, index)
+}
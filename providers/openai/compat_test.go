@@ -0,0 +1,118 @@
+package openai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/provider"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+func TestBuildRequest_LegacyFunctionsTranslatesTools(t *testing.T) {
+	p := New(WithAPIKey("k"), WithAPIVersion(APIVersionLegacyFunctions))
+
+	req := &types.ChatRequest{
+		Model: "gpt-4",
+		Messages: []types.ChatMessage{
+			{Role: "user", Content: json.RawMessage(`"weather?"`)},
+			{Role: "assistant", ToolCalls: []types.ToolCall{{
+				ID:       "call_1",
+				Type:     "function",
+				Function: types.ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+			}}},
+			{Role: "tool", ToolCallID: "call_1", Content: json.RawMessage(`"sunny"`)},
+		},
+		Tools: []types.Tool{{
+			Type:     "function",
+			Function: types.ToolFunction{Name: "get_weather", Parameters: json.RawMessage(`{"type":"object"}`)},
+		}},
+		ToolChoice: json.RawMessage(`{"type":"function","function":{"name":"get_weather"}}`),
+	}
+
+	httpReq, err := p.BuildRequest(context.Background(), req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(httpReq.Body)
+	require.NoError(t, err)
+
+	var payload struct {
+		Tools        json.RawMessage `json:"tools"`
+		ToolChoice   json.RawMessage `json:"tool_choice"`
+		Functions    []map[string]any
+		FunctionCall map[string]string `json:"function_call"`
+		Messages     []map[string]any
+	}
+	require.NoError(t, json.Unmarshal(body, &payload))
+
+	assert.Nil(t, payload.Tools)
+	assert.Nil(t, payload.ToolChoice)
+	require.Len(t, payload.Functions, 1)
+	assert.Equal(t, "get_weather", payload.Functions[0]["name"])
+	assert.Equal(t, map[string]string{"name": "get_weather"}, payload.FunctionCall)
+
+	require.Len(t, payload.Messages, 3)
+	call := payload.Messages[1]["function_call"].(map[string]any)
+	assert.Equal(t, "get_weather", call["name"])
+	assert.Equal(t, "function", payload.Messages[2]["role"])
+	assert.Equal(t, "get_weather", payload.Messages[2]["name"])
+}
+
+func TestParseResponse_LegacyFunctionCallBecomesToolCall(t *testing.T) {
+	p := New(WithAPIVersion(APIVersionLegacyFunctions))
+	body := `{"id":"resp1","choices":[{"index":0,"finish_reason":"function_call","message":{"role":"assistant","content":null,"function_call":{"name":"get_weather","arguments":"{}"}}}]}`
+
+	resp, err := p.ParseResponse(&http.Response{Body: io.NopCloser(strings.NewReader(body))})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices[0].Message.ToolCalls, 1)
+	tc := resp.Choices[0].Message.ToolCalls[0]
+	assert.Equal(t, "call_resp1_0", tc.ID)
+	assert.Equal(t, "get_weather", tc.Function.Name)
+	assert.Equal(t, "tool_calls", resp.Choices[0].FinishReason)
+}
+
+func TestParseStreamChunk_LegacyFunctionCallDelta(t *testing.T) {
+	p := New(WithAPIVersion(APIVersionLegacyFunctions))
+
+	first, err := p.ParseStreamChunk([]byte(`data: {"id":"c1","choices":[{"index":0,"delta":{"function_call":{"name":"f","arguments":""}}}]}`))
+	require.NoError(t, err)
+	require.Len(t, first.Choices[0].Delta.ToolCalls, 1)
+	assert.Equal(t, "call_c1_0", first.Choices[0].Delta.ToolCalls[0].ID)
+
+	next, err := p.ParseStreamChunk([]byte(`data: {"id":"c1","choices":[{"index":0,"delta":{"function_call":{"arguments":"{\"a\":1}"}},"finish_reason":"function_call"}]}`))
+	require.NoError(t, err)
+	require.Len(t, next.Choices[0].Delta.ToolCalls, 1)
+	assert.Empty(t, next.Choices[0].Delta.ToolCalls[0].ID)
+	assert.Equal(t, `{"a":1}`, next.Choices[0].Delta.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "tool_calls", next.Choices[0].FinishReason)
+}
+
+func TestNewFromConfig_RejectsUnknownAPIVersion(t *testing.T) {
+	_, err := NewFromConfig(provider.Config{APIKey: "k", APIVersion: "v9"})
+	require.Error(t, err)
+}
+
+func TestResolveAPIVersion_DatedVersions(t *testing.T) {
+	for version, want := range map[string]string{
+		"":                   APIVersionTools,
+		"functions":          APIVersionLegacyFunctions,
+		"2024-06-01":         APIVersionTools,
+		"2023-12-01":         APIVersionTools,
+		"2023-07-01-preview": APIVersionLegacyFunctions,
+	} {
+		got, err := ResolveAPIVersion(version)
+		require.NoError(t, err, version)
+		assert.Equal(t, want, got, version)
+	}
+
+	p, err := NewFromConfig(provider.Config{APIKey: "k", APIVersion: "2023-05-15"})
+	require.NoError(t, err)
+	assert.Equal(t, APIVersionLegacyFunctions, p.(*Provider).apiVersion)
+	assert.False(t, p.(*Provider).StreamPassthrough())
+}
@@ -33,6 +33,7 @@ type Provider struct {
 	apiKey      string
 	tokenSource provider.TokenSource
 	baseURL     string
+	apiVersion  string
 	models      []string
 	headers     map[string]string
 }
@@ -40,8 +41,9 @@ type Provider struct {
 // New creates a new OpenAI provider with the given options.
 func New(opts ...Option) *Provider {
 	p := &Provider{
-		baseURL: DefaultBaseURL,
-		headers: make(map[string]string),
+		baseURL:    DefaultBaseURL,
+		apiVersion: DefaultAPIVersion,
+		headers:    make(map[string]string),
 	}
 	for _, opt := range opts {
 		opt(p)
@@ -55,11 +57,15 @@ func NewFromConfig(cfg provider.Config) (provider.Provider, error) {
 		WithAPIKey(cfg.APIKey),
 		WithBaseURL(cfg.BaseURL),
 		WithModels(cfg.Models...),
+		WithAPIVersion(cfg.APIVersion),
 	}
 	if cfg.TokenSource != nil {
 		opts = append(opts, WithTokenSource(cfg.TokenSource))
 	}
 	p := New(opts...)
+	if _, err := ResolveAPIVersion(p.apiVersion); err != nil {
+		return nil, err
+	}
 	if err := provider.ValidateBaseURL(p.baseURL, cfg.AllowPrivateBaseURL); err != nil {
 		return nil, fmt.Errorf("invalid base_url: %w", err)
 	}
@@ -91,7 +97,13 @@ func (p *Provider) SupportsModel(model string) bool {
 
 // BuildRequest creates an HTTP request for the OpenAI API.
 func (p *Provider) BuildRequest(ctx context.Context, req *types.ChatRequest) (*http.Request, error) {
-	body, err := json.Marshal(req)
+	var body []byte
+	var err error
+	if p.apiVersion == APIVersionLegacyFunctions {
+		body, err = MarshalLegacyFunctionsRequest(req)
+	} else {
+		body, err = json.Marshal(req)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
@@ -134,10 +146,22 @@ func (p *Provider) ParseResponse(resp *http.Response) (*types.ChatResponse, erro
 	if err := json.Unmarshal(body, &chatResp); err != nil {
 		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
+	if p.apiVersion == APIVersionLegacyFunctions {
+		if err := UpgradeLegacyResponse(&chatResp, body); err != nil {
+			return nil, fmt.Errorf("upgrade legacy response: %w", err)
+		}
+	}
+	types.ApplyReasoningContent(&chatResp, body)
 
 	return &chatResp, nil
 }
 
+// StreamPassthrough reports whether stream events can be forwarded unchanged.
+// Chunks of the legacy function-calling version must be upgraded first.
+func (p *Provider) StreamPassthrough() bool {
+	return p.apiVersion != APIVersionLegacyFunctions
+}
+
 // ParseStreamChunk parses a single SSE chunk from OpenAI.
 func (p *Provider) ParseStreamChunk(data []byte) (*types.StreamChunk, error) {
 	trimmed := bytes.TrimSpace(data)
@@ -157,6 +181,12 @@ func (p *Provider) ParseStreamChunk(data []byte) (*types.StreamChunk, error) {
 	if err := json.Unmarshal(trimmed, &chunk); err != nil {
 		return nil, fmt.Errorf("unmarshal chunk: %w", err)
 	}
+	if p.apiVersion == APIVersionLegacyFunctions {
+		if err := UpgradeLegacyChunk(&chunk, trimmed); err != nil {
+			return nil, fmt.Errorf("upgrade legacy chunk: %w", err)
+		}
+	}
+	types.ApplyChunkReasoningContent(&chunk, trimmed)
 
 	return &chunk, nil
 }
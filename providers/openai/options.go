@@ -29,6 +29,21 @@ func WithBaseURL(url string) Option {
 	}
 }
 
+// WithAPIVersion selects the upstream wire format (APIVersionTools or
+// APIVersionLegacyFunctions) or a dated version resolved to one by
+// ResolveAPIVersion. Empty keeps the default.
+func WithAPIVersion(version string) Option {
+	return func(p *Provider) {
+		if version == "" {
+			return
+		}
+		if resolved, err := ResolveAPIVersion(version); err == nil {
+			version = resolved
+		}
+		p.apiVersion = version
+	}
+}
+
 // WithModels sets the supported models.
 func WithModels(models ...string) Option {
 	return func(p *Provider) {
@@ -198,10 +198,17 @@ func (p *Provider) ParseResponse(resp *http.Response) (*types.ChatResponse, erro
 	if err := json.Unmarshal(body, &chatResp); err != nil {
 		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
+	types.ApplyReasoningContent(&chatResp, body)
 
 	return &chatResp, nil
 }
 
+// StreamPassthrough reports that stream events are already in the unified
+// format and can be forwarded unchanged.
+func (p *Provider) StreamPassthrough() bool {
+	return true
+}
+
 // ParseStreamChunk parses a single SSE chunk.
 func (p *Provider) ParseStreamChunk(data []byte) (*types.StreamChunk, error) {
 	// Skip empty lines and [DONE] marker
@@ -224,6 +231,7 @@ func (p *Provider) ParseStreamChunk(data []byte) (*types.StreamChunk, error) {
 	if err := json.Unmarshal(trimmed, &chunk); err != nil {
 		return nil, fmt.Errorf("unmarshal chunk: %w", err)
 	}
+	types.ApplyChunkReasoningContent(&chunk, trimmed)
 
 	return &chunk, nil
 }
@@ -0,0 +1,32 @@
+package llmux
+
+import (
+	"github.com/blueberrycongee/llmux/internal/tokenizer"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// countReasoningTokens records the tokens of the reasoning returned with
+// resp when the provider does not break them down, as Anthropic includes
+// thinking in its output tokens. When the usage was estimated from the
+// answer alone, the reasoning tokens are added to the completion tokens.
+func countReasoningTokens(model string, resp *ChatResponse, estimated bool) {
+	if resp.Usage == nil || resp.Usage.ReasoningTokens() > 0 {
+		return
+	}
+	reasoning := 0
+	for i := range resp.Choices {
+		if r := resp.Choices[i].Reasoning; r != nil && r.Content != "" {
+			reasoning += tokenizer.CountTextTokens(model, r.Content)
+		}
+	}
+	if reasoning == 0 {
+		return
+	}
+	if estimated {
+		resp.Usage.CompletionTokens += reasoning
+		resp.Usage.TotalTokens += reasoning
+	}
+	resp.Usage.CompletionTokensDetails = &types.CompletionTokensDetails{
+		ReasoningTokens: min(reasoning, resp.Usage.CompletionTokens),
+	}
+}
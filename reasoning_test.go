@@ -0,0 +1,62 @@
+package llmux
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChatCompletion_CountsReasoningTokens(t *testing.T) {
+	var usage *Usage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			ID: "resp",
+			Choices: []Choice{{
+				Message:      ChatMessage{Role: "assistant", Content: jsonString("4")},
+				FinishReason: "stop",
+				Reasoning:    &Reasoning{Content: "The user asks for two plus two, which is four."},
+			}},
+			Usage: usage,
+		})
+	}))
+	defer server.Close()
+
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"m"}, baseURL: server.URL}, []string{"m"}),
+		withTestPricing(t, "m"),
+		WithTimeout(5*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+	req := &ChatRequest{Model: "m", Messages: []ChatMessage{{Role: "user", Content: jsonString("2+2?")}}}
+
+	// Reported output tokens already include the reasoning.
+	usage = &Usage{PromptTokens: 10, CompletionTokens: 40, TotalTokens: 50}
+	resp, err := client.ChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	reasoning := resp.Usage.ReasoningTokens()
+	if reasoning == 0 || resp.Usage.CompletionTokens != 40 {
+		t.Fatalf("usage = %+v, reasoning = %d", resp.Usage, reasoning)
+	}
+
+	// Estimated usage adds the reasoning to the answer's tokens.
+	usage = nil
+	resp, err = client.ChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.Usage.ReasoningTokens() != reasoning || resp.Usage.CompletionTokens <= reasoning {
+		t.Fatalf("estimated usage = %+v, want reasoning %d included", resp.Usage, reasoning)
+	}
+	if resp.Choices[0].Reasoning == nil {
+		t.Fatal("reasoning was dropped from the response")
+	}
+}
@@ -0,0 +1,45 @@
+package llmux
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/provider"
+)
+
+// ChatCompletionOnDeployment sends req to the deployment with the given ID,
+// bypassing routing, retries, fallback and the response cache, e.g. to
+// replay recorded traffic against one deployment. The request model is
+// replaced by the deployment's.
+func (c *Client) ChatCompletionOnDeployment(ctx context.Context, deploymentID string, req *ChatRequest) (*ChatResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request is nil")
+	}
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("messages is required")
+	}
+
+	var (
+		deployment *provider.Deployment
+		prov       provider.Provider
+	)
+	c.mu.RLock()
+	for _, deployments := range c.deployments {
+		for _, d := range deployments {
+			if d != nil && d.ID == deploymentID {
+				deployment = d
+				prov = c.providers[d.ProviderName]
+			}
+		}
+	}
+	c.mu.RUnlock()
+	if deployment == nil || prov == nil {
+		return nil, errors.NewNotFoundError("", req.Model, fmt.Sprintf("deployment %q not found", deploymentID))
+	}
+
+	pinned := *req
+	pinned.Model = deployment.ModelName
+	pinned.Stream = false
+	return c.executeOnce(ctx, prov, deployment, &pinned)
+}
@@ -0,0 +1,65 @@
+package llmux
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChatCompletionOnDeployment(t *testing.T) {
+	newServer := func(content string, calls *atomic.Int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(ChatResponse{
+				ID:      "resp",
+				Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: jsonString(content)}, FinishReason: "stop"}},
+				Usage:   &Usage{PromptTokens: 3, CompletionTokens: 1, TotalTokens: 4},
+			})
+		}))
+	}
+	var primaryCalls, secondaryCalls atomic.Int32
+	primary := newServer("primary", &primaryCalls)
+	defer primary.Close()
+	secondary := newServer("secondary", &secondaryCalls)
+	defer secondary.Close()
+
+	client, err := New(
+		WithProviderInstance("primary", &httpMockProvider{name: "primary", models: []string{"m"}, baseURL: primary.URL}, []string{"m"}),
+		WithProviderInstance("secondary", &httpMockProvider{name: "secondary", models: []string{"m"}, baseURL: secondary.URL}, []string{"m"}),
+		withTestPricing(t, "m"),
+		WithTimeout(5*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	req := &ChatRequest{Model: "other", Stream: true, Messages: []ChatMessage{{Role: "user", Content: jsonString("hi")}}}
+	for i := 0; i < 3; i++ {
+		resp, err := client.ChatCompletionOnDeployment(context.Background(), "secondary-m", req)
+		if err != nil {
+			t.Fatalf("ChatCompletionOnDeployment() error = %v", err)
+		}
+		if got := resp.Choices[0].Message.TextContent(); got != "secondary" {
+			t.Fatalf("content = %q, want secondary", got)
+		}
+		if resp.Usage.Deployment != "secondary-m" {
+			t.Fatalf("deployment = %q, want secondary-m", resp.Usage.Deployment)
+		}
+	}
+	if primaryCalls.Load() != 0 || secondaryCalls.Load() != 3 {
+		t.Fatalf("calls = primary %d, secondary %d", primaryCalls.Load(), secondaryCalls.Load())
+	}
+	if req.Model != "other" || !req.Stream {
+		t.Fatalf("request was modified: %+v", req)
+	}
+
+	if _, err := client.ChatCompletionOnDeployment(context.Background(), "missing", req); err == nil {
+		t.Fatal("expected error for unknown deployment")
+	}
+}
@@ -42,6 +42,9 @@ type statsEntry struct {
 	CurrentMinuteKey   string
 	LastRequestTime    time.Time
 	CooldownUntil      time.Time
+	CooldownTrips      int
+	HalfOpenSuccesses  int
+	ProbeStartedAt     time.Time
 }
 
 type failureBucket struct {
@@ -67,9 +70,12 @@ type BaseRouter struct {
 	rngMu       sync.Mutex
 	deployments map[string][]*ExtendedDeployment
 	stats       map[string]*statsEntry
-	config      router.Config
-	rng         *rand.Rand
-	strategy    router.Strategy
+	// quotaUsage tracks per-minute usage per deployment across all tenant
+	// scopes, since upstream RPM/TPM quotas are shared by every tenant.
+	quotaUsage map[string]*statsEntry
+	config     router.Config
+	rng        *rand.Rand
+	strategy   router.Strategy
 
 	// statsStore is an optional distributed stats store.
 	// When nil, local stats map is used (backward compatible).
@@ -83,6 +89,7 @@ func NewBaseRouter(config router.Config) *BaseRouter {
 	return &BaseRouter{
 		deployments: make(map[string][]*ExtendedDeployment),
 		stats:       make(map[string]*statsEntry),
+		quotaUsage:  make(map[string]*statsEntry),
 		config:      config,
 		// #nosec G404 -- non-cryptographic randomness for routing decisions.
 		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
@@ -164,6 +171,7 @@ func (r *BaseRouter) RemoveDeployment(deploymentID string) {
 		}
 	}
 	delete(r.stats, deploymentID)
+	delete(r.quotaUsage, deploymentID)
 }
 
 // GetDeployments returns all deployments for a model.
@@ -252,6 +260,9 @@ func (r *BaseRouter) statsEntrySnapshot(stats *statsEntry) *router.DeploymentSta
 		CurrentMinuteKey:   stats.CurrentMinuteKey,
 		LastRequestTime:    stats.LastRequestTime,
 		CooldownUntil:      stats.CooldownUntil,
+		CooldownTrips:      stats.CooldownTrips,
+		HalfOpenSuccesses:  stats.HalfOpenSuccesses,
+		ProbeStartedAt:     stats.ProbeStartedAt,
 	}
 }
 
@@ -316,7 +327,7 @@ func (r *BaseRouter) IsCircuitOpen(deployment *provider.Deployment) bool {
 }
 
 // SetCooldown updates the cooldown expiration time for a deployment.
-// A zero time clears any active cooldown.
+// A zero time clears any active cooldown and fully reopens the deployment.
 func (r *BaseRouter) SetCooldown(deploymentID string, until time.Time) error {
 	if r.statsStore != nil {
 		ctx := context.Background()
@@ -334,6 +345,11 @@ func (r *BaseRouter) SetCooldown(deploymentID string, until time.Time) error {
 	stats := r.getOrCreateStats(deploymentID)
 	before := stats.CooldownUntil
 	stats.CooldownUntil = until
+	if until.IsZero() {
+		stats.CooldownTrips = 0
+		stats.HalfOpenSuccesses = 0
+		stats.ProbeStartedAt = time.Time{}
+	}
 	r.recordCooldownMetric(r.findDeploymentByIDLocked(deploymentID), before, until)
 	return nil
 }
@@ -356,6 +372,9 @@ func (r *BaseRouter) ReportRequestStart(ctx context.Context, deployment *provide
 
 	stats := r.getOrCreateStats(r.localStatsKey(ctx, deployment.ID))
 	stats.ActiveRequests++
+	if now := time.Now(); stats.halfOpen(now) {
+		stats.ProbeStartedAt = now
+	}
 }
 
 // ReportRequestEnd decrements the active request count.
@@ -386,6 +405,8 @@ func (r *BaseRouter) ReportSuccess(ctx context.Context, deployment *provider.Dep
 	if r.statsStore != nil {
 		// Fail-safe: ignore errors
 		_ = r.statsStore.RecordSuccess(ctx, deployment.ID, metrics)
+		r.recordResponseMetrics(deployment, metrics)
+		r.publishStoreStatsMetrics(ctx, deployment)
 		return
 	}
 
@@ -399,6 +420,7 @@ func (r *BaseRouter) ReportSuccess(ctx context.Context, deployment *provider.Dep
 	now := time.Now()
 	stats.LastRequestTime = now
 	r.recordWindowSuccess(stats, now)
+	r.recordProbeSuccessLocked(stats, now)
 
 	latencyMs := float64(metrics.Latency.Milliseconds())
 	r.appendToHistory(&stats.LatencyHistory, latencyMs, stats.MaxLatencyListSize)
@@ -438,6 +460,16 @@ func (r *BaseRouter) ReportSuccess(ctx context.Context, deployment *provider.Dep
 	stats.EWMASuccessRate = alpha*1.0 + (1.0-alpha)*stats.EWMASuccessRate
 
 	r.updateUsageStats(stats, metrics.TotalTokens)
+	if statsKey := r.localStatsKey(ctx, deployment.ID); statsKey != deployment.ID {
+		quota, ok := r.quotaUsage[deployment.ID]
+		if !ok {
+			quota = &statsEntry{}
+			r.quotaUsage[deployment.ID] = quota
+		}
+		r.updateUsageStats(quota, metrics.TotalTokens)
+	}
+	r.recordResponseMetrics(deployment, metrics)
+	r.publishLocalStatsMetrics(deployment, stats, now)
 }
 
 // ReportFailure records a failed request and triggers cooldown if needed.
@@ -445,6 +477,10 @@ func (r *BaseRouter) ReportSuccess(ctx context.Context, deployment *provider.Dep
 //   - Immediate cooldown on 429 (Rate Limit) if ImmediateCooldownOn429 is true
 //   - Immediate cooldown on non-retryable errors (401, 404)
 //   - Failure rate based cooldown when rate exceeds FailureThresholdPercent
+//   - Immediate cooldown when a half-open probe fails
+//
+// Each consecutive cooldown without recovery doubles the cooldown period up to
+// CooldownMaxPeriod.
 func (r *BaseRouter) ReportFailure(ctx context.Context, deployment *provider.Deployment, err error) {
 	// Distributed mode: delegate to StatsStore
 	if r.statsStore != nil {
@@ -463,6 +499,7 @@ func (r *BaseRouter) ReportFailure(ctx context.Context, deployment *provider.Dep
 		}
 		afterCooldown, _ := r.statsStore.GetCooldownUntil(ctx, deployment.ID)
 		r.recordCooldownMetric(deployment, beforeCooldown, afterCooldown)
+		r.publishStoreStatsMetrics(ctx, deployment)
 		return
 	}
 
@@ -476,6 +513,8 @@ func (r *BaseRouter) ReportFailure(ctx context.Context, deployment *provider.Dep
 	stats.TotalRequests++
 	stats.FailureCount++
 	now := time.Now()
+	defer r.publishLocalStatsMetrics(deployment, stats, now)
+	halfOpen := stats.halfOpen(now)
 	stats.LastRequestTime = now
 	r.recordWindowFailure(stats, now)
 
@@ -484,14 +523,14 @@ func (r *BaseRouter) ReportFailure(ctx context.Context, deployment *provider.Dep
 	if isLLMErr {
 		// Immediate cooldown: 429 Rate Limit
 		if r.config.ImmediateCooldownOn429 && llmErr.StatusCode == 429 && !isSingleDeployment {
-			stats.CooldownUntil = time.Now().Add(r.config.CooldownPeriod)
+			r.tripCooldownLocked(stats, now)
 			r.recordCooldownMetric(deployment, beforeCooldown, stats.CooldownUntil)
 			return
 		}
 
 		// Immediate cooldown: Non-retryable errors (401, 404, 408)
 		if llmErr.StatusCode == 401 || llmErr.StatusCode == 404 || llmErr.StatusCode == 408 {
-			stats.CooldownUntil = time.Now().Add(r.config.CooldownPeriod)
+			r.tripCooldownLocked(stats, now)
 			r.recordCooldownMetric(deployment, beforeCooldown, stats.CooldownUntil)
 			return
 		}
@@ -503,8 +542,8 @@ func (r *BaseRouter) ReportFailure(ctx context.Context, deployment *provider.Dep
 	}
 
 	// Failure rate based cooldown
-	if r.shouldCooldownByFailureRate(stats, now, isSingleDeployment) {
-		stats.CooldownUntil = time.Now().Add(r.config.CooldownPeriod)
+	if halfOpen || r.shouldCooldownByFailureRate(stats, now, isSingleDeployment) {
+		r.tripCooldownLocked(stats, now)
 		r.recordCooldownMetric(deployment, beforeCooldown, stats.CooldownUntil)
 	}
 
@@ -543,8 +582,7 @@ func (r *BaseRouter) getHealthyDeployments(deployments []*ExtendedDeployment, st
 	now := time.Now()
 	healthy := make([]*ExtendedDeployment, 0, len(deployments))
 	for _, d := range deployments {
-		stats := statsByID[d.ID]
-		if stats == nil || now.After(stats.CooldownUntil) {
+		if r.isAvailable(statsByID[d.ID], now) {
 			healthy = append(healthy, d)
 		}
 	}
@@ -612,18 +650,24 @@ func (r *BaseRouter) filterByDefaultProvider(deployments []*ExtendedDeployment)
 func (r *BaseRouter) filterByTPMRPM(deployments []*ExtendedDeployment, statsByID map[string]*router.DeploymentStats, inputTokens int) []*ExtendedDeployment {
 	filtered := make([]*ExtendedDeployment, 0, len(deployments))
 
+	currentMinute := minuteKey(time.Now())
 	for _, d := range deployments {
-		stats := statsByID[d.ID]
-		if stats == nil {
+		if d.Config.TPMLimit <= 0 && d.Config.RPMLimit <= 0 {
 			filtered = append(filtered, d)
 			continue
 		}
 
-		if d.Config.TPMLimit > 0 && stats.CurrentMinuteTPM+int64(inputTokens) > d.Config.TPMLimit {
+		tpm, rpm, ok := r.quotaUsageFor(d.ID, statsByID, currentMinute)
+		if !ok {
+			filtered = append(filtered, d)
 			continue
 		}
 
-		if d.Config.RPMLimit > 0 && stats.CurrentMinuteRPM+1 > d.Config.RPMLimit {
+		if d.Config.TPMLimit > 0 && tpm+int64(inputTokens) > d.Config.TPMLimit {
+			continue
+		}
+
+		if d.Config.RPMLimit > 0 && rpm+1 > d.Config.RPMLimit {
 			continue
 		}
 
@@ -633,6 +677,33 @@ func (r *BaseRouter) filterByTPMRPM(deployments []*ExtendedDeployment, statsByID
 	return filtered
 }
 
+// quotaUsageFor returns the current-minute TPM/RPM for a deployment. In local
+// mode with tenant-scoped stats, usage is aggregated across tenants; usage from
+// a previous minute bucket counts as zero.
+func (r *BaseRouter) quotaUsageFor(deploymentID string, statsByID map[string]*router.DeploymentStats, currentMinute string) (tpm, rpm int64, ok bool) {
+	if r.statsStore == nil {
+		r.mu.RLock()
+		quota := r.quotaUsage[deploymentID]
+		unscoped := r.stats[deploymentID]
+		if quota != nil && quota.CurrentMinuteKey == currentMinute {
+			tpm, rpm, ok = quota.CurrentMinuteTPM, quota.CurrentMinuteRPM, true
+		}
+		if unscoped != nil && unscoped.CurrentMinuteKey == currentMinute {
+			tpm += unscoped.CurrentMinuteTPM
+			rpm += unscoped.CurrentMinuteRPM
+			ok = true
+		}
+		r.mu.RUnlock()
+		return tpm, rpm, ok
+	}
+
+	stats := statsByID[deploymentID]
+	if stats == nil {
+		return 0, 0, false
+	}
+	return stats.CurrentMinuteTPM, stats.CurrentMinuteRPM, true
+}
+
 func (r *BaseRouter) localStatsKey(ctx context.Context, deploymentID string) string {
 	scope := router.TenantScopeFromContext(ctx)
 	if scope == "" {
@@ -743,11 +814,12 @@ func (r *BaseRouter) findDeploymentByIDLocked(deploymentID string) *provider.Dep
 }
 
 func (r *BaseRouter) recordCooldownMetric(deployment *provider.Deployment, before, after time.Time) {
-	if deployment == nil || after.IsZero() {
+	if deployment == nil {
 		return
 	}
 	now := time.Now()
-	if now.After(after) {
+	deploymentMetrics.UpdateRouterCooldown(deployment.ID, deployment.ModelName, deployment.ProviderName, now.Before(after))
+	if after.IsZero() || now.After(after) {
 		return
 	}
 	if !before.IsZero() && now.Before(before) {
@@ -762,6 +834,51 @@ func (r *BaseRouter) recordCooldownMetric(deployment *provider.Deployment, befor
 	)
 }
 
+// recordResponseMetrics observes the latency and TTFT reported for a
+// successful request.
+func (r *BaseRouter) recordResponseMetrics(deployment *provider.Deployment, m *router.ResponseMetrics) {
+	if deployment == nil || m == nil {
+		return
+	}
+	deploymentMetrics.RecordRouterResponse(
+		deployment.ID, deployment.ModelName, deployment.ProviderName,
+		m.Latency, m.TimeToFirstToken,
+	)
+}
+
+// publishLocalStatsMetrics exports the local stats of a deployment.
+// MUST be called with r.mu locked.
+func (r *BaseRouter) publishLocalStatsMetrics(deployment *provider.Deployment, stats *statsEntry, now time.Time) {
+	if deployment == nil || stats == nil {
+		return
+	}
+	deploymentMetrics.UpdateRouterDeploymentStats(
+		deployment.ID, deployment.ModelName, deployment.ProviderName,
+		stats.EWMALatencyMs, stats.EWMAAvgTTFTMs, now.Before(stats.CooldownUntil),
+	)
+	successes, failures := r.windowTotals(stats, now)
+	deploymentMetrics.UpdateRouterFailureRate(
+		deployment.ID, deployment.ModelName, deployment.ProviderName,
+		successes, failures,
+	)
+}
+
+// publishStoreStatsMetrics exports the shared stats of a deployment in
+// distributed mode. The failure window lives in the store and is not exported.
+func (r *BaseRouter) publishStoreStatsMetrics(ctx context.Context, deployment *provider.Deployment) {
+	if deployment == nil {
+		return
+	}
+	stats, err := r.statsStore.GetStats(ctx, deployment.ID)
+	if err != nil || stats == nil {
+		return
+	}
+	deploymentMetrics.UpdateRouterDeploymentStats(
+		deployment.ID, deployment.ModelName, deployment.ProviderName,
+		stats.EWMALatencyMs, stats.EWMAAvgTTFTMs, time.Now().Before(stats.CooldownUntil),
+	)
+}
+
 func (r *BaseRouter) appendToHistory(history *[]float64, value float64, maxSize int) {
 	if maxSize <= 0 {
 		maxSize = 10
@@ -818,6 +935,38 @@ func hasMatchingTag(deploymentTags, requestTags []string) bool {
 	return false
 }
 
+// traceCandidates records the deployments serving the model when the pick is traced.
+func traceCandidates(reqCtx *router.RequestContext, deployments []*ExtendedDeployment) {
+	if reqCtx == nil || reqCtx.Trace == nil {
+		return
+	}
+	reqCtx.Trace.SetCandidates(deploymentIDs(deployments))
+}
+
+// traceFilter records a filter step when the pick is traced and returns after.
+func traceFilter(reqCtx *router.RequestContext, name string, before, after []*ExtendedDeployment) []*ExtendedDeployment {
+	if reqCtx != nil && reqCtx.Trace != nil {
+		reqCtx.Trace.AddFilter(name, deploymentIDs(before), deploymentIDs(after))
+	}
+	return after
+}
+
+// traceScore records a strategy score when the pick is traced.
+func traceScore(reqCtx *router.RequestContext, kind string, d *ExtendedDeployment, score float64) {
+	if reqCtx == nil || reqCtx.Trace == nil {
+		return
+	}
+	reqCtx.Trace.SetScore(kind, d.ID, score)
+}
+
+func deploymentIDs(deployments []*ExtendedDeployment) []string {
+	ids := make([]string, 0, len(deployments))
+	for _, d := range deployments {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
+
 // Pick implements basic random selection (used as fallback).
 func (r *BaseRouter) Pick(ctx context.Context, model string) (*provider.Deployment, error) {
 	return r.PickWithContext(ctx, &router.RequestContext{Model: model})
@@ -826,22 +975,23 @@ func (r *BaseRouter) Pick(ctx context.Context, model string) (*provider.Deployme
 // PickWithContext implements basic random selection with context.
 func (r *BaseRouter) PickWithContext(ctx context.Context, reqCtx *router.RequestContext) (*provider.Deployment, error) {
 	deployments := r.snapshotDeployments(reqCtx.Model)
+	traceCandidates(reqCtx, deployments)
 	if len(deployments) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 	statsByID := r.statsSnapshot(ctx, deployments)
-	healthy := r.getHealthyDeployments(deployments, statsByID)
+	healthy := traceFilter(reqCtx, router.FilterHealth, deployments, r.getHealthyDeployments(deployments, statsByID))
 	if len(healthy) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 
 	if r.config.EnableTagFiltering && len(reqCtx.Tags) > 0 {
-		healthy = r.filterByTags(healthy, reqCtx.Tags)
+		healthy = traceFilter(reqCtx, router.FilterTags, healthy, r.filterByTags(healthy, reqCtx.Tags))
 		if len(healthy) == 0 {
 			return nil, ErrNoDeploymentsWithTag
 		}
 	}
 
-	healthy = r.filterByDefaultProvider(healthy)
+	healthy = traceFilter(reqCtx, router.FilterDefaultProvider, healthy, r.filterByDefaultProvider(healthy))
 	return healthy[r.randIntn(len(healthy))].Deployment, nil
 }
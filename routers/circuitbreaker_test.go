@@ -192,3 +192,106 @@ func TestBaseRouter_CooldownRecovery(t *testing.T) {
 	// Should have recovered
 	assert.False(t, r.IsCircuitOpen(deployment), "Circuit should be closed after cooldown expires")
 }
+
+func TestBaseRouter_CooldownBackoff(t *testing.T) {
+	// Each failed half-open probe doubles the cooldown up to CooldownMaxPeriod
+	config := router.DefaultConfig()
+	config.CooldownPeriod = 20 * time.Millisecond
+	config.CooldownMaxPeriod = 50 * time.Millisecond
+	r := routers.NewBaseRouter(config)
+
+	deployment := &provider.Deployment{ID: "test-deployment", ModelName: "gpt-4"}
+	secondary := &provider.Deployment{ID: "test-deployment-2", ModelName: "gpt-4"}
+	r.AddDeployment(deployment)
+	r.AddDeployment(secondary)
+
+	err := llmerrors.NewAuthenticationError("openai", "gpt-4", "invalid key")
+	expected := []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond}
+	for i, want := range expected {
+		before := time.Now()
+		r.ReportFailure(context.Background(), deployment, err)
+
+		stats := r.GetStats(deployment.ID)
+		require.NotNil(t, stats)
+		assert.Equal(t, i+1, stats.CooldownTrips)
+		assert.WithinDuration(t, before.Add(want), stats.CooldownUntil, 10*time.Millisecond)
+
+		// Failures during an active cooldown do not extend it
+		r.ReportFailure(context.Background(), deployment, err)
+		assert.Equal(t, i+1, r.GetStats(deployment.ID).CooldownTrips)
+
+		time.Sleep(want + 5*time.Millisecond)
+	}
+}
+
+func TestBaseRouter_HalfOpenSingleProbe(t *testing.T) {
+	// After cooldown only one probe is routed at a time, and the deployment is
+	// fully reopened after HalfOpenSuccessThreshold consecutive successes
+	config := router.DefaultConfig()
+	config.CooldownPeriod = 10 * time.Millisecond
+	config.HalfOpenSuccessThreshold = 2
+	r := routers.NewBaseRouter(config)
+
+	deployment := &provider.Deployment{ID: "test-deployment", ModelName: "gpt-4"}
+	secondary := &provider.Deployment{ID: "test-deployment-2", ModelName: "gpt-4"}
+	r.AddDeployment(deployment)
+	r.AddDeployment(secondary)
+	ctx := context.Background()
+
+	r.ReportFailure(ctx, deployment, llmerrors.NewAuthenticationError("openai", "gpt-4", "invalid key"))
+	time.Sleep(15 * time.Millisecond)
+	require.False(t, r.IsCircuitOpen(deployment))
+
+	for probe := 1; probe <= 2; probe++ {
+		r.ReportRequestStart(ctx, deployment)
+		for i := 0; i < 20; i++ {
+			picked, err := r.Pick(ctx, "gpt-4")
+			require.NoError(t, err)
+			require.Equal(t, secondary.ID, picked.ID, "probe in flight should block deployment")
+		}
+		r.ReportSuccess(ctx, deployment, &router.ResponseMetrics{Latency: 10 * time.Millisecond})
+		r.ReportRequestEnd(ctx, deployment)
+		if probe == 1 {
+			stats := r.GetStats(deployment.ID)
+			assert.Equal(t, 1, stats.HalfOpenSuccesses)
+			assert.Equal(t, 1, stats.CooldownTrips, "one success should not reopen the deployment")
+		}
+	}
+
+	stats := r.GetStats(deployment.ID)
+	assert.Zero(t, stats.CooldownTrips, "deployment should be fully reopened")
+
+	// Once reopened, starting a request no longer claims a probe
+	r.ReportRequestStart(ctx, deployment)
+	seen := false
+	for i := 0; i < 50 && !seen; i++ {
+		picked, err := r.Pick(ctx, "gpt-4")
+		require.NoError(t, err)
+		seen = picked.ID == deployment.ID
+	}
+	assert.True(t, seen)
+}
+
+func TestBaseRouter_HalfOpenProbeFailure(t *testing.T) {
+	// A failed probe trips the circuit immediately with a longer cooldown
+	config := router.DefaultConfig()
+	config.CooldownPeriod = 10 * time.Millisecond
+	config.CooldownMaxPeriod = time.Second
+	config.FailureThresholdPercent = 1.0
+	r := routers.NewBaseRouter(config)
+
+	deployment := &provider.Deployment{ID: "test-deployment", ModelName: "gpt-4"}
+	secondary := &provider.Deployment{ID: "test-deployment-2", ModelName: "gpt-4"}
+	r.AddDeployment(deployment)
+	r.AddDeployment(secondary)
+	ctx := context.Background()
+
+	r.ReportFailure(ctx, deployment, llmerrors.NewAuthenticationError("openai", "gpt-4", "invalid key"))
+	time.Sleep(15 * time.Millisecond)
+
+	r.ReportRequestStart(ctx, deployment)
+	r.ReportFailure(ctx, deployment, llmerrors.NewInternalError("openai", "gpt-4", "boom"))
+
+	assert.True(t, r.IsCircuitOpen(deployment), "failed probe should reopen the circuit")
+	assert.Equal(t, 2, r.GetStats(deployment.ID).CooldownTrips)
+}
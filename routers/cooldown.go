@@ -0,0 +1,88 @@
+package routers
+
+import (
+	"time"
+
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+const defaultHalfOpenSuccessThreshold = 3
+
+// cooldownBackoff returns the cooldown for the given consecutive trip count:
+// base doubled per trip after the first, capped at maxPeriod. A maxPeriod at or
+// below base keeps the cooldown fixed.
+func cooldownBackoff(base, maxPeriod time.Duration, trips int) time.Duration {
+	if base <= 0 || trips <= 1 || maxPeriod <= base {
+		return base
+	}
+	d := base
+	for i := 1; i < trips; i++ {
+		d *= 2
+		if d >= maxPeriod {
+			return maxPeriod
+		}
+	}
+	return d
+}
+
+func (r *BaseRouter) halfOpenSuccessThreshold() int {
+	if r.config.HalfOpenSuccessThreshold > 0 {
+		return r.config.HalfOpenSuccessThreshold
+	}
+	return defaultHalfOpenSuccessThreshold
+}
+
+// halfOpenProbeTimeout bounds how long an unreported probe blocks the next one.
+func (r *BaseRouter) halfOpenProbeTimeout() time.Duration {
+	if r.config.CooldownPeriod > 0 {
+		return r.config.CooldownPeriod
+	}
+	return router.DefaultConfig().CooldownPeriod
+}
+
+// tripCooldownLocked opens the circuit for the next backoff step. Failures
+// reported while a cooldown is already active do not extend it.
+// MUST be called with r.mu locked.
+func (r *BaseRouter) tripCooldownLocked(stats *statsEntry, now time.Time) {
+	if r.config.CooldownPeriod <= 0 || now.Before(stats.CooldownUntil) {
+		return
+	}
+	stats.CooldownTrips++
+	stats.HalfOpenSuccesses = 0
+	stats.ProbeStartedAt = time.Time{}
+	stats.CooldownUntil = now.Add(cooldownBackoff(r.config.CooldownPeriod, r.config.CooldownMaxPeriod, stats.CooldownTrips))
+}
+
+// recordProbeSuccessLocked counts a successful half-open probe and fully
+// reopens the deployment once HalfOpenSuccessThreshold is reached.
+// MUST be called with r.mu locked.
+func (r *BaseRouter) recordProbeSuccessLocked(stats *statsEntry, now time.Time) {
+	if !stats.halfOpen(now) {
+		return
+	}
+	stats.ProbeStartedAt = time.Time{}
+	stats.HalfOpenSuccesses++
+	if stats.HalfOpenSuccesses >= r.halfOpenSuccessThreshold() {
+		stats.CooldownTrips = 0
+		stats.HalfOpenSuccesses = 0
+	}
+}
+
+func (s *statsEntry) halfOpen(now time.Time) bool {
+	return s.CooldownTrips > 0 && !now.Before(s.CooldownUntil)
+}
+
+// isAvailable reports whether a deployment may be routed to: it is not cooling
+// down and, when half-open, has no probe in flight.
+func (r *BaseRouter) isAvailable(stats *router.DeploymentStats, now time.Time) bool {
+	if stats == nil {
+		return true
+	}
+	if now.Before(stats.CooldownUntil) {
+		return false
+	}
+	if stats.CooldownTrips == 0 || stats.ProbeStartedAt.IsZero() || stats.ProbeStartedAt.Before(stats.CooldownUntil) {
+		return true
+	}
+	return now.Sub(stats.ProbeStartedAt) >= r.halfOpenProbeTimeout()
+}
@@ -38,16 +38,10 @@ func NewCostRouter(cooldownPeriod ...interface{}) *CostRouter {
 // NewCostRouterWithConfig creates a new cost router with custom config.
 func NewCostRouterWithConfig(config router.Config) *CostRouter {
 	config.Strategy = router.StrategyLowestCost
-	r := &CostRouter{
+	return &CostRouter{
 		BaseRouter: NewBaseRouter(config),
-		registry:   pricing.NewRegistry(),
+		registry:   costRegistry(config),
 	}
-	if config.PricingFile != "" {
-		// Do not panic on configuration errors; the caller should handle this.
-		// If the file cannot be loaded, we keep defaults and rely on UnknownModelCost fallback.
-		_ = r.registry.Load(config.PricingFile)
-	}
-	return r
 }
 
 // newCostRouterWithStore creates a new cost router with optional distributed StatsStore.
@@ -59,14 +53,25 @@ func newCostRouterWithStore(config router.Config, store router.StatsStore) *Cost
 	} else {
 		base = NewBaseRouter(config)
 	}
-	r := &CostRouter{
+	return &CostRouter{
 		BaseRouter: base,
-		registry:   pricing.NewRegistry(),
+		registry:   costRegistry(config),
+	}
+}
+
+// costRegistry returns the shared registry of config, or a new one with
+// PricingFile loaded.
+func costRegistry(config router.Config) *pricing.Registry {
+	if config.Pricing != nil {
+		return config.Pricing
 	}
+	reg := pricing.NewRegistry()
 	if config.PricingFile != "" {
-		_ = r.registry.Load(config.PricingFile)
+		// Do not panic on configuration errors; the caller should handle this.
+		// If the file cannot be loaded, we keep defaults and rely on UnknownModelCost fallback.
+		_ = reg.Load(config.PricingFile)
 	}
-	return r
+	return reg
 }
 
 // Pick selects the deployment with lowest cost.
@@ -77,30 +82,31 @@ func (r *CostRouter) Pick(ctx context.Context, model string) (*provider.Deployme
 // PickWithContext selects the deployment with lowest cost per token.
 func (r *CostRouter) PickWithContext(ctx context.Context, reqCtx *router.RequestContext) (*provider.Deployment, error) {
 	deployments := r.snapshotDeployments(reqCtx.Model)
+	traceCandidates(reqCtx, deployments)
 	if len(deployments) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 	statsByID := r.statsSnapshot(ctx, deployments)
-	healthy := r.getHealthyDeployments(deployments, statsByID)
+	healthy := traceFilter(reqCtx, router.FilterHealth, deployments, r.getHealthyDeployments(deployments, statsByID))
 	if len(healthy) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 
 	if r.config.EnableTagFiltering && len(reqCtx.Tags) > 0 {
-		healthy = r.filterByTags(healthy, reqCtx.Tags)
+		healthy = traceFilter(reqCtx, router.FilterTags, healthy, r.filterByTags(healthy, reqCtx.Tags))
 		if len(healthy) == 0 {
 			return nil, ErrNoDeploymentsWithTag
 		}
 	}
 
 	if reqCtx.EstimatedInputTokens > 0 {
-		healthy = r.filterByTPMRPM(healthy, statsByID, reqCtx.EstimatedInputTokens)
+		healthy = traceFilter(reqCtx, router.FilterTPMRPM, healthy, r.filterByTPMRPM(healthy, statsByID, reqCtx.EstimatedInputTokens))
 		if len(healthy) == 0 {
 			return nil, ErrNoAvailableDeployment
 		}
 	}
 
-	healthy = r.filterByDefaultProvider(healthy)
+	healthy = traceFilter(reqCtx, router.FilterDefaultProvider, healthy, r.filterByDefaultProvider(healthy))
 	type deploymentCost struct {
 		deployment *ExtendedDeployment
 		cost       float64
@@ -128,6 +134,7 @@ func (r *CostRouter) PickWithContext(ctx context.Context, reqCtx *router.Request
 		}
 
 		totalCost := inputCost + outputCost
+		traceScore(reqCtx, "cost_per_token", d, totalCost)
 
 		candidates = append(candidates, deploymentCost{
 			deployment: d,
@@ -0,0 +1,78 @@
+package routers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/provider"
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+func TestLeastBusyRouter_DecisionTrace(t *testing.T) {
+	config := router.DefaultConfig()
+	config.EnableTagFiltering = true
+	r := NewLeastBusyRouterWithConfig(config)
+
+	eu1 := &provider.Deployment{ID: "eu-1", ModelName: "gpt-4", ProviderName: "eu"}
+	eu2 := &provider.Deployment{ID: "eu-2", ModelName: "gpt-4", ProviderName: "eu"}
+	us := &provider.Deployment{ID: "us-1", ModelName: "gpt-4", ProviderName: "us"}
+	cooling := &provider.Deployment{ID: "eu-3", ModelName: "gpt-4", ProviderName: "eu"}
+	r.AddDeploymentWithConfig(eu1, router.DeploymentConfig{Tags: []string{"eu"}})
+	r.AddDeploymentWithConfig(eu2, router.DeploymentConfig{Tags: []string{"eu"}})
+	r.AddDeploymentWithConfig(us, router.DeploymentConfig{Tags: []string{"us"}})
+	r.AddDeploymentWithConfig(cooling, router.DeploymentConfig{Tags: []string{"eu"}})
+	require.NoError(t, r.SetCooldown(cooling.ID, time.Now().Add(time.Minute)))
+	r.ReportRequestStart(context.Background(), eu1)
+
+	trace := &router.DecisionTrace{}
+	picked, err := r.PickWithContext(context.Background(), &router.RequestContext{
+		Model: "gpt-4",
+		Tags:  []string{"eu"},
+		Trace: trace,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, eu2.ID, picked.ID)
+
+	assert.ElementsMatch(t, []string{"eu-1", "eu-2", "us-1", "eu-3"}, trace.Candidates)
+	require.Len(t, trace.Filters, 3)
+	assert.Equal(t, router.FilterHealth, trace.Filters[0].Name)
+	assert.Equal(t, []string{"eu-3"}, trace.Filters[0].Removed)
+	assert.Equal(t, router.FilterTags, trace.Filters[1].Name)
+	assert.Equal(t, []string{"us-1"}, trace.Filters[1].Removed)
+	assert.ElementsMatch(t, []string{"eu-1", "eu-2"}, trace.Filters[1].Remaining)
+	assert.Equal(t, router.FilterDefaultProvider, trace.Filters[2].Name)
+	assert.Empty(t, trace.Filters[2].Removed)
+
+	assert.Equal(t, "active_requests", trace.ScoreKind)
+	assert.Equal(t, map[string]float64{"eu-1": 1, "eu-2": 0}, trace.Scores)
+}
+
+func TestShuffleRouter_DecisionTraceWeights(t *testing.T) {
+	r := NewShuffleRouter()
+	r.AddDeploymentWithConfig(&provider.Deployment{ID: "a", ModelName: "gpt-4", ProviderName: "p"}, router.DeploymentConfig{Weight: 3})
+	r.AddDeploymentWithConfig(&provider.Deployment{ID: "b", ModelName: "gpt-4", ProviderName: "p"}, router.DeploymentConfig{Weight: 1})
+
+	trace := &router.DecisionTrace{}
+	_, err := r.PickWithContext(context.Background(), &router.RequestContext{Model: "gpt-4", Trace: trace})
+	require.NoError(t, err)
+
+	assert.Equal(t, "weight", trace.ScoreKind)
+	assert.Equal(t, 3.0, trace.Scores["a"])
+	assert.Equal(t, 1.0, trace.Scores["b"])
+}
+
+func TestDecisionTrace_NilSafe(t *testing.T) {
+	r := NewShuffleRouter()
+	r.AddDeployment(&provider.Deployment{ID: "a", ModelName: "gpt-4", ProviderName: "p"})
+
+	_, err := r.PickWithContext(context.Background(), &router.RequestContext{Model: "gpt-4"})
+	require.NoError(t, err)
+
+	var trace *router.DecisionTrace
+	trace.AddFilter(router.FilterHealth, []string{"a"}, nil)
+	trace.SetScore("weight", "a", 1)
+}
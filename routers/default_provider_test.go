@@ -72,3 +72,51 @@ func TestShuffleRouter_DefaultProviderFallsBackWhenThrottled(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, secondary.ID, picked.ID)
 }
+
+func TestShuffleRouter_RPMLimitSharedAcrossTenants(t *testing.T) {
+	r := NewShuffleRouterWithConfig(router.DefaultConfig())
+
+	limited := &provider.Deployment{ID: "limited-gpt-4", ModelName: "gpt-4", ProviderName: "limited"}
+	spare := &provider.Deployment{ID: "spare-gpt-4", ModelName: "gpt-4", ProviderName: "spare"}
+	r.AddDeploymentWithConfig(limited, router.DeploymentConfig{RPMLimit: 2})
+	r.AddDeployment(spare)
+
+	// Two tenants each use one request of the shared upstream quota.
+	for _, tenant := range []string{"team-a", "team-b"} {
+		r.ReportSuccess(router.WithTenantScope(context.Background(), tenant), limited, &router.ResponseMetrics{
+			Latency:     time.Millisecond,
+			TotalTokens: 10,
+		})
+	}
+
+	for i := 0; i < 10; i++ {
+		picked, err := r.PickWithContext(router.WithTenantScope(context.Background(), "team-c"), &router.RequestContext{
+			Model:                "gpt-4",
+			EstimatedInputTokens: 1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, spare.ID, picked.ID)
+	}
+}
+
+func TestShuffleRouter_RemoveDeploymentResetsSharedQuota(t *testing.T) {
+	r := NewShuffleRouterWithConfig(router.DefaultConfig())
+
+	limited := &provider.Deployment{ID: "limited-gpt-4", ModelName: "gpt-4", ProviderName: "limited"}
+	r.AddDeploymentWithConfig(limited, router.DeploymentConfig{RPMLimit: 1})
+	r.ReportSuccess(router.WithTenantScope(context.Background(), "team-a"), limited, &router.ResponseMetrics{
+		Latency:     time.Millisecond,
+		TotalTokens: 10,
+	})
+
+	// A re-added deployment starts without the quota usage of the removed one.
+	r.RemoveDeployment(limited.ID)
+	r.AddDeploymentWithConfig(limited, router.DeploymentConfig{RPMLimit: 1})
+
+	picked, err := r.PickWithContext(router.WithTenantScope(context.Background(), "team-b"), &router.RequestContext{
+		Model:                "gpt-4",
+		EstimatedInputTokens: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, limited.ID, picked.ID)
+}
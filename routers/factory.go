@@ -36,7 +36,7 @@ func NewWithStores(config router.Config, statsStore router.StatsStore, rrStore r
 	case router.StrategyLowestTPMRPM:
 		return newTPMRPMRouterWithStore(config, statsStore), nil
 	case router.StrategyLowestCost:
-		if config.PricingFile != "" {
+		if config.Pricing == nil && config.PricingFile != "" {
 			reg := pricing.NewRegistry()
 			if err := reg.Load(config.PricingFile); err != nil {
 				return nil, fmt.Errorf("load pricing file %s: %w", config.PricingFile, err)
@@ -46,6 +46,13 @@ func NewWithStores(config router.Config, statsStore router.StatsStore, rrStore r
 	case router.StrategyTagBased:
 		return newTagBasedRouterWithStore(config, statsStore), nil
 	default:
+		if factory, ok := lookupFactory(config.Strategy); ok {
+			r, err := factory(config, statsStore, rrStore)
+			if err != nil {
+				return nil, fmt.Errorf("create %s router: %w", config.Strategy, err)
+			}
+			return r, nil
+		}
 		return nil, fmt.Errorf("unknown routing strategy: %s", config.Strategy)
 	}
 }
@@ -61,8 +68,13 @@ func MustNew(config router.Config) router.Router {
 	return r
 }
 
-// AvailableStrategies returns a list of all available routing strategies.
+// AvailableStrategies returns a list of all available routing strategies,
+// built-in strategies first followed by registered custom strategies.
 func AvailableStrategies() []router.Strategy {
+	return append(builtinStrategies(), registeredStrategies()...)
+}
+
+func builtinStrategies() []router.Strategy {
 	return []router.Strategy{
 		router.StrategyRoundRobin,
 		router.StrategySimpleShuffle,
@@ -74,6 +86,15 @@ func AvailableStrategies() []router.Strategy {
 	}
 }
 
+func isBuiltinStrategy(strategy router.Strategy) bool {
+	for _, builtin := range builtinStrategies() {
+		if strategy == builtin {
+			return true
+		}
+	}
+	return false
+}
+
 // IsValidStrategy checks if a strategy string is valid.
 func IsValidStrategy(s string) bool {
 	strategy := router.Strategy(s)
@@ -52,30 +52,31 @@ func (r *LatencyRouter) Pick(ctx context.Context, model string) (*provider.Deplo
 // PickWithContext selects the deployment with lowest latency, considering streaming mode.
 func (r *LatencyRouter) PickWithContext(ctx context.Context, reqCtx *router.RequestContext) (*provider.Deployment, error) {
 	deployments := r.snapshotDeployments(reqCtx.Model)
+	traceCandidates(reqCtx, deployments)
 	if len(deployments) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 	statsByID := r.statsSnapshot(ctx, deployments)
-	healthy := r.getHealthyDeployments(deployments, statsByID)
+	healthy := traceFilter(reqCtx, router.FilterHealth, deployments, r.getHealthyDeployments(deployments, statsByID))
 	if len(healthy) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 
 	if r.config.EnableTagFiltering && len(reqCtx.Tags) > 0 {
-		healthy = r.filterByTags(healthy, reqCtx.Tags)
+		healthy = traceFilter(reqCtx, router.FilterTags, healthy, r.filterByTags(healthy, reqCtx.Tags))
 		if len(healthy) == 0 {
 			return nil, ErrNoDeploymentsWithTag
 		}
 	}
 
 	if reqCtx.EstimatedInputTokens > 0 {
-		healthy = r.filterByTPMRPM(healthy, statsByID, reqCtx.EstimatedInputTokens)
+		healthy = traceFilter(reqCtx, router.FilterTPMRPM, healthy, r.filterByTPMRPM(healthy, statsByID, reqCtx.EstimatedInputTokens))
 		if len(healthy) == 0 {
 			return nil, ErrNoAvailableDeployment
 		}
 	}
 
-	healthy = r.filterByDefaultProvider(healthy)
+	healthy = traceFilter(reqCtx, router.FilterDefaultProvider, healthy, r.filterByDefaultProvider(healthy))
 	type deploymentLatency struct {
 		deployment *ExtendedDeployment
 		latency    float64
@@ -102,6 +103,7 @@ func (r *LatencyRouter) PickWithContext(ctx context.Context, reqCtx *router.Requ
 			latency = 0
 		}
 
+		traceScore(reqCtx, "latency_ms", d, latency)
 		candidates = append(candidates, deploymentLatency{
 			deployment: d,
 			latency:    latency,
@@ -54,30 +54,31 @@ func (r *LeastBusyRouter) Pick(ctx context.Context, model string) (*provider.Dep
 // PickWithContext selects the deployment with fewest active requests.
 func (r *LeastBusyRouter) PickWithContext(ctx context.Context, reqCtx *router.RequestContext) (*provider.Deployment, error) {
 	deployments := r.snapshotDeployments(reqCtx.Model)
+	traceCandidates(reqCtx, deployments)
 	if len(deployments) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 	statsByID := r.statsSnapshot(ctx, deployments)
-	healthy := r.getHealthyDeployments(deployments, statsByID)
+	healthy := traceFilter(reqCtx, router.FilterHealth, deployments, r.getHealthyDeployments(deployments, statsByID))
 	if len(healthy) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 
 	if r.config.EnableTagFiltering && len(reqCtx.Tags) > 0 {
-		healthy = r.filterByTags(healthy, reqCtx.Tags)
+		healthy = traceFilter(reqCtx, router.FilterTags, healthy, r.filterByTags(healthy, reqCtx.Tags))
 		if len(healthy) == 0 {
 			return nil, ErrNoDeploymentsWithTag
 		}
 	}
 
 	if reqCtx.EstimatedInputTokens > 0 {
-		healthy = r.filterByTPMRPM(healthy, statsByID, reqCtx.EstimatedInputTokens)
+		healthy = traceFilter(reqCtx, router.FilterTPMRPM, healthy, r.filterByTPMRPM(healthy, statsByID, reqCtx.EstimatedInputTokens))
 		if len(healthy) == 0 {
 			return nil, ErrNoAvailableDeployment
 		}
 	}
 
-	healthy = r.filterByDefaultProvider(healthy)
+	healthy = traceFilter(reqCtx, router.FilterDefaultProvider, healthy, r.filterByDefaultProvider(healthy))
 	type deploymentInfo struct {
 		deployment     *ExtendedDeployment
 		activeRequests int64
@@ -89,6 +90,7 @@ func (r *LeastBusyRouter) PickWithContext(ctx context.Context, reqCtx *router.Re
 			activeRequests = stats.ActiveRequests
 		}
 		candidates[i] = deploymentInfo{deployment: d, activeRequests: activeRequests}
+		traceScore(reqCtx, "active_requests", d, float64(activeRequests))
 	}
 
 	// Shuffle first to randomize selection among equal candidates
@@ -0,0 +1,48 @@
+package routers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blueberrycongee/llmux/internal/metrics"
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/provider"
+	"github.com/blueberrycongee/llmux/pkg/router"
+	"github.com/blueberrycongee/llmux/routers"
+)
+
+func TestBaseRouter_ExportsDeploymentMetrics(t *testing.T) {
+	config := router.DefaultConfig()
+	config.CooldownPeriod = time.Minute
+	config.EWMAAlpha = 0.5
+	r := routers.NewBaseRouter(config)
+
+	deployment := &provider.Deployment{ID: "metrics-deployment", ModelName: "gpt-metrics", ProviderName: "openai"}
+	secondary := &provider.Deployment{ID: "metrics-deployment-2", ModelName: "gpt-metrics", ProviderName: "openai"}
+	r.AddDeployment(deployment)
+	r.AddDeployment(secondary)
+	labels := []string{deployment.ID, deployment.ModelName, deployment.ProviderName}
+
+	ctx := context.Background()
+	r.ReportSuccess(ctx, deployment, &router.ResponseMetrics{Latency: 200 * time.Millisecond, TimeToFirstToken: 50 * time.Millisecond})
+	r.ReportSuccess(ctx, deployment, &router.ResponseMetrics{Latency: 400 * time.Millisecond, TimeToFirstToken: 150 * time.Millisecond})
+
+	assert.InDelta(t, 0.3, testutil.ToFloat64(metrics.RouterDeploymentEWMALatency.WithLabelValues(labels...)), 1e-9)
+	assert.InDelta(t, 0.1, testutil.ToFloat64(metrics.RouterDeploymentEWMATTFT.WithLabelValues(labels...)), 1e-9)
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.RouterDeploymentCooldown.WithLabelValues(labels...)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.RouterDeploymentFailureRate.WithLabelValues(labels...)))
+	assert.Positive(t, testutil.CollectAndCount(metrics.RouterDeploymentLatency))
+	assert.Positive(t, testutil.CollectAndCount(metrics.RouterDeploymentTTFT))
+
+	r.ReportFailure(ctx, deployment, llmerrors.NewAuthenticationError("openai", "gpt-metrics", "bad key"))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RouterDeploymentCooldown.WithLabelValues(labels...)))
+	assert.InDelta(t, 1.0/3.0, testutil.ToFloat64(metrics.RouterDeploymentFailureRate.WithLabelValues(labels...)), 1e-9)
+
+	assert.NoError(t, r.SetCooldown(deployment.ID, time.Time{}))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.RouterDeploymentCooldown.WithLabelValues(labels...)))
+}
@@ -16,6 +16,7 @@ const (
 	//   KEYS[3] - counters hash key (e.g., "llmux:router:stats:deployment-1:counters")
 	//   KEYS[4] - usage hash key prefix (e.g., "llmux:router:stats:deployment-1:usage:")
 	//   KEYS[5] - success bucket key prefix (e.g., "llmux:router:stats:deployment-1:successes:")
+	//   KEYS[6] - cooldown key
 	//
 	// Args:
 	//   ARGV[1] - latency value in milliseconds (float)
@@ -25,6 +26,7 @@ const (
 	//   ARGV[5] - usage TTL in seconds (integer, default 120)
 	//   ARGV[6] - bucket TTL in seconds (integer)
 	//   ARGV[7] - bucket size in seconds (integer)
+	//   ARGV[8] - half-open successes required to reopen (integer)
 	//
 	// Returns:
 	//   "OK" on success
@@ -34,6 +36,7 @@ local ttft_key = KEYS[2]
 local counters_key = KEYS[3]
 local usage_prefix = KEYS[4]
 local success_prefix = KEYS[5]
+local cooldown_key = KEYS[6]
 
 local latency = tonumber(ARGV[1])
 local ttft = tonumber(ARGV[2])
@@ -42,6 +45,7 @@ local max_size = tonumber(ARGV[4])
 local usage_ttl = tonumber(ARGV[5])
 local bucket_ttl = tonumber(ARGV[6])
 local bucket_seconds = tonumber(ARGV[7])
+local half_open_threshold = tonumber(ARGV[8])
 
 local time_data = redis.call('TIME')
 local now = tonumber(time_data[1])
@@ -78,6 +82,19 @@ redis.call('EXPIRE', usage_key, usage_ttl)
 redis.call('INCRBY', success_key, 1)
 redis.call('EXPIRE', success_key, bucket_ttl)
 
+-- 6. Count half-open probe successes; fully reopen after the threshold
+local trips = tonumber(redis.call('HGET', counters_key, 'cooldown_trips') or '0')
+if trips > 0 then
+    local cooldown_until = tonumber(redis.call('GET', cooldown_key) or '0')
+    if now >= cooldown_until then
+        local successes = redis.call('HINCRBY', counters_key, 'half_open_successes', 1)
+        redis.call('HDEL', counters_key, 'probe_started_at')
+        if successes >= half_open_threshold then
+            redis.call('HDEL', counters_key, 'cooldown_trips', 'half_open_successes')
+        end
+    end
+end
+
 return redis.status_reply("OK")
 `
 
@@ -102,7 +119,7 @@ return redis.status_reply("OK")
 	//   ARGV[9] - status code (int)
 	//   ARGV[10] - single deployment min requests
 	//   ARGV[11] - is single deployment (1 or 0)
-	//   ARGV[12] - cooldown TTL seconds
+	//   ARGV[12] - max cooldown seconds (exponential backoff cap)
 	//   ARGV[13] - bucket size in seconds
 	//
 	// Returns:
@@ -125,7 +142,7 @@ local immediate_on_429 = tonumber(ARGV[8])
 local status_code = tonumber(ARGV[9])
 local single_deploy_min_requests = tonumber(ARGV[10])
 local is_single_deployment = tonumber(ARGV[11])
-local cooldown_ttl = tonumber(ARGV[12])
+local cooldown_max_seconds = tonumber(ARGV[12])
 local bucket_seconds = tonumber(ARGV[13])
 
 local time_data = redis.call('TIME')
@@ -168,6 +185,13 @@ end
 local total_requests = total_success + total_failure
 local should_cooldown = false
 
+-- A failed half-open probe trips the circuit again immediately
+local trips = tonumber(redis.call('HGET', counters_key, 'cooldown_trips') or '0')
+local cooldown_until = tonumber(redis.call('GET', cooldown_key) or '0')
+if trips > 0 and now >= cooldown_until then
+    should_cooldown = true
+end
+
 if status_code == 401 or status_code == 404 or status_code == 408 then
     should_cooldown = true
 end
@@ -191,24 +215,51 @@ if not should_cooldown then
     end
 end
 
-if should_cooldown and cooldown_seconds and cooldown_seconds > 0 then
-    redis.call('SET', cooldown_key, now + cooldown_seconds)
-    redis.call('EXPIRE', cooldown_key, cooldown_ttl)
+-- Failures reported during an active cooldown do not extend it
+if should_cooldown and cooldown_seconds and cooldown_seconds > 0 and now >= cooldown_until then
+    trips = trips + 1
+    local duration = cooldown_seconds
+    if cooldown_max_seconds > cooldown_seconds then
+        for i = 2, trips do
+            duration = duration * 2
+            if duration >= cooldown_max_seconds then
+                duration = cooldown_max_seconds
+                break
+            end
+        end
+    end
+    redis.call('SET', cooldown_key, now + duration)
+    redis.call('EXPIRE', cooldown_key, duration + 10)
+    redis.call('HSET', counters_key, 'cooldown_trips', trips)
+    redis.call('HDEL', counters_key, 'half_open_successes', 'probe_started_at')
 end
 
 return redis.status_reply("OK")
 `
 
-	// incrementActiveRequestsScript atomically increments active request count.
+	// incrementActiveRequestsScript atomically increments active request count
+	// and claims the probe slot when the deployment is half-open.
 	//
 	// Keys:
 	//   KEYS[1] - counters hash key
+	//   KEYS[2] - cooldown key
 	//
 	// Returns:
 	//   The new active_requests count
 	incrementActiveRequestsScript = `
 local counters_key = KEYS[1]
+local cooldown_key = KEYS[2]
 local result = redis.call('HINCRBY', counters_key, 'active_requests', 1)
+
+local trips = tonumber(redis.call('HGET', counters_key, 'cooldown_trips') or '0')
+if trips > 0 then
+    local now = tonumber(redis.call('TIME')[1])
+    local cooldown_until = tonumber(redis.call('GET', cooldown_key) or '0')
+    if now >= cooldown_until then
+        redis.call('HSET', counters_key, 'probe_started_at', now)
+    end
+end
+
 redis.call('EXPIRE', counters_key, 3600)
 return result
 `
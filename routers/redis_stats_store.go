@@ -25,6 +25,8 @@ type RedisStatsStore struct {
 	failureThreshold   float64
 	minRequests        int
 	cooldownPeriod     time.Duration
+	cooldownMaxPeriod  time.Duration
+	halfOpenSuccesses  int
 	immediateOn429     bool
 	singleDeployMinReq int
 
@@ -97,6 +99,21 @@ func WithCooldownPeriod(period time.Duration) RedisStatsOption {
 	}
 }
 
+// WithCooldownMaxPeriod caps the exponential cooldown for distributed stats.
+func WithCooldownMaxPeriod(period time.Duration) RedisStatsOption {
+	return func(r *RedisStatsStore) {
+		r.cooldownMaxPeriod = period
+	}
+}
+
+// WithHalfOpenSuccessThreshold sets the consecutive probe successes required
+// to fully reopen a deployment after cooldown.
+func WithHalfOpenSuccessThreshold(successes int) RedisStatsOption {
+	return func(r *RedisStatsStore) {
+		r.halfOpenSuccesses = successes
+	}
+}
+
 // WithImmediateCooldownOn429 toggles immediate cooldown for 429 responses.
 func WithImmediateCooldownOn429(enabled bool) RedisStatsOption {
 	return func(r *RedisStatsStore) {
@@ -124,6 +141,8 @@ func NewRedisStatsStore(client redis.UniversalClient, opts ...RedisStatsOption)
 		failureThreshold:   defaults.FailureThresholdPercent,
 		minRequests:        defaults.MinRequestsForThreshold,
 		cooldownPeriod:     defaults.CooldownPeriod,
+		cooldownMaxPeriod:  defaults.CooldownMaxPeriod,
+		halfOpenSuccesses:  defaults.HalfOpenSuccessThreshold,
 		immediateOn429:     defaults.ImmediateCooldownOn429,
 		singleDeployMinReq: defaultSingleDeploymentFailureMinReq,
 	}
@@ -209,6 +228,11 @@ func (r *RedisStatsStore) GetStats(ctx context.Context, deploymentID string) (*D
 				stats.SuccessCount = parseInt64(countersMap["success_count"])
 				stats.FailureCount = parseInt64(countersMap["failure_count"])
 				stats.ActiveRequests = parseInt64(countersMap["active_requests"])
+				stats.CooldownTrips = int(parseInt64(countersMap["cooldown_trips"]))
+				stats.HalfOpenSuccesses = int(parseInt64(countersMap["half_open_successes"]))
+				if probeStartedAt := parseInt64(countersMap["probe_started_at"]); probeStartedAt > 0 {
+					stats.ProbeStartedAt = time.Unix(probeStartedAt, 0)
+				}
 
 				if lastReqTime := parseInt64(countersMap["last_request_time"]); lastReqTime > 0 {
 					stats.LastRequestTime = time.Unix(lastReqTime, 0)
@@ -273,7 +297,7 @@ func (r *RedisStatsStore) GetStats(ctx context.Context, deploymentID string) (*D
 
 // IncrementActiveRequests atomically increments the active request count.
 func (r *RedisStatsStore) IncrementActiveRequests(ctx context.Context, deploymentID string) error {
-	keys := []string{r.countersKey(ctx, deploymentID)}
+	keys := []string{r.countersKey(ctx, deploymentID), r.cooldownKey(ctx, deploymentID)}
 	_, err := r.incrementActiveReqScript.Run(ctx, r.client, keys).Result()
 	return err
 }
@@ -293,6 +317,7 @@ func (r *RedisStatsStore) RecordSuccess(ctx context.Context, deploymentID string
 		r.countersKey(ctx, deploymentID),
 		r.usageKeyPrefix(ctx, deploymentID),
 		r.successKeyPrefix(ctx, deploymentID),
+		r.cooldownKey(ctx, deploymentID),
 	}
 
 	latencyMs := float64(metrics.Latency.Milliseconds())
@@ -309,6 +334,7 @@ func (r *RedisStatsStore) RecordSuccess(ctx context.Context, deploymentID string
 		int(r.usageTTL.Seconds()),
 		r.bucketTTLSeconds(),
 		r.bucketSeconds(),
+		r.halfOpenSuccessThreshold(),
 	}
 
 	_, err := r.recordSuccessScript.Run(ctx, r.client, keys, args...).Result()
@@ -353,7 +379,7 @@ func (r *RedisStatsStore) RecordFailureWithOptions(ctx context.Context, deployme
 		statusCode,
 		r.singleDeployMinReq,
 		boolToInt(opts.isSingleDeployment),
-		r.cooldownTTLSeconds(),
+		int(r.cooldownMaxPeriod.Seconds()),
 		r.bucketSeconds(),
 	}
 
@@ -362,9 +388,16 @@ func (r *RedisStatsStore) RecordFailureWithOptions(ctx context.Context, deployme
 }
 
 // SetCooldown manually sets a cooldown period for a deployment.
+// A zero time also clears the half-open state.
 func (r *RedisStatsStore) SetCooldown(ctx context.Context, deploymentID string, until time.Time) error {
 	keys := []string{r.cooldownKey(ctx, deploymentID)}
 
+	if until.IsZero() {
+		if err := r.client.HDel(ctx, r.countersKey(ctx, deploymentID), "cooldown_trips", "half_open_successes", "probe_started_at").Err(); err != nil {
+			return err
+		}
+	}
+
 	ttl := time.Until(until)
 	if ttl <= 0 {
 		// Already expired, delete the key
@@ -540,12 +573,11 @@ func (r *RedisStatsStore) bucketTTLSeconds() int {
 	return windowSeconds + r.bucketSeconds()
 }
 
-func (r *RedisStatsStore) cooldownTTLSeconds() int {
-	cooldownSeconds := int(r.cooldownPeriod.Seconds())
-	if cooldownSeconds <= 0 {
-		return 0
+func (r *RedisStatsStore) halfOpenSuccessThreshold() int {
+	if r.halfOpenSuccesses > 0 {
+		return r.halfOpenSuccesses
 	}
-	return cooldownSeconds + 10
+	return defaultHalfOpenSuccessThreshold
 }
 
 func boolToInt(v bool) int {
@@ -188,3 +188,57 @@ func TestRedisStatsStore_UsesRedisTimeForBucketKeys(t *testing.T) {
 	expectedKey := store.successKey(ctx, deploymentID, strconv.FormatInt(bucket, 10))
 	require.True(t, s.Exists(expectedKey))
 }
+
+func TestRedisStatsStore_HalfOpenProbing(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	store := NewRedisStatsStore(
+		client,
+		WithCooldownPeriod(time.Minute),
+		WithCooldownMaxPeriod(4*time.Minute),
+		WithHalfOpenSuccessThreshold(2),
+	)
+
+	ctx := context.Background()
+	deploymentID := "deployment-half-open"
+	start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	s.SetTime(start)
+
+	require.NoError(t, store.RecordFailure(ctx, deploymentID, llmerrors.NewAuthenticationError("openai", "gpt-4", "invalid key")))
+	cooldownUntil, err := store.GetCooldownUntil(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, start.Add(time.Minute).Unix(), cooldownUntil.Unix())
+
+	// Half-open: starting a request claims the probe slot
+	probeTime := start.Add(61 * time.Second)
+	s.SetTime(probeTime)
+	require.NoError(t, store.IncrementActiveRequests(ctx, deploymentID))
+	stats, err := store.GetStats(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.CooldownTrips)
+	require.Equal(t, probeTime.Unix(), stats.ProbeStartedAt.Unix())
+
+	// A failed probe doubles the cooldown
+	require.NoError(t, store.RecordFailure(ctx, deploymentID, llmerrors.NewInternalError("openai", "gpt-4", "boom")))
+	cooldownUntil, err = store.GetCooldownUntil(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, probeTime.Add(2*time.Minute).Unix(), cooldownUntil.Unix())
+	stats, err = store.GetStats(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.CooldownTrips)
+	require.True(t, stats.ProbeStartedAt.IsZero())
+
+	// Two consecutive successful probes fully reopen the deployment
+	s.SetTime(cooldownUntil.Add(time.Second))
+	require.NoError(t, store.RecordSuccess(ctx, deploymentID, &router.ResponseMetrics{Latency: 10 * time.Millisecond}))
+	stats, err = store.GetStats(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.CooldownTrips)
+	require.Equal(t, 1, stats.HalfOpenSuccesses)
+
+	require.NoError(t, store.RecordSuccess(ctx, deploymentID, &router.ResponseMetrics{Latency: 10 * time.Millisecond}))
+	stats, err = store.GetStats(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Zero(t, stats.CooldownTrips)
+	require.Zero(t, stats.HalfOpenSuccesses)
+}
@@ -0,0 +1,61 @@
+package routers
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+// Factory creates a router for a registered strategy. statsStore and rrStore
+// are the distributed stores configured by the caller and may be nil.
+type Factory func(config router.Config, statsStore router.StatsStore, rrStore router.RoundRobinStore) (router.Router, error)
+
+var (
+	customRegistry   = make(map[router.Strategy]Factory)
+	customRegistryMu sync.RWMutex
+)
+
+// Register makes a custom routing strategy available by name to New,
+// NewWithStores and llmux.WithRouterStrategy. Registering an existing name
+// replaces its factory; built-in strategy names cannot be overridden and
+// return an error.
+//
+// Custom routers can embed *BaseRouter (see NewBaseRouterWithStore) to reuse
+// deployment bookkeeping, health tracking and cooldowns.
+func Register(name string, factory Factory) error {
+	strategy := router.Strategy(name)
+	switch {
+	case name == "":
+		return fmt.Errorf("routing strategy name is required")
+	case factory == nil:
+		return fmt.Errorf("routing strategy %q: factory is required", name)
+	case isBuiltinStrategy(strategy):
+		return fmt.Errorf("routing strategy %q is built in and cannot be overridden", name)
+	}
+	customRegistryMu.Lock()
+	defer customRegistryMu.Unlock()
+	customRegistry[strategy] = factory
+	return nil
+}
+
+// lookupFactory returns the factory registered for strategy.
+func lookupFactory(strategy router.Strategy) (Factory, bool) {
+	customRegistryMu.RLock()
+	defer customRegistryMu.RUnlock()
+	f, ok := customRegistry[strategy]
+	return f, ok
+}
+
+// registeredStrategies returns the custom strategy names, sorted.
+func registeredStrategies() []router.Strategy {
+	customRegistryMu.RLock()
+	defer customRegistryMu.RUnlock()
+	names := make([]router.Strategy, 0, len(customRegistry))
+	for name := range customRegistry {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
@@ -0,0 +1,87 @@
+package routers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/provider"
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+// firstDeploymentRouter always picks the first registered deployment.
+type firstDeploymentRouter struct {
+	*BaseRouter
+}
+
+func (r *firstDeploymentRouter) Pick(ctx context.Context, model string) (*provider.Deployment, error) {
+	return r.PickWithContext(ctx, &router.RequestContext{Model: model})
+}
+
+func (r *firstDeploymentRouter) PickWithContext(_ context.Context, reqCtx *router.RequestContext) (*provider.Deployment, error) {
+	deployments := r.GetDeployments(reqCtx.Model)
+	if len(deployments) == 0 {
+		return nil, ErrNoAvailableDeployment
+	}
+	return deployments[0], nil
+}
+
+func TestRegister_CustomStrategy(t *testing.T) {
+	var gotStore router.StatsStore
+	require.NoError(t, Register("test-first", func(config router.Config, statsStore router.StatsStore, _ router.RoundRobinStore) (router.Router, error) {
+		gotStore = statsStore
+		return &firstDeploymentRouter{BaseRouter: NewBaseRouterWithStore(config, statsStore)}, nil
+	}))
+
+	assert.True(t, IsValidStrategy("test-first"))
+	assert.Contains(t, AvailableStrategies(), router.Strategy("test-first"))
+
+	store := NewMemoryStatsStore()
+	r, err := NewWithStores(router.Config{Strategy: "test-first"}, store, nil)
+	require.NoError(t, err)
+	require.IsType(t, &firstDeploymentRouter{}, r)
+	assert.Same(t, store, gotStore)
+	assert.Equal(t, router.Strategy("test-first"), r.GetStrategy())
+
+	r.AddDeployment(&provider.Deployment{ID: "a", ModelName: "gpt-4"})
+	r.AddDeployment(&provider.Deployment{ID: "b", ModelName: "gpt-4"})
+	for i := 0; i < 5; i++ {
+		picked, err := r.Pick(context.Background(), "gpt-4")
+		require.NoError(t, err)
+		assert.Equal(t, "a", picked.ID)
+	}
+}
+
+func TestRegister_FactoryErrorAndBuiltins(t *testing.T) {
+	require.NoError(t, Register("test-broken", func(router.Config, router.StatsStore, router.RoundRobinStore) (router.Router, error) {
+		return nil, errors.New("boom")
+	}))
+	_, err := New(router.Config{Strategy: "test-broken"})
+	assert.ErrorContains(t, err, "boom")
+
+	called := false
+	err = Register(string(router.StrategyLeastBusy), func(router.Config, router.StatsStore, router.RoundRobinStore) (router.Router, error) {
+		called = true
+		return nil, errors.New("should not be used")
+	})
+	assert.ErrorContains(t, err, "cannot be overridden")
+	assert.Error(t, Register("", func(router.Config, router.StatsStore, router.RoundRobinStore) (router.Router, error) { return nil, nil }))
+	assert.Error(t, Register("test-nil", nil))
+	r, err := New(router.Config{Strategy: router.StrategyLeastBusy})
+	require.NoError(t, err)
+	assert.IsType(t, &LeastBusyRouter{}, r)
+	assert.False(t, called)
+	count := 0
+	for _, s := range AvailableStrategies() {
+		if s == router.StrategyLeastBusy {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+
+	_, err = New(router.Config{Strategy: "test-unknown"})
+	assert.Error(t, err)
+}
@@ -61,24 +61,25 @@ func (r *RoundRobinRouter) PickWithContext(ctx context.Context, reqCtx *router.R
 	}
 
 	deployments := r.snapshotDeployments(reqCtx.Model)
+	traceCandidates(reqCtx, deployments)
 	if len(deployments) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 	statsByID := r.statsSnapshot(ctx, deployments)
-	healthy := r.getHealthyDeployments(deployments, statsByID)
+	healthy := traceFilter(reqCtx, router.FilterHealth, deployments, r.getHealthyDeployments(deployments, statsByID))
 	if len(healthy) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 
 	if r.config.EnableTagFiltering && len(reqCtx.Tags) > 0 {
-		healthy = r.filterByTags(healthy, reqCtx.Tags)
+		healthy = traceFilter(reqCtx, router.FilterTags, healthy, r.filterByTags(healthy, reqCtx.Tags))
 		if len(healthy) == 0 {
 			return nil, ErrNoDeploymentsWithTag
 		}
 	}
 
 	if reqCtx.EstimatedInputTokens > 0 {
-		healthy = r.filterByTPMRPM(healthy, statsByID, reqCtx.EstimatedInputTokens)
+		healthy = traceFilter(reqCtx, router.FilterTPMRPM, healthy, r.filterByTPMRPM(healthy, statsByID, reqCtx.EstimatedInputTokens))
 		if len(healthy) == 0 {
 			return nil, ErrNoAvailableDeployment
 		}
@@ -50,41 +50,42 @@ func (r *ShuffleRouter) Pick(ctx context.Context, model string) (*provider.Deplo
 // PickWithContext selects a deployment using weighted random selection if weights are configured.
 func (r *ShuffleRouter) PickWithContext(ctx context.Context, reqCtx *router.RequestContext) (*provider.Deployment, error) {
 	deployments := r.snapshotDeployments(reqCtx.Model)
+	traceCandidates(reqCtx, deployments)
 	if len(deployments) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 	statsByID := r.statsSnapshot(ctx, deployments)
-	healthy := r.getHealthyDeployments(deployments, statsByID)
+	healthy := traceFilter(reqCtx, router.FilterHealth, deployments, r.getHealthyDeployments(deployments, statsByID))
 	if len(healthy) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 
 	if r.config.EnableTagFiltering && len(reqCtx.Tags) > 0 {
-		healthy = r.filterByTags(healthy, reqCtx.Tags)
+		healthy = traceFilter(reqCtx, router.FilterTags, healthy, r.filterByTags(healthy, reqCtx.Tags))
 		if len(healthy) == 0 {
 			return nil, ErrNoDeploymentsWithTag
 		}
 	}
 
 	if reqCtx.EstimatedInputTokens > 0 {
-		healthy = r.filterByTPMRPM(healthy, statsByID, reqCtx.EstimatedInputTokens)
+		healthy = traceFilter(reqCtx, router.FilterTPMRPM, healthy, r.filterByTPMRPM(healthy, statsByID, reqCtx.EstimatedInputTokens))
 		if len(healthy) == 0 {
 			return nil, ErrNoAvailableDeployment
 		}
 	}
 
-	healthy = r.filterByDefaultProvider(healthy)
+	healthy = traceFilter(reqCtx, router.FilterDefaultProvider, healthy, r.filterByDefaultProvider(healthy))
 	healthyCopy := make([]*ExtendedDeployment, len(healthy))
 	copy(healthyCopy, healthy)
 
 	// Try weighted selection by weight, rpm, or tpm (in that order)
-	if deployment := r.weightedPick(healthyCopy, "weight"); deployment != nil {
+	if deployment := r.weightedPick(reqCtx, healthyCopy, "weight"); deployment != nil {
 		return deployment, nil
 	}
-	if deployment := r.weightedPick(healthyCopy, "rpm"); deployment != nil {
+	if deployment := r.weightedPick(reqCtx, healthyCopy, "rpm"); deployment != nil {
 		return deployment, nil
 	}
-	if deployment := r.weightedPick(healthyCopy, "tpm"); deployment != nil {
+	if deployment := r.weightedPick(reqCtx, healthyCopy, "tpm"); deployment != nil {
 		return deployment, nil
 	}
 
@@ -93,7 +94,7 @@ func (r *ShuffleRouter) PickWithContext(ctx context.Context, reqCtx *router.Requ
 }
 
 // weightedPick performs weighted random selection based on the specified weight type.
-func (r *ShuffleRouter) weightedPick(deployments []*ExtendedDeployment, weightType string) *provider.Deployment {
+func (r *ShuffleRouter) weightedPick(reqCtx *router.RequestContext, deployments []*ExtendedDeployment, weightType string) *provider.Deployment {
 	weights := make([]float64, len(deployments))
 	hasWeights := false
 
@@ -126,6 +127,10 @@ func (r *ShuffleRouter) weightedPick(deployments []*ExtendedDeployment, weightTy
 		return nil
 	}
 
+	for i, d := range deployments {
+		traceScore(reqCtx, weightType, d, weights[i])
+	}
+
 	for i := range weights {
 		weights[i] /= totalWeight
 	}
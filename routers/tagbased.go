@@ -67,28 +67,29 @@ func (r *TagBasedRouter) Pick(ctx context.Context, model string) (*provider.Depl
 // PickWithContext filters deployments by tags and selects randomly.
 func (r *TagBasedRouter) PickWithContext(ctx context.Context, reqCtx *router.RequestContext) (*provider.Deployment, error) {
 	deployments := r.snapshotDeployments(reqCtx.Model)
+	traceCandidates(reqCtx, deployments)
 	if len(deployments) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 	statsByID := r.statsSnapshot(ctx, deployments)
-	healthy := r.getHealthyDeployments(deployments, statsByID)
+	healthy := traceFilter(reqCtx, router.FilterHealth, deployments, r.getHealthyDeployments(deployments, statsByID))
 	if len(healthy) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 
 	// Apply tag filtering
-	filtered := r.filterByTags(healthy, reqCtx.Tags)
+	filtered := traceFilter(reqCtx, router.FilterTags, healthy, r.filterByTags(healthy, reqCtx.Tags))
 	if len(filtered) == 0 {
 		return nil, ErrNoDeploymentsWithTag
 	}
 
 	if reqCtx.EstimatedInputTokens > 0 {
-		filtered = r.filterByTPMRPM(filtered, statsByID, reqCtx.EstimatedInputTokens)
+		filtered = traceFilter(reqCtx, router.FilterTPMRPM, filtered, r.filterByTPMRPM(filtered, statsByID, reqCtx.EstimatedInputTokens))
 		if len(filtered) == 0 {
 			return nil, ErrNoAvailableDeployment
 		}
 	}
 
-	filtered = r.filterByDefaultProvider(filtered)
+	filtered = traceFilter(reqCtx, router.FilterDefaultProvider, filtered, r.filterByDefaultProvider(filtered))
 	return filtered[r.randIntn(len(filtered))].Deployment, nil
 }
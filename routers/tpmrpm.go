@@ -54,17 +54,18 @@ func (r *TPMRPMRouter) Pick(ctx context.Context, model string) (*provider.Deploy
 // PickWithContext selects the deployment with lowest TPM/RPM usage.
 func (r *TPMRPMRouter) PickWithContext(ctx context.Context, reqCtx *router.RequestContext) (*provider.Deployment, error) {
 	deployments := r.snapshotDeployments(reqCtx.Model)
+	traceCandidates(reqCtx, deployments)
 	if len(deployments) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 	statsByID := r.statsSnapshot(ctx, deployments)
-	healthy := r.getHealthyDeployments(deployments, statsByID)
+	healthy := traceFilter(reqCtx, router.FilterHealth, deployments, r.getHealthyDeployments(deployments, statsByID))
 	if len(healthy) == 0 {
 		return nil, ErrNoAvailableDeployment
 	}
 
 	if r.config.EnableTagFiltering && len(reqCtx.Tags) > 0 {
-		healthy = r.filterByTags(healthy, reqCtx.Tags)
+		healthy = traceFilter(reqCtx, router.FilterTags, healthy, r.filterByTags(healthy, reqCtx.Tags))
 		if len(healthy) == 0 {
 			return nil, ErrNoDeploymentsWithTag
 		}
@@ -83,6 +84,7 @@ func (r *TPMRPMRouter) PickWithContext(ctx context.Context, reqCtx *router.Reque
 			currentRPM = stats.CurrentMinuteRPM
 		}
 		candidates[i] = deploymentInfo{deployment: d, currentTPM: currentTPM, currentRPM: currentRPM}
+		traceScore(reqCtx, "current_tpm", d, float64(currentTPM))
 	}
 
 	// Shuffle first to randomize selection among equal candidates
@@ -109,15 +111,16 @@ func (r *TPMRPMRouter) PickWithContext(ctx context.Context, reqCtx *router.Reque
 		eligible = append(eligible, c)
 	}
 
-	if len(eligible) == 0 {
-		return nil, ErrNoAvailableDeployment
-	}
-
 	eligibleDeployments := make([]*ExtendedDeployment, 0, len(eligible))
 	for _, c := range eligible {
 		eligibleDeployments = append(eligibleDeployments, c.deployment)
 	}
-	preferredDeployments := r.filterByDefaultProvider(eligibleDeployments)
+	traceFilter(reqCtx, router.FilterTPMRPM, healthy, eligibleDeployments)
+
+	if len(eligible) == 0 {
+		return nil, ErrNoAvailableDeployment
+	}
+	preferredDeployments := traceFilter(reqCtx, router.FilterDefaultProvider, eligibleDeployments, r.filterByDefaultProvider(eligibleDeployments))
 
 	var allowed map[string]struct{}
 	if len(preferredDeployments) < len(eligibleDeployments) {
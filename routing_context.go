@@ -1,8 +1,13 @@
 package llmux
 
 import (
+	stderrors "errors"
+	"fmt"
+
+	llmerrors "github.com/blueberrycongee/llmux/pkg/errors"
 	"github.com/blueberrycongee/llmux/pkg/router"
 	"github.com/blueberrycongee/llmux/pkg/types"
+	"github.com/blueberrycongee/llmux/routers"
 )
 
 func buildRouterRequestContext(req *types.ChatRequest, promptTokens int, isStreaming bool) *router.RequestContext {
@@ -21,6 +26,15 @@ func buildRouterRequestContext(req *types.ChatRequest, promptTokens int, isStrea
 	}
 }
 
+// routingError wraps a router pick failure. Tag mismatches become a client
+// error so callers can tell them apart from exhausted deployments.
+func routingError(model string, tags []string, err error) error {
+	if stderrors.Is(err, routers.ErrNoDeploymentsWithTag) {
+		return llmerrors.NewNoDeploymentsWithTagError(model, tags)
+	}
+	return fmt.Errorf("no available deployment for model %s: %w", model, err)
+}
+
 func sanitizeChatRequestForProvider(req *types.ChatRequest) *types.ChatRequest {
 	if req == nil {
 		return nil
@@ -0,0 +1,128 @@
+package llmux
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+// RoutingPolicy is the part of the routing configuration that can be
+// changed on a running client: the strategy, the cooldown applied to
+// failing deployments and the retry policy.
+type RoutingPolicy struct {
+	Strategy        Strategy
+	CooldownPeriod  time.Duration
+	RetryCount      int
+	RetryBackoff    time.Duration
+	RetryMaxBackoff time.Duration
+	RetryJitter     float64
+}
+
+// Validate checks the policy's values.
+func (p RoutingPolicy) Validate() error {
+	if p.Strategy == "" {
+		return fmt.Errorf("strategy is required")
+	}
+	if p.CooldownPeriod < 0 {
+		return fmt.Errorf("cooldown period must be non-negative")
+	}
+	if p.RetryCount < 0 {
+		return fmt.Errorf("retry count must be non-negative")
+	}
+	if p.RetryBackoff < 0 || p.RetryMaxBackoff < 0 {
+		return fmt.Errorf("retry backoff must be non-negative")
+	}
+	if p.RetryJitter < 0 || p.RetryJitter > 1 {
+		return fmt.Errorf("retry jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// RoutingPolicy returns the client's current routing policy.
+func (c *Client) RoutingPolicy() RoutingPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return RoutingPolicy{
+		Strategy:        c.router.GetStrategy(),
+		CooldownPeriod:  c.config.CooldownPeriod,
+		RetryCount:      c.config.RetryCount,
+		RetryBackoff:    c.config.RetryBackoff,
+		RetryMaxBackoff: c.config.RetryMaxBackoff,
+		RetryJitter:     c.config.RetryJitter,
+	}
+}
+
+// SetRoutingPolicy applies policy to the running client. Retry changes take
+// effect for new requests. A strategy or cooldown change replaces the
+// router: deployments and active cooldowns carry over, while local latency
+// and usage history start fresh unless a shared stats store is configured.
+// Requests already in flight finish on the previous router.
+func (c *Client) SetRoutingPolicy(policy RoutingPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current := c.router
+	if policy.Strategy != current.GetStrategy() || policy.CooldownPeriod != c.config.CooldownPeriod {
+		if c.config.Router != nil {
+			return fmt.Errorf("routing strategy and cooldown cannot be changed on a custom router")
+		}
+		next := *c.config
+		next.CooldownPeriod = policy.CooldownPeriod
+		rt, err := newStrategyRouter(&next, policy.Strategy)
+		if err != nil {
+			return err
+		}
+		c.moveDeployments(current, rt)
+		c.router = rt
+	}
+
+	c.config.RouterStrategy = policy.Strategy
+	c.config.CooldownPeriod = policy.CooldownPeriod
+	c.config.RetryCount = policy.RetryCount
+	c.config.RetryBackoff = policy.RetryBackoff
+	c.config.RetryMaxBackoff = policy.RetryMaxBackoff
+	c.config.RetryJitter = policy.RetryJitter
+
+	c.logger.Info("routing policy updated",
+		"strategy", policy.Strategy,
+		"cooldown_period", policy.CooldownPeriod,
+		"retry_count", policy.RetryCount,
+	)
+	return nil
+}
+
+// moveDeployments registers the client's deployments with to, keeping the
+// cooldowns still active on from. The caller must hold c.mu.
+func (c *Client) moveDeployments(from, to router.Router) {
+	now := time.Now()
+	for _, deployments := range c.deployments {
+		for _, d := range deployments {
+			to.AddDeploymentWithConfig(d, c.deploymentConfig[d.ID])
+			if stats := from.GetStats(d.ID); stats != nil && stats.CooldownUntil.After(now) {
+				if err := to.SetCooldown(d.ID, stats.CooldownUntil); err != nil {
+					c.logger.Warn("failed to carry over cooldown", "deployment", d.ID, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// activeRouter returns the router requests are currently routed with.
+// Callers that report on a deployment must keep using the router they
+// picked it from.
+func (c *Client) activeRouter() router.Router {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.router
+}
+
+func (c *Client) retryCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.config.RetryCount
+}
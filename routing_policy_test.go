@@ -0,0 +1,56 @@
+package llmux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/routers"
+)
+
+func TestSetRoutingPolicy_SwapsRouterAndKeepsCooldowns(t *testing.T) {
+	client := newRetryTestClient(t)
+	deploymentID := client.ListDeployments()[0].ID
+	until := time.Now().Add(time.Minute)
+	require.NoError(t, client.SetCooldown(deploymentID, until))
+
+	policy := client.RoutingPolicy()
+	require.Equal(t, StrategySimpleShuffle, policy.Strategy)
+	policy.Strategy = StrategyRoundRobin
+	policy.CooldownPeriod = 5 * time.Second
+	policy.RetryCount = 1
+	require.NoError(t, client.SetRoutingPolicy(policy))
+
+	require.Equal(t, policy, client.RoutingPolicy())
+	require.Equal(t, StrategyRoundRobin, client.activeRouter().GetStrategy())
+	require.Len(t, client.activeRouter().GetDeployments("test-model"), 1)
+	stats := client.GetStats(deploymentID)
+	require.NotNil(t, stats)
+	require.WithinDuration(t, until, stats.CooldownUntil, time.Millisecond)
+}
+
+func TestSetRoutingPolicy_Rejects(t *testing.T) {
+	client := newRetryTestClient(t)
+	before := client.RoutingPolicy()
+
+	invalid := before
+	invalid.Strategy = "fastest"
+	require.Error(t, client.SetRoutingPolicy(invalid))
+	invalid = before
+	invalid.RetryJitter = 1.5
+	require.Error(t, client.SetRoutingPolicy(invalid))
+	require.Equal(t, before, client.RoutingPolicy())
+
+	custom, err := New(
+		WithProviderInstance("primary", &httpMockProvider{name: "primary", models: []string{"test-model"}}, []string{"test-model"}),
+		WithRouter(routers.NewShuffleRouter()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = custom.Close() })
+	policy := custom.RoutingPolicy()
+	policy.RetryCount = 0
+	require.NoError(t, custom.SetRoutingPolicy(policy), "retry changes apply to custom routers")
+	policy.Strategy = StrategyLeastBusy
+	require.Error(t, custom.SetRoutingPolicy(policy))
+}
@@ -0,0 +1,130 @@
+package llmux
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/metrics"
+	"github.com/blueberrycongee/llmux/internal/observability"
+	"github.com/blueberrycongee/llmux/pkg/provider"
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+// RoutingDecision explains the deployment picks made for one request.
+type RoutingDecision struct {
+	RequestID string           `json:"request_id"`
+	Model     string           `json:"model"`
+	Strategy  Strategy         `json:"strategy"`
+	Tags      []string         `json:"tags,omitempty"`
+	Attempts  []RoutingAttempt `json:"attempts"`
+}
+
+// RoutingAttempt is one router pick. Retries, fallbacks and stream recovery
+// each add an attempt to the request's decision.
+type RoutingAttempt struct {
+	Time time.Time `json:"time"`
+	DecisionTrace
+	Selected string `json:"selected,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// routingTraceLog keeps the most recent routing decisions by request ID.
+type routingTraceLog struct {
+	mu        sync.Mutex
+	capacity  int
+	decisions map[string]*RoutingDecision
+	order     []string
+}
+
+func newRoutingTraceLog(capacity int) *routingTraceLog {
+	return &routingTraceLog{
+		capacity:  capacity,
+		decisions: make(map[string]*RoutingDecision, capacity),
+	}
+}
+
+func (l *routingTraceLog) record(requestID string, reqCtx *router.RequestContext, strategy Strategy, attempt RoutingAttempt) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	decision, ok := l.decisions[requestID]
+	if !ok {
+		if len(l.order) >= l.capacity {
+			delete(l.decisions, l.order[0])
+			l.order = l.order[1:]
+		}
+		decision = &RoutingDecision{
+			RequestID: requestID,
+			Model:     reqCtx.Model,
+			Strategy:  strategy,
+			Tags:      append([]string(nil), reqCtx.Tags...),
+		}
+		l.decisions[requestID] = decision
+		l.order = append(l.order, requestID)
+	}
+	decision.Attempts = append(decision.Attempts, attempt)
+}
+
+func (l *routingTraceLog) get(requestID string) (*RoutingDecision, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	decision, ok := l.decisions[requestID]
+	if !ok {
+		return nil, false
+	}
+	out := *decision
+	out.Attempts = append([]RoutingAttempt(nil), decision.Attempts...)
+	return &out, true
+}
+
+// RoutingExplanation returns why deployments were chosen for requestID.
+// It requires WithRouterTracing and reports false once the decision has been
+// evicted or if the request carried no request ID.
+func (c *Client) RoutingExplanation(requestID string) (*RoutingDecision, bool) {
+	if c.routingTraces == nil || requestID == "" {
+		return nil, false
+	}
+	return c.routingTraces.get(requestID)
+}
+
+// pickDeployment routes a request, counting the decision per strategy and
+// recording a decision trace when router tracing is enabled and the context
+// carries a request ID.
+func (c *Client) pickDeployment(ctx context.Context, reqCtx *router.RequestContext) (*provider.Deployment, error) {
+	deployment, err := c.tracedPick(ctx, reqCtx)
+	outcome, deploymentID := metrics.RoutingOutcomeSelected, ""
+	if err != nil || deployment == nil {
+		outcome = metrics.RoutingOutcomeNoDeployment
+	} else {
+		deploymentID = deployment.ID
+	}
+	metrics.RouterDecisions.WithLabelValues(string(c.activeRouter().GetStrategy()), reqCtx.Model, deploymentID, outcome).Inc()
+	return deployment, err
+}
+
+func (c *Client) tracedPick(ctx context.Context, reqCtx *router.RequestContext) (*provider.Deployment, error) {
+	rt := c.activeRouter()
+	if c.routingTraces == nil {
+		return rt.PickWithContext(ctx, reqCtx)
+	}
+	requestID := observability.RequestIDFromContext(ctx)
+	if requestID == "" {
+		return rt.PickWithContext(ctx, reqCtx)
+	}
+
+	trace := &router.DecisionTrace{}
+	traced := *reqCtx
+	traced.Trace = trace
+	deployment, err := rt.PickWithContext(ctx, &traced)
+
+	attempt := RoutingAttempt{Time: time.Now(), DecisionTrace: *trace}
+	if err != nil {
+		attempt.Error = err.Error()
+	} else if deployment != nil {
+		attempt.Selected = deployment.ID
+	}
+	c.routingTraces.record(requestID, reqCtx, rt.GetStrategy(), attempt)
+	return deployment, err
+}
@@ -0,0 +1,199 @@
+package llmux
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/internal/tokenizer"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// SandboxProviderName is the provider reported for sandbox responses.
+// Usage attributed to it is never priced.
+const SandboxProviderName = "sandbox"
+
+// defaultSandboxContent is returned when no sandbox rule matches.
+const defaultSandboxContent = "This is a sandbox response."
+
+// defaultSandboxDimensions is the size of sandbox embedding vectors.
+const defaultSandboxDimensions = 8
+
+// SandboxRule returns Content for requests whose last user message contains Match.
+type SandboxRule struct {
+	Match   string
+	Content string
+}
+
+// SandboxConfig configures the built-in mock provider that serves sandbox
+// requests. Responses depend only on the request, so integration tests can
+// assert on them.
+type SandboxConfig struct {
+	// Rules are evaluated in order; the first match wins.
+	Rules []SandboxRule
+	// DefaultContent is returned when no rule matches.
+	DefaultContent string
+	// EmbeddingDimensions is the size of sandbox embedding vectors.
+	EmbeddingDimensions int
+}
+
+type sandboxKey struct{}
+
+// WithSandbox marks requests made with ctx as sandbox requests: they are
+// answered by the built-in mock provider at zero cost instead of reaching a
+// real provider. Requests authenticated with a sandbox API key are sandbox
+// requests automatically.
+func WithSandbox(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sandboxKey{}, true)
+}
+
+// IsSandbox reports whether requests made with ctx are served by the mock provider.
+func IsSandbox(ctx context.Context) bool {
+	if sandbox, _ := ctx.Value(sandboxKey{}).(bool); sandbox {
+		return true
+	}
+	authCtx := auth.GetAuthContext(ctx)
+	return authCtx != nil && authCtx.APIKey != nil && authCtx.APIKey.IsSandbox()
+}
+
+// sandboxContent returns the deterministic reply to req.
+func (c *Client) sandboxContent(req *ChatRequest) string {
+	cfg := c.config.Sandbox
+	prompt := ""
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == "user" {
+			prompt = req.Messages[i].TextContent()
+			break
+		}
+	}
+	for _, rule := range cfg.Rules {
+		if strings.Contains(prompt, rule.Match) {
+			return rule.Content
+		}
+	}
+	if cfg.DefaultContent != "" {
+		return cfg.DefaultContent
+	}
+	return defaultSandboxContent
+}
+
+// sandboxID derives a stable response ID from the request content.
+func sandboxID(req *ChatRequest) string {
+	h := sha256.New()
+	h.Write([]byte(req.Model))
+	for _, msg := range req.Messages {
+		h.Write([]byte(msg.Role))
+		h.Write(msg.Content)
+	}
+	return "sandbox-" + hex.EncodeToString(h.Sum(nil)[:12])
+}
+
+// sandboxCompletion answers req with the mock provider.
+func (c *Client) sandboxCompletion(req *ChatRequest) *ChatResponse {
+	content := c.sandboxContent(req)
+	promptTokens := tokenizer.EstimatePromptTokens(req.Model, req)
+	completionTokens := tokenizer.EstimateCompletionTokensFromText(req.Model, content)
+	return &ChatResponse{
+		ID:      sandboxID(req),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+		Choices: []Choice{{
+			Message:      ChatMessage{Role: "assistant", Content: jsonString(content)},
+			FinishReason: "stop",
+		}},
+		Usage: &Usage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+			Provider:         SandboxProviderName,
+		},
+	}
+}
+
+// sandboxStream streams the mock reply to req word by word.
+func (c *Client) sandboxStream(req *ChatRequest) <-chan *StreamChunk {
+	resp := c.sandboxCompletion(req)
+	words := strings.SplitAfter(resp.Choices[0].Message.TextContent(), " ")
+
+	ch := make(chan *StreamChunk, len(words)+2)
+	chunk := func(delta StreamDelta, finish string) *StreamChunk {
+		return &StreamChunk{
+			ID:      resp.ID,
+			Object:  "chat.completion.chunk",
+			Created: resp.Created,
+			Model:   resp.Model,
+			Choices: []StreamChoice{{Delta: delta, FinishReason: finish}},
+		}
+	}
+	ch <- chunk(StreamDelta{Role: "assistant"}, "")
+	for _, word := range words {
+		ch <- chunk(StreamDelta{Content: word}, "")
+	}
+	last := chunk(StreamDelta{}, "stop")
+	if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+		last.Usage = resp.Usage
+	}
+	ch <- last
+	close(ch)
+	return ch
+}
+
+// sandboxEmbedding returns deterministic vectors derived from each input.
+func (c *Client) sandboxEmbedding(req *types.EmbeddingRequest) *types.EmbeddingResponse {
+	dims := c.config.Sandbox.EmbeddingDimensions
+	if dims <= 0 {
+		dims = defaultSandboxDimensions
+	}
+
+	var inputs []string
+	switch in := req.Input; {
+	case in == nil:
+	case in.Text != nil:
+		inputs = []string{*in.Text}
+	case len(in.Texts) > 0:
+		inputs = in.Texts
+	case len(in.Tokens) > 0:
+		inputs = []string{tokensString(in.Tokens)}
+	default:
+		for _, tokens := range in.TokensList {
+			inputs = append(inputs, tokensString(tokens))
+		}
+	}
+
+	resp := &types.EmbeddingResponse{Object: "list", Model: req.Model}
+	for i, input := range inputs {
+		resp.Data = append(resp.Data, types.EmbeddingObject{
+			Object:    "embedding",
+			Embedding: sandboxVector(input, dims),
+			Index:     i,
+		})
+	}
+	tokens := tokenizer.EstimateEmbeddingTokens(req.Model, req)
+	resp.Usage = types.Usage{PromptTokens: tokens, TotalTokens: tokens, Provider: SandboxProviderName}
+	return resp
+}
+
+func tokensString(tokens []int) string {
+	return fmt.Sprint(tokens)
+}
+
+// sandboxVector expands a hash of input into a vector in [-1, 1].
+func sandboxVector(input string, dims int) []float64 {
+	vec := make([]float64, dims)
+	seed := sha256.Sum256([]byte(input))
+	block := seed
+	for i := range vec {
+		if i > 0 && i%8 == 0 {
+			block = sha256.Sum256(block[:])
+		}
+		v := binary.BigEndian.Uint32(block[(i%8)*4:])
+		vec[i] = float64(v)/float64(^uint32(0))*2 - 1
+	}
+	return vec
+}
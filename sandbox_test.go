@@ -0,0 +1,128 @@
+package llmux
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+func newSandboxTestClient(t *testing.T) (*Client, *atomic.Int32) {
+	t.Helper()
+	var upstream atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstream.Add(1)
+		http.Error(w, "unexpected upstream call", http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"m"}, baseURL: server.URL}, []string{"m"}),
+		withTestPricing(t, "m"),
+		WithSandboxConfig(SandboxConfig{
+			Rules:               []SandboxRule{{Match: "weather", Content: "Sunny, 22C."}},
+			EmbeddingDimensions: 4,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client, &upstream
+}
+
+func TestSandbox_ChatCompletionIsDeterministicAndFree(t *testing.T) {
+	client, upstream := newSandboxTestClient(t)
+	ctx := auth.WithAuthContext(context.Background(), &auth.AuthContext{
+		APIKey: &auth.APIKey{ID: "test-key", IsActive: true, Metadata: auth.Metadata{"sandbox": true}},
+	})
+	req := func(text string) *ChatRequest {
+		return &ChatRequest{Model: "m", Messages: []ChatMessage{{Role: "user", Content: jsonString(text)}}}
+	}
+
+	first, err := client.ChatCompletion(ctx, req("what's the weather?"))
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	second, err := client.ChatCompletion(ctx, req("what's the weather?"))
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if got := first.Choices[0].Message.TextContent(); got != "Sunny, 22C." {
+		t.Fatalf("content = %q, want rule content", got)
+	}
+	if first.ID != second.ID {
+		t.Fatalf("IDs differ for identical requests: %q vs %q", first.ID, second.ID)
+	}
+	if cost := client.CalculateCost("m", first.Usage); cost != 0 {
+		t.Fatalf("sandbox cost = %v, want 0", cost)
+	}
+
+	other, err := client.ChatCompletion(ctx, req("hello"))
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if got := other.Choices[0].Message.TextContent(); got != defaultSandboxContent {
+		t.Fatalf("content = %q, want default content", got)
+	}
+	if n := upstream.Load(); n != 0 {
+		t.Fatalf("upstream called %d times", n)
+	}
+}
+
+func TestSandbox_StreamAndEmbedding(t *testing.T) {
+	client, upstream := newSandboxTestClient(t)
+	ctx := WithSandbox(context.Background())
+
+	stream, err := client.ChatCompletionStream(ctx, &ChatRequest{
+		Model:    "m",
+		Messages: []ChatMessage{{Role: "user", Content: jsonString("weather please")}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+	var content strings.Builder
+	for {
+		chunk, recvErr := stream.Recv()
+		if recvErr == io.EOF {
+			break
+		}
+		if recvErr != nil {
+			t.Fatalf("Recv() error = %v", recvErr)
+		}
+		if len(chunk.Choices) > 0 {
+			content.WriteString(chunk.Choices[0].Delta.Content)
+		}
+	}
+	if content.String() != "Sunny, 22C." {
+		t.Fatalf("streamed content = %q", content.String())
+	}
+
+	text := "hello"
+	embed := func() []float64 {
+		resp, embedErr := client.Embedding(ctx, &types.EmbeddingRequest{Model: "m", Input: &types.EmbeddingInput{Text: &text}})
+		if embedErr != nil {
+			t.Fatalf("Embedding() error = %v", embedErr)
+		}
+		if len(resp.Data) != 1 || len(resp.Data[0].Embedding) != 4 {
+			t.Fatalf("unexpected embedding response: %+v", resp.Data)
+		}
+		return resp.Data[0].Embedding
+	}
+	a, b := embed(), embed()
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("embeddings differ: %v vs %v", a, b)
+		}
+	}
+	if n := upstream.Load(); n != 0 {
+		t.Fatalf("upstream called %d times", n)
+	}
+}
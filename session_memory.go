@@ -0,0 +1,167 @@
+package llmux
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/memory"
+	"github.com/blueberrycongee/llmux/pkg/errors"
+	"github.com/blueberrycongee/llmux/pkg/router"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// sessionRecordTimeout bounds how long recording a turn may take after the
+// response has been produced.
+const sessionRecordTimeout = 5 * time.Second
+
+// Re-export session memory types.
+type (
+	// SessionMemory stores and replays conversation history by session ID.
+	SessionMemory = memory.SessionMemory
+
+	// SessionStore persists conversation turns.
+	SessionStore = memory.SessionStore
+
+	// SessionTurn is one exchange of a conversation.
+	SessionTurn = memory.Turn
+
+	// SessionWindow bounds the history replayed into a request.
+	SessionWindow = memory.Window
+
+	// RedisSessionConfig configures the Redis session store.
+	RedisSessionConfig = memory.RedisSessionConfig
+)
+
+// Session memory constructors.
+var (
+	// NewSessionMemory creates a session memory over a store.
+	NewSessionMemory = memory.NewSessionMemory
+	// NewMemorySessionStore creates an in-process session store.
+	NewMemorySessionStore = memory.NewMemorySessionStore
+	// NewRedisSessionStore creates a Redis-backed session store.
+	NewRedisSessionStore = memory.NewRedisSessionStore
+	// NewPostgresSessionStore creates a Postgres-backed session store.
+	NewPostgresSessionStore = memory.NewPostgresSessionStore
+)
+
+type sessionIDKey struct{}
+
+// WithSessionID attaches a conversation session ID to ctx. When the client
+// has session memory, the session's history is prepended to requests made
+// with ctx and each completed exchange is appended to it, so callers only
+// send the new messages of every turn.
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, sessionID)
+}
+
+// SessionIDFromContext returns the session ID attached to ctx.
+func SessionIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(sessionIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// sessionKey scopes the session ID to the caller's tenant so sessions of
+// different API keys never share history.
+func sessionKey(ctx context.Context) (string, bool) {
+	id, ok := SessionIDFromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	if scope := router.TenantScopeFromContext(ctx); scope != "" {
+		return scope + "/" + id, true
+	}
+	return id, true
+}
+
+// withSessionHistory returns req with the session's history inserted after
+// its leading system messages, and the caller's own messages to record once
+// the exchange completes. It returns req unchanged without a session.
+func (c *Client) withSessionHistory(ctx context.Context, req *ChatRequest) (*ChatRequest, []ChatMessage, error) {
+	if c.sessionMemory == nil {
+		return req, nil, nil
+	}
+	key, ok := sessionKey(ctx)
+	if !ok {
+		return req, nil, nil
+	}
+
+	split := 0
+	for split < len(req.Messages) && req.Messages[split].Role == "system" {
+		split++
+	}
+	turn := append([]ChatMessage(nil), req.Messages[split:]...)
+
+	history, err := c.sessionMemory.History(ctx, key)
+	if stderrors.Is(err, memory.ErrInvalidSessionID) {
+		return nil, nil, errors.NewInvalidRequestError("", req.Model, "invalid session id")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("load session history: %w", err)
+	}
+	if err := c.checkSessionQuota(ctx, req.Model, key, turn); err != nil {
+		return nil, nil, err
+	}
+	if len(history) == 0 {
+		return req, turn, nil
+	}
+
+	cloned := *req
+	cloned.Messages = make([]ChatMessage, 0, len(req.Messages)+len(history))
+	cloned.Messages = append(cloned.Messages, req.Messages[:split]...)
+	cloned.Messages = append(cloned.Messages, history...)
+	cloned.Messages = append(cloned.Messages, turn...)
+	return &cloned, turn, nil
+}
+
+// recordSessionTurn appends the caller's messages and the assistant reply to
+// the session. Failures are logged; they never fail the request.
+func (c *Client) recordSessionTurn(ctx context.Context, turn []ChatMessage, reply *types.ChatMessage) {
+	key, ok := sessionKey(ctx)
+	if !ok || c.sessionMemory == nil || len(turn) == 0 {
+		return
+	}
+	messages := turn
+	if reply != nil {
+		messages = append(append([]ChatMessage(nil), turn...), *reply)
+	}
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sessionRecordTimeout)
+	defer cancel()
+	if err := c.sessionMemory.Record(recordCtx, key, messages); err != nil {
+		c.logger.Warn("failed to record session turn", "error", err)
+		return
+	}
+	c.trackSession(recordCtx, key)
+}
+
+// SessionHistory returns the replayable history of a session for the tenant
+// attached to ctx. It returns nil when session memory is not configured.
+func (c *Client) SessionHistory(ctx context.Context, sessionID string) ([]ChatMessage, error) {
+	if c.sessionMemory == nil {
+		return nil, nil
+	}
+	ctx = c.withTenantScope(ctx)
+	key, ok := sessionKey(WithSessionID(ctx, sessionID))
+	if !ok {
+		return nil, memory.ErrInvalidSessionID
+	}
+	return c.sessionMemory.History(ctx, key)
+}
+
+// ClearSession deletes the history of a session for the tenant attached to ctx.
+func (c *Client) ClearSession(ctx context.Context, sessionID string) error {
+	if c.sessionMemory == nil {
+		return nil
+	}
+	ctx = c.withTenantScope(ctx)
+	key, ok := sessionKey(WithSessionID(ctx, sessionID))
+	if !ok {
+		return memory.ErrInvalidSessionID
+	}
+	if err := c.sessionMemory.Clear(ctx, key); err != nil {
+		return err
+	}
+	c.untrackMemory(ctx, memory.KindSession, key)
+	return nil
+}
@@ -0,0 +1,124 @@
+package llmux
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blueberrycongee/llmux/pkg/router"
+)
+
+func TestSessionMemory_ReplaysAndRecordsHistory(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received [][]ChatMessage
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		received = append(received, req.Messages)
+		n := len(received)
+		mu.Unlock()
+
+		reply := "first reply"
+		if n > 1 {
+			reply = "second reply"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			ID:      "resp",
+			Model:   "m",
+			Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: jsonString(reply)}, FinishReason: "stop"}},
+		})
+	}))
+	defer server.Close()
+
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"m"}, baseURL: server.URL}, []string{"m"}),
+		withTestPricing(t, "m"),
+		WithTimeout(5*time.Second),
+		WithSessionMemory(NewSessionMemory(NewMemorySessionStore(0), SessionWindow{MaxTurns: 10})),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := WithSessionID(router.WithTenantScope(context.Background(), "key-1"), "conv")
+	send := func(ctx context.Context, text string) {
+		t.Helper()
+		_, err := client.ChatCompletion(ctx, &ChatRequest{
+			Model: "m",
+			Messages: []ChatMessage{
+				{Role: "system", Content: jsonString("be brief")},
+				{Role: "user", Content: jsonString(text)},
+			},
+		})
+		if err != nil {
+			t.Fatalf("ChatCompletion(%q) error = %v", text, err)
+		}
+	}
+
+	send(ctx, "hello")
+	send(ctx, "again")
+
+	mu.Lock()
+	second := received[1]
+	mu.Unlock()
+	var got []string
+	for _, m := range second {
+		got = append(got, m.Role+":"+m.TextContent())
+	}
+	want := []string{"system:be brief", "user:hello", "assistant:first reply", "user:again"}
+	if len(got) != len(want) {
+		t.Fatalf("second request messages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("second request messages = %v, want %v", got, want)
+		}
+	}
+
+	history, err := client.SessionHistory(router.WithTenantScope(context.Background(), "key-1"), "conv")
+	if err != nil {
+		t.Fatalf("SessionHistory() error = %v", err)
+	}
+	if len(history) != 4 {
+		t.Fatalf("history length = %d, want 4", len(history))
+	}
+
+	other, err := client.SessionHistory(router.WithTenantScope(context.Background(), "key-2"), "conv")
+	if err != nil {
+		t.Fatalf("SessionHistory() error = %v", err)
+	}
+	if len(other) != 0 {
+		t.Fatalf("another tenant sees %d messages of the session", len(other))
+	}
+}
+
+func TestSessionMemory_RejectsInvalidSessionID(t *testing.T) {
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"m"}}, []string{"m"}),
+		withTestPricing(t, "m"),
+		WithSessionMemory(NewSessionMemory(NewMemorySessionStore(0), SessionWindow{})),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.ChatCompletion(WithSessionID(context.Background(), strings.Repeat("x", 300)), &ChatRequest{
+		Model:    "m",
+		Messages: []ChatMessage{{Role: "user", Content: jsonString("hi")}},
+	})
+	llmErr, ok := err.(*LLMError)
+	if !ok || llmErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 LLMError, got %v", err)
+	}
+}
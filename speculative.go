@@ -0,0 +1,251 @@
+package llmux
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+// defaultJudgeMinScore is the judge score (1-10) at which a draft is accepted
+// when JudgeVerifier.MinScore is unset.
+const defaultJudgeMinScore = 7
+
+// SpeculativeConfig configures speculative draft routing: requests for a
+// target model are first sent to a cheaper draft model, and the draft is
+// returned only if the verifier accepts it. Otherwise the request escalates
+// to the target model. Only non-streaming chat completions are speculated.
+type SpeculativeConfig struct {
+	// Drafts maps a target model to the draft model tried first.
+	Drafts map[string]string
+	// Verifier decides whether a draft response is returned.
+	Verifier DraftVerifier
+}
+
+// DraftVerdict is the outcome of verifying a draft response.
+type DraftVerdict struct {
+	Accept bool
+	// Score is the verifier's confidence measure (average logprob or judge score).
+	Score  float64
+	Reason string
+}
+
+// DraftCompleter executes a chat completion outside of speculative routing.
+// Verifiers use it to call judge models.
+type DraftCompleter func(ctx context.Context, req *ChatRequest) (*ChatResponse, error)
+
+// DraftVerifier decides whether a draft response is good enough to return.
+type DraftVerifier interface {
+	VerifyDraft(ctx context.Context, req *ChatRequest, draft *ChatResponse, complete DraftCompleter) (DraftVerdict, error)
+}
+
+// logprobsVerifier is implemented by verifiers that need token logprobs on
+// the draft response.
+type logprobsVerifier interface {
+	needsLogprobs() bool
+}
+
+// LogprobVerifier accepts drafts whose mean token logprob is at least
+// MinAvgLogprob (e.g. -0.5). Drafts without logprobs are escalated.
+type LogprobVerifier struct {
+	MinAvgLogprob float64
+}
+
+func (v *LogprobVerifier) needsLogprobs() bool { return true }
+
+// VerifyDraft implements DraftVerifier.
+func (v *LogprobVerifier) VerifyDraft(_ context.Context, _ *ChatRequest, draft *ChatResponse, _ DraftCompleter) (DraftVerdict, error) {
+	if draft == nil || len(draft.Choices) == 0 || draft.Choices[0].Logprobs == nil || len(draft.Choices[0].Logprobs.Content) == 0 {
+		return DraftVerdict{Reason: "draft returned no logprobs"}, nil
+	}
+	tokens := draft.Choices[0].Logprobs.Content
+	sum := 0.0
+	for _, token := range tokens {
+		sum += token.Logprob
+	}
+	avg := sum / float64(len(tokens))
+	if avg < v.MinAvgLogprob {
+		return DraftVerdict{Score: avg, Reason: fmt.Sprintf("average logprob %.3f below %.3f", avg, v.MinAvgLogprob)}, nil
+	}
+	return DraftVerdict{Accept: true, Score: avg}, nil
+}
+
+// JudgeVerifier asks a judge model to score the draft from 1 to 10 and
+// accepts drafts scoring at least MinScore (default 7).
+type JudgeVerifier struct {
+	Model    string
+	MinScore float64
+}
+
+var judgeScorePattern = regexp.MustCompile(`\d+(\.\d+)?`)
+
+const judgeSystemPrompt = "You grade draft answers from an AI assistant. Rate how correct, complete " +
+	"and helpful the draft answer is for the conversation on a scale from 1 to 10. " +
+	"Reply with the number only."
+
+// VerifyDraft implements DraftVerifier.
+func (v *JudgeVerifier) VerifyDraft(ctx context.Context, req *ChatRequest, draft *ChatResponse, complete DraftCompleter) (DraftVerdict, error) {
+	if draft == nil || len(draft.Choices) == 0 {
+		return DraftVerdict{Reason: "draft returned no choices"}, nil
+	}
+
+	var transcript strings.Builder
+	for _, msg := range req.Messages {
+		fmt.Fprintf(&transcript, "%s: %s\n", msg.Role, msg.TextContent())
+	}
+	prompt := fmt.Sprintf("Conversation:\n%s\nDraft answer:\n%s", transcript.String(), draft.Choices[0].Message.TextContent())
+
+	judgeResp, err := complete(ctx, &ChatRequest{
+		Model:     v.Model,
+		MaxTokens: 8,
+		Messages: []ChatMessage{
+			{Role: "system", Content: jsonString(judgeSystemPrompt)},
+			{Role: "user", Content: jsonString(prompt)},
+		},
+	})
+	if err != nil {
+		return DraftVerdict{}, fmt.Errorf("judge %s: %w", v.Model, err)
+	}
+	if judgeResp == nil || len(judgeResp.Choices) == 0 {
+		return DraftVerdict{}, fmt.Errorf("judge %s returned no choices", v.Model)
+	}
+	match := judgeScorePattern.FindString(judgeResp.Choices[0].Message.TextContent())
+	if match == "" {
+		return DraftVerdict{}, fmt.Errorf("judge %s returned no score", v.Model)
+	}
+	score, err := strconv.ParseFloat(match, 64)
+	if err != nil {
+		return DraftVerdict{}, fmt.Errorf("judge %s returned invalid score %q", v.Model, match)
+	}
+
+	minScore := v.MinScore
+	if minScore <= 0 {
+		minScore = defaultJudgeMinScore
+	}
+	if score < minScore {
+		return DraftVerdict{Score: score, Reason: fmt.Sprintf("judge score %.1f below %.1f", score, minScore)}, nil
+	}
+	return DraftVerdict{Accept: true, Score: score}, nil
+}
+
+func jsonString(s string) json.RawMessage {
+	data, _ := json.Marshal(s)
+	return data
+}
+
+// speculativeDraftModel returns the draft model configured for req, if any.
+func (c *Client) speculativeDraftModel(req *ChatRequest, canonicalModel string) (string, bool) {
+	spec := c.config.Speculative
+	if spec == nil || spec.Verifier == nil || req.Stream || len(spec.Drafts) == 0 {
+		return "", false
+	}
+	if draft, ok := spec.Drafts[req.Model]; ok && draft != "" && draft != req.Model {
+		return draft, true
+	}
+	if draft, ok := spec.Drafts[canonicalModel]; ok && draft != "" && draft != canonicalModel {
+		return draft, true
+	}
+	return "", false
+}
+
+// speculativeCompletion tries draftModel first and escalates to req.Model
+// when the draft fails or is rejected. The cost of the draft and of any
+// verifier calls not reflected in the returned response is added to its
+// Usage.AuxiliaryCost so spend tracking covers both calls.
+func (c *Client) speculativeCompletion(ctx context.Context, req *ChatRequest, draftModel string, promptEstimate int) (*ChatResponse, error) {
+	spec := c.config.Speculative
+
+	draftReq := *req
+	draftReq.Model = draftModel
+	wantLogprobs := false
+	if lv, ok := spec.Verifier.(logprobsVerifier); ok && lv.needsLogprobs() {
+		_, callerLogprobs := req.Extra["logprobs"]
+		wantLogprobs = !callerLogprobs
+		if wantLogprobs {
+			draftReq.Extra = make(map[string]json.RawMessage, len(req.Extra)+1)
+			for k, v := range req.Extra {
+				draftReq.Extra[k] = v
+			}
+			draftReq.Extra["logprobs"] = json.RawMessage("true")
+		}
+	}
+
+	var (
+		verifyMu   sync.Mutex
+		verifyCost float64
+	)
+	complete := func(ctx context.Context, judgeReq *ChatRequest) (*ChatResponse, error) {
+		resp, err := c.routeAndExecute(ctx, judgeReq, 0)
+		if err == nil && resp != nil {
+			verifyMu.Lock()
+			verifyCost += c.responseCost(judgeReq.Model, resp)
+			verifyMu.Unlock()
+		}
+		return resp, err
+	}
+
+	var verdict DraftVerdict
+	draftResp, err := c.routeAndExecute(ctx, &draftReq, promptEstimate)
+	if err != nil {
+		verdict.Reason = "draft failed: " + err.Error()
+	} else if verdict, err = spec.Verifier.VerifyDraft(ctx, req, draftResp, complete); err != nil {
+		verdict = DraftVerdict{Reason: "verifier failed: " + err.Error()}
+	}
+
+	draftCost := c.responseCost(draftModel, draftResp)
+	verifyMu.Lock()
+	auxCost := verifyCost
+	verifyMu.Unlock()
+
+	c.logger.Info("speculative routing decision",
+		"model", req.Model,
+		"draft_model", draftModel,
+		"accepted", verdict.Accept,
+		"score", verdict.Score,
+		"reason", verdict.Reason,
+		"draft_cost", draftCost,
+		"verify_cost", auxCost,
+	)
+
+	if verdict.Accept {
+		if wantLogprobs {
+			for i := range draftResp.Choices {
+				draftResp.Choices[i].Logprobs = nil
+			}
+		}
+		addAuxiliaryCost(draftResp, auxCost)
+		return draftResp, nil
+	}
+
+	resp, err := c.routeAndExecute(ctx, req, promptEstimate)
+	if err != nil {
+		return nil, err
+	}
+	addAuxiliaryCost(resp, draftCost+auxCost)
+	return resp, nil
+}
+
+// responseCost prices resp using the model it reports, falling back to model.
+func (c *Client) responseCost(model string, resp *ChatResponse) float64 {
+	if resp == nil || resp.Usage == nil {
+		return 0
+	}
+	if resp.Model != "" {
+		model = resp.Model
+	}
+	return c.CalculateCost(model, resp.Usage)
+}
+
+func addAuxiliaryCost(resp *ChatResponse, cost float64) {
+	if resp == nil || cost <= 0 {
+		return
+	}
+	if resp.Usage == nil {
+		resp.Usage = &Usage{}
+	}
+	resp.Usage.AuxiliaryCost += cost
+}
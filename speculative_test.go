@@ -0,0 +1,176 @@
+package llmux
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// speculativeServer answers chat requests per model. The draft model returns
+// logprobs of draftLogprob when asked for them; the judge model replies with
+// judgeReply.
+type speculativeServer struct {
+	mu           sync.Mutex
+	models       []string
+	draftLogprob float64
+	judgeReply   string
+}
+
+func (s *speculativeServer) handler(w http.ResponseWriter, r *http.Request) {
+	var body map[string]json.RawMessage
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	var model string
+	_ = json.Unmarshal(body["model"], &model)
+
+	s.mu.Lock()
+	s.models = append(s.models, model)
+	s.mu.Unlock()
+
+	content := "answer from " + model
+	if model == "judge" {
+		content = s.judgeReply
+	}
+	choice := Choice{
+		Message:      ChatMessage{Role: "assistant", Content: jsonString(content)},
+		FinishReason: "stop",
+	}
+	if _, ok := body["logprobs"]; ok {
+		choice.Logprobs = &types.Logprobs{Content: []types.LogprobContent{
+			{Token: "answer", Logprob: s.draftLogprob},
+			{Token: "!", Logprob: s.draftLogprob},
+		}}
+	}
+	resp := ChatResponse{
+		ID:      "spec-" + model,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   model,
+		Choices: []Choice{choice},
+		Usage:   &Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *speculativeServer) calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.models...)
+}
+
+func newSpeculativeClient(t *testing.T, srv *speculativeServer, verifier DraftVerifier) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(srv.handler))
+	t.Cleanup(server.Close)
+
+	models := []string{"big", "small", "judge"}
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: models, baseURL: server.URL}, models),
+		withTestPricing(t, models...),
+		WithRetry(0, 0),
+		WithSpeculativeRouting(SpeculativeConfig{
+			Drafts:   map[string]string{"big": "small"},
+			Verifier: verifier,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func speculativeRequest() *ChatRequest {
+	return &ChatRequest{
+		Model:    "big",
+		Messages: []ChatMessage{{Role: "user", Content: jsonString("hi")}},
+	}
+}
+
+// 10 prompt tokens * 0.00001 + 5 completion tokens * 0.00002.
+const speculativeCallCost = 0.0002
+
+func TestSpeculativeRouting_LogprobAccept(t *testing.T) {
+	srv := &speculativeServer{draftLogprob: -0.1}
+	client := newSpeculativeClient(t, srv, &LogprobVerifier{MinAvgLogprob: -0.5})
+
+	resp, err := client.ChatCompletion(context.Background(), speculativeRequest())
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.Model != "small" {
+		t.Fatalf("expected draft response, got model %q", resp.Model)
+	}
+	if resp.Choices[0].Logprobs != nil {
+		t.Fatalf("expected logprobs requested for verification to be stripped")
+	}
+	if calls := srv.calls(); len(calls) != 1 || calls[0] != "small" {
+		t.Fatalf("unexpected upstream calls: %v", calls)
+	}
+	if got := client.CalculateCost(resp.Model, resp.Usage); math.Abs(got-speculativeCallCost) > 1e-12 {
+		t.Fatalf("cost = %v, want %v", got, speculativeCallCost)
+	}
+}
+
+func TestSpeculativeRouting_LogprobEscalates(t *testing.T) {
+	srv := &speculativeServer{draftLogprob: -2}
+	client := newSpeculativeClient(t, srv, &LogprobVerifier{MinAvgLogprob: -0.5})
+
+	resp, err := client.ChatCompletion(context.Background(), speculativeRequest())
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.Model != "big" {
+		t.Fatalf("expected escalated response, got model %q", resp.Model)
+	}
+	if calls := srv.calls(); len(calls) != 2 || calls[0] != "small" || calls[1] != "big" {
+		t.Fatalf("unexpected upstream calls: %v", calls)
+	}
+	if math.Abs(resp.Usage.AuxiliaryCost-speculativeCallCost) > 1e-12 {
+		t.Fatalf("auxiliary cost = %v, want draft cost %v", resp.Usage.AuxiliaryCost, speculativeCallCost)
+	}
+	if got := client.CalculateCost(resp.Model, resp.Usage); math.Abs(got-2*speculativeCallCost) > 1e-12 {
+		t.Fatalf("cost = %v, want draft plus target %v", got, 2*speculativeCallCost)
+	}
+}
+
+func TestSpeculativeRouting_Judge(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     string
+		wantModel string
+		wantCalls int
+		wantAux   float64
+	}{
+		{name: "accept", reply: "9", wantModel: "small", wantCalls: 2, wantAux: speculativeCallCost},
+		{name: "reject", reply: "Score: 3", wantModel: "big", wantCalls: 3, wantAux: 2 * speculativeCallCost},
+		{name: "unparseable escalates", reply: "great", wantModel: "big", wantCalls: 3, wantAux: 2 * speculativeCallCost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &speculativeServer{judgeReply: tt.reply}
+			client := newSpeculativeClient(t, srv, &JudgeVerifier{Model: "judge"})
+
+			resp, err := client.ChatCompletion(context.Background(), speculativeRequest())
+			if err != nil {
+				t.Fatalf("ChatCompletion() error = %v", err)
+			}
+			if resp.Model != tt.wantModel {
+				t.Fatalf("model = %q, want %q", resp.Model, tt.wantModel)
+			}
+			if calls := srv.calls(); len(calls) != tt.wantCalls {
+				t.Fatalf("upstream calls = %v, want %d", calls, tt.wantCalls)
+			}
+			if math.Abs(resp.Usage.AuxiliaryCost-tt.wantAux) > 1e-12 {
+				t.Fatalf("auxiliary cost = %v, want %v", resp.Usage.AuxiliaryCost, tt.wantAux)
+			}
+		})
+	}
+}
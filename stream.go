@@ -9,17 +9,38 @@ import (
 	"io"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode/utf8"
 
 	"github.com/blueberrycongee/llmux/internal/httputil"
 	"github.com/blueberrycongee/llmux/internal/plugin"
 	"github.com/blueberrycongee/llmux/internal/tokenizer"
+	"github.com/blueberrycongee/llmux/pkg/errors"
 	"github.com/blueberrycongee/llmux/pkg/provider"
 	"github.com/blueberrycongee/llmux/pkg/router"
 	"github.com/blueberrycongee/llmux/pkg/types"
 )
 
+const (
+	// streamLineBufSize is the initial scanner buffer. Most SSE lines fit, so
+	// buffers are pooled at this size and only longer lines grow a new one.
+	streamLineBufSize = 4096
+	// maxStreamLineSize bounds a single SSE line (bufio.Scanner defaults to 64K).
+	maxStreamLineSize = 256 * 1024
+)
+
+var (
+	sseDoneEvent = []byte("data: [DONE]")
+
+	streamLineBufPool = sync.Pool{
+		New: func() any {
+			buf := make([]byte, streamLineBufSize)
+			return &buf
+		},
+	}
+)
+
 // StreamReader provides an iterator interface for streaming responses.
 // It handles SSE parsing and provides a simple Recv() method for consuming chunks.
 //
@@ -44,14 +65,21 @@ import (
 type StreamReader struct {
 	body       io.ReadCloser
 	scanner    *bufio.Scanner
+	lineBuf    *[]byte // pooled initial scanner buffer
 	provider   provider.Provider
 	deployment *provider.Deployment
 	router     router.Router
 
-	closed     bool
-	firstChunk bool
-	startTime  time.Time
-	ttft       time.Duration // Time To First Token
+	closed       bool
+	firstChunk   bool
+	startTime    time.Time
+	ttft         time.Duration // Time To First Token of the stream
+	firstTokenAt time.Time
+
+	// The current upstream attempt, reported to the router. They differ from
+	// startTime and ttft after recovery to another deployment.
+	attemptStart time.Time
+	attemptTTFT  time.Duration
 
 	mu sync.Mutex
 
@@ -76,6 +104,15 @@ type StreamReader struct {
 	postHooksRun  bool
 
 	release func()
+
+	// onFinish receives the accumulated content when the stream completes.
+	onFinish func(content string)
+
+	// structured validates the output of json_schema requests.
+	structured *structuredStream
+
+	// pipeDecoded switches Pipe to decoded chunks once the stream recovered.
+	pipeDecoded bool
 }
 
 func (s *StreamReader) appendAccumulatedLocked(content string) {
@@ -84,6 +121,9 @@ func (s *StreamReader) appendAccumulatedLocked(content string) {
 	}
 
 	s.accumulatedRunes += utf8.RuneCountInString(content)
+	if s.structured != nil {
+		s.structured.content.WriteString(content)
+	}
 
 	maxBytes := 0
 	if s.client != nil {
@@ -107,11 +147,13 @@ func (s *StreamReader) appendAccumulatedLocked(content string) {
 	s.accumulated.WriteString(value)
 }
 
-// newStreamReader creates a new StreamReader.
+// newStreamReader creates a new StreamReader for an upstream request sent at
+// sentAt.
 func newStreamReader(
 	ctx context.Context,
 	client *Client,
 	req *types.ChatRequest,
+	sentAt time.Time,
 	body io.ReadCloser,
 	prov provider.Provider,
 	deployment *provider.Deployment,
@@ -121,19 +163,14 @@ func newStreamReader(
 	runFrom int,
 	release func(),
 ) *StreamReader {
-	scanner := bufio.NewScanner(body)
-	// Allow larger SSE lines (bufio.Scanner defaults to 64K, and old code used 16KB).
-	// Keep a small initial buffer to reduce allocations.
-	scanner.Buffer(make([]byte, 4096), 256*1024)
-
-	return &StreamReader{
-		body:            body,
-		scanner:         scanner,
+	body = client.withStreamIdleTimeout(body, deployment, req.Model)
+	s := &StreamReader{
 		provider:        prov,
 		deployment:      deployment,
 		router:          r,
 		firstChunk:      true,
-		startTime:       time.Now(),
+		startTime:       sentAt,
+		attemptStart:    sentAt,
 		ctx:             ctx,
 		client:          client,
 		originalReq:     req,
@@ -145,6 +182,10 @@ func newStreamReader(
 		streamRunFrom:   runFrom,
 		release:         release,
 	}
+	s.resetBody(body)
+	s.structured = client.newStructuredStream(req)
+	client.trackStream(s)
+	return s
 }
 
 func newStreamReaderFromChannel(
@@ -156,7 +197,7 @@ func newStreamReaderFromChannel(
 	pluginCtx *plugin.Context,
 	runFrom int,
 ) *StreamReader {
-	return &StreamReader{
+	s := &StreamReader{
 		ctx:             ctx,
 		client:          client,
 		originalReq:     req,
@@ -170,6 +211,9 @@ func newStreamReaderFromChannel(
 		pluginCtx:       pluginCtx,
 		streamRunFrom:   runFrom,
 	}
+	s.structured = client.newStructuredStream(req)
+	client.trackStream(s)
+	return s
 }
 
 // Recv returns the next chunk from the stream.
@@ -197,8 +241,7 @@ func (s *StreamReader) Recv() (*types.StreamChunk, error) {
 		}
 
 		// Check for stream end markers
-		if bytes.Equal(trimmed, []byte("data: [DONE]")) ||
-			bytes.Equal(trimmed, []byte("[DONE]")) {
+		if bytes.Equal(trimmed, sseDoneEvent) || bytes.Equal(trimmed, sseDoneMarker) {
 			s.seenDone = true
 			s.finish()
 			return nil, io.EOF
@@ -233,17 +276,17 @@ func (s *StreamReader) Recv() (*types.StreamChunk, error) {
 			continue
 		}
 
-		// Record Time To First Token on first content chunk
-		if s.firstChunk {
-			s.ttft = time.Since(s.startTime)
-			s.firstChunk = false
-		}
+		s.markTokenLocked()
 
 		// Accumulate content for recovery
 		if len(chunk.Choices) > 0 {
 			s.appendAccumulatedLocked(chunk.Choices[0].Delta.Content)
 		}
 
+		if chunk = s.applyStreamTransforms(chunk); chunk == nil {
+			continue
+		}
+		s.attributeUsageLocked(chunk.Usage)
 		return chunk, nil
 	}
 
@@ -309,19 +352,34 @@ func (s *StreamReader) recvFromPluginStreamLocked() (*types.StreamChunk, error)
 			continue
 		}
 
-		if s.firstChunk {
-			s.ttft = time.Since(s.startTime)
-			s.firstChunk = false
-		}
+		s.markTokenLocked()
 
 		if len(chunk.Choices) > 0 {
 			s.appendAccumulatedLocked(chunk.Choices[0].Delta.Content)
 		}
 
+		if chunk = s.applyStreamTransforms(chunk); chunk == nil {
+			continue
+		}
+		s.attributeUsageLocked(chunk.Usage)
 		return chunk, nil
 	}
 }
 
+// markTokenLocked records the time to first token of the stream and of the
+// current attempt on their first content chunk (must be called with lock held).
+func (s *StreamReader) markTokenLocked() {
+	now := time.Now()
+	if s.firstChunk {
+		s.firstTokenAt = now
+		s.ttft = now.Sub(s.startTime)
+		s.firstChunk = false
+	}
+	if s.attemptTTFT == 0 && !s.attemptStart.IsZero() {
+		s.attemptTTFT = now.Sub(s.attemptStart)
+	}
+}
+
 func (s *StreamReader) applyStreamPluginsLocked(chunk *types.StreamChunk) *types.StreamChunk {
 	if s.pipeline == nil || s.pluginCtx == nil || chunk == nil {
 		return chunk
@@ -331,6 +389,17 @@ func (s *StreamReader) applyStreamPluginsLocked(chunk *types.StreamChunk) *types
 	return out
 }
 
+// applyStreamTransforms runs the client's stream transforms in order. They see
+// chunks after plugins; recovery and accounting use the untransformed content.
+func (s *StreamReader) applyStreamTransforms(chunk *types.StreamChunk) *types.StreamChunk {
+	for _, transform := range s.client.config.StreamTransforms {
+		if chunk = transform(chunk); chunk == nil {
+			return nil
+		}
+	}
+	return chunk
+}
+
 func (s *StreamReader) finalizeStreamLocked(err error) {
 	if s.postHooksRun {
 		return
@@ -417,7 +486,7 @@ func (s *StreamReader) tryRecover(originalErr error) (*types.StreamChunk, error)
 	// but Pick() handles that logic (it might return the same node).
 	promptTokens := tokenizer.EstimatePromptTokens(newReq.Model, &newReq)
 	reqCtx := buildRouterRequestContext(&newReq, promptTokens, true)
-	deployment, err = s.client.router.PickWithContext(s.ctx, reqCtx)
+	deployment, err = s.client.pickDeployment(s.ctx, reqCtx)
 	if err != nil {
 		return nil, fmt.Errorf("recovery pick failed: %w", err)
 	}
@@ -431,7 +500,11 @@ func (s *StreamReader) tryRecover(originalErr error) (*types.StreamChunk, error)
 	}
 
 	// Build request
-	httpReq, err := prov.BuildRequest(s.ctx, sanitizeChatRequestForProvider(&newReq))
+	upstreamReq, err := s.client.limitMaxTokens(deployment, newReq.Model, &newReq)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := prov.BuildRequest(s.ctx, sanitizeChatRequestForProvider(upstreamReq))
 	if err != nil {
 		return nil, fmt.Errorf("recovery build request failed: %w", err)
 	}
@@ -440,6 +513,10 @@ func (s *StreamReader) tryRecover(originalErr error) (*types.StreamChunk, error)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.client.allowUpstream(deployment); err != nil {
+		release()
+		return nil, fmt.Errorf("recovery execute failed: %w", err)
+	}
 
 	if s.router != nil && deployment != nil {
 		s.router.ReportRequestStart(s.ctx, deployment)
@@ -447,10 +524,19 @@ func (s *StreamReader) tryRecover(originalErr error) (*types.StreamChunk, error)
 	s.mu.Lock()
 	s.requestEnded = false // New request started
 	s.release = release
+	s.attemptStart = time.Now()
+	s.attemptTTFT = 0
 	s.mu.Unlock()
 
-	resp, err := s.client.streamHTTPClient.Do(httpReq)
+	upstreamCtx, stopHeaderTimeout, cancelReq := s.client.withHeaderTimeout(s.ctx, deployment, s.originalReq.Model)
+	httpReq = httpReq.WithContext(upstreamCtx)
+
+	resp, err := s.client.httpClientFor(deployment.ProviderName).Do(httpReq)
+	stopHeaderTimeout()
+	s.client.reportCredential(deployment.ProviderName, httpReq, resp, err)
+	s.client.recordUpstream(s.ctx, deployment, err)
 	if err != nil {
+		cancelReq()
 		release()
 		if s.router != nil && deployment != nil {
 			s.router.ReportFailure(s.ctx, deployment, err)
@@ -462,6 +548,7 @@ func (s *StreamReader) tryRecover(originalErr error) (*types.StreamChunk, error)
 		s.mu.Unlock()
 		return nil, fmt.Errorf("recovery execute failed: %w", err)
 	}
+	resp.Body = cancelOnClose(resp.Body, cancelReq)
 
 	if resp.StatusCode >= 400 {
 		body, _ := httputil.ReadLimitedBody(resp.Body, httputil.DefaultMaxResponseBodyBytes)
@@ -480,10 +567,9 @@ func (s *StreamReader) tryRecover(originalErr error) (*types.StreamChunk, error)
 	}
 
 	// Update StreamReader state
+	body := s.client.withStreamIdleTimeout(resp.Body, deployment, s.originalReq.Model)
 	s.mu.Lock()
-	s.body = resp.Body
-	s.scanner = bufio.NewScanner(resp.Body)
-	s.scanner.Buffer(make([]byte, 4096), 256*1024)
+	s.resetBody(body)
 	s.provider = prov
 	s.deployment = deployment
 	if s.pluginCtx != nil {
@@ -508,14 +594,54 @@ func (s *StreamReader) Close() error {
 	return s.close()
 }
 
-// TTFT returns the Time To First Token duration.
-// Returns 0 if no chunks have been received yet.
+// TTFT returns the Time To First Token duration, measured from when the
+// upstream request was sent. Returns 0 if no chunks have been received yet.
 func (s *StreamReader) TTFT() time.Duration {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	return s.ttft
 }
 
+// StructuredOutput returns "valid" or "invalid" once a stream of a
+// json_schema request has completed and structured output validation is
+// enabled, and "" otherwise.
+func (s *StreamReader) StructuredOutput() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.structured == nil {
+		return ""
+	}
+	return s.structured.status
+}
+
+// FirstTokenTime returns when the first content chunk was received, or the
+// zero time if none has been received yet.
+func (s *StreamReader) FirstTokenTime() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.firstTokenAt
+}
+
+// attributeUsageLocked records the serving deployment and the request's
+// images on usage, as non-streaming responses carry them, so the stream is
+// priced like one.
+func (s *StreamReader) attributeUsageLocked(usage *types.Usage) {
+	if usage == nil {
+		return
+	}
+	if s.deployment != nil {
+		if usage.Provider == "" {
+			usage.Provider = s.deployment.ProviderName
+		}
+		if usage.Deployment == "" {
+			usage.Deployment = s.deployment.ID
+		}
+	}
+	if usage.Images == 0 && s.originalReq != nil {
+		usage.Images = s.originalReq.ImageCount()
+	}
+}
+
 // endRequest reports request end if not already reported (must be called with lock held).
 func (s *StreamReader) endRequest() {
 	if s.requestEnded {
@@ -531,6 +657,26 @@ func (s *StreamReader) endRequest() {
 	s.requestEnded = true
 }
 
+// resetBody starts reading SSE lines from body, reusing a pooled line buffer
+// (must be called with lock held).
+func (s *StreamReader) resetBody(body io.ReadCloser) {
+	s.releaseLineBuf()
+	s.lineBuf = streamLineBufPool.Get().(*[]byte)
+	s.body = body
+	s.scanner = bufio.NewScanner(body)
+	s.scanner.Buffer(*s.lineBuf, maxStreamLineSize)
+}
+
+// releaseLineBuf returns the line buffer to the pool once the scanner no
+// longer reads into it (must be called with lock held).
+func (s *StreamReader) releaseLineBuf() {
+	if s.lineBuf == nil {
+		return
+	}
+	streamLineBufPool.Put(s.lineBuf)
+	s.lineBuf = nil
+}
+
 // closeBody closes the body without reporting (must be called with lock held).
 func (s *StreamReader) closeBody() error {
 	if s.closed {
@@ -548,27 +694,38 @@ func (s *StreamReader) close() error {
 	if s.closed {
 		return nil
 	}
+	if s.client != nil {
+		s.client.untrackStream(s)
+	}
 	s.endRequest()
-	return s.closeBody()
+	err := s.closeBody()
+	s.releaseLineBuf()
+	return err
 }
 
 // finish reports success metrics and closes the stream.
 func (s *StreamReader) finish() {
 	if !s.closed {
 		if s.router != nil && s.deployment != nil {
-			latency := time.Since(s.startTime)
+			latency := time.Since(s.attemptStart)
 			promptTokens := tokenizer.EstimatePromptTokens(s.originalReq.Model, s.originalReq)
 			completionTokens := tokenizer.EstimateCompletionTokensFromText(s.originalReq.Model, s.accumulated.String())
 			s.router.ReportSuccess(s.ctx, s.deployment, &router.ResponseMetrics{
 				Latency:          latency,
-				TimeToFirstToken: s.ttft,
+				TimeToFirstToken: s.attemptTTFT,
 				InputTokens:      promptTokens,
 				OutputTokens:     completionTokens,
 				TotalTokens:      promptTokens + completionTokens,
 			})
 		}
+		if s.structured != nil {
+			s.structured.finish()
+		}
 		s.finalizeStreamLocked(nil)
 		_ = s.close()
+		if s.onFinish != nil {
+			s.onFinish(s.accumulated.String())
+		}
 	}
 }
 
@@ -587,3 +744,51 @@ func trimLeadingRunes(value string, count int) (string, int) {
 
 	return "", count - seen
 }
+
+// withStreamIdleTimeout aborts body when the upstream sends nothing for the
+// client's StreamIdleTimeout. Reads then fail with a retryable timeout error,
+// so the stream recovers like after a dropped connection.
+func (c *Client) withStreamIdleTimeout(body io.ReadCloser, deployment *provider.Deployment, model string) io.ReadCloser {
+	timeout := c.config.StreamIdleTimeout
+	if timeout <= 0 || body == nil {
+		return body
+	}
+	b := &idleTimeoutBody{
+		body:    body,
+		timeout: timeout,
+		err: errors.NewTimeoutError(deployment.ProviderName, model,
+			fmt.Sprintf("upstream stream idle for %s", timeout)),
+	}
+	b.timer = time.AfterFunc(timeout, func() {
+		b.timedOut.Store(true)
+		_ = b.body.Close()
+	})
+	b.timer.Stop()
+	return b
+}
+
+// idleTimeoutBody closes the wrapped body when a read blocks for longer than
+// timeout. Time spent between reads, while the consumer is busy, is not
+// counted.
+type idleTimeoutBody struct {
+	body     io.ReadCloser
+	timeout  time.Duration
+	timer    *time.Timer
+	timedOut atomic.Bool
+	err      error
+}
+
+func (b *idleTimeoutBody) Read(p []byte) (int, error) {
+	b.timer.Reset(b.timeout)
+	n, err := b.body.Read(p)
+	b.timer.Stop()
+	if b.timedOut.Load() {
+		return n, b.err
+	}
+	return n, err
+}
+
+func (b *idleTimeoutBody) Close() error {
+	b.timer.Stop()
+	return b.body.Close()
+}
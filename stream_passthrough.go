@@ -0,0 +1,170 @@
+package llmux
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/goccy/go-json"
+
+	"github.com/blueberrycongee/llmux/pkg/provider"
+	"github.com/blueberrycongee/llmux/pkg/types"
+)
+
+// PipeResult summarizes a stream forwarded with Pipe.
+type PipeResult struct {
+	// Content is the completion text of the first choice.
+	Content string
+	// Usage is the usage reported by the upstream, or nil.
+	Usage *types.Usage
+}
+
+// pipedChunk is the part of a stream chunk Pipe decodes for accounting.
+type pipedChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *types.Usage `json:"usage"`
+}
+
+var (
+	sseDataPrefix = []byte("data:")
+	sseDoneMarker = []byte("[DONE]")
+)
+
+// CanPipe reports whether Pipe can forward the upstream events of this stream
+// unchanged: the provider streams the unified chunk format and no plugin,
+// stream transform or structured output validation needs decoded chunks.
+func (s *StreamReader) CanPipe() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || s.scanner == nil || s.pluginStream != nil || s.pipeDecoded {
+		return false
+	}
+	if s.structured != nil || len(s.client.config.StreamTransforms) > 0 {
+		return false
+	}
+	if s.pipeline != nil && s.pluginCtx != nil && s.pipeline.HasStreamPlugins(s.pluginCtx) {
+		return false
+	}
+	p, ok := s.provider.(provider.StreamPassthrough)
+	return ok && p.StreamPassthrough()
+}
+
+// Pipe forwards the stream to write, one SSE "data:" payload per call, until
+// the stream ends. Upstream events are passed through as received instead of
+// being decoded and re-encoded; only the content and usage are scanned for
+// accounting. The payload is only valid during the call. If the upstream
+// fails and the stream recovers, the remaining chunks are re-encoded as with
+// Recv. Pipe returns nil at the end of the stream without writing [DONE], and
+// must not be mixed with Recv. Use it only when CanPipe reports true.
+func (s *StreamReader) Pipe(write func(data []byte) error) (PipeResult, error) {
+	var content strings.Builder
+	var result PipeResult
+	for {
+		data, delta, usage, err := s.nextPipedEvent()
+		if err == io.EOF {
+			result.Content = content.String()
+			return result, nil
+		}
+		if err != nil {
+			result.Content = content.String()
+			return result, err
+		}
+		content.WriteString(delta)
+		if usage != nil {
+			result.Usage = usage
+		}
+		if err := write(data); err != nil {
+			result.Content = content.String()
+			return result, err
+		}
+	}
+}
+
+// nextPipedEvent returns the payload of the next event with its content delta
+// and usage.
+func (s *StreamReader) nextPipedEvent() ([]byte, string, *types.Usage, error) {
+	s.mu.Lock()
+	if s.pipeDecoded {
+		s.mu.Unlock()
+		chunk, err := s.Recv()
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return encodePipedChunk(chunk)
+	}
+	if s.closed {
+		s.mu.Unlock()
+		return nil, "", nil, io.EOF
+	}
+
+	for s.scanner.Scan() {
+		payload, ok := bytes.CutPrefix(bytes.TrimSpace(s.scanner.Bytes()), sseDataPrefix)
+		if !ok {
+			// Comments, event names and blank lines between events.
+			continue
+		}
+		payload = bytes.TrimSpace(payload)
+		if len(payload) == 0 {
+			continue
+		}
+		if bytes.Equal(payload, sseDoneMarker) {
+			s.seenDone = true
+			s.finish()
+			s.mu.Unlock()
+			return nil, "", nil, io.EOF
+		}
+
+		var scanned pipedChunk
+		if err := json.Unmarshal(payload, &scanned); err != nil {
+			// Skip unparseable events like Recv does.
+			continue
+		}
+		var delta string
+		if len(scanned.Choices) > 0 {
+			delta = scanned.Choices[0].Delta.Content
+		}
+		s.markTokenLocked()
+		s.appendAccumulatedLocked(delta)
+		s.attributeUsageLocked(scanned.Usage)
+		s.mu.Unlock()
+		return payload, delta, scanned.Usage, nil
+	}
+
+	err := s.scanner.Err()
+	if err == nil {
+		err = io.ErrUnexpectedEOF
+	}
+	s.reportFailure(err)
+	// Events already sent cannot be replayed, so a recovered stream is
+	// decoded to skip the content the client has received.
+	s.pipeDecoded = true
+	for s.canRecover(err) {
+		s.mu.Unlock()
+		chunk, retryErr := s.tryRecover(err)
+		if retryErr == nil {
+			return encodePipedChunk(chunk)
+		}
+		s.mu.Lock()
+	}
+	s.finalizeStreamLocked(err)
+	_ = s.close()
+	s.mu.Unlock()
+	return nil, "", nil, err
+}
+
+func encodePipedChunk(chunk *types.StreamChunk) ([]byte, string, *types.Usage, error) {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	var delta string
+	if len(chunk.Choices) > 0 {
+		delta = chunk.Choices[0].Delta.Content
+	}
+	return data, delta, chunk.Usage, nil
+}
@@ -0,0 +1,136 @@
+package llmux
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/provider"
+)
+
+func newPassthroughTestClient(t *testing.T, baseURLs []string, opts ...Option) *Client {
+	t.Helper()
+	base := []Option{withTestPricing(t, "gpt-test"), WithRetry(3, 10*time.Millisecond)}
+	for i, url := range baseURLs {
+		base = append(base, WithProvider(ProviderConfig{
+			Name:                fmt.Sprintf("provider%d", i),
+			Type:                "openai",
+			Models:              []string{"gpt-test"},
+			APIKey:              "test-key",
+			BaseURL:             url,
+			AllowPrivateBaseURL: true,
+		}))
+	}
+	client, err := New(append(base, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func passthroughTestRequest() *ChatRequest {
+	return &ChatRequest{
+		Model:    "gpt-test",
+		Messages: []ChatMessage{{Role: "user", Content: json.RawMessage(`"hi"`)}},
+	}
+}
+
+func TestStreamReader_PipeForwardsUpstreamEvents(t *testing.T) {
+	events := []string{
+		`{"id":"c1","choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}}],"x_vendor":1}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{"content":"lo"}}]}`,
+		`{"id":"c1","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, ": keep-alive\n\n")
+		for _, event := range events {
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", event)
+		}
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := newPassthroughTestClient(t, []string{server.URL})
+	stream, err := client.ChatCompletionStream(context.Background(), passthroughTestRequest())
+	require.NoError(t, err)
+	defer stream.Close()
+	require.True(t, stream.CanPipe())
+
+	var got []string
+	result, err := stream.Pipe(func(data []byte) error {
+		got = append(got, string(data))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, events, got)
+	require.Equal(t, "Hello", result.Content)
+	require.NotNil(t, result.Usage)
+	require.Equal(t, 5, result.Usage.TotalTokens)
+	require.False(t, stream.FirstTokenTime().IsZero())
+}
+
+func TestStreamReader_CanPipeRequiresUndecodedStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := newPassthroughTestClient(t, []string{server.URL},
+		WithStreamTransform(func(chunk *StreamChunk) *StreamChunk { return chunk }))
+	stream, err := client.ChatCompletionStream(context.Background(), passthroughTestRequest())
+	require.NoError(t, err)
+	defer stream.Close()
+	require.False(t, stream.CanPipe())
+}
+
+func TestStreamReader_PipeRecoversWithDecodedChunks(t *testing.T) {
+	serverA := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, "data: %s\n\n", `{"choices":[{"delta":{"content":"Hello, "}}]}`)
+	}))
+	defer serverA.Close()
+	serverB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, "data: %s\n\n", `{"choices":[{"delta":{"content":"Hello, world!"}}]}`)
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer serverB.Close()
+
+	client := newPassthroughTestClient(t, []string{serverA.URL, serverB.URL},
+		WithFallback(true), WithStreamRecoveryMode(StreamRecoveryRetry))
+	trackingR := newTrackingRouter(client.router)
+	client.router = trackingR
+	var depA, depB *provider.Deployment
+	for _, d := range trackingR.GetDeployments("gpt-test") {
+		if d.ProviderName == "provider0" {
+			depA = d
+		} else {
+			depB = d
+		}
+	}
+	trackingR.pickDeployments = []*provider.Deployment{depA, depB}
+
+	stream, err := client.ChatCompletionStream(context.Background(), passthroughTestRequest())
+	require.NoError(t, err)
+	defer stream.Close()
+	require.True(t, stream.CanPipe())
+
+	var got []string
+	result, err := stream.Pipe(func(data []byte) error {
+		got = append(got, string(data))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, `{"choices":[{"delta":{"content":"Hello, "}}]}`, got[0])
+	require.True(t, strings.Contains(got[1], `"content":"world!"`), got[1])
+	require.Equal(t, "Hello, world!", result.Content)
+}
@@ -0,0 +1,142 @@
+package llmux
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+
+	"github.com/blueberrycongee/llmux/internal/jsonschema"
+)
+
+// Structured output statuses reported on ChatResponse.StructuredOutput and by
+// StreamReader.StructuredOutput.
+const (
+	StructuredOutputValid    = "valid"
+	StructuredOutputInvalid  = "invalid"
+	StructuredOutputRepaired = "repaired"
+)
+
+// StructuredOutputConfig configures validation of json_schema structured
+// outputs (see WithStructuredOutputValidation).
+type StructuredOutputConfig struct {
+	// Repair retries a non-streaming request once, with the validation
+	// problems appended to the conversation, when its output does not match
+	// the schema. Streams are only validated.
+	Repair bool
+}
+
+// WithStructuredOutputValidation validates the output of requests whose
+// response_format is json_schema against the schema. Validation never fails
+// a request; its outcome is reported on ChatResponse.StructuredOutput and
+// StreamReader.StructuredOutput.
+func WithStructuredOutputValidation(cfg StructuredOutputConfig) Option {
+	return func(c *ClientConfig) {
+		c.StructuredOutput = &cfg
+	}
+}
+
+// structuredOutputSchema returns the compiled schema req's output must match,
+// or nil when validation is disabled or does not apply.
+func (c *Client) structuredOutputSchema(req *ChatRequest) *jsonschema.Schema {
+	if c.config.StructuredOutput == nil || req.ResponseFormat == nil ||
+		req.ResponseFormat.Type != "json_schema" || req.ResponseFormat.JSONSchema == nil ||
+		len(req.ResponseFormat.JSONSchema.Schema) == 0 {
+		return nil
+	}
+	schema, err := jsonschema.Compile(req.ResponseFormat.JSONSchema.Schema)
+	if err != nil {
+		c.logger.Warn("skipping structured output validation", "model", req.Model, "error", err)
+		return nil
+	}
+	return schema
+}
+
+// validationProblems returns why content does not match schema, or nil.
+func validationProblems(schema *jsonschema.Schema, content string) []string {
+	err := schema.Validate([]byte(content))
+	if err == nil {
+		return nil
+	}
+	var verr *jsonschema.ValidationError
+	if stderrors.As(err, &verr) {
+		return verr.Problems
+	}
+	return []string{err.Error()}
+}
+
+// checkStructuredOutput validates resp against the schema of req and, when
+// repair is enabled, retries an invalid response once. The returned response
+// carries the validation status, and the cost of a discarded attempt in its
+// Usage.AuxiliaryCost.
+func (c *Client) checkStructuredOutput(ctx context.Context, req *ChatRequest, resp *ChatResponse) *ChatResponse {
+	if resp == nil || len(resp.Choices) == 0 {
+		return resp
+	}
+	schema := c.structuredOutputSchema(req)
+	if schema == nil {
+		return resp
+	}
+	content := resp.Choices[0].Message.TextContent()
+	problems := validationProblems(schema, content)
+	if problems == nil {
+		resp.StructuredOutput = StructuredOutputValid
+		return resp
+	}
+	resp.StructuredOutput = StructuredOutputInvalid
+	c.logger.Warn("structured output does not match schema", "model", req.Model, "problems", problems)
+	if !c.config.StructuredOutput.Repair || resp.CacheStatus == CacheStatusHit || IsSandbox(ctx) {
+		return resp
+	}
+
+	repairReq := *req
+	repairReq.Messages = append(append([]ChatMessage(nil), req.Messages...),
+		ChatMessage{Role: "assistant", Content: jsonString(content)},
+		ChatMessage{Role: "user", Content: jsonString(repairPrompt(problems))},
+	)
+	repaired, err := c.routeAndExecute(ctx, &repairReq, 0)
+	if err != nil {
+		c.logger.Warn("structured output repair failed", "model", req.Model, "error", err)
+		return resp
+	}
+	if len(repaired.Choices) == 0 || validationProblems(schema, repaired.Choices[0].Message.TextContent()) != nil {
+		c.logger.Warn("repaired structured output does not match schema", "model", req.Model)
+		addAuxiliaryCost(resp, c.responseCost(req.Model, repaired))
+		return resp
+	}
+	addAuxiliaryCost(repaired, c.responseCost(req.Model, resp))
+	repaired.StructuredOutput = StructuredOutputRepaired
+	return repaired
+}
+
+func repairPrompt(problems []string) string {
+	return "Your previous reply does not match the required JSON schema:\n- " +
+		strings.Join(problems, "\n- ") +
+		"\nReply again with only the corrected JSON document."
+}
+
+// structuredStream collects the content of a stream whose output must match
+// a schema.
+type structuredStream struct {
+	schema  *jsonschema.Schema
+	content strings.Builder
+	status  string
+}
+
+// finish validates the collected content once.
+func (s *structuredStream) finish() {
+	if s.status != "" {
+		return
+	}
+	s.status = StructuredOutputValid
+	if validationProblems(s.schema, s.content.String()) != nil {
+		s.status = StructuredOutputInvalid
+	}
+}
+
+func (c *Client) newStructuredStream(req *ChatRequest) *structuredStream {
+	schema := c.structuredOutputSchema(req)
+	if schema == nil {
+		return nil
+	}
+	return &structuredStream{schema: schema}
+}
@@ -0,0 +1,202 @@
+package llmux
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+const structuredTestSchema = `{"type":"object","properties":{"city":{"type":"string"}},"required":["city"],"additionalProperties":false}`
+
+func structuredRequest() *ChatRequest {
+	return &ChatRequest{
+		Model:    "m",
+		Messages: []ChatMessage{{Role: "user", Content: jsonString("where?")}},
+		ResponseFormat: &ResponseFormat{
+			Type:       "json_schema",
+			JSONSchema: &JSONSchema{Name: "place", Schema: json.RawMessage(structuredTestSchema)},
+		},
+	}
+}
+
+// newStructuredClient serves the given contents in order, one per call, and
+// records the requests it received.
+func newStructuredClient(t *testing.T, cfg StructuredOutputConfig, contents ...string) (*Client, *[]ChatRequest) {
+	t.Helper()
+	var (
+		calls    atomic.Int32
+		requests []ChatRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+		content := contents[min(int(calls.Add(1))-1, len(contents)-1)]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			ID:      "resp",
+			Object:  "chat.completion",
+			Model:   "m",
+			Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: jsonString(content)}, FinishReason: "stop"}},
+			Usage:   &Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"m"}, baseURL: server.URL}, []string{"m"}),
+		withTestPricing(t, "m"),
+		WithRetry(0, 0),
+		WithStructuredOutputValidation(cfg),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client, &requests
+}
+
+func TestStructuredOutput_Valid(t *testing.T) {
+	client, requests := newStructuredClient(t, StructuredOutputConfig{Repair: true}, `{"city":"Paris"}`)
+
+	resp, err := client.ChatCompletion(context.Background(), structuredRequest())
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.StructuredOutput != StructuredOutputValid {
+		t.Fatalf("StructuredOutput = %q, want valid", resp.StructuredOutput)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("upstream calls = %d, want 1", len(*requests))
+	}
+	if (*requests)[0].ResponseFormat.JSONSchema == nil {
+		t.Fatal("expected the json_schema to be forwarded upstream")
+	}
+}
+
+func TestStructuredOutput_Invalid(t *testing.T) {
+	client, requests := newStructuredClient(t, StructuredOutputConfig{}, `{"town":"Paris"}`)
+
+	resp, err := client.ChatCompletion(context.Background(), structuredRequest())
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.StructuredOutput != StructuredOutputInvalid {
+		t.Fatalf("StructuredOutput = %q, want invalid", resp.StructuredOutput)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("upstream calls = %d, want 1 without repair", len(*requests))
+	}
+}
+
+func TestStructuredOutput_Repair(t *testing.T) {
+	client, requests := newStructuredClient(t, StructuredOutputConfig{Repair: true}, "Sure! Paris.", `{"city":"Paris"}`)
+
+	resp, err := client.ChatCompletion(context.Background(), structuredRequest())
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.StructuredOutput != StructuredOutputRepaired {
+		t.Fatalf("StructuredOutput = %q, want repaired", resp.StructuredOutput)
+	}
+	if got := resp.Choices[0].Message.TextContent(); got != `{"city":"Paris"}` {
+		t.Fatalf("content = %q, want the repaired output", got)
+	}
+	if resp.Usage == nil || resp.Usage.AuxiliaryCost <= 0 {
+		t.Fatalf("expected the discarded attempt in AuxiliaryCost, got %+v", resp.Usage)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("upstream calls = %d, want 2", len(*requests))
+	}
+	repair := (*requests)[1].Messages
+	if len(repair) != 3 || repair[1].Role != "assistant" || repair[1].TextContent() != "Sure! Paris." {
+		t.Fatalf("expected the invalid output to be replayed, got %+v", repair)
+	}
+	if !strings.Contains(repair[2].TextContent(), "invalid JSON") {
+		t.Fatalf("expected the repair prompt to list problems, got %q", repair[2].TextContent())
+	}
+}
+
+func TestStructuredOutput_RepairStillInvalid(t *testing.T) {
+	client, requests := newStructuredClient(t, StructuredOutputConfig{Repair: true}, `{"town":"Paris"}`)
+
+	resp, err := client.ChatCompletion(context.Background(), structuredRequest())
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.StructuredOutput != StructuredOutputInvalid {
+		t.Fatalf("StructuredOutput = %q, want invalid", resp.StructuredOutput)
+	}
+	if len(*requests) != 2 {
+		t.Fatalf("upstream calls = %d, want one repair attempt", len(*requests))
+	}
+}
+
+// jsonStreamProvider parses OpenAI-style stream chunks.
+type jsonStreamProvider struct {
+	*httpMockProvider
+}
+
+func (m *jsonStreamProvider) ParseStreamChunk(data []byte) (*StreamChunk, error) {
+	data = bytes.TrimPrefix(data, []byte("data: "))
+	var chunk StreamChunk
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return nil, err
+	}
+	return &chunk, nil
+}
+
+func TestStructuredOutput_Stream(t *testing.T) {
+	for _, tt := range []struct {
+		parts []string
+		want  string
+	}{
+		{parts: []string{`{"ci`, `ty":"Pa`, `ris"}`}, want: StructuredOutputValid},
+		{parts: []string{`{"city":`, `1}`}, want: StructuredOutputInvalid},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, part := range tt.parts {
+				data, _ := json.Marshal(StreamChunk{Choices: []StreamChoice{{Delta: StreamDelta{Content: part}}}})
+				_, _ = w.Write([]byte("data: " + string(data) + "\n\n"))
+			}
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+
+		mock := &jsonStreamProvider{&httpMockProvider{name: "mock", models: []string{"m"}, baseURL: server.URL}}
+		client, err := New(
+			WithProviderInstance("mock", mock, []string{"m"}),
+			withTestPricing(t, "m"),
+			WithStructuredOutputValidation(StructuredOutputConfig{}),
+		)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+
+		stream, err := client.ChatCompletionStream(context.Background(), structuredRequest())
+		if err != nil {
+			t.Fatalf("ChatCompletionStream() error = %v", err)
+		}
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Recv() error = %v", err)
+			}
+		}
+		if got := stream.StructuredOutput(); got != tt.want {
+			t.Fatalf("StructuredOutput() = %q, want %q for %q", got, tt.want, tt.parts)
+		}
+		_ = stream.Close()
+		_ = client.Close()
+		server.Close()
+	}
+}
@@ -245,7 +245,7 @@ func NewTestServer(opts ...ServerOption) (*TestServer, error) {
 	// Setup routes
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /health/live", handler.HealthCheck)
-	mux.HandleFunc("GET /health/ready", handler.HealthCheck)
+	mux.HandleFunc("GET /health/ready", handler.ReadinessCheck)
 	mux.HandleFunc("POST /v1/chat/completions", handler.ChatCompletions)
 	mux.HandleFunc("POST /v1/completions", handler.Completions)
 	mux.HandleFunc("POST /v1/embeddings", handler.Embeddings)
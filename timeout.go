@@ -0,0 +1,111 @@
+package llmux
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/pkg/provider"
+)
+
+// TimeoutMetadataKey is the API key metadata field that overrides the
+// upstream request timeout, as a duration ("10m") or a number of seconds.
+const TimeoutMetadataKey = "timeout"
+
+type timeoutContextKey struct{}
+
+// WithRequestTimeout overrides the upstream request timeout for requests made
+// with ctx. It takes precedence over every other timeout setting.
+func WithRequestTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutContextKey{}, d)
+}
+
+// requestTimeout resolves the upstream timeout for a request to deployment,
+// from the most specific setting to the least: the context, the API key's
+// metadata, the model group, the deployment (its provider's Timeout), then
+// the client-wide timeout. Zero means no timeout.
+func (c *Client) requestTimeout(ctx context.Context, deployment *provider.Deployment, model string) time.Duration {
+	if d, ok := ctx.Value(timeoutContextKey{}).(time.Duration); ok && d > 0 {
+		return d
+	}
+	if authCtx := auth.GetAuthContext(ctx); authCtx != nil && authCtx.APIKey != nil {
+		if d, ok := parseTimeoutMetadata(authCtx.APIKey.Metadata[TimeoutMetadataKey]); ok {
+			return d
+		}
+	}
+	if d := c.config.ModelTimeouts[model]; d > 0 {
+		return d
+	}
+	if deployment != nil {
+		if d := c.config.ModelTimeouts[deployment.ModelName]; d > 0 {
+			return d
+		}
+		if deployment.Timeout > 0 {
+			return time.Duration(deployment.Timeout) * time.Second
+		}
+	}
+	return c.config.Timeout
+}
+
+func parseTimeoutMetadata(raw any) (time.Duration, bool) {
+	var d time.Duration
+	switch v := raw.(type) {
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			seconds, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return 0, false
+			}
+			parsed = time.Duration(seconds * float64(time.Second))
+		}
+		d = parsed
+	case float64:
+		d = time.Duration(v * float64(time.Second))
+	case int:
+		d = time.Duration(v) * time.Second
+	default:
+		return 0, false
+	}
+	return d, d > 0
+}
+
+// withUpstreamTimeout bounds a whole non-streaming upstream request,
+// including reading the response body.
+func (c *Client) withUpstreamTimeout(ctx context.Context, deployment *provider.Deployment, model string) (context.Context, context.CancelFunc) {
+	if d := c.requestTimeout(ctx, deployment, model); d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
+
+// withHeaderTimeout bounds only the wait for a stream's response headers, so
+// long-running streams are not killed mid-flight. Call stop once headers have
+// arrived; cancel ends the request and is tied to the body by cancelOnClose.
+func (c *Client) withHeaderTimeout(ctx context.Context, deployment *provider.Deployment, model string) (reqCtx context.Context, stop func(), cancel context.CancelFunc) {
+	reqCtx, cancel = context.WithCancel(ctx)
+	d := c.requestTimeout(ctx, deployment, model)
+	if d <= 0 {
+		return reqCtx, func() {}, cancel
+	}
+	timer := time.AfterFunc(d, cancel)
+	return reqCtx, func() { timer.Stop() }, cancel
+}
+
+// cancelOnClose ends the request context when the body is closed.
+func cancelOnClose(body io.ReadCloser, cancel context.CancelFunc) io.ReadCloser {
+	return &cancelOnCloseBody{ReadCloser: body, cancel: cancel}
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
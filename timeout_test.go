@@ -0,0 +1,74 @@
+package llmux
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/internal/auth"
+	"github.com/blueberrycongee/llmux/pkg/provider"
+)
+
+func TestRequestTimeout_Hierarchy(t *testing.T) {
+	client, err := New(
+		WithTimeout(30*time.Second),
+		WithModelTimeout("o1", 10*time.Minute),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	plain := &provider.Deployment{ModelName: "gpt-4o"}
+	slowProvider := &provider.Deployment{ModelName: "gpt-4o", Timeout: 90}
+	reasoning := &provider.Deployment{ModelName: "o1", Timeout: 90}
+
+	require.Equal(t, 30*time.Second, client.requestTimeout(ctx, plain, "gpt-4o"))
+	require.Equal(t, 90*time.Second, client.requestTimeout(ctx, slowProvider, "gpt-4o"))
+	require.Equal(t, 10*time.Minute, client.requestTimeout(ctx, reasoning, "o1"))
+	require.Equal(t, 10*time.Minute, client.requestTimeout(ctx, reasoning, "openai/o1-alias"))
+
+	keyCtx := auth.WithAuthContext(ctx, &auth.AuthContext{
+		APIKey: &auth.APIKey{Metadata: map[string]any{TimeoutMetadataKey: "20m"}},
+	})
+	require.Equal(t, 20*time.Minute, client.requestTimeout(keyCtx, reasoning, "o1"))
+
+	keyCtx = auth.WithAuthContext(ctx, &auth.AuthContext{
+		APIKey: &auth.APIKey{Metadata: map[string]any{TimeoutMetadataKey: float64(45)}},
+	})
+	require.Equal(t, 45*time.Second, client.requestTimeout(keyCtx, plain, "gpt-4o"))
+
+	require.Equal(t, time.Second, client.requestTimeout(WithRequestTimeout(keyCtx, time.Second), reasoning, "o1"))
+}
+
+func TestClient_ModelTimeoutExtendsClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(150 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","model":"slow-model","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	newClient := func(opts ...Option) *Client {
+		base := []Option{
+			WithProviderInstance("primary", &httpMockProvider{name: "primary", models: []string{"slow-model"}, baseURL: server.URL}, []string{"slow-model"}),
+			withTestPricing(t, "slow-model"),
+			WithRetry(0, 0),
+			WithTimeout(50 * time.Millisecond),
+		}
+		client, err := New(append(base, opts...)...)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = client.Close() })
+		return client
+	}
+	req := &ChatRequest{Model: "slow-model", Messages: []ChatMessage{{Role: "user", Content: jsonString("hi")}}}
+
+	_, err := newClient().ChatCompletion(context.Background(), req)
+	require.Error(t, err)
+
+	_, err = newClient(WithModelTimeout("slow-model", time.Second)).ChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+}
@@ -0,0 +1,173 @@
+package llmux
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/goccy/go-json"
+	"github.com/invopop/jsonschema"
+)
+
+// DefaultMaxToolIterations bounds the model calls of Tools.Run when
+// NewTools is given no limit.
+const DefaultMaxToolIterations = 10
+
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Tools is a set of Go functions exposed to models as tools. It runs the
+// tool-calling loop in process: the model is called, requested tools are
+// executed, their results appended to the conversation, and the model is
+// called again until it answers without tool calls.
+//
+// Example:
+//
+//	type WeatherArgs struct {
+//	    City string `json:"city" jsonschema:"description=City name"`
+//	}
+//	tools := llmux.NewTools(0)
+//	err := llmux.AddTool(tools, "get_weather", "Current weather for a city",
+//	    func(ctx context.Context, args WeatherArgs) (string, error) {
+//	        return "Sunny in " + args.City, nil
+//	    })
+//	resp, err := tools.Run(ctx, client, req)
+type Tools struct {
+	maxIterations int
+	tools         map[string]*goTool
+	order         []string
+}
+
+// goTool is a registered function with its tool definition.
+type goTool struct {
+	def  Tool
+	call func(ctx context.Context, args []byte) (string, error)
+}
+
+// NewTools creates an empty tool set whose Run calls the model at most
+// maxIterations times (DefaultMaxToolIterations if not positive).
+func NewTools(maxIterations int) *Tools {
+	if maxIterations <= 0 {
+		maxIterations = DefaultMaxToolIterations
+	}
+	return &Tools{maxIterations: maxIterations, tools: make(map[string]*goTool)}
+}
+
+// AddTool registers fn as a tool. Args must be a struct; its JSON schema,
+// derived by reflection from its fields and their json and jsonschema tags,
+// describes the tool's parameters. Fields without omitempty are required.
+// The result is sent to the model as is when it is a string and as JSON
+// otherwise.
+func AddTool[Args, Result any](t *Tools, name, description string, fn func(context.Context, Args) (Result, error)) error {
+	if !toolNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid tool name %q", name)
+	}
+	if fn == nil {
+		return fmt.Errorf("tool %q has no function", name)
+	}
+	if _, exists := t.tools[name]; exists {
+		return fmt.Errorf("tool %q already registered", name)
+	}
+	argsType := reflect.TypeFor[Args]()
+	if argsType.Kind() == reflect.Pointer {
+		argsType = argsType.Elem()
+	}
+	if argsType.Kind() != reflect.Struct {
+		return fmt.Errorf("tool %q: arguments must be a struct, got %s", name, argsType)
+	}
+
+	reflector := &jsonschema.Reflector{DoNotReference: true, ExpandedStruct: true, Anonymous: true}
+	schema := reflector.ReflectFromType(argsType)
+	schema.Version = ""
+	parameters, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("tool %q: %w", name, err)
+	}
+
+	t.tools[name] = &goTool{
+		def: Tool{
+			Type:     "function",
+			Function: ToolFunction{Name: name, Description: description, Parameters: parameters},
+		},
+		call: func(ctx context.Context, raw []byte) (string, error) {
+			var args Args
+			if len(raw) > 0 {
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return "", fmt.Errorf("invalid arguments: %w", err)
+				}
+			}
+			result, err := fn(ctx, args)
+			if err != nil {
+				return "", err
+			}
+			if s, ok := any(result).(string); ok {
+				return s, nil
+			}
+			data, err := json.Marshal(result)
+			if err != nil {
+				return "", fmt.Errorf("encode result: %w", err)
+			}
+			return string(data), nil
+		},
+	}
+	t.order = append(t.order, name)
+	return nil
+}
+
+// Definitions returns the tool definitions in registration order.
+func (t *Tools) Definitions() []Tool {
+	defs := make([]Tool, 0, len(t.order))
+	for _, name := range t.order {
+		defs = append(defs, t.tools[name].def)
+	}
+	return defs
+}
+
+// Call executes one tool call and returns the content of its tool message.
+// Unknown tools and tool errors are reported to the model as text rather
+// than failing the loop, so it can correct itself.
+func (t *Tools) Call(ctx context.Context, call ToolCall) string {
+	tool, ok := t.tools[call.Function.Name]
+	if !ok {
+		return fmt.Sprintf("error: unknown tool %q", call.Function.Name)
+	}
+	result, err := tool.call(ctx, []byte(call.Function.Arguments))
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return result
+}
+
+// Run sends req with the registered tools added and executes the tool calls
+// of each response until the model answers without calling tools. It returns
+// that answer, or an error once the model has been called maxIterations
+// times. req is not modified.
+func (t *Tools) Run(ctx context.Context, client *Client, req *ChatRequest) (*ChatResponse, error) {
+	conv := *req
+	conv.Messages = append([]ChatMessage(nil), req.Messages...)
+	conv.Tools = append(append([]Tool(nil), req.Tools...), t.Definitions()...)
+
+	for iteration := 0; iteration < t.maxIterations; iteration++ {
+		resp, err := client.ChatCompletion(ctx, &conv)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Choices) == 0 || len(resp.Choices[0].Message.ToolCalls) == 0 {
+			return resp, nil
+		}
+
+		msg := resp.Choices[0].Message
+		conv.Messages = append(conv.Messages, msg)
+		for _, call := range msg.ToolCalls {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			conv.Messages = append(conv.Messages, ChatMessage{
+				Role:       "tool",
+				Content:    jsonString(t.Call(ctx, call)),
+				ToolCallID: call.ID,
+			})
+		}
+	}
+	return nil, fmt.Errorf("exceeded maximum tool iterations (%d)", t.maxIterations)
+}
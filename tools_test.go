@@ -0,0 +1,186 @@
+package llmux
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+type weatherArgs struct {
+	City  string `json:"city" jsonschema:"description=City name"`
+	Units string `json:"units,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+}
+
+type weatherResult struct {
+	City string  `json:"city"`
+	Temp float64 `json:"temp"`
+}
+
+func TestAddTool_Schema(t *testing.T) {
+	tools := NewTools(0)
+	err := AddTool(tools, "get_weather", "Current weather", func(ctx context.Context, args weatherArgs) (weatherResult, error) {
+		return weatherResult{}, nil
+	})
+	if err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+
+	defs := tools.Definitions()
+	if len(defs) != 1 || defs[0].Function.Name != "get_weather" || defs[0].Type != "function" {
+		t.Fatalf("unexpected definitions %+v", defs)
+	}
+	var schema struct {
+		Type       string                    `json:"type"`
+		Properties map[string]map[string]any `json:"properties"`
+		Required   []string                  `json:"required"`
+	}
+	if err := json.Unmarshal(defs[0].Function.Parameters, &schema); err != nil {
+		t.Fatalf("parameters are not JSON: %v", err)
+	}
+	if schema.Type != "object" || schema.Properties["city"]["type"] != "string" {
+		t.Fatalf("unexpected schema %s", defs[0].Function.Parameters)
+	}
+	if schema.Properties["city"]["description"] != "City name" {
+		t.Fatalf("expected the jsonschema tag description, got %s", defs[0].Function.Parameters)
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "city" {
+		t.Fatalf("required = %v, want [city]", schema.Required)
+	}
+}
+
+func TestAddTool_Rejects(t *testing.T) {
+	tools := NewTools(0)
+	noop := func(ctx context.Context, args weatherArgs) (string, error) { return "", nil }
+	if err := AddTool(tools, "bad name", "", noop); err == nil {
+		t.Fatal("expected an invalid name to be rejected")
+	}
+	if err := AddTool(tools, "scalar", "", func(ctx context.Context, n int) (string, error) { return "", nil }); err == nil {
+		t.Fatal("expected non-struct arguments to be rejected")
+	}
+	if err := AddTool(tools, "dup", "", noop); err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+	if err := AddTool(tools, "dup", "", noop); err == nil {
+		t.Fatal("expected a duplicate name to be rejected")
+	}
+}
+
+func TestTools_Call(t *testing.T) {
+	tools := NewTools(0)
+	_ = AddTool(tools, "get_weather", "", func(ctx context.Context, args weatherArgs) (weatherResult, error) {
+		if args.City == "" {
+			return weatherResult{}, errors.New("city is required")
+		}
+		return weatherResult{City: args.City, Temp: 21.5}, nil
+	})
+
+	call := func(name, args string) string {
+		return tools.Call(context.Background(), ToolCall{ID: "1", Type: "function", Function: ToolCallFunction{Name: name, Arguments: args}})
+	}
+	if got := call("get_weather", `{"city":"Oslo"}`); got != `{"city":"Oslo","temp":21.5}` {
+		t.Fatalf("result = %s", got)
+	}
+	if got := call("get_weather", `{}`); got != "error: city is required" {
+		t.Fatalf("tool error = %q", got)
+	}
+	if got := call("get_weather", `not json`); !strings.HasPrefix(got, "error: invalid arguments") {
+		t.Fatalf("bad arguments = %q", got)
+	}
+	if got := call("missing", `{}`); got != `error: unknown tool "missing"` {
+		t.Fatalf("unknown tool = %q", got)
+	}
+}
+
+// newToolLoopClient answers with a get_weather call until a tool message is
+// present, or forever when always is set.
+func newToolLoopClient(t *testing.T, always bool) (*Client, *[]ChatRequest) {
+	t.Helper()
+	var requests []ChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+
+		msg := ChatMessage{Role: "assistant"}
+		finish := "tool_calls"
+		last := req.Messages[len(req.Messages)-1]
+		if last.Role == "tool" && !always {
+			msg.Content = jsonString("It is " + last.TextContent())
+			finish = "stop"
+		} else {
+			msg.Content = json.RawMessage("null")
+			msg.ToolCalls = []ToolCall{{ID: "call_1", Type: "function", Function: ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Oslo"}`}}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			ID:      "resp",
+			Model:   "m",
+			Choices: []Choice{{Message: msg, FinishReason: finish}},
+			Usage:   &Usage{PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"m"}, baseURL: server.URL}, []string{"m"}),
+		withTestPricing(t, "m"),
+		WithRetry(0, 0),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client, &requests
+}
+
+func TestTools_Run(t *testing.T) {
+	client, requests := newToolLoopClient(t, false)
+	tools := NewTools(0)
+	_ = AddTool(tools, "get_weather", "", func(ctx context.Context, args weatherArgs) (string, error) {
+		return "sunny in " + args.City, nil
+	})
+
+	req := &ChatRequest{Model: "m", Messages: []ChatMessage{{Role: "user", Content: jsonString("weather?")}}}
+	resp, err := tools.Run(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := resp.Choices[0].Message.TextContent(); got != "It is sunny in Oslo" {
+		t.Fatalf("answer = %q", got)
+	}
+	if len(req.Messages) != 1 || len(req.Tools) != 0 {
+		t.Fatal("expected Run to leave the request unmodified")
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("model calls = %d, want 2", len(*requests))
+	}
+	first, second := (*requests)[0], (*requests)[1]
+	if len(first.Tools) != 1 || first.Tools[0].Function.Name != "get_weather" {
+		t.Fatalf("expected the tool to be offered, got %+v", first.Tools)
+	}
+	if len(second.Messages) != 3 || second.Messages[1].Role != "assistant" || second.Messages[2].ToolCallID != "call_1" {
+		t.Fatalf("unexpected conversation %+v", second.Messages)
+	}
+}
+
+func TestTools_RunMaxIterations(t *testing.T) {
+	client, requests := newToolLoopClient(t, true)
+	tools := NewTools(3)
+	_ = AddTool(tools, "get_weather", "", func(ctx context.Context, args weatherArgs) (string, error) {
+		return "sunny", nil
+	})
+
+	req := &ChatRequest{Model: "m", Messages: []ChatMessage{{Role: "user", Content: jsonString("weather?")}}}
+	if _, err := tools.Run(context.Background(), client, req); err == nil || !strings.Contains(err.Error(), "maximum tool iterations") {
+		t.Fatalf("Run() error = %v, want max iterations error", err)
+	}
+	if len(*requests) != 3 {
+		t.Fatalf("model calls = %d, want 3", len(*requests))
+	}
+}
@@ -0,0 +1,115 @@
+package llmux
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/blueberrycongee/llmux/pkg/provider"
+)
+
+// newHTTPClient wraps transport in an upstream client. It has no overall
+// timeout: each request carries its own deadline (see requestTimeout).
+func newHTTPClient(transport *http.Transport) *http.Client {
+	return &http.Client{Transport: traceContextTransport{base: transport}}
+}
+
+// Connection pool defaults. Upstream traffic goes to a few hosts, so most
+// idle connections are kept per host.
+const (
+	defaultMaxIdleConns        = 1000
+	defaultMaxIdleConnsPerHost = 100
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultDialTimeout         = 30 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+)
+
+// newUpstreamTransport returns the connection-pooling transport used for
+// upstream requests, tuned by cfg.
+func newUpstreamTransport(cfg provider.TransportConfig) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   valueOr(cfg.DialTimeout, defaultDialTimeout),
+		KeepAlive: valueOr(cfg.KeepAlive, defaultKeepAlive),
+	}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          valueOr(cfg.MaxIdleConns, defaultMaxIdleConns),
+		MaxIdleConnsPerHost:   valueOr(cfg.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost),
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       valueOr(cfg.IdleConnTimeout, defaultIdleConnTimeout),
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		ForceAttemptHTTP2:     !cfg.DisableHTTP2,
+	}
+	if cfg.DisableHTTP2 {
+		// A non-nil empty map turns off the automatic HTTP/2 upgrade.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
+
+// valueOr returns v, or def when v is zero.
+func valueOr[T int | time.Duration](v, def T) T {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+// newProviderTransport builds a provider's own transport, applying its
+// pool, proxy and TLS settings, so providers do not share connection pools.
+func newProviderTransport(cfg provider.Config) (*http.Transport, error) {
+	transport := newUpstreamTransport(cfg.Transport)
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", cfg.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	if cfg.CAFile != "" || cfg.InsecureSkipVerify {
+		tlsConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			// #nosec G402 -- opt-in per provider for self-signed test endpoints.
+			InsecureSkipVerify: cfg.InsecureSkipVerify,
+		}
+		if cfg.CAFile != "" {
+			// #nosec G304 -- path is operator-configured.
+			data, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("read CA bundle: %w", err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil || pool == nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", cfg.CAFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport, nil
+}
+
+// httpClientFor returns the client for requests to providerName.
+// Providers added as instances share the client-wide client.
+func (c *Client) httpClientFor(providerName string) *http.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if client, ok := c.providerHTTP[providerName]; ok {
+		return client
+	}
+	return c.defaultHTTP
+}
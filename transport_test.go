@@ -0,0 +1,98 @@
+package llmux
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blueberrycongee/llmux/pkg/provider"
+)
+
+func TestNewProviderTransport_CAFileAndSkipVerify(t *testing.T) {
+	upstream := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer upstream.Close()
+
+	get := func(cfg provider.Config) error {
+		transport, err := newProviderTransport(cfg)
+		require.NoError(t, err)
+		resp, err := (&http.Client{Transport: transport}).Get(upstream.URL)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	require.Error(t, get(provider.Config{}), "self-signed upstream must not be trusted by default")
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: upstream.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, certPEM, 0o600))
+	require.NoError(t, get(provider.Config{CAFile: caFile}))
+	require.NoError(t, get(provider.Config{InsecureSkipVerify: true}))
+
+	_, err := newProviderTransport(provider.Config{CAFile: filepath.Join(t.TempDir(), "missing.pem")})
+	require.Error(t, err)
+}
+
+func TestNewProviderTransport_Proxy(t *testing.T) {
+	transport, err := newProviderTransport(provider.Config{ProxyURL: "http://proxy.internal:3128"})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "https://api.openai.com/v1/chat/completions", nil)
+	proxyURL, err := transport.Proxy(req)
+	require.NoError(t, err)
+	require.Equal(t, "proxy.internal:3128", proxyURL.Host)
+
+	direct, err := newProviderTransport(provider.Config{})
+	require.NoError(t, err)
+	require.Nil(t, direct.Proxy)
+
+	_, err = newProviderTransport(provider.Config{ProxyURL: "ftp://proxy.internal"})
+	require.Error(t, err)
+}
+
+func TestClient_SeparateTransportPerProvider(t *testing.T) {
+	client, err := New(
+		WithProvider(ProviderConfig{Name: "a", Type: "openai", APIKey: "k", Models: []string{"gpt-4o"}}),
+		WithProvider(ProviderConfig{Name: "b", Type: "openai", APIKey: "k", Models: []string{"gpt-4o-mini"}, ProxyURL: "http://proxy.internal:3128"}),
+		withTestPricing(t, "gpt-4o", "gpt-4o-mini"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	a, b := client.httpClientFor("a"), client.httpClientFor("b")
+	require.NotSame(t, a, b)
+	require.NotSame(t, client.defaultHTTP, a)
+	require.NotSame(t, a.Transport.(traceContextTransport).base, b.Transport.(traceContextTransport).base)
+
+	require.NoError(t, client.RemoveProvider("b"))
+	require.Same(t, client.defaultHTTP, client.httpClientFor("b"))
+}
+
+func TestNewUpstreamTransport_Tuning(t *testing.T) {
+	defaults := newUpstreamTransport(provider.TransportConfig{})
+	require.Equal(t, defaultMaxIdleConnsPerHost, defaults.MaxIdleConnsPerHost)
+	require.Equal(t, defaultMaxIdleConns, defaults.MaxIdleConns)
+	require.True(t, defaults.ForceAttemptHTTP2)
+	require.Nil(t, defaults.TLSNextProto)
+
+	tuned := newUpstreamTransport(provider.TransportConfig{
+		MaxIdleConnsPerHost: 512,
+		MaxConnsPerHost:     1024,
+		IdleConnTimeout:     time.Minute,
+		DisableHTTP2:        true,
+	})
+	require.Equal(t, 512, tuned.MaxIdleConnsPerHost)
+	require.Equal(t, 1024, tuned.MaxConnsPerHost)
+	require.Equal(t, time.Minute, tuned.IdleConnTimeout)
+	require.False(t, tuned.ForceAttemptHTTP2)
+	require.NotNil(t, tuned.TLSNextProto)
+	require.Empty(t, tuned.TLSNextProto)
+}
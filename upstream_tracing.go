@@ -0,0 +1,59 @@
+package llmux
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/blueberrycongee/llmux/internal/observability"
+	"github.com/blueberrycongee/llmux/pkg/provider"
+)
+
+// traceContextPropagator writes W3C traceparent/tracestate headers. It is
+// used regardless of the global propagator so upstream requests join the
+// caller's trace even when the gateway does not export spans itself.
+var traceContextPropagator = propagation.TraceContext{}
+
+// traceContextTransport injects the trace context of each request's context
+// into its headers before sending it upstream.
+type traceContextTransport struct {
+	base http.RoundTripper
+}
+
+func (t traceContextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if trace.SpanContextFromContext(req.Context()).IsValid() {
+		req = req.Clone(req.Context())
+		traceContextPropagator.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+	}
+	return t.base.RoundTrip(req)
+}
+
+// startAttemptSpan starts a child span for one upstream attempt. The tracer
+// follows the global provider installed by observability.InitTracing and is
+// a no-op when tracing is disabled.
+func startAttemptSpan(ctx context.Context, deployment *provider.Deployment, attempt int, fallback bool) (context.Context, trace.Span) {
+	return otel.Tracer(observability.TracerName).Start(ctx, "llmux.upstream_attempt",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("gen_ai.system", deployment.ProviderName),
+			attribute.String("gen_ai.request.model", deployment.ModelName),
+			attribute.String("llmux.deployment_id", deployment.ID),
+			attribute.Int("llmux.attempt", attempt),
+			attribute.Bool("llmux.fallback", fallback),
+		),
+	)
+}
+
+// endAttemptSpan records the outcome of an attempt and ends its span.
+func endAttemptSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,86 @@
+package llmux
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestClient_PropagatesTraceContextPerAttempt(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	var mu sync.Mutex
+	var traceparents []string
+	var calls atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		mu.Unlock()
+		if calls.Add(1) == 1 {
+			// Drop the connection so the first attempt fails with a retryable transport error.
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChatResponse{ID: "resp-1", Model: "test-model"})
+	}))
+	defer upstream.Close()
+
+	client, err := New(
+		WithProviderInstance("primary", &httpMockProvider{name: "primary", models: []string{"test-model"}, baseURL: upstream.URL}, []string{"test-model"}),
+		withTestPricing(t, "test-model"),
+		WithRetry(1, 0),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "gateway")
+	_, err = client.ChatCompletion(ctx, &ChatRequest{
+		Model:    "test-model",
+		Messages: []ChatMessage{{Role: "user", Content: json.RawMessage(`"hi"`)}},
+	})
+	parent.End()
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	var attempts []sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "llmux.upstream_attempt" {
+			attempts = append(attempts, s)
+		}
+	}
+	if len(attempts) != 2 || len(traceparents) != 2 {
+		t.Fatalf("expected 2 attempt spans and 2 upstream calls, got %d and %d", len(attempts), len(traceparents))
+	}
+	for i, s := range attempts {
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("attempt %d is not a child of the request span", i)
+		}
+		carrier := propagation.HeaderCarrier(http.Header{"Traceparent": []string{traceparents[i]}})
+		got := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+		if got.TraceID() != parent.SpanContext().TraceID() || got.SpanID() != s.SpanContext().SpanID() {
+			t.Errorf("attempt %d traceparent %q does not reference its span", i, traceparents[i])
+		}
+	}
+	if attempts[0].Status().Code.String() != "Error" {
+		t.Errorf("failed attempt status = %v, want Error", attempts[0].Status())
+	}
+}
@@ -0,0 +1,89 @@
+package llmux
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-json"
+)
+
+// VirtualModel is a model name that clients call like any other and that
+// the client expands into a request for Model with preset parameters before
+// routing. Presets are fixed: set fields override the caller's values.
+type VirtualModel struct {
+	// Name is the model name clients request.
+	Name string
+	// Model is the model the request is routed to.
+	Model string
+	// SystemPrompt, when set, is sent as a system message ahead of the
+	// caller's messages.
+	SystemPrompt string
+	Temperature  *float64
+	TopP         *float64
+	MaxTokens    int
+	Stop         []string
+	// Tools, when set, replace the caller's tools.
+	Tools      []Tool
+	ToolChoice json.RawMessage
+}
+
+// validateVirtualModels checks that every virtual model names a target and
+// that virtual models do not target each other.
+func validateVirtualModels(models map[string]VirtualModel) error {
+	for name, vm := range models {
+		if name == "" {
+			return fmt.Errorf("virtual model name is required")
+		}
+		if vm.Model == "" {
+			return fmt.Errorf("virtual model %s: model is required", name)
+		}
+		if _, ok := models[vm.Model]; ok {
+			return fmt.Errorf("virtual model %s: target %s is itself a virtual model", name, vm.Model)
+		}
+	}
+	return nil
+}
+
+// expandVirtualModel returns req rewritten for the virtual model it names,
+// or req itself when req.Model is not virtual. The caller's request is not
+// modified.
+func (c *Client) expandVirtualModel(req *ChatRequest) *ChatRequest {
+	vm, ok := c.config.VirtualModels[req.Model]
+	if !ok {
+		return req
+	}
+
+	expanded := *req
+	expanded.Model = vm.Model
+	if vm.SystemPrompt != "" {
+		expanded.Messages = make([]ChatMessage, 0, len(req.Messages)+1)
+		expanded.Messages = append(expanded.Messages, ChatMessage{Role: "system", Content: jsonString(vm.SystemPrompt)})
+		expanded.Messages = append(expanded.Messages, req.Messages...)
+	}
+	if vm.Temperature != nil {
+		expanded.Temperature = vm.Temperature
+	}
+	if vm.TopP != nil {
+		expanded.TopP = vm.TopP
+	}
+	if vm.MaxTokens > 0 {
+		expanded.MaxTokens = vm.MaxTokens
+	}
+	if len(vm.Stop) > 0 {
+		expanded.Stop = vm.Stop
+	}
+	if len(vm.Tools) > 0 {
+		expanded.Tools = vm.Tools
+	}
+	if len(vm.ToolChoice) > 0 {
+		expanded.ToolChoice = vm.ToolChoice
+	}
+	return &expanded
+}
+
+// resolveModel returns the model a virtual model routes to, or model itself.
+func (c *Client) resolveModel(model string) string {
+	if vm, ok := c.config.VirtualModels[model]; ok {
+		return vm.Model
+	}
+	return model
+}
@@ -0,0 +1,106 @@
+package llmux
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVirtualModel_ExpandsPreset(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received ChatRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ChatResponse{
+			ID:      "resp",
+			Model:   "m",
+			Choices: []Choice{{Message: ChatMessage{Role: "assistant", Content: jsonString("hi")}, FinishReason: "stop"}},
+			Usage:   &Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		})
+	}))
+	defer server.Close()
+
+	temperature := 0.2
+	client, err := New(
+		WithProviderInstance("mock", &httpMockProvider{name: "mock", models: []string{"m"}, baseURL: server.URL}, []string{"m"}),
+		withTestPricing(t, "m"),
+		WithTimeout(5*time.Second),
+		WithVirtualModels(VirtualModel{
+			Name:         "support-bot",
+			Model:        "m",
+			SystemPrompt: "You are a support agent.",
+			Temperature:  &temperature,
+			Tools:        []Tool{{Type: "function", Function: ToolFunction{Name: "lookup_order"}}},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	callerTemperature := 1.5
+	req := &ChatRequest{
+		Model:       "support-bot",
+		Messages:    []ChatMessage{{Role: "user", Content: jsonString("where is my order?")}},
+		Temperature: &callerTemperature,
+	}
+	if _, err := client.ChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if received.Model != "m" {
+		t.Fatalf("upstream model = %q, want m", received.Model)
+	}
+	if len(received.Messages) != 2 || received.Messages[0].Role != "system" || received.Messages[0].TextContent() != "You are a support agent." {
+		t.Fatalf("upstream messages = %+v, want the preset system prompt first", received.Messages)
+	}
+	if received.Temperature == nil || *received.Temperature != 0.2 {
+		t.Fatalf("upstream temperature = %v, want 0.2", received.Temperature)
+	}
+	if len(received.Tools) != 1 || received.Tools[0].Function.Name != "lookup_order" {
+		t.Fatalf("upstream tools = %+v, want the preset tools", received.Tools)
+	}
+	if req.Model != "support-bot" || len(req.Messages) != 1 || *req.Temperature != 1.5 {
+		t.Fatal("expected the caller's request to be left unchanged")
+	}
+
+	if got, want := client.CalculateCost("support-bot", &Usage{PromptTokens: 10, CompletionTokens: 5}), client.CalculateCost("m", &Usage{PromptTokens: 10, CompletionTokens: 5}); got != want {
+		t.Fatalf("CalculateCost(support-bot) = %v, want the target's %v", got, want)
+	}
+
+	models, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels() error = %v", err)
+	}
+	found := false
+	for _, m := range models {
+		found = found || m.ID == "support-bot"
+	}
+	if !found {
+		t.Fatalf("ListModels() = %+v, want support-bot listed", models)
+	}
+}
+
+func TestVirtualModel_RejectsChains(t *testing.T) {
+	_, err := New(WithVirtualModels(
+		VirtualModel{Name: "a", Model: "b"},
+		VirtualModel{Name: "b", Model: "m"},
+	))
+	if err == nil {
+		t.Fatal("expected an error for a virtual model targeting another")
+	}
+	if _, err := New(WithVirtualModels(VirtualModel{Name: "a"})); err == nil {
+		t.Fatal("expected an error for a virtual model without a target")
+	}
+}